@@ -256,3 +256,43 @@ var Password action.GTSAction = func(ctx context.Context) error {
 
 	return nil
 }
+
+// Quota sets a media storage quota override for the given account.
+var Quota action.GTSAction = func(ctx context.Context) error {
+	dbConn, err := bundb.NewBunDBService(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	username := viper.GetString(config.Keys.AdminAccountUsername)
+	if username == "" {
+		return errors.New("no username set")
+	}
+	if err := validate.Username(username); err != nil {
+		return err
+	}
+
+	quota := viper.GetInt(config.Keys.AdminAccountQuota)
+	if quota < -1 {
+		return errors.New("quota must be -1 (unlimited), 0 (instance default), or a positive number of bytes")
+	}
+
+	a, err := dbConn.GetLocalAccountByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	u := &gtsmodel.User{}
+	if err := dbConn.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, u); err != nil {
+		return err
+	}
+
+	u.MediaQuota = quota
+	u.UpdatedAt = time.Now()
+
+	if err := dbConn.UpdateByPrimaryKey(ctx, u); err != nil {
+		return err
+	}
+
+	return dbConn.Stop(ctx)
+}
@@ -0,0 +1,241 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package domainblocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/blocklist"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/bundb"
+	"github.com/superseriousbusiness/gotosocial/internal/email"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/federation/federatingdb"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+)
+
+// Import creates domain blocks for every domain in the blocklist at the given path, which can be JSON
+// as exported by GoToSocial, or CSV as exported by Mastodon. Domains that are already blocked are left alone.
+//
+// Blocks are created the same way as through the admin API, so accounts on suspended domains are deleted
+// too; Import doesn't return until that's done.
+var Import action.GTSAction = func(ctx context.Context) error {
+	path := viper.GetString(config.Keys.AdminTransPath)
+	if path == "" {
+		return errors.New("no path set")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening blocklist: %s", err)
+	}
+	defer f.Close()
+
+	entries, skipped, err := blocklist.Parse(f)
+	if err != nil {
+		return err
+	}
+
+	dbConn, err := bundb.NewBunDBService(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	processor, stop, err := newProcessor(ctx, dbConn)
+	if err != nil {
+		return err
+	}
+
+	// there's no admin doing this through the api, so the blocks are created by the instance itself
+	instanceAccount, err := dbConn.GetInstanceAccount(ctx, "")
+	if err != nil {
+		return fmt.Errorf("error getting instance account: %s", err)
+	}
+	authed := &oauth.Auth{Account: instanceAccount}
+
+	created := 0
+	for _, e := range entries {
+		existing := &gtsmodel.DomainBlock{}
+		err := dbConn.GetWhere(ctx, []db.Where{{Key: "domain", Value: e.Domain, CaseInsensitive: true}}, existing)
+		if err == nil {
+			continue
+		}
+		if err != db.ErrNoEntries {
+			return fmt.Errorf("error checking for existing domain block for %s: %s", e.Domain, err)
+		}
+
+		if _, errWithCode := processor.AdminDomainBlockCreate(ctx, authed, &apimodel.DomainBlockCreateRequest{
+			Domain:            e.Domain,
+			Obfuscate:         e.Obfuscate,
+			IncludeSubdomains: e.IncludeSubdomains,
+			Severity:          e.Severity,
+			RejectMedia:       e.RejectMedia,
+			PrivateComment:    e.PrivateComment,
+			PublicComment:     e.PublicComment,
+		}); errWithCode != nil {
+			return fmt.Errorf("error creating domain block for %s: %s", e.Domain, errWithCode)
+		}
+		created++
+	}
+
+	if len(skipped) != 0 {
+		logrus.Infof("skipped %d domains that can't be blocked: %s", len(skipped), strings.Join(skipped, ", "))
+	}
+	logrus.Infof("created %d domain blocks; %d domains were already blocked", created, len(entries)-created)
+
+	logrus.Info("waiting for accounts on blocked domains to be deleted")
+	processor.Wait()
+
+	if err := stop(); err != nil {
+		return err
+	}
+	return dbConn.Stop(ctx)
+}
+
+// newProcessor creates and starts a processor, for processing domain blocks the same way the server would.
+// The returned function stops it again, along with the media manager and storage that it uses.
+func newProcessor(ctx context.Context, dbConn db.DB) (processing.Processor, func() error, error) {
+	clientWorker := worker.New[messages.FromClientAPI](-1, -1)
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+
+	storage, err := gtsstorage.Open(viper.GetString(config.Keys.StorageBackend))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating storage backend: %s", err)
+	}
+
+	mediaManager, err := media.NewManager(dbConn, storage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating media manager: %s", err)
+	}
+
+	// accounts on suspended domains are deleted without emailing or pushing anything to anyone
+	emailSender, err := email.NewNoopSender(nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating noop email sender: %s", err)
+	}
+	webPushSender := webpush.NewSender(dbConn, transport.PublicOnlyClient(&http.Client{Timeout: 30 * time.Second}))
+	translator, err := translate.New(http.DefaultClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating translator: %s", err)
+	}
+
+	typeConverter := typeutils.NewConverter(dbConn)
+	federatingDB := federatingdb.New(dbConn, fedWorker)
+	transportController := transport.NewController(dbConn, federatingDB, &federation.Clock{}, http.DefaultClient)
+	federator := federation.NewFederator(dbConn, federatingDB, transportController, typeConverter, mediaManager)
+	oauthServer := oauth.New(ctx, dbConn)
+
+	processor := processing.NewProcessor(typeConverter, federator, oauthServer, mediaManager, storage, dbConn, emailSender, webPushSender, translator, clientWorker, fedWorker)
+	if err := processor.Start(); err != nil {
+		return nil, nil, fmt.Errorf("error starting processor: %s", err)
+	}
+
+	stop := func() error {
+		if err := processor.Stop(); err != nil {
+			return fmt.Errorf("error stopping processor: %s", err)
+		}
+		if err := mediaManager.Stop(); err != nil {
+			return fmt.Errorf("error stopping media manager: %s", err)
+		}
+		return storage.Close()
+	}
+
+	return processor, stop, nil
+}
+
+// Export writes all the domain blocks currently in place to a blocklist at the given path. If the path ends
+// in .csv, the blocklist is written as CSV in the format used by Mastodon, otherwise it's written as JSON.
+var Export action.GTSAction = func(ctx context.Context) error {
+	path := viper.GetString(config.Keys.AdminTransPath)
+	if path == "" {
+		return errors.New("no path set")
+	}
+
+	dbConn, err := bundb.NewBunDBService(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating dbservice: %s", err)
+	}
+
+	blocks := []*gtsmodel.DomainBlock{}
+	if err := dbConn.GetAll(ctx, &blocks); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error getting domain blocks: %s", err)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Domain < blocks[j].Domain
+	})
+
+	// private comments stay private
+	entries := make([]blocklist.Entry, 0, len(blocks))
+	for _, b := range blocks {
+		entries = append(entries, blocklist.Entry{
+			Domain:            b.Domain,
+			Severity:          b.Severity,
+			Obfuscate:         b.Obfuscate,
+			IncludeSubdomains: b.IncludeSubdomains,
+			PublicComment:     b.PublicComment,
+		})
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating blocklist: %s", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = blocklist.WriteCSV(f, entries)
+	} else {
+		err = json.NewEncoder(f).Encode(entries)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("error writing blocklist: %s", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing blocklist: %s", err)
+	}
+
+	logrus.Infof("exported %d domain blocks to %s", len(entries), path)
+	return dbConn.Stop(ctx)
+}
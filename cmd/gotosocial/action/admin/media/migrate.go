@@ -0,0 +1,68 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/storage"
+)
+
+// Migrate copies all stored media from one storage backend to another.
+var Migrate action.GTSAction = func(ctx context.Context) error {
+	from := viper.GetString(config.Keys.AdminMediaFrom)
+	if from == "" {
+		return errors.New("no source storage backend set")
+	}
+
+	to := viper.GetString(config.Keys.AdminMediaTo)
+	if to == "" {
+		return errors.New("no destination storage backend set")
+	}
+
+	if from == to {
+		return fmt.Errorf("source and destination storage backends are both %s", from)
+	}
+
+	src, err := storage.Open(from)
+	if err != nil {
+		return fmt.Errorf("error opening source storage backend: %s", err)
+	}
+	defer src.Close()
+
+	dst, err := storage.Open(to)
+	if err != nil {
+		return fmt.Errorf("error opening destination storage backend: %s", err)
+	}
+	defer dst.Close()
+
+	copied, skipped, err := storage.Migrate(ctx, src, dst)
+	if err != nil {
+		return fmt.Errorf("error migrating media after copying %d objects: %s; run the command again to resume", copied, err)
+	}
+
+	logrus.Infof("finished migrating media from %s to %s: %d objects copied, %d already present", from, to, copied, skipped)
+	return nil
+}
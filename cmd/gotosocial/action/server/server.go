@@ -24,11 +24,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"path"
 	"syscall"
+	"time"
 
-	"codeberg.org/gruf/go-store/kv"
-	"codeberg.org/gruf/go-store/storage"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action"
@@ -38,20 +37,36 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/app"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/auth"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/blocks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/conversation"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/directory"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/emoji"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/endorsements"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/exports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/favourites"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/featuredtags"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/fileserver"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/filter"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/followrequest"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/imports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/instance"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/markers"
 	mediaModule "github.com/superseriousbusiness/gotosocial/internal/api/client/media"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/mutes"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/notification"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/poll"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/preferences"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/profile"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/push"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/reports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/search"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/tags"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/timeline"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/trends"
 	userClient "github.com/superseriousbusiness/gotosocial/internal/api/client/user"
+	"github.com/superseriousbusiness/gotosocial/internal/api/metrics"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/nodeinfo"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/webfinger"
@@ -68,9 +83,12 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oidc"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/web"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 )
 
@@ -89,6 +107,10 @@ var Start action.GTSAction = func(ctx context.Context) error {
 		return fmt.Errorf("error creating instance instance: %s", err)
 	}
 
+	if err := dbService.CreateInstanceVAPIDKeyPair(ctx); err != nil {
+		return fmt.Errorf("error creating instance vapid key pair: %s", err)
+	}
+
 	// Create the client API and federator worker pools
 	// NOTE: these MUST NOT be used until they are passed to the
 	// processor and it is started. The reason being that the processor
@@ -107,15 +129,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	typeConverter := typeutils.NewConverter(dbService)
 
 	// Open the storage backend
-	storageBasePath := viper.GetString(config.Keys.StorageLocalBasePath)
-	storage, err := kv.OpenFile(storageBasePath, &storage.DiskConfig{
-		// Put the store lockfile in the storage dir itself.
-		// Normally this would not be safe, since we could end up
-		// overwriting the lockfile if we store a file called 'store.lock'.
-		// However, in this case it's OK because the keys are set by
-		// GtS and not the user, so we know we're never going to overwrite it.
-		LockFile: path.Join(storageBasePath, "store.lock"),
-	})
+	storage, err := gtsstorage.Open(viper.GetString(config.Keys.StorageBackend))
 	if err != nil {
 		return fmt.Errorf("error creating storage backend: %s", err)
 	}
@@ -146,8 +160,19 @@ var Start action.GTSAction = func(ctx context.Context) error {
 		}
 	}
 
+	// web push notifications are sent straight to the push services of subscribed clients,
+	// giving up on any push service that takes too long to respond; endpoints are given to us by
+	// users, so they must never be able to point us at addresses on our own private network
+	webPushSender := webpush.NewSender(dbService, transport.PublicOnlyClient(&http.Client{Timeout: 30 * time.Second}))
+
+	// statuses are translated by whichever translation backend is configured, if any
+	translator, err := translate.New(http.DefaultClient)
+	if err != nil {
+		return fmt.Errorf("error creating translator: %s", err)
+	}
+
 	// create and start the message processor using the other services we've created so far
-	processor := processing.NewProcessor(typeConverter, federator, oauthServer, mediaManager, storage, dbService, emailSender, clientWorker, fedWorker)
+	processor := processing.NewProcessor(typeConverter, federator, oauthServer, mediaManager, storage, dbService, emailSender, webPushSender, translator, clientWorker, fedWorker)
 	if err := processor.Start(); err != nil {
 		return fmt.Errorf("error starting processor: %s", err)
 	}
@@ -164,7 +189,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	}
 
 	// build client api modules
-	authModule := auth.New(dbService, oauthServer, idp)
+	authModule := auth.New(dbService, oauthServer, idp, processor)
 	accountModule := account.New(processor)
 	instanceModule := instance.New(processor)
 	appsModule := app.New(processor)
@@ -186,6 +211,21 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	streamingModule := streaming.New(processor)
 	favouritesModule := favourites.New(processor)
 	blocksModule := blocks.New(processor)
+	pollModule := poll.New(processor)
+	conversationModule := conversation.New(processor)
+	markersModule := markers.New(processor)
+	trendsModule := trends.New(processor)
+	directoryModule := directory.New(processor)
+	endorsementsModule := endorsements.New(processor)
+	featuredTagsModule := featuredtags.New(processor)
+	mutesModule := mutes.New(processor)
+	tagsModule := tags.New(processor)
+	exportsModule := exports.New(processor)
+	importsModule := imports.New(processor)
+	preferencesModule := preferences.New(processor)
+	profileModule := profile.New(processor)
+	reportsModule := reports.New(processor)
+	pushModule := push.New(processor)
 	userClientModule := userClient.New(processor)
 
 	apis := []api.ClientModule{
@@ -217,9 +257,32 @@ var Start action.GTSAction = func(ctx context.Context) error {
 		streamingModule,
 		favouritesModule,
 		blocksModule,
+		pollModule,
+		conversationModule,
+		markersModule,
+		trendsModule,
+		directoryModule,
+		endorsementsModule,
+		featuredTagsModule,
+		mutesModule,
+		tagsModule,
+		exportsModule,
+		importsModule,
+		preferencesModule,
+		profileModule,
+		reportsModule,
+		pushModule,
 		userClientModule,
 	}
 
+	// only expose metrics if the admin has asked for them
+	if viper.GetBool(config.Keys.MetricsEnabled) {
+		if err := media.RegisterMetrics(prometheus.DefaultRegisterer, mediaManager); err != nil {
+			return fmt.Errorf("error registering media manager metrics: %s", err)
+		}
+		apis = append(apis, metrics.New())
+	}
+
 	for _, m := range apis {
 		if err := m.Route(router); err != nil {
 			return fmt.Errorf("routing error: %s", err)
@@ -36,19 +36,34 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/app"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/auth"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/blocks"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/conversation"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/directory"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/emoji"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/endorsements"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/exports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/favourites"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/featuredtags"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/fileserver"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/filter"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/followrequest"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/imports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/instance"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/list"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/markers"
 	mediaModule "github.com/superseriousbusiness/gotosocial/internal/api/client/media"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/mutes"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/notification"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/poll"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/preferences"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/profile"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/push"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/reports"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/search"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/tags"
 	"github.com/superseriousbusiness/gotosocial/internal/api/client/timeline"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/trends"
 	userClient "github.com/superseriousbusiness/gotosocial/internal/api/client/user"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/nodeinfo"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
@@ -108,7 +123,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	}
 
 	// build client api modules
-	authModule := auth.New(dbService, oauthServer, idp)
+	authModule := auth.New(dbService, oauthServer, idp, processor)
 	accountModule := account.New(processor)
 	instanceModule := instance.New(processor)
 	appsModule := app.New(processor)
@@ -130,6 +145,21 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	streamingModule := streaming.New(processor)
 	favouritesModule := favourites.New(processor)
 	blocksModule := blocks.New(processor)
+	pollModule := poll.New(processor)
+	conversationModule := conversation.New(processor)
+	markersModule := markers.New(processor)
+	trendsModule := trends.New(processor)
+	directoryModule := directory.New(processor)
+	endorsementsModule := endorsements.New(processor)
+	featuredTagsModule := featuredtags.New(processor)
+	mutesModule := mutes.New(processor)
+	tagsModule := tags.New(processor)
+	exportsModule := exports.New(processor)
+	importsModule := imports.New(processor)
+	preferencesModule := preferences.New(processor)
+	profileModule := profile.New(processor)
+	reportsModule := reports.New(processor)
+	pushModule := push.New(processor)
 	userClientModule := userClient.New(processor)
 
 	apis := []api.ClientModule{
@@ -161,6 +191,21 @@ var Start action.GTSAction = func(ctx context.Context) error {
 		streamingModule,
 		favouritesModule,
 		blocksModule,
+		pollModule,
+		conversationModule,
+		markersModule,
+		trendsModule,
+		directoryModule,
+		endorsementsModule,
+		featuredTagsModule,
+		mutesModule,
+		tagsModule,
+		exportsModule,
+		importsModule,
+		preferencesModule,
+		profileModule,
+		reportsModule,
+		pushModule,
 		userClientModule,
 	}
 
@@ -21,6 +21,8 @@ package main
 import (
 	"github.com/spf13/cobra"
 	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action/admin/account"
+	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action/admin/domainblocks"
+	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action/admin/media"
 	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/action/admin/trans"
 	"github.com/superseriousbusiness/gotosocial/cmd/gotosocial/flag"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
@@ -133,6 +135,19 @@ func adminCommands() *cobra.Command {
 	flag.AdminAccountPassword(adminAccountPasswordCmd, config.Defaults)
 	adminAccountCmd.AddCommand(adminAccountPasswordCmd)
 
+	adminAccountQuotaCmd := &cobra.Command{
+		Use:   "quota",
+		Short: "set a media storage quota for the given account",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Context(), account.Quota)
+		},
+	}
+	flag.AdminAccountQuota(adminAccountQuotaCmd, config.Defaults)
+	adminAccountCmd.AddCommand(adminAccountQuotaCmd)
+
 	adminCmd.AddCommand(adminAccountCmd)
 
 	/*
@@ -165,5 +180,66 @@ func adminCommands() *cobra.Command {
 	flag.AdminTrans(adminImportCmd, config.Defaults)
 	adminCmd.AddCommand(adminImportCmd)
 
+	/*
+	   ADMIN DOMAIN BLOCK COMMANDS
+	*/
+
+	adminDomainBlocksCmd := &cobra.Command{
+		Use:   "domainblocks",
+		Short: "admin commands related to domain blocks",
+	}
+
+	adminDomainBlocksImportCmd := &cobra.Command{
+		Use:   "import",
+		Short: "block every domain in a blocklist file, either JSON as exported by GoToSocial or CSV as exported by Mastodon",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Context(), domainblocks.Import)
+		},
+	}
+	flag.AdminTrans(adminDomainBlocksImportCmd, config.Defaults)
+	adminDomainBlocksCmd.AddCommand(adminDomainBlocksImportCmd)
+
+	adminDomainBlocksExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export all domain blocks to a blocklist file, as CSV if the path ends in .csv and as JSON otherwise",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Context(), domainblocks.Export)
+		},
+	}
+	flag.AdminTrans(adminDomainBlocksExportCmd, config.Defaults)
+	adminDomainBlocksCmd.AddCommand(adminDomainBlocksExportCmd)
+
+	adminCmd.AddCommand(adminDomainBlocksCmd)
+
+	/*
+	   ADMIN MEDIA COMMANDS
+	*/
+
+	adminMediaCmd := &cobra.Command{
+		Use:   "media",
+		Short: "admin commands related to stored media",
+	}
+
+	adminMediaMigrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "copy all stored media from one storage backend to another",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Context(), media.Migrate)
+		},
+	}
+	flag.AdminMedia(adminMediaMigrateCmd, config.Defaults)
+	adminMediaCmd.AddCommand(adminMediaMigrateCmd)
+
+	adminCmd.AddCommand(adminMediaCmd)
+
 	return adminCmd
 }
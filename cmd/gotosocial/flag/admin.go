@@ -40,6 +40,15 @@ func AdminAccountPassword(cmd *cobra.Command, values config.Values) {
 	}
 }
 
+// AdminAccountQuota attaches flags pertaining to setting an account's media quota.
+func AdminAccountQuota(cmd *cobra.Command, values config.Values) {
+	AdminAccount(cmd, values)
+	cmd.Flags().Int(config.Keys.AdminAccountQuota, 0, usage.AdminAccountQuota) // REQUIRED
+	if err := cmd.MarkFlagRequired(config.Keys.AdminAccountQuota); err != nil {
+		panic(err)
+	}
+}
+
 // AdminAccountCreate attaches flags pertaining to admin account creation.
 func AdminAccountCreate(cmd *cobra.Command, values config.Values) {
 	AdminAccount(cmd, values)
@@ -60,3 +69,16 @@ func AdminTrans(cmd *cobra.Command, values config.Values) {
 		panic(err)
 	}
 }
+
+// AdminMedia attaches flags pertaining to media storage migration commands.
+func AdminMedia(cmd *cobra.Command, values config.Values) {
+	Storage(cmd, values)
+	cmd.Flags().String(config.Keys.AdminMediaFrom, "", usage.AdminMediaFrom) // REQUIRED
+	if err := cmd.MarkFlagRequired(config.Keys.AdminMediaFrom); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(config.Keys.AdminMediaTo, "", usage.AdminMediaTo) // REQUIRED
+	if err := cmd.MarkFlagRequired(config.Keys.AdminMediaTo); err != nil {
+		panic(err)
+	}
+}
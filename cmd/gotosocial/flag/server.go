@@ -30,11 +30,15 @@ func Server(cmd *cobra.Command, values config.Values) {
 	Media(cmd, values)
 	Storage(cmd, values)
 	Statuses(cmd, values)
+	Federation(cmd, values)
 	LetsEncrypt(cmd, values)
 	OIDC(cmd, values)
 	SMTP(cmd, values)
 	Router(cmd, values)
 	Syslog(cmd, values)
+	Metrics(cmd, values)
+	RateLimit(cmd, values)
+	Translation(cmd, values)
 }
 
 // Router attaches flags pertaining to the gin router.
@@ -60,16 +64,43 @@ func Accounts(cmd *cobra.Command, values config.Values) {
 // Media attaches flags pertaining to media config.
 func Media(cmd *cobra.Command, values config.Values) {
 	cmd.Flags().Int(config.Keys.MediaImageMaxSize, values.MediaImageMaxSize, usage.MediaImageMaxSize)
+	cmd.Flags().Int(config.Keys.MediaImageMaxPixels, values.MediaImageMaxPixels, usage.MediaImageMaxPixels)
 	cmd.Flags().Int(config.Keys.MediaVideoMaxSize, values.MediaVideoMaxSize, usage.MediaVideoMaxSize)
+	cmd.Flags().Int(config.Keys.MediaVideoMaxPixels, values.MediaVideoMaxPixels, usage.MediaVideoMaxPixels)
+	cmd.Flags().Int(config.Keys.MediaVideoMaxDuration, values.MediaVideoMaxDuration, usage.MediaVideoMaxDuration)
+	cmd.Flags().Int(config.Keys.MediaEmojiMaxSize, values.MediaEmojiMaxSize, usage.MediaEmojiMaxSize)
+	cmd.Flags().Int(config.Keys.MediaEmojiMaxPixels, values.MediaEmojiMaxPixels, usage.MediaEmojiMaxPixels)
+	cmd.Flags().Int(config.Keys.MediaUnknownMaxSize, values.MediaUnknownMaxSize, usage.MediaUnknownMaxSize)
 	cmd.Flags().Int(config.Keys.MediaDescriptionMinChars, values.MediaDescriptionMinChars, usage.MediaDescriptionMinChars)
 	cmd.Flags().Int(config.Keys.MediaDescriptionMaxChars, values.MediaDescriptionMaxChars, usage.MediaDescriptionMaxChars)
 	cmd.Flags().Int(config.Keys.MediaRemoteCacheDays, values.MediaRemoteCacheDays, usage.MediaRemoteCacheDays)
+	cmd.Flags().Int(config.Keys.MediaRemoteRecacheTimeout, values.MediaRemoteRecacheTimeout, usage.MediaRemoteRecacheTimeout)
+	cmd.Flags().Int(config.Keys.MediaAccountQuota, values.MediaAccountQuota, usage.MediaAccountQuota)
+	cmd.Flags().Int(config.Keys.MediaThumbnailMaxWidth, values.MediaThumbnailMaxWidth, usage.MediaThumbnailMaxWidth)
+	cmd.Flags().Int(config.Keys.MediaThumbnailMaxHeight, values.MediaThumbnailMaxHeight, usage.MediaThumbnailMaxHeight)
+	cmd.Flags().StringSlice(config.Keys.MediaThumbnailVariants, values.MediaThumbnailVariants, usage.MediaThumbnailVariants)
+	cmd.Flags().Bool(config.Keys.MediaJpegProgressive, values.MediaJpegProgressive, usage.MediaJpegProgressive)
+	cmd.Flags().Bool(config.Keys.MediaGifvEnabled, values.MediaGifvEnabled, usage.MediaGifvEnabled)
+	cmd.Flags().Int(config.Keys.MediaGifvMinSize, values.MediaGifvMinSize, usage.MediaGifvMinSize)
+	cmd.Flags().Bool(config.Keys.MediaGifvKeepOriginal, values.MediaGifvKeepOriginal, usage.MediaGifvKeepOriginal)
+	cmd.Flags().String(config.Keys.MediaGifvFfmpegPath, values.MediaGifvFfmpegPath, usage.MediaGifvFfmpegPath)
+	cmd.Flags().Int(config.Keys.MediaJobTimeout, values.MediaJobTimeout, usage.MediaJobTimeout)
+	cmd.Flags().Int(config.Keys.MediaRecacheBatchRate, values.MediaRecacheBatchRate, usage.MediaRecacheBatchRate)
+	cmd.Flags().Int(config.Keys.MediaIntegrityCheckSample, values.MediaIntegrityCheckSample, usage.MediaIntegrityCheckSample)
+	cmd.Flags().Int(config.Keys.MediaRemoteFetchRate, values.MediaRemoteFetchRate, usage.MediaRemoteFetchRate)
+	cmd.Flags().Int(config.Keys.MediaCacheWarmCount, values.MediaCacheWarmCount, usage.MediaCacheWarmCount)
 }
 
 // Storage attaches flags pertaining to storage config.
 func Storage(cmd *cobra.Command, values config.Values) {
 	cmd.Flags().String(config.Keys.StorageBackend, values.StorageBackend, usage.StorageBackend)
 	cmd.Flags().String(config.Keys.StorageLocalBasePath, values.StorageLocalBasePath, usage.StorageLocalBasePath)
+	cmd.Flags().String(config.Keys.StorageS3Endpoint, values.StorageS3Endpoint, usage.StorageS3Endpoint)
+	cmd.Flags().String(config.Keys.StorageS3AccessKey, values.StorageS3AccessKey, usage.StorageS3AccessKey)
+	cmd.Flags().String(config.Keys.StorageS3SecretKey, values.StorageS3SecretKey, usage.StorageS3SecretKey)
+	cmd.Flags().Bool(config.Keys.StorageS3UseSSL, values.StorageS3UseSSL, usage.StorageS3UseSSL)
+	cmd.Flags().String(config.Keys.StorageS3BucketName, values.StorageS3BucketName, usage.StorageS3BucketName)
+	cmd.Flags().Bool(config.Keys.StorageS3Proxy, values.StorageS3Proxy, usage.StorageS3Proxy)
 }
 
 // Statuses attaches flags pertaining to statuses config.
@@ -79,6 +110,22 @@ func Statuses(cmd *cobra.Command, values config.Values) {
 	cmd.Flags().Int(config.Keys.StatusesPollMaxOptions, values.StatusesPollMaxOptions, usage.StatusesPollMaxOptions)
 	cmd.Flags().Int(config.Keys.StatusesPollOptionMaxChars, values.StatusesPollOptionMaxChars, usage.StatusesPollOptionMaxChars)
 	cmd.Flags().Int(config.Keys.StatusesMediaMaxFiles, values.StatusesMediaMaxFiles, usage.StatusesMediaMaxFiles)
+	cmd.Flags().Bool(config.Keys.StatusesQuotesEnabled, values.StatusesQuotesEnabled, usage.StatusesQuotesEnabled)
+	cmd.Flags().Bool(config.Keys.StatusesReactionsEnabled, values.StatusesReactionsEnabled, usage.StatusesReactionsEnabled)
+	cmd.Flags().Int(config.Keys.StatusesTrendsDays, values.StatusesTrendsDays, usage.StatusesTrendsDays)
+	cmd.Flags().Bool(config.Keys.StatusesTrendsApproval, values.StatusesTrendsApproval, usage.StatusesTrendsApproval)
+	cmd.Flags().Bool(config.Keys.StatusesSearchEnabled, values.StatusesSearchEnabled, usage.StatusesSearchEnabled)
+}
+
+// Federation attaches flags pertaining to federation config.
+func Federation(cmd *cobra.Command, values config.Values) {
+	cmd.Flags().Int(config.Keys.FederationUnreachableDays, values.FederationUnreachableDays, usage.FederationUnreachableDays)
+	cmd.Flags().StringToString(config.Keys.FederationNodeInfoMetadata, values.FederationNodeInfoMetadata, usage.FederationNodeInfoMetadata)
+	cmd.Flags().Int(config.Keys.FederationInboxRateLimit, values.FederationInboxRateLimit, usage.FederationInboxRateLimit)
+	cmd.Flags().Bool(config.Keys.FederationHideCollections, values.FederationHideCollections, usage.FederationHideCollections)
+	cmd.Flags().Int(config.Keys.FederationRefreshDays, values.FederationRefreshDays, usage.FederationRefreshDays)
+	cmd.Flags().Int(config.Keys.FederationRefreshPerDomain, values.FederationRefreshPerDomain, usage.FederationRefreshPerDomain)
+	cmd.Flags().StringSlice(config.Keys.FederationRefuseActivities, values.FederationRefuseActivities, usage.FederationRefuseActivities)
 }
 
 // LetsEncrypt attaches flags pertaining to letsencrypt config.
@@ -115,3 +162,21 @@ func Syslog(cmd *cobra.Command, values config.Values) {
 	cmd.Flags().String(config.Keys.SyslogProtocol, values.SyslogProtocol, usage.SyslogProtocol)
 	cmd.Flags().String(config.Keys.SyslogAddress, values.SyslogAddress, usage.SyslogAddress)
 }
+
+// Metrics attaches flags pertaining to metrics config.
+func Metrics(cmd *cobra.Command, values config.Values) {
+	cmd.Flags().Bool(config.Keys.MetricsEnabled, values.MetricsEnabled, usage.MetricsEnabled)
+}
+
+// RateLimit attaches flags pertaining to rate limiting config.
+func RateLimit(cmd *cobra.Command, values config.Values) {
+	cmd.Flags().Int(config.Keys.RateLimitRequests, values.RateLimitRequests, usage.RateLimitRequests)
+	cmd.Flags().Int(config.Keys.RateLimitExpensiveRequests, values.RateLimitExpensiveRequests, usage.RateLimitExpensiveRequests)
+}
+
+// Translation attaches flags pertaining to status translation config.
+func Translation(cmd *cobra.Command, values config.Values) {
+	cmd.Flags().String(config.Keys.TranslationBackend, values.TranslationBackend, usage.TranslationBackend)
+	cmd.Flags().String(config.Keys.TranslationEndpoint, values.TranslationEndpoint, usage.TranslationEndpoint)
+	cmd.Flags().String(config.Keys.TranslationAPIKey, values.TranslationAPIKey, usage.TranslationAPIKey)
+}
@@ -45,17 +45,56 @@ var usage = config.KeyNames{
 	AccountsApprovalRequired:   "Do account signups require approval by an admin or moderator before user can log in? If false, new registrations will be automatically approved.",
 	AccountsReasonRequired:     "Do new account signups require a reason to be submitted on registration?",
 	MediaImageMaxSize:          "Max size of accepted images in bytes",
+	MediaImageMaxPixels:        "Max number of pixels (width multiplied by height) of accepted images. If set to 0, images may have any dimensions.",
 	MediaVideoMaxSize:          "Max size of accepted videos in bytes",
+	MediaVideoMaxPixels:        "Max number of pixels (width multiplied by height) of accepted videos, advertised to clients. If set to 0, videos may have any dimensions.",
+	MediaVideoMaxDuration:      "Max duration of accepted videos in seconds, advertised to clients. If set to 0, videos may be any length.",
+	MediaEmojiMaxSize:          "Max size of accepted emoji in bytes",
+	MediaEmojiMaxPixels:        "Max number of pixels (width multiplied by height) of accepted emoji. If set to 0, emoji may have any dimensions.",
+	MediaUnknownMaxSize:        "Max size in bytes of remote attachments of types that GoToSocial can't process, which are stored as they are for download. If set to 0, such attachments are rejected.",
 	MediaDescriptionMinChars:   "Min required chars for an image description",
 	MediaDescriptionMaxChars:   "Max permitted chars for an image description",
 	MediaRemoteCacheDays:       "Number of days to locally cache media from remote instances. If set to 0, remote media will be kept indefinitely.",
+	MediaRemoteRecacheTimeout:  "Number of seconds to wait for uncached remote media to be fetched again when it's requested, before asking the requester to try again later. If set to 0, requesters will wait until the media has been fetched.",
+	MediaAccountQuota:          "Maximum total bytes of media that each local account may store. If set to 0, accounts may store unlimited media.",
+	MediaThumbnailMaxWidth:     "Max width in pixels of the small thumbnail generated for each image.",
+	MediaThumbnailMaxHeight:    "Max height in pixels of the small thumbnail generated for each image.",
+	MediaThumbnailVariants:     "Additional thumbnails to generate for each image, in the format 'name:[width]x[height]', eg., 'medium:1280x1280'.",
+	MediaJpegProgressive:       "Store full-size jpegs as progressive jpegs, which show a blurry version of the whole image while they're still loading. Thumbnails are always stored as baseline jpegs.",
+	MediaGifvEnabled:           "Convert large animated gifs into silent, looping mp4 videos (gifv). Requires ffmpeg.",
+	MediaGifvMinSize:           "Minimum size in bytes of an animated gif before it will be converted to gifv.",
+	MediaGifvKeepOriginal:      "Keep the original gif in storage alongside the converted gifv, so that it can still be retrieved.",
+	MediaGifvFfmpegPath:        "Path to the ffmpeg executable used for gifv conversion. If just a name is given, ffmpeg will be looked up in $PATH.",
+	MediaJobTimeout:            "Maximum number of seconds that processing a single piece of media may take before it's given up on. If set to 0, processing may take as long as it needs.",
+	MediaRecacheBatchRate:      "Maximum number of remote media per second to queue for fetching again when an admin recaches a batch of media. If set to 0, all the media is queued at once.",
+	MediaIntegrityCheckSample:  "Number of stored media attachments, and of emojis, to check for missing or corrupted files every hour. If set to 0, media won't be checked.",
+	MediaRemoteFetchRate:       "Maximum number of requests per second to make to any one remote host when fetching media. If set to 0, media fetches aren't limited.",
+	MediaCacheWarmCount:        "Number of the most viewed remote media attachments that aren't cached to fetch again every hour, before anyone asks for them. If set to 0, media won't be fetched ahead of time.",
 	StorageBackend:             "Storage backend to use for media attachments",
 	StorageLocalBasePath:       "Full path to an already-created directory where gts should store/retrieve media files. Subfolders will be created within this dir.",
+	StorageS3Endpoint:          "S3 Endpoint URL (e.g 'minio.example.org:9000')",
+	StorageS3AccessKey:         "S3 Access Key",
+	StorageS3SecretKey:         "S3 Secret Key",
+	StorageS3UseSSL:            "Use SSL for S3 connections. Only set this to 'false' when testing locally",
+	StorageS3BucketName:        "Place blobs in this bucket",
+	StorageS3Proxy:             "Proxy S3 contents through GoToSocial instead of redirecting to a presigned URL",
 	StatusesMaxChars:           "Max permitted characters for posted statuses",
 	StatusesCWMaxChars:         "Max permitted characters for content/spoiler warnings on statuses",
 	StatusesPollMaxOptions:     "Max amount of options permitted on a poll",
 	StatusesPollOptionMaxChars: "Max amount of characters for a poll option",
 	StatusesMediaMaxFiles:      "Maximum number of media files/attachments per status",
+	StatusesQuotesEnabled:      "Allow local users to create statuses that quote other statuses",
+	StatusesReactionsEnabled:   "Allow local users to react to statuses with emojis",
+	StatusesTrendsDays:         "Number of days of public statuses, and of faves and boosts of them, to count when working out which hashtags, statuses and links are trending.",
+	StatusesTrendsApproval:     "Only show hashtags, statuses and links in trends once an admin has approved them. If false, everything is shown unless an admin has rejected it.",
+	StatusesSearchEnabled:      "Allow users to search the text of statuses they've posted, faved, bookmarked, or been mentioned in.",
+	FederationUnreachableDays:  "Number of days that deliveries to a remote instance can keep failing before deliveries to it are suspended. If set to 0, deliveries are never suspended.",
+	FederationNodeInfoMetadata: "Extra key/value pairs to include in the metadata of the nodeinfo served by this instance, eg. nodeAdmin=someone.",
+	FederationInboxRateLimit:   "Maximum number of requests per minute that any one remote domain can post to inboxes on this instance. If set to 0, inbox requests aren't limited.",
+	FederationHideCollections:  "Hide the followers and following lists of every account on this instance from remote instances, showing only how many accounts are in them.",
+	FederationRefreshDays:      "Number of days after which a remote account is fetched from its instance again, to keep its profile up to date. If set to 0, remote accounts aren't refreshed in the background.",
+	FederationRefreshPerDomain: "Maximum number of accounts from any one remote domain to refresh each hour.",
+	FederationRefuseActivities: "Types of activity to refuse from remote instances, eg. Like to refuse likes from everywhere, or Announce@example.org to refuse boosts from example.org and its subdomains.",
 	LetsEncryptEnabled:         "Enable letsencrypt TLS certs for this server. If set to true, then cert dir also needs to be set (or take the default).",
 	LetsEncryptPort:            "Port to listen on for letsencrypt certificate challenges. Must not be the same as the GtS webserver/API port.",
 	LetsEncryptCertDir:         "Directory to store acquired letsencrypt certificates.",
@@ -75,8 +114,17 @@ var usage = config.KeyNames{
 	SyslogEnabled:              "Enable the syslog logging hook. Logs will be mirrored to the configured destination.",
 	SyslogProtocol:             "Protocol to use when directing logs to syslog. Leave empty to connect to local syslog.",
 	SyslogAddress:              "Address:port to send syslog logs to. Leave empty to connect to local syslog.",
+	MetricsEnabled:             "Expose Prometheus metrics at /metrics.",
+	RateLimitRequests:          "Maximum number of requests that can be made to the client API with any one access token, or from any one IP address without a token, in 5 minutes. If set to 0, requests aren't limited.",
+	RateLimitExpensiveRequests: "Maximum number of requests that can be made to expensive client API endpoints, like search and media uploads, with any one access token or from any one IP address in 5 minutes. If set to 0, these requests aren't limited separately.",
+	TranslationBackend:         "Backend to use for translating statuses. Leave empty to disable translation. Options: [libretranslate, deepl]",
+	TranslationEndpoint:        "URL of the translation backend's API. Leave empty to use the default endpoint of the backend, if it has one.",
+	TranslationAPIKey:          "API key to use with the translation backend.",
 	AdminAccountUsername:       "the username to create/delete/etc",
 	AdminAccountEmail:          "the email address of this account",
 	AdminAccountPassword:       "the password to set for this account",
+	AdminAccountQuota:          "the media storage quota to set for this account in bytes: 0 to use the instance default, or -1 for unlimited",
 	AdminTransPath:             "the path of the file to import from/export to",
+	AdminMediaFrom:             "the storage backend to copy media from: [local, s3]",
+	AdminMediaTo:               "the storage backend to copy media to: [local, s3]",
 }
@@ -55,18 +55,60 @@ const (
 	ActorPerson       = "Person"       // ActivityStreamsPerson https://www.w3.org/TR/activitystreams-vocabulary/#dfn-person
 	ActorService      = "Service"      // ActivityStreamsService https://www.w3.org/TR/activitystreams-vocabulary/#dfn-service
 
-	ObjectArticle        = "Article"        // ActivityStreamsArticle https://www.w3.org/TR/activitystreams-vocabulary/#dfn-article
-	ObjectAudio          = "Audio"          // ActivityStreamsAudio https://www.w3.org/TR/activitystreams-vocabulary/#dfn-audio
-	ObjectDocument       = "Document"       // ActivityStreamsDocument https://www.w3.org/TR/activitystreams-vocabulary/#dfn-document
-	ObjectEvent          = "Event"          // ActivityStreamsEvent https://www.w3.org/TR/activitystreams-vocabulary/#dfn-event
-	ObjectImage          = "Image"          // ActivityStreamsImage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-image
-	ObjectNote           = "Note"           // ActivityStreamsNote https://www.w3.org/TR/activitystreams-vocabulary/#dfn-note
-	ObjectPage           = "Page"           // ActivityStreamsPage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-page
-	ObjectPlace          = "Place"          // ActivityStreamsPlace https://www.w3.org/TR/activitystreams-vocabulary/#dfn-place
-	ObjectProfile        = "Profile"        // ActivityStreamsProfile https://www.w3.org/TR/activitystreams-vocabulary/#dfn-profile
-	ObjectRelationship   = "Relationship"   // ActivityStreamsRelationship https://www.w3.org/TR/activitystreams-vocabulary/#dfn-relationship
-	ObjectTombstone      = "Tombstone"      // ActivityStreamsTombstone https://www.w3.org/TR/activitystreams-vocabulary/#dfn-tombstone
-	ObjectVideo          = "Video"          // ActivityStreamsVideo https://www.w3.org/TR/activitystreams-vocabulary/#dfn-video
-	ObjectCollection     = "Collection"     // ActivityStreamsCollection https://www.w3.org/TR/activitystreams-vocabulary/#dfn-collection
-	ObjectCollectionPage = "CollectionPage" // ActivityStreamsCollectionPage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-collectionpage
+	ObjectArticle               = "Article"               // ActivityStreamsArticle https://www.w3.org/TR/activitystreams-vocabulary/#dfn-article
+	ObjectAudio                 = "Audio"                 // ActivityStreamsAudio https://www.w3.org/TR/activitystreams-vocabulary/#dfn-audio
+	ObjectDocument              = "Document"              // ActivityStreamsDocument https://www.w3.org/TR/activitystreams-vocabulary/#dfn-document
+	ObjectEvent                 = "Event"                 // ActivityStreamsEvent https://www.w3.org/TR/activitystreams-vocabulary/#dfn-event
+	ObjectImage                 = "Image"                 // ActivityStreamsImage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-image
+	ObjectNote                  = "Note"                  // ActivityStreamsNote https://www.w3.org/TR/activitystreams-vocabulary/#dfn-note
+	ObjectPage                  = "Page"                  // ActivityStreamsPage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-page
+	ObjectPlace                 = "Place"                 // ActivityStreamsPlace https://www.w3.org/TR/activitystreams-vocabulary/#dfn-place
+	ObjectProfile               = "Profile"               // ActivityStreamsProfile https://www.w3.org/TR/activitystreams-vocabulary/#dfn-profile
+	ObjectRelationship          = "Relationship"          // ActivityStreamsRelationship https://www.w3.org/TR/activitystreams-vocabulary/#dfn-relationship
+	ObjectTombstone             = "Tombstone"             // ActivityStreamsTombstone https://www.w3.org/TR/activitystreams-vocabulary/#dfn-tombstone
+	ObjectVideo                 = "Video"                 // ActivityStreamsVideo https://www.w3.org/TR/activitystreams-vocabulary/#dfn-video
+	ObjectCollection            = "Collection"            // ActivityStreamsCollection https://www.w3.org/TR/activitystreams-vocabulary/#dfn-collection
+	ObjectCollectionPage        = "CollectionPage"        // ActivityStreamsCollectionPage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-collectionpage
+	ObjectOrderedCollection     = "OrderedCollection"     // ActivityStreamsOrderedCollection https://www.w3.org/TR/activitystreams-vocabulary/#dfn-orderedcollection
+	ObjectOrderedCollectionPage = "OrderedCollectionPage" // ActivityStreamsOrderedCollectionPage https://www.w3.org/TR/activitystreams-vocabulary/#dfn-orderedcollectionpage
+)
+
+// Properties that are used by other fediverse software, but aren't part of the
+// go-fed vocabulary, so have to be read and written as unknown properties.
+const (
+	PropertyAlsoKnownAs     = "alsoKnownAs"       // other actors that this actor is also known as, see https://www.w3.org/TR/did-core/#also-known-as
+	PropertyMovedTo         = "movedTo"           // the actor that this actor has moved to, set by mastodon after a Move
+	PropertyFeaturedTags    = "featuredTags"      // the collection of hashtags that an actor features on its profile, set by mastodon
+	PropertyEndorsements    = "endorsements"      // the collection of accounts that an actor features on its profile, set by mastodon
+	PropertyMisskeyReaction = "_misskey_reaction" // the emoji of a Like that's an emoji reaction, set by misskey alongside content
+	PropertyQuoteURL        = "quoteUrl"          // the status that a status quotes, set by misskey and others
+	PropertyQuoteURI        = "quoteUri"          // the status that a status quotes, set by fedibird
+	PropertyMisskeyQuote    = "_misskey_quote"    // the status that a status quotes, set by older versions of misskey
+	PropertyAssertionMethod = "assertionMethod"   // keys that an actor makes integrity proofs with, see https://www.w3.org/TR/controller-document/#assertion
+	PropertyProof           = "proof"             // the integrity proof of an object or activity, see https://www.w3.org/TR/vc-data-integrity/#proofs
+	PropertyEndpoints       = "endpoints"         // endpoints of an actor's instance, such as its sharedInbox, see https://www.w3.org/TR/activitypub/#actor-objects
+)
+
+// MediaTypeActivityStreams is the media type of links to activitystreams objects, as used by links
+// from one status to another that it quotes, see https://codeberg.org/fediverse/fep/src/branch/main/feps/fep-e232.md
+const MediaTypeActivityStreams = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+// Activity types that are used by other fediverse software, but aren't part of the go-fed vocabulary.
+const (
+	ActivityEmojiReact = "EmojiReact" // an emoji reaction to a status, sent by pleroma; handled as a Like with the emoji as its content
+)
+
+// Object types that are used by other fediverse software, but aren't part of the go-fed vocabulary.
+const (
+	ObjectHashtag = "Hashtag" // a hashtag, as used by mastodon in tags and featured tags collections
+)
+
+// Object types that are used internally, but are federated as one of the object types of the go-fed vocabulary.
+const (
+	ObjectPollVote = "PollVote" // a vote in a poll, federated as a Note that's named after the chosen option and in reply to the poll
+)
+
+// Object types that are only used internally, and are never federated.
+const (
+	ObjectMute = "Mute" // one account muting another
 )
@@ -36,6 +36,12 @@ const (
 	ContextRequestingPublicKeyVerifier ContextKey = "requestingPublicKeyVerifier"
 	// ContextRequestingPublicKeySignature can be used to set and retrieve the value of the signature header of an incoming federation request.
 	ContextRequestingPublicKeySignature ContextKey = "requestingPublicKeySignature"
+	// ContextActivityBody can be used to set and retrieve the raw json body of an incoming activity,
+	// which is needed to verify an integrity proof of the activity exactly as it was sent.
+	ContextActivityBody ContextKey = "activityBody"
+	// ContextProofOwnerIRI can be used to set and retrieve the actor whose integrity proof of an incoming activity was verified.
+	// If it's set, the activity really is from that actor, even if it was forwarded to us by someone else.
+	ContextProofOwnerIRI ContextKey = "proofOwnerIRI"
 	// ContextFromFederatorChan can be used to pass a pointer to the fromFederator channel into the federator for use in callbacks.
 	ContextFromFederatorChan ContextKey = "fromFederatorChan"
 )
@@ -22,6 +22,8 @@
 package ap
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -167,6 +169,16 @@ func ExtractPublished(i WithPublished) (time.Time, error) {
 	return t, nil
 }
 
+// ExtractUpdated extracts the time that an activity was last updated. If it
+// was never updated, or the updated time isn't set, the zero time is returned.
+func ExtractUpdated(i WithUpdated) time.Time {
+	updatedProp := i.GetActivityStreamsUpdated()
+	if updatedProp == nil || !updatedProp.IsXMLSchemaDateTime() {
+		return time.Time{}
+	}
+	return updatedProp.Get()
+}
+
 // ExtractIconURL extracts a URL to a supported image file from something like:
 //   "icon": {
 //     "mediaType": "image/jpeg",
@@ -285,7 +297,9 @@ func ExtractURL(i WithURL) (*url.URL, error) {
 
 // ExtractPublicKeyForOwner extracts the public key from an interface, as long as it belongs to the specified owner.
 // It will return the public key itself, the id/URL of the public key, or an error if something goes wrong.
-func ExtractPublicKeyForOwner(i WithPublicKey, forOwner *url.URL) (*rsa.PublicKey, *url.URL, error) {
+//
+// The returned key will be either an *rsa.PublicKey or an ed25519.PublicKey; other key types aren't supported.
+func ExtractPublicKeyForOwner(i WithPublicKey, forOwner *url.URL) (crypto.PublicKey, *url.URL, error) {
 	publicKeyProp := i.GetW3IDSecurityV1PublicKey()
 	if publicKeyProp == nil {
 		return nil, nil, errors.New("public key property was nil")
@@ -328,7 +342,10 @@ func ExtractPublicKeyForOwner(i WithPublicKey, forOwner *url.URL) (*rsa.PublicKe
 			return nil, nil, errors.New("returned public key was empty")
 		}
 
-		if publicKey, ok := p.(*rsa.PublicKey); ok {
+		switch publicKey := p.(type) {
+		case *rsa.PublicKey:
+			return publicKey, pkeyID, nil
+		case ed25519.PublicKey:
 			return publicKey, pkeyID, nil
 		}
 	}
@@ -611,6 +628,303 @@ func ExtractObject(i WithObject) (*url.URL, error) {
 	return nil, errors.New("no iri found for object prop")
 }
 
+// ErrAnnouncedActivity is returned by ExtractAnnounced when an announce is of an activity that isn't a
+// create, such as a like or a delete that a group is passing on to its followers.
+var ErrAnnouncedActivity = errors.New("announce is of an activity that can't be shown as a boost")
+
+// ExtractAnnounced extracts the URI of the status that an announce is boosting. Usually the object of an
+// announce is just the status' IRI, but groups (see FEP-1b12) announce the activities of their members
+// instead, so if the object is an embedded create, the URI of the status it created is returned.
+func ExtractAnnounced(i WithObject) (*url.URL, error) {
+	objectProp := i.GetActivityStreamsObject()
+	if objectProp == nil {
+		return nil, errors.New("object property was nil")
+	}
+	for iter := objectProp.Begin(); iter != objectProp.End(); iter = iter.Next() {
+		if iter.IsIRI() && iter.GetIRI() != nil {
+			return iter.GetIRI(), nil
+		}
+
+		if iter.IsActivityStreamsCreate() {
+			return ExtractObjectID(iter.GetActivityStreamsCreate())
+		}
+
+		t := iter.GetType()
+		if t == nil {
+			continue
+		}
+		switch t.GetTypeName() {
+		case ObjectArticle, ObjectDocument, ObjectImage, ObjectVideo, ObjectNote, ObjectPage, ObjectEvent, ObjectPlace, ObjectProfile, ActivityQuestion:
+			// an embedded status
+			if idProp := t.GetJSONLDId(); idProp != nil && idProp.IsIRI() {
+				return idProp.GetIRI(), nil
+			}
+		default:
+			return nil, ErrAnnouncedActivity
+		}
+	}
+	return nil, errors.New("no iri found for object prop")
+}
+
+// ExtractObjectID extracts the URI of the first object of a WithObject interface,
+// whether the object is given as an IRI, or embedded in the interface.
+func ExtractObjectID(i WithObject) (*url.URL, error) {
+	objectProp := i.GetActivityStreamsObject()
+	if objectProp == nil {
+		return nil, errors.New("object property was nil")
+	}
+	for iter := objectProp.Begin(); iter != objectProp.End(); iter = iter.Next() {
+		if iter.IsIRI() && iter.GetIRI() != nil {
+			return iter.GetIRI(), nil
+		}
+		if t := iter.GetType(); t != nil {
+			if idProp := t.GetJSONLDId(); idProp != nil && idProp.IsIRI() {
+				return idProp.GetIRI(), nil
+			}
+		}
+	}
+	return nil, errors.New("no iri found for object prop")
+}
+
+// ExtractObjects extracts all the URL objects from a WithObject interface.
+func ExtractObjects(i WithObject) ([]*url.URL, error) {
+	objectProp := i.GetActivityStreamsObject()
+	if objectProp == nil {
+		return nil, errors.New("object property was nil")
+	}
+	objects := []*url.URL{}
+	for iter := objectProp.Begin(); iter != objectProp.End(); iter = iter.Next() {
+		if iter.IsIRI() && iter.GetIRI() != nil {
+			objects = append(objects, iter.GetIRI())
+		}
+	}
+	if len(objects) == 0 {
+		return nil, errors.New("no iris found for object prop")
+	}
+	return objects, nil
+}
+
+// ExtractTarget extracts a URL target from a WithTarget interface.
+func ExtractTarget(i WithTarget) (*url.URL, error) {
+	targetProp := i.GetActivityStreamsTarget()
+	if targetProp == nil {
+		return nil, errors.New("target property was nil")
+	}
+	for iter := targetProp.Begin(); iter != targetProp.End(); iter = iter.Next() {
+		if iter.IsIRI() && iter.GetIRI() != nil {
+			return iter.GetIRI(), nil
+		}
+	}
+	return nil, errors.New("no iri found for target prop")
+}
+
+// ExtractAlsoKnownAs extracts the URIs of the alsoKnownAs property of an interface, if it's set.
+// The property can be a single IRI, an object with an id, or an array of either.
+func ExtractAlsoKnownAs(i WithUnknownProperties) []*url.URL {
+	uris := []*url.URL{}
+
+	var values []interface{}
+	switch v := i.GetUnknownProperties()[PropertyAlsoKnownAs].(type) {
+	case []interface{}:
+		values = v
+	case nil:
+		return uris
+	default:
+		values = []interface{}{v}
+	}
+
+	for _, v := range values {
+		if uri := unknownPropertyIRI(v); uri != nil {
+			uris = append(uris, uri)
+		}
+	}
+
+	return uris
+}
+
+// ExtractMovedTo extracts the URI of the movedTo property of an interface, or nil if it's not set.
+func ExtractMovedTo(i WithUnknownProperties) *url.URL {
+	return unknownPropertyIRI(i.GetUnknownProperties()[PropertyMovedTo])
+}
+
+// ExtractSharedInbox extracts the URI of the sharedInbox endpoint of an actor, or nil if it's not set.
+func ExtractSharedInbox(i WithUnknownProperties) *url.URL {
+	endpoints, ok := i.GetUnknownProperties()[PropertyEndpoints].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return unknownPropertyIRI(endpoints["sharedInbox"])
+}
+
+// ExtractQuoteURI extracts the URI of the status that the given status quotes, or nil if it doesn't quote anything.
+//
+// Since there's no single agreed way of marking a quote yet, the properties used by misskey and fedibird are
+// checked first, followed by links to activitystreams objects in the tags, as described in FEP-e232.
+func ExtractQuoteURI(i Statusable) *url.URL {
+	for _, property := range []string{PropertyQuoteURI, PropertyQuoteURL, PropertyMisskeyQuote} {
+		if uri := unknownPropertyIRI(i.GetUnknownProperties()[property]); uri != nil {
+			return uri
+		}
+	}
+
+	tagsProp := i.GetActivityStreamsTag()
+	if tagsProp == nil {
+		return nil
+	}
+	for iter := tagsProp.Begin(); iter != tagsProp.End(); iter = iter.Next() {
+		if !iter.IsActivityStreamsLink() {
+			continue
+		}
+		link := iter.GetActivityStreamsLink()
+
+		mediaTypeProp := link.GetActivityStreamsMediaType()
+		if mediaTypeProp == nil || !isActivityStreamsMediaType(mediaTypeProp.Get()) {
+			continue
+		}
+
+		hrefProp := link.GetActivityStreamsHref()
+		if hrefProp != nil && hrefProp.IsIRI() {
+			return hrefProp.GetIRI()
+		}
+	}
+
+	return nil
+}
+
+// isActivityStreamsMediaType returns true if the given media type is one that's used for activitystreams objects.
+func isActivityStreamsMediaType(mediaType string) bool {
+	mediaType = strings.ReplaceAll(mediaType, " ", "")
+	return mediaType == strings.ReplaceAll(MediaTypeActivityStreams, " ", "") || mediaType == "application/activity+json"
+}
+
+// ExtractRawIRI parses the value of a property of a json document that's been decoded without go-fed as an IRI,
+// where the value is either the IRI as a string, or an object with the IRI set as its id.
+func ExtractRawIRI(v interface{}) *url.URL {
+	return unknownPropertyIRI(v)
+}
+
+// unknownPropertyIRI parses the value of an unknown property as an IRI, where the
+// value is either the IRI as a string, or an object with the IRI set as its id.
+func unknownPropertyIRI(v interface{}) *url.URL {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case map[string]interface{}:
+		s, _ = v["id"].(string)
+	}
+
+	if s == "" {
+		return nil
+	}
+
+	uri, err := url.Parse(s)
+	if err != nil || uri.Scheme == "" || uri.Host == "" {
+		return nil
+	}
+
+	return uri
+}
+
+// ExtractReaction returns the emoji that the given reaction was made with, or an empty string if it's
+// just a plain like. The emoji is taken from the content, or from the misskey-specific reaction property
+// if the content isn't set. Custom emojis are returned as their :shortcode:.
+func ExtractReaction(i Reactable) string {
+	if content, err := ExtractContent(i); err == nil && content != "" {
+		return strings.TrimSpace(content)
+	}
+
+	if reaction, ok := i.GetUnknownProperties()[PropertyMisskeyReaction].(string); ok {
+		return strings.TrimSpace(reaction)
+	}
+
+	return ""
+}
+
+// ExtractPoll extracts a gts model poll from a Pollable interface. The options of the poll are taken from oneOf
+// if it's set, or otherwise from anyOf, in which case voters are allowed to choose more than one option.
+//
+// The ID and StatusID of the returned poll aren't set, that's up to the caller.
+func ExtractPoll(i Pollable) (*gtsmodel.Poll, error) {
+	poll := &gtsmodel.Poll{}
+
+	var options []PollOptionable
+	if oneOf := i.GetActivityStreamsOneOf(); oneOf != nil && oneOf.Len() != 0 {
+		for iter := oneOf.Begin(); iter != oneOf.End(); iter = iter.Next() {
+			if option, ok := iter.GetType().(PollOptionable); ok {
+				options = append(options, option)
+			}
+		}
+	} else if anyOf := i.GetActivityStreamsAnyOf(); anyOf != nil {
+		poll.Multiple = true
+		for iter := anyOf.Begin(); iter != anyOf.End(); iter = iter.Next() {
+			if option, ok := iter.GetType().(PollOptionable); ok {
+				options = append(options, option)
+			}
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, errors.New("ExtractPoll: poll had no options")
+	}
+
+	for _, option := range options {
+		name, err := ExtractName(option)
+		if err != nil {
+			return nil, fmt.Errorf("ExtractPoll: error extracting name of poll option: %s", err)
+		}
+		poll.Options = append(poll.Options, name)
+		poll.Votes = append(poll.Votes, extractRepliesCount(option))
+	}
+
+	if endTime := i.GetActivityStreamsEndTime(); endTime != nil && endTime.IsXMLSchemaDateTime() {
+		poll.ExpiresAt = endTime.Get()
+	}
+
+	// closed is either the time that the poll was closed, or just true
+	if closed := i.GetActivityStreamsClosed(); closed != nil {
+		for iter := closed.Begin(); iter != closed.End(); iter = iter.Next() {
+			if iter.IsXMLSchemaDateTime() {
+				poll.ClosedAt = iter.GetXMLSchemaDateTime()
+				break
+			}
+			if iter.IsXMLSchemaBoolean() && iter.GetXMLSchemaBoolean() {
+				poll.ClosedAt = poll.ExpiresAt
+				if poll.ClosedAt.IsZero() {
+					poll.ClosedAt = time.Now()
+				}
+				break
+			}
+		}
+	}
+
+	if votersCount := i.GetTootVotersCount(); votersCount != nil && votersCount.IsXMLSchemaNonNegativeInteger() {
+		poll.VotersCount = votersCount.Get()
+	} else if !poll.Multiple {
+		// every voter can only vote once, so the number of voters is the number of votes
+		for _, v := range poll.Votes {
+			poll.VotersCount += v
+		}
+	}
+
+	return poll, nil
+}
+
+// extractRepliesCount returns the totalItems of the replies collection of the given interface, or 0 if it's not set.
+func extractRepliesCount(i WithReplies) int {
+	repliesProp := i.GetActivityStreamsReplies()
+	if repliesProp == nil || !repliesProp.IsActivityStreamsCollection() {
+		return 0
+	}
+
+	totalItemsProp := repliesProp.GetActivityStreamsCollection().GetActivityStreamsTotalItems()
+	if totalItemsProp == nil || !totalItemsProp.IsXMLSchemaNonNegativeInteger() {
+		return 0
+	}
+
+	return totalItemsProp.Get()
+}
+
 // ExtractVisibility extracts the gtsmodel.Visibility of a given addressable with a To and CC property.
 //
 // ActorFollowersURI is needed to check whether the visibility is FollowersOnly or not. The passed-in value
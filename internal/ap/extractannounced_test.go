@@ -0,0 +1,97 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ap_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+type ExtractAnnouncedTestSuite struct {
+	ExtractTestSuite
+}
+
+func (suite *ExtractAnnouncedTestSuite) announce(s string) ap.Announceable {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(s), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	announce, ok := t.(ap.Announceable)
+	suite.True(ok)
+	return announce
+}
+
+func (suite *ExtractAnnouncedTestSuite) TestExtractAnnouncedIRI() {
+	announced, err := ap.ExtractAnnounced(suite.addressable4)
+	suite.NoError(err)
+	suite.Equal("https://another.instance/users/someone_else/statuses/107026674805188668", announced.String())
+}
+
+func (suite *ExtractAnnouncedTestSuite) TestExtractAnnouncedCreate() {
+	announce := suite.announce(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://lemmy.example.org/activities/announce/1",
+		"type": "Announce",
+		"actor": "https://lemmy.example.org/c/gardening",
+		"object": {
+			"id": "https://lemmy.example.org/activities/create/1",
+			"type": "Create",
+			"actor": "https://lemmy.example.org/u/someone",
+			"object": {
+				"id": "https://lemmy.example.org/post/1",
+				"type": "Page",
+				"name": "look at my tomatoes"
+			}
+		}
+	}`)
+
+	announced, err := ap.ExtractAnnounced(announce)
+	suite.NoError(err)
+	suite.Equal("https://lemmy.example.org/post/1", announced.String())
+}
+
+func (suite *ExtractAnnouncedTestSuite) TestExtractAnnouncedLike() {
+	announce := suite.announce(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://lemmy.example.org/activities/announce/2",
+		"type": "Announce",
+		"actor": "https://lemmy.example.org/c/gardening",
+		"object": {
+			"id": "https://lemmy.example.org/activities/like/1",
+			"type": "Like",
+			"actor": "https://lemmy.example.org/u/someone",
+			"object": "https://lemmy.example.org/post/1"
+		}
+	}`)
+
+	_, err := ap.ExtractAnnounced(announce)
+	suite.ErrorIs(err, ap.ErrAnnouncedActivity)
+}
+
+func TestExtractAnnouncedTestSuite(t *testing.T) {
+	suite.Run(t, &ExtractAnnouncedTestSuite{})
+}
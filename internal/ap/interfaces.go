@@ -40,17 +40,22 @@ type Accountable interface {
 	WithFollowers
 	WithFeatured
 	WithManuallyApprovesFollowers
+	WithTag
+	WithUnknownProperties
 }
 
 // Statusable represents the minimum activitypub interface for representing a 'status'.
-// This interface is fulfilled by: Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile
+// This interface is fulfilled by: Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile, Question
 type Statusable interface {
+	vocab.Type
 	WithJSONLDId
 	WithTypeName
 
+	WithName
 	WithSummary
 	WithInReplyTo
 	WithPublished
+	WithUpdated
 	WithURL
 	WithAttributedTo
 	WithTo
@@ -61,6 +66,26 @@ type Statusable interface {
 	WithAttachment
 	WithTag
 	WithReplies
+	WithUnknownProperties
+}
+
+// Pollable represents the minimum activitypub interface for representing a 'poll': a status with options that can be voted for.
+// This interface is fulfilled by: Question
+type Pollable interface {
+	Statusable
+
+	WithOneOf
+	WithAnyOf
+	WithEndTime
+	WithClosed
+	WithVotersCount
+}
+
+// PollOptionable represents the minimum activitypub interface for representing one of the options of a 'poll'.
+// This interface is fulfilled by: Note
+type PollOptionable interface {
+	WithName
+	WithReplies
 }
 
 // Attachmentable represents the minimum activitypub interface for representing a 'mediaAttachment'.
@@ -113,6 +138,16 @@ type Likeable interface {
 	WithObject
 }
 
+// Reactable represents the minimum interface for an emoji reaction, which is a 'like' activity with the emoji as its content.
+// Reactions with a custom emoji have the emoji set as a tag.
+type Reactable interface {
+	Likeable
+
+	WithContent
+	WithTag
+	WithUnknownProperties
+}
+
 // Blockable represents the minimum interface for an activitystreams 'block' activity.
 type Blockable interface {
 	WithJSONLDId
@@ -122,6 +157,16 @@ type Blockable interface {
 	WithObject
 }
 
+// Flaggable represents the minimum interface for an activitystreams 'flag' activity.
+type Flaggable interface {
+	WithJSONLDId
+	WithTypeName
+
+	WithActor
+	WithObject
+	WithContent
+}
+
 // Announceable represents the minimum interface for an activitystreams 'announce' activity.
 type Announceable interface {
 	WithJSONLDId
@@ -134,6 +179,16 @@ type Announceable interface {
 	WithCC
 }
 
+// Moveable represents the minimum interface for an activitystreams 'move' activity.
+type Moveable interface {
+	WithJSONLDId
+	WithTypeName
+
+	WithActor
+	WithObject
+	WithTarget
+}
+
 // Addressable represents the minimum interface for an addressed activity.
 type Addressable interface {
 	WithTo
@@ -314,6 +369,36 @@ type WithObject interface {
 	GetActivityStreamsObject() vocab.ActivityStreamsObjectProperty
 }
 
+// WithTarget represents an activity with ActivityStreamsTargetProperty
+type WithTarget interface {
+	GetActivityStreamsTarget() vocab.ActivityStreamsTargetProperty
+}
+
+// WithOneOf represents an activity with ActivityStreamsOneOfProperty
+type WithOneOf interface {
+	GetActivityStreamsOneOf() vocab.ActivityStreamsOneOfProperty
+}
+
+// WithAnyOf represents an activity with ActivityStreamsAnyOfProperty
+type WithAnyOf interface {
+	GetActivityStreamsAnyOf() vocab.ActivityStreamsAnyOfProperty
+}
+
+// WithEndTime represents an activity with ActivityStreamsEndTimeProperty
+type WithEndTime interface {
+	GetActivityStreamsEndTime() vocab.ActivityStreamsEndTimeProperty
+}
+
+// WithClosed represents an activity with ActivityStreamsClosedProperty
+type WithClosed interface {
+	GetActivityStreamsClosed() vocab.ActivityStreamsClosedProperty
+}
+
+// WithVotersCount represents an activity with TootVotersCountProperty
+type WithVotersCount interface {
+	GetTootVotersCount() vocab.TootVotersCountProperty
+}
+
 // WithNext represents an activity with ActivityStreamsNextProperty
 type WithNext interface {
 	GetActivityStreamsNext() vocab.ActivityStreamsNextProperty
@@ -333,3 +418,9 @@ type WithItems interface {
 type WithManuallyApprovesFollowers interface {
 	GetActivityStreamsManuallyApprovesFollowers() vocab.ActivityStreamsManuallyApprovesFollowersProperty
 }
+
+// WithUnknownProperties represents an activity with properties that go-fed doesn't have in its vocabulary,
+// such as alsoKnownAs and movedTo. These are kept as the plain values they were decoded into from json.
+type WithUnknownProperties interface {
+	GetUnknownProperties() map[string]interface{}
+}
@@ -0,0 +1,143 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ap_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+type JCSTestSuite struct {
+	suite.Suite
+}
+
+func (suite *JCSTestSuite) canonicalize(in string) string {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(in)))
+	decoder.UseNumber()
+
+	var v interface{}
+	suite.NoError(decoder.Decode(&v))
+
+	out, err := ap.CanonicalJSON(v)
+	suite.NoError(err)
+	return string(out)
+}
+
+// TestCanonicalJSONExample is the example from section 3.2.4 of RFC 8785.
+func (suite *JCSTestSuite) TestCanonicalJSONExample() {
+	in := `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+	suite.Equal(`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`, suite.canonicalize(in))
+}
+
+// TestCanonicalJSONSorting is the property sorting example from section 3.2.3 of RFC 8785.
+func (suite *JCSTestSuite) TestCanonicalJSONSorting() {
+	in := `{
+  "€": "Euro Sign",
+  "\r": "Carriage Return",
+  "דּ": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "😀": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "ö": "Latin Small Letter O With Diaeresis"
+}`
+	suite.Equal(`{"\r":"Carriage Return","1":"One","`+"\u0080"+`":"Control","ö":"Latin Small Letter O With Diaeresis",`+
+		`"€":"Euro Sign","😀":"Emoji: Grinning Face","`+"דּ"+`":"Hebrew Letter Dalet With Dagesh"}`, suite.canonicalize(in))
+}
+
+// TestCanonicalJSONNumbers checks the number serialization samples from appendix B of RFC 8785.
+func (suite *JCSTestSuite) TestCanonicalJSONNumbers() {
+	for bits, expected := range map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	} {
+		out, err := ap.CanonicalJSON(math.Float64frombits(bits))
+		suite.NoError(err)
+		suite.Equal(expected, string(out), "%016x", bits)
+	}
+
+	for _, bits := range []uint64{0x7fffffffffffffff, 0x7ff0000000000000} {
+		_, err := ap.CanonicalJSON(math.Float64frombits(bits))
+		suite.Error(err, "%016x", bits)
+	}
+}
+
+// TestBase58 checks the base58 encode and decode test vectors from bitcoin core, which uses the same alphabet as multibase base58btc.
+func (suite *JCSTestSuite) TestBase58() {
+	for _, v := range [][2]string{
+		{"", ""},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+		{"572e4794", "3EFU7m"},
+		{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+		{"10c8511e", "Rt5zm"},
+		{"00000000000000000000", "1111111111"},
+	} {
+		b, err := hex.DecodeString(v[0])
+		suite.NoError(err)
+		suite.Equal(v[1], ap.EncodeBase58(b), v[0])
+
+		decoded, err := ap.DecodeBase58(v[1])
+		suite.NoError(err)
+		suite.Equal(v[0], hex.EncodeToString(decoded), v[1])
+	}
+
+	_, err := ap.DecodeBase58("0OIl")
+	suite.Error(err)
+}
+
+func TestJCSTestSuite(t *testing.T) {
+	suite.Run(t, new(JCSTestSuite))
+}
@@ -0,0 +1,166 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ap
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// multikeyEd25519Prefix is the multicodec prefix of an ed25519 public key.
+var multikeyEd25519Prefix = []byte{0xed, 0x01}
+
+// base58Alphabet is the bitcoin base58 alphabet, which is what multibase base58btc uses.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeMultikey encodes the given ed25519 public key as a multibase string, as
+// used for the publicKeyMultibase of a Multikey, eg., z6MkrJVnaZkeFzdQyMZu1cgjg7k1pZZ6pvBQ7XJPt4swbTQ2.
+func EncodeMultikey(publicKey ed25519.PublicKey) string {
+	return "z" + encodeBase58(append(append([]byte{}, multikeyEd25519Prefix...), publicKey...))
+}
+
+// DecodeMultikey decodes the publicKeyMultibase of a Multikey. Only ed25519 keys encoded as base58btc are supported.
+func DecodeMultikey(multibase string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(multibase, "z") {
+		return nil, errors.New("multibase key wasn't encoded as base58btc")
+	}
+
+	b, err := decodeBase58(multibase[1:])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding multibase key: %s", err)
+	}
+
+	if len(b) != len(multikeyEd25519Prefix)+ed25519.PublicKeySize || b[0] != multikeyEd25519Prefix[0] || b[1] != multikeyEd25519Prefix[1] {
+		return nil, errors.New("multibase key wasn't an ed25519 public key")
+	}
+
+	return ed25519.PublicKey(b[len(multikeyEd25519Prefix):]), nil
+}
+
+// ExtractAssertionMethodForOwner extracts the ed25519 Multikey that the given owner makes integrity proofs with from the
+// assertionMethod property of an interface. It will return the key itself and the id/URL of the key, or an error if there's no such key.
+func ExtractAssertionMethodForOwner(i WithUnknownProperties, forOwner *url.URL) (ed25519.PublicKey, *url.URL, error) {
+	var values []interface{}
+	switch v := i.GetUnknownProperties()[PropertyAssertionMethod].(type) {
+	case []interface{}:
+		values = v
+	case map[string]interface{}:
+		values = []interface{}{v}
+	default:
+		return nil, nil, errors.New("assertion method property was not set")
+	}
+
+	for _, v := range values {
+		key, ok := v.(map[string]interface{})
+		if !ok {
+			// keys that are only linked to can't be used, since we'd have to fetch them separately
+			continue
+		}
+
+		if t, _ := key["type"].(string); t != "Multikey" {
+			continue
+		}
+
+		keyID := unknownPropertyIRI(key["id"])
+		if keyID == nil {
+			continue
+		}
+
+		if controller := unknownPropertyIRI(key["controller"]); controller == nil || controller.String() != forOwner.String() {
+			continue
+		}
+
+		multibase, _ := key["publicKeyMultibase"].(string)
+		publicKey, err := DecodeMultikey(multibase)
+		if err != nil {
+			continue
+		}
+
+		return publicKey, keyID, nil
+	}
+
+	return nil, nil, errors.New("couldn't find assertion method")
+}
+
+// encodeBase58 encodes b as base58, keeping leading zero bytes as leading 1s.
+func encodeBase58(b []byte) string {
+	zeroes := 0
+	for zeroes < len(b) && b[zeroes] == 0 {
+		zeroes++
+	}
+
+	// repeatedly divide the number by 58, collecting the remainders as digits, least significant first
+	digits := []byte{}
+	for _, c := range b[zeroes:] {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, 0, zeroes+len(digits))
+	for i := 0; i < zeroes; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		out = append(out, base58Alphabet[digits[i]])
+	}
+	return string(out)
+}
+
+// decodeBase58 decodes a base58 string that was encoded with encodeBase58.
+func decodeBase58(s string) ([]byte, error) {
+	zeroes := 0
+	for zeroes < len(s) && s[zeroes] == base58Alphabet[0] {
+		zeroes++
+	}
+
+	// repeatedly multiply the number by 58, collecting the bytes, least significant first
+	bytes := []byte{}
+	for _, r := range s[zeroes:] {
+		carry := strings.IndexRune(base58Alphabet, r)
+		if carry == -1 {
+			return nil, fmt.Errorf("invalid base58 character %q", r)
+		}
+		for i := range bytes {
+			carry += int(bytes[i]) * 58
+			bytes[i] = byte(carry & 0xff)
+			carry >>= 8
+		}
+		for carry > 0 {
+			bytes = append(bytes, byte(carry&0xff))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeroes, zeroes+len(bytes))
+	for i := len(bytes) - 1; i >= 0; i-- {
+		out = append(out, bytes[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,297 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ap
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Values used in integrity proofs, as described in https://codeberg.org/fediverse/fep/src/branch/main/fep/8b32/fep-8b32.md
+const (
+	ProofTypeDataIntegrity       = "DataIntegrityProof"                          // the type of a data integrity proof
+	ProofCryptosuiteEddsaJcs2022 = "eddsa-jcs-2022"                              // the only cryptosuite that proofs are made and verified with
+	ProofPurposeAssertionMethod  = "assertionMethod"                             // the purpose of proofs of activities and objects
+	ContextDataIntegrity         = "https://w3id.org/security/data-integrity/v1" // the json-ld context that defines data integrity proofs
+)
+
+const (
+	proofValueMultibaseBase58BTC    = "z"
+	proofDocumentContextProperty    = "@context"
+	proofValueProperty              = "proofValue"
+	proofVerificationMethodProperty = "verificationMethod"
+)
+
+// Proof is an eddsa-jcs-2022 integrity proof that was made of an activity or object, which
+// can be verified with the key that the proof names as its verification method.
+type Proof struct {
+	// VerificationMethod is the id of the Multikey that the proof was made with.
+	VerificationMethod *url.URL
+	// Owner is the actor of the activity, or the attributedTo of the object, that the proof was made of.
+	Owner *url.URL
+
+	document  map[string]interface{} // the activity or object, without the proof
+	options   map[string]interface{} // the proof, without the proof value
+	signature []byte
+}
+
+// AddProof adds an eddsa-jcs-2022 integrity proof, made with the given key, to the json activity or object in b, and
+// returns the json with the proof added. So that nobody else's activities are signed by mistake, for example when an
+// activity is being forwarded, a proof is only added if the activity's actor, or the object's attributedTo, is the given
+// owner, and if the json doesn't have a proof already. If no proof was added, b is returned as it was, along with false.
+func AddProof(b []byte, owner string, verificationMethod string, privateKey ed25519.PrivateKey, created time.Time) ([]byte, bool, error) {
+	document, err := decodeProofDocument(b)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, ok := document[PropertyProof]; ok {
+		return b, false, nil
+	}
+
+	if o := proofDocumentOwner(document); o == nil || o.String() != owner {
+		return b, false, nil
+	}
+
+	// the context of the proof has to match the context of the document it's a proof of
+	if documentContext, ok := document[proofDocumentContextProperty]; ok {
+		document[proofDocumentContextProperty] = appendContext(documentContext, ContextDataIntegrity)
+	}
+
+	options := map[string]interface{}{
+		"type":                          ProofTypeDataIntegrity,
+		"cryptosuite":                   ProofCryptosuiteEddsaJcs2022,
+		proofVerificationMethodProperty: verificationMethod,
+		"proofPurpose":                  ProofPurposeAssertionMethod,
+		"created":                       created.UTC().Format(time.RFC3339),
+	}
+	if documentContext, ok := document[proofDocumentContextProperty]; ok {
+		options[proofDocumentContextProperty] = documentContext
+	}
+
+	hash, err := proofHash(options, document)
+	if err != nil {
+		return nil, false, err
+	}
+
+	options[proofValueProperty] = proofValueMultibaseBase58BTC + encodeBase58(ed25519.Sign(privateKey, hash))
+	document[PropertyProof] = options
+
+	proved, err := json.Marshal(document)
+	if err != nil {
+		return nil, false, fmt.Errorf("error serializing document with proof: %s", err)
+	}
+
+	return proved, true, nil
+}
+
+// ExtractProof extracts the eddsa-jcs-2022 integrity proof from the json activity or object in b. If there
+// isn't one, nil will be returned, along with a nil error; proofs made with other cryptosuites are ignored,
+// since there's no way of verifying them, as are signatures made the old way with linked data signatures.
+func ExtractProof(b []byte) (*Proof, error) {
+	document, err := decodeProofDocument(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	switch v := document[PropertyProof].(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		values = v
+	default:
+		values = []interface{}{v}
+	}
+	delete(document, PropertyProof)
+
+	for _, v := range values {
+		options, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if t, _ := options["type"].(string); t != ProofTypeDataIntegrity {
+			continue
+		}
+		if c, _ := options["cryptosuite"].(string); c != ProofCryptosuiteEddsaJcs2022 {
+			continue
+		}
+
+		if p, _ := options["proofPurpose"].(string); p != ProofPurposeAssertionMethod {
+			return nil, fmt.Errorf("proof purpose %s isn't supported", p)
+		}
+
+		verificationMethod := unknownPropertyIRI(options[proofVerificationMethodProperty])
+		if verificationMethod == nil {
+			return nil, errors.New("proof has no verification method")
+		}
+
+		owner := proofDocumentOwner(document)
+		if owner == nil {
+			return nil, errors.New("proof is of a document without an actor or attributedTo")
+		}
+
+		proofValue, _ := options[proofValueProperty].(string)
+		if !strings.HasPrefix(proofValue, proofValueMultibaseBase58BTC) {
+			return nil, errors.New("proof value wasn't encoded as base58btc")
+		}
+		signature, err := decodeBase58(proofValue[len(proofValueMultibaseBase58BTC):])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding proof value: %s", err)
+		}
+
+		withoutValue := make(map[string]interface{}, len(options))
+		for k, v := range options {
+			if k != proofValueProperty {
+				withoutValue[k] = v
+			}
+		}
+
+		return &Proof{
+			VerificationMethod: verificationMethod,
+			Owner:              owner,
+			document:           document,
+			options:            withoutValue,
+			signature:          signature,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// Verify checks the proof against the given public key, which should be the key named as the proof's verification method.
+func (p *Proof) Verify(publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("public key wasn't an ed25519 key")
+	}
+
+	if proofContext, ok := p.options[proofDocumentContextProperty]; ok {
+		if !contextHasPrefix(p.document[proofDocumentContextProperty], proofContext) {
+			return errors.New("context of the proof doesn't match the context of the document")
+		}
+	}
+
+	hash, err := proofHash(p.options, p.document)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, hash, p.signature) {
+		return errors.New("proof didn't verify")
+	}
+
+	return nil
+}
+
+// proofHash returns the data that's signed to make a proof: the sha256 hash of the canonicalized
+// proof options, followed by the sha256 hash of the canonicalized document without its proof.
+func proofHash(options map[string]interface{}, document map[string]interface{}) ([]byte, error) {
+	canonicalOptions, err := canonicalJSON(options)
+	if err != nil {
+		return nil, fmt.Errorf("error canonicalizing proof: %s", err)
+	}
+
+	canonicalDocument, err := canonicalJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("error canonicalizing document: %s", err)
+	}
+
+	optionsHash := sha256.Sum256(canonicalOptions)
+	documentHash := sha256.Sum256(canonicalDocument)
+	return append(optionsHash[:], documentHash[:]...), nil
+}
+
+// decodeProofDocument decodes the json object in b, keeping numbers as they were written.
+func decodeProofDocument(b []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	document := make(map[string]interface{})
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("error decoding document: %s", err)
+	}
+	return document, nil
+}
+
+// proofDocumentOwner returns the actor of an activity, or the attributedTo of an object.
+func proofDocumentOwner(document map[string]interface{}) *url.URL {
+	if actor := unknownPropertyIRI(document["actor"]); actor != nil {
+		return actor
+	}
+	return unknownPropertyIRI(document["attributedTo"])
+}
+
+// appendContext adds the given context to the end of a json-ld @context, if it isn't in there already.
+func appendContext(documentContext interface{}, add string) interface{} {
+	var contexts []interface{}
+	switch c := documentContext.(type) {
+	case []interface{}:
+		contexts = c
+	default:
+		contexts = []interface{}{c}
+	}
+
+	for _, c := range contexts {
+		if s, ok := c.(string); ok && s == add {
+			return documentContext
+		}
+	}
+
+	return append(contexts, add)
+}
+
+// contextHasPrefix returns true if the document context starts with all of the entries of the proof context, in the same order.
+func contextHasPrefix(documentContext interface{}, proofContext interface{}) bool {
+	toSlice := func(c interface{}) []interface{} {
+		if s, ok := c.([]interface{}); ok {
+			return s
+		}
+		return []interface{}{c}
+	}
+
+	document := toSlice(documentContext)
+	proof := toSlice(proofContext)
+	if len(proof) > len(document) {
+		return false
+	}
+
+	for i := range proof {
+		a, err := canonicalJSON(proof[i])
+		if err != nil {
+			return false
+		}
+		b, err := canonicalJSON(document[i])
+		if err != nil {
+			return false
+		}
+		if !bytes.Equal(a, b) {
+			return false
+		}
+	}
+
+	return true
+}
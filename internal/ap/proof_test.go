@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ap_test
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+const proofTestActivity = `{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "actor": "https://example.org/users/alice",
+  "id": "https://example.org/users/alice/statuses/01G0KQ5NJ7D2NR2Q1N8CNTTCHF/activity",
+  "object": {
+    "attributedTo": "https://example.org/users/alice",
+    "content": "hello \u003cb\u003eworld\u003c/b\u003e 🦥",
+    "id": "https://example.org/users/alice/statuses/01G0KQ5NJ7D2NR2Q1N8CNTTCHF",
+    "type": "Note",
+    "replies": {"totalItems": 1.0e0}
+  },
+  "to": ["https://www.w3.org/ns/activitystreams#Public"],
+  "type": "Create"
+}`
+
+type ProofTestSuite struct {
+	suite.Suite
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+}
+
+func (suite *ProofTestSuite) SetupTest() {
+	suite.privateKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	suite.publicKey = suite.privateKey.Public().(ed25519.PublicKey)
+}
+
+func (suite *ProofTestSuite) TestMultikeyRoundTrip() {
+	multibase := "z6MkrJVnaZkeFzdQyMZu1cgjg7k1pZZ6pvBQ7XJPt4swbTQ2"
+
+	publicKey, err := ap.DecodeMultikey(multibase)
+	suite.NoError(err)
+	suite.Len(publicKey, ed25519.PublicKeySize)
+	suite.Equal(multibase, ap.EncodeMultikey(publicKey))
+
+	_, err = ap.DecodeMultikey("mO+0BnQ==")
+	suite.Error(err)
+}
+
+func (suite *ProofTestSuite) TestAddAndVerifyProof() {
+	proved, added, err := ap.AddProof([]byte(proofTestActivity), "https://example.org/users/alice", "https://example.org/users/alice#ed25519-key", suite.privateKey, time.Now())
+	suite.NoError(err)
+	suite.True(added)
+	suite.Contains(string(proved), ap.ContextDataIntegrity)
+
+	proof, err := ap.ExtractProof(proved)
+	suite.NoError(err)
+	suite.NotNil(proof)
+	suite.Equal("https://example.org/users/alice#ed25519-key", proof.VerificationMethod.String())
+	suite.Equal("https://example.org/users/alice", proof.Owner.String())
+	suite.NoError(proof.Verify(suite.publicKey))
+
+	// a proof doesn't verify with someone else's key
+	otherKey := ed25519.NewKeyFromSeed(append(make([]byte, ed25519.SeedSize-1), 1))
+	suite.Error(proof.Verify(otherKey.Public().(ed25519.PublicKey)))
+}
+
+func (suite *ProofTestSuite) TestTamperedProof() {
+	proved, _, err := ap.AddProof([]byte(proofTestActivity), "https://example.org/users/alice", "https://example.org/users/alice#ed25519-key", suite.privateKey, time.Now())
+	suite.NoError(err)
+
+	tampered := []byte(strings.Replace(string(proved), "world", "moon", 1))
+	proof, err := ap.ExtractProof(tampered)
+	suite.NoError(err)
+	suite.NotNil(proof)
+	suite.Error(proof.Verify(suite.publicKey))
+}
+
+func (suite *ProofTestSuite) TestNoProofForSomeoneElse() {
+	// a forwarded activity mustn't be signed by the account forwarding it
+	proved, added, err := ap.AddProof([]byte(proofTestActivity), "https://example.org/users/bob", "https://example.org/users/bob#ed25519-key", suite.privateKey, time.Now())
+	suite.NoError(err)
+	suite.False(added)
+	suite.Equal(proofTestActivity, string(proved))
+}
+
+func (suite *ProofTestSuite) TestNoProof() {
+	proof, err := ap.ExtractProof([]byte(proofTestActivity))
+	suite.NoError(err)
+	suite.Nil(proof)
+
+	// proofs made with other cryptosuites are ignored
+	proof, err = ap.ExtractProof([]byte(`{"actor":"https://example.org/users/alice","type":"Create","proof":{"type":"DataIntegrityProof","cryptosuite":"eddsa-rdfc-2022","proofPurpose":"assertionMethod","verificationMethod":"https://example.org/users/alice#ed25519-key","proofValue":"z1"}}`))
+	suite.NoError(err)
+	suite.Nil(proof)
+}
+
+func TestProofTestSuite(t *testing.T) {
+	suite.Run(t, new(ProofTestSuite))
+}
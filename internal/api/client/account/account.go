@@ -63,6 +63,8 @@ const (
 	GetFollowersPath = BasePathWithID + "/followers"
 	// GetFollowingPath is for showing account's that an account follows.
 	GetFollowingPath = BasePathWithID + "/following"
+	// GetFeaturedTagsPath is for showing the hashtags that an account features on its profile
+	GetFeaturedTagsPath = BasePathWithID + "/featured_tags"
 	// GetRelationshipsPath is for showing an account's relationship with other accounts
 	GetRelationshipsPath = BasePath + "/relationships"
 	// FollowPath is for POSTing new follows to, and updating existing follows
@@ -73,8 +75,20 @@ const (
 	BlockPath = BasePathWithID + "/block"
 	// UnblockPath is for removing a block of an account
 	UnblockPath = BasePathWithID + "/unblock"
+	// MutePath is for muting an account, or updating an existing mute
+	MutePath = BasePathWithID + "/mute"
+	// UnmutePath is for removing a mute of an account
+	UnmutePath = BasePathWithID + "/unmute"
+	// PinPath is for featuring an account on one's profile
+	PinPath = BasePathWithID + "/pin"
+	// UnpinPath is for no longer featuring an account on one's profile
+	UnpinPath = BasePathWithID + "/unpin"
 	// DeleteAccountPath is for deleting one's account via the API
 	DeleteAccountPath = BasePath + "/delete"
+	// MoveAccountPath is for moving one's account to another account via the API
+	MoveAccountPath = BasePath + "/move"
+	// AliasesPath is for seeing and setting the aliases of one's account
+	AliasesPath = BasePath + "/aliases"
 )
 
 // Module implements the ClientAPIModule interface for account-related actions
@@ -97,6 +111,13 @@ func (m *Module) Route(r router.Router) error {
 	// delete account
 	r.AttachHandler(http.MethodPost, DeleteAccountPath, m.AccountDeletePOSTHandler)
 
+	// move account
+	r.AttachHandler(http.MethodPost, MoveAccountPath, m.AccountMovePOSTHandler)
+
+	// see or set account aliases
+	r.AttachHandler(http.MethodGet, AliasesPath, m.AccountAliasesGETHandler)
+	r.AttachHandler(http.MethodPost, AliasesPath, m.AccountAliasesPOSTHandler)
+
 	// get account
 	r.AttachHandler(http.MethodGet, BasePathWithID, m.muxHandler)
 
@@ -110,6 +131,9 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, GetFollowersPath, m.AccountFollowersGETHandler)
 	r.AttachHandler(http.MethodGet, GetFollowingPath, m.AccountFollowingGETHandler)
 
+	// get featured tags of account
+	r.AttachHandler(http.MethodGet, GetFeaturedTagsPath, m.AccountFeaturedTagsGETHandler)
+
 	// get relationship with account
 	r.AttachHandler(http.MethodGet, GetRelationshipsPath, m.AccountRelationshipsGETHandler)
 
@@ -121,6 +145,14 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodPost, BlockPath, m.AccountBlockPOSTHandler)
 	r.AttachHandler(http.MethodPost, UnblockPath, m.AccountUnblockPOSTHandler)
 
+	// mute or unmute account
+	r.AttachHandler(http.MethodPost, MutePath, m.AccountMutePOSTHandler)
+	r.AttachHandler(http.MethodPost, UnmutePath, m.AccountUnmutePOSTHandler)
+
+	// endorse or unendorse account
+	r.AttachHandler(http.MethodPost, PinPath, m.AccountPinPOSTHandler)
+	r.AttachHandler(http.MethodPost, UnpinPath, m.AccountUnpinPOSTHandler)
+
 	return nil
 }
 
@@ -32,12 +32,18 @@ import (
 //
 // Delete your account.
 //
+// Your statuses and media will be removed, other instances will be sent a Delete for your account,
+// and a stub of the account will be kept so that the username can't be taken again. This happens
+// in the background, so the account might not be fully deleted by the time a response is returned.
+//
 // ---
 // tags:
 // - accounts
 //
 // consumes:
 // - multipart/form-data
+// - application/json
+// - application/x-www-form-urlencoded
 //
 // parameters:
 // - name: password
@@ -57,6 +63,8 @@ import (
 //      description: bad request
 //   '401':
 //      description: unauthorized
+//   '403':
+//      description: forbidden
 func (m *Module) AccountDeletePOSTHandler(c *gin.Context) {
 	l := logrus.WithField("func", "AccountDeletePOSTHandler")
 	authed, err := oauth.Authed(c, true, true, true, true)
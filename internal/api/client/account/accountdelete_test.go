@@ -54,6 +54,19 @@ func (suite *AccountDeleteTestSuite) TestAccountDeletePOSTHandler() {
 	suite.Equal(http.StatusAccepted, recorder.Code)
 }
 
+func (suite *AccountDeleteTestSuite) TestAccountDeletePOSTHandlerJSON() {
+	// set up the request
+	// we're deleting zork, as the settings panel would
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, []byte(`{"password":"password"}`), account.DeleteAccountPath, "application/json")
+
+	// call the handler
+	suite.accountModule.AccountDeletePOSTHandler(ctx)
+
+	// 1. we should have Accepted because our request was valid
+	suite.Equal(http.StatusAccepted, recorder.Code)
+}
+
 func (suite *AccountDeleteTestSuite) TestAccountDeletePOSTHandlerWrongPassword() {
 	// set up the request
 	// we're deleting zork
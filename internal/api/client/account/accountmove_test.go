@@ -0,0 +1,107 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/account"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type AccountMoveTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *AccountMoveTestSuite) SetupTest() {
+	suite.AccountStandardTestSuite.SetupTest()
+
+	// moving changes the requesting account, so make sure every test starts with fresh accounts
+	suite.testAccounts = testrig.NewTestAccounts()
+}
+
+func (suite *AccountMoveTestSuite) move(password string, movedToURI string) *httptest.ResponseRecorder {
+	requestBody, w, err := testrig.CreateMultipartFormData(
+		"", "",
+		map[string]string{
+			"password":     password,
+			"moved_to_uri": movedToURI,
+		})
+	if err != nil {
+		panic(err)
+	}
+	bodyBytes := requestBody.Bytes()
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, bodyBytes, account.MoveAccountPath, w.FormDataContentType())
+
+	// call the handler
+	suite.accountModule.AccountMovePOSTHandler(ctx)
+
+	return recorder
+}
+
+func (suite *AccountMoveTestSuite) TestAccountMovePOSTHandler() {
+	// we're moving zork to turtle, so turtle needs to list zork as an alias first
+	zork := suite.testAccounts["local_account_1"]
+	turtle := suite.testAccounts["local_account_2"]
+	turtle.AlsoKnownAsURIs = []string{zork.URI}
+	if _, err := suite.db.UpdateAccount(context.Background(), turtle); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	recorder := suite.move("password", turtle.URI)
+
+	// we should have Accepted because our request was valid
+	suite.Equal(http.StatusAccepted, recorder.Code)
+
+	// zork should now be moved to turtle, and locked
+	dbZork, err := suite.db.GetAccountByID(context.Background(), zork.ID)
+	suite.NoError(err)
+	suite.Equal(turtle.ID, dbZork.MovedToAccountID)
+	suite.True(dbZork.Locked)
+}
+
+func (suite *AccountMoveTestSuite) TestAccountMovePOSTHandlerNotAliased() {
+	// turtle doesn't list zork as an alias, so zork can't move there
+	turtle := suite.testAccounts["local_account_2"]
+
+	recorder := suite.move("password", turtle.URI)
+
+	// we should have StatusBadRequest because the target account didn't agree to the move
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+
+	dbZork, err := suite.db.GetAccountByID(context.Background(), suite.testAccounts["local_account_1"].ID)
+	suite.NoError(err)
+	suite.Empty(dbZork.MovedToAccountID)
+}
+
+func (suite *AccountMoveTestSuite) TestAccountMovePOSTHandlerWrongPassword() {
+	recorder := suite.move("aaaaaaaaaaaaaaaaaaaaaaaaaaaa", suite.testAccounts["local_account_2"].URI)
+
+	// we should have Forbidden because we supplied the wrong password
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+func TestAccountMoveTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountMoveTestSuite))
+}
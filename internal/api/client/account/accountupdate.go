@@ -21,6 +21,9 @@ package account
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/sirupsen/logrus"
@@ -66,11 +69,11 @@ import (
 //   allowEmptyValue: true
 // - name: avatar
 //   in: formData
-//   description: Avatar of the user.
+//   description: Avatar of the user. Send an empty value instead of a file to remove the avatar.
 //   type: file
 // - name: header
 //   in: formData
-//   description: Header of the user.
+//   description: Header of the user. Send an empty value instead of a file to remove the header.
 //   type: file
 // - name: locked
 //   in: formData
@@ -88,6 +91,39 @@ import (
 //   in: formData
 //   description: Default language to use for authored statuses (ISO 6391).
 //   type: string
+// - name: source[excluded_notifications][]
+//   in: formData
+//   description: |-
+//     Types of notification that shouldn't be sent to the account, eg., favourite, reblog.
+//     Replaces any types that were excluded before; send a single empty value to exclude none.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+// - name: source[expand_media]
+//   in: formData
+//   description: How media attachments should be shown by default.
+//   type: string
+//   enum:
+//   - default
+//   - show_all
+//   - hide_all
+// - name: source[expand_spoilers]
+//   in: formData
+//   description: Expand content warnings by default.
+//   type: boolean
+// - name: fields_attributes[0][name]
+//   in: formData
+//   description: |-
+//     Name of the first profile field. Up to 4 fields can be set, with fields_attributes[1][name] and so on.
+//     The fields that are sent replace all the fields that were set before.
+//   type: string
+// - name: fields_attributes[0][value]
+//   in: formData
+//   description: |-
+//     Value of the first profile field. If this is a link to a web page that links back to the account
+//     with rel="me", the field is marked as verified once the page has been checked.
+//   type: string
 //
 // security:
 // - OAuth2 Bearer:
@@ -130,10 +166,15 @@ func (m *Module) AccountUpdateCredentialsPATCHHandler(c *gin.Context) {
 		form.Note == nil &&
 		form.Avatar == nil &&
 		form.Header == nil &&
+		!form.DeleteAvatar &&
+		!form.DeleteHeader &&
 		form.Locked == nil &&
 		form.Source.Privacy == nil &&
 		form.Source.Sensitive == nil &&
 		form.Source.Language == nil &&
+		form.Source.ExcludedNotifications == nil &&
+		form.Source.ExpandMedia == nil &&
+		form.Source.ExpandSpoilers == nil &&
 		form.FieldsAttributes == nil {
 		l.Debugf("could not parse form from request")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "empty form submitted"})
@@ -156,9 +197,17 @@ func parseUpdateAccountForm(c *gin.Context) (*model.UpdateCredentialsRequest, er
 	form := &model.UpdateCredentialsRequest{
 		Source: &model.UpdateSource{},
 	}
+
+	// an avatar or header sent as an empty value rather than a file means it should be removed;
+	// the empty values are taken out before binding, since gin would try to parse them as files
+	deleteAvatar := takeEmptyFormValue(c, "avatar")
+	deleteHeader := takeEmptyFormValue(c, "header")
+
 	if err := c.ShouldBind(&form); err != nil || form == nil {
 		return nil, fmt.Errorf("could not parse form from request: %s", err)
 	}
+	form.DeleteAvatar = deleteAvatar
+	form.DeleteHeader = deleteHeader
 
 	// parse source field-by-field
 	sourceMap := c.PostFormMap("source")
@@ -179,5 +228,97 @@ func parseUpdateAccountForm(c *gin.Context) (*model.UpdateCredentialsRequest, er
 		form.Source.Language = &language
 	}
 
+	if expandMedia, ok := sourceMap["expand_media"]; ok {
+		form.Source.ExpandMedia = &expandMedia
+	}
+
+	if expandSpoilers, ok := sourceMap["expand_spoilers"]; ok {
+		expandSpoilersBool, err := strconv.ParseBool(expandSpoilers)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing form source[expand_spoilers]: %s", err)
+		}
+		form.Source.ExpandSpoilers = &expandSpoilersBool
+	}
+
+	// send a single empty value to stop excluding any notification types
+	if excludedNotifications, ok := c.GetPostFormArray("source[excluded_notifications][]"); ok {
+		types := []string{}
+		for _, t := range excludedNotifications {
+			if t != "" {
+				types = append(types, t)
+			}
+		}
+		form.Source.ExcludedNotifications = &types
+	}
+
+	// profile fields can't be bound by gin, since each one is sent as
+	// fields_attributes[0][name] and fields_attributes[0][value] and so on
+	if form.FieldsAttributes == nil {
+		form.FieldsAttributes = parseFieldsAttributes(c.Request.PostForm)
+	}
+
 	return form, nil
 }
+
+// takeEmptyFormValue removes the given key from the form of the request if it was sent with an empty value,
+// and returns true if it was removed.
+func takeEmptyFormValue(c *gin.Context, key string) bool {
+	if value, ok := c.GetPostForm(key); !ok || value != "" {
+		return false
+	}
+
+	delete(c.Request.Form, key)
+	delete(c.Request.PostForm, key)
+	if c.Request.MultipartForm != nil {
+		delete(c.Request.MultipartForm.Value, key)
+	}
+	return true
+}
+
+var fieldsAttributesKey = regexp.MustCompile(`^fields_attributes\[(\d+)\]\[(name|value)\]$`)
+
+// parseFieldsAttributes returns the profile fields set in the given form values, in the order
+// of their indexes, or nil if no fields were set.
+func parseFieldsAttributes(values url.Values) *[]model.UpdateField {
+	fieldsByIndex := map[int]*model.UpdateField{}
+	for k, v := range values {
+		matches := fieldsAttributesKey.FindStringSubmatch(k)
+		if matches == nil || len(v) == 0 {
+			continue
+		}
+
+		index, err := strconv.Atoi(matches[1])
+		if err != nil {
+			continue
+		}
+
+		field, ok := fieldsByIndex[index]
+		if !ok {
+			field = &model.UpdateField{}
+			fieldsByIndex[index] = field
+		}
+
+		value := v[0]
+		if matches[2] == "name" {
+			field.Name = &value
+		} else {
+			field.Value = &value
+		}
+	}
+
+	if len(fieldsByIndex) == 0 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(fieldsByIndex))
+	for index := range fieldsByIndex {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	fields := make([]model.UpdateField, 0, len(indexes))
+	for _, index := range indexes {
+		fields = append(fields, *fieldsByIndex[index])
+	}
+	return &fields
+}
@@ -359,6 +359,88 @@ func (suite *AccountUpdateTestSuite) TestAccountUpdateCredentialsPATCHHandlerUpd
 	suite.True(apimodelAccount.Locked)
 }
 
+func (suite *AccountUpdateTestSuite) TestAccountUpdateCredentialsPATCHHandlerFields() {
+	// set up the request
+	// we're setting two profile fields on zork, out of order, and leaving one empty
+	requestBody, w, err := testrig.CreateMultipartFormData(
+		"", "",
+		map[string]string{
+			"fields_attributes[1][name]":  "website",
+			"fields_attributes[1][value]": "https://zork.example.org",
+			"fields_attributes[0][name]":  "pronouns",
+			"fields_attributes[0][value]": "they/them",
+			"fields_attributes[2][name]":  "",
+			"fields_attributes[2][value]": "",
+		})
+	if err != nil {
+		panic(err)
+	}
+	bodyBytes := requestBody.Bytes()
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPatch, bodyBytes, account.UpdateCredentialsPath, w.FormDataContentType())
+
+	// call the handler
+	suite.accountModule.AccountUpdateCredentialsPATCHHandler(ctx)
+
+	// we should have OK because our request was valid
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	apimodelAccount := &apimodel.Account{}
+	err = json.Unmarshal(b, apimodelAccount)
+	suite.NoError(err)
+
+	// the fields should be set in the order of their indexes, without the empty one
+	if suite.Len(apimodelAccount.Fields, 2) {
+		suite.Equal("pronouns", apimodelAccount.Fields[0].Name)
+		suite.Equal("they/them", apimodelAccount.Fields[0].Value)
+		suite.Equal("website", apimodelAccount.Fields[1].Name)
+		suite.Equal("https://zork.example.org", apimodelAccount.Fields[1].Value)
+	}
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountUpdateCredentialsPATCHHandlerDeleteHeader() {
+	// set up the request
+	// we're sending an empty header instead of a file, to remove zork's header
+	requestBody, w, err := testrig.CreateMultipartFormData(
+		"", "",
+		map[string]string{
+			"header": "",
+		})
+	if err != nil {
+		panic(err)
+	}
+	bodyBytes := requestBody.Bytes()
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPatch, bodyBytes, account.UpdateCredentialsPath, w.FormDataContentType())
+
+	// call the handler
+	suite.accountModule.AccountUpdateCredentialsPATCHHandler(ctx)
+
+	// we should have OK because our request was valid
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	apimodelAccount := &apimodel.Account{}
+	err = json.Unmarshal(b, apimodelAccount)
+	suite.NoError(err)
+
+	// the header should be gone, but the avatar should be left alone
+	suite.Empty(apimodelAccount.Header)
+	suite.Empty(apimodelAccount.HeaderStatic)
+	suite.NotEmpty(apimodelAccount.Avatar)
+}
+
 func TestAccountUpdateTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountUpdateTestSuite))
 }
@@ -0,0 +1,156 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// AccountAliasesGETHandler swagger:operation GET /api/v1/accounts/aliases accountAliasesGet
+//
+// See the aliases of your account.
+//
+// These are the accounts that your account is also known as. An account can only move to your account if it's listed here.
+//
+// ---
+// tags:
+// - accounts
+//
+// produces:
+// - application/json
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:accounts
+//
+// responses:
+//   '200':
+//     name: account aliases
+//     description: The aliases of your account.
+//     schema:
+//       "$ref": "#/definitions/accountAliases"
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) AccountAliasesGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "AccountAliasesGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	aliases, errWithCode := m.processor.AccountAliasesGet(c.Request.Context(), authed)
+	if errWithCode != nil {
+		l.Debugf("could not get account aliases: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, aliases)
+}
+
+// AccountAliasesPOSTHandler swagger:operation POST /api/v1/accounts/aliases accountAliasesSet
+//
+// Set the aliases of your account.
+//
+// Any aliases that your account had before are replaced by the ones given, so to remove all aliases, don't give any.
+// Each alias must be the ActivityPub URI of an account that can be found, either on this instance or on another one.
+//
+// To move an account on another instance to your account, first add it as an alias here, then start the move from the other account.
+//
+// ---
+// tags:
+// - accounts
+//
+// consumes:
+// - multipart/form-data
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: also_known_as_uris[]
+//   in: formData
+//   description: ActivityPub URIs of the accounts that your account is also known as.
+//   type: array
+//   items:
+//     type: string
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:accounts
+//
+// responses:
+//   '200':
+//     name: account aliases
+//     description: The new aliases of your account.
+//     schema:
+//       "$ref": "#/definitions/accountAliases"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) AccountAliasesPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "AccountAliasesPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.AccountAliasesRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	aliases, errWithCode := m.processor.AccountAliasesSet(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("could not set account aliases: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, aliases)
+}
@@ -0,0 +1,121 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/account"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type AccountAliasesTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *AccountAliasesTestSuite) SetupTest() {
+	suite.AccountStandardTestSuite.SetupTest()
+
+	// setting aliases changes the requesting account, so make sure every test starts with fresh accounts
+	suite.testAccounts = testrig.NewTestAccounts()
+}
+
+func (suite *AccountAliasesTestSuite) setAliases(aliases map[string]string) *httptest.ResponseRecorder {
+	requestBody, w, err := testrig.CreateMultipartFormData("", "", aliases)
+	if err != nil {
+		panic(err)
+	}
+	bodyBytes := requestBody.Bytes()
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, bodyBytes, account.AliasesPath, w.FormDataContentType())
+
+	// call the handler
+	suite.accountModule.AccountAliasesPOSTHandler(ctx)
+
+	return recorder
+}
+
+func (suite *AccountAliasesTestSuite) TestSetAndGetAliases() {
+	turtle := suite.testAccounts["local_account_2"]
+
+	recorder := suite.setAliases(map[string]string{
+		"also_known_as_uris[]": turtle.URI,
+	})
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Body)
+	suite.NoError(err)
+	aliases := &apimodel.AccountAliases{}
+	err = json.Unmarshal(b, aliases)
+	suite.NoError(err)
+	suite.Equal([]string{turtle.URI}, aliases.AlsoKnownAsURIs)
+
+	// the alias should be stored on zork now
+	dbZork, err := suite.db.GetAccountByID(context.Background(), suite.testAccounts["local_account_1"].ID)
+	suite.NoError(err)
+	suite.Equal([]string{turtle.URI}, dbZork.AlsoKnownAsURIs)
+
+	// and we should get it back when we ask for it
+	recorder = httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, account.AliasesPath, "")
+	suite.accountModule.AccountAliasesGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	b, err = ioutil.ReadAll(recorder.Body)
+	suite.NoError(err)
+	suite.Equal(`{"also_known_as_uris":["http://localhost:8080/users/1happyturtle"]}`, string(b))
+}
+
+func (suite *AccountAliasesTestSuite) TestClearAliases() {
+	recorder := suite.setAliases(map[string]string{})
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Body)
+	suite.NoError(err)
+	suite.Equal(`{"also_known_as_uris":[]}`, string(b))
+}
+
+func (suite *AccountAliasesTestSuite) TestSetAliasToSelf() {
+	recorder := suite.setAliases(map[string]string{
+		"also_known_as_uris[]": suite.testAccounts["local_account_1"].URI,
+	})
+
+	// an account can't be an alias of itself
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func (suite *AccountAliasesTestSuite) TestSetAliasToUnknownAccount() {
+	recorder := suite.setAliases(map[string]string{
+		"also_known_as_uris[]": "http://localhost:8080/users/nobody_at_all",
+	})
+
+	// the alias has to be an account that exists
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestAccountAliasesTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountAliasesTestSuite))
+}
@@ -35,6 +35,15 @@ import (
 //
 // The statuses will be returned in descending chronological order (newest first), with sequential IDs (bigger = newer).
 //
+// The returned Link header can be used to generate the previous and next queries when scrolling up or down the statuses.
+// It's not set when only pinned statuses are requested.
+//
+// Example:
+//
+// ```
+// <https://example.org/api/v1/accounts/01FC0SKA48HNSVR6YKZCQGS2V8/statuses?limit=30&max_id=01FC3GSQ8A3MMJ43BPZSGEG29M>; rel="next", <https://example.org/api/v1/accounts/01FC0SKA48HNSVR6YKZCQGS2V8/statuses?limit=30&min_id=01FC3KJW2GYXSDDRA6RWNDM46M>; rel="prev"
+// ```
+//
 // ---
 // tags:
 // - accounts
@@ -75,7 +84,7 @@ import (
 // - name: min_id
 //   type: string
 //   description: |-
-//     Return only statuses *NEWER* than the given min status ID.
+//     Return only statuses *IMMEDIATELY NEWER* than the given min status ID.
 //     The status with the specified ID will not be included in the response.
 //   in: query
 //   required: false
@@ -106,6 +115,10 @@ import (
 //   '200':
 //     name: statuses
 //     description: Array of statuses.
+//     headers:
+//       Link:
+//         type: string
+//         description: Links to the next and previous queries.
 //     schema:
 //       type: array
 //       items:
@@ -222,12 +235,15 @@ func (m *Module) AccountStatusesGETHandler(c *gin.Context) {
 		publicOnly = i
 	}
 
-	statuses, errWithCode := m.processor.AccountStatusesGet(c.Request.Context(), authed, targetAcctID, limit, excludeReplies, excludeReblogs, maxID, minID, pinnedOnly, mediaOnly, publicOnly)
+	resp, errWithCode := m.processor.AccountStatusesGet(c.Request.Context(), authed, targetAcctID, limit, excludeReplies, excludeReblogs, maxID, minID, pinnedOnly, mediaOnly, publicOnly)
 	if errWithCode != nil {
 		l.Debugf("error from processor account statuses get: %s", errWithCode)
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
 
-	c.JSON(http.StatusOK, statuses)
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.Statuses)
 }
@@ -76,6 +76,38 @@ func (suite *AccountStatusesTestSuite) TestGetStatusesMediaOnly() {
 	}
 }
 
+func (suite *AccountStatusesTestSuite) TestGetStatusesLinkHeader() {
+	targetAccount := suite.testAccounts["local_account_1"]
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, fmt.Sprintf("/api/v1/accounts/%s/statuses?limit=2&exclude_replies=true", targetAccount.ID), "")
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   account.IDKey,
+			Value: targetAccount.ID,
+		},
+	}
+
+	suite.accountModule.AccountStatusesGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	apimodelStatuses := []*apimodel.Status{}
+	err = json.Unmarshal(b, &apimodelStatuses)
+	suite.NoError(err)
+	suite.Len(apimodelStatuses, 2)
+
+	// the links should page from the returned statuses, keeping the filters of the request
+	suite.Equal(fmt.Sprintf(
+		`<http://localhost:8080/api/v1/accounts/%s/statuses?limit=2&max_id=%s&exclude_replies=true>; rel="next", <http://localhost:8080/api/v1/accounts/%s/statuses?limit=2&min_id=%s&exclude_replies=true>; rel="prev"`,
+		targetAccount.ID, apimodelStatuses[1].ID, targetAccount.ID, apimodelStatuses[0].ID,
+	), result.Header.Get("Link"))
+}
+
 func TestAccountStatusesTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountStatusesTestSuite))
 }
@@ -35,12 +35,60 @@ const (
 	DomainBlocksPath = BasePath + "/domain_blocks"
 	// DomainBlocksPathWithID is used for interacting with a single domain block.
 	DomainBlocksPathWithID = DomainBlocksPath + "/:" + IDKey
+	// EmailDomainBlocksPath is used for listing and creating blocks on signing up with email addresses on a domain.
+	EmailDomainBlocksPath = BasePath + "/email_domain_blocks"
+	// EmailDomainBlocksPathWithID is used for interacting with a single email domain block.
+	EmailDomainBlocksPathWithID = EmailDomainBlocksPath + "/:" + IDKey
+	// IPBlocksPath is used for listing and creating blocks on IP addresses and ranges.
+	IPBlocksPath = BasePath + "/ip_blocks"
+	// IPBlocksPathWithID is used for interacting with a single IP block.
+	IPBlocksPathWithID = IPBlocksPath + "/:" + IDKey
 	// AccountsPath is used for listing + acting on accounts.
 	AccountsPath = BasePath + "/accounts"
 	// AccountsPathWithID is used for interacting with a single account.
 	AccountsPathWithID = AccountsPath + "/:" + IDKey
 	// AccountsActionPath is used for taking action on a single account.
 	AccountsActionPath = AccountsPathWithID + "/action"
+	// MediaRecachePath is used for recaching remote media.
+	MediaRecachePath = BasePath + "/media/recache"
+	// MediaIntegrityPath is used for viewing the results of media integrity checks.
+	MediaIntegrityPath = BasePath + "/media/integrity"
+	// MediaStatsPath is used for viewing the state of media processing and storage.
+	MediaStatsPath = BasePath + "/media/stats"
+	// DeliveriesPath is used for listing failed deliveries to other instances.
+	DeliveriesPath = BasePath + "/deliveries"
+	// DeliveryStatsPath is used for viewing the state of failed deliveries to other instances.
+	DeliveryStatsPath = DeliveriesPath + "/stats"
+	// UnreachableDomainsPath is used for listing remote domains that deliveries have been failing to.
+	UnreachableDomainsPath = BasePath + "/unreachable_domains"
+	// UnreachableDomainsPathWithID is used for interacting with a single unreachable domain.
+	UnreachableDomainsPathWithID = UnreachableDomainsPath + "/:" + IDKey
+	// RulesPath is used for listing and creating the rules of this instance.
+	RulesPath = BasePath + "/rules"
+	// RulesPathWithID is used for interacting with a single rule.
+	RulesPathWithID = RulesPath + "/:" + IDKey
+	// ReportsPath is used for listing reports made to the moderators of this instance.
+	ReportsPath = BasePath + "/reports"
+	// ReportsPathWithID is used for interacting with a single report.
+	ReportsPathWithID = ReportsPath + "/:" + IDKey
+	// ReportAssignToSelfPath is used for assigning a single report to the requesting moderator.
+	ReportAssignToSelfPath = ReportsPathWithID + "/assign_to_self"
+	// ReportUnassignPath is used for unassigning a single report.
+	ReportUnassignPath = ReportsPathWithID + "/unassign"
+	// ReportResolvePath is used for marking a single report as resolved.
+	ReportResolvePath = ReportsPathWithID + "/resolve"
+	// ReportReopenPath is used for marking a single resolved report as not resolved again.
+	ReportReopenPath = ReportsPathWithID + "/reopen"
+	// MeasuresPath is used for viewing how much happened on this instance, week by week.
+	MeasuresPath = BasePath + "/measures"
+	// DimensionsPath is used for viewing the current state of storage and queues on this instance, and where its traffic comes from.
+	DimensionsPath = BasePath + "/dimensions"
+	// TrendsPath is used for listing trending hashtags, statuses or links, depending on the trend type.
+	TrendsPath = BasePath + "/trends/:" + TrendTypeKey
+	// TrendApprovePath is used for approving a single hashtag, status or link for public trends.
+	TrendApprovePath = TrendsPath + "/:" + IDKey + "/approve"
+	// TrendRejectPath is used for keeping a single hashtag, status or link out of public trends.
+	TrendRejectPath = TrendsPath + "/:" + IDKey + "/reject"
 
 	// ExportQueryKey is for requesting a public export of some data.
 	ExportQueryKey = "export"
@@ -48,6 +96,24 @@ const (
 	ImportQueryKey = "import"
 	// IDKey specifies the ID of a single item being interacted with.
 	IDKey = "id"
+	// TrendTypeKey specifies which kind of trends are being interacted with: tags, statuses or links.
+	TrendTypeKey = "type"
+	// DomainQueryKey is for only listing things to do with the given domain.
+	DomainQueryKey = "domain"
+	// ResolvedQueryKey is for only listing reports that are or aren't resolved.
+	ResolvedQueryKey = "resolved"
+	// AccountIDQueryKey is for only listing reports made by the given account.
+	AccountIDQueryKey = "account_id"
+	// TargetAccountIDQueryKey is for only listing reports about the given account.
+	TargetAccountIDQueryKey = "target_account_id"
+	// MaxIDQueryKey is for only listing items older than the given ID.
+	MaxIDQueryKey = "max_id"
+	// SinceIDQueryKey is for only listing items newer than the given ID.
+	SinceIDQueryKey = "since_id"
+	// WeeksQueryKey is for specifying how many weeks to measure.
+	WeeksQueryKey = "weeks"
+	// LimitQueryKey is for specifying the maximum number of items to return.
+	LimitQueryKey = "limit"
 )
 
 // Module implements the ClientAPIModule interface for admin-related actions (reports, emojis, etc)
@@ -68,7 +134,41 @@ func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodPost, DomainBlocksPath, m.DomainBlocksPOSTHandler)
 	r.AttachHandler(http.MethodGet, DomainBlocksPath, m.DomainBlocksGETHandler)
 	r.AttachHandler(http.MethodGet, DomainBlocksPathWithID, m.DomainBlockGETHandler)
+	r.AttachHandler(http.MethodPut, DomainBlocksPathWithID, m.DomainBlockPUTHandler)
 	r.AttachHandler(http.MethodDelete, DomainBlocksPathWithID, m.DomainBlockDELETEHandler)
+	r.AttachHandler(http.MethodGet, EmailDomainBlocksPath, m.EmailDomainBlocksGETHandler)
+	r.AttachHandler(http.MethodPost, EmailDomainBlocksPath, m.EmailDomainBlockPOSTHandler)
+	r.AttachHandler(http.MethodGet, EmailDomainBlocksPathWithID, m.EmailDomainBlockGETHandler)
+	r.AttachHandler(http.MethodDelete, EmailDomainBlocksPathWithID, m.EmailDomainBlockDELETEHandler)
+	r.AttachHandler(http.MethodGet, IPBlocksPath, m.IPBlocksGETHandler)
+	r.AttachHandler(http.MethodPost, IPBlocksPath, m.IPBlockPOSTHandler)
+	r.AttachHandler(http.MethodGet, IPBlocksPathWithID, m.IPBlockGETHandler)
+	r.AttachHandler(http.MethodPut, IPBlocksPathWithID, m.IPBlockPUTHandler)
+	r.AttachHandler(http.MethodDelete, IPBlocksPathWithID, m.IPBlockDELETEHandler)
 	r.AttachHandler(http.MethodPost, AccountsActionPath, m.AccountActionPOSTHandler)
+	r.AttachHandler(http.MethodPost, MediaRecachePath, m.MediaRecachePOSTHandler)
+	r.AttachHandler(http.MethodGet, MediaIntegrityPath, m.MediaIntegrityGETHandler)
+	r.AttachHandler(http.MethodGet, MediaStatsPath, m.MediaStatsGETHandler)
+	r.AttachHandler(http.MethodGet, DeliveriesPath, m.DeliveriesGETHandler)
+	r.AttachHandler(http.MethodGet, DeliveryStatsPath, m.DeliveryStatsGETHandler)
+	r.AttachHandler(http.MethodGet, UnreachableDomainsPath, m.UnreachableDomainsGETHandler)
+	r.AttachHandler(http.MethodPost, UnreachableDomainsPath, m.UnreachableDomainPOSTHandler)
+	r.AttachHandler(http.MethodDelete, UnreachableDomainsPathWithID, m.UnreachableDomainDELETEHandler)
+	r.AttachHandler(http.MethodGet, RulesPath, m.RulesGETHandler)
+	r.AttachHandler(http.MethodPost, RulesPath, m.RulePOSTHandler)
+	r.AttachHandler(http.MethodGet, RulesPathWithID, m.RuleGETHandler)
+	r.AttachHandler(http.MethodPatch, RulesPathWithID, m.RulePATCHHandler)
+	r.AttachHandler(http.MethodDelete, RulesPathWithID, m.RuleDELETEHandler)
+	r.AttachHandler(http.MethodGet, ReportsPath, m.ReportsGETHandler)
+	r.AttachHandler(http.MethodGet, ReportsPathWithID, m.ReportGETHandler)
+	r.AttachHandler(http.MethodPost, ReportAssignToSelfPath, m.ReportAssignToSelfPOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportUnassignPath, m.ReportUnassignPOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportResolvePath, m.ReportResolvePOSTHandler)
+	r.AttachHandler(http.MethodPost, ReportReopenPath, m.ReportReopenPOSTHandler)
+	r.AttachHandler(http.MethodGet, MeasuresPath, m.MeasuresGETHandler)
+	r.AttachHandler(http.MethodGet, DimensionsPath, m.DimensionsGETHandler)
+	r.AttachHandler(http.MethodGet, TrendsPath, m.TrendsGETHandler)
+	r.AttachHandler(http.MethodPost, TrendApprovePath, m.TrendApprovePOSTHandler)
+	r.AttachHandler(http.MethodPost, TrendRejectPath, m.TrendRejectPOSTHandler)
 	return nil
 }
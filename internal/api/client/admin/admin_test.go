@@ -21,10 +21,11 @@ package admin_test
 import (
 	"bytes"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
@@ -38,6 +39,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
@@ -46,7 +48,7 @@ type AdminStandardTestSuite struct {
 	// standard suite interfaces
 	suite.Suite
 	db           db.DB
-	storage      *kv.KVStore
+	storage      *gtsstorage.Driver
 	mediaManager media.Manager
 	federator    federation.Federator
 	processor    processing.Processor
@@ -124,3 +126,28 @@ func (suite *AdminStandardTestSuite) newContext(recorder *httptest.ResponseRecor
 
 	return ctx
 }
+
+// formRequest calls the given handler with the given fields as a multipart form, at the given path with its id parameter
+// filled in with the given id if it isn't empty, and returns the status code and body of the response.
+func (suite *AdminStandardTestSuite) formRequest(handler gin.HandlerFunc, method string, path string, id string, fields map[string]string) (int, []byte) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		suite.NoError(w.WriteField(k, v))
+	}
+	suite.NoError(w.Close())
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, method, body.Bytes(), strings.Replace(path, ":"+admin.IDKey, id, 1), w.FormDataContentType())
+	if id != "" {
+		ctx.Params = gin.Params{
+			gin.Param{
+				Key:   admin.IDKey,
+				Value: id,
+			},
+		}
+	}
+
+	handler(ctx)
+	return recorder.Code, recorder.Body.Bytes()
+}
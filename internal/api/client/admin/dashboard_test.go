@@ -0,0 +1,122 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type DashboardTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// get calls the given handler with the given path and query, and unmarshals the response into v.
+func (suite *DashboardTestSuite) get(handler gin.HandlerFunc, path string, v interface{}) {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, path, "")
+
+	handler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), v))
+}
+
+// postStatusNow puts a copy of the given test status in the db, as if it had just been posted.
+func (suite *DashboardTestSuite) postStatusNow(testStatus string) {
+	status := *suite.testStatuses[testStatus]
+	status.ID = "01G2VJ8YZP6B9H0GAQZ1W1J5QN"
+	status.URI += "/again"
+	status.URL += "/again"
+	status.CreatedAt = time.Now()
+	status.UpdatedAt = time.Now()
+	suite.NoError(suite.db.Put(context.Background(), &status))
+}
+
+func (suite *DashboardTestSuite) TestMeasures() {
+	measures := []*apimodel.AdminWeeklyMeasures{}
+	suite.get(suite.adminModule.MeasuresGETHandler, admin.MeasuresPath+"?weeks=2", &measures)
+	suite.Len(measures, 2)
+
+	// weeks start at midnight on a Monday, one week apart
+	lastWeek, err := time.Parse(time.RFC3339, measures[0].StartsAt)
+	suite.NoError(err)
+	thisWeek, err := time.Parse(time.RFC3339, measures[1].StartsAt)
+	suite.NoError(err)
+	suite.Equal(time.Monday, thisWeek.Weekday())
+	suite.Equal(thisWeek, thisWeek.Truncate(24*time.Hour))
+	suite.Equal(7*24*time.Hour, thisWeek.Sub(lastWeek))
+	suite.False(time.Now().Before(thisWeek))
+
+	// all the test users signed up, and all the ones with tokens signed in, in the last few days
+	newUsers, logins := 0, 0
+	for _, m := range measures {
+		newUsers += m.NewUsers
+		logins += m.Logins
+	}
+	suite.Equal(len(suite.testUsers), newUsers)
+	suite.Equal(3, logins)
+
+	suite.postStatusNow("local_account_1_status_1")
+
+	after := []*apimodel.AdminWeeklyMeasures{}
+	suite.get(suite.adminModule.MeasuresGETHandler, admin.MeasuresPath+"?weeks=2", &after)
+	suite.Equal(measures[0].Statuses, after[0].Statuses)
+	suite.Equal(measures[1].Statuses+1, after[1].Statuses)
+}
+
+func (suite *DashboardTestSuite) TestMeasuresNegativeWeeks() {
+	measures := []*apimodel.AdminWeeklyMeasures{}
+	suite.get(suite.adminModule.MeasuresGETHandler, admin.MeasuresPath+"?weeks=-1", &measures)
+	suite.Len(measures, 1)
+}
+
+func (suite *DashboardTestSuite) TestDimensionsNegativeLimit() {
+	suite.postStatusNow("remote_account_1_status_1")
+
+	dimensions := &apimodel.AdminDimensions{}
+	suite.get(suite.adminModule.DimensionsGETHandler, admin.DimensionsPath+"?limit=-1", dimensions)
+	suite.Len(dimensions.TopRemoteDomains, 1)
+}
+
+func (suite *DashboardTestSuite) TestDimensions() {
+	suite.postStatusNow("remote_account_1_status_1")
+
+	dimensions := &apimodel.AdminDimensions{}
+	suite.get(suite.adminModule.DimensionsGETHandler, admin.DimensionsPath, dimensions)
+	suite.NotZero(dimensions.LocalBytesStored)
+	suite.NotZero(dimensions.RemoteBytesStored)
+	suite.Equal(suite.mediaManager.JobsQueued(), dimensions.MediaJobsQueued)
+	suite.Contains(dimensions.TopRemoteDomains, apimodel.AdminDomainTraffic{
+		Domain:   "fossbros-anonymous.io",
+		Statuses: 1,
+	})
+}
+
+func TestDashboardTestSuite(t *testing.T) {
+	suite.Run(t, new(DashboardTestSuite))
+}
@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type DeliveriesTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *DeliveriesTestSuite) getDeliveries(query string) []*apimodel.AdminDelivery {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, admin.DeliveriesPath+query, "")
+
+	suite.adminModule.DeliveriesGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	deliveries := []*apimodel.AdminDelivery{}
+	suite.NoError(json.Unmarshal(b, &deliveries))
+	return deliveries
+}
+
+func (suite *DeliveriesTestSuite) TestGetDeliveries() {
+	suite.Empty(suite.getDeliveries(""))
+
+	ctx := context.Background()
+	now := time.Now()
+
+	pending := &gtsmodel.Delivery{
+		ID:            "01G0Q3X6JDRDTRZ0R4PB6SFJ9B",
+		UpdatedAt:     now,
+		PubKeyID:      suite.testAccounts["local_account_1"].PublicKeyURI,
+		InboxURI:      suite.testAccounts["remote_account_1"].InboxURI,
+		Payload:       []byte(`{"type":"Create"}`),
+		Attempts:      1,
+		LastError:     "POST request to http://fossbros-anonymous.io/users/foss_satan/inbox failed (502): 502 Bad Gateway",
+		NextAttemptAt: now.Add(time.Minute),
+	}
+	suite.NoError(suite.db.Put(ctx, pending))
+
+	failed := &gtsmodel.Delivery{
+		ID:        "01G0Q3XBYRY0KK6M8J6R2A3NSM",
+		UpdatedAt: now.Add(-time.Hour),
+		PubKeyID:  suite.testAccounts["local_account_1"].PublicKeyURI,
+		InboxURI:  "https://example.org/inbox",
+		Payload:   []byte(`{"type":"Delete"}`),
+		Attempts:  12,
+		LastError: "dial tcp: lookup example.org: no such host",
+		FailedAt:  now.Add(-time.Hour),
+	}
+	suite.NoError(suite.db.Put(ctx, failed))
+
+	deliveries := suite.getDeliveries("")
+	if suite.Len(deliveries, 2) {
+		suite.Equal(pending.ID, deliveries[0].ID)
+		suite.Equal("fossbros-anonymous.io", deliveries[0].Domain)
+		suite.Equal(pending.InboxURI, deliveries[0].Inbox)
+		suite.Equal("Create", deliveries[0].ActivityType)
+		suite.Equal(pending.LastError, deliveries[0].LastError)
+		suite.NotEmpty(deliveries[0].NextAttemptAt)
+		suite.Empty(deliveries[0].FailedAt)
+
+		suite.Equal(failed.ID, deliveries[1].ID)
+		suite.Equal("example.org", deliveries[1].Domain)
+		suite.Equal("Delete", deliveries[1].ActivityType)
+		suite.Equal(12, deliveries[1].Attempts)
+		suite.Empty(deliveries[1].NextAttemptAt)
+		suite.NotEmpty(deliveries[1].FailedAt)
+	}
+
+	deliveries = suite.getDeliveries("?domain=example.org")
+	if suite.Len(deliveries, 1) {
+		suite.Equal(failed.ID, deliveries[0].ID)
+	}
+
+	deliveries = suite.getDeliveries("?limit=1")
+	if suite.Len(deliveries, 1) {
+		suite.Equal(pending.ID, deliveries[0].ID)
+	}
+}
+
+func TestDeliveriesTestSuite(t *testing.T) {
+	suite.Run(t, &DeliveriesTestSuite{})
+}
@@ -0,0 +1,94 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type DeliveryStatsTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *DeliveryStatsTestSuite) getStats() *apimodel.AdminDeliveryStats {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, admin.DeliveryStatsPath, "")
+
+	suite.adminModule.DeliveryStatsGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	stats := &apimodel.AdminDeliveryStats{}
+	suite.NoError(json.Unmarshal(b, stats))
+	return stats
+}
+
+func (suite *DeliveryStatsTestSuite) TestDeliveryStats() {
+	stats := suite.getStats()
+	suite.Zero(stats.Pending)
+	suite.Zero(stats.Failed)
+
+	ctx := context.Background()
+	pubKeyID := suite.testAccounts["local_account_1"].PublicKeyURI
+	inboxURI := suite.testAccounts["remote_account_1"].InboxURI
+
+	pending := &gtsmodel.Delivery{
+		ID:            "01G0Q3X6JDRDTRZ0R4PB6SFJ9B",
+		PubKeyID:      pubKeyID,
+		InboxURI:      inboxURI,
+		Payload:       []byte(`{"type":"Create"}`),
+		Attempts:      1,
+		NextAttemptAt: time.Now().Add(time.Minute),
+	}
+	suite.NoError(suite.db.Put(ctx, pending))
+
+	failed := &gtsmodel.Delivery{
+		ID:       "01G0Q3XBYRY0KK6M8J6R2A3NSM",
+		PubKeyID: pubKeyID,
+		InboxURI: inboxURI,
+		Payload:  []byte(`{"type":"Delete"}`),
+		Attempts: 12,
+		FailedAt: time.Now(),
+	}
+	suite.NoError(suite.db.Put(ctx, failed))
+
+	stats = suite.getStats()
+	suite.Equal(1, stats.Pending)
+	suite.Equal(1, stats.Failed)
+}
+
+func TestDeliveryStatsTestSuite(t *testing.T) {
+	suite.Run(t, &DeliveryStatsTestSuite{})
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
@@ -18,10 +20,22 @@ import (
 // Create one or more domain blocks, from a string or a file.
 //
 // Note that you have two options when using this endpoint: either you can set `import` to true
-// and upload a file containing multiple domain blocks, JSON-formatted, or you can leave import as
+// and upload a file containing multiple domain blocks, JSON- or CSV-formatted, or you can leave import as
 // false, and just add one domain block.
 //
-// The format of the json file should be something like: `[{"domain":"example.org"},{"domain":"whatever.com","public_comment":"they smell"}]`
+// The format of a json file should be something like: `[{"domain":"example.org"},{"domain":"whatever.com","public_comment":"they smell","obfuscate":true}]`
+//
+// A csv file can be a blocklist exported from Mastodon, with a header like `#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate`,
+// or just a list of domains, one per line. Only domains with a severity of `suspend` or `silence` are blocked, and obfuscated domains like `ex**ple.org` are skipped.
+//
+// To block all the subdomains of a domain as well, set `include_subdomains` to true, either on the form or on an entry in the file,
+// or write the domain as a wildcard like `*.example.org`.
+//
+// A block with a severity of `silence` doesn't cut the domain off: its posts are still accepted, but they're kept out of public timelines
+// and its accounts are marked as limited, follows from its accounts always need to be approved, and its media is never cached.
+// Creating a `suspend` block for a domain that's already silenced turns the silence into a suspension.
+//
+// A block with `reject_media` set to true keeps the domain's media from being cached, whatever its severity.
 //
 // ---
 // tags:
@@ -38,13 +52,13 @@ import (
 //   in: query
 //   description: |-
 //     Signal that a list of domain blocks is being imported as a file.
-//     If set to true, then 'domains' must be present as a JSON-formatted file.
+//     If set to true, then 'domains' must be present as a JSON- or CSV-formatted file.
 //     If set to false, then 'domains' will be ignored, and 'domain' must be present.
 //   type: boolean
 // - name: domains
 //   in: formData
 //   description: |-
-//     JSON-formatted list of domain blocks to import.
+//     JSON- or CSV-formatted list of domain blocks to import.
 //     This is only used if `import` is set to true.
 //   type: file
 // - name: domain
@@ -53,6 +67,13 @@ import (
 //     Single domain to block.
 //     Used only if `import` is not true.
 //   type: string
+// - name: include_subdomains
+//   in: formData
+//   description: |-
+//     Block all subdomains of the domain as well, eg. 'sub.example.org' as well as 'example.org'.
+//     Setting the domain to a wildcard like '*.example.org' does the same thing.
+//     Used only if `import` is not true.
+//   type: boolean
 // - name: obfuscate
 //   in: formData
 //   description: |-
@@ -60,6 +81,18 @@ import (
 //     Eg., 'example.org' becomes something like 'ex***e.org'.
 //     Used only if `import` is not true.
 //   type: boolean
+// - name: severity
+//   in: formData
+//   description: |-
+//     How severe the block should be: either 'suspend' or 'silence'. Defaults to 'suspend'.
+//     Used only if `import` is not true.
+//   type: string
+// - name: reject_media
+//   in: formData
+//   description: |-
+//     Never cache media from the domain, even if the block is only a silence.
+//     Used only if `import` is not true.
+//   type: boolean
 // - name: public_comment
 //   in: formData
 //   description: |-
@@ -172,10 +205,25 @@ func validateCreateDomainBlock(form *model.DomainBlockCreateRequest, imp bool) e
 			return errors.New("import was specified but list of domains is empty")
 		}
 	} else {
+		// a wildcard domain like '*.example.org' is shorthand for blocking example.org and its subdomains
+		if strings.HasPrefix(form.Domain, "*.") {
+			form.Domain = strings.TrimPrefix(form.Domain, "*.")
+			form.IncludeSubdomains = true
+		}
+
 		// add some more validation here later if necessary
 		if form.Domain == "" {
 			return errors.New("empty domain provided")
 		}
+
+		form.Severity = strings.ToLower(strings.TrimSpace(form.Severity))
+		switch form.Severity {
+		case "":
+			form.Severity = gtsmodel.DomainBlockSeveritySuspend
+		case gtsmodel.DomainBlockSeveritySuspend, gtsmodel.DomainBlockSeveritySilence:
+		default:
+			return fmt.Errorf("severity must be either %s or %s", gtsmodel.DomainBlockSeveritySuspend, gtsmodel.DomainBlockSeveritySilence)
+		}
 	}
 
 	return nil
@@ -0,0 +1,245 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type DomainBlocksTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *DomainBlocksTestSuite) importBlocklist(blocklist string) []*apimodel.DomainBlock {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fw, err := w.CreateFormFile("domains", "blocklist.csv")
+	suite.NoError(err)
+	_, err = fw.Write([]byte(blocklist))
+	suite.NoError(err)
+	suite.NoError(w.Close())
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, body.Bytes(), admin.DomainBlocksPath+"?"+admin.ImportQueryKey+"=true", w.FormDataContentType())
+
+	suite.adminModule.DomainBlocksPOSTHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	blocks := []*apimodel.DomainBlock{}
+	suite.NoError(json.Unmarshal(b, &blocks))
+	return blocks
+}
+
+func (suite *DomainBlocksTestSuite) TestImportMastodonCSV() {
+	blocks := suite.importBlocklist(`#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate
+example.org,suspend,false,false,they smell,false
+whatever.com,suspend,true,true,spam,true
+quiet.example.net,silence,false,false,,false
+ex**ple.net,suspend,false,false,,true
+`)
+
+	suite.Len(blocks, 3)
+	suite.Equal("example.org", blocks[0].Domain)
+	suite.Equal("they smell", blocks[0].PublicComment)
+	suite.False(blocks[0].Obfuscate)
+	suite.Equal("suspend", blocks[0].Severity)
+	suite.Equal("whatever.com", blocks[1].Domain)
+	suite.Equal("spam", blocks[1].PublicComment)
+	suite.True(blocks[1].Obfuscate)
+	suite.True(blocks[1].RejectMedia)
+	suite.Equal("quiet.example.net", blocks[2].Domain)
+	suite.Equal("silence", blocks[2].Severity)
+}
+
+func (suite *DomainBlocksTestSuite) TestImportJSON() {
+	blocks := suite.importBlocklist(`[{"domain":"example.org","obfuscate":true,"private_comment":"reported a lot"}]`)
+
+	suite.Len(blocks, 1)
+	suite.Equal("example.org", blocks[0].Domain)
+	suite.True(blocks[0].Obfuscate)
+	suite.Equal("reported a lot", blocks[0].PrivateComment)
+}
+
+func (suite *DomainBlocksTestSuite) TestCreateWildcard() {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	suite.NoError(w.WriteField("domain", "*.example.org"))
+	suite.NoError(w.Close())
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, body.Bytes(), admin.DomainBlocksPath, w.FormDataContentType())
+
+	suite.adminModule.DomainBlocksPOSTHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	block := &apimodel.DomainBlock{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), block))
+	suite.Equal("example.org", block.Domain)
+	suite.True(block.IncludeSubdomains)
+
+	// subdomains of the blocked domain are now blocked too
+	blocked, err := suite.db.IsDomainBlocked(context.Background(), "sub.example.org")
+	suite.NoError(err)
+	suite.True(blocked)
+}
+
+func (suite *DomainBlocksTestSuite) createBlock(fields map[string]string) (int, *apimodel.DomainBlock) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		suite.NoError(w.WriteField(k, v))
+	}
+	suite.NoError(w.Close())
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPost, body.Bytes(), admin.DomainBlocksPath, w.FormDataContentType())
+
+	suite.adminModule.DomainBlocksPOSTHandler(ctx)
+
+	block := &apimodel.DomainBlock{}
+	if recorder.Code == http.StatusOK {
+		suite.NoError(json.Unmarshal(recorder.Body.Bytes(), block))
+	}
+	return recorder.Code, block
+}
+
+func (suite *DomainBlocksTestSuite) TestCreateSilence() {
+	code, block := suite.createBlock(map[string]string{"domain": "example.org", "severity": "silence"})
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("example.org", block.Domain)
+	suite.Equal("silence", block.Severity)
+
+	// a silenced domain isn't blocked
+	blocked, err := suite.db.IsDomainBlocked(context.Background(), "example.org")
+	suite.NoError(err)
+	suite.False(blocked)
+
+	silenced, err := suite.db.IsDomainSilenced(context.Background(), "example.org")
+	suite.NoError(err)
+	suite.True(silenced)
+
+	// blocking the domain again with suspend severity escalates the existing block
+	code, block = suite.createBlock(map[string]string{"domain": "example.org", "severity": "suspend"})
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("suspend", block.Severity)
+
+	blocked, err = suite.db.IsDomainBlocked(context.Background(), "example.org")
+	suite.NoError(err)
+	suite.True(blocked)
+}
+
+func (suite *DomainBlocksTestSuite) TestCreateBadSeverity() {
+	code, _ := suite.createBlock(map[string]string{"domain": "example.org", "severity": "noop"})
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *DomainBlocksTestSuite) updateBlock(id string, fields map[string]string) (int, *apimodel.DomainBlock) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, v := range fields {
+		suite.NoError(w.WriteField(k, v))
+	}
+	suite.NoError(w.Close())
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodPut, body.Bytes(), strings.Replace(admin.DomainBlocksPathWithID, ":"+admin.IDKey, id, 1), w.FormDataContentType())
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   admin.IDKey,
+			Value: id,
+		},
+	}
+
+	suite.adminModule.DomainBlockPUTHandler(ctx)
+
+	block := &apimodel.DomainBlock{}
+	if recorder.Code == http.StatusOK {
+		suite.NoError(json.Unmarshal(recorder.Body.Bytes(), block))
+	}
+	return recorder.Code, block
+}
+
+func (suite *DomainBlocksTestSuite) TestUpdate() {
+	code, block := suite.createBlock(map[string]string{"domain": "example.org", "severity": "silence", "public_comment": "they smell"})
+	suite.Equal(http.StatusOK, code)
+
+	// only the fields that are set are changed
+	code, block = suite.updateBlock(block.ID, map[string]string{"reject_media": "true", "private_comment": "lots of reports"})
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("silence", block.Severity)
+	suite.True(block.RejectMedia)
+	suite.Equal("they smell", block.PublicComment)
+	suite.Equal("lots of reports", block.PrivateComment)
+
+	code, block = suite.updateBlock(block.ID, map[string]string{"severity": "Suspend"})
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("suspend", block.Severity)
+
+	blocked, err := suite.db.IsDomainBlocked(context.Background(), "example.org")
+	suite.NoError(err)
+	suite.True(blocked)
+
+	// there's no going back from a suspension
+	code, _ = suite.updateBlock(block.ID, map[string]string{"severity": "silence"})
+	suite.Equal(http.StatusUnprocessableEntity, code)
+
+	code, _ = suite.updateBlock(block.ID, map[string]string{"severity": "noop"})
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func (suite *DomainBlocksTestSuite) TestExportCSV() {
+	suite.importBlocklist("example.org\n")
+
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, admin.DomainBlocksPath, "")
+	ctx.Request.Header.Set("accept", "text/csv")
+
+	suite.adminModule.DomainBlocksGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("text/csv", recorder.Header().Get("Content-Type"))
+
+	// blocks come back in the order they were created, and private comments aren't included
+	suite.Equal(`#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate,#include_subdomains
+replyguys.com,suspend,false,false,reply-guying to tech posts,false,false
+example.org,suspend,false,false,,false,false
+`, recorder.Body.String())
+}
+
+func TestDomainBlocksTestSuite(t *testing.T) {
+	suite.Run(t, &DomainBlocksTestSuite{})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/blocklist"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
@@ -14,19 +15,23 @@ import (
 //
 // View all domain blocks currently in place.
 //
+// If the request accepts `text/csv`, the domain blocks are returned as a CSV blocklist
+// in the format that Mastodon uses, so that they can be imported into other instances.
+//
 // ---
 // tags:
 // - admin
 //
 // produces:
 // - application/json
+// - text/csv
 //
 // parameters:
 // - name: export
 //   type: boolean
 //   description: |-
 //     If set to true, then each entry in the returned list of domain blocks will only consist of
-//     the fields 'domain' and 'public_comment'. This is perfect for when you want to save and share
+//     the fields 'domain', 'obfuscate', and 'public_comment'. This is perfect for when you want to save and share
 //     a list of all the domains you have blocked on your instance, so that someone else can easily import them,
 //     but you don't need them to see the database IDs of your blocks, or private comments etc.
 //   in: query
@@ -70,7 +75,8 @@ func (m *Module) DomainBlocksGETHandler(c *gin.Context) {
 		return
 	}
 
-	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+	format, err := api.NegotiateAccept(c, api.AppJSON, api.TextCSV)
+	if err != nil {
 		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
 		return
 	}
@@ -87,6 +93,12 @@ func (m *Module) DomainBlocksGETHandler(c *gin.Context) {
 		export = i
 	}
 
+	// a csv blocklist only has room for exported fields anyway
+	csv := format == string(api.TextCSV)
+	if csv {
+		export = true
+	}
+
 	domainBlocks, err := m.processor.AdminDomainBlocksGet(c.Request.Context(), authed, export)
 	if err != nil {
 		l.Debugf("error getting domain blocks: %s", err)
@@ -94,5 +106,26 @@ func (m *Module) DomainBlocksGETHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, domainBlocks)
+	if !csv {
+		c.JSON(http.StatusOK, domainBlocks)
+		return
+	}
+
+	entries := make([]blocklist.Entry, 0, len(domainBlocks))
+	for _, b := range domainBlocks {
+		entries = append(entries, blocklist.Entry{
+			Domain:        b.Domain,
+			Severity:      b.Severity,
+			Obfuscate:     b.Obfuscate,
+			RejectMedia:   b.RejectMedia,
+			PublicComment: b.PublicComment,
+		})
+	}
+
+	c.Header("Content-Type", format)
+	c.Header("Content-Disposition", `attachment; filename="domain_blocks.csv"`)
+	c.Status(http.StatusOK)
+	if err := blocklist.WriteCSV(c.Writer, entries); err != nil {
+		l.Errorf("error writing domain blocks: %s", err)
+	}
 }
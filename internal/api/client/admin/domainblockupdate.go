@@ -0,0 +1,166 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// DomainBlockPUTHandler swagger:operation PUT /api/v1/admin/domain_blocks/{id} domainBlockUpdate
+//
+// Change one domain block. Fields that aren't set are left as they are.
+//
+// A silence can be escalated to a suspension, which has the same effects as creating a new suspension,
+// but a suspension can't be changed back into a silence, since the domain's accounts will have been removed already.
+//
+// ---
+// tags:
+// - admin
+//
+// consumes:
+// - multipart/form-data
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: id
+//   type: string
+//   description: The id of the domain block.
+//   in: path
+//   required: true
+// - name: severity
+//   in: formData
+//   description: How severe the block should be, either 'suspend' or 'silence'.
+//   type: string
+// - name: reject_media
+//   in: formData
+//   description: Never cache media from the domain, even if the block is only a silence.
+//   type: boolean
+// - name: obfuscate
+//   in: formData
+//   description: Obfuscate the name of the domain when serving it publicly.
+//   type: boolean
+// - name: public_comment
+//   in: formData
+//   description: Public comment about this domain block.
+//   type: string
+// - name: private_comment
+//   in: formData
+//   description: Private comment about this domain block, which is only shown to other admins.
+//   type: string
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: The updated domain block.
+//     schema:
+//       "$ref": "#/definitions/domainBlock"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: unprocessable
+//   '500':
+//      description: internal error
+func (m *Module) DomainBlockPUTHandler(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "DomainBlockPUTHandler",
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainBlockID := c.Param(IDKey)
+	if domainBlockID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no domain block id provided"})
+		return
+	}
+
+	form := &model.DomainBlockUpdateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	if err := validateUpdateDomainBlock(form); err != nil {
+		l.Debugf("error validating form: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainBlock, errWithCode := m.processor.AdminDomainBlockUpdate(c.Request.Context(), authed, domainBlockID, form)
+	if errWithCode != nil {
+		l.Debugf("error updating domain block: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domainBlock)
+}
+
+func validateUpdateDomainBlock(form *model.DomainBlockUpdateRequest) error {
+	if form.Severity != nil {
+		severity := strings.ToLower(strings.TrimSpace(*form.Severity))
+		switch severity {
+		case gtsmodel.DomainBlockSeveritySuspend, gtsmodel.DomainBlockSeveritySilence:
+			form.Severity = &severity
+		default:
+			return fmt.Errorf("severity must be either %s or %s", gtsmodel.DomainBlockSeveritySuspend, gtsmodel.DomainBlockSeveritySilence)
+		}
+	}
+
+	return nil
+}
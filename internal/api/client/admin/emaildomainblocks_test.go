@@ -0,0 +1,112 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type EmailDomainBlocksTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// emailDomainBlock calls the given handler for the email domain block with the given id, or for all
+// email domain blocks if id is empty, with the given form fields, and returns the status code and body of the response.
+func (suite *EmailDomainBlocksTestSuite) emailDomainBlock(handler gin.HandlerFunc, method string, id string, fields map[string]string) (int, []byte) {
+	path := admin.EmailDomainBlocksPath
+	if id != "" {
+		path = admin.EmailDomainBlocksPathWithID
+	}
+	return suite.formRequest(handler, method, path, id, fields)
+}
+
+func (suite *EmailDomainBlocksTestSuite) getEmailDomainBlock(id string) *apimodel.AdminEmailDomainBlock {
+	code, b := suite.emailDomainBlock(suite.adminModule.EmailDomainBlockGETHandler, http.MethodGet, id, nil)
+	suite.Equal(http.StatusOK, code)
+
+	block := &apimodel.AdminEmailDomainBlock{}
+	suite.NoError(json.Unmarshal(b, block))
+	return block
+}
+
+func (suite *EmailDomainBlocksTestSuite) TestEmailDomainBlocks() {
+	code, b := suite.emailDomainBlock(suite.adminModule.EmailDomainBlockPOSTHandler, http.MethodPost, "", map[string]string{"domain": " someone@Mailinator.com "})
+	suite.Equal(http.StatusOK, code)
+	block := &apimodel.AdminEmailDomainBlock{}
+	suite.NoError(json.Unmarshal(b, block))
+	suite.Equal("mailinator.com", block.Domain)
+	suite.Equal(suite.testAccounts["admin_account"].ID, block.CreatedBy)
+	suite.Zero(block.Attempts)
+	suite.Nil(block.LastAttemptAt)
+
+	// the same domain can't be blocked twice
+	code, b = suite.emailDomainBlock(suite.adminModule.EmailDomainBlockPOSTHandler, http.MethodPost, "", map[string]string{"domain": "mailinator.com"})
+	suite.Equal(http.StatusConflict, code)
+	suite.Equal(`{"error":"conflict: email domain mailinator.com is already blocked"}`, string(b))
+
+	// someone tries to sign up with an address on a subdomain of the blocked domain
+	available, err := suite.db.IsEmailAvailable(context.Background(), "spammer@eu.mailinator.com")
+	suite.Error(err)
+	suite.False(available)
+
+	// checking the address doesn't count as an attempt, only signing up with it does
+	block = suite.getEmailDomainBlock(block.ID)
+	suite.Zero(block.Attempts)
+	suite.NoError(suite.db.CountEmailDomainBlockAttempt(context.Background(), "spammer@eu.mailinator.com"))
+
+	block = suite.getEmailDomainBlock(block.ID)
+	suite.Equal(1, block.Attempts)
+	suite.NotNil(block.LastAttemptAt)
+
+	code, b = suite.emailDomainBlock(suite.adminModule.EmailDomainBlocksGETHandler, http.MethodGet, "", nil)
+	suite.Equal(http.StatusOK, code)
+	blocks := []*apimodel.AdminEmailDomainBlock{}
+	suite.NoError(json.Unmarshal(b, &blocks))
+	suite.Len(blocks, 1)
+	suite.Equal(block.ID, blocks[0].ID)
+
+	// once the block is deleted, the domain can be signed up with again
+	code, _ = suite.emailDomainBlock(suite.adminModule.EmailDomainBlockDELETEHandler, http.MethodDelete, block.ID, nil)
+	suite.Equal(http.StatusOK, code)
+
+	code, _ = suite.emailDomainBlock(suite.adminModule.EmailDomainBlockGETHandler, http.MethodGet, block.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+
+	available, err = suite.db.IsEmailAvailable(context.Background(), "spammer@eu.mailinator.com")
+	suite.NoError(err)
+	suite.True(available)
+}
+
+func (suite *EmailDomainBlocksTestSuite) TestCreateEmailDomainBlockInvalid() {
+	code, b := suite.emailDomainBlock(suite.adminModule.EmailDomainBlockPOSTHandler, http.MethodPost, "", map[string]string{"domain": "not a domain"})
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"bad request: not a domain is not a valid domain"}`, string(b))
+}
+
+func TestEmailDomainBlocksTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailDomainBlocksTestSuite))
+}
@@ -25,8 +25,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/validate"
 )
@@ -56,7 +58,7 @@ import (
 //   required: true
 // - name: image
 //   in: formData
-//   description: A png or gif image of the emoji. Animated pngs work too!
+//   description: A png, gif, or svg image of the emoji. Animated pngs work too! Svgs are sanitized, and a png version is rendered for clients that can't display them.
 //   type: file
 //   required: true
 //
@@ -134,5 +136,10 @@ func validateCreateEmoji(form *model.EmojiCreateRequest) error {
 		return errors.New("no emoji given")
 	}
 
+	maxSize := viper.GetInt(config.Keys.MediaEmojiMaxSize)
+	if maxSize > 0 && form.Image.Size > int64(maxSize) {
+		return fmt.Errorf("emoji size limit exceeded: limit is %d bytes but emoji was %d bytes", maxSize, form.Image.Size)
+	}
+
 	return validate.EmojiShortcode(form.Shortcode)
 }
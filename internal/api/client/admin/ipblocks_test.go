@@ -0,0 +1,155 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type IPBlocksTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// ipBlock calls the given handler for the IP block with the given id, or for all IP blocks if id is empty,
+// with the given form fields, and returns the status code and body of the response.
+func (suite *IPBlocksTestSuite) ipBlock(handler gin.HandlerFunc, method string, id string, fields map[string]string) (int, []byte) {
+	path := admin.IPBlocksPath
+	if id != "" {
+		path = admin.IPBlocksPathWithID
+	}
+	return suite.formRequest(handler, method, path, id, fields)
+}
+
+func (suite *IPBlocksTestSuite) createIPBlock(fields map[string]string) *apimodel.AdminIPBlock {
+	code, b := suite.ipBlock(suite.adminModule.IPBlockPOSTHandler, http.MethodPost, "", fields)
+	suite.Equal(http.StatusOK, code)
+
+	block := &apimodel.AdminIPBlock{}
+	suite.NoError(json.Unmarshal(b, block))
+	return block
+}
+
+func (suite *IPBlocksTestSuite) getIPBlock(id string) *apimodel.AdminIPBlock {
+	code, b := suite.ipBlock(suite.adminModule.IPBlockGETHandler, http.MethodGet, id, nil)
+	suite.Equal(http.StatusOK, code)
+
+	block := &apimodel.AdminIPBlock{}
+	suite.NoError(json.Unmarshal(b, block))
+	return block
+}
+
+func (suite *IPBlocksTestSuite) severity(ip string) string {
+	severity, err := suite.db.GetIPBlockSeverity(context.Background(), net.ParseIP(ip))
+	suite.NoError(err)
+	return severity
+}
+
+func (suite *IPBlocksTestSuite) TestIPBlocks() {
+	single := suite.createIPBlock(map[string]string{"ip": "192.0.2.1", "comment": "spam bot"})
+	suite.Equal("192.0.2.1/32", single.IP)
+	suite.Equal(gtsmodel.IPBlockSeverityNoAccess, single.Severity)
+	suite.Equal("spam bot", single.Comment)
+	suite.Nil(single.ExpiresAt)
+
+	// ranges are stored by their network address
+	subnet := suite.createIPBlock(map[string]string{"ip": "192.0.2.77/24", "severity": gtsmodel.IPBlockSeveritySignUpBlock, "expires_in": "3600"})
+	suite.Equal("192.0.2.0/24", subnet.IP)
+	suite.NotNil(subnet.ExpiresAt)
+
+	// the most severe block covering an address wins
+	suite.Equal(gtsmodel.IPBlockSeverityNoAccess, suite.severity("192.0.2.1"))
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpBlock, suite.severity("192.0.2.2"))
+	suite.Empty(suite.severity("198.51.100.1"))
+
+	code, b := suite.ipBlock(suite.adminModule.IPBlockPOSTHandler, http.MethodPost, "", map[string]string{"ip": "192.0.2.0/24"})
+	suite.Equal(http.StatusConflict, code)
+	suite.Equal(`{"error":"conflict: ip range 192.0.2.0/24 is already blocked"}`, string(b))
+
+	code, b = suite.ipBlock(suite.adminModule.IPBlocksGETHandler, http.MethodGet, "", nil)
+	suite.Equal(http.StatusOK, code)
+	blocks := []*apimodel.AdminIPBlock{}
+	suite.NoError(json.Unmarshal(b, &blocks))
+	suite.Len(blocks, 2)
+	suite.Equal(subnet.ID, blocks[0].ID)
+	suite.Equal(single.ID, blocks[1].ID)
+
+	// soften the single address block, and stop the subnet block from expiring
+	code, b = suite.ipBlock(suite.adminModule.IPBlockPUTHandler, http.MethodPut, single.ID, map[string]string{"severity": gtsmodel.IPBlockSeveritySignUpRequiresApproval})
+	suite.Equal(http.StatusOK, code)
+	updated := &apimodel.AdminIPBlock{}
+	suite.NoError(json.Unmarshal(b, updated))
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpRequiresApproval, updated.Severity)
+	suite.Equal("spam bot", updated.Comment)
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpBlock, suite.severity("192.0.2.1"))
+
+	code, b = suite.ipBlock(suite.adminModule.IPBlockPUTHandler, http.MethodPut, subnet.ID, map[string]string{"expires_in": "0"})
+	suite.Equal(http.StatusOK, code)
+	updated = &apimodel.AdminIPBlock{}
+	suite.NoError(json.Unmarshal(b, updated))
+	suite.Nil(updated.ExpiresAt)
+
+	code, _ = suite.ipBlock(suite.adminModule.IPBlockDELETEHandler, http.MethodDelete, subnet.ID, nil)
+	suite.Equal(http.StatusOK, code)
+
+	code, _ = suite.ipBlock(suite.adminModule.IPBlockGETHandler, http.MethodGet, subnet.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpRequiresApproval, suite.severity("192.0.2.1"))
+	suite.Empty(suite.severity("192.0.2.2"))
+}
+
+func (suite *IPBlocksTestSuite) TestIPBlockExpiry() {
+	block := suite.createIPBlock(map[string]string{"ip": "2001:db8::/32", "expires_in": "60"})
+	suite.Equal(gtsmodel.IPBlockSeverityNoAccess, suite.severity("2001:db8::1"))
+
+	// nothing has expired yet
+	suite.NoError(suite.db.DeleteExpiredIPBlocks(context.Background(), time.Now()))
+	suite.Equal(block.ID, suite.getIPBlock(block.ID).ID)
+
+	// but it will have in an hour
+	suite.NoError(suite.db.DeleteExpiredIPBlocks(context.Background(), time.Now().Add(time.Hour)))
+	code, _ := suite.ipBlock(suite.adminModule.IPBlockGETHandler, http.MethodGet, block.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+	suite.Empty(suite.severity("2001:db8::1"))
+}
+
+func (suite *IPBlocksTestSuite) TestCreateIPBlockInvalid() {
+	code, b := suite.ipBlock(suite.adminModule.IPBlockPOSTHandler, http.MethodPost, "", map[string]string{"ip": "not an ip"})
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"bad request: not an ip is not a valid ip address or range"}`, string(b))
+
+	code, b = suite.ipBlock(suite.adminModule.IPBlockPOSTHandler, http.MethodPost, "", map[string]string{"ip": "192.0.2.1", "severity": "silence"})
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"bad request: ip block severity must be one of sign_up_requires_approval, sign_up_block, or no_access"}`, string(b))
+}
+
+func TestIPBlocksTestSuite(t *testing.T) {
+	suite.Run(t, new(IPBlocksTestSuite))
+}
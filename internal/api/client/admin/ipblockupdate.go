@@ -0,0 +1,141 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// IPBlockPUTHandler swagger:operation PUT /api/v1/admin/ip_blocks/{id} ipBlockUpdate
+//
+// Change one IP block. Fields that aren't provided are left as they are.
+//
+// ---
+// tags:
+// - admin
+//
+// consumes:
+// - multipart/form-data
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: id
+//   type: string
+//   description: The id of the IP block.
+//   in: path
+//   required: true
+// - name: ip
+//   in: formData
+//   description: The IP address or range to block, eg. 192.0.2.1 or 192.0.2.0/24.
+//   type: string
+// - name: severity
+//   in: formData
+//   description: How severe the block should be. sign_up_requires_approval makes new accounts from the range need approval, sign_up_block stops new accounts being signed up from the range, and no_access stops the range from making any requests at all.
+//   type: string
+//   enum:
+//     - sign_up_requires_approval
+//     - sign_up_block
+//     - no_access
+// - name: comment
+//   in: formData
+//   description: Private comment on the block, viewable to admins.
+//   type: string
+// - name: expires_in
+//   in: formData
+//   description: Number of seconds from now that the block should stop applying. 0 means it never expires.
+//   type: integer
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: The updated IP block.
+//     schema:
+//       "$ref": "#/definitions/adminIPBlock"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+//   '409':
+//      description: conflict; the IP range is already blocked by another IP block
+//   '500':
+//      description: internal error
+func (m *Module) IPBlockPUTHandler(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "IPBlockPUTHandler",
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	blockID := c.Param(IDKey)
+	if blockID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no ip block id provided"})
+		return
+	}
+
+	form := &model.AdminIPBlockUpdateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("error parsing form %+v: %s", c.Request.Form, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("could not parse form: %s", err)})
+		return
+	}
+
+	block, errWithCode := m.processor.AdminIPBlockUpdate(c.Request.Context(), authed, blockID, form)
+	if errWithCode != nil {
+		l.Debugf("error updating ip block: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, block)
+}
@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// MeasuresGETHandler swagger:operation GET /api/v1/admin/measures measuresGet
+//
+// View how many accounts signed up, statuses were posted, and accounts signed in on this instance, week by week.
+//
+// Weeks start at midnight UTC on a Monday. The current week is included, measured up to now.
+//
+// ---
+// tags:
+// - admin
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: weeks
+//   type: integer
+//   description: Number of weeks to measure, up to and including the current one. At least 1, and at most 52.
+//   default: 12
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: Measures of each week, oldest first.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/adminWeeklyMeasures"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) MeasuresGETHandler(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "MeasuresGETHandler",
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	weeks := 12
+	weeksString := c.Query(WeeksQueryKey)
+	if weeksString != "" {
+		i, err := strconv.ParseInt(weeksString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing weeks string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse weeks query param"})
+			return
+		}
+		weeks = int(i)
+	}
+	if weeks < 1 {
+		weeks = 1
+	}
+
+	measures, errWithCode := m.processor.AdminMeasuresGet(c.Request.Context(), authed, weeks)
+	if errWithCode != nil {
+		l.Debugf("error getting measures: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, measures)
+}
@@ -0,0 +1,90 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type MediaStatsTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *MediaStatsTestSuite) getStats() *apimodel.AdminMediaStats {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, admin.MediaStatsPath, "")
+
+	suite.adminModule.MediaStatsGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	stats := &apimodel.AdminMediaStats{}
+	suite.NoError(json.Unmarshal(b, stats))
+	return stats
+}
+
+func (suite *MediaStatsTestSuite) TestMediaStats() {
+	stats := suite.getStats()
+
+	suite.Equal(suite.mediaManager.NumWorkers(), stats.NumWorkers)
+	suite.Equal(suite.mediaManager.QueueSize(), stats.QueueSize)
+	suite.Equal(suite.mediaManager.EmojiNumWorkers(), stats.EmojiNumWorkers)
+	suite.Equal(suite.mediaManager.EmojiQueueSize(), stats.EmojiQueueSize)
+	suite.NotZero(stats.LocalBytesStored)
+	suite.NotZero(stats.RemoteBytesStored)
+
+	// nothing has been pruned yet
+	suite.Nil(stats.LastPrune)
+}
+
+func (suite *MediaStatsTestSuite) TestMediaStatsAfterPrune() {
+	before := suite.getStats()
+
+	pruned, err := suite.mediaManager.PruneRemote(context.Background(), 1)
+	suite.NoError(err)
+	suite.Equal(1, pruned)
+
+	after := suite.getStats()
+	suite.Equal(before.LocalBytesStored, after.LocalBytesStored)
+	suite.Zero(after.RemoteBytesStored)
+
+	suite.NotNil(after.LastPrune)
+	suite.Equal(1, after.LastPrune.Pruned)
+	suite.Equal(before.RemoteBytesStored, after.LastPrune.PrunedBytes)
+	suite.NotEmpty(after.LastPrune.StartedAt)
+	suite.NotEmpty(after.LastPrune.FinishedAt)
+}
+
+func TestMediaStatsTestSuite(t *testing.T) {
+	suite.Run(t, &MediaStatsTestSuite{})
+}
@@ -0,0 +1,124 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ReportsTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// report calls the given handler at the given path for the report with the given id, if it isn't empty,
+// with the given form fields, and returns the status code and body of the response.
+func (suite *ReportsTestSuite) report(handler gin.HandlerFunc, path string, id string, fields map[string]string) (int, []byte) {
+	return suite.formRequest(handler, http.MethodPost, path, id, fields)
+}
+
+func (suite *ReportsTestSuite) getReports(query string) []*apimodel.AdminReportInfo {
+	code, b := suite.report(suite.adminModule.ReportsGETHandler, admin.ReportsPath+query, "", nil)
+	suite.Equal(http.StatusOK, code)
+
+	reports := []*apimodel.AdminReportInfo{}
+	suite.NoError(json.Unmarshal(b, &reports))
+	return reports
+}
+
+func (suite *ReportsTestSuite) reportAction(handler gin.HandlerFunc, path string, id string, fields map[string]string) *apimodel.AdminReportInfo {
+	code, b := suite.report(handler, path, id, fields)
+	suite.Equal(http.StatusOK, code)
+
+	report := &apimodel.AdminReportInfo{}
+	suite.NoError(json.Unmarshal(b, report))
+	return report
+}
+
+func (suite *ReportsTestSuite) TestModerationWorkflow() {
+	reporter := suite.testAccounts["local_account_1"]
+	target := suite.testAccounts["local_account_2"]
+	moderator := suite.testAccounts["admin_account"]
+
+	created, errWithCode := suite.processor.ReportCreate(context.Background(), &oauth.Auth{
+		Application: suite.testApplications["local_account_1"],
+		User:        suite.testUsers["local_account_1"],
+		Account:     reporter,
+	}, &apimodel.ReportCreateRequest{
+		AccountID: target.ID,
+		StatusIDs: []string{suite.testStatuses["local_account_2_status_1"].ID},
+		Comment:   "this is spam",
+		Category:  "spam",
+	})
+	suite.NoError(errWithCode)
+
+	reports := suite.getReports("?" + admin.ResolvedQueryKey + "=false")
+	suite.Len(reports, 1)
+	suite.Equal(created.ID, reports[0].ID)
+	suite.Equal(reporter.ID, reports[0].Account.ID)
+	suite.Equal(target.ID, reports[0].TargetAccount.ID)
+	suite.Equal("spam", reports[0].Category)
+	suite.Len(reports[0].Statuses, 1)
+	suite.Nil(reports[0].AssignedAccount)
+
+	assigned := suite.reportAction(suite.adminModule.ReportAssignToSelfPOSTHandler, admin.ReportAssignToSelfPath, created.ID, nil)
+	suite.Equal(moderator.ID, assigned.AssignedAccount.ID)
+
+	resolved := suite.reportAction(suite.adminModule.ReportResolvePOSTHandler, admin.ReportResolvePath, created.ID, map[string]string{"action_taken_comment": "silenced the account"})
+	suite.True(resolved.ActionTaken)
+	suite.NotNil(resolved.ActionTakenAt)
+	suite.Equal("silenced the account", resolved.ActionTakenComment)
+	suite.Equal(moderator.ID, resolved.ActionTakenByAccount.ID)
+
+	// it's only listed with resolved reports now
+	suite.Empty(suite.getReports("?" + admin.ResolvedQueryKey + "=false"))
+	suite.Len(suite.getReports("?"+admin.ResolvedQueryKey+"=true&"+admin.TargetAccountIDQueryKey+"="+target.ID), 1)
+
+	// a resolved report can't be resolved again until it's reopened
+	code, b := suite.report(suite.adminModule.ReportResolvePOSTHandler, admin.ReportResolvePath, created.ID, nil)
+	suite.Equal(http.StatusConflict, code)
+	suite.Equal(`{"error":"conflict: report was already resolved"}`, string(b))
+
+	reopened := suite.reportAction(suite.adminModule.ReportReopenPOSTHandler, admin.ReportReopenPath, created.ID, nil)
+	suite.False(reopened.ActionTaken)
+	suite.Nil(reopened.ActionTakenAt)
+	suite.Empty(reopened.ActionTakenComment)
+	suite.Nil(reopened.ActionTakenByAccount)
+	suite.Equal(moderator.ID, reopened.AssignedAccount.ID)
+
+	unassigned := suite.reportAction(suite.adminModule.ReportUnassignPOSTHandler, admin.ReportUnassignPath, created.ID, nil)
+	suite.Nil(unassigned.AssignedAccount)
+}
+
+func (suite *ReportsTestSuite) TestGetReportNotFound() {
+	code, _ := suite.report(suite.adminModule.ReportGETHandler, admin.ReportsPathWithID, "01GB3ZCVD2BV0NRMAXZ1R5SS64", nil)
+	suite.Equal(http.StatusNotFound, code)
+}
+
+func TestReportsTestSuite(t *testing.T) {
+	suite.Run(t, &ReportsTestSuite{})
+}
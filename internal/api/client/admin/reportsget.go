@@ -0,0 +1,148 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ReportsGETHandler swagger:operation GET /api/v1/admin/reports reportsGet
+//
+// View reports made to the moderators of this instance, newest first.
+//
+// The next and previous pages of reports can be got with max_id and since_id.
+//
+// ---
+// tags:
+// - admin
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: resolved
+//   type: boolean
+//   description: If set, only list reports that have (true) or haven't (false) been resolved.
+//   in: query
+// - name: account_id
+//   type: string
+//   description: Only list reports made by this account.
+//   in: query
+// - name: target_account_id
+//   type: string
+//   description: Only list reports about this account.
+//   in: query
+// - name: max_id
+//   type: string
+//   description: Only list reports older than this ID.
+//   in: query
+// - name: since_id
+//   type: string
+//   description: Only list reports newer than this ID.
+//   in: query
+// - name: limit
+//   type: integer
+//   description: Number of reports to return.
+//   default: 20
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: Reports, newest first.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/adminReport"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) ReportsGETHandler(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "ReportsGETHandler",
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resolved *bool
+	resolvedString := c.Query(ResolvedQueryKey)
+	if resolvedString != "" {
+		r, err := strconv.ParseBool(resolvedString)
+		if err != nil {
+			l.Debugf("error parsing resolved string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse resolved query param"})
+			return
+		}
+		resolved = &r
+	}
+
+	limit := 20
+	limitString := c.Query(LimitQueryKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil || i <= 0 {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	reports, errWithCode := m.processor.AdminReportsGet(c.Request.Context(), authed, resolved, c.Query(AccountIDQueryKey), c.Query(TargetAccountIDQueryKey), c.Query(MaxIDQueryKey), c.Query(SinceIDQueryKey), limit)
+	if errWithCode != nil {
+		l.Debugf("error getting reports: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
@@ -0,0 +1,118 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type RulesTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// rule calls the given handler for the rule with the given id, or for all rules if id is empty,
+// with the given form fields, and returns the status code and body of the response.
+func (suite *RulesTestSuite) rule(handler gin.HandlerFunc, method string, id string, fields map[string]string) (int, []byte) {
+	path := admin.RulesPath
+	if id != "" {
+		path = admin.RulesPathWithID
+	}
+	return suite.formRequest(handler, method, path, id, fields)
+}
+
+func (suite *RulesTestSuite) createRule(fields map[string]string) *apimodel.AdminRule {
+	code, b := suite.rule(suite.adminModule.RulePOSTHandler, http.MethodPost, "", fields)
+	suite.Equal(http.StatusOK, code)
+
+	rule := &apimodel.AdminRule{}
+	suite.NoError(json.Unmarshal(b, rule))
+	return rule
+}
+
+func (suite *RulesTestSuite) getRules() []*apimodel.AdminRule {
+	code, b := suite.rule(suite.adminModule.RulesGETHandler, http.MethodGet, "", nil)
+	suite.Equal(http.StatusOK, code)
+
+	rules := []*apimodel.AdminRule{}
+	suite.NoError(json.Unmarshal(b, &rules))
+	return rules
+}
+
+func (suite *RulesTestSuite) TestRules() {
+	suite.Empty(suite.getRules())
+
+	jerk := suite.createRule(map[string]string{"text": "Don't be a jerk."})
+	suite.NotEmpty(jerk.ID)
+	suite.Equal("Don't be a jerk.", jerk.Text)
+	suite.Equal(0, jerk.Priority)
+
+	spam := suite.createRule(map[string]string{"text": "  No spam.  ", "priority": "1"})
+	suite.Equal("No spam.", spam.Text)
+	suite.Equal(1, spam.Priority)
+
+	rules := suite.getRules()
+	suite.Len(rules, 2)
+	suite.Equal(jerk.ID, rules[0].ID)
+	suite.Equal(spam.ID, rules[1].ID)
+
+	// move spam to the top and reword it
+	code, b := suite.rule(suite.adminModule.RulePATCHHandler, http.MethodPatch, spam.ID, map[string]string{"text": "No spam, please.", "priority": "-1"})
+	suite.Equal(http.StatusOK, code)
+	updated := &apimodel.AdminRule{}
+	suite.NoError(json.Unmarshal(b, updated))
+	suite.Equal("No spam, please.", updated.Text)
+	suite.Equal(-1, updated.Priority)
+
+	// clients see the rules in their new order
+	instanceRules, errWithCode := suite.processor.InstanceRulesGet(context.Background())
+	suite.NoError(errWithCode)
+	suite.Equal([]apimodel.InstanceRule{
+		{ID: spam.ID, Text: "No spam, please."},
+		{ID: jerk.ID, Text: "Don't be a jerk."},
+	}, instanceRules)
+
+	// delete jerk, after which it can't be got any more
+	code, _ = suite.rule(suite.adminModule.RuleDELETEHandler, http.MethodDelete, jerk.ID, nil)
+	suite.Equal(http.StatusOK, code)
+
+	code, _ = suite.rule(suite.adminModule.RuleGETHandler, http.MethodGet, jerk.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+
+	rules = suite.getRules()
+	suite.Len(rules, 1)
+	suite.Equal(spam.ID, rules[0].ID)
+}
+
+func (suite *RulesTestSuite) TestCreateRuleEmpty() {
+	code, b := suite.rule(suite.adminModule.RulePOSTHandler, http.MethodPost, "", map[string]string{"text": " "})
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"bad request: rule text was empty"}`, string(b))
+}
+
+func TestRulesTestSuite(t *testing.T) {
+	suite.Run(t, &RulesTestSuite{})
+}
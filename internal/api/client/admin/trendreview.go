@@ -0,0 +1,174 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TrendApprovePOSTHandler swagger:operation POST /api/v1/admin/trends/{type}/{id}/approve trendApprove
+//
+// Approve a hashtag, status or link, so that it's shown in public trends whenever it's trending.
+//
+// ---
+// tags:
+// - admin
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: type
+//   type: string
+//   enum:
+//   - tags
+//   - statuses
+//   - links
+//   description: The kind of thing to approve.
+//   in: path
+//   required: true
+// - name: id
+//   type: string
+//   description: The id of the hashtag, status or link.
+//   in: path
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: The approved hashtag, status or link.
+//     schema:
+//       "$ref": "#/definitions/adminTrend"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
+//   '500':
+//      description: internal error
+func (m *Module) TrendApprovePOSTHandler(c *gin.Context) {
+	m.trendReview(c, true)
+}
+
+// TrendRejectPOSTHandler swagger:operation POST /api/v1/admin/trends/{type}/{id}/reject trendReject
+//
+// Reject a hashtag, status or link, so that it's never shown in public trends.
+//
+// ---
+// tags:
+// - admin
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: type
+//   type: string
+//   enum:
+//   - tags
+//   - statuses
+//   - links
+//   description: The kind of thing to reject.
+//   in: path
+//   required: true
+// - name: id
+//   type: string
+//   description: The id of the hashtag, status or link.
+//   in: path
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: The rejected hashtag, status or link.
+//     schema:
+//       "$ref": "#/definitions/adminTrend"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
+//   '500':
+//      description: internal error
+func (m *Module) TrendRejectPOSTHandler(c *gin.Context) {
+	m.trendReview(c, false)
+}
+
+// trendReview does the work of approving or rejecting a trend.
+func (m *Module) trendReview(c *gin.Context, approve bool) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "trendReview",
+		"approve":     approve,
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	trendType, ok := trendTypes[c.Param(TrendTypeKey)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "404 page not found"})
+		return
+	}
+
+	targetID := c.Param(IDKey)
+	if targetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no id provided"})
+		return
+	}
+
+	trend, errWithCode := m.processor.AdminTrendReview(c.Request.Context(), authed, trendType, targetID, approve)
+	if errWithCode != nil {
+		l.Debugf("error reviewing trend: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
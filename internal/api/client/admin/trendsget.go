@@ -0,0 +1,139 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// trendTypes maps the trend types used in paths to the kinds of trend that they're for.
+var trendTypes = map[string]gtsmodel.TrendType{
+	"tags":     gtsmodel.TrendTypeTag,
+	"statuses": gtsmodel.TrendTypeStatus,
+	"links":    gtsmodel.TrendTypeLink,
+}
+
+// TrendsGETHandler swagger:operation GET /api/v1/admin/trends/{type} trendsAdminGet
+//
+// View the hashtags, statuses or links that are trending on this instance, most trending first.
+//
+// Unlike the public trends endpoints, this includes trends that haven't been approved yet, and trends that were rejected.
+//
+// ---
+// tags:
+// - admin
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: type
+//   type: string
+//   enum:
+//   - tags
+//   - statuses
+//   - links
+//   description: The kind of trends to view.
+//   in: path
+//   required: true
+// - name: limit
+//   type: integer
+//   description: Number of trends to return.
+//   default: 20
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - admin
+//
+// responses:
+//   '200':
+//     description: Trending hashtags, statuses or links, with whether they've been reviewed.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/adminTrend"
+//   '400':
+//      description: bad request
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
+//   '500':
+//      description: internal error
+func (m *Module) TrendsGETHandler(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":        "TrendsGETHandler",
+		"request_uri": c.Request.RequestURI,
+		"user_agent":  c.Request.UserAgent(),
+		"origin_ip":   c.ClientIP(),
+	})
+
+	// make sure we're authed with an admin account
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("couldn't auth: %s", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if !authed.User.Admin {
+		l.Debugf("user %s not an admin", authed.User.ID)
+		c.JSON(http.StatusForbidden, gin.H{"error": "not an admin"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	trendType, ok := trendTypes[c.Param(TrendTypeKey)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "404 page not found"})
+		return
+	}
+
+	limit := 20
+	limitString := c.Query(LimitQueryKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil || i <= 0 {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	trends, errWithCode := m.processor.AdminTrendsGet(c.Request.Context(), authed, trendType, limit)
+	if errWithCode != nil {
+		l.Debugf("error getting trends: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trends)
+}
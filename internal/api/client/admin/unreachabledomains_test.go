@@ -0,0 +1,161 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type UnreachableDomainsTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *UnreachableDomainsTestSuite) getDomains() []*apimodel.AdminUnreachableDomain {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodGet, nil, admin.UnreachableDomainsPath, "")
+
+	suite.adminModule.UnreachableDomainsGETHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	domains := []*apimodel.AdminUnreachableDomain{}
+	suite.NoError(json.Unmarshal(b, &domains))
+	return domains
+}
+
+func (suite *UnreachableDomainsTestSuite) deleteDomain(id string) (int, *apimodel.AdminUnreachableDomain) {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, http.MethodDelete, nil, strings.Replace(admin.UnreachableDomainsPathWithID, ":"+admin.IDKey, id, 1), "")
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   admin.IDKey,
+			Value: id,
+		},
+	}
+
+	suite.adminModule.UnreachableDomainDELETEHandler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	domain := &apimodel.AdminUnreachableDomain{}
+	suite.NoError(json.Unmarshal(b, domain))
+	return recorder.Code, domain
+}
+
+func (suite *UnreachableDomainsTestSuite) TestUnreachableDomains() {
+	suite.Empty(suite.getDomains())
+
+	ctx := context.Background()
+	failingSince := time.Now().Add(-10 * 24 * time.Hour)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.UnreachableDomain{
+		ID:          "01G0SRHQ5X5KTSAT2C1MVPJ2W8",
+		CreatedAt:   failingSince,
+		UpdatedAt:   time.Now(),
+		Domain:      "dead.example.org",
+		Failures:    40,
+		LastError:   "POST request to https://dead.example.org/inbox failed (502): 502 Bad Gateway",
+		SuspendedAt: time.Now().Add(-3 * 24 * time.Hour),
+	}))
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.UnreachableDomain{
+		ID:        "01G0SRJ2XQ9T2PZ6M7VZ6D4E1N",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UpdatedAt: time.Now(),
+		Domain:    "flaky.example.org",
+		Failures:  2,
+		LastError: "POST request to https://flaky.example.org/inbox failed (503): 503 Service Unavailable",
+	}))
+
+	domains := suite.getDomains()
+	suite.Len(domains, 2)
+	suite.Equal("dead.example.org", domains[0].Domain)
+	suite.Equal(40, domains[0].Failures)
+	suite.Equal(failingSince.Format(time.RFC3339), domains[0].FailingSince)
+	suite.NotEmpty(domains[0].SuspendedAt)
+	suite.Equal("flaky.example.org", domains[1].Domain)
+	suite.Empty(domains[1].SuspendedAt)
+
+	// deleting a domain resumes deliveries to it
+	code, deleted := suite.deleteDomain("01G0SRHQ5X5KTSAT2C1MVPJ2W8")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("dead.example.org", deleted.Domain)
+
+	domains = suite.getDomains()
+	suite.Len(domains, 1)
+	suite.Equal("flaky.example.org", domains[0].Domain)
+
+	// it can't be deleted twice
+	code, _ = suite.deleteDomain("01G0SRHQ5X5KTSAT2C1MVPJ2W8")
+	suite.Equal(http.StatusNotFound, code)
+}
+
+func (suite *UnreachableDomainsTestSuite) TestSuspendDeliveries() {
+	code, b := suite.formRequest(suite.adminModule.UnreachableDomainPOSTHandler, http.MethodPost, admin.UnreachableDomainsPath, "", map[string]string{
+		"domain": " Fine.Example.org ",
+	})
+	suite.Equal(http.StatusOK, code)
+
+	suspended := &apimodel.AdminUnreachableDomain{}
+	suite.NoError(json.Unmarshal(b, suspended))
+	suite.Equal("fine.example.org", suspended.Domain)
+	suite.NotEmpty(suspended.SuspendedAt)
+	suite.True(suspended.SuspendedByAdmin)
+	suite.Zero(suspended.Failures)
+
+	domains := suite.getDomains()
+	suite.Len(domains, 1)
+	suite.Equal(suspended.ID, domains[0].ID)
+	suite.True(domains[0].SuspendedByAdmin)
+
+	// deleting the domain resumes deliveries to it again
+	code, _ = suite.deleteDomain(suspended.ID)
+	suite.Equal(http.StatusOK, code)
+	suite.Empty(suite.getDomains())
+
+	// our own domain can't be suspended
+	code, _ = suite.formRequest(suite.adminModule.UnreachableDomainPOSTHandler, http.MethodPost, admin.UnreachableDomainsPath, "", map[string]string{
+		"domain": "localhost:8080",
+	})
+	suite.Equal(http.StatusBadRequest, code)
+}
+
+func TestUnreachableDomainsTestSuite(t *testing.T) {
+	suite.Run(t, &UnreachableDomainsTestSuite{})
+}
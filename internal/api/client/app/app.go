@@ -26,8 +26,12 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 )
 
-// BasePath is the base path for this api module
-const BasePath = "/api/v1/apps"
+const (
+	// BasePath is the base path for this api module
+	BasePath = "/api/v1/apps"
+	// VerifyCredentialsPath is for checking that an app token is valid
+	VerifyCredentialsPath = BasePath + "/verify_credentials"
+)
 
 // Module implements the ClientAPIModule interface for requests relating to registering/removing applications
 type Module struct {
@@ -44,5 +48,6 @@ func New(processor processing.Processor) api.ClientModule {
 // Route satisfies the RESTAPIModule interface
 func (m *Module) Route(s router.Router) error {
 	s.AttachHandler(http.MethodPost, BasePath, m.AppsPOSTHandler)
+	s.AttachHandler(http.MethodGet, VerifyCredentialsPath, m.AppVerifyGETHandler)
 	return nil
 }
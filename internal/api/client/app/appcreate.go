@@ -110,6 +110,10 @@ func (m *Module) AppsPOSTHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("scopes must be less than %d bytes", formFieldLen)})
 		return
 	}
+	if _, err := oauth.ParseScopes(form.Scopes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	apiApp, err := m.processor.AppCreate(c.Request.Context(), authed, form)
 	if err != nil {
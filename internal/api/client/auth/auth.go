@@ -25,6 +25,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/oidc"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 )
 
@@ -46,6 +47,9 @@ const (
 	// OauthTokenPath is the API path to use for granting token requests to users with valid credentials
 	OauthTokenPath = "/oauth/token"
 
+	// OauthRevokePath is the API path for clients to revoke access tokens, eg., when a user logs out
+	OauthRevokePath = "/oauth/revoke"
+
 	// OauthAuthorizePath is the API path for authorization requests (eg., authorize this app to act on my behalf as a user)
 	OauthAuthorizePath = "/oauth/authorize"
 
@@ -55,28 +59,32 @@ const (
 	callbackStateParam = "state"
 	callbackCodeParam  = "code"
 
-	sessionUserID       = "userid"
-	sessionClientID     = "client_id"
-	sessionRedirectURI  = "redirect_uri"
-	sessionForceLogin   = "force_login"
-	sessionResponseType = "response_type"
-	sessionScope        = "scope"
-	sessionState        = "state"
+	sessionUserID              = "userid"
+	sessionClientID            = "client_id"
+	sessionRedirectURI         = "redirect_uri"
+	sessionForceLogin          = "force_login"
+	sessionResponseType        = "response_type"
+	sessionScope               = "scope"
+	sessionState               = "state"
+	sessionCodeChallenge       = "code_challenge"
+	sessionCodeChallengeMethod = "code_challenge_method"
 )
 
 // Module implements the ClientAPIModule interface for
 type Module struct {
-	db     db.DB
-	server oauth.Server
-	idp    oidc.IDP
+	db        db.DB
+	server    oauth.Server
+	idp       oidc.IDP
+	processor processing.Processor
 }
 
 // New returns a new auth module
-func New(db db.DB, server oauth.Server, idp oidc.IDP) api.ClientModule {
+func New(db db.DB, server oauth.Server, idp oidc.IDP, processor processing.Processor) api.ClientModule {
 	return &Module{
-		db:     db,
-		server: server,
-		idp:    idp,
+		db:        db,
+		server:    server,
+		idp:       idp,
+		processor: processor,
 	}
 }
 
@@ -86,6 +94,7 @@ func (m *Module) Route(s router.Router) error {
 	s.AttachHandler(http.MethodPost, AuthSignInPath, m.SignInPOSTHandler)
 
 	s.AttachHandler(http.MethodPost, OauthTokenPath, m.TokenPOSTHandler)
+	s.AttachHandler(http.MethodPost, OauthRevokePath, m.RevokePOSTHandler)
 
 	s.AttachHandler(http.MethodGet, OauthAuthorizePath, m.AuthorizeGETHandler)
 	s.AttachHandler(http.MethodPost, OauthAuthorizePath, m.AuthorizePOSTHandler)
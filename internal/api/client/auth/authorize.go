@@ -34,6 +34,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
 // AuthorizeGETHandler should be served as GET at https://example.org/oauth/authorize
@@ -119,6 +120,28 @@ func (m *Module) AuthorizeGETHandler(c *gin.Context) {
 		return
 	}
 
+	// the requested scopes must be a subset of the scopes that the app registered with
+	scopes, err := oauth.ParseScopes(scope)
+	if err != nil {
+		m.clearSession(s)
+		c.HTML(http.StatusBadRequest, "error.tmpl", gin.H{"error": err.Error()})
+		return
+	}
+	scopeDescriptions := make([]gin.H, 0, len(scopes))
+	for _, requested := range scopes {
+		if !requested.PermittedBy(app.Scopes) {
+			m.clearSession(s)
+			c.HTML(http.StatusBadRequest, "error.tmpl", gin.H{
+				"error": fmt.Sprintf("scope %s was not registered by application %s", requested, app.Name),
+			})
+			return
+		}
+		scopeDescriptions = append(scopeDescriptions, gin.H{
+			"scope":       requested,
+			"description": requested.Description(),
+		})
+	}
+
 	// the authorize template will display a form to the user where they can get some information
 	// about the app that's trying to authorize, and the scope of the request.
 	// They can then approve it if it looks OK to them, which will POST to the AuthorizePOSTHandler
@@ -128,6 +151,7 @@ func (m *Module) AuthorizeGETHandler(c *gin.Context) {
 		"appwebsite": app.Website,
 		"redirect":   redirect,
 		sessionScope: scope,
+		"scopes":     scopeDescriptions,
 		"user":       acct.Username,
 	})
 }
@@ -170,6 +194,10 @@ func (m *Module) AuthorizePOSTHandler(c *gin.Context) {
 		errs = append(errs, "session missing scope")
 	}
 
+	// code challenge is optional, so don't error if it's not set
+	codeChallenge, _ := s.Get(sessionCodeChallenge).(string)
+	codeChallengeMethod, _ := s.Get(sessionCodeChallengeMethod).(string)
+
 	userID, ok := s.Get(sessionUserID).(string)
 	if !ok {
 		errs = append(errs, "session missing userid")
@@ -208,6 +236,10 @@ func (m *Module) AuthorizePOSTHandler(c *gin.Context) {
 	values.Set(sessionRedirectURI, redirectURI)
 	values.Set(sessionScope, scope)
 	values.Set(sessionUserID, userID)
+	if codeChallenge != "" {
+		values.Set(sessionCodeChallenge, codeChallenge)
+		values.Set(sessionCodeChallengeMethod, codeChallengeMethod)
+	}
 	c.Request.Form = values
 	l.Tracef("values on request set to %+v", c.Request.Form)
 
@@ -227,7 +259,22 @@ func extractAuthForm(s sessions.Session, form *model.OAuthAuthorize) error {
 
 	// set default scope to read
 	if form.Scope == "" {
-		form.Scope = "read"
+		form.Scope = string(oauth.DefaultScope)
+	}
+
+	// make sure we know about all the requested scopes
+	if _, err := oauth.ParseScopes(form.Scope); err != nil {
+		return err
+	}
+
+	// if a code challenge is given, make sure we support the method
+	if form.CodeChallenge != "" {
+		if form.CodeChallengeMethod == "" {
+			return errors.New("code_challenge_method must be set when giving a code_challenge, use S256 or plain")
+		}
+		if form.CodeChallengeMethod != "S256" && form.CodeChallengeMethod != "plain" {
+			return fmt.Errorf("code_challenge_method %s not supported, use S256 or plain", form.CodeChallengeMethod)
+		}
 	}
 
 	// save these values from the form so we can use them elsewhere in the session
@@ -237,6 +284,8 @@ func extractAuthForm(s sessions.Session, form *model.OAuthAuthorize) error {
 	s.Set(sessionRedirectURI, form.RedirectURI)
 	s.Set(sessionScope, form.Scope)
 	s.Set(sessionState, uuid.NewString())
+	s.Set(sessionCodeChallenge, form.CodeChallenge)
+	s.Set(sessionCodeChallengeMethod, form.CodeChallengeMethod)
 	return s.Save()
 }
 
@@ -30,6 +30,7 @@ import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oidc"
@@ -138,6 +139,11 @@ func (m *Module) parseUserFromClaims(ctx context.Context, claims *oidc.Claims, i
 	// check if the email address is available for use; if it's not there's nothing we can so
 	emailAvailable, err := m.db.IsEmailAvailable(ctx, claims.Email)
 	if err != nil {
+		if _, blocked := err.(*db.ErrEmailDomainBlocked); blocked {
+			if err := m.db.CountEmailDomainBlockAttempt(ctx, claims.Email); err != nil {
+				logrus.Errorf("error counting email domain block attempt: %s", err)
+			}
+		}
 		return nil, fmt.Errorf("email %s not available: %s", claims.Email, err)
 	}
 	if !emailAvailable {
@@ -0,0 +1,90 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+)
+
+type revokeBody struct {
+	ClientID      *string `form:"client_id" json:"client_id" xml:"client_id"`
+	ClientSecret  *string `form:"client_secret" json:"client_secret" xml:"client_secret"`
+	Token         *string `form:"token" json:"token" xml:"token"`
+	TokenTypeHint *string `form:"token_type_hint" json:"token_type_hint" xml:"token_type_hint"`
+}
+
+// RevokePOSTHandler should be served as a POST at https://example.org/oauth/revoke
+// It revokes an access token of the requesting client, as described in RFC 7009, so that it can't be used anymore:
+// this is what clients should call when a user logs out.
+//
+// The client authenticates with its client_id and client_secret, either in the body of the request or with HTTP basic auth.
+// Revoking a token that doesn't exist (anymore) is not an error, so that clients can safely retry.
+func (m *Module) RevokePOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "RevokePOSTHandler")
+	l.Trace("entered RevokePOSTHandler")
+	ctx := c.Request.Context()
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &revokeBody{}
+	if err := c.ShouldBind(form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	if form.Token == nil || *form.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": "token must be provided"})
+		return
+	}
+
+	// we only hand out access tokens, so anything else can't be one of ours
+	if form.TokenTypeHint != nil && *form.TokenTypeHint != "" && *form.TokenTypeHint != "access_token" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_token_type", "error_description": "only access tokens can be revoked"})
+		return
+	}
+
+	var clientID, clientSecret string
+	if form.ClientID != nil && form.ClientSecret != nil {
+		clientID, clientSecret = *form.ClientID, *form.ClientSecret
+	} else if id, secret, ok := c.Request.BasicAuth(); ok {
+		clientID, clientSecret = id, secret
+	}
+
+	if errWithCode := m.processor.AppRevokeToken(ctx, clientID, clientSecret, *form.Token); errWithCode != nil {
+		switch errWithCode.Code() {
+		case http.StatusUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client", "error_description": "client authentication failed"})
+		case http.StatusForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": "unauthorized_client", "error_description": "you are not authorized to revoke this token"})
+		default:
+			l.Errorf("error revoking token: %s", errWithCode.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
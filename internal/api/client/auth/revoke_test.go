@@ -0,0 +1,102 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/auth"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type AuthRevokeTestSuite struct {
+	AuthStandardTestSuite
+}
+
+func (suite *AuthRevokeTestSuite) revoke(form url.Values) *httptest.ResponseRecorder {
+	ctx, recorder := suite.newContext(http.MethodPost, auth.OauthRevokePath)
+	ctx.Request = httptest.NewRequest(http.MethodPost, auth.OauthRevokePath, strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx.Request.Header.Set("accept", "application/json")
+
+	suite.authModule.RevokePOSTHandler(ctx)
+	return recorder
+}
+
+func (suite *AuthRevokeTestSuite) TestRevoke() {
+	token := suite.testTokens["local_account_1"]
+	client := suite.testClients["local_account_1"]
+	form := url.Values{
+		"client_id":     {client.ID},
+		"client_secret": {client.Secret},
+		"token":         {token.Access},
+	}
+
+	recorder := suite.revoke(form)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("{}", recorder.Body.String())
+
+	// the token is gone, so it can't be used anymore
+	err := suite.db.GetByID(context.Background(), token.ID, &gtsmodel.Token{})
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	// revoking it again is fine
+	recorder = suite.revoke(form)
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *AuthRevokeTestSuite) TestRevokeWrongSecret() {
+	token := suite.testTokens["local_account_1"]
+	recorder := suite.revoke(url.Values{
+		"client_id":     {suite.testClients["local_account_1"].ID},
+		"client_secret": {suite.testClients["local_account_2"].Secret},
+		"token":         {token.Access},
+	})
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+	suite.Contains(recorder.Body.String(), "invalid_client")
+
+	err := suite.db.GetByID(context.Background(), token.ID, &gtsmodel.Token{})
+	suite.NoError(err)
+}
+
+func (suite *AuthRevokeTestSuite) TestRevokeOtherClientsToken() {
+	token := suite.testTokens["local_account_1"]
+	client := suite.testClients["local_account_2"]
+	recorder := suite.revoke(url.Values{
+		"client_id":     {client.ID},
+		"client_secret": {client.Secret},
+		"token":         {token.Access},
+	})
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	suite.Contains(recorder.Body.String(), "unauthorized_client")
+
+	err := suite.db.GetByID(context.Background(), token.ID, &gtsmodel.Token{})
+	suite.NoError(err)
+}
+
+func TestAuthRevokeTestSuite(t *testing.T) {
+	suite.Run(t, &AuthRevokeTestSuite{})
+}
@@ -32,6 +32,7 @@ type tokenBody struct {
 	ClientID     *string `form:"client_id" json:"client_id" xml:"client_id"`
 	ClientSecret *string `form:"client_secret" json:"client_secret" xml:"client_secret"`
 	Code         *string `form:"code" json:"code" xml:"code"`
+	CodeVerifier *string `form:"code_verifier" json:"code_verifier" xml:"code_verifier"`
 	GrantType    *string `form:"grant_type" json:"grant_type" xml:"grant_type"`
 	RedirectURI  *string `form:"redirect_uri" json:"redirect_uri" xml:"redirect_uri"`
 	Scope        *string `form:"scope" json:"scope" xml:"scope"`
@@ -60,6 +61,9 @@ func (m *Module) TokenPOSTHandler(c *gin.Context) {
 		if form.Code != nil {
 			c.Request.Form.Set("code", *form.Code)
 		}
+		if form.CodeVerifier != nil {
+			c.Request.Form.Set("code_verifier", *form.CodeVerifier)
+		}
 		if form.GrantType != nil {
 			c.Request.Form.Set("grant_type", *form.GrantType)
 		}
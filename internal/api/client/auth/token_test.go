@@ -0,0 +1,167 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package auth_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type AuthTokenTestSuite struct {
+	AuthStandardTestSuite
+}
+
+const pkceVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func (suite *AuthTokenTestSuite) token(form url.Values) *httptest.ResponseRecorder {
+	ctx, recorder := suite.newContext(http.MethodPost, "/oauth/token")
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx.Request.Header.Set("accept", "application/json")
+
+	suite.authModule.TokenPOSTHandler(ctx)
+	return recorder
+}
+
+// putPKCECode stores an authorization code for local_account_1 which was issued with a code challenge of the given method.
+func (suite *AuthTokenTestSuite) putPKCECode(code string, method string) {
+	client := suite.testClients["local_account_1"]
+	challenge := pkceVerifier
+	if method == "S256" {
+		sum := sha256.Sum256([]byte(pkceVerifier))
+		challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	err := suite.db.Put(context.Background(), &gtsmodel.Token{
+		ID:                  "01FHJ9Z4A09R5S3KB3ZV2BBNDD",
+		ClientID:            client.ID,
+		UserID:              suite.testUsers["local_account_1"].ID,
+		RedirectURI:         "http://localhost:8080",
+		Scope:               "read",
+		Code:                code,
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: method,
+		CodeCreateAt:        time.Now(),
+		CodeExpiresAt:       time.Now().Add(10 * time.Minute),
+	})
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *AuthTokenTestSuite) TestPKCEWithoutSecret() {
+	suite.putPKCECode("ZJYWNDGXYTITMDBKMS0ZMJY1LWIXMDETMZLMMDE0OWIYMJU1", "S256")
+
+	recorder := suite.token(url.Values{
+		"client_id":     {suite.testClients["local_account_1"].ID},
+		"grant_type":    {"authorization_code"},
+		"code":          {"ZJYWNDGXYTITMDBKMS0ZMJY1LWIXMDETMZLMMDE0OWIYMJU1"},
+		"redirect_uri":  {"http://localhost:8080"},
+		"code_verifier": {pkceVerifier},
+	})
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	token := map[string]interface{}{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &token); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NotEmpty(token["access_token"])
+	suite.Equal("Bearer", token["token_type"])
+}
+
+func (suite *AuthTokenTestSuite) TestPKCEWrongVerifier() {
+	suite.putPKCECode("OWM3ZJQ4NZMTYZDHZC0ZNJFILTKXOGUTNJQ2YJDJNZU4ZWM1", "S256")
+
+	recorder := suite.token(url.Values{
+		"client_id":     {suite.testClients["local_account_1"].ID},
+		"grant_type":    {"authorization_code"},
+		"code":          {"OWM3ZJQ4NZMTYZDHZC0ZNJFILTKXOGUTNJQ2YJDJNZU4ZWM1"},
+		"redirect_uri":  {"http://localhost:8080"},
+		"code_verifier": {"not-the-verifier-that-was-used-to-make-the-challenge"},
+	})
+	suite.NotEqual(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "access_token")
+}
+
+func (suite *AuthTokenTestSuite) TestPKCEPlainWithoutSecret() {
+	suite.putPKCECode("NTHMMZQ5NDITMJVLMS0ZZDA1LWJKOTGTNDC2NWQ5NTQ3ZDUX", "plain")
+
+	// a plain challenge was sent in the clear, so it doesn't stand in for the client secret
+	recorder := suite.token(url.Values{
+		"client_id":     {suite.testClients["local_account_1"].ID},
+		"grant_type":    {"authorization_code"},
+		"code":          {"NTHMMZQ5NDITMJVLMS0ZZDA1LWJKOTGTNDC2NWQ5NTQ3ZDUX"},
+		"redirect_uri":  {"http://localhost:8080"},
+		"code_verifier": {pkceVerifier},
+	})
+	suite.NotEqual(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "access_token")
+}
+
+func (suite *AuthTokenTestSuite) clientCredentials(scope string) *httptest.ResponseRecorder {
+	client := suite.testClients["local_account_1"]
+	return suite.token(url.Values{
+		"client_id":     {client.ID},
+		"client_secret": {client.Secret},
+		"grant_type":    {"client_credentials"},
+		"redirect_uri":  {"http://localhost:8080"},
+		"scope":         {scope},
+	})
+}
+
+func (suite *AuthTokenTestSuite) TestClientCredentialsRegisteredScope() {
+	// read:statuses falls under the read scope that the application registered with
+	recorder := suite.clientCredentials("read:statuses")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	token := map[string]interface{}{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &token); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NotEmpty(token["access_token"])
+	suite.Equal("read:statuses", token["scope"])
+}
+
+func (suite *AuthTokenTestSuite) TestClientCredentialsUnregisteredScope() {
+	// the application didn't register with any admin scopes
+	recorder := suite.clientCredentials("read admin")
+	suite.NotEqual(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "access_token")
+}
+
+func (suite *AuthTokenTestSuite) TestClientCredentialsInvalidScope() {
+	recorder := suite.clientCredentials("read:everything")
+	suite.NotEqual(http.StatusOK, recorder.Code)
+	suite.NotContains(recorder.Body.String(), "access_token")
+}
+
+func TestAuthTokenTestSuite(t *testing.T) {
+	suite.Run(t, &AuthTokenTestSuite{})
+}
@@ -0,0 +1,155 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package directory
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+)
+
+// DirectoryGETHandler swagger:operation GET /api/v1/directory directoryGet
+//
+// Get accounts that have chosen to be listed in the profile directory.
+//
+// Remote accounts are included too, unless local is set.
+//
+// ---
+// tags:
+// - directory
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: limit
+//   type: integer
+//   description: Number of accounts to return.
+//   default: 40
+//   maximum: 80
+//   in: query
+//   required: false
+// - name: offset
+//   type: integer
+//   description: Skip this many accounts, for paging.
+//   default: 0
+//   in: query
+//   required: false
+// - name: order
+//   type: string
+//   description: |-
+//     Use `active` to sort by most recently posted first, or `new` to sort by most recently created first.
+//   default: active
+//   in: query
+//   required: false
+// - name: local
+//   type: boolean
+//   description: Only return accounts on this instance.
+//   default: false
+//   in: query
+//   required: false
+//
+// responses:
+//   '200':
+//     description: Array of accounts.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/account"
+//   '400':
+//      description: bad request
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) DirectoryGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "DirectoryGETHandler")
+
+	authed, err := oauth.Authed(c, false, false, false, false)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 40
+	if limitString := c.Query(LimitKey); limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+	if limit > 80 {
+		limit = 80
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	offset := 0
+	if offsetString := c.Query(OffsetKey); offsetString != "" {
+		i, err := strconv.ParseInt(offsetString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing offset string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse offset query param"})
+			return
+		}
+		if i > 0 {
+			offset = int(i)
+		}
+	}
+
+	order := processing.DirectoryOrderActive
+	if orderString := c.Query(OrderKey); orderString != "" {
+		order = orderString
+	}
+
+	local := false
+	if localString := c.Query(LocalKey); localString != "" {
+		i, err := strconv.ParseBool(localString)
+		if err != nil {
+			l.Debugf("error parsing local string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse local query param"})
+			return
+		}
+		local = i
+	}
+
+	accounts, errWithCode := m.processor.DirectoryGet(c.Request.Context(), authed, order, local, limit, offset)
+	if errWithCode != nil {
+		l.Debugf("error from processor DirectoryGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
@@ -0,0 +1,123 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package exports
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ExportPOSTHandler swagger:operation POST /api/v1/exports exportCreate
+//
+// Export some of the requesting account's data as a csv file, or the whole account as an archive.
+//
+// The file is generated in the background, so the returned export will usually still be pending.
+// Poll the export until its state is done, and then download the file from its url.
+// Csv files use the same format as Mastodon. Archives are zip files containing actor.json, outbox.json,
+// and the account's media files, with the actor and outbox as activitypub json.
+// Files are deleted a week after they're requested.
+//
+// If an export of the same type is already pending, that export is returned instead of starting a new one.
+//
+// ---
+// tags:
+// - exports
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: type
+//   in: formData
+//   description: The data to export.
+//   type: string
+//   enum:
+//   - follows
+//   - followers
+//   - blocks
+//   - mutes
+//   - bookmarks
+//   - archive
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:accounts
+//
+// responses:
+//   '200':
+//     description: The requested export.
+//     schema:
+//       "$ref": "#/definitions/export"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: unprocessable entity
+//   '500':
+//      description: internal error
+func (m *Module) ExportPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "ExportPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.ExportCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if form.Type == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no export type provided"})
+		return
+	}
+
+	export, errWithCode := m.processor.ExportCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor ExportCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
@@ -0,0 +1,115 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package exports_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/exports"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type ExportCreateTestSuite struct {
+	ExportsStandardTestSuite
+}
+
+// createExport posts an export of the given type, and returns the status code and body of the response.
+func (suite *ExportCreateTestSuite) createExport(exportType string) (int, []byte) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	suite.NoError(w.WriteField("type", exportType))
+	suite.NoError(w.Close())
+
+	recorder := suite.request(suite.exportsModule.ExportPOSTHandler, http.MethodPost, exports.BasePath, "", body.Bytes(), w.FormDataContentType())
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+// getExport gets the export with the given id.
+func (suite *ExportCreateTestSuite) getExport(id string) *apimodel.Export {
+	recorder := suite.request(suite.exportsModule.ExportGETHandler, http.MethodGet, exports.BasePathWithID, id, nil, "")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	export := &apimodel.Export{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), export))
+	return export
+}
+
+func (suite *ExportCreateTestSuite) TestExportFollows() {
+	code, b := suite.createExport("follows")
+	suite.Equal(http.StatusOK, code)
+
+	export := &apimodel.Export{}
+	suite.NoError(json.Unmarshal(b, export))
+	suite.Equal("follows", export.Type)
+
+	for i := 0; export.State == "pending" && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		export = suite.getExport(export.ID)
+	}
+	suite.Equal("done", export.State)
+	suite.NotEmpty(export.URL)
+
+	recorder := suite.request(suite.exportsModule.ExportDownloadGETHandler, http.MethodGet, exports.DownloadPath, export.ID, nil, "")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("text/csv", recorder.Header().Get("Content-Type"))
+	suite.Equal(`attachment; filename="following_accounts.csv"`, recorder.Header().Get("Content-Disposition"))
+	suite.Contains(recorder.Body.String(), "Account address,Show boosts,Notify on new posts,Languages\n")
+
+	// the export is listed along with any others
+	recorder = suite.request(suite.exportsModule.ExportsGETHandler, http.MethodGet, exports.BasePath, "", nil, "")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	apiExports := []*apimodel.Export{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &apiExports))
+	suite.Len(apiExports, 1)
+	suite.Equal(export.ID, apiExports[0].ID)
+}
+
+func (suite *ExportCreateTestSuite) TestExportUnknownType() {
+	code, b := suite.createExport("statuses")
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"bad request: export type statuses not recognized"}`, string(b))
+}
+
+func (suite *ExportCreateTestSuite) TestExportLists() {
+	code, b := suite.createExport("lists")
+	suite.Equal(http.StatusUnprocessableEntity, code)
+	suite.Equal(`{"error":"unprocessable entity: export type lists not supported"}`, string(b))
+}
+
+func (suite *ExportCreateTestSuite) TestExportNoType() {
+	code, b := suite.createExport("")
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"no export type provided"}`, string(b))
+}
+
+func (suite *ExportCreateTestSuite) TestExportNotFound() {
+	recorder := suite.request(suite.exportsModule.ExportDownloadGETHandler, http.MethodGet, exports.DownloadPath, "01G2B2PS4B7DAGQ2YGMKC2H5WB", nil, "")
+	suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func TestExportCreateTestSuite(t *testing.T) {
+	suite.Run(t, &ExportCreateTestSuite{})
+}
@@ -0,0 +1,112 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package exports
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ExportDownloadGETHandler swagger:operation GET /api/v1/exports/{id}/download exportDownload
+//
+// Download the file of one export of the requesting account.
+//
+// If the file is still being generated, 202 is returned with a Retry-After header, and the caller should try again later.
+//
+// ---
+// tags:
+// - exports
+//
+// produces:
+// - text/csv
+// - application/zip
+//
+// parameters:
+// - name: id
+//   type: string
+//   description: ID of the export.
+//   in: path
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:accounts
+//
+// responses:
+//   '200':
+//     description: The csv file or zip archive of the export.
+//     schema:
+//       type: file
+//   '202':
+//      description: the file is still being generated
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '404':
+//      description: not found, or the file couldn't be generated
+//   '500':
+//      description: internal error
+func (m *Module) ExportDownloadGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "ExportDownloadGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id := c.Param(IDKey)
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no export id specified"})
+		return
+	}
+
+	content, errWithCode := m.processor.ExportFileGet(c.Request.Context(), authed, id)
+	if errWithCode != nil {
+		l.Debugf("error from processor ExportFileGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	if content.Pending {
+		c.Header("Retry-After", strconv.Itoa(pendingRetryAfter))
+		c.String(http.StatusAccepted, "202 export is being generated, try again later")
+		return
+	}
+
+	defer func() {
+		if closer, ok := content.Content.(io.ReadCloser); ok {
+			if err := closer.Close(); err != nil {
+				l.Errorf("error closing readcloser: %s", err)
+			}
+		}
+	}()
+
+	c.DataFromReader(http.StatusOK, content.ContentLength, content.ContentType, content.Content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", content.Filename),
+	})
+}
@@ -0,0 +1,63 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package exports
+
+import (
+	"net/http"
+
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+const (
+	// IDKey is the url parameter for the ID of an export
+	IDKey = "id"
+	// BasePath is the base path for serving the exports API
+	BasePath = "/api/v1/exports"
+	// BasePathWithID is the base path with the ID of an export, for interacting with a single export
+	BasePathWithID = BasePath + "/:" + IDKey
+	// DownloadPath is used for downloading the file of an export
+	DownloadPath = BasePathWithID + "/download"
+
+	// pendingRetryAfter is how many seconds the caller is asked to wait before
+	// trying to download an export again, if its file is still being generated.
+	pendingRetryAfter = 5
+)
+
+// Module implements the ClientAPIModule interface for everything relating to exporting account data
+type Module struct {
+	processor processing.Processor
+}
+
+// New returns a new exports module
+func New(processor processing.Processor) api.ClientModule {
+	return &Module{
+		processor: processor,
+	}
+}
+
+// Route attaches all routes from this module to the given router
+func (m *Module) Route(r router.Router) error {
+	r.AttachHandler(http.MethodPost, BasePath, m.ExportPOSTHandler)
+	r.AttachHandler(http.MethodGet, BasePath, m.ExportsGETHandler)
+	r.AttachHandler(http.MethodGet, BasePathWithID, m.ExportGETHandler)
+	r.AttachHandler(http.MethodGet, DownloadPath, m.ExportDownloadGETHandler)
+	return nil
+}
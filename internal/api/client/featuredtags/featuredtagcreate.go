@@ -0,0 +1,110 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package featuredtags
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FeaturedTagPOSTHandler swagger:operation POST /api/v1/featured_tags featuredTagCreate
+//
+// Feature a hashtag on the requesting account's profile.
+//
+// At most 10 hashtags can be featured at once.
+//
+// ---
+// tags:
+// - featured_tags
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: name
+//   in: formData
+//   description: The hashtag to feature, with or without the leading #.
+//   type: string
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:accounts
+//
+// responses:
+//   '200':
+//     description: The newly featured tag.
+//     schema:
+//       "$ref": "#/definitions/featuredTag"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: invalid or already featured hashtag, or too many featured hashtags
+//   '500':
+//      description: internal error
+func (m *Module) FeaturedTagPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "FeaturedTagPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.FeaturedTagCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if form.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag name provided"})
+		return
+	}
+
+	featuredTag, errWithCode := m.processor.FeaturedTagCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor FeaturedTagCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, featuredTag)
+}
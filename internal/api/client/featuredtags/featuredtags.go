@@ -0,0 +1,56 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package featuredtags
+
+import (
+	"net/http"
+
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+const (
+	// IDKey is the url parameter for the ID of a featured tag
+	IDKey = "id"
+	// BasePath is the base path for serving the featured tags API
+	BasePath = "/api/v1/featured_tags"
+	// BasePathWithID is the base path with the ID of a featured tag, for interacting with a single featured tag
+	BasePathWithID = BasePath + "/:" + IDKey
+)
+
+// Module implements the ClientAPIModule interface for everything relating to featured tags
+type Module struct {
+	processor processing.Processor
+}
+
+// New returns a new featured tags module
+func New(processor processing.Processor) api.ClientModule {
+	return &Module{
+		processor: processor,
+	}
+}
+
+// Route attaches all routes from this module to the given router
+func (m *Module) Route(r router.Router) error {
+	r.AttachHandler(http.MethodGet, BasePath, m.FeaturedTagsGETHandler)
+	r.AttachHandler(http.MethodPost, BasePath, m.FeaturedTagPOSTHandler)
+	r.AttachHandler(http.MethodDelete, BasePathWithID, m.FeaturedTagDELETEHandler)
+	return nil
+}
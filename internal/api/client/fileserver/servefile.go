@@ -21,6 +21,8 @@ package fileserver
 import (
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -29,6 +31,18 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
+// pendingRetryAfter is the number of seconds that callers should wait before
+// trying again, when the content they've requested is still being fetched.
+const pendingRetryAfter = 5
+
+// svgContentSecurityPolicy is served along with svgs, so that any scripts or links to other resources
+// that made it through sanitizing won't do anything if someone opens an svg in their browser.
+const svgContentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+
+// downloadContentSecurityPolicy is served along with content of types that we couldn't process,
+// so that nothing in it can run even if a browser ignores the request to download it.
+const downloadContentSecurityPolicy = "default-src 'none'; sandbox"
+
 // ServeFile is for serving attachments, headers, and avatars to the requester from instance storage.
 //
 // Note: to mitigate scraping attempts, no information should be given out on a bad request except "404 page not found".
@@ -91,6 +105,20 @@ func (m *FileServer) ServeFile(c *gin.Context) {
 		return
 	}
 
+	if content.Pending {
+		// the content is still being fetched from a remote instance, so ask the caller to come back in a bit
+		c.Header("Retry-After", strconv.Itoa(pendingRetryAfter))
+		c.String(http.StatusAccepted, "202 media is being fetched, try again later")
+		return
+	}
+
+	if content.URL != nil {
+		// the content can be fetched directly from elsewhere (eg., s3), so redirect there; content
+		// that needs the security headers below is never given a url, since they'd be lost on redirect
+		c.Redirect(http.StatusFound, content.URL.String())
+		return
+	}
+
 	defer func() {
 		// if the content is a ReadCloser, close it when we're done
 		if closer, ok := content.Content.(io.ReadCloser); ok {
@@ -109,5 +137,29 @@ func (m *FileServer) ServeFile(c *gin.Context) {
 		return
 	}
 
-	c.DataFromReader(http.StatusOK, content.ContentLength, format, content.Content, nil)
+	var extraHeaders map[string]string
+	switch {
+	case content.Download:
+		// we don't know what this content is, so make sure browsers download it rather than trying to render it
+		extraHeaders = map[string]string{
+			"Content-Disposition":     "attachment",
+			"Content-Security-Policy": downloadContentSecurityPolicy,
+			"X-Content-Type-Options":  "nosniff",
+		}
+	case content.ContentType == "image/svg+xml":
+		extraHeaders = map[string]string{"Content-Security-Policy": svgContentSecurityPolicy}
+	}
+
+	// if the content can be seeked through, let the standard library handle
+	// range requests for us, so that callers can fetch just the parts they need
+	if seeker, ok := content.Content.(io.ReadSeeker); ok {
+		c.Header("Content-Type", format)
+		for k, v := range extraHeaders {
+			c.Header(k, v)
+		}
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, seeker)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, content.ContentLength, format, content.Content, extraHeaders)
 }
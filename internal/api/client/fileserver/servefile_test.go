@@ -26,7 +26,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
@@ -39,6 +38,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 	"github.com/superseriousbusiness/gotosocial/testrig"
@@ -48,7 +48,7 @@ type ServeFileTestSuite struct {
 	// standard suite interfaces
 	suite.Suite
 	db           db.DB
-	storage      *kv.KVStore
+	storage      *gtsstorage.Driver
 	federator    federation.Federator
 	tc           typeutils.TypeConverter
 	processor    processing.Processor
@@ -212,6 +212,121 @@ func (suite *ServeFileTestSuite) TestServeSmallFileSuccessful() {
 	suite.Equal(b, fileInStorage)
 }
 
+func (suite *ServeFileTestSuite) TestServeVideoRange() {
+	targetAttachment, ok := suite.testAttachments["admin_account_status_1_attachment_1"]
+	suite.True(ok)
+	suite.NotNil(targetAttachment)
+
+	// pretend the attachment is a video
+	video := []byte("pretend this is a video")
+	targetAttachment.Type = gtsmodel.FileTypeGifv
+	targetAttachment.File.Path = fmt.Sprintf("%s/attachment/original/%s.mp4", targetAttachment.AccountID, targetAttachment.ID)
+	targetAttachment.File.ContentType = "video/mp4"
+	targetAttachment.File.FileSize = len(video)
+	suite.NoError(suite.storage.Put(targetAttachment.File.Path, video))
+	suite.NoError(suite.db.UpdateByPrimaryKey(context.Background(), targetAttachment))
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, targetAttachment.URL, nil)
+	ctx.Request.Header.Set("accept", "*/*")
+	ctx.Request.Header.Set("range", "bytes=8-11")
+
+	// normally the router would populate these params from the path values,
+	// but because we're calling the ServeFile function directly, we need to set them manually.
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   fileserver.AccountIDKey,
+			Value: targetAttachment.AccountID,
+		},
+		gin.Param{
+			Key:   fileserver.MediaTypeKey,
+			Value: string(media.TypeAttachment),
+		},
+		gin.Param{
+			Key:   fileserver.MediaSizeKey,
+			Value: string(media.SizeOriginal),
+		},
+		gin.Param{
+			Key:   fileserver.FileNameKey,
+			Value: fmt.Sprintf("%s.mp4", targetAttachment.ID),
+		},
+	}
+
+	// only the requested part of the video should be served
+	suite.fileServer.ServeFile(ctx)
+	suite.EqualValues(http.StatusPartialContent, recorder.Code)
+	suite.EqualValues("video/mp4", recorder.Header().Get("content-type"))
+	suite.EqualValues("bytes", recorder.Header().Get("accept-ranges"))
+	suite.EqualValues(fmt.Sprintf("bytes 8-11/%d", len(video)), recorder.Header().Get("content-range"))
+
+	b, err := ioutil.ReadAll(recorder.Body)
+	suite.NoError(err)
+	suite.Equal("this", string(b))
+}
+
+func (suite *ServeFileTestSuite) TestServeUnknownTypeAsDownload() {
+	targetAttachment, ok := suite.testAttachments["admin_account_status_1_attachment_1"]
+	suite.True(ok)
+	suite.NotNil(targetAttachment)
+
+	// pretend the attachment is something we couldn't process
+	document := []byte("%PDF-1.4\n<html><script>alert('hi')</script></html>")
+	targetAttachment.Type = gtsmodel.FileTypeUnknown
+	targetAttachment.File.Path = fmt.Sprintf("%s/attachment/original/%s.pdf", targetAttachment.AccountID, targetAttachment.ID)
+	targetAttachment.File.ContentType = "application/pdf"
+	targetAttachment.File.FileSize = len(document)
+	suite.NoError(suite.storage.Put(targetAttachment.File.Path, document))
+	suite.NoError(suite.db.UpdateByPrimaryKey(context.Background(), targetAttachment))
+
+	for _, mediaSize := range []media.Size{media.SizeOriginal, media.SizeSmall} {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest(http.MethodGet, targetAttachment.URL, nil)
+		ctx.Request.Header.Set("accept", "*/*")
+
+		// normally the router would populate these params from the path values,
+		// but because we're calling the ServeFile function directly, we need to set them manually.
+		ctx.Params = gin.Params{
+			gin.Param{
+				Key:   fileserver.AccountIDKey,
+				Value: targetAttachment.AccountID,
+			},
+			gin.Param{
+				Key:   fileserver.MediaTypeKey,
+				Value: string(media.TypeAttachment),
+			},
+			gin.Param{
+				Key:   fileserver.MediaSizeKey,
+				Value: string(mediaSize),
+			},
+			gin.Param{
+				Key:   fileserver.FileNameKey,
+				Value: fmt.Sprintf("%s.pdf", targetAttachment.ID),
+			},
+		}
+
+		suite.fileServer.ServeFile(ctx)
+
+		if mediaSize == media.SizeSmall {
+			// there's no thumbnail for media of unknown types
+			suite.EqualValues(http.StatusNotFound, recorder.Code)
+			continue
+		}
+
+		// the original should be served as a download that browsers won't try to render
+		suite.EqualValues(http.StatusOK, recorder.Code)
+		suite.EqualValues("application/pdf", recorder.Header().Get("content-type"))
+		suite.EqualValues("attachment", recorder.Header().Get("content-disposition"))
+		suite.EqualValues("nosniff", recorder.Header().Get("x-content-type-options"))
+		suite.EqualValues("default-src 'none'; sandbox", recorder.Header().Get("content-security-policy"))
+
+		b, err := ioutil.ReadAll(recorder.Body)
+		suite.NoError(err)
+		suite.Equal(document, b)
+	}
+}
+
 func TestServeFileTestSuite(t *testing.T) {
 	suite.Run(t, new(ServeFileTestSuite))
 }
@@ -21,14 +21,34 @@ package filter
 import (
 	"net/http"
 
+	"github.com/gin-gonic/gin"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 )
 
 const (
+	// IDKey is the url parameter for the ID of a filter
+	IDKey = "id"
 	// BasePath is the base path for serving the filter API
 	BasePath = "/api/v1/filters"
+	// BasePathWithID is the base path with the ID of a filter, for interacting with a single filter
+	BasePathWithID = BasePath + "/:" + IDKey
+
+	// SubKey is the url parameter after the ID of a filter: either the keywords or statuses of that filter,
+	// or, if the ID is itself keywords or statuses, the ID of a single keyword or filter status
+	SubKey = "sub"
+	// KeywordsSegment is the path segment for the keywords of filters
+	KeywordsSegment = "keywords"
+	// StatusesSegment is the path segment for the statuses of filters
+	StatusesSegment = "statuses"
+	// V2BasePath is the base path for serving the v2 filter API
+	V2BasePath = "/api/v2/filters"
+	// V2BasePathWithID is the v2 base path with the ID of a filter, for interacting with a single filter
+	V2BasePathWithID = V2BasePath + "/:" + IDKey
+	// V2BasePathWithSub is the v2 base path with the ID of a filter followed by keywords or statuses, or with
+	// keywords or statuses followed by the ID of a single keyword or filter status
+	V2BasePathWithSub = V2BasePathWithID + "/:" + SubKey
 )
 
 // Module implements the ClientAPIModule interface for every related to filters
@@ -46,5 +66,70 @@ func New(processor processing.Processor) api.ClientModule {
 // Route attaches all routes from this module to the given router
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, BasePath, m.FiltersGETHandler)
+	r.AttachHandler(http.MethodPost, BasePath, m.FilterPOSTHandler)
+	r.AttachHandler(http.MethodGet, BasePathWithID, m.FilterGETHandler)
+	r.AttachHandler(http.MethodPut, BasePathWithID, m.FilterPUTHandler)
+	r.AttachHandler(http.MethodDelete, BasePathWithID, m.FilterDELETEHandler)
+
+	r.AttachHandler(http.MethodGet, V2BasePath, m.FiltersV2GETHandler)
+	r.AttachHandler(http.MethodPost, V2BasePath, m.FilterV2POSTHandler)
+	r.AttachHandler(http.MethodGet, V2BasePathWithID, m.FilterV2GETHandler)
+	r.AttachHandler(http.MethodPut, V2BasePathWithID, m.FilterV2PUTHandler)
+	r.AttachHandler(http.MethodDelete, V2BasePathWithID, m.FilterV2DELETEHandler)
+	r.AttachHandler(http.MethodGet, V2BasePathWithSub, m.muxHandler)
+	r.AttachHandler(http.MethodPost, V2BasePathWithSub, m.muxHandler)
+	r.AttachHandler(http.MethodPut, V2BasePathWithSub, m.muxHandler)
+	r.AttachHandler(http.MethodDelete, V2BasePathWithSub, m.muxHandler)
 	return nil
 }
+
+// muxHandler is a little workaround to overcome the limitations of Gin, which won't let
+// /api/v2/filters/keywords/:id share a router with /api/v2/filters/:id/keywords
+func (m *Module) muxHandler(c *gin.Context) {
+	id := c.Param(IDKey)
+	sub := c.Param(SubKey)
+
+	switch {
+	case id == KeywordsSegment:
+		switch c.Request.Method {
+		case http.MethodGet:
+			m.FilterKeywordGETHandler(c)
+			return
+		case http.MethodPut:
+			m.FilterKeywordPUTHandler(c)
+			return
+		case http.MethodDelete:
+			m.FilterKeywordDELETEHandler(c)
+			return
+		}
+	case id == StatusesSegment:
+		switch c.Request.Method {
+		case http.MethodGet:
+			m.FilterStatusGETHandler(c)
+			return
+		case http.MethodDelete:
+			m.FilterStatusDELETEHandler(c)
+			return
+		}
+	case sub == KeywordsSegment:
+		switch c.Request.Method {
+		case http.MethodGet:
+			m.FilterKeywordsGETHandler(c)
+			return
+		case http.MethodPost:
+			m.FilterKeywordPOSTHandler(c)
+			return
+		}
+	case sub == StatusesSegment:
+		switch c.Request.Method {
+		case http.MethodGet:
+			m.FilterStatusesGETHandler(c)
+			return
+		case http.MethodPost:
+			m.FilterStatusPOSTHandler(c)
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+}
@@ -0,0 +1,128 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package filter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FilterPOSTHandler swagger:operation POST /api/v1/filters filterCreate
+//
+// Create a keyword filter for the requesting account.
+//
+// Statuses containing the phrase, in their content, content warning, media descriptions, or poll options,
+// are removed from the given contexts before they're served to the account.
+//
+// ---
+// tags:
+// - filters
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: phrase
+//   in: formData
+//   description: The text to filter out.
+//   type: string
+//   required: true
+// - name: context[]
+//   in: formData
+//   description: |-
+//     Where the filter applies. At least one of:
+//     home: the home timeline;
+//     notifications: notifications;
+//     public: public timelines;
+//     thread: the parents and replies of a status;
+//     account: the statuses of an account.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   required: true
+// - name: whole_word
+//   in: formData
+//   description: Only match the phrase where it is not part of a longer word.
+//   type: boolean
+// - name: irreversible
+//   in: formData
+//   description: Ask clients not to offer to show filtered statuses. Filtered statuses are always removed by the server anyway.
+//   type: boolean
+// - name: expires_in
+//   in: formData
+//   description: Number of seconds from now that the filter should stop applying. Leave unset for a filter that doesn't expire.
+//   type: integer
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:filters
+//
+// responses:
+//   '200':
+//     description: The new filter.
+//     schema:
+//       "$ref": "#/definitions/filter"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) FilterPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "FilterPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.FilterCreateUpdateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, errWithCode := m.processor.FilterCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor FilterCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
@@ -0,0 +1,131 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package filter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FilterV2POSTHandler swagger:operation POST /api/v2/filters filterV2Create
+//
+// Create a filter for the requesting account.
+//
+// Statuses that contain any of the keywords of the filter, in their content, content warning, media descriptions, or poll options,
+// or that are one of the statuses of the filter, are either removed from the given contexts before they're served to the account,
+// or served with a filtered field saying which parts of the filter they matched, depending on the filter action.
+//
+// Keywords can only be given in JSON requests, as an array of objects with keyword and whole_word fields.
+//
+// ---
+// tags:
+// - filters
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: title
+//   in: formData
+//   description: A name for the filter.
+//   type: string
+//   required: true
+// - name: context[]
+//   in: formData
+//   description: |-
+//     Where the filter applies. At least one of:
+//     home: the home timeline;
+//     notifications: notifications;
+//     public: public timelines;
+//     thread: the parents and replies of a status;
+//     account: the statuses of an account.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   required: true
+// - name: filter_action
+//   in: formData
+//   description: |-
+//     What to do with statuses that match the filter. One of:
+//     warn: serve them with the results of the filter, so clients can hide them behind a warning;
+//     hide: don't serve them at all.
+//   type: string
+//   default: warn
+// - name: expires_in
+//   in: formData
+//   description: Number of seconds from now that the filter should stop applying. Leave unset for a filter that doesn't expire.
+//   type: integer
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:filters
+//
+// responses:
+//   '200':
+//     description: The new filter.
+//     schema:
+//       "$ref": "#/definitions/filterV2"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) FilterV2POSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "FilterV2POSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.FilterV2CreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, errWithCode := m.processor.FilterV2Create(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor FilterV2Create: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
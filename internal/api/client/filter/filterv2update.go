@@ -0,0 +1,139 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package filter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// FilterV2PUTHandler swagger:operation PUT /api/v2/filters/{id} filterV2Update
+//
+// Update a filter of the requesting account.
+//
+// Only the options that are set are changed. In JSON requests, keywords can be added, changed, or removed with
+// keywords_attributes, an array of objects with id, keyword, whole_word, and _destroy fields: objects without an id
+// add a new keyword, and objects with an id change that keyword, or remove it if _destroy is true.
+//
+// ---
+// tags:
+// - filters
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: id
+//   type: string
+//   description: ID of the filter.
+//   in: path
+//   required: true
+// - name: title
+//   in: formData
+//   description: A name for the filter.
+//   type: string
+// - name: context[]
+//   in: formData
+//   description: |-
+//     Where the filter applies. If set, at least one of:
+//     home: the home timeline;
+//     notifications: notifications;
+//     public: public timelines;
+//     thread: the parents and replies of a status;
+//     account: the statuses of an account.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+// - name: filter_action
+//   in: formData
+//   description: |-
+//     What to do with statuses that match the filter. One of:
+//     warn: serve them with the results of the filter, so clients can hide them behind a warning;
+//     hide: don't serve them at all.
+//   type: string
+// - name: expires_in
+//   in: formData
+//   description: Number of seconds from now that the filter should stop applying. Zero for a filter that doesn't expire.
+//   type: integer
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:filters
+//
+// responses:
+//   '200':
+//     description: The updated filter.
+//     schema:
+//       "$ref": "#/definitions/filterV2"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+func (m *Module) FilterV2PUTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "FilterV2PUTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	filterID := c.Param(IDKey)
+	if filterID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no filter id provided"})
+		return
+	}
+
+	form := &model.FilterV2UpdateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, errWithCode := m.processor.FilterV2Update(c.Request.Context(), authed, filterID, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor FilterV2Update: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"net/http/httptest"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
@@ -37,6 +36,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
@@ -44,7 +44,7 @@ import (
 type FollowRequestStandardTestSuite struct {
 	suite.Suite
 	db           db.DB
-	storage      *kv.KVStore
+	storage      *gtsstorage.Driver
 	mediaManager media.Manager
 	federator    federation.Federator
 	processor    processing.Processor
@@ -0,0 +1,133 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package imports
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+)
+
+// ImportPOSTHandler swagger:operation POST /api/v1/imports importCreate
+//
+// Upload a csv file of accounts to follow, block or mute, such as one exported from Mastodon.
+//
+// The file is checked straight away, but the accounts in it are followed, blocked or muted in the background,
+// one every second, so that remote instances aren't flooded. Poll the returned import to see how far along it is,
+// and which accounts couldn't be imported. Only one import can be in progress at a time.
+//
+// ---
+// tags:
+// - imports
+//
+// consumes:
+// - multipart/form-data
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: type
+//   in: formData
+//   description: What to do with the accounts in the file.
+//   type: string
+//   enum:
+//   - follows
+//   - blocks
+//   - mutes
+//   required: true
+// - name: data
+//   in: formData
+//   description: |-
+//     CSV file of accounts, in the format that Mastodon exports.
+//     The first column is the address of each account, such as `someone@example.org`.
+//   type: file
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:accounts
+//
+// responses:
+//   '200':
+//     description: The newly started import.
+//     schema:
+//       "$ref": "#/definitions/import"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: the file couldn't be read, is larger than 5MB, has no accounts or too many accounts, or an import is already in progress
+//   '500':
+//      description: internal error
+func (m *Module) ImportPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "ImportPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.ImportCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if form.Type == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no import type provided"})
+		return
+	}
+
+	if form.Data == nil || form.Data.Size == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no import file provided"})
+		return
+	}
+
+	if form.Data.Size > processing.ImportMaxSize {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("import file is larger than %d bytes", processing.ImportMaxSize)})
+		return
+	}
+
+	imp, errWithCode := m.processor.ImportCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor ImportCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, imp)
+}
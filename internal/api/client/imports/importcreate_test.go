@@ -0,0 +1,120 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package imports_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/imports"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+)
+
+type ImportCreateTestSuite struct {
+	ImportsStandardTestSuite
+}
+
+// createImport posts an import of the given type and data, and returns the status code and body of the response.
+func (suite *ImportCreateTestSuite) createImport(importType string, data []byte) (int, []byte) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	suite.NoError(w.WriteField("type", importType))
+	if data != nil {
+		fw, err := w.CreateFormFile("data", "import.csv")
+		suite.NoError(err)
+		_, err = fw.Write(data)
+		suite.NoError(err)
+	}
+	suite.NoError(w.Close())
+
+	recorder := suite.request(suite.importsModule.ImportPOSTHandler, http.MethodPost, imports.BasePath, "", body.Bytes(), w.FormDataContentType())
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+// getImport gets the import with the given id.
+func (suite *ImportCreateTestSuite) getImport(id string) *apimodel.Import {
+	recorder := suite.request(suite.importsModule.ImportGETHandler, http.MethodGet, imports.BasePathWithID, id, nil, "")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	imp := &apimodel.Import{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), imp))
+	return imp
+}
+
+func (suite *ImportCreateTestSuite) TestImportBlocks() {
+	code, b := suite.createImport("blocks", []byte("1happyturtle@localhost:8080\nnobody@localhost:8080\n"))
+	suite.Equal(http.StatusOK, code)
+
+	imp := &apimodel.Import{}
+	suite.NoError(json.Unmarshal(b, imp))
+	suite.Equal("blocks", imp.Type)
+	suite.Equal(2, imp.Total)
+
+	// only one import can be in progress at a time
+	code, b = suite.createImport("mutes", []byte("1happyturtle@localhost:8080\n"))
+	suite.Equal(http.StatusUnprocessableEntity, code)
+	suite.Equal(`{"error":"unprocessable entity: an import is already in progress"}`, string(b))
+
+	for i := 0; imp.State == "pending" && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		imp = suite.getImport(imp.ID)
+	}
+	suite.Equal("done", imp.State)
+	suite.Equal(2, imp.Processed)
+	suite.Equal([]string{"nobody@localhost:8080"}, imp.FailedAddresses)
+
+	// the import is listed along with any others
+	recorder := suite.request(suite.importsModule.ImportsGETHandler, http.MethodGet, imports.BasePath, "", nil, "")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	apiImports := []*apimodel.Import{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), &apiImports))
+	suite.Len(apiImports, 1)
+	suite.Equal(imp.ID, apiImports[0].ID)
+	suite.Equal([]string{"nobody@localhost:8080"}, apiImports[0].FailedAddresses)
+}
+
+func (suite *ImportCreateTestSuite) TestImportTooLarge() {
+	data := bytes.Repeat([]byte("1happyturtle@localhost:8080\n"), processing.ImportMaxSize/28+1)
+
+	code, b := suite.createImport("follows", data)
+	suite.Equal(http.StatusUnprocessableEntity, code)
+	suite.Equal(`{"error":"import file is larger than 5242880 bytes"}`, string(b))
+}
+
+func (suite *ImportCreateTestSuite) TestImportNoFile() {
+	code, b := suite.createImport("follows", nil)
+	suite.Equal(http.StatusBadRequest, code)
+	suite.Equal(`{"error":"no import file provided"}`, string(b))
+}
+
+func (suite *ImportCreateTestSuite) TestImportNotFound() {
+	recorder := suite.request(suite.importsModule.ImportGETHandler, http.MethodGet, imports.BasePathWithID, "01G2B2PS4B7DAGQ2YGMKC2H5WB", nil, "")
+	suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
+func TestImportCreateTestSuite(t *testing.T) {
+	suite.Run(t, &ImportCreateTestSuite{})
+}
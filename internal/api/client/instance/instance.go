@@ -11,6 +11,10 @@ import (
 const (
 	// InstanceInformationPath is for serving instance info requests
 	InstanceInformationPath = "api/v1/instance"
+	// InstanceRulesPath is for serving the rules of this instance
+	InstanceRulesPath = InstanceInformationPath + "/rules"
+	// InstanceInformationPathV2 is for serving v2 instance info requests
+	InstanceInformationPathV2 = "api/v2/instance"
 )
 
 // Module implements the ClientModule interface
@@ -29,5 +33,7 @@ func New(processor processing.Processor) api.ClientModule {
 func (m *Module) Route(s router.Router) error {
 	s.AttachHandler(http.MethodGet, InstanceInformationPath, m.InstanceInformationGETHandler)
 	s.AttachHandler(http.MethodPatch, InstanceInformationPath, m.InstanceUpdatePATCHHandler)
+	s.AttachHandler(http.MethodGet, InstanceRulesPath, m.InstanceRulesGETHandler)
+	s.AttachHandler(http.MethodGet, InstanceInformationPathV2, m.InstanceInformationGETHandlerV2)
 	return nil
 }
@@ -52,3 +52,43 @@ func (m *Module) InstanceInformationGETHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, instance)
 }
+
+// InstanceInformationGETHandlerV2 swagger:operation GET /api/v2/instance instanceGetV2
+//
+// View instance information, in the layout of version 2 of the Mastodon instance API.
+//
+// Amongst other things, this tells clients whether statuses can be translated on this instance.
+//
+// ---
+// tags:
+// - instance
+//
+// produces:
+// - application/json
+//
+// responses:
+//   '200':
+//     description: "Instance information."
+//     schema:
+//       "$ref": "#/definitions/instanceV2"
+//   '500':
+//      description: internal error
+func (m *Module) InstanceInformationGETHandlerV2(c *gin.Context) {
+	l := logrus.WithField("func", "InstanceInformationGETHandlerV2")
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	host := viper.GetString(config.Keys.Host)
+
+	instance, err := m.processor.InstanceGetV2(c.Request.Context(), host)
+	if err != nil {
+		l.Debugf("error getting instance from processor: %s", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, instance)
+}
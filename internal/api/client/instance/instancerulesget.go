@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instance
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+)
+
+// InstanceRulesGETHandler swagger:operation GET /api/v1/instance/rules instanceRulesGet
+//
+// View the rules of this instance.
+//
+// The rules are also included in the instance information, so that clients can show them to accounts when they sign up.
+//
+// ---
+// tags:
+// - instance
+//
+// produces:
+// - application/json
+//
+// responses:
+//   '200':
+//     description: The rules of this instance, in the order they should be shown.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/instanceRule"
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) InstanceRulesGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "InstanceRulesGETHandler")
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, errWithCode := m.processor.InstanceRulesGet(c.Request.Context())
+	if errWithCode != nil {
+		l.Debugf("error getting rules from processor: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
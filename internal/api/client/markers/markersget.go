@@ -0,0 +1,93 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package markers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// MarkersGETHandler swagger:operation GET /api/v1/markers markersGet
+//
+// Get the saved read positions of the requesting account in the given timelines.
+//
+// Timelines that no marker has been set for yet are left out of the response.
+//
+// ---
+// tags:
+// - markers
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: timeline[]
+//   type: array
+//   items:
+//     type: string
+//     enum:
+//     - home
+//     - notifications
+//   collectionFormat: multi
+//   description: Timelines to get markers for.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: Markers of the requested timelines.
+//     schema:
+//       "$ref": "#/definitions/markers"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) MarkersGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "MarkersGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	markers, errWithCode := m.processor.MarkersGet(c.Request.Context(), authed, c.QueryArray(TimelineKey))
+	if errWithCode != nil {
+		l.Debugf("error from processor MarkersGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, markers)
+}
@@ -48,7 +48,7 @@ import (
 //
 // security:
 // - OAuth2 Bearer:
-//   - read:media
+//   - read:statuses
 //
 // responses:
 //   '200':
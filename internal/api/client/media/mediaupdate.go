@@ -37,7 +37,8 @@ import (
 //
 // Update a media attachment.
 //
-// You must own the media attachment, and the attachment must not yet be attached to a status.
+// You must own the media attachment. If the attachment has already been attached to a status,
+// the updated status will be sent out to other instances so that they see the change too.
 //
 // The parameters can also be given in the body of the request, as JSON, if the content-type is set to 'application/json'.
 // The parameters can also be given in the body of the request, as XML, if the content-type is set to 'application/xml'.
@@ -21,6 +21,7 @@ package notification
 import (
 	"net/http"
 
+	"github.com/gin-gonic/gin"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
@@ -34,6 +35,12 @@ const (
 	// BasePathWithID is just the base path with the ID key in it.
 	// Use this anywhere you need to know the ID of the notification being queried.
 	BasePathWithID = BasePath + "/:" + IDKey
+	// DismissPath is for dismissing a single notification
+	DismissPath = BasePathWithID + "/dismiss"
+	// V2BasePath is the base path for serving grouped notifications
+	V2BasePath = "/api/v2/notifications"
+	// ClearSegment is the last path segment for clearing all notifications, ie., /api/v1/notifications/clear
+	ClearSegment = "clear"
 
 	// MaxIDKey is the url query for setting a max notification ID to return
 	MaxIDKey = "max_id"
@@ -41,6 +48,14 @@ const (
 	LimitKey = "limit"
 	// SinceIDKey is for specifying the minimum notification ID to return.
 	SinceIDKey = "since_id"
+	// MinIDKey is for specifying the notification ID to page up from, returning the notifications right after it.
+	MinIDKey = "min_id"
+	// TypesKey is for specifying the types of notification to return.
+	TypesKey = "types[]"
+	// ExcludeTypesKey is for specifying the types of notification not to return.
+	ExcludeTypesKey = "exclude_types[]"
+	// GroupedTypesKey is for specifying the types of notification that should be grouped.
+	GroupedTypesKey = "grouped_types[]"
 )
 
 // Module implements the ClientAPIModule interface for every related to posting/deleting/interacting with notifications
@@ -58,5 +73,19 @@ func New(processor processing.Processor) api.ClientModule {
 // Route attaches all routes from this module to the given router
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, BasePath, m.NotificationsGETHandler)
+	r.AttachHandler(http.MethodGet, V2BasePath, m.NotificationsGroupedGETHandler)
+	r.AttachHandler(http.MethodPost, DismissPath, m.NotificationDismissPOSTHandler)
+	r.AttachHandler(http.MethodPost, BasePathWithID, m.muxHandler)
 	return nil
 }
+
+// muxHandler is a little workaround to overcome the limitations of Gin, which won't let
+// /api/v1/notifications/clear share a router with /api/v1/notifications/:id/dismiss
+func (m *Module) muxHandler(c *gin.Context) {
+	if c.Param(IDKey) == ClearSegment && c.Request.Method == http.MethodPost {
+		m.NotificationsClearPOSTHandler(c)
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+}
@@ -28,7 +28,76 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-// NotificationsGETHandler serves a list of notifications to the caller, with the desired query parameters
+// NotificationsGETHandler swagger:operation GET /api/v1/notifications notificationsGet
+//
+// Get notifications for the requesting account, newest first.
+//
+// The next and previous queries can be parsed from the returned Link header.
+// Example:
+//
+// ```
+// <https://example.org/api/v1/notifications?limit=20&max_id=01FC3GSQ8A3MMJ43BPZSGEG29M>; rel="next", <https://example.org/api/v1/notifications?limit=20&min_id=01FC3KJW2GYXSDDRA6RWNDM46M>; rel="prev"
+// ```
+//
+// ---
+// tags:
+// - notifications
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: limit
+//   type: integer
+//   description: Number of notifications to return.
+//   default: 20
+//   in: query
+// - name: max_id
+//   type: string
+//   description: Return only notifications *OLDER* than the given max ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: since_id
+//   type: string
+//   description: Return only notifications *NEWER* than the given since ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: min_id
+//   type: string
+//   description: Return only notifications *IMMEDIATELY NEWER* than the given min ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: types[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Return only notifications of the given types, eg., mention, favourite.
+//   in: query
+// - name: exclude_types[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Don't return notifications of the given types, eg., follow, reblog.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:notifications
+//
+// responses:
+//   '200':
+//     headers:
+//       Link:
+//         type: string
+//         description: Links to the next and previous queries.
+//     description: Array of notifications.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/notification"
+//   '400':
+//      description: bad request
+//   '406':
+//      description: not acceptable
 func (m *Module) NotificationsGETHandler(c *gin.Context) {
 	l := logrus.WithFields(logrus.Fields{
 		"func":        "NotificationsGETHandler",
@@ -74,12 +143,24 @@ func (m *Module) NotificationsGETHandler(c *gin.Context) {
 		sinceID = sinceIDString
 	}
 
-	notifs, errWithCode := m.processor.NotificationsGet(c.Request.Context(), authed, limit, maxID, sinceID)
+	minID := ""
+	minIDString := c.Query(MinIDKey)
+	if minIDString != "" {
+		minID = minIDString
+	}
+
+	types := c.QueryArray(TypesKey)
+	excludeTypes := c.QueryArray(ExcludeTypesKey)
+
+	resp, errWithCode := m.processor.NotificationsGet(c.Request.Context(), authed, limit, maxID, sinceID, minID, types, excludeTypes)
 	if errWithCode != nil {
 		l.Debugf("error processing notifications get: %s", errWithCode.Error())
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
 
-	c.JSON(http.StatusOK, notifs)
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.Notifications)
 }
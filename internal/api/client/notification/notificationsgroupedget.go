@@ -0,0 +1,162 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package notification
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// NotificationsGroupedGETHandler swagger:operation GET /api/v2/notifications notificationsGetGrouped
+//
+// Get notifications for the requesting account, newest first, with notifications about the same thing grouped together.
+//
+// Favourites and boosts of the same status are grouped together, as are follows on the same day.
+// The accounts and statuses that the groups refer to are returned once each, alongside the groups.
+//
+// The next and previous queries can be parsed from the returned Link header.
+// Example:
+//
+// ```
+// <https://example.org/api/v2/notifications?limit=20&max_id=01FC3GSQ8A3MMJ43BPZSGEG29M>; rel="next", <https://example.org/api/v2/notifications?limit=20&min_id=01FC3KJW2GYXSDDRA6RWNDM46M>; rel="prev"
+// ```
+//
+// ---
+// tags:
+// - notifications
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: limit
+//   type: integer
+//   description: Number of notifications to group.
+//   default: 40
+//   in: query
+// - name: max_id
+//   type: string
+//   description: Group only notifications *OLDER* than the given max ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: since_id
+//   type: string
+//   description: Group only notifications *NEWER* than the given since ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: min_id
+//   type: string
+//   description: Group only notifications *IMMEDIATELY NEWER* than the given min ID. The notification with the specified ID will not be included in the response.
+//   in: query
+// - name: types[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Return only notifications of the given types, eg., mention, favourite.
+//   in: query
+// - name: exclude_types[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Don't return notifications of the given types, eg., follow, reblog.
+//   in: query
+// - name: grouped_types[]
+//   type: array
+//   items:
+//     type: string
+//     enum:
+//     - favourite
+//     - reblog
+//     - follow
+//   collectionFormat: multi
+//   description: Types of notification to group. Defaults to all of favourite, reblog, and follow.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:notifications
+//
+// responses:
+//   '200':
+//     headers:
+//       Link:
+//         type: string
+//         description: Links to the next and previous queries.
+//     description: The grouped notifications.
+//     schema:
+//       "$ref": "#/definitions/groupedNotifications"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) NotificationsGroupedGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "NotificationsGroupedGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 40
+	limitString := c.Query(LimitKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	resp, errWithCode := m.processor.NotificationsGetGrouped(
+		c.Request.Context(),
+		authed,
+		limit,
+		c.Query(MaxIDKey),
+		c.Query(SinceIDKey),
+		c.Query(MinIDKey),
+		c.QueryArray(TypesKey),
+		c.QueryArray(ExcludeTypesKey),
+		c.QueryArray(GroupedTypesKey),
+	)
+	if errWithCode != nil {
+		l.Debugf("error from processor NotificationsGetGrouped: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.GroupedNotifications)
+}
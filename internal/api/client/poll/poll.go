@@ -0,0 +1,57 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package poll
+
+import (
+	"net/http"
+
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+const (
+	// IDKey is for poll UUIDs
+	IDKey = "id"
+	// BasePath is the base path for serving polls
+	BasePath = "/api/v1/polls"
+	// BasePathWithID is the base path with the ID key in it, for serving a single poll
+	BasePathWithID = BasePath + "/:" + IDKey
+	// VotesPath is for casting votes in a poll
+	VotesPath = BasePathWithID + "/votes"
+)
+
+// Module implements the ClientAPIModule interface for everything relating to polls
+type Module struct {
+	processor processing.Processor
+}
+
+// New returns a new poll module
+func New(processor processing.Processor) api.ClientModule {
+	return &Module{
+		processor: processor,
+	}
+}
+
+// Route attaches all routes from this module to the given router
+func (m *Module) Route(r router.Router) error {
+	r.AttachHandler(http.MethodGet, BasePathWithID, m.PollGETHandler)
+	r.AttachHandler(http.MethodPost, VotesPath, m.PollVotePOSTHandler)
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package poll
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// PollVotePOSTHandler swagger:operation POST /api/v1/polls/{id}/votes pollVote
+//
+// Vote in a poll.
+//
+// Votes in polls on other instances are sent to the instance of the poll's author. Until the
+// poll is refreshed from there, the vote counts of such a poll only include votes from this instance.
+//
+// ---
+// tags:
+// - polls
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: id
+//   type: string
+//   description: ID of the poll.
+//   in: path
+//   required: true
+// - name: choices[]
+//   in: formData
+//   description: Indices of the chosen options. Only one may be given unless the poll allows multiple choices.
+//   type: array
+//   items:
+//     type: integer
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:statuses
+//
+// responses:
+//   '200':
+//     description: The poll, with the new vote counted.
+//     schema:
+//       "$ref": "#/definitions/poll"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: unprocessable entity
+func (m *Module) PollVotePOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "PollVotePOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	pollID := c.Param(IDKey)
+	if pollID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no poll id provided"})
+		return
+	}
+
+	form := &model.PollVoteRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	poll, errWithCode := m.processor.PollVote(c.Request.Context(), authed, pollID, form.Choices)
+	if errWithCode != nil {
+		l.Debugf("error from processor PollVote: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
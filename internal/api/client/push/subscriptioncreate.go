@@ -0,0 +1,140 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package push
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// PushSubscriptionPOSTHandler swagger:operation POST /api/v1/push/subscription pushSubscriptionCreate
+//
+// Subscribe to web push notifications for the access token used to make this request.
+//
+// Each access token has at most one subscription, so this replaces any existing subscription of the token.
+// Notifications are encrypted with the given keys, and signed with the `server_key` of the returned subscription.
+//
+// ---
+// tags:
+// - push
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: subscription[endpoint]
+//   in: formData
+//   description: The https URL of the push service endpoint to send notifications to.
+//   type: string
+//   required: true
+// - name: subscription[keys][p256dh]
+//   in: formData
+//   description: The client's public key, as a base64url encoded P-256 point.
+//   type: string
+//   required: true
+// - name: subscription[keys][auth]
+//   in: formData
+//   description: The client's auth secret, as 16 base64url encoded bytes.
+//   type: string
+//   required: true
+// - name: data[alerts][follow]
+//   in: formData
+//   description: Send notifications of new followers.
+//   type: boolean
+// - name: data[alerts][follow_request]
+//   in: formData
+//   description: Send notifications of new follow requests.
+//   type: boolean
+// - name: data[alerts][favourite]
+//   in: formData
+//   description: Send notifications of statuses being favourited.
+//   type: boolean
+// - name: data[alerts][reblog]
+//   in: formData
+//   description: Send notifications of statuses being boosted.
+//   type: boolean
+// - name: data[alerts][mention]
+//   in: formData
+//   description: Send notifications of mentions.
+//   type: boolean
+// - name: data[alerts][poll]
+//   in: formData
+//   description: Send notifications of polls ending.
+//   type: boolean
+// - name: data[alerts][status]
+//   in: formData
+//   description: Send notifications of new statuses from accounts that notifications have been enabled for.
+//   type: boolean
+//
+// security:
+// - OAuth2 Bearer:
+//   - push
+//
+// responses:
+//   '200':
+//     description: The new web push subscription.
+//     schema:
+//       "$ref": "#/definitions/webPushSubscription"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '406':
+//      description: not acceptable
+func (m *Module) PushSubscriptionPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "PushSubscriptionPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.WebPushSubscriptionCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, errWithCode := m.processor.PushSubscriptionCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor PushSubscriptionCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
@@ -0,0 +1,137 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package reports
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// ReportPOSTHandler swagger:operation POST /api/v1/reports reportCreate
+//
+// Report an account, and optionally some of its statuses, to the moderators of this instance.
+//
+// A report about a remote account can also be forwarded to the account's own instance, as a Flag activity that comes from this instance rather than from the requesting account.
+//
+// ---
+// tags:
+// - reports
+//
+// consumes:
+// - application/json
+// - application/xml
+// - application/x-www-form-urlencoded
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: account_id
+//   in: formData
+//   description: ID of the account to report.
+//   type: string
+//   required: true
+// - name: status_ids[]
+//   in: formData
+//   description: IDs of statuses of the account to attach to the report.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+// - name: comment
+//   in: formData
+//   description: Why the account is being reported. At most 1000 characters.
+//   type: string
+// - name: forward
+//   in: formData
+//   description: Forward the report to the instance of the account, if it's remote.
+//   type: boolean
+// - name: category
+//   in: formData
+//   description: |-
+//     What kind of problem the report is about.
+//     Defaults to violation if rule_ids are given, and other if they aren't.
+//   type: string
+//   enum:
+//   - spam
+//   - violation
+//   - other
+// - name: rule_ids[]
+//   in: formData
+//   description: IDs of the rules of this instance that the account broke. Must be given when the category is violation, and is ignored otherwise.
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:reports
+//
+// responses:
+//   '200':
+//     description: The new report.
+//     schema:
+//       "$ref": "#/definitions/report"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+//   '422':
+//      description: unprocessable entity
+func (m *Module) ReportPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "ReportPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	form := &model.ReportCreateRequest{}
+	if err := c.ShouldBind(form); err != nil {
+		l.Debugf("could not parse form from request: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, errWithCode := m.processor.ReportCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error from processor ReportCreate: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -33,6 +33,8 @@ import (
 const (
 	// IDKey is for status UUIDs
 	IDKey = "id"
+	// EmojiKey is for the emoji of an emoji reaction
+	EmojiKey = "emoji"
 	// BasePath is the base path for serving the status API
 	BasePath = "/api/v1/statuses"
 	// BasePathWithID is just the base path with the ID key in it.
@@ -41,6 +43,10 @@ const (
 
 	// ContextPath is used for fetching context of posts
 	ContextPath = BasePathWithID + "/context"
+	// HistoryPath is used for fetching the previous versions of edited posts
+	HistoryPath = BasePathWithID + "/history"
+	// TranslatePath is used for translating posts into the language of the requester
+	TranslatePath = BasePathWithID + "/translate"
 
 	// FavouritedPath is for seeing who's faved a given status
 	FavouritedPath = BasePathWithID + "/favourited_by"
@@ -70,6 +76,13 @@ const (
 	PinPath = BasePathWithID + "/pin"
 	// UnpinPath is for undoing a pin and returning a status to the ever-swirling drain of time and entropy
 	UnpinPath = BasePathWithID + "/unpin"
+
+	// PleromaBasePathWithID is the base path for pleroma-specific status endpoints, with the ID key in it.
+	PleromaBasePathWithID = "/api/v1/pleroma/statuses/:" + IDKey
+	// ReactionsPath is for seeing the emoji reactions to a given status
+	ReactionsPath = PleromaBasePathWithID + "/reactions"
+	// ReactionPath is for reacting to a given status with an emoji, or removing the reaction
+	ReactionPath = ReactionsPath + "/:" + EmojiKey
 )
 
 // Module implements the ClientAPIModule interface for every related to posting/deleting/interacting with statuses
@@ -88,6 +101,9 @@ func New(processor processing.Processor) api.ClientModule {
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodPost, BasePath, m.StatusCreatePOSTHandler)
 	r.AttachHandler(http.MethodDelete, BasePathWithID, m.StatusDELETEHandler)
+	r.AttachHandler(http.MethodPut, BasePathWithID, m.StatusEditPUTHandler)
+	r.AttachHandler(http.MethodGet, HistoryPath, m.StatusHistoryGETHandler)
+	r.AttachHandler(http.MethodPost, TranslatePath, m.StatusTranslatePOSTHandler)
 
 	r.AttachHandler(http.MethodPost, FavouritePath, m.StatusFavePOSTHandler)
 	r.AttachHandler(http.MethodPost, UnfavouritePath, m.StatusUnfavePOSTHandler)
@@ -99,6 +115,13 @@ func (m *Module) Route(r router.Router) error {
 
 	r.AttachHandler(http.MethodGet, ContextPath, m.StatusContextGETHandler)
 
+	r.AttachHandler(http.MethodPost, PinPath, m.StatusPinPOSTHandler)
+	r.AttachHandler(http.MethodPost, UnpinPath, m.StatusUnpinPOSTHandler)
+
+	r.AttachHandler(http.MethodPut, ReactionPath, m.StatusReactPUTHandler)
+	r.AttachHandler(http.MethodDelete, ReactionPath, m.StatusUnreactDELETEHandler)
+	r.AttachHandler(http.MethodGet, ReactionsPath, m.StatusReactionsGETHandler)
+
 	r.AttachHandler(http.MethodGet, BasePathWithID, m.muxHandler)
 	return nil
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/validate"
 )
@@ -110,16 +111,18 @@ func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
 		return
 	}
 
-	apiStatus, err := m.processor.StatusCreate(c.Request.Context(), authed, form)
-	if err != nil {
-		l.Debugf("error processing status create: %s", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+	apiStatus, errWithCode := m.processor.StatusCreate(c.Request.Context(), authed, form)
+	if errWithCode != nil {
+		l.Debugf("error processing status create: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
 
 	c.JSON(http.StatusOK, apiStatus)
 }
 
+// validateCreateStatus checks that the given form makes sense as a status. Limits on the length of the
+// status and the number of attachments and poll options are checked when the status is processed.
 func validateCreateStatus(form *model.AdvancedStatusCreateForm) error {
 	// validate that, structurally, we have a valid status/post
 	if form.Status == "" && form.MediaIDs == nil && form.Poll == nil {
@@ -130,45 +133,24 @@ func validateCreateStatus(form *model.AdvancedStatusCreateForm) error {
 		return errors.New("can't post media + poll in same status")
 	}
 
-	keys := config.Keys
-	maxChars := viper.GetInt(keys.StatusesMaxChars)
-	maxMediaFiles := viper.GetInt(keys.StatusesMediaMaxFiles)
-	maxPollOptions := viper.GetInt(keys.StatusesPollMaxOptions)
-	maxPollChars := viper.GetInt(keys.StatusesPollOptionMaxChars)
-	maxCwChars := viper.GetInt(keys.StatusesCWMaxChars)
-
-	// validate status
-	if form.Status != "" {
-		if len(form.Status) > maxChars {
-			return fmt.Errorf("status too long, %d characters provided but limit is %d", len(form.Status), maxChars)
-		}
-	}
-
-	// validate media attachments
-	if len(form.MediaIDs) > maxMediaFiles {
-		return fmt.Errorf("too many media files attached to status, %d attached but limit is %d", len(form.MediaIDs), maxMediaFiles)
-	}
-
 	// validate poll
 	if form.Poll != nil {
 		if form.Poll.Options == nil {
 			return errors.New("poll with no options")
 		}
-		if len(form.Poll.Options) > maxPollOptions {
-			return fmt.Errorf("too many poll options provided, %d provided but limit is %d", len(form.Poll.Options), maxPollOptions)
+		if len(form.Poll.Options) < 2 {
+			return fmt.Errorf("not enough poll options provided, %d provided but at least 2 are needed", len(form.Poll.Options))
 		}
-		for _, p := range form.Poll.Options {
-			if len(p) > maxPollChars {
-				return fmt.Errorf("poll option too long, %d characters provided but limit is %d", len(p), maxPollChars)
-			}
+		minExpiresIn := int(gtsmodel.PollMinExpiresIn.Seconds())
+		maxExpiresIn := int(gtsmodel.PollMaxExpiresIn.Seconds())
+		if form.Poll.ExpiresIn < minExpiresIn || form.Poll.ExpiresIn > maxExpiresIn {
+			return fmt.Errorf("poll expires_in must be between %d and %d seconds, but %d was provided", minExpiresIn, maxExpiresIn, form.Poll.ExpiresIn)
 		}
 	}
 
-	// validate spoiler text/cw
-	if form.SpoilerText != "" {
-		if len(form.SpoilerText) > maxCwChars {
-			return fmt.Errorf("content-warning/spoilertext too long, %d characters provided but limit is %d", len(form.SpoilerText), maxCwChars)
-		}
+	// validate quote
+	if form.QuoteID != "" && !viper.GetBool(config.Keys.StatusesQuotesEnabled) {
+		return errors.New("quotes are not enabled on this instance")
 	}
 
 	// validate post language
@@ -0,0 +1,129 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type StatusReactTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusReactTestSuite) reactionContext(method string, statusID string, emoji string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+
+	path := strings.Replace(status.ReactionPath, ":"+status.IDKey, statusID, 1)
+	path = strings.Replace(path, ":"+status.EmojiKey, url.PathEscape(emoji), 1)
+	ctx.Request = httptest.NewRequest(method, fmt.Sprintf("http://localhost:8080%s", path), nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/json")
+
+	// normally the router would populate these params from the path values,
+	// but because we're calling the function directly, we need to set them manually.
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   status.IDKey,
+			Value: statusID,
+		},
+		gin.Param{
+			Key:   status.EmojiKey,
+			Value: emoji,
+		},
+	}
+
+	return ctx, recorder
+}
+
+func (suite *StatusReactTestSuite) statusFromRecorder(recorder *httptest.ResponseRecorder) *model.Status {
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	apiStatus := &model.Status{}
+	suite.NoError(json.Unmarshal(b, apiStatus))
+	return apiStatus
+}
+
+func (suite *StatusReactTestSuite) TestReactAndUnreact() {
+	targetStatus := suite.testStatuses["admin_account_status_2"]
+
+	// react to the status
+	ctx, recorder := suite.reactionContext(http.MethodPut, targetStatus.ID, "👍")
+	suite.statusModule.StatusReactPUTHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	reacted := suite.statusFromRecorder(recorder)
+	suite.Equal(targetStatus.ID, reacted.ID)
+	suite.NotNil(reacted.Pleroma)
+	suite.Equal([]model.EmojiReaction{{Name: "👍", Count: 1, Me: true}}, reacted.Pleroma.EmojiReactions)
+
+	// reacting again with the same emoji shouldn't change anything
+	ctx, recorder = suite.reactionContext(http.MethodPut, targetStatus.ID, "👍")
+	suite.statusModule.StatusReactPUTHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal(reacted.Pleroma, suite.statusFromRecorder(recorder).Pleroma)
+
+	// now remove the reaction again
+	ctx, recorder = suite.reactionContext(http.MethodDelete, targetStatus.ID, "👍")
+	suite.statusModule.StatusUnreactDELETEHandler(ctx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	unreacted := suite.statusFromRecorder(recorder)
+	suite.Equal(targetStatus.ID, unreacted.ID)
+	suite.Nil(unreacted.Pleroma)
+}
+
+func (suite *StatusReactTestSuite) TestReactUnreactable() {
+	targetStatus := suite.testStatuses["local_account_2_status_3"] // this one is unlikeable and unreplyable
+
+	ctx, recorder := suite.reactionContext(http.MethodPut, targetStatus.ID, "👍")
+	suite.statusModule.StatusReactPUTHandler(ctx)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+func (suite *StatusReactTestSuite) TestReactInvalidEmoji() {
+	targetStatus := suite.testStatuses["admin_account_status_2"]
+
+	ctx, recorder := suite.reactionContext(http.MethodPut, targetStatus.ID, "not an emoji")
+	suite.statusModule.StatusReactPUTHandler(ctx)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestStatusReactTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusReactTestSuite))
+}
@@ -0,0 +1,102 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/status"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type StatusTranslateTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusTranslateTestSuite) translate(targetStatus *gtsmodel.Status, lang string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+
+	form := url.Values{}
+	if lang != "" {
+		form.Set("lang", lang)
+	}
+	ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:8080%s", strings.Replace(status.TranslatePath, ":id", targetStatus.ID, 1)), strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx.Request.Header.Set("accept", "application/json")
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   status.IDKey,
+			Value: targetStatus.ID,
+		},
+	}
+
+	suite.statusModule.StatusTranslatePOSTHandler(ctx)
+	return recorder
+}
+
+func (suite *StatusTranslateTestSuite) TestTranslate() {
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	recorder := suite.translate(targetStatus, "de")
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	translation := &model.Translation{}
+	err := json.Unmarshal(recorder.Body.Bytes(), translation)
+	suite.NoError(err)
+
+	suite.Equal("[de] "+targetStatus.Content, translation.Content)
+	suite.Equal("en", translation.DetectedSourceLanguage)
+	suite.Equal("Test Translator", translation.Provider)
+	suite.Nil(translation.Poll)
+	if suite.Len(translation.MediaAttachments, 1) {
+		suite.Equal("01F8MH6NEM8D7527KZAECTCR76", translation.MediaAttachments[0].ID)
+		suite.Equal("[de] "+suite.testAttachments["admin_account_status_1_attachment_1"].Description, translation.MediaAttachments[0].Description)
+	}
+}
+
+func (suite *StatusTranslateTestSuite) TestTranslateAlreadyInLanguage() {
+	recorder := suite.translate(suite.testStatuses["admin_account_status_1"], "en")
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	suite.Equal(`{"error":"bad request: status is already in the requested language"}`, recorder.Body.String())
+}
+
+func (suite *StatusTranslateTestSuite) TestTranslatePrivateStatus() {
+	recorder := suite.translate(suite.testStatuses["local_account_1_status_5"], "de")
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	suite.Equal(`{"error":"forbidden: only public and unlisted statuses can be translated"}`, recorder.Body.String())
+}
+
+func TestStatusTranslateTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusTranslateTestSuite))
+}
@@ -0,0 +1,354 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package streaming
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
+)
+
+// accessToken returns the access token of a streaming request, taken either from
+// the access_token query parameter or from a bearer token in the Authorization header.
+func accessToken(c *gin.Context) string {
+	if token := c.Query(AccessTokenQueryKey); token != "" {
+		return token
+	}
+
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); token != c.GetHeader("Authorization") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// eventStream streams the given timeline to the client as server-sent events, for clients and proxies
+// that can't do websockets. Messages are taken from the same stream as a websocket connection would use,
+// and written as an `event` line with the event type, followed by a `data` line with the payload.
+func (m *Module) eventStream(c *gin.Context, timeline string) {
+	l := logrus.WithFields(logrus.Fields{
+		"func":     "eventStream",
+		"timeline": timeline,
+	})
+
+	token := accessToken(c)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("no access token provided under query key %s or in Authorization header", AccessTokenQueryKey)})
+		return
+	}
+
+	// make sure a valid token has been provided and obtain the associated account
+	account, err := m.processor.AuthorizeStreamingRequest(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "could not authorize with given token"})
+		return
+	}
+
+	s, errWithCode := m.processor.OpenStreamForAccount(c.Request.Context(), account, timeline, c.Query(TagQueryKey))
+	if errWithCode != nil {
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+	defer close(s.Hangup) // the client has gone when we leave this function, so we're done with the stream
+
+	// the stream stays open for as long as the client wants it, so it mustn't be cut off by the server's write timeout
+	if err := router.ClearWriteDeadline(c); err != nil {
+		l.Debugf("error clearing write deadline: %s", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // tell nginx not to buffer the stream
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// write a comment into the stream periodically, to keep the connection from idling out and to notice when the client has gone
+	t := time.NewTicker(15 * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case msg := <-s.Messages:
+			if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", msg.Event, msg.Payload); err != nil {
+				l.Debugf("error writing event: %s", err)
+				return
+			}
+			c.Writer.Flush()
+		case <-t.C:
+			if _, err := fmt.Fprint(c.Writer, ":thump\n\n"); err != nil {
+				l.Debugf("error writing heartbeat: %s", err)
+				return
+			}
+			c.Writer.Flush()
+		case <-c.Request.Context().Done():
+			l.Trace("client closed the connection")
+			return
+		}
+	}
+}
+
+// HealthGETHandler swagger:operation GET /api/v1/streaming/health streamHealthGet
+//
+// Check whether the streaming API is up.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/plain
+//
+// responses:
+//   '200':
+//     description: OK
+func (m *Module) HealthGETHandler(c *gin.Context) {
+	c.String(http.StatusOK, "OK")
+}
+
+// UserEventsGETHandler swagger:operation GET /api/v1/streaming/user streamUserGet
+//
+// Stream statuses for the account's home timeline, and notifications, as server-sent events.
+//
+// Each event has the type of event (`update`, `notification`, `delete`) as its `event`, and the same payload as the websocket stream as its `data`.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '401':
+//      description: unauthorized
+func (m *Module) UserEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineHome)
+}
+
+// NotificationEventsGETHandler swagger:operation GET /api/v1/streaming/user/notification streamUserNotificationGet
+//
+// Stream notifications for the account as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '401':
+//      description: unauthorized
+func (m *Module) NotificationEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineNotifications)
+}
+
+// PublicEventsGETHandler swagger:operation GET /api/v1/streaming/public streamPublicGet
+//
+// Stream statuses for the public timeline as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '401':
+//      description: unauthorized
+func (m *Module) PublicEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelinePublic)
+}
+
+// PublicLocalEventsGETHandler swagger:operation GET /api/v1/streaming/public/local streamPublicLocalGet
+//
+// Stream statuses for the local timeline as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '401':
+//      description: unauthorized
+func (m *Module) PublicLocalEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineLocal)
+}
+
+// HashtagEventsGETHandler swagger:operation GET /api/v1/streaming/hashtag streamHashtagGet
+//
+// Stream public statuses with the given hashtag as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+// - name: tag
+//   type: string
+//   description: Name of the hashtag to stream.
+//   in: query
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+func (m *Module) HashtagEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineHashtag)
+}
+
+// HashtagLocalEventsGETHandler swagger:operation GET /api/v1/streaming/hashtag/local streamHashtagLocalGet
+//
+// Stream public statuses from local accounts with the given hashtag as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+// - name: tag
+//   type: string
+//   description: Name of the hashtag to stream.
+//   in: query
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+func (m *Module) HashtagLocalEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineHashtagLocal)
+}
+
+// DirectEventsGETHandler swagger:operation GET /api/v1/streaming/direct streamDirectGet
+//
+// Stream direct messages to or from the account as server-sent events.
+//
+// ---
+// tags:
+// - streaming
+//
+// produces:
+// - text/event-stream
+//
+// parameters:
+// - name: access_token
+//   type: string
+//   description: Access token for the requesting account. May instead be given as a bearer token in the Authorization header.
+//   in: query
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     description: a stream of server-sent events
+//   '401':
+//      description: unauthorized
+func (m *Module) DirectEventsGETHandler(c *gin.Context) {
+	m.eventStream(c, stream.TimelineDirect)
+}
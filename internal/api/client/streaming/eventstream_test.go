@@ -0,0 +1,186 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package streaming_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/email"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type EventStreamTestSuite struct {
+	suite.Suite
+	db           db.DB
+	storage      *gtsstorage.Driver
+	mediaManager media.Manager
+	federator    federation.Federator
+	processor    processing.Processor
+	emailSender  email.Sender
+
+	testTokens       map[string]*gtsmodel.Token
+	testApplications map[string]*gtsmodel.Application
+	testUsers        map[string]*gtsmodel.User
+	testAccounts     map[string]*gtsmodel.Account
+
+	server *httptest.Server
+}
+
+func (suite *EventStreamTestSuite) SetupSuite() {
+	suite.testTokens = testrig.NewTestTokens()
+	suite.testApplications = testrig.NewTestApplications()
+	suite.testUsers = testrig.NewTestUsers()
+	suite.testAccounts = testrig.NewTestAccounts()
+}
+
+func (suite *EventStreamTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	clientWorker := worker.New[messages.FromClientAPI](-1, -1)
+
+	suite.db = testrig.NewTestDB()
+	suite.storage = testrig.NewTestStorage()
+	suite.mediaManager = testrig.NewTestMediaManager(suite.db, suite.storage)
+	suite.federator = testrig.NewTestFederator(suite.db, testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker), suite.storage, suite.mediaManager, fedWorker)
+	suite.emailSender = testrig.NewEmailSender("../../../../web/template/", nil)
+	suite.processor = testrig.NewTestProcessor(suite.db, suite.storage, suite.federator, suite.emailSender, suite.mediaManager, clientWorker, fedWorker)
+	testrig.StandardDBSetup(suite.db, nil)
+	testrig.StandardStorageSetup(suite.storage, "../../../../testrig/media")
+	suite.NoError(suite.processor.Start())
+
+	// serve the event stream the way the router does, but with a write timeout short enough to test
+	streamingModule := streaming.New(suite.processor).(*streaming.Module)
+	engine := gin.New()
+	engine.GET(streaming.PublicPath, streamingModule.PublicEventsGETHandler)
+	suite.server = httptest.NewUnstartedServer(engine)
+	suite.server.Config.WriteTimeout = 1 * time.Second
+	suite.server.Config.ConnContext = router.ConnContext
+	suite.server.Start()
+}
+
+func (suite *EventStreamTestSuite) TearDownTest() {
+	suite.server.CloseClientConnections()
+	suite.server.Close()
+	suite.NoError(suite.processor.Stop())
+	testrig.StandardDBTeardown(suite.db)
+	testrig.StandardStorageTeardown(suite.storage)
+}
+
+// openStream opens the public event stream with the given access token, and returns the response to read events from.
+func (suite *EventStreamTestSuite) openStream(ctx context.Context, token string) *http.Response {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, suite.server.URL+streaming.PublicPath, nil)
+	suite.NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := suite.server.Client().Do(req)
+	suite.NoError(err)
+	return resp
+}
+
+func (suite *EventStreamTestSuite) TestStreamUnauthorized() {
+	resp := suite.openStream(context.Background(), "not a real token")
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *EventStreamTestSuite) TestStreamOutlivesWriteTimeout() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	resp := suite.openStream(ctx, suite.testTokens["local_account_1"].Access)
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	// wait out the write timeout of the server before there's anything to stream
+	time.Sleep(2 * time.Second)
+
+	authed := &oauth.Auth{
+		Account:     suite.testAccounts["local_account_1"],
+		User:        suite.testUsers["local_account_1"],
+		Application: suite.testApplications["application_1"],
+	}
+	apiStatus, errWithCode := suite.processor.StatusCreate(context.Background(), authed, &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:     "still streaming",
+			Visibility: apimodel.VisibilityPublic,
+			Format:     apimodel.StatusFormatPlain,
+		},
+	})
+	suite.NoError(errWithCode)
+
+	// the new status should still come through as an event
+	events := make(chan []string, 1)
+	go func() {
+		r := bufio.NewReader(resp.Body)
+		lines := []string{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				close(events)
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				events <- lines
+				return
+			}
+			lines = append(lines, line)
+		}
+	}()
+
+	select {
+	case lines, ok := <-events:
+		suite.True(ok, "stream was closed before an event came in")
+		suite.Len(lines, 2)
+		suite.Equal("event: update", lines[0])
+		suite.Contains(lines[1], fmt.Sprintf(`"id":"%s"`, apiStatus.ID))
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for event")
+	}
+}
+
+func TestEventStreamTestSuite(t *testing.T) {
+	suite.Run(t, new(EventStreamTestSuite))
+}
@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"net/http"
@@ -8,8 +9,19 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
 )
 
+// streamRequest is a message sent by the client to change the subscriptions of an open stream.
+type streamRequest struct {
+	// subscribe or unsubscribe
+	Type string `json:"type"`
+	// The stream type to (un)subscribe, as in the stream query parameter.
+	Stream string `json:"stream"`
+	// The tag to (un)subscribe, for hashtag streams.
+	Tag string `json:"tag"`
+}
+
 // StreamGETHandler swagger:operation GET /api/v1/streaming streamGet
 //
 // Initiate a websocket connection for live streaming of statuses and notifications.
@@ -20,6 +32,11 @@ import (
 //
 // As long as the connection is open, various message types will be streamed into it.
 //
+// The client can change which streams it receives over the connection by sending
+// `{"type":"subscribe","stream":"hashtag","tag":"example"}` or `{"type":"unsubscribe","stream":"public"}`,
+// so that one connection can carry multiple streams. If such a message can't be handled,
+// the server will reply with `{"error":"...","status":400}` and keep the connection open.
+//
 // GoToSocial will ping the connection every 30 seconds to check whether the client is still receiving.
 //
 // If the ping fails, or something else goes wrong during transmission, then the connection will be dropped, and the client will be expected to start it again.
@@ -37,9 +54,11 @@ import (
 // parameters:
 // - name: access_token
 //   type: string
-//   description: Access token for the requesting account.
+//   description: |-
+//     Access token for the requesting account.
+//     May instead be given as a bearer token in the Authorization header,
+//     or as the websocket protocol in the `Sec-WebSocket-Protocol` header.
 //   in: query
-//   required: true
 // - name: stream
 //   type: string
 //   description: |-
@@ -52,13 +71,18 @@ import (
 //     `public:local`: receive updates for the local timeline.
 //     `hashtag`: receive updates for a given hashtag.
 //     `hashtag:local`: receive local updates for a given hashtag.
-//     `list`: receive updates for a certain list of accounts.
+//     `user:notification`: receive notifications for the account.
 //     `direct`: receive updates for direct messages.
+//
+//     If not set, the connection will start out without any streams, and the client should subscribe to some over the connection.
+//   in: query
+// - name: tag
+//   type: string
+//   description: Name of the hashtag to receive updates for, when `stream` is `hashtag` or `hashtag:local`.
 //   in: query
-//   required: true
 // security:
 // - OAuth2 Bearer:
-//   - read:streaming
+//   - read:statuses
 //
 // responses:
 //   '101':
@@ -73,9 +97,9 @@ import (
 //             - user
 //             - public
 //             - public:local
+//             - user:notification
 //             - hashtag
 //             - hashtag:local
-//             - list
 //             - direct
 //         event:
 //           description: |-
@@ -110,25 +134,36 @@ import (
 func (m *Module) StreamGETHandler(c *gin.Context) {
 	l := logrus.WithField("func", "StreamGETHandler")
 
-	streamType := c.Query(StreamQueryKey)
-	if streamType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("no stream type provided under query key %s", StreamQueryKey)})
-		return
+	// the access token can be given as the websocket protocol instead of in the query,
+	// in which case we need to echo it back as the selected protocol when we upgrade
+	var responseHeader http.Header
+	token := accessToken(c)
+	if token == "" {
+		token = c.GetHeader(ProtocolHeaderKey)
+		if token != "" {
+			responseHeader = http.Header{ProtocolHeaderKey: []string{token}}
+		}
 	}
-
-	accessToken := c.Query(AccessTokenQueryKey)
-	if accessToken == "" {
+	if token == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("no access token provided under query key %s", AccessTokenQueryKey)})
 		return
 	}
 
 	// make sure a valid token has been provided and obtain the associated account
-	account, err := m.processor.AuthorizeStreamingRequest(c.Request.Context(), accessToken)
+	account, err := m.processor.AuthorizeStreamingRequest(c.Request.Context(), token)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "could not authorize with given token"})
 		return
 	}
 
+	// inform the processor that we have a new connection and want a s for it
+	s, errWithCode := m.processor.OpenStreamForAccount(c.Request.Context(), account, c.Query(StreamQueryKey), c.Query(TagQueryKey))
+	if errWithCode != nil {
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+	defer close(s.Hangup) // closing stream.Hangup indicates that we've finished with the connection (the client has gone), so we want to do this on exiting this handler
+
 	// prepare to upgrade the connection to a websocket connection
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
@@ -140,23 +175,22 @@ func (m *Module) StreamGETHandler(c *gin.Context) {
 	}
 
 	// do the actual upgrade here
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		l.Infof("error upgrading websocket connection: %s", err)
 		return
 	}
 	defer conn.Close() // whatever happens, when we leave this function we want to close the websocket connection
 
-	// inform the processor that we have a new connection and want a s for it
-	s, errWithCode := m.processor.OpenStreamForAccount(c.Request.Context(), account, streamType)
-	if errWithCode != nil {
-		c.JSON(errWithCode.Code(), errWithCode.Safe())
-		return
-	}
-	defer close(s.Hangup) // closing stream.Hangup indicates that we've finished with the connection (the client has gone), so we want to do this on exiting this handler
+	// read (un)subscribe requests from the client in the background -- any errors are passed back
+	// to the send loop to be written, since only one goroutine may write to the connection at a time
+	clientErrs := make(chan string, 10)
+	readDone := make(chan struct{})
+	go readLoop(conn, s, clientErrs, readDone)
 
 	// spawn a new ticker for pinging the connection periodically
 	t := time.NewTicker(30 * time.Second)
+	defer t.Stop()
 
 	// we want to stay in the sendloop as long as possible while the client is connected -- the only thing that should break the loop is if the client leaves or something else goes wrong
 sendLoop:
@@ -171,6 +205,14 @@ sendLoop:
 				break sendLoop
 			}
 			l.Trace("wrote message into websocket connection")
+		case e := <-clientErrs:
+			if err := conn.WriteJSON(gin.H{"error": e, "status": http.StatusBadRequest}); err != nil {
+				l.Debugf("error writing error to websocket connection: %s", err)
+				break sendLoop
+			}
+		case <-readDone:
+			l.Trace("client closed the connection")
+			break sendLoop
 		case <-t.C:
 			l.Trace("received TICK from ticker")
 			if err := conn.WriteMessage(websocket.PingMessage, []byte(": ping")); err != nil {
@@ -184,3 +226,49 @@ sendLoop:
 
 	l.Trace("leaving StreamGETHandler")
 }
+
+// readLoop reads subscribe and unsubscribe requests from the client and applies them to the given stream,
+// until reading from the connection fails, at which point it closes done.
+func readLoop(conn *websocket.Conn, s *stream.Stream, clientErrs chan<- string, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if err := handleStreamRequest(msg, s); err != nil {
+			select {
+			case clientErrs <- err.Error():
+			default:
+				// the client isn't reading its errors, no point queueing more
+			}
+		}
+	}
+}
+
+// handleStreamRequest subscribes or unsubscribes the given stream according to the given client message.
+func handleStreamRequest(msg []byte, s *stream.Stream) error {
+	r := &streamRequest{}
+	if err := json.Unmarshal(msg, r); err != nil {
+		return fmt.Errorf("could not parse message: %s", err)
+	}
+
+	names, err := stream.Names(r.Stream, r.Tag)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	switch r.Type {
+	case "subscribe":
+		s.Subscribe(names)
+	case "unsubscribe":
+		s.Unsubscribe(names)
+	default:
+		return fmt.Errorf("unknown message type %q", r.Type)
+	}
+	return nil
+}
@@ -29,12 +29,34 @@ import (
 const (
 	// BasePath is the path for the streaming api
 	BasePath = "/api/v1/streaming"
+	// HealthPath is for checking whether the streaming api is up
+	HealthPath = BasePath + "/health"
+	// UserPath is for streaming the home timeline and notifications as server-sent events
+	UserPath = BasePath + "/user"
+	// UserNotificationPath is for streaming notifications as server-sent events
+	UserNotificationPath = UserPath + "/notification"
+	// PublicPath is for streaming the public timeline as server-sent events
+	PublicPath = BasePath + "/public"
+	// PublicLocalPath is for streaming the local timeline as server-sent events
+	PublicLocalPath = PublicPath + "/local"
+	// HashtagPath is for streaming a hashtag timeline as server-sent events
+	HashtagPath = BasePath + "/hashtag"
+	// HashtagLocalPath is for streaming a local hashtag timeline as server-sent events
+	HashtagLocalPath = HashtagPath + "/local"
+	// DirectPath is for streaming direct messages as server-sent events
+	DirectPath = BasePath + "/direct"
 
 	// StreamQueryKey is the query key for the type of stream being requested
 	StreamQueryKey = "stream"
 
+	// TagQueryKey is the query key for the hashtag of a hashtag stream
+	TagQueryKey = "tag"
+
 	// AccessTokenQueryKey is the query key for an oauth access token that should be passed in streaming requests.
 	AccessTokenQueryKey = "access_token"
+
+	// ProtocolHeaderKey is the websocket protocol header, which may carry the access token instead of the query.
+	ProtocolHeaderKey = "Sec-Websocket-Protocol"
 )
 
 // Module implements the api.ClientModule interface for everything related to streaming
@@ -52,5 +74,13 @@ func New(processor processing.Processor) api.ClientModule {
 // Route attaches all routes from this module to the given router
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, BasePath, m.StreamGETHandler)
+	r.AttachHandler(http.MethodGet, HealthPath, m.HealthGETHandler)
+	r.AttachHandler(http.MethodGet, UserPath, m.UserEventsGETHandler)
+	r.AttachHandler(http.MethodGet, UserNotificationPath, m.NotificationEventsGETHandler)
+	r.AttachHandler(http.MethodGet, PublicPath, m.PublicEventsGETHandler)
+	r.AttachHandler(http.MethodGet, PublicLocalPath, m.PublicLocalEventsGETHandler)
+	r.AttachHandler(http.MethodGet, HashtagPath, m.HashtagEventsGETHandler)
+	r.AttachHandler(http.MethodGet, HashtagLocalPath, m.HashtagLocalEventsGETHandler)
+	r.AttachHandler(http.MethodGet, DirectPath, m.DirectEventsGETHandler)
 	return nil
 }
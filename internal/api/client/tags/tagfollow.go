@@ -0,0 +1,99 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tags
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TagFollowPOSTHandler swagger:operation POST /api/v1/tags/{name}/follow tagFollow
+//
+// Follow a hashtag, so that statuses using it show up in the requesting account's home timeline.
+//
+// Statuses using the hashtag are shown whether or not the requesting account follows the account that posted them,
+// as long as the requesting account is allowed to see them. Following a hashtag that's already followed does nothing.
+//
+// ---
+// tags:
+// - tags
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: name
+//   type: string
+//   description: The name of the hashtag, without the leading #.
+//   in: path
+//   required: true
+//
+// security:
+// - OAuth2 Bearer:
+//   - write:follows
+//
+// responses:
+//   '200':
+//     description: The followed hashtag.
+//     schema:
+//       "$ref": "#/definitions/tag"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '404':
+//      description: not found
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) TagFollowPOSTHandler(c *gin.Context) {
+	l := logrus.WithField("func", "TagFollowPOSTHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := c.Param(NameKey)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag name specified"})
+		return
+	}
+
+	tag, errWithCode := m.processor.TagFollow(c.Request.Context(), authed, name)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagFollow: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
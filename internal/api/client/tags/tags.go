@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tags
+
+import (
+	"net/http"
+
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+const (
+	// NameKey is the url parameter for the name of a hashtag
+	NameKey = "name"
+	// BasePath is the base path for serving the tags API
+	BasePath = "/api/v1/tags"
+	// BasePathWithName is the base path with the name of a hashtag, for interacting with a single hashtag
+	BasePathWithName = BasePath + "/:" + NameKey
+	// FollowPath is used for following a hashtag
+	FollowPath = BasePathWithName + "/follow"
+	// UnfollowPath is used for unfollowing a hashtag
+	UnfollowPath = BasePathWithName + "/unfollow"
+	// FollowedTagsPath is the path for serving the hashtags that the requesting account follows
+	FollowedTagsPath = "/api/v1/followed_tags"
+
+	// MaxIDKey is the url query for setting a max ID to return
+	MaxIDKey = "max_id"
+	// SinceIDKey is the url query for returning results newer than the given ID
+	SinceIDKey = "since_id"
+	// LimitKey is for specifying maximum number of results to return.
+	LimitKey = "limit"
+)
+
+// Module implements the ClientAPIModule interface for everything relating to hashtags
+type Module struct {
+	processor processing.Processor
+}
+
+// New returns a new tags module
+func New(processor processing.Processor) api.ClientModule {
+	return &Module{
+		processor: processor,
+	}
+}
+
+// Route attaches all routes from this module to the given router
+func (m *Module) Route(r router.Router) error {
+	r.AttachHandler(http.MethodPost, FollowPath, m.TagFollowPOSTHandler)
+	r.AttachHandler(http.MethodPost, UnfollowPath, m.TagUnfollowPOSTHandler)
+	r.AttachHandler(http.MethodGet, FollowedTagsPath, m.FollowedTagsGETHandler)
+	return nil
+}
@@ -67,7 +67,7 @@ import (
 // - name: min_id
 //   type: string
 //   description: |-
-//     Return only statuses *NEWER* than the given since status ID.
+//     Return only statuses *IMMEDIATELY NEWER* than the given min status ID.
 //     The status with the specified ID will not be included in the response.
 //   in: query
 //   required: false
@@ -67,7 +67,7 @@ import (
 // - name: min_id
 //   type: string
 //   description: |-
-//     Return only statuses *NEWER* than the given since status ID.
+//     Return only statuses *IMMEDIATELY NEWER* than the given min status ID.
 //     The status with the specified ID will not be included in the response.
 //   in: query
 //   required: false
@@ -83,6 +83,18 @@ import (
 //   default: false
 //   in: query
 //   required: false
+// - name: remote
+//   type: boolean
+//   description: Show only statuses posted by remote accounts.
+//   default: false
+//   in: query
+//   required: false
+// - name: only_media
+//   type: boolean
+//   description: Show only statuses with media attachments.
+//   default: false
+//   in: query
+//   required: false
 //
 // security:
 // - OAuth2 Bearer:
@@ -161,7 +173,31 @@ func (m *Module) PublicTimelineGETHandler(c *gin.Context) {
 		local = i
 	}
 
-	resp, errWithCode := m.processor.PublicTimelineGet(c.Request.Context(), authed, maxID, sinceID, minID, limit, local)
+	remote := false
+	remoteString := c.Query(RemoteKey)
+	if remoteString != "" {
+		i, err := strconv.ParseBool(remoteString)
+		if err != nil {
+			l.Debugf("error parsing remote string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse remote query param"})
+			return
+		}
+		remote = i
+	}
+
+	onlyMedia := false
+	onlyMediaString := c.Query(OnlyMediaKey)
+	if onlyMediaString != "" {
+		i, err := strconv.ParseBool(onlyMediaString)
+		if err != nil {
+			l.Debugf("error parsing only media string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse only_media query param"})
+			return
+		}
+		onlyMedia = i
+	}
+
+	resp, errWithCode := m.processor.PublicTimelineGet(c.Request.Context(), authed, maxID, sinceID, minID, limit, local, remote, onlyMedia)
 	if errWithCode != nil {
 		l.Debugf("error from processor PublicTimelineGet: %s", errWithCode)
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
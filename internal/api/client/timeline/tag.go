@@ -0,0 +1,195 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package timeline
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TagTimelineGETHandler swagger:operation GET /api/v1/timelines/tag/{hashtag} tagTimeline
+//
+// See public statuses/posts that use the given hashtag.
+//
+// The any[], all[] and none[] parameters can be used to build a timeline of several hashtags at once.
+// Statuses are included if they use the given hashtag or any of the hashtags in any[], as well as all of the hashtags in all[], and none of the hashtags in none[].
+//
+// The statuses will be returned in descending chronological order (newest first), with sequential IDs (bigger = newer).
+//
+// The returned Link header can be used to generate the previous and next queries when scrolling up or down a timeline.
+//
+// Example:
+//
+// ```
+// <https://example.org/api/v1/timelines/tag/cats?limit=20&max_id=01FC3GSQ8A3MMJ43BPZSGEG29M>; rel="next", <https://example.org/api/v1/timelines/tag/cats?limit=20&min_id=01FC3KJW2GYXSDDRA6RWNDM46M>; rel="prev"
+// ```
+//
+// ---
+// tags:
+// - timelines
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: hashtag
+//   type: string
+//   description: Name of the hashtag, with or without the leading hash.
+//   in: path
+//   required: true
+// - name: any[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Also include statuses that use any of these hashtags instead.
+//   in: query
+//   required: false
+// - name: all[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Only include statuses that use all of these hashtags too.
+//   in: query
+//   required: false
+// - name: none[]
+//   type: array
+//   items:
+//     type: string
+//   collectionFormat: multi
+//   description: Leave out statuses that use any of these hashtags.
+//   in: query
+//   required: false
+// - name: max_id
+//   type: string
+//   description: |-
+//     Return only statuses *OLDER* than the given max status ID.
+//     The status with the specified ID will not be included in the response.
+//   in: query
+//   required: false
+// - name: since_id
+//   type: string
+//   description: |-
+//     Return only statuses *NEWER* than the given since status ID.
+//     The status with the specified ID will not be included in the response.
+//   in: query
+// - name: min_id
+//   type: string
+//   description: |-
+//     Return only statuses *IMMEDIATELY NEWER* than the given min status ID.
+//     The status with the specified ID will not be included in the response.
+//   in: query
+//   required: false
+// - name: limit
+//   type: integer
+//   description: Number of statuses to return.
+//   default: 20
+//   in: query
+//   required: false
+// - name: local
+//   type: boolean
+//   description: Show only statuses posted by local accounts.
+//   default: false
+//   in: query
+//   required: false
+//
+// security:
+// - OAuth2 Bearer:
+//   - read:statuses
+//
+// responses:
+//   '200':
+//     name: statuses
+//     description: Array of statuses.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/status"
+//     headers:
+//       Link:
+//         type: string
+//         description: Links to the next and previous queries.
+//   '401':
+//      description: unauthorized
+//   '400':
+//      description: bad request
+func (m *Module) TagTimelineGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "TagTimelineGETHandler")
+
+	authed, err := oauth.Authed(c, true, true, true, true)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashtag := c.Param(HashtagKey)
+	if hashtag == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no hashtag provided"})
+		return
+	}
+
+	limit := 20
+	limitString := c.Query(LimitKey)
+	if limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			l.Debugf("error parsing limit string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse limit query param"})
+			return
+		}
+		limit = int(i)
+	}
+
+	local := false
+	localString := c.Query(LocalKey)
+	if localString != "" {
+		i, err := strconv.ParseBool(localString)
+		if err != nil {
+			l.Debugf("error parsing local string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse local query param"})
+			return
+		}
+		local = i
+	}
+
+	resp, errWithCode := m.processor.TagTimelineGet(c.Request.Context(), authed, hashtag, c.QueryArray(AnyKey), c.QueryArray(AllKey), c.QueryArray(NoneKey), c.Query(MaxIDKey), c.Query(SinceIDKey), c.Query(MinIDKey), limit, local)
+	if errWithCode != nil {
+		l.Debugf("error from processor TagTimelineGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	if resp.LinkHeader != "" {
+		c.Header("Link", resp.LinkHeader)
+	}
+	c.JSON(http.StatusOK, resp.Statuses)
+}
@@ -33,6 +33,8 @@ const (
 	HomeTimeline = BasePath + "/home"
 	// PublicTimeline is the path for the public (and public local) timeline
 	PublicTimeline = BasePath + "/public"
+	// TagTimeline is the path for the timeline of a hashtag
+	TagTimeline = BasePath + "/tag/:" + HashtagKey
 	// MaxIDKey is the url query for setting a max status ID to return
 	MaxIDKey = "max_id"
 	// SinceIDKey is the url query for returning results newer than the given ID
@@ -43,6 +45,18 @@ const (
 	LimitKey = "limit"
 	// LocalKey is for specifying whether only local statuses should be returned
 	LocalKey = "local"
+	// RemoteKey is for specifying whether only remote statuses should be returned
+	RemoteKey = "remote"
+	// OnlyMediaKey is for specifying whether only statuses with media attachments should be returned
+	OnlyMediaKey = "only_media"
+	// HashtagKey is the url path key for the hashtag whose timeline is being viewed
+	HashtagKey = "hashtag"
+	// AnyKey is the url query for additional hashtags that statuses may use instead of the timeline's hashtag
+	AnyKey = "any[]"
+	// AllKey is the url query for hashtags that statuses must all use as well as the timeline's hashtag
+	AllKey = "all[]"
+	// NoneKey is the url query for hashtags that statuses must not use
+	NoneKey = "none[]"
 )
 
 // Module implements the ClientAPIModule interface for everything relating to viewing timelines
@@ -61,5 +75,6 @@ func New(processor processing.Processor) api.ClientModule {
 func (m *Module) Route(r router.Router) error {
 	r.AttachHandler(http.MethodGet, HomeTimeline, m.HomeTimelineGETHandler)
 	r.AttachHandler(http.MethodGet, PublicTimeline, m.PublicTimelineGETHandler)
+	r.AttachHandler(http.MethodGet, TagTimeline, m.TagTimelineGETHandler)
 	return nil
 }
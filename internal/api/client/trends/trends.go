@@ -0,0 +1,100 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package trends
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+const (
+	// BasePath is the base path for serving trends, which on its own serves trending hashtags, as mastodon does
+	BasePath = "/api/v1/trends"
+	// TagsPath is for serving trending hashtags
+	TagsPath = BasePath + "/tags"
+	// StatusesPath is for serving trending statuses
+	StatusesPath = BasePath + "/statuses"
+	// LinksPath is for serving trending links
+	LinksPath = BasePath + "/links"
+
+	// LimitKey is for specifying the maximum number of results to return.
+	LimitKey = "limit"
+	// OffsetKey is for specifying how many results to skip, for paging.
+	OffsetKey = "offset"
+)
+
+// Module implements the ClientAPIModule interface for everything relating to trends
+type Module struct {
+	processor processing.Processor
+}
+
+// New returns a new trends module
+func New(processor processing.Processor) api.ClientModule {
+	return &Module{
+		processor: processor,
+	}
+}
+
+// Route attaches all routes from this module to the given router
+func (m *Module) Route(r router.Router) error {
+	r.AttachHandler(http.MethodGet, BasePath, m.TrendsTagsGETHandler)
+	r.AttachHandler(http.MethodGet, TagsPath, m.TrendsTagsGETHandler)
+	r.AttachHandler(http.MethodGet, StatusesPath, m.TrendsStatusesGETHandler)
+	r.AttachHandler(http.MethodGet, LinksPath, m.TrendsLinksGETHandler)
+	return nil
+}
+
+// parseLimitOffset parses the limit and offset query params of the given request,
+// keeping the limit between 1 and maxLimit.
+func parseLimitOffset(c *gin.Context, defaultLimit int, maxLimit int) (int, int, error) {
+	limit := defaultLimit
+	if limitString := c.Query(LimitKey); limitString != "" {
+		i, err := strconv.ParseInt(limitString, 10, 64)
+		if err != nil {
+			return 0, 0, errors.New("couldn't parse limit query param")
+		}
+		limit = int(i)
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	offset := 0
+	if offsetString := c.Query(OffsetKey); offsetString != "" {
+		i, err := strconv.ParseInt(offsetString, 10, 64)
+		if err != nil {
+			return 0, 0, errors.New("couldn't parse offset query param")
+		}
+		offset = int(i)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset, nil
+}
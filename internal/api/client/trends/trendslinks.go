@@ -0,0 +1,99 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package trends
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// TrendsLinksGETHandler swagger:operation GET /api/v1/trends/links trendsLinksGet
+//
+// Get links that are trending on this instance, most trending first.
+//
+// ---
+// tags:
+// - trends
+//
+// produces:
+// - application/json
+//
+// parameters:
+// - name: limit
+//   type: integer
+//   description: Number of links to return.
+//   default: 10
+//   maximum: 20
+//   in: query
+//   required: false
+// - name: offset
+//   type: integer
+//   description: Skip this many links, for paging.
+//   default: 0
+//   in: query
+//   required: false
+//
+// responses:
+//   '200':
+//     description: Array of trending links, with their usage on each of the last few days.
+//     schema:
+//       type: array
+//       items:
+//         "$ref": "#/definitions/trendsLink"
+//   '400':
+//      description: bad request
+//   '406':
+//      description: not acceptable
+//   '500':
+//      description: internal error
+func (m *Module) TrendsLinksGETHandler(c *gin.Context) {
+	l := logrus.WithField("func", "TrendsLinksGETHandler")
+
+	authed, err := oauth.Authed(c, false, false, false, false)
+	if err != nil {
+		l.Debugf("error authing: %s", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if _, err := api.NegotiateAccept(c, api.JSONAcceptHeaders...); err != nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, offset, err := parseLimitOffset(c, 10, 20)
+	if err != nil {
+		l.Debugf("error parsing query params: %s", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	links, errWithCode := m.processor.TrendsLinksGet(c.Request.Context(), authed, limit, offset)
+	if errWithCode != nil {
+		l.Debugf("error from processor TrendsLinksGet: %s", errWithCode)
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
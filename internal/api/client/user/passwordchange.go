@@ -49,7 +49,7 @@ import (
 //
 // security:
 // - OAuth2 Bearer:
-//   - write:user
+//   - write:accounts
 //
 // responses:
 //   '200':
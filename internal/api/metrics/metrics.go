@@ -0,0 +1,50 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/router"
+)
+
+// MetricsPath is the path for serving prometheus metrics.
+const MetricsPath = "/metrics"
+
+// Module implements the ClientModule interface
+type Module struct {
+	handler http.Handler
+}
+
+// New returns a new metrics module, which serves
+// metrics from the default prometheus gatherer.
+func New() api.ClientModule {
+	return &Module{
+		handler: promhttp.Handler(),
+	}
+}
+
+// Route satisfies the ClientModule interface
+func (m *Module) Route(s router.Router) error {
+	s.AttachHandler(http.MethodGet, MetricsPath, gin.WrapH(m.handler))
+	return nil
+}
@@ -48,6 +48,8 @@ type Account struct {
 	Discoverable bool `json:"discoverable,omitempty"`
 	// Account identifies as a bot.
 	Bot bool `json:"bot"`
+	// Account is a group, such as a Lemmy community, that passes on the posts of its members to its followers.
+	Group bool `json:"group"`
 	// When the account was created (ISO 8601 Datetime).
 	// example: 2021-07-30T09:20:25+00:00
 	CreatedAt string `json:"created_at"`
@@ -85,11 +87,16 @@ type Account struct {
 	Fields []Field `json:"fields"`
 	// Account has been suspended by our instance.
 	Suspended bool `json:"suspended,omitempty"`
+	// Account is on a domain that has been silenced by our instance,
+	// so its posts are kept out of public timelines.
+	Limited bool `json:"limited,omitempty"`
 	// If this account has been muted, when will the mute expire (ISO 8601 Datetime).
 	// example: 2021-07-30T09:20:25+00:00
 	MuteExpiresAt string `json:"mute_expires_at,omitempty"`
 	// Extra profile information. Shown only if the requester owns the account being requested.
 	Source *Source `json:"source,omitempty"`
+	// The account that this account has moved to, if it has moved.
+	Moved *Account `json:"moved,omitempty"`
 }
 
 // AccountCreateRequest models account creation parameters.
@@ -151,6 +158,10 @@ type UpdateCredentialsRequest struct {
 	Source *UpdateSource `form:"source" json:"source" xml:"source"`
 	// Profile metadata name and value
 	FieldsAttributes *[]UpdateField `form:"fields_attributes" json:"fields_attributes" xml:"fields_attributes"`
+	// Remove the avatar image, so that the default is shown instead. Set when the avatar is sent as an empty value rather than a file.
+	DeleteAvatar bool `form:"-" json:"-" xml:"-"`
+	// Remove the header image, so that the default is shown instead. Set when the header is sent as an empty value rather than a file.
+	DeleteHeader bool `form:"-" json:"-" xml:"-"`
 }
 
 // UpdateSource is to be used specifically in an UpdateCredentialsRequest.
@@ -163,6 +174,12 @@ type UpdateSource struct {
 	Sensitive *bool `form:"sensitive" json:"sensitive" xml:"sensitive"`
 	// Default language to use for authored statuses. (ISO 6391)
 	Language *string `form:"language" json:"language" xml:"language"`
+	// Types of notification that shouldn't be sent to the account. Replaces any types that were excluded before.
+	ExcludedNotifications *[]string `form:"excluded_notifications" json:"excluded_notifications" xml:"excluded_notifications"`
+	// How media attachments should be shown by default: default, show_all or hide_all.
+	ExpandMedia *string `form:"expand_media" json:"expand_media" xml:"expand_media"`
+	// Expand content warnings by default.
+	ExpandSpoilers *bool `form:"expand_spoilers" json:"expand_spoilers" xml:"expand_spoilers"`
 }
 
 // UpdateField is to be used specifically in an UpdateCredentialsRequest.
@@ -188,6 +205,16 @@ type AccountFollowRequest struct {
 	Notify *bool `form:"notify" json:"notify" xml:"notify"`
 }
 
+// AccountMuteRequest models a request to mute an account.
+//
+// swagger:ignore
+type AccountMuteRequest struct {
+	// Mute notifications from this account as well as its posts. Defaults to true.
+	Notifications *bool `form:"notifications" json:"notifications" xml:"notifications"`
+	// How long the mute should last, in seconds. 0 means the mute lasts until it's removed.
+	Duration int `form:"duration" json:"duration" xml:"duration"`
+}
+
 // AccountDeleteRequest models a request to delete an account.
 //
 // swagger:ignore
@@ -198,3 +225,32 @@ type AccountDeleteRequest struct {
 	// Can be the ID of the account owner, or the ID of an admin account.
 	DeleteOriginID string `form:"-" json:"-" xml:"-"`
 }
+
+// AccountMoveRequest models a request to move an account to another account.
+//
+// swagger:ignore
+type AccountMoveRequest struct {
+	// Password of the account's user, for confirmation.
+	Password string `form:"password" json:"password" xml:"password"`
+	// ActivityPub URI of the account to move to.
+	MovedToURI string `form:"moved_to_uri" json:"moved_to_uri" xml:"moved_to_uri"`
+}
+
+// AccountAliases models the aliases of an account, ie., the other accounts that it's also known as.
+//
+// swagger:model accountAliases
+type AccountAliases struct {
+	// ActivityPub URIs of the accounts that this account is also known as.
+	// An account can only be moved to another account if that account lists it here.
+	// example: ["https://example.org/users/some_user"]
+	AlsoKnownAsURIs []string `json:"also_known_as_uris"`
+}
+
+// AccountAliasesRequest models a request to set the aliases of an account.
+//
+// swagger:ignore
+type AccountAliasesRequest struct {
+	// ActivityPub URIs of the accounts that this account is also known as.
+	// Any aliases that the account had before are replaced.
+	AlsoKnownAsURIs []string `form:"also_known_as_uris[]" json:"also_known_as_uris" xml:"also_known_as_uris"`
+}
@@ -57,16 +57,35 @@ type AdminAccountInfo struct {
 }
 
 // AdminReportInfo models the admin view of a report.
+//
+// swagger:model adminReport
 type AdminReportInfo struct {
 	// The ID of the report in the database.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
 	ID string `json:"id"`
-	// The action taken to resolve this report.
-	ActionTaken string `json:"action_taken"`
+	// Has a moderator resolved the report yet?
+	// example: false
+	ActionTaken bool `json:"action_taken"`
+	// When a moderator resolved the report, if they have (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	ActionTakenAt *string `json:"action_taken_at"`
+	// What the moderator who resolved the report did about it.
+	// example: suspended the account
+	ActionTakenComment string `json:"action_taken_comment"`
+	// What kind of problem the report is about: spam, violation or other.
+	// example: spam
+	Category string `json:"category"`
 	// An optional reason for reporting.
+	// example: this account posts nothing but ads
 	Comment string `json:"comment"`
+	// Was the report forwarded to the instance of the reported account?
+	// example: false
+	Forwarded bool `json:"forwarded"`
 	// The time the report was filed. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:25+00:00
 	CreatedAt string `json:"created_at"`
 	// The time of last action on this report. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:25+00:00
 	UpdatedAt string `json:"updated_at"`
 	// The account which filed the report.
 	Account *Account `json:"account"`
@@ -74,10 +93,22 @@ type AdminReportInfo struct {
 	TargetAccount *Account `json:"target_account"`
 	// The account of the moderator assigned to this report.
 	AssignedAccount *Account `json:"assigned_account"`
-	// The action taken by the moderator who handled the report.
-	ActionTakenByAccount string `json:"action_taken_by_account"`
+	// The account of the moderator who resolved the report.
+	ActionTakenByAccount *Account `json:"action_taken_by_account"`
 	// Statuses attached to the report, for context.
 	Statuses []Status `json:"statuses"`
+	// Rules of this instance that the reported account is said to have broken.
+	Rules []InstanceRule `json:"rules"`
+}
+
+// AdminReportResolveRequest models a request to mark a report as resolved.
+//
+// swagger:ignore
+type AdminReportResolveRequest struct {
+	// What the moderator did about the report.
+	ActionTakenComment string `form:"action_taken_comment" json:"action_taken_comment" xml:"action_taken_comment"`
+	// Forward the report to the instance of the reported account as a flag, if it's remote and the report hasn't been forwarded already.
+	Forward bool `form:"forward" json:"forward" xml:"forward"`
 }
 
 // AdminAccountActionRequest models the admin view of an account's details.
@@ -91,3 +122,246 @@ type AdminAccountActionRequest struct {
 	// ID of the account to be acted on.
 	TargetAccountID string `form:"-" json:"-" xml:"-"`
 }
+
+// AdminMediaRecacheRequest models a request to recache remote media that has been removed from the cache.
+//
+// swagger:ignore
+type AdminMediaRecacheRequest struct {
+	// Only recache media belonging to the account with this ID.
+	AccountID string `form:"account_id" json:"account_id" xml:"account_id"`
+	// Only recache media belonging to accounts on this domain.
+	Domain string `form:"domain" json:"domain" xml:"domain"`
+}
+
+// AdminMediaIntegrityReport models the results of the last check of stored media for missing or corrupted files.
+//
+// swagger:model adminMediaIntegrityReport
+type AdminMediaIntegrityReport struct {
+	// When the check began. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:25+00:00
+	StartedAt string `json:"started_at"`
+	// When the check ended. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:31+00:00
+	FinishedAt string `json:"finished_at"`
+	// Number of attachments that were checked.
+	// example: 20
+	Checked int `json:"checked"`
+	// Number of attachments that didn't have checksums stored yet, and had them filled in.
+	// example: 3
+	Backfilled int `json:"backfilled"`
+	// IDs of attachments that had files missing from storage.
+	Missing []string `json:"missing"`
+	// IDs of attachments that had files that didn't match their checksums.
+	Corrupted []string `json:"corrupted"`
+	// IDs of remote attachments that were queued to be fetched again.
+	Requeued []string `json:"requeued"`
+	// Number of emojis that were checked.
+	// example: 20
+	CheckedEmojis int `json:"checked_emojis"`
+	// Number of emojis that didn't have checksums stored yet, and had them filled in.
+	// example: 3
+	BackfilledEmojis int `json:"backfilled_emojis"`
+	// IDs of emojis that had files missing from storage.
+	MissingEmojis []string `json:"missing_emojis"`
+	// IDs of emojis that had files that didn't match their checksums.
+	CorruptedEmojis []string `json:"corrupted_emojis"`
+	// IDs of remote emojis that were queued to be fetched again.
+	RequeuedEmojis []string `json:"requeued_emojis"`
+}
+
+// AdminMediaStats models the current state of media processing and storage on this instance.
+//
+// swagger:model adminMediaStats
+type AdminMediaStats struct {
+	// Number of workers available for processing attachments.
+	// example: 4
+	NumWorkers int `json:"num_workers"`
+	// Total capacity of the attachment processing queue.
+	// example: 40
+	QueueSize int `json:"queue_size"`
+	// Number of attachments currently waiting in the processing queue.
+	// example: 2
+	JobsQueued int `json:"jobs_queued"`
+	// Number of workers currently processing attachments.
+	// example: 1
+	ActiveWorkers int `json:"active_workers"`
+	// Number of workers available for processing emoji.
+	// example: 2
+	EmojiNumWorkers int `json:"emoji_num_workers"`
+	// Total capacity of the emoji processing queue.
+	// example: 20
+	EmojiQueueSize int `json:"emoji_queue_size"`
+	// Number of emoji currently waiting in the processing queue.
+	// example: 0
+	EmojiJobsQueued int `json:"emoji_jobs_queued"`
+	// Number of workers currently processing emoji.
+	// example: 0
+	EmojiActiveWorkers int `json:"emoji_active_workers"`
+	// Number of bytes of local media, including thumbnails, currently in storage.
+	// example: 104857600
+	LocalBytesStored int `json:"local_bytes_stored"`
+	// Number of bytes of cached remote media, including thumbnails, currently in storage.
+	// example: 524288000
+	RemoteBytesStored int `json:"remote_bytes_stored"`
+	// Results of the last time remote media was pruned from the cache, or null if it hasn't been pruned yet.
+	LastPrune *AdminMediaPruneReport `json:"last_prune"`
+}
+
+// AdminMediaPruneReport models the results of pruning remote media from the cache.
+//
+// swagger:model adminMediaPruneReport
+type AdminMediaPruneReport struct {
+	// When pruning began. (ISO 8601 Datetime)
+	// example: 2021-07-30T00:00:00+00:00
+	StartedAt string `json:"started_at"`
+	// When pruning ended. (ISO 8601 Datetime)
+	// example: 2021-07-30T00:00:42+00:00
+	FinishedAt string `json:"finished_at"`
+	// Number of attachments that were removed from the cache.
+	// example: 120
+	Pruned int `json:"pruned"`
+	// Number of bytes of media, including thumbnails, that were removed from storage.
+	// example: 52428800
+	PrunedBytes int `json:"pruned_bytes"`
+}
+
+// AdminMediaRecacheResponse models the response to a request to recache remote media.
+//
+// swagger:model adminMediaRecacheResponse
+type AdminMediaRecacheResponse struct {
+	// Number of attachments that have been queued to be recached.
+	// example: 42
+	Queued int `json:"queued"`
+}
+
+// AdminDeliveryStats models the state of deliveries of activities to other instances that failed, and are being retried.
+//
+// swagger:model adminDeliveryStats
+type AdminDeliveryStats struct {
+	// Number of failed deliveries that are waiting to be tried again.
+	// example: 12
+	Pending int `json:"pending"`
+	// Number of deliveries that were given up on after failing for too long, in the last week.
+	// example: 3
+	Failed int `json:"failed"`
+}
+
+// AdminWeeklyMeasures models how much happened on this instance during one week.
+//
+// swagger:model adminWeeklyMeasures
+type AdminWeeklyMeasures struct {
+	// Start of the week, at midnight UTC on a Monday. (ISO 8601 Datetime)
+	// example: 2021-07-26T00:00:00+00:00
+	StartsAt string `json:"starts_at"`
+	// Number of local accounts that signed up during the week.
+	// example: 5
+	NewUsers int `json:"new_users"`
+	// Number of statuses posted by local accounts during the week.
+	// example: 420
+	Statuses int `json:"statuses"`
+	// Number of different local accounts that signed in to an app during the week.
+	// example: 31
+	Logins int `json:"logins"`
+}
+
+// AdminDimensions models the current state of storage and queues on this instance, and where its traffic comes from.
+//
+// swagger:model adminDimensions
+type AdminDimensions struct {
+	// Number of bytes of local media, including thumbnails, currently in storage.
+	// example: 104857600
+	LocalBytesStored int `json:"local_bytes_stored"`
+	// Number of bytes of cached remote media, including thumbnails, currently in storage.
+	// example: 524288000
+	RemoteBytesStored int `json:"remote_bytes_stored"`
+	// Number of attachments currently waiting to be processed.
+	// example: 2
+	MediaJobsQueued int `json:"media_jobs_queued"`
+	// Number of emoji currently waiting to be processed.
+	// example: 0
+	EmojiJobsQueued int `json:"emoji_jobs_queued"`
+	// Remote domains that the most statuses were received from in the last week, busiest first.
+	TopRemoteDomains []AdminDomainTraffic `json:"top_remote_domains"`
+}
+
+// AdminDomainTraffic models how many statuses were received from one remote domain.
+//
+// swagger:model adminDomainTraffic
+type AdminDomainTraffic struct {
+	// The remote domain.
+	// example: example.org
+	Domain string `json:"domain"`
+	// Number of statuses received from the domain.
+	// example: 1337
+	Statuses int `json:"statuses"`
+}
+
+// AdminDelivery models a delivery of an activity to another instance that failed, and is being tried again or was given up on.
+//
+// swagger:model adminDelivery
+type AdminDelivery struct {
+	// The id of the delivery.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// The domain that the activity is being delivered to.
+	// example: example.org
+	Domain string `json:"domain"`
+	// The inbox that the activity is being delivered to.
+	// example: https://example.org/users/some_user/inbox
+	Inbox string `json:"inbox"`
+	// The type of the activity being delivered.
+	// example: Create
+	ActivityType string `json:"activity_type,omitempty"`
+	// Number of times delivery has been tried so far.
+	// example: 4
+	Attempts int `json:"attempts"`
+	// The error from the most recent attempt.
+	// example: POST request to https://example.org/users/some_user/inbox failed (502): 502 Bad Gateway
+	LastError string `json:"last_error"`
+	// When delivery was last tried (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	LastAttemptAt string `json:"last_attempt_at"`
+	// When delivery will be tried again (ISO 8601 Datetime), if it hasn't been given up on.
+	// example: 2021-07-30T10:20:25+00:00
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+	// When delivery was given up on (ISO 8601 Datetime), if it has been.
+	// example: 2021-08-02T09:20:25+00:00
+	FailedAt string `json:"failed_at,omitempty"`
+}
+
+// AdminUnreachableDomain models a remote domain that deliveries of activities have been failing to.
+//
+// swagger:model adminUnreachableDomain
+type AdminUnreachableDomain struct {
+	// The id of the unreachable domain.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// The domain that deliveries have been failing to.
+	// example: example.org
+	Domain string `json:"domain"`
+	// When deliveries to the domain started failing (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	FailingSince string `json:"failing_since"`
+	// Number of deliveries in a row that have failed.
+	// example: 15
+	Failures int `json:"failures"`
+	// The error from the most recent failed delivery.
+	// example: POST request to https://example.org/inbox failed (502): 502 Bad Gateway
+	LastError string `json:"last_error"`
+	// When deliveries to the domain were suspended (ISO 8601 Datetime), if they have been.
+	// While deliveries are suspended, nothing is sent to the domain.
+	// example: 2021-08-06T09:20:25+00:00
+	SuspendedAt string `json:"suspended_at,omitempty"`
+	// Whether deliveries to the domain were suspended by an admin, rather than because they kept failing.
+	// If they were, they stay suspended until an admin resumes them.
+	// example: false
+	SuspendedByAdmin bool `json:"suspended_by_admin"`
+}
+
+// AdminUnreachableDomainCreateRequest models a request to suspend deliveries to a remote domain.
+//
+// swagger:ignore
+type AdminUnreachableDomainCreateRequest struct {
+	// The domain to suspend deliveries to, eg. 'example.org'.
+	Domain string `form:"domain" json:"domain" xml:"domain"`
+}
@@ -85,6 +85,9 @@ type Attachment struct {
 	TextURL string `json:"text_url,omitempty"`
 	// Metadata for this attachment.
 	Meta MediaMeta `json:"meta,omitempty"`
+	// Additional scaled-down versions of the attachment, keyed by name.
+	// Only defined if the instance admin has configured thumbnail variants.
+	Variants map[string]MediaVariant `json:"variants,omitempty"`
 	// Alt text that describes what is in the media attachment.
 	// example: This is a picture of a kitten.
 	Description string `json:"description,omitempty"`
@@ -134,6 +137,17 @@ type MediaMeta struct {
 	Focus MediaFocus `json:"focus,omitempty"`
 }
 
+// MediaVariant models an additional scaled-down version of a piece of media.
+//
+// swagger:model mediaVariant
+type MediaVariant struct {
+	// The location of this version of the attachment.
+	// example: https://example.org/fileserver/some_id/attachments/some_id/medium/attachment.jpeg
+	URL string `json:"url"`
+	// Dimensions of this version of the attachment.
+	Meta MediaDimensions `json:"meta"`
+}
+
 // MediaFocus models the focal point of a piece of media.
 //
 // swagger:model mediaFocus
@@ -18,7 +18,10 @@
 
 package model
 
-import "io"
+import (
+	"io"
+	"net/url"
+)
 
 // Content wraps everything needed to serve a blob of content (some kind of media) through the API.
 type Content struct {
@@ -26,8 +29,23 @@ type Content struct {
 	ContentType string
 	// ContentLength in bytes
 	ContentLength int64
-	// Actual content
+	// Actual content. If this is also an io.ReadSeeker,
+	// then parts of it can be served for range requests.
 	Content io.Reader
+	// URL points to where the content can be fetched from directly, if
+	// it's stored somewhere external to the instance. If URL is set, then
+	// Content will be nil and the caller should be redirected to URL instead.
+	URL *url.URL
+	// Pending is true if the content isn't available yet because it's still
+	// being fetched. If Pending is true, then Content will be nil and the
+	// caller should be told to try again later.
+	Pending bool
+	// Download is true if the content is of a type that we couldn't process, and
+	// was stored as it is. It should be served as a download, and never rendered
+	// by the browser, since we don't know what it might contain.
+	Download bool
+	// Filename is the name that the content should be saved under, if it's served as a download.
+	Filename string
 }
 
 // GetContentRequestForm describes a piece of content desired by the caller of the fileserver API.
@@ -35,6 +35,17 @@ type DomainBlock struct {
 	// A useful anti-harassment tool.
 	// example: false
 	Obfuscate bool `json:"obfuscate,omitempty"`
+	// The block also covers all subdomains of the blocked domain.
+	// example: false
+	IncludeSubdomains bool `json:"include_subdomains,omitempty"`
+	// How severe the block is. Either 'suspend', which means nothing from the domain is accepted,
+	// or 'silence', which means the domain's posts are accepted but kept out of public timelines,
+	// follows from its accounts always need approval, and its media isn't cached.
+	// example: suspend
+	Severity string `json:"severity,omitempty"`
+	// Media from the blocked domain is never cached, even if the domain isn't silenced.
+	// example: false
+	RejectMedia bool `json:"reject_media,omitempty"`
 	// Private comment for this block, visible to our instance admins only.
 	// example: they are poopoo
 	PrivateComment string `json:"private_comment,omitempty"`
@@ -62,8 +73,31 @@ type DomainBlockCreateRequest struct {
 	Domain string `form:"domain" json:"domain" xml:"domain"`
 	// whether the domain should be obfuscated when being displayed publicly
 	Obfuscate bool `form:"obfuscate" json:"obfuscate" xml:"obfuscate"`
+	// whether the block should also cover all subdomains of the domain
+	IncludeSubdomains bool `form:"include_subdomains" json:"include_subdomains" xml:"include_subdomains"`
+	// how severe the block should be: 'suspend' (the default) or 'silence'
+	Severity string `form:"severity" json:"severity" xml:"severity"`
+	// whether media from the domain should never be cached, even if the domain isn't silenced
+	RejectMedia bool `form:"reject_media" json:"reject_media" xml:"reject_media"`
 	// private comment for other admins on why the domain was blocked
 	PrivateComment string `form:"private_comment" json:"private_comment" xml:"private_comment"`
 	// public comment on the reason for the domain block
 	PublicComment string `form:"public_comment" json:"public_comment" xml:"public_comment"`
 }
+
+// DomainBlockUpdateRequest is the form submitted as a PUT to /api/v1/admin/domain_blocks/{id} to change a block.
+// Fields that aren't set are left as they are.
+//
+// swagger:ignore
+type DomainBlockUpdateRequest struct {
+	// how severe the block should be: 'suspend' or 'silence'
+	Severity *string `form:"severity" json:"severity" xml:"severity"`
+	// whether media from the domain should never be cached, even if the domain isn't silenced
+	RejectMedia *bool `form:"reject_media" json:"reject_media" xml:"reject_media"`
+	// whether the domain should be obfuscated when being displayed publicly
+	Obfuscate *bool `form:"obfuscate" json:"obfuscate" xml:"obfuscate"`
+	// private comment for other admins on why the domain was blocked
+	PrivateComment *string `form:"private_comment" json:"private_comment" xml:"private_comment"`
+	// public comment on the reason for the domain block
+	PublicComment *string `form:"public_comment" json:"public_comment" xml:"public_comment"`
+}
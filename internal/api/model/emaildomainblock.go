@@ -0,0 +1,51 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// AdminEmailDomainBlock represents a block on signing up with email addresses on one domain, or its subdomains, as seen by an admin.
+//
+// swagger:model adminEmailDomainBlock
+type AdminEmailDomainBlock struct {
+	// The ID of the email domain block.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// The blocked email domain.
+	// example: mailinator.com
+	Domain string `json:"domain"`
+	// Time at which this block was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// ID of the account that created this block.
+	// example: 01FBW2758ZB6PBR200YPDDJK4C
+	CreatedBy string `json:"created_by"`
+	// How many times someone has tried to sign up with an email address on the blocked domain.
+	// example: 3
+	Attempts int `json:"attempts"`
+	// When someone last tried to sign up with an email address on the blocked domain, if anyone has (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	LastAttemptAt *string `json:"last_attempt_at"`
+}
+
+// AdminEmailDomainBlockCreateRequest models a request to block signing up with email addresses on a domain.
+//
+// swagger:ignore
+type AdminEmailDomainBlockCreateRequest struct {
+	// The email domain to block, eg. 'mailinator.com'. Subdomains are blocked too.
+	Domain string `form:"domain" json:"domain" xml:"domain"`
+}
@@ -48,6 +48,6 @@ type EmojiCreateRequest struct {
 	// Desired shortcode for the emoji, without surrounding colons. This must be unique for the domain.
 	// example: blobcat_uwu
 	Shortcode string `form:"shortcode" validation:"required"`
-	// Image file to use for the emoji. Must be png or gif and no larger than 50kb.
+	// Image file to use for the emoji. Must be png, gif, or svg and no larger than 50kb.
 	Image *multipart.FileHeader `form:"image" validation:"required"`
 }
@@ -0,0 +1,41 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// EmojiReaction represents all of the reactions to a status that use the same emoji.
+// It's not part of the Mastodon API, but is compatible with the one used by Pleroma.
+//
+// swagger:model emojiReaction
+type EmojiReaction struct {
+	// The unicode emoji used to react, or the :shortcode: of a custom emoji.
+	// example: 👍
+	Name string `json:"name"`
+	// Number of accounts that reacted with this emoji.
+	// example: 3
+	Count int `json:"count"`
+	// The requesting account reacted with this emoji.
+	// example: false
+	Me bool `json:"me"`
+	// Web URL of the custom emoji, if this reaction uses a custom emoji that this instance knows about.
+	// example: https://example.org/fileserver/emojis/blogcat_uwu.gif
+	URL string `json:"url,omitempty"`
+	// Accounts that reacted with this emoji.
+	// Only included when reactions are fetched on their own, not when they're part of a status.
+	Accounts []Account `json:"accounts,omitempty"`
+}
@@ -0,0 +1,67 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// Export represents a file of an account's data, such as the accounts it follows or blocks.
+// Csv export files use the same format as Mastodon, so they can be imported into Mastodon or GoToSocial.
+// Archive exports are zip files of the account's actor, outbox and media, as activitypub json.
+//
+// swagger:model export
+type Export struct {
+	// The ID of the export.
+	// example: 01G2B2PS4B7DAGQ2YGMKC2H5WB
+	ID string `json:"id"`
+	// The data in the export.
+	// enum:
+	// - follows
+	// - followers
+	// - blocks
+	// - mutes
+	// - bookmarks
+	// - archive
+	// example: follows
+	Type string `json:"type"`
+	// Whether the export file is still being generated, is ready to download, or couldn't be generated.
+	// enum:
+	// - pending
+	// - done
+	// - failed
+	// example: done
+	State string `json:"state"`
+	// When the export was requested. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// When the export will be deleted. (ISO 8601 Datetime)
+	// example: 2021-08-06T09:20:25+00:00
+	ExpiresAt string `json:"expires_at"`
+	// The size of the export file in bytes. 0 until the file is ready to download.
+	// example: 2048
+	Size int `json:"size"`
+	// Where the export file can be downloaded. Only set when state is done.
+	// example: https://example.org/api/v1/exports/01G2B2PS4B7DAGQ2YGMKC2H5WB/download
+	URL string `json:"url,omitempty"`
+}
+
+// ExportCreateRequest models a request to export some of one's account data.
+//
+// swagger:ignore
+type ExportCreateRequest struct {
+	// The data to export: follows, followers, blocks, mutes, bookmarks or archive.
+	Type string `form:"type" json:"type" xml:"type"`
+}
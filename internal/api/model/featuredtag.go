@@ -19,15 +19,31 @@
 package model
 
 // FeaturedTag represents a hashtag that is featured on a profile.
+//
+// swagger:model featuredTag
 type FeaturedTag struct {
 	// The internal ID of the featured tag in the database.
+	// example: 01G1TR6BADACCN3D8QMC2J3FJ0
 	ID string `json:"id"`
 	// The name of the hashtag being featured.
+	// example: welcome
 	Name string `json:"name"`
-	// A link to all statuses by a user that contain this hashtag.
+	// A link to all statuses that contain this hashtag.
+	// example: https://example.org/tags/welcome
 	URL string `json:"url"`
 	// The number of authored statuses containing this hashtag.
+	// example: 3
 	StatusesCount int `json:"statuses_count"`
 	// The timestamp of the last authored status containing this hashtag. (ISO 8601 Datetime)
+	// Empty if no authored status contains the hashtag.
+	// example: 2021-07-30T09:20:25+00:00
 	LastStatusAt string `json:"last_status_at"`
 }
+
+// FeaturedTagCreateRequest models a request to feature a hashtag on one's profile.
+//
+// swagger:ignore
+type FeaturedTagCreateRequest struct {
+	// The hashtag to feature, with or without the leading #.
+	Name string `form:"name" json:"name" xml:"name"`
+}
@@ -25,6 +25,11 @@ package model
 // If the phrase starts with a word character, and if the previous character before matched range is a word character, its matched range should be treated to not match.
 // If the phrase ends with a word character, and if the next character after matched range is a word character, its matched range should be treated to not match.
 // Please check app/javascript/mastodon/selectors/index.js and app/lib/feed_manager.rb in the Mastodon source code for more details.
+//
+// Each v1 filter is one keyword of a v2 filter. GoToSocial removes statuses that match a filter with irreversible set
+// before serving them, so clients don't have to apply those filters themselves.
+//
+// swagger:model filter
 type Filter struct {
 	// The ID of the filter in the database.
 	ID string `json:"id"`
@@ -36,6 +41,7 @@ type Filter struct {
 	// 	notifications = notifications timeline
 	// 	public = public timelines
 	// 	thread = expanded thread of a detailed status
+	// 	account = statuses of an account
 	Context []string `json:"context"`
 	// Should the filter consider word boundaries?
 	WholeWord bool `json:"whole_word"`
@@ -44,3 +50,145 @@ type Filter struct {
 	// Should matching entities in home and notifications be dropped by the server?
 	Irreversible bool `json:"irreversible"`
 }
+
+// FilterCreateUpdateRequest models a request to create a filter, or to replace an existing one.
+//
+// swagger:ignore
+type FilterCreateUpdateRequest struct {
+	// The text to be filtered.
+	Phrase string `form:"phrase" json:"phrase" xml:"phrase"`
+	// The contexts in which the filter should be applied: home, notifications, public, thread, or account.
+	Context []string `form:"context[]" json:"context" xml:"context"`
+	// Should the filter consider word boundaries?
+	WholeWord bool `form:"whole_word" json:"whole_word" xml:"whole_word"`
+	// Should clients avoid offering to show filtered statuses?
+	Irreversible bool `form:"irreversible" json:"irreversible" xml:"irreversible"`
+	// Number of seconds from now that the filter should expire. Zero or unset means it doesn't expire.
+	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+}
+
+// FilterV2 represents a group of keywords and statuses that the user doesn't want to see, or wants to be warned about.
+//
+// swagger:model filterV2
+type FilterV2 struct {
+	// The ID of the filter in the database.
+	ID string `json:"id"`
+	// A name for the filter, for the user's own reference.
+	Title string `json:"title"`
+	// The contexts in which the filter should be applied.
+	// Array of String (Enumerable anyOf)
+	// 	home = home timeline and lists
+	// 	notifications = notifications timeline
+	// 	public = public timelines
+	// 	thread = expanded thread of a detailed status
+	// 	account = statuses of an account
+	Context []string `json:"context"`
+	// When the filter should no longer be applied (ISO 8601 Datetime), or null if the filter does not expire
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// What to do with statuses that match the filter.
+	// 	warn = serve the status along with the filter results, so the client can hide it behind a warning
+	// 	hide = don't serve the status at all
+	FilterAction string `json:"filter_action"`
+	// Keywords that the filter matches.
+	Keywords []FilterKeyword `json:"keywords"`
+	// Statuses that the filter matches.
+	Statuses []FilterStatus `json:"statuses"`
+}
+
+// FilterKeyword represents a keyword that a filter matches.
+//
+// swagger:model filterKeyword
+type FilterKeyword struct {
+	// The ID of the keyword in the database.
+	ID string `json:"id"`
+	// The text to be filtered.
+	Keyword string `json:"keyword"`
+	// Should the keyword consider word boundaries?
+	WholeWord bool `json:"whole_word"`
+}
+
+// FilterStatus represents a status that a filter matches.
+//
+// swagger:model filterStatus
+type FilterStatus struct {
+	// The ID of the filter status in the database.
+	ID string `json:"id"`
+	// The ID of the status that the filter matches.
+	StatusID string `json:"status_id"`
+}
+
+// FilterResult describes which parts of a filter a status matched.
+//
+// swagger:model filterResult
+type FilterResult struct {
+	// The filter that was matched.
+	Filter FilterV2 `json:"filter"`
+	// The keywords of the filter that were found in the status.
+	KeywordMatches []string `json:"keyword_matches"`
+	// The ids of statuses of the filter that the status is, or boosts.
+	StatusMatches []string `json:"status_matches"`
+}
+
+// FilterV2CreateRequest models a request to create a v2 filter.
+//
+// swagger:ignore
+type FilterV2CreateRequest struct {
+	// A name for the filter.
+	Title string `form:"title" json:"title" xml:"title"`
+	// The contexts in which the filter should be applied: home, notifications, public, thread, or account.
+	Context []string `form:"context[]" json:"context" xml:"context"`
+	// What to do with statuses that match the filter: warn or hide. Defaults to warn.
+	FilterAction string `form:"filter_action" json:"filter_action" xml:"filter_action"`
+	// Number of seconds from now that the filter should expire. Zero or unset means it doesn't expire.
+	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+	// Keywords to add to the filter. Only accepted in JSON requests.
+	KeywordsAttributes []FilterKeywordAttributes `form:"-" json:"keywords_attributes" xml:"keywords_attributes"`
+}
+
+// FilterV2UpdateRequest models a request to update a v2 filter. Fields that aren't set are left alone.
+//
+// swagger:ignore
+type FilterV2UpdateRequest struct {
+	// A new name for the filter.
+	Title *string `form:"title" json:"title" xml:"title"`
+	// The contexts in which the filter should be applied: home, notifications, public, thread, or account.
+	Context []string `form:"context[]" json:"context" xml:"context"`
+	// What to do with statuses that match the filter: warn or hide.
+	FilterAction *string `form:"filter_action" json:"filter_action" xml:"filter_action"`
+	// Number of seconds from now that the filter should expire. Zero means it doesn't expire.
+	ExpiresIn *int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+	// Keywords to add to, change, or remove from the filter. Only accepted in JSON requests.
+	KeywordsAttributes []FilterKeywordAttributes `form:"-" json:"keywords_attributes" xml:"keywords_attributes"`
+}
+
+// FilterKeywordAttributes models a keyword to add to, change, or remove from a filter, as part of a request to create or update it.
+//
+// swagger:ignore
+type FilterKeywordAttributes struct {
+	// The ID of an existing keyword to change or remove. Leave unset to add a new keyword.
+	ID string `json:"id" xml:"id"`
+	// The text to be filtered.
+	Keyword string `json:"keyword" xml:"keyword"`
+	// Should the keyword consider word boundaries?
+	WholeWord *bool `json:"whole_word" xml:"whole_word"`
+	// Remove the existing keyword with the given ID.
+	Destroy bool `json:"_destroy" xml:"_destroy"`
+}
+
+// FilterKeywordCreateUpdateRequest models a request to add a keyword to a filter, or to replace an existing one.
+//
+// swagger:ignore
+type FilterKeywordCreateUpdateRequest struct {
+	// The text to be filtered.
+	Keyword string `form:"keyword" json:"keyword" xml:"keyword"`
+	// Should the keyword consider word boundaries?
+	WholeWord bool `form:"whole_word" json:"whole_word" xml:"whole_word"`
+}
+
+// FilterStatusCreateRequest models a request to add a status to a filter.
+//
+// swagger:ignore
+type FilterStatusCreateRequest struct {
+	// The ID of the status to filter.
+	StatusID string `form:"status_id" json:"status_id" xml:"status_id"`
+}
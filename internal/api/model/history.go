@@ -18,12 +18,17 @@
 
 package model
 
-// History represents daily usage history of a hashtag.
+// History represents daily usage history of a hashtag or link.
+//
+// swagger:model history
 type History struct {
 	// UNIX timestamp on midnight of the given day (string cast from integer).
+	// example: 1651190400
 	Day string `json:"day"`
 	// The counted usage of the tag within that day (string cast from integer).
+	// example: 12
 	Uses string `json:"uses"`
 	// The total of accounts using the tag within that day (string cast from integer).
+	// example: 9
 	Accounts string `json:"accounts"`
 }
@@ -0,0 +1,66 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import "mime/multipart"
+
+// Import represents a csv file of accounts that's being worked through, to follow, block or mute each of them.
+//
+// swagger:model import
+type Import struct {
+	// The ID of the import.
+	// example: 01G2D1GZ7Z0F6E5ERY5VPEWD5T
+	ID string `json:"id"`
+	// What's being done with the accounts in the file.
+	// enum:
+	// - follows
+	// - blocks
+	// - mutes
+	// example: follows
+	Type string `json:"type"`
+	// Whether the file is still being worked through, has been worked through, or couldn't be worked through at all.
+	// enum:
+	// - pending
+	// - done
+	// - failed
+	// example: pending
+	State string `json:"state"`
+	// When the file was uploaded. (ISO 8601 Datetime)
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// How many accounts are in the file.
+	// example: 120
+	Total int `json:"total"`
+	// How many accounts in the file have been worked through so far, including any that failed.
+	// example: 80
+	Processed int `json:"processed"`
+	// Addresses of accounts in the file that couldn't be found, or couldn't be followed, blocked or muted.
+	// example: ["someone@example.org"]
+	FailedAddresses []string `json:"failed_addresses"`
+}
+
+// ImportCreateRequest models a request to import a csv file of accounts.
+//
+// swagger:ignore
+type ImportCreateRequest struct {
+	// What to do with the accounts in the file: follows, blocks or mutes.
+	Type string `form:"type" json:"type" xml:"type"`
+	// The csv file, in the format that Mastodon exports.
+	Data *multipart.FileHeader `form:"data" json:"data" xml:"data"`
+}
@@ -77,6 +77,98 @@ type Instance struct {
 	//
 	// example: 5000
 	MaxTootChars uint `json:"max_toot_chars"`
+	// Limits that this instance applies to uploaded media and emoji, so that clients can check media before uploading it.
+	Configuration *InstanceConfiguration `json:"configuration,omitempty"`
+	// Rules of this instance, which accounts should be shown when they sign up.
+	Rules []InstanceRule `json:"rules"`
+}
+
+// InstanceConfiguration models limits and other configured values of an instance.
+//
+// swagger:model instanceConfiguration
+type InstanceConfiguration struct {
+	// Limits that apply to statuses.
+	Statuses *InstanceConfigurationStatuses `json:"statuses"`
+	// Limits that apply to polls.
+	Polls *InstanceConfigurationPolls `json:"polls"`
+	// Limits that apply to media attachments.
+	MediaAttachments *InstanceConfigurationMediaAttachments `json:"media_attachments"`
+	// Limits that apply to custom emoji.
+	Emojis *InstanceConfigurationEmojis `json:"emojis"`
+}
+
+// InstanceConfigurationStatuses models limits that an instance applies to statuses.
+//
+// swagger:model instanceConfigurationStatuses
+type InstanceConfigurationStatuses struct {
+	// Maximum allowed length of a status, in characters.
+	// example: 5000
+	MaxCharacters int `json:"max_characters"`
+	// Maximum allowed length of the content warning of a status, in characters.
+	// This is counted separately from the status itself.
+	// example: 100
+	MaxContentWarningCharacters int `json:"max_content_warning_characters"`
+	// Maximum number of media attachments that a status can have.
+	// example: 6
+	MaxMediaAttachments int `json:"max_media_attachments"`
+	// Number of characters that each link in a status counts as, however long it really is.
+	// example: 23
+	CharactersReservedPerURL int `json:"characters_reserved_per_url"`
+}
+
+// InstanceConfigurationPolls models limits that an instance applies to polls.
+//
+// swagger:model instanceConfigurationPolls
+type InstanceConfigurationPolls struct {
+	// Maximum number of options that a poll can have.
+	// example: 6
+	MaxOptions int `json:"max_options"`
+	// Maximum allowed length of each option of a poll, in characters.
+	// example: 50
+	MaxCharactersPerOption int `json:"max_characters_per_option"`
+	// Shortest time that a poll can be open for, in seconds.
+	// example: 300
+	MinExpiration int `json:"min_expiration"`
+	// Longest time that a poll can be open for, in seconds.
+	// example: 2678400
+	MaxExpiration int `json:"max_expiration"`
+}
+
+// InstanceConfigurationMediaAttachments models limits that an instance applies to media attachments.
+//
+// A limit of 0 means that there is no limit.
+//
+// swagger:model instanceConfigurationMediaAttachments
+type InstanceConfigurationMediaAttachments struct {
+	// Maximum size of an image in bytes.
+	// example: 2097152
+	ImageSizeLimit int `json:"image_size_limit"`
+	// Maximum number of pixels of an image (width multiplied by height).
+	// example: 16777216
+	ImageMatrixLimit int `json:"image_matrix_limit"`
+	// Maximum size of a video in bytes.
+	// example: 10485760
+	VideoSizeLimit int `json:"video_size_limit"`
+	// Maximum number of pixels of a video (width multiplied by height).
+	// example: 2304000
+	VideoMatrixLimit int `json:"video_matrix_limit"`
+	// Maximum duration of a video in seconds.
+	// example: 300
+	VideoDurationLimit int `json:"video_duration_limit"`
+}
+
+// InstanceConfigurationEmojis models limits that an instance applies to custom emoji.
+//
+// A limit of 0 means that there is no limit.
+//
+// swagger:model instanceConfigurationEmojis
+type InstanceConfigurationEmojis struct {
+	// Maximum size of an emoji in bytes.
+	// example: 51200
+	EmojiSizeLimit int `json:"emoji_size_limit"`
+	// Maximum number of pixels of an emoji (width multiplied by height). Doesn't apply to svg emoji.
+	// example: 1048576
+	EmojiMatrixLimit int `json:"emoji_matrix_limit"`
 }
 
 // InstanceURLs models instance-relevant URLs for client application consumption.
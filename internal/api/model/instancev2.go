@@ -0,0 +1,120 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// InstanceV2 models information about this instance, as served at /api/v2/instance.
+//
+// swagger:model instanceV2
+type InstanceV2 struct {
+	// The domain name of the instance.
+	// example: example.org
+	Domain string `json:"domain"`
+	// The title of the instance.
+	// example: GoToSocial Example Instance
+	Title string `json:"title"`
+	// The version of GoToSocial installed on the instance.
+	// example: 0.1.1 cb85f65
+	Version string `json:"version"`
+	// The URL of the source code of the software running on the instance.
+	// example: https://github.com/superseriousbusiness/gotosocial
+	SourceURL string `json:"source_url"`
+	// A short description of the instance.
+	//
+	// Should be HTML formatted, but might be plaintext.
+	Description string `json:"description"`
+	// Header image of the instance.
+	Thumbnail InstanceV2Thumbnail `json:"thumbnail"`
+	// Primary languages of the instance.
+	// example: en
+	Languages []string `json:"languages"`
+	// Configured values and limits of the instance.
+	Configuration InstanceV2Configuration `json:"configuration"`
+	// Information about registering on the instance.
+	Registrations InstanceV2Registrations `json:"registrations"`
+	// Hints on how to contact the admins of the instance.
+	Contact InstanceV2Contact `json:"contact"`
+	// Rules of this instance, which accounts should be shown when they sign up.
+	Rules []InstanceRule `json:"rules"`
+}
+
+// InstanceV2Thumbnail models the header image of an instance.
+//
+// swagger:model instanceV2Thumbnail
+type InstanceV2Thumbnail struct {
+	// URL of the header image, empty if the instance doesn't have one.
+	// example: https://example.org/fileserver/01BPSX2MKCRVMD4YN4D71G9CP5/attachment/original/01H88X0KQ2DFYYDSWYP93VDJZA.png
+	URL string `json:"url"`
+}
+
+// InstanceV2Configuration models configured values and limits of an instance.
+//
+// swagger:model instanceV2Configuration
+type InstanceV2Configuration struct {
+	// URLs of interest for client applications.
+	URLs InstanceV2URLs `json:"urls"`
+	// Limits that apply to statuses.
+	Statuses InstanceConfigurationStatuses `json:"statuses"`
+	// Limits that apply to polls.
+	Polls InstanceConfigurationPolls `json:"polls"`
+	// Limits that apply to media attachments.
+	MediaAttachments InstanceConfigurationMediaAttachments `json:"media_attachments"`
+	// Limits that apply to custom emoji.
+	Emojis InstanceConfigurationEmojis `json:"emojis"`
+	// Whether statuses can be translated on this instance.
+	Translation InstanceV2ConfigurationTranslation `json:"translation"`
+}
+
+// InstanceV2URLs models instance-relevant URLs for client application consumption.
+//
+// swagger:model instanceV2URLs
+type InstanceV2URLs struct {
+	// Websockets address for status and notification streaming.
+	// example: wss://example.org
+	Streaming string `json:"streaming"`
+}
+
+// InstanceV2ConfigurationTranslation models whether an instance can translate statuses.
+//
+// swagger:model instanceV2ConfigurationTranslation
+type InstanceV2ConfigurationTranslation struct {
+	// Statuses can be translated with POST /api/v1/statuses/{id}/translate.
+	// example: true
+	Enabled bool `json:"enabled"`
+}
+
+// InstanceV2Registrations models information about registering on an instance.
+//
+// swagger:model instanceV2Registrations
+type InstanceV2Registrations struct {
+	// New account registrations are enabled on this instance.
+	Enabled bool `json:"enabled"`
+	// New account registrations require admin approval.
+	ApprovalRequired bool `json:"approval_required"`
+}
+
+// InstanceV2Contact models hints on how to contact the admins of an instance.
+//
+// swagger:model instanceV2Contact
+type InstanceV2Contact struct {
+	// An email address that may be used for inquiries.
+	// example: admin@example.org
+	Email string `json:"email"`
+	// Contact account for the instance, if one has been set.
+	Account *Account `json:"account"`
+}
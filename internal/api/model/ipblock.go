@@ -0,0 +1,73 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// AdminIPBlock represents a block on requests and/or sign-ups from an IP address or range, as seen by an admin.
+//
+// swagger:model adminIPBlock
+type AdminIPBlock struct {
+	// The ID of the IP block.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// The blocked range of IP addresses, in CIDR notation.
+	// example: 192.0.2.0/24
+	IP string `json:"ip"`
+	// How severe the block is.
+	// Either sign_up_requires_approval, sign_up_block, or no_access.
+	// example: no_access
+	Severity string `json:"severity"`
+	// Private comment on this block, viewable to admins.
+	// example: spam bots
+	Comment string `json:"comment"`
+	// Time at which this block was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// Time at which this block stops applying (ISO 8601 Datetime), or null if it doesn't expire.
+	// example: 2021-07-30T09:20:25+00:00
+	ExpiresAt *string `json:"expires_at"`
+}
+
+// AdminIPBlockCreateRequest models a request to block an IP address or range.
+//
+// swagger:ignore
+type AdminIPBlockCreateRequest struct {
+	// The IP address or range to block, eg. '192.0.2.1' or '192.0.2.0/24'.
+	IP string `form:"ip" json:"ip" xml:"ip"`
+	// How severe the block should be: 'sign_up_requires_approval', 'sign_up_block', or 'no_access'.
+	Severity string `form:"severity" json:"severity" xml:"severity"`
+	// Private comment on this block, viewable to admins.
+	Comment string `form:"comment" json:"comment" xml:"comment"`
+	// Number of seconds from now that the block should expire. 0 means it doesn't expire.
+	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+}
+
+// AdminIPBlockUpdateRequest models a request to change a block on an IP address or range.
+// Fields that aren't set are left as they are.
+//
+// swagger:ignore
+type AdminIPBlockUpdateRequest struct {
+	// The IP address or range to block, eg. '192.0.2.1' or '192.0.2.0/24'.
+	IP *string `form:"ip" json:"ip" xml:"ip"`
+	// How severe the block should be: 'sign_up_requires_approval', 'sign_up_block', or 'no_access'.
+	Severity *string `form:"severity" json:"severity" xml:"severity"`
+	// Private comment on this block, viewable to admins.
+	Comment *string `form:"comment" json:"comment" xml:"comment"`
+	// Number of seconds from now that the block should expire. 0 means it doesn't expire.
+	ExpiresIn *int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+}
@@ -19,19 +19,63 @@
 package model
 
 // Marker represents the last read position within a user's timelines.
+//
+// swagger:model markers
 type Marker struct {
 	// Information about the user's position in the home timeline.
-	Home *TimelineMarker `json:"home"`
+	Home *TimelineMarker `json:"home,omitempty"`
 	// Information about the user's position in their notifications.
-	Notifications *TimelineMarker `json:"notifications"`
+	Notifications *TimelineMarker `json:"notifications,omitempty"`
 }
 
 // TimelineMarker contains information about a user's progress through a specific timeline.
+//
+// swagger:model timelineMarker
 type TimelineMarker struct {
 	// The ID of the most recently viewed entity.
 	LastReadID string `json:"last_read_id"`
 	// The timestamp of when the marker was set (ISO 8601 Datetime)
 	UpdatedAt string `json:"updated_at"`
 	// Used for locking to prevent write conflicts.
-	Version string `json:"version"`
+	Version int `json:"version"`
+}
+
+// MarkerPostRequest models a request to set the markers of one or more timelines.
+// Form requests use the home[last_read_id] and notifications[last_read_id] fields,
+// while JSON requests use nested home and notifications objects.
+//
+// swagger:ignore
+type MarkerPostRequest struct {
+	// Marker to set for the home timeline. Only used in JSON requests.
+	Home *MarkerTimelinePostRequest `form:"-" json:"home" xml:"home"`
+	// Marker to set for notifications. Only used in JSON requests.
+	Notifications *MarkerTimelinePostRequest `form:"-" json:"notifications" xml:"notifications"`
+	// ID of the last read status in the home timeline. Only used in form requests.
+	HomeLastReadID string `form:"home[last_read_id]" json:"-" xml:"-"`
+	// ID of the last read notification. Only used in form requests.
+	NotificationsLastReadID string `form:"notifications[last_read_id]" json:"-" xml:"-"`
+}
+
+// MarkerTimelinePostRequest models the marker to set for one timeline, as part of a MarkerPostRequest.
+//
+// swagger:ignore
+type MarkerTimelinePostRequest struct {
+	// ID of the last read status or notification in the timeline.
+	LastReadID string `json:"last_read_id" xml:"last_read_id"`
+}
+
+// HomeID returns the last read id for the home timeline given in the request, whether it was a form or JSON request.
+func (r *MarkerPostRequest) HomeID() string {
+	if r.Home != nil {
+		return r.Home.LastReadID
+	}
+	return r.HomeLastReadID
+}
+
+// NotificationsID returns the last read id for notifications given in the request, whether it was a form or JSON request.
+func (r *MarkerPostRequest) NotificationsID() string {
+	if r.Notifications != nil {
+		return r.Notifications.LastReadID
+	}
+	return r.NotificationsLastReadID
 }
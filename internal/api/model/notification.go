@@ -19,6 +19,8 @@
 package model
 
 // Notification represents a notification of an event relevant to the user.
+//
+// swagger:model notification
 type Notification struct {
 	// REQUIRED
 
@@ -43,3 +45,55 @@ type Notification struct {
 	// Status that was the object of the notification, e.g. in mentions, reblogs, favourites, or polls.
 	Status *Status `json:"status,omitempty"`
 }
+
+// GroupedNotifications is a page of notifications in which notifications about the same thing, like many
+// favourites of one status, are coalesced into a single group. The accounts and statuses that the groups
+// refer to are returned alongside them, so each one is only sent once.
+//
+// swagger:model groupedNotifications
+type GroupedNotifications struct {
+	// Accounts referred to by the notification groups.
+	Accounts []*Account `json:"accounts"`
+	// Statuses referred to by the notification groups.
+	Statuses []*Status `json:"statuses"`
+	// The notification groups, most recently notified first.
+	NotificationGroups []*NotificationGroup `json:"notification_groups"`
+}
+
+// NotificationGroup is one or more notifications of the same type about the same thing.
+//
+// swagger:model notificationGroup
+type NotificationGroup struct {
+	// Identifies the group; notifications that can't be grouped get a key of their own.
+	GroupKey string `json:"group_key"`
+	// Number of notifications in the group.
+	NotificationsCount int `json:"notifications_count"`
+	// The type of the notifications in the group, eg., favourite.
+	Type string `json:"type"`
+	// ID of the most recent notification in the group.
+	MostRecentNotificationID string `json:"most_recent_notification_id"`
+	// ID of the oldest notification of the group in this page.
+	PageMinID string `json:"page_min_id"`
+	// ID of the newest notification of the group in this page.
+	PageMaxID string `json:"page_max_id"`
+	// When the most recent notification of the group in this page was created (ISO 8601 Datetime).
+	LatestPageNotificationAt string `json:"latest_page_notification_at"`
+	// IDs of some of the accounts that the notifications in the group came from, most recent first.
+	SampleAccountIDs []string `json:"sample_account_ids"`
+	// ID of the status that the notifications in the group are about, if any.
+	StatusID string `json:"status_id,omitempty"`
+}
+
+// NotificationsResponse wraps a slice of notifications, ready to be serialized, along with the Link
+// header for the previous and next queries, to be returned to the client.
+type NotificationsResponse struct {
+	Notifications []*Notification
+	LinkHeader    string
+}
+
+// GroupedNotificationsResponse wraps grouped notifications, ready to be serialized, along with the Link
+// header for the previous and next queries, to be returned to the client.
+type GroupedNotificationsResponse struct {
+	GroupedNotifications *GroupedNotifications
+	LinkHeader           string
+}
@@ -33,4 +33,10 @@ type OAuthAuthorize struct {
 	// List of requested OAuth scopes, separated by spaces (or by pluses, if using query parameters).
 	// Must be a subset of scopes declared during app registration. If not provided, defaults to read.
 	Scope string `form:"scope" json:"scope"`
+	// PKCE code challenge derived from a client-generated code verifier.
+	// Public clients which can't keep a client secret should always set this.
+	CodeChallenge string `form:"code_challenge" json:"code_challenge"`
+	// Method used to derive the code challenge: S256 or plain. Must be set if code_challenge is set.
+	// Only S256 challenges let a client exchange the authorization code without its client secret.
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
 }
@@ -60,11 +60,11 @@ type PollOptions struct {
 //
 // swagger:parameters createStatus
 type PollRequest struct {
-	// Array of possible answers.
+	// Array of possible answers. At least 2 must be provided.
 	// If provided, media_ids cannot be used, and poll[expires_in] must be provided.
 	// name: poll[options]
 	Options []string `form:"options" json:"options" xml:"options"`
-	// Duration the poll should be open, in seconds.
+	// Duration the poll should be open, in seconds. Must be between 5 minutes and 31 days.
 	// If provided, media_ids cannot be used, and poll[options] must be provided.
 	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
 	// Allow multiple choices on this poll.
@@ -72,3 +72,11 @@ type PollRequest struct {
 	// Hide vote counts until the poll ends.
 	HideTotals bool `form:"hide_totals" json:"hide_totals" xml:"hide_totals"`
 }
+
+// PollVoteRequest models a request to vote in a poll.
+//
+// swagger:ignore
+type PollVoteRequest struct {
+	// Indices of the chosen options. Only one may be given unless the poll allows multiple choices.
+	Choices []int `form:"choices[]" json:"choices" xml:"choices"`
+}
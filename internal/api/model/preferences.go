@@ -19,6 +19,8 @@
 package model
 
 // Preferences represents a user's preferences.
+//
+// swagger:model preferences
 type Preferences struct {
 	// Default visibility for new posts.
 	// 	public = Public post
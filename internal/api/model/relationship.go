@@ -41,6 +41,10 @@ type Relationship struct {
 	Muting bool `json:"muting"`
 	// You are muting notifications from this account.
 	MutingNotifications bool `json:"muting_notifications"`
+	// If you are muting this account, when the mute will expire (ISO 8601 Datetime).
+	// Omitted if you aren't muting this account, or if the mute doesn't expire.
+	// example: 2021-07-30T09:20:25+00:00
+	MuteExpiresAt string `json:"mute_expires_at,omitempty"`
 	// You have requested to follow this account, and the request is pending.
 	Requested bool `json:"requested"`
 	// You are blocking this account's domain.
@@ -0,0 +1,70 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// Report represents a report made by the requesting account about another account.
+//
+// swagger:model report
+type Report struct {
+	// The ID of the report.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// Has a moderator resolved the report yet?
+	// example: false
+	ActionTaken bool `json:"action_taken"`
+	// When a moderator resolved the report, if they have (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	ActionTakenAt *string `json:"action_taken_at"`
+	// What kind of problem the report is about: spam, violation or other.
+	// example: spam
+	Category string `json:"category"`
+	// Why the report was made.
+	// example: this account posts nothing but ads
+	Comment string `json:"comment"`
+	// Was the report forwarded to the instance of the reported account?
+	// example: false
+	Forwarded bool `json:"forwarded"`
+	// When the report was made (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// IDs of the statuses of the reported account that the report points to.
+	StatusIDs []string `json:"status_ids"`
+	// IDs of the rules of this instance that the reported account is said to have broken.
+	RuleIDs []string `json:"rule_ids"`
+	// The account that was reported.
+	TargetAccount *Account `json:"target_account"`
+}
+
+// ReportCreateRequest models a request to report an account to the moderators of this instance.
+//
+// swagger:ignore
+type ReportCreateRequest struct {
+	// ID of the account to report.
+	AccountID string `form:"account_id" json:"account_id" xml:"account_id"`
+	// IDs of statuses of the account to attach to the report.
+	StatusIDs []string `form:"status_ids[]" json:"status_ids" xml:"status_ids"`
+	// Why the account is being reported.
+	Comment string `form:"comment" json:"comment" xml:"comment"`
+	// Forward the report to the instance of the account, if it's remote.
+	Forward bool `form:"forward" json:"forward" xml:"forward"`
+	// What kind of problem the report is about: spam, violation or other.
+	Category string `form:"category" json:"category" xml:"category"`
+	// IDs of the rules of this instance that the account broke. Only used when the category is violation.
+	RuleIDs []string `form:"rule_ids[]" json:"rule_ids" xml:"rule_ids"`
+}
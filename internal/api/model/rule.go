@@ -0,0 +1,73 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// InstanceRule represents one of the rules of this instance.
+//
+// swagger:model instanceRule
+type InstanceRule struct {
+	// The ID of the rule.
+	// example: 01GB3Z7Y8M7C6WDCWD3YJ6J6AX
+	ID string `json:"id"`
+	// The text of the rule.
+	// example: Don't be a jerk.
+	Text string `json:"text"`
+}
+
+// AdminRule represents one of the rules of this instance, as seen by an admin.
+//
+// swagger:model adminRule
+type AdminRule struct {
+	// The ID of the rule.
+	// example: 01GB3Z7Y8M7C6WDCWD3YJ6J6AX
+	ID string `json:"id"`
+	// The text of the rule.
+	// example: Don't be a jerk.
+	Text string `json:"text"`
+	// Rules are shown in ascending order of priority, and then in the order they were created.
+	// example: 0
+	Priority int `json:"priority"`
+	// Time at which this rule was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// Time at which this rule was last changed (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	UpdatedAt string `json:"updated_at"`
+}
+
+// AdminRuleCreateRequest is the form submitted as a POST to /api/v1/admin/rules to create a new rule.
+//
+// swagger:ignore
+type AdminRuleCreateRequest struct {
+	// The text of the rule.
+	Text string `form:"text" json:"text" xml:"text"`
+	// Rules are shown in ascending order of priority, and then in the order they were created.
+	Priority int `form:"priority" json:"priority" xml:"priority"`
+}
+
+// AdminRuleUpdateRequest is the form submitted as a PATCH to /api/v1/admin/rules/{id} to change a rule.
+// Only the fields that are set are changed.
+//
+// swagger:ignore
+type AdminRuleUpdateRequest struct {
+	// The new text of the rule.
+	Text *string `form:"text" json:"text" xml:"text"`
+	// The new priority of the rule.
+	Priority *int `form:"priority" json:"priority" xml:"priority"`
+}
@@ -58,6 +58,9 @@ type SearchQuery struct {
 	//
 	// For a status, this can be in the format: `https://some.instance.com/@someaccount/SOME_ID_OF_A_STATUS`
 	//
+	// If full-text search is enabled on this instance, any other text will be searched for in statuses
+	// that the requester has posted, faved, bookmarked, or been mentioned in.
+	//
 	// required: true
 	// in: query
 	Query string `json:"q"`
@@ -37,4 +37,11 @@ type Source struct {
 	Fields []Field `json:"fields"`
 	// The number of pending follow requests.
 	FollowRequestsCount int `json:"follow_requests_count,omitempty"`
+	// Total size in bytes of media currently stored by this account on the instance.
+	MediaStorageUsed int `json:"media_storage_used"`
+	// Maximum total size in bytes of media this account may store on the instance.
+	// 0 means the account may store an unlimited amount of media.
+	MediaStorageQuota int `json:"media_storage_quota"`
+	// Types of notification that the account doesn't want to be sent, eg., favourite, reblog.
+	ExcludedNotifications []string `json:"excluded_notifications"`
 }
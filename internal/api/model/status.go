@@ -28,6 +28,10 @@ type Status struct {
 	// The date when this status was created (ISO 8601 Datetime).
 	// example: 2021-07-30T09:20:25+00:00
 	CreatedAt string `json:"created_at"`
+	// The date when this status was last edited (ISO 8601 Datetime).
+	// Not set if the status has never been edited.
+	// example: 2021-07-30T09:32:12+00:00
+	EditedAt string `json:"edited_at,omitempty"`
 	// ID of the status being replied to.
 	// example: 01FBVD42CQ3ZEEVMW180SBX03B
 	InReplyToID string `json:"in_reply_to_id,omitempty"`
@@ -94,6 +98,20 @@ type Status struct {
 	// so the user may redraft from the source text without the client having to reverse-engineer
 	// the original text from the HTML content.
 	Text string `json:"text"`
+	// The status that this status quotes. Only set if the quoted status is public or unlisted.
+	Quote *Status `json:"quote,omitempty"`
+	// Pleroma-specific additions to the status. Only set if there's something to put in it.
+	Pleroma *StatusPleroma `json:"pleroma,omitempty"`
+	// Warn filters of the requesting account that this status matched, in the context it's being served in.
+	Filtered []FilterResult `json:"filtered,omitempty"`
+}
+
+// StatusPleroma contains fields that pleroma adds to statuses, which some clients understand.
+//
+// swagger:model statusPleroma
+type StatusPleroma struct {
+	// Emoji reactions to this status, grouped by emoji.
+	EmojiReactions []EmojiReaction `json:"emoji_reactions"`
 }
 
 /*
@@ -152,6 +170,10 @@ type StatusCreateRequest struct {
 	// ID of the status being replied to, if status is a reply.
 	// in: formData
 	InReplyToID string `form:"in_reply_to_id" json:"in_reply_to_id" xml:"in_reply_to_id"`
+	// ID of the status being quoted, if status is a quote.
+	// Only allowed if quotes are enabled on this instance.
+	// in: formData
+	QuoteID string `form:"quote_id" json:"quote_id" xml:"quote_id"`
 	// Status and attached media should be marked as sensitive.
 	// in: formData
 	Sensitive bool `form:"sensitive" json:"sensitive" xml:"sensitive"`
@@ -28,4 +28,10 @@ type Tag struct {
 	// Web link to the hashtag.
 	// example: https://example.org/tags/helloworld
 	URL string `json:"url"`
+	// Usage of the hashtag on each of the last few days, most recent day first.
+	// Only included when the hashtag is trending.
+	History []History `json:"history,omitempty"`
+	// Whether the requesting account follows this hashtag.
+	// Only included when following or unfollowing the hashtag, or listing followed hashtags.
+	Following *bool `json:"following,omitempty"`
 }
@@ -0,0 +1,82 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// Translation models a status translated into the language of the requesting user.
+//
+// swagger:model translation
+type Translation struct {
+	// The translated content of the status (html-formatted).
+	// example: <p>Hey this is a status!</p>
+	Content string `json:"content"`
+	// The translated subject, summary, or content warning of the status.
+	// example: warning nsfw
+	SpoilerText string `json:"spoiler_text"`
+	// The translated poll options of the status, if it has a poll.
+	Poll *TranslationPoll `json:"poll,omitempty"`
+	// The translated descriptions of the media attached to the status.
+	MediaAttachments []TranslationAttachment `json:"media_attachments"`
+	// The language that the status was translated from (ISO 639 Part 1 two-letter language code).
+	// example: nl
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	// The service that was used to translate the status.
+	// example: DeepL.com
+	Provider string `json:"provider"`
+}
+
+// TranslationPoll models the translated options of a poll.
+//
+// swagger:model translationPoll
+type TranslationPoll struct {
+	// The ID of the poll.
+	// example: 01FBYJHQWQZAVWFRK9PDYTKGMB
+	ID string `json:"id"`
+	// The translated poll options, in the same order as the options of the poll.
+	Options []TranslationPollOption `json:"options"`
+}
+
+// TranslationPollOption models a translated poll option.
+//
+// swagger:model translationPollOption
+type TranslationPollOption struct {
+	// The translated title of the option.
+	// example: yes
+	Title string `json:"title"`
+}
+
+// TranslationAttachment models the translated description of a media attachment.
+//
+// swagger:model translationAttachment
+type TranslationAttachment struct {
+	// The ID of the attachment.
+	// example: 01FC31DZT1AYWDZ8XTCRWRBYRK
+	ID string `json:"id"`
+	// The translated description of the attachment.
+	// example: This is a picture of a kitten.
+	Description string `json:"description"`
+}
+
+// StatusTranslateRequest models a request to translate a status.
+//
+// swagger:ignore
+type StatusTranslateRequest struct {
+	// ISO 639 language code to translate the status into.
+	// Defaults to the locale of the requesting user, or English if that's not set.
+	Lang string `form:"lang" json:"lang" xml:"lang"`
+}
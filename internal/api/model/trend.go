@@ -0,0 +1,56 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// TrendsLink represents a web page that's trending, ie., that has been linked to from a lot of public statuses recently.
+//
+// swagger:model trendsLink
+type TrendsLink struct {
+	Card
+	// Usage of the link on each of the last few days, most recent day first.
+	History []History `json:"history"`
+}
+
+// AdminTrend models a hashtag, status or link that's trending, along with whether it has been reviewed by an admin.
+//
+// swagger:model adminTrend
+type AdminTrend struct {
+	// The id of the hashtag, status or link in the database.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// What's trending.
+	// enum:
+	// - tag
+	// - status
+	// - link
+	// example: tag
+	Type string `json:"type"`
+	// The trending hashtag, if type is tag.
+	Tag *Tag `json:"tag,omitempty"`
+	// The trending status, if type is status.
+	Status *Status `json:"status,omitempty"`
+	// The trending link, if type is link.
+	Link *TrendsLink `json:"link,omitempty"`
+	// Whether this is shown in public trends.
+	// example: true
+	Trendable bool `json:"trendable"`
+	// Whether this hasn't been approved or rejected by an admin yet.
+	// example: false
+	RequiresReview bool `json:"requires_review"`
+}
@@ -0,0 +1,112 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package model
+
+// WebPushSubscription represents a subscription to web push notifications, for the access token it was made with.
+//
+// swagger:model webPushSubscription
+type WebPushSubscription struct {
+	// The ID of the subscription.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	ID string `json:"id"`
+	// Where notifications are sent to.
+	// example: https://push.example.org/send/abcdef
+	Endpoint string `json:"endpoint"`
+	// Which types of notification are sent.
+	Alerts WebPushSubscriptionAlerts `json:"alerts"`
+	// The public key of this instance, for the client to check that notifications are really from us.
+	// example: BCk-QqERU0q-CfYZjcuB6lnyyOYfJ2AifKqfeGIm7Z-HiTU5T9eTG5GxVA0_OH5mMlI4UkkDTpaZwozy0TzdZ2M=
+	ServerKey string `json:"server_key"`
+}
+
+// WebPushSubscriptionAlerts are the types of notification that a web push subscription sends.
+//
+// swagger:model webPushSubscriptionAlerts
+type WebPushSubscriptionAlerts struct {
+	// Send notifications of new followers.
+	Follow bool `form:"data[alerts][follow]" json:"follow"`
+	// Send notifications of new follow requests.
+	FollowRequest bool `form:"data[alerts][follow_request]" json:"follow_request"`
+	// Send notifications of statuses being favourited.
+	Favourite bool `form:"data[alerts][favourite]" json:"favourite"`
+	// Send notifications of statuses being boosted.
+	Reblog bool `form:"data[alerts][reblog]" json:"reblog"`
+	// Send notifications of mentions.
+	Mention bool `form:"data[alerts][mention]" json:"mention"`
+	// Send notifications of polls ending.
+	Poll bool `form:"data[alerts][poll]" json:"poll"`
+	// Send notifications of new statuses from accounts that notifications have been enabled for.
+	Status bool `form:"data[alerts][status]" json:"status"`
+}
+
+// WebPushSubscriptionCreateRequest is the form submitted as a POST to /api/v1/push/subscription to subscribe to web push notifications.
+//
+// swagger:model webPushSubscriptionCreateRequest
+type WebPushSubscriptionCreateRequest struct {
+	// The push service endpoint and keys of the client.
+	Subscription WebPushSubscriptionRequestSubscription `json:"subscription"`
+	// Which types of notification should be sent.
+	Data WebPushSubscriptionRequestData `json:"data"`
+}
+
+// WebPushSubscriptionUpdateRequest is the form submitted as a PUT to /api/v1/push/subscription to change which notifications are sent.
+//
+// swagger:model webPushSubscriptionUpdateRequest
+type WebPushSubscriptionUpdateRequest struct {
+	// Which types of notification should be sent.
+	Data WebPushSubscriptionRequestData `json:"data"`
+}
+
+// WebPushSubscriptionRequestSubscription is the endpoint and keys part of a web push subscription request.
+type WebPushSubscriptionRequestSubscription struct {
+	// Where notifications should be sent to.
+	Endpoint string `form:"subscription[endpoint]" json:"endpoint"`
+	// The keys to encrypt notifications with.
+	Keys WebPushSubscriptionRequestKeys `json:"keys"`
+}
+
+// WebPushSubscriptionRequestKeys are the encryption keys of a web push subscription request.
+type WebPushSubscriptionRequestKeys struct {
+	// Base64url encoded public key of the client, on the P-256 curve.
+	P256dh string `form:"subscription[keys][p256dh]" json:"p256dh"`
+	// Base64url encoded auth secret of the client.
+	Auth string `form:"subscription[keys][auth]" json:"auth"`
+}
+
+// WebPushSubscriptionRequestData is the data part of a web push subscription request.
+type WebPushSubscriptionRequestData struct {
+	// Which types of notification should be sent.
+	Alerts WebPushSubscriptionAlerts `json:"alerts"`
+}
+
+// WebPushNotification is the payload of a notification sent through web push, before it's encrypted.
+type WebPushNotification struct {
+	// The access token that the subscription was made with, so the client knows which of its accounts this is for.
+	AccessToken string `json:"access_token"`
+	// The ID of the notification.
+	NotificationID string `json:"notification_id"`
+	// The type of the notification.
+	NotificationType string `json:"notification_type"`
+	// URL of the avatar of the account that caused the notification.
+	Icon string `json:"icon"`
+	// A short summary of the notification.
+	Title string `json:"title"`
+	// The text of the status, if there is one.
+	Body string `json:"body"`
+}
@@ -69,6 +69,12 @@ type NodeInfoSoftware struct {
 	Name string `json:"name"`
 	// example: 0.1.2 1234567
 	Version string `json:"version"`
+	// The url of the source code repository of the software. Only included in nodeinfo 2.1.
+	// example: https://github.com/superseriousbusiness/gotosocial
+	Repository string `json:"repository,omitempty"`
+	// The url of the homepage of the software. Only included in nodeinfo 2.1.
+	// example: https://docs.gotosocial.org
+	Homepage string `json:"homepage,omitempty"`
 }
 
 // NodeInfoServices represents inbound and outbound services that this node offers connections to.
@@ -79,8 +85,22 @@ type NodeInfoServices struct {
 
 // NodeInfoUsage represents usage information about this server, such as number of users.
 type NodeInfoUsage struct {
+	// Statistics about the users of this server.
 	Users NodeInfoUsers `json:"users"`
+	// The amount of posts that were made by users that are registered on this server.
+	// example: 1234
+	LocalPosts int `json:"localPosts"`
 }
 
-// NodeInfoUsers is a stub for usage information, currently empty.
-type NodeInfoUsers struct{}
+// NodeInfoUsers represents statistics about the users of this server.
+type NodeInfoUsers struct {
+	// The total amount of users on this server.
+	// example: 10
+	Total int `json:"total"`
+	// The amount of users that posted something in the last 180 days.
+	// example: 8
+	ActiveHalfyear int `json:"activeHalfyear"`
+	// The amount of users that posted something in the last 30 days.
+	// example: 5
+	ActiveMonth int `json:"activeMonth"`
+}
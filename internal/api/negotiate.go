@@ -33,6 +33,7 @@ const (
 	AppActivityJSON   Offer = `application/activity+json`                                            // AppActivityJSON is the mime type for 'application/activity+json'.
 	AppActivityLDJSON Offer = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"` // AppActivityLDJSON is the mime type for 'application/ld+json; profile="https://www.w3.org/ns/activitystreams"'
 	TextHTML          Offer = `text/html`                                                            // TextHTML is the mime type for 'text/html'.
+	TextCSV           Offer = `text/csv`                                                             // TextCSV is the mime type for 'text/csv'.
 )
 
 // ActivityPubAcceptHeaders represents the Accept headers mentioned here:
@@ -29,8 +29,10 @@ import (
 const (
 	// NodeInfoWellKnownPath is the base path for serving responses to nodeinfo lookup requests.
 	NodeInfoWellKnownPath = ".well-known/nodeinfo"
+	// VersionKey is the url param for the version of the nodeinfo schema being requested.
+	VersionKey = "version"
 	// NodeInfoBasePath is the path for serving nodeinfo responses.
-	NodeInfoBasePath = "/nodeinfo/2.0"
+	NodeInfoBasePath = "/nodeinfo/:" + VersionKey
 )
 
 // Module implements the FederationModule interface
@@ -20,6 +20,7 @@ package nodeinfo
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -27,9 +28,9 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 )
 
-// NodeInfoGETHandler swagger:operation GET /nodeinfo/2.0 nodeInfoGet
+// NodeInfoGETHandler swagger:operation GET /nodeinfo/{version} nodeInfoGet
 //
-// Returns a compliant nodeinfo response to node info queries.
+// Returns a compliant nodeinfo response to node info queries. Versions 2.0 and 2.1 of the schema are served.
 //
 // See: https://nodeinfo.diaspora.software/schema.html
 //
@@ -38,12 +39,22 @@ import (
 // - nodeinfo
 //
 // produces:
+// - application/json; profile="http://nodeinfo.diaspora.software/ns/schema/2.1#"
 // - application/json; profile="http://nodeinfo.diaspora.software/ns/schema/2.0#"
 //
+// parameters:
+// - name: version
+//   type: string
+//   description: The version of the nodeinfo schema to use, either 2.0 or 2.1.
+//   in: path
+//   required: true
+//
 // responses:
 //   '200':
 //     schema:
 //       "$ref": "#/definitions/nodeinfo"
+//   '404':
+//      description: not found
 func (m *Module) NodeInfoGETHandler(c *gin.Context) {
 	l := logrus.WithFields(logrus.Fields{
 		"func":       "NodeInfoGETHandler",
@@ -55,7 +66,9 @@ func (m *Module) NodeInfoGETHandler(c *gin.Context) {
 		return
 	}
 
-	ni, err := m.processor.GetNodeInfo(c.Request.Context(), c.Request)
+	version := c.Param(VersionKey)
+
+	ni, err := m.processor.GetNodeInfo(c.Request.Context(), c.Request, version)
 	if err != nil {
 		l.Debugf("error with get node info request: %s", err)
 		c.JSON(err.Code(), err.Safe())
@@ -67,5 +80,5 @@ func (m *Module) NodeInfoGETHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": jsonErr.Error()})
 	}
 
-	c.Data(http.StatusOK, `application/json; profile="http://nodeinfo.diaspora.software/ns/schema/2.0#"`, b)
+	c.Data(http.StatusOK, fmt.Sprintf(`application/json; profile="http://nodeinfo.diaspora.software/ns/schema/%s#"`, ni.Version), b)
 }
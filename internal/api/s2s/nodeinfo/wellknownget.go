@@ -28,9 +28,9 @@ import (
 
 // NodeInfoWellKnownGETHandler swagger:operation GET /.well-known/nodeinfo nodeInfoWellKnownGet
 //
-// Directs callers to /nodeinfo/2.0.
+// Directs callers to /nodeinfo/2.1 and /nodeinfo/2.0.
 //
-// eg. `{"links":[{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.0","href":"http://example.org/nodeinfo/2.0"}]}`
+// eg. `{"links":[{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.1","href":"http://example.org/nodeinfo/2.1"},{"rel":"http://nodeinfo.diaspora.software/ns/schema/2.0","href":"http://example.org/nodeinfo/2.0"}]}`
 // See: https://nodeinfo.diaspora.software/protocol.html
 //
 // ---
@@ -20,9 +20,12 @@ package user
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 )
 
 // transferContext transfers the signature verifier and signature from the gin context to the request context
@@ -42,6 +45,22 @@ func transferContext(c *gin.Context) context.Context {
 	return ctx
 }
 
+// serveTombstone responds with the given tombstone and 410 Gone, if the processor gave one along with a 410 error
+// for something that's been deleted, so that remote instances know to stop asking for it. It returns false otherwise.
+func serveTombstone(c *gin.Context, format string, tombstone interface{}, errWithCode gtserror.WithCode) bool {
+	if tombstone == nil || errWithCode.Code() != http.StatusGone {
+		return false
+	}
+
+	b, err := json.Marshal(tombstone)
+	if err != nil {
+		return false
+	}
+
+	c.Data(http.StatusGone, format, b)
+	return true
+}
+
 // SwaggerCollection represents an activitypub collection.
 // swagger:model swaggerCollection
 type SwaggerCollection struct {
@@ -0,0 +1,110 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type FeaturedAccountsGetTestSuite struct {
+	UserStandardTestSuite
+}
+
+func (suite *FeaturedAccountsGetTestSuite) TestGetFeaturedAccounts() {
+	signedRequest := testrig.NewTestDereferenceRequests(suite.testAccounts)["foss_satan_dereference_zork_featured_accounts"]
+	targetAccount := suite.testAccounts["local_account_1"]
+	featuredAccountsURI := targetAccount.URI + "/collections/featured_accounts"
+
+	// feature an account that local_account_1 follows
+	suite.NoError(suite.db.Put(context.Background(), &gtsmodel.Endorsement{
+		ID:              "01G1TR6BADACCN3D8QMC2J3FJ0",
+		AccountID:       targetAccount.ID,
+		TargetAccountID: suite.testAccounts["admin_account"].ID,
+	}))
+
+	clientWorker := worker.New[messages.FromClientAPI](-1, -1)
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker)
+	federator := testrig.NewTestFederator(suite.db, tc, suite.storage, suite.mediaManager, fedWorker)
+	emailSender := testrig.NewEmailSender("../../../../web/template/", nil)
+	processor := testrig.NewTestProcessor(suite.db, suite.storage, federator, emailSender, suite.mediaManager, clientWorker, fedWorker)
+	userModule := user.New(processor).(*user.Module)
+
+	// setup request
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, featuredAccountsURI, nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/activity+json")
+	ctx.Request.Header.Set("Signature", signedRequest.SignatureHeader)
+	ctx.Request.Header.Set("Date", signedRequest.DateHeader)
+
+	// we need to pass the context through signature check first to set appropriate values on it
+	suite.securityModule.SignatureCheck(ctx)
+
+	// normally the router would populate these params from the path values,
+	// but because we're calling the function directly, we need to set them manually.
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   user.UsernameKey,
+			Value: targetAccount.Username,
+		},
+	}
+
+	// trigger the function being tested
+	userModule.FeaturedAccountsGETHandler(ctx)
+
+	// check response
+	suite.EqualValues(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	collection := struct {
+		ID           string `json:"id"`
+		Type         string `json:"type"`
+		TotalItems   int    `json:"totalItems"`
+		OrderedItems string `json:"orderedItems"`
+	}{}
+	suite.NoError(json.Unmarshal(b, &collection))
+
+	suite.Equal(featuredAccountsURI, collection.ID)
+	suite.Equal("OrderedCollection", collection.Type)
+	suite.Equal(1, collection.TotalItems)
+	suite.Equal(suite.testAccounts["admin_account"].URI, collection.OrderedItems)
+}
+
+func TestFeaturedAccountsGetTestSuite(t *testing.T) {
+	suite.Run(t, new(FeaturedAccountsGetTestSuite))
+}
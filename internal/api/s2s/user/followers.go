@@ -22,13 +22,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
 )
 
-// FollowersGETHandler returns a collection of URIs for followers of the target user, formatted so that other AP servers can understand it.
+// FollowersGETHandler swagger:operation GET /users/{username}/followers s2sFollowersGet
+//
+// Get the followers collection for an actor, which lists the accounts that follow the actor.
+//
+// Note that the response will be a Collection with a page as `first`, as shown below, if `page` is `false`.
+//
+// If `page` is `true`, then the response will be a single `CollectionPage` without the wrapping `Collection`.
+//
+// If the collection is hidden, then the Collection will only show how many items are in it, and pages can't be fetched.
+//
+// HTTP signature is required on the request.
+//
+// ---
+// tags:
+// - s2s/federation
+//
+// produces:
+// - application/activity+json
+//
+// parameters:
+// - name: username
+//   type: string
+//   description: Username of the account.
+//   in: path
+//   required: true
+// - name: page
+//   type: boolean
+//   description: Return response as a CollectionPage.
+//   in: query
+//   default: false
+// - name: min_id
+//   type: string
+//   description: Minimum ID of the next follow, used for paging.
+//   in: query
+// - name: max_id
+//   type: string
+//   description: Maximum ID of the next follow, used for paging.
+//   in: query
+//
+// responses:
+//   '200':
+//      in: body
+//      schema:
+//        "$ref": "#/definitions/swaggerCollection"
+//   '400':
+//      description: bad request
+//   '401':
+//      description: unauthorized
+//   '403':
+//      description: forbidden
+//   '404':
+//      description: not found
 func (m *Module) FollowersGETHandler(c *gin.Context) {
 	l := logrus.WithFields(logrus.Fields{
 		"func": "FollowersGETHandler",
@@ -41,6 +93,20 @@ func (m *Module) FollowersGETHandler(c *gin.Context) {
 		return
 	}
 
+	var page bool
+	if pageString := c.Query(PageKey); pageString != "" {
+		i, err := strconv.ParseBool(pageString)
+		if err != nil {
+			l.Debugf("error parsing page string: %s", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "couldn't parse page query param"})
+			return
+		}
+		page = i
+	}
+
+	minID := c.Query(MinIDKey)
+	maxID := c.Query(MaxIDKey)
+
 	format, err := api.NegotiateAccept(c, api.ActivityPubAcceptHeaders...)
 	if err != nil {
 		c.JSON(http.StatusNotAcceptable, gin.H{"error": err.Error()})
@@ -50,7 +116,7 @@ func (m *Module) FollowersGETHandler(c *gin.Context) {
 
 	ctx := transferContext(c)
 
-	followers, errWithCode := m.processor.GetFediFollowers(ctx, requestedUsername, c.Request.URL)
+	followers, errWithCode := m.processor.GetFediFollowers(ctx, requestedUsername, page, maxID, minID, c.Request.URL)
 	if errWithCode != nil {
 		l.Info(errWithCode.Error())
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
@@ -0,0 +1,113 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package user_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type FollowingGetTestSuite struct {
+	UserStandardTestSuite
+}
+
+// getFollowing makes a signed request for the given dereference to zork's following collection, and returns the response code and body.
+func (suite *FollowingGetTestSuite) getFollowing(dereference string, query string) (int, string) {
+	signedRequest := testrig.NewTestDereferenceRequests(suite.testAccounts)[dereference]
+	targetAccount := suite.testAccounts["local_account_1"]
+
+	clientWorker := worker.New[messages.FromClientAPI](-1, -1)
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker)
+	federator := testrig.NewTestFederator(suite.db, tc, suite.storage, suite.mediaManager, fedWorker)
+	emailSender := testrig.NewEmailSender("../../../../web/template/", nil)
+	processor := testrig.NewTestProcessor(suite.db, suite.storage, federator, emailSender, suite.mediaManager, clientWorker, fedWorker)
+	userModule := user.New(processor).(*user.Module)
+
+	// setup request
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, targetAccount.FollowingURI+query, nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/activity+json")
+	ctx.Request.Header.Set("Signature", signedRequest.SignatureHeader)
+	ctx.Request.Header.Set("Date", signedRequest.DateHeader)
+
+	// we need to pass the context through signature check first to set appropriate values on it
+	suite.securityModule.SignatureCheck(ctx)
+
+	// normally the router would populate these params from the path values,
+	// but because we're calling the function directly, we need to set them manually.
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   user.UsernameKey,
+			Value: targetAccount.Username,
+		},
+	}
+
+	// trigger the function being tested
+	userModule.FollowingGETHandler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	return recorder.Code, string(b)
+}
+
+func (suite *FollowingGetTestSuite) TestGetFollowing() {
+	code, body := suite.getFollowing("foss_satan_dereference_zork_following", "")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","first":"http://localhost:8080/users/the_mighty_zork/following?page=true","id":"http://localhost:8080/users/the_mighty_zork/following","totalItems":2,"type":"OrderedCollection"}`, body)
+}
+
+func (suite *FollowingGetTestSuite) TestGetFollowingFirstPage() {
+	code, body := suite.getFollowing("foss_satan_dereference_zork_following_first", "?page=true")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","id":"http://localhost:8080/users/the_mighty_zork/following?page=true","next":"http://localhost:8080/users/the_mighty_zork/following?page=true\u0026max_id=01F8PY8RHWRQZV038T4E8T9YK8","orderedItems":["http://localhost:8080/users/1happyturtle","http://localhost:8080/users/admin"],"partOf":"http://localhost:8080/users/the_mighty_zork/following","prev":"http://localhost:8080/users/the_mighty_zork/following?page=true\u0026min_id=01F8PYDCE8XE23GRE5DPZJDZDP","totalItems":2,"type":"OrderedCollectionPage"}`, body)
+}
+
+func (suite *FollowingGetTestSuite) TestGetFollowingHidden() {
+	viper.Set(config.Keys.FederationHideCollections, true)
+
+	// the collection only shows how many follows there are
+	code, body := suite.getFollowing("foss_satan_dereference_zork_following", "")
+	suite.Equal(http.StatusOK, code)
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","id":"http://localhost:8080/users/the_mighty_zork/following","totalItems":2,"type":"OrderedCollection"}`, body)
+
+	// and pages of it can't be fetched
+	code, _ = suite.getFollowing("foss_satan_dereference_zork_following_first", "?page=true")
+	suite.Equal(http.StatusForbidden, code)
+}
+
+func TestFollowingGetTestSuite(t *testing.T) {
+	suite.Run(t, new(FollowingGetTestSuite))
+}
@@ -59,6 +59,9 @@ func (m *Module) StatusGETHandler(c *gin.Context) {
 	status, errWithCode := m.processor.GetFediStatus(ctx, requestedUsername, requestedStatusID, c.Request.URL)
 	if errWithCode != nil {
 		l.Info(errWithCode.Error())
+		if serveTombstone(c, format, status, errWithCode) {
+			return
+		}
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
@@ -57,6 +57,12 @@ const (
 	UsersFollowersPath = UsersBasePathWithUsername + "/" + uris.FollowersPath
 	// UsersFollowingPath is for serving GET request's to a user's following list, with the given username key.
 	UsersFollowingPath = UsersBasePathWithUsername + "/" + uris.FollowingPath
+	// UsersFeaturedCollectionPath is for serving GET requests to a user's featured collection, which holds their pinned statuses.
+	UsersFeaturedCollectionPath = UsersBasePathWithUsername + "/" + uris.CollectionsPath + "/" + uris.FeaturedPath
+	// UsersFeaturedAccountsPath is for serving GET requests to a user's featured accounts collection, which holds the accounts they endorse.
+	UsersFeaturedAccountsPath = UsersBasePathWithUsername + "/" + uris.CollectionsPath + "/" + uris.FeaturedAccountsPath
+	// UsersFeaturedTagsPath is for serving GET requests to a user's featured tags collection, which holds the hashtags they feature.
+	UsersFeaturedTagsPath = UsersBasePathWithUsername + "/" + uris.CollectionsPath + "/" + uris.FeaturedTagsPath
 	// UsersStatusPath is for serving GET requests to a particular status by a user, with the given username key and status ID
 	UsersStatusPath = UsersBasePathWithUsername + "/" + uris.StatusesPath + "/:" + StatusIDKey
 	// UsersStatusRepliesPath is for serving the replies collection of a status.
@@ -85,5 +91,8 @@ func (m *Module) Route(s router.Router) error {
 	s.AttachHandler(http.MethodGet, UsersPublicKeyPath, m.PublicKeyGETHandler)
 	s.AttachHandler(http.MethodGet, UsersStatusRepliesPath, m.StatusRepliesGETHandler)
 	s.AttachHandler(http.MethodGet, UsersOutboxPath, m.OutboxGETHandler)
+	s.AttachHandler(http.MethodGet, UsersFeaturedCollectionPath, m.FeaturedGETHandler)
+	s.AttachHandler(http.MethodGet, UsersFeaturedAccountsPath, m.FeaturedAccountsGETHandler)
+	s.AttachHandler(http.MethodGet, UsersFeaturedTagsPath, m.FeaturedTagsGETHandler)
 	return nil
 }
@@ -61,6 +61,9 @@ func (m *Module) UsersGETHandler(c *gin.Context) {
 	user, errWithCode := m.processor.GetFediUser(ctx, requestedUsername, c.Request.URL) // GetFediUser handles auth as well
 	if errWithCode != nil {
 		l.Info(errWithCode.Error())
+		if serveTombstone(c, format, user, errWithCode) {
+			return
+		}
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
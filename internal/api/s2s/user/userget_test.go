@@ -33,6 +33,7 @@ import (
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/api/s2s/user"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
@@ -187,6 +188,95 @@ func (suite *UserGetTestSuite) TestGetUserPublicKeyDeleted() {
 	suite.EqualValues(targetAccount.Username, a.Username)
 }
 
+// TestGetUserDeleted checks that a deleted account is reported as gone, rather than being served as usual.
+func (suite *UserGetTestSuite) TestGetUserDeleted() {
+	targetAccount := &gtsmodel.Account{}
+	*targetAccount = *suite.testAccounts["local_account_1"]
+	targetAccount.SuspendedAt = time.Now()
+	targetAccount.SuspensionOrigin = targetAccount.ID
+	_, err := suite.db.UpdateAccount(context.Background(), targetAccount)
+	suite.NoError(err)
+
+	derefRequests := testrig.NewTestDereferenceRequests(suite.testAccounts)
+	signedRequest := derefRequests["foss_satan_dereference_zork"]
+
+	// setup request
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, targetAccount.URI, nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/activity+json")
+	ctx.Request.Header.Set("Signature", signedRequest.SignatureHeader)
+	ctx.Request.Header.Set("Date", signedRequest.DateHeader)
+
+	suite.securityModule.SignatureCheck(ctx)
+
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   user.UsernameKey,
+			Value: targetAccount.Username,
+		},
+	}
+
+	suite.userModule.UsersGETHandler(ctx)
+
+	suite.EqualValues(http.StatusGone, recorder.Code)
+}
+
+// TestGetInstanceActorUnsigned checks that the instance actor can be fetched without a signed request,
+// since remote instances need to fetch it to check the signatures on requests that it makes.
+func (suite *UserGetTestSuite) TestGetInstanceActorUnsigned() {
+	instanceAccount, err := suite.db.GetInstanceAccount(context.Background(), "")
+	suite.NoError(err)
+
+	// setup request
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, instanceAccount.URI, nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/activity+json")
+
+	suite.securityModule.SignatureCheck(ctx)
+
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   user.UsernameKey,
+			Value: instanceAccount.Username,
+		},
+	}
+
+	suite.userModule.UsersGETHandler(ctx)
+
+	suite.EqualValues(http.StatusOK, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+
+	m := make(map[string]interface{})
+	err = json.Unmarshal(b, &m)
+	suite.NoError(err)
+	suite.Equal(instanceAccount.URI, m["id"])
+	suite.Equal(instanceAccount.Username, m["preferredUsername"])
+
+	// an ordinary account still needs a signed request
+	targetAccount := suite.testAccounts["local_account_1"]
+	recorder = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, targetAccount.URI, nil)
+	ctx.Request.Header.Set("accept", "application/activity+json")
+	suite.securityModule.SignatureCheck(ctx)
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   user.UsernameKey,
+			Value: targetAccount.Username,
+		},
+	}
+
+	suite.userModule.UsersGETHandler(ctx)
+
+	suite.EqualValues(http.StatusUnauthorized, recorder.Code)
+}
+
 func TestUserGetTestSuite(t *testing.T) {
 	suite.Run(t, new(UserGetTestSuite))
 }
@@ -0,0 +1,52 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package security
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// IPBlock blocks requests from IP addresses that an admin has blocked from accessing this instance at all.
+func (m *Module) IPBlock(c *gin.Context) {
+	l := logrus.WithFields(logrus.Fields{
+		"func": "IPBlock",
+	})
+
+	ip := net.ParseIP(c.ClientIP())
+	if ip == nil {
+		return
+	}
+
+	severity, err := m.db.GetIPBlockSeverity(c.Request.Context(), ip)
+	if err != nil {
+		l.Errorf("error checking ip blocks for %s: %s", ip, err)
+		return
+	}
+
+	if severity == gtsmodel.IPBlockSeverityNoAccess {
+		l.Debugf("aborting request because %s is blocked", ip)
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+}
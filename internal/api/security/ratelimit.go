@@ -0,0 +1,113 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package security
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/oauth2/v4"
+)
+
+// rateLimitPeriod is how long it takes for a client that has used up its rate limit to be able to make as many requests again.
+const rateLimitPeriod = 5 * time.Minute
+
+// expensiveEndpoints are the client API endpoints that have their own, lower rate limit,
+// because each request to them makes the instance do a lot of work.
+var expensiveEndpoints = []struct {
+	method     string
+	pathPrefix string
+}{
+	{http.MethodGet, "/api/v1/search"},
+	{http.MethodGet, "/api/v2/search"},
+	{http.MethodPost, "/api/v1/media"},
+	{http.MethodPost, "/api/v2/media"},
+}
+
+// RateLimit limits how many requests can be made to the client API with any one access token, or from any one
+// IP address for requests without a valid token. The X-RateLimit headers are set on responses so that clients
+// can see how close they are to the limit, and requests over it are refused with a 429 and a Retry-After header.
+//
+// It relies on TokenCheck having already validated the token of the request, if there was one.
+// Tokens that TokenCheck didn't accept are ignored, so requests with them are limited by IP address.
+func (m *Module) RateLimit(c *gin.Context) {
+	if !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		return
+	}
+
+	limiter := m.rateLimiter
+	if m.expensiveRateLimiter != nil && isExpensive(c.Request) {
+		limiter = m.expensiveRateLimiter
+	}
+
+	// only key on the token once TokenCheck has finished with it, which is when the application is set,
+	// so that made-up or unusable tokens count against the ip address they're sent from instead
+	key := "ip:" + ipKey(c.ClientIP())
+	if _, ok := c.Get(oauth.SessionAuthorizedApplication); ok {
+		if i, ok := c.Get(oauth.SessionAuthorizedToken); ok {
+			if ti, ok := i.(oauth2.TokenInfo); ok && ti.GetAccess() != "" {
+				key = "token:" + ti.GetAccess()
+			}
+		}
+	}
+
+	result := limiter.Allow(key, time.Now())
+	if result.Limit == 0 {
+		// requests aren't limited
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", result.Reset.UTC().Format(time.RFC3339))
+
+	if !result.Allowed {
+		logrus.WithField("func", "RateLimit").Debugf("refusing request to %s because the rate limit was reached", c.Request.URL.Path)
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+		return
+	}
+}
+
+// isExpensive returns true if the given request is to one of the expensiveEndpoints.
+func isExpensive(r *http.Request) bool {
+	for _, e := range expensiveEndpoints {
+		if r.Method == e.method && strings.HasPrefix(r.URL.Path, e.pathPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipKey returns the part of the given ip address that requests from it are limited by. That's the whole address
+// for IPv4, but only the /64 network for IPv6, since anyone with an IPv6 address usually has the whole /64 of it.
+func ipKey(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.To4() != nil {
+		return ip
+	}
+	return parsed.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
@@ -0,0 +1,169 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package security_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/security"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	testApplications map[string]*gtsmodel.Application
+	testTokens       map[string]*gtsmodel.Token
+}
+
+func (suite *RateLimitTestSuite) SetupSuite() {
+	suite.testApplications = testrig.NewTestApplications()
+	suite.testTokens = testrig.NewTestTokens()
+}
+
+func (suite *RateLimitTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+}
+
+// newModule returns a security module that lets each client make the given number of requests,
+// and the given number of requests to expensive endpoints.
+func (suite *RateLimitTestSuite) newModule(requests int, expensiveRequests int) *security.Module {
+	viper.Set(config.Keys.RateLimitRequests, requests)
+	viper.Set(config.Keys.RateLimitExpensiveRequests, expensiveRequests)
+	return security.New(nil, nil).(*security.Module)
+}
+
+// request runs the RateLimit middleware on a request to the given path, made from the given ip address
+// with the given access token, which can be empty for a request without one.
+func (suite *RateLimitTestSuite) request(m *security.Module, method string, path string, ip string, tokenName string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(method, "http://localhost:8080"+path, nil)
+	ctx.Request.RemoteAddr = ip + ":12345"
+
+	if tokenName != "" {
+		// this is what TokenCheck sets once it's accepted a token
+		ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+		ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens[tokenName]))
+	}
+
+	m.RateLimit(ctx)
+	if !ctx.IsAborted() {
+		ctx.Status(http.StatusOK)
+	}
+	return recorder
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitHeaders() {
+	m := suite.newModule(2, 0)
+
+	recorder := suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("2", recorder.Header().Get("X-RateLimit-Limit"))
+	suite.Equal("1", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	reset, err := time.Parse(time.RFC3339, recorder.Header().Get("X-RateLimit-Reset"))
+	suite.NoError(err)
+	suite.WithinDuration(time.Now(), reset, 5*time.Minute)
+	suite.Empty(recorder.Header().Get("Retry-After"))
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitTooManyRequests() {
+	m := suite.newModule(2, 0)
+
+	for i := 0; i < 2; i++ {
+		suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "").Code)
+	}
+
+	recorder := suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "")
+	suite.Equal(http.StatusTooManyRequests, recorder.Code)
+	suite.Equal(`{"error":"too many requests"}`, recorder.Body.String())
+	suite.Equal("0", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	// one token comes back every two and a half minutes
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	suite.NoError(err)
+	suite.InDelta(150, retryAfter, 1)
+
+	// other ip addresses have their own limit
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.2", "").Code)
+
+	// but the rest of the same IPv6 /64 doesn't
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "2001:db8::1", "").Code)
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "2001:db8::2", "").Code)
+	suite.Equal(http.StatusTooManyRequests, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "2001:db8::3", "").Code)
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitByToken() {
+	m := suite.newModule(1, 0)
+
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "local_account_1").Code)
+	suite.Equal(http.StatusTooManyRequests, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.2", "local_account_1").Code)
+
+	// requests with a different token, or without one, aren't held up by it
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "local_account_2").Code)
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "").Code)
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitExpensive() {
+	m := suite.newModule(5, 1)
+
+	suite.Equal(http.StatusOK, suite.request(m, http.MethodGet, "/api/v2/search", "192.0.2.1", "").Code)
+
+	recorder := suite.request(m, http.MethodGet, "/api/v2/search", "192.0.2.1", "")
+	suite.Equal(http.StatusTooManyRequests, recorder.Code)
+	suite.Equal("1", recorder.Header().Get("X-RateLimit-Limit"))
+
+	// other endpoints have the normal limit
+	recorder = suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal("5", recorder.Header().Get("X-RateLimit-Limit"))
+}
+
+func (suite *RateLimitTestSuite) TestNoRateLimit() {
+	m := suite.newModule(0, 0)
+
+	for i := 0; i < 10; i++ {
+		recorder := suite.request(m, http.MethodGet, "/api/v1/timelines/home", "192.0.2.1", "")
+		suite.Equal(http.StatusOK, recorder.Code)
+		suite.Empty(recorder.Header().Get("X-RateLimit-Limit"))
+	}
+
+	// and paths outside of the client API are never limited
+	m = suite.newModule(1, 0)
+	for i := 0; i < 10; i++ {
+		recorder := suite.request(m, http.MethodGet, "/@the_mighty_zork", "192.0.2.1", "")
+		suite.Equal(http.StatusOK, recorder.Code)
+		suite.Empty(recorder.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
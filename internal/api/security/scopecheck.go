@@ -0,0 +1,170 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package security
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/oauth2/v4"
+)
+
+// scopedEndpoint is a client API endpoint, or group of endpoints, along with the scopes that a token
+// needs to be granted to read from it (GET and HEAD requests) or to write to it (all other requests).
+//
+// An empty scope means that no particular scope is needed, because the endpoint is public.
+type scopedEndpoint struct {
+	pathPrefix string
+	read       oauth.Scope
+	write      oauth.Scope
+}
+
+// scopedEndpoints are matched in order against the route of a request, so more specific
+// path prefixes must come before the less specific ones that they start with.
+//
+// Requests to client API routes that aren't covered here need the broad read or write scope.
+var scopedEndpoints = []scopedEndpoint{
+	// public endpoints, or those that do their own token checks
+	{"/api/v1/apps", "", ""},
+	{"/api/v1/instance", "", oauth.ScopeAdminWrite},
+	{"/api/v2/instance", "", ""},
+	{"/api/v1/custom_emojis", "", ""},
+	{"/api/v1/directory", "", ""},
+	{"/api/v1/trends", "", ""},
+	{"/api/v1/streaming/health", "", ""},
+
+	// accounts
+	{"/api/v1/accounts/relationships", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+	{"/api/v1/accounts/:id/statuses", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/accounts/:id/follow", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+	{"/api/v1/accounts/:id/unfollow", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+	{"/api/v1/accounts/:id/block", oauth.ScopeReadBlocks, oauth.ScopeWriteBlocks},
+	{"/api/v1/accounts/:id/unblock", oauth.ScopeReadBlocks, oauth.ScopeWriteBlocks},
+	{"/api/v1/accounts/:id/mute", oauth.ScopeReadMutes, oauth.ScopeWriteMutes},
+	{"/api/v1/accounts/:id/unmute", oauth.ScopeReadMutes, oauth.ScopeWriteMutes},
+	{"/api/v1/accounts", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/user", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/preferences", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/profile", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/endorsements", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/featured_tags", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/exports", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/imports", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+
+	// relationships
+	{"/api/v1/blocks", oauth.ScopeReadBlocks, oauth.ScopeWriteBlocks},
+	{"/api/v1/mutes", oauth.ScopeReadMutes, oauth.ScopeWriteMutes},
+	{"/api/v1/follow_requests", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+	{"/api/v1/followed_tags", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+	{"/api/v1/tags", oauth.ScopeReadFollows, oauth.ScopeWriteFollows},
+
+	// statuses
+	{"/api/v1/statuses/:id/favourite", oauth.ScopeReadFavourites, oauth.ScopeWriteFavourites},
+	{"/api/v1/statuses/:id/unfavourite", oauth.ScopeReadFavourites, oauth.ScopeWriteFavourites},
+	{"/api/v1/statuses/:id/bookmark", oauth.ScopeReadBookmarks, oauth.ScopeWriteBookmarks},
+	{"/api/v1/statuses/:id/unbookmark", oauth.ScopeReadBookmarks, oauth.ScopeWriteBookmarks},
+	{"/api/v1/statuses/:id/mute", oauth.ScopeReadMutes, oauth.ScopeWriteMutes},
+	{"/api/v1/statuses/:id/unmute", oauth.ScopeReadMutes, oauth.ScopeWriteMutes},
+	{"/api/v1/statuses/:id/translate", oauth.ScopeReadStatuses, oauth.ScopeReadStatuses},
+	{"/api/v1/statuses/:id/pin", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/statuses/:id/unpin", oauth.ScopeReadAccounts, oauth.ScopeWriteAccounts},
+	{"/api/v1/statuses", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/pleroma/statuses", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/polls", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/media", oauth.ScopeReadStatuses, oauth.ScopeWriteMedia},
+	{"/api/v2/media", oauth.ScopeReadStatuses, oauth.ScopeWriteMedia},
+	{"/api/v1/timelines", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/streaming", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/markers", oauth.ScopeReadStatuses, oauth.ScopeWriteStatuses},
+	{"/api/v1/conversations", oauth.ScopeReadStatuses, oauth.ScopeWriteConversations},
+	{"/api/v1/favourites", oauth.ScopeReadFavourites, oauth.ScopeWriteFavourites},
+	{"/api/v1/bookmarks", oauth.ScopeReadBookmarks, oauth.ScopeWriteBookmarks},
+
+	// everything else
+	{"/api/v1/notifications", oauth.ScopeReadNotifications, oauth.ScopeWriteNotifications},
+	{"/api/v2/notifications", oauth.ScopeReadNotifications, oauth.ScopeWriteNotifications},
+	{"/api/v1/push", oauth.ScopePush, oauth.ScopePush},
+	{"/api/v1/lists", oauth.ScopeReadLists, oauth.ScopeWriteLists},
+	{"/api/v1/filters", oauth.ScopeReadFilters, oauth.ScopeWriteFilters},
+	{"/api/v2/filters", oauth.ScopeReadFilters, oauth.ScopeWriteFilters},
+	{"/api/v1/search", oauth.ScopeReadSearch, oauth.ScopeReadSearch},
+	{"/api/v2/search", oauth.ScopeReadSearch, oauth.ScopeReadSearch},
+	{"/api/v1/reports", oauth.ScopeWriteReports, oauth.ScopeWriteReports},
+
+	// admin
+	{"/api/v1/admin/accounts", oauth.ScopeAdminReadAccounts, oauth.ScopeAdminWriteAccounts},
+	{"/api/v1/admin/reports", oauth.ScopeAdminReadReports, oauth.ScopeAdminWriteReports},
+	{"/api/v1/admin/domain_blocks", oauth.ScopeAdminReadDomainBlocks, oauth.ScopeAdminWriteDomainBlocks},
+	{"/api/v1/admin/email_domain_blocks", oauth.ScopeAdminReadEmailDomainBlocks, oauth.ScopeAdminWriteEmailDomainBlocks},
+	{"/api/v1/admin/ip_blocks", oauth.ScopeAdminReadIPBlocks, oauth.ScopeAdminWriteIPBlocks},
+	{"/api/v1/admin", oauth.ScopeAdminRead, oauth.ScopeAdminWrite},
+}
+
+// ScopeCheck refuses requests to the client API that were made with a token which
+// hasn't been granted the scope needed for the route of the request.
+//
+// It relies on TokenCheck having already validated the token of the request, if there was one.
+// Requests without a token aren't checked here, since it's up to each handler to decide whether
+// or not a token is required.
+func (m *Module) ScopeCheck(c *gin.Context) {
+	i, ok := c.Get(oauth.SessionAuthorizedToken)
+	if !ok {
+		return
+	}
+
+	ti, ok := i.(oauth2.TokenInfo)
+	if !ok {
+		return
+	}
+
+	scope := requiredScope(c.Request.Method, c.FullPath())
+	if scope == "" || scope.PermittedBy(ti.GetScope()) {
+		return
+	}
+
+	logrus.WithField("func", "ScopeCheck").Debugf("refusing request to %s because token scope %q doesn't grant %s", c.FullPath(), ti.GetScope(), scope)
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This action is outside the authorized scopes"})
+}
+
+// requiredScope returns the scope needed to make a request with the given method to the given route,
+// or an empty scope if the route isn't part of the client API or doesn't need a particular scope.
+func requiredScope(method string, route string) oauth.Scope {
+	if !strings.HasPrefix(route, "/api/") {
+		return ""
+	}
+
+	read := method == http.MethodGet || method == http.MethodHead
+	for _, e := range scopedEndpoints {
+		if route != e.pathPrefix && !strings.HasPrefix(route, e.pathPrefix+"/") {
+			continue
+		}
+		if read {
+			return e.read
+		}
+		return e.write
+	}
+
+	if read {
+		return oauth.ScopeRead
+	}
+	return oauth.ScopeWrite
+}
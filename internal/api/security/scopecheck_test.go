@@ -0,0 +1,185 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package security_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/security"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ScopeCheckTestSuite struct {
+	suite.Suite
+	testTokens map[string]*gtsmodel.Token
+}
+
+func (suite *ScopeCheckTestSuite) SetupSuite() {
+	suite.testTokens = testrig.NewTestTokens()
+}
+
+func (suite *ScopeCheckTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+}
+
+// request runs the ScopeCheck middleware on a request with the given method to the given path, which is
+// served on the given route, made with a token that's been granted the given scope. If scope is nil,
+// the request is made without a token.
+func (suite *ScopeCheckTestSuite) request(method string, route string, path string, scope *string) *httptest.ResponseRecorder {
+	m := security.New(nil, nil).(*security.Module)
+
+	recorder := httptest.NewRecorder()
+	_, engine := gin.CreateTestContext(recorder)
+	engine.Handle(method, route, func(c *gin.Context) {
+		if scope != nil {
+			// this is what TokenCheck sets once it's accepted a token
+			token := *suite.testTokens["local_account_1"]
+			token.Scope = *scope
+			c.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(&token))
+		}
+	}, m.ScopeCheck, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	engine.ServeHTTP(recorder, httptest.NewRequest(method, "http://localhost:8080"+path, nil))
+	return recorder
+}
+
+func scope(s string) *string {
+	return &s
+}
+
+func (suite *ScopeCheckTestSuite) TestNoToken() {
+	// it's up to the handler to decide whether a token is needed
+	recorder := suite.request(http.MethodPost, "/api/v1/statuses", "/api/v1/statuses", nil)
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestBroadScope() {
+	recorder := suite.request(http.MethodGet, "/api/v1/timelines/home", "/api/v1/timelines/home", scope("read write follow push"))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.request(http.MethodPost, "/api/v1/statuses", "/api/v1/statuses", scope("read write follow push"))
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestEmptyScope() {
+	// a token without a scope is treated as having the default read scope
+	recorder := suite.request(http.MethodGet, "/api/v1/accounts/:id", "/api/v1/accounts/01F8MH1H7YV1Z7D2C8K2730QBF", scope(""))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.request(http.MethodPost, "/api/v1/statuses", "/api/v1/statuses", scope(""))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestGranularScope() {
+	recorder := suite.request(http.MethodGet, "/api/v1/statuses/:id", "/api/v1/statuses/01F8MHAMCHF6Y650WCRSCP4WMY", scope("read:statuses"))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	// reading statuses doesn't grant reading accounts
+	recorder = suite.request(http.MethodGet, "/api/v1/accounts/:id", "/api/v1/accounts/01F8MH1H7YV1Z7D2C8K2730QBF", scope("read:statuses"))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	suite.Equal(`{"error":"This action is outside the authorized scopes"}`, recorder.Body.String())
+
+	// or writing statuses
+	recorder = suite.request(http.MethodPost, "/api/v1/statuses", "/api/v1/statuses", scope("read:statuses"))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	recorder = suite.request(http.MethodPost, "/api/v2/media", "/api/v2/media", scope("write:media"))
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestFollowScope() {
+	// the legacy follow scope grants blocks, but not other write scopes
+	recorder := suite.request(http.MethodPost, "/api/v1/accounts/:id/block", "/api/v1/accounts/01F8MH5ZK5VRH73AKHQM6Y9VNX/block", scope("follow"))
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	recorder = suite.request(http.MethodPatch, "/api/v1/accounts/update_credentials", "/api/v1/accounts/update_credentials", scope("follow"))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestAdminScope() {
+	recorder := suite.request(http.MethodGet, "/api/v1/admin/reports", "/api/v1/admin/reports", scope("read write follow push"))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+
+	recorder = suite.request(http.MethodGet, "/api/v1/admin/reports", "/api/v1/admin/reports", scope("read write follow push admin"))
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestNotClientAPI() {
+	recorder := suite.request(http.MethodGet, "/users/:username", "/users/the_mighty_zork", scope("write:media"))
+	suite.Equal(http.StatusOK, recorder.Code)
+}
+
+func (suite *ScopeCheckTestSuite) TestRequiredScope() {
+	for _, test := range []struct {
+		method string
+		route  string
+		scope  oauth.Scope
+	}{
+		// public endpoints
+		{http.MethodGet, "/api/v1/instance", ""},
+		{http.MethodPatch, "/api/v1/instance", oauth.ScopeAdminWrite},
+		{http.MethodPost, "/api/v1/apps", ""},
+		{http.MethodGet, "/api/v1/streaming/health", ""},
+
+		// more specific prefixes win over less specific ones
+		{http.MethodGet, "/api/v1/accounts/relationships", oauth.ScopeReadFollows},
+		{http.MethodGet, "/api/v1/accounts/:id/statuses", oauth.ScopeReadStatuses},
+		{http.MethodPost, "/api/v1/accounts/:id/follow", oauth.ScopeWriteFollows},
+		{http.MethodPost, "/api/v1/accounts/:id/mute", oauth.ScopeWriteMutes},
+		{http.MethodGet, "/api/v1/accounts/:id", oauth.ScopeReadAccounts},
+		{http.MethodPost, "/api/v1/statuses/:id/favourite", oauth.ScopeWriteFavourites},
+		{http.MethodPost, "/api/v1/statuses/:id/pin", oauth.ScopeWriteAccounts},
+		{http.MethodDelete, "/api/v1/statuses/:id", oauth.ScopeWriteStatuses},
+		{http.MethodGet, "/api/v1/admin/reports/:id", oauth.ScopeAdminReadReports},
+		{http.MethodPost, "/api/v1/admin/custom_emojis", oauth.ScopeAdminWrite},
+
+		// reads and writes
+		{http.MethodHead, "/api/v1/lists", oauth.ScopeReadLists},
+		{http.MethodPut, "/api/v1/lists/:id", oauth.ScopeWriteLists},
+		{http.MethodGet, "/api/v1/media/:id", oauth.ScopeReadStatuses},
+		{http.MethodPost, "/api/v2/media", oauth.ScopeWriteMedia},
+		{http.MethodGet, "/api/v2/search", oauth.ScopeReadSearch},
+
+		// prefixes only match whole path segments
+		{http.MethodGet, "/api/v1/listsomething", oauth.ScopeRead},
+
+		// routes that aren't covered need the broad scopes
+		{http.MethodGet, "/api/v1/something_new", oauth.ScopeRead},
+		{http.MethodPost, "/api/v1/something_new", oauth.ScopeWrite},
+
+		// routes outside the client API don't need a scope
+		{http.MethodPost, "/oauth/token", ""},
+		{http.MethodGet, "/users/:username", ""},
+	} {
+		suite.Equal(test.scope, security.RequiredScope(test.method, test.route), test.method+" "+test.route)
+	}
+}
+
+func TestScopeCheckTestSuite(t *testing.T) {
+	suite.Run(t, &ScopeCheckTestSuite{})
+}
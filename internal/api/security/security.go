@@ -21,9 +21,12 @@ package security
 import (
 	"net/http"
 
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/internal/api"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/ratelimit"
 	"github.com/superseriousbusiness/gotosocial/internal/router"
 )
 
@@ -31,25 +34,37 @@ const robotsPath = "/robots.txt"
 
 // Module implements the ClientAPIModule interface for security middleware
 type Module struct {
-	db     db.DB
-	server oauth.Server
+	db                   db.DB
+	server               oauth.Server
+	rateLimiter          *ratelimit.Limiter
+	expensiveRateLimiter *ratelimit.Limiter // nil if expensive endpoints share the normal rate limit
 }
 
 // New returns a new security module
 func New(db db.DB, server oauth.Server) api.ClientModule {
-	return &Module{
-		db:     db,
-		server: server,
+	m := &Module{
+		db:          db,
+		server:      server,
+		rateLimiter: ratelimit.New(viper.GetInt(config.Keys.RateLimitRequests), rateLimitPeriod),
 	}
+
+	if expensiveLimit := viper.GetInt(config.Keys.RateLimitExpensiveRequests); expensiveLimit > 0 {
+		m.expensiveRateLimiter = ratelimit.New(expensiveLimit, rateLimitPeriod)
+	}
+
+	return m
 }
 
 // Route attaches security middleware to the given router
 func (m *Module) Route(s router.Router) error {
+	s.AttachMiddleware(m.IPBlock)
 	s.AttachMiddleware(m.SignatureCheck)
 	s.AttachMiddleware(m.FlocBlock)
 	s.AttachMiddleware(m.ExtraHeaders)
 	s.AttachMiddleware(m.UserAgentBlock)
 	s.AttachMiddleware(m.TokenCheck)
+	s.AttachMiddleware(m.ScopeCheck)
+	s.AttachMiddleware(m.RateLimit)
 	s.AttachHandler(http.MethodGet, robotsPath, m.RobotsGETHandler)
 	return nil
 }
@@ -0,0 +1,225 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package blocklist reads and writes lists of domain blocks, so that they can be shared between instances.
+package blocklist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// severitySuspend and severitySilence are the Mastodon severities that match the severities of a GoToSocial
+// domain block. Mastodon also has a 'noop' severity, but there's nothing it corresponds to here.
+const (
+	severitySuspend = "suspend"
+	severitySilence = "silence"
+)
+
+// csvHeader is the header row of a blocklist exported from Mastodon, plus a column for whether the block
+// includes subdomains. Mastodon matches columns up by name, so it just ignores the extra one.
+var csvHeader = []string{"#domain", "#severity", "#reject_media", "#reject_reports", "#public_comment", "#obfuscate", "#include_subdomains"}
+
+// wildcardPrefix can be put in front of a domain in a blocklist to block all of its subdomains too.
+const wildcardPrefix = "*."
+
+// Entry is a single domain block in a blocklist.
+type Entry struct {
+	Domain            string `json:"domain"`
+	Severity          string `json:"severity,omitempty"`
+	Obfuscate         bool   `json:"obfuscate,omitempty"`
+	IncludeSubdomains bool   `json:"include_subdomains,omitempty"`
+	RejectMedia       bool   `json:"reject_media,omitempty"`
+	PublicComment     string `json:"public_comment,omitempty"`
+	PrivateComment    string `json:"private_comment,omitempty"`
+}
+
+// Parse reads a blocklist from r, which can either be a JSON array of domain blocks, like the one exported
+// by GoToSocial or Mastodon's admin API, or a CSV file, like the one exported by Mastodon's admin panel.
+// CSV files without a header row are read as a list of domains, one per line.
+//
+// Entries that can't be turned into a domain block are skipped, and their domains returned separately:
+// that's entries with a severity other than 'suspend' or 'silence', and entries with obfuscated domain names like
+// 'ex**ple.org', which can turn up in blocklists that were copied from an instance's public list of blocks.
+// Domains written as wildcards, like '*.example.org', are read as blocks on example.org that include subdomains.
+func Parse(r io.Reader) (entries []Entry, skipped []string, err error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading blocklist: %s", err)
+	}
+
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf")) // utf-8 byte order mark
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil, nil, errors.New("blocklist is empty")
+	}
+
+	var parsed []Entry
+	if b[0] == '[' {
+		if err := json.Unmarshal(b, &parsed); err != nil {
+			return nil, nil, fmt.Errorf("error parsing json blocklist: %s", err)
+		}
+	} else {
+		parsed, err = parseCSV(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing csv blocklist: %s", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(parsed))
+	for _, e := range parsed {
+		e.Domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(e.Domain)), ".")
+		e.Severity = strings.ToLower(strings.TrimSpace(e.Severity))
+		if strings.HasPrefix(e.Domain, wildcardPrefix) {
+			e.Domain = strings.TrimPrefix(e.Domain, wildcardPrefix)
+			e.IncludeSubdomains = true
+		}
+		if e.Domain == "" || seen[e.Domain] {
+			continue
+		}
+		seen[e.Domain] = true
+
+		if strings.Contains(e.Domain, "*") || (e.Severity != "" && e.Severity != severitySuspend && e.Severity != severitySilence) {
+			skipped = append(skipped, e.Domain)
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, skipped, nil
+}
+
+// parseCSV reads the entries from a csv blocklist. Columns are matched up using the header row,
+// if there is one, with or without the '#' that Mastodon puts at the start of each column name.
+func parseCSV(b []byte) ([]Entry, error) {
+	reader := csv.NewReader(bytes.NewReader(b))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// without a header, the only column we can be sure of is the domain
+	columns := map[string]int{"domain": 0}
+	if len(records) > 0 && isCSVHeader(records[0]) {
+		columns = make(map[string]int, len(records[0]))
+		for i, name := range records[0] {
+			columns[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "#")] = i
+		}
+		records = records[1:]
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		e := Entry{
+			Domain:         field(record, "domain"),
+			Severity:       field(record, "severity"),
+			PublicComment:  field(record, "public_comment"),
+			PrivateComment: field(record, "private_comment"),
+		}
+
+		if obfuscate := strings.TrimSpace(field(record, "obfuscate")); obfuscate != "" {
+			e.Obfuscate, err = strconv.ParseBool(obfuscate)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse obfuscate value %q for domain %s: %s", obfuscate, e.Domain, err)
+			}
+		}
+
+		if includeSubdomains := strings.TrimSpace(field(record, "include_subdomains")); includeSubdomains != "" {
+			e.IncludeSubdomains, err = strconv.ParseBool(includeSubdomains)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse include_subdomains value %q for domain %s: %s", includeSubdomains, e.Domain, err)
+			}
+		}
+
+		if rejectMedia := strings.TrimSpace(field(record, "reject_media")); rejectMedia != "" {
+			e.RejectMedia, err = strconv.ParseBool(rejectMedia)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse reject_media value %q for domain %s: %s", rejectMedia, e.Domain, err)
+			}
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// isCSVHeader returns true if the given csv record looks like a header row rather than a domain block.
+func isCSVHeader(record []string) bool {
+	for _, name := range record {
+		if strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "#") == "domain" {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteCSV writes the given entries to w as a csv blocklist, in the format that Mastodon exports and imports.
+// Private comments aren't written, since blocklists are meant to be shared. Entries without a severity are written as suspensions.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("error writing csv blocklist: %s", err)
+	}
+
+	for _, e := range entries {
+		severity := e.Severity
+		if severity == "" {
+			severity = severitySuspend
+		}
+
+		record := []string{
+			e.Domain,
+			severity,
+			strconv.FormatBool(e.RejectMedia),
+			"false",
+			e.PublicComment,
+			strconv.FormatBool(e.Obfuscate),
+			strconv.FormatBool(e.IncludeSubdomains),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing csv blocklist: %s", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("error writing csv blocklist: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package blocklist_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/blocklist"
+)
+
+type BlocklistTestSuite struct {
+	suite.Suite
+}
+
+func (suite *BlocklistTestSuite) TestParseMastodonCSV() {
+	csv := `#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate
+example.org,suspend,false,false,they smell,false
+Whatever.COM,suspend,true,false,"spam, and lots of it",true
+quiet.example.net,silence,false,false,,false
+loud.example.net,noop,false,false,,false
+ex**ple.net,suspend,false,false,,true
+`
+
+	entries, skipped, err := blocklist.Parse(strings.NewReader(csv))
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{
+		{Domain: "example.org", Severity: "suspend", PublicComment: "they smell"},
+		{Domain: "whatever.com", Severity: "suspend", PublicComment: "spam, and lots of it", Obfuscate: true, RejectMedia: true},
+		{Domain: "quiet.example.net", Severity: "silence"},
+	}, entries)
+	suite.Equal([]string{"loud.example.net", "ex**ple.net"}, skipped)
+}
+
+func (suite *BlocklistTestSuite) TestParseCSVWithoutHeader() {
+	entries, skipped, err := blocklist.Parse(strings.NewReader("example.org\nwhatever.com.\n\nexample.org\n"))
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{{Domain: "example.org"}, {Domain: "whatever.com"}}, entries)
+	suite.Empty(skipped)
+}
+
+func (suite *BlocklistTestSuite) TestParseCSVPrivateComment() {
+	csv := "domain,private_comment,obfuscate\nexample.org,reported by several users,1\n"
+
+	entries, _, err := blocklist.Parse(strings.NewReader(csv))
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{{Domain: "example.org", PrivateComment: "reported by several users", Obfuscate: true}}, entries)
+}
+
+func (suite *BlocklistTestSuite) TestParseWildcards() {
+	csv := "#domain,#include_subdomains\n*.example.org,false\nwhatever.com,true\n*.ex**ple.net,false\n"
+
+	entries, skipped, err := blocklist.Parse(strings.NewReader(csv))
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{
+		{Domain: "example.org", IncludeSubdomains: true},
+		{Domain: "whatever.com", IncludeSubdomains: true},
+	}, entries)
+	suite.Equal([]string{"ex**ple.net"}, skipped)
+}
+
+func (suite *BlocklistTestSuite) TestParseCSVBadObfuscate() {
+	_, _, err := blocklist.Parse(strings.NewReader("#domain,#obfuscate\nexample.org,maybe\n"))
+	suite.EqualError(err, `error parsing csv blocklist: couldn't parse obfuscate value "maybe" for domain example.org: strconv.ParseBool: parsing "maybe": invalid syntax`)
+}
+
+func (suite *BlocklistTestSuite) TestParseJSON() {
+	json := `[{"domain":"example.org"},{"domain":"whatever.com","public_comment":"they smell","obfuscate":true},{"domain":"quiet.example.net","severity":"silence"},{"domain":"loud.example.net","severity":"noop"}]`
+
+	entries, skipped, err := blocklist.Parse(strings.NewReader(json))
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{
+		{Domain: "example.org"},
+		{Domain: "whatever.com", PublicComment: "they smell", Obfuscate: true},
+		{Domain: "quiet.example.net", Severity: "silence"},
+	}, entries)
+	suite.Equal([]string{"loud.example.net"}, skipped)
+}
+
+func (suite *BlocklistTestSuite) TestParseEmpty() {
+	_, _, err := blocklist.Parse(strings.NewReader(" \n"))
+	suite.EqualError(err, "blocklist is empty")
+}
+
+func (suite *BlocklistTestSuite) TestWriteCSV() {
+	entries := []blocklist.Entry{
+		{Domain: "example.org", PublicComment: "they smell", PrivateComment: "don't tell anyone"},
+		{Domain: "whatever.com", PublicComment: "spam, and lots of it", Obfuscate: true, IncludeSubdomains: true, RejectMedia: true},
+		{Domain: "quiet.example.net", Severity: "silence"},
+	}
+
+	buf := &bytes.Buffer{}
+	suite.NoError(blocklist.WriteCSV(buf, entries))
+	suite.Equal(`#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate,#include_subdomains
+example.org,suspend,false,false,they smell,false,false
+whatever.com,suspend,true,false,"spam, and lots of it",true,true
+quiet.example.net,silence,false,false,,false,false
+`, buf.String())
+
+	// what's written can be read back in again, minus the private comment
+	parsed, _, err := blocklist.Parse(buf)
+	suite.NoError(err)
+	suite.Equal([]blocklist.Entry{
+		{Domain: "example.org", Severity: "suspend", PublicComment: "they smell"},
+		{Domain: "whatever.com", Severity: "suspend", PublicComment: "spam, and lots of it", Obfuscate: true, IncludeSubdomains: true, RejectMedia: true},
+		{Domain: "quiet.example.net", Severity: "silence"},
+	}, parsed)
+}
+
+func TestBlocklistTestSuite(t *testing.T) {
+	suite.Run(t, &BlocklistTestSuite{})
+}
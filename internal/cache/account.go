@@ -134,6 +134,8 @@ func copyAccount(account *gtsmodel.Account) *gtsmodel.Account {
 		DisplayName:             account.DisplayName,
 		Fields:                  account.Fields,
 		Note:                    account.Note,
+		EmojiIDs:                account.EmojiIDs,
+		Emojis:                  nil,
 		Memorial:                account.Memorial,
 		MovedToAccountID:        account.MovedToAccountID,
 		CreatedAt:               account.CreatedAt,
@@ -149,19 +151,28 @@ func copyAccount(account *gtsmodel.Account) *gtsmodel.Account {
 		URL:                     account.URL,
 		LastWebfingeredAt:       account.LastWebfingeredAt,
 		InboxURI:                account.InboxURI,
+		SharedInboxURI:          account.SharedInboxURI,
 		OutboxURI:               account.OutboxURI,
 		FollowingURI:            account.FollowingURI,
 		FollowersURI:            account.FollowersURI,
 		FeaturedCollectionURI:   account.FeaturedCollectionURI,
 		ActorType:               account.ActorType,
 		AlsoKnownAs:             account.AlsoKnownAs,
+		AlsoKnownAsURIs:         account.AlsoKnownAsURIs,
 		PrivateKey:              account.PrivateKey,
 		PublicKey:               account.PublicKey,
+		PublicKeyEd25519:        account.PublicKeyEd25519,
 		PublicKeyURI:            account.PublicKeyURI,
+		AssertionPrivateKey:     account.AssertionPrivateKey,
+		AssertionPublicKey:      account.AssertionPublicKey,
+		AssertionKeyURI:         account.AssertionKeyURI,
 		SensitizedAt:            account.SensitizedAt,
 		SilencedAt:              account.SilencedAt,
 		SuspendedAt:             account.SuspendedAt,
 		HideCollections:         account.HideCollections,
+		ExcludedNotifications:   account.ExcludedNotifications,
+		ExpandMedia:             account.ExpandMedia,
+		ExpandSpoilers:          account.ExpandSpoilers,
 		SuspensionOrigin:        account.SuspensionOrigin,
 	}
 }
@@ -134,8 +134,11 @@ func copyStatus(status *gtsmodel.Status) *gtsmodel.Status {
 		Mentions:                 nil,
 		EmojiIDs:                 status.EmojiIDs,
 		Emojis:                   nil,
+		PollID:                   status.PollID,
+		Poll:                     nil,
 		CreatedAt:                status.CreatedAt,
 		UpdatedAt:                status.UpdatedAt,
+		EditedAt:                 status.EditedAt,
 		Local:                    status.Local,
 		AccountID:                status.AccountID,
 		Account:                  nil,
@@ -149,6 +152,9 @@ func copyStatus(status *gtsmodel.Status) *gtsmodel.Status {
 		BoostOf:                  nil,
 		BoostOfAccountID:         status.BoostOfAccountID,
 		BoostOfAccount:           nil,
+		QuoteOfID:                status.QuoteOfID,
+		QuoteOfURI:               status.QuoteOfURI,
+		QuoteOf:                  nil,
 		ContentWarning:           status.ContentWarning,
 		Visibility:               status.Visibility,
 		Sensitive:                status.Sensitive,
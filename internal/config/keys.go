@@ -55,15 +55,42 @@ type KeyNames struct {
 	AccountsReasonRequired   string
 
 	// media
-	MediaImageMaxSize        string
-	MediaVideoMaxSize        string
-	MediaDescriptionMinChars string
-	MediaDescriptionMaxChars string
-	MediaRemoteCacheDays     string
+	MediaImageMaxSize         string
+	MediaImageMaxPixels       string
+	MediaVideoMaxSize         string
+	MediaVideoMaxPixels       string
+	MediaVideoMaxDuration     string
+	MediaEmojiMaxSize         string
+	MediaEmojiMaxPixels       string
+	MediaUnknownMaxSize       string
+	MediaDescriptionMinChars  string
+	MediaDescriptionMaxChars  string
+	MediaRemoteCacheDays      string
+	MediaRemoteRecacheTimeout string
+	MediaAccountQuota         string
+	MediaThumbnailMaxWidth    string
+	MediaThumbnailMaxHeight   string
+	MediaThumbnailVariants    string
+	MediaJpegProgressive      string
+	MediaGifvEnabled          string
+	MediaGifvMinSize          string
+	MediaGifvKeepOriginal     string
+	MediaGifvFfmpegPath       string
+	MediaJobTimeout           string
+	MediaRecacheBatchRate     string
+	MediaIntegrityCheckSample string
+	MediaRemoteFetchRate      string
+	MediaCacheWarmCount       string
 
 	// storage
 	StorageBackend       string
 	StorageLocalBasePath string
+	StorageS3Endpoint    string
+	StorageS3AccessKey   string
+	StorageS3SecretKey   string
+	StorageS3UseSSL      string
+	StorageS3BucketName  string
+	StorageS3Proxy       string
 
 	// statuses
 	StatusesMaxChars           string
@@ -71,6 +98,20 @@ type KeyNames struct {
 	StatusesPollMaxOptions     string
 	StatusesPollOptionMaxChars string
 	StatusesMediaMaxFiles      string
+	StatusesQuotesEnabled      string
+	StatusesReactionsEnabled   string
+	StatusesTrendsDays         string
+	StatusesTrendsApproval     string
+	StatusesSearchEnabled      string
+
+	// federation
+	FederationUnreachableDays  string
+	FederationNodeInfoMetadata string
+	FederationInboxRateLimit   string
+	FederationHideCollections  string
+	FederationRefreshDays      string
+	FederationRefreshPerDomain string
+	FederationRefuseActivities string
 
 	// letsencrypt
 	LetsEncryptEnabled      string
@@ -99,11 +140,26 @@ type KeyNames struct {
 	SyslogProtocol string
 	SyslogAddress  string
 
+	// metrics
+	MetricsEnabled string
+
+	// rate limiting
+	RateLimitRequests          string
+	RateLimitExpensiveRequests string
+
+	// translation
+	TranslationBackend  string
+	TranslationEndpoint string
+	TranslationAPIKey   string
+
 	// admin
 	AdminAccountUsername string
 	AdminAccountEmail    string
 	AdminAccountPassword string
+	AdminAccountQuota    string
 	AdminTransPath       string
+	AdminMediaFrom       string
+	AdminMediaTo         string
 }
 
 // Keys contains the names of the various keys used for initializing and storing flag variables,
@@ -137,20 +193,60 @@ var Keys = KeyNames{
 	AccountsApprovalRequired: "accounts-approval-required",
 	AccountsReasonRequired:   "accounts-reason-required",
 
-	MediaImageMaxSize:        "media-image-max-size",
-	MediaVideoMaxSize:        "media-video-max-size",
-	MediaDescriptionMinChars: "media-description-min-chars",
-	MediaDescriptionMaxChars: "media-description-max-chars",
-	MediaRemoteCacheDays:     "media-remote-cache-days",
+	MediaImageMaxSize:         "media-image-max-size",
+	MediaImageMaxPixels:       "media-image-max-pixels",
+	MediaVideoMaxSize:         "media-video-max-size",
+	MediaVideoMaxPixels:       "media-video-max-pixels",
+	MediaVideoMaxDuration:     "media-video-max-duration",
+	MediaEmojiMaxSize:         "media-emoji-max-size",
+	MediaEmojiMaxPixels:       "media-emoji-max-pixels",
+	MediaUnknownMaxSize:       "media-unknown-max-size",
+	MediaDescriptionMinChars:  "media-description-min-chars",
+	MediaDescriptionMaxChars:  "media-description-max-chars",
+	MediaRemoteCacheDays:      "media-remote-cache-days",
+	MediaRemoteRecacheTimeout: "media-remote-recache-timeout",
+	MediaAccountQuota:         "media-account-quota",
+	MediaThumbnailMaxWidth:    "media-thumbnail-max-width",
+	MediaThumbnailMaxHeight:   "media-thumbnail-max-height",
+	MediaThumbnailVariants:    "media-thumbnail-variants",
+	MediaJpegProgressive:      "media-jpeg-progressive",
+	MediaGifvEnabled:          "media-gifv-enabled",
+	MediaGifvMinSize:          "media-gifv-min-size",
+	MediaGifvKeepOriginal:     "media-gifv-keep-original",
+	MediaGifvFfmpegPath:       "media-gifv-ffmpeg-path",
+	MediaJobTimeout:           "media-job-timeout",
+	MediaRecacheBatchRate:     "media-recache-batch-rate",
+	MediaIntegrityCheckSample: "media-integrity-check-sample",
+	MediaRemoteFetchRate:      "media-remote-fetch-rate",
+	MediaCacheWarmCount:       "media-cache-warm-count",
 
 	StorageBackend:       "storage-backend",
 	StorageLocalBasePath: "storage-local-base-path",
+	StorageS3Endpoint:    "storage-s3-endpoint",
+	StorageS3AccessKey:   "storage-s3-access-key",
+	StorageS3SecretKey:   "storage-s3-secret-key",
+	StorageS3UseSSL:      "storage-s3-use-ssl",
+	StorageS3BucketName:  "storage-s3-bucket",
+	StorageS3Proxy:       "storage-s3-proxy",
 
 	StatusesMaxChars:           "statuses-max-chars",
 	StatusesCWMaxChars:         "statuses-cw-max-chars",
 	StatusesPollMaxOptions:     "statuses-poll-max-options",
 	StatusesPollOptionMaxChars: "statuses-poll-option-max-chars",
 	StatusesMediaMaxFiles:      "statuses-media-max-files",
+	StatusesQuotesEnabled:      "statuses-quotes-enabled",
+	StatusesReactionsEnabled:   "statuses-reactions-enabled",
+	StatusesTrendsDays:         "statuses-trends-days",
+	StatusesTrendsApproval:     "statuses-trends-approval",
+	StatusesSearchEnabled:      "statuses-search-enabled",
+
+	FederationUnreachableDays:  "federation-unreachable-days",
+	FederationNodeInfoMetadata: "federation-nodeinfo-metadata",
+	FederationInboxRateLimit:   "federation-inbox-rate-limit",
+	FederationHideCollections:  "federation-hide-collections",
+	FederationRefreshDays:      "federation-refresh-days",
+	FederationRefreshPerDomain: "federation-refresh-per-domain",
+	FederationRefuseActivities: "federation-refuse-activities",
 
 	LetsEncryptEnabled:      "letsencrypt-enabled",
 	LetsEncryptPort:         "letsencrypt-port",
@@ -175,8 +271,20 @@ var Keys = KeyNames{
 	SyslogProtocol: "syslog-protocol",
 	SyslogAddress:  "syslog-address",
 
+	MetricsEnabled: "metrics-enabled",
+
+	RateLimitRequests:          "rate-limit-requests",
+	RateLimitExpensiveRequests: "rate-limit-expensive-requests",
+
+	TranslationBackend:  "translation-backend",
+	TranslationEndpoint: "translation-endpoint",
+	TranslationAPIKey:   "translation-api-key",
+
 	AdminAccountUsername: "username",
 	AdminAccountEmail:    "email",
 	AdminAccountPassword: "password",
+	AdminAccountQuota:    "quota",
 	AdminTransPath:       "path",
+	AdminMediaFrom:       "from",
+	AdminMediaTo:         "to",
 }
@@ -48,20 +48,60 @@ type Values struct {
 	AccountsApprovalRequired bool
 	AccountsReasonRequired   bool
 
-	MediaImageMaxSize        int
-	MediaVideoMaxSize        int
-	MediaDescriptionMinChars int
-	MediaDescriptionMaxChars int
-	MediaRemoteCacheDays     int
+	MediaImageMaxSize         int
+	MediaImageMaxPixels       int
+	MediaVideoMaxSize         int
+	MediaVideoMaxPixels       int
+	MediaVideoMaxDuration     int
+	MediaEmojiMaxSize         int
+	MediaEmojiMaxPixels       int
+	MediaUnknownMaxSize       int
+	MediaDescriptionMinChars  int
+	MediaDescriptionMaxChars  int
+	MediaRemoteCacheDays      int
+	MediaRemoteRecacheTimeout int
+	MediaAccountQuota         int
+	MediaThumbnailMaxWidth    int
+	MediaThumbnailMaxHeight   int
+	MediaThumbnailVariants    []string
+	MediaJpegProgressive      bool
+	MediaGifvEnabled          bool
+	MediaGifvMinSize          int
+	MediaGifvKeepOriginal     bool
+	MediaGifvFfmpegPath       string
+	MediaJobTimeout           int
+	MediaRecacheBatchRate     int
+	MediaIntegrityCheckSample int
+	MediaRemoteFetchRate      int
+	MediaCacheWarmCount       int
 
 	StorageBackend       string
 	StorageLocalBasePath string
+	StorageS3Endpoint    string
+	StorageS3AccessKey   string
+	StorageS3SecretKey   string
+	StorageS3UseSSL      bool
+	StorageS3BucketName  string
+	StorageS3Proxy       bool
 
 	StatusesMaxChars           int
 	StatusesCWMaxChars         int
 	StatusesPollMaxOptions     int
 	StatusesPollOptionMaxChars int
 	StatusesMediaMaxFiles      int
+	StatusesQuotesEnabled      bool
+	StatusesReactionsEnabled   bool
+	StatusesTrendsDays         int
+	StatusesTrendsApproval     bool
+	StatusesSearchEnabled      bool
+
+	FederationUnreachableDays  int
+	FederationNodeInfoMetadata map[string]string
+	FederationInboxRateLimit   int
+	FederationHideCollections  bool
+	FederationRefreshDays      int
+	FederationRefreshPerDomain int
+	FederationRefuseActivities []string
 
 	LetsEncryptEnabled      bool
 	LetsEncryptCertDir      string
@@ -86,8 +126,20 @@ type Values struct {
 	SyslogProtocol string
 	SyslogAddress  string
 
+	MetricsEnabled bool
+
+	RateLimitRequests          int
+	RateLimitExpensiveRequests int
+
+	TranslationBackend  string
+	TranslationEndpoint string
+	TranslationAPIKey   string
+
 	AdminAccountUsername string
 	AdminAccountEmail    string
 	AdminAccountPassword string
+	AdminAccountQuota    int
 	AdminTransPath       string
+	AdminMediaFrom       string
+	AdminMediaTo         string
 }
@@ -39,6 +39,15 @@ type Account interface {
 	// UpdateAccount updates one account by ID.
 	UpdateAccount(ctx context.Context, account *gtsmodel.Account) (*gtsmodel.Account, Error)
 
+	// VerifyAccountFields marks the profile fields of the given account that match the given fields, by name and value,
+	// as verified at the given time. Only the fields of the account are written, and only if they haven't changed in the
+	// meantime, so that a slow verification can't overwrite more recent changes to the account.
+	VerifyAccountFields(ctx context.Context, accountID string, verified []gtsmodel.Field, verifiedAt time.Time) Error
+
+	// UpdateAccountLastWebfingeredAt sets when the given account was last fetched from its instance, without
+	// touching any of its other fields or its UpdatedAt.
+	UpdateAccountLastWebfingeredAt(ctx context.Context, accountID string, lastWebfingeredAt time.Time) Error
+
 	// GetLocalAccountByUsername returns an account on this instance by its username.
 	GetLocalAccountByUsername(ctx context.Context, username string) (*gtsmodel.Account, Error)
 
@@ -51,11 +60,24 @@ type Account interface {
 	// GetAccountStatuses is a shortcut for getting the most recent statuses. accountID is optional, if not provided
 	// then all statuses will be returned. If limit is set to 0, the size of the returned slice will not be limited. This can
 	// be very memory intensive so you probably shouldn't do this!
+	// If minID is set without maxID, the statuses immediately newer than minID are returned, rather than the newest ones.
 	// In case of no entries, a 'no entries' error will be returned
 	GetAccountStatuses(ctx context.Context, accountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinnedOnly bool, mediaOnly bool, publicOnly bool) ([]*gtsmodel.Status, Error)
 
 	GetAccountBlocks(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, Error)
 
+	// GetAccountEndorsements returns the accounts that the given account features on its profile, most recently endorsed first,
+	// along with the endorsement IDs to use as max_id and min_id for the next and previous pages.
+	// If limit is 0, all endorsed accounts are returned.
+	GetAccountEndorsements(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, Error)
+
+	// GetAccountFeaturedTags returns the hashtags that the given account features on its profile, oldest first, with their tags populated.
+	GetAccountFeaturedTags(ctx context.Context, accountID string) ([]*gtsmodel.FeaturedTag, Error)
+
+	// GetAccountTagUsage returns how many public and unlisted statuses by the given account use the given tag,
+	// and when the latest of them was created. The returned time will be zero if there are no such statuses.
+	GetAccountTagUsage(ctx context.Context, accountID string, tagID string) (int, time.Time, Error)
+
 	// GetAccountLastPosted simply gets the timestamp of the most recent post by the account.
 	//
 	// The returned time will be zero if account has never posted anything.
@@ -67,4 +89,21 @@ type Account interface {
 	// GetInstanceAccount returns the instance account for the given domain.
 	// If domain is empty, this instance account will be returned.
 	GetInstanceAccount(ctx context.Context, domain string) (*gtsmodel.Account, Error)
+
+	// GetKnownInboxes returns one inbox for each remote domain that this instance knows about, so that an activity
+	// can be delivered to every known instance. The shared inbox of the domain is used if any of its accounts has one,
+	// and otherwise the inbox of one of its accounts. Suspended accounts aren't included.
+	//
+	// If exceptFollowersOf is set, domains that the account with that ID has followers on are left out.
+	GetKnownInboxes(ctx context.Context, exceptFollowersOf string) ([]string, Error)
+
+	// GetStaleRemoteAccounts returns up to limit remote accounts that haven't been fetched from their instance
+	// since olderThan, in order of ID, starting after the account with ID sinceID so that they can be paged through.
+	// If sinceID is empty, it starts from the beginning. Suspended accounts aren't included.
+	GetStaleRemoteAccounts(ctx context.Context, olderThan time.Time, sinceID string, limit int) ([]*gtsmodel.Account, Error)
+
+	// GetDirectoryAccounts returns up to limit accounts that have opted in to the profile directory, skipping the first offset.
+	// If newest is true, the most recently created accounts come first; otherwise the accounts that posted most recently come first.
+	// If local is true, only accounts on this instance are returned. Suspended, silenced and moved accounts aren't included.
+	GetDirectoryAccounts(ctx context.Context, newest bool, local bool, limit int, offset int) ([]*gtsmodel.Account, Error)
 }
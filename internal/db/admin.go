@@ -34,10 +34,14 @@ type Admin interface {
 	// IsEmailAvailable checks whether a given email address for a new account is available to be used on our domain.
 	// Return an error if:
 	// A) the email is already associated with an account
-	// B) we block signups from this email domain
+	// B) we block signups from this email domain, or from a domain that it's a subdomain of, in which case the error is an *ErrEmailDomainBlocked
 	// C) something went wrong in the db
 	IsEmailAvailable(ctx context.Context, email string) (bool, Error)
 
+	// CountEmailDomainBlockAttempt counts a signup attempt with the given email address on the email domain blocks
+	// that it falls under, if any, so admins can see how much each block is being used.
+	CountEmailDomainBlockAttempt(ctx context.Context, email string) Error
+
 	// NewSignup creates a new user in the database with the given parameters.
 	// By the time this function is called, it should be assumed that all the parameters have passed validation!
 	NewSignup(ctx context.Context, username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, Error)
@@ -51,4 +55,8 @@ type Admin interface {
 	// Ie., if the instance is hosted at 'example.org' the instance will have a domain of 'example.org'.
 	// This is needed for things like serving instance information through /api/v1/instance
 	CreateInstanceInstance(ctx context.Context) Error
+
+	// CreateInstanceVAPIDKeyPair creates the key pair that this instance uses to identify itself to web push services,
+	// if it doesn't exist yet. This needs to stay the same across restarts, since clients subscribe with its public key.
+	CreateInstanceVAPIDKeyPair(ctx context.Context) Error
 }
@@ -20,6 +20,7 @@ package bundb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -122,6 +123,91 @@ func (a *accountDB) UpdateAccount(ctx context.Context, account *gtsmodel.Account
 	return account, nil
 }
 
+func (a *accountDB) VerifyAccountFields(ctx context.Context, accountID string, verified []gtsmodel.Field, verifiedAt time.Time) db.Error {
+	// fields are stored as json, so the stored json of them is what's compared to make sure they haven't changed
+	var current string
+	if err := a.conn.
+		NewSelect().
+		Model((*gtsmodel.Account)(nil)).
+		Column("account.fields").
+		Where("account.id = ?", accountID).
+		Scan(ctx, &current); err != nil {
+		return a.conn.ProcessError(err)
+	}
+
+	if current == "" {
+		// no fields left to verify
+		return nil
+	}
+
+	fields := []gtsmodel.Field{}
+	if err := json.Unmarshal([]byte(current), &fields); err != nil {
+		return fmt.Errorf("error unmarshalling fields of account %s: %s", accountID, err)
+	}
+
+	var changed bool
+	for i, field := range fields {
+		for _, v := range verified {
+			if field.VerifiedAt.IsZero() && field.Name == v.Name && field.Value == v.Value {
+				fields[i].VerifiedAt = verifiedAt
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	updated, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("error marshalling fields of account %s: %s", accountID, err)
+	}
+
+	res, err := a.conn.
+		NewUpdate().
+		Model((*gtsmodel.Account)(nil)).
+		Set("fields = ?", string(updated)).
+		Where("account.id = ?", accountID).
+		Where("account.fields = ?", current).
+		Exec(ctx)
+	if err != nil {
+		return a.conn.ProcessError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		// the fields were changed while they were being verified, so the verification doesn't apply to them anymore
+		return nil
+	}
+
+	// make sure the cached account has the verified fields
+	account := new(gtsmodel.Account)
+	if err := a.newAccountQ(account).Where("account.id = ?", accountID).Scan(ctx); err != nil {
+		return a.conn.ProcessError(err)
+	}
+	a.cache.Put(account)
+
+	return nil
+}
+
+func (a *accountDB) UpdateAccountLastWebfingeredAt(ctx context.Context, accountID string, lastWebfingeredAt time.Time) db.Error {
+	if _, err := a.conn.
+		NewUpdate().
+		Model((*gtsmodel.Account)(nil)).
+		Set("last_webfingered_at = ?", lastWebfingeredAt).
+		Where("account.id = ?", accountID).
+		Exec(ctx); err != nil {
+		return a.conn.ProcessError(err)
+	}
+
+	// make sure the cached account has the new time
+	account := new(gtsmodel.Account)
+	if err := a.newAccountQ(account).Where("account.id = ?", accountID).Scan(ctx); err != nil {
+		return a.conn.ProcessError(err)
+	}
+	a.cache.Put(account)
+
+	return nil
+}
+
 func (a *accountDB) GetInstanceAccount(ctx context.Context, domain string) (*gtsmodel.Account, db.Error) {
 	account := new(gtsmodel.Account)
 
@@ -144,6 +230,139 @@ func (a *accountDB) GetInstanceAccount(ctx context.Context, domain string) (*gts
 	return account, nil
 }
 
+func (a *accountDB) GetKnownInboxes(ctx context.Context, exceptFollowersOf string) ([]string, db.Error) {
+	inboxes := []string{}
+
+	// prefer the shared inbox of the instance, if any account on it has told us about one
+	q := a.conn.
+		NewSelect().
+		Model((*gtsmodel.Account)(nil)).
+		ColumnExpr("COALESCE(MIN(NULLIF(?, '')), MIN(?))", bun.Ident("account.shared_inbox_uri"), bun.Ident("account.inbox_uri")).
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("account.domain")).
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("account.inbox_uri")).
+		Where("account.suspended_at IS NULL").
+		Group("account.domain")
+
+	if exceptFollowersOf != "" {
+		followerDomains := a.conn.
+			NewSelect().
+			TableExpr("? AS ?", bun.Ident("follows"), bun.Ident("follow")).
+			Join("JOIN ? AS ? ON ? = ?", bun.Ident("accounts"), bun.Ident("follower"), bun.Ident("follower.id"), bun.Ident("follow.account_id")).
+			Column("follower.domain").
+			Where("? = ?", bun.Ident("follow.target_account_id"), exceptFollowersOf).
+			WhereGroup(" AND ", whereNotEmptyAndNotNull("follower.domain"))
+		q = q.Where("? NOT IN (?)", bun.Ident("account.domain"), followerDomains)
+	}
+
+	if err := q.Scan(ctx, &inboxes); err != nil {
+		return nil, a.conn.ProcessError(err)
+	}
+	return inboxes, nil
+}
+
+func (a *accountDB) GetStaleRemoteAccounts(ctx context.Context, olderThan time.Time, sinceID string, limit int) ([]*gtsmodel.Account, db.Error) {
+	accounts := []*gtsmodel.Account{}
+
+	q := a.conn.
+		NewSelect().
+		Model(&accounts).
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("account.domain")).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("? IS NULL", bun.Ident("account.last_webfingered_at")).
+				WhereOr("? < ?", bun.Ident("account.last_webfingered_at"), olderThan)
+		}).
+		Where("? IS NULL", bun.Ident("account.suspended_at")).
+		Order("account.id ASC").
+		Limit(limit)
+
+	if sinceID != "" {
+		q = q.Where("? > ?", bun.Ident("account.id"), sinceID)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, a.conn.ProcessError(err)
+	}
+	return accounts, nil
+}
+
+func (a *accountDB) GetDirectoryAccounts(ctx context.Context, newest bool, local bool, limit int, offset int) ([]*gtsmodel.Account, db.Error) {
+	accounts := []*gtsmodel.Account{}
+
+	q := a.conn.
+		NewSelect().
+		Model(&accounts).
+		Where("? = ?", bun.Ident("account.discoverable"), true).
+		Where("? IS NULL", bun.Ident("account.suspended_at")).
+		Where("? IS NULL", bun.Ident("account.silenced_at")).
+		WhereGroup(" AND ", whereEmptyOrNull("account.moved_to_account_id"))
+
+	if local {
+		q = q.WhereGroup(" AND ", whereEmptyOrNull("account.domain"))
+	}
+
+	if newest {
+		q = q.Order("account.created_at DESC")
+	} else {
+		// accounts that have never posted anything go last
+		lastPosted := a.conn.
+			NewSelect().
+			Table("statuses").
+			ColumnExpr("MAX(?)", bun.Ident("statuses.created_at")).
+			Where("? = ?", bun.Ident("statuses.account_id"), bun.Ident("account.id"))
+		q = q.OrderExpr("(?) DESC NULLS LAST", lastPosted)
+	}
+
+	q = q.
+		Order("account.id DESC").
+		Limit(limit).
+		Offset(offset)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, a.conn.ProcessError(err)
+	}
+	return accounts, nil
+}
+
+func (a *accountDB) GetAccountFeaturedTags(ctx context.Context, accountID string) ([]*gtsmodel.FeaturedTag, db.Error) {
+	featuredTags := []*gtsmodel.FeaturedTag{}
+
+	q := a.conn.
+		NewSelect().
+		Model(&featuredTags).
+		Relation("Tag").
+		Where("featured_tag.account_id = ?", accountID).
+		Order("featured_tag.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, a.conn.ProcessError(err)
+	}
+	return featuredTags, nil
+}
+
+func (a *accountDB) GetAccountTagUsage(ctx context.Context, accountID string, tagID string) (int, time.Time, db.Error) {
+	usage := struct {
+		Count        int
+		LastStatusAt time.Time `bun:",nullzero"`
+	}{}
+
+	q := a.conn.
+		NewSelect().
+		Table("statuses").
+		ColumnExpr("COUNT(*) AS ?", bun.Ident("count")).
+		ColumnExpr("MAX(?) AS ?", bun.Ident("statuses.created_at"), bun.Ident("last_status_at")).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("status_to_tags"), bun.Ident("status_to_tag"), bun.Ident("status_to_tag.status_id"), bun.Ident("statuses.id")).
+		Where("? = ?", bun.Ident("statuses.account_id"), accountID).
+		Where("? = ?", bun.Ident("status_to_tag.tag_id"), tagID).
+		Where("? IN (?)", bun.Ident("statuses.visibility"), bun.In([]gtsmodel.Visibility{gtsmodel.VisibilityPublic, gtsmodel.VisibilityUnlocked})).
+		WhereGroup(" AND ", whereEmptyOrNull("statuses.boost_of_id"))
+
+	if err := q.Scan(ctx, &usage); err != nil {
+		return 0, time.Time{}, a.conn.ProcessError(err)
+	}
+	return usage.Count, usage.LastStatusAt, nil
+}
+
 func (a *accountDB) GetAccountLastPosted(ctx context.Context, accountID string) (time.Time, db.Error) {
 	status := new(gtsmodel.Status)
 
@@ -235,8 +454,14 @@ func (a *accountDB) GetAccountStatuses(ctx context.Context, accountID string, li
 
 	q := a.conn.
 		NewSelect().
-		Model(&statuses).
-		Order("id DESC")
+		Model(&statuses)
+
+	if minID != "" && maxID == "" {
+		// page up from minID, so that we get the statuses right after it rather than the newest ones
+		q = q.Order("id ASC")
+	} else {
+		q = q.Order("id DESC")
+	}
 
 	if accountID != "" {
 		q = q.Where("account_id = ?", accountID)
@@ -292,6 +517,11 @@ func (a *accountDB) GetAccountStatuses(ctx context.Context, accountID string, li
 		return nil, db.ErrNoEntries
 	}
 
+	if minID != "" && maxID == "" {
+		// put the statuses back in descending order
+		reverseStatuses(statuses)
+	}
+
 	return statuses, nil
 }
 
@@ -334,3 +564,43 @@ func (a *accountDB) GetAccountBlocks(ctx context.Context, accountID string, maxI
 	prevMinID := blocks[0].ID
 	return accounts, nextMaxID, prevMinID, nil
 }
+
+func (a *accountDB) GetAccountEndorsements(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Account, string, string, db.Error) {
+	endorsements := []*gtsmodel.Endorsement{}
+
+	fq := a.conn.
+		NewSelect().
+		Model(&endorsements).
+		Where("endorsement.account_id = ?", accountID).
+		Relation("TargetAccount").
+		Order("endorsement.id DESC")
+
+	if maxID != "" {
+		fq = fq.Where("endorsement.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		fq = fq.Where("endorsement.id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		fq = fq.Limit(limit)
+	}
+
+	if err := fq.Scan(ctx); err != nil {
+		return nil, "", "", a.conn.ProcessError(err)
+	}
+
+	if len(endorsements) == 0 {
+		return nil, "", "", db.ErrNoEntries
+	}
+
+	accounts := []*gtsmodel.Account{}
+	for _, e := range endorsements {
+		accounts = append(accounts, e.TargetAccount)
+	}
+
+	nextMaxID := endorsements[len(endorsements)-1].ID
+	prevMinID := endorsements[0].ID
+	return accounts, nextMaxID, prevMinID, nil
+}
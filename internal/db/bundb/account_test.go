@@ -60,6 +60,46 @@ func (suite *AccountTestSuite) TestUpdateAccount() {
 	suite.WithinDuration(time.Now(), updated.UpdatedAt, 5*time.Second)
 }
 
+func (suite *AccountTestSuite) TestVerifyAccountFields() {
+	testAccount := suite.testAccounts["local_account_1"]
+	testAccount.DisplayName = "new display name!"
+	testAccount.Fields = []gtsmodel.Field{
+		{Name: "website", Value: "https://example.org"},
+		{Name: "pronouns", Value: "they/them"},
+	}
+	_, err := suite.db.UpdateAccount(context.Background(), testAccount)
+	suite.NoError(err)
+
+	verifiedAt := time.Now()
+	err = suite.db.VerifyAccountFields(context.Background(), testAccount.ID, []gtsmodel.Field{{Name: "website", Value: "https://example.org"}}, verifiedAt)
+	suite.NoError(err)
+
+	updated, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Equal("new display name!", updated.DisplayName)
+	suite.WithinDuration(verifiedAt, updated.Fields[0].VerifiedAt, time.Second)
+	suite.True(updated.Fields[1].VerifiedAt.IsZero())
+}
+
+func (suite *AccountTestSuite) TestVerifyAccountFieldsChanged() {
+	testAccount := suite.testAccounts["local_account_1"]
+	testAccount.Fields = []gtsmodel.Field{
+		{Name: "website", Value: "https://example.org/somewhere-else"},
+	}
+	_, err := suite.db.UpdateAccount(context.Background(), testAccount)
+	suite.NoError(err)
+
+	// the field that was verified has been changed in the meantime, so it shouldn't be marked as verified
+	err = suite.db.VerifyAccountFields(context.Background(), testAccount.ID, []gtsmodel.Field{{Name: "website", Value: "https://example.org"}}, time.Now())
+	suite.NoError(err)
+
+	updated, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Len(updated.Fields, 1)
+	suite.Equal("https://example.org/somewhere-else", updated.Fields[0].Value)
+	suite.True(updated.Fields[0].VerifiedAt.IsZero())
+}
+
 func (suite *AccountTestSuite) TestInsertAccountWithDefaults() {
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	suite.NoError(err)
@@ -88,6 +128,92 @@ func (suite *AccountTestSuite) TestInsertAccountWithDefaults() {
 	suite.False(newAccount.HideCollections)
 }
 
+func (suite *AccountTestSuite) TestGetKnownInboxes() {
+	ctx := context.Background()
+	remoteAccount1 := suite.testAccounts["remote_account_1"]
+	remoteAccount2 := suite.testAccounts["remote_account_2"]
+
+	inboxes, err := suite.db.GetKnownInboxes(ctx, "")
+	suite.NoError(err)
+	suite.ElementsMatch([]string{remoteAccount1.InboxURI, remoteAccount2.InboxURI}, inboxes)
+
+	// the shared inbox of an instance should be used if there is one
+	remoteAccount1.SharedInboxURI = "http://fossbros-anonymous.io/inbox"
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, remoteAccount1))
+
+	inboxes, err = suite.db.GetKnownInboxes(ctx, "")
+	suite.NoError(err)
+	suite.ElementsMatch([]string{remoteAccount1.SharedInboxURI, remoteAccount2.InboxURI}, inboxes)
+
+	// instances that an account has followers on can be left out
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Follow{
+		ID:              "01G3J7X5Y9QZ1TKZ8B8C6S5H4E",
+		URI:             "http://example.org/users/Some_User/follows/01G3J7X5Y9QZ1TKZ8B8C6S5H4E",
+		AccountID:       remoteAccount2.ID,
+		TargetAccountID: suite.testAccounts["local_account_1"].ID,
+	}))
+
+	inboxes, err = suite.db.GetKnownInboxes(ctx, suite.testAccounts["local_account_1"].ID)
+	suite.NoError(err)
+	suite.Equal([]string{remoteAccount1.SharedInboxURI}, inboxes)
+}
+
+func (suite *AccountTestSuite) TestGetStaleRemoteAccounts() {
+	ctx := context.Background()
+	remoteAccount1 := suite.testAccounts["remote_account_1"]
+	remoteAccount2 := suite.testAccounts["remote_account_2"]
+
+	// neither remote account has been fetched yet, so both are stale
+	accounts, err := suite.db.GetStaleRemoteAccounts(ctx, time.Now().Add(-24*time.Hour), "", 10)
+	suite.NoError(err)
+	suite.Len(accounts, 2)
+
+	// one fetched just now isn't stale
+	err = suite.db.UpdateAccountLastWebfingeredAt(ctx, remoteAccount1.ID, time.Now())
+	suite.NoError(err)
+
+	accounts, err = suite.db.GetStaleRemoteAccounts(ctx, time.Now().Add(-24*time.Hour), "", 10)
+	suite.NoError(err)
+	suite.Len(accounts, 1)
+	suite.Equal(remoteAccount2.ID, accounts[0].ID)
+
+	// once both are stale again, they should be paged through in order of id
+	err = suite.db.UpdateAccountLastWebfingeredAt(ctx, remoteAccount1.ID, time.Now().Add(-72*time.Hour))
+	suite.NoError(err)
+
+	accounts, err = suite.db.GetStaleRemoteAccounts(ctx, time.Now().Add(-24*time.Hour), "", 1)
+	suite.NoError(err)
+	suite.Len(accounts, 1)
+	firstID := accounts[0].ID
+
+	accounts, err = suite.db.GetStaleRemoteAccounts(ctx, time.Now().Add(-24*time.Hour), firstID, 1)
+	suite.NoError(err)
+	suite.Len(accounts, 1)
+	suite.Less(firstID, accounts[0].ID)
+
+	accounts, err = suite.db.GetStaleRemoteAccounts(ctx, time.Now().Add(-24*time.Hour), accounts[0].ID, 1)
+	suite.NoError(err)
+	suite.Empty(accounts)
+}
+
+func (suite *AccountTestSuite) TestUpdateAccountLastWebfingeredAt() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["remote_account_1"]
+
+	// make sure the account is cached first
+	before, err := suite.db.GetAccountByID(ctx, testAccount.ID)
+	suite.NoError(err)
+
+	fetchedAt := time.Now().Add(-time.Hour)
+	err = suite.db.UpdateAccountLastWebfingeredAt(ctx, testAccount.ID, fetchedAt)
+	suite.NoError(err)
+
+	after, err := suite.db.GetAccountByID(ctx, testAccount.ID)
+	suite.NoError(err)
+	suite.WithinDuration(fetchedAt, after.LastWebfingeredAt, time.Second)
+	suite.Equal(before.UpdatedAt.Unix(), after.UpdatedAt.Unix())
+}
+
 func TestAccountTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountTestSuite))
 }
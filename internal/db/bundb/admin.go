@@ -20,9 +20,9 @@ package bundb
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
-	"database/sql"
 	"fmt"
 	"net"
 	"net/mail"
@@ -38,6 +38,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+	"github.com/uptrace/bun"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -56,27 +58,28 @@ func (a *adminDB) IsUsernameAvailable(ctx context.Context, username string) (boo
 }
 
 func (a *adminDB) IsEmailAvailable(ctx context.Context, email string) (bool, db.Error) {
-	// parse the domain from the email
-	m, err := mail.ParseAddress(email)
+	domain, domains, err := emailDomains(email)
 	if err != nil {
-		return false, fmt.Errorf("error parsing email address %s: %s", email, err)
+		return false, err
 	}
-	domain := strings.Split(m.Address, "@")[1] // domain will always be the second part after @
 
-	// check if the email domain is blocked
-	if err := a.conn.
+	// check if the email domain, or any domain that it's a subdomain of, is blocked
+	q := a.conn.
 		NewSelect().
 		Model(&gtsmodel.EmailDomainBlock{}).
-		Where("domain = ?", domain).
-		Scan(ctx); err == nil {
+		Where("LOWER(?) IN (?)", bun.Ident("domain"), bun.In(domains))
+
+	blocked, err := a.conn.Exists(ctx, q)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
 		// fail because we found something
-		return false, fmt.Errorf("email domain %s is blocked", domain)
-	} else if err != sql.ErrNoRows {
-		return false, a.conn.ProcessError(err)
+		return false, db.NewErrEmailDomainBlocked(fmt.Sprintf("email domain %s is blocked", domain))
 	}
 
 	// check if this email is associated with a user already
-	q := a.conn.
+	q = a.conn.
 		NewSelect().
 		Model(&gtsmodel.User{}).
 		Where("email = ?", email).
@@ -85,6 +88,36 @@ func (a *adminDB) IsEmailAvailable(ctx context.Context, email string) (bool, db.
 	return a.conn.NotExists(ctx, q)
 }
 
+func (a *adminDB) CountEmailDomainBlockAttempt(ctx context.Context, email string) db.Error {
+	_, domains, err := emailDomains(email)
+	if err != nil {
+		return err
+	}
+
+	if _, err := a.conn.
+		NewUpdate().
+		Model(&gtsmodel.EmailDomainBlock{}).
+		Set("? = ? + 1", bun.Ident("attempts"), bun.Ident("attempts")).
+		Set("? = ?", bun.Ident("last_attempt_at"), time.Now()).
+		Where("LOWER(?) IN (?)", bun.Ident("domain"), bun.In(domains)).
+		Exec(ctx); err != nil {
+		return a.conn.ProcessError(err)
+	}
+	return nil
+}
+
+// emailDomains returns the domain of the given email address, and that domain lowercased followed by all the domains
+// that it's a subdomain of, which are the domains that email domain blocks are checked against.
+func emailDomains(email string) (string, []string, error) {
+	m, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing email address %s: %s", email, err)
+	}
+	domain := strings.Split(m.Address, "@")[1] // domain will always be the second part after @
+	lower := strings.ToLower(domain)
+	return domain, append([]string{lower}, parentDomains(lower)...), nil
+}
+
 func (a *adminDB) NewSignup(ctx context.Context, username string, reason string, requireApproval bool, email string, password string, signUpIP net.IP, locale string, appID string, emailVerified bool, admin bool) (*gtsmodel.User, db.Error) {
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -92,6 +125,12 @@ func (a *adminDB) NewSignup(ctx context.Context, username string, reason string,
 		return nil, err
 	}
 
+	_, assertionKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		logrus.Errorf("error creating new ed25519 key: %s", err)
+		return nil, err
+	}
+
 	// if something went wrong while creating a user, we might already have an account, so check here first...
 	acct := &gtsmodel.Account{}
 	q := a.conn.NewSelect().
@@ -117,6 +156,9 @@ func (a *adminDB) NewSignup(ctx context.Context, username string, reason string,
 			PrivateKey:            key,
 			PublicKey:             &key.PublicKey,
 			PublicKeyURI:          accountURIs.PublicKeyURI,
+			AssertionPrivateKey:   assertionKey,
+			AssertionPublicKey:    assertionKey.Public().(ed25519.PublicKey),
+			AssertionKeyURI:       accountURIs.AssertionKeyURI,
 			ActorType:             ap.ActorPerson,
 			URI:                   accountURIs.UserURI,
 			InboxURI:              accountURIs.InboxURI,
@@ -201,6 +243,12 @@ func (a *adminDB) CreateInstanceAccount(ctx context.Context) db.Error {
 		return err
 	}
 
+	_, assertionKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		logrus.Errorf("error creating new ed25519 key: %s", err)
+		return err
+	}
+
 	aID, err := id.NewRandomULID()
 	if err != nil {
 		return err
@@ -215,6 +263,9 @@ func (a *adminDB) CreateInstanceAccount(ctx context.Context) db.Error {
 		PrivateKey:            key,
 		PublicKey:             &key.PublicKey,
 		PublicKeyURI:          newAccountURIs.PublicKeyURI,
+		AssertionPrivateKey:   assertionKey,
+		AssertionPublicKey:    assertionKey.Public().(ed25519.PublicKey),
+		AssertionKeyURI:       newAccountURIs.AssertionKeyURI,
 		ActorType:             ap.ActorPerson,
 		URI:                   newAccountURIs.UserURI,
 		InboxURI:              newAccountURIs.InboxURI,
@@ -279,3 +330,46 @@ func (a *adminDB) CreateInstanceInstance(ctx context.Context) db.Error {
 	logrus.Infof("created instance instance %s with id %s", host, i.ID)
 	return nil
 }
+
+func (a *adminDB) CreateInstanceVAPIDKeyPair(ctx context.Context) db.Error {
+	// check if a key pair already exists
+	q := a.conn.
+		NewSelect().
+		Model((*gtsmodel.VAPIDKeyPair)(nil))
+
+	exists, err := a.conn.Exists(ctx, q)
+	if err != nil {
+		return err
+	}
+	if exists {
+		logrus.Infof("instance vapid key pair already exists")
+		return nil
+	}
+
+	privateKey, publicKey, err := webpush.GenerateVAPIDKeyPair()
+	if err != nil {
+		return err
+	}
+
+	kpID, err := id.NewRandomULID()
+	if err != nil {
+		return err
+	}
+
+	keyPair := &gtsmodel.VAPIDKeyPair{
+		ID:         kpID,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}
+
+	insertQ := a.conn.
+		NewInsert().
+		Model(keyPair)
+
+	if _, err := insertQ.Exec(ctx); err != nil {
+		return a.conn.ProcessError(err)
+	}
+
+	logrus.Infof("created instance vapid key pair with id %s", keyPair.ID)
+	return nil
+}
@@ -23,6 +23,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
@@ -51,6 +54,46 @@ func (suite *AdminTestSuite) TestCreateInstanceAccount() {
 	suite.NotNil(acct)
 }
 
+func (suite *AdminTestSuite) TestIsEmailAvailableBlockedDomain() {
+	ctx := context.Background()
+
+	blockID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.EmailDomainBlock{
+		ID:                 blockID,
+		Domain:             "disposable.example.org",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+	}))
+
+	available, err := suite.db.IsEmailAvailable(ctx, "someone@Disposable.Example.org")
+	suite.EqualError(err, "email domain Disposable.Example.org is blocked")
+	suite.IsType(&db.ErrEmailDomainBlocked{}, err)
+	suite.False(available)
+
+	// subdomains are blocked too
+	available, err = suite.db.IsEmailAvailable(ctx, "someone@mx.disposable.example.org")
+	suite.Error(err)
+	suite.False(available)
+
+	// but not the domain above it
+	available, err = suite.db.IsEmailAvailable(ctx, "someone@example.org")
+	suite.NoError(err)
+	suite.True(available)
+
+	// checking doesn't count as an attempt
+	block := &gtsmodel.EmailDomainBlock{}
+	suite.NoError(suite.db.GetByID(ctx, blockID, block))
+	suite.Zero(block.Attempts)
+
+	// attempts are counted on the block that the address falls under
+	suite.NoError(suite.db.CountEmailDomainBlockAttempt(ctx, "someone@Disposable.Example.org"))
+	suite.NoError(suite.db.CountEmailDomainBlockAttempt(ctx, "someone@mx.disposable.example.org"))
+	suite.NoError(suite.db.CountEmailDomainBlockAttempt(ctx, "someone@example.org"))
+	suite.NoError(suite.db.GetByID(ctx, blockID, block))
+	suite.Equal(2, block.Attempts)
+	suite.False(block.LastAttemptAt.IsZero())
+}
+
 func TestAdminTestSuite(t *testing.T) {
 	suite.Run(t, new(AdminTestSuite))
 }
@@ -131,11 +131,16 @@ func (b *basicDB) CreateAllTables(ctx context.Context) db.Error {
 		&gtsmodel.Follow{},
 		&gtsmodel.FollowRequest{},
 		&gtsmodel.MediaAttachment{},
+		&gtsmodel.MediaJob{},
 		&gtsmodel.Mention{},
+		&gtsmodel.Poll{},
+		&gtsmodel.PollVote{},
 		&gtsmodel.Status{},
 		&gtsmodel.StatusToEmoji{},
 		&gtsmodel.StatusToTag{},
 		&gtsmodel.StatusFave{},
+		&gtsmodel.StatusReaction{},
+		&gtsmodel.StatusEdit{},
 		&gtsmodel.StatusBookmark{},
 		&gtsmodel.StatusMute{},
 		&gtsmodel.Tag{},
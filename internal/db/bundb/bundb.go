@@ -76,15 +76,29 @@ type bunDBService struct {
 	db.Account
 	db.Admin
 	db.Basic
+	db.Conversation
+	db.Delivery
 	db.Domain
+	db.Export
+	db.Filter
+	db.Import
 	db.Instance
+	db.IPBlock
+	db.Marker
 	db.Media
 	db.Mention
 	db.Notification
+	db.Poll
 	db.Relationship
+	db.Report
+	db.Rule
+	db.Search
 	db.Session
 	db.Status
 	db.Timeline
+	db.Tombstone
+	db.Trend
+	db.WebPush
 	conn *DBConn
 }
 
@@ -165,12 +179,34 @@ func NewBunDBService(ctx context.Context) (db.DB, error) {
 		Basic: &basicDB{
 			conn: conn,
 		},
+		Conversation: &conversationDB{
+			conn: conn,
+		},
+		Delivery: &deliveryDB{
+			conn: conn,
+		},
 		Domain: &domainDB{
 			conn: conn,
 		},
+		Export: &exportDB{
+			conn: conn,
+		},
+		Filter: &filterDB{
+			conn:  conn,
+			cache: newFilterCache(),
+		},
+		Import: &importDB{
+			conn: conn,
+		},
 		Instance: &instanceDB{
 			conn: conn,
 		},
+		IPBlock: &ipBlockDB{
+			conn: conn,
+		},
+		Marker: &markerDB{
+			conn: conn,
+		},
 		Media: &mediaDB{
 			conn: conn,
 		},
@@ -182,20 +218,42 @@ func NewBunDBService(ctx context.Context) (db.DB, error) {
 			conn:  conn,
 			cache: ttlcache.NewCache(),
 		},
+		Poll: &pollDB{
+			conn: conn,
+		},
 		Relationship: &relationshipDB{
 			conn: conn,
 		},
+		Report: &reportDB{
+			conn: conn,
+		},
+		Rule: &ruleDB{
+			conn: conn,
+		},
+		Search: &searchDB{
+			conn: conn,
+		},
 		Session: &sessionDB{
 			conn: conn,
 		},
 		Status: &statusDB{
-			conn:     conn,
-			cache:    cache.NewStatusCache(),
-			accounts: accounts,
+			conn:      conn,
+			cache:     cache.NewStatusCache(),
+			reactions: newReactionCache(),
+			accounts:  accounts,
 		},
 		Timeline: &timelineDB{
 			conn: conn,
 		},
+		Tombstone: &tombstoneDB{
+			conn: conn,
+		},
+		Trend: &trendDB{
+			conn: conn,
+		},
+		WebPush: &webPushDB{
+			conn: conn,
+		},
 		conn: conn,
 	}
 
@@ -425,7 +483,7 @@ func (ps *bunDBService) EmojiStringsToEmojis(ctx context.Context, emojis []strin
 	newEmojis := []*gtsmodel.Emoji{}
 	for _, e := range emojis {
 		emoji := &gtsmodel.Emoji{}
-		err := ps.conn.NewSelect().Model(emoji).Where("shortcode = ?", e).Where("visible_in_picker = true").Where("disabled = false").Scan(ctx)
+		err := ps.conn.NewSelect().Model(emoji).Where("shortcode = ?", e).Where("domain = ''").Where("visible_in_picker = true").Where("disabled = false").Scan(ctx)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				// no result found for this username/domain so just don't include it as an emoji and carry on about our business
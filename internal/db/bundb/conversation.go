@@ -0,0 +1,187 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+)
+
+type conversationDB struct {
+	conn *DBConn
+}
+
+func (c *conversationDB) GetConversationByID(ctx context.Context, id string) (*gtsmodel.Conversation, db.Error) {
+	conversation := &gtsmodel.Conversation{}
+
+	q := c.conn.
+		NewSelect().
+		Model(conversation).
+		Where("conversation.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, c.conn.ProcessError(err)
+	}
+
+	if err := c.populateOtherAccounts(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (c *conversationDB) GetConversationByOtherAccountIDs(ctx context.Context, accountID string, otherAccountIDs []string) (*gtsmodel.Conversation, db.Error) {
+	conversation := &gtsmodel.Conversation{}
+
+	q := c.conn.
+		NewSelect().
+		Model(conversation).
+		Where("conversation.account_id = ?", accountID).
+		Where("conversation.other_accounts_key = ?", gtsmodel.ConversationOtherAccountsKey(otherAccountIDs))
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, c.conn.ProcessError(err)
+	}
+
+	if err := c.populateOtherAccounts(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (c *conversationDB) GetConversationsByAccountID(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.Conversation, db.Error) {
+	conversations := []*gtsmodel.Conversation{}
+
+	q := c.conn.
+		NewSelect().
+		Model(&conversations).
+		Where("conversation.account_id = ?", accountID).
+		Order("conversation.last_status_id DESC")
+
+	if maxID != "" {
+		q = q.Where("conversation.last_status_id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("conversation.last_status_id > ?", sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("conversation.last_status_id > ?", minID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, c.conn.ProcessError(err)
+	}
+
+	for _, conversation := range conversations {
+		if err := c.populateOtherAccounts(ctx, conversation); err != nil {
+			return nil, err
+		}
+	}
+	return conversations, nil
+}
+
+func (c *conversationDB) GetConversationsByStatusID(ctx context.Context, statusID string) ([]*gtsmodel.Conversation, db.Error) {
+	conversations := []*gtsmodel.Conversation{}
+
+	q := c.conn.
+		NewSelect().
+		Model(&conversations).
+		Join("JOIN conversation_to_statuses AS conversation_to_status ON conversation_to_status.conversation_id = conversation.id").
+		Where("conversation_to_status.status_id = ?", statusID).
+		Order("conversation.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, c.conn.ProcessError(err)
+	}
+	return conversations, nil
+}
+
+func (c *conversationDB) GetConversationLatestStatusID(ctx context.Context, conversationID string) (string, db.Error) {
+	conversationStatus := &gtsmodel.ConversationToStatus{}
+
+	q := c.conn.
+		NewSelect().
+		Model(conversationStatus).
+		Where("conversation_to_status.conversation_id = ?", conversationID).
+		Order("conversation_to_status.status_id DESC").
+		Limit(1)
+
+	if err := q.Scan(ctx); err != nil {
+		return "", c.conn.ProcessError(err)
+	}
+	return conversationStatus.StatusID, nil
+}
+
+func (c *conversationDB) DeleteConversationByID(ctx context.Context, id string) db.Error {
+	return c.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		if _, err := tx.
+			NewDelete().
+			Model((*gtsmodel.ConversationToStatus)(nil)).
+			Where("conversation_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewDelete().
+			Model((*gtsmodel.Conversation)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+// populateOtherAccounts sets the other accounts of the given conversation, in the same order as their ids.
+func (c *conversationDB) populateOtherAccounts(ctx context.Context, conversation *gtsmodel.Conversation) db.Error {
+	conversation.OtherAccounts = make([]*gtsmodel.Account, 0, len(conversation.OtherAccountIDs))
+	if len(conversation.OtherAccountIDs) == 0 {
+		return nil
+	}
+
+	accounts := []*gtsmodel.Account{}
+
+	q := c.conn.
+		NewSelect().
+		Model(&accounts).
+		Where("account.id IN (?)", bun.In(conversation.OtherAccountIDs))
+
+	if err := q.Scan(ctx); err != nil {
+		return c.conn.ProcessError(err)
+	}
+
+	byID := make(map[string]*gtsmodel.Account, len(accounts))
+	for _, a := range accounts {
+		byID[a.ID] = a
+	}
+
+	for _, id := range conversation.OtherAccountIDs {
+		if a, ok := byID[id]; ok {
+			conversation.OtherAccounts = append(conversation.OtherAccounts, a)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+)
+
+type deliveryDB struct {
+	conn *DBConn
+}
+
+func (d *deliveryDB) GetDueDeliveries(ctx context.Context, now time.Time, storedBefore time.Time, limit int) ([]*gtsmodel.Delivery, db.Error) {
+	deliveries := []*gtsmodel.Delivery{}
+
+	q := d.conn.
+		NewSelect().
+		Model(&deliveries).
+		Where("delivery.failed_at IS NULL").
+		Where("delivery.next_attempt_at <= ?", now).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("delivery.attempts > 0").
+				WhereOr("delivery.created_at < ?", storedBefore)
+		}).
+		Order("delivery.next_attempt_at ASC").
+		Limit(limit)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.conn.ProcessError(err)
+	}
+	return deliveries, nil
+}
+
+func (d *deliveryDB) CountDeliveries(ctx context.Context) (int, int, db.Error) {
+	pending, err := d.conn.
+		NewSelect().
+		Model((*gtsmodel.Delivery)(nil)).
+		Where("failed_at IS NULL").
+		Where("attempts > 0").
+		Count(ctx)
+	if err != nil {
+		return 0, 0, d.conn.ProcessError(err)
+	}
+
+	failed, err := d.conn.
+		NewSelect().
+		Model((*gtsmodel.Delivery)(nil)).
+		Where("failed_at IS NOT NULL").
+		Count(ctx)
+	if err != nil {
+		return 0, 0, d.conn.ProcessError(err)
+	}
+
+	return pending, failed, nil
+}
+
+func (d *deliveryDB) GetRecentDeliveries(ctx context.Context, domain string, limit int) ([]*gtsmodel.Delivery, db.Error) {
+	deliveries := []*gtsmodel.Delivery{}
+
+	q := d.conn.
+		NewSelect().
+		Model(&deliveries).
+		Where("delivery.attempts > 0").
+		Order("delivery.updated_at DESC").
+		Limit(limit)
+
+	if domain != "" {
+		q = q.Where("delivery.inbox_uri LIKE ?", "%://"+domain+"/%")
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.conn.ProcessError(err)
+	}
+	return deliveries, nil
+}
+
+func (d *deliveryDB) DeleteFailedDeliveries(ctx context.Context, before time.Time) (int, db.Error) {
+	res, err := d.conn.
+		NewDelete().
+		Model((*gtsmodel.Delivery)(nil)).
+		Where("failed_at IS NOT NULL").
+		Where("failed_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, d.conn.ProcessError(err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, d.conn.ProcessError(err)
+	}
+	return int(deleted), nil
+}
@@ -0,0 +1,156 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type DeliveryTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+// putDelivery stores a delivery from zork to a remote inbox, which is next due at the given time, or which failed at the given time.
+func (suite *DeliveryTestSuite) putDelivery(nextAttemptAt time.Time, failedAt time.Time) *gtsmodel.Delivery {
+	deliveryID, err := id.NewULID()
+	suite.NoError(err)
+
+	delivery := &gtsmodel.Delivery{
+		ID:            deliveryID,
+		PubKeyID:      suite.testAccounts["local_account_1"].PublicKeyURI,
+		InboxURI:      suite.testAccounts["remote_account_1"].InboxURI,
+		Payload:       []byte(`{"type":"Create"}`),
+		Attempts:      1,
+		NextAttemptAt: nextAttemptAt,
+		FailedAt:      failedAt,
+	}
+	suite.NoError(suite.db.Put(context.Background(), delivery))
+	return delivery
+}
+
+func (suite *DeliveryTestSuite) TestGetDueDeliveries() {
+	now := time.Now()
+	due := suite.putDelivery(now.Add(-time.Minute), time.Time{})
+	dueLonger := suite.putDelivery(now.Add(-time.Hour), time.Time{})
+	suite.putDelivery(now.Add(time.Minute), time.Time{})
+	suite.putDelivery(time.Time{}, now.Add(-time.Hour))
+
+	deliveries, err := suite.db.GetDueDeliveries(context.Background(), now, now, 10)
+	suite.NoError(err)
+	suite.Len(deliveries, 2)
+	suite.Equal(dueLonger.ID, deliveries[0].ID)
+	suite.Equal(due.ID, deliveries[1].ID)
+	suite.Equal(`{"type":"Create"}`, string(deliveries[0].Payload))
+
+	// the limit should be respected
+	deliveries, err = suite.db.GetDueDeliveries(context.Background(), now, now, 1)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+	suite.Equal(dueLonger.ID, deliveries[0].ID)
+}
+
+func (suite *DeliveryTestSuite) TestGetDueDeliveriesUntried() {
+	now := time.Now()
+	untried := suite.putDelivery(now.Add(-time.Minute), time.Time{})
+	untried.Attempts = 0
+	suite.NoError(suite.db.UpdateByPrimaryKey(context.Background(), untried))
+
+	// a delivery that hasn't been tried yet might still be waiting to be made, if it was stored since storedBefore
+	deliveries, err := suite.db.GetDueDeliveries(context.Background(), now, untried.CreatedAt.Add(-time.Minute), 10)
+	suite.NoError(err)
+	suite.Empty(deliveries)
+
+	// if it was stored before then, it should be tried again
+	deliveries, err = suite.db.GetDueDeliveries(context.Background(), now, untried.CreatedAt.Add(time.Minute), 10)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+	suite.Equal(untried.ID, deliveries[0].ID)
+
+	// it shouldn't be counted as pending or shown as a recent delivery either way
+	pending, _, err := suite.db.CountDeliveries(context.Background())
+	suite.NoError(err)
+	suite.Zero(pending)
+	recent, err := suite.db.GetRecentDeliveries(context.Background(), "", 10)
+	suite.NoError(err)
+	suite.Empty(recent)
+}
+
+func (suite *DeliveryTestSuite) TestCountAndDeleteFailedDeliveries() {
+	now := time.Now()
+	suite.putDelivery(now.Add(time.Minute), time.Time{})
+	suite.putDelivery(time.Time{}, now.Add(-time.Hour))
+	suite.putDelivery(time.Time{}, now.Add(-8*24*time.Hour))
+
+	pending, failed, err := suite.db.CountDeliveries(context.Background())
+	suite.NoError(err)
+	suite.Equal(1, pending)
+	suite.Equal(2, failed)
+
+	// only the delivery that failed a long time ago should be deleted
+	deleted, err := suite.db.DeleteFailedDeliveries(context.Background(), now.Add(-7*24*time.Hour))
+	suite.NoError(err)
+	suite.Equal(1, deleted)
+
+	pending, failed, err = suite.db.CountDeliveries(context.Background())
+	suite.NoError(err)
+	suite.Equal(1, pending)
+	suite.Equal(1, failed)
+}
+
+func (suite *DeliveryTestSuite) TestGetRecentDeliveries() {
+	now := time.Now()
+	older := suite.putDelivery(now.Add(time.Minute), time.Time{})
+	newer := suite.putDelivery(time.Time{}, now)
+
+	// make sure the deliveries were tried at different times
+	older.UpdatedAt = now.Add(-time.Hour)
+	suite.NoError(suite.db.UpdateByPrimaryKey(context.Background(), older))
+	newer.UpdatedAt = now
+	suite.NoError(suite.db.UpdateByPrimaryKey(context.Background(), newer))
+
+	deliveries, err := suite.db.GetRecentDeliveries(context.Background(), "", 10)
+	suite.NoError(err)
+	suite.Len(deliveries, 2)
+	suite.Equal(newer.ID, deliveries[0].ID)
+	suite.Equal(older.ID, deliveries[1].ID)
+
+	// the limit should be respected
+	deliveries, err = suite.db.GetRecentDeliveries(context.Background(), "", 1)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+
+	// deliveries can be narrowed down to one domain
+	deliveries, err = suite.db.GetRecentDeliveries(context.Background(), "fossbros-anonymous.io", 10)
+	suite.NoError(err)
+	suite.Len(deliveries, 2)
+
+	deliveries, err = suite.db.GetRecentDeliveries(context.Background(), "example.org", 10)
+	suite.NoError(err)
+	suite.Empty(deliveries)
+}
+
+func TestDeliveryTestSuite(t *testing.T) {
+	suite.Run(t, new(DeliveryTestSuite))
+}
@@ -21,10 +21,12 @@ package bundb
 import (
 	"context"
 	"net/url"
+	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/uptrace/bun"
 )
 
 type domainDB struct {
@@ -32,6 +34,39 @@ type domainDB struct {
 }
 
 func (d *domainDB) IsDomainBlocked(ctx context.Context, domain string) (bool, db.Error) {
+	return d.domainBlockExists(ctx, domain, severityIs(gtsmodel.DomainBlockSeveritySuspend))
+}
+
+func (d *domainDB) IsDomainSilenced(ctx context.Context, domain string) (bool, db.Error) {
+	return d.domainBlockExists(ctx, domain, severityIs(gtsmodel.DomainBlockSeveritySilence))
+}
+
+func (d *domainDB) IsURISilenced(ctx context.Context, uri *url.URL) (bool, db.Error) {
+	return d.IsDomainSilenced(ctx, uri.Hostname())
+}
+
+func (d *domainDB) IsDomainMediaRejected(ctx context.Context, domain string) (bool, db.Error) {
+	return d.domainBlockExists(ctx, domain, func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			Where("severity = ?", gtsmodel.DomainBlockSeveritySilence).
+			WhereOr("reject_media = ?", true)
+	})
+}
+
+func (d *domainDB) IsURIMediaRejected(ctx context.Context, uri *url.URL) (bool, db.Error) {
+	return d.IsDomainMediaRejected(ctx, uri.Hostname())
+}
+
+// severityIs returns a filter for domainBlockExists that only matches blocks of the given severity.
+func severityIs(severity string) func(*bun.SelectQuery) *bun.SelectQuery {
+	return func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("severity = ?", severity)
+	}
+}
+
+// domainBlockExists checks if a domain block matching the given filter exists for the given domain,
+// or for any domain that it's a subdomain of, if that block includes subdomains.
+func (d *domainDB) domainBlockExists(ctx context.Context, domain string, filter func(*bun.SelectQuery) *bun.SelectQuery) (bool, db.Error) {
 	if domain == "" {
 		return false, nil
 	}
@@ -39,12 +74,58 @@ func (d *domainDB) IsDomainBlocked(ctx context.Context, domain string) (bool, db
 	q := d.conn.
 		NewSelect().
 		Model(&gtsmodel.DomainBlock{}).
-		Where("LOWER(domain) = LOWER(?)", domain).
+		WhereGroup(" AND ", filter).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			q = q.Where("LOWER(domain) = LOWER(?)", domain)
+
+			// a block on a parent domain covers this domain too, if the block includes subdomains
+			if parents := parentDomains(domain); len(parents) != 0 {
+				q = q.WhereOr("include_subdomains = ? AND LOWER(domain) IN (?)", true, bun.In(parents))
+			}
+
+			return q
+		}).
 		Limit(1)
 
 	return d.conn.Exists(ctx, q)
 }
 
+func (d *domainDB) GetKnownSubdomains(ctx context.Context, domain string) ([]string, db.Error) {
+	domain = strings.ToLower(domain)
+	pattern := "%." + domain
+
+	accountDomains := []string{}
+	if err := d.conn.
+		NewSelect().
+		Model(&gtsmodel.Account{}).
+		Column("domain").
+		Distinct().
+		Where("LOWER(domain) LIKE ?", pattern).
+		Scan(ctx, &accountDomains); err != nil {
+		return nil, d.conn.ProcessError(err)
+	}
+
+	instanceDomains := []string{}
+	if err := d.conn.
+		NewSelect().
+		Model(&gtsmodel.Instance{}).
+		Column("domain").
+		Where("LOWER(domain) LIKE ?", pattern).
+		Scan(ctx, &instanceDomains); err != nil {
+		return nil, d.conn.ProcessError(err)
+	}
+
+	// underscores in the pattern match any character, so check each result properly
+	subdomains := []string{}
+	for _, s := range util.UniqueStrings(append(accountDomains, instanceDomains...)) {
+		if strings.HasSuffix(strings.ToLower(s), "."+domain) {
+			subdomains = append(subdomains, s)
+		}
+	}
+
+	return subdomains, nil
+}
+
 func (d *domainDB) AreDomainsBlocked(ctx context.Context, domains []string) (bool, db.Error) {
 	// filter out any doubles
 	uniqueDomains := util.UniqueStrings(domains)
@@ -74,3 +155,24 @@ func (d *domainDB) AreURIsBlocked(ctx context.Context, uris []*url.URL) (bool, d
 
 	return d.AreDomainsBlocked(ctx, domains)
 }
+
+// parentDomains returns all the domains that the given domain is a subdomain of, lowercased,
+// from nearest to furthest. For example, 'a.b.example.org' gives 'b.example.org', 'example.org', and 'org'.
+func parentDomains(domain string) []string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	parents := []string{}
+	for {
+		i := strings.Index(domain, ".")
+		if i == -1 {
+			break
+		}
+		domain = domain[i+1:]
+		if domain == "" {
+			break
+		}
+		parents = append(parents, domain)
+	}
+
+	return parents
+}
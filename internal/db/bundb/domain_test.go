@@ -0,0 +1,156 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type DomainTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *DomainTestSuite) TestIsDomainBlocked() {
+	ctx := context.Background()
+
+	blockID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 blockID,
+		Domain:             "example.net",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		IncludeSubdomains:  true,
+	}))
+
+	for domain, blocked := range map[string]bool{
+		"example.net":            true,
+		"Sub.Example.NET":        true,
+		"deeper.sub.example.net": true,
+		"notexample.net":         false,
+		"example.net.org":        false,
+		"replyguys.com":          true,
+		"sub.replyguys.com":      false, // this block doesn't include subdomains
+		"":                       false,
+	} {
+		isBlocked, err := suite.db.IsDomainBlocked(ctx, domain)
+		suite.NoError(err)
+		suite.Equal(blocked, isBlocked, domain)
+	}
+}
+
+func (suite *DomainTestSuite) TestIsDomainSilenced() {
+	ctx := context.Background()
+
+	blockID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 blockID,
+		Domain:             "example.net",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		IncludeSubdomains:  true,
+		Severity:           gtsmodel.DomainBlockSeveritySilence,
+	}))
+
+	for domain, silenced := range map[string]bool{
+		"example.net":     true,
+		"sub.example.net": true,
+		"notexample.net":  false,
+		"replyguys.com":   false, // this domain is suspended, not silenced
+		"":                false,
+	} {
+		isSilenced, err := suite.db.IsDomainSilenced(ctx, domain)
+		suite.NoError(err)
+		suite.Equal(silenced, isSilenced, domain)
+	}
+
+	// a silenced domain isn't blocked
+	blocked, err := suite.db.IsDomainBlocked(ctx, "sub.example.net")
+	suite.NoError(err)
+	suite.False(blocked)
+}
+
+func (suite *DomainTestSuite) TestIsDomainMediaRejected() {
+	ctx := context.Background()
+
+	silenceID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 silenceID,
+		Domain:             "quiet.example.net",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		Severity:           gtsmodel.DomainBlockSeveritySilence,
+	}))
+
+	rejectID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 rejectID,
+		Domain:             "example.org",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		IncludeSubdomains:  true,
+		Severity:           gtsmodel.DomainBlockSeveritySuspend,
+		RejectMedia:        true,
+	}))
+
+	for domain, rejected := range map[string]bool{
+		"quiet.example.net": true, // media from silenced domains is always rejected
+		"example.org":       true,
+		"sub.example.org":   true,
+		"replyguys.com":     false, // this domain is suspended, but its media isn't rejected
+		"example.net":       false,
+		"":                  false,
+	} {
+		isRejected, err := suite.db.IsDomainMediaRejected(ctx, domain)
+		suite.NoError(err)
+		suite.Equal(rejected, isRejected, domain)
+	}
+}
+
+func (suite *DomainTestSuite) TestGetKnownSubdomains() {
+	ctx := context.Background()
+
+	instanceID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Instance{
+		ID:     instanceID,
+		Domain: "sub.example.org",
+		URI:    "https://sub.example.org",
+	}))
+
+	subdomains, err := suite.db.GetKnownSubdomains(ctx, "example.org")
+	suite.NoError(err)
+	suite.Equal([]string{"sub.example.org"}, subdomains)
+
+	subdomains, err = suite.db.GetKnownSubdomains(ctx, "IO")
+	suite.NoError(err)
+	suite.Contains(subdomains, "fossbros-anonymous.io")
+
+	subdomains, err = suite.db.GetKnownSubdomains(ctx, "anonymous.io")
+	suite.NoError(err)
+	suite.Empty(subdomains)
+}
+
+func TestDomainTestSuite(t *testing.T) {
+	suite.Run(t, new(DomainTestSuite))
+}
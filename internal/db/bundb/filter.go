@@ -0,0 +1,200 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+)
+
+// filterCacheTTL is how long the filters of an account are kept in memory after they were last used.
+const filterCacheTTL = 5 * time.Minute
+
+type filterDB struct {
+	conn *DBConn
+
+	// filters of accounts by account id, since they're checked against every status served to them
+	cache *ttlcache.Cache
+}
+
+func newFilterCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(filterCacheTTL)
+	return c
+}
+
+func (f *filterDB) newFilterQ(filter interface{}) *bun.SelectQuery {
+	return f.conn.
+		NewSelect().
+		Model(filter).
+		Relation("Keywords", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("filter_keyword.id ASC")
+		}).
+		Relation("Statuses", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("filter_status.id ASC")
+		})
+}
+
+func (f *filterDB) GetFilterByID(ctx context.Context, id string) (*gtsmodel.Filter, db.Error) {
+	filter := &gtsmodel.Filter{}
+
+	q := f.newFilterQ(filter).
+		Where("filter.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, f.conn.ProcessError(err)
+	}
+	return filter, nil
+}
+
+func (f *filterDB) GetFiltersByAccountID(ctx context.Context, accountID string) ([]*gtsmodel.Filter, db.Error) {
+	if v, ok := f.cache.Get(accountID); ok {
+		filters, ok := v.([]*gtsmodel.Filter)
+		if !ok {
+			panic("filter cache entry was not a slice of filters")
+		}
+		return filters, nil
+	}
+
+	filters := []*gtsmodel.Filter{}
+
+	q := f.newFilterQ(&filters).
+		Where("filter.account_id = ?", accountID).
+		Order("filter.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, f.conn.ProcessError(err)
+	}
+
+	f.cache.Set(accountID, filters)
+	return filters, nil
+}
+
+func (f *filterDB) GetFilterKeywordByID(ctx context.Context, id string) (*gtsmodel.FilterKeyword, db.Error) {
+	keyword := &gtsmodel.FilterKeyword{}
+
+	q := f.conn.
+		NewSelect().
+		Model(keyword).
+		Where("filter_keyword.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, f.conn.ProcessError(err)
+	}
+	return keyword, nil
+}
+
+func (f *filterDB) GetFilterStatusByID(ctx context.Context, id string) (*gtsmodel.FilterStatus, db.Error) {
+	status := &gtsmodel.FilterStatus{}
+
+	q := f.conn.
+		NewSelect().
+		Model(status).
+		Where("filter_status.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, f.conn.ProcessError(err)
+	}
+	return status, nil
+}
+
+func (f *filterDB) PutFilter(ctx context.Context, filter *gtsmodel.Filter) db.Error {
+	defer f.cache.Remove(filter.AccountID)
+	_, err := f.conn.NewInsert().Model(filter).Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) UpdateFilter(ctx context.Context, filter *gtsmodel.Filter) db.Error {
+	defer f.cache.Remove(filter.AccountID)
+	_, err := f.conn.NewUpdate().Model(filter).WherePK().Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) DeleteFilterByID(ctx context.Context, id string) db.Error {
+	filter := &gtsmodel.Filter{}
+	if err := f.conn.
+		NewSelect().
+		Model(filter).
+		Column("filter.account_id").
+		Where("filter.id = ?", id).
+		Scan(ctx); err != nil {
+		return f.conn.ProcessError(err)
+	}
+	defer f.cache.Remove(filter.AccountID)
+
+	return f.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		if _, err := tx.
+			NewDelete().
+			Model((*gtsmodel.FilterKeyword)(nil)).
+			Where("filter_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		if _, err := tx.
+			NewDelete().
+			Model((*gtsmodel.FilterStatus)(nil)).
+			Where("filter_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewDelete().
+			Model((*gtsmodel.Filter)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (f *filterDB) PutFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) db.Error {
+	defer f.cache.Remove(keyword.AccountID)
+	_, err := f.conn.NewInsert().Model(keyword).Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) UpdateFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) db.Error {
+	defer f.cache.Remove(keyword.AccountID)
+	_, err := f.conn.NewUpdate().Model(keyword).WherePK().Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) DeleteFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) db.Error {
+	defer f.cache.Remove(keyword.AccountID)
+	_, err := f.conn.NewDelete().Model(keyword).WherePK().Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) PutFilterStatus(ctx context.Context, status *gtsmodel.FilterStatus) db.Error {
+	defer f.cache.Remove(status.AccountID)
+	_, err := f.conn.NewInsert().Model(status).Exec(ctx)
+	return f.conn.ProcessError(err)
+}
+
+func (f *filterDB) DeleteFilterStatus(ctx context.Context, status *gtsmodel.FilterStatus) db.Error {
+	defer f.cache.Remove(status.AccountID)
+	_, err := f.conn.NewDelete().Model(status).WherePK().Exec(ctx)
+	return f.conn.ProcessError(err)
+}
@@ -0,0 +1,120 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+type importDB struct {
+	conn *DBConn
+}
+
+func (i *importDB) newImportQ(imp interface{}) *bun.SelectQuery {
+	return i.conn.
+		NewSelect().
+		Model(imp).
+		Relation("Failures", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("import_failure.id ASC")
+		})
+}
+
+func (i *importDB) GetImportByID(ctx context.Context, id string) (*gtsmodel.Import, db.Error) {
+	imp := &gtsmodel.Import{}
+
+	q := i.newImportQ(imp).
+		Where("import.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, i.conn.ProcessError(err)
+	}
+	return imp, nil
+}
+
+func (i *importDB) GetAccountImports(ctx context.Context, accountID string) ([]*gtsmodel.Import, db.Error) {
+	imports := []*gtsmodel.Import{}
+
+	q := i.newImportQ(&imports).
+		Where("import.account_id = ?", accountID).
+		Order("import.id DESC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, i.conn.ProcessError(err)
+	}
+	return imports, nil
+}
+
+func (i *importDB) GetPendingImports(ctx context.Context) ([]*gtsmodel.Import, db.Error) {
+	imports := []*gtsmodel.Import{}
+
+	q := i.conn.
+		NewSelect().
+		Model(&imports).
+		Where("import.state = ?", gtsmodel.ImportStatePending).
+		Order("import.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, i.conn.ProcessError(err)
+	}
+	return imports, nil
+}
+
+func (i *importDB) PutImport(ctx context.Context, imp *gtsmodel.Import) db.Error {
+	return i.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		// lock the importing account where we can, so that concurrent imports by it are checked one at a time
+		if i.conn.Dialect().Name() == dialect.PG {
+			if _, err := tx.
+				NewSelect().
+				Model((*gtsmodel.Account)(nil)).
+				Column("account.id").
+				Where("account.id = ?", imp.AccountID).
+				For("UPDATE").
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		pending, err := tx.
+			NewSelect().
+			Model((*gtsmodel.Import)(nil)).
+			Where("import.account_id = ?", imp.AccountID).
+			Where("import.state = ?", gtsmodel.ImportStatePending).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		if pending != 0 {
+			return db.NewErrAlreadyExists(fmt.Sprintf("account %s already has a pending import", imp.AccountID))
+		}
+
+		_, err = tx.NewInsert().Model(imp).Exec(ctx)
+		return err
+	})
+}
+
+func (i *importDB) PutImportFailure(ctx context.Context, failure *gtsmodel.ImportFailure) db.Error {
+	_, err := i.conn.NewInsert().Model(failure).Exec(ctx)
+	return i.conn.ProcessError(err)
+}
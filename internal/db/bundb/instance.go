@@ -20,6 +20,7 @@ package bundb
 
 import (
 	"context"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -56,6 +57,20 @@ func (i *instanceDB) CountInstanceUsers(ctx context.Context, domain string) (int
 	return count, nil
 }
 
+func (i *instanceDB) CountActiveLocalUsers(ctx context.Context, since time.Time) (int, db.Error) {
+	var count int
+	if err := i.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.Status{}).
+		ColumnExpr("COUNT(DISTINCT ?)", bun.Ident("account_id")).
+		Where("local = ?", true).
+		Where("created_at >= ?", since).
+		Scan(ctx, &count); err != nil {
+		return 0, i.conn.ProcessError(err)
+	}
+	return count, nil
+}
+
 func (i *instanceDB) CountInstanceStatuses(ctx context.Context, domain string) (int, db.Error) {
 	q := i.conn.
 		NewSelect().
@@ -102,6 +117,71 @@ func (i *instanceDB) CountInstanceDomains(ctx context.Context, domain string) (i
 	return count, nil
 }
 
+func (i *instanceDB) CountLocalUsersCreated(ctx context.Context, since time.Time, until time.Time) (int, db.Error) {
+	count, err := i.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.User{}).
+		Where("created_at >= ?", since).
+		Where("created_at < ?", until).
+		Count(ctx)
+	if err != nil {
+		return 0, i.conn.ProcessError(err)
+	}
+	return count, nil
+}
+
+func (i *instanceDB) CountLocalStatusesCreated(ctx context.Context, since time.Time, until time.Time) (int, db.Error) {
+	count, err := i.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.Status{}).
+		Where("local = ?", true).
+		Where("created_at >= ?", since).
+		Where("created_at < ?", until).
+		Count(ctx)
+	if err != nil {
+		return 0, i.conn.ProcessError(err)
+	}
+	return count, nil
+}
+
+func (i *instanceDB) CountLocalUsersSignedIn(ctx context.Context, since time.Time, until time.Time) (int, db.Error) {
+	var count int
+	if err := i.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.Token{}).
+		ColumnExpr("COUNT(DISTINCT ?)", bun.Ident("user_id")).
+		Where("? IS NOT NULL", bun.Ident("user_id")).
+		Where("access_create_at >= ?", since).
+		Where("access_create_at < ?", until).
+		Scan(ctx, &count); err != nil {
+		return 0, i.conn.ProcessError(err)
+	}
+	return count, nil
+}
+
+func (i *instanceDB) GetTopRemoteDomains(ctx context.Context, since time.Time, limit int) ([]*gtsmodel.DomainTraffic, db.Error) {
+	traffic := []*gtsmodel.DomainTraffic{}
+
+	q := i.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.Status{}).
+		ColumnExpr("? AS ?", bun.Ident("account.domain"), bun.Ident("domain")).
+		ColumnExpr("COUNT(*) AS ?", bun.Ident("statuses")).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("accounts"), bun.Ident("account"), bun.Ident("account.id"), bun.Ident("status.account_id")).
+		Where("? = ?", bun.Ident("status.local"), false).
+		Where("? >= ?", bun.Ident("status.created_at"), since).
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("account.domain")).
+		Group("account.domain").
+		OrderExpr("? DESC", bun.Ident("statuses")).
+		Order("account.domain").
+		Limit(limit)
+
+	if err := q.Scan(ctx, &traffic); err != nil {
+		return nil, i.conn.ProcessError(err)
+	}
+	return traffic, nil
+}
+
 func (i *instanceDB) GetInstanceAccounts(ctx context.Context, domain string, maxID string, limit int) ([]*gtsmodel.Account, db.Error) {
 	logrus.Debug("GetAccountsForInstance")
 
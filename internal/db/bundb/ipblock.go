@@ -0,0 +1,170 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// ipBlockSeverities ranks IP block severities, from least to most severe.
+var ipBlockSeverities = map[string]int{
+	gtsmodel.IPBlockSeveritySignUpRequiresApproval: 1,
+	gtsmodel.IPBlockSeveritySignUpBlock:            2,
+	gtsmodel.IPBlockSeverityNoAccess:               3,
+}
+
+type ipBlockDB struct {
+	conn *DBConn
+
+	// parsed holds the parsed ranges of all ip blocks, so that requests can be checked
+	// without going to the database; it's nil until it's loaded, and reset to nil whenever
+	// ip blocks are changed, so that it's loaded again next time it's needed
+	parsed      []*parsedIPBlock
+	parsedGen   uint64 // incremented on every invalidation, so that a load racing with a change isn't kept
+	parsedMutex sync.RWMutex
+}
+
+// parsedIPBlock is an ip block along with its parsed ip range.
+type parsedIPBlock struct {
+	block *gtsmodel.IPBlock
+	ipNet *net.IPNet
+}
+
+func (i *ipBlockDB) GetIPBlocks(ctx context.Context) ([]*gtsmodel.IPBlock, db.Error) {
+	blocks := []*gtsmodel.IPBlock{}
+
+	q := i.conn.
+		NewSelect().
+		Model(&blocks).
+		Order("ip_block.ip ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, i.conn.ProcessError(err)
+	}
+	return blocks, nil
+}
+
+func (i *ipBlockDB) GetIPBlockSeverity(ctx context.Context, ip net.IP) (string, db.Error) {
+	parsed, err := i.getParsedIPBlocks(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var severity string
+	for _, p := range parsed {
+		if p.block.Expired() || ipBlockSeverities[p.block.Severity] <= ipBlockSeverities[severity] {
+			continue
+		}
+
+		if p.ipNet.Contains(ip) {
+			severity = p.block.Severity
+		}
+	}
+
+	return severity, nil
+}
+
+func (i *ipBlockDB) PutIPBlock(ctx context.Context, block *gtsmodel.IPBlock) db.Error {
+	defer i.invalidateParsedIPBlocks()
+	_, err := i.conn.
+		NewInsert().
+		Model(block).
+		Exec(ctx)
+	return i.conn.ProcessError(err)
+}
+
+func (i *ipBlockDB) UpdateIPBlock(ctx context.Context, block *gtsmodel.IPBlock) db.Error {
+	defer i.invalidateParsedIPBlocks()
+	_, err := i.conn.
+		NewUpdate().
+		Model(block).
+		WherePK().
+		Exec(ctx)
+	return i.conn.ProcessError(err)
+}
+
+func (i *ipBlockDB) DeleteIPBlockByID(ctx context.Context, id string) db.Error {
+	defer i.invalidateParsedIPBlocks()
+	_, err := i.conn.
+		NewDelete().
+		Model(&gtsmodel.IPBlock{}).
+		Where("id = ?", id).
+		Exec(ctx)
+	return i.conn.ProcessError(err)
+}
+
+func (i *ipBlockDB) DeleteExpiredIPBlocks(ctx context.Context, before time.Time) db.Error {
+	defer i.invalidateParsedIPBlocks()
+	_, err := i.conn.
+		NewDelete().
+		Model(&gtsmodel.IPBlock{}).
+		Where("expires_at IS NOT NULL").
+		Where("expires_at <= ?", before).
+		Exec(ctx)
+	return i.conn.ProcessError(err)
+}
+
+// getParsedIPBlocks returns the parsed ranges of all ip blocks, loading and parsing them if they haven't been yet.
+func (i *ipBlockDB) getParsedIPBlocks(ctx context.Context) ([]*parsedIPBlock, db.Error) {
+	i.parsedMutex.RLock()
+	parsed, gen := i.parsed, i.parsedGen
+	i.parsedMutex.RUnlock()
+	if parsed != nil {
+		return parsed, nil
+	}
+
+	blocks, err := i.GetIPBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// ranges are stored as strings, since not every database we support has
+	// a type for them, so they're parsed here rather than matched in a query
+	parsed = make([]*parsedIPBlock, 0, len(blocks))
+	for _, b := range blocks {
+		_, ipNet, err := net.ParseCIDR(b.IP)
+		if err != nil {
+			logrus.Errorf("getParsedIPBlocks: couldn't parse ip range %s of ip block %s: %s", b.IP, b.ID, err)
+			continue
+		}
+		parsed = append(parsed, &parsedIPBlock{block: b, ipNet: ipNet})
+	}
+
+	i.parsedMutex.Lock()
+	if i.parsedGen == gen {
+		i.parsed = parsed
+	}
+	i.parsedMutex.Unlock()
+	return parsed, nil
+}
+
+// invalidateParsedIPBlocks drops the parsed ip blocks, so that they're loaded from the database again next time.
+func (i *ipBlockDB) invalidateParsedIPBlocks() {
+	i.parsedMutex.Lock()
+	i.parsed = nil
+	i.parsedGen++
+	i.parsedMutex.Unlock()
+}
@@ -0,0 +1,87 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type IPBlockTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *IPBlockTestSuite) TestGetIPBlockSeverity() {
+	ctx := context.Background()
+	ip := net.ParseIP("192.0.2.10")
+
+	severity := func() string {
+		s, err := suite.db.GetIPBlockSeverity(ctx, ip)
+		suite.NoError(err)
+		return s
+	}
+
+	// nothing is blocked to begin with, and checking loads the (empty) blocks into memory
+	suite.Empty(severity())
+
+	// new blocks are picked up straight away
+	block := &gtsmodel.IPBlock{
+		ID:                 "01G3F8NKHA3XP9JDDC0WTK9W84",
+		IP:                 "192.0.2.0/24",
+		Severity:           gtsmodel.IPBlockSeveritySignUpBlock,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+	}
+	suite.NoError(suite.db.PutIPBlock(ctx, block))
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpBlock, severity())
+
+	// the most severe block covering the ip wins
+	suite.NoError(suite.db.PutIPBlock(ctx, &gtsmodel.IPBlock{
+		ID:                 "01G3F8P2Y6WJ2M4WQ0HXK8DZ9B",
+		IP:                 "192.0.2.10/32",
+		Severity:           gtsmodel.IPBlockSeverityNoAccess,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+	}))
+	suite.Equal(gtsmodel.IPBlockSeverityNoAccess, severity())
+
+	// as are updates
+	suite.NoError(suite.db.DeleteIPBlockByID(ctx, "01G3F8P2Y6WJ2M4WQ0HXK8DZ9B"))
+	block.Severity = gtsmodel.IPBlockSeveritySignUpRequiresApproval
+	suite.NoError(suite.db.UpdateIPBlock(ctx, block))
+	suite.Equal(gtsmodel.IPBlockSeveritySignUpRequiresApproval, severity())
+
+	// expired blocks don't count, whether or not they've been cleaned up yet
+	block.ExpiresAt = time.Now().Add(-time.Minute)
+	suite.NoError(suite.db.UpdateIPBlock(ctx, block))
+	suite.Empty(severity())
+	suite.NoError(suite.db.DeleteExpiredIPBlocks(ctx, time.Now()))
+	suite.Empty(severity())
+
+	blocks, err := suite.db.GetIPBlocks(ctx)
+	suite.NoError(err)
+	suite.Empty(blocks)
+}
+
+func TestIPBlockTestSuite(t *testing.T) {
+	suite.Run(t, new(IPBlockTestSuite))
+}
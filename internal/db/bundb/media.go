@@ -25,6 +25,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
 )
 
 type mediaDB struct {
@@ -72,3 +73,222 @@ func (m *mediaDB) GetRemoteOlderThan(ctx context.Context, olderThan time.Time, l
 	}
 	return attachments, nil
 }
+
+func (m *mediaDB) GetRemoteUncached(ctx context.Context, accountID string, domain string, maxID string, limit int) ([]*gtsmodel.MediaAttachment, db.Error) {
+	attachments := []*gtsmodel.MediaAttachment{}
+
+	q := m.newRemoteUncachedQ(&attachments, accountID, domain).
+		Order("media_attachment.id DESC")
+
+	if maxID != "" {
+		q = q.Where("media_attachment.id < ?", maxID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+	return attachments, nil
+}
+
+func (m *mediaDB) CountRemoteUncached(ctx context.Context, accountID string, domain string) (int, db.Error) {
+	count, err := m.newRemoteUncachedQ((*gtsmodel.MediaAttachment)(nil), accountID, domain).Count(ctx)
+	if err != nil {
+		return 0, m.conn.ProcessError(err)
+	}
+	return count, nil
+}
+
+// newRemoteUncachedQ returns a query that selects remote attachments that aren't cached,
+// of the given account and on the given domain if they're set, into the given model.
+func (m *mediaDB) newRemoteUncachedQ(model interface{}, accountID string, domain string) *bun.SelectQuery {
+	q := m.conn.
+		NewSelect().
+		Model(model).
+		Where("media_attachment.cached = false").
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("media_attachment.remote_url"))
+
+	if accountID != "" {
+		q = q.Where("media_attachment.account_id = ?", accountID)
+	}
+
+	if domain != "" {
+		q = q.
+			Join("JOIN accounts AS account ON account.id = media_attachment.account_id").
+			Where("account.domain = ?", domain)
+	}
+
+	return q
+}
+
+func (m *mediaDB) GetAccountUnattached(ctx context.Context, accountID string, maxID string, limit int) ([]*gtsmodel.MediaAttachment, db.Error) {
+	attachments := []*gtsmodel.MediaAttachment{}
+
+	q := m.conn.
+		NewSelect().
+		Model(&attachments).
+		Where("media_attachment.account_id = ?", accountID).
+		WhereGroup(" AND ", whereEmptyOrNull("media_attachment.status_id")).
+		Order("media_attachment.id DESC")
+
+	if maxID != "" {
+		q = q.Where("media_attachment.id < ?", maxID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+	return attachments, nil
+}
+
+func (m *mediaDB) GetRandomCached(ctx context.Context, limit int) ([]*gtsmodel.MediaAttachment, db.Error) {
+	newQ := func(dest *[]*gtsmodel.MediaAttachment) *bun.SelectQuery {
+		return m.conn.
+			NewSelect().
+			Model(dest).
+			Where("media_attachment.cached = true")
+	}
+
+	attachments, err := selectRandomSample(ctx, newQ, "media_attachment.id", limit)
+	if err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+	return attachments, nil
+}
+
+func (m *mediaDB) GetRandomEmojis(ctx context.Context, limit int) ([]*gtsmodel.Emoji, db.Error) {
+	newQ := func(dest *[]*gtsmodel.Emoji) *bun.SelectQuery {
+		return m.conn.
+			NewSelect().
+			Model(dest)
+	}
+
+	emojis, err := selectRandomSample(ctx, newQ, "emoji.id", limit)
+	if err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+	return emojis, nil
+}
+
+func (m *mediaDB) GetPopularUncached(ctx context.Context, limit int) ([]*gtsmodel.MediaAttachment, db.Error) {
+	attachments := []*gtsmodel.MediaAttachment{}
+
+	q := m.conn.
+		NewSelect().
+		Model(&attachments).
+		Where("media_attachment.cached = false").
+		Where("media_attachment.access_count > 0").
+		WhereGroup(" AND ", whereNotEmptyAndNotNull("media_attachment.remote_url")).
+		Order("media_attachment.access_count DESC", "media_attachment.id DESC")
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+	return attachments, nil
+}
+
+func (m *mediaDB) IncrementAccessCounts(ctx context.Context, counts map[string]int) db.Error {
+	return m.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		for id, count := range counts {
+			if _, err := tx.
+				NewUpdate().
+				Model(&gtsmodel.MediaAttachment{}).
+				Set("access_count = access_count + ?", count).
+				Where("id = ?", id).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *mediaDB) GetAccountMediaSize(ctx context.Context, accountID string) (int, db.Error) {
+	var size int
+
+	q := m.conn.
+		NewSelect().
+		Model((*gtsmodel.MediaAttachment)(nil)).
+		ColumnExpr("COALESCE(SUM("+m.storedSizeExpr()+"), 0)").
+		Where("media_attachment.account_id = ?", accountID).
+		Where("media_attachment.cached = true")
+
+	if err := q.Scan(ctx, &size); err != nil {
+		return 0, m.conn.ProcessError(err)
+	}
+	return size, nil
+}
+
+func (m *mediaDB) GetMediaSize(ctx context.Context, remote bool) (int, db.Error) {
+	var size int
+
+	q := m.conn.
+		NewSelect().
+		Model((*gtsmodel.MediaAttachment)(nil)).
+		ColumnExpr("COALESCE(SUM("+m.storedSizeExpr()+"), 0)").
+		Where("media_attachment.cached = true")
+
+	if remote {
+		q = q.WhereGroup(" AND ", whereNotEmptyAndNotNull("media_attachment.remote_url"))
+	} else {
+		q = q.WhereGroup(" AND ", whereEmptyOrNull("media_attachment.remote_url"))
+	}
+
+	if err := q.Scan(ctx, &size); err != nil {
+		return 0, m.conn.ProcessError(err)
+	}
+	return size, nil
+}
+
+// storedSizeExpr returns an sql expression for the number of bytes that a media_attachment
+// takes up in storage: its file and thumbnail, and any thumbnail variants. The variants are
+// stored as a json array on the attachment, so their sizes have to be summed from the json.
+func (m *mediaDB) storedSizeExpr() string {
+	var variants string
+	switch m.conn.Dialect().Name() {
+	case dialect.PG:
+		variants = "(SELECT SUM((v ->> 'file_size')::BIGINT) FROM jsonb_array_elements(CASE WHEN jsonb_typeof(media_attachment.variants) = 'array' THEN media_attachment.variants ELSE '[]'::JSONB END) AS v)"
+	case dialect.SQLite:
+		variants = "(SELECT SUM(json_extract(v.value, '$.file_size')) FROM json_each(media_attachment.variants) AS v)"
+	}
+	return "media_attachment.file_file_size + media_attachment.thumbnail_file_size + COALESCE(" + variants + ", 0)"
+}
+
+func (m *mediaDB) GetEmojisByIDs(ctx context.Context, ids []string) ([]*gtsmodel.Emoji, db.Error) {
+	if len(ids) == 0 {
+		return []*gtsmodel.Emoji{}, nil
+	}
+
+	found := []*gtsmodel.Emoji{}
+	if err := m.conn.
+		NewSelect().
+		Model(&found).
+		Where("emoji.id IN (?)", bun.In(ids)).
+		Scan(ctx); err != nil {
+		return nil, m.conn.ProcessError(err)
+	}
+
+	// put them back in the order they were asked for
+	byID := make(map[string]*gtsmodel.Emoji, len(found))
+	for _, e := range found {
+		byID[e.ID] = e
+	}
+	emojis := make([]*gtsmodel.Emoji, 0, len(found))
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			emojis = append(emojis, e)
+		}
+	}
+	return emojis, nil
+}
@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
 type MediaTestSuite struct {
@@ -43,6 +45,238 @@ func (suite *MediaTestSuite) TestGetOlder() {
 	suite.Len(attachments, 1)
 }
 
+func (suite *MediaTestSuite) TestGetRemoteUncached() {
+	ctx := context.Background()
+
+	// everything is cached to begin with
+	attachments, err := suite.db.GetRemoteUncached(ctx, "", "", "", 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+
+	// uncache the remote attachment
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+	testAttachment.Cached = false
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, testAttachment))
+
+	attachments, err = suite.db.GetRemoteUncached(ctx, "", "", "", 20)
+	suite.NoError(err)
+	suite.Len(attachments, 1)
+	suite.Equal(testAttachment.ID, attachments[0].ID)
+
+	attachments, err = suite.db.GetRemoteUncached(ctx, testAttachment.AccountID, "", "", 20)
+	suite.NoError(err)
+	suite.Len(attachments, 1)
+
+	attachments, err = suite.db.GetRemoteUncached(ctx, suite.testAccounts["local_account_2"].ID, "", "", 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+
+	attachments, err = suite.db.GetRemoteUncached(ctx, "", "example.org", "", 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+
+	// paging past the attachment should give nothing
+	attachments, err = suite.db.GetRemoteUncached(ctx, "", "", testAttachment.ID, 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+
+	// counts should match what's selected
+	count, err := suite.db.CountRemoteUncached(ctx, testAttachment.AccountID, "")
+	suite.NoError(err)
+	suite.Equal(1, count)
+
+	count, err = suite.db.CountRemoteUncached(ctx, "", "example.org")
+	suite.NoError(err)
+	suite.Equal(0, count)
+}
+
+func (suite *MediaTestSuite) TestGetAccountUnattached() {
+	ctx := context.Background()
+
+	testAccount := suite.testAccounts["local_account_1"]
+
+	// the avatar, header, and unused upload of the account
+	attachments, err := suite.db.GetAccountUnattached(ctx, testAccount.ID, "", 20)
+	suite.NoError(err)
+	suite.Len(attachments, 3)
+	for _, a := range attachments {
+		suite.Equal(testAccount.ID, a.AccountID)
+		suite.Empty(a.StatusID)
+	}
+
+	// paging should carry on from the last attachment
+	page, err := suite.db.GetAccountUnattached(ctx, testAccount.ID, "", 2)
+	suite.NoError(err)
+	suite.Len(page, 2)
+
+	page, err = suite.db.GetAccountUnattached(ctx, testAccount.ID, page[1].ID, 2)
+	suite.NoError(err)
+	suite.Len(page, 1)
+	suite.Equal(attachments[2].ID, page[0].ID)
+
+	attachments, err = suite.db.GetAccountUnattached(ctx, suite.testAccounts["local_account_2"].ID, "", 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+}
+
+func (suite *MediaTestSuite) TestGetRandomCached() {
+	var cached int
+	for _, a := range suite.testAttachments {
+		if a.Cached {
+			cached++
+		}
+	}
+
+	attachments, err := suite.db.GetRandomCached(context.Background(), 0)
+	suite.NoError(err)
+	suite.Len(attachments, cached)
+	for _, a := range attachments {
+		suite.True(a.Cached)
+	}
+
+	attachments, err = suite.db.GetRandomCached(context.Background(), 2)
+	suite.NoError(err)
+	suite.Len(attachments, 2)
+
+	// however the sample starts, it should wrap around to cover every attachment without repeating any
+	for i := 0; i < 10; i++ {
+		attachments, err = suite.db.GetRandomCached(context.Background(), cached)
+		suite.NoError(err)
+		ids := map[string]bool{}
+		for _, a := range attachments {
+			ids[a.ID] = true
+		}
+		suite.Len(ids, cached)
+	}
+}
+
+func (suite *MediaTestSuite) TestGetRandomEmojis() {
+	emojis, err := suite.db.GetRandomEmojis(context.Background(), 0)
+	suite.NoError(err)
+	suite.Len(emojis, len(suite.testEmojis))
+
+	emojis, err = suite.db.GetRandomEmojis(context.Background(), 1)
+	suite.NoError(err)
+	suite.Len(emojis, 1)
+}
+
+func (suite *MediaTestSuite) TestGetPopularUncached() {
+	ctx := context.Background()
+
+	// uncache the remote attachment
+	testAttachment, err := suite.db.GetAttachmentByID(ctx, suite.testAttachments["remote_account_1_status_1_attachment_1"].ID)
+	suite.NoError(err)
+	testAttachment.Cached = false
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, testAttachment))
+
+	// it hasn't been accessed yet, so it's not popular
+	attachments, err := suite.db.GetPopularUncached(ctx, 20)
+	suite.NoError(err)
+	suite.Empty(attachments)
+
+	suite.NoError(suite.db.IncrementAccessCounts(ctx, map[string]int{testAttachment.ID: 2}))
+
+	attachments, err = suite.db.GetPopularUncached(ctx, 20)
+	suite.NoError(err)
+	suite.Len(attachments, 1)
+	suite.Equal(testAttachment.ID, attachments[0].ID)
+	suite.Equal(2, attachments[0].AccessCount)
+}
+
+func (suite *MediaTestSuite) TestGetAccountMediaSize() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	var expected int
+	for _, a := range suite.testAttachments {
+		if a.AccountID == testAccount.ID && a.Cached {
+			expected += storedSize(a)
+		}
+	}
+
+	size, err := suite.db.GetAccountMediaSize(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.NotZero(size)
+	suite.Equal(expected, size)
+}
+
+func (suite *MediaTestSuite) TestGetMediaSize() {
+	var expectedLocal, expectedRemote int
+	for _, a := range suite.testAttachments {
+		if !a.Cached {
+			continue
+		}
+		if a.RemoteURL == "" {
+			expectedLocal += storedSize(a)
+		} else {
+			expectedRemote += storedSize(a)
+		}
+	}
+
+	local, err := suite.db.GetMediaSize(context.Background(), false)
+	suite.NoError(err)
+	suite.NotZero(local)
+	suite.Equal(expectedLocal, local)
+
+	remote, err := suite.db.GetMediaSize(context.Background(), true)
+	suite.NoError(err)
+	suite.NotZero(remote)
+	suite.Equal(expectedRemote, remote)
+}
+
+func (suite *MediaTestSuite) TestGetAccountMediaSizeVariants() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["local_account_1"]
+
+	before, err := suite.db.GetAccountMediaSize(ctx, testAccount.ID)
+	suite.NoError(err)
+	localBefore, err := suite.db.GetMediaSize(ctx, false)
+	suite.NoError(err)
+
+	// thumbnail variants take up storage too
+	attachment := testrig.NewTestAttachments()["local_account_1_unattached_1"]
+	attachment.Variants = []gtsmodel.Variant{
+		{Name: "medium", FileSize: 1000},
+		{Name: "large", FileSize: 234},
+	}
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, attachment))
+
+	after, err := suite.db.GetAccountMediaSize(ctx, testAccount.ID)
+	suite.NoError(err)
+	suite.Equal(before+1234, after)
+
+	localAfter, err := suite.db.GetMediaSize(ctx, false)
+	suite.NoError(err)
+	suite.Equal(localBefore+1234, localAfter)
+}
+
+func (suite *MediaTestSuite) TestGetAccountMediaSizeNoMedia() {
+	size, err := suite.db.GetAccountMediaSize(context.Background(), suite.testAccounts["local_account_2"].ID)
+	suite.NoError(err)
+	suite.Zero(size)
+}
+
+func (suite *MediaTestSuite) TestGetEmojisByIDs() {
+	emojis, err := suite.db.GetEmojisByIDs(context.Background(), []string{"01GD5KR15NMKDDWCV6DTNCWPXB", suite.testEmojis["rainbow"].ID})
+	suite.NoError(err)
+
+	// the emoji that doesn't exist should just be left out
+	suite.Len(emojis, 1)
+	suite.Equal("rainbow", emojis[0].Shortcode)
+
+	emojis, err = suite.db.GetEmojisByIDs(context.Background(), nil)
+	suite.NoError(err)
+	suite.Empty(emojis)
+}
+
 func TestMediaTestSuite(t *testing.T) {
 	suite.Run(t, new(MediaTestSuite))
 }
+
+// storedSize returns the number of bytes that the given attachment takes up in storage.
+func storedSize(a *gtsmodel.MediaAttachment) int {
+	size := a.File.FileSize + a.Thumbnail.FileSize
+	for _, v := range a.Variants {
+		size += v.FileSize
+	}
+	return size
+}
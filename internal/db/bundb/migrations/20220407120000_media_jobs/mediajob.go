@@ -0,0 +1,34 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// MediaJob represents a piece of remote media that's been queued for processing by the media manager, but not finished yet.
+// Jobs are kept in the database so that they can be queued again if GoToSocial is stopped before they're done.
+type MediaJob struct {
+	ID           string                 `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt    time.Time              `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	Type         string                 `validate:"oneof=attachment emoji recache" bun:",nullzero,notnull"`
+	AttachmentID string                 `validate:"required_without=EmojiID,omitempty,ulid" bun:"type:CHAR(26),nullzero"`
+	EmojiID      string                 `validate:"required_without=AttachmentID,omitempty,ulid" bun:"type:CHAR(26),nullzero"`
+	RemoteURL    string                 `validate:"required,url" bun:",nullzero,notnull"`
+	Attachment   map[string]interface{} `validate:"-" bun:",nullzero"`
+	Emoji        map[string]interface{} `validate:"-" bun:",nullzero"`
+}
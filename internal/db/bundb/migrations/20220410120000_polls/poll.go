@@ -0,0 +1,46 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Poll represents a poll attached to a status, either local or remote.
+type Poll struct {
+	ID          string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	StatusID    string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique"`
+	Options     []string  `validate:"min=1" bun:",nullzero,notnull"`
+	Votes       []int     `validate:"-" bun:",nullzero"`
+	VotersCount int       `validate:"-" bun:",notnull,default:0"`
+	Multiple    bool      `validate:"-" bun:",notnull,default:false"`
+	HideCounts  bool      `validate:"-" bun:",notnull,default:false"`
+	ExpiresAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero"`
+	ClosedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero"`
+}
+
+// PollVote represents one choice made by an account in a poll.
+type PollVote struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	URI       string    `validate:"omitempty,url" bun:",nullzero,unique"`
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),unique:pollvote,nullzero,notnull"`
+	PollID    string    `validate:"required,ulid" bun:"type:CHAR(26),unique:pollvote,nullzero,notnull"`
+	Choice    int       `validate:"min=0" bun:",unique:pollvote,notnull"`
+}
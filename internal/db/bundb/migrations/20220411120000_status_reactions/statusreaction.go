@@ -0,0 +1,34 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// StatusReaction refers to an emoji reaction in the database, from one account, targeting the status of another account.
+type StatusReaction struct {
+	ID              string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	AccountID       string    `validate:"required,ulid" bun:"type:CHAR(26),unique:statusreaction,nullzero,notnull"`
+	TargetAccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+	StatusID        string    `validate:"required,ulid" bun:"type:CHAR(26),unique:statusreaction,nullzero,notnull"`
+	Emoji           string    `validate:"required" bun:",unique:statusreaction,nullzero,notnull"`
+	CustomEmojiID   string    `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`
+	URI             string    `validate:"required,url" bun:",nullzero,notnull"`
+}
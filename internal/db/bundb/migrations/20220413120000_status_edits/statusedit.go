@@ -0,0 +1,35 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// StatusEdit is a previous version of a status that has since been edited.
+type StatusEdit struct {
+	ID             string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	StatusID       string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+	Content        string    `validate:"-" bun:""`
+	ContentWarning string    `validate:"-" bun:",nullzero"`
+	Text           string    `validate:"-" bun:""`
+	Sensitive      bool      `validate:"-" bun:",notnull,default:false"`
+	AttachmentIDs  []string  `validate:"dive,ulid" bun:"attachments,array"`
+	EmojiIDs       []string  `validate:"dive,ulid" bun:"emojis,array"`
+}
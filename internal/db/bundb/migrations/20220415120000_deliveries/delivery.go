@@ -0,0 +1,35 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Delivery represents an activity that couldn't be delivered to a remote inbox, and is waiting to be tried again.
+type Delivery struct {
+	ID            string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt     time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt     time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	PubKeyID      string    `validate:"required,url" bun:",nullzero,notnull"`                                // id of the public key of the local account that the activity is delivered as
+	InboxURI      string    `validate:"required,url" bun:",nullzero,notnull"`                                // inbox that the activity is being delivered to
+	Payload       []byte    `validate:"required" bun:",notnull"`                                             // serialized activity to deliver
+	Attempts      int       `validate:"-" bun:",notnull,default:0"`                                          // how many times delivery has been tried so far
+	LastError     string    `validate:"-" bun:",nullzero"`                                                   // error from the last time delivery was tried
+	NextAttemptAt time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when should delivery be tried again?
+	FailedAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when was delivery given up on? zero means it's still being tried
+}
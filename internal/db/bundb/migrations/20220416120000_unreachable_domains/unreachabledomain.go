@@ -0,0 +1,33 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// UnreachableDomain represents a remote domain that deliveries have been failing to, since the last one that succeeded.
+type UnreachableDomain struct {
+	ID           string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created, ie., when did deliveries start failing
+	UpdatedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Domain       string    `validate:"required,fqdn" bun:",nullzero,notnull,unique"`                        // domain that deliveries are failing to eg example.org
+	Failures     int       `validate:"-" bun:",notnull,default:0"`                                          // how many deliveries in a row have failed
+	LastError    string    `validate:"-" bun:",nullzero"`                                                   // error from the most recent failed delivery
+	SuspendedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when were deliveries to this domain suspended? zero means they haven't been
+	LastProbedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when was this domain last checked to see if it's come back?
+}
@@ -0,0 +1,107 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// ed25519 keys are stored as raw bytes
+			keyType := "BYTEA"
+			if tx.Dialect().Name() == dialect.SQLite {
+				keyType = "BLOB"
+			}
+
+			// add the assertion key columns to accounts
+			for _, column := range []struct {
+				name       string
+				columnType string
+			}{
+				{name: "assertion_private_key", columnType: keyType},
+				{name: "assertion_public_key", columnType: keyType},
+				{name: "assertion_key_uri", columnType: "VARCHAR"},
+			} {
+				if _, err := tx.
+					NewAddColumn().
+					Model(&gtsmodel.Account{}).
+					ColumnExpr("? "+column.columnType, bun.Ident(column.name)).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			// give every local account an assertion key, so that they can all make integrity proofs straight away
+			localAccounts := []struct {
+				ID  string
+				URI string
+			}{}
+			if err := tx.
+				NewSelect().
+				Table("accounts").
+				Column("id", "uri").
+				WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.
+						WhereOr("? IS NULL", bun.Ident("domain")).
+						WhereOr("? = ''", bun.Ident("domain"))
+				}).
+				Scan(ctx, &localAccounts); err != nil {
+				return err
+			}
+
+			for _, account := range localAccounts {
+				publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+				if err != nil {
+					return err
+				}
+
+				if _, err := tx.
+					NewUpdate().
+					Table("accounts").
+					Set("? = ?", bun.Ident("assertion_private_key"), []byte(privateKey)).
+					Set("? = ?", bun.Ident("assertion_public_key"), []byte(publicKey)).
+					Set("? = ?", bun.Ident("assertion_key_uri"), account.URI+"#ed25519-key").
+					Where("? = ?", bun.Ident("id"), account.ID).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// WebPushSubscription is a subscription by a client of a local account to be sent notifications through a web push service.
+// Each access token can have one subscription.
+type WebPushSubscription struct {
+	ID                 string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID          string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that owns this subscription
+	TokenID            string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique"`           // id of the access token this subscription was created with
+	Endpoint           string    `validate:"required,url" bun:",nullzero,notnull"`                                // url of the push service endpoint to send notifications to
+	P256dh             string    `validate:"required" bun:",nullzero,notnull"`                                    // base64url encoded public key of the client, to encrypt notifications with
+	Auth               string    `validate:"required" bun:",nullzero,notnull"`                                    // base64url encoded auth secret of the client, to encrypt notifications with
+	AlertFollow        bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of new follows
+	AlertFollowRequest bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of new follow requests
+	AlertFavourite     bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of faves
+	AlertReblog        bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of boosts
+	AlertMention       bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of mentions
+	AlertPoll          bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of ended polls
+	AlertStatus        bool      `validate:"-" bun:",notnull,default:false"`                                      // send notifications of new statuses by accounts with notifications enabled
+}
+
+// VAPIDKeyPair is the ECDSA P-256 key pair that this instance uses to identify itself to web push services.
+// There's only ever one of these, created at startup if it doesn't exist yet.
+type VAPIDKeyPair struct {
+	ID         string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	PrivateKey string    `validate:"required" bun:",nullzero,notnull"`                                    // base64url encoded private key
+	PublicKey  string    `validate:"required" bun:",nullzero,notnull"`                                    // base64url encoded uncompressed public key, given to clients as the server key
+}
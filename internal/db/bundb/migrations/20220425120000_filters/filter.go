@@ -0,0 +1,37 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Filter is a phrase that an account doesn't want to see statuses containing, in the given contexts.
+type Filter struct {
+	ID                   string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt            time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt            time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID            string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that created the filter
+	Phrase               string    `validate:"required" bun:",nullzero,notnull"`                                    // text to filter out
+	ContextHome          bool      `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to the home timeline
+	ContextNotifications bool      `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to notifications
+	ContextPublic        bool      `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to public and hashtag timelines
+	ContextThread        bool      `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to the replies and parents of a status
+	WholeWord            bool      `validate:"-" bun:",notnull,default:false"`                                      // only match the phrase at word boundaries
+	Irreversible         bool      `validate:"-" bun:",notnull,default:false"`                                      // clients shouldn't offer to show filtered statuses anyway; we always drop them server side, so this is just stored for clients
+	ExpiresAt            time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when does the filter stop applying? zero means it doesn't expire
+}
@@ -0,0 +1,137 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+
+	gtsmodel "github.com/superseriousbusiness/gotosocial/internal/db/bundb/migrations/20220426120000_filters_v2"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// keywords and statuses get tables of their own, so that one filter can have several of them
+			if _, err := tx.NewCreateTable().Model(&gtsmodel.FilterKeyword{}).IfNotExists().Exec(ctx); err != nil {
+				return err
+			}
+
+			if _, err := tx.
+				NewCreateIndex().
+				Model(&gtsmodel.FilterKeyword{}).
+				Index("filter_keywords_filter_id_idx").
+				Column("filter_id").
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			if _, err := tx.NewCreateTable().Model(&gtsmodel.FilterStatus{}).IfNotExists().Exec(ctx); err != nil {
+				return err
+			}
+
+			if _, err := tx.
+				NewCreateIndex().
+				Model(&gtsmodel.FilterStatus{}).
+				Index("filter_statuses_filter_id_idx").
+				Column("filter_id").
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			// filters get a title, an action, and a new context
+			for _, column := range []struct {
+				name string
+				expr string
+			}{
+				{name: "title", expr: "? VARCHAR NOT NULL DEFAULT ''"},
+				{name: "action", expr: "? VARCHAR NOT NULL DEFAULT 'hide'"},
+				{name: "context_account", expr: "? BOOLEAN NOT NULL DEFAULT false"},
+			} {
+				if _, err := tx.
+					NewAddColumn().
+					Table("filters").
+					ColumnExpr(column.expr, bun.Ident(column.name)).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			// every existing filter had one phrase, which becomes its one keyword; the keyword takes the id of
+			// the filter, so that filters keep the same ids in the v1 api. Existing filters were always applied
+			// by removing matching statuses, so they keep the default action of hide.
+			oldFilters := []struct {
+				ID        string `bun:"id"`
+				AccountID string `bun:"account_id"`
+				Phrase    string `bun:"phrase"`
+				WholeWord bool   `bun:"whole_word"`
+			}{}
+			if err := tx.
+				NewSelect().
+				Table("filters").
+				Column("id", "account_id", "phrase", "whole_word").
+				Scan(ctx, &oldFilters); err != nil {
+				return err
+			}
+
+			for _, f := range oldFilters {
+				if _, err := tx.NewInsert().Model(&gtsmodel.FilterKeyword{
+					ID:        f.ID,
+					AccountID: f.AccountID,
+					FilterID:  f.ID,
+					Keyword:   f.Phrase,
+					WholeWord: f.WholeWord,
+				}).Exec(ctx); err != nil {
+					return err
+				}
+
+				if _, err := tx.
+					NewUpdate().
+					Table("filters").
+					Set("? = ?", bun.Ident("title"), f.Phrase).
+					Where("? = ?", bun.Ident("id"), f.ID).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			for _, column := range []string{"phrase", "whole_word", "irreversible"} {
+				if _, err := tx.
+					NewDropColumn().
+					Table("filters").
+					Column(column).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
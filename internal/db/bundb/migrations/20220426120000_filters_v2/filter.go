@@ -0,0 +1,42 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// FilterKeyword is a phrase that a filter matches statuses containing.
+type FilterKeyword struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that owns the filter
+	FilterID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the filter this keyword belongs to
+	Keyword   string    `validate:"required" bun:",nullzero,notnull"`                                    // text to match
+	WholeWord bool      `validate:"-" bun:",notnull,default:false"`                                      // only match the keyword at word boundaries
+}
+
+// FilterStatus is one particular status that a filter matches.
+type FilterStatus struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that owns the filter
+	FilterID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the filter this status belongs to
+	StatusID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the status to match
+}
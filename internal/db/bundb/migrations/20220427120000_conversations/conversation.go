@@ -0,0 +1,39 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Conversation is a thread of direct messages, as seen by one local account taking part in it.
+type Conversation struct {
+	ID               string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt        time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt        time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	AccountID        string    `validate:"required,ulid" bun:"type:CHAR(26),unique:conversationaccounts,nullzero,notnull"`
+	OtherAccountIDs  []string  `validate:"dive,ulid" bun:"other_accounts,array"`
+	OtherAccountsKey string    `validate:"-" bun:",unique:conversationaccounts,notnull"`
+	LastStatusID     string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+	Read             bool      `validate:"-" bun:",notnull,default:false"`
+}
+
+// ConversationToStatus is an intermediate struct to facilitate the many2many relationship between a conversation and the statuses in it.
+type ConversationToStatus struct {
+	ConversationID string `validate:"ulid,required" bun:"type:CHAR(26),unique:conversationstatus,nullzero,notnull"`
+	StatusID       string `validate:"ulid,required" bun:"type:CHAR(26),unique:conversationstatus,nullzero,notnull"`
+}
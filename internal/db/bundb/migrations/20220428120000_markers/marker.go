@@ -0,0 +1,30 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Marker is the last read position of a local account in one of its timelines, for syncing between clients.
+type Marker struct {
+	AccountID  string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull"`
+	Name       string    `validate:"oneof=home notifications" bun:",pk,nullzero,notnull"`
+	UpdatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	Version    int       `validate:"-" bun:",notnull,default:0"`
+	LastReadID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+}
@@ -0,0 +1,66 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var stmts []string
+			switch tx.Dialect().Name() {
+			case dialect.PG:
+				stmts = []string{
+					"CREATE INDEX IF NOT EXISTS statuses_search_idx ON statuses USING GIN (to_tsvector('simple', coalesce(content_warning, '') || ' ' || coalesce(content, '')))",
+				}
+			case dialect.SQLite:
+				stmts = []string{
+					"CREATE VIRTUAL TABLE IF NOT EXISTS status_search USING fts5(status_id UNINDEXED, content, content_warning)",
+					"CREATE TRIGGER IF NOT EXISTS status_search_insert AFTER INSERT ON statuses BEGIN INSERT INTO status_search(status_id, content, content_warning) VALUES (new.id, new.content, new.content_warning); END",
+					"CREATE TRIGGER IF NOT EXISTS status_search_delete AFTER DELETE ON statuses BEGIN DELETE FROM status_search WHERE status_id = old.id; END",
+					"CREATE TRIGGER IF NOT EXISTS status_search_update AFTER UPDATE OF content, content_warning ON statuses BEGIN UPDATE status_search SET content = new.content, content_warning = new.content_warning WHERE status_id = new.id; END",
+					// index the statuses we already have
+					"INSERT INTO status_search(status_id, content, content_warning) SELECT id, content, content_warning FROM statuses",
+				}
+			}
+
+			for _, stmt := range stmts {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
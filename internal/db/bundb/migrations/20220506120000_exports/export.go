@@ -0,0 +1,33 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Export is a file of an account's data, such as the accounts it follows, generated in the background for the account to download.
+type Export struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+	Type      string    `validate:"oneof=follows followers blocks mutes bookmarks lists" bun:",nullzero,notnull"`
+	State     string    `validate:"oneof=pending done failed" bun:",nullzero,notnull"`
+	Path      string    `validate:"required_if=State done" bun:",nullzero"`
+	Size      int       `validate:"min=0" bun:",nullzero"`
+}
@@ -0,0 +1,36 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Import is a csv file of accounts, such as one exported from Mastodon, which an account has uploaded to follow, block or mute
+// all of those accounts. Imports are worked through in the background, a bit at a time, so that remote instances aren't flooded.
+type Import struct {
+	ID              string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`
+	CreatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`
+	AccountID       string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`
+	Type            string    `validate:"oneof=follows blocks mutes" bun:",nullzero,notnull"`
+	State           string    `validate:"oneof=pending done failed" bun:",nullzero,notnull"`
+	Path            string    `validate:"-" bun:",nullzero"`
+	Total           int       `validate:"min=0" bun:",nullzero"`
+	Processed       int       `validate:"min=0" bun:",nullzero"`
+	FailedAddresses []string  `validate:"-" bun:"failed_addresses,array"`
+}
@@ -0,0 +1,135 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+
+	text "github.com/superseriousbusiness/gotosocial/internal/db/bundb/migrations/20220516120000_status_search_text"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		// the plain text is filled in a page at a time, with each page committed as it goes, so that a big
+		// instance isn't stuck in one huge transaction; if that's interrupted, the column will already be
+		// there when this is run again, and only the statuses that haven't been filled in yet are done
+		columnQuery := "SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'statuses' AND column_name = 'content_text'"
+		if db.Dialect().Name() == dialect.SQLite {
+			columnQuery = "SELECT COUNT(*) FROM pragma_table_info('statuses') WHERE name = 'content_text'"
+		}
+		var columns int
+		if err := db.QueryRowContext(ctx, columnQuery).Scan(&columns); err != nil {
+			return err
+		}
+		columnExists := columns > 0
+
+		if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if !columnExists {
+				if _, err := tx.
+					NewAddColumn().
+					Model(&gtsmodel.Status{}).
+					ColumnExpr("? TEXT", bun.Ident("content_text")).
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			// the old index covers the raw html content, so drop it before filling in the new column
+			for _, stmt := range StatusSearchIndexDrop(tx.Dialect()) {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// fill in the plain text of the statuses we already have
+		type statusContent struct {
+			bun.BaseModel `bun:"table:statuses"`
+			ID            string `bun:",pk"`
+			Content       string
+		}
+		var lastID string
+		for {
+			page := []*statusContent{}
+			if err := db.
+				NewSelect().
+				Model(&page).
+				Column("id", "content").
+				Where("id > ?", lastID).
+				Where("content_text IS NULL").
+				Order("id ASC").
+				Limit(500).
+				Scan(ctx); err != nil {
+				return err
+			}
+			if len(page) == 0 {
+				break
+			}
+
+			if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				for _, s := range page {
+					if _, err := tx.
+						NewUpdate().
+						Table("statuses").
+						Set("content_text = ?", text.SearchableText(s.Content)).
+						Where("id = ?", s.ID).
+						Exec(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			lastID = page[len(page)-1].ID
+		}
+
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			for _, stmt := range StatusSearchIndexCreate(tx.Dialect()) {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+
+			if tx.Dialect().Name() == dialect.SQLite {
+				// index the statuses we already have
+				if _, err := tx.ExecContext(ctx, "INSERT INTO status_search(status_id, content, content_warning) SELECT id, content_text, content_warning FROM statuses"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
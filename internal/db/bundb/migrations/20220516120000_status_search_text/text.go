@@ -0,0 +1,36 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package text
+
+import (
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// searchable strips all html, putting a space where each element was, so that
+// words either side of a tag like <br/> aren't run together.
+var searchable *bluemonday.Policy = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+
+// SearchableText removes all HTML from the given string and unescapes any entities,
+// leaving plain words separated by single spaces, suitable for full-text indexing.
+func SearchableText(in string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(searchable.Sanitize(in))), " ")
+}
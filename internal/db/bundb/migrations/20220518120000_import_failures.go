@@ -0,0 +1,93 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"context"
+
+	gtsmodel "github.com/superseriousbusiness/gotosocial/internal/db/bundb/migrations/20220518120000_import_failures"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	up := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			// failures get a table of their own, so that a failure can be stored without rewriting all the ones before it
+			if _, err := tx.NewCreateTable().Model(&gtsmodel.ImportFailure{}).IfNotExists().Exec(ctx); err != nil {
+				return err
+			}
+
+			if _, err := tx.
+				NewCreateIndex().
+				Model(&gtsmodel.ImportFailure{}).
+				Index("import_failures_import_id_idx").
+				Column("import_id").
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			oldImports := []struct {
+				ID              string   `bun:"id"`
+				FailedAddresses []string `bun:"failed_addresses,array"`
+			}{}
+			if err := tx.
+				NewSelect().
+				Table("imports").
+				Column("id", "failed_addresses").
+				Scan(ctx, &oldImports); err != nil {
+				return err
+			}
+
+			for _, i := range oldImports {
+				for _, address := range i.FailedAddresses {
+					failureID, err := id.NewULID()
+					if err != nil {
+						return err
+					}
+
+					if _, err := tx.NewInsert().Model(&gtsmodel.ImportFailure{
+						ID:       failureID,
+						ImportID: i.ID,
+						Address:  address,
+					}).Exec(ctx); err != nil {
+						return err
+					}
+				}
+			}
+
+			_, err := tx.
+				NewDropColumn().
+				Table("imports").
+				Column("failed_addresses").
+				Exec(ctx)
+			return err
+		})
+	}
+
+	down := func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+	}
+
+	if err := Migrations.Register(up, down); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package migrations
+
+import (
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// StatusSearchIndexCreate returns the statements that create the full-text index used to search
+// statuses: a gin index for postgres, or an fts5 table kept in step with the statuses table by
+// triggers for sqlite. Both index the content warning and the html-stripped content_text column.
+//
+// The statements don't index any existing statuses in the sqlite fts5 table.
+func StatusSearchIndexCreate(d schema.Dialect) []string {
+	switch d.Name() {
+	case dialect.PG:
+		return []string{
+			"CREATE INDEX IF NOT EXISTS statuses_search_idx ON statuses USING GIN (to_tsvector('simple', coalesce(content_warning, '') || ' ' || coalesce(content_text, '')))",
+		}
+	case dialect.SQLite:
+		return []string{
+			"CREATE VIRTUAL TABLE IF NOT EXISTS status_search USING fts5(status_id UNINDEXED, content, content_warning)",
+			"CREATE TRIGGER IF NOT EXISTS status_search_insert AFTER INSERT ON statuses BEGIN INSERT INTO status_search(status_id, content, content_warning) VALUES (new.id, new.content_text, new.content_warning); END",
+			"CREATE TRIGGER IF NOT EXISTS status_search_delete AFTER DELETE ON statuses BEGIN DELETE FROM status_search WHERE status_id = old.id; END",
+			"CREATE TRIGGER IF NOT EXISTS status_search_update AFTER UPDATE OF content_text, content_warning ON statuses BEGIN UPDATE status_search SET content = new.content_text, content_warning = new.content_warning WHERE status_id = new.id; END",
+		}
+	}
+	return nil
+}
+
+// StatusSearchIndexDrop returns the statements that drop everything created by StatusSearchIndexCreate.
+func StatusSearchIndexDrop(d schema.Dialect) []string {
+	switch d.Name() {
+	case dialect.PG:
+		return []string{
+			"DROP INDEX IF EXISTS statuses_search_idx",
+		}
+	case dialect.SQLite:
+		return []string{
+			"DROP TRIGGER IF EXISTS status_search_insert",
+			"DROP TRIGGER IF EXISTS status_search_delete",
+			"DROP TRIGGER IF EXISTS status_search_update",
+			"DROP TABLE IF EXISTS status_search",
+		}
+	}
+	return nil
+}
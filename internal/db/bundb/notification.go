@@ -46,7 +46,7 @@ func (n *notificationDB) GetNotification(ctx context.Context, id string) (*gtsmo
 		return notification, nil
 	}
 
-	notif := &gtsmodel.Notification{}
+	notif := &gtsmodel.Notification{ID: id}
 	err := n.getNotificationDB(ctx, id, notif)
 	if err != nil {
 		return nil, err
@@ -54,7 +54,7 @@ func (n *notificationDB) GetNotification(ctx context.Context, id string) (*gtsmo
 	return notif, nil
 }
 
-func (n *notificationDB) GetNotifications(ctx context.Context, accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, db.Error) {
+func (n *notificationDB) GetNotifications(ctx context.Context, accountID string, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string) ([]*gtsmodel.Notification, db.Error) {
 	// Ensure reasonable
 	if limit < 0 {
 		limit = 0
@@ -76,9 +76,26 @@ func (n *notificationDB) GetNotifications(ctx context.Context, accountID string,
 		q = q.Where("id > ?", sinceID)
 	}
 
-	q = q.
-		Where("target_account_id = ?", accountID).
-		Order("id DESC")
+	if minID != "" {
+		q = q.Where("id > ?", minID)
+	}
+
+	if len(types) != 0 {
+		q = q.Where("notification_type IN (?)", bun.In(types))
+	}
+
+	if len(excludeTypes) != 0 {
+		q = q.Where("notification_type NOT IN (?)", bun.In(excludeTypes))
+	}
+
+	q = q.Where("target_account_id = ?", accountID)
+
+	if minID != "" && maxID == "" {
+		// page up from minID, so that we get the notifications right after it rather than the newest ones
+		q = q.Order("id ASC")
+	} else {
+		q = q.Order("id DESC")
+	}
 
 	if limit != 0 {
 		q = q.Limit(limit)
@@ -89,6 +106,13 @@ func (n *notificationDB) GetNotifications(ctx context.Context, accountID string,
 		return nil, n.conn.ProcessError(err)
 	}
 
+	if minID != "" && maxID == "" {
+		// put the notifications back in descending order
+		for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+			notifications[i], notifications[j] = notifications[j], notifications[i]
+		}
+	}
+
 	// now we have the IDs, select the notifs one by one
 	// reason for this is that for each notif, we can instead get it from our cache if it's cached
 	for i, notif := range notifications {
@@ -109,6 +133,49 @@ func (n *notificationDB) GetNotifications(ctx context.Context, accountID string,
 	return notifications, nil
 }
 
+func (n *notificationDB) DeleteNotification(ctx context.Context, id string) db.Error {
+	if _, err := n.conn.
+		NewDelete().
+		Model(&gtsmodel.Notification{}).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return n.conn.ProcessError(err)
+	}
+
+	n.cache.Remove(id)
+	return nil
+}
+
+func (n *notificationDB) ClearNotifications(ctx context.Context, accountID string) db.Error {
+	ids := []string{}
+	if err := n.conn.
+		NewSelect().
+		Model(&gtsmodel.Notification{}).
+		Column("id").
+		Where("target_account_id = ?", accountID).
+		Scan(ctx, &ids); err != nil {
+		return n.conn.ProcessError(err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := n.conn.
+		NewDelete().
+		Model(&gtsmodel.Notification{}).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx); err != nil {
+		return n.conn.ProcessError(err)
+	}
+
+	// make sure the deleted notifications aren't served from the cache
+	for _, id := range ids {
+		n.cache.Remove(id)
+	}
+	return nil
+}
+
 func (n *notificationDB) getNotificationCache(id string) (*gtsmodel.Notification, bool) {
 	v, ok := n.cache.Get(id)
 	if !ok {
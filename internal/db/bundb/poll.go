@@ -0,0 +1,170 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+type pollDB struct {
+	conn *DBConn
+}
+
+func (p *pollDB) GetPollByID(ctx context.Context, id string) (*gtsmodel.Poll, db.Error) {
+	poll := &gtsmodel.Poll{}
+
+	q := p.conn.
+		NewSelect().
+		Model(poll).
+		Where("poll.id = ?", id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, p.conn.ProcessError(err)
+	}
+	return poll, nil
+}
+
+func (p *pollDB) GetExpiredPolls(ctx context.Context, before time.Time) ([]*gtsmodel.Poll, db.Error) {
+	polls := []*gtsmodel.Poll{}
+
+	q := p.conn.
+		NewSelect().
+		Model(&polls).
+		Where("poll.expires_at IS NOT NULL").
+		Where("poll.expires_at <= ?", before).
+		Where("poll.closed_at IS NULL").
+		Order("poll.expires_at ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, p.conn.ProcessError(err)
+	}
+	return polls, nil
+}
+
+func (p *pollDB) GetPollVotes(ctx context.Context, pollID string) ([]*gtsmodel.PollVote, db.Error) {
+	votes := []*gtsmodel.PollVote{}
+
+	q := p.conn.
+		NewSelect().
+		Model(&votes).
+		Relation("Account").
+		Where("poll_vote.poll_id = ?", pollID).
+		Order("poll_vote.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, p.conn.ProcessError(err)
+	}
+	return votes, nil
+}
+
+func (p *pollDB) GetAccountPollVotes(ctx context.Context, pollID string, accountID string) ([]*gtsmodel.PollVote, db.Error) {
+	votes := []*gtsmodel.PollVote{}
+
+	q := p.conn.
+		NewSelect().
+		Model(&votes).
+		Where("poll_vote.poll_id = ?", pollID).
+		Where("poll_vote.account_id = ?", accountID).
+		Order("poll_vote.choice ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, p.conn.ProcessError(err)
+	}
+	return votes, nil
+}
+
+func (p *pollDB) PutPollVote(ctx context.Context, vote *gtsmodel.PollVote) db.Error {
+	return p.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		// get the poll first, locking it where we can, so that concurrent votes are checked and counted one at a time
+		poll := &gtsmodel.Poll{}
+		q := tx.NewSelect().Model(poll).Where("poll.id = ?", vote.PollID)
+		if p.conn.Dialect().Name() == dialect.PG {
+			q = q.For("UPDATE")
+		}
+		if err := q.Scan(ctx); err != nil {
+			return err
+		}
+
+		if vote.Choice >= len(poll.Options) {
+			return fmt.Errorf("PutPollVote: poll %s has no option %d", poll.ID, vote.Choice)
+		}
+
+		existingVotes, err := tx.
+			NewSelect().
+			Model((*gtsmodel.PollVote)(nil)).
+			Where("poll_vote.poll_id = ?", vote.PollID).
+			Where("poll_vote.account_id = ?", vote.AccountID).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+
+		// only one vote per account is allowed in single choice polls
+		if existingVotes != 0 && !poll.Multiple {
+			return db.NewErrAlreadyExists(fmt.Sprintf("account %s has already voted in single choice poll %s", vote.AccountID, poll.ID))
+		}
+
+		if _, err := tx.NewInsert().Model(vote).Exec(ctx); err != nil {
+			return err
+		}
+
+		if len(poll.Votes) != len(poll.Options) {
+			poll.Votes = make([]int, len(poll.Options))
+		}
+		poll.Votes[vote.Choice]++
+		if existingVotes == 0 {
+			poll.VotersCount++
+		}
+		poll.UpdatedAt = time.Now()
+
+		_, err = tx.
+			NewUpdate().
+			Model(poll).
+			Column("votes", "voters_count", "updated_at").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+}
+
+func (p *pollDB) DeletePoll(ctx context.Context, id string) db.Error {
+	return p.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		if _, err := tx.
+			NewDelete().
+			Model((*gtsmodel.PollVote)(nil)).
+			Where("poll_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		_, err := tx.
+			NewDelete().
+			Model((*gtsmodel.Poll)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -78,6 +79,159 @@ func (r *relationshipDB) GetBlock(ctx context.Context, account1 string, account2
 	return block, nil
 }
 
+func (r *relationshipDB) GetBlockedAccountIDs(ctx context.Context, accountID string, accountIDs []string) ([]string, db.Error) {
+	blockedIDs := []string{}
+	if len(accountIDs) == 0 {
+		return blockedIDs, nil
+	}
+
+	q := r.conn.
+		NewSelect().
+		Model(&gtsmodel.Block{}).
+		ColumnExpr("CASE WHEN ? = ? THEN ? ELSE ? END", bun.Ident("block.account_id"), accountID, bun.Ident("block.target_account_id"), bun.Ident("block.account_id")).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.
+						Where("block.account_id = ?", accountID).
+						Where("block.target_account_id IN (?)", bun.In(accountIDs))
+				}).
+				WhereGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.
+						Where("block.target_account_id = ?", accountID).
+						Where("block.account_id IN (?)", bun.In(accountIDs))
+				})
+		})
+
+	if err := q.Scan(ctx, &blockedIDs); err != nil && err != sql.ErrNoRows {
+		return nil, r.conn.ProcessError(err)
+	}
+	return blockedIDs, nil
+}
+
+// newMuteQ returns a select query for unexpired mutes, ie., mutes that don't expire, or that expire in the future.
+func (r *relationshipDB) newMuteQ(mute interface{}) *bun.SelectQuery {
+	return r.conn.
+		NewSelect().
+		Model(mute).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("account_mute.expires_at IS NULL").
+				WhereOr("account_mute.expires_at > ?", time.Now())
+		})
+}
+
+func (r *relationshipDB) IsMuted(ctx context.Context, account1 string, account2 string, notifications bool) (bool, db.Error) {
+	q := r.newMuteQ(&gtsmodel.AccountMute{}).
+		Where("account_mute.account_id = ?", account1).
+		Where("account_mute.target_account_id = ?", account2).
+		Limit(1)
+
+	if notifications {
+		q = q.Where("account_mute.notifications = ?", true)
+	}
+
+	return r.conn.Exists(ctx, q)
+}
+
+func (r *relationshipDB) GetMute(ctx context.Context, account1 string, account2 string) (*gtsmodel.AccountMute, db.Error) {
+	mute := &gtsmodel.AccountMute{}
+
+	q := r.newMuteQ(mute).
+		Relation("Account").
+		Relation("TargetAccount").
+		Where("account_mute.account_id = ?", account1).
+		Where("account_mute.target_account_id = ?", account2)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, r.conn.ProcessError(err)
+	}
+	return mute, nil
+}
+
+func (r *relationshipDB) GetAccountMutes(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.AccountMute, db.Error) {
+	mutes := []*gtsmodel.AccountMute{}
+
+	q := r.newMuteQ(&mutes).
+		Relation("TargetAccount").
+		Where("account_mute.account_id = ?", accountID).
+		Order("account_mute.id DESC")
+
+	if maxID != "" {
+		q = q.Where("account_mute.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("account_mute.id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil && err != sql.ErrNoRows {
+		return nil, r.conn.ProcessError(err)
+	}
+	return mutes, nil
+}
+
+func (r *relationshipDB) DeleteExpiredMutes(ctx context.Context, before time.Time) db.Error {
+	_, err := r.conn.
+		NewDelete().
+		Model(&gtsmodel.AccountMute{}).
+		Where("expires_at IS NOT NULL").
+		Where("expires_at <= ?", before).
+		Exec(ctx)
+	return r.conn.ProcessError(err)
+}
+
+func (r *relationshipDB) GetFollowedTags(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.FollowedTag, db.Error) {
+	followedTags := []*gtsmodel.FollowedTag{}
+
+	q := r.conn.
+		NewSelect().
+		Model(&followedTags).
+		Relation("Tag").
+		Where("followed_tag.account_id = ?", accountID).
+		Order("followed_tag.id DESC")
+
+	if maxID != "" {
+		q = q.Where("followed_tag.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("followed_tag.id > ?", sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil && err != sql.ErrNoRows {
+		return nil, r.conn.ProcessError(err)
+	}
+	return followedTags, nil
+}
+
+func (r *relationshipDB) GetTagFollowerIDs(ctx context.Context, tagIDs []string) ([]string, db.Error) {
+	accountIDs := []string{}
+	if len(tagIDs) == 0 {
+		return accountIDs, nil
+	}
+
+	q := r.conn.
+		NewSelect().
+		Model(&gtsmodel.FollowedTag{}).
+		Distinct().
+		Column("followed_tag.account_id").
+		Where("followed_tag.tag_id IN (?)", bun.In(tagIDs))
+
+	if err := q.Scan(ctx, &accountIDs); err != nil && err != sql.ErrNoRows {
+		return nil, r.conn.ProcessError(err)
+	}
+	return accountIDs, nil
+}
+
 func (r *relationshipDB) GetRelationship(ctx context.Context, requestingAccount string, targetAccount string) (*gtsmodel.Relationship, db.Error) {
 	rel := &gtsmodel.Relationship{
 		ID: targetAccount,
@@ -158,6 +312,35 @@ func (r *relationshipDB) GetRelationship(ctx context.Context, requestingAccount
 	}
 	rel.Requested = count > 0
 
+	// check if the requesting account features the target account on its profile
+	count, err = r.conn.
+		NewSelect().
+		Model(&gtsmodel.Endorsement{}).
+		Where("account_id = ?", requestingAccount).
+		Where("target_account_id = ?", targetAccount).
+		Limit(1).
+		Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getrelationship: error checking endorsed existence: %s", err)
+	}
+	rel.Endorsed = count > 0
+
+	// check if the requesting account mutes the target account
+	mute := &gtsmodel.AccountMute{}
+	if err := r.newMuteQ(mute).
+		Where("account_mute.account_id = ?", requestingAccount).
+		Where("account_mute.target_account_id = ?", targetAccount).
+		Limit(1).
+		Scan(ctx); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("getrelationship: error checking mute existence: %s", err)
+		}
+	} else {
+		rel.Muting = true
+		rel.MutingNotifications = mute.Notifications
+		rel.MuteExpiresAt = mute.ExpiresAt
+	}
+
 	return rel, nil
 }
 
@@ -339,3 +522,37 @@ func (r *relationshipDB) CountAccountFollowedBy(ctx context.Context, accountID s
 		Where("target_account_id = ?", accountID).
 		Count(ctx)
 }
+
+func (r *relationshipDB) GetAccountFollowsPage(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Follow, db.Error) {
+	return r.getFollowsPage(ctx, "follow.account_id", accountID, maxID, minID, limit)
+}
+
+func (r *relationshipDB) GetAccountFollowedByPage(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Follow, db.Error) {
+	return r.getFollowsPage(ctx, "follow.target_account_id", accountID, maxID, minID, limit)
+}
+
+// getFollowsPage returns a page of follows where the given column is equal to accountID, newest first.
+func (r *relationshipDB) getFollowsPage(ctx context.Context, column string, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Follow, db.Error) {
+	follows := []*gtsmodel.Follow{}
+
+	q := r.newFollowQ(&follows).
+		Where("? = ?", bun.Ident(column), accountID).
+		Order("follow.id DESC")
+
+	if maxID != "" {
+		q = q.Where("follow.id < ?", maxID)
+	}
+
+	if minID != "" {
+		q = q.Where("follow.id > ?", minID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil && err != sql.ErrNoRows {
+		return nil, r.conn.ProcessError(err)
+	}
+	return follows, nil
+}
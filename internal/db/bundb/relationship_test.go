@@ -39,6 +39,28 @@ func (suite *RelationshipTestSuite) TestGetBlock() {
 	suite.Suite.T().Skip("TODO: implement")
 }
 
+func (suite *RelationshipTestSuite) TestGetBlockedAccountIDs() {
+	ctx := context.Background()
+	accountIDs := []string{
+		suite.testAccounts["local_account_1"].ID,
+		suite.testAccounts["local_account_2"].ID,
+		suite.testAccounts["remote_account_1"].ID,
+	}
+
+	// local_account_2 blocks remote_account_1, which should be picked up from either side
+	blockedIDs, err := suite.db.GetBlockedAccountIDs(ctx, suite.testAccounts["local_account_2"].ID, accountIDs)
+	suite.NoError(err)
+	suite.Equal([]string{suite.testAccounts["remote_account_1"].ID}, blockedIDs)
+
+	blockedIDs, err = suite.db.GetBlockedAccountIDs(ctx, suite.testAccounts["remote_account_1"].ID, accountIDs)
+	suite.NoError(err)
+	suite.Equal([]string{suite.testAccounts["local_account_2"].ID}, blockedIDs)
+
+	blockedIDs, err = suite.db.GetBlockedAccountIDs(ctx, suite.testAccounts["local_account_1"].ID, accountIDs)
+	suite.NoError(err)
+	suite.Empty(blockedIDs)
+}
+
 func (suite *RelationshipTestSuite) TestGetRelationship() {
 	suite.Suite.T().Skip("TODO: implement")
 }
@@ -0,0 +1,87 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+)
+
+type reportDB struct {
+	conn *DBConn
+}
+
+func (r *reportDB) GetReportByID(ctx context.Context, id string) (*gtsmodel.Report, db.Error) {
+	report := &gtsmodel.Report{}
+
+	q := r.conn.
+		NewSelect().
+		Model(report).
+		Where("? = ?", bun.Ident("report.id"), id)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, r.conn.ProcessError(err)
+	}
+	return report, nil
+}
+
+func (r *reportDB) GetReports(ctx context.Context, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Report, db.Error) {
+	reports := []*gtsmodel.Report{}
+
+	q := r.conn.
+		NewSelect().
+		Model(&reports).
+		Order("report.id DESC")
+
+	if resolved != nil {
+		if *resolved {
+			q = q.Where("? IS NOT NULL", bun.Ident("report.action_taken_at"))
+		} else {
+			q = q.Where("? IS NULL", bun.Ident("report.action_taken_at"))
+		}
+	}
+
+	if accountID != "" {
+		q = q.Where("? = ?", bun.Ident("report.account_id"), accountID)
+	}
+
+	if targetAccountID != "" {
+		q = q.Where("? = ?", bun.Ident("report.target_account_id"), targetAccountID)
+	}
+
+	if maxID != "" {
+		q = q.Where("? < ?", bun.Ident("report.id"), maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("? > ?", bun.Ident("report.id"), sinceID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, r.conn.ProcessError(err)
+	}
+	return reports, nil
+}
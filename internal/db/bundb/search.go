@@ -0,0 +1,125 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/db/bundb/migrations"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// statusSearchVector is the postgres text search vector of a status, which is indexed by statuses_search_idx.
+const statusSearchVector = "to_tsvector('simple', coalesce(status.content_warning, '') || ' ' || coalesce(status.content_text, ''))"
+
+type searchDB struct {
+	conn *DBConn
+}
+
+func (s *searchDB) SearchStatuses(ctx context.Context, accountID string, query string, fromAccountID string, maxID string, minID string, limit int, offset int) ([]*gtsmodel.Status, db.Error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return []*gtsmodel.Status{}, nil
+	}
+
+	// Ensure reasonable
+	if limit < 0 {
+		limit = 0
+	}
+
+	statuses := make([]*gtsmodel.Status, 0, limit)
+
+	q := s.conn.
+		NewSelect().
+		Model(&statuses).
+		Order("status.id DESC")
+
+	switch s.conn.Dialect().Name() {
+	case dialect.PG:
+		q = q.Where(statusSearchVector+" @@ plainto_tsquery('simple', ?)", strings.Join(terms, " "))
+	case dialect.SQLite:
+		q = q.Where("status.id IN (SELECT status_id FROM status_search WHERE status_search MATCH ?)", sqliteSearchQuery(terms))
+	}
+
+	// only search statuses that the account has posted or interacted with
+	q = q.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.
+			WhereOr("status.account_id = ?", accountID).
+			WhereOr("status.id IN (?)", s.conn.NewSelect().Table("status_faves").Column("status_id").Where("account_id = ?", accountID)).
+			WhereOr("status.id IN (?)", s.conn.NewSelect().Table("status_bookmarks").Column("status_id").Where("account_id = ?", accountID)).
+			WhereOr("status.id IN (?)", s.conn.NewSelect().Table("mentions").Column("status_id").Where("target_account_id = ?", accountID))
+	})
+
+	if fromAccountID != "" {
+		q = q.Where("status.account_id = ?", fromAccountID)
+	}
+
+	if maxID != "" {
+		q = q.Where("status.id < ?", maxID)
+	}
+
+	if minID != "" {
+		q = q.Where("status.id > ?", minID)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, s.conn.ProcessError(err)
+	}
+	return statuses, nil
+}
+
+// sqliteSearchQuery quotes each of the given terms so that fts5 treats them as
+// plain strings rather than query syntax, and returns a query matching all of them.
+func sqliteSearchQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		quoted = append(quoted, "\""+strings.ReplaceAll(t, "\"", "\"\"")+"\"")
+	}
+	return strings.Join(quoted, " ")
+}
+
+func (s *searchDB) CreateStatusSearchIndex(ctx context.Context) db.Error {
+	for _, stmt := range migrations.StatusSearchIndexCreate(s.conn.Dialect()) {
+		if _, err := s.conn.ExecContext(ctx, stmt); err != nil {
+			return s.conn.ProcessError(err)
+		}
+	}
+	return nil
+}
+
+func (s *searchDB) DropStatusSearchIndex(ctx context.Context) db.Error {
+	for _, stmt := range migrations.StatusSearchIndexDrop(s.conn.Dialect()) {
+		if _, err := s.conn.ExecContext(ctx, stmt); err != nil {
+			return s.conn.ProcessError(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type SearchTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesOwnAndFaved() {
+	account := suite.testAccounts["local_account_1"]
+
+	statuses, err := suite.db.SearchStatuses(context.Background(), account.ID, "hello", "", "", "", 20, 0)
+	suite.NoError(err)
+
+	// own status, then an older status by the admin that zork faved
+	suite.Len(statuses, 2)
+	suite.Equal(suite.testStatuses["local_account_1_status_1"].ID, statuses[0].ID)
+	suite.Equal(suite.testStatuses["admin_account_status_1"].ID, statuses[1].ID)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesMentioned() {
+	account := suite.testAccounts["local_account_1"]
+
+	statuses, err := suite.db.SearchStatuses(context.Background(), account.ID, "ZORK shhhhhh", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Len(statuses, 1)
+	suite.Equal(suite.testStatuses["local_account_2_status_6"].ID, statuses[0].ID)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesFromAccount() {
+	account := suite.testAccounts["local_account_1"]
+
+	statuses, err := suite.db.SearchStatuses(context.Background(), account.ID, "hi", suite.testAccounts["local_account_2"].ID, "", "", 20, 0)
+	suite.NoError(err)
+	suite.Len(statuses, 2)
+	for _, s := range statuses {
+		suite.Equal(suite.testAccounts["local_account_2"].ID, s.AccountID)
+	}
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesNotInteracted() {
+	account := suite.testAccounts["local_account_1"]
+
+	// turtles are only mentioned in a status by local_account_2 that zork hasn't interacted with
+	statuses, err := suite.db.SearchStatuses(context.Background(), account.ID, "turtles", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Empty(statuses)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesQuerySyntax() {
+	account := suite.testAccounts["local_account_1"]
+
+	// search syntax in the query should be treated as plain text
+	statuses, err := suite.db.SearchStatuses(context.Background(), account.ID, "hello\" OR \"gif", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Empty(statuses)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesUpdatedAndDeleted() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	status := &gtsmodel.Status{}
+	*status = *suite.testStatuses["local_account_1_status_4"]
+	status.Content = "here's a capybara"
+	suite.NoError(suite.db.UpdateStatus(ctx, status))
+
+	statuses, err := suite.db.SearchStatuses(ctx, account.ID, "capybara", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Len(statuses, 1)
+
+	statuses, err = suite.db.SearchStatuses(ctx, account.ID, "little", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Empty(statuses)
+
+	suite.NoError(suite.db.DeleteByID(ctx, status.ID, &gtsmodel.Status{}))
+
+	statuses, err = suite.db.SearchStatuses(ctx, account.ID, "capybara", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Empty(statuses)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusesIgnoresHTML() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	status := &gtsmodel.Status{}
+	*status = *suite.testStatuses["local_account_1_status_4"]
+	status.Content = `<p>look at this<br/><a href="https://example.org/capybara" class="u-url">capybara</a> &amp; friends</p>`
+	suite.NoError(suite.db.UpdateStatus(ctx, status))
+
+	// the words in the status are found, even the ones either side of a tag
+	statuses, err := suite.db.SearchStatuses(ctx, account.ID, "this capybara friends", "", "", "", 20, 0)
+	suite.NoError(err)
+	suite.Len(statuses, 1)
+
+	// but tags, attributes and entities aren't
+	for _, query := range []string{"href", "example.org", "u-url", "amp", "br"} {
+		statuses, err = suite.db.SearchStatuses(ctx, account.ID, query, "", "", "", 20, 0)
+		suite.NoError(err)
+		suite.Empty(statuses, query)
+	}
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
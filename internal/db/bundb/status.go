@@ -21,25 +21,40 @@ package bundb
 import (
 	"container/list"
 	"context"
+	"errors"
 	"time"
 
+	"github.com/ReneKroon/ttlcache"
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/cache"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
 	"github.com/uptrace/bun"
 )
 
+// reactionCacheTTL is how long the reactions to a status are kept in memory after they were last used.
+const reactionCacheTTL = 5 * time.Minute
+
 type statusDB struct {
 	conn  *DBConn
 	cache *cache.StatusCache
 
+	// reactions to statuses by status id, since they're looked up for every status that's served
+	reactions *ttlcache.Cache
+
 	// TODO: keep method definitions in same place but instead have receiver
 	//       all point to one single "db" type, so they can all share methods
 	//       and caches where necessary
 	accounts *accountDB
 }
 
+func newReactionCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(reactionCacheTTL)
+	return c
+}
+
 func (s *statusDB) newStatusQ(status interface{}) *bun.SelectQuery {
 	return s.conn.
 		NewSelect().
@@ -168,12 +183,86 @@ func (s *statusDB) PutStatus(ctx context.Context, status *gtsmodel.Status) db.Er
 			}
 		}
 
-		// Finally, insert the status
+		// insert the poll attached to this status, if there is one
+		if status.Poll != nil {
+			status.Poll.StatusID = status.ID
+			status.PollID = status.Poll.ID
+			if _, err := tx.NewInsert().Model(status.Poll).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// Finally, insert the status, along with its plain text for searching
+		status.ContentText = text.SearchableText(status.Content)
 		_, err := tx.NewInsert().Model(status).Exec(ctx)
 		return err
 	})
 }
 
+func (s *statusDB) UpdateStatus(ctx context.Context, status *gtsmodel.Status) db.Error {
+	if err := s.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		// replace the links between this status and any emojis it uses
+		if _, err := tx.
+			NewDelete().
+			Model(&gtsmodel.StatusToEmoji{}).
+			Where("status_id = ?", status.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		for _, i := range status.EmojiIDs {
+			if _, err := tx.NewInsert().Model(&gtsmodel.StatusToEmoji{
+				StatusID: status.ID,
+				EmojiID:  i,
+			}).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// replace the links between this status and any tags it uses
+		if _, err := tx.
+			NewDelete().
+			Model(&gtsmodel.StatusToTag{}).
+			Where("status_id = ?", status.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		for _, i := range status.TagIDs {
+			if _, err := tx.NewInsert().Model(&gtsmodel.StatusToTag{
+				StatusID: status.ID,
+				TagID:    i,
+			}).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// make sure any newly attached media attachments point to this status
+		for _, a := range status.Attachments {
+			a.StatusID = status.ID
+			a.UpdatedAt = time.Now()
+			if _, err := tx.NewUpdate().Model(a).
+				Where("id = ?", a.ID).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// Finally, update the status itself, along with its plain text for searching
+		status.ContentText = text.SearchableText(status.Content)
+		_, err := tx.
+			NewUpdate().
+			Model(status).
+			WherePK().
+			Exec(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// replace the old version of the status in the cache
+	s.cache.Put(status)
+	return nil
+}
+
 func (s *statusDB) GetStatusParents(ctx context.Context, status *gtsmodel.Status, onlyDirect bool) ([]*gtsmodel.Status, db.Error) {
 	parents := []*gtsmodel.Status{}
 	s.statusParent(ctx, status, &parents, onlyDirect)
@@ -319,6 +408,85 @@ func (s *statusDB) GetStatusFaves(ctx context.Context, status *gtsmodel.Status)
 	return faves, nil
 }
 
+func (s *statusDB) GetStatusReactions(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.StatusReaction, db.Error) {
+	if v, ok := s.reactions.Get(status.ID); ok {
+		reactions, ok := v.([]*gtsmodel.StatusReaction)
+		if !ok {
+			panic("reaction cache entry was not a slice of reactions")
+		}
+		return reactions, nil
+	}
+
+	reactions := []*gtsmodel.StatusReaction{}
+
+	q := s.conn.
+		NewSelect().
+		Model(&reactions).
+		Relation("Account").
+		Relation("CustomEmoji").
+		Where("status_reaction.status_id = ?", status.ID).
+		Order("status_reaction.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, s.conn.ProcessError(err)
+	}
+
+	s.reactions.Set(status.ID, reactions)
+	return reactions, nil
+}
+
+func (s *statusDB) PutStatusReaction(ctx context.Context, reaction *gtsmodel.StatusReaction) db.Error {
+	defer s.reactions.Remove(reaction.StatusID)
+	_, err := s.conn.NewInsert().Model(reaction).Exec(ctx)
+	return s.conn.ProcessError(err)
+}
+
+func (s *statusDB) DeleteStatusReactions(ctx context.Context, where []db.Where) db.Error {
+	if len(where) == 0 {
+		return errors.New("no queries provided")
+	}
+
+	// find out which statuses are losing reactions first, so that their cached reactions can be dropped
+	statusIDs := []string{}
+	q := s.conn.
+		NewSelect().
+		Model((*gtsmodel.StatusReaction)(nil)).
+		Column("status_reaction.status_id")
+	selectWhere(q, where)
+
+	if err := q.Scan(ctx, &statusIDs); err != nil {
+		return s.conn.ProcessError(err)
+	}
+	defer func() {
+		for _, statusID := range statusIDs {
+			s.reactions.Remove(statusID)
+		}
+	}()
+
+	dq := s.conn.
+		NewDelete().
+		Model((*gtsmodel.StatusReaction)(nil))
+	deleteWhere(dq, where)
+
+	_, err := dq.Exec(ctx)
+	return s.conn.ProcessError(err)
+}
+
+func (s *statusDB) GetStatusEdits(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.StatusEdit, db.Error) {
+	edits := []*gtsmodel.StatusEdit{}
+
+	q := s.conn.
+		NewSelect().
+		Model(&edits).
+		Where("status_edit.status_id = ?", status.ID).
+		Order("status_edit.id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, s.conn.ProcessError(err)
+	}
+	return edits, nil
+}
+
 func (s *statusDB) GetStatusReblogs(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Status, db.Error) {
 	reblogs := []*gtsmodel.Status{}
 
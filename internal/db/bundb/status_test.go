@@ -25,6 +25,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 type StatusTestSuite struct {
@@ -132,6 +134,37 @@ func (suite *StatusTestSuite) TestGetStatusChildren() {
 	}
 }
 
+func (suite *StatusTestSuite) TestStatusReactionsCached() {
+	ctx := context.Background()
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+
+	reactions, err := suite.db.GetStatusReactions(ctx, targetStatus)
+	suite.NoError(err)
+	suite.Empty(reactions)
+
+	// putting a reaction drops the reactions that were cached for the status
+	reaction := &gtsmodel.StatusReaction{
+		ID:              "01G2CFQ8ZPNBZQHJ3ZGS8FPN3J",
+		AccountID:       suite.testAccounts["admin_account"].ID,
+		TargetAccountID: targetStatus.AccountID,
+		StatusID:        targetStatus.ID,
+		Emoji:           "👍",
+		URI:             "http://localhost:8080/users/admin/liked/01G2CFQ8ZPNBZQHJ3ZGS8FPN3J",
+	}
+	suite.NoError(suite.db.PutStatusReaction(ctx, reaction))
+
+	reactions, err = suite.db.GetStatusReactions(ctx, targetStatus)
+	suite.NoError(err)
+	suite.Len(reactions, 1)
+
+	// and so does deleting it
+	suite.NoError(suite.db.DeleteStatusReactions(ctx, []db.Where{{Key: "account_id", Value: reaction.AccountID}}))
+
+	reactions, err = suite.db.GetStatusReactions(ctx, targetStatus)
+	suite.NoError(err)
+	suite.Empty(reactions)
+}
+
 func TestStatusTestSuite(t *testing.T) {
 	suite.Run(t, new(StatusTestSuite))
 }
@@ -21,7 +21,9 @@ package bundb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"sort"
+	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -45,11 +47,16 @@ func (t *timelineDB) GetHomeTimeline(ctx context.Context, accountID string, maxI
 		NewSelect().
 		Model(&statuses)
 
-	q = q.ColumnExpr("status.*").
-		// Find out who accountID follows.
-		Join("LEFT JOIN follows AS f ON f.target_account_id = status.account_id").
+	q = q.ColumnExpr("status.*")
+
+	if minID != "" && maxID == "" {
+		// page up from minID: sort by lowest ID (oldest) to highest ID (newest),
+		// so that we get the statuses right after minID rather than the newest ones
+		q = q.Order("status.id ASC")
+	} else {
 		// Sort by highest ID (newest) to lowest ID (oldest)
-		Order("status.id DESC")
+		q = q.Order("status.id DESC")
+	}
 
 	if maxID != "" {
 		// return only statuses LOWER (ie., older) than maxID
@@ -76,15 +83,33 @@ func (t *timelineDB) GetHomeTimeline(ctx context.Context, accountID string, maxI
 		q = q.Limit(limit)
 	}
 
+	// Accounts that accountID follows. This is a subquery rather than a join, so
+	// that statuses aren't returned once for each of their author's followers.
+	followedAccountIDs := t.conn.
+		NewSelect().
+		Table("follows").
+		Column("follows.target_account_id").
+		Where("follows.account_id = ?", accountID)
+
+	// Statuses using hashtags that accountID follows.
+	followedTagStatusIDs := t.conn.
+		NewSelect().
+		Table("status_to_tags").
+		Column("status_to_tags.status_id").
+		Join("JOIN followed_tags ON followed_tags.tag_id = status_to_tags.tag_id").
+		Where("followed_tags.account_id = ?", accountID)
+
 	// Use a WhereGroup here to specify that we want EITHER statuses posted by accounts that accountID follows,
-	// OR statuses posted by accountID itself (since a user should be able to see their own statuses).
+	// OR statuses posted by accountID itself (since a user should be able to see their own statuses),
+	// OR statuses using hashtags that accountID follows.
 	//
 	// This is equivalent to something like WHERE ... AND (... OR ...)
 	// See: https://bun.uptrace.dev/guide/queries.html#select
 	whereGroup := func(*bun.SelectQuery) *bun.SelectQuery {
 		return q.
-			WhereOr("f.account_id = ?", accountID).
-			WhereOr("status.account_id = ?", accountID)
+			WhereOr("status.account_id IN (?)", followedAccountIDs).
+			WhereOr("status.account_id = ?", accountID).
+			WhereOr("status.id IN (?)", followedTagStatusIDs)
 	}
 
 	q = q.WhereGroup(" AND ", whereGroup)
@@ -92,10 +117,15 @@ func (t *timelineDB) GetHomeTimeline(ctx context.Context, accountID string, maxI
 	if err := q.Scan(ctx); err != nil {
 		return nil, t.conn.ProcessError(err)
 	}
+
+	if minID != "" && maxID == "" {
+		// put the statuses back in descending order
+		reverseStatuses(statuses)
+	}
 	return statuses, nil
 }
 
-func (t *timelineDB) GetPublicTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, db.Error) {
+func (t *timelineDB) GetPublicTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool, remote bool, onlyMedia bool) ([]*gtsmodel.Status, db.Error) {
 	// Ensure reasonable
 	if limit < 0 {
 		limit = 0
@@ -110,8 +140,93 @@ func (t *timelineDB) GetPublicTimeline(ctx context.Context, accountID string, ma
 		Where("visibility = ?", gtsmodel.VisibilityPublic).
 		WhereGroup(" AND ", whereEmptyOrNull("in_reply_to_id")).
 		WhereGroup(" AND ", whereEmptyOrNull("in_reply_to_uri")).
-		WhereGroup(" AND ", whereEmptyOrNull("boost_of_id")).
-		Order("status.id DESC")
+		WhereGroup(" AND ", whereEmptyOrNull("boost_of_id"))
+
+	if minID != "" && maxID == "" {
+		q = q.Order("status.id ASC")
+	} else {
+		q = q.Order("status.id DESC")
+	}
+
+	if maxID != "" {
+		q = q.Where("status.id < ?", maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("status.id > ?", sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("status.id > ?", minID)
+	}
+
+	if local != remote {
+		// only one of local or remote statuses; asking for both is the same as asking for everything
+		q = q.Where("status.local = ?", local)
+	}
+
+	if onlyMedia {
+		// media_attachments is indexed by status_id, so this is cheaper than checking the attachments array of each status
+		q = q.Where("status.id IN (?)", t.conn.
+			NewSelect().
+			Table("media_attachments").
+			Column("media_attachments.status_id").
+			Where("media_attachments.status_id IS NOT NULL"))
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+
+	if minID != "" && maxID == "" {
+		reverseStatuses(statuses)
+	}
+	return statuses, nil
+}
+
+func (t *timelineDB) GetTagTimeline(ctx context.Context, anyTags []string, allTags []string, noneTags []string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, db.Error) {
+	anyTags = lowerTagNames(anyTags)
+	allTags = lowerTagNames(allTags)
+	noneTags = lowerTagNames(noneTags)
+	if len(anyTags) == 0 {
+		return nil, errors.New("GetTagTimeline: no tags given to match")
+	}
+
+	// Ensure reasonable
+	if limit < 0 {
+		limit = 0
+	}
+
+	// Make educated guess for slice size
+	statuses := make([]*gtsmodel.Status, 0, limit)
+
+	q := t.conn.
+		NewSelect().
+		Model(&statuses).
+		Where("status.visibility = ?", gtsmodel.VisibilityPublic).
+		WhereGroup(" AND ", whereEmptyOrNull("status.boost_of_id")).
+		Where("status.id IN (?)", t.taggedStatusIDs(anyTags))
+
+	if len(allTags) != 0 {
+		// statuses that use every one of allTags are the ones with as many distinct matching tag names as there are tags
+		q = q.Where("status.id IN (?)", t.taggedStatusIDs(allTags).
+			GroupExpr("status_to_tags.status_id").
+			Having("COUNT(DISTINCT LOWER(tags.name)) = ?", len(allTags)))
+	}
+
+	if len(noneTags) != 0 {
+		q = q.Where("status.id NOT IN (?)", t.taggedStatusIDs(noneTags))
+	}
+
+	if minID != "" && maxID == "" {
+		q = q.Order("status.id ASC")
+	} else {
+		q = q.Order("status.id DESC")
+	}
 
 	if maxID != "" {
 		q = q.Where("status.id < ?", maxID)
@@ -136,9 +251,38 @@ func (t *timelineDB) GetPublicTimeline(ctx context.Context, accountID string, ma
 	if err := q.Scan(ctx); err != nil {
 		return nil, t.conn.ProcessError(err)
 	}
+
+	if minID != "" && maxID == "" {
+		reverseStatuses(statuses)
+	}
 	return statuses, nil
 }
 
+// taggedStatusIDs returns a subquery selecting the IDs of statuses that use any of the given lowercase tag names.
+func (t *timelineDB) taggedStatusIDs(tagNames []string) *bun.SelectQuery {
+	return t.conn.
+		NewSelect().
+		Table("status_to_tags").
+		Column("status_to_tags.status_id").
+		Join("JOIN tags ON tags.id = status_to_tags.tag_id").
+		Where("LOWER(tags.name) IN (?)", bun.In(tagNames))
+}
+
+// lowerTagNames lowercases the given tag names and strips any leading hash, dropping empty names and duplicates.
+func lowerTagNames(tagNames []string) []string {
+	lowered := make([]string, 0, len(tagNames))
+	seen := make(map[string]bool, len(tagNames))
+	for _, n := range tagNames {
+		n = strings.ToLower(strings.TrimPrefix(n, "#"))
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		lowered = append(lowered, n)
+	}
+	return lowered
+}
+
 // TODO optimize this query and the logic here, because it's slow as balls -- it takes like a literal second to return with a limit of 20!
 // It might be worth serving it through a timeline instead of raw DB queries, like we do for Home feeds.
 func (t *timelineDB) GetFavedTimeline(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Status, string, string, db.Error) {
@@ -153,8 +297,13 @@ func (t *timelineDB) GetFavedTimeline(ctx context.Context, accountID string, max
 	fq := t.conn.
 		NewSelect().
 		Model(&faves).
-		Where("account_id = ?", accountID).
-		Order("id DESC")
+		Where("account_id = ?", accountID)
+
+	if minID != "" && maxID == "" {
+		fq = fq.Order("id ASC")
+	} else {
+		fq = fq.Order("id DESC")
+	}
 
 	if maxID != "" {
 		fq = fq.Where("id < ?", maxID)
@@ -180,6 +329,13 @@ func (t *timelineDB) GetFavedTimeline(ctx context.Context, accountID string, max
 		return nil, "", "", db.ErrNoEntries
 	}
 
+	if minID != "" && maxID == "" {
+		// put the faves back in descending order
+		for i, j := 0, len(faves)-1; i < j; i, j = i+1, j-1 {
+			faves[i], faves[j] = faves[j], faves[i]
+		}
+	}
+
 	// map[statusID]faveID -- we need this to sort statuses by fave ID rather than status ID
 	statusesFavesMap := make(map[string]string, len(faves))
 	statusIDs := make([]string, 0, len(faves))
@@ -203,14 +359,21 @@ func (t *timelineDB) GetFavedTimeline(ctx context.Context, accountID string, max
 		return nil, "", "", db.ErrNoEntries
 	}
 
-	// arrange statuses by fave ID
+	// arrange statuses by fave ID, most recently faved first
 	sort.Slice(statuses, func(i int, j int) bool {
 		statusI := statuses[i]
 		statusJ := statuses[j]
-		return statusesFavesMap[statusI.ID] < statusesFavesMap[statusJ.ID]
+		return statusesFavesMap[statusI.ID] > statusesFavesMap[statusJ.ID]
 	})
 
 	nextMaxID := faves[len(faves)-1].ID
 	prevMinID := faves[0].ID
 	return statuses, nextMaxID, prevMinID, nil
 }
+
+// reverseStatuses reverses the order of the given statuses in place.
+func reverseStatuses(statuses []*gtsmodel.Status) {
+	for i, j := 0, len(statuses)-1; i < j; i, j = i+1, j-1 {
+		statuses[i], statuses[j] = statuses[j], statuses[i]
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 type TimelineTestSuite struct {
@@ -32,12 +34,186 @@ type TimelineTestSuite struct {
 func (suite *TimelineTestSuite) TestGetPublicTimeline() {
 	viewingAccount := suite.testAccounts["local_account_1"]
 
-	s, err := suite.db.GetPublicTimeline(context.Background(), viewingAccount.ID, "", "", "", 20, false)
+	s, err := suite.db.GetPublicTimeline(context.Background(), viewingAccount.ID, "", "", "", 20, false, false, false)
 	suite.NoError(err)
 
 	suite.Len(s, 6)
 }
 
+func (suite *TimelineTestSuite) TestGetPublicTimelineMinID() {
+	ctx := context.Background()
+	viewingAccount := suite.testAccounts["local_account_1"]
+
+	all, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, false, false, false)
+	suite.NoError(err)
+	suite.Len(all, 6)
+
+	// paging up from the oldest status should give the statuses right after it, newest first
+	s, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", all[5].ID, 2, false, false, false)
+	suite.NoError(err)
+	suite.Len(s, 2)
+	suite.Equal(all[3].ID, s[0].ID)
+	suite.Equal(all[4].ID, s[1].ID)
+
+	// whereas since ID gives the newest statuses
+	s, err = suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", all[5].ID, "", 2, false, false, false)
+	suite.NoError(err)
+	suite.Len(s, 2)
+	suite.Equal(all[0].ID, s[0].ID)
+	suite.Equal(all[1].ID, s[1].ID)
+}
+
+func (suite *TimelineTestSuite) TestGetPublicTimelineFilters() {
+	ctx := context.Background()
+	viewingAccount := suite.testAccounts["local_account_1"]
+
+	// make the remote test status public so that it shows up on the public timeline
+	remoteStatus := suite.testStatuses["remote_account_1_status_1"]
+	suite.NoError(suite.db.UpdateWhere(ctx, []db.Where{{Key: "id", Value: remoteStatus.ID}}, "visibility", gtsmodel.VisibilityPublic, &gtsmodel.Status{}))
+
+	all, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, false, false, false)
+	suite.NoError(err)
+
+	local, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, true, false, false)
+	suite.NoError(err)
+	suite.NotEmpty(local)
+	for _, s := range local {
+		suite.True(s.Local)
+	}
+
+	remote, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, false, true, false)
+	suite.NoError(err)
+	suite.NotEmpty(remote)
+	for _, s := range remote {
+		suite.False(s.Local)
+	}
+	suite.Len(all, len(local)+len(remote))
+
+	// asking for both local and remote statuses is the same as asking for all of them
+	both, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, true, true, false)
+	suite.NoError(err)
+	suite.Equal(all, both)
+
+	onlyMedia, err := suite.db.GetPublicTimeline(ctx, viewingAccount.ID, "", "", "", 20, false, false, true)
+	suite.NoError(err)
+	suite.NotEmpty(onlyMedia)
+	suite.Less(len(onlyMedia), len(all))
+	for _, s := range onlyMedia {
+		suite.NotEmpty(s.AttachmentIDs)
+	}
+}
+
+func (suite *TimelineTestSuite) TestGetHomeTimelineFollowedTag() {
+	ctx := context.Background()
+	viewingAccount := suite.testAccounts["local_account_2"]
+	taggedStatus := suite.testStatuses["admin_account_status_1"]
+
+	containsTaggedStatus := func() bool {
+		s, err := suite.db.GetHomeTimeline(ctx, viewingAccount.ID, "", "", "", 20, false)
+		suite.NoError(err)
+		for _, status := range s {
+			if status.ID == taggedStatus.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	// local_account_2 doesn't follow the admin account
+	suite.False(containsTaggedStatus())
+
+	// but the admin account's status shows up once local_account_2 follows one of its hashtags
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.FollowedTag{
+		ID:        "01G2E5Y8S1HQ3JV8VQ4MQ0T2ZK",
+		AccountID: viewingAccount.ID,
+		TagID:     suite.testTags["welcome"].ID,
+	}))
+	suite.True(containsTaggedStatus())
+}
+
+func (suite *TimelineTestSuite) TestGetHomeTimelineNoDuplicates() {
+	ctx := context.Background()
+	viewingAccount := suite.testAccounts["local_account_1"]
+
+	// the viewing account follows the admin account, and a hashtag that the admin account
+	// uses, and so does someone else, so the admin account's statuses are there for more
+	// than one reason, and followed by more than one account
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.FollowedTag{
+		ID:        "01G2E5Y8S1HQ3JV8VQ4MQ0T2ZK",
+		AccountID: viewingAccount.ID,
+		TagID:     suite.testTags["welcome"].ID,
+	}))
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Follow{
+		ID:              "01G2E6B9DXJ6Y2W6ZC0M6T6K8R",
+		AccountID:       suite.testAccounts["local_account_2"].ID,
+		TargetAccountID: suite.testAccounts["admin_account"].ID,
+		URI:             "http://localhost:8080/users/1happyturtle/follow/01G2E6B9DXJ6Y2W6ZC0M6T6K8R",
+	}))
+
+	s, err := suite.db.GetHomeTimeline(ctx, viewingAccount.ID, "", "", "", 20, false)
+	suite.NoError(err)
+	suite.NotEmpty(s)
+
+	seen := make(map[string]bool)
+	for _, status := range s {
+		suite.False(seen[status.ID], "status %s is in the timeline more than once", status.ID)
+		seen[status.ID] = true
+	}
+}
+
+func (suite *TimelineTestSuite) TestGetTagTimeline() {
+	ctx := context.Background()
+	welcomeTag := suite.testTags["welcome"]
+	hashtagTag := suite.testTags["Hashtag"]
+
+	// admin_account_status_1 already uses #welcome, so tag a few more statuses
+	for _, st := range []*gtsmodel.StatusToTag{
+		{StatusID: suite.testStatuses["admin_account_status_2"].ID, TagID: welcomeTag.ID},
+		{StatusID: suite.testStatuses["admin_account_status_2"].ID, TagID: hashtagTag.ID},
+		{StatusID: suite.testStatuses["local_account_1_status_1"].ID, TagID: hashtagTag.ID},
+		// not public, so this one should never show up
+		{StatusID: suite.testStatuses["local_account_1_status_2"].ID, TagID: hashtagTag.ID},
+	} {
+		suite.NoError(suite.db.Put(ctx, st))
+	}
+
+	statusIDs := func(anyTags []string, allTags []string, noneTags []string) []string {
+		s, err := suite.db.GetTagTimeline(ctx, anyTags, allTags, noneTags, "", "", "", 20, false)
+		suite.NoError(err)
+		ids := []string{}
+		for _, status := range s {
+			ids = append(ids, status.ID)
+		}
+		return ids
+	}
+
+	suite.Equal([]string{
+		suite.testStatuses["admin_account_status_2"].ID,
+		suite.testStatuses["admin_account_status_1"].ID,
+	}, statusIDs([]string{"welcome"}, nil, nil))
+
+	// any tag, matched regardless of case
+	suite.Equal([]string{
+		suite.testStatuses["local_account_1_status_1"].ID,
+		suite.testStatuses["admin_account_status_2"].ID,
+		suite.testStatuses["admin_account_status_1"].ID,
+	}, statusIDs([]string{"#Welcome", "hashtag"}, nil, nil))
+
+	// all tags
+	suite.Equal([]string{
+		suite.testStatuses["admin_account_status_2"].ID,
+	}, statusIDs([]string{"welcome"}, []string{"hashtag", "welcome"}, nil))
+
+	// none of the tags
+	suite.Equal([]string{
+		suite.testStatuses["admin_account_status_1"].ID,
+	}, statusIDs([]string{"welcome"}, nil, []string{"HASHTAG"}))
+
+	// at least one tag has to be given
+	_, err := suite.db.GetTagTimeline(ctx, nil, []string{"welcome"}, nil, "", "", "", 20, false)
+	suite.Error(err)
+}
+
 func TestTimelineTestSuite(t *testing.T) {
 	suite.Run(t, new(TimelineTestSuite))
 }
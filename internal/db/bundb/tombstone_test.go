@@ -0,0 +1,61 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type TombstoneTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *TombstoneTestSuite) TestTombstoneExistsWithURI() {
+	ctx := context.Background()
+	uri := "https://unknown-instance.com/users/brand_new_person/statuses/01FE4NTHKWW7THT67EF10EB839"
+
+	exists, err := suite.db.TombstoneExistsWithURI(ctx, uri)
+	suite.NoError(err)
+	suite.False(exists)
+
+	tombstoneID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Tombstone{
+		ID:     tombstoneID,
+		Domain: "unknown-instance.com",
+		URI:    uri,
+	}))
+
+	exists, err = suite.db.TombstoneExistsWithURI(ctx, uri)
+	suite.NoError(err)
+	suite.True(exists)
+
+	exists, err = suite.db.TombstoneExistsWithURI(ctx, "https://unknown-instance.com/users/brand_new_person")
+	suite.NoError(err)
+	suite.False(exists)
+}
+
+func TestTombstoneTestSuite(t *testing.T) {
+	suite.Run(t, new(TombstoneTestSuite))
+}
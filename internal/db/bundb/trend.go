@@ -0,0 +1,176 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+type trendDB struct {
+	conn *DBConn
+}
+
+func (t *trendDB) GetLinkByURL(ctx context.Context, url string) (*gtsmodel.Link, db.Error) {
+	link := &gtsmodel.Link{}
+
+	q := t.conn.
+		NewSelect().
+		Model(link).
+		Where("link.url = ?", url)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+	return link, nil
+}
+
+func (t *trendDB) PutStatusLinks(ctx context.Context, statusID string, linkIDs []string) db.Error {
+	return t.conn.RunInTx(ctx, func(tx bun.Tx) error {
+		for _, i := range linkIDs {
+			if _, err := tx.NewInsert().Model(&gtsmodel.StatusToLink{
+				StatusID: statusID,
+				LinkID:   i,
+			}).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *trendDB) GetTagUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, db.Error) {
+	uses := []*gtsmodel.TrendUse{}
+
+	q := t.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.StatusToTag{}).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("statuses"), bun.Ident("status"), bun.Ident("status.id"), bun.Ident("status_to_tag.status_id"))
+	q = t.countUsesByDay(q, "status_to_tag.tag_id", "status.account_id", "status.created_at")
+
+	if err := publicOriginalStatusesSince(q, since).Scan(ctx, &uses); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+	return uses, nil
+}
+
+func (t *trendDB) GetLinkUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, db.Error) {
+	uses := []*gtsmodel.TrendUse{}
+
+	q := t.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.StatusToLink{}).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("statuses"), bun.Ident("status"), bun.Ident("status.id"), bun.Ident("status_to_link.status_id"))
+	q = t.countUsesByDay(q, "status_to_link.link_id", "status.account_id", "status.created_at")
+
+	if err := publicOriginalStatusesSince(q, since).Scan(ctx, &uses); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+	return uses, nil
+}
+
+func (t *trendDB) GetStatusUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, db.Error) {
+	faves := []*gtsmodel.TrendUse{}
+
+	q := t.conn.
+		NewSelect().
+		Model(&[]*gtsmodel.StatusFave{}).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("statuses"), bun.Ident("status"), bun.Ident("status.id"), bun.Ident("status_fave.status_id"))
+	q = t.countUsesByDay(q, "status_fave.status_id", "status_fave.account_id", "status_fave.created_at")
+
+	if err := publicOriginalStatusesSince(q, since).Scan(ctx, &faves); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+
+	boosts := []*gtsmodel.TrendUse{}
+
+	q = t.conn.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("statuses"), bun.Ident("boost")).
+		Join("JOIN ? AS ? ON ? = ?", bun.Ident("statuses"), bun.Ident("status"), bun.Ident("status.id"), bun.Ident("boost.boost_of_id"))
+	q = t.countUsesByDay(q, "boost.boost_of_id", "boost.account_id", "boost.created_at")
+
+	if err := publicOriginalStatusesSince(q, since).Scan(ctx, &boosts); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+
+	return append(faves, boosts...), nil
+}
+
+func (t *trendDB) GetTrendReviews(ctx context.Context, targetType gtsmodel.TrendType) ([]*gtsmodel.TrendReview, db.Error) {
+	reviews := []*gtsmodel.TrendReview{}
+
+	q := t.conn.
+		NewSelect().
+		Model(&reviews).
+		Where("trend_review.target_type = ?", targetType)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+	return reviews, nil
+}
+
+func (t *trendDB) GetTrendReview(ctx context.Context, targetType gtsmodel.TrendType, targetID string) (*gtsmodel.TrendReview, db.Error) {
+	review := &gtsmodel.TrendReview{}
+
+	q := t.conn.
+		NewSelect().
+		Model(review).
+		Where("trend_review.target_type = ?", targetType).
+		Where("trend_review.target_id = ?", targetID)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, t.conn.ProcessError(err)
+	}
+	return review, nil
+}
+
+// publicOriginalStatusesSince narrows the given query, which must join the statuses table as status,
+// down to public statuses that aren't boosts, and were created since the given time.
+func publicOriginalStatusesSince(q *bun.SelectQuery, since time.Time) *bun.SelectQuery {
+	return q.
+		Where("? = ?", bun.Ident("status.visibility"), gtsmodel.VisibilityPublic).
+		Where("? IS NULL", bun.Ident("status.boost_of_id")).
+		Where("? >= ?", bun.Ident("status.created_at"), since)
+}
+
+// countUsesByDay makes the given query select the target and account columns as target_id and account_id,
+// grouped by the UTC day of the createdAt column, with the number of rows in each group as uses.
+func (t *trendDB) countUsesByDay(q *bun.SelectQuery, target string, account string, createdAt string) *bun.SelectQuery {
+	var day string
+	switch t.conn.Dialect().Name() {
+	case dialect.PG:
+		day = "to_char(? AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	case dialect.SQLite:
+		day = "strftime('%Y-%m-%d', ?)"
+	}
+
+	return q.
+		ColumnExpr("? AS ?", bun.Ident(target), bun.Ident("target_id")).
+		ColumnExpr("? AS ?", bun.Ident(account), bun.Ident("account_id")).
+		ColumnExpr(day+" AS ?", bun.Ident(createdAt), bun.Ident("day")).
+		ColumnExpr("COUNT(*) AS ?", bun.Ident("uses")).
+		GroupExpr("?, ?, "+day, bun.Ident(target), bun.Ident(account), bun.Ident(createdAt))
+}
@@ -19,7 +19,13 @@
 package bundb
 
 import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/oklog/ulid"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/uptrace/bun"
 )
 
@@ -112,3 +118,71 @@ func parseWhere(w db.Where) (query string, args []interface{}) {
 	args = []interface{}{bun.Safe(w.Key), w.Value}
 	return
 }
+
+// selectRandomSample selects up to limit rows with newQ, starting from a random point in their IDs, and wrapping
+// around to the oldest rows if there aren't enough after that point. Unlike ordering the rows by random(), this only
+// walks the index on idColumn, at the cost of the rows in any one sample being next to each other. If limit is 0, all
+// the rows are selected.
+func selectRandomSample[T any](ctx context.Context, newQ func(dest *[]*T) *bun.SelectQuery, idColumn string, limit int) ([]*T, error) {
+	if limit == 0 {
+		rows := []*T{}
+		if err := newQ(&rows).Scan(ctx); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+
+	// pick a random point to start from, between the oldest row and now
+	var oldestID string
+	if err := newQ(&[]*T{}).
+		ColumnExpr("?", bun.Ident(idColumn)).
+		OrderExpr("? ASC", bun.Ident(idColumn)).
+		Limit(1).
+		Scan(ctx, &oldestID); err != nil {
+		return nil, err
+	}
+
+	startID, err := randomIDSince(oldestID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*T{}
+	if err := newQ(&rows).
+		Where("? >= ?", bun.Ident(idColumn), startID).
+		OrderExpr("? ASC", bun.Ident(idColumn)).
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(rows) < limit {
+		wrapped := []*T{}
+		if err := newQ(&wrapped).
+			Where("? < ?", bun.Ident(idColumn), startID).
+			OrderExpr("? ASC", bun.Ident(idColumn)).
+			Limit(limit - len(rows)).
+			Scan(ctx); err != nil {
+			return nil, err
+		}
+		rows = append(rows, wrapped...)
+	}
+
+	return rows, nil
+}
+
+// randomIDSince returns an ID for a random moment between when the given ID was created and now.
+func randomIDSince(sinceID string) (string, error) {
+	since, err := ulid.Parse(sinceID)
+	if err != nil {
+		return "", err
+	}
+
+	sinceTime := ulid.Time(since.Time())
+	span := time.Since(sinceTime)
+	if span <= 0 {
+		return sinceID, nil
+	}
+
+	return id.NewULIDFromTime(sinceTime.Add(time.Duration(rand.Int63n(int64(span)))))
+}
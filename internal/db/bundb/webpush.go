@@ -0,0 +1,73 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type webPushDB struct {
+	conn *DBConn
+}
+
+func (w *webPushDB) GetWebPushSubscriptionByTokenID(ctx context.Context, tokenID string) (*gtsmodel.WebPushSubscription, db.Error) {
+	subscription := &gtsmodel.WebPushSubscription{}
+
+	q := w.conn.
+		NewSelect().
+		Model(subscription).
+		Where("web_push_subscription.token_id = ?", tokenID)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, w.conn.ProcessError(err)
+	}
+	return subscription, nil
+}
+
+func (w *webPushDB) GetWebPushSubscriptionsByAccountID(ctx context.Context, accountID string) ([]*gtsmodel.WebPushSubscription, db.Error) {
+	subscriptions := []*gtsmodel.WebPushSubscription{}
+
+	q := w.conn.
+		NewSelect().
+		Model(&subscriptions).
+		Where("web_push_subscription.account_id = ?", accountID)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, w.conn.ProcessError(err)
+	}
+	return subscriptions, nil
+}
+
+func (w *webPushDB) GetVAPIDKeyPair(ctx context.Context) (*gtsmodel.VAPIDKeyPair, db.Error) {
+	keyPair := &gtsmodel.VAPIDKeyPair{}
+
+	q := w.conn.
+		NewSelect().
+		Model(keyPair).
+		Order("vapid_key_pair.id ASC").
+		Limit(1)
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, w.conn.ProcessError(err)
+	}
+	return keyPair, nil
+}
@@ -0,0 +1,43 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Conversation contains functions for getting conversations of direct messages, and the statuses in them.
+type Conversation interface {
+	// GetConversationByID gets the conversation with the given ID, with its other accounts populated.
+	GetConversationByID(ctx context.Context, id string) (*gtsmodel.Conversation, Error)
+	// GetConversationByOtherAccountIDs gets the conversation of the account with the given ID with exactly the accounts with the given IDs.
+	GetConversationByOtherAccountIDs(ctx context.Context, accountID string, otherAccountIDs []string) (*gtsmodel.Conversation, Error)
+	// GetConversationsByAccountID gets the conversations of the account with the given ID, with their other accounts populated.
+	//
+	// Conversations are paged by the ID of their last status, and returned with the most recently active conversation first.
+	GetConversationsByAccountID(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.Conversation, Error)
+	// GetConversationsByStatusID gets all the conversations that the status with the given ID is in.
+	GetConversationsByStatusID(ctx context.Context, statusID string) ([]*gtsmodel.Conversation, Error)
+	// GetConversationLatestStatusID gets the ID of the newest status in the conversation with the given ID, or ErrNoEntries if there aren't any.
+	GetConversationLatestStatusID(ctx context.Context, conversationID string) (string, Error)
+	// DeleteConversationByID deletes the conversation with the given ID, along with its record of which statuses are in it.
+	DeleteConversationByID(ctx context.Context, id string) Error
+}
@@ -34,15 +34,29 @@ type DB interface {
 	Account
 	Admin
 	Basic
+	Conversation
+	Delivery
 	Domain
+	Export
+	Filter
+	Import
 	Instance
+	IPBlock
+	Marker
 	Media
 	Mention
 	Notification
+	Poll
 	Relationship
+	Report
+	Rule
+	Search
 	Session
 	Status
 	Timeline
+	Tombstone
+	Trend
+	WebPush
 
 	/*
 		USEFUL CONVERSION FUNCTIONS
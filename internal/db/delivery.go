@@ -0,0 +1,47 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Delivery contains functions for getting and counting deliveries of activities that are waiting to be tried again.
+//
+// Deliveries are stored, updated, and deleted with the functions in Basic.
+type Delivery interface {
+	// GetDueDeliveries gets up to limit deliveries that are due to be tried again at the given time, and
+	// haven't been given up on yet. The deliveries that have been due for longest are returned first.
+	//
+	// Deliveries that haven't been tried at all yet are only returned if they were stored before storedBefore,
+	// since deliveries are stored before they're first made, and newer ones might still be waiting to be made.
+	GetDueDeliveries(ctx context.Context, now time.Time, storedBefore time.Time, limit int) ([]*gtsmodel.Delivery, Error)
+	// CountDeliveries returns how many deliveries are still being tried, and how many have been given up on.
+	// Deliveries that haven't failed yet aren't counted.
+	CountDeliveries(ctx context.Context) (pending int, failed int, err Error)
+	// GetRecentDeliveries gets up to limit deliveries that are being tried again or were given up on, most recently tried first.
+	// Deliveries that haven't failed yet aren't returned.
+	// If domain isn't empty, only deliveries to inboxes on that domain are returned.
+	GetRecentDeliveries(ctx context.Context, domain string, limit int) ([]*gtsmodel.Delivery, Error)
+	// DeleteFailedDeliveries deletes deliveries that were given up on before the given time, and returns how many were deleted.
+	DeleteFailedDeliveries(ctx context.Context, before time.Time) (int, Error)
+}
@@ -25,9 +25,27 @@ import (
 
 // Domain contains DB functions related to domains and domain blocks.
 type Domain interface {
-	// IsDomainBlocked checks if an instance-level domain block exists for the given domain string (eg., `example.org`).
+	// IsDomainBlocked checks if an instance-level domain block exists for the given domain string (eg., `example.org`),
+	// or for any domain that it's a subdomain of, if that block includes subdomains.
+	//
+	// Only blocks with suspend severity are counted: a domain that's only silenced isn't blocked.
 	IsDomainBlocked(ctx context.Context, domain string) (bool, Error)
 
+	// IsDomainSilenced checks if an instance-level domain block with silence severity exists for the given domain
+	// string (eg., `example.org`), or for any domain that it's a subdomain of, if that block includes subdomains.
+	IsDomainSilenced(ctx context.Context, domain string) (bool, Error)
+
+	// IsURISilenced checks if an instance-level domain block with silence severity exists for the `host` in the given URI.
+	IsURISilenced(ctx context.Context, uri *url.URL) (bool, Error)
+
+	// IsDomainMediaRejected checks if media from the given domain string (eg., `example.org`) should never be cached,
+	// because an instance-level domain block for it, or for any domain that it's a subdomain of if that block includes
+	// subdomains, either has silence severity or rejects media.
+	IsDomainMediaRejected(ctx context.Context, domain string) (bool, Error)
+
+	// IsURIMediaRejected checks if media from the `host` in the given URI should never be cached, like IsDomainMediaRejected.
+	IsURIMediaRejected(ctx context.Context, uri *url.URL) (bool, Error)
+
 	// AreDomainsBlocked checks if an instance-level domain block exists for any of the given domains strings, and returns true if even one is found.
 	AreDomainsBlocked(ctx context.Context, domains []string) (bool, Error)
 
@@ -36,4 +54,7 @@ type Domain interface {
 
 	// AreURIsBlocked checks if an instance-level domain block exists for any `host` in the given URI slice, and returns true if even one is found.
 	AreURIsBlocked(ctx context.Context, uris []*url.URL) (bool, Error)
+
+	// GetKnownSubdomains returns the domains of all the accounts and instances we know about that are subdomains of the given domain.
+	GetKnownSubdomains(ctx context.Context, domain string) ([]string, Error)
 }
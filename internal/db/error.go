@@ -44,3 +44,16 @@ func (e *ErrAlreadyExists) Error() string {
 func NewErrAlreadyExists(msg string) error {
 	return &ErrAlreadyExists{message: msg}
 }
+
+// ErrEmailDomainBlocked is returned when a caller checks an email address whose domain is blocked from signing up.
+type ErrEmailDomainBlocked struct {
+	message string
+}
+
+func (e *ErrEmailDomainBlocked) Error() string {
+	return e.message
+}
+
+func NewErrEmailDomainBlocked(msg string) error {
+	return &ErrEmailDomainBlocked{message: msg}
+}
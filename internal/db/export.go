@@ -0,0 +1,38 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Export contains functions for getting the data exports of accounts.
+type Export interface {
+	// GetAccountExports gets all the exports of the account with the given ID, newest first.
+	GetAccountExports(ctx context.Context, accountID string) ([]*gtsmodel.Export, Error)
+
+	// GetPendingExports gets all exports, of any account, whose files haven't been generated yet.
+	GetPendingExports(ctx context.Context) ([]*gtsmodel.Export, Error)
+
+	// GetExportsCreatedBefore gets all exports, of any account, that were created before the given time.
+	GetExportsCreatedBefore(ctx context.Context, before time.Time) ([]*gtsmodel.Export, Error)
+}
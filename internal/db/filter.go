@@ -0,0 +1,59 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Filter contains functions for getting and changing filters, and the keywords and statuses that they match.
+//
+// The filters of each account are cached, so filters, keywords, and statuses should only be changed
+// through the functions here rather than the ones in Basic, which would leave the cache out of date.
+type Filter interface {
+	// GetFilterByID gets the filter with the given ID, along with its keywords and statuses.
+	GetFilterByID(ctx context.Context, id string) (*gtsmodel.Filter, Error)
+	// GetFiltersByAccountID gets all filters of the account with the given ID, including expired ones,
+	// along with their keywords and statuses, oldest first.
+	//
+	// The filters may be served from the cache and shared with other callers, so they must not be modified.
+	GetFiltersByAccountID(ctx context.Context, accountID string) ([]*gtsmodel.Filter, Error)
+	// GetFilterKeywordByID gets the filter keyword with the given ID.
+	GetFilterKeywordByID(ctx context.Context, id string) (*gtsmodel.FilterKeyword, Error)
+	// GetFilterStatusByID gets the filter status with the given ID.
+	GetFilterStatusByID(ctx context.Context, id string) (*gtsmodel.FilterStatus, Error)
+	// PutFilter stores the given filter, without its keywords or statuses.
+	PutFilter(ctx context.Context, filter *gtsmodel.Filter) Error
+	// UpdateFilter updates the given filter, without its keywords or statuses.
+	UpdateFilter(ctx context.Context, filter *gtsmodel.Filter) Error
+	// DeleteFilterByID deletes the filter with the given ID, along with its keywords and statuses.
+	DeleteFilterByID(ctx context.Context, id string) Error
+	// PutFilterKeyword stores the given filter keyword.
+	PutFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) Error
+	// UpdateFilterKeyword updates the given filter keyword.
+	UpdateFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) Error
+	// DeleteFilterKeyword deletes the given filter keyword.
+	DeleteFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) Error
+	// PutFilterStatus stores the given filter status.
+	PutFilterStatus(ctx context.Context, status *gtsmodel.FilterStatus) Error
+	// DeleteFilterStatus deletes the given filter status.
+	DeleteFilterStatus(ctx context.Context, status *gtsmodel.FilterStatus) Error
+}
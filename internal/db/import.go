@@ -0,0 +1,44 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Import contains functions for getting and storing the imports of accounts.
+type Import interface {
+	// GetImportByID gets one import by its ID, with its failures populated.
+	GetImportByID(ctx context.Context, id string) (*gtsmodel.Import, Error)
+
+	// GetAccountImports gets all the imports of the account with the given ID, newest first, with their failures populated.
+	GetAccountImports(ctx context.Context, accountID string) ([]*gtsmodel.Import, Error)
+
+	// GetPendingImports gets all imports, of any account, that haven't been worked through yet, oldest first.
+	GetPendingImports(ctx context.Context) ([]*gtsmodel.Import, Error)
+
+	// PutImport stores a new import. Only one import per account can be pending at a time,
+	// so if the account already has a pending import, ErrAlreadyExists will be returned.
+	PutImport(ctx context.Context, imp *gtsmodel.Import) Error
+
+	// PutImportFailure stores an account that couldn't be imported.
+	PutImportFailure(ctx context.Context, failure *gtsmodel.ImportFailure) Error
+}
@@ -20,6 +20,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
@@ -32,9 +33,26 @@ type Instance interface {
 	// CountInstanceStatuses returns the number of known statuses posted from the given domain.
 	CountInstanceStatuses(ctx context.Context, domain string) (int, Error)
 
+	// CountActiveLocalUsers returns the number of local accounts that have posted a status since the given time.
+	CountActiveLocalUsers(ctx context.Context, since time.Time) (int, Error)
+
 	// CountInstanceDomains returns the number of known instances known that the given domain federates with.
 	CountInstanceDomains(ctx context.Context, domain string) (int, Error)
 
+	// CountLocalUsersCreated returns the number of local users that signed up between since (inclusive) and until (exclusive).
+	CountLocalUsersCreated(ctx context.Context, since time.Time, until time.Time) (int, Error)
+
+	// CountLocalStatusesCreated returns the number of local statuses that were posted between since (inclusive) and until (exclusive).
+	CountLocalStatusesCreated(ctx context.Context, since time.Time, until time.Time) (int, Error)
+
+	// CountLocalUsersSignedIn returns the number of different local users that were issued an access token,
+	// ie., that signed in to an app, between since (inclusive) and until (exclusive).
+	CountLocalUsersSignedIn(ctx context.Context, since time.Time, until time.Time) (int, Error)
+
+	// GetTopRemoteDomains returns up to limit remote domains that the most statuses have been received from since the given time,
+	// along with how many statuses were received from each of them, busiest first.
+	GetTopRemoteDomains(ctx context.Context, since time.Time, limit int) ([]*gtsmodel.DomainTraffic, Error)
+
 	// GetInstanceAccounts returns a slice of accounts from the given instance, arranged by ID.
 	GetInstanceAccounts(ctx context.Context, domain string, maxID string, limit int) ([]*gtsmodel.Account, Error)
 }
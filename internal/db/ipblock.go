@@ -0,0 +1,52 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// IPBlock contains functions for getting and checking blocks on IP addresses.
+//
+// IP blocks are checked on every request, so implementations may keep them in memory;
+// IP blocks should only be changed through these functions, so that they're kept up to date.
+type IPBlock interface {
+	// GetIPBlocks gets all IP blocks, including expired ones that haven't been deleted yet, sorted by IP range.
+	GetIPBlocks(ctx context.Context) ([]*gtsmodel.IPBlock, Error)
+
+	// GetIPBlockSeverity returns the severity of the most severe unexpired IP block covering the given ip,
+	// or an empty string if the ip isn't blocked.
+	GetIPBlockSeverity(ctx context.Context, ip net.IP) (string, Error)
+
+	// PutIPBlock stores a new IP block.
+	PutIPBlock(ctx context.Context, block *gtsmodel.IPBlock) Error
+
+	// UpdateIPBlock updates all values of the given IP block.
+	UpdateIPBlock(ctx context.Context, block *gtsmodel.IPBlock) Error
+
+	// DeleteIPBlockByID deletes the IP block with the given ID.
+	DeleteIPBlockByID(ctx context.Context, id string) Error
+
+	// DeleteExpiredIPBlocks deletes all IP blocks that expired before the given time.
+	DeleteExpiredIPBlocks(ctx context.Context, before time.Time) Error
+}
@@ -35,4 +35,46 @@ type Media interface {
 	// The selected media attachments will be those with both a URL and a RemoteURL filled in.
 	// In other words, media attachments that originated remotely, and that we currently have cached locally.
 	GetRemoteOlderThan(ctx context.Context, olderThan time.Time, limit int) ([]*gtsmodel.MediaAttachment, Error)
+	// GetRemoteUncached gets limit n remote media attachments that aren't currently cached locally.
+	// These will be returned in order of attachment.id descending, starting after maxID if it's set.
+	//
+	// If accountID is set, only attachments belonging to that account will be selected. If domain is set,
+	// only attachments belonging to accounts on that domain will be selected.
+	GetRemoteUncached(ctx context.Context, accountID string, domain string, maxID string, limit int) ([]*gtsmodel.MediaAttachment, Error)
+	// CountRemoteUncached counts the remote media attachments that aren't currently cached locally,
+	// only counting those of the given account and on the given domain if they're set, like GetRemoteUncached.
+	CountRemoteUncached(ctx context.Context, accountID string, domain string) (int, Error)
+	// GetAccountUnattached gets limit n media attachments belonging to the given account that aren't attached to
+	// a status, such as avatars and headers, or uploads that were never used in a status. These will be returned in
+	// order of attachment.id descending, starting after maxID if it's set.
+	GetAccountUnattached(ctx context.Context, accountID string, maxID string, limit int) ([]*gtsmodel.MediaAttachment, Error)
+	// GetRandomCached gets limit n media attachments, picked at random from all the attachments
+	// that currently have files in storage on this instance, both local and remote. If limit is
+	// 0, every such attachment is returned.
+	//
+	// Attachments are sampled from a random point in their IDs, so the attachments returned
+	// by one call were created around the same time, but repeated calls cover every attachment.
+	GetRandomCached(ctx context.Context, limit int) ([]*gtsmodel.MediaAttachment, Error)
+	// GetRandomEmojis gets limit n emojis, both local and remote, picked at random in the same way as GetRandomCached.
+	// If limit is 0, every emoji is returned.
+	GetRandomEmojis(ctx context.Context, limit int) ([]*gtsmodel.Emoji, Error)
+	// GetPopularUncached gets limit n remote media attachments that aren't currently cached locally,
+	// and that have been accessed at least once. These will be returned in order of access count
+	// descending, so that the most popular attachments come first.
+	GetPopularUncached(ctx context.Context, limit int) ([]*gtsmodel.MediaAttachment, Error)
+	// IncrementAccessCounts adds to the number of times attachments have been accessed,
+	// where counts maps the id of each attachment to how many more times it's been accessed.
+	IncrementAccessCounts(ctx context.Context, counts map[string]int) Error
+	// GetAccountMediaSize returns the total size in bytes of all media attachments currently
+	// stored on this instance for the given accountID, including both full size files and thumbnails.
+	//
+	// Attachments that are not currently cached are not counted.
+	GetAccountMediaSize(ctx context.Context, accountID string) (int, Error)
+	// GetMediaSize returns the total size in bytes of all local media attachments currently stored on
+	// this instance if remote is false, or of all cached remote media attachments if remote is true,
+	// including both full size files and thumbnails.
+	GetMediaSize(ctx context.Context, remote bool) (int, Error)
+	// GetEmojisByIDs returns the emojis with the given IDs in one go, in the same order as the IDs.
+	// Any that can't be found, such as remote emojis that are still being processed, are left out.
+	GetEmojisByIDs(ctx context.Context, ids []string) ([]*gtsmodel.Emoji, Error)
 }
@@ -24,12 +24,19 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-// Notification contains functions for creating and getting notifications.
+// Notification contains functions for creating, getting, and deleting notifications.
 type Notification interface {
 	// GetNotifications returns a slice of notifications that pertain to the given accountID.
+	// If types is set, only notifications of those types are returned; notifications of any of excludeTypes are never returned.
+	//
+	// If minID is set without maxID, the notifications immediately newer than minID are returned, rather than the newest ones.
 	//
 	// Returned notifications will be ordered ID descending (ie., highest/newest to lowest/oldest).
-	GetNotifications(ctx context.Context, accountID string, limit int, maxID string, sinceID string) ([]*gtsmodel.Notification, Error)
+	GetNotifications(ctx context.Context, accountID string, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string) ([]*gtsmodel.Notification, Error)
 	// GetNotification returns one notification according to its id.
 	GetNotification(ctx context.Context, id string) (*gtsmodel.Notification, Error)
+	// DeleteNotification deletes one notification according to its id.
+	DeleteNotification(ctx context.Context, id string) Error
+	// ClearNotifications deletes all the notifications that pertain to the given accountID.
+	ClearNotifications(ctx context.Context, accountID string) Error
 }
@@ -0,0 +1,48 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Poll contains functions for getting polls, and for storing and counting the votes cast in them.
+//
+// Polls are created along with the status they're attached to, see PutStatus.
+type Poll interface {
+	// GetPollByID gets one poll by its ID.
+	GetPollByID(ctx context.Context, id string) (*gtsmodel.Poll, Error)
+	// GetExpiredPolls gets all the polls that expired before the given time, but haven't been closed yet.
+	GetExpiredPolls(ctx context.Context, before time.Time) ([]*gtsmodel.Poll, Error)
+	// GetPollVotes gets all the votes cast in the poll with the given ID, with the voting accounts populated.
+	GetPollVotes(ctx context.Context, pollID string) ([]*gtsmodel.PollVote, Error)
+	// GetAccountPollVotes gets the votes cast by the account with the given ID in the poll with the given ID, ordered by the index of the chosen option.
+	GetAccountPollVotes(ctx context.Context, pollID string, accountID string) ([]*gtsmodel.PollVote, Error)
+	// PutPollVote stores one vote, and updates the vote counts of the poll it was cast in to include it.
+	// This should only be used for local polls, since the vote counts of remote polls come from their own instance.
+	//
+	// If the account has already chosen the same option in the poll, or has already voted at all in a single
+	// choice poll, ErrAlreadyExists will be returned.
+	PutPollVote(ctx context.Context, vote *gtsmodel.PollVote) Error
+	// DeletePoll deletes the poll with the given ID, along with all the votes cast in it.
+	DeletePoll(ctx context.Context, id string) Error
+}
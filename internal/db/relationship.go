@@ -20,6 +20,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
@@ -36,6 +37,31 @@ type Relationship interface {
 	// not if you're just checking for the existence of a block.
 	GetBlock(ctx context.Context, account1 string, account2 string) (*gtsmodel.Block, Error)
 
+	// GetBlockedAccountIDs returns those of the given accountIDs that have a block in place against accountID,
+	// or that accountID has a block in place against.
+	GetBlockedAccountIDs(ctx context.Context, accountID string, accountIDs []string) ([]string, Error)
+
+	// IsMuted checks whether account1 has an unexpired mute in place against account2.
+	// If notifications is true, then only mutes that also cover notifications from account2 are counted.
+	IsMuted(ctx context.Context, account1 string, account2 string, notifications bool) (bool, Error)
+
+	// GetMute returns the unexpired mute from account1 targeting account2, if it exists, or an error if it doesn't.
+	GetMute(ctx context.Context, account1 string, account2 string) (*gtsmodel.AccountMute, Error)
+
+	// GetAccountMutes returns a page of up to limit unexpired mutes owned by the given accountID, newest first,
+	// with their target accounts populated. maxID and sinceID can be set to page through the mutes by mute ID.
+	GetAccountMutes(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.AccountMute, Error)
+
+	// DeleteExpiredMutes deletes all mutes that expired before the given time.
+	DeleteExpiredMutes(ctx context.Context, before time.Time) Error
+
+	// GetFollowedTags returns a page of up to limit hashtags followed by the given accountID, newest first,
+	// with their tags populated. maxID and sinceID can be set to page through the followed tags by followed tag ID.
+	GetFollowedTags(ctx context.Context, accountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.FollowedTag, Error)
+
+	// GetTagFollowerIDs returns the IDs of all accounts that follow at least one of the given tags.
+	GetTagFollowerIDs(ctx context.Context, tagIDs []string) ([]string, Error)
+
 	// GetRelationship retrieves the relationship of the targetAccount to the requestingAccount.
 	GetRelationship(ctx context.Context, requestingAccount string, targetAccount string) (*gtsmodel.Relationship, Error)
 
@@ -77,4 +103,12 @@ type Relationship interface {
 
 	// CountAccountFollowedBy returns the amounts that the given ID is followed by.
 	CountAccountFollowedBy(ctx context.Context, accountID string, localOnly bool) (int, Error)
+
+	// GetAccountFollowsPage returns a page of up to limit follows owned by the given accountID, newest first,
+	// with their target accounts populated. maxID and minID can be set to page through the follows by follow ID.
+	GetAccountFollowsPage(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Follow, Error)
+
+	// GetAccountFollowedByPage returns a page of up to limit follows that target the given accountID, newest first,
+	// with their owning accounts populated. maxID and minID can be set to page through the follows by follow ID.
+	GetAccountFollowedByPage(ctx context.Context, accountID string, maxID string, minID string, limit int) ([]*gtsmodel.Follow, Error)
 }
@@ -0,0 +1,37 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Report contains functions for getting reports made to the moderators of this instance.
+//
+// Reports are stored and updated with the functions in Basic.
+type Report interface {
+	// GetReportByID gets one report by its database ID.
+	GetReportByID(ctx context.Context, id string) (*gtsmodel.Report, Error)
+	// GetReports gets up to limit reports, newest first, paged with maxID and sinceID.
+	// If resolved isn't nil, only reports that have or haven't been resolved are returned.
+	// If accountID or targetAccountID aren't empty, only reports made by or about that account are returned.
+	GetReports(ctx context.Context, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*gtsmodel.Report, Error)
+}
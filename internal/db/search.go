@@ -0,0 +1,42 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Search contains functions for full-text searching of statuses.
+type Search interface {
+	// SearchStatuses returns statuses whose content or content warning contains all the words in the given query,
+	// taken from the statuses that the given account has posted, faved, bookmarked, or been mentioned in.
+	// Statuses are returned newest first.
+	//
+	// If fromAccountID is set, only statuses posted by that account will be returned.
+	SearchStatuses(ctx context.Context, accountID string, query string, fromAccountID string, maxID string, minID string, limit int, offset int) ([]*gtsmodel.Status, Error)
+
+	// CreateStatusSearchIndex creates the full-text index used by SearchStatuses, if it doesn't exist yet.
+	// This is done by migrations, so it's only needed for databases whose tables are created directly, like test databases.
+	CreateStatusSearchIndex(ctx context.Context) Error
+
+	// DropStatusSearchIndex drops the full-text index created by CreateStatusSearchIndex, if it exists.
+	DropStatusSearchIndex(ctx context.Context) Error
+}
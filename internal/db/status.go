@@ -35,9 +35,13 @@ type Status interface {
 	// GetStatusByURL returns one status from the database, with no rel fields populated, only their linking ID / URIs
 	GetStatusByURL(ctx context.Context, uri string) (*gtsmodel.Status, Error)
 
-	// PutStatus stores one status in the database.
+	// PutStatus stores one status in the database, along with the poll attached to it if there is one.
 	PutStatus(ctx context.Context, status *gtsmodel.Status) Error
 
+	// UpdateStatus updates one status in the database, along with the emojis, tags, and media attachments linked to it.
+	// The poll attached to the status, if there is one, is left as it is.
+	UpdateStatus(ctx context.Context, status *gtsmodel.Status) Error
+
 	// CountStatusReplies returns the amount of replies recorded for a status, or an error if something goes wrong
 	CountStatusReplies(ctx context.Context, status *gtsmodel.Status) (int, Error)
 
@@ -73,6 +77,20 @@ type Status interface {
 	// This slice will be unfiltered, not taking account of blocks and whatnot, so filter it before serving it back to a user.
 	GetStatusFaves(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.StatusFave, Error)
 
+	// GetStatusReactions returns a slice of emoji reactions to the given status, oldest first.
+	// This slice will be unfiltered, not taking account of blocks and whatnot, so filter it before serving it back to a user.
+	GetStatusReactions(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.StatusReaction, Error)
+
+	// PutStatusReaction stores the given emoji reaction to a status.
+	PutStatusReaction(ctx context.Context, reaction *gtsmodel.StatusReaction) Error
+
+	// DeleteStatusReactions deletes all emoji reactions matching the given where clauses.
+	// Reactions should always be deleted through here, so that cached reactions are dropped with them.
+	DeleteStatusReactions(ctx context.Context, where []Where) Error
+
+	// GetStatusEdits returns a slice of the previous versions of the given status, oldest first.
+	GetStatusEdits(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.StatusEdit, Error)
+
 	// GetStatusReblogs returns a slice of statuses that are a boost/reblog of the given status.
 	// This slice will be unfiltered, not taking account of blocks and whatnot, so filter it before serving it back to a user.
 	GetStatusReblogs(ctx context.Context, status *gtsmodel.Status) ([]*gtsmodel.Status, Error)
@@ -28,14 +28,29 @@ import (
 type Timeline interface {
 	// GetHomeTimeline returns a slice of statuses from accounts that are followed by the given account id.
 	//
+	// If minID is set without maxID, the statuses immediately newer than minID are returned, rather than the newest ones.
+	//
 	// Statuses should be returned in descending order of when they were created (newest first).
 	GetHomeTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, Error)
 
 	// GetPublicTimeline fetches the account's PUBLIC timeline -- ie., posts and replies that are public.
 	// It will use the given filters and try to return as many statuses as possible up to the limit.
 	//
+	// If local is set, only statuses from local accounts are returned, and if remote is set, only statuses from remote accounts;
+	// setting both is the same as setting neither. If onlyMedia is set, only statuses with media attachments are returned.
+	//
+	// If minID is set without maxID, the statuses immediately newer than minID are returned, rather than the newest ones.
+	//
+	// Statuses should be returned in descending order of when they were created (newest first).
+	GetPublicTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool, remote bool, onlyMedia bool) ([]*gtsmodel.Status, Error)
+
+	// GetTagTimeline fetches public statuses that use hashtags, matched by name regardless of case.
+	// Statuses must use at least one of anyTags, all of allTags, and none of noneTags; anyTags must not be empty.
+	//
+	// If minID is set without maxID, the statuses immediately newer than minID are returned, rather than the newest ones.
+	//
 	// Statuses should be returned in descending order of when they were created (newest first).
-	GetPublicTimeline(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, Error)
+	GetTagTimeline(ctx context.Context, anyTags []string, allTags []string, noneTags []string, maxID string, sinceID string, minID string, limit int, local bool) ([]*gtsmodel.Status, Error)
 
 	// GetFavedTimeline fetches the account's FAVED timeline -- ie., posts and replies that the requesting account has faved.
 	// It will use the given filters and try to return as many statuses as possible up to the limit.
@@ -0,0 +1,31 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+)
+
+// Tombstone contains functions for checking whether activitypub objects and actors have been deleted.
+//
+// Tombstones are stored and deleted with the functions in Basic.
+type Tombstone interface {
+	// TombstoneExistsWithURI returns true if there's a tombstone for the given activitypub URI.
+	TombstoneExistsWithURI(ctx context.Context, uri string) (bool, Error)
+}
@@ -0,0 +1,50 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Trend contains functions for counting what's trending on this instance, and for getting admin reviews of trends.
+type Trend interface {
+	// GetLinkByURL gets the link with the given url.
+	GetLinkByURL(ctx context.Context, url string) (*gtsmodel.Link, Error)
+
+	// PutStatusLinks records that the content of the status with the given ID links to the links with the given IDs.
+	PutStatusLinks(ctx context.Context, statusID string, linkIDs []string) Error
+
+	// GetTagUses counts the uses of each tag in public, original statuses created since the given time, per account and day.
+	GetTagUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, Error)
+
+	// GetLinkUses counts the uses of each link in public, original statuses created since the given time, per account and day.
+	GetLinkUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, Error)
+
+	// GetStatusUses counts the faves and boosts of each public status created since the given time, per account and day.
+	GetStatusUses(ctx context.Context, since time.Time) ([]*gtsmodel.TrendUse, Error)
+
+	// GetTrendReviews returns all the admin reviews of trends of the given type.
+	GetTrendReviews(ctx context.Context, targetType gtsmodel.TrendType) ([]*gtsmodel.TrendReview, Error)
+
+	// GetTrendReview gets the admin review of the trend of the given type, with the given target ID.
+	GetTrendReview(ctx context.Context, targetType gtsmodel.TrendType, targetID string) (*gtsmodel.TrendReview, Error)
+}
@@ -0,0 +1,76 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"strings"
+)
+
+// activityPolicy decides which types of activity are refused from which remote domains,
+// as configured by the admin with entries like "Like", or "Announce@example.org".
+type activityPolicy struct {
+	everywhere map[string]bool     // lowercased activity types refused from every domain
+	domains    map[string][]string // lowercased activity types to the lowercased domains they're refused from
+}
+
+// newActivityPolicy parses the given entries into an activityPolicy. Each entry is either an activity type,
+// which is refused from everywhere, or an activity type and a domain separated by "@", which is refused from
+// that domain and its subdomains. Activity types and domains are both matched case-insensitively.
+func newActivityPolicy(entries []string) *activityPolicy {
+	p := &activityPolicy{
+		everywhere: make(map[string]bool),
+		domains:    make(map[string][]string),
+	}
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		typeName, domain, found := strings.Cut(entry, "@")
+		if !found {
+			p.everywhere[typeName] = true
+			continue
+		}
+
+		p.domains[typeName] = append(p.domains[typeName], strings.TrimSuffix(domain, "."))
+	}
+
+	return p
+}
+
+// refuses returns true if the given type of activity is refused from any of the given domains.
+func (p *activityPolicy) refuses(typeName string, domains ...string) bool {
+	typeName = strings.ToLower(typeName)
+	if p.everywhere[typeName] {
+		return true
+	}
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		for _, refused := range p.domains[typeName] {
+			if domain == refused || strings.HasSuffix(domain, "."+refused) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -20,9 +20,10 @@ package federation
 
 import (
 	"context"
-	"crypto/x509"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/json"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/url"
@@ -119,7 +120,8 @@ func getPublicKeyFromResponse(c context.Context, b []byte, keyID *url.URL) (voca
 func (f *federator) AuthenticateFederatedRequest(ctx context.Context, requestedUsername string) (*url.URL, gtserror.WithCode) {
 	l := logrus.WithField("func", "AuthenticateFederatedRequest")
 
-	var publicKey interface{}
+	var publicKey crypto.PublicKey
+	var remoteKey *remotePublicKey // only set if the request is from a remote account
 	var pkOwnerURI *url.URL
 	var err error
 
@@ -165,97 +167,37 @@ func (f *federator) AuthenticateFederatedRequest(ctx context.Context, requestedU
 		return nil, errWithCode
 	}
 
-	requestingRemoteAccount := &gtsmodel.Account{}
-	requestingLocalAccount := &gtsmodel.Account{}
 	requestingHost := requestingPublicKeyID.Host
 	host := viper.GetString(config.Keys.Host)
 	if strings.EqualFold(requestingHost, host) {
 		// LOCAL ACCOUNT REQUEST
 		// the request is coming from INSIDE THE HOUSE so skip the remote dereferencing
 		l.Tracef("proceeding without dereference for local public key %s", requestingPublicKeyID)
+		requestingLocalAccount := &gtsmodel.Account{}
 		if err := f.db.GetWhere(ctx, []db.Where{{Key: "public_key_uri", Value: requestingPublicKeyID.String()}}, requestingLocalAccount); err != nil {
 			errWithCode := gtserror.NewErrorInternalError(fmt.Errorf("couldn't get account with public key uri %s from the database: %s", requestingPublicKeyID.String(), err))
 			l.Debug(errWithCode)
 			return nil, errWithCode
 		}
-		publicKey = requestingLocalAccount.PublicKey
+		publicKey = accountPublicKey(requestingLocalAccount)
 		pkOwnerURI, err = url.Parse(requestingLocalAccount.URI)
 		if err != nil {
 			errWithCode := gtserror.NewErrorBadRequest(err, fmt.Sprintf("couldn't parse public key owner URL %s", requestingLocalAccount.URI))
 			l.Debug(errWithCode)
 			return nil, errWithCode
 		}
-	} else if err := f.db.GetWhere(ctx, []db.Where{{Key: "public_key_uri", Value: requestingPublicKeyID.String()}}, requestingRemoteAccount); err == nil {
-		// REMOTE ACCOUNT REQUEST WITH KEY CACHED LOCALLY
-		// this is a remote account and we already have the public key for it so use that
-		l.Tracef("proceeding without dereference for cached public key %s", requestingPublicKeyID)
-		publicKey = requestingRemoteAccount.PublicKey
-		pkOwnerURI, err = url.Parse(requestingRemoteAccount.URI)
-		if err != nil {
-			errWithCode := gtserror.NewErrorBadRequest(err, fmt.Sprintf("couldn't parse public key owner URL %s", requestingRemoteAccount.URI))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
 	} else {
-		// REMOTE ACCOUNT REQUEST WITHOUT KEY CACHED LOCALLY
-		// the request is remote and we don't have the public key yet,
-		// so we need to authenticate the request properly by dereferencing the remote key
-		l.Tracef("proceeding with dereference for uncached public key %s", requestingPublicKeyID)
-		transport, err := f.transportController.NewTransportForUsername(ctx, requestedUsername)
-		if err != nil {
-			errWithCode := gtserror.NewErrorInternalError(fmt.Errorf("error creating transport for %s: %s", requestedUsername, err))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-
-		// The actual http call to the remote server is made right here in the Dereference function.
-		b, err := transport.Dereference(ctx, requestingPublicKeyID)
-		if err != nil {
-			errWithCode := gtserror.NewErrorNotAuthorized(fmt.Errorf("error dereferencing public key %s: %s", requestingPublicKeyID, err))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-
-		// if the key isn't in the response, we can't authenticate the request
-		requestingPublicKey, err := getPublicKeyFromResponse(ctx, b, requestingPublicKeyID)
-		if err != nil {
-			errWithCode := gtserror.NewErrorNotAuthorized(fmt.Errorf("error parsing public key %s: %s", requestingPublicKeyID, err))
+		// REMOTE ACCOUNT REQUEST
+		// use the cached key if we have it, or fetch it from the database or the remote server if we don't
+		l.Tracef("getting remote public key %s", requestingPublicKeyID)
+		var errWithCode gtserror.WithCode
+		remoteKey, errWithCode = f.getRemotePublicKey(ctx, requestingPublicKeyID)
+		if errWithCode != nil {
 			l.Debug(errWithCode)
 			return nil, errWithCode
 		}
-
-		// we should be able to get the actual key embedded in the vocab.W3IDSecurityV1PublicKey
-		pkPemProp := requestingPublicKey.GetW3IDSecurityV1PublicKeyPem()
-		if pkPemProp == nil || !pkPemProp.IsXMLSchemaString() {
-			errWithCode := gtserror.NewErrorNotAuthorized(errors.New("publicKeyPem property is not provided or it is not embedded as a value"))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-
-		// and decode the PEM so that we can parse it as a golang public key
-		pubKeyPem := pkPemProp.Get()
-		block, _ := pem.Decode([]byte(pubKeyPem))
-		if block == nil || block.Type != "PUBLIC KEY" {
-			errWithCode := gtserror.NewErrorNotAuthorized(errors.New("could not decode publicKeyPem to PUBLIC KEY pem block type"))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-
-		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
-		if err != nil {
-			errWithCode := gtserror.NewErrorNotAuthorized(fmt.Errorf("could not parse public key %s from block bytes: %s", requestingPublicKeyID, err))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-
-		// all good! we just need the URI of the key owner to return
-		pkOwnerProp := requestingPublicKey.GetW3IDSecurityV1Owner()
-		if pkOwnerProp == nil || !pkOwnerProp.IsIRI() {
-			errWithCode := gtserror.NewErrorNotAuthorized(errors.New("publicKeyOwner property is not provided or it is not embedded as a value"))
-			l.Debug(errWithCode)
-			return nil, errWithCode
-		}
-		pkOwnerURI = pkOwnerProp.GetIRI()
+		publicKey = remoteKey.publicKey
+		pkOwnerURI = remoteKey.ownerURI
 	}
 
 	// after all that, public key should be defined
@@ -266,23 +208,71 @@ func (f *federator) AuthenticateFederatedRequest(ctx context.Context, requestedU
 	}
 
 	// do the actual authentication here!
-	algos := []httpsig.Algorithm{
-		httpsig.RSA_SHA256,
-		httpsig.RSA_SHA512,
-		httpsig.ED25519,
+	algorithm := signatureAlgorithm(signature)
+	algo, verified := verifySignature(verifier, publicKey, algorithm)
+
+	if !verified && remoteKey != nil {
+		// the remote account may have rotated its key since we cached it, so give it one more try with a fresh copy
+		if refetched := f.refetchPublicKey(ctx, requestingPublicKeyID, remoteKey); refetched != nil {
+			algo, verified = verifySignature(verifier, refetched.publicKey, algorithm)
+			if verified {
+				l.Debugf("public key %s has changed since it was cached", requestingPublicKeyID)
+				publicKeyRefetches.WithLabelValues(refetchRotated).Inc()
+				f.updateAccountPublicKey(ctx, requestingPublicKeyID, refetched.publicKey)
+				pkOwnerURI = refetched.ownerURI
+			} else {
+				publicKeyRefetches.WithLabelValues(refetchUnchanged).Inc()
+			}
+		}
 	}
 
-	for _, algo := range algos {
-		l.Tracef("trying algo: %s", algo)
-		err := verifier.Verify(publicKey, algo)
-		if err == nil {
-			l.Tracef("authentication for %s PASSED with algorithm %s", pkOwnerURI, algo)
-			return pkOwnerURI, nil
+	if verified {
+		l.Tracef("authentication for %s PASSED with algorithm %s", pkOwnerURI, algo)
+		if remoteKey != nil {
+			// remember how this host names its algorithms, so we can sign requests to it the same way
+			f.transportController.NoteSignatureAlgorithm(requestingHost, algorithm)
 		}
-		l.Tracef("authentication for %s NOT PASSED with algorithm %s: %s", pkOwnerURI, algo, err)
+		return pkOwnerURI, nil
 	}
 
 	errWithCode := gtserror.NewErrorNotAuthorized(fmt.Errorf("authentication not passed for public key owner %s; signature value was '%s'", pkOwnerURI, signature))
 	l.Debug(errWithCode)
 	return nil, errWithCode
 }
+
+// signatureAlgorithm returns the value of the algorithm parameter of the given signature header, or
+// an empty string if it doesn't have one. The value isn't checked, since it's only a hint; the key
+// that made the signature decides which algorithms it can be verified with.
+func signatureAlgorithm(signature string) string {
+	for _, param := range strings.Split(signature, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(key, "algorithm") {
+			continue
+		}
+		return strings.Trim(value, `"`)
+	}
+	return ""
+}
+
+// verifyAlgorithms returns the algorithms that a signature naming the given algorithm, and made
+// with the given key, should be verified with. Signatures that name hs2019, or that don't name an
+// algorithm we recognize, have the algorithms worked out from the type of the key instead.
+func verifyAlgorithms(algorithm string, publicKey crypto.PublicKey) []httpsig.Algorithm {
+	switch strings.ToLower(algorithm) {
+	case string(httpsig.RSA_SHA256):
+		return []httpsig.Algorithm{httpsig.RSA_SHA256}
+	case string(httpsig.RSA_SHA512):
+		return []httpsig.Algorithm{httpsig.RSA_SHA512}
+	case string(httpsig.ED25519):
+		return []httpsig.Algorithm{httpsig.ED25519}
+	}
+
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return []httpsig.Algorithm{httpsig.RSA_SHA256, httpsig.RSA_SHA512}
+	case ed25519.PublicKey:
+		return []httpsig.Algorithm{httpsig.ED25519}
+	default:
+		return nil
+	}
+}
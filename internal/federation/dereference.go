@@ -49,3 +49,7 @@ func (f *federator) GetRemoteInstance(ctx context.Context, username string, remo
 func (f *federator) DereferenceAnnounce(ctx context.Context, announce *gtsmodel.Status, requestingUsername string) error {
 	return f.dereferencer.DereferenceAnnounce(ctx, announce, requestingUsername)
 }
+
+func (f *federator) DereferenceFeatured(ctx context.Context, username string, account *gtsmodel.Account) error {
+	return f.dereferencer.DereferenceFeatured(ctx, username, account)
+}
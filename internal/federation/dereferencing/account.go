@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -84,7 +85,7 @@ func (d *deref) GetRemoteAccount(ctx context.Context, username string, remoteAcc
 
 	if new {
 		// we haven't seen this account before: dereference it from remote
-		accountable, err := d.dereferenceAccountable(ctx, username, remoteAccountID)
+		accountable, err := d.dereferenceAccountable(ctx, username, remoteAccountID, refresh)
 		if err != nil {
 			return nil, fmt.Errorf("GetRemoteAccount: error dereferencing accountable: %s", err)
 		}
@@ -103,6 +104,7 @@ func (d *deref) GetRemoteAccount(ctx context.Context, username string, remoteAcc
 		if _, err := d.populateAccountFields(ctx, newAccount, username, refresh, blocking); err != nil {
 			return nil, fmt.Errorf("GetRemoteAccount: error populating further account fields: %s", err)
 		}
+		newAccount.LastWebfingeredAt = time.Now()
 
 		if err := d.db.Put(ctx, newAccount); err != nil {
 			return nil, fmt.Errorf("GetRemoteAccount: error putting new account: %s", err)
@@ -112,7 +114,7 @@ func (d *deref) GetRemoteAccount(ctx context.Context, username string, remoteAcc
 	}
 
 	// we have seen this account before, but we have to refresh it
-	refreshedAccountable, err := d.dereferenceAccountable(ctx, username, remoteAccountID)
+	refreshedAccountable, err := d.dereferenceAccountable(ctx, username, remoteAccountID, true)
 	if err != nil {
 		return nil, fmt.Errorf("GetRemoteAccount: error dereferencing refreshedAccountable: %s", err)
 	}
@@ -122,28 +124,83 @@ func (d *deref) GetRemoteAccount(ctx context.Context, username string, remoteAcc
 		return nil, fmt.Errorf("GetRemoteAccount: error converting refreshedAccountable to refreshedAccount: %s", err)
 	}
 	refreshedAccount.ID = remoteAccount.ID
+	keepLocalAccountFields(refreshedAccount, remoteAccount)
 
-	changed, err := d.populateAccountFields(ctx, refreshedAccount, username, refresh, blocking)
-	if err != nil {
+	// media only needs fetching again if it's changed since we last saw it
+	if _, err := d.populateAccountFields(ctx, refreshedAccount, username, false, blocking); err != nil {
 		return nil, fmt.Errorf("GetRemoteAccount: error populating further refreshedAccount fields: %s", err)
 	}
+	refreshedAccount.LastWebfingeredAt = time.Now()
 
-	if changed {
-		updatedAccount, err := d.db.UpdateAccount(ctx, refreshedAccount)
-		if err != nil {
-			return nil, fmt.Errorf("GetRemoteAccount: error updating refreshedAccount: %s", err)
+	if !accountChanged(refreshedAccount, remoteAccount) {
+		// nothing to store except when the account was fetched
+		if err := d.db.UpdateAccountLastWebfingeredAt(ctx, remoteAccount.ID, refreshedAccount.LastWebfingeredAt); err != nil {
+			return nil, fmt.Errorf("GetRemoteAccount: error updating last fetch time of remoteAccount: %s", err)
 		}
-		return updatedAccount, nil
+		remoteAccount.LastWebfingeredAt = refreshedAccount.LastWebfingeredAt
+		return remoteAccount, nil
 	}
 
-	return refreshedAccount, nil
+	// store the refreshed account so that any changes to its name, keys, endpoints etc are picked up
+	updatedAccount, err := d.db.UpdateAccount(ctx, refreshedAccount)
+	if err != nil {
+		return nil, fmt.Errorf("GetRemoteAccount: error updating refreshedAccount: %s", err)
+	}
+
+	return updatedAccount, nil
+}
+
+// accountChanged returns true if a refreshed account differs from the stored account it was refreshed from in
+// anything that gets stored, ignoring when each was updated and fetched, and which of their models are populated.
+func accountChanged(refreshed *gtsmodel.Account, stored *gtsmodel.Account) bool {
+	a, b := *refreshed, *stored
+	for _, account := range []*gtsmodel.Account{&a, &b} {
+		account.UpdatedAt = time.Time{}
+		account.LastWebfingeredAt = time.Time{}
+		account.AvatarMediaAttachment = nil
+		account.HeaderMediaAttachment = nil
+		account.Emojis = nil
+
+		// an empty slice is stored the same as a nil one
+		v := reflect.ValueOf(account).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Kind() == reflect.Slice && f.Len() == 0 {
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+	}
+	return !reflect.DeepEqual(a, b)
+}
+
+// keepLocalAccountFields copies the fields of a stored account that don't come from its remote
+// representation onto a freshly dereferenced version of it, so that they're not lost when it's
+// stored. Avatar and header are kept as well, unless their remote URLs have changed.
+func keepLocalAccountFields(refreshed *gtsmodel.Account, stored *gtsmodel.Account) {
+	refreshed.CreatedAt = stored.CreatedAt
+	refreshed.Fields = stored.Fields
+	refreshed.Memorial = stored.Memorial
+	refreshed.Reason = stored.Reason
+	refreshed.Privacy = stored.Privacy
+	refreshed.Language = stored.Language
+	refreshed.SensitizedAt = stored.SensitizedAt
+	refreshed.SilencedAt = stored.SilencedAt
+	refreshed.SuspendedAt = stored.SuspendedAt
+	refreshed.SuspensionOrigin = stored.SuspensionOrigin
+
+	if refreshed.AvatarRemoteURL == stored.AvatarRemoteURL {
+		refreshed.AvatarMediaAttachmentID = stored.AvatarMediaAttachmentID
+	}
+	if refreshed.HeaderRemoteURL == stored.HeaderRemoteURL {
+		refreshed.HeaderMediaAttachmentID = stored.HeaderMediaAttachmentID
+	}
 }
 
 // dereferenceAccountable calls remoteAccountID with a GET request, and tries to parse whatever
 // it finds as something that an account model can be constructed out of.
 //
-// Will work for Person, Application, or Service models.
-func (d *deref) dereferenceAccountable(ctx context.Context, username string, remoteAccountID *url.URL) (ap.Accountable, error) {
+// Will work for Person, Application, or Service models. If refresh is true, the account
+// is fetched from the remote instance even if it was fetched only a moment ago.
+func (d *deref) dereferenceAccountable(ctx context.Context, username string, remoteAccountID *url.URL, refresh bool) (ap.Accountable, error) {
 	d.startHandshake(username, remoteAccountID)
 	defer d.stopHandshake(username, remoteAccountID)
 
@@ -156,7 +213,7 @@ func (d *deref) dereferenceAccountable(ctx context.Context, username string, rem
 		return nil, fmt.Errorf("DereferenceAccountable: transport err: %s", err)
 	}
 
-	b, err := transport.Dereference(ctx, remoteAccountID)
+	b, err := d.dereferenceObject(ctx, transport, username, remoteAccountID, refresh)
 	if err != nil {
 		return nil, fmt.Errorf("DereferenceAccountable: error deferencing %s: %s", remoteAccountID.String(), err)
 	}
@@ -209,7 +266,7 @@ func (d *deref) dereferenceAccountable(ctx context.Context, username string, rem
 
 // populateAccountFields populates any fields on the given account that weren't populated by the initial
 // dereferencing. This includes things like header and avatar etc.
-func (d *deref) populateAccountFields(ctx context.Context, account *gtsmodel.Account, requestingUsername string, blocking bool, refresh bool) (bool, error) {
+func (d *deref) populateAccountFields(ctx context.Context, account *gtsmodel.Account, requestingUsername string, refresh bool, blocking bool) (bool, error) {
 	// if we're dealing with an instance account, just bail, we don't need to do anything
 	if instanceAccount(account) {
 		return false, nil
@@ -230,11 +287,29 @@ func (d *deref) populateAccountFields(ctx context.Context, account *gtsmodel.Acc
 	}
 
 	// fetch the header and avatar
-	changed, err := d.fetchRemoteAccountMedia(ctx, account, t, refresh, blocking)
+	changed, err := d.fetchRemoteAccountMedia(ctx, account, t, blocking, refresh)
 	if err != nil {
 		return false, fmt.Errorf("populateAccountFields: error fetching header/avi for account: %s", err)
 	}
 
+	// store any emojis used in the profile; these are only set if the account has just been dereferenced
+	if account.Emojis != nil {
+		emojis, err := d.populateEmojis(ctx, account.Emojis, accountURI.Host, t, blocking)
+		if err != nil {
+			return false, fmt.Errorf("populateAccountFields: error populating emojis for account: %s", err)
+		}
+
+		emojiIDs := make([]string, 0, len(emojis))
+		for _, e := range emojis {
+			emojiIDs = append(emojiIDs, e.ID)
+		}
+		if strings.Join(emojiIDs, ",") != strings.Join(account.EmojiIDs, ",") {
+			changed = true
+		}
+		account.Emojis = emojis
+		account.EmojiIDs = emojiIDs
+	}
+
 	return changed, nil
 }
 
@@ -262,6 +337,15 @@ func (d *deref) fetchRemoteAccountMedia(ctx context.Context, targetAccount *gtsm
 		return changed, fmt.Errorf("fetchRemoteAccountMedia: domain %s is blocked", accountURI.Host)
 	}
 
+	// media from silenced domains, or domains whose media is rejected, is never cached, so there's nothing to fetch
+	rejected, err := d.db.IsDomainMediaRejected(ctx, accountURI.Host)
+	if err != nil {
+		return changed, fmt.Errorf("fetchRemoteAccountMedia: error checking whether media from domain %s is rejected: %s", accountURI.Host, err)
+	}
+	if rejected {
+		return changed, nil
+	}
+
 	if targetAccount.AvatarRemoteURL != "" && (targetAccount.AvatarMediaAttachmentID == "" || refresh) {
 		var processingMedia *media.ProcessingMedia
 
@@ -20,10 +20,16 @@ package dereferencing_test
 
 import (
 	"context"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
@@ -51,6 +57,210 @@ func (suite *AccountTestSuite) TestDereferenceGroup() {
 	suite.Equal(ap.ActorGroup, dbGroup.ActorType)
 }
 
+func (suite *AccountTestSuite) TestRefreshAccount() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+
+	personURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+	person, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, false, false)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now(), person.LastWebfingeredAt, 30*time.Second)
+
+	// change some things about the stored account: one that comes from the remote
+	// representation of the account, and some that only exist on this instance
+	person.DisplayName = "some outdated name"
+	person.LastWebfingeredAt = time.Now().Add(-30 * 24 * time.Hour)
+	person.SilencedAt = time.Now()
+	_, err = suite.db.UpdateAccount(ctx, person)
+	suite.NoError(err)
+
+	refreshed, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, false, true)
+	suite.NoError(err)
+
+	dbPerson, err := suite.db.GetAccountByID(ctx, person.ID)
+	suite.NoError(err)
+	suite.Equal(refreshed.DisplayName, dbPerson.DisplayName)
+	suite.NotEqual("some outdated name", dbPerson.DisplayName)
+	suite.WithinDuration(time.Now(), dbPerson.LastWebfingeredAt, 30*time.Second)
+	suite.False(dbPerson.SilencedAt.IsZero())
+	suite.Equal(person.CreatedAt.Unix(), dbPerson.CreatedAt.Unix())
+	suite.Equal(person.AvatarMediaAttachmentID, dbPerson.AvatarMediaAttachmentID)
+}
+
+func (suite *AccountTestSuite) TestRefreshAccountUnchanged() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+
+	personURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+	person, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, false, false)
+	suite.NoError(err)
+
+	// pretend the account was stored and fetched a while ago
+	updatedAt := time.Now().Add(-30 * 24 * time.Hour)
+	err = suite.db.UpdateWhere(ctx, []db.Where{{Key: "id", Value: person.ID}}, "updated_at", updatedAt, &gtsmodel.Account{})
+	suite.NoError(err)
+	err = suite.db.UpdateAccountLastWebfingeredAt(ctx, person.ID, updatedAt)
+	suite.NoError(err)
+
+	// nothing about the account has changed on its instance, so only when it was fetched should be stored
+	refreshed, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, false, true)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now(), refreshed.LastWebfingeredAt, 30*time.Second)
+
+	dbPerson, err := suite.db.GetAccountByID(ctx, person.ID)
+	suite.NoError(err)
+	suite.WithinDuration(time.Now(), dbPerson.LastWebfingeredAt, 30*time.Second)
+	suite.WithinDuration(updatedAt, dbPerson.UpdatedAt, time.Second)
+}
+
+func (suite *AccountTestSuite) TestDereferenceAccountWithEmoji() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+
+	rainbowBytes, err := os.ReadFile("../../../testrig/media/rainbow-original.png")
+	suite.NoError(err)
+	suite.testRemoteAttachments["https://unknown-instance.com/emoji/blobcat.png"] = testrig.RemoteAttachmentFile{
+		Data:        rainbowBytes,
+		ContentType: "image/png",
+	}
+
+	// the person uses a custom emoji from their instance in their display name
+	personURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+	person := suite.testRemotePeople[personURL.String()]
+	nameProp := streams.NewActivityStreamsNameProperty()
+	nameProp.AppendXMLSchemaString("brand new person :blobcat:")
+	person.SetActivityStreamsName(nameProp)
+
+	setEmojis(person, newEmoji("https://unknown-instance.com/emoji/blobcat", ":blobcat:", "https://unknown-instance.com/emoji/blobcat.png"))
+
+	account, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, true, false)
+	suite.NoError(err)
+	suite.Len(account.EmojiIDs, 1)
+
+	// the emoji should be stored as a remote emoji, kept out of the picker
+	dbEmoji := &gtsmodel.Emoji{}
+	suite.NoError(suite.db.GetByID(ctx, account.EmojiIDs[0], dbEmoji))
+	suite.Equal("blobcat", dbEmoji.Shortcode)
+	suite.Equal("unknown-instance.com", dbEmoji.Domain)
+	suite.Equal("https://unknown-instance.com/emoji/blobcat", dbEmoji.URI)
+	suite.Equal("https://unknown-instance.com/emoji/blobcat.png", dbEmoji.ImageRemoteURL)
+	suite.NotEmpty(dbEmoji.ImageURL)
+	suite.False(dbEmoji.VisibleInPicker)
+
+	// and the account should point to it in the database
+	dbAccount, err := suite.db.GetAccountByID(ctx, account.ID)
+	suite.NoError(err)
+	suite.Equal(account.EmojiIDs, dbAccount.EmojiIDs)
+
+	// refreshing the account uses the emoji that's already stored, instead of fetching it again
+	refreshed, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, true, true)
+	suite.NoError(err)
+	suite.Equal(account.EmojiIDs, refreshed.EmojiIDs)
+	suite.Equal(1, suite.requests["https://unknown-instance.com/emoji/blobcat.png"])
+}
+
+func (suite *AccountTestSuite) TestDereferenceAccountWithForeignEmoji() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+
+	rainbowBytes, err := os.ReadFile("../../../testrig/media/rainbow-original.png")
+	suite.NoError(err)
+	suite.testRemoteAttachments["https://fossbros-anonymous.io/emoji/blobcat.png"] = testrig.RemoteAttachmentFile{
+		Data:        rainbowBytes,
+		ContentType: "image/png",
+	}
+
+	// the person uses an emoji that claims to be from another instance
+	personURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+	person := suite.testRemotePeople[personURL.String()]
+	setEmojis(person, newEmoji("https://fossbros-anonymous.io/emoji/blobcat", ":blobcat:", "https://fossbros-anonymous.io/emoji/blobcat.png"))
+
+	// so it shouldn't be stored, or even fetched
+	account, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, true, false)
+	suite.NoError(err)
+	suite.Empty(account.EmojiIDs)
+	suite.Zero(suite.requests["https://fossbros-anonymous.io/emoji/blobcat.png"])
+
+	dbEmoji := &gtsmodel.Emoji{}
+	err = suite.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: "https://fossbros-anonymous.io/emoji/blobcat"}}, dbEmoji)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *AccountTestSuite) TestDereferenceAccountWithChangedEmoji() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+
+	rainbowBytes, err := os.ReadFile("../../../testrig/media/rainbow-original.png")
+	suite.NoError(err)
+	staticBytes, err := os.ReadFile("../../../testrig/media/rainbow-static.png")
+	suite.NoError(err)
+	suite.testRemoteAttachments["https://unknown-instance.com/emoji/blobcat.png"] = testrig.RemoteAttachmentFile{
+		Data:        rainbowBytes,
+		ContentType: "image/png",
+	}
+	suite.testRemoteAttachments["https://unknown-instance.com/emoji/blobcat-new.png"] = testrig.RemoteAttachmentFile{
+		Data:        staticBytes,
+		ContentType: "image/png",
+	}
+
+	personURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+	person := suite.testRemotePeople[personURL.String()]
+	setEmojis(person, newEmoji("https://unknown-instance.com/emoji/blobcat", ":blobcat:", "https://unknown-instance.com/emoji/blobcat.png"))
+
+	account, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, true, false)
+	suite.NoError(err)
+	suite.Len(account.EmojiIDs, 1)
+
+	// the image of the emoji has changed on its instance since
+	setEmojis(person, newEmoji("https://unknown-instance.com/emoji/blobcat", ":blobcat:", "https://unknown-instance.com/emoji/blobcat-new.png"))
+
+	refreshed, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, personURL, true, true)
+	suite.NoError(err)
+	suite.Equal(account.EmojiIDs, refreshed.EmojiIDs)
+	suite.Equal(1, suite.requests["https://unknown-instance.com/emoji/blobcat-new.png"])
+
+	// so the emoji should have been updated in place, with the new image
+	dbEmoji := &gtsmodel.Emoji{}
+	suite.NoError(suite.db.GetByID(ctx, account.EmojiIDs[0], dbEmoji))
+	suite.Equal("https://unknown-instance.com/emoji/blobcat-new.png", dbEmoji.ImageRemoteURL)
+	suite.Equal(len(staticBytes), dbEmoji.ImageFileSize)
+
+	stored, err := suite.storage.Get(dbEmoji.ImagePath)
+	suite.NoError(err)
+	suite.Equal(staticBytes, stored)
+}
+
+// newEmoji returns a toot:Emoji with the given id, name, and image url, for using as a tag.
+func newEmoji(id string, name string, imageURL string) vocab.TootEmoji {
+	emoji := streams.NewTootEmoji()
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(testrig.URLMustParse(id))
+	emoji.SetJSONLDId(idProp)
+	emojiNameProp := streams.NewActivityStreamsNameProperty()
+	emojiNameProp.AppendXMLSchemaString(name)
+	emoji.SetActivityStreamsName(emojiNameProp)
+	image := streams.NewActivityStreamsImage()
+	mediaTypeProp := streams.NewActivityStreamsMediaTypeProperty()
+	mediaTypeProp.Set("image/png")
+	image.SetActivityStreamsMediaType(mediaTypeProp)
+	urlProp := streams.NewActivityStreamsUrlProperty()
+	urlProp.AppendIRI(testrig.URLMustParse(imageURL))
+	image.SetActivityStreamsUrl(urlProp)
+	iconProp := streams.NewActivityStreamsIconProperty()
+	iconProp.AppendActivityStreamsImage(image)
+	emoji.SetActivityStreamsIcon(iconProp)
+	return emoji
+}
+
+// setEmojis sets the given emojis as the tags of the given person.
+func setEmojis(person vocab.ActivityStreamsPerson, emojis ...vocab.TootEmoji) {
+	tagProp := streams.NewActivityStreamsTagProperty()
+	for _, emoji := range emojis {
+		tagProp.AppendTootEmoji(emoji)
+	}
+	person.SetActivityStreamsTag(tagProp)
+}
+
 func TestAccountTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountTestSuite))
 }
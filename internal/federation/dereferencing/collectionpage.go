@@ -67,3 +67,100 @@ func (d *deref) DereferenceCollectionPage(ctx context.Context, username string,
 
 	return p, nil
 }
+
+// collectionItems is implemented by collections and collection pages.
+type collectionItems interface {
+	GetActivityStreamsItems() vocab.ActivityStreamsItemsProperty
+}
+
+// collectionOrderedItems is implemented by ordered collections and ordered collection pages.
+type collectionOrderedItems interface {
+	GetActivityStreamsOrderedItems() vocab.ActivityStreamsOrderedItemsProperty
+}
+
+// collectionFirst is implemented by both unordered and ordered collections.
+type collectionFirst interface {
+	GetActivityStreamsFirst() vocab.ActivityStreamsFirstProperty
+}
+
+// collectionPageNext is implemented by both unordered and ordered collection pages.
+type collectionPageNext interface {
+	GetActivityStreamsNext() vocab.ActivityStreamsNextProperty
+}
+
+// dereferenceCollection returns the collection, ordered collection, or page of either, at the specified IRI.
+func (d *deref) dereferenceCollection(ctx context.Context, username string, iri *url.URL) (vocab.Type, error) {
+	if blocked, err := d.db.IsDomainBlocked(ctx, iri.Host); blocked || err != nil {
+		return nil, fmt.Errorf("dereferenceCollection: domain %s is blocked", iri.Host)
+	}
+
+	transport, err := d.transportController.NewTransportForUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("dereferenceCollection: error creating transport: %s", err)
+	}
+
+	b, err := d.dereferenceObject(ctx, transport, username, iri, false)
+	if err != nil {
+		return nil, fmt.Errorf("dereferenceCollection: error dereferencing %s: %s", iri, err)
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("dereferenceCollection: error unmarshalling bytes into json: %s", err)
+	}
+
+	t, err := streams.ToType(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("dereferenceCollection: error resolving json into ap vocab type: %s", err)
+	}
+
+	switch t.GetTypeName() {
+	case ap.ObjectCollection, ap.ObjectOrderedCollection, ap.ObjectCollectionPage, ap.ObjectOrderedCollectionPage:
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("dereferenceCollection: type name %s not supported", t.GetTypeName())
+}
+
+// collectionItemIRIs returns the ids of the items in the given collection or collection page, which can be
+// either IRIs, or objects with an id. Anything that isn't a collection or collection page has no items.
+func collectionItemIRIs(t vocab.Type) []*url.URL {
+	iris := []*url.URL{}
+
+	add := func(iri *url.URL, item vocab.Type) {
+		if iri == nil && item != nil {
+			if id := item.GetJSONLDId(); id != nil && id.IsIRI() {
+				iri = id.GetIRI()
+			}
+		}
+		if iri != nil {
+			iris = append(iris, iri)
+		}
+	}
+
+	if c, ok := t.(collectionOrderedItems); ok {
+		if items := c.GetActivityStreamsOrderedItems(); items != nil {
+			for iter := items.Begin(); iter != items.End(); iter = iter.Next() {
+				if iter.IsIRI() {
+					add(iter.GetIRI(), nil)
+				} else {
+					add(nil, iter.GetType())
+				}
+			}
+		}
+	}
+
+	if c, ok := t.(collectionItems); ok {
+		if items := c.GetActivityStreamsItems(); items != nil {
+			for iter := items.Begin(); iter != items.End(); iter = iter.Next() {
+				if iter.IsIRI() {
+					add(iter.GetIRI(), nil)
+				} else {
+					add(nil, iter.GetType())
+				}
+			}
+		}
+	}
+
+	return iris
+}
@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"sync"
 
+	"github.com/ReneKroon/ttlcache"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -44,6 +45,7 @@ type Dereferencer interface {
 
 	DereferenceAnnounce(ctx context.Context, announce *gtsmodel.Status, requestingUsername string) error
 	DereferenceThread(ctx context.Context, username string, statusIRI *url.URL) error
+	DereferenceFeatured(ctx context.Context, username string, account *gtsmodel.Account) error
 
 	Handshaking(ctx context.Context, username string, remoteAccountID *url.URL) bool
 }
@@ -59,6 +61,9 @@ type deref struct {
 	dereferencingHeadersLock *sync.Mutex
 	handshakes               map[string][]*url.URL
 	handshakeSync            *sync.Mutex // mutex to lock/unlock when checking or updating the handshakes map
+	objectCache              *ttlcache.Cache
+	threadWalks              *ttlcache.Cache // statuses whose replies were walked recently
+	threadWalkSlots          chan struct{}   // limits how many threads are walked at the same time
 }
 
 // NewDereferencer returns a Dereferencer initialized with the given parameters.
@@ -73,5 +78,8 @@ func NewDereferencer(db db.DB, typeConverter typeutils.TypeConverter, transportC
 		dereferencingHeaders:     make(map[string]*media.ProcessingMedia),
 		dereferencingHeadersLock: &sync.Mutex{},
 		handshakeSync:            &sync.Mutex{},
+		objectCache:              newObjectCache(),
+		threadWalks:              newThreadWalks(),
+		threadWalkSlots:          make(chan struct{}, threadWalkConcurrency),
 	}
 }
@@ -23,8 +23,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/streams"
@@ -33,6 +34,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/federation/dereferencing"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 	"github.com/superseriousbusiness/gotosocial/testrig"
@@ -41,7 +43,7 @@ import (
 type DereferencerStandardTestSuite struct {
 	suite.Suite
 	db      db.DB
-	storage *kv.KVStore
+	storage *gtsstorage.Driver
 
 	testRemoteStatuses    map[string]vocab.ActivityStreamsNote
 	testRemotePeople      map[string]vocab.ActivityStreamsPerson
@@ -49,6 +51,13 @@ type DereferencerStandardTestSuite struct {
 	testRemoteAttachments map[string]testrig.RemoteAttachmentFile
 	testAccounts          map[string]*gtsmodel.Account
 
+	// testRemoteJSON is served as-is by the mock transport controller, for things like collections that aren't in testrig
+	testRemoteJSON map[string]string
+
+	// requests counts how many times each URL has been requested from the mock transport controller
+	requests   map[string]int
+	requestsMu sync.Mutex
+
 	dereferencer dereferencing.Dereferencer
 }
 
@@ -61,6 +70,8 @@ func (suite *DereferencerStandardTestSuite) SetupTest() {
 	suite.testRemotePeople = testrig.NewTestFediPeople()
 	suite.testRemoteGroups = testrig.NewTestFediGroups()
 	suite.testRemoteAttachments = testrig.NewTestFediAttachments("../../../testrig/media")
+	suite.requests = make(map[string]int)
+	suite.testRemoteJSON = make(map[string]string)
 
 	suite.db = testrig.NewTestDB()
 	suite.storage = testrig.NewTestStorage()
@@ -75,11 +86,24 @@ func (suite *DereferencerStandardTestSuite) TearDownTest() {
 // mockTransportController returns basically a miniature muxer, which returns a different
 // value based on the request URL. It can be used to return remote statuses, profiles, etc,
 // as though they were actually being dereferenced. If the URL doesn't correspond to any person
-// or note or attachment that we have stored, then just a 200 code will be returned, with an empty body.
+// or note or attachment that we have stored, then just a 200 code will be returned, with an empty body,
+// unless the URL path contains '/gone/', in which case a 410 code will be returned.
 func (suite *DereferencerStandardTestSuite) mockTransportController() transport.Controller {
 	do := func(req *http.Request) (*http.Response, error) {
 		logrus.Debugf("received request for %s", req.URL)
 
+		suite.requestsMu.Lock()
+		suite.requests[req.URL.String()]++
+		suite.requestsMu.Unlock()
+
+		if strings.Contains(req.URL.Path, "/gone/") {
+			return &http.Response{
+				StatusCode: http.StatusGone,
+				Status:     "410 Gone",
+				Body:       io.NopCloser(bytes.NewReader([]byte{})),
+			}, nil
+		}
+
 		responseBytes := []byte{}
 		responseType := ""
 		responseLength := 0
@@ -126,6 +150,13 @@ func (suite *DereferencerStandardTestSuite) mockTransportController() transport.
 			responseType = "application/activity+json"
 		}
 
+		suite.requestsMu.Lock()
+		if j, ok := suite.testRemoteJSON[req.URL.String()]; ok {
+			responseBytes = []byte(j)
+			responseType = "application/activity+json"
+		}
+		suite.requestsMu.Unlock()
+
 		if attachment, ok := suite.testRemoteAttachments[req.URL.String()]; ok {
 			responseBytes = attachment.Data
 			responseType = attachment.ContentType
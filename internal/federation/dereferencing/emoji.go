@@ -0,0 +1,175 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+const (
+	// maxEmojis is the most emojis of one remote object that will be stored, so that a misbehaving
+	// server can't get us to fetch loads of images at once.
+	maxEmojis = 50
+	// emojiRefreshInterval is how long after its image was fetched that a remote emoji will be fetched again.
+	emojiRefreshInterval = 7 * 24 * time.Hour
+)
+
+// populateEmojis makes sure that each of the given emojis, as extracted from a remote object, is stored
+// in the database, fetching the images of any that haven't been seen before. The stored emojis are returned,
+// in the same order, leaving out any that couldn't be stored.
+//
+// Only emojis from the given host, which should be the host of the object the emojis were found on, are
+// stored, since anyone could otherwise put emojis on their objects that pass themselves off as another
+// instance's. Stored emojis whose image has changed, or that haven't been fetched in a while, are refreshed.
+//
+// If blocking is true, then this function won't return until any new emojis have been fully processed.
+// Otherwise they're processed in the background, and the returned emojis are just placeholders with an ID.
+func (d *deref) populateEmojis(ctx context.Context, emojis []*gtsmodel.Emoji, host string, t transport.Transport, blocking bool) ([]*gtsmodel.Emoji, error) {
+	stored := make([]*gtsmodel.Emoji, 0, len(emojis))
+
+	for _, e := range emojis {
+		if len(stored) >= maxEmojis {
+			logrus.Debugf("populateEmojis: more than %d emojis from %s, leaving out the rest", maxEmojis, host)
+			break
+		}
+
+		if e.Domain != host {
+			logrus.Debugf("populateEmojis: leaving out emoji %s, since it's not from %s", e.URI, host)
+			continue
+		}
+
+		// we might know about this emoji already, either by its uri, or by its shortcode on its instance
+		existing := &gtsmodel.Emoji{}
+		err := d.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: e.URI}}, existing)
+		if err == db.ErrNoEntries {
+			err = d.db.GetWhere(ctx, []db.Where{{Key: "shortcode", Value: e.Shortcode}, {Key: "domain", Value: e.Domain}}, existing)
+		}
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("populateEmojis: error getting emoji %s: %s", e.URI, err)
+		}
+		if err == nil && existing.ImageRemoteURL == e.ImageRemoteURL && time.Since(existing.ImageUpdatedAt) < emojiRefreshInterval {
+			stored = append(stored, existing)
+			continue
+		}
+
+		// images of emojis from silenced domains, or domains whose media is rejected, are never cached, so they can't be stored
+		rejected, err := d.db.IsDomainMediaRejected(ctx, e.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("populateEmojis: error checking whether media from domain %s is rejected: %s", e.Domain, err)
+		}
+		if rejected {
+			if existing.ID != "" {
+				stored = append(stored, existing)
+			}
+			continue
+		}
+
+		var processingEmoji *media.ProcessingEmoji
+		if existing.ID != "" {
+			processingEmoji, err = d.refreshRemoteEmoji(ctx, existing, e, t)
+		} else {
+			processingEmoji, err = d.getRemoteEmoji(ctx, e, t)
+		}
+		if err != nil {
+			logrus.Errorf("populateEmojis: error fetching emoji %s: %s", e.URI, err)
+			if existing.ID != "" {
+				stored = append(stored, existing)
+			}
+			continue
+		}
+
+		if !blocking {
+			stored = append(stored, &gtsmodel.Emoji{ID: processingEmoji.EmojiID()})
+			continue
+		}
+
+		emoji, err := processingEmoji.LoadEmoji(ctx)
+		if err != nil {
+			logrus.Errorf("populateEmojis: error loading emoji %s: %s", e.URI, err)
+			if existing.ID != "" {
+				stored = append(stored, existing)
+			}
+			continue
+		}
+		stored = append(stored, emoji)
+	}
+
+	return stored, nil
+}
+
+// getRemoteEmoji starts processing the given remote emoji, fetching its image with the given transport.
+func (d *deref) getRemoteEmoji(ctx context.Context, e *gtsmodel.Emoji, t transport.Transport) (*media.ProcessingEmoji, error) {
+	imageURL, err := url.Parse(e.ImageRemoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("getRemoteEmoji: error parsing image url: %s", err)
+	}
+
+	emojiID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, fmt.Errorf("getRemoteEmoji: error creating id: %s", err)
+	}
+
+	dataFunc := func(innerCtx context.Context) (io.Reader, int, error) {
+		return t.DereferenceMedia(innerCtx, imageURL)
+	}
+
+	// remote emojis can be shown, but they're not for using on this instance, so keep them out of the picker
+	visibleInPicker := false
+	processingEmoji, err := d.mediaManager.ProcessEmoji(ctx, dataFunc, nil, e.Shortcode, emojiID, e.URI, &media.AdditionalEmojiInfo{
+		Domain:          &e.Domain,
+		ImageRemoteURL:  &e.ImageRemoteURL,
+		VisibleInPicker: &visibleInPicker,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getRemoteEmoji: error processing emoji: %s", err)
+	}
+
+	return processingEmoji, nil
+}
+
+// refreshRemoteEmoji starts fetching and processing the image of the given stored emoji again,
+// from the image url of e, which is the same emoji as it's been found on a remote object now.
+func (d *deref) refreshRemoteEmoji(ctx context.Context, existing *gtsmodel.Emoji, e *gtsmodel.Emoji, t transport.Transport) (*media.ProcessingEmoji, error) {
+	imageURL, err := url.Parse(e.ImageRemoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("refreshRemoteEmoji: error parsing image url: %s", err)
+	}
+
+	dataFunc := func(innerCtx context.Context) (io.Reader, int, error) {
+		return t.DereferenceMedia(innerCtx, imageURL)
+	}
+
+	processingEmoji, err := d.mediaManager.RefreshEmoji(ctx, dataFunc, nil, existing, e.ImageRemoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("refreshRemoteEmoji: error processing emoji: %s", err)
+	}
+
+	return processingEmoji, nil
+}
@@ -0,0 +1,122 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// maxFeaturedStatuses is the most statuses from a remote account's featured collection that will be
+// marked as pinned, so that a misbehaving server can't get us to dereference loads of statuses at once.
+const maxFeaturedStatuses = 20
+
+// DereferenceFeatured fetches the featured collection of the given remote account, and makes sure that the statuses in it,
+// and only those, are marked as pinned. Statuses in the collection that aren't known yet are dereferenced and stored.
+func (d *deref) DereferenceFeatured(ctx context.Context, username string, account *gtsmodel.Account) error {
+	if account.FeaturedCollectionURI == "" {
+		// nothing to do
+		return nil
+	}
+
+	featuredIRI, err := url.Parse(account.FeaturedCollectionURI)
+	if err != nil {
+		return fmt.Errorf("DereferenceFeatured: error parsing url %s: %s", account.FeaturedCollectionURI, err)
+	}
+
+	if blocked, err := d.db.IsDomainBlocked(ctx, featuredIRI.Host); blocked || err != nil {
+		return fmt.Errorf("DereferenceFeatured: domain %s is blocked", featuredIRI.Host)
+	}
+
+	transport, err := d.transportController.NewTransportForUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("DereferenceFeatured: error creating transport: %s", err)
+	}
+
+	b, err := d.dereferenceObject(ctx, transport, username, featuredIRI, false)
+	if err != nil {
+		return fmt.Errorf("DereferenceFeatured: error dereferencing %s: %s", featuredIRI, err)
+	}
+
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("DereferenceFeatured: error unmarshalling bytes into json: %s", err)
+	}
+
+	t, err := streams.ToType(ctx, m)
+	if err != nil {
+		return fmt.Errorf("DereferenceFeatured: error resolving json into ap vocab type: %s", err)
+	}
+
+	itemIRIs := collectionItemIRIs(t)
+
+	// work out which statuses should be pinned now
+	pinned := make(map[string]bool, len(itemIRIs))
+	for _, itemIRI := range itemIRIs {
+		if len(pinned) >= maxFeaturedStatuses {
+			break
+		}
+
+		status, _, _, err := d.GetRemoteStatus(ctx, username, itemIRI, false, false)
+		if err != nil {
+			logrus.Debugf("DereferenceFeatured: couldn't get featured status %s: %s", itemIRI, err)
+			continue
+		}
+
+		// accounts can only feature their own statuses
+		if status.AccountID != account.ID {
+			continue
+		}
+
+		pinned[status.ID] = true
+
+		if !status.Pinned {
+			status.Pinned = true
+			if err := d.db.UpdateStatus(ctx, status); err != nil {
+				return fmt.Errorf("DereferenceFeatured: error pinning status %s: %s", status.ID, err)
+			}
+		}
+	}
+
+	// unpin anything that isn't featured anymore
+	previouslyPinned, err := d.db.GetAccountStatuses(ctx, account.ID, 0, false, false, "", "", true, false, false)
+	if err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("DereferenceFeatured: error getting pinned statuses of account %s: %s", account.ID, err)
+	}
+
+	for _, status := range previouslyPinned {
+		if pinned[status.ID] {
+			continue
+		}
+
+		status.Pinned = false
+		if err := d.db.UpdateStatus(ctx, status); err != nil {
+			return fmt.Errorf("DereferenceFeatured: error unpinning status %s: %s", status.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type FeaturedTestSuite struct {
+	DereferencerStandardTestSuite
+}
+
+func (suite *FeaturedTestSuite) TestDereferenceFeatured() {
+	ctx := context.Background()
+	account := suite.testAccounts["remote_account_1"]
+	status := testrig.NewTestStatuses()["remote_account_1_status_1"]
+
+	// the collection has one of the account's own statuses, and a status by someone else, which should be left alone
+	suite.testRemoteJSON[account.FeaturedCollectionURI] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + account.FeaturedCollectionURI + `",
+		"type": "OrderedCollection",
+		"totalItems": 2,
+		"orderedItems": [
+			"` + status.URI + `",
+			{
+				"id": "https://unknown-instance.com/users/brand_new_person/statuses/01FE4NTHKWW7THT67EF10EB839",
+				"type": "Note"
+			}
+		]
+	}`
+
+	err := suite.dereferencer.DereferenceFeatured(ctx, suite.testAccounts["local_account_1"].Username, account)
+	suite.NoError(err)
+
+	dbStatus, err := suite.db.GetStatusByID(ctx, status.ID)
+	suite.NoError(err)
+	suite.True(dbStatus.Pinned)
+
+	otherStatus, err := suite.db.GetStatusByURI(ctx, "https://unknown-instance.com/users/brand_new_person/statuses/01FE4NTHKWW7THT67EF10EB839")
+	suite.NoError(err)
+	suite.False(otherStatus.Pinned)
+
+	// the status is unfeatured, which is noticed the next time the collection is fetched
+	suite.testRemoteJSON[account.FeaturedCollectionURI] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + account.FeaturedCollectionURI + `",
+		"type": "OrderedCollection",
+		"totalItems": 0,
+		"orderedItems": []
+	}`
+
+	err = suite.dereferencer.DereferenceFeatured(ctx, suite.testAccounts["local_account_2"].Username, account)
+	suite.NoError(err)
+
+	dbStatus, err = suite.db.GetStatusByID(ctx, status.ID)
+	suite.NoError(err)
+	suite.False(dbStatus.Pinned)
+}
+
+func TestFeaturedTestSuite(t *testing.T) {
+	suite.Run(t, new(FeaturedTestSuite))
+}
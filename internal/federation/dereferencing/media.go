@@ -23,7 +23,10 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"time"
 
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 )
 
@@ -53,3 +56,26 @@ func (d *deref) GetRemoteMedia(ctx context.Context, requestingUsername string, a
 
 	return processingMedia, nil
 }
+
+// putUncachedMedia puts the given remote attachment in the database without fetching it, for media that
+// should never be cached, like media from silenced domains. The attachment has no URL on this instance,
+// so clients are pointed at its remote URL instead.
+func (d *deref) putUncachedMedia(ctx context.Context, a *gtsmodel.MediaAttachment) (*gtsmodel.MediaAttachment, error) {
+	attachmentID, err := id.NewULID()
+	if err != nil {
+		return nil, fmt.Errorf("putUncachedMedia: error creating id: %s", err)
+	}
+
+	a.ID = attachmentID
+	a.CreatedAt = time.Now()
+	a.UpdatedAt = time.Now()
+	a.URL = ""
+	a.Processing = gtsmodel.ProcessingStatusProcessed
+	a.Cached = false
+
+	if err := d.db.Put(ctx, a); err != nil {
+		return nil, fmt.Errorf("putUncachedMedia: error putting attachment: %s", err)
+	}
+
+	return a, nil
+}
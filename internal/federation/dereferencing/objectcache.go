@@ -0,0 +1,151 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+const (
+	// objectCacheTTL is how long the json of a dereferenced remote actor or status is cached for,
+	// so that it isn't fetched over and over again while, for example, a busy thread is being viewed.
+	objectCacheTTL = 5 * time.Minute
+
+	// objectCacheGoneTTL is how long a remote server having said that an actor or status doesn't exist is cached for.
+	// This is longer than objectCacheTTL, since things that have been deleted are very unlikely to come back.
+	objectCacheGoneTTL = 30 * time.Minute
+
+	// objectCacheMaxEntries is the most results that are cached at once. Once there are this many,
+	// new results aren't cached until old ones expire, so that the cache can't grow without bound
+	// while, for example, a large instance is being crawled.
+	objectCacheMaxEntries = 10000
+)
+
+// cachedObject is the result of dereferencing a remote actor or status: either its json, or the error that the remote server responded with.
+type cachedObject struct {
+	b   []byte
+	err error
+}
+
+// newObjectCache returns a cache for the results of dereferencing remote actors and statuses. Entries aren't kept
+// alive by being used, so that nothing is served from the cache for longer than its TTL after it was fetched.
+func newObjectCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(objectCacheTTL)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
+// objectCacheKey returns the key that the result of dereferencing the given iri on behalf of the given user is cached with.
+// The username is part of the key since remote servers might show different things to different accounts.
+func objectCacheKey(username string, iri *url.URL) string {
+	return username + " " + iri.String()
+}
+
+// dereferenceObject does a GET to the given iri on behalf of the given user, and returns the json of the response.
+// The results of successful requests, and of requests for things that the remote server says don't exist, are cached,
+// so that the same thing isn't fetched again from the remote server until the cached result expires.
+//
+// If refresh is true, a cached result isn't used, and the thing is fetched from the remote server again.
+//
+// Things that have been tombstoned, because their owner told us that they were deleted, are never fetched again;
+// a 410 error is returned for them straight away instead. A remote server responding with 410 Gone or with a
+// Tombstone is only cached, since whoever answers for an iri isn't necessarily the one who owns it.
+func (d *deref) dereferenceObject(ctx context.Context, t transport.Transport, username string, iri *url.URL, refresh bool) ([]byte, error) {
+	key := objectCacheKey(username, iri)
+
+	if cached, ok := d.objectCache.Get(key); ok && !refresh {
+		if o, ok := cached.(*cachedObject); ok {
+			logrus.Tracef("dereferenceObject: using cached result for %s", iri)
+			return o.b, o.err
+		}
+	}
+
+	tombstoned, err := d.db.TombstoneExistsWithURI(ctx, iri.String())
+	if err != nil {
+		return nil, fmt.Errorf("dereferenceObject: error checking for tombstone: %s", err)
+	}
+	if tombstoned {
+		err := goneError(iri)
+		d.cacheObject(key, &cachedObject{err: err}, objectCacheGoneTTL)
+		return nil, err
+	}
+
+	b, err := t.Dereference(ctx, iri)
+	if err != nil {
+		var statusErr *transport.StatusError
+		if errors.As(err, &statusErr) && statusErr.Gone() {
+			d.cacheObject(key, &cachedObject{err: err}, objectCacheGoneTTL)
+		}
+		return nil, err
+	}
+
+	// some servers respond to requests for deleted things with a Tombstone rather than with 410 Gone
+	if isTombstone(b) {
+		err := goneError(iri)
+		d.cacheObject(key, &cachedObject{err: err}, objectCacheGoneTTL)
+		return nil, err
+	}
+
+	d.cacheObject(key, &cachedObject{b: b}, objectCacheTTL)
+	return b, nil
+}
+
+// cacheObject caches the given result with the given ttl, unless the cache is full.
+func (d *deref) cacheObject(key string, o *cachedObject, ttl time.Duration) {
+	if d.objectCache.Count() >= objectCacheMaxEntries {
+		// a result that's already cached can still be replaced by a fresher one
+		if _, ok := d.objectCache.Get(key); !ok {
+			return
+		}
+	}
+	d.objectCache.SetWithTTL(key, o, ttl)
+}
+
+// goneError returns the error that a remote server would have responded with if it had said that the given iri is gone.
+func goneError(iri *url.URL) error {
+	return &transport.StatusError{
+		Method:     http.MethodGet,
+		IRI:        iri.String(),
+		StatusCode: http.StatusGone,
+		Status:     "410 Gone",
+	}
+}
+
+// isTombstone returns true if the given json is of a Tombstone.
+func isTombstone(b []byte) bool {
+	t := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return false
+	}
+	return t.Type == ap.ObjectTombstone
+}
@@ -0,0 +1,141 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ObjectCacheTestSuite struct {
+	DereferencerStandardTestSuite
+}
+
+func (suite *ObjectCacheTestSuite) TestDereferenceGoneStatus() {
+	fetchingAccount := suite.testAccounts["local_account_1"]
+	statusURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person/gone/01FE4NTHKWW7THT67EF10EB839")
+
+	for i := 0; i < 3; i++ {
+		status, _, _, err := suite.dereferencer.GetRemoteStatus(context.Background(), fetchingAccount.Username, statusURL, false, false)
+		suite.Error(err)
+		suite.Nil(status)
+	}
+
+	// the remote server only had to tell us once that the status is gone
+	suite.Equal(1, suite.requests[statusURL.String()])
+}
+
+func (suite *ObjectCacheTestSuite) TestDereferenceGoneStatusNotTombstoned() {
+	statusURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person/gone/01FE4NTHKWW7THT67EF10EB839")
+
+	_, _, _, err := suite.dereferencer.GetRemoteStatus(context.Background(), suite.testAccounts["local_account_1"].Username, statusURL, false, false)
+	suite.Error(err)
+
+	// a 410 from whoever answered for the iri isn't proof that its owner deleted it
+	tombstoned, err := suite.db.TombstoneExistsWithURI(context.Background(), statusURL.String())
+	suite.NoError(err)
+	suite.False(tombstoned)
+}
+
+func (suite *ObjectCacheTestSuite) TestDereferenceTombstoned() {
+	fetchingAccount := suite.testAccounts["local_account_1"]
+	statusURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person/statuses/01FE4NTHKWW7THT67EF10EB839")
+
+	tombstoneID, err := id.NewULID()
+	suite.NoError(err)
+	err = suite.db.Put(context.Background(), &gtsmodel.Tombstone{
+		ID:     tombstoneID,
+		Domain: statusURL.Host,
+		URI:    statusURL.String(),
+	})
+	suite.NoError(err)
+
+	status, _, _, err := suite.dereferencer.GetRemoteStatus(context.Background(), fetchingAccount.Username, statusURL, false, false)
+	suite.Error(err)
+	suite.Nil(status)
+
+	// the status is known to be gone, so the remote server wasn't asked about it at all
+	suite.Equal(0, suite.requests[statusURL.String()])
+}
+
+func (suite *ObjectCacheTestSuite) TestDereferenceTombstone() {
+	statusURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person/statuses/01FE4NTHKWW7THT67EF10EB839")
+	suite.testRemoteJSON[statusURL.String()] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + statusURL.String() + `",
+		"type": "Tombstone"
+	}`
+
+	for i := 0; i < 3; i++ {
+		status, _, _, err := suite.dereferencer.GetRemoteStatus(context.Background(), suite.testAccounts["local_account_1"].Username, statusURL, false, false)
+		suite.Error(err)
+		suite.Nil(status)
+	}
+
+	// the Tombstone is cached like any other gone response, but nothing is stored for it
+	suite.Equal(1, suite.requests[statusURL.String()])
+	tombstoned, err := suite.db.TombstoneExistsWithURI(context.Background(), statusURL.String())
+	suite.NoError(err)
+	suite.False(tombstoned)
+}
+
+func (suite *ObjectCacheTestSuite) TestDereferenceAccountCached() {
+	ctx := context.Background()
+	fetchingAccount := suite.testAccounts["local_account_1"]
+	accountURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+
+	account, err := suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, accountURL, true, false)
+	suite.NoError(err)
+	suite.Equal(1, suite.requests[accountURL.String()])
+
+	// if the account has to be dereferenced again soon after, the cached copy is used
+	suite.NoError(suite.db.DeleteWhere(ctx, []db.Where{{Key: "uri", Value: account.URI}}, &gtsmodel.Account{}))
+	_, err = suite.dereferencer.GetRemoteAccount(ctx, fetchingAccount.Username, accountURL, true, false)
+	suite.NoError(err)
+	suite.Equal(1, suite.requests[accountURL.String()])
+
+	// someone else fetching the account gets their own copy
+	suite.NoError(suite.db.DeleteWhere(ctx, []db.Where{{Key: "uri", Value: accountURL.String()}}, &gtsmodel.Account{}))
+	_, err = suite.dereferencer.GetRemoteAccount(ctx, suite.testAccounts["local_account_2"].Username, accountURL, true, false)
+	suite.NoError(err)
+	suite.Equal(2, suite.requests[accountURL.String()])
+}
+
+func (suite *ObjectCacheTestSuite) TestRefreshAccountNotCached() {
+	fetchingAccount := suite.testAccounts["local_account_1"]
+	accountURL := testrig.URLMustParse("https://unknown-instance.com/users/brand_new_person")
+
+	// refreshing an account always fetches it again, since that's the point of refreshing it
+	for i := 1; i <= 3; i++ {
+		account, err := suite.dereferencer.GetRemoteAccount(context.Background(), fetchingAccount.Username, accountURL, true, true)
+		suite.NoError(err)
+		suite.Equal(accountURL.String(), account.URI)
+		suite.Equal(i, suite.requests[accountURL.String()])
+	}
+}
+
+func TestObjectCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(ObjectCacheTestSuite))
+}
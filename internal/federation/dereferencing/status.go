@@ -25,11 +25,13 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
@@ -78,7 +80,7 @@ func (d *deref) GetRemoteStatus(ctx context.Context, username string, remoteStat
 		}
 	}
 
-	statusable, err := d.dereferenceStatusable(ctx, username, remoteStatusID)
+	statusable, err := d.dereferenceStatusable(ctx, username, remoteStatusID, refresh)
 	if err != nil {
 		return nil, statusable, new, fmt.Errorf("GetRemoteStatus: error dereferencing statusable: %s", err)
 	}
@@ -120,6 +122,10 @@ func (d *deref) GetRemoteStatus(ctx context.Context, username string, remoteStat
 			return nil, statusable, new, fmt.Errorf("GetRemoteStatus: error populating status fields: %s", err)
 		}
 
+		if err := d.updateStatusPoll(ctx, maybeStatus, gtsStatus); err != nil {
+			return nil, statusable, new, fmt.Errorf("GetRemoteStatus: error updating status poll: %s", err)
+		}
+
 		if err := d.db.UpdateByPrimaryKey(ctx, gtsStatus); err != nil {
 			return nil, statusable, new, fmt.Errorf("GetRemoteStatus: error updating status: %s", err)
 		}
@@ -128,7 +134,43 @@ func (d *deref) GetRemoteStatus(ctx context.Context, username string, remoteStat
 	return gtsStatus, statusable, new, nil
 }
 
-func (d *deref) dereferenceStatusable(ctx context.Context, username string, remoteStatusID *url.URL) (ap.Statusable, error) {
+// updateStatusPoll stores the poll of a freshly dereferenced status that we've already seen before.
+// If we already have a poll for the status, it's updated with the new options and vote counts,
+// otherwise the new poll is inserted. Polls can't be removed from a status once it's been posted,
+// so if the new status doesn't have a poll, the existing poll (if any) is kept.
+func (d *deref) updateStatusPoll(ctx context.Context, existing *gtsmodel.Status, updated *gtsmodel.Status) error {
+	if updated.Poll == nil {
+		updated.PollID = existing.PollID
+		return nil
+	}
+
+	updated.Poll.StatusID = updated.ID
+
+	if existing.PollID == "" {
+		return d.db.Put(ctx, updated.Poll)
+	}
+
+	existingPoll, err := d.db.GetPollByID(ctx, existing.PollID)
+	if err != nil {
+		if err != db.ErrNoEntries {
+			return err
+		}
+		return d.db.Put(ctx, updated.Poll)
+	}
+
+	updated.Poll.ID = existingPoll.ID
+	updated.Poll.CreatedAt = existingPoll.CreatedAt
+	updated.Poll.UpdatedAt = time.Now()
+	updated.PollID = existingPoll.ID
+	if updated.Poll.ClosedAt.IsZero() {
+		// we might have closed the poll ourselves once it expired
+		updated.Poll.ClosedAt = existingPoll.ClosedAt
+	}
+
+	return d.db.UpdateByPrimaryKey(ctx, updated.Poll)
+}
+
+func (d *deref) dereferenceStatusable(ctx context.Context, username string, remoteStatusID *url.URL, refresh bool) (ap.Statusable, error) {
 	if blocked, err := d.db.IsDomainBlocked(ctx, remoteStatusID.Host); blocked || err != nil {
 		return nil, fmt.Errorf("DereferenceStatusable: domain %s is blocked", remoteStatusID.Host)
 	}
@@ -138,7 +180,7 @@ func (d *deref) dereferenceStatusable(ctx context.Context, username string, remo
 		return nil, fmt.Errorf("DereferenceStatusable: transport err: %s", err)
 	}
 
-	b, err := transport.Dereference(ctx, remoteStatusID)
+	b, err := d.dereferenceObject(ctx, transport, username, remoteStatusID, refresh)
 	if err != nil {
 		return nil, fmt.Errorf("DereferenceStatusable: error deferencing %s: %s", remoteStatusID.String(), err)
 	}
@@ -153,7 +195,7 @@ func (d *deref) dereferenceStatusable(ctx context.Context, username string, remo
 		return nil, fmt.Errorf("DereferenceStatusable: error resolving json into ap vocab type: %s", err)
 	}
 
-	// Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile
+	// Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile, Question
 	switch t.GetTypeName() {
 	case ap.ObjectArticle:
 		p, ok := t.(vocab.ActivityStreamsArticle)
@@ -209,6 +251,12 @@ func (d *deref) dereferenceStatusable(ctx context.Context, username string, remo
 			return nil, errors.New("DereferenceStatusable: error resolving type as ActivityStreamsProfile")
 		}
 		return p, nil
+	case ap.ActivityQuestion:
+		p, ok := t.(vocab.ActivityStreamsQuestion)
+		if !ok {
+			return nil, errors.New("DereferenceStatusable: error resolving type as ActivityStreamsQuestion")
+		}
+		return p, nil
 	}
 
 	return nil, fmt.Errorf("DereferenceStatusable: type name %s not supported", t.GetTypeName())
@@ -231,6 +279,7 @@ func (d *deref) dereferenceStatusable(ctx context.Context, username string, remo
 // 3. Emojis.
 // 4. Mentions.
 // 5. Replied-to-status.
+// 6. Quoted status.
 //
 // SIDE EFFECTS:
 // This function will deference all of the above, insert them in the database as necessary,
@@ -289,6 +338,14 @@ func (d *deref) populateStatusFields(ctx context.Context, status *gtsmodel.Statu
 		}
 	}
 
+	// 6. Quoted status (only if requested, for the same reason as the replied-to-status)
+	if includeParent {
+		// a quote that can't be fetched shouldn't stop the status itself from coming in
+		if err := d.populateStatusQuoted(ctx, status, requestingUsername); err != nil {
+			l.Errorf("populateStatusFields: error populating quoted status: %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -390,10 +447,33 @@ func (d *deref) populateStatusAttachments(ctx context.Context, status *gtsmodel.
 	attachmentIDs := []string{}
 	attachments := []*gtsmodel.MediaAttachment{}
 
+	statusIRI, err := url.Parse(status.URI)
+	if err != nil {
+		return fmt.Errorf("populateStatusAttachments: couldn't parse status URI %s: %s", status.URI, err)
+	}
+
+	// media from silenced domains, or domains whose media is rejected, is never cached, so it's just recorded with its remote URL
+	rejected, err := d.db.IsURIMediaRejected(ctx, statusIRI)
+	if err != nil {
+		return fmt.Errorf("populateStatusAttachments: error checking whether media from domain %s is rejected: %s", statusIRI.Host, err)
+	}
+
 	for _, a := range status.Attachments {
 		a.AccountID = status.AccountID
 		a.StatusID = status.ID
 
+		if rejected {
+			attachment, err := d.putUncachedMedia(ctx, a)
+			if err != nil {
+				logrus.Errorf("populateStatusAttachments: couldn't put uncached remote attachment %s: %s", a.RemoteURL, err)
+				continue
+			}
+
+			attachmentIDs = append(attachmentIDs, attachment.ID)
+			attachments = append(attachments, attachment)
+			continue
+		}
+
 		processingMedia, err := d.GetRemoteMedia(ctx, requestingUsername, a.AccountID, a.RemoteURL, &media.AdditionalMediaInfo{
 			CreatedAt:   &a.CreatedAt,
 			StatusID:    &a.StatusID,
@@ -448,3 +528,28 @@ func (d *deref) populateStatusRepliedTo(ctx context.Context, status *gtsmodel.St
 
 	return nil
 }
+
+func (d *deref) populateStatusQuoted(ctx context.Context, status *gtsmodel.Status, requestingUsername string) error {
+	if status.QuoteOfURI != "" && status.QuoteOfID == "" {
+		statusURI, err := url.Parse(status.QuoteOfURI)
+		if err != nil {
+			return err
+		}
+
+		// see if we have the status in our db already
+		quoteOf, err := d.db.GetStatusByURI(ctx, status.QuoteOfURI)
+		if err != nil {
+			// Status was not in the DB, try fetch
+			quoteOf, _, _, err = d.GetRemoteStatus(ctx, requestingUsername, statusURI, false, false)
+			if err != nil {
+				return fmt.Errorf("populateStatusQuoted: couldn't get quoted status with uri %s: %s", status.QuoteOfURI, err)
+			}
+		}
+
+		// we have the status
+		status.QuoteOfID = quoteOf.ID
+		status.QuoteOf = quoteOf
+	}
+
+	return nil
+}
@@ -22,14 +22,38 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"time"
 
+	"github.com/ReneKroon/ttlcache"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
+const (
+	// threadMaxDepth is how many levels of replies below a status are walked down through.
+	threadMaxDepth = 5
+	// threadMaxReplies is the most replies that are looked at in one walk down through a thread.
+	threadMaxReplies = 100
+	// threadMaxPages is the most pages of any one replies collection that are looked through.
+	threadMaxPages = 5
+	// threadWalkInterval is how long after the replies of a status have been walked before they'll be walked again.
+	threadWalkInterval = 10 * time.Minute
+	// threadWalkConcurrency is the most walks down through threads that are done at the same time.
+	threadWalkConcurrency = 4
+)
+
+// newThreadWalks returns a cache of the statuses whose replies have been walked in the last threadWalkInterval.
+func newThreadWalks() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(threadWalkInterval)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
 // DereferenceThread takes a statusable (something that has withReplies and withInReplyTo),
 // and dereferences statusables in the conversation.
 //
@@ -124,6 +148,10 @@ func (d *deref) iterateAncestors(ctx context.Context, username string, statusIRI
 	return d.iterateAncestors(ctx, username, *inReplyTo)
 }
 
+// iterateDescendants walks down through the replies collections of the given status and its replies, breadth first,
+// dereferencing and stashing any replies that we haven't seen before along the way. The walk is limited in depth and in
+// the number of replies looked at, and the replies of any one status are walked at most once every threadWalkInterval,
+// so that opening a thread over and over again, or a huge or misbehaving thread, can't get us to make loads of requests.
 func (d *deref) iterateDescendants(ctx context.Context, username string, statusIRI url.URL, statusable ap.Statusable) error {
 	l := logrus.WithFields(logrus.Fields{
 		"func":      "iterateDescendants",
@@ -139,106 +167,129 @@ func (d *deref) iterateDescendants(ctx context.Context, username string, statusI
 		return nil
 	}
 
-	replies := statusable.GetActivityStreamsReplies()
-	if replies == nil || !replies.IsActivityStreamsCollection() {
-		l.Debug("no replies, bailing")
-		return nil
-	}
-
-	repliesCollection := replies.GetActivityStreamsCollection()
-	if repliesCollection == nil {
-		l.Debug("replies collection is nil, bailing")
+	if _, walked := d.threadWalks.Get(statusIRI.String()); walked {
+		l.Debug("replies were walked recently, bailing")
 		return nil
 	}
 
-	first := repliesCollection.GetActivityStreamsFirst()
-	if first == nil {
-		l.Debug("replies collection has no first, bailing")
+	// don't pile up walks if lots of threads are opened at once
+	select {
+	case d.threadWalkSlots <- struct{}{}:
+		defer func() { <-d.threadWalkSlots }()
+	default:
+		l.Debug("too many threads being walked already, bailing")
 		return nil
 	}
+	d.threadWalks.Set(statusIRI.String(), struct{}{})
 
-	firstPage := first.GetActivityStreamsCollectionPage()
-	if firstPage == nil {
-		l.Debug("first has no collection page, bailing")
-		return nil
-	}
-
-	firstPageNext := firstPage.GetActivityStreamsNext()
-	if firstPageNext == nil || !firstPageNext.IsIRI() {
-		l.Debug("next is not an iri, bailing")
-		return nil
+	type descendant struct {
+		statusable ap.Statusable
+		depth      int
 	}
 
 	var foundReplies int
-	currentPageIRI := firstPageNext.GetIRI()
+	queue := []descendant{{statusable: statusable, depth: 0}}
 
-pageLoop:
-	for {
-		l.Debugf("dereferencing page %s", currentPageIRI)
-		nextPage, err := d.DereferenceCollectionPage(ctx, username, currentPageIRI)
-		if err != nil {
-			return err
-		}
-
-		// next items could be either a list of URLs or a list of statuses
-
-		nextItems := nextPage.GetActivityStreamsItems()
-		if nextItems.Len() == 0 {
-			// no items on this page, which means we're done
-			break pageLoop
-		}
+walkLoop:
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
 
-		// have a look through items and see what we can find
-		for iter := nextItems.Begin(); iter != nextItems.End(); iter = iter.Next() {
-			// We're looking for a url to feed to GetRemoteStatus.
-			// Items can be either an IRI, or a Note.
-			// If a note, we grab the ID from it and call it, rather than parsing the note.
-			var itemURI *url.URL
-			switch {
-			case iter.IsIRI():
-				// iri, easy
-				itemURI = iter.GetIRI()
-			case iter.IsActivityStreamsNote():
-				// note, get the id from it to use as iri
-				n := iter.GetActivityStreamsNote()
-				id := n.GetJSONLDId()
-				if id != nil && id.IsIRI() {
-					itemURI = id.GetIRI()
-				}
-			default:
-				// if it's not an iri or a note, we don't know how to process it
-				continue
+		for _, replyIRI := range d.statusReplyIRIs(ctx, username, current.statusable) {
+			if foundReplies >= threadMaxReplies {
+				l.Debug("reached max replies, bailing")
+				break walkLoop
 			}
 
-			host := viper.GetString(config.Keys.Host)
-			if itemURI.Host == host {
+			if replyIRI.Host == host {
 				// skip if the reply is from us -- we already have it then
 				continue
 			}
-
-			// we can confidently say now that we found something
 			foundReplies++
 
 			// get the remote statusable and put it in the db
-			_, statusable, new, err := d.GetRemoteStatus(ctx, username, itemURI, false, false)
-			if new && err == nil && statusable != nil {
-				// now iterate descendants of *that* status
-				if err := d.iterateDescendants(ctx, username, *itemURI, statusable); err != nil {
-					continue
-				}
+			_, replyStatusable, new, err := d.GetRemoteStatus(ctx, username, replyIRI, false, false)
+			if err != nil {
+				l.Debugf("error getting reply %s: %s", replyIRI, err)
+				continue
 			}
-		}
 
-		next := nextPage.GetActivityStreamsNext()
-		if next != nil && next.IsIRI() {
-			l.Debug("setting next page")
-			currentPageIRI = next.GetIRI()
-		} else {
-			l.Debug("no next page, bailing")
-			break pageLoop
+			// we only have the statusable, and so the replies collection, of statuses we've just dereferenced
+			if new && replyStatusable != nil && current.depth+1 < threadMaxDepth {
+				queue = append(queue, descendant{statusable: replyStatusable, depth: current.depth + 1})
+			}
 		}
 	}
 
 	l.Debugf("foundReplies %d", foundReplies)
 	return nil
 }
+
+// statusReplyIRIs returns the ids of the replies in the replies collection of the given statusable, going through
+// up to threadMaxPages pages of the collection. The collection, and its pages, can be either embedded or IRIs.
+func (d *deref) statusReplyIRIs(ctx context.Context, username string, statusable ap.Statusable) []*url.URL {
+	replies := statusable.GetActivityStreamsReplies()
+	if replies == nil {
+		return nil
+	}
+
+	var collection vocab.Type
+	if replies.IsIRI() {
+		c, err := d.dereferenceCollection(ctx, username, replies.GetIRI())
+		if err != nil {
+			logrus.Debugf("statusReplyIRIs: error dereferencing replies collection: %s", err)
+			return nil
+		}
+		collection = c
+	} else {
+		collection = replies.GetType()
+	}
+
+	if collection == nil {
+		return nil
+	}
+
+	// some collections have their items on the collection itself rather than on pages
+	iris := collectionItemIRIs(collection)
+
+	c, ok := collection.(collectionFirst)
+	if !ok || c.GetActivityStreamsFirst() == nil {
+		return iris
+	}
+
+	var page vocab.Type
+	first := c.GetActivityStreamsFirst()
+	if first.IsIRI() {
+		p, err := d.dereferenceCollection(ctx, username, first.GetIRI())
+		if err != nil {
+			logrus.Debugf("statusReplyIRIs: error dereferencing first page of replies: %s", err)
+			return iris
+		}
+		page = p
+	} else {
+		page = first.GetType()
+	}
+
+	for pages := 1; page != nil; pages++ {
+		iris = append(iris, collectionItemIRIs(page)...)
+
+		p, ok := page.(collectionPageNext)
+		if !ok || pages >= threadMaxPages {
+			break
+		}
+
+		next := p.GetActivityStreamsNext()
+		if next == nil || !next.IsIRI() {
+			break
+		}
+
+		nextPage, err := d.dereferenceCollection(ctx, username, next.GetIRI())
+		if err != nil {
+			logrus.Debugf("statusReplyIRIs: error dereferencing next page of replies: %s", err)
+			break
+		}
+		page = nextPage
+	}
+
+	return iris
+}
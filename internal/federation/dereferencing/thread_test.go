@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dereferencing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ThreadTestSuite struct {
+	DereferencerStandardTestSuite
+}
+
+const threadTestAuthor = "https://unknown-instance.com/users/brand_new_person"
+
+func threadTestNote(id string, inReplyTo string, replies string) string {
+	j := `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + id + `",
+		"type": "Note",
+		"attributedTo": "` + threadTestAuthor + `",
+		"to": ["https://www.w3.org/ns/activitystreams#Public"],
+		"published": "2022-06-01T12:00:00Z",
+		"url": "` + id + `",
+		"content": "<p>hello</p>"`
+	if inReplyTo != "" {
+		j += `,
+		"inReplyTo": "` + inReplyTo + `"`
+	}
+	if replies != "" {
+		j += `,
+		"replies": ` + replies
+	}
+	return j + `
+	}`
+}
+
+func (suite *ThreadTestSuite) TestDereferenceThreadReplies() {
+	ctx := context.Background()
+
+	rootURI := threadTestAuthor + "/statuses/root"
+	repliesURI := rootURI + "/replies"
+	secondPageURI := repliesURI + "?page=2"
+	reply1URI := threadTestAuthor + "/statuses/reply1"
+	reply2URI := threadTestAuthor + "/statuses/reply2"
+	reply3URI := threadTestAuthor + "/statuses/reply3"
+
+	// the replies of the root status are on two pages of a collection that has to be fetched
+	suite.testRemoteJSON[rootURI] = threadTestNote(rootURI, "", `"`+repliesURI+`"`)
+	suite.testRemoteJSON[repliesURI] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + repliesURI + `",
+		"type": "Collection",
+		"first": {
+			"id": "` + repliesURI + `?page=1",
+			"type": "CollectionPage",
+			"partOf": "` + repliesURI + `",
+			"next": "` + secondPageURI + `",
+			"items": ["` + reply1URI + `"]
+		}
+	}`
+	suite.testRemoteJSON[secondPageURI] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + secondPageURI + `",
+		"type": "CollectionPage",
+		"partOf": "` + repliesURI + `",
+		"items": ["` + reply2URI + `"]
+	}`
+
+	// the first reply has a reply of its own, in an embedded collection
+	suite.testRemoteJSON[reply1URI] = threadTestNote(reply1URI, rootURI, `{
+			"id": "`+reply1URI+`/replies",
+			"type": "OrderedCollection",
+			"orderedItems": ["`+reply3URI+`"]
+		}`)
+	suite.testRemoteJSON[reply2URI] = threadTestNote(reply2URI, rootURI, "")
+	suite.testRemoteJSON[reply3URI] = threadTestNote(reply3URI, reply1URI, "")
+
+	err := suite.dereferencer.DereferenceThread(ctx, suite.testAccounts["local_account_1"].Username, testrig.URLMustParse(rootURI))
+	suite.NoError(err)
+
+	for _, uri := range []string{rootURI, reply1URI, reply2URI, reply3URI} {
+		status, err := suite.db.GetStatusByURI(ctx, uri)
+		if suite.NoError(err, uri) {
+			suite.Equal(threadTestAuthor, status.Account.URI)
+		}
+	}
+
+	reply3, err := suite.db.GetStatusByURI(ctx, reply3URI)
+	suite.NoError(err)
+	suite.Equal(reply1URI, reply3.InReplyToURI)
+
+	// opening the thread again straight away doesn't walk the replies again
+	suite.testRemoteJSON[secondPageURI] = `{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "` + secondPageURI + `",
+		"type": "CollectionPage",
+		"partOf": "` + repliesURI + `",
+		"items": ["` + reply2URI + `", "` + threadTestAuthor + `/statuses/reply4"]
+	}`
+
+	err = suite.dereferencer.DereferenceThread(ctx, suite.testAccounts["local_account_1"].Username, testrig.URLMustParse(rootURI))
+	suite.NoError(err)
+
+	suite.Equal(1, suite.requests[secondPageURI])
+	suite.Zero(suite.requests[threadTestAuthor+"/statuses/reply4"])
+}
+
+func TestThreadTestSuite(t *testing.T) {
+	suite.Run(t, new(ThreadTestSuite))
+}
@@ -19,12 +19,16 @@
 package federation
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 )
 
 // federatingActor implements the go-fed federating protocol interface
@@ -74,6 +78,16 @@ func (f *federatingActor) Send(c context.Context, outbox *url.URL, t vocab.Type)
 // http.StatusMethodNotAllowed status code in the response. No side
 // effects occur.
 func (f *federatingActor) PostInbox(c context.Context, w http.ResponseWriter, r *http.Request) (bool, error) {
+	// hang on to the body, since integrity proofs have to be verified against exactly what was sent
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return false, fmt.Errorf("PostInbox: error reading request body: %s", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(b))
+		c = context.WithValue(c, ap.ContextActivityBody, b)
+	}
+
 	return f.actor.PostInbox(c, w, r)
 }
 
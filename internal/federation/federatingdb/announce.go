@@ -52,6 +52,13 @@ func (f *federatingDB) Announce(ctx context.Context, announce vocab.ActivityStre
 		return nil
 	}
 
+	// groups pass on all sorts of activities from their members by announcing them,
+	// but only the posts they pass on are something that can be shown as a boost
+	if _, err := ap.ExtractAnnounced(announce); err == ap.ErrAnnouncedActivity {
+		l.Debug("ignoring announce of an activity that isn't a create")
+		return nil
+	}
+
 	boost, isNew, err := f.typeConverter.ASAnnounceToStatus(ctx, announce)
 	if err != nil {
 		return fmt.Errorf("Announce: error converting announce to boost: %s", err)
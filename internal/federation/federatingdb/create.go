@@ -155,6 +155,11 @@ func (f *federatingDB) activityCreate(ctx context.Context, asType vocab.Type, re
 			if err := f.createNote(ctx, objectIter.GetActivityStreamsNote(), receivingAccount, requestingAccount); err != nil {
 				errs = append(errs, err.Error())
 			}
+		case ap.ActivityQuestion:
+			// CREATE A QUESTION (a note with a poll)
+			if err := f.createNote(ctx, objectIter.GetActivityStreamsQuestion(), receivingAccount, requestingAccount); err != nil {
+				errs = append(errs, err.Error())
+			}
 		default:
 			errs = append(errs, fmt.Sprintf("received an object on a Create that we couldn't handle: %s", asObjectType.GetTypeName()))
 		}
@@ -167,8 +172,11 @@ func (f *federatingDB) activityCreate(ctx context.Context, asType vocab.Type, re
 	return nil
 }
 
-// createNote handles a Create activity with a Note type.
-func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStreamsNote, receivingAccount *gtsmodel.Account, requestingAccount *gtsmodel.Account) error {
+// createNote handles a Create activity with a Note or Question type.
+//
+// Votes in polls are also sent as Notes, so if the note turns out to be a vote in a poll on this instance,
+// the vote will be counted instead of creating a new status.
+func (f *federatingDB) createNote(ctx context.Context, note ap.Statusable, receivingAccount *gtsmodel.Account, requestingAccount *gtsmodel.Account) error {
 	l := logrus.WithFields(logrus.Fields{
 		"func":              "createNote",
 		"receivingAccount":  receivingAccount.URI,
@@ -218,6 +226,16 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 
 	// if we reach this point, we know it's not a forwarded status, so proceed with processing it as normal
 
+	if voteNote, ok := note.(vocab.ActivityStreamsNote); ok && voteNote.GetActivityStreamsName() != nil {
+		isVote, err := f.createPollVote(ctx, voteNote, requestingAccount)
+		if err != nil {
+			return fmt.Errorf("createNote: error creating poll vote: %s", err)
+		}
+		if isVote {
+			return nil
+		}
+	}
+
 	status, err := f.typeConverter.ASStatusToStatus(ctx, note)
 	if err != nil {
 		return fmt.Errorf("createNote: error converting note to status: %s", err)
@@ -251,6 +269,88 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 	return nil
 }
 
+// createPollVote checks whether the given note is a vote in a poll on this instance, and stores the vote if it is.
+// The returned bool will be true if the note was a vote, even if the vote itself wasn't valid and so was ignored.
+func (f *federatingDB) createPollVote(ctx context.Context, note vocab.ActivityStreamsNote, requestingAccount *gtsmodel.Account) (bool, error) {
+	// a vote is named after the option it's for, and is in reply to the status with the poll
+	choiceName, err := ap.ExtractName(note)
+	if err != nil {
+		return false, nil
+	}
+
+	inReplyToURI := ap.ExtractInReplyToURI(note)
+	if inReplyToURI == nil {
+		return false, nil
+	}
+
+	status, err := f.db.GetStatusByURI(ctx, inReplyToURI.String())
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return false, nil
+		}
+		return false, fmt.Errorf("createPollVote: error getting status %s: %s", inReplyToURI, err)
+	}
+
+	if !status.Local || status.PollID == "" {
+		return false, nil
+	}
+
+	l := logrus.WithFields(logrus.Fields{
+		"func":              "createPollVote",
+		"requestingAccount": requestingAccount.URI,
+		"status":            status.URI,
+	})
+
+	poll, err := f.db.GetPollByID(ctx, status.PollID)
+	if err != nil {
+		return true, fmt.Errorf("createPollVote: error getting poll %s: %s", status.PollID, err)
+	}
+
+	if poll.Closed() {
+		l.Debug("ignoring vote in closed poll")
+		return true, nil
+	}
+
+	choice := -1
+	for i, option := range poll.Options {
+		if option == choiceName {
+			choice = i
+			break
+		}
+	}
+	if choice == -1 {
+		l.Debugf("ignoring vote for unknown option %s", choiceName)
+		return true, nil
+	}
+
+	voteID, err := id.NewULID()
+	if err != nil {
+		return true, err
+	}
+
+	vote := &gtsmodel.PollVote{
+		ID:        voteID,
+		AccountID: requestingAccount.ID,
+		PollID:    poll.ID,
+		Choice:    choice,
+	}
+	if noteID := note.GetJSONLDId(); noteID != nil && noteID.IsIRI() {
+		vote.URI = noteID.GetIRI().String()
+	}
+
+	if err := f.db.PutPollVote(ctx, vote); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if errors.As(err, &alreadyExistsError) {
+			// we've already counted this vote, or the account has already voted in a single choice poll
+			l.Debugf("ignoring vote: %s", err)
+			return true, nil
+		}
+		return true, fmt.Errorf("createPollVote: database error inserting vote: %s", err)
+	}
+
+	return true, nil
+}
+
 /*
 	FOLLOW HANDLERS
 */
@@ -296,6 +396,11 @@ func (f *federatingDB) activityLike(ctx context.Context, asType vocab.Type, rece
 		return errors.New("activityLike: could not convert type to like")
 	}
 
+	// a like with an emoji as its content is an emoji reaction rather than a plain like
+	if ap.ExtractReaction(like) != "" {
+		return f.activityReaction(ctx, like)
+	}
+
 	fave, err := f.typeConverter.ASLikeToFave(ctx, like)
 	if err != nil {
 		return fmt.Errorf("activityLike: could not convert Like to fave: %s", err)
@@ -320,3 +425,31 @@ func (f *federatingDB) activityLike(ctx context.Context, asType vocab.Type, rece
 
 	return nil
 }
+
+/*
+	REACTION HANDLERS
+*/
+
+func (f *federatingDB) activityReaction(ctx context.Context, reactable ap.Reactable) error {
+	reaction, err := f.typeConverter.ASLikeToReaction(ctx, reactable)
+	if err != nil {
+		return fmt.Errorf("activityReaction: could not convert Like to reaction: %s", err)
+	}
+
+	newID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+	reaction.ID = newID
+
+	if err := f.db.PutStatusReaction(ctx, reaction); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if errors.As(err, &alreadyExistsError) {
+			// the same reaction can be delivered to more than one inbox on this instance
+			return nil
+		}
+		return fmt.Errorf("activityReaction: database error inserting reaction: %s", err)
+	}
+
+	return nil
+}
@@ -20,10 +20,14 @@ package federatingdb_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
@@ -83,6 +87,84 @@ func (suite *CreateTestSuite) TestCreateNoteForward() {
 	suite.Equal("http://example.org/users/some_user/statuses/afaba698-5740-4e32-a702-af61aa543bc1", msg.APIri.String())
 }
 
+func (suite *CreateTestSuite) TestCreatePollVote() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_1"]
+
+	// put a status with a poll in the db for the remote account to vote in
+	pollStatus := &gtsmodel.Status{}
+	*pollStatus = *suite.testStatuses["local_account_1_status_1"]
+	pollStatus.ID = "01G1XEF8KB2T5HZ5P2NQM3B7YB"
+	pollStatus.URI = "http://localhost:8080/users/the_mighty_zork/statuses/01G1XEF8KB2T5HZ5P2NQM3B7YB"
+	pollStatus.URL = "http://localhost:8080/@the_mighty_zork/statuses/01G1XEF8KB2T5HZ5P2NQM3B7YB"
+	pollStatus.ActivityStreamsType = ap.ActivityQuestion
+	pollStatus.Poll = &gtsmodel.Poll{
+		ID:        "01G1XEFK3C1QPWKQ7TQ2X8HJ6V",
+		Options:   []string{"yes", "no"},
+		Votes:     []int{0, 0},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	err := suite.db.PutStatus(context.Background(), pollStatus)
+	suite.NoError(err)
+
+	m := make(map[string]interface{})
+	err = json.Unmarshal([]byte(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/users/foss_satan#votes/1/activity",
+		"type": "Create",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"to": "http://localhost:8080/users/the_mighty_zork",
+		"object": {
+			"id": "http://fossbros-anonymous.io/users/foss_satan#votes/1",
+			"type": "Note",
+			"name": "no",
+			"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+			"to": "http://localhost:8080/users/the_mighty_zork",
+			"inReplyTo": "http://localhost:8080/users/the_mighty_zork/statuses/01G1XEF8KB2T5HZ5P2NQM3B7YB"
+		}
+	}`), &m)
+	suite.NoError(err)
+
+	create, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	ctx := createTestContext(receivingAccount, requestingAccount)
+	err = suite.federatingDB.Create(ctx, create)
+	suite.NoError(err)
+
+	// the vote should have been counted, without creating a new status
+	poll, err := suite.db.GetPollByID(context.Background(), pollStatus.PollID)
+	suite.NoError(err)
+	suite.Equal([]int{0, 1}, poll.Votes)
+	suite.Equal(1, poll.VotersCount)
+
+	votes, err := suite.db.GetPollVotes(context.Background(), poll.ID)
+	suite.NoError(err)
+	suite.Len(votes, 1)
+	suite.Equal(requestingAccount.ID, votes[0].AccountID)
+	suite.Equal(1, votes[0].Choice)
+	suite.Equal("http://fossbros-anonymous.io/users/foss_satan#votes/1", votes[0].URI)
+
+	_, err = suite.db.GetStatusByURI(context.Background(), "http://fossbros-anonymous.io/users/foss_satan#votes/1")
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	// voting for the other option as well isn't allowed, since it's a single choice poll
+	m["id"] = "http://fossbros-anonymous.io/users/foss_satan#votes/2/activity"
+	object := m["object"].(map[string]interface{})
+	object["id"] = "http://fossbros-anonymous.io/users/foss_satan#votes/2"
+	object["name"] = "yes"
+
+	create, err = streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	err = suite.federatingDB.Create(ctx, create)
+	suite.NoError(err)
+
+	poll, err = suite.db.GetPollByID(context.Background(), pollStatus.PollID)
+	suite.NoError(err)
+	suite.Equal([]int{0, 1}, poll.Votes)
+}
+
 func TestCreateTestSuite(t *testing.T) {
 	suite.Run(t, &CreateTestSuite{})
 }
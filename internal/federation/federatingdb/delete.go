@@ -20,12 +20,17 @@ package federatingdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 )
 
@@ -44,7 +49,7 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 	)
 	l.Debug("entering Delete")
 
-	receivingAccount, _ := extractFromCtx(ctx)
+	receivingAccount, requestingAccount := extractFromCtx(ctx)
 	if receivingAccount == nil {
 		// If the receiving account wasn't set on the context, that means this request didn't pass
 		// through the API, but came from inside GtS as the result of another activity on this instance. That being so,
@@ -52,11 +57,27 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 		return nil
 	}
 
+	if requestingAccount == nil {
+		return errors.New("DELETE: requesting account wasn't set on the context")
+	}
+
+	// nobody gets to delete things on someone else's server
+	requestingAccountURI, err := url.Parse(requestingAccount.URI)
+	if err != nil {
+		return fmt.Errorf("DELETE: error parsing requesting account uri %s: %s", requestingAccount.URI, err)
+	}
+	if id.Host != requestingAccountURI.Host {
+		return fmt.Errorf("DELETE: delete of %s was requested by account %s, this is not valid", id, requestingAccount.URI)
+	}
+
 	// in a delete we only get the URI, we can't know if we have a status or a profile or something else,
 	// so we have to try a few different things...
 	s, err := f.db.GetStatusByURI(ctx, id.String())
 	if err == nil {
 		// it's a status
+		if s.AccountID != requestingAccount.ID {
+			return fmt.Errorf("DELETE: status %s was requested to be deleted by account %s, who doesn't own it", s.URI, requestingAccount.URI)
+		}
 		l.Debugf("uri is for status with id: %s", s.ID)
 		if err := f.db.DeleteByID(ctx, s.ID, &gtsmodel.Status{}); err != nil {
 			return fmt.Errorf("DELETE: err deleting status: %s", err)
@@ -72,6 +93,9 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 	a, err := f.db.GetAccountByURI(ctx, id.String())
 	if err == nil {
 		// it's an account
+		if a.ID != requestingAccount.ID {
+			return fmt.Errorf("DELETE: account %s was requested to be deleted by account %s", a.URI, requestingAccount.URI)
+		}
 		l.Debugf("uri is for an account with id %s, passing delete message to the processor", a.ID)
 		f.fedWorker.Queue(messages.FromFederator{
 			APObjectType:     ap.ObjectProfile,
@@ -81,5 +105,32 @@ func (f *federatingDB) Delete(ctx context.Context, id *url.URL) error {
 		})
 	}
 
+	// whatever it was, it came from its own server, so make sure we never go and fetch it again
+	f.putTombstone(ctx, id)
+
 	return nil
 }
+
+// putTombstone stores a tombstone for the given remote iri, so that it isn't dereferenced again.
+func (f *federatingDB) putTombstone(ctx context.Context, iri *url.URL) {
+	if iri.Host == viper.GetString(config.Keys.Host) {
+		return
+	}
+
+	tombstoneID, err := id.NewULID()
+	if err != nil {
+		logrus.Errorf("putTombstone: error generating id: %s", err)
+		return
+	}
+
+	if err := f.db.Put(ctx, &gtsmodel.Tombstone{
+		ID:     tombstoneID,
+		Domain: iri.Host,
+		URI:    iri.String(),
+	}); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if !errors.As(err, &alreadyExistsError) {
+			logrus.Errorf("putTombstone: error storing tombstone for %s: %s", iri, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DeleteTestSuite struct {
+	FederatingDBTestSuite
+}
+
+func (suite *DeleteTestSuite) TestDeleteStatus() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_1"]
+	deletedStatus := suite.testStatuses["remote_account_1_status_1"]
+	ctx := createTestContext(receivingAccount, requestingAccount)
+
+	err := suite.federatingDB.Delete(ctx, testrig.URLMustParse(deletedStatus.URI))
+	suite.NoError(err)
+
+	// the status shouldn't ever be fetched again
+	tombstoned, err := suite.db.TombstoneExistsWithURI(context.Background(), deletedStatus.URI)
+	suite.NoError(err)
+	suite.True(tombstoned)
+
+	// deleting it again is fine
+	err = suite.federatingDB.Delete(ctx, testrig.URLMustParse(deletedStatus.URI))
+	suite.NoError(err)
+}
+
+func (suite *DeleteTestSuite) TestDeleteStatusNotOwned() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_2"]
+	deletedStatus := suite.testStatuses["remote_account_1_status_1"]
+	ctx := createTestContext(receivingAccount, requestingAccount)
+
+	err := suite.federatingDB.Delete(ctx, testrig.URLMustParse(deletedStatus.URI))
+	suite.Error(err)
+
+	// the status is still there, and nothing was tombstoned
+	_, err = suite.db.GetStatusByID(context.Background(), deletedStatus.ID)
+	suite.NoError(err)
+
+	tombstoned, err := suite.db.TombstoneExistsWithURI(context.Background(), deletedStatus.URI)
+	suite.NoError(err)
+	suite.False(tombstoned)
+}
+
+func TestDeleteTestSuite(t *testing.T) {
+	suite.Run(t, new(DeleteTestSuite))
+}
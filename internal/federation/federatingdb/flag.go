@@ -0,0 +1,76 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+// Flag handles a remote instance reporting one of our accounts, and optionally some of its
+// statuses. The flag is stored as a report, for the moderators of this instance to look at.
+func (f *federatingDB) Flag(ctx context.Context, flag vocab.ActivityStreamsFlag) error {
+	l := logrus.WithFields(
+		logrus.Fields{
+			"func": "Flag",
+		},
+	)
+
+	if logrus.GetLevel() >= logrus.DebugLevel {
+		i, err := marshalItem(flag)
+		if err != nil {
+			return err
+		}
+		l = l.WithField("flag", i)
+		l.Debug("entering Flag")
+	}
+
+	receivingAccount, requestingAccount := extractFromCtx(ctx)
+	if receivingAccount == nil || requestingAccount == nil {
+		// If the receiving account wasn't set on the context, that means this request didn't pass
+		// through the API, but came from inside GtS as the result of another activity on this instance. That being so,
+		// we can safely just ignore this activity, since we know we've already processed it elsewhere.
+		return nil
+	}
+
+	actorIRI, err := ap.ExtractActor(flag)
+	if err != nil {
+		return fmt.Errorf("Flag: error extracting actor: %s", err)
+	}
+
+	if actorIRI.String() != requestingAccount.URI {
+		return fmt.Errorf("Flag: flag by %s was delivered by account %s, this is not valid", actorIRI, requestingAccount.URI)
+	}
+
+	report, err := f.typeConverter.ASFlagToReport(ctx, flag)
+	if err != nil {
+		return fmt.Errorf("Flag: could not convert flag to report: %s", err)
+	}
+
+	if err := f.db.Put(ctx, report); err != nil {
+		return fmt.Errorf("Flag: database error inserting report: %s", err)
+	}
+
+	l.Infof("stored report %s about account %s from %s", report.ID, report.TargetAccountID, requestingAccount.URI)
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type FlagTestSuite struct {
+	FederatingDBTestSuite
+}
+
+func (suite *FlagTestSuite) flag(s string) vocab.ActivityStreamsFlag {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(s), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+	flag, ok := t.(vocab.ActivityStreamsFlag)
+	suite.True(ok)
+	return flag
+}
+
+func (suite *FlagTestSuite) TestFlag() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_1"]
+	reportedStatus := suite.testStatuses["local_account_1_status_1"]
+	ctx := createTestContext(receivingAccount, requestingAccount)
+
+	flag := suite.flag(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/01G1Y3A5V1RGD3FRJM55BSBWSN",
+		"type": "Flag",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"object": [
+			"http://localhost:8080/users/the_mighty_zork",
+			"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+			"http://fossbros-anonymous.io/users/foss_satan/statuses/01FVW7JHQFSFK166WWKR8CBA6M"
+		],
+		"content": "misinformation"
+	}`)
+
+	err := suite.federatingDB.Flag(ctx, flag)
+	suite.NoError(err)
+
+	report := &gtsmodel.Report{}
+	err = suite.db.GetWhere(context.Background(), []db.Where{{Key: "uri", Value: "http://fossbros-anonymous.io/01G1Y3A5V1RGD3FRJM55BSBWSN"}}, report)
+	suite.NoError(err)
+	suite.Equal(requestingAccount.ID, report.AccountID)
+	suite.Equal(receivingAccount.ID, report.TargetAccountID)
+	// the status that isn't by the reported account should be dropped
+	suite.Equal([]string{reportedStatus.ID}, report.StatusIDs)
+	suite.Equal("misinformation", report.Comment)
+	suite.False(report.Forwarded)
+}
+
+func (suite *FlagTestSuite) TestFlagWrongActor() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_2"]
+	ctx := createTestContext(receivingAccount, requestingAccount)
+
+	flag := suite.flag(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/01G1Y3F3TJG1VA3KZ5RDDGYJJ9",
+		"type": "Flag",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"object": "http://localhost:8080/users/the_mighty_zork"
+	}`)
+
+	// the flag was delivered by someone other than its actor, so it should be refused
+	err := suite.federatingDB.Flag(ctx, flag)
+	suite.Error(err)
+}
+
+func TestFlagTestSuite(t *testing.T) {
+	suite.Run(t, &FlagTestSuite{})
+}
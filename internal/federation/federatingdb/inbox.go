@@ -28,6 +28,7 @@ import (
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
@@ -78,6 +79,10 @@ func (f *federatingDB) SetInbox(c context.Context, inbox vocab.ActivityStreamsOr
 // then each follower inbox IRI should be returned in the inboxIRIs slice.
 //
 // The library makes this call only after acquiring a lock first.
+//
+// Implementation note: followers on the same remote instance are collapsed down
+// to that instance's shared inbox where they have one, so that an activity for
+// followers is only posted once per instance rather than once per follower.
 func (f *federatingDB) InboxesForIRI(c context.Context, iri *url.URL) (inboxIRIs []*url.URL, err error) {
 	// check if this is a followers collection iri for a local account...
 	if iri.Host == viper.GetString(config.Keys.Host) && uris.IsFollowersPath(iri) {
@@ -96,6 +101,7 @@ func (f *federatingDB) InboxesForIRI(c context.Context, iri *url.URL) (inboxIRIs
 			return nil, fmt.Errorf("couldn't get followers of local account %s: %s", localAccountUsername, err)
 		}
 
+		seen := make(map[string]bool, len(follows))
 		for _, follow := range follows {
 			// make sure we retrieved the following account from the db
 			if follow.Account == nil {
@@ -109,10 +115,16 @@ func (f *federatingDB) InboxesForIRI(c context.Context, iri *url.URL) (inboxIRIs
 				follow.Account = followingAccount
 			}
 
-			inboxIRI, err := url.Parse(follow.Account.InboxURI)
+			inboxIRI, err := deliveryInbox(follow.Account)
 			if err != nil {
-				return nil, fmt.Errorf("error parsing inbox uri of following account %s: %s", follow.Account.InboxURI, err)
+				return nil, err
+			}
+
+			// followers sharing an inbox only need the activity delivered once
+			if seen[inboxIRI.String()] {
+				continue
 			}
+			seen[inboxIRI.String()] = true
 			inboxIRIs = append(inboxIRIs, inboxIRI)
 		}
 		return inboxIRIs, nil
@@ -135,3 +147,25 @@ func (f *federatingDB) InboxesForIRI(c context.Context, iri *url.URL) (inboxIRIs
 	// no error, we just didn't find anything so let the library handle the rest
 	return nil, nil
 }
+
+// deliveryInbox returns the inbox that activities addressed to the followers of a local account should be
+// delivered to for the given follower. This is the shared inbox of the follower's instance if it has one
+// on the same host as the follower's own inbox, or the follower's own inbox otherwise.
+func deliveryInbox(account *gtsmodel.Account) (*url.URL, error) {
+	inboxIRI, err := url.Parse(account.InboxURI)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing inbox uri of following account %s: %s", account.InboxURI, err)
+	}
+
+	if account.SharedInboxURI == "" {
+		return inboxIRI, nil
+	}
+
+	sharedInboxIRI, err := url.Parse(account.SharedInboxURI)
+	if err != nil || sharedInboxIRI.Host != inboxIRI.Host {
+		// don't trust a shared inbox that would send the activity somewhere else
+		return inboxIRI, nil
+	}
+
+	return sharedInboxIRI, nil
+}
@@ -0,0 +1,109 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type InboxTestSuite struct {
+	FederatingDBTestSuite
+}
+
+func (suite *InboxTestSuite) TestInboxesForFollowersSharedInbox() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["local_account_2"]
+
+	// foss_satan's instance has a shared inbox...
+	remoteAccount1 := &gtsmodel.Account{}
+	*remoteAccount1 = *suite.testAccounts["remote_account_1"]
+	remoteAccount1.SharedInboxURI = "http://fossbros-anonymous.io/inbox"
+	_, err := suite.db.UpdateAccount(ctx, remoteAccount1)
+	suite.NoError(err)
+
+	// ...as does another account on the same instance
+	remoteAccount3 := &gtsmodel.Account{}
+	*remoteAccount3 = *remoteAccount1
+	remoteAccount3.ID = "01G1TR6BADACCTJSY4XNSVG6KW"
+	remoteAccount3.Username = "foss_satan_2"
+	remoteAccount3.URI = "http://fossbros-anonymous.io/users/foss_satan_2"
+	remoteAccount3.URL = "http://fossbros-anonymous.io/@foss_satan_2"
+	remoteAccount3.InboxURI = "http://fossbros-anonymous.io/users/foss_satan_2/inbox"
+	remoteAccount3.OutboxURI = "http://fossbros-anonymous.io/users/foss_satan_2/outbox"
+	remoteAccount3.FollowersURI = "http://fossbros-anonymous.io/users/foss_satan_2/followers"
+	remoteAccount3.FollowingURI = "http://fossbros-anonymous.io/users/foss_satan_2/following"
+	remoteAccount3.FeaturedCollectionURI = "http://fossbros-anonymous.io/users/foss_satan_2/collections/featured"
+	remoteAccount3.PublicKeyURI = "http://fossbros-anonymous.io/users/foss_satan_2/main-key"
+	suite.NoError(suite.db.Put(ctx, remoteAccount3))
+
+	// ...but the shared inbox of this account points to a different host, so it shouldn't be used
+	remoteAccount2 := &gtsmodel.Account{}
+	*remoteAccount2 = *suite.testAccounts["remote_account_2"]
+	remoteAccount2.SharedInboxURI = "http://fossbros-anonymous.io/inbox"
+	_, err = suite.db.UpdateAccount(ctx, remoteAccount2)
+	suite.NoError(err)
+
+	for i, follower := range []*gtsmodel.Account{remoteAccount1, remoteAccount3, remoteAccount2} {
+		suite.NoError(suite.db.Put(ctx, &gtsmodel.Follow{
+			ID:              []string{"01G1TR8AF1N3XW7GYD6Q4XG2H1", "01G1TR8AF1N3XW7GYD6Q4XG2H2", "01G1TR8AF1N3XW7GYD6Q4XG2H3"}[i],
+			AccountID:       follower.ID,
+			TargetAccountID: testAccount.ID,
+			URI:             follower.URI + "/follows/" + testAccount.ID,
+		}))
+	}
+
+	inboxes, err := suite.federatingDB.InboxesForIRI(ctx, testrig.URLMustParse(testAccount.FollowersURI))
+	suite.NoError(err)
+
+	inboxStrings := []string{}
+	for _, inbox := range inboxes {
+		inboxStrings = append(inboxStrings, inbox.String())
+	}
+
+	suite.ElementsMatch([]string{
+		"http://localhost:8080/users/the_mighty_zork/inbox",
+		"http://fossbros-anonymous.io/inbox",
+		"http://example.org/users/some_user/inbox",
+	}, inboxStrings)
+}
+
+func (suite *InboxTestSuite) TestInboxesForAccountIgnoresSharedInbox() {
+	ctx := context.Background()
+
+	remoteAccount := &gtsmodel.Account{}
+	*remoteAccount = *suite.testAccounts["remote_account_1"]
+	remoteAccount.SharedInboxURI = "http://fossbros-anonymous.io/inbox"
+	_, err := suite.db.UpdateAccount(ctx, remoteAccount)
+	suite.NoError(err)
+
+	// activities addressed directly to an account should still go to its own inbox
+	inboxes, err := suite.federatingDB.InboxesForIRI(ctx, testrig.URLMustParse(remoteAccount.URI))
+	suite.NoError(err)
+	suite.Len(inboxes, 1)
+	suite.Equal(remoteAccount.InboxURI, inboxes[0].String())
+}
+
+func TestInboxTestSuite(t *testing.T) {
+	suite.Run(t, &InboxTestSuite{})
+}
@@ -67,21 +67,25 @@ func (f *federatingDB) Reject(ctx context.Context, reject vocab.ActivityStreamsR
 			rejectedObjectIRI := iter.GetIRI()
 			if uris.IsFollowPath(rejectedObjectIRI) {
 				// REJECT FOLLOW
+				// the follow might still be a request, or it might have been accepted already
+				where := []db.Where{{Key: "uri", Value: rejectedObjectIRI.String()}}
+				var accountID, targetAccountID string
 				gtsFollowRequest := &gtsmodel.FollowRequest{}
-				if err := f.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: rejectedObjectIRI.String()}}, gtsFollowRequest); err != nil {
-					return fmt.Errorf("Reject: couldn't get follow request with id %s from the database: %s", rejectedObjectIRI.String(), err)
+				gtsFollow := &gtsmodel.Follow{}
+				if err := f.db.GetWhere(ctx, where, gtsFollowRequest); err == nil {
+					accountID, targetAccountID = gtsFollowRequest.AccountID, gtsFollowRequest.TargetAccountID
+				} else if err := f.db.GetWhere(ctx, where, gtsFollow); err == nil {
+					accountID, targetAccountID = gtsFollow.AccountID, gtsFollow.TargetAccountID
+				} else {
+					return fmt.Errorf("Reject: couldn't get follow or follow request with id %s from the database: %s", rejectedObjectIRI.String(), err)
 				}
 
 				// make sure the addressee of the original follow is the same as whatever inbox this landed in
-				if gtsFollowRequest.AccountID != receivingAccount.ID {
+				if accountID != receivingAccount.ID {
 					return errors.New("Reject: follow object account and inbox account were not the same")
 				}
 
-				if _, err := f.db.RejectFollowRequest(ctx, gtsFollowRequest.AccountID, gtsFollowRequest.TargetAccountID); err != nil {
-					return err
-				}
-
-				return nil
+				return f.rejectFollow(ctx, accountID, targetAccountID)
 			}
 		}
 
@@ -106,13 +110,31 @@ func (f *federatingDB) Reject(ctx context.Context, reject vocab.ActivityStreamsR
 			if gtsFollow.AccountID != receivingAccount.ID {
 				return errors.New("Reject: follow object account and inbox account were not the same")
 			}
-			if _, err := f.db.RejectFollowRequest(ctx, gtsFollow.AccountID, gtsFollow.TargetAccountID); err != nil {
-				return err
-			}
 
-			return nil
+			return f.rejectFollow(ctx, gtsFollow.AccountID, gtsFollow.TargetAccountID)
 		}
 	}
 
 	return nil
 }
+
+// rejectFollow rejects the follow request from the given account to the given target account or, if the target had
+// accepted it already, removes the follow, along with the account's endorsement of the target, since accounts can
+// only be featured by their followers.
+func (f *federatingDB) rejectFollow(ctx context.Context, accountID string, targetAccountID string) error {
+	if _, err := f.db.RejectFollowRequest(ctx, accountID, targetAccountID); err != db.ErrNoEntries {
+		return err
+	}
+
+	where := []db.Where{
+		{Key: "account_id", Value: accountID},
+		{Key: "target_account_id", Value: targetAccountID},
+	}
+	if err := f.db.DeleteWhere(ctx, where, &gtsmodel.Follow{}); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("Reject: db error removing follow: %s", err)
+	}
+	if err := f.db.DeleteWhere(ctx, where, &gtsmodel.Endorsement{}); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("Reject: db error removing endorsement: %s", err)
+	}
+	return nil
+}
@@ -89,6 +89,46 @@ func (suite *RejectTestSuite) TestRejectFollowRequest() {
 	suite.ErrorIs(err, db.ErrNoEntries)
 }
 
+func (suite *RejectTestSuite) TestRejectAcceptedFollow() {
+	// local_account_1 follows remote_account_2, and features them on its profile;
+	// remote_account_2 removes local_account_1 from its followers by rejecting the follow
+	followingAccount := suite.testAccounts["local_account_1"]
+	followedAccount := suite.testAccounts["remote_account_2"]
+	ctx := createTestContext(followingAccount, followedAccount)
+
+	follow := &gtsmodel.Follow{
+		ID:              "01FJ1S8DX3STJJ6CEYPMZ1M0R3",
+		URI:             uris.GenerateURIForFollow(followingAccount.Username, "01FJ1S8DX3STJJ6CEYPMZ1M0R3"),
+		AccountID:       followingAccount.ID,
+		TargetAccountID: followedAccount.ID,
+	}
+	suite.NoError(suite.db.Put(ctx, follow))
+	endorsement := &gtsmodel.Endorsement{
+		ID:              "01FJ1S8DX3STJJ6CEYPMZ1M0R4",
+		AccountID:       followingAccount.ID,
+		TargetAccountID: followedAccount.ID,
+	}
+	suite.NoError(suite.db.Put(ctx, endorsement))
+
+	// reject the follow by its uri
+	reject := streams.NewActivityStreamsReject()
+	rejectActorProp := streams.NewActivityStreamsActorProperty()
+	rejectActorProp.AppendIRI(testrig.URLMustParse(followedAccount.URI))
+	reject.SetActivityStreamsActor(rejectActorProp)
+	rejectObject := streams.NewActivityStreamsObjectProperty()
+	rejectObject.AppendIRI(testrig.URLMustParse(follow.URI))
+	reject.SetActivityStreamsObject(rejectObject)
+
+	err := suite.federatingDB.Reject(ctx, reject)
+	suite.NoError(err)
+
+	// the follow and the endorsement that depended on it should both be gone
+	err = suite.db.GetByID(ctx, follow.ID, &gtsmodel.Follow{})
+	suite.ErrorIs(err, db.ErrNoEntries)
+	err = suite.db.GetByID(ctx, endorsement.ID, &gtsmodel.Endorsement{})
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
 func TestRejectTestSuite(t *testing.T) {
 	suite.Run(t, &RejectTestSuite{})
 }
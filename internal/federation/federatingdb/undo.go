@@ -46,7 +46,7 @@ func (f *federatingDB) Undo(ctx context.Context, undo vocab.ActivityStreamsUndo)
 		l.Debug("entering Undo")
 	}
 
-	receivingAccount, _ := extractFromCtx(ctx)
+	receivingAccount, requestingAccount := extractFromCtx(ctx)
 	if receivingAccount == nil {
 		// If the receiving account wasn't set on the context, that means this request didn't pass
 		// through the API, but came from inside GtS as the result of another activity on this instance. That being so,
@@ -95,6 +95,31 @@ func (f *federatingDB) Undo(ctx context.Context, undo vocab.ActivityStreamsUndo)
 			return nil
 		case ap.ActivityLike:
 			// UNDO LIKE
+			ASLike, ok := iter.GetType().(vocab.ActivityStreamsLike)
+			if !ok {
+				return errors.New("UNDO: couldn't parse like into vocab.ActivityStreamsLike")
+			}
+			// make sure the actor owns the like
+			if !sameActor(undo.GetActivityStreamsActor(), ASLike.GetActivityStreamsActor()) {
+				return errors.New("UNDO: like actor and activity actor not the same")
+			}
+			// only emoji reactions are undone for now
+			if ap.ExtractReaction(ASLike) == "" {
+				return nil
+			}
+			if requestingAccount == nil {
+				return errors.New("UNDO: requesting account wasn't set on the context")
+			}
+			likeID := ASLike.GetJSONLDId()
+			if likeID == nil || !likeID.IsIRI() {
+				return errors.New("UNDO: no id set on like, or was not an iri")
+			}
+			// delete any existing REACTION
+			if err := f.db.DeleteStatusReactions(ctx, []db.Where{{Key: "uri", Value: likeID.GetIRI().String()}, {Key: "account_id", Value: requestingAccount.ID}}); err != nil {
+				return fmt.Errorf("UNDO: db error removing reaction: %s", err)
+			}
+			l.Debug("reaction undone")
+			return nil
 		case ap.ActivityAnnounce:
 			// UNDO BOOST/REBLOG/ANNOUNCE
 		case ap.ActivityBlock:
@@ -0,0 +1,89 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+)
+
+type UndoTestSuite struct {
+	FederatingDBTestSuite
+}
+
+func (suite *UndoTestSuite) TestUndoReaction() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_1"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+	ctx := createTestContext(receivingAccount, requestingAccount)
+
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/users/foss_satan/undo/01G1XGH2C55V3SF5DKSWEN8ZKA",
+		"type": "Undo",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"object": {
+			"id": "http://fossbros-anonymous.io/users/foss_satan/reactions/01G1XGGM6MAZ7PZ2D3XKCN3VAT",
+			"type": "Like",
+			"actor": "http://fossbros-anonymous.io/users/foss_satan",
+			"object": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+			"content": "🦊"
+		}
+	}`), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+	undo, ok := t.(vocab.ActivityStreamsUndo)
+	suite.True(ok)
+
+	// the like being undone is an emoji reaction, so it should be stored as one rather than as a fave
+	like := undo.GetActivityStreamsObject().Begin().GetActivityStreamsLike()
+	err = suite.federatingDB.Create(ctx, like)
+	suite.NoError(err)
+
+	reactions, err := suite.db.GetStatusReactions(context.Background(), targetStatus)
+	suite.NoError(err)
+	suite.Len(reactions, 1)
+	suite.Equal("🦊", reactions[0].Emoji)
+	suite.Equal(requestingAccount.ID, reactions[0].AccountID)
+	suite.Equal(receivingAccount.ID, reactions[0].TargetAccountID)
+
+	faved, err := suite.db.IsStatusFavedBy(context.Background(), targetStatus, requestingAccount.ID)
+	suite.NoError(err)
+	suite.False(faved)
+
+	// now undo it
+	err = suite.federatingDB.Undo(ctx, undo)
+	suite.NoError(err)
+
+	reactions, err = suite.db.GetStatusReactions(context.Background(), targetStatus)
+	suite.NoError(err)
+	suite.Empty(reactions)
+}
+
+func TestUndoTestSuite(t *testing.T) {
+	suite.Run(t, &UndoTestSuite{})
+}
@@ -22,12 +22,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 )
@@ -75,6 +77,30 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 	}
 
 	typeName := asType.GetTypeName()
+	if typeName == ap.ActivityQuestion {
+		// it's an UPDATE to a poll, most likely because votes were cast in it or it was closed
+		l.Debug("got update for QUESTION")
+		question, ok := asType.(vocab.ActivityStreamsQuestion)
+		if !ok {
+			return errors.New("UPDATE: could not convert type to question")
+		}
+		if err := f.updatePoll(ctx, question, requestingAcct); err != nil {
+			return err
+		}
+		// the content of the poll might have been edited too
+		return f.updateStatus(ctx, question, requestingAcct)
+	}
+
+	if typeName == ap.ObjectNote || typeName == ap.ObjectArticle {
+		// it's an UPDATE to a status, because it was edited by its author
+		l.Debug("got update for NOTE/ARTICLE")
+		statusable, ok := asType.(ap.Statusable)
+		if !ok {
+			return errors.New("UPDATE: could not convert type to statusable")
+		}
+		return f.updateStatus(ctx, statusable, requestingAcct)
+	}
+
 	if typeName == ap.ActorApplication ||
 		typeName == ap.ActorGroup ||
 		typeName == ap.ActorOrganization ||
@@ -158,3 +184,117 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 
 	return nil
 }
+
+// updatePoll updates the options, vote counts and closing time of the remote poll
+// represented by the given question, if it's a poll that we already know about.
+func (f *federatingDB) updatePoll(ctx context.Context, pollable ap.Pollable, requestingAcct *gtsmodel.Account) error {
+	idProp := pollable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return errors.New("UPDATE: question had no id, or id was not an iri")
+	}
+
+	status, err := f.db.GetStatusByURI(ctx, idProp.GetIRI().String())
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// we don't know this status, so there's nothing to update
+			return nil
+		}
+		return fmt.Errorf("UPDATE: error getting status %s: %s", idProp.GetIRI(), err)
+	}
+
+	if status.Local || status.PollID == "" {
+		// we're the source of truth for local polls, and there's nothing to update if the status has no poll
+		return nil
+	}
+
+	if requestingAcct.URI != status.AccountURI {
+		return fmt.Errorf("UPDATE: update for question %s was requested by account %s, this is not valid", status.URI, requestingAcct.URI)
+	}
+
+	existingPoll, err := f.db.GetPollByID(ctx, status.PollID)
+	if err != nil {
+		return fmt.Errorf("UPDATE: error getting poll %s: %s", status.PollID, err)
+	}
+
+	updatedPoll, err := ap.ExtractPoll(pollable)
+	if err != nil {
+		return fmt.Errorf("UPDATE: error extracting poll: %s", err)
+	}
+
+	updatedPoll.ID = existingPoll.ID
+	updatedPoll.CreatedAt = existingPoll.CreatedAt
+	updatedPoll.UpdatedAt = time.Now()
+	updatedPoll.StatusID = existingPoll.StatusID
+	if updatedPoll.ClosedAt.IsZero() {
+		// we might have closed the poll ourselves once it expired
+		updatedPoll.ClosedAt = existingPoll.ClosedAt
+	}
+
+	if err := f.db.UpdateByPrimaryKey(ctx, updatedPoll); err != nil {
+		return fmt.Errorf("UPDATE: database error updating poll: %s", err)
+	}
+
+	return nil
+}
+
+// updateStatus keeps the current version of the remote status represented by the given statusable as
+// a previous version, and then updates the status with its new content, if it's a status that we already
+// know about and its content has actually changed.
+func (f *federatingDB) updateStatus(ctx context.Context, statusable ap.Statusable, requestingAcct *gtsmodel.Account) error {
+	idProp := statusable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return errors.New("UPDATE: status had no id, or id was not an iri")
+	}
+
+	status, err := f.db.GetStatusByURI(ctx, idProp.GetIRI().String())
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// we don't know this status, so there's nothing to update
+			return nil
+		}
+		return fmt.Errorf("UPDATE: error getting status %s: %s", idProp.GetIRI(), err)
+	}
+
+	if status.Local {
+		// we're the source of truth for local statuses
+		return nil
+	}
+
+	if requestingAcct.URI != status.AccountURI {
+		return fmt.Errorf("UPDATE: update for status %s was requested by account %s, this is not valid", status.URI, requestingAcct.URI)
+	}
+
+	// a status with no content or content warning is fine, so errors extracting them can be ignored
+	content, _ := ap.ExtractContent(statusable)
+	contentWarning, _ := ap.ExtractSummary(statusable)
+	sensitive := ap.ExtractSensitive(statusable)
+
+	if content == status.Content && contentWarning == status.ContentWarning && sensitive == status.Sensitive {
+		// nothing that we keep track of has changed, eg., the vote counts of a poll were updated
+		return nil
+	}
+
+	edit, err := f.typeConverter.StatusToEdit(ctx, status)
+	if err != nil {
+		return fmt.Errorf("UPDATE: error creating status edit: %s", err)
+	}
+
+	status.Content = content
+	status.ContentWarning = contentWarning
+	status.Sensitive = sensitive
+	status.UpdatedAt = time.Now()
+	status.EditedAt = ap.ExtractUpdated(statusable)
+	if status.EditedAt.IsZero() {
+		status.EditedAt = status.UpdatedAt
+	}
+
+	if err := f.db.Put(ctx, edit); err != nil {
+		return fmt.Errorf("UPDATE: database error putting status edit: %s", err)
+	}
+
+	if err := f.db.UpdateStatus(ctx, status); err != nil {
+		return fmt.Errorf("UPDATE: database error updating status: %s", err)
+	}
+
+	return nil
+}
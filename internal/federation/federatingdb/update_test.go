@@ -0,0 +1,82 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federatingdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type UpdateTestSuite struct {
+	FederatingDBTestSuite
+}
+
+func (suite *UpdateTestSuite) TestUpdateNote() {
+	receivingAccount := suite.testAccounts["local_account_1"]
+	requestingAccount := suite.testAccounts["remote_account_1"]
+	targetStatus := suite.testStatuses["remote_account_1_status_1"]
+
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/users/foss_satan/statuses/01FVW7JHQFSFK166WWKR8CBA6M",
+		"type": "Note",
+		"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+		"to": "https://www.w3.org/ns/activitystreams#Public",
+		"content": "dark souls status bot: \"thoughts of cat\"",
+		"summary": "dark souls",
+		"sensitive": true,
+		"published": "2021-09-20T10:40:37Z",
+		"updated": "2022-04-13T12:00:00Z"
+	}`), &m)
+	suite.NoError(err)
+
+	note, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	ctx := createTestContext(receivingAccount, requestingAccount)
+	err = suite.federatingDB.Update(ctx, note)
+	suite.NoError(err)
+
+	// the status should have been updated in place
+	status, err := suite.db.GetStatusByID(context.Background(), targetStatus.ID)
+	suite.NoError(err)
+	suite.Equal("dark souls status bot: \"thoughts of cat\"", status.Content)
+	suite.Equal("dark souls", status.ContentWarning)
+	suite.True(status.Sensitive)
+	suite.Equal(testrig.TimeMustParse("2022-04-13T12:00:00Z"), status.EditedAt.UTC())
+
+	// and the previous version should have been kept
+	edits, err := suite.db.GetStatusEdits(context.Background(), status)
+	suite.NoError(err)
+	suite.Len(edits, 1)
+	suite.Equal(targetStatus.Content, edits[0].Content)
+	suite.Empty(edits[0].ContentWarning)
+	suite.False(edits[0].Sensitive)
+	suite.Equal(targetStatus.AttachmentIDs, edits[0].AttachmentIDs)
+}
+
+func TestUpdateTestSuite(t *testing.T) {
+	suite.Run(t, &UpdateTestSuite{})
+}
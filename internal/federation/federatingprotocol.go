@@ -19,11 +19,17 @@
 package federation
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/activity/pub"
@@ -132,6 +138,34 @@ func (f *federator) AuthenticatePostInbox(ctx context.Context, w http.ResponseWr
 		}
 	}
 
+	// refuse the request if the sending domain has been posting too much, so that it can't flood our processing workers;
+	// this is done after authentication so that one domain can't use up the limit of another by faking where requests are from
+	if result := f.inboxLimiter.Allow(strings.ToLower(publicKeyOwnerURI.Host), time.Now()); !result.Allowed {
+		inboxRequests.WithLabelValues(inboxLimited).Inc()
+		l.Debugf("domain %s is over the inbox rate limit", publicKeyOwnerURI.Host)
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return ctx, false, nil
+	}
+
+	// drop the activity if the admin has refused its type from the sending domain, or from the domain of its actor if it was
+	// forwarded; like a forwarded activity that can't be verified, the sender is told it was accepted so that it doesn't retry
+	if body, ok := ctx.Value(ap.ContextActivityBody).([]byte); ok {
+		if activity, err := decodeRawActivity(body); err == nil {
+			domains := []string{publicKeyOwnerURI.Host}
+			if activity.actor != nil {
+				domains = append(domains, activity.actor.Host)
+			}
+			if f.activityPolicy.refuses(activity.typeName, domains...) {
+				inboxRequests.WithLabelValues(inboxRefused).Inc()
+				l.Debugf("refusing %s activity from %s", activity.typeName, publicKeyOwnerURI)
+				w.WriteHeader(http.StatusAccepted)
+				return ctx, false, nil
+			}
+		}
+	}
+	inboxRequests.WithLabelValues(inboxAllowed).Inc()
+
 	// authentication has passed, so add an instance entry for this instance if it hasn't been done already
 	i := &gtsmodel.Instance{}
 	if err := f.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: publicKeyOwnerURI.Host, CaseInsensitive: true}}, i); err != nil {
@@ -140,8 +174,8 @@ func (f *federator) AuthenticatePostInbox(ctx context.Context, w http.ResponseWr
 			return ctx, false, fmt.Errorf("error getting requesting account with public key id %s: %s", publicKeyOwnerURI.String(), err)
 		}
 
-		// we don't have an entry for this instance yet so dereference it
-		i, err = f.GetRemoteInstance(ctx, username, &url.URL{
+		// we don't have an entry for this instance yet so dereference it, using the instance actor
+		i, err = f.GetRemoteInstance(ctx, "", &url.URL{
 			Scheme: publicKeyOwnerURI.Scheme,
 			Host:   publicKeyOwnerURI.Host,
 		})
@@ -162,6 +196,50 @@ func (f *federator) AuthenticatePostInbox(ctx context.Context, w http.ResponseWr
 
 	withRequesting := context.WithValue(ctx, ap.ContextRequestingAccount, requestingAccount)
 	withReceiving := context.WithValue(withRequesting, ap.ContextReceivingAccount, receivingAccount)
+
+	// if the activity has an integrity proof, then it has to verify, even though the request itself is authenticated
+	if body, ok := ctx.Value(ap.ContextActivityBody).([]byte); ok {
+		proofOwnerURI, errWithCode := f.verifyIntegrityProof(withReceiving, username, body)
+		if errWithCode != nil {
+			switch errWithCode.Code() {
+			case http.StatusUnauthorized, http.StatusForbidden:
+				l.Debugf("refusing activity from %s: %s", publicKeyOwnerURI, errWithCode)
+				w.WriteHeader(errWithCode.Code())
+				return ctx, false, nil
+			default:
+				return ctx, false, errWithCode
+			}
+		}
+		if proofOwnerURI != nil {
+			withReceiving = context.WithValue(withReceiving, ap.ContextProofOwnerIRI, proofOwnerURI)
+		}
+
+		// if the activity was forwarded to us by someone other than its actor, make sure that it really is from its actor,
+		// and then process it as coming from them; if it can't be verified, then it's dropped, but since the forwarder did
+		// nothing wrong by passing it on, they're still told that it was accepted, so that they don't keep trying
+		if activity, err := decodeRawActivity(body); err == nil && activity.actor != nil && activity.actor.String() != publicKeyOwnerURI.String() {
+			verified, err := f.verifyForwardedActivity(withReceiving, username, activity, proofOwnerURI)
+			if err != nil {
+				l.Debugf("ignoring activity forwarded by %s: %s", publicKeyOwnerURI, err)
+				w.WriteHeader(http.StatusAccepted)
+				return ctx, false, nil
+			}
+
+			actorAccount, err := f.GetRemoteAccount(ctx, username, activity.actor, false, false)
+			if err != nil {
+				l.Debugf("ignoring activity forwarded by %s: couldn't get actor %s: %s", publicKeyOwnerURI, activity.actor, err)
+				w.WriteHeader(http.StatusAccepted)
+				return ctx, false, nil
+			}
+			withReceiving = context.WithValue(withReceiving, ap.ContextRequestingAccount, actorAccount)
+
+			if verified != nil {
+				r.Body = ioutil.NopCloser(bytes.NewReader(verified))
+				withReceiving = context.WithValue(withReceiving, ap.ContextActivityBody, verified)
+			}
+		}
+	}
+
 	return withReceiving, true, nil
 }
 
@@ -267,6 +345,12 @@ func (f *federator) FederatingCallbacks(ctx context.Context) (wrapped pub.Federa
 		func(ctx context.Context, announce vocab.ActivityStreamsAnnounce) error {
 			return f.FederatingDB().Announce(ctx, announce)
 		},
+		func(ctx context.Context, move vocab.ActivityStreamsMove) error {
+			return f.FederatingDB().Move(ctx, move)
+		},
+		func(ctx context.Context, flag vocab.ActivityStreamsFlag) error {
+			return f.FederatingDB().Flag(ctx, flag)
+		},
 	}
 
 	return
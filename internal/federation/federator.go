@@ -21,15 +21,20 @@ package federation
 import (
 	"context"
 	"net/url"
+	"time"
 
+	"github.com/ReneKroon/ttlcache"
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/federation/dereferencing"
 	"github.com/superseriousbusiness/gotosocial/internal/federation/federatingdb"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/ratelimit"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 )
@@ -54,11 +59,16 @@ type Federator interface {
 	AuthenticateFederatedRequest(ctx context.Context, username string) (*url.URL, gtserror.WithCode)
 
 	// FingerRemoteAccount performs a webfinger lookup for a remote account, using the .well-known path. It will return the ActivityPub URI for that
-	// account, or an error if it doesn't exist or can't be retrieved.
+	// account, or an error if it doesn't exist or can't be retrieved. Results are cached, and looked up again in the background every so often.
 	FingerRemoteAccount(ctx context.Context, requestingUsername string, targetUsername string, targetDomain string) (*url.URL, error)
+	// FingerRemoteAccounts performs webfinger lookups for many remote accounts at once, given as 'username@domain', and returns the ActivityPub
+	// URIs of the ones that could be looked up, keyed by how they were given.
+	FingerRemoteAccounts(ctx context.Context, requestingUsername string, targets []string) map[string]*url.URL
 
 	DereferenceRemoteThread(ctx context.Context, username string, statusURI *url.URL) error
 	DereferenceAnnounce(ctx context.Context, announce *gtsmodel.Status, requestingUsername string) error
+	// DereferenceFeatured fetches the featured collection of the given remote account, and marks the statuses in it as pinned.
+	DereferenceFeatured(ctx context.Context, username string, account *gtsmodel.Account) error
 
 	GetRemoteAccount(ctx context.Context, username string, remoteAccountID *url.URL, blocking bool, refresh bool) (*gtsmodel.Account, error)
 
@@ -82,6 +92,11 @@ type federator struct {
 	dereferencer        dereferencing.Dereferencer
 	mediaManager        media.Manager
 	actor               pub.FederatingActor
+	publicKeyCache      *ttlcache.Cache
+	proofOwnerRefetches *ttlcache.Cache
+	webfingers          *webfingers
+	inboxLimiter        *ratelimit.Limiter
+	activityPolicy      *activityPolicy
 }
 
 // NewFederator returns a new federator
@@ -97,6 +112,11 @@ func NewFederator(db db.DB, federatingDB federatingdb.DB, transportController tr
 		transportController: transportController,
 		dereferencer:        dereferencer,
 		mediaManager:        mediaManager,
+		publicKeyCache:      newPublicKeyCache(),
+		proofOwnerRefetches: newProofOwnerRefetchCache(),
+		webfingers:          newWebfingers(),
+		inboxLimiter:        ratelimit.New(viper.GetInt(config.Keys.FederationInboxRateLimit), time.Minute),
+		activityPolicy:      newActivityPolicy(viper.GetStringSlice(config.Keys.FederationRefuseActivities)),
 	}
 	actor := newFederatingActor(f, f, federatingDB, clock)
 	f.actor = actor
@@ -19,22 +19,37 @@
 package federation_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/go-fed/httpsig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/pub"
+	"github.com/superseriousbusiness/activity/streams"
 
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 	"github.com/superseriousbusiness/gotosocial/testrig"
@@ -43,7 +58,7 @@ import (
 type ProtocolTestSuite struct {
 	suite.Suite
 	db            db.DB
-	storage       *kv.KVStore
+	storage       *gtsstorage.Driver
 	typeConverter typeutils.TypeConverter
 	accounts      map[string]*gtsmodel.Account
 	activities    map[string]testrig.ActivityWithSignature
@@ -148,6 +163,432 @@ func (suite *ProtocolTestSuite) TestAuthenticatePostInbox() {
 	assert.Equal(suite.T(), sendingAccount.Username, requestingAccount.Username)
 }
 
+func (suite *ProtocolTestSuite) TestAuthenticatePostInboxRateLimited() {
+	activity := suite.activities["dm_for_zork"]
+	inboxAccount := suite.accounts["local_account_1"]
+
+	// let each domain post 2 requests a minute
+	viper.Set(config.Keys.FederationInboxRateLimit, 2)
+
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker)
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+	authenticate := func() (bool, *httptest.ResponseRecorder) {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users/the_mighty_zork/inbox", nil)
+		request.Header.Set("Signature", activity.SignatureHeader)
+		request.Header.Set("Date", activity.DateHeader)
+		request.Header.Set("Digest", activity.DigestHeader)
+
+		verifier, err := httpsig.NewVerifier(request)
+		suite.NoError(err)
+
+		ctx := context.WithValue(context.Background(), ap.ContextReceivingAccount, inboxAccount)
+		ctx = context.WithValue(ctx, ap.ContextActivity, activity)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeyVerifier, verifier)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeySignature, activity.SignatureHeader)
+
+		recorder := httptest.NewRecorder()
+		_, authed, err := federator.AuthenticatePostInbox(ctx, recorder, request)
+		suite.NoError(err)
+		return authed, recorder
+	}
+
+	limitedBefore := gatherValue(suite.T(), "gotosocial_federation_inbox_requests_total", "limited")
+
+	// the first two requests fit in the bucket
+	authed, _ := authenticate()
+	suite.True(authed)
+	authed, _ = authenticate()
+	suite.True(authed)
+
+	// but the third is refused, and the sender is told when to try again
+	authed, recorder := authenticate()
+	suite.False(authed)
+	suite.Equal(http.StatusTooManyRequests, recorder.Code)
+	suite.Equal("30", recorder.Header().Get("Retry-After"))
+	suite.EqualValues(limitedBefore+1, gatherValue(suite.T(), "gotosocial_federation_inbox_requests_total", "limited"))
+}
+
+func (suite *ProtocolTestSuite) TestAuthenticatePostInboxIntegrityProof() {
+	activity := suite.activities["dm_for_zork"]
+	inboxAccount := suite.accounts["local_account_1"]
+	sendingAccount := suite.accounts["remote_account_1"]
+
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker)
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+	// the activity is signed by its actor with the key from the assertionMethod of their actor
+	serialized, err := streams.Serialize(activity.Activity)
+	suite.NoError(err)
+	b, err := json.Marshal(serialized)
+	suite.NoError(err)
+	proved, added, err := ap.AddProof(b, sendingAccount.URI, sendingAccount.AssertionKeyURI, sendingAccount.AssertionPrivateKey, time.Now())
+	suite.NoError(err)
+	suite.True(added)
+
+	authenticate := func(body []byte) (context.Context, bool, *httptest.ResponseRecorder) {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users/the_mighty_zork/inbox", bytes.NewReader(body))
+		request.Header.Set("Signature", activity.SignatureHeader)
+		request.Header.Set("Date", activity.DateHeader)
+		request.Header.Set("Digest", activity.DigestHeader)
+
+		verifier, err := httpsig.NewVerifier(request)
+		suite.NoError(err)
+
+		ctx := context.WithValue(context.Background(), ap.ContextReceivingAccount, inboxAccount)
+		ctx = context.WithValue(ctx, ap.ContextActivity, activity)
+		ctx = context.WithValue(ctx, ap.ContextActivityBody, body)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeyVerifier, verifier)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeySignature, activity.SignatureHeader)
+
+		recorder := httptest.NewRecorder()
+		ctx, authed, err := federator.AuthenticatePostInbox(ctx, recorder, request)
+		suite.NoError(err)
+		return ctx, authed, recorder
+	}
+
+	// with a valid proof, the activity is known to be from its actor
+	ctx, authed, _ := authenticate(proved)
+	suite.True(authed)
+	suite.Equal(sendingAccount.URI, ctx.Value(ap.ContextProofOwnerIRI).(*url.URL).String())
+
+	// without a proof, the request is still authenticated by its signature
+	ctx, authed, _ = authenticate(b)
+	suite.True(authed)
+	suite.Nil(ctx.Value(ap.ContextProofOwnerIRI))
+
+	// but an activity that was changed after the proof was made is refused
+	tampered := bytes.Replace(proved, []byte(`hey zork here's a new private note for you`), []byte(`hey zork here's a new public note for you`), 1)
+	suite.NotEqual(proved, tampered)
+	_, authed, recorder := authenticate(tampered)
+	suite.False(authed)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+func (suite *ProtocolTestSuite) TestAuthenticatePostInboxRefusedActivity() {
+	activity := suite.activities["dm_for_zork"]
+	inboxAccount := suite.accounts["local_account_1"]
+
+	serialized, err := streams.Serialize(activity.Activity)
+	suite.NoError(err)
+	b, err := json.Marshal(serialized)
+	suite.NoError(err)
+
+	authenticate := func(refuse []string) (bool, *httptest.ResponseRecorder) {
+		viper.Set(config.Keys.FederationRefuseActivities, refuse)
+
+		fedWorker := worker.New[messages.FromFederator](-1, -1)
+		tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(nil), suite.db, fedWorker)
+		federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users/the_mighty_zork/inbox", bytes.NewReader(b))
+		request.Header.Set("Signature", activity.SignatureHeader)
+		request.Header.Set("Date", activity.DateHeader)
+		request.Header.Set("Digest", activity.DigestHeader)
+
+		verifier, err := httpsig.NewVerifier(request)
+		suite.NoError(err)
+
+		ctx := context.WithValue(context.Background(), ap.ContextReceivingAccount, inboxAccount)
+		ctx = context.WithValue(ctx, ap.ContextActivity, activity)
+		ctx = context.WithValue(ctx, ap.ContextActivityBody, b)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeyVerifier, verifier)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeySignature, activity.SignatureHeader)
+
+		recorder := httptest.NewRecorder()
+		_, authed, err := federator.AuthenticatePostInbox(ctx, recorder, request)
+		suite.NoError(err)
+		return authed, recorder
+	}
+
+	refusedBefore := gatherValue(suite.T(), "gotosocial_federation_inbox_requests_total", "refused")
+
+	// creates are accepted if only other types, or creates from other domains, are refused;
+	// fossbros-anonymous.io only shares a suffix with anonymous.io, it's not a subdomain of it
+	authed, _ := authenticate([]string{"Like", "Announce@fossbros-anonymous.io", "Create@example.org", "Create@anonymous.io"})
+	suite.True(authed)
+
+	// but refusing creates from the sending domain, or from everywhere, drops the activity
+	for _, refuse := range [][]string{{"create@fossbros-anonymous.io"}, {"Create"}} {
+		authed, recorder := authenticate(refuse)
+		suite.False(authed)
+		suite.Equal(http.StatusAccepted, recorder.Code)
+	}
+	suite.EqualValues(refusedBefore+2, gatherValue(suite.T(), "gotosocial_federation_inbox_requests_total", "refused"))
+}
+
+// signedContext returns a context holding the verifier and signature of a request to the_mighty_zork,
+// signed with the given ed25519 key. The signature names the given algorithm instead of hs2019.
+func (suite *ProtocolTestSuite) signedContext(ctx context.Context, privateKey ed25519.PrivateKey, keyID string, algorithm string) context.Context {
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/users/the_mighty_zork", nil)
+	request.Header.Set("Host", request.Host)
+	request.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	signer, _, err := httpsig.NewSigner([]httpsig.Algorithm{httpsig.ED25519}, httpsig.DigestSha256, []string{httpsig.RequestTarget, "host", "date"}, httpsig.Signature, 120)
+	suite.NoError(err)
+	suite.NoError(signer.SignRequest(privateKey, keyID, request, nil))
+	signature := strings.Replace(request.Header.Get("Signature"), `algorithm="hs2019"`, `algorithm="`+algorithm+`"`, 1)
+	request.Header.Set("Signature", signature)
+
+	verifier, err := httpsig.NewVerifier(request)
+	suite.NoError(err)
+
+	ctxWithVerifier := context.WithValue(ctx, ap.ContextRequestingPublicKeyVerifier, verifier)
+	return context.WithValue(ctxWithVerifier, ap.ContextRequestingPublicKeySignature, signature)
+}
+
+func (suite *ProtocolTestSuite) TestAuthenticateEd25519() {
+	ctx := context.Background()
+	sendingAccount := &gtsmodel.Account{}
+	*sendingAccount = *suite.accounts["remote_account_1"]
+
+	// swap the sending account's rsa key for an ed25519 one
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.NoError(err)
+	sendingAccount.PublicKey = nil
+	sendingAccount.PublicKeyEd25519 = publicKey
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, sendingAccount))
+
+	// keep track of the signature on the last request we sent out
+	var sentSignature string
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		sentSignature = req.Header.Get("Signature")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}), suite.db, fedWorker)
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+	authenticate := func(algorithm string) (*url.URL, gtserror.WithCode) {
+		return federator.AuthenticateFederatedRequest(suite.signedContext(ctx, privateKey, sendingAccount.PublicKeyURI, algorithm), "the_mighty_zork")
+	}
+
+	deref := func() {
+		t, err := tc.NewTransportForUsername(ctx, "the_mighty_zork")
+		suite.NoError(err)
+		_, err = t.Dereference(ctx, testrig.URLMustParse(sendingAccount.URI))
+		suite.NoError(err)
+	}
+
+	// hs2019 signatures have the algorithm worked out from the key
+	ownerURI, errWithCode := authenticate("hs2019")
+	suite.NoError(errWithCode)
+	suite.Equal(sendingAccount.URI, ownerURI.String())
+	deref()
+	suite.Contains(sentSignature, `algorithm="hs2019"`)
+
+	// signatures can name the algorithm instead, in which case we sign our requests to that host the same way
+	ownerURI, errWithCode = authenticate("ed25519")
+	suite.NoError(errWithCode)
+	suite.Equal(sendingAccount.URI, ownerURI.String())
+	deref()
+	suite.Contains(sentSignature, `algorithm="rsa-sha256"`)
+
+	// but the named algorithm has to match the key
+	_, errWithCode = authenticate("rsa-sha256")
+	suite.Error(errWithCode)
+}
+
+func (suite *ProtocolTestSuite) TestAuthenticateRotatedKey() {
+	ctx := context.Background()
+	sendingAccount := suite.accounts["remote_account_1"]
+
+	// the sending account has swapped its rsa key for an ed25519 one, but we don't know that yet
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.NoError(err)
+	encodedPublicKey, err := x509.MarshalPKIXPublicKey(publicKey)
+	suite.NoError(err)
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedPublicKey})
+
+	// serve the new key, and count how many times it's fetched
+	fetches := 0
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		fetches++
+		person, err := json.Marshal(map[string]interface{}{
+			"@context":          []string{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"},
+			"id":                sendingAccount.URI,
+			"type":              "Person",
+			"preferredUsername": sendingAccount.Username,
+			"publicKey": map[string]string{
+				"id":           sendingAccount.PublicKeyURI,
+				"owner":        sendingAccount.URI,
+				"publicKeyPem": string(publicKeyPem),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(person)),
+		}, nil
+	}), suite.db, fedWorker)
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+	hitsBefore := gatherValue(suite.T(), "gotosocial_federation_public_key_cache_lookups_total", "hit")
+	rotatedBefore := gatherValue(suite.T(), "gotosocial_federation_public_key_refetches_total", "rotated")
+
+	// the stored key doesn't verify the signature, so the key should be fetched again
+	ownerURI, errWithCode := federator.AuthenticateFederatedRequest(suite.signedContext(ctx, privateKey, sendingAccount.PublicKeyURI, "hs2019"), "the_mighty_zork")
+	suite.NoError(errWithCode)
+	suite.Equal(sendingAccount.URI, ownerURI.String())
+	suite.Equal(1, fetches)
+	suite.EqualValues(rotatedBefore+1, gatherValue(suite.T(), "gotosocial_federation_public_key_refetches_total", "rotated"))
+
+	// and the account should have the new key stored now
+	dbAccount, err := suite.db.GetAccountByID(ctx, sendingAccount.ID)
+	suite.NoError(err)
+	suite.Nil(dbAccount.PublicKey)
+	suite.EqualValues(publicKey, dbAccount.PublicKeyEd25519)
+
+	// the new key is cached, so it doesn't need to be fetched for the next request
+	ownerURI, errWithCode = federator.AuthenticateFederatedRequest(suite.signedContext(ctx, privateKey, sendingAccount.PublicKeyURI, "hs2019"), "the_mighty_zork")
+	suite.NoError(errWithCode)
+	suite.Equal(sendingAccount.URI, ownerURI.String())
+	suite.Equal(1, fetches)
+	suite.EqualValues(hitsBefore+1, gatherValue(suite.T(), "gotosocial_federation_public_key_cache_lookups_total", "hit"))
+
+	// a request signed with some other key fails, without the key being fetched again so soon
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.NoError(err)
+	_, errWithCode = federator.AuthenticateFederatedRequest(suite.signedContext(ctx, otherPrivateKey, sendingAccount.PublicKeyURI, "hs2019"), "the_mighty_zork")
+	suite.Error(errWithCode)
+	suite.Equal(1, fetches)
+}
+
+// gatherValue returns the value of the counter with the given name
+// and result label from the default prometheus gatherer.
+func gatherValue(t *testing.T, name string, result string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	// metric hasn't been observed yet
+	return 0
+}
+
+func (suite *ProtocolTestSuite) TestAuthenticatePostInboxForwarded() {
+	activity := suite.activities["dm_for_zork"]
+	inboxAccount := suite.accounts["local_account_1"]
+	forwardingAccount := suite.accounts["remote_account_1"]
+	actorAccount := suite.accounts["remote_account_2"]
+
+	// what the instance of the actor has to say about things
+	remote := map[string]string{
+		"http://example.org/users/some_user/statuses/01G20ZM733MGN8J344T4ZDDFY1": `{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"id": "http://example.org/users/some_user/statuses/01G20ZM733MGN8J344T4ZDDFY1",
+			"type": "Note",
+			"attributedTo": "http://example.org/users/some_user",
+			"to": "https://www.w3.org/ns/activitystreams#Public",
+			"content": "the genuine article"
+		}`,
+	}
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if b, ok := remote[req.URL.String()]; ok {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(b))}, nil
+		}
+		if strings.Contains(req.URL.Path, "/gone/") {
+			return &http.Response{StatusCode: http.StatusGone, Status: "410 Gone", Body: io.NopCloser(bytes.NewReader([]byte{}))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(bytes.NewReader([]byte{}))}, nil
+	}), suite.db, fedWorker)
+	federator := federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, suite.typeConverter, testrig.NewTestMediaManager(suite.db, suite.storage))
+
+	// the activities are all signed by foss_satan, who's passing on activities by some_user
+	authenticate := func(body string) (context.Context, bool, *httptest.ResponseRecorder, *http.Request) {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users/the_mighty_zork/inbox", strings.NewReader(body))
+		request.Header.Set("Signature", activity.SignatureHeader)
+		request.Header.Set("Date", activity.DateHeader)
+		request.Header.Set("Digest", activity.DigestHeader)
+
+		verifier, err := httpsig.NewVerifier(request)
+		suite.NoError(err)
+
+		ctx := context.WithValue(context.Background(), ap.ContextReceivingAccount, inboxAccount)
+		ctx = context.WithValue(ctx, ap.ContextActivityBody, []byte(body))
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeyVerifier, verifier)
+		ctx = context.WithValue(ctx, ap.ContextRequestingPublicKeySignature, activity.SignatureHeader)
+
+		recorder := httptest.NewRecorder()
+		ctx, authed, err := federator.AuthenticatePostInbox(ctx, recorder, request)
+		suite.NoError(err)
+		return ctx, authed, recorder, request
+	}
+
+	// a forwarded delete of something that's gone is processed as coming from its actor
+	ctx, authed, _, _ := authenticate(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://example.org/users/some_user#deletes/1",
+		"type": "Delete",
+		"actor": "http://example.org/users/some_user",
+		"object": "http://example.org/users/some_user/gone/01G20ZM733MGN8J344T4ZDDFY1"
+	}`)
+	suite.True(authed)
+	suite.Equal(actorAccount.URI, ctx.Value(ap.ContextRequestingAccount).(*gtsmodel.Account).URI)
+
+	// but a forwarded delete of something that's still there is dropped
+	_, authed, recorder, _ := authenticate(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://example.org/users/some_user#deletes/2",
+		"type": "Delete",
+		"actor": "http://example.org/users/some_user",
+		"object": "http://example.org/users/some_user/statuses/01G20ZM733MGN8J344T4ZDDFY1"
+	}`)
+	suite.False(authed)
+	suite.Equal(http.StatusAccepted, recorder.Code)
+
+	// a forwarded create has its object replaced with the one from the instance of its actor
+	ctx, authed, _, request := authenticate(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://example.org/users/some_user/statuses/01G20ZM733MGN8J344T4ZDDFY1/activity",
+		"type": "Create",
+		"actor": "http://example.org/users/some_user",
+		"object": {
+			"id": "http://example.org/users/some_user/statuses/01G20ZM733MGN8J344T4ZDDFY1",
+			"type": "Note",
+			"attributedTo": "http://example.org/users/some_user",
+			"to": "https://www.w3.org/ns/activitystreams#Public",
+			"content": "a forgery"
+		}
+	}`)
+	suite.True(authed)
+	suite.Equal(actorAccount.URI, ctx.Value(ap.ContextRequestingAccount).(*gtsmodel.Account).URI)
+	b, err := io.ReadAll(request.Body)
+	suite.NoError(err)
+	suite.Contains(string(b), "the genuine article")
+	suite.NotContains(string(b), "a forgery")
+
+	// an activity delivered by its own actor doesn't need checking
+	ctx, authed, _, _ = authenticate(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/users/foss_satan#deletes/1",
+		"type": "Delete",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"object": "http://fossbros-anonymous.io/users/foss_satan/statuses/01G20ZM733MGN8J344T4ZDDFY1"
+	}`)
+	suite.True(authed)
+	suite.Equal(forwardingAccount.URI, ctx.Value(ap.ContextRequestingAccount).(*gtsmodel.Account).URI)
+}
+
 func TestProtocolTestSuite(t *testing.T) {
 	suite.Run(t, new(ProtocolTestSuite))
 }
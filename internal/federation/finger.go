@@ -23,17 +23,210 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/ReneKroon/ttlcache"
+	"github.com/sirupsen/logrus"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 )
 
+const (
+	// webfingerCacheTTL is how long a webfinger result is cached for after it was last used.
+	webfingerCacheTTL = 24 * time.Hour
+
+	// webfingerRevalidateAfter is how long a webfinger result is used for before it's looked up again in the background.
+	// A random amount of time up to webfingerRevalidateJitter is added on, so that results that were cached at the same
+	// time, like all the accounts mentioned in one post, aren't all looked up again at the same time.
+	webfingerRevalidateAfter  = 1 * time.Hour
+	webfingerRevalidateJitter = 30 * time.Minute
+
+	// webfingerBatchConcurrency is the most webfinger lookups that FingerRemoteAccounts does at the same time.
+	webfingerBatchConcurrency = 8
+)
+
+// webfingerResult is the account URI that a webfinger lookup for an account resolved to.
+type webfingerResult struct {
+	accountURI   *url.URL
+	revalidateAt time.Time // when the lookup should be done again
+}
+
+// webfingerCall is a webfinger lookup that's in progress. Anyone else who wants the same
+// account looked up while it's in progress waits for it to be done, rather than doing it again.
+type webfingerCall struct {
+	done       chan struct{}
+	accountURI *url.URL
+	err        error
+}
+
+// webfingers caches webfinger results, and keeps track of the lookups that are in progress.
+type webfingers struct {
+	cache    *ttlcache.Cache
+	mu       sync.Mutex
+	inFlight map[string]*webfingerCall
+}
+
+// newWebfingers returns a webfinger cache. Entries are kept alive by being used, and the
+// ones that are used get looked up again every webfingerRevalidateAfter or so.
+func newWebfingers() *webfingers {
+	c := ttlcache.NewCache()
+	c.SetTTL(webfingerCacheTTL)
+	return &webfingers{
+		cache:    c,
+		inFlight: make(map[string]*webfingerCall),
+	}
+}
+
+// webfingerKey returns the key that the webfinger result for the given account is cached with.
+func webfingerKey(targetUsername string, targetDomain string) string {
+	return strings.ToLower(targetUsername + "@" + targetDomain)
+}
+
+// newWebfingerResult returns a webfinger result for the given account URI, that's due to be revalidated after a jittered interval.
+func newWebfingerResult(accountURI *url.URL) *webfingerResult {
+	jitter := time.Duration(rand.Int63n(int64(webfingerRevalidateJitter)))
+	return &webfingerResult{
+		accountURI:   accountURI,
+		revalidateAt: time.Now().Add(webfingerRevalidateAfter + jitter),
+	}
+}
+
 func (f *federator) FingerRemoteAccount(ctx context.Context, requestingUsername string, targetUsername string, targetDomain string) (*url.URL, error) {
 	if blocked, err := f.db.IsDomainBlocked(ctx, targetDomain); blocked || err != nil {
 		return nil, fmt.Errorf("FingerRemoteAccount: domain %s is blocked", targetDomain)
 	}
 
+	key := webfingerKey(targetUsername, targetDomain)
+
+	if cached, ok := f.webfingers.cache.Get(key); ok {
+		if result, ok := cached.(*webfingerResult); ok {
+			if time.Now().After(result.revalidateAt) {
+				// use the cached result this time, but look it up again in the background
+				// so that any change to the account URI is picked up for next time
+				go f.revalidateWebfinger(key, requestingUsername, targetUsername, targetDomain, result)
+			}
+			return result.accountURI, nil
+		}
+	}
+
+	call, started := f.startWebfinger(key)
+	if !started {
+		select {
+		case <-call.done:
+			return call.accountURI, call.err
+		case <-ctx.Done():
+			return nil, fmt.Errorf("FingerRemoteAccount: gave up waiting for webfinger lookup of @%s@%s: %s", targetUsername, targetDomain, ctx.Err())
+		}
+	}
+
+	accountURI, err := f.fingerRemoteAccount(ctx, requestingUsername, targetUsername, targetDomain)
+	if err == nil {
+		f.webfingers.cache.Set(key, newWebfingerResult(accountURI))
+	}
+	f.finishWebfinger(key, call, accountURI, err)
+
+	return accountURI, err
+}
+
+// FingerRemoteAccounts looks up many accounts at once, given as 'username@domain', and returns the account URIs
+// of the ones that could be looked up, keyed by how they were given. The lookups are done in parallel, and
+// any account that's given more than once is only looked up once.
+func (f *federator) FingerRemoteAccounts(ctx context.Context, requestingUsername string, targets []string) map[string]*url.URL {
+	accountURIs := make(map[string]*url.URL, len(targets))
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, webfingerBatchConcurrency)
+
+	seen := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+
+		username, domain, ok := strings.Cut(strings.TrimPrefix(target, "@"), "@")
+		if !ok || username == "" || domain == "" {
+			logrus.Debugf("FingerRemoteAccounts: %s isn't a valid remote account", target)
+			continue
+		}
+
+		wg.Add(1)
+		go func(target string, username string, domain string) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+
+			accountURI, err := f.FingerRemoteAccount(ctx, requestingUsername, username, domain)
+			if err != nil {
+				logrus.Debugf("FingerRemoteAccounts: couldn't look up %s: %s", target, err)
+				return
+			}
+
+			mu.Lock()
+			accountURIs[target] = accountURI
+			mu.Unlock()
+		}(target, username, domain)
+	}
+
+	wg.Wait()
+	return accountURIs
+}
+
+// startWebfinger returns the in-progress lookup for the given key, and true if the caller has to do the lookup
+// itself because there wasn't one in progress already. If true is returned, finishWebfinger must be called.
+func (f *federator) startWebfinger(key string) (*webfingerCall, bool) {
+	f.webfingers.mu.Lock()
+	defer f.webfingers.mu.Unlock()
+
+	if call, ok := f.webfingers.inFlight[key]; ok {
+		return call, false
+	}
+
+	call := &webfingerCall{done: make(chan struct{})}
+	f.webfingers.inFlight[key] = call
+	return call, true
+}
+
+// finishWebfinger records the result of a lookup started with startWebfinger, and lets anyone waiting for it know it's done.
+func (f *federator) finishWebfinger(key string, call *webfingerCall, accountURI *url.URL, err error) {
+	f.webfingers.mu.Lock()
+	delete(f.webfingers.inFlight, key)
+	f.webfingers.mu.Unlock()
+
+	call.accountURI = accountURI
+	call.err = err
+	close(call.done)
+}
+
+// revalidateWebfinger looks up the given account again, and updates the cached result. If the
+// lookup fails, the old result is kept, and the lookup isn't tried again for another interval.
+func (f *federator) revalidateWebfinger(key string, requestingUsername string, targetUsername string, targetDomain string, old *webfingerResult) {
+	call, started := f.startWebfinger(key)
+	if !started {
+		// someone else is already on it
+		return
+	}
+
+	accountURI, err := f.fingerRemoteAccount(context.Background(), requestingUsername, targetUsername, targetDomain)
+	if err != nil {
+		logrus.Debugf("revalidateWebfinger: couldn't look up @%s@%s again, keeping cached result: %s", targetUsername, targetDomain, err)
+		f.webfingers.cache.Set(key, newWebfingerResult(old.accountURI))
+	} else {
+		f.webfingers.cache.Set(key, newWebfingerResult(accountURI))
+	}
+	f.finishWebfinger(key, call, accountURI, err)
+}
+
+// fingerRemoteAccount does a webfinger lookup for the given account on the remote instance, without using the cache.
+func (f *federator) fingerRemoteAccount(ctx context.Context, requestingUsername string, targetUsername string, targetDomain string) (*url.URL, error) {
 	t, err := f.transportController.NewTransportForUsername(ctx, requestingUsername)
 	if err != nil {
 		return nil, fmt.Errorf("FingerRemoteAccount: error getting transport for username %s while dereferencing @%s@%s: %s", requestingUsername, targetUsername, targetDomain, err)
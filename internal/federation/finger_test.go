@@ -0,0 +1,144 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type FingerTestSuite struct {
+	suite.Suite
+	db db.DB
+
+	federator federation.Federator
+
+	// requests counts the webfinger requests made for each resource
+	requests   map[string]int
+	requestsMu sync.Mutex
+}
+
+func (suite *FingerTestSuite) SetupTest() {
+	testrig.InitTestLog()
+	testrig.InitTestConfig()
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db, nil)
+	suite.requests = make(map[string]int)
+
+	fedWorker := worker.New[messages.FromFederator](-1, -1)
+	tc := testrig.NewTestTransportController(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		resource := req.URL.Query().Get("resource")
+
+		suite.requestsMu.Lock()
+		suite.requests[resource]++
+		suite.requestsMu.Unlock()
+
+		// take a moment, like a real remote instance would
+		time.Sleep(50 * time.Millisecond)
+
+		if resource == "acct:nobody@example.org" {
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		}
+
+		username, domain, _ := strings.Cut(strings.TrimPrefix(resource, "acct:"), "@")
+		body := fmt.Sprintf(`{"subject":"%s","links":[{"rel":"self","type":"application/activity+json","href":"https://%s/users/%s"}]}`, resource, domain, username)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	}), suite.db, fedWorker)
+	suite.federator = federation.NewFederator(suite.db, testrig.NewTestFederatingDB(suite.db, fedWorker), tc, testrig.NewTestTypeConverter(suite.db), testrig.NewTestMediaManager(suite.db, testrig.NewTestStorage()))
+}
+
+func (suite *FingerTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *FingerTestSuite) TestFingerCached() {
+	for i := 0; i < 3; i++ {
+		accountURI, err := suite.federator.FingerRemoteAccount(context.Background(), "the_mighty_zork", "someone", "example.org")
+		suite.NoError(err)
+		suite.Equal("https://example.org/users/someone", accountURI.String())
+	}
+
+	// differently cased lookups of the same account use the cached result too
+	accountURI, err := suite.federator.FingerRemoteAccount(context.Background(), "", "SomeOne", "Example.org")
+	suite.NoError(err)
+	suite.Equal("https://example.org/users/someone", accountURI.String())
+
+	suite.Equal(1, suite.requests["acct:someone@example.org"])
+}
+
+func (suite *FingerTestSuite) TestFingerFailureNotCached() {
+	for i := 0; i < 2; i++ {
+		_, err := suite.federator.FingerRemoteAccount(context.Background(), "the_mighty_zork", "nobody", "example.org")
+		suite.Error(err)
+	}
+
+	suite.Equal(2, suite.requests["acct:nobody@example.org"])
+}
+
+func (suite *FingerTestSuite) TestFingerConcurrent() {
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accountURI, err := suite.federator.FingerRemoteAccount(context.Background(), "the_mighty_zork", "someone", "example.org")
+			suite.NoError(err)
+			suite.Equal("https://example.org/users/someone", accountURI.String())
+		}()
+	}
+	wg.Wait()
+
+	// everyone waited for the same lookup
+	suite.Equal(1, suite.requests["acct:someone@example.org"])
+}
+
+func (suite *FingerTestSuite) TestFingerRemoteAccounts() {
+	accountURIs := suite.federator.FingerRemoteAccounts(context.Background(), "the_mighty_zork", []string{
+		"someone@example.org",
+		"someone_else@example.org",
+		"someone@example.org",
+		"nobody@example.org",
+		"not_an_account",
+		"another@whatever.com",
+	})
+
+	suite.Len(accountURIs, 3)
+	suite.Equal("https://example.org/users/someone", accountURIs["someone@example.org"].String())
+	suite.Equal("https://example.org/users/someone_else", accountURIs["someone_else@example.org"].String())
+	suite.Equal("https://whatever.com/users/another", accountURIs["another@whatever.com"].String())
+	suite.Equal(1, suite.requests["acct:someone@example.org"])
+}
+
+func TestFingerTestSuite(t *testing.T) {
+	suite.Run(t, new(FingerTestSuite))
+}
@@ -0,0 +1,159 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+// rawActivity is an activity posted to an inbox, decoded just far enough to tell who it's from and what it's about,
+// before go-fed parses it.
+type rawActivity struct {
+	document map[string]interface{}
+	id       *url.URL
+	typeName string
+	actor    *url.URL
+	object   *url.URL
+}
+
+// decodeRawActivity decodes the json activity or object in b, keeping numbers as they were written.
+func decodeRawActivity(b []byte) (*rawActivity, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	document := make(map[string]interface{})
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("error decoding activity: %s", err)
+	}
+
+	typeName, _ := document["type"].(string)
+	return &rawActivity{
+		document: document,
+		id:       ap.ExtractRawIRI(document["id"]),
+		typeName: typeName,
+		actor:    ap.ExtractRawIRI(document["actor"]),
+		object:   ap.ExtractRawIRI(document["object"]),
+	}, nil
+}
+
+// attributedTo returns true if the given actor is one of the attributedTo of the decoded object, or is the object itself.
+func (a *rawActivity) attributedTo(actor *url.URL) bool {
+	if a.id != nil && a.id.String() == actor.String() {
+		return true
+	}
+
+	attributedTo, ok := a.document["attributedTo"].([]interface{})
+	if !ok {
+		attributedTo = []interface{}{a.document["attributedTo"]}
+	}
+
+	for _, v := range attributedTo {
+		if iri := ap.ExtractRawIRI(v); iri != nil && iri.String() == actor.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyForwardedActivity checks an activity that was delivered to us by someone other than its actor, which happens when an
+// instance forwards an activity to the other recipients of whatever it's about, like a reply to one of its posts, or the deletion
+// of a reply. The signature of the request only shows who forwarded the activity, so the activity is only trusted if it has an
+// integrity proof made by its actor, or if it can be fetched from the instance of its actor: either the activity itself or, for
+// creates, updates, and deletes, the thing that the activity is about.
+//
+// What's fetched is returned to be processed in place of what was delivered, since it's what the actor's instance vouches for.
+// Nil is returned if what was delivered can be processed as it is, and an error is returned if it couldn't be verified.
+func (f *federator) verifyForwardedActivity(ctx context.Context, username string, activity *rawActivity, proofOwner *url.URL) ([]byte, error) {
+	if activity.actor.Host == viper.GetString(config.Keys.Host) {
+		return nil, fmt.Errorf("activity %s claims to be from %s, which is one of our own accounts", activity.id, activity.actor)
+	}
+
+	if proofOwner != nil && proofOwner.String() == activity.actor.String() {
+		return nil, nil
+	}
+
+	if activity.id == nil || activity.id.Host != activity.actor.Host {
+		return nil, fmt.Errorf("activity %s isn't from the instance of its actor %s", activity.id, activity.actor)
+	}
+
+	t, err := f.transportController.NewTransportForUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transport: %s", err)
+	}
+
+	// if the activity itself can be fetched, then there's no doubt about what it is
+	if b, err := t.Dereference(ctx, activity.id); err == nil {
+		fetched, err := decodeRawActivity(b)
+		if err == nil && fetched.id != nil && fetched.id.String() == activity.id.String() &&
+			fetched.actor != nil && fetched.actor.String() == activity.actor.String() && fetched.typeName == activity.typeName {
+			return b, nil
+		}
+	}
+
+	// not every instance serves its activities, so fall back on fetching what the activity is about
+	if activity.object == nil || activity.object.Host != activity.actor.Host {
+		return nil, fmt.Errorf("object %s of activity %s isn't from the instance of its actor %s", activity.object, activity.id, activity.actor)
+	}
+
+	switch activity.typeName {
+	case ap.ActivityDelete:
+		// a delete is genuine if what it deletes is gone
+		b, err := t.Dereference(ctx, activity.object)
+		var statusErr *transport.StatusError
+		if errors.As(err, &statusErr) && statusErr.Gone() {
+			return nil, nil
+		}
+		if err == nil {
+			if fetched, err := decodeRawActivity(b); err == nil && fetched.typeName == ap.ObjectTombstone {
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("object %s of delete %s hasn't been deleted", activity.object, activity.id)
+	case ap.ActivityCreate, ap.ActivityUpdate:
+		// a create or update is genuine if the object as its actor's instance has it now belongs to the actor,
+		// and in that case the object from the actor's instance is used, instead of whatever was delivered
+		b, err := t.Dereference(ctx, activity.object)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching object %s of activity %s: %s", activity.object, activity.id, err)
+		}
+
+		fetched, err := decodeRawActivity(b)
+		if err != nil {
+			return nil, err
+		}
+		if fetched.id == nil || fetched.id.String() != activity.object.String() || !fetched.attributedTo(activity.actor) {
+			return nil, fmt.Errorf("object %s of activity %s doesn't belong to its actor %s", activity.object, activity.id, activity.actor)
+		}
+
+		activity.document["object"] = fetched.document
+		return json.Marshal(activity.document)
+	default:
+		return nil, fmt.Errorf("activity %s couldn't be fetched from the instance of its actor %s", activity.id, activity.actor)
+	}
+}
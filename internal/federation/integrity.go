@@ -0,0 +1,112 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+)
+
+// newProofOwnerRefetchCache returns a cache of the URIs of integrity proof owners that were refetched lately, because
+// their proof didn't verify. Entries last for publicKeyRefetchInterval, so that proofs that don't verify can't make us
+// fetch the same account over and over again.
+func newProofOwnerRefetchCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(publicKeyRefetchInterval)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
+// verifyIntegrityProof verifies the eddsa-jcs-2022 integrity proof of the given incoming activity, if it has one, and returns
+// the URI of the actor that made the proof. Nil is returned if the activity doesn't have a proof that can be verified, and an
+// error is returned if it has a proof that doesn't verify, or that wasn't made by the activity's own actor.
+//
+// Unlike the http signature of a request, a proof stays with the activity when it's forwarded to us by one of its other
+// recipients, so it shows that the activity really came from its actor no matter who delivered it.
+func (f *federator) verifyIntegrityProof(ctx context.Context, username string, body []byte) (*url.URL, gtserror.WithCode) {
+	proof, err := ap.ExtractProof(body)
+	if err != nil {
+		integrityProofs.WithLabelValues(proofInvalid).Inc()
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("error extracting integrity proof: %s", err))
+	}
+	if proof == nil {
+		return nil, nil
+	}
+
+	if blocked, err := f.db.IsURIBlocked(ctx, proof.Owner); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking block of integrity proof owner %s: %s", proof.Owner, err))
+	} else if blocked {
+		return nil, gtserror.NewErrorForbidden(fmt.Errorf("integrity proof owner %s is blocked", proof.Owner))
+	}
+
+	owner, err := f.GetRemoteAccount(ctx, username, proof.Owner, false, false)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("couldn't get integrity proof owner %s: %s", proof.Owner, err))
+	}
+
+	// if the proof was made with a key we don't know about, or doesn't verify with the key
+	// we know about, then the owner may have changed keys since we last saw them
+	if owner.AssertionKeyURI != proof.VerificationMethod.String() || proof.Verify(owner.AssertionPublicKey) != nil {
+		if f.shouldRefetchProofOwner(proof, body) {
+			owner, err = f.GetRemoteAccount(ctx, username, proof.Owner, false, true)
+			if err != nil {
+				return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("couldn't refresh integrity proof owner %s: %s", proof.Owner, err))
+			}
+		}
+	}
+
+	if owner.AssertionKeyURI != proof.VerificationMethod.String() {
+		integrityProofs.WithLabelValues(proofInvalid).Inc()
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("integrity proof was made with %s, which isn't an assertion key of %s", proof.VerificationMethod, proof.Owner))
+	}
+
+	if err := proof.Verify(owner.AssertionPublicKey); err != nil {
+		integrityProofs.WithLabelValues(proofInvalid).Inc()
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("integrity proof by %s didn't verify: %s", proof.Owner, err))
+	}
+
+	integrityProofs.WithLabelValues(proofVerified).Inc()
+	return proof.Owner, nil
+}
+
+// shouldRefetchProofOwner returns true if the owner of the given proof, which didn't verify, is worth fetching again in case
+// they've changed keys. Anyone can send us a proof that doesn't verify, so the owner is only fetched if the proof's key and
+// the activity's actor are on the owner's own instance, and if the owner wasn't already fetched in the last publicKeyRefetchInterval.
+func (f *federator) shouldRefetchProofOwner(proof *ap.Proof, body []byte) bool {
+	if !strings.EqualFold(proof.VerificationMethod.Host, proof.Owner.Host) {
+		return false
+	}
+
+	activity, err := decodeRawActivity(body)
+	if err != nil || activity.actor == nil || !strings.EqualFold(activity.actor.Host, proof.Owner.Host) {
+		return false
+	}
+
+	if _, recent := f.proofOwnerRefetches.Get(proof.Owner.String()); recent {
+		return false
+	}
+	f.proofOwnerRefetches.Set(proof.Owner.String(), struct{}{})
+	return true
+}
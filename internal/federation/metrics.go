@@ -0,0 +1,78 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsNamespace = "gotosocial"
+	metricsSubsystem = "federation"
+
+	lookupHit  = "hit"  // lookupHit is the metrics label for public keys that were found in the cache
+	lookupMiss = "miss" // lookupMiss is the metrics label for public keys that had to be fetched from the database or a remote instance
+
+	refetchRotated   = "rotated"   // refetchRotated is the metrics label for refetched public keys that verified a signature the cached key didn't
+	refetchUnchanged = "unchanged" // refetchUnchanged is the metrics label for refetched public keys that still didn't verify the signature
+	refetchFailed    = "failed"    // refetchFailed is the metrics label for public keys that couldn't be refetched
+
+	inboxAllowed = "allowed" // inboxAllowed is the metrics label for inbox requests that were within the rate limit for their domain
+	inboxLimited = "limited" // inboxLimited is the metrics label for inbox requests that were refused because their domain was over the rate limit
+	inboxRefused = "refused" // inboxRefused is the metrics label for inbox requests that were dropped because their type of activity is refused from their domain
+
+	proofVerified = "verified" // proofVerified is the metrics label for integrity proofs of incoming activities that verified
+	proofInvalid  = "invalid"  // proofInvalid is the metrics label for integrity proofs of incoming activities that didn't verify
+)
+
+var (
+	// publicKeyLookups counts lookups of remote public keys when authenticating requests, by whether the key was cached.
+	publicKeyLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "public_key_cache_lookups_total",
+		Help:      "Number of remote public key lookups made to authenticate requests, by whether the key was found in the cache.",
+	}, []string{"result"})
+
+	// publicKeyRefetches counts cached remote public keys that were fetched again because they didn't verify a signature.
+	publicKeyRefetches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "public_key_refetches_total",
+		Help:      "Number of remote public keys fetched again after failing to verify a signature, by whether the key turned out to have changed.",
+	}, []string{"result"})
+
+	// inboxRequests counts authenticated requests to inboxes, by whether they were within the rate limit for the sending domain,
+	// and whether their type of activity is accepted from it.
+	inboxRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "inbox_requests_total",
+		Help:      "Number of authenticated requests posted to inboxes, by whether they were allowed, refused because the sending domain was over the rate limit, or refused because of their activity type.",
+	}, []string{"result"})
+
+	// integrityProofs counts incoming activities with eddsa-jcs-2022 integrity proofs, by whether the proof verified.
+	integrityProofs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "integrity_proofs_total",
+		Help:      "Number of activities posted to inboxes with integrity proofs, by whether the proof verified.",
+	}, []string{"result"})
+)
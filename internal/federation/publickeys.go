@@ -0,0 +1,228 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/go-fed/httpsig"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+const (
+	// publicKeyCacheTTL is how long a remote public key is cached for before it's looked up again.
+	publicKeyCacheTTL = 6 * time.Hour
+
+	// publicKeyRefetchInterval is how long to wait after fetching a remote public key before fetching it again
+	// because it didn't verify a signature. This stops badly signed requests from making us fetch the same key
+	// over and over again.
+	publicKeyRefetchInterval = time.Minute
+)
+
+// remotePublicKey is the public key of a remote account, along with the URI of the account that owns it.
+type remotePublicKey struct {
+	publicKey crypto.PublicKey
+	ownerURI  *url.URL
+	fetchedAt time.Time // when the key was last dereferenced, or zero if it came from the database
+}
+
+// newPublicKeyCache returns a cache for remote public keys, keyed by the ID of the key. Entries aren't
+// kept alive by being used, so every key gets looked up again at least once every publicKeyCacheTTL.
+func newPublicKeyCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(publicKeyCacheTTL)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
+// getRemotePublicKey returns the remote public key with the given ID. The key is taken from the cache if possible;
+// if not, it's taken from the account in the database that has the key, and if there's no such account, then the key
+// is dereferenced from the remote instance.
+func (f *federator) getRemotePublicKey(ctx context.Context, keyID *url.URL) (*remotePublicKey, gtserror.WithCode) {
+	if cached, ok := f.publicKeyCache.Get(keyID.String()); ok {
+		if pk, ok := cached.(*remotePublicKey); ok {
+			publicKeyLookups.WithLabelValues(lookupHit).Inc()
+			return pk, nil
+		}
+	}
+	publicKeyLookups.WithLabelValues(lookupMiss).Inc()
+
+	account := &gtsmodel.Account{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "public_key_uri", Value: keyID.String()}}, account); err != nil {
+		// we don't know this key yet, so we need to fetch it
+		return f.dereferencePublicKey(ctx, keyID)
+	}
+
+	ownerURI, err := url.Parse(account.URI)
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, fmt.Sprintf("couldn't parse public key owner URL %s", account.URI))
+	}
+
+	pk := &remotePublicKey{
+		publicKey: accountPublicKey(account),
+		ownerURI:  ownerURI,
+	}
+	if pk.publicKey != nil {
+		f.publicKeyCache.Set(keyID.String(), pk)
+	}
+
+	return pk, nil
+}
+
+// dereferencePublicKey fetches the remote public key with the given ID, and caches it. This is done
+// by the instance actor, since it's not being done on behalf of any particular user.
+func (f *federator) dereferencePublicKey(ctx context.Context, keyID *url.URL) (*remotePublicKey, gtserror.WithCode) {
+	transport, err := f.transportController.NewTransportForInstance(ctx)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error creating instance transport: %s", err))
+	}
+
+	// The actual http call to the remote server is made right here in the Dereference function.
+	b, err := transport.Dereference(ctx, keyID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("error dereferencing public key %s: %s", keyID, err))
+	}
+
+	// if the key isn't in the response, we can't authenticate the request
+	requestingPublicKey, err := getPublicKeyFromResponse(ctx, b, keyID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("error parsing public key %s: %s", keyID, err))
+	}
+
+	// we should be able to get the actual key embedded in the vocab.W3IDSecurityV1PublicKey
+	pkPemProp := requestingPublicKey.GetW3IDSecurityV1PublicKeyPem()
+	if pkPemProp == nil || !pkPemProp.IsXMLSchemaString() {
+		return nil, gtserror.NewErrorNotAuthorized(errors.New("publicKeyPem property is not provided or it is not embedded as a value"))
+	}
+
+	// and decode the PEM so that we can parse it as a golang public key
+	pubKeyPem := pkPemProp.Get()
+	block, _ := pem.Decode([]byte(pubKeyPem))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, gtserror.NewErrorNotAuthorized(errors.New("could not decode publicKeyPem to PUBLIC KEY pem block type"))
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("could not parse public key %s from block bytes: %s", keyID, err))
+	}
+
+	// all good! we just need the URI of the key owner to return
+	pkOwnerProp := requestingPublicKey.GetW3IDSecurityV1Owner()
+	if pkOwnerProp == nil || !pkOwnerProp.IsIRI() {
+		return nil, gtserror.NewErrorNotAuthorized(errors.New("publicKeyOwner property is not provided or it is not embedded as a value"))
+	}
+
+	pk := &remotePublicKey{
+		publicKey: publicKey,
+		ownerURI:  pkOwnerProp.GetIRI(),
+		fetchedAt: time.Now(),
+	}
+	if pk.publicKey != nil {
+		f.publicKeyCache.Set(keyID.String(), pk)
+	}
+
+	return pk, nil
+}
+
+// refetchPublicKey fetches the given remote public key again after it failed to verify a signature, in case it's
+// been rotated since it was cached. If the key changed, then the account that has it is updated with the new key.
+//
+// Nil will be returned if the key was fetched too recently to be worth fetching again, or if it couldn't be fetched.
+func (f *federator) refetchPublicKey(ctx context.Context, keyID *url.URL, pk *remotePublicKey) *remotePublicKey {
+	if time.Since(pk.fetchedAt) < publicKeyRefetchInterval {
+		return nil
+	}
+
+	refetched, errWithCode := f.dereferencePublicKey(ctx, keyID)
+	if errWithCode != nil {
+		logrus.Debugf("refetchPublicKey: error refetching public key %s: %s", keyID, errWithCode)
+		publicKeyRefetches.WithLabelValues(refetchFailed).Inc()
+		return nil
+	}
+
+	return refetched
+}
+
+// updateAccountPublicKey replaces the public key of the account in the database that has the given key ID, if there is one.
+func (f *federator) updateAccountPublicKey(ctx context.Context, keyID *url.URL, publicKey crypto.PublicKey) {
+	account := &gtsmodel.Account{}
+	if err := f.db.GetWhere(ctx, []db.Where{{Key: "public_key_uri", Value: keyID.String()}}, account); err != nil {
+		// we don't have an account with this key, so there's nothing to update
+		return
+	}
+
+	account, err := f.db.GetAccountByID(ctx, account.ID)
+	if err != nil {
+		logrus.Errorf("updateAccountPublicKey: error getting account with public key %s: %s", keyID, err)
+		return
+	}
+
+	account.PublicKey = nil
+	account.PublicKeyEd25519 = nil
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		account.PublicKey = publicKey
+	case ed25519.PublicKey:
+		account.PublicKeyEd25519 = publicKey
+	default:
+		logrus.Errorf("updateAccountPublicKey: public key %s was of unsupported type %T", keyID, publicKey)
+		return
+	}
+
+	if _, err := f.db.UpdateAccount(ctx, account); err != nil {
+		logrus.Errorf("updateAccountPublicKey: error updating account with public key %s: %s", keyID, err)
+	}
+}
+
+// accountPublicKey returns the public key that the given account signs requests with, or nil if it doesn't have one.
+func accountPublicKey(account *gtsmodel.Account) crypto.PublicKey {
+	if account.PublicKey != nil {
+		return account.PublicKey
+	}
+	if len(account.PublicKeyEd25519) != 0 {
+		return account.PublicKeyEd25519
+	}
+	return nil
+}
+
+// verifySignature checks the signature held by the given verifier against the given public key, using
+// the algorithms that fit the algorithm named in the signature, and returns the algorithm that verified
+// the signature. If the signature couldn't be verified, false will be returned.
+func verifySignature(verifier httpsig.Verifier, publicKey crypto.PublicKey, algorithm string) (httpsig.Algorithm, bool) {
+	for _, algo := range verifyAlgorithms(algorithm, publicKey) {
+		if err := verifier.Verify(publicKey, algo); err == nil {
+			return algo, true
+		}
+	}
+	return "", false
+}
@@ -135,3 +135,42 @@ func NewErrorConflict(original error, helpText ...string) WithCode {
 		code:     http.StatusConflict,
 	}
 }
+
+// NewErrorUnprocessableEntity returns an ErrorWithCode 422 with the given original error and optional help text.
+func NewErrorUnprocessableEntity(original error, helpText ...string) WithCode {
+	safe := "unprocessable entity"
+	if helpText != nil {
+		safe = safe + ": " + strings.Join(helpText, ": ")
+	}
+	return withCode{
+		original: original,
+		safe:     errors.New(safe),
+		code:     http.StatusUnprocessableEntity,
+	}
+}
+
+// NewErrorGone returns an ErrorWithCode 410 with the given original error and optional help text.
+func NewErrorGone(original error, helpText ...string) WithCode {
+	safe := "gone"
+	if helpText != nil {
+		safe = safe + ": " + strings.Join(helpText, ": ")
+	}
+	return withCode{
+		original: original,
+		safe:     errors.New(safe),
+		code:     http.StatusGone,
+	}
+}
+
+// NewErrorServiceUnavailable returns an ErrorWithCode 503 with the given original error and optional help text.
+func NewErrorServiceUnavailable(original error, helpText ...string) WithCode {
+	safe := "service unavailable"
+	if helpText != nil {
+		safe = safe + ": " + strings.Join(helpText, ": ")
+	}
+	return withCode{
+		original: original,
+		safe:     errors.New(safe),
+		code:     http.StatusServiceUnavailable,
+	}
+}
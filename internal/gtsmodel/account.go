@@ -23,55 +23,79 @@
 package gtsmodel
 
 import (
+	"crypto/ed25519"
 	"crypto/rsa"
 	"time"
 )
 
 // Account represents either a local or a remote fediverse account, gotosocial or otherwise (mastodon, pleroma, etc).
 type Account struct {
-	ID                      string           `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                               // id of this item in the database
-	CreatedAt               time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                        // when was item created
-	UpdatedAt               time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                        // when was item last updated
-	Username                string           `validate:"required" bun:",nullzero,notnull,unique:userdomain"`                                                         // Username of the account, should just be a string of [a-zA-Z0-9_]. Can be added to domain to create the full username in the form ``[username]@[domain]`` eg., ``user_96@example.org``. Username and domain should be unique *with* each other
-	Domain                  string           `validate:"omitempty,fqdn" bun:",nullzero,unique:userdomain"`                                                           // Domain of the account, will be null if this is a local account, otherwise something like ``example.org``. Should be unique with username.
-	AvatarMediaAttachmentID string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // Database ID of the media attachment, if present
-	AvatarMediaAttachment   *MediaAttachment `validate:"-" bun:"rel:belongs-to"`                                                                                     // MediaAttachment corresponding to avatarMediaAttachmentID
-	AvatarRemoteURL         string           `validate:"omitempty,url" bun:",nullzero"`                                                                              // For a non-local account, where can the header be fetched?
-	HeaderMediaAttachmentID string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // Database ID of the media attachment, if present
-	HeaderMediaAttachment   *MediaAttachment `validate:"-" bun:"rel:belongs-to"`                                                                                     // MediaAttachment corresponding to headerMediaAttachmentID
-	HeaderRemoteURL         string           `validate:"omitempty,url" bun:",nullzero"`                                                                              // For a non-local account, where can the header be fetched?
-	DisplayName             string           `validate:"-" bun:""`                                                                                                   // DisplayName for this account. Can be empty, then just the Username will be used for display purposes.
-	Fields                  []Field          `validate:"-"`                                                                                                          // a key/value map of fields that this account has added to their profile
-	Note                    string           `validate:"-" bun:""`                                                                                                   // A note that this account has on their profile (ie., the account's bio/description of themselves)
-	Memorial                bool             `validate:"-" bun:",default:false"`                                                                                     // Is this a memorial account, ie., has the user passed away?
-	AlsoKnownAs             string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // This account is associated with x account id (TODO: migrate to be AlsoKnownAsID)
-	MovedToAccountID        string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // This account has moved this account id in the database
-	Bot                     bool             `validate:"-" bun:",default:false"`                                                                                     // Does this account identify itself as a bot?
-	Reason                  string           `validate:"-" bun:""`                                                                                                   // What reason was given for signing up when this account was created?
-	Locked                  bool             `validate:"-" bun:",default:true"`                                                                                      // Does this account need an approval for new followers?
-	Discoverable            bool             `validate:"-" bun:",default:false"`                                                                                     // Should this account be shown in the instance's profile directory?
-	Privacy                 Visibility       `validate:"required_without=Domain,omitempty,oneof=public unlocked followers_only mutuals_only direct" bun:",nullzero"` // Default post privacy for this account
-	Sensitive               bool             `validate:"-" bun:",default:false"`                                                                                     // Set posts from this account to sensitive by default?
-	Language                string           `validate:"omitempty,bcp47_language_tag" bun:",nullzero,notnull,default:'en'"`                                          // What language does this account post in?
-	URI                     string           `validate:"required,url" bun:",nullzero,notnull,unique"`                                                                // ActivityPub URI for this account.
-	URL                     string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Web URL for this account's profile
-	LastWebfingeredAt       time.Time        `validate:"required_with=Domain" bun:"type:timestamptz,nullzero"`                                                       // Last time this account was refreshed/located with webfinger.
-	InboxURI                string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Address of this account's ActivityPub inbox, for sending activity to
-	OutboxURI               string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Address of this account's activitypub outbox
-	FollowingURI            string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URI for getting the following list of this account
-	FollowersURI            string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URI for getting the followers list of this account
-	FeaturedCollectionURI   string           `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URL for getting the featured collection list of this account
-	ActorType               string           `validate:"oneof=Application Group Organization Person Service" bun:",nullzero,notnull"`                                // What type of activitypub actor is this account?
-	PrivateKey              *rsa.PrivateKey  `validate:"required_without=Domain"`                                                                                    // Privatekey for validating activitypub requests, will only be defined for local accounts
-	PublicKey               *rsa.PublicKey   `validate:"required"`                                                                                                   // Publickey for encoding activitypub requests, will be defined for both local and remote accounts
-	PublicKeyURI            string           `validate:"required,url" bun:",nullzero,notnull,unique"`                                                                // Web-reachable location of this account's public key
-	SensitizedAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account set to have all its media shown as sensitive?
-	SilencedAt              time.Time        `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account silenced (eg., statuses only visible to followers, not public)?
-	SuspendedAt             time.Time        `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account suspended (eg., don't allow it to log in/post, don't accept media/posts from this account)
-	HideCollections         bool             `validate:"-" bun:",default:false"`                                                                                     // Hide this account's collections
-	SuspensionOrigin        string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // id of the database entry that caused this account to become suspended -- can be an account ID or a domain block ID
+	ID                      string             `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                               // id of this item in the database
+	CreatedAt               time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                        // when was item created
+	UpdatedAt               time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                        // when was item last updated
+	Username                string             `validate:"required" bun:",nullzero,notnull,unique:userdomain"`                                                         // Username of the account, should just be a string of [a-zA-Z0-9_]. Can be added to domain to create the full username in the form ``[username]@[domain]`` eg., ``user_96@example.org``. Username and domain should be unique *with* each other
+	Domain                  string             `validate:"omitempty,fqdn" bun:",nullzero,unique:userdomain"`                                                           // Domain of the account, will be null if this is a local account, otherwise something like ``example.org``. Should be unique with username.
+	AvatarMediaAttachmentID string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // Database ID of the media attachment, if present
+	AvatarMediaAttachment   *MediaAttachment   `validate:"-" bun:"rel:belongs-to"`                                                                                     // MediaAttachment corresponding to avatarMediaAttachmentID
+	AvatarRemoteURL         string             `validate:"omitempty,url" bun:",nullzero"`                                                                              // For a non-local account, where can the header be fetched?
+	HeaderMediaAttachmentID string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // Database ID of the media attachment, if present
+	HeaderMediaAttachment   *MediaAttachment   `validate:"-" bun:"rel:belongs-to"`                                                                                     // MediaAttachment corresponding to headerMediaAttachmentID
+	HeaderRemoteURL         string             `validate:"omitempty,url" bun:",nullzero"`                                                                              // For a non-local account, where can the header be fetched?
+	DisplayName             string             `validate:"-" bun:""`                                                                                                   // DisplayName for this account. Can be empty, then just the Username will be used for display purposes.
+	Fields                  []Field            `validate:"-"`                                                                                                          // a key/value map of fields that this account has added to their profile
+	Note                    string             `validate:"-" bun:""`                                                                                                   // A note that this account has on their profile (ie., the account's bio/description of themselves)
+	EmojiIDs                []string           `validate:"dive,ulid" bun:"emojis,nullzero"`                                                                            // Database IDs of any custom emojis used in the display name, note, or fields of this account
+	Emojis                  []*Emoji           `validate:"-" bun:"-"`                                                                                                  // Emojis corresponding to emojiIDs, or the emojis found on a remote account that are still to be stored
+	Memorial                bool               `validate:"-" bun:",default:false"`                                                                                     // Is this a memorial account, ie., has the user passed away?
+	AlsoKnownAs             string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // This account is associated with x account id (TODO: migrate to be AlsoKnownAsID)
+	AlsoKnownAsURIs         []string           `validate:"-" bun:"also_known_as_uris,nullzero"`                                                                        // ActivityPub URIs of other accounts that this account is also known as, ie., that it can be moved to or from
+	MovedToAccountID        string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // This account has moved this account id in the database
+	Bot                     bool               `validate:"-" bun:",default:false"`                                                                                     // Does this account identify itself as a bot?
+	Reason                  string             `validate:"-" bun:""`                                                                                                   // What reason was given for signing up when this account was created?
+	Locked                  bool               `validate:"-" bun:",default:true"`                                                                                      // Does this account need an approval for new followers?
+	Discoverable            bool               `validate:"-" bun:",default:false"`                                                                                     // Should this account be shown in the instance's profile directory?
+	Privacy                 Visibility         `validate:"required_without=Domain,omitempty,oneof=public unlocked followers_only mutuals_only direct" bun:",nullzero"` // Default post privacy for this account
+	Sensitive               bool               `validate:"-" bun:",default:false"`                                                                                     // Set posts from this account to sensitive by default?
+	Language                string             `validate:"omitempty,bcp47_language_tag" bun:",nullzero,notnull,default:'en'"`                                          // What language does this account post in?
+	URI                     string             `validate:"required,url" bun:",nullzero,notnull,unique"`                                                                // ActivityPub URI for this account.
+	URL                     string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Web URL for this account's profile
+	LastWebfingeredAt       time.Time          `validate:"required_with=Domain" bun:"type:timestamptz,nullzero"`                                                       // Last time this account was refreshed/located with webfinger.
+	InboxURI                string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Address of this account's ActivityPub inbox, for sending activity to
+	SharedInboxURI          string             `validate:"omitempty,url" bun:",nullzero"`                                                                              // Address of the inbox shared by accounts on this account's instance, if it has one
+	OutboxURI               string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // Address of this account's activitypub outbox
+	FollowingURI            string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URI for getting the following list of this account
+	FollowersURI            string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URI for getting the followers list of this account
+	FeaturedCollectionURI   string             `validate:"required_without=Domain,omitempty,url" bun:",nullzero,unique"`                                               // URL for getting the featured collection list of this account
+	ActorType               string             `validate:"oneof=Application Group Organization Person Service" bun:",nullzero,notnull"`                                // What type of activitypub actor is this account?
+	PrivateKey              *rsa.PrivateKey    `validate:"required_without=Domain"`                                                                                    // Privatekey for validating activitypub requests, will only be defined for local accounts
+	PublicKey               *rsa.PublicKey     `validate:"required_without=PublicKeyEd25519"`                                                                          // Publickey for encoding activitypub requests, will be defined for both local and remote accounts, unless a remote account only has an ed25519 key
+	PublicKeyEd25519        ed25519.PublicKey  `validate:"-" bun:",nullzero"`                                                                                          // Ed25519 public key of a remote account, if that's the type of key it signs requests with
+	PublicKeyURI            string             `validate:"required,url" bun:",nullzero,notnull,unique"`                                                                // Web-reachable location of this account's public key
+	AssertionPrivateKey     ed25519.PrivateKey `validate:"-" bun:",nullzero"`                                                                                          // Ed25519 private key that integrity proofs of activities are made with, will only be defined for local accounts
+	AssertionPublicKey      ed25519.PublicKey  `validate:"-" bun:",nullzero"`                                                                                          // Ed25519 public key from the assertionMethod of the account's actor, that integrity proofs made by the account are verified with
+	AssertionKeyURI         string             `validate:"omitempty,url" bun:",nullzero"`                                                                              // Web-reachable location of the account's assertion key, usually a fragment of the account's URI
+	SensitizedAt            time.Time          `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account set to have all its media shown as sensitive?
+	SilencedAt              time.Time          `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account silenced (eg., statuses only visible to followers, not public)?
+	SuspendedAt             time.Time          `validate:"-" bun:"type:timestamptz,nullzero"`                                                                          // When was this account suspended (eg., don't allow it to log in/post, don't accept media/posts from this account)
+	HideCollections         bool               `validate:"-" bun:",default:false"`                                                                                     // Hide this account's collections
+	ExcludedNotifications   []NotificationType `validate:"-" bun:"excluded_notifications,nullzero"`                                                                    // Types of notification that this account doesn't want to be sent
+	ExpandMedia             ExpandMedia        `validate:"omitempty,oneof=default show_all hide_all" bun:",nullzero"`                                                  // How media attachments should be shown to this account by default; empty means the same as default
+	ExpandSpoilers          bool               `validate:"-" bun:",default:false"`                                                                                     // Should content warnings be expanded for this account by default?
+	SuspensionOrigin        string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                                                // id of the database entry that caused this account to become suspended -- can be an account ID or a domain block ID
 }
 
+// ExpandMedia is the choice of an account for how its clients should show media attachments by default.
+type ExpandMedia string
+
+const (
+	// ExpandMediaDefault means media marked as sensitive is hidden, and other media is shown.
+	ExpandMediaDefault ExpandMedia = "default"
+	// ExpandMediaShowAll means all media is shown, whether it's marked as sensitive or not.
+	ExpandMediaShowAll ExpandMedia = "show_all"
+	// ExpandMediaHideAll means all media is hidden, whether it's marked as sensitive or not.
+	ExpandMediaHideAll ExpandMedia = "hide_all"
+)
+
 // Field represents a key value field on an account, for things like pronouns, website, etc.
 // VerifiedAt is optional, to be used only if Value is a URL to a webpage that contains the
 // username of the user.
@@ -83,17 +107,18 @@ type Field struct {
 
 // Relationship describes a requester's relationship with another account.
 type Relationship struct {
-	ID                  string // The account id.
-	Following           bool   // Are you following this user?
-	ShowingReblogs      bool   // Are you receiving this user's boosts in your home timeline?
-	Notifying           bool   // Have you enabled notifications for this user?
-	FollowedBy          bool   // Are you followed by this user?
-	Blocking            bool   // Are you blocking this user?
-	BlockedBy           bool   // Is this user blocking you?
-	Muting              bool   // Are you muting this user?
-	MutingNotifications bool   // Are you muting notifications from this user?
-	Requested           bool   // Do you have a pending follow request for this user?
-	DomainBlocking      bool   // Are you blocking this user's domain?
-	Endorsed            bool   // Are you featuring this user on your profile?
-	Note                string // Your note on this account.
+	ID                  string    // The account id.
+	Following           bool      // Are you following this user?
+	ShowingReblogs      bool      // Are you receiving this user's boosts in your home timeline?
+	Notifying           bool      // Have you enabled notifications for this user?
+	FollowedBy          bool      // Are you followed by this user?
+	Blocking            bool      // Are you blocking this user?
+	BlockedBy           bool      // Is this user blocking you?
+	Muting              bool      // Are you muting this user?
+	MutingNotifications bool      // Are you muting notifications from this user?
+	MuteExpiresAt       time.Time // When does your mute of this user expire? Zero if it doesn't, or if you're not muting them.
+	Requested           bool      // Do you have a pending follow request for this user?
+	DomainBlocking      bool      // Are you blocking this user's domain?
+	Endorsed            bool      // Are you featuring this user on your profile?
+	Note                string    // Your note on this account.
 }
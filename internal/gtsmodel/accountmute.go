@@ -0,0 +1,35 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// AccountMute refers to one account having muted another, so that the target's posts are hidden from the muting account's
+// home timeline, and optionally so that notifications from the target aren't created either.
+type AccountMute struct {
+	ID              string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                   // id of this item in the database
+	CreatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`            // when was item created
+	UpdatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`            // when was item last updated
+	ExpiresAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                              // when does this mute expire? zero means it doesn't
+	AccountID       string    `validate:"required,ulid" bun:"type:CHAR(26),unique:accountmutesrctarget,notnull,nullzero"` // Who is doing the muting?
+	Account         *Account  `validate:"-" bun:"rel:belongs-to"`                                                         // Account corresponding to accountID
+	TargetAccountID string    `validate:"required,ulid" bun:"type:CHAR(26),unique:accountmutesrctarget,notnull,nullzero"` // Who is being muted?
+	TargetAccount   *Account  `validate:"-" bun:"rel:belongs-to"`                                                         // Account corresponding to targetAccountID
+	Notifications   bool      `validate:"-" bun:",default:true"`                                                          // Are notifications from the target muted too?
+}
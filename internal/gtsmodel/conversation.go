@@ -0,0 +1,58 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Conversation is a thread of direct messages, as seen by one local account taking part in it.
+// All direct messages between the same group of accounts belong to the same conversation.
+type Conversation struct {
+	ID               string     `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                   // id of this item in the database
+	CreatedAt        time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`            // when was item created
+	UpdatedAt        time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`            // when was item last updated
+	AccountID        string     `validate:"required,ulid" bun:"type:CHAR(26),unique:conversationaccounts,nullzero,notnull"` // id of the local account that this conversation belongs to
+	Account          *Account   `validate:"-" bun:"rel:belongs-to"`                                                         // account corresponding to accountID
+	OtherAccountIDs  []string   `validate:"dive,ulid" bun:"other_accounts,array"`                                           // ids of the other accounts taking part in this conversation
+	OtherAccounts    []*Account `validate:"-" bun:"-"`                                                                      // accounts corresponding to otherAccountIDs, not stored in the database
+	OtherAccountsKey string     `validate:"-" bun:",unique:conversationaccounts,notnull"`                                   // otherAccountIDs in a form that can be compared in the database, see ConversationOtherAccountsKey
+	LastStatusID     string     `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                             // id of the latest status in this conversation
+	LastStatus       *Status    `validate:"-" bun:"rel:belongs-to"`                                                         // status corresponding to lastStatusID
+	Read             bool       `validate:"-" bun:",notnull,default:false"`                                                 // has the account read the latest status in this conversation?
+}
+
+// ConversationToStatus is an intermediate struct to facilitate the many2many relationship between a conversation and the statuses in it.
+type ConversationToStatus struct {
+	ConversationID string        `validate:"ulid,required" bun:"type:CHAR(26),unique:conversationstatus,nullzero,notnull"`
+	Conversation   *Conversation `validate:"-" bun:"rel:belongs-to"`
+	StatusID       string        `validate:"ulid,required" bun:"type:CHAR(26),unique:conversationstatus,nullzero,notnull"`
+	Status         *Status       `validate:"-" bun:"rel:belongs-to"`
+}
+
+// ConversationOtherAccountsKey returns the key that identifies the conversation between
+// an account and the accounts with the given ids, whatever order the ids are given in.
+func ConversationOtherAccountsKey(otherAccountIDs []string) string {
+	ids := make([]string, len(otherAccountIDs))
+	copy(ids, otherAccountIDs)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
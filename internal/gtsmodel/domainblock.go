@@ -31,5 +31,16 @@ type DomainBlock struct {
 	PrivateComment     string    `validate:"-" bun:""`                                                            // Private comment on this block, viewable to admins
 	PublicComment      string    `validate:"-" bun:""`                                                            // Public comment on this block, viewable (optionally) by everyone
 	Obfuscate          bool      `validate:"-" bun:",default:false"`                                              // whether the domain name should appear obfuscated when displaying it publicly
+	IncludeSubdomains  bool      `validate:"-" bun:",default:false"`                                              // whether the block also covers all subdomains of the domain, eg. 'sub.whatever.com'
 	SubscriptionID     string    `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // if this block was created through a subscription, what's the subscription ID?
+	Severity           string    `validate:"oneof=suspend silence" bun:",nullzero,notnull,default:'suspend'"`     // how severe is this block? Either 'suspend' or 'silence'
+	RejectMedia        bool      `validate:"-" bun:",default:false"`                                              // whether media from the domain should never be cached, even if the domain isn't silenced
 }
+
+const (
+	// DomainBlockSeveritySuspend means that nothing from the domain is accepted, and everything already known from it is removed.
+	DomainBlockSeveritySuspend = "suspend"
+	// DomainBlockSeveritySilence means that the domain is limited: its posts are accepted but kept out of public timelines,
+	// follows from its accounts always need approval, and its media is never cached.
+	DomainBlockSeveritySilence = "silence"
+)
@@ -0,0 +1,27 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+// DomainTraffic is how many statuses have been received from one remote domain over some period of time.
+//
+// It isn't stored in the database, but is scanned out of it.
+type DomainTraffic struct {
+	Domain   string `bun:"domain"`   // domain that the statuses were received from
+	Statuses int    `bun:"statuses"` // how many statuses were received
+}
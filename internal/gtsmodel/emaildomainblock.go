@@ -25,7 +25,9 @@ type EmailDomainBlock struct {
 	ID                 string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
 	CreatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
 	UpdatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
-	Domain             string    `validate:"required,fqdn" bun:",nullzero,notnull"`                               // Email domain to block. Eg. 'gmail.com' or 'hotmail.com'
+	Domain             string    `validate:"required,fqdn" bun:",nullzero,notnull"`                               // Email domain to block, along with its subdomains. Eg. 'gmail.com' or 'hotmail.com'
 	CreatedByAccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // Account ID of the creator of this block
 	CreatedByAccount   *Account  `validate:"-" bun:"rel:belongs-to"`                                              // Account corresponding to createdByAccountID
+	Attempts           int       `validate:"-" bun:",notnull,default:0"`                                          // how many times has someone tried to sign up with an email address on this domain?
+	LastAttemptAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when did someone last try to sign up with an email address on this domain?
 }
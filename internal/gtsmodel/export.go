@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Export is a file of an account's data, such as the accounts it follows, generated in the background for the account to download.
+type Export struct {
+	ID        string      `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                         // id of this item in the database
+	CreatedAt time.Time   `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                  // when was item created
+	UpdatedAt time.Time   `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                  // when was item last updated
+	AccountID string      `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                   // which account's data is this?
+	Type      ExportType  `validate:"oneof=follows followers blocks mutes bookmarks lists archive" bun:",nullzero,notnull"` // what data is this?
+	State     ExportState `validate:"oneof=pending done failed" bun:",nullzero,notnull"`                                    // has the file been generated yet?
+	Path      string      `validate:"required_if=State done" bun:",nullzero"`                                               // where the file is in storage, once it's been generated
+	Size      int         `validate:"min=0" bun:",nullzero"`                                                                // size of the file in bytes, once it's been generated
+}
+
+// ExportType is the kind of data in an export.
+type ExportType string
+
+// Export types. Apart from archives, each one is exported as a csv file in the same format that Mastodon uses.
+const (
+	ExportTypeFollows   ExportType = "follows"   // accounts that the account follows
+	ExportTypeFollowers ExportType = "followers" // accounts that follow the account
+	ExportTypeBlocks    ExportType = "blocks"    // accounts that the account blocks
+	ExportTypeMutes     ExportType = "mutes"     // accounts that the account mutes
+	ExportTypeBookmarks ExportType = "bookmarks" // statuses that the account has bookmarked
+	ExportTypeArchive   ExportType = "archive"   // zip of the account's actor, outbox and media, as activitypub json
+)
+
+// ExportState is how far along the generation of an export is.
+type ExportState string
+
+// Export states.
+const (
+	ExportStatePending ExportState = "pending" // the file is still being generated
+	ExportStateDone    ExportState = "done"    // the file is ready to download
+	ExportStateFailed  ExportState = "failed"  // something went wrong generating the file
+)
+
+// ExportLifetime is how long an export is kept around after it's created, before it's deleted.
+const ExportLifetime = 7 * 24 * time.Hour
+
+// ExpiresAt returns the time at which the export will be deleted.
+func (e *Export) ExpiresAt() time.Time {
+	return e.CreatedAt.Add(ExportLifetime)
+}
@@ -0,0 +1,31 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// FeaturedTag refers to a hashtag that an account showcases on its profile.
+type FeaturedTag struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`             // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`             // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),unique:featuredtagaccounttag,notnull,nullzero"` // Who is featuring the tag?
+	TagID     string    `validate:"required,ulid" bun:"type:CHAR(26),unique:featuredtagaccounttag,notnull,nullzero"` // Which tag is being featured?
+	Tag       *Tag      `validate:"-" bun:"rel:belongs-to"`                                                          // Tag corresponding to tagID
+}
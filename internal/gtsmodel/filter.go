@@ -0,0 +1,188 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import (
+	"regexp"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is a group of keywords and statuses that an account doesn't want to see, or wants to be warned about, in the given contexts.
+type Filter struct {
+	ID                   string           `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID            string           `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that created the filter
+	Title                string           `validate:"required" bun:",nullzero,notnull"`                                    // name of the filter, for the account's own reference
+	Action               FilterAction     `validate:"oneof=warn hide" bun:",nullzero,notnull,default:'hide'"`              // what to do with statuses that match the filter
+	ContextHome          bool             `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to the home timeline
+	ContextNotifications bool             `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to notifications
+	ContextPublic        bool             `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to public and hashtag timelines
+	ContextThread        bool             `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to the replies and parents of a status
+	ContextAccount       bool             `validate:"-" bun:",notnull,default:false"`                                      // apply the filter to the statuses of an account
+	ExpiresAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when does the filter stop applying? zero means it doesn't expire
+	Keywords             []*FilterKeyword `validate:"-" bun:"rel:has-many,join:id=filter_id"`                              // phrases that the filter matches
+	Statuses             []*FilterStatus  `validate:"-" bun:"rel:has-many,join:id=filter_id"`                              // statuses that the filter matches
+}
+
+// FilterKeyword is a phrase that a filter matches statuses containing.
+type FilterKeyword struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that owns the filter
+	FilterID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the filter this keyword belongs to
+	Keyword   string    `validate:"required" bun:",nullzero,notnull"`                                    // text to match
+	WholeWord bool      `validate:"-" bun:",notnull,default:false"`                                      // only match the keyword at word boundaries
+
+	regexp     *regexp.Regexp // compiled by Regexp the first time it's called
+	regexpOnce sync.Once
+}
+
+// FilterStatus is one particular status that a filter matches.
+type FilterStatus struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the account that owns the filter
+	FilterID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the filter this status belongs to
+	StatusID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the status to match
+}
+
+// FilterResult describes how a status matched a filter. It's worked out when statuses are served, and isn't stored.
+type FilterResult struct {
+	Filter         *Filter  // the filter that matched
+	KeywordMatches []string // keywords of the filter that were found in the status
+	StatusMatches  []string // ids of statuses of the filter that the status is
+}
+
+// FilterAction is what happens to statuses that match a filter.
+type FilterAction string
+
+const (
+	// FilterActionWarn means matching statuses are served along with the filters they matched, so clients can hide them behind a warning.
+	FilterActionWarn FilterAction = "warn"
+	// FilterActionHide means matching statuses aren't served at all.
+	FilterActionHide FilterAction = "hide"
+)
+
+// FilterContext is a place where statuses can be filtered out.
+type FilterContext string
+
+const (
+	// FilterContextHome is the home timeline.
+	FilterContextHome FilterContext = "home"
+	// FilterContextNotifications is the notifications timeline.
+	FilterContextNotifications FilterContext = "notifications"
+	// FilterContextPublic is the public, local, and hashtag timelines.
+	FilterContextPublic FilterContext = "public"
+	// FilterContextThread is the replies and parents of a status.
+	FilterContextThread FilterContext = "thread"
+	// FilterContextAccount is the statuses of an account.
+	FilterContextAccount FilterContext = "account"
+)
+
+// FilterContexts are all the contexts a filter can apply in, in the order they're shown in.
+var FilterContexts = []FilterContext{
+	FilterContextHome,
+	FilterContextNotifications,
+	FilterContextPublic,
+	FilterContextThread,
+	FilterContextAccount,
+}
+
+// Expired returns true if the filter has passed its expiry time.
+func (f *Filter) Expired() bool {
+	return !f.ExpiresAt.IsZero() && !time.Now().Before(f.ExpiresAt)
+}
+
+// AppliesTo returns true if the filter is set to apply in the given context.
+func (f *Filter) AppliesTo(context FilterContext) bool {
+	switch context {
+	case FilterContextHome:
+		return f.ContextHome
+	case FilterContextNotifications:
+		return f.ContextNotifications
+	case FilterContextPublic:
+		return f.ContextPublic
+	case FilterContextThread:
+		return f.ContextThread
+	case FilterContextAccount:
+		return f.ContextAccount
+	}
+	return false
+}
+
+// SetContexts sets the filter to apply in exactly the given contexts, returning false if any of them isn't recognised.
+func (f *Filter) SetContexts(contexts []FilterContext) bool {
+	f.ContextHome = false
+	f.ContextNotifications = false
+	f.ContextPublic = false
+	f.ContextThread = false
+	f.ContextAccount = false
+
+	for _, context := range contexts {
+		switch context {
+		case FilterContextHome:
+			f.ContextHome = true
+		case FilterContextNotifications:
+			f.ContextNotifications = true
+		case FilterContextPublic:
+			f.ContextPublic = true
+		case FilterContextThread:
+			f.ContextThread = true
+		case FilterContextAccount:
+			f.ContextAccount = true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Regexp returns a case insensitive regular expression that matches the keyword. It's compiled
+// the first time it's needed and kept with the keyword, so it shouldn't be changed after that.
+//
+// If the keyword is whole word, it only matches where it isn't part of a longer word: if it starts
+// with a word character there can't be a word character before it, and the same goes for the end.
+func (k *FilterKeyword) Regexp() *regexp.Regexp {
+	k.regexpOnce.Do(func() {
+		expr := regexp.QuoteMeta(k.Keyword)
+
+		if k.WholeWord {
+			if first, _ := utf8.DecodeRuneInString(k.Keyword); isWordRune(first) {
+				expr = `(?:^|[^\p{L}\p{M}\p{Nd}\p{Pc}])` + expr
+			}
+			if last, _ := utf8.DecodeLastRuneInString(k.Keyword); isWordRune(last) {
+				expr = expr + `(?:$|[^\p{L}\p{M}\p{Nd}\p{Pc}])`
+			}
+		}
+
+		k.regexp = regexp.MustCompile(`(?i)` + expr)
+	})
+	return k.regexp
+}
+
+// isWordRune returns true if r is a word constituent character, in the same sense as the posix [[:word:]] class in Ruby.
+func isWordRune(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.Pc)
+}
@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Import is a csv file of accounts, such as one exported from Mastodon, which an account has uploaded to follow, block or mute
+// all of those accounts. Imports are worked through in the background, a bit at a time, so that remote instances aren't flooded.
+type Import struct {
+	ID        string           `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	AccountID string           `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // which account is doing the importing?
+	Type      ImportType       `validate:"oneof=follows blocks mutes" bun:",nullzero,notnull"`                  // what should be done with the accounts in the file?
+	State     ImportState      `validate:"oneof=pending done failed" bun:",nullzero,notnull"`                   // has the whole file been worked through yet?
+	Path      string           `validate:"-" bun:",nullzero"`                                                   // where the uploaded file is in storage, until it's been worked through
+	Total     int              `validate:"min=0" bun:",nullzero"`                                               // how many accounts are in the file
+	Processed int              `validate:"min=0" bun:",nullzero"`                                               // how many accounts in the file have been worked through so far, whether it went well or not
+	Failures  []*ImportFailure `validate:"-" bun:"rel:has-many,join:id=import_id"`                              // accounts in the file that couldn't be found, or followed, blocked or muted
+}
+
+// ImportFailure is an account in an import file that couldn't be found, or followed, blocked or muted.
+type ImportFailure struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	ImportID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the import that the account was in
+	Address   string    `validate:"required" bun:",nullzero,notnull"`                                    // address of the account, as it was given in the file
+}
+
+// ImportType is what should be done with the accounts in an import.
+type ImportType string
+
+// Import types. Each one accepts a csv file in the same format that Mastodon exports.
+const (
+	ImportTypeFollows ImportType = "follows" // follow the accounts
+	ImportTypeBlocks  ImportType = "blocks"  // block the accounts
+	ImportTypeMutes   ImportType = "mutes"   // mute the accounts
+)
+
+// ImportState is how far along an import is.
+type ImportState string
+
+// Import states.
+const (
+	ImportStatePending ImportState = "pending" // the file is still being worked through
+	ImportStateDone    ImportState = "done"    // every account in the file has been worked through
+	ImportStateFailed  ImportState = "failed"  // the file couldn't be worked through at all
+)
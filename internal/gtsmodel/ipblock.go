@@ -0,0 +1,49 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// IPBlock represents a block on requests and/or sign-ups from an IP address or range of IP addresses.
+type IPBlock struct {
+	ID                 string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                        // id of this item in the database
+	CreatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                 // when was item created
+	UpdatedAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                 // when was item last updated
+	IP                 string    `validate:"required,cidr" bun:",nullzero,notnull,unique"`                                                        // range of addresses to block in CIDR notation, eg. '192.0.2.0/24'. Single addresses are stored as a /32 or /128.
+	Severity           string    `validate:"oneof=sign_up_requires_approval sign_up_block no_access" bun:",nullzero,notnull,default:'no_access'"` // how severe is this block? See the IPBlockSeverity constants.
+	Comment            string    `validate:"-" bun:""`                                                                                            // private comment on this block, viewable to admins
+	ExpiresAt          time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                                                   // when does the block stop applying? zero means it doesn't expire
+	CreatedByAccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                                  // Account ID of the creator of this block
+	CreatedByAccount   *Account  `validate:"-" bun:"rel:belongs-to"`                                                                              // Account corresponding to createdByAccountID
+}
+
+const (
+	// IPBlockSeveritySignUpRequiresApproval means that new accounts signed up from the range need to be approved by an admin,
+	// even if this instance doesn't otherwise require approval.
+	IPBlockSeveritySignUpRequiresApproval = "sign_up_requires_approval"
+	// IPBlockSeveritySignUpBlock means that new accounts can't be signed up from the range.
+	IPBlockSeveritySignUpBlock = "sign_up_block"
+	// IPBlockSeverityNoAccess means that the range can't make any requests to this instance at all.
+	IPBlockSeverityNoAccess = "no_access"
+)
+
+// Expired returns true if the block has passed its expiry time.
+func (b *IPBlock) Expired() bool {
+	return !b.ExpiresAt.IsZero() && !time.Now().Before(b.ExpiresAt)
+}
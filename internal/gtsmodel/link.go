@@ -0,0 +1,37 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Link represents a web page that has been linked to from the content of public statuses.
+type Link struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created, ie., when was the link first seen
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	URL       string    `validate:"required,url" bun:",unique,nullzero,notnull"`                         // the url that was linked to, eg https://example.org/some/article
+}
+
+// StatusToLink is an intermediate struct to facilitate the many2many relationship between a status and the links in its content.
+type StatusToLink struct {
+	StatusID string  `validate:"ulid,required" bun:"type:CHAR(26),unique:statuslink,nullzero,notnull"`
+	Status   *Status `validate:"-" bun:"rel:belongs-to"`
+	LinkID   string  `validate:"ulid,required" bun:"type:CHAR(26),unique:statuslink,nullzero,notnull"`
+	Link     *Link   `validate:"-" bun:"rel:belongs-to"`
+}
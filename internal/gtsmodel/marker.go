@@ -0,0 +1,46 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Marker is the last read position of a local account in one of its timelines, for syncing between clients.
+type Marker struct {
+	AccountID  string     `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull"`               // id of the account that this marker belongs to
+	Name       MarkerName `validate:"oneof=home notifications" bun:",pk,nullzero,notnull"`                 // name of the timeline that this marker is for
+	UpdatedAt  time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was the marker last set
+	Version    int        `validate:"-" bun:",notnull,default:0"`                                          // how many times the marker has been set, to help clients spot conflicting writes
+	LastReadID string     `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // id of the last status or notification that was read in the timeline
+}
+
+// MarkerName is the name of a timeline that a marker can be set for.
+type MarkerName string
+
+const (
+	// MarkerNameHome is the marker for the home timeline; its last read id is a status id.
+	MarkerNameHome MarkerName = "home"
+	// MarkerNameNotifications is the marker for notifications; its last read id is a notification id.
+	MarkerNameNotifications MarkerName = "notifications"
+)
+
+// MarkerNames are all the timelines that a marker can be set for.
+var MarkerNames = []MarkerName{
+	MarkerNameHome,
+	MarkerNameNotifications,
+}
@@ -31,7 +31,7 @@ type MediaAttachment struct {
 	StatusID          string           `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                        // ID of the status to which this is attached
 	URL               string           `validate:"required_without=RemoteURL,omitempty,url" bun:",nullzero"`                           // Where can the attachment be retrieved on *this* server
 	RemoteURL         string           `validate:"required_without=URL,omitempty,url" bun:",nullzero"`                                 // Where can the attachment be retrieved on a remote server (empty for local media)
-	Type              FileType         `validate:"oneof=Image Gif Audio Video Unknown" bun:",nullzero,notnull"`                        // Type of file (image/gif/audio/video)
+	Type              FileType         `validate:"oneof=Image Gif Gifv Audio Video Unknown" bun:",nullzero,notnull"`                   // Type of file (image/gif/gifv/audio/video)
 	FileMeta          FileMeta         `validate:"required" bun:",embed:filemeta_,nullzero,notnull"`                                   // Metadata about the file
 	AccountID         string           `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                 // To which account does this attachment belong
 	Account           *Account         `validate:"-" bun:"rel:has-one"`                                                                // Account corresponding to accountID
@@ -41,9 +41,11 @@ type MediaAttachment struct {
 	Processing        ProcessingStatus `validate:"oneof=0 1 2 666" bun:",notnull,default:2"`                                           // What is the processing status of this attachment
 	File              File             `validate:"required" bun:",embed:file_,notnull,nullzero"`                                       // metadata for the whole file
 	Thumbnail         Thumbnail        `validate:"required" bun:",embed:thumbnail_,notnull,nullzero"`                                  // small image thumbnail derived from a larger image, video, or audio file.
+	Variants          []Variant        `validate:"-" bun:",nullzero"`                                                                  // additional thumbnails derived from a larger image, in sizes configured by the instance admin.
 	Avatar            bool             `validate:"-" bun:",notnull,default:false"`                                                     // Is this attachment being used as an avatar?
 	Header            bool             `validate:"-" bun:",notnull,default:false"`                                                     // Is this attachment being used as a header?
 	Cached            bool             `validate:"-" bun:",notnull"`                                                                   // Is this attachment currently cached by our instance?
+	AccessCount       int              `validate:"-" bun:",notnull,default:0"`                                                         // How many times has this attachment been fetched from our instance?
 }
 
 // File refers to the metadata for the whole file
@@ -52,6 +54,7 @@ type File struct {
 	ContentType string    `validate:"required" bun:",nullzero,notnull"`                                    // MIME content type of the file.
 	FileSize    int       `validate:"required" bun:",notnull"`                                             // File size in bytes
 	UpdatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // When was the file last updated.
+	Checksum    string    `validate:"-" bun:",nullzero"`                                                   // Hex-encoded SHA-256 checksum of the file in storage.
 }
 
 // Thumbnail refers to a small image thumbnail derived from a larger image, video, or audio file.
@@ -62,6 +65,22 @@ type Thumbnail struct {
 	UpdatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // When was the file last updated.
 	URL         string    `validate:"required_without=RemoteURL,omitempty,url" bun:",nullzero"`            // What is the URL of the thumbnail on the local server
 	RemoteURL   string    `validate:"required_without=URL,omitempty,url" bun:",nullzero"`                  // What is the remote URL of the thumbnail (empty for local media)
+	Checksum    string    `validate:"-" bun:",nullzero"`                                                   // Hex-encoded SHA-256 checksum of the thumbnail in storage.
+}
+
+// Variant refers to an additional thumbnail derived from a larger image, in a size other than the small thumbnail.
+type Variant struct {
+	Name        string    `json:"name"`         // Name of the variant, used in its path and URL.
+	Path        string    `json:"path"`         // Path of the file in storage.
+	ContentType string    `json:"content_type"` // MIME content type of the file.
+	FileSize    int       `json:"file_size"`    // File size in bytes
+	UpdatedAt   time.Time `json:"updated_at"`   // When was the file last updated.
+	URL         string    `json:"url"`          // What is the URL of the variant on the local server
+	Width       int       `json:"width"`        // width in pixels
+	Height      int       `json:"height"`       // height in pixels
+	Size        int       `json:"size"`         // size in pixels (width * height)
+	Aspect      float64   `json:"aspect"`       // aspect ratio (width / height)
+	Checksum    string    `json:"checksum"`     // Hex-encoded SHA-256 checksum of the file in storage.
 }
 
 // ProcessingStatus refers to how far along in the processing stage the attachment is.
@@ -82,6 +101,7 @@ type FileType string
 const (
 	FileTypeImage   FileType = "Image"   // FileTypeImage is for jpegs and pngs
 	FileTypeGif     FileType = "Gif"     // FileTypeGif is for native gifs and soundless videos that have been converted to gifs
+	FileTypeGifv    FileType = "Gifv"    // FileTypeGifv is for silent, looping videos that have been converted from animated gifs
 	FileTypeAudio   FileType = "Audio"   // FileTypeAudio is for audio-only files (no video)
 	FileTypeVideo   FileType = "Video"   // FileTypeVideo is for files with audio + visual
 	FileTypeUnknown FileType = "Unknown" // FileTypeUnknown is for unknown file types (surprise surprise!)
@@ -0,0 +1,44 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// MediaJob represents a piece of remote media that's been queued for processing by the media manager, but not finished yet.
+// Jobs are kept in the database so that they can be queued again if GoToSocial is stopped before they're done.
+type MediaJob struct {
+	ID           string           `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`              // id of this item in the database
+	CreatedAt    time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`       // when was item created
+	Type         MediaJobType     `validate:"oneof=attachment emoji recache" bun:",nullzero,notnull"`                    // what kind of processing is this job for?
+	AttachmentID string           `validate:"required_without=EmojiID,omitempty,ulid" bun:"type:CHAR(26),nullzero"`      // id of the attachment being processed, if this is an attachment or recache job
+	EmojiID      string           `validate:"required_without=AttachmentID,omitempty,ulid" bun:"type:CHAR(26),nullzero"` // id of the emoji being processed, if this is an emoji job
+	RemoteURL    string           `validate:"required,url" bun:",nullzero,notnull"`                                      // where the media can be fetched from again
+	Attachment   *MediaAttachment `validate:"-" bun:",nullzero"`                                                         // the new attachment as it was before processing started, since it's not in the database until processing finishes
+	Emoji        *Emoji           `validate:"-" bun:",nullzero"`                                                         // the new emoji as it was before processing started, since it's not in the database until processing finishes
+}
+
+// MediaJobType is the kind of processing that a media job is for.
+type MediaJobType string
+
+// MediaJob types.
+const (
+	MediaJobTypeAttachment MediaJobType = "attachment" // processing a new attachment
+	MediaJobTypeEmoji      MediaJobType = "emoji"      // processing a new emoji
+	MediaJobTypeRecache    MediaJobType = "recache"    // recaching an existing attachment that was pruned
+)
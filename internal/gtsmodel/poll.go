@@ -0,0 +1,62 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+const (
+	// PollMinExpiresIn is the shortest time that a poll posted on this instance can be open for.
+	PollMinExpiresIn = 5 * time.Minute
+	// PollMaxExpiresIn is the longest time that a poll posted on this instance can be open for.
+	PollMaxExpiresIn = 31 * 24 * time.Hour
+)
+
+// Poll represents a poll attached to a status, either local or remote.
+type Poll struct {
+	ID          string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	StatusID    string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique"`           // id of the status this poll is attached to
+	Options     []string  `validate:"min=1" bun:",nullzero,notnull"`                                       // titles of the options that can be voted for, in order
+	Votes       []int     `validate:"-" bun:",nullzero"`                                                   // how many votes each option has received, in the same order as options
+	VotersCount int       `validate:"-" bun:",notnull,default:0"`                                          // how many different accounts have voted in this poll
+	Multiple    bool      `validate:"-" bun:",notnull,default:false"`                                      // can voters choose more than one option?
+	HideCounts  bool      `validate:"-" bun:",notnull,default:false"`                                      // should vote counts be hidden until the poll is closed?
+	ExpiresAt   time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when does voting end? zero means the poll doesn't end by itself
+	ClosedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when was voting closed? zero means the poll is still open
+}
+
+// Closed returns true if voting in this poll has been closed, or if it's past its expiry time and just hasn't been closed yet.
+func (p *Poll) Closed() bool {
+	if !p.ClosedAt.IsZero() {
+		return true
+	}
+	return !p.ExpiresAt.IsZero() && !time.Now().Before(p.ExpiresAt)
+}
+
+// PollVote represents one choice made by an account in a poll. Voting for several options in a multiple choice poll creates one vote per option.
+type PollVote struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	URI       string    `validate:"omitempty,url" bun:",nullzero,unique"`                                // activitypub uri of the vote, if it was federated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),unique:pollvote,nullzero,notnull"`  // id of the account that voted
+	Account   *Account  `validate:"-" bun:"rel:belongs-to"`                                              // account corresponding to accountID
+	PollID    string    `validate:"required,ulid" bun:"type:CHAR(26),unique:pollvote,nullzero,notnull"`  // id of the poll that was voted in
+	Choice    int       `validate:"min=0" bun:",unique:pollvote,notnull"`                                // index of the chosen option in the poll's options
+}
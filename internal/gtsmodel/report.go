@@ -0,0 +1,56 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Report models a report about an account, and optionally some of its statuses, for moderators to review.
+// Reports are either made on this instance, or forwarded to this instance from a remote instance as a
+// Flag activity, in which case the account that made the report is the one that sent the Flag.
+type Report struct {
+	ID                     string         `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt              time.Time      `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt              time.Time      `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	URI                    string         `validate:"required,url" bun:",nullzero,notnull,unique"`                         // activitypub URI of this report, or of the flag it was created from
+	AccountID              string         `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // which account created this report
+	Account                *Account       `validate:"-" bun:"rel:belongs-to"`                                              // account corresponding to AccountID
+	TargetAccountID        string         `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // which account is being reported
+	TargetAccount          *Account       `validate:"-" bun:"rel:belongs-to"`                                              // account corresponding to TargetAccountID
+	StatusIDs              []string       `validate:"dive,ulid" bun:"statuses,array"`                                      // database IDs of any statuses of the target account that are being reported
+	Category               ReportCategory `validate:"omitempty,oneof=spam violation other" bun:",nullzero"`                // what kind of problem was reported? empty means the same as other
+	RuleIDs                []string       `validate:"dive,ulid" bun:"rules,array"`                                         // database IDs of any rules of this instance that the target account is said to have broken
+	Comment                string         `validate:"-" bun:",nullzero"`                                                   // why was this report made?
+	Forwarded              bool           `validate:"-" bun:",notnull,default:false"`                                      // has this report been forwarded to the instance of the target account as a flag?
+	AssignedAccountID      string         `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // which moderator is looking into this report, if any
+	ActionTaken            string         `validate:"-" bun:",nullzero"`                                                   // what did the moderator who resolved this report do about it?
+	ActionTakenAt          time.Time      `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when was this report resolved? zero means it's still open
+	ActionTakenByAccountID string         `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // which moderator resolved this report
+}
+
+// ReportCategory is the kind of problem that a report is about.
+type ReportCategory string
+
+const (
+	// ReportCategorySpam means the reported account is posting spam.
+	ReportCategorySpam ReportCategory = "spam"
+	// ReportCategoryViolation means the reported account broke one or more rules of this instance.
+	ReportCategoryViolation ReportCategory = "violation"
+	// ReportCategoryOther means the report is about something else, which the comment on it should explain.
+	ReportCategoryOther ReportCategory = "other"
+)
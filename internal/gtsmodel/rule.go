@@ -0,0 +1,32 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// Rule is one of the rules of this instance, which accounts are shown when they sign up, and which reports can point to as having been broken.
+// Rules are never removed from the database, only marked as deleted, so that reports which point to them still make sense.
+type Rule struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Text      string    `validate:"required" bun:",nullzero,notnull"`                                    // text of the rule, as shown to accounts
+	Priority  int       `validate:"-" bun:",notnull,default:0"`                                          // rules are shown in ascending order of priority, and then in the order they were created
+	DeletedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // when was this rule deleted by an admin? zero means it's still in force
+}
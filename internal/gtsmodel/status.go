@@ -27,9 +27,11 @@ type Status struct {
 	ID                       string             `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                              // id of this item in the database
 	CreatedAt                time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                       // when was item created
 	UpdatedAt                time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                       // when was item last updated
+	EditedAt                 time.Time          `validate:"-" bun:"type:timestamptz,nullzero"`                                                         // when was this status last edited by its author, if ever
 	URI                      string             `validate:"required,url" bun:",unique,nullzero,notnull"`                                               // activitypub URI of this status
 	URL                      string             `validate:"url" bun:",nullzero"`                                                                       // web url for viewing this status
 	Content                  string             `validate:"-" bun:""`                                                                                  // content of this status; likely html-formatted but not guaranteed
+	ContentText              string             `validate:"-" bun:",nullzero"`                                                                         // content of this status with html removed, for full-text search
 	AttachmentIDs            []string           `validate:"dive,ulid" bun:"attachments,array"`                                                         // Database IDs of any media attachments associated with this status
 	Attachments              []*MediaAttachment `validate:"-" bun:"attached_media,rel:has-many"`                                                       // Attachments corresponding to attachmentIDs
 	TagIDs                   []string           `validate:"dive,ulid" bun:"tags,array"`                                                                // Database IDs of any tags used in this status
@@ -38,6 +40,8 @@ type Status struct {
 	Mentions                 []*Mention         `validate:"-" bun:"attached_mentions,rel:has-many"`                                                    // Mentions corresponding to mentionIDs
 	EmojiIDs                 []string           `validate:"dive,ulid" bun:"emojis,array"`                                                              // Database IDs of any emojis used in this status
 	Emojis                   []*Emoji           `validate:"-" bun:"attached_emojis,m2m:status_to_emojis"`                                              // Emojis corresponding to emojiIDs. https://bun.uptrace.dev/guide/relations.html#many-to-many-relation
+	PollID                   string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                               // Database ID of the poll attached to this status, if any
+	Poll                     *Poll              `validate:"-" bun:"-"`                                                                                 // Poll corresponding to pollID
 	Local                    bool               `validate:"-" bun:",notnull,default:false"`                                                            // is this status from a local account?
 	AccountID                string             `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                        // which account posted this status?
 	Account                  *Account           `validate:"-" bun:"rel:belongs-to"`                                                                    // account corresponding to accountID
@@ -51,6 +55,9 @@ type Status struct {
 	BoostOfAccountID         string             `validate:"required_with=BoostOfID,omitempty,ulid" bun:"type:CHAR(26),nullzero"`                       // id of the account that owns the boosted status
 	BoostOf                  *Status            `validate:"-" bun:"-"`                                                                                 // status that corresponds to boostOfID
 	BoostOfAccount           *Account           `validate:"-" bun:"rel:belongs-to"`                                                                    // account that corresponds to boostOfAccountID
+	QuoteOfID                string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                               // id of the status this status quotes
+	QuoteOfURI               string             `validate:"required_with=QuoteOfID,omitempty,url" bun:",nullzero"`                                     // activitypub uri of the status this status quotes
+	QuoteOf                  *Status            `validate:"-" bun:"-"`                                                                                 // status that corresponds to quoteOfID
 	ContentWarning           string             `validate:"-" bun:",nullzero"`                                                                         // cw string for this status
 	Visibility               Visibility         `validate:"oneof=public unlocked followers_only mutuals_only direct" bun:",nullzero,notnull"`          // visibility entry for this status
 	Sensitive                bool               `validate:"-" bun:",notnull,default:false"`                                                            // mark the status as sensitive?
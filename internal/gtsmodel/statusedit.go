@@ -0,0 +1,36 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// StatusEdit is a previous version of a status that has since been edited. The current version
+// of a status is always the status itself; the edits are only kept so that its history can be shown.
+type StatusEdit struct {
+	ID             string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when this version of the status was written
+	UpdatedAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	StatusID       string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // database id of the status that this is a previous version of
+	Content        string    `validate:"-" bun:""`                                                            // content of this version of the status
+	ContentWarning string    `validate:"-" bun:",nullzero"`                                                   // cw string of this version of the status
+	Text           string    `validate:"-" bun:""`                                                            // original text of this version of the status without formatting
+	Sensitive      bool      `validate:"-" bun:",notnull,default:false"`                                      // was this version of the status marked as sensitive?
+	AttachmentIDs  []string  `validate:"dive,ulid" bun:"attachments,array"`                                   // database IDs of the media attachments of this version of the status
+	EmojiIDs       []string  `validate:"dive,ulid" bun:"emojis,array"`                                        // database IDs of the emojis used in this version of the status
+}
@@ -0,0 +1,39 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// StatusReaction refers to an emoji reaction in the database, from one account, targeting the status of another account.
+// An account can react to the same status more than once, but only once with each emoji.
+type StatusReaction struct {
+	ID              string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`             // id of this item in the database
+	CreatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`      // when was item created
+	UpdatedAt       time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`      // when was item last updated
+	AccountID       string    `validate:"required,ulid" bun:"type:CHAR(26),unique:statusreaction,nullzero,notnull"` // id of the account that reacted
+	Account         *Account  `validate:"-" bun:"rel:belongs-to"`                                                   // account that reacted
+	TargetAccountID string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                       // id the account owning the reacted-to status
+	TargetAccount   *Account  `validate:"-" bun:"rel:belongs-to"`                                                   // account owning the reacted-to status
+	StatusID        string    `validate:"required,ulid" bun:"type:CHAR(26),unique:statusreaction,nullzero,notnull"` // database id of the status that has been reacted to
+	Status          *Status   `validate:"-" bun:"rel:belongs-to"`                                                   // the reacted-to status
+	Emoji           string    `validate:"required" bun:",unique:statusreaction,nullzero,notnull"`                   // the unicode emoji of the reaction, or the :shortcode: of a custom emoji
+	CustomEmojiID   string    `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                              // id of the custom emoji of the reaction, if it's a custom emoji that we know about
+	CustomEmoji     *Emoji    `validate:"-" bun:"rel:belongs-to"`                                                   // custom emoji corresponding to customEmojiID
+	URI             string    `validate:"required,url" bun:",nullzero,notnull"`                                     // ActivityPub URI of this reaction
+}
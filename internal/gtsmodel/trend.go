@@ -0,0 +1,55 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package gtsmodel
+
+import "time"
+
+// TrendType is the kind of thing that can be trending.
+type TrendType string
+
+const (
+	// TrendTypeTag is a hashtag used in public statuses.
+	TrendTypeTag TrendType = "tag"
+	// TrendTypeStatus is a public status that's being faved and boosted.
+	TrendTypeStatus TrendType = "status"
+	// TrendTypeLink is a web page linked to from public statuses.
+	TrendTypeLink TrendType = "link"
+)
+
+// TrendReview is the decision of an admin on whether a tag, status or link may be shown in trends.
+type TrendReview struct {
+	ID         string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                // id of this item in the database
+	CreatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`         // when was item created
+	UpdatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`         // when was item last updated, ie., when was it last reviewed
+	TargetType TrendType `validate:"oneof=tag status link" bun:",unique:trendreviewtarget,nullzero,notnull"`      // what kind of thing was reviewed
+	TargetID   string    `validate:"required,ulid" bun:"type:CHAR(26),unique:trendreviewtarget,nullzero,notnull"` // id of the tag, status or link that was reviewed
+	Approved   bool      `validate:"-" bun:",notnull,default:false"`                                              // may the target be shown in trends? false means it was rejected
+	AccountID  string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                          // id of the admin account that reviewed the target
+}
+
+// TrendUse is how many times one account used a tag or link in public statuses, or faved or boosted a public status,
+// on one day, counted towards trends.
+//
+// It isn't stored in the database, but is counted up in it.
+type TrendUse struct {
+	TargetID  string `bun:"target_id"`  // id of the tag, link or status that was used
+	AccountID string `bun:"account_id"` // id of the account that used it
+	Day       string `bun:"day"`        // the day it was used on, as YYYY-MM-DD in UTC
+	Uses      int    `bun:"uses"`       // how many times it was used that day
+}
@@ -56,4 +56,5 @@ type User struct {
 	Approved               bool         `validate:"-" bun:",notnull,default:false"`                                      // Has this user been approved by a moderator?
 	ResetPasswordToken     string       `validate:"required_with=ResetPasswordSentAt" bun:",nullzero"`                   // The generated token that the user can use to reset their password
 	ResetPasswordSentAt    time.Time    `validate:"required_with=ResetPasswordToken" bun:"type:timestamptz,nullzero"`    // When did we email the user their reset-password email?
+	MediaQuota             int          `validate:"-" bun:",nullzero"`                                                   // Override for the instance media storage quota in bytes. 0 means use the instance default, negative means unlimited.
 }
@@ -0,0 +1,59 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// checksum returns the hex-encoded SHA-256 checksum of b.
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// checksumReader wraps a reader, and keeps a running checksum
+// of everything that's read from it. This lets media be checksummed
+// while it's streamed into storage, without reading it twice.
+type checksumReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+// newChecksumReader returns a checksumReader for the given reader.
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{
+		r: r,
+		h: sha256.New(),
+	}
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n]) // writing to a hash never fails
+	return n, err
+}
+
+// sum returns the hex-encoded SHA-256 checksum of everything read so far.
+func (c *checksumReader) sum() string {
+	return hex.EncodeToString(c.h.Sum(nil))
+}
@@ -0,0 +1,103 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"fmt"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// amount of media attachments to select at a time from the db when deleting the media of an account
+const selectDeleteLimit = 20
+
+func (m *manager) DeleteAccountMedia(ctx context.Context, accountID string) (int, error) {
+	// page down through the attachments by ID and queue each page as we go; attachments
+	// that have already been deleted are above maxID, so they don't disturb the paging
+	var queued int
+	var maxID string
+	for {
+		page, err := m.db.GetAccountUnattached(ctx, accountID, maxID, selectDeleteLimit)
+		if err != nil && err != db.ErrNoEntries {
+			return queued, fmt.Errorf("DeleteAccountMedia: error getting attachments: %s", err)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		for _, attachment := range page {
+			a := attachment
+			m.pool.Enqueue(func(innerCtx context.Context) {
+				if err := m.deleteAttachment(innerCtx, a); err != nil {
+					logrus.Errorf("DeleteAccountMedia: error deleting attachment %s: %s", a.ID, err)
+				}
+			})
+		}
+
+		queued += len(page)
+		maxID = page[len(page)-1].ID
+	}
+
+	logrus.Infof("DeleteAccountMedia: queued %d attachments of account %s for deletion", queued, accountID)
+	return queued, nil
+}
+
+func (m *manager) DeleteMedia(ctx context.Context, attachmentID string) error {
+	attachment, err := m.db.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil
+		}
+		return fmt.Errorf("DeleteMedia: error getting attachment %s: %s", attachmentID, err)
+	}
+
+	if err := m.deleteAttachment(ctx, attachment); err != nil {
+		return fmt.Errorf("DeleteMedia: error deleting attachment %s: %s", attachmentID, err)
+	}
+	return nil
+}
+
+// deleteAttachment removes all the files of the given attachment from storage, and then removes the attachment itself.
+func (m *manager) deleteAttachment(ctx context.Context, attachment *gtsmodel.MediaAttachment) error {
+	paths := []string{attachment.File.Path, attachment.Thumbnail.Path}
+	for _, variant := range attachment.Variants {
+		paths = append(paths, variant.Path)
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		logrus.Tracef("deleteAttachment: deleting %s", path)
+		if err := m.storage.Delete(path); err != nil && err != storage.ErrNotFound {
+			return err
+		}
+	}
+
+	if err := m.db.DeleteByID(ctx, attachment.ID, attachment); err != nil && err != db.ErrNoEntries {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+type DeleteAccountMediaTestSuite struct {
+	MediaStandardTestSuite
+}
+
+func (suite *DeleteAccountMediaTestSuite) TestDeleteAccountMedia() {
+	ctx := context.Background()
+	avatar := suite.testAttachments["local_account_1_avatar"]
+	statusAttachment := suite.testAttachments["local_account_1_status_4_attachment_1"]
+
+	queued, err := suite.manager.DeleteAccountMedia(ctx, avatar.AccountID)
+	suite.NoError(err)
+	suite.Equal(3, queued)
+
+	// wait for the worker pool to get through the deletions
+	for i := 0; i < 100; i++ {
+		if _, err = suite.db.GetAttachmentByID(ctx, avatar.ID); err == db.ErrNoEntries {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	_, err = suite.storage.Get(avatar.File.Path)
+	suite.ErrorIs(err, storage.ErrNotFound)
+	_, err = suite.storage.Get(avatar.Thumbnail.Path)
+	suite.ErrorIs(err, storage.ErrNotFound)
+
+	// media attached to statuses should be left alone
+	_, err = suite.db.GetAttachmentByID(ctx, statusAttachment.ID)
+	suite.NoError(err)
+	_, err = suite.storage.Get(statusAttachment.File.Path)
+	suite.NoError(err)
+}
+
+func (suite *DeleteAccountMediaTestSuite) TestDeleteMedia() {
+	ctx := context.Background()
+	header := suite.testAttachments["local_account_1_header"]
+
+	err := suite.manager.DeleteMedia(ctx, header.ID)
+	suite.NoError(err)
+
+	// the header should be gone straight away
+	_, err = suite.db.GetAttachmentByID(ctx, header.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+	_, err = suite.storage.Get(header.File.Path)
+	suite.ErrorIs(err, storage.ErrNotFound)
+
+	// deleting it again shouldn't be an error
+	err = suite.manager.DeleteMedia(ctx, header.ID)
+	suite.NoError(err)
+}
+
+func TestDeleteAccountMediaTestSuite(t *testing.T) {
+	suite.Run(t, &DeleteAccountMediaTestSuite{})
+}
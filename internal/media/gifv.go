@@ -0,0 +1,102 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+// shouldConvertGifv returns true if a gif with the given
+// amount of frames and file size should be converted to gifv,
+// based on the values currently set in viper.
+func shouldConvertGifv(frames int, fileSize int) bool {
+	keys := config.Keys
+
+	if !viper.GetBool(keys.MediaGifvEnabled) {
+		return false
+	}
+
+	// there's no point converting a static gif into a video
+	if frames <= 1 {
+		return false
+	}
+
+	return fileSize >= viper.GetInt(keys.MediaGifvMinSize)
+}
+
+// deriveGifv converts the given animated gif into a silent, looping mp4 using
+// ffmpeg, and returns the bytes of the mp4, or an error if something goes wrong.
+//
+// The mp4 is written to a temporary file rather than streamed, so that ffmpeg can
+// move the metadata to the start of the file; this lets clients start playing the
+// video before they've downloaded all of it.
+func deriveGifv(ctx context.Context, ffmpegPath string, r io.Reader) ([]byte, error) {
+	out, err := os.CreateTemp("", "gotosocial-gifv-*."+mimeMp4)
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %s", err)
+	}
+	outPath := out.Name()
+	defer os.Remove(outPath)
+
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("error closing temp file: %s", err)
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpegPath,
+		"-hide_banner",
+		"-loglevel", "error",
+		"-f", "gif",
+		"-i", "pipe:0", // read the gif from stdin
+		"-an", // there's no audio in a gif
+		"-movflags", "+faststart",
+		"-pix_fmt", "yuv420p", // most widely supported pixel format
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2", // yuv420p needs even dimensions
+		"-f", mimeMp4,
+		"-y", outPath,
+	)
+	cmd.Stdin = r
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running ffmpeg: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	b, err := os.ReadFile(outPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading converted gifv: %s", err)
+	}
+
+	if len(b) == 0 {
+		return nil, errors.New("ffmpeg produced an empty gifv")
+	}
+
+	return b, nil
+}
@@ -32,18 +32,14 @@ import (
 	"github.com/nfnt/resize"
 )
 
-const (
-	thumbnailMaxWidth  = 512
-	thumbnailMaxHeight = 512
-)
-
 type imageMeta struct {
 	width    int
 	height   int
 	size     int
 	aspect   float64
-	blurhash string // defined only for calls to deriveThumbnail if createBlurhash is true
-	small    []byte // defined only for calls to deriveStaticEmoji or deriveThumbnail
+	frames   int    // defined only for calls to decodeGif
+	blurhash string // defined only for calls to deriveThumbnails if createBlurhash is true
+	small    []byte // defined only for calls to deriveStaticEmoji or deriveThumbnails
 }
 
 func decodeGif(r io.Reader) (*imageMeta, error) {
@@ -63,6 +59,7 @@ func decodeGif(r io.Reader) (*imageMeta, error) {
 		height: height,
 		size:   size,
 		aspect: aspect,
+		frames: len(gif.Image),
 	}, nil
 }
 
@@ -100,16 +97,18 @@ func decodeImage(r io.Reader, contentType string) (*imageMeta, error) {
 	}, nil
 }
 
-// deriveThumbnail returns a byte slice and metadata for a thumbnail
-// of a given jpeg, png, or gif, or an error if something goes wrong.
+// deriveThumbnails returns a byte slice and metadata for a thumbnail of a given jpeg,
+// png, or gif for each of the given sizes, or an error if something goes wrong.
+// The image is only decoded once, and the returned thumbnails will be in the same
+// order as the given sizes.
 //
 // If createBlurhash is true, then a blurhash will also be generated from a tiny
-// version of the image. This costs precious CPU cycles, so only use it if you
+// version of the first thumbnail. This costs precious CPU cycles, so only use it if you
 // really need a blurhash and don't have one already.
 //
-// If createBlurhash is false, then the blurhash field on the returned ImageAndMeta
+// If createBlurhash is false, then the blurhash field on the returned imageMetas
 // will be an empty string.
-func deriveThumbnail(r io.Reader, contentType string, createBlurhash bool) (*imageMeta, error) {
+func deriveThumbnails(r io.Reader, contentType string, createBlurhash bool, sizes []thumbnailSize) ([]*imageMeta, error) {
 	var i image.Image
 	var err error
 
@@ -132,7 +131,23 @@ func deriveThumbnail(r io.Reader, contentType string, createBlurhash bool) (*ima
 		return nil, errors.New("processed image was nil")
 	}
 
-	thumb := resize.Thumbnail(thumbnailMaxWidth, thumbnailMaxHeight, i, resize.NearestNeighbor)
+	thumbs := make([]*imageMeta, 0, len(sizes))
+	for idx, ts := range sizes {
+		// only the first thumbnail needs a blurhash, since they're all of the same image
+		im, err := deriveThumbnail(i, ts, createBlurhash && idx == 0)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving %s thumbnail: %s", ts.size, err)
+		}
+		thumbs = append(thumbs, im)
+	}
+
+	return thumbs, nil
+}
+
+// deriveThumbnail resizes the given decoded image to fit within the given thumbnail
+// size, and returns the jpeg encoded thumbnail along with its metadata.
+func deriveThumbnail(i image.Image, ts thumbnailSize, createBlurhash bool) (*imageMeta, error) {
+	thumb := resize.Thumbnail(uint(ts.maxWidth), uint(ts.maxHeight), i, resize.NearestNeighbor)
 	width := thumb.Bounds().Size().X
 	height := thumb.Bounds().Size().Y
 	size := width * height
@@ -184,6 +199,11 @@ func deriveStaticEmoji(r io.Reader, contentType string) (*imageMeta, error) {
 		if err != nil {
 			return nil, err
 		}
+	case mimeImageSvg:
+		i, err = rasterizeSVG(r)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("content type %s not allowed for emoji", contentType)
 	}
@@ -0,0 +1,303 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// IntegrityReport describes the results of checking a sample of stored media for missing or corrupted files.
+type IntegrityReport struct {
+	// Started is when the check began.
+	Started time.Time
+	// Finished is when the check ended.
+	Finished time.Time
+	// Checked is the number of attachments that were checked.
+	Checked int
+	// Backfilled is the number of attachments that didn't have checksums stored yet, and had them filled in.
+	Backfilled int
+	// Missing contains the IDs of attachments that had files missing from storage.
+	Missing []string
+	// Corrupted contains the IDs of attachments that had files that didn't match their checksums.
+	Corrupted []string
+	// Requeued contains the IDs of remote attachments that were queued to be fetched again.
+	Requeued []string
+	// CheckedEmojis is the number of emojis that were checked.
+	CheckedEmojis int
+	// BackfilledEmojis is the number of emojis that didn't have checksums stored yet, and had them filled in.
+	BackfilledEmojis int
+	// MissingEmojis contains the IDs of emojis that had files missing from storage.
+	MissingEmojis []string
+	// CorruptedEmojis contains the IDs of emojis that had files that didn't match their checksums.
+	CorruptedEmojis []string
+	// RequeuedEmojis contains the IDs of remote emojis that were queued to be fetched again.
+	RequeuedEmojis []string
+}
+
+// fileCheck is the outcome of checking a single file in storage. Outcomes are ordered from best to worst.
+type fileCheck int
+
+const (
+	fileOK        fileCheck = iota // file is present and matches its checksum
+	fileNoSum                      // file is present, but there was no checksum to check it against
+	fileMissing                    // file isn't in storage
+	fileCorrupted                  // file doesn't match its checksum
+)
+
+func (m *manager) VerifyMedia(ctx context.Context, sampleSize int) (*IntegrityReport, error) {
+	report := &IntegrityReport{
+		Started:         time.Now(),
+		Missing:         []string{},
+		Corrupted:       []string{},
+		Requeued:        []string{},
+		MissingEmojis:   []string{},
+		CorruptedEmojis: []string{},
+		RequeuedEmojis:  []string{},
+	}
+
+	attachments, err := m.db.GetRandomCached(ctx, sampleSize)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, fmt.Errorf("VerifyMedia: error selecting attachments to check: %s", err)
+	}
+
+	for _, attachment := range attachments {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("VerifyMedia: stopped early: %s", err)
+		}
+
+		if err := m.verifyOne(ctx, attachment, report); err != nil {
+			logrus.Errorf("VerifyMedia: error checking attachment %s: %s", attachment.ID, err)
+			continue
+		}
+		report.Checked++
+	}
+
+	emojis, err := m.db.GetRandomEmojis(ctx, sampleSize)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, fmt.Errorf("VerifyMedia: error selecting emojis to check: %s", err)
+	}
+
+	for _, emoji := range emojis {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("VerifyMedia: stopped early: %s", err)
+		}
+
+		if err := m.verifyEmoji(ctx, emoji, report); err != nil {
+			logrus.Errorf("VerifyMedia: error checking emoji %s: %s", emoji.ID, err)
+			continue
+		}
+		report.CheckedEmojis++
+	}
+
+	report.Finished = time.Now()
+
+	m.integrityMu.Lock()
+	m.lastIntegrityReport = report
+	m.integrityMu.Unlock()
+
+	logrus.Infof("VerifyMedia: checked %d attachments: %d missing, %d corrupted, %d requeued", report.Checked, len(report.Missing), len(report.Corrupted), len(report.Requeued))
+	logrus.Infof("VerifyMedia: checked %d emojis: %d missing, %d corrupted, %d requeued", report.CheckedEmojis, len(report.MissingEmojis), len(report.CorruptedEmojis), len(report.RequeuedEmojis))
+	return report, nil
+}
+
+func (m *manager) LastIntegrityReport() *IntegrityReport {
+	m.integrityMu.Lock()
+	defer m.integrityMu.Unlock()
+	return m.lastIntegrityReport
+}
+
+func (m *manager) SetRecacheDataFunc(data RecacheDataFunc) {
+	m.integrityMu.Lock()
+	defer m.integrityMu.Unlock()
+	m.recacheData = data
+}
+
+// verifyOne checks the full size file, thumbnail, and any variants of the given attachment, and adds any problems to report.
+func (m *manager) verifyOne(ctx context.Context, attachment *gtsmodel.MediaAttachment, report *IntegrityReport) error {
+	fileResult, fileSum, err := m.checkFile(attachment.File.Path, attachment.File.Checksum)
+	if err != nil {
+		return err
+	}
+
+	// media of unknown types is stored without a thumbnail, so there's nothing else to check
+	thumbResult, thumbSum := fileOK, ""
+	if attachment.Type != gtsmodel.FileTypeUnknown {
+		thumbResult, thumbSum, err = m.checkFile(attachment.Thumbnail.Path, attachment.Thumbnail.Checksum)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the worst result of any variant stands for all of them
+	variantsResult := fileOK
+	variantSums := make([]string, len(attachment.Variants))
+	for i, variant := range attachment.Variants {
+		var variantResult fileCheck
+		variantResult, variantSums[i], err = m.checkFile(variant.Path, variant.Checksum)
+		if err != nil {
+			return err
+		}
+		if variantResult > variantsResult {
+			variantsResult = variantResult
+		}
+	}
+
+	switch {
+	case fileResult == fileMissing || thumbResult == fileMissing || variantsResult == fileMissing:
+		report.Missing = append(report.Missing, attachment.ID)
+		integrityProblems.WithLabelValues(integrityMissing).Inc()
+	case fileResult == fileCorrupted || thumbResult == fileCorrupted || variantsResult == fileCorrupted:
+		report.Corrupted = append(report.Corrupted, attachment.ID)
+		integrityProblems.WithLabelValues(integrityCorrupted).Inc()
+	case fileResult == fileNoSum || thumbResult == fileNoSum || variantsResult == fileNoSum:
+		// media from before checksums were stored; trust what's there now, so it can be checked next time
+		attachment.File.Checksum = fileSum
+		attachment.Thumbnail.Checksum = thumbSum
+		for i := range attachment.Variants {
+			attachment.Variants[i].Checksum = variantSums[i]
+		}
+		if err := m.db.UpdateByPrimaryKey(ctx, attachment); err != nil {
+			return fmt.Errorf("error storing checksums: %s", err)
+		}
+		report.Backfilled++
+		return nil
+	default:
+		return nil
+	}
+
+	if attachment.RemoteURL == "" {
+		// there's nowhere else to get local media from, so all we can do is shout about it
+		logrus.Errorf("verifyOne: files for local attachment %s are missing or corrupted, and can't be fetched again", attachment.ID)
+		return nil
+	}
+
+	// get rid of whatever's left of the remote media, so it'll be fetched again when it's next needed
+	if err := m.PruneOne(ctx, attachment); err != nil {
+		return fmt.Errorf("error removing damaged remote media: %s", err)
+	}
+
+	m.integrityMu.Lock()
+	data := m.recacheData
+	m.integrityMu.Unlock()
+
+	// if we know how to fetch remote media, we don't need to wait for it to be needed
+	if data != nil {
+		if _, _, err := m.RecacheMedia(ctx, data(attachment.RemoteURL), nil, attachment.ID); err != nil {
+			return fmt.Errorf("error queueing recache: %s", err)
+		}
+		report.Requeued = append(report.Requeued, attachment.ID)
+	}
+
+	return nil
+}
+
+// verifyEmoji checks the image and static image of the given emoji, and adds any problems to report.
+func (m *manager) verifyEmoji(ctx context.Context, emoji *gtsmodel.Emoji, report *IntegrityReport) error {
+	imageResult, imageSum, err := m.checkFile(emoji.ImagePath, emoji.ImageChecksum)
+	if err != nil {
+		return err
+	}
+
+	staticResult, staticSum, err := m.checkFile(emoji.ImageStaticPath, emoji.ImageStaticChecksum)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case imageResult == fileMissing || staticResult == fileMissing:
+		report.MissingEmojis = append(report.MissingEmojis, emoji.ID)
+		integrityProblems.WithLabelValues(integrityMissing).Inc()
+	case imageResult == fileCorrupted || staticResult == fileCorrupted:
+		report.CorruptedEmojis = append(report.CorruptedEmojis, emoji.ID)
+		integrityProblems.WithLabelValues(integrityCorrupted).Inc()
+	case imageResult == fileNoSum || staticResult == fileNoSum:
+		// emojis from before checksums were stored; trust what's there now, so they can be checked next time
+		emoji.ImageChecksum = imageSum
+		emoji.ImageStaticChecksum = staticSum
+		if err := m.db.UpdateByPrimaryKey(ctx, emoji); err != nil {
+			return fmt.Errorf("error storing checksums: %s", err)
+		}
+		report.BackfilledEmojis++
+		return nil
+	default:
+		return nil
+	}
+
+	if emoji.ImageRemoteURL == "" {
+		logrus.Errorf("verifyEmoji: files for local emoji %s are missing or corrupted, and can't be fetched again", emoji.ID)
+		return nil
+	}
+
+	m.integrityMu.Lock()
+	data := m.recacheData
+	m.integrityMu.Unlock()
+
+	// refreshing a remote emoji replaces whatever's left of its files
+	if data != nil {
+		if _, err := m.RefreshEmoji(ctx, data(emoji.ImageRemoteURL), nil, emoji, emoji.ImageRemoteURL); err != nil {
+			return fmt.Errorf("error queueing refresh: %s", err)
+		}
+		report.RequeuedEmojis = append(report.RequeuedEmojis, emoji.ID)
+	}
+
+	return nil
+}
+
+// checkFile compares the file at the given path in storage with the given checksum, and returns
+// the result along with the checksum of what's actually in storage. An error is only returned if
+// the file couldn't be checked at all.
+func (m *manager) checkFile(path string, expected string) (fileCheck, string, error) {
+	stored, err := m.storage.GetStream(path)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fileMissing, "", nil
+		}
+		return 0, "", fmt.Errorf("error fetching %s from storage: %s", path, err)
+	}
+
+	defer func() {
+		if err := stored.Close(); err != nil {
+			logrus.Errorf("checkFile: error closing stored file %s: %s", path, err)
+		}
+	}()
+
+	checksummed := newChecksumReader(stored)
+	if _, err := io.Copy(io.Discard, checksummed); err != nil {
+		return 0, "", fmt.Errorf("error reading %s from storage: %s", path, err)
+	}
+	actual := checksummed.sum()
+
+	switch expected {
+	case "":
+		return fileNoSum, actual, nil
+	case actual:
+		return fileOK, actual, nil
+	default:
+		return fileCorrupted, actual, nil
+	}
+}
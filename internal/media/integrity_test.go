@@ -0,0 +1,187 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type IntegrityTestSuite struct {
+	MediaStandardTestSuite
+}
+
+func (suite *IntegrityTestSuite) TestVerifyMediaBackfill() {
+	ctx := context.Background()
+
+	// nothing has been checked yet
+	suite.Nil(suite.manager.LastIntegrityReport())
+	testEmojis := testrig.NewTestEmojis()
+
+	var cached int
+	for _, a := range suite.testAttachments {
+		if a.Cached {
+			cached++
+		}
+	}
+
+	// test attachments don't have checksums, so they should all be filled in
+	report, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Equal(cached, report.Checked)
+	suite.Equal(cached, report.Backfilled)
+	suite.Empty(report.Missing)
+	suite.Empty(report.Corrupted)
+	suite.Empty(report.Requeued)
+	suite.Equal(len(testEmojis), report.CheckedEmojis)
+	suite.Equal(len(testEmojis), report.BackfilledEmojis)
+
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, suite.testAttachments["local_account_1_unattached_1"].ID)
+	suite.NoError(err)
+	suite.Len(dbAttachment.File.Checksum, 64)
+	suite.Len(dbAttachment.Thumbnail.Checksum, 64)
+
+	dbEmoji := &gtsmodel.Emoji{}
+	suite.NoError(suite.db.GetByID(ctx, testEmojis["rainbow"].ID, dbEmoji))
+	suite.Len(dbEmoji.ImageChecksum, 64)
+	suite.Len(dbEmoji.ImageStaticChecksum, 64)
+
+	// now everything should just check out
+	report, err = suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Equal(cached, report.Checked)
+	suite.Zero(report.Backfilled)
+	suite.Empty(report.Missing)
+	suite.Empty(report.Corrupted)
+	suite.Equal(len(testEmojis), report.CheckedEmojis)
+	suite.Zero(report.BackfilledEmojis)
+	suite.Equal(report, suite.manager.LastIntegrityReport())
+}
+
+func (suite *IntegrityTestSuite) TestVerifyMediaCorruptedRemote() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	// fill in checksums first
+	_, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+
+	// mess up the stored file
+	suite.NoError(suite.storage.Delete(testAttachment.File.Path))
+	suite.NoError(suite.storage.Put(testAttachment.File.Path, []byte("this isn't a jpeg")))
+
+	suite.manager.SetRecacheDataFunc(func(remoteURL string) media.DataFunc {
+		return func(_ context.Context) (io.Reader, int, error) {
+			// load bytes from a test image
+			b, err := os.ReadFile("../../testrig/media/thoughtsofdog-original.jpeg")
+			if err != nil {
+				panic(err)
+			}
+			return bytes.NewBuffer(b), len(b), nil
+		}
+	})
+
+	report, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Empty(report.Missing)
+	suite.Equal([]string{testAttachment.ID}, report.Corrupted)
+	suite.Equal([]string{testAttachment.ID}, report.Requeued)
+
+	// recaching happens in the background, so wait for it to finish
+	var recachedAttachment *gtsmodel.MediaAttachment
+	for i := 0; i < 50; i++ {
+		recachedAttachment, err = suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+		suite.NoError(err)
+		if recachedAttachment.Cached {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	suite.True(recachedAttachment.Cached)
+
+	// the recached file should be whole again
+	stored, err := suite.storage.Get(recachedAttachment.File.Path)
+	suite.NoError(err)
+	suite.NotEqual([]byte("this isn't a jpeg"), stored)
+}
+
+func (suite *IntegrityTestSuite) TestVerifyMediaMissingLocal() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["local_account_1_unattached_1"]
+
+	suite.NoError(suite.storage.Delete(testAttachment.Thumbnail.Path))
+
+	report, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Equal([]string{testAttachment.ID}, report.Missing)
+	suite.Empty(report.Corrupted)
+	suite.Empty(report.Requeued)
+
+	// there's nowhere to get local media from again, so it should be left alone
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+	suite.NoError(err)
+	suite.True(dbAttachment.Cached)
+}
+
+func (suite *IntegrityTestSuite) TestVerifyMediaCorruptedVariant() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["local_account_1_unattached_1"]
+
+	// give the attachment a variant, with a checksum that its file doesn't match
+	testAttachment.Variants = []gtsmodel.Variant{{
+		Name:     "medium",
+		Path:     "01F8MH1H7YV1Z7D2C8K2730QBF/attachment/medium/01F8MH8RMYQ6MSNY3JM2XT1CQ5.jpeg",
+		Checksum: "0000000000000000000000000000000000000000000000000000000000000000",
+	}}
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, testAttachment))
+	suite.NoError(suite.storage.Put(testAttachment.Variants[0].Path, []byte("this isn't the right jpeg")))
+
+	report, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Empty(report.Missing)
+	suite.Equal([]string{testAttachment.ID}, report.Corrupted)
+}
+
+func (suite *IntegrityTestSuite) TestVerifyMediaMissingEmoji() {
+	ctx := context.Background()
+	testEmoji := testrig.NewTestEmojis()["rainbow"]
+
+	suite.NoError(suite.storage.Delete(testEmoji.ImageStaticPath))
+
+	report, err := suite.manager.VerifyMedia(ctx, 0)
+	suite.NoError(err)
+	suite.Equal([]string{testEmoji.ID}, report.MissingEmojis)
+	suite.Empty(report.CorruptedEmojis)
+
+	// local emojis can't be fetched again, so they're just reported
+	suite.Empty(report.RequeuedEmojis)
+}
+
+func TestIntegrityTestSuite(t *testing.T) {
+	suite.Run(t, &IntegrityTestSuite{})
+}
@@ -0,0 +1,111 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrJobTimeout is returned when processing a piece of media
+// takes longer than the configured media job timeout.
+var ErrJobTimeout = errors.New("media job timed out")
+
+// maxAbandonedJobs is the most jobs that can be left running in the background after
+// timing out at once. Jobs that time out past this are waited on by their worker instead,
+// so that jobs stuck on something that doesn't respect their context can't pile up forever.
+const maxAbandonedJobs = 64
+
+// abandonedJobs holds a token for each job that's been left running after timing out.
+var abandonedJobs = make(chan struct{}, maxAbandonedJobs)
+
+// runJob runs the given load function for a job of the given type, and returns its error.
+//
+// If timeout is greater than 0 and the job takes longer than that, runJob cancels the
+// context passed to load and calls abort with an error wrapping ErrJobTimeout. If abort
+// returns false, the job had already stored its result, so runJob waits for load to return
+// and returns its error as usual. Otherwise runJob returns the timeout error without waiting
+// for load to return, as long as fewer than maxAbandonedJobs jobs have been left running,
+// which frees up the worker that's running the job even if load is stuck doing something
+// that doesn't respect its context. Whenever load does return, cleanup is called to remove
+// anything it stored along the way, since nothing it did after being aborted will be used.
+func runJob(ctx context.Context, timeout time.Duration, jobType string, load func(context.Context) error, abort func(error) bool, cleanup func()) error {
+	if timeout <= 0 {
+		return load(ctx)
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- load(jobCtx)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-jobCtx.Done():
+	}
+
+	// load might have finished just as the deadline passed, in which case its result stands
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+
+	var err error
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// the worker pool is stopping, so this isn't the job's fault
+		err = fmt.Errorf("media job cancelled: %s", ctxErr)
+	} else {
+		err = fmt.Errorf("%w after %s", ErrJobTimeout, timeout)
+	}
+
+	if !abort(err) {
+		// load finished storing its result just as the deadline passed, so its result stands
+		return <-errs
+	}
+
+	if ctx.Err() == nil {
+		processingTimeouts.WithLabelValues(jobType).Inc()
+	}
+
+	select {
+	case abandonedJobs <- struct{}{}:
+		// load might still be writing to storage before it notices that it's been given up on
+		go func() {
+			<-errs
+			cleanup()
+			<-abandonedJobs
+		}()
+	default:
+		// too many jobs have been left running already, so wait for this one here
+		logrus.Warnf("runJob: too many timed out media jobs still running, waiting for %s job to return", jobType)
+		<-errs
+		cleanup()
+	}
+
+	return err
+}
@@ -0,0 +1,126 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunJobCleansUpLateSuccess(t *testing.T) {
+	release := make(chan struct{})
+	cleaned := make(chan struct{})
+
+	err := runJob(context.Background(), 10*time.Millisecond, jobAttachment, func(_ context.Context) error {
+		<-release
+		return nil
+	}, func(error) bool {
+		return true
+	}, func() {
+		close(cleaned)
+	})
+	if !errors.Is(err, ErrJobTimeout) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+
+	// the job succeeding after it was given up on doesn't mean its result gets used
+	close(release)
+	select {
+	case <-cleaned:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup wasn't called after the job returned successfully")
+	}
+}
+
+func TestRunJobAlreadyStored(t *testing.T) {
+	release := make(chan struct{})
+	var cleanups int32
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	// the job had stored its result before it could be aborted, so runJob waits for it
+	err := runJob(context.Background(), 10*time.Millisecond, jobAttachment, func(_ context.Context) error {
+		<-release
+		return nil
+	}, func(error) bool {
+		return false
+	}, func() {
+		atomic.AddInt32(&cleanups, 1)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if atomic.LoadInt32(&cleanups) != 0 {
+		t.Fatal("cleanup was called for a job whose result was stored")
+	}
+}
+
+func TestRunJobAbandonedCap(t *testing.T) {
+	release := make(chan struct{})
+	var cleanups int32
+
+	load := func(_ context.Context) error {
+		<-release
+		return nil
+	}
+	abort := func(error) bool {
+		return true
+	}
+	cleanup := func() {
+		atomic.AddInt32(&cleanups, 1)
+	}
+
+	// fill up the abandoned jobs
+	for i := 0; i < maxAbandonedJobs; i++ {
+		if err := runJob(context.Background(), time.Millisecond, jobAttachment, load, abort, cleanup); !errors.Is(err, ErrJobTimeout) {
+			t.Fatalf("expected a timeout error, got %v", err)
+		}
+	}
+
+	// the next job that times out is waited on instead of being left running
+	returned := make(chan error)
+	go func() {
+		returned <- runJob(context.Background(), time.Millisecond, jobAttachment, load, abort, cleanup)
+	}()
+
+	select {
+	case err := <-returned:
+		t.Fatalf("runJob returned %v before the job did", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-returned; !errors.Is(err, ErrJobTimeout) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&cleanups) != maxAbandonedJobs+1 || len(abandonedJobs) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d cleanups and no abandoned jobs, got %d and %d", maxAbandonedJobs+1, atomic.LoadInt32(&cleanups), len(abandonedJobs))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
+)
+
+// limits are the size and dimension limits that a piece of media is checked against while it's
+// processed. They're read from viper when processing starts, so that a piece of media will be
+// checked against the same limits all the way through processing.
+type limits struct {
+	maxSize     int // maximum size of the media in bytes, or 0 for no limit
+	maxPixels   int // maximum width multiplied by height of the media, or 0 for no limit
+	maxDuration int // maximum duration of the media in seconds, or 0 for no limit
+}
+
+// imageLimits returns the limits that apply to images, based on the values currently set in viper.
+func imageLimits() limits {
+	return limits{
+		maxSize:   viper.GetInt(config.Keys.MediaImageMaxSize),
+		maxPixels: viper.GetInt(config.Keys.MediaImageMaxPixels),
+	}
+}
+
+// videoLimits returns the limits that apply to videos, based on the values currently set in viper.
+func videoLimits() limits {
+	return limits{
+		maxSize:     viper.GetInt(config.Keys.MediaVideoMaxSize),
+		maxPixels:   viper.GetInt(config.Keys.MediaVideoMaxPixels),
+		maxDuration: viper.GetInt(config.Keys.MediaVideoMaxDuration),
+	}
+}
+
+// emojiLimits returns the limits that apply to emoji, based on the values currently set in viper.
+func emojiLimits() limits {
+	return limits{
+		maxSize:   viper.GetInt(config.Keys.MediaEmojiMaxSize),
+		maxPixels: viper.GetInt(config.Keys.MediaEmojiMaxPixels),
+	}
+}
+
+// unknownLimits returns the limits that apply to remote media of types that can't be processed,
+// and are stored as they are instead, based on the values currently set in viper. If the max size
+// is 0, then media of unknown types shouldn't be stored at all.
+func unknownLimits() limits {
+	return limits{
+		maxSize: viper.GetInt(config.Keys.MediaUnknownMaxSize),
+	}
+}
+
+// checkDimensions reads just enough of the image in r to work out its dimensions, and returns an
+// error wrapping ErrTooLarge if it has more pixels than allowed. This means that images that would
+// take up a huge amount of memory can be rejected before they're decoded. Mp4 videos are checked
+// against the max duration too; other types of video can't be checked, so they're let through.
+func (l limits) checkDimensions(r io.Reader, contentType string) error {
+	if l.maxPixels == 0 && l.maxDuration == 0 {
+		return nil
+	}
+
+	var c image.Config
+	var err error
+
+	switch contentType {
+	case mimeImageJpeg:
+		c, err = jpeg.DecodeConfig(r)
+	case mimeImageGif:
+		c, err = gif.DecodeConfig(r)
+	case mimeImagePng:
+		c, err = png.DecodeConfig(&PNGAncillaryChunkStripper{Reader: r})
+	case mimeVideoMp4, mimeVideoQuicktime:
+		return l.checkMp4(r, contentType)
+	default:
+		// not a type we know how to get dimensions for
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading dimensions of %s: %s", contentType, err)
+	}
+
+	if l.maxPixels != 0 && c.Width*c.Height > l.maxPixels {
+		return fmt.Errorf("%w: %dx%d is more than the max of %d pixels", ErrTooLarge, c.Width, c.Height, l.maxPixels)
+	}
+
+	return nil
+}
+
+// checkMp4 checks the dimensions and duration of the mp4 video in r.
+func (l limits) checkMp4(r io.Reader, contentType string) error {
+	info, err := readMp4Info(r)
+	if err != nil {
+		return fmt.Errorf("error reading dimensions of %s: %s", contentType, err)
+	}
+
+	if l.maxPixels != 0 && info.width*info.height > l.maxPixels {
+		return fmt.Errorf("%w: %dx%d is more than the max of %d pixels", ErrTooLarge, info.width, info.height, l.maxPixels)
+	}
+
+	if l.maxDuration != 0 && info.duration > float64(l.maxDuration) {
+		return fmt.Errorf("%w: %.1f seconds is longer than the max of %d seconds", ErrTooLarge, info.duration, l.maxDuration)
+	}
+
+	return nil
+}
+
+// checkStoredDimensions checks the dimensions of the image at the given path in storage against the given limits.
+// If the image has too many pixels, or its dimensions can't be read, it's removed from storage and an error is returned.
+func checkStoredDimensions(s *gtsstorage.Driver, path string, contentType string, l limits) error {
+	if l.maxPixels == 0 && l.maxDuration == 0 {
+		return nil
+	}
+
+	stored, err := s.GetStream(path)
+	if err != nil {
+		return fmt.Errorf("error fetching file from storage: %s", err)
+	}
+
+	checkErr := l.checkDimensions(stored, contentType)
+
+	if err := stored.Close(); err != nil {
+		logrus.Errorf("checkStoredDimensions: error closing stored file %s: %s", path, err)
+	}
+
+	if checkErr != nil {
+		if err := s.Delete(path); err != nil && err != storage.ErrNotFound {
+			logrus.Errorf("checkStoredDimensions: error removing stored file %s: %s", path, err)
+		}
+		return checkErr
+	}
+
+	return nil
+}
@@ -23,15 +23,17 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
 	"codeberg.org/gruf/go-runners"
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 )
 
 // Manager provides an interface for managing media: parsing, storing, and retrieving media objects like photos, videos, and gifs.
@@ -48,6 +50,14 @@ type Manager interface {
 	// accountID should be the account that the media belongs to.
 	//
 	// ai is optional and can be nil. Any additional information about the attachment provided will be put in the database.
+	//
+	// The media will be processed by the manager's worker pool. Callers who need the finished attachment can either call
+	// LoadAttachment on the returned ProcessingMedia, which will do any remaining processing straight away, or wait for
+	// the worker pool to finish processing by receiving from ProcessingMedia.Done().
+	//
+	// If ai includes a remote URL, and media from that URL is already being processed for the same account, then the
+	// ProcessingMedia that's already in progress will be returned instead of fetching and processing the media again.
+	// In that case, the given data, postData, and ai aren't used.
 	ProcessMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, accountID string, ai *AdditionalMediaInfo) (*ProcessingMedia, error)
 	// ProcessEmoji begins the process of decoding and storing the given data as an emoji.
 	// It will return a pointer to a ProcessingEmoji struct upon which further actions can be performed, such as getting
@@ -65,22 +75,96 @@ type Manager interface {
 	// uri is the ActivityPub URI/ID of the emoji.
 	//
 	// ai is optional and can be nil. Any additional information about the emoji provided will be put in the database.
+	//
+	// As with ProcessMedia, callers can either call LoadEmoji on the returned ProcessingEmoji,
+	// or wait for the worker pool to finish processing by receiving from ProcessingEmoji.Done().
 	ProcessEmoji(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, shortcode string, id string, uri string, ai *AdditionalEmojiInfo) (*ProcessingEmoji, error)
+	// RefreshEmoji refetches and reprocesses the image of an existing remote emoji, for when the image has
+	// changed on the instance the emoji came from. The emoji keeps its ID and files, and is updated in the
+	// database once processing is done. imageRemoteURL is the new URL of the image.
+	//
+	// Refreshes aren't persisted as jobs, since a refresh that didn't finish can just be tried again the
+	// next time the emoji is seen.
+	RefreshEmoji(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, emoji *gtsmodel.Emoji, imageRemoteURL string) (*ProcessingEmoji, error)
 	// RecacheMedia refetches, reprocesses, and recaches an existing attachment that has been uncached via pruneRemote.
-	RecacheMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, attachmentID string) (*ProcessingMedia, error)
+	//
+	// An attachment is only recached once at a time, so if it's already being recached, the recache that's in
+	// progress is returned instead, and data and postData aren't used. The returned bool is true if a new recache
+	// was started with data and postData.
+	RecacheMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, attachmentID string) (*ProcessingMedia, bool, error)
+	// RecacheRemote refetches, reprocesses, and recaches all remote media that has been uncached via pruneRemote.
+	// If accountID is set, only media belonging to that account will be recached. If domain is set, only media
+	// belonging to accounts on that domain will be recached.
+	//
+	// data will be called with each attachment that's recached, and should return a function that fetches it again.
+	//
+	// The recaches are queued in the background, at a rate set by media-recache-batch-rate so that remote instances
+	// aren't flooded with requests. RecacheRemote returns the number of attachments that will be recached.
+	RecacheRemote(ctx context.Context, accountID string, domain string, data RecacheDataFunc) (int, error)
+	// VerifyMedia checks the files of up to sampleSize stored attachments, and up to sampleSize emojis, picked at
+	// random, against the checksums that were stored when they were processed. Files that don't have checksums yet
+	// will have them filled in.
+	//
+	// Remote attachments with missing or corrupted files are pruned, and queued to be recached if a function for
+	// fetching remote media has been set with SetRecacheDataFunc; remote emojis are refreshed in the same way.
+	// Local attachments and emojis can't be fixed, so they're just reported. The report is also kept, so it can be retrieved later with LastIntegrityReport.
+	VerifyMedia(ctx context.Context, sampleSize int) (*IntegrityReport, error)
+	// LastIntegrityReport returns the report from the last time VerifyMedia was run, or nil if it hasn't been run yet.
+	LastIntegrityReport() *IntegrityReport
+	// SetRecacheDataFunc sets the function that VerifyMedia uses to fetch remote media again when its files are
+	// missing or corrupted. If it's never set, damaged remote media will be pruned, but not recached straight away.
+	// It's also used by ResumeJobs to fetch remote media that hadn't finished processing.
+	SetRecacheDataFunc(data RecacheDataFunc)
+	// WarmCache queues up to count remote attachments that aren't currently cached to be recached, picking the
+	// ones that have been accessed most often first, so that popular media is already cached before it's needed.
+	// Remote media is fetched using the function set with SetRecacheDataFunc; if that hasn't been set, an error
+	// is returned. The recaches are queued at a rate set by media-recache-batch-rate, and WarmCache blocks until
+	// they've all been queued. It returns the number of attachments that were selected to be recached.
+	WarmCache(ctx context.Context, count int) (int, error)
+	// ResumeJobs queues up any jobs for processing remote media that were still pending when the manager was last
+	// stopped, so that media isn't left unprocessed by a restart. Jobs for local media aren't kept, since local
+	// uploads can't be fetched again. Remote media is fetched using the function set with SetRecacheDataFunc.
+	//
+	// ResumeJobs should be called once, when GoToSocial starts. It returns the number of jobs that were queued.
+	ResumeJobs(ctx context.Context) (int, error)
 	// PruneRemote prunes all remote media cached on this instance that's older than the given amount of days.
 	// 'Pruning' in this context means removing the locally stored data of the attachment (both thumbnail and full size),
 	// and setting 'cached' to false on the associated attachment.
+	//
+	// When pruning finishes successfully, a report of what was pruned is kept, so it can be retrieved later with LastPruneReport.
 	PruneRemote(ctx context.Context, olderThanDays int) (int, error)
-	// NumWorkers returns the total number of workers available to this manager.
+	// LastPruneReport returns the report from the last time PruneRemote finished successfully, or nil if it hasn't yet.
+	LastPruneReport() *PruneReport
+	// DeleteAccountMedia queues all the media attachments of the given account that aren't attached to a status,
+	// such as its avatar and header, to be removed from storage and the database by the manager's worker pool.
+	// Attachments of statuses aren't included, since they're removed along with the statuses themselves.
+	//
+	// It returns the number of attachments that were queued for deletion.
+	DeleteAccountMedia(ctx context.Context, accountID string) (int, error)
+	// DeleteMedia removes the files of the attachment with the given ID from storage, and then removes the attachment
+	// from the database. Unlike DeleteAccountMedia, it does this straight away rather than queueing it, since it's for
+	// just one attachment. If the attachment doesn't exist, nothing is done and no error is returned.
+	DeleteMedia(ctx context.Context, attachmentID string) error
+	// ParseMediaSize converts s to a recognized MediaSize, including the thumbnail variants that the manager
+	// was configured with when it was created, or returns an error if unrecognized.
+	ParseMediaSize(s string) (Size, error)
+	// NumWorkers returns the total number of workers available to this manager for processing attachments.
 	NumWorkers() int
-	// QueueSize returns the total capacity of the queue.
+	// QueueSize returns the total capacity of the attachment queue.
 	QueueSize() int
-	// JobsQueued returns the number of jobs currently in the task queue.
+	// JobsQueued returns the number of jobs currently in the attachment task queue.
 	JobsQueued() int
-	// ActiveWorkers returns the number of workers currently performing jobs.
+	// ActiveWorkers returns the number of attachment workers currently performing jobs.
 	ActiveWorkers() int
-	// Stop stops the underlying worker pool of the manager. It should be called
+	// EmojiNumWorkers returns the total number of workers available to this manager for processing emoji.
+	EmojiNumWorkers() int
+	// EmojiQueueSize returns the total capacity of the emoji queue.
+	EmojiQueueSize() int
+	// EmojiJobsQueued returns the number of jobs currently in the emoji task queue.
+	EmojiJobsQueued() int
+	// EmojiActiveWorkers returns the number of emoji workers currently performing jobs.
+	EmojiActiveWorkers() int
+	// Stop stops the underlying worker pools of the manager. It should be called
 	// when closing GoToSocial in order to cleanly finish any in-progress jobs.
 	// It will block until workers are finished processing.
 	Stop() error
@@ -88,11 +172,41 @@ type Manager interface {
 
 type manager struct {
 	db           db.DB
-	storage      *kv.KVStore
+	storage      *gtsstorage.Driver
 	pool         runners.WorkerPool
 	stopCronJobs func() error
 	numWorkers   int
 	queueSize    int
+	jobTimeout   time.Duration // maximum time a single job may take, or 0 for no limit
+
+	// new remote media that's being processed, by remote URL, account, and status, so that concurrent fetches
+	// can share a job, and the fetches of remote URLs that the media shares, by remote URL and account
+	inFlightMu    sync.Mutex
+	inFlight      map[string]*ProcessingMedia
+	sharedFetches map[string]*sharedFetch
+	// remote attachments that are being recached, by attachment ID, also guarded by inFlightMu
+	recaching map[string]*ProcessingMedia
+
+	// emoji get their own pool, so that a flood of remote emoji can't hold up attachments
+	emojiPool       runners.WorkerPool
+	emojiNumWorkers int
+	emojiQueueSize  int
+
+	// thumbnailSizes are the thumbnails to derive for each piece of media, as configured
+	thumbnailSizes []thumbnailSize
+
+	// background work like batch recaches should stop when this is done
+	stopCtx    context.Context
+	stopCancel context.CancelFunc
+
+	// integrity checks can be run by cron and read by the admin API at the same time
+	integrityMu         sync.Mutex
+	lastIntegrityReport *IntegrityReport
+	recacheData         RecacheDataFunc
+
+	// likewise for pruning the remote cache
+	pruneMu         sync.Mutex
+	lastPruneReport *PruneReport
 }
 
 // NewManager returns a media manager with the given db and underlying storage.
@@ -108,7 +222,15 @@ type manager struct {
 // So for an 8 core machine, the media manager will get 4 workers, and a queue of length 40.
 // For a 4 core machine, this will be 2 workers, and a queue length of 20.
 // For a single or 2-core machine, the media manager will get 1 worker, and a queue of length 10.
-func NewManager(database db.DB, storage *kv.KVStore) (Manager, error) {
+//
+// Emoji are processed by a second, smaller worker pool, so that lots of remote emoji arriving at
+// once don't hold up attachments that users are waiting on. It gets half as many workers as the
+// attachment pool (but always at least 1), and a queue of 10 times that.
+func NewManager(database db.DB, storage *gtsstorage.Driver) (Manager, error) {
+	sizes, err := thumbnailSizes()
+	if err != nil {
+		return nil, fmt.Errorf("error configuring thumbnails: %s", err)
+	}
 
 	// configure the worker pool
 	// make sure we always have at least 1 worker even on single-core machines
@@ -118,13 +240,31 @@ func NewManager(database db.DB, storage *kv.KVStore) (Manager, error) {
 	}
 	queueSize := numWorkers * 10
 
+	emojiNumWorkers := numWorkers / 2
+	if emojiNumWorkers == 0 {
+		emojiNumWorkers = 1
+	}
+	emojiQueueSize := emojiNumWorkers * 10
+
 	m := &manager{
 		db:         database,
 		storage:    storage,
 		pool:       runners.NewWorkerPool(numWorkers, queueSize),
 		numWorkers: numWorkers,
 		queueSize:  queueSize,
+		jobTimeout: time.Duration(viper.GetInt(config.Keys.MediaJobTimeout)) * time.Second,
+
+		emojiPool:       runners.NewWorkerPool(emojiNumWorkers, emojiQueueSize),
+		emojiNumWorkers: emojiNumWorkers,
+		emojiQueueSize:  emojiQueueSize,
+
+		inFlight:      make(map[string]*ProcessingMedia),
+		sharedFetches: make(map[string]*sharedFetch),
+		recaching:     make(map[string]*ProcessingMedia),
+
+		thumbnailSizes: sizes,
 	}
+	m.stopCtx, m.stopCancel = context.WithCancel(context.Background())
 
 	// start the worker pool
 	if start := m.pool.Start(); !start {
@@ -132,79 +272,151 @@ func NewManager(database db.DB, storage *kv.KVStore) (Manager, error) {
 	}
 	logrus.Debugf("started media manager worker pool with %d workers and queue capacity of %d", numWorkers, queueSize)
 
-	// start remote cache cleanup cronjob if configured
+	if start := m.emojiPool.Start(); !start {
+		m.pool.Stop()
+		return nil, errors.New("could not start emoji worker pool")
+	}
+	logrus.Debugf("started media manager emoji worker pool with %d workers and queue capacity of %d", emojiNumWorkers, emojiQueueSize)
+
+	// start cron jobs for any periodic work that's configured
 	cacheCleanupDays := viper.GetInt(config.Keys.MediaRemoteCacheDays)
-	if cacheCleanupDays != 0 {
+	integritySample := viper.GetInt(config.Keys.MediaIntegrityCheckSample)
+	cacheWarmCount := viper.GetInt(config.Keys.MediaCacheWarmCount)
+	if cacheCleanupDays != 0 || integritySample != 0 || cacheWarmCount != 0 {
 		// we need a way of cancelling running jobs if the media manager is told to stop
-		pruneCtx, pruneCancel := context.WithCancel(context.Background())
+		cronCtx, cronCancel := context.WithCancel(context.Background())
 
-		// create a new cron instance and add a function to it
+		// create a new cron instance and add functions to it
 		c := cron.New(cron.WithLogger(&logrusWrapper{}))
 
-		pruneFunc := func() {
-			begin := time.Now()
-			pruned, err := m.PruneRemote(pruneCtx, cacheCleanupDays)
+		var pruneEntryID, integrityEntryID, warmEntryID cron.EntryID
+		if cacheCleanupDays != 0 {
+			pruneFunc := func() {
+				begin := time.Now()
+				pruned, err := m.PruneRemote(cronCtx, cacheCleanupDays)
+				if err != nil {
+					logrus.Errorf("media manager: error pruning remote cache: %s", err)
+					return
+				}
+				logrus.Infof("media manager: pruned %d remote cache entries in %s", pruned, time.Since(begin))
+			}
+
+			// run every night
+			pruneEntryID, err = c.AddFunc("@midnight", pruneFunc)
 			if err != nil {
-				logrus.Errorf("media manager: error pruning remote cache: %s", err)
-				return
+				cronCancel()
+				return nil, fmt.Errorf("error starting media manager remote cache cleanup job: %s", err)
 			}
-			logrus.Infof("media manager: pruned %d remote cache entries in %s", pruned, time.Since(begin))
 		}
 
-		// run every night
-		entryID, err := c.AddFunc("@midnight", pruneFunc)
-		if err != nil {
-			pruneCancel()
-			return nil, fmt.Errorf("error starting media manager remote cache cleanup job: %s", err)
+		if integritySample != 0 {
+			integrityFunc := func() {
+				begin := time.Now()
+				report, err := m.VerifyMedia(cronCtx, integritySample)
+				if err != nil {
+					logrus.Errorf("media manager: error checking media integrity: %s", err)
+					return
+				}
+				logrus.Infof("media manager: checked integrity of %d attachments and %d emojis in %s", report.Checked, report.CheckedEmojis, time.Since(begin))
+			}
+
+			// only a small sample is checked each time, so run often enough to get through everything eventually
+			integrityEntryID, err = c.AddFunc("@hourly", integrityFunc)
+			if err != nil {
+				cronCancel()
+				return nil, fmt.Errorf("error starting media manager integrity check job: %s", err)
+			}
 		}
 
-		// since we're running a cron job, we should define how the manager should stop them
+		if cacheWarmCount != 0 {
+			warmFunc := func() {
+				begin := time.Now()
+				warmed, err := m.WarmCache(cronCtx, cacheWarmCount)
+				if err != nil {
+					logrus.Errorf("media manager: error warming remote cache: %s", err)
+					return
+				}
+				logrus.Infof("media manager: queued %d popular remote attachments for recaching in %s", warmed, time.Since(begin))
+			}
+
+			warmEntryID, err = c.AddFunc("@hourly", warmFunc)
+			if err != nil {
+				cronCancel()
+				return nil, fmt.Errorf("error starting media manager cache warming job: %s", err)
+			}
+		}
+
+		// since we're running cron jobs, we should define how the manager should stop them
 		m.stopCronJobs = func() error {
 			// try to stop any jobs gracefully by waiting til they're finished
-			cronCtx := c.Stop()
+			stopCtx := c.Stop()
 
 			select {
-			case <-cronCtx.Done():
+			case <-stopCtx.Done():
 				logrus.Infof("media manager: cron finished jobs and stopped gracefully")
 			case <-time.After(1 * time.Minute):
 				logrus.Infof("media manager: cron didn't stop after 60 seconds, will force close")
 				break
 			}
 
-			// whether the job is finished neatly or we had to wait a minute, cancel the context on the prune job
-			pruneCancel()
+			// whether the jobs finished neatly or we had to wait a minute, cancel the context on the jobs
+			cronCancel()
 			return nil
 		}
 
 		// now start all the cron stuff we've lined up
 		c.Start()
-		logrus.Infof("started media manager remote cache cleanup job: will run next at %s", c.Entry(entryID).Next)
+		if pruneEntryID != 0 {
+			logrus.Infof("started media manager remote cache cleanup job: will run next at %s", c.Entry(pruneEntryID).Next)
+		}
+		if integrityEntryID != 0 {
+			logrus.Infof("started media manager integrity check job: will run next at %s", c.Entry(integrityEntryID).Next)
+		}
+		if warmEntryID != 0 {
+			logrus.Infof("started media manager cache warming job: will run next at %s", c.Entry(warmEntryID).Next)
+		}
 	}
 
 	return m, nil
 }
 
 func (m *manager) ProcessMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, accountID string, ai *AdditionalMediaInfo) (*ProcessingMedia, error) {
+	var remoteURL, statusID string
+	if ai != nil && ai.RemoteURL != nil {
+		remoteURL = *ai.RemoteURL
+	}
+	if ai != nil && ai.StatusID != nil {
+		statusID = *ai.StatusID
+	}
+
+	if inFlight := m.getInFlight(remoteURL, accountID, statusID); inFlight != nil {
+		logrus.Debugf("ProcessMedia: media from %s is already being processed with attachmentID %s", remoteURL, inFlight.AttachmentID())
+		return inFlight, nil
+	}
+
 	processingMedia, err := m.preProcessMedia(ctx, data, postData, accountID, ai)
 	if err != nil {
 		return nil, err
 	}
 
-	logrus.Tracef("ProcessMedia: about to enqueue media with attachmentID %s, queue length is %d", processingMedia.AttachmentID(), m.pool.Queue())
-	m.pool.Enqueue(func(innerCtx context.Context) {
-		select {
-		case <-innerCtx.Done():
-			// if the inner context is done that means the worker pool is closing, so we should just return
-			return
-		default:
-			// start loading the media already for the caller's convenience
-			if _, err := processingMedia.LoadAttachment(innerCtx); err != nil {
-				logrus.Errorf("ProcessMedia: error processing media with attachmentID %s: %s", processingMedia.AttachmentID(), err)
-			}
+	if remoteURL != "" {
+		// someone else might have started on the same url while we were preprocessing
+		if inFlight := m.putInFlight(remoteURL, processingMedia); inFlight != processingMedia {
+			logrus.Debugf("ProcessMedia: media from %s is already being processed with attachmentID %s", remoteURL, inFlight.AttachmentID())
+			return inFlight, nil
 		}
-	})
-	logrus.Tracef("ProcessMedia: succesfully queued media with attachmentID %s, queue length is %d", processingMedia.AttachmentID(), m.pool.Queue())
 
+		// remote media can be fetched again, so keep track of it in case we're stopped before it's done
+		attachment := *processingMedia.attachment
+		processingMedia.jobID = m.persistJob(ctx, &gtsmodel.MediaJob{
+			Type:         gtsmodel.MediaJobTypeAttachment,
+			AttachmentID: attachment.ID,
+			RemoteURL:    remoteURL,
+			Attachment:   &attachment,
+		})
+	}
+
+	m.enqueueMedia(processingMedia, jobAttachment)
 	return processingMedia, nil
 }
 
@@ -214,46 +426,189 @@ func (m *manager) ProcessEmoji(ctx context.Context, data DataFunc, postData Post
 		return nil, err
 	}
 
-	logrus.Tracef("ProcessEmoji: about to enqueue emoji with id %s, queue length is %d", processingEmoji.EmojiID(), m.pool.Queue())
+	// remote emoji can be fetched again, so keep track of them in case we're stopped before they're done
+	if remoteURL := processingEmoji.emoji.ImageRemoteURL; remoteURL != "" {
+		emoji := *processingEmoji.emoji
+		processingEmoji.jobID = m.persistJob(ctx, &gtsmodel.MediaJob{
+			Type:      gtsmodel.MediaJobTypeEmoji,
+			EmojiID:   emoji.ID,
+			RemoteURL: remoteURL,
+			Emoji:     &emoji,
+		})
+	}
+
+	m.enqueueEmoji(processingEmoji)
+	return processingEmoji, nil
+}
+
+func (m *manager) RefreshEmoji(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, emoji *gtsmodel.Emoji, imageRemoteURL string) (*ProcessingEmoji, error) {
+	processingEmoji, err := m.preProcessEmoji(ctx, data, postData, emoji.Shortcode, emoji.ID, emoji.URI, &AdditionalEmojiInfo{
+		CreatedAt:       &emoji.CreatedAt,
+		Domain:          &emoji.Domain,
+		ImageRemoteURL:  &imageRemoteURL,
+		Disabled:        &emoji.Disabled,
+		VisibleInPicker: &emoji.VisibleInPicker,
+		CategoryID:      &emoji.CategoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	processingEmoji.refreshing = emoji
+
+	m.enqueueEmoji(processingEmoji)
+	return processingEmoji, nil
+}
+
+func (m *manager) RecacheMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, attachmentID string) (*ProcessingMedia, bool, error) {
+	processingRecache, err := m.preProcessRecache(ctx, data, postData, attachmentID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if recaching := m.putRecaching(processingRecache); recaching != processingRecache {
+		return recaching, false, nil
+	}
+
+	if remoteURL := processingRecache.attachment.RemoteURL; remoteURL != "" {
+		processingRecache.jobID = m.persistJob(ctx, &gtsmodel.MediaJob{
+			Type:         gtsmodel.MediaJobTypeRecache,
+			AttachmentID: attachmentID,
+			RemoteURL:    remoteURL,
+		})
+	}
+
+	m.enqueueMedia(processingRecache, jobRecache)
+	return processingRecache, true, nil
+}
+
+// enqueueMedia queues the given media to be processed by the worker pool, as a job of the given type.
+func (m *manager) enqueueMedia(processingMedia *ProcessingMedia, jobType string) {
+	logrus.Tracef("enqueueMedia: about to enqueue %s job with attachmentID %s, queue length is %d", jobType, processingMedia.AttachmentID(), m.pool.Queue())
 	m.pool.Enqueue(func(innerCtx context.Context) {
+		// once the worker's done with the media, new fetches of the same url shouldn't share it anymore
+		defer m.removeInFlight(processingMedia)
+
 		select {
 		case <-innerCtx.Done():
-			// if the inner context is done that means the worker pool is closing, so we should just return
+			// if the inner context is done that means the worker pool is closing, so we should just return;
+			// if the job was persisted it'll be picked up again when the manager is next started
 			return
 		default:
-			// start loading the emoji already for the caller's convenience
-			if _, err := processingEmoji.LoadEmoji(innerCtx); err != nil {
-				logrus.Errorf("ProcessEmoji: error processing emoji with id %s: %s", processingEmoji.EmojiID(), err)
+			// start loading the media already for the caller's convenience
+			begin := time.Now()
+			err := runJob(innerCtx, m.jobTimeout, jobType, func(jobCtx context.Context) error {
+				_, err := processingMedia.LoadAttachment(jobCtx)
+				return err
+			}, processingMedia.abort, processingMedia.cleanup)
+			observeJob(jobType, begin, err)
+			if err != nil {
+				logrus.Errorf("enqueueMedia: error processing %s job with attachmentID %s: %s", jobType, processingMedia.AttachmentID(), err)
+			}
+
+			// if the pool was stopped partway through, leave any persisted job to be resumed next time
+			if innerCtx.Err() == nil {
+				m.forgetJob(innerCtx, processingMedia.jobID)
 			}
 		}
 	})
-	logrus.Tracef("ProcessEmoji: succesfully queued emoji with id %s, queue length is %d", processingEmoji.EmojiID(), m.pool.Queue())
+	logrus.Tracef("enqueueMedia: succesfully queued %s job with attachmentID %s, queue length is %d", jobType, processingMedia.AttachmentID(), m.pool.Queue())
+}
 
-	return processingEmoji, nil
+// inFlightKey returns the key that new media from the given remote url is kept in inFlight with.
+// Media is only shared between fetches for the same account and status, since the attachment
+// that comes out of processing it belongs to just that account and status; media for other
+// statuses shares just the fetch of the url instead.
+func inFlightKey(remoteURL string, accountID string, statusID string) string {
+	return remoteURL + " " + accountID + " " + statusID
 }
 
-func (m *manager) RecacheMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, attachmentID string) (*ProcessingMedia, error) {
-	processingRecache, err := m.preProcessRecache(ctx, data, postData, attachmentID)
-	if err != nil {
-		return nil, err
+// getInFlight returns the new media from the given remote url that's currently being
+// processed for the given account and status, or nil if there isn't any.
+func (m *manager) getInFlight(remoteURL string, accountID string, statusID string) *ProcessingMedia {
+	if remoteURL == "" {
+		return nil
 	}
 
-	logrus.Tracef("RecacheMedia: about to enqueue recache with attachmentID %s, queue length is %d", processingRecache.AttachmentID(), m.pool.Queue())
-	m.pool.Enqueue(func(innerCtx context.Context) {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	return m.inFlight[inFlightKey(remoteURL, accountID, statusID)]
+}
+
+// putInFlight marks the given media as being processed from the given remote url, and returns it,
+// unless media from that url is already being processed for the same account and status, in which
+// case the media that's already being processed is returned instead. Media that's put in flight
+// shares the fetch of the url with any other media from it for the same account.
+func (m *manager) putInFlight(remoteURL string, processingMedia *ProcessingMedia) *ProcessingMedia {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	key := inFlightKey(remoteURL, processingMedia.attachment.AccountID, processingMedia.attachment.StatusID)
+	if inFlight, ok := m.inFlight[key]; ok {
+		return inFlight
+	}
+	m.inFlight[key] = processingMedia
+	m.shareFetch(processingMedia)
+	return processingMedia
+}
+
+// putRecaching marks the given media as recaching its attachment, and returns it, unless the
+// attachment is already being recached, in which case the media that's recaching it is returned instead.
+func (m *manager) putRecaching(processingRecache *ProcessingMedia) *ProcessingMedia {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	if recaching, ok := m.recaching[processingRecache.attachment.ID]; ok {
+		return recaching
+	}
+	m.recaching[processingRecache.attachment.ID] = processingRecache
+	return processingRecache
+}
+
+// removeInFlight stops the given media being shared with new fetches of its remote url, or with new recaches of its attachment.
+func (m *manager) removeInFlight(processingMedia *ProcessingMedia) {
+	m.inFlightMu.Lock()
+	defer m.inFlightMu.Unlock()
+
+	key := inFlightKey(processingMedia.attachment.RemoteURL, processingMedia.attachment.AccountID, processingMedia.attachment.StatusID)
+	if m.inFlight[key] == processingMedia {
+		delete(m.inFlight, key)
+		m.unshareFetch(processingMedia)
+	}
+
+	if m.recaching[processingMedia.attachment.ID] == processingMedia {
+		delete(m.recaching, processingMedia.attachment.ID)
+	}
+}
+
+// enqueueEmoji queues the given emoji to be processed by the emoji worker pool.
+func (m *manager) enqueueEmoji(processingEmoji *ProcessingEmoji) {
+	logrus.Tracef("enqueueEmoji: about to enqueue emoji with id %s, queue length is %d", processingEmoji.EmojiID(), m.emojiPool.Queue())
+	m.emojiPool.Enqueue(func(innerCtx context.Context) {
 		select {
 		case <-innerCtx.Done():
-			// if the inner context is done that means the worker pool is closing, so we should just return
+			// if the inner context is done that means the worker pool is closing, so we should just return;
+			// if the job was persisted it'll be picked up again when the manager is next started
 			return
 		default:
-			// start loading the media already for the caller's convenience
-			if _, err := processingRecache.LoadAttachment(innerCtx); err != nil {
-				logrus.Errorf("RecacheMedia: error processing recache with attachmentID %s: %s", processingRecache.AttachmentID(), err)
+			// start loading the emoji already for the caller's convenience
+			begin := time.Now()
+			err := runJob(innerCtx, m.jobTimeout, jobEmoji, func(jobCtx context.Context) error {
+				_, err := processingEmoji.LoadEmoji(jobCtx)
+				return err
+			}, processingEmoji.abort, processingEmoji.cleanup)
+			observeJob(jobEmoji, begin, err)
+			if err != nil {
+				logrus.Errorf("enqueueEmoji: error processing emoji with id %s: %s", processingEmoji.EmojiID(), err)
+			}
+
+			// if the pool was stopped partway through, leave any persisted job to be resumed next time
+			if innerCtx.Err() == nil {
+				m.forgetJob(innerCtx, processingEmoji.jobID)
 			}
 		}
 	})
-	logrus.Tracef("RecacheMedia: succesfully queued recache with attachmentID %s, queue length is %d", processingRecache.AttachmentID(), m.pool.Queue())
-
-	return processingRecache, nil
+	logrus.Tracef("enqueueEmoji: succesfully queued emoji with id %s, queue length is %d", processingEmoji.EmojiID(), m.emojiPool.Queue())
 }
 
 func (m *manager) NumWorkers() int {
@@ -272,14 +627,38 @@ func (m *manager) ActiveWorkers() int {
 	return m.pool.Workers()
 }
 
+func (m *manager) EmojiNumWorkers() int {
+	return m.emojiNumWorkers
+}
+
+func (m *manager) EmojiQueueSize() int {
+	return m.emojiQueueSize
+}
+
+func (m *manager) EmojiJobsQueued() int {
+	return m.emojiPool.Queue()
+}
+
+func (m *manager) EmojiActiveWorkers() int {
+	return m.emojiPool.Workers()
+}
+
 func (m *manager) Stop() error {
+	// stop queueing any more background work before stopping the workers
+	m.stopCancel()
+
 	logrus.Info("stopping media manager worker pool")
 	if !m.pool.Stop() {
 		return errors.New("could not stop media manager worker pool")
 	}
 
+	logrus.Info("stopping media manager emoji worker pool")
+	if !m.emojiPool.Stop() {
+		return errors.New("could not stop media manager emoji worker pool")
+	}
+
 	if m.stopCronJobs != nil { // only defined if cron jobs are actually running
-		logrus.Info("stopping media manager cache cleanup jobs")
+		logrus.Info("stopping media manager cron jobs")
 		return m.stopCronJobs()
 	}
 
@@ -21,18 +21,28 @@ package media_test
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"os"
+	"os/exec"
 	"path"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"codeberg.org/gruf/go-store/kv"
 	"codeberg.org/gruf/go-store/storage"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	gtsmodel "github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
 type ManagerTestSuite struct {
@@ -525,7 +535,7 @@ func (suite *ManagerTestSuite) TestSimpleJpegProcessBlockingWithDiskStorage() {
 		panic(err)
 	}
 
-	diskManager, err := media.NewManager(suite.db, diskStorage)
+	diskManager, err := media.NewManager(suite.db, gtsstorage.NewDriver(&gtsstorage.KVStorage{KVStore: diskStorage}))
 	if err != nil {
 		panic(err)
 	}
@@ -589,6 +599,1029 @@ func (suite *ManagerTestSuite) TestSimpleJpegProcessBlockingWithDiskStorage() {
 	suite.Equal(processedThumbnailBytesExpected, processedThumbnailBytes)
 }
 
+func (suite *ManagerTestSuite) TestSimpleJpegProcessQuotaExceeded() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := testrig.NewTestAccounts()["local_account_1"].ID
+
+	// give the account just a little bit more room than it's already using
+	used, err := suite.db.GetAccountMediaSize(ctx, accountID)
+	suite.NoError(err)
+	viper.Set(config.Keys.MediaAccountQuota, used+100)
+	defer viper.Set(config.Keys.MediaAccountQuota, 0)
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	// the test image is much bigger than 100 bytes so this should fail
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrQuotaExceeded)
+	suite.Nil(attachment)
+
+	// usage should not have changed
+	usedAfter, err := suite.db.GetAccountMediaSize(ctx, accountID)
+	suite.NoError(err)
+	suite.Equal(used, usedAfter)
+
+	// once the account is full, new media shouldn't even be queued
+	viper.Set(config.Keys.MediaAccountQuota, used)
+	processingMedia, err = suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.ErrorIs(err, media.ErrQuotaExceeded)
+	suite.Nil(processingMedia)
+}
+
+func (suite *ManagerTestSuite) TestSimpleJpegProcessQuotaUnlimitedOverride() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := testrig.NewTestAccounts()["local_account_1"].ID
+
+	// set a tiny instance quota
+	viper.Set(config.Keys.MediaAccountQuota, 1)
+	defer viper.Set(config.Keys.MediaAccountQuota, 0)
+
+	// but give this user unlimited storage
+	user := testrig.NewTestUsers()["local_account_1"]
+	user.MediaQuota = -1
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, user))
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestSimpleJpegProcessThumbnailVariants() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// configure a smaller small thumbnail, and an additional medium one
+	viper.Set(config.Keys.MediaThumbnailMaxWidth, 256)
+	viper.Set(config.Keys.MediaThumbnailMaxHeight, 256)
+	viper.Set(config.Keys.MediaThumbnailVariants, []string{"medium:1024x1024"})
+	defer func() {
+		viper.Set(config.Keys.MediaThumbnailMaxWidth, 512)
+		viper.Set(config.Keys.MediaThumbnailMaxHeight, 512)
+		viper.Set(config.Keys.MediaThumbnailVariants, []string{})
+	}()
+
+	// thumbnail sizes are read when the manager is created, so we need a new one
+	variantManager, err := media.NewManager(suite.db, suite.storage)
+	suite.NoError(err)
+	suite.manager = variantManager
+
+	processingMedia, err := variantManager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	// the small thumbnail should fit in the configured size
+	suite.EqualValues(gtsmodel.Small{
+		Width: 256, Height: 144, Size: 36864, Aspect: 1.7777777777777777,
+	}, attachment.FileMeta.Small)
+
+	// and we should have a medium variant as well
+	suite.Len(attachment.Variants, 1)
+	variant := attachment.Variants[0]
+	suite.Equal("medium", variant.Name)
+	suite.Equal(1024, variant.Width)
+	suite.Equal(576, variant.Height)
+	suite.Equal("image/jpeg", variant.ContentType)
+	suite.Equal(fmt.Sprintf("%s/attachment/medium/%s.jpeg", accountID, attachment.ID), variant.Path)
+	suite.Equal(fmt.Sprintf("http://localhost:8080/fileserver/%s/attachment/medium/%s.jpeg", accountID, attachment.ID), variant.URL)
+
+	// the variant should be in storage
+	variantBytes, err := suite.storage.Get(variant.Path)
+	suite.NoError(err)
+	suite.Len(variantBytes, variant.FileSize)
+
+	// and it should have been stored in the database
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, attachment.ID)
+	suite.NoError(err)
+	suite.Equal(attachment.Variants[0].Path, dbAttachment.Variants[0].Path)
+}
+
+func (suite *ManagerTestSuite) TestInvalidThumbnailVariant() {
+	viper.Set(config.Keys.MediaThumbnailVariants, []string{"original:1024x1024"})
+	defer viper.Set(config.Keys.MediaThumbnailVariants, []string{})
+
+	manager, err := media.NewManager(suite.db, suite.storage)
+	suite.EqualError(err, "error configuring thumbnails: thumbnail variant original:1024x1024 name is reserved")
+	suite.Nil(manager)
+}
+
+func (suite *ManagerTestSuite) TestAnimatedGifProcessBelowGifvMinSize() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-gif.gif")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// the test gif is a bit bigger than the default max image size for tests
+	viper.Set(config.Keys.MediaImageMaxSize, 2097152)
+	defer viper.Set(config.Keys.MediaImageMaxSize, 1048576)
+
+	// enable gifv conversion, but only for gifs bigger than the test gif
+	viper.Set(config.Keys.MediaGifvEnabled, true)
+	viper.Set(config.Keys.MediaGifvMinSize, 10485760)
+	defer func() {
+		viper.Set(config.Keys.MediaGifvEnabled, false)
+		viper.Set(config.Keys.MediaGifvMinSize, 1048576)
+	}()
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	// the gif should have been left alone
+	suite.Equal(gtsmodel.FileTypeGif, attachment.Type)
+	suite.Equal("image/gif", attachment.File.ContentType)
+	suite.Equal(fmt.Sprintf("%s/attachment/original/%s.gif", accountID, attachment.ID), attachment.File.Path)
+	suite.Empty(attachment.Variants)
+	suite.True(processingMedia.Finished())
+}
+
+func (suite *ManagerTestSuite) TestAnimatedGifProcessGifvNoFfmpeg() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-gif.gif")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// the test gif is a bit bigger than the default max image size for tests
+	viper.Set(config.Keys.MediaImageMaxSize, 2097152)
+	defer viper.Set(config.Keys.MediaImageMaxSize, 1048576)
+
+	// enable gifv conversion, but point it at an ffmpeg that doesn't exist
+	viper.Set(config.Keys.MediaGifvEnabled, true)
+	viper.Set(config.Keys.MediaGifvFfmpegPath, "/this/path/does/not/exist/ffmpeg")
+	defer func() {
+		viper.Set(config.Keys.MediaGifvEnabled, false)
+		viper.Set(config.Keys.MediaGifvFfmpegPath, "ffmpeg")
+	}()
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.Error(err)
+	suite.Contains(err.Error(), "loadGifv: error deriving gifv: error running ffmpeg")
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestAnimatedGifProcessGifvKeepOriginal() {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		suite.T().Skip("ffmpeg not installed, skipping gifv conversion test")
+	}
+
+	ctx := context.Background()
+
+	gifBytes, err := os.ReadFile("./test/test-gif.gif")
+	suite.NoError(err)
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(gifBytes), len(gifBytes), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// the test gif is a bit bigger than the default max image size for tests
+	viper.Set(config.Keys.MediaImageMaxSize, 2097152)
+	defer viper.Set(config.Keys.MediaImageMaxSize, 1048576)
+
+	viper.Set(config.Keys.MediaGifvEnabled, true)
+	viper.Set(config.Keys.MediaGifvKeepOriginal, true)
+	defer func() {
+		viper.Set(config.Keys.MediaGifvEnabled, false)
+		viper.Set(config.Keys.MediaGifvKeepOriginal, false)
+	}()
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	// the attachment should now point to an mp4
+	suite.Equal(gtsmodel.FileTypeGifv, attachment.Type)
+	suite.Equal("video/mp4", attachment.File.ContentType)
+	suite.Equal(fmt.Sprintf("%s/attachment/original/%s.mp4", accountID, attachment.ID), attachment.File.Path)
+	suite.Equal(fmt.Sprintf("http://localhost:8080/fileserver/%s/attachment/original/%s.mp4", accountID, attachment.ID), attachment.URL)
+	suite.Less(attachment.File.FileSize, len(gifBytes))
+
+	// dimensions should be those of the gif
+	suite.EqualValues(gtsmodel.Original{
+		Width: 400, Height: 280, Size: 112000, Aspect: 1.4285714285714286,
+	}, attachment.FileMeta.Original)
+
+	mp4Bytes, err := suite.storage.Get(attachment.File.Path)
+	suite.NoError(err)
+	suite.Len(mp4Bytes, attachment.File.FileSize)
+
+	// the gif should have been moved out of the way and kept as a variant
+	_, err = suite.storage.Get(fmt.Sprintf("%s/attachment/original/%s.gif", accountID, attachment.ID))
+	suite.ErrorIs(err, storage.ErrNotFound)
+
+	suite.Len(attachment.Variants, 1)
+	variant := attachment.Variants[0]
+	suite.Equal("gif", variant.Name)
+	suite.Equal("image/gif", variant.ContentType)
+	suite.Equal(fmt.Sprintf("%s/attachment/gif/%s.gif", accountID, attachment.ID), variant.Path)
+
+	storedGif, err := suite.storage.Get(variant.Path)
+	suite.NoError(err)
+	suite.Equal(gifBytes, storedGif)
+}
+
+func (suite *ManagerTestSuite) TestPngProcessDone() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-png-noalphachannel.png")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+	attachmentID := processingMedia.AttachmentID()
+
+	// don't load the attachment ourselves, just wait for the worker pool to finish with it
+	select {
+	case <-processingMedia.Done():
+	case <-time.After(10 * time.Second):
+		suite.FailNow("timed out waiting for media to finish processing")
+	}
+	suite.NoError(processingMedia.Err())
+	suite.True(processingMedia.Finished())
+
+	// the attachment should already be in the database
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, attachmentID)
+	suite.NoError(err)
+	suite.NotNil(dbAttachment)
+	suite.Equal("image/png", dbAttachment.File.ContentType)
+
+	// loading it now should give us the same attachment without doing any more work
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.Equal(attachmentID, attachment.ID)
+}
+
+func (suite *ManagerTestSuite) TestProcessDoneWithError() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return nil, 0, fmt.Errorf("no media for you")
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	select {
+	case <-processingMedia.Done():
+	case <-time.After(10 * time.Second):
+		suite.FailNow("timed out waiting for media to finish processing")
+	}
+
+	// the error from the data function should be passed through
+	err = processingMedia.Err()
+	suite.Error(err)
+	suite.Contains(err.Error(), "no media for you")
+
+	// and loading the attachment should give us the same error rather than trying again
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.Nil(attachment)
+	suite.Equal(processingMedia.Err(), err)
+
+	// nothing should have been put in the database
+	_, err = suite.db.GetAttachmentByID(ctx, processingMedia.AttachmentID())
+	suite.Error(err)
+}
+
+func (suite *ManagerTestSuite) TestProcessJobTimeout() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// simulate something that hangs without paying attention to its context
+		time.Sleep(3 * time.Second)
+
+		b, err := os.ReadFile("./test/test-png-noalphachannel.png")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	viper.Set(config.Keys.MediaJobTimeout, 1)
+	defer viper.Set(config.Keys.MediaJobTimeout, 300)
+
+	// the job timeout is read when the manager is created, so we need a new one
+	suite.NoError(suite.manager.Stop())
+	timeoutManager, err := media.NewManager(suite.db, suite.storage)
+	suite.NoError(err)
+	suite.manager = timeoutManager
+
+	processingMedia, err := timeoutManager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	// the job should be given up on before the data function returns
+	select {
+	case <-processingMedia.Done():
+	case <-time.After(2500 * time.Millisecond):
+		suite.FailNow("timed out waiting for media job to time out")
+	}
+	suite.ErrorIs(processingMedia.Err(), media.ErrJobTimeout)
+
+	// loading the attachment doesn't wait for the hung job to let go of it
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrJobTimeout)
+	suite.Nil(attachment)
+
+	// once the hung data function does return, the media still shouldn't be stored
+	_, err = suite.db.GetAttachmentByID(ctx, processingMedia.AttachmentID())
+	suite.Error(err)
+}
+
+// hangingReader reads from r, but hangs for a while partway through without paying attention to any context.
+type hangingReader struct {
+	r        io.Reader
+	after    int
+	read     int
+	hang     time.Duration
+	returned chan struct{}
+}
+
+func (h *hangingReader) Read(p []byte) (int, error) {
+	if h.read >= h.after && h.returned != nil {
+		time.Sleep(h.hang)
+		close(h.returned)
+		h.returned = nil
+	}
+	if remaining := h.after - h.read; remaining > 0 && len(p) > remaining {
+		p = p[:remaining]
+	}
+	n, err := h.r.Read(p)
+	h.read += n
+	return n, err
+}
+
+func (suite *ManagerTestSuite) TestProcessJobTimeoutWhileStoring() {
+	ctx := context.Background()
+
+	b, err := os.ReadFile("./test/test-png-noalphachannel.png")
+	if err != nil {
+		panic(err)
+	}
+	returned := make(chan struct{})
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// the media starts coming in fine, but then hangs halfway through being stored
+		return &hangingReader{r: bytes.NewReader(b), after: len(b) / 2, hang: 3 * time.Second, returned: returned}, len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	viper.Set(config.Keys.MediaJobTimeout, 1)
+	defer viper.Set(config.Keys.MediaJobTimeout, 300)
+
+	// the job timeout is read when the manager is created, so we need a new one
+	suite.NoError(suite.manager.Stop())
+	timeoutManager, err := media.NewManager(suite.db, suite.storage)
+	suite.NoError(err)
+	suite.manager = timeoutManager
+
+	processingMedia, err := timeoutManager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	select {
+	case <-processingMedia.Done():
+	case <-time.After(2500 * time.Millisecond):
+		suite.FailNow("timed out waiting for media job to time out")
+	}
+	suite.ErrorIs(processingMedia.Err(), media.ErrJobTimeout)
+
+	// once the hung read does return, the job carries on storing files until it notices it's been given up on,
+	// and then the files it stored are cleaned up again
+	<-returned
+	originalPath := fmt.Sprintf("%s/attachment/original/%s.png", accountID, processingMedia.AttachmentID())
+	thumbnailPath := fmt.Sprintf("%s/attachment/small/%s.jpeg", accountID, processingMedia.AttachmentID())
+	suite.Eventually(func() bool {
+		original, err := suite.storage.Has(originalPath)
+		suite.NoError(err)
+		thumbnail, err := suite.storage.Has(thumbnailPath)
+		suite.NoError(err)
+		return !original && !thumbnail
+	}, 5*time.Second, 50*time.Millisecond)
+
+	_, err = suite.db.GetAttachmentByID(ctx, processingMedia.AttachmentID())
+	suite.Error(err)
+}
+
+func (suite *ManagerTestSuite) TestPngProcessTooLarge() {
+	ctx := context.Background()
+
+	b, err := os.ReadFile("./test/test-png-noalphachannel.png")
+	suite.NoError(err)
+
+	// declare the real size of the image, so it can be rejected before anything is read
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	viper.Set(config.Keys.MediaImageMaxSize, len(b)-1)
+	defer viper.Set(config.Keys.MediaImageMaxSize, 1048576)
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestGifProcessTooLargeWrongSize() {
+	ctx := context.Background()
+
+	b, err := os.ReadFile("./test/test-gif.gif")
+	suite.NoError(err)
+
+	// claim the image is much smaller than it really is, like a lying remote server might
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), 1024, nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	viper.Set(config.Keys.MediaImageMaxSize, 524288)
+	defer viper.Set(config.Keys.MediaImageMaxSize, 1048576)
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Nil(attachment)
+
+	// nothing should have been left behind in storage
+	_, err = suite.storage.Get(fmt.Sprintf("%s/attachment/original/%s.gif", accountID, processingMedia.AttachmentID()))
+	suite.ErrorIs(err, storage.ErrNotFound)
+}
+
+func (suite *ManagerTestSuite) TestSvgEmojiProcess() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test svg with some nasty stuff in it
+		b, err := os.ReadFile("./test/test-svg.svg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	emojiID := "01GDQ9G782X42BAMFJ40WFCG2V"
+	emojiURI := "http://localhost:8080/emoji/01GDQ9G782X42BAMFJ40WFCG2V"
+
+	processingEmoji, err := suite.manager.ProcessEmoji(ctx, data, nil, "svg_test", emojiID, emojiURI, nil)
+	suite.NoError(err)
+
+	emoji, err := processingEmoji.LoadEmoji(ctx)
+	suite.NoError(err)
+	suite.NotNil(emoji)
+
+	suite.Equal("image/svg+xml", emoji.ImageContentType)
+	suite.True(strings.HasSuffix(emoji.ImagePath, emojiID+".svg"))
+	suite.Equal("image/png", emoji.ImageStaticContentType)
+
+	// the stored svg should have had everything nasty taken out of it
+	svgBytes, err := suite.storage.Get(emoji.ImagePath)
+	suite.NoError(err)
+	suite.Equal(emoji.ImageFileSize, len(svgBytes))
+
+	svg := string(svgBytes)
+	for _, gone := range []string{"<script", "onload", "onclick", "@import", "evil.example.org", "javascript:", "foreignObject", "<!DOCTYPE", "<!--"} {
+		suite.NotContains(svg, gone)
+	}
+	for _, kept := range []string{"<svg", `fill="url(#gradient)"`, "<circle", "<linearGradient", `xmlns:xlink="http://www.w3.org/1999/xlink"`} {
+		suite.Contains(svg, kept)
+	}
+
+	// the static version should be a png render of the svg, keeping its aspect ratio
+	staticBytes, err := suite.storage.Get(emoji.ImageStaticPath)
+	suite.NoError(err)
+	suite.Equal(emoji.ImageStaticFileSize, len(staticBytes))
+
+	static, err := png.Decode(bytes.NewReader(staticBytes))
+	suite.NoError(err)
+	suite.Equal(128, static.Bounds().Dx())
+	suite.Equal(64, static.Bounds().Dy())
+
+	// the middle of the render should be the red circle
+	r, g, b, a := static.At(64, 32).RGBA()
+	suite.Equal([]uint32{0xffff, 0, 0, 0xffff}, []uint32{r, g, b, a})
+}
+
+func (suite *ManagerTestSuite) TestNotSvgEmojiProcess() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		b := []byte(`<?xml version="1.0"?><html><body><script>alert('hello')</script></body></html>`)
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	emojiID := "01GDQ9G782X42BAMFJ40WFCG2W"
+	emojiURI := "http://localhost:8080/emoji/01GDQ9G782X42BAMFJ40WFCG2W"
+
+	processingEmoji, err := suite.manager.ProcessEmoji(ctx, data, nil, "not_svg_test", emojiID, emojiURI, nil)
+	suite.NoError(err)
+
+	emoji, err := processingEmoji.LoadEmoji(ctx)
+	suite.Error(err)
+	suite.Contains(err.Error(), "root element was html, not svg")
+	suite.Nil(emoji)
+}
+
+func (suite *ManagerTestSuite) TestJpegProcessTooManyPixels() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// the test jpeg is 1920x1080, so allow one pixel less than that
+	viper.Set(config.Keys.MediaImageMaxPixels, 1920*1080-1)
+	defer viper.Set(config.Keys.MediaImageMaxPixels, 16777216)
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Contains(err.Error(), "1920x1080 is more than the max of 2073599 pixels")
+	suite.Nil(attachment)
+
+	// the stored original should have been cleaned up
+	_, err = suite.storage.Get(fmt.Sprintf("%s/attachment/original/%s.jpeg", accountID, processingMedia.AttachmentID()))
+	suite.ErrorIs(err, storage.ErrNotFound)
+}
+
+func (suite *ManagerTestSuite) TestEmojiProcessTooLarge() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/rainbow-original.png")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	emojiID := "01GDQ9G782X42BAMFJ40WFCG2X"
+	emojiURI := "http://localhost:8080/emoji/01GDQ9G782X42BAMFJ40WFCG2X"
+
+	// the emoji is well below the image size limit, but above the emoji size limit
+	viper.Set(config.Keys.MediaEmojiMaxSize, 1024)
+	defer viper.Set(config.Keys.MediaEmojiMaxSize, 51200)
+
+	processingEmoji, err := suite.manager.ProcessEmoji(ctx, data, nil, "rainbow_test", emojiID, emojiURI, nil)
+	suite.NoError(err)
+
+	emoji, err := processingEmoji.LoadEmoji(ctx)
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Nil(emoji)
+}
+
+func (suite *ManagerTestSuite) TestEmojiNotHeldUpByAttachments() {
+	ctx := context.Background()
+
+	// attachment jobs that hang until we let them go
+	release := make(chan struct{})
+	defer close(release)
+	hangingData := func(_ context.Context) (io.Reader, int, error) {
+		<-release
+		return nil, 0, fmt.Errorf("released")
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// tie up every attachment worker
+	for i := 0; i < suite.manager.NumWorkers(); i++ {
+		_, err := suite.manager.ProcessMedia(ctx, hangingData, nil, accountID, nil)
+		suite.NoError(err)
+	}
+	suite.Eventually(func() bool {
+		return suite.manager.ActiveWorkers() == suite.manager.NumWorkers()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	emojiData := func(_ context.Context) (io.Reader, int, error) {
+		b, err := os.ReadFile("./test/test-png-noalphachannel.png")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	emojiID := "01GDQ9G782X42BAMFJ40WFCG2V"
+	emojiURI := "http://localhost:8080/emoji/01GDQ9G782X42BAMFJ40WFCG2V"
+
+	processingEmoji, err := suite.manager.ProcessEmoji(ctx, emojiData, nil, "pool_test", emojiID, emojiURI, nil)
+	suite.NoError(err)
+
+	// the emoji pool should get on with it without waiting for the attachments
+	select {
+	case <-processingEmoji.Done():
+	case <-time.After(10 * time.Second):
+		suite.FailNow("timed out waiting for emoji to finish processing")
+	}
+	suite.NoError(processingEmoji.Err())
+}
+
+func (suite *ManagerTestSuite) TestSameRemoteURLProcessedOnce() {
+	ctx := context.Background()
+
+	var fetches int32
+	release := make(chan struct{})
+	data := func(_ context.Context) (io.Reader, int, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+	remoteURL := "http://example.org/media/some_image.jpeg"
+	ai := &media.AdditionalMediaInfo{RemoteURL: &remoteURL}
+
+	// the same url arriving a few times at once should only be fetched and processed once
+	first, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, ai)
+	suite.NoError(err)
+	second, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, ai)
+	suite.NoError(err)
+	suite.Same(first, second)
+
+	// a different account using the same url gets its own attachment
+	otherAccount, err := suite.manager.ProcessMedia(ctx, data, nil, "01F8MH1H7YV1Z7D2C8K2730QBF", ai)
+	suite.NoError(err)
+	suite.NotEqual(first.AttachmentID(), otherAccount.AttachmentID())
+
+	// and so does the same account using the same url in a different status, but it shares the fetch of the url
+	otherStatusID := "01FVW7JHQFSFK166WWKR8CBA6M"
+	otherStatus, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, &media.AdditionalMediaInfo{RemoteURL: &remoteURL, StatusID: &otherStatusID})
+	suite.NoError(err)
+	suite.NotEqual(first.AttachmentID(), otherStatus.AttachmentID())
+
+	close(release)
+
+	for _, processingMedia := range []*media.ProcessingMedia{first, otherAccount, otherStatus} {
+		select {
+		case <-processingMedia.Done():
+		case <-time.After(10 * time.Second):
+			suite.FailNow("timed out waiting for media to finish processing")
+		}
+		suite.NoError(processingMedia.Err())
+	}
+	suite.EqualValues(2, atomic.LoadInt32(&fetches))
+
+	// once it's done, the url can be fetched again
+	var again *media.ProcessingMedia
+	suite.Eventually(func() bool {
+		again, err = suite.manager.ProcessMedia(ctx, data, nil, accountID, ai)
+		suite.NoError(err)
+		return again != first
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = again.LoadAttachment(ctx)
+	suite.NoError(err)
+}
+
+func (suite *ManagerTestSuite) TestRemoteUnknownTypeProcess() {
+	ctx := context.Background()
+
+	b := []byte("%PDF-1.4\n% not a real pdf, but it starts like one\n%%EOF\n")
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+	remoteURL := "http://example.org/media/some_document.pdf"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, &media.AdditionalMediaInfo{RemoteURL: &remoteURL})
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	// the file should be stored as it is, without a thumbnail
+	suite.Equal(gtsmodel.FileTypeUnknown, attachment.Type)
+	suite.Equal(gtsmodel.ProcessingStatusProcessed, attachment.Processing)
+	suite.Equal("application/pdf", attachment.File.ContentType)
+	suite.Equal(len(b), attachment.File.FileSize)
+	suite.True(strings.HasSuffix(attachment.File.Path, ".pdf"))
+	suite.Empty(attachment.Variants)
+	_, err = suite.storage.Get(attachment.Thumbnail.Path)
+	suite.ErrorIs(err, storage.ErrNotFound)
+
+	stored, err := suite.storage.Get(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(b, stored)
+}
+
+func (suite *ManagerTestSuite) TestLocalUnknownTypeRejected() {
+	ctx := context.Background()
+
+	b := []byte("%PDF-1.4\n% not a real pdf, but it starts like one\n%%EOF\n")
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	// local media has to be something we can process
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.EqualError(err, "store: media type application/pdf not (yet) supported")
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestRemoteUnknownTypeTooLarge() {
+	ctx := context.Background()
+
+	b := append([]byte("%PDF-1.4\n"), make([]byte, 2048)...)
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+	remoteURL := "http://example.org/media/some_document.pdf"
+
+	viper.Set(config.Keys.MediaUnknownMaxSize, 1024)
+	defer viper.Set(config.Keys.MediaUnknownMaxSize, 5242880)
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, &media.AdditionalMediaInfo{RemoteURL: &remoteURL})
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Nil(attachment)
+}
+
+// mp4Box returns an mp4 box of the given type, holding the given content.
+func mp4Box(boxType string, content ...[]byte) []byte {
+	b := bytes.Join(content, nil)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(b)+8))
+	copy(header[4:], boxType)
+	return append(header, b...)
+}
+
+// testMp4 returns a minimal mp4 with one video track of the given dimensions and duration, with
+// the moov box after the media data like an mp4 that hasn't been made ready for streaming.
+func testMp4(width uint32, height uint32, seconds uint32) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)         // timescale
+	binary.BigEndian.PutUint32(mvhd[16:], seconds*1000) // duration
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], width<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], height<<16)
+
+	return bytes.Join([][]byte{
+		mp4Box("ftyp", []byte("isom"), make([]byte, 4), []byte("isomiso2mp41")),
+		mp4Box("mdat", make([]byte, 1024)),
+		mp4Box("moov", mp4Box("mvhd", mvhd), mp4Box("trak", mp4Box("tkhd", tkhd))),
+	}, nil)
+}
+
+func (suite *ManagerTestSuite) processRemoteVideo(b []byte) (*media.ProcessingMedia, *gtsmodel.MediaAttachment, error) {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+	remoteURL := "http://example.org/media/some_video.mp4"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, &media.AdditionalMediaInfo{RemoteURL: &remoteURL})
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	return processingMedia, attachment, err
+}
+
+func (suite *ManagerTestSuite) TestRemoteVideoProcess() {
+	b := testMp4(1280, 720, 60)
+
+	_, attachment, err := suite.processRemoteVideo(b)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	suite.Equal("video/mp4", attachment.File.ContentType)
+	suite.Equal(len(b), attachment.File.FileSize)
+
+	stored, err := suite.storage.Get(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(b, stored)
+}
+
+func (suite *ManagerTestSuite) TestRemoteVideoTooLarge() {
+	// videos are held to the video size limit, rather than the limit on media of unknown types
+	viper.Set(config.Keys.MediaVideoMaxSize, 1024)
+	defer viper.Set(config.Keys.MediaVideoMaxSize, 5242880)
+
+	_, attachment, err := suite.processRemoteVideo(testMp4(1280, 720, 60))
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestRemoteVideoTooManyPixels() {
+	processingMedia, attachment, err := suite.processRemoteVideo(testMp4(3840, 2160, 60))
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Contains(err.Error(), "3840x2160 is more than the max of 2304000 pixels")
+	suite.Nil(attachment)
+
+	// the stored file should have been cleaned up
+	_, err = suite.storage.Get(fmt.Sprintf("01FS1X72SK9ZPW0J1QQ68BD264/attachment/original/%s.mp4", processingMedia.AttachmentID()))
+	suite.ErrorIs(err, storage.ErrNotFound)
+}
+
+func (suite *ManagerTestSuite) TestRemoteVideoTooLong() {
+	_, attachment, err := suite.processRemoteVideo(testMp4(1280, 720, 301))
+	suite.ErrorIs(err, media.ErrTooLarge)
+	suite.Contains(err.Error(), "301.0 seconds is longer than the max of 300 seconds")
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestRemoteVideoInvalid() {
+	// an mp4 without a moov box can't be checked, so it isn't stored
+	b := testMp4(1280, 720, 60)
+	_, attachment, err := suite.processRemoteVideo(b[:len(b)-100])
+	suite.Error(err)
+	suite.Nil(attachment)
+}
+
+func (suite *ManagerTestSuite) TestJpegWithOrientationProcessBlocking() {
+	ctx := context.Background()
+
+	// a 60x40 jpeg, red on the left and blue on the right, with an exif orientation
+	// saying it needs to be rotated 90 degrees clockwise to be the right way up
+	data := func(_ context.Context) (io.Reader, int, error) {
+		b, err := os.ReadFile("./test/test-jpeg-orientation-6.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	// the pixels themselves should have been rotated, so width and height swap over
+	suite.EqualValues(gtsmodel.Original{
+		Width: 40, Height: 60, Size: 2400, Aspect: 0.6666666666666666,
+	}, attachment.FileMeta.Original)
+	suite.Equal(40, attachment.FileMeta.Small.Width)
+	suite.Equal(60, attachment.FileMeta.Small.Height)
+
+	processedFullBytes, err := suite.storage.Get(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(len(processedFullBytes), attachment.File.FileSize)
+	suite.NotContains(string(processedFullBytes), "Exif")
+
+	// what was on the left is now at the top
+	processed, err := jpeg.Decode(bytes.NewReader(processedFullBytes))
+	suite.NoError(err)
+	r, _, b, _ := processed.At(20, 5).RGBA()
+	suite.Greater(r, b)
+	r, _, b, _ = processed.At(20, 55).RGBA()
+	suite.Greater(b, r)
+}
+
+func (suite *ManagerTestSuite) TestJpegProcessProgressive() {
+	ctx := context.Background()
+
+	viper.Set(config.Keys.MediaJpegProgressive, true)
+	defer viper.Set(config.Keys.MediaJpegProgressive, false)
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, accountID, nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+	suite.NotNil(attachment)
+
+	suite.EqualValues(gtsmodel.Original{
+		Width: 1920, Height: 1080, Size: 2073600, Aspect: 1.7777777777777777,
+	}, attachment.FileMeta.Original)
+	suite.EqualValues(gtsmodel.Small{
+		Width: 512, Height: 288, Size: 147456, Aspect: 1.7777777777777777,
+	}, attachment.FileMeta.Small)
+
+	// the full-size jpeg should be progressive (start of frame 2)...
+	processedFullBytes, err := suite.storage.Get(attachment.File.Path)
+	suite.NoError(err)
+	suite.Equal(len(processedFullBytes), attachment.File.FileSize)
+	suite.True(bytes.Contains(processedFullBytes, []byte{0xff, 0xc2}))
+	_, err = jpeg.Decode(bytes.NewReader(processedFullBytes))
+	suite.NoError(err)
+
+	// ...but the thumbnail should still be baseline (start of frame 0)
+	processedThumbnailBytes, err := suite.storage.Get(attachment.Thumbnail.Path)
+	suite.NoError(err)
+	suite.True(bytes.Contains(processedThumbnailBytes, []byte{0xff, 0xc0}))
+	suite.False(bytes.Contains(processedThumbnailBytes, []byte{0xff, 0xc2}))
+}
+
 func TestManagerTestSuite(t *testing.T) {
 	suite.Run(t, &ManagerTestSuite{})
 }
@@ -0,0 +1,175 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+const (
+	metricsNamespace = "gotosocial"
+	metricsSubsystem = "media"
+
+	jobAttachment = "attachment" // jobAttachment is the metrics label for jobs processing new attachments
+	jobEmoji      = "emoji"      // jobEmoji is the metrics label for jobs processing new emoji
+	jobRecache    = "recache"    // jobRecache is the metrics label for jobs recaching existing attachments
+
+	integrityMissing   = "missing"   // integrityMissing is the metrics label for attachments with files missing from storage
+	integrityCorrupted = "corrupted" // integrityCorrupted is the metrics label for attachments with files that don't match their checksums
+)
+
+var (
+	// processingDuration measures how long media jobs take, from being picked up by a worker until they finish.
+	processingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "processing_duration_seconds",
+		Help:      "Time taken by the media manager to process a piece of media, by job type.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"type"})
+
+	// processingFailures counts media jobs that finished with an error.
+	processingFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "processing_failures_total",
+		Help:      "Number of media jobs that failed, by job type.",
+	}, []string{"type"})
+
+	// processingTimeouts counts media jobs that were given up on because they took longer than the job timeout.
+	processingTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "processing_timeouts_total",
+		Help:      "Number of media jobs that were given up on for taking longer than the configured job timeout, by job type.",
+	}, []string{"type"})
+
+	// integrityProblems counts attachments found to be damaged by media integrity checks.
+	integrityProblems = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "integrity_problems_total",
+		Help:      "Number of attachments found by integrity checks to have missing or corrupted files, by problem.",
+	}, []string{"problem"})
+
+	// storedBytes counts bytes put in storage by finished media jobs, including thumbnails.
+	storedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "stored_bytes_total",
+		Help:      "Number of bytes of media, including thumbnails, put in storage by the media manager.",
+	})
+
+	// prunedBytes counts bytes removed from storage when pruning remote media.
+	prunedBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "pruned_bytes_total",
+		Help:      "Number of bytes of remote media, including thumbnails, removed from storage by the media manager.",
+	})
+)
+
+// RegisterMetrics registers gauges describing the worker pools and
+// queues of the given manager with the given prometheus registerer.
+//
+// Metrics about the media processed by the manager are always registered
+// with the default prometheus registerer, so they don't need to be
+// registered separately.
+func RegisterMetrics(registerer prometheus.Registerer, m Manager) error {
+	gauges := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "workers",
+			Help:      "Total number of workers available to the media manager.",
+		}, func() float64 { return float64(m.NumWorkers()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "active_workers",
+			Help:      "Number of media manager workers currently processing a job.",
+		}, func() float64 { return float64(m.ActiveWorkers()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "queue_size",
+			Help:      "Total capacity of the media manager job queue.",
+		}, func() float64 { return float64(m.QueueSize()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "jobs_queued",
+			Help:      "Number of jobs currently waiting in the media manager job queue.",
+		}, func() float64 { return float64(m.JobsQueued()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "emoji_workers",
+			Help:      "Total number of workers available to the media manager for processing emoji.",
+		}, func() float64 { return float64(m.EmojiNumWorkers()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "emoji_active_workers",
+			Help:      "Number of media manager emoji workers currently processing a job.",
+		}, func() float64 { return float64(m.EmojiActiveWorkers()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "emoji_queue_size",
+			Help:      "Total capacity of the media manager emoji job queue.",
+		}, func() float64 { return float64(m.EmojiQueueSize()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "emoji_jobs_queued",
+			Help:      "Number of jobs currently waiting in the media manager emoji job queue.",
+		}, func() float64 { return float64(m.EmojiJobsQueued()) }),
+	}
+
+	for _, g := range gauges {
+		if err := registerer.Register(g); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// observeJob records the duration and outcome of a media job of the given type that began at begin.
+func observeJob(jobType string, begin time.Time, err error) {
+	processingDuration.WithLabelValues(jobType).Observe(time.Since(begin).Seconds())
+	if err != nil {
+		processingFailures.WithLabelValues(jobType).Inc()
+	}
+}
+
+// attachmentStorageSize returns the total amount of bytes that the
+// given attachment takes up in storage, including any thumbnails.
+func attachmentStorageSize(attachment *gtsmodel.MediaAttachment) int {
+	size := attachment.File.FileSize + attachment.Thumbnail.FileSize
+	for _, variant := range attachment.Variants {
+		size += variant.FileSize
+	}
+	return size
+}
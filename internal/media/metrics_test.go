@@ -0,0 +1,138 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+)
+
+type MetricsTestSuite struct {
+	MediaStandardTestSuite
+}
+
+// gatherValue returns the value of the metric with the given name and (optional)
+// type label from the given gatherer. For histograms, the sample count is returned.
+func (suite *MetricsTestSuite) gatherValue(gatherer prometheus.Gatherer, name string, jobType string) float64 {
+	families, err := gatherer.Gather()
+	suite.NoError(err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if jobType != "" {
+				var matches bool
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "type" && label.GetValue() == jobType {
+						matches = true
+					}
+				}
+				if !matches {
+					continue
+				}
+			}
+
+			switch {
+			case metric.Gauge != nil:
+				return metric.Gauge.GetValue()
+			case metric.Counter != nil:
+				return metric.Counter.GetValue()
+			case metric.Histogram != nil:
+				return float64(metric.Histogram.GetSampleCount())
+			}
+		}
+	}
+
+	// metric hasn't been observed yet
+	return 0
+}
+
+func (suite *MetricsTestSuite) TestRegisterMetrics() {
+	registry := prometheus.NewRegistry()
+	suite.NoError(media.RegisterMetrics(registry, suite.manager))
+
+	suite.EqualValues(suite.manager.NumWorkers(), suite.gatherValue(registry, "gotosocial_media_workers", ""))
+	suite.EqualValues(suite.manager.QueueSize(), suite.gatherValue(registry, "gotosocial_media_queue_size", ""))
+	suite.EqualValues(0, suite.gatherValue(registry, "gotosocial_media_jobs_queued", ""))
+	suite.EqualValues(suite.manager.EmojiNumWorkers(), suite.gatherValue(registry, "gotosocial_media_emoji_workers", ""))
+	suite.EqualValues(suite.manager.EmojiQueueSize(), suite.gatherValue(registry, "gotosocial_media_emoji_queue_size", ""))
+	suite.EqualValues(0, suite.gatherValue(registry, "gotosocial_media_emoji_jobs_queued", ""))
+
+	// registering the same metrics twice should fail
+	suite.Error(media.RegisterMetrics(registry, suite.manager))
+}
+
+func (suite *MetricsTestSuite) TestProcessMediaMetrics() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		// load bytes from a test image
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	storedBefore := suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_stored_bytes_total", "")
+	jobsBefore := suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_processing_duration_seconds", "attachment")
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, "01FS1X72SK9ZPW0J1QQ68BD264", nil)
+	suite.NoError(err)
+
+	attachment, err := processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+
+	// both the full size and the thumbnail should be counted
+	storedAfter := suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_stored_bytes_total", "")
+	suite.EqualValues(attachment.File.FileSize+attachment.Thumbnail.FileSize, storedAfter-storedBefore)
+
+	// the job is observed by the worker once it's done, which might be a tiny bit after we get the attachment
+	suite.Eventually(func() bool {
+		return suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_processing_duration_seconds", "attachment") == jobsBefore+1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *MetricsTestSuite) TestPruneRemoteMetrics() {
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	prunedBefore := suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_pruned_bytes_total", "")
+
+	totalPruned, err := suite.manager.PruneRemote(context.Background(), 1)
+	suite.NoError(err)
+	suite.Equal(1, totalPruned)
+
+	prunedAfter := suite.gatherValue(prometheus.DefaultGatherer, "gotosocial_media_pruned_bytes_total", "")
+	suite.EqualValues(testAttachment.File.FileSize+testAttachment.Thumbnail.FileSize, prunedAfter-prunedBefore)
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, &MetricsTestSuite{})
+}
@@ -0,0 +1,311 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+)
+
+// maxExifHeaderBytes is how much of the start of a jpeg is looked through for its exif orientation.
+// Exif data has to fit in a single 64KiB segment near the start of the file, so this is plenty.
+const maxExifHeaderBytes = 128 * 1024
+
+// exifOrientationNormal is the exif orientation of an image whose pixels are already the right way up.
+const exifOrientationNormal = 1
+
+// maxJpegQuality is the highest quality that jpegs are encoded again at. The photo has already
+// been compressed once, so we don't want to lose more detail than we have to.
+const maxJpegQuality = 95
+
+// prepareJpeg reads the exif orientation of the jpeg in r. Phones usually store photos in whatever
+// way the camera sensor was held, and set the orientation so that they're displayed the right way up;
+// once exif data has been stripped, the photo would be displayed sideways or upside down instead.
+//
+// If the jpeg isn't already the right way up, or progressive is true and the jpeg isn't progressive already,
+// it's decoded, its pixels are transformed according to its orientation, and it's encoded again (as a progressive
+// jpeg if progressive is true), and a reader of the new jpeg is returned along with its size. Otherwise, a reader
+// of the jpeg as it was is returned, along with the given fileSize.
+//
+// Jpegs are encoded again at the quality they were originally encoded at, up to maxJpegQuality, since encoding
+// them at a higher quality than that would make them bigger without bringing back any of the detail they've lost.
+//
+// The dimensions of the jpeg are checked against the given limits before it's decoded.
+func prepareJpeg(r io.Reader, fileSize int, l limits, progressive bool) (io.Reader, int, error) {
+	buffered := bufio.NewReaderSize(r, maxExifHeaderBytes)
+
+	// a short peek just means the jpeg is smaller than the buffer; any
+	// real read error will show up again when the rest is read
+	header, _ := buffered.Peek(maxExifHeaderBytes)
+
+	orientation := jpegOrientation(header)
+	if orientation == exifOrientationNormal && (!progressive || jpegIsProgressive(header)) {
+		return buffered, fileSize, nil
+	}
+
+	quality := maxJpegQuality
+	if q := jpegQuality(header); q > 0 && q < quality {
+		quality = q
+	}
+
+	b, err := io.ReadAll(buffered)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := l.checkDimensions(bytes.NewReader(b), mimeImageJpeg); err != nil {
+		return nil, 0, err
+	}
+
+	i, err := jpeg.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error decoding jpeg: %s", err)
+	}
+
+	out := &bytes.Buffer{}
+	if progressive {
+		err = encodeProgressiveJpeg(out, applyOrientation(i, orientation), quality)
+	} else {
+		err = jpeg.Encode(out, applyOrientation(i, orientation), &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("error encoding jpeg: %s", err)
+	}
+
+	return out, out.Len(), nil
+}
+
+// jpegOrientation returns the exif orientation (1-8) of the jpeg that starts with the given header. If the
+// jpeg doesn't have an orientation, or the header can't be parsed, then exifOrientationNormal is returned.
+func jpegOrientation(header []byte) int {
+	orientation := exifOrientationNormal
+	walkJpegHeader(header, func(marker byte, data []byte) bool {
+		if marker == 0xe1 && bytes.HasPrefix(data, []byte("Exif\x00\x00")) {
+			orientation = tiffOrientation(data[6:])
+			return false
+		}
+		return true
+	})
+	return orientation
+}
+
+// jpegIsProgressive returns true if the jpeg that starts with the given header is a progressive jpeg.
+func jpegIsProgressive(header []byte) bool {
+	progressive := false
+	walkJpegHeader(header, func(marker byte, data []byte) bool {
+		switch marker {
+		case 0xc2, 0xc6, 0xca, 0xce:
+			// start of frame for any of the progressive modes
+			progressive = true
+			return false
+		case 0xc0, 0xc1, 0xc3, 0xc5, 0xc7, 0xc9, 0xcb, 0xcd, 0xcf:
+			// start of frame for any other mode
+			return false
+		}
+		return true
+	})
+	return progressive
+}
+
+// jpegQuality estimates the quality (1-100) that the jpeg that starts with the given header was encoded with, by
+// comparing its luma quantization table with the example table that encoders scale by quality, in the same way as
+// libjpeg. If the jpeg doesn't have a luma quantization table in the header, 0 is returned.
+func jpegQuality(header []byte) int {
+	quality := 0
+	walkJpegHeader(header, func(marker byte, data []byte) bool {
+		if marker != 0xdb {
+			return true
+		}
+
+		// a segment can hold several tables, each with 8 or 16 bit values
+		for len(data) > 0 {
+			precision, table := data[0]>>4, data[0]&0x0f
+			size := jpegBlockSize
+			if precision != 0 {
+				size *= 2
+			}
+			if len(data) < 1+size {
+				return false
+			}
+
+			if table == 0 {
+				scale := 0
+				for i, q := range jpegQuant[0] {
+					v := int(data[1+i])
+					if precision != 0 {
+						v = int(binary.BigEndian.Uint16(data[1+i*2:]))
+					}
+					scale += v * 100 / q
+				}
+				scale /= jpegBlockSize
+
+				switch {
+				case scale <= 0:
+					quality = 100
+				case scale <= 100:
+					quality = (200 - scale + 1) / 2
+				default:
+					quality = (5000 + scale/2) / scale
+				}
+				if quality < 1 {
+					quality = 1
+				}
+				return false
+			}
+
+			data = data[1+size:]
+		}
+		return true
+	})
+	return quality
+}
+
+// walkJpegHeader calls fn with the marker and data of each segment in the jpeg that starts with the given header, in
+// order, until fn returns false, or the image data starts, or the rest of the header is too short or can't be parsed.
+func walkJpegHeader(header []byte, fn func(marker byte, data []byte) bool) {
+	if len(header) < 2 || header[0] != 0xff || header[1] != 0xd8 {
+		return
+	}
+
+	for pos := 2; pos+4 <= len(header); {
+		if header[pos] != 0xff {
+			return
+		}
+
+		marker := header[pos+1]
+		if marker == 0xff {
+			// markers can be padded with any number of 0xff bytes
+			pos++
+			continue
+		}
+		if marker == 0xda || marker == 0xd9 {
+			// start of scan or end of image, so there's no more metadata
+			return
+		}
+
+		length := int(binary.BigEndian.Uint16(header[pos+2 : pos+4]))
+		if length < 2 {
+			return
+		}
+
+		dataStart := pos + 4
+		dataEnd := pos + 2 + length
+		if dataEnd > len(header) || !fn(marker, header[dataStart:dataEnd]) {
+			return
+		}
+
+		pos = dataEnd
+	}
+}
+
+// tiffOrientation returns the orientation tag from the first image file directory of the given tiff data.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return exifOrientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return exifOrientationNormal
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return exifOrientationNormal
+	}
+
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		// the orientation is a single SHORT, so its value is stored in the entry itself
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 && order.Uint16(tiff[entry+2:entry+4]) == 3 {
+			if o := int(order.Uint16(tiff[entry+8 : entry+10])); o >= 1 && o <= 8 {
+				return o
+			}
+			break
+		}
+	}
+
+	return exifOrientationNormal
+}
+
+// applyOrientation returns a copy of i with its pixels flipped and rotated so that
+// an image with the given exif orientation is the right way up.
+func applyOrientation(i image.Image, orientation int) image.Image {
+	b := i.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// the source pixel for each pixel (x, y) of the transformed image
+	var source func(x, y int) (int, int)
+	switch orientation {
+	case 2: // mirrored horizontally
+		source = func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3: // rotated 180
+		source = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4: // mirrored vertically
+		source = func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5: // mirrored along the top-left to bottom-right diagonal
+		source = func(x, y int) (int, int) { return y, x }
+	case 6: // needs rotating 90 clockwise
+		source = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7: // mirrored along the top-right to bottom-left diagonal
+		source = func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8: // needs rotating 90 anticlockwise
+		source = func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return i
+	}
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		// the image is turned on its side, so width and height swap over
+		dw, dh = h, w
+	}
+
+	// read from an rgba copy, since At on a decoded jpeg has to convert every pixel
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), i, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := source(x, y)
+			si := src.PixOffset(sx, sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+
+	return dst
+}
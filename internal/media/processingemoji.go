@@ -28,10 +28,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"codeberg.org/gruf/go-store/kv"
+	"codeberg.org/gruf/go-store/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
@@ -64,12 +65,31 @@ type ProcessingEmoji struct {
 	*/
 
 	database db.DB
-	storage  *kv.KVStore
+	storage  *gtsstorage.Driver
 
 	err error // error created during processing, if any
 
 	// track whether this emoji has already been put in the databse
 	insertedInDB bool
+
+	// held while the result of processing is put in the database, so that
+	// the job can't be given up on after it's been stored
+	commitMu sync.Mutex
+
+	// the emoji as it was before, if this is a refresh of an emoji that's already stored,
+	// and whether its files have been removed to make way for the new ones yet
+	refreshing      *gtsmodel.Emoji
+	removedOldFiles bool
+
+	// the size and dimension limits that this emoji is checked against
+	limits limits
+
+	// id of the job persisted for this emoji, if any, so it can be resumed after a restart
+	jobID string
+
+	done     chan struct{} // closed when processing has finished, successfully or not
+	doneOnce sync.Once     // makes sure done is only closed once
+	doneErr  error         // error that processing finished with, if any
 }
 
 // EmojiID returns the ID of the underlying emoji without blocking processing.
@@ -80,9 +100,86 @@ func (p *ProcessingEmoji) EmojiID() string {
 // LoadEmoji blocks until the static and fullsize image
 // has been processed, and then returns the completed emoji.
 func (p *ProcessingEmoji) LoadEmoji(ctx context.Context) (*gtsmodel.Emoji, error) {
+	// a job that's been given up on might still be holding the lock, so check this before waiting for it
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	// don't try again if processing has already failed
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	emoji, err := p.load(ctx)
+	p.finish(err)
+	return emoji, err
+}
+
+// Done returns a channel that's closed once processing of this emoji has finished,
+// either successfully or with an error. After it's closed, LoadEmoji will return
+// without doing any more processing, and Err will return any processing error.
+func (p *ProcessingEmoji) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error that processing of this emoji finished with.
+// If processing hasn't finished yet, or it finished successfully, Err returns nil.
+func (p *ProcessingEmoji) Err() error {
+	select {
+	case <-p.done:
+		return p.doneErr
+	default:
+		return nil
+	}
+}
+
+// cleanup removes any files of this emoji from storage, for when processing has been given up on.
+func (p *ProcessingEmoji) cleanup() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the files of an emoji that's being refreshed are still the stored emoji's until they've been replaced
+	if p.refreshing != nil && !p.removedOldFiles {
+		return
+	}
+
+	for _, path := range []string{p.emoji.ImagePath, p.emoji.ImageStaticPath} {
+		if path == "" {
+			continue
+		}
+		if err := p.storage.Delete(path); err != nil && err != storage.ErrNotFound {
+			logrus.Errorf("cleanup: error removing file %s of emoji %s: %s", path, p.emoji.ID, err)
+		}
+	}
+}
+
+// finish marks processing of this emoji as finished with the given error, and
+// notifies anyone waiting on Done. Only the first call to finish has any effect.
+func (p *ProcessingEmoji) finish(err error) {
+	p.doneOnce.Do(func() {
+		p.doneErr = err
+		close(p.done)
+	})
+}
+
+// abort marks processing of this emoji as finished with the given error, unless processing has
+// already stored its result in the database, in which case abort does nothing and returns false.
+// Once abort has returned true, processing will fail without storing anything in the database.
+func (p *ProcessingEmoji) abort(err error) bool {
+	p.commitMu.Lock()
+	defer p.commitMu.Unlock()
+
+	if p.insertedInDB {
+		return false
+	}
+	p.finish(err)
+	return true
+}
+
+func (p *ProcessingEmoji) load(ctx context.Context) (*gtsmodel.Emoji, error) {
 	if err := p.store(ctx); err != nil {
 		return nil, err
 	}
@@ -93,10 +190,23 @@ func (p *ProcessingEmoji) LoadEmoji(ctx context.Context) (*gtsmodel.Emoji, error
 
 	// store the result in the database before returning it
 	if !p.insertedInDB {
-		if err := p.database.Put(ctx, p.emoji); err != nil {
+		p.commitMu.Lock()
+		defer p.commitMu.Unlock()
+
+		// if the job was given up on while we were processing, then don't store anything
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+
+		if p.refreshing != nil {
+			if err := p.database.UpdateByPrimaryKey(ctx, p.emoji); err != nil {
+				return nil, err
+			}
+		} else if err := p.database.Put(ctx, p.emoji); err != nil {
 			return nil, err
 		}
 		p.insertedInDB = true
+		storedBytes.Add(float64(p.emoji.ImageFileSize + p.emoji.ImageStaticFileSize))
 	}
 
 	return p.emoji, nil
@@ -142,6 +252,7 @@ func (p *ProcessingEmoji) loadStatic(ctx context.Context) error {
 		}
 
 		p.emoji.ImageStaticFileSize = len(static.small)
+		p.emoji.ImageStaticChecksum = checksum(static.small)
 
 		// we're done processing the static version of the emoji!
 		atomic.StoreInt32(&p.staticState, int32(complete))
@@ -179,16 +290,30 @@ func (p *ProcessingEmoji) store(ctx context.Context) error {
 		}
 	}()
 
+	// don't let more than the max size be read, even if the data function claims the
+	// emoji is smaller -- but if it's honest about being too big we can bail right now
+	limited := newSizeLimitedReader(reader, p.limits.maxSize)
+	if limited.limit > 0 && fileSize > limited.limit {
+		return fmt.Errorf("store: %w", limited.err())
+	}
+
 	// extract no more than 261 bytes from the beginning of the file -- this is the header
+	// (emoji can be very small, so there might not even be that many bytes)
 	firstBytes := make([]byte, maxFileHeaderBytes)
-	if _, err := reader.Read(firstBytes); err != nil {
+	n, err := io.ReadFull(limited, firstBytes)
+	if err != nil && err != io.ErrUnexpectedEOF {
 		return fmt.Errorf("store: error reading initial %d bytes: %s", maxFileHeaderBytes, err)
 	}
+	firstBytes = firstBytes[:n]
 
 	// now we have the file header we can work out the content type from it
 	contentType, err := parseContentType(firstBytes)
 	if err != nil {
-		return fmt.Errorf("store: error parsing content type: %s", err)
+		// svgs don't have any magic bytes in their header, so check for them separately
+		if !looksLikeSVG(firstBytes) {
+			return fmt.Errorf("store: error parsing content type: %s", err)
+		}
+		contentType = mimeImageSvg
 	}
 
 	// bail if this is a type we can't process
@@ -199,6 +324,25 @@ func (p *ProcessingEmoji) store(ctx context.Context) error {
 	// extract the file extension
 	split := strings.Split(contentType, "/")
 	extension := split[1] // something like 'gif'
+	if contentType == mimeImageSvg {
+		extension = mimeSvg
+	}
+
+	// concatenate the first bytes with the existing bytes still in the reader (thanks Mara)
+	var stored io.Reader = io.MultiReader(bytes.NewBuffer(firstBytes), limited)
+
+	// svgs can contain scripts and links to other resources, so they need to be sanitized before they're stored
+	if contentType == mimeImageSvg {
+		sanitized, err := sanitizeSVG(stored)
+		if err != nil {
+			if limited.exceeded() {
+				return fmt.Errorf("store: %w", limited.err())
+			}
+			return fmt.Errorf("store: error sanitizing svg: %s", err)
+		}
+		stored = bytes.NewReader(sanitized)
+		fileSize = len(sanitized)
+	}
 
 	// set some additional fields on the emoji now that
 	// we know more about what the underlying image actually is
@@ -207,14 +351,36 @@ func (p *ProcessingEmoji) store(ctx context.Context) error {
 	p.emoji.ImageContentType = contentType
 	p.emoji.ImageFileSize = fileSize
 
-	// concatenate the first bytes with the existing bytes still in the reader (thanks Mara)
-	multiReader := io.MultiReader(bytes.NewBuffer(firstBytes), reader)
+	// the old files of an emoji that's being refreshed make way for the new ones; if storing the new ones
+	// fails, the emoji is left as it was in the database, so it'll be refreshed again next time it's seen
+	if p.refreshing != nil {
+		for _, path := range []string{p.refreshing.ImagePath, p.refreshing.ImageStaticPath} {
+			if err := p.storage.Delete(path); err != nil && err != storage.ErrNotFound {
+				return fmt.Errorf("store: error removing old file %s: %s", path, err)
+			}
+		}
+		p.removedOldFiles = true
+	}
 
 	// store this for now -- other processes can pull it out of storage as they please
-	if err := p.storage.PutStream(p.emoji.ImagePath, multiReader); err != nil {
+	checksummed := newChecksumReader(stored)
+	if err := p.storage.PutStream(p.emoji.ImagePath, checksummed); err != nil {
+		// don't leave half a file lying around in storage
+		if deleteErr := p.storage.Delete(p.emoji.ImagePath); deleteErr != nil && deleteErr != storage.ErrNotFound {
+			logrus.Errorf("store: error removing partially stored file %s: %s", p.emoji.ImagePath, deleteErr)
+		}
+		if limited.exceeded() {
+			return fmt.Errorf("store: %w", limited.err())
+		}
 		return fmt.Errorf("store: error storing stream: %s", err)
 	}
 
+	// make sure the emoji isn't too big to decode before anything tries to decode it
+	if err := checkStoredDimensions(p.storage, p.emoji.ImagePath, contentType, p.limits); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	p.emoji.ImageChecksum = checksummed.sum()
+
 	p.read = true
 
 	if p.postData != nil {
@@ -286,15 +452,20 @@ func (m *manager) preProcessEmoji(ctx context.Context, data DataFunc, postData P
 		}
 	}
 
-	processingEmoji := &ProcessingEmoji{
-		instanceAccountID: instanceAccount.ID,
+	return m.newProcessingEmoji(instanceAccount.ID, emoji, data, postData), nil
+}
+
+// newProcessingEmoji returns a ProcessingEmoji for the given emoji, ready to start processing.
+func (m *manager) newProcessingEmoji(instanceAccountID string, emoji *gtsmodel.Emoji, data DataFunc, postData PostDataCallbackFunc) *ProcessingEmoji {
+	return &ProcessingEmoji{
+		instanceAccountID: instanceAccountID,
 		emoji:             emoji,
 		data:              data,
 		postData:          postData,
 		staticState:       int32(received),
 		database:          m.db,
 		storage:           m.storage,
+		limits:            emojiLimits(),
+		done:              make(chan struct{}),
 	}
-
-	return processingEmoji, nil
 }
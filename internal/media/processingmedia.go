@@ -28,12 +28,15 @@ import (
 	"sync/atomic"
 	"time"
 
-	"codeberg.org/gruf/go-store/kv"
+	"codeberg.org/gruf/go-store/storage"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	terminator "github.com/superseriousbusiness/exif-terminator"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
@@ -54,6 +57,7 @@ type ProcessingMedia struct {
 
 	thumbState    int32 // the processing state of the media thumbnail
 	fullSizeState int32 // the processing state of the full-sized media
+	gifvState     int32 // the processing state of the gifv conversion, if any
 
 	/*
 		below pointers to database and storage are maintained so that
@@ -61,15 +65,35 @@ type ProcessingMedia struct {
 	*/
 
 	database db.DB
-	storage  *kv.KVStore
+	storage  *gtsstorage.Driver
+
+	// the thumbnails that should be derived for this media
+	thumbnailSizes []thumbnailSize
+
+	// the size and dimension limits that this media is checked against
+	limits limits
+
+	// amount of frames in the media, set when the full size is processed
+	frames int
 
 	err error // error created during processing, if any
 
 	// track whether this media has already been put in the databse
 	insertedInDB bool
 
+	// held while the result of processing is put in the database, so that
+	// the job can't be given up on after it's been stored
+	commitMu sync.Mutex
+
 	// true if this is a recache, false if it's brand new media
 	recache bool
+
+	// id of the job persisted for this media, if any, so it can be resumed after a restart
+	jobID string
+
+	done     chan struct{} // closed when processing has finished, successfully or not
+	doneOnce sync.Once     // makes sure done is only closed once
+	doneErr  error         // error that processing finished with, if any
 }
 
 // AttachmentID returns the ID of the underlying media attachment without blocking processing.
@@ -80,11 +104,91 @@ func (p *ProcessingMedia) AttachmentID() string {
 // LoadAttachment blocks until the thumbnail and fullsize content
 // has been processed, and then returns the completed attachment.
 func (p *ProcessingMedia) LoadAttachment(ctx context.Context) (*gtsmodel.MediaAttachment, error) {
+	// a job that's been given up on might still be holding the lock, so check this before waiting for it
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
 	logrus.Tracef("LoadAttachment: getting lock for attachment %s", p.attachment.URL)
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	logrus.Tracef("LoadAttachment: got lock for attachment %s", p.attachment.URL)
 
+	// don't try again if processing has already failed
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	attachment, err := p.load(ctx)
+	p.finish(err)
+	return attachment, err
+}
+
+// Done returns a channel that's closed once processing of this media has finished,
+// either successfully or with an error. After it's closed, LoadAttachment will return
+// without doing any more processing, and Err will return any processing error.
+//
+// This is useful for callers who want to know when the media manager's worker pool
+// has finished processing the media, without doing the processing themselves.
+func (p *ProcessingMedia) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error that processing of this media finished with.
+// If processing hasn't finished yet, or it finished successfully, Err returns nil.
+func (p *ProcessingMedia) Err() error {
+	select {
+	case <-p.done:
+		return p.doneErr
+	default:
+		return nil
+	}
+}
+
+// finish marks processing of this media as finished with the given error, and
+// notifies anyone waiting on Done. Only the first call to finish has any effect.
+func (p *ProcessingMedia) finish(err error) {
+	p.doneOnce.Do(func() {
+		p.doneErr = err
+		close(p.done)
+	})
+}
+
+// abort marks processing of this media as finished with the given error, unless processing has
+// already stored its result in the database, in which case abort does nothing and returns false.
+// Once abort has returned true, processing will fail without storing anything in the database.
+func (p *ProcessingMedia) abort(err error) bool {
+	p.commitMu.Lock()
+	defer p.commitMu.Unlock()
+
+	if p.insertedInDB {
+		return false
+	}
+	p.finish(err)
+	return true
+}
+
+// cleanup removes any files of this media from storage, for when processing has been given up on.
+func (p *ProcessingMedia) cleanup() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	paths := []string{p.attachment.File.Path, p.attachment.Thumbnail.Path}
+	for _, variant := range p.attachment.Variants {
+		paths = append(paths, variant.Path)
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := p.storage.Delete(path); err != nil && err != storage.ErrNotFound {
+			logrus.Errorf("cleanup: error removing file %s of attachment %s: %s", path, p.attachment.ID, err)
+		}
+	}
+}
+
+func (p *ProcessingMedia) load(ctx context.Context) (*gtsmodel.MediaAttachment, error) {
 	if err := p.store(ctx); err != nil {
 		return nil, err
 	}
@@ -97,8 +201,20 @@ func (p *ProcessingMedia) LoadAttachment(ctx context.Context) (*gtsmodel.MediaAt
 		return nil, err
 	}
 
+	if err := p.loadGifv(ctx); err != nil {
+		return nil, err
+	}
+
 	// store the result in the database before returning it
 	if !p.insertedInDB {
+		p.commitMu.Lock()
+		defer p.commitMu.Unlock()
+
+		// if the job was given up on while we were processing, then don't store anything
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+
 		if p.recache {
 			// if it's a recache we should only need to update
 			if err := p.database.UpdateByPrimaryKey(ctx, p.attachment); err != nil {
@@ -111,22 +227,31 @@ func (p *ProcessingMedia) LoadAttachment(ctx context.Context) (*gtsmodel.MediaAt
 			}
 		}
 		p.insertedInDB = true
+		storedBytes.Add(float64(attachmentStorageSize(p.attachment)))
 	}
 
 	logrus.Tracef("LoadAttachment: finished, returning attachment %s", p.attachment.URL)
 	return p.attachment, nil
 }
 
-// Finished returns true if processing has finished for both the thumbnail
-// and full fized version of this piece of media.
+// Finished returns true if processing has finished for the thumbnail,
+// full sized version, and gifv conversion of this piece of media.
 func (p *ProcessingMedia) Finished() bool {
-	return atomic.LoadInt32(&p.thumbState) == int32(complete) && atomic.LoadInt32(&p.fullSizeState) == int32(complete)
+	return atomic.LoadInt32(&p.thumbState) == int32(complete) &&
+		atomic.LoadInt32(&p.fullSizeState) == int32(complete) &&
+		atomic.LoadInt32(&p.gifvState) == int32(complete)
 }
 
 func (p *ProcessingMedia) loadThumb(ctx context.Context) error {
 	thumbState := atomic.LoadInt32(&p.thumbState)
 	switch processState(thumbState) {
 	case received:
+		// media of unknown types is stored without a thumbnail
+		if p.attachment.Type == gtsmodel.FileTypeUnknown {
+			atomic.StoreInt32(&p.thumbState, int32(complete))
+			return nil
+		}
+
 		// we haven't processed a thumbnail for this media yet so do it now
 
 		// check if we need to create a blurhash or if there's already one set
@@ -153,15 +278,18 @@ func (p *ProcessingMedia) loadThumb(ctx context.Context) error {
 			}
 		}()
 
-		// stream the file from storage straight into the derive thumbnail function
-		logrus.Tracef("loadThumb: calling deriveThumbnail %s", p.attachment.URL)
-		thumb, err := deriveThumbnail(stored, p.attachment.File.ContentType, createBlurhash)
+		// stream the file from storage straight into the derive thumbnails function
+		logrus.Tracef("loadThumb: calling deriveThumbnails %s", p.attachment.URL)
+		thumbs, err := deriveThumbnails(stored, p.attachment.File.ContentType, createBlurhash, p.thumbnailSizes)
 		if err != nil {
-			p.err = fmt.Errorf("loadThumb: error deriving thumbnail: %s", err)
+			p.err = fmt.Errorf("loadThumb: error deriving thumbnails: %s", err)
 			atomic.StoreInt32(&p.thumbState, int32(errored))
 			return p.err
 		}
 
+		// the first thumbnail is always the small one
+		thumb := thumbs[0]
+
 		// put the thumbnail in storage
 		logrus.Tracef("loadThumb: storing new thumbnail %s", p.attachment.URL)
 		if err := p.storage.Put(p.attachment.Thumbnail.Path, thumb.small); err != nil {
@@ -170,6 +298,34 @@ func (p *ProcessingMedia) loadThumb(ctx context.Context) error {
 			return p.err
 		}
 
+		// put any additional variants in storage too
+		variants := make([]gtsmodel.Variant, 0, len(thumbs)-1)
+		for idx, variantThumb := range thumbs[1:] {
+			name := string(p.thumbnailSizes[idx+1].size)
+			variant := gtsmodel.Variant{
+				Name:        name,
+				Path:        fmt.Sprintf("%s/%s/%s/%s.%s", p.attachment.AccountID, TypeAttachment, name, p.attachment.ID, mimeJpeg), // all thumbnails are encoded as jpeg
+				ContentType: mimeImageJpeg,
+				FileSize:    len(variantThumb.small),
+				Checksum:    checksum(variantThumb.small),
+				UpdatedAt:   time.Now(),
+				URL:         uris.GenerateURIForAttachment(p.attachment.AccountID, string(TypeAttachment), name, p.attachment.ID, mimeJpeg),
+				Width:       variantThumb.width,
+				Height:      variantThumb.height,
+				Size:        variantThumb.size,
+				Aspect:      variantThumb.aspect,
+			}
+
+			logrus.Tracef("loadThumb: storing new %s thumbnail variant %s", name, p.attachment.URL)
+			if err := p.storage.Put(variant.Path, variantThumb.small); err != nil {
+				p.err = fmt.Errorf("loadThumb: error storing %s thumbnail variant: %s", name, err)
+				atomic.StoreInt32(&p.thumbState, int32(errored))
+				return p.err
+			}
+			variants = append(variants, variant)
+		}
+		p.attachment.Variants = variants
+
 		// set appropriate fields on the attachment based on the thumbnail we derived
 		if createBlurhash {
 			p.attachment.Blurhash = thumb.blurhash
@@ -181,6 +337,7 @@ func (p *ProcessingMedia) loadThumb(ctx context.Context) error {
 			Aspect: thumb.aspect,
 		}
 		p.attachment.Thumbnail.FileSize = len(thumb.small)
+		p.attachment.Thumbnail.Checksum = checksum(thumb.small)
 
 		// we're done processing the thumbnail!
 		atomic.StoreInt32(&p.thumbState, int32(complete))
@@ -199,6 +356,14 @@ func (p *ProcessingMedia) loadFullSize(ctx context.Context) error {
 	fullSizeState := atomic.LoadInt32(&p.fullSizeState)
 	switch processState(fullSizeState) {
 	case received:
+		// media of unknown types is stored as it is, so there's nothing to decode
+		if p.attachment.Type == gtsmodel.FileTypeUnknown {
+			p.attachment.File.UpdatedAt = time.Now()
+			p.attachment.Processing = gtsmodel.ProcessingStatusProcessed
+			atomic.StoreInt32(&p.fullSizeState, int32(complete))
+			return nil
+		}
+
 		var err error
 		var decoded *imageMeta
 
@@ -242,6 +407,7 @@ func (p *ProcessingMedia) loadFullSize(ctx context.Context) error {
 		}
 		p.attachment.File.UpdatedAt = time.Now()
 		p.attachment.Processing = gtsmodel.ProcessingStatusProcessed
+		p.frames = decoded.frames
 
 		// we're done processing the full-size image
 		atomic.StoreInt32(&p.fullSizeState, int32(complete))
@@ -256,6 +422,97 @@ func (p *ProcessingMedia) loadFullSize(ctx context.Context) error {
 	return fmt.Errorf("loadFullSize: full size processing status %d unknown", p.fullSizeState)
 }
 
+func (p *ProcessingMedia) loadGifv(ctx context.Context) error {
+	gifvState := atomic.LoadInt32(&p.gifvState)
+	switch processState(gifvState) {
+	case received:
+		// check if this media should be converted at all; if not, there's nothing to do
+		if p.attachment.File.ContentType != mimeImageGif || !shouldConvertGifv(p.frames, p.attachment.File.FileSize) {
+			atomic.StoreInt32(&p.gifvState, int32(complete))
+			return nil
+		}
+
+		// get the original gif out of storage
+		logrus.Tracef("loadGifv: fetching gif from storage %s", p.attachment.URL)
+		gifBytes, err := p.storage.Get(p.attachment.File.Path)
+		if err != nil {
+			p.err = fmt.Errorf("loadGifv: error fetching gif from storage: %s", err)
+			atomic.StoreInt32(&p.gifvState, int32(errored))
+			return p.err
+		}
+
+		logrus.Tracef("loadGifv: calling deriveGifv %s", p.attachment.URL)
+		mp4Bytes, err := deriveGifv(ctx, viper.GetString(config.Keys.MediaGifvFfmpegPath), bytes.NewReader(gifBytes))
+		if err != nil {
+			p.err = fmt.Errorf("loadGifv: error deriving gifv: %s", err)
+			atomic.StoreInt32(&p.gifvState, int32(errored))
+			return p.err
+		}
+
+		// put the converted mp4 in storage
+		mp4Path := fmt.Sprintf("%s/%s/%s/%s.%s", p.attachment.AccountID, TypeAttachment, SizeOriginal, p.attachment.ID, mimeMp4)
+		logrus.Tracef("loadGifv: storing new gifv %s", mp4Path)
+		if err := p.storage.Put(mp4Path, mp4Bytes); err != nil {
+			p.err = fmt.Errorf("loadGifv: error storing gifv: %s", err)
+			atomic.StoreInt32(&p.gifvState, int32(errored))
+			return p.err
+		}
+
+		// either keep the original gif around as a variant, or get rid of it
+		if viper.GetBool(config.Keys.MediaGifvKeepOriginal) {
+			original := p.attachment.FileMeta.Original
+			variant := gtsmodel.Variant{
+				Name:        string(SizeGif),
+				Path:        fmt.Sprintf("%s/%s/%s/%s.%s", p.attachment.AccountID, TypeAttachment, SizeGif, p.attachment.ID, mimeGif),
+				ContentType: mimeImageGif,
+				FileSize:    len(gifBytes),
+				Checksum:    checksum(gifBytes),
+				UpdatedAt:   time.Now(),
+				URL:         uris.GenerateURIForAttachment(p.attachment.AccountID, string(TypeAttachment), string(SizeGif), p.attachment.ID, mimeGif),
+				Width:       original.Width,
+				Height:      original.Height,
+				Size:        original.Size,
+				Aspect:      original.Aspect,
+			}
+
+			logrus.Tracef("loadGifv: storing original gif %s", variant.Path)
+			if err := p.storage.Put(variant.Path, gifBytes); err != nil {
+				p.err = fmt.Errorf("loadGifv: error storing original gif: %s", err)
+				atomic.StoreInt32(&p.gifvState, int32(errored))
+				return p.err
+			}
+			p.attachment.Variants = append(p.attachment.Variants, variant)
+		}
+
+		logrus.Tracef("loadGifv: deleting gif %s", p.attachment.File.Path)
+		if err := p.storage.Delete(p.attachment.File.Path); err != nil {
+			p.err = fmt.Errorf("loadGifv: error deleting gif: %s", err)
+			atomic.StoreInt32(&p.gifvState, int32(errored))
+			return p.err
+		}
+
+		// the attachment now refers to the mp4 rather than the gif
+		p.attachment.Type = gtsmodel.FileTypeGifv
+		p.attachment.URL = uris.GenerateURIForAttachment(p.attachment.AccountID, string(TypeAttachment), string(SizeOriginal), p.attachment.ID, mimeMp4)
+		p.attachment.File.Path = mp4Path
+		p.attachment.File.ContentType = mimeVideoMp4
+		p.attachment.File.FileSize = len(mp4Bytes)
+		p.attachment.File.Checksum = checksum(mp4Bytes)
+		p.attachment.File.UpdatedAt = time.Now()
+
+		// we're done converting the gif!
+		atomic.StoreInt32(&p.gifvState, int32(complete))
+		logrus.Tracef("loadGifv: finished converting gif to gifv for attachment %s", p.attachment.URL)
+		fallthrough
+	case complete:
+		return nil
+	case errored:
+		return p.err
+	}
+
+	return fmt.Errorf("loadGifv: gifv processing status %d unknown", p.gifvState)
+}
+
 // store calls the data function attached to p if it hasn't been called yet,
 // and updates the underlying attachment fields as necessary. It will then stream
 // bytes from p's reader directly into storage so that it can be retrieved later.
@@ -281,43 +538,90 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 		}
 	}()
 
+	// don't let more than the max size be read, even if the data function claims the media is
+	// smaller; the limit is checked against the declared size once we know what type of media it is
+	limited := newSizeLimitedReader(reader, p.limits.maxSize)
+
+	// now we know how big the media is, make sure it will fit in the account's quota;
+	// recaches are only done for remote media, which never counts towards a quota
+	if !p.recache {
+		if err := checkQuota(ctx, p.database, p.attachment.AccountID, fileSize); err != nil {
+			return err
+		}
+	}
+
 	// extract no more than 261 bytes from the beginning of the file -- this is the header
 	firstBytes := make([]byte, maxFileHeaderBytes)
-	if _, err := reader.Read(firstBytes); err != nil {
+	n, err := limited.Read(firstBytes)
+	if err != nil {
 		return fmt.Errorf("store: error reading initial %d bytes: %s", maxFileHeaderBytes, err)
 	}
+	firstBytes = firstBytes[:n]
 
 	// now we have the file header we can work out the content type from it
+	var extension string
+	var passthrough bool
 	contentType, err := parseContentType(firstBytes)
-	if err != nil {
+	switch {
+	case err == nil && supportedImage(contentType):
+		// extract the file extension
+		split := strings.Split(contentType, "/")
+		if len(split) != 2 {
+			return fmt.Errorf("store: content type %s was not valid", contentType)
+		}
+		extension = split[1] // something like 'jpeg'
+	case p.attachment.RemoteURL != "" && unknownLimits().maxSize != 0:
+		// remote media that we can't process can still be stored as it is, so that people can download it
+		contentType, extension = passthroughContentType(firstBytes)
+		p.limits = unknownLimits()
+		if strings.HasPrefix(contentType, mimeVideo+"/") {
+			// videos can't be processed yet either, but they're still held to the limits on videos
+			p.limits = videoLimits()
+		}
+		limited.limit = p.limits.maxSize
+		passthrough = true
+	case err != nil:
 		return fmt.Errorf("store: error parsing content type: %s", err)
-	}
-
-	// bail if this is a type we can't process
-	if !supportedImage(contentType) {
+	default:
+		// bail if this is a type we can't process
 		return fmt.Errorf("store: media type %s not (yet) supported", contentType)
 	}
 
-	// extract the file extension
-	split := strings.Split(contentType, "/")
-	if len(split) != 2 {
-		return fmt.Errorf("store: content type %s was not valid", contentType)
+	// if the data function is honest about the media being too big we can bail right now
+	if limited.limit > 0 && fileSize > limited.limit {
+		return fmt.Errorf("store: %w", limited.err())
 	}
-	extension := split[1] // something like 'jpeg'
 
 	// concatenate the cleaned up first bytes with the existing bytes still in the reader (thanks Mara)
-	multiReader := io.MultiReader(bytes.NewBuffer(firstBytes), reader)
+	multiReader := io.MultiReader(bytes.NewBuffer(firstBytes), limited)
 
 	// we'll need to clean exif data from the first bytes; while we're
-	// here, we can also use the extension to derive the attachment type
+	// here, we can also use the extension to derive the attachment type.
+	// Jpegs are turned the right way up first, since their exif orientation
+	// won't be around to tell anything how to display them once it's cleaned,
+	// and they're made progressive at the same time if that's configured.
 	var clean io.Reader
-	switch extension {
-	case mimeGif:
+	switch {
+	case passthrough:
+		// we can't do anything with media of unknown types except store it, so there's no thumbnail either
+		p.attachment.Type = gtsmodel.FileTypeUnknown
+		clean = multiReader
+	case extension == mimeGif:
 		p.attachment.Type = gtsmodel.FileTypeGif
 		clean = multiReader // nothing to clean from a gif
-	case mimeJpeg, mimePng:
+	case extension == mimeJpeg || extension == mimePng:
 		p.attachment.Type = gtsmodel.FileTypeImage
-		purged, err := terminator.Terminate(multiReader, fileSize, extension)
+		toClean := multiReader
+		if extension == mimeJpeg {
+			toClean, fileSize, err = prepareJpeg(multiReader, fileSize, p.limits, viper.GetBool(config.Keys.MediaJpegProgressive))
+			if err != nil {
+				if limited.exceeded() {
+					return fmt.Errorf("store: %w", limited.err())
+				}
+				return fmt.Errorf("store: error preparing jpeg: %w", err)
+			}
+		}
+		purged, err := terminator.Terminate(toClean, fileSize, extension)
 		if err != nil {
 			return fmt.Errorf("store: exif error: %s", err)
 		}
@@ -343,9 +647,23 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 	p.attachment.File.FileSize = fileSize
 
 	// store this for now -- other processes can pull it out of storage as they please
-	if err := p.storage.PutStream(p.attachment.File.Path, clean); err != nil {
+	checksummed := newChecksumReader(clean)
+	if err := p.storage.PutStream(p.attachment.File.Path, checksummed); err != nil {
+		// don't leave half a file lying around in storage
+		if deleteErr := p.storage.Delete(p.attachment.File.Path); deleteErr != nil && deleteErr != storage.ErrNotFound {
+			logrus.Errorf("store: error removing partially stored file %s: %s", p.attachment.File.Path, deleteErr)
+		}
+		if limited.exceeded() {
+			return fmt.Errorf("store: %w", limited.err())
+		}
 		return fmt.Errorf("store: error storing stream: %s", err)
 	}
+
+	// make sure the media isn't too big to decode before anything tries to decode it
+	if err := checkStoredDimensions(p.storage, p.attachment.File.Path, contentType, p.limits); err != nil {
+		return fmt.Errorf("store: %w", err)
+	}
+	p.attachment.File.Checksum = checksummed.sum()
 	p.attachment.Cached = true
 	p.read = true
 
@@ -358,6 +676,12 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 }
 
 func (m *manager) preProcessMedia(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, accountID string, ai *AdditionalMediaInfo) (*ProcessingMedia, error) {
+	// bail early if the account has already used up its quota: we don't know how big the media
+	// is until the data function is called, but it'll take up at least one byte in storage
+	if err := checkQuota(ctx, m.db, accountID, 1); err != nil {
+		return nil, err
+	}
+
 	id, err := id.NewRandomULID()
 	if err != nil {
 		return nil, err
@@ -442,17 +766,7 @@ func (m *manager) preProcessMedia(ctx context.Context, data DataFunc, postData P
 		}
 	}
 
-	processingMedia := &ProcessingMedia{
-		attachment:    attachment,
-		data:          data,
-		postData:      postData,
-		thumbState:    int32(received),
-		fullSizeState: int32(received),
-		database:      m.db,
-		storage:       m.storage,
-	}
-
-	return processingMedia, nil
+	return m.newProcessingMedia(attachment, data, postData, false), nil
 }
 
 func (m *manager) preProcessRecache(ctx context.Context, data DataFunc, postData PostDataCallbackFunc, attachmentID string) (*ProcessingMedia, error) {
@@ -462,16 +776,23 @@ func (m *manager) preProcessRecache(ctx context.Context, data DataFunc, postData
 		return nil, err
 	}
 
-	processingMedia := &ProcessingMedia{
-		attachment:    attachment,
-		data:          data,
-		postData:      postData,
-		thumbState:    int32(received),
-		fullSizeState: int32(received),
-		database:      m.db,
-		storage:       m.storage,
-		recache:       true, // indicate it's a recache
-	}
+	return m.newProcessingMedia(attachment, data, postData, true), nil
+}
 
-	return processingMedia, nil
+// newProcessingMedia returns a ProcessingMedia for the given attachment, ready to start processing.
+func (m *manager) newProcessingMedia(attachment *gtsmodel.MediaAttachment, data DataFunc, postData PostDataCallbackFunc, recache bool) *ProcessingMedia {
+	return &ProcessingMedia{
+		attachment:     attachment,
+		data:           data,
+		postData:       postData,
+		thumbState:     int32(received),
+		fullSizeState:  int32(received),
+		gifvState:      int32(received),
+		database:       m.db,
+		storage:        m.storage,
+		thumbnailSizes: m.thumbnailSizes,
+		limits:         imageLimits(),
+		done:           make(chan struct{}),
+		recache:        recache,
+	}
 }
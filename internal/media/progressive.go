@@ -0,0 +1,593 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+)
+
+/*
+   The standard library can only encode baseline jpegs, where the image is stored one
+   block of 8x8 pixels at a time, so slow connections show the image filling in from top
+   to bottom. Progressive jpegs store the image in several scans over the whole image
+   instead, each adding more detail, so a blurry version of the whole image is shown
+   as soon as the first scan has arrived.
+
+   The encoder below always encodes images as YCbCr with 4:2:0 chroma subsampling, and
+   uses the example quantization and huffman tables from annex K of the jpeg spec
+   (ITU T.81), just like the standard library does. It uses spectral selection only:
+   the first scan holds the average color of every block, and the following scans add
+   the rest of the detail for each component, starting with the lowest frequencies
+   of the luma, which is what people notice the most.
+*/
+
+// jpegBlockSize is the number of coefficients in an 8x8 block.
+const jpegBlockSize = 64
+
+// jpegZigzag maps from the zig-zag order that coefficients are stored in to
+// their natural order within the 8x8 block, as shown in figure A.6 of the spec.
+var jpegZigzag = [jpegBlockSize]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// jpegQuant are the luma and chroma quantization tables from section K.1 of the spec, in zig-zag order.
+var jpegQuant = [2][jpegBlockSize]int{
+	{
+		16, 11, 12, 14, 12, 10, 16, 14,
+		13, 14, 18, 17, 16, 19, 24, 40,
+		26, 24, 22, 22, 24, 49, 35, 37,
+		29, 40, 58, 51, 61, 60, 57, 51,
+		56, 55, 64, 72, 92, 78, 64, 68,
+		87, 69, 55, 56, 80, 109, 81, 87,
+		95, 98, 103, 104, 103, 62, 77, 113,
+		121, 112, 100, 120, 92, 101, 103, 99,
+	},
+	{
+		17, 18, 18, 24, 21, 24, 47, 26,
+		26, 47, 99, 66, 56, 66, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+	},
+}
+
+// jpegHuffmanSpec is a huffman table as it's written to a jpeg: counts[i] is the
+// number of codes that are i+1 bits long, and values are the values that the codes
+// stand for, in order of code.
+type jpegHuffmanSpec struct {
+	counts [16]byte
+	values []byte
+}
+
+// jpegHuffmanSpecs are the luma dc, luma ac, chroma dc, and chroma ac tables from section K.3 of the spec.
+var jpegHuffmanSpecs = [4]jpegHuffmanSpec{
+	{
+		[16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125},
+		[]byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+			0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+			0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+			0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+			0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+			0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+			0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+			0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+			0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+			0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+			0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+			0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+			0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+			0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+	{
+		[16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119},
+		[]byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+			0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+			0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+			0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+			0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+			0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+			0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+			0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+			0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+			0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+			0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+			0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+			0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+			0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+			0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+			0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+}
+
+// jpegHuffmanCode is the code for a value in a huffman table, and how many bits long it is.
+type jpegHuffmanCode struct {
+	code uint32
+	size uint32
+}
+
+// jpegHuffmanCodes are the codes for each value in jpegHuffmanSpecs, generated as in section C of the spec.
+var jpegHuffmanCodes [4][256]jpegHuffmanCode
+
+// jpegCosines holds cos((2x+1)uπ/16) at [u][x], for the forward dct.
+var jpegCosines [8][8]float64
+
+func init() {
+	for i, spec := range jpegHuffmanSpecs {
+		code, k := uint32(0), 0
+		for size, count := range spec.counts {
+			for j := 0; j < int(count); j++ {
+				jpegHuffmanCodes[i][spec.values[k]] = jpegHuffmanCode{code: code, size: uint32(size + 1)}
+				code++
+				k++
+			}
+			code <<= 1
+		}
+	}
+
+	for u := 0; u < 8; u++ {
+		for x := 0; x < 8; x++ {
+			jpegCosines[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / 16)
+		}
+	}
+}
+
+// jpegScan is a single scan of a progressive jpeg: the coefficients from start to end
+// (inclusive, in zig-zag order) of the given components.
+type jpegScan struct {
+	components []int
+	start      int
+	end        int
+}
+
+// jpegProgressiveScans are the scans that images are encoded with, in order. Components
+// are 0 for luma (Y), and 1 and 2 for chroma (Cb and Cr). Only the dc scan may hold more
+// than one component.
+var jpegProgressiveScans = []jpegScan{
+	{components: []int{0, 1, 2}, start: 0, end: 0},
+	{components: []int{0}, start: 1, end: 5},
+	{components: []int{1}, start: 1, end: 63},
+	{components: []int{2}, start: 1, end: 63},
+	{components: []int{0}, start: 6, end: 63},
+}
+
+// progressiveEncoder holds the state of a progressive jpeg while it's encoded.
+type progressiveEncoder struct {
+	w   *bufio.Writer
+	err error
+
+	// bits that haven't been written yet, and how many of them there are
+	bits  uint32
+	nBits uint32
+
+	// quant are the quantization tables, scaled for the requested quality
+	quant [2][jpegBlockSize]int
+
+	// the number of 16x16 minimum coded units across and down the image
+	mcusX int
+	mcusY int
+
+	// coefficients are the quantized coefficients of every block of each component, in
+	// zig-zag order. Blocks are stored left to right, top to bottom, and cover every mcu,
+	// even where the edge of the image only covers part of the mcu.
+	coefficients [3][][jpegBlockSize]int32
+
+	// width and height of the image in pixels
+	width  int
+	height int
+}
+
+// encodeProgressiveJpeg writes i to w as a progressive jpeg, with a quality between 1 and 100.
+func encodeProgressiveJpeg(w io.Writer, i image.Image, quality int) error {
+	b := i.Bounds()
+	if b.Dx() < 1 || b.Dy() < 1 {
+		return errors.New("image is empty")
+	}
+	if b.Dx() >= 1<<16 || b.Dy() >= 1<<16 {
+		return errors.New("image is too large to encode as jpeg")
+	}
+
+	e := &progressiveEncoder{
+		w:      bufio.NewWriter(w),
+		width:  b.Dx(),
+		height: b.Dy(),
+		mcusX:  (b.Dx() + 15) / 16,
+		mcusY:  (b.Dy() + 15) / 16,
+	}
+	e.scaleQuant(quality)
+	e.transform(i)
+
+	e.write([]byte{0xff, 0xd8}) // start of image
+	e.writeDQT()
+	e.writeSOF2()
+	e.writeDHT()
+	for _, s := range jpegProgressiveScans {
+		e.writeScan(s)
+	}
+	e.write([]byte{0xff, 0xd9}) // end of image
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// scaleQuant scales the quantization tables for the given quality, in the same way as libjpeg.
+func (e *progressiveEncoder) scaleQuant(quality int) {
+	if quality < 1 {
+		quality = 1
+	} else if quality > 100 {
+		quality = 100
+	}
+
+	var scale int
+	if quality < 50 {
+		scale = 5000 / quality
+	} else {
+		scale = 200 - quality*2
+	}
+
+	for t := range e.quant {
+		for i, q := range jpegQuant[t] {
+			q = (q*scale + 50) / 100
+			if q < 1 {
+				q = 1
+			} else if q > 255 {
+				q = 255
+			}
+			e.quant[t][i] = q
+		}
+	}
+}
+
+// transform converts i to YCbCr, and works out the quantized coefficients of every block.
+func (e *progressiveEncoder) transform(i image.Image) {
+	// the planes cover whole mcus, so pixels past the edge of the image repeat the last row or column
+	lumaW, lumaH := e.mcusX*16, e.mcusY*16
+	chromaW, chromaH := e.mcusX*8, e.mcusY*8
+
+	luma := make([]uint8, lumaW*lumaH)
+	cb := make([]uint16, chromaW*chromaH)
+	cr := make([]uint16, chromaW*chromaH)
+
+	b := i.Bounds()
+	ycbcr, _ := i.(*image.YCbCr)
+	rgba, _ := i.(*image.RGBA)
+	for y := 0; y < lumaH; y++ {
+		sy := b.Min.Y + clampEdge(y, e.height)
+		for x := 0; x < lumaW; x++ {
+			sx := b.Min.X + clampEdge(x, e.width)
+
+			var yy, u, v uint8
+			switch {
+			case ycbcr != nil:
+				yi, ci := ycbcr.YOffset(sx, sy), ycbcr.COffset(sx, sy)
+				yy, u, v = ycbcr.Y[yi], ycbcr.Cb[ci], ycbcr.Cr[ci]
+			case rgba != nil:
+				p := rgba.PixOffset(sx, sy)
+				yy, u, v = color.RGBToYCbCr(rgba.Pix[p], rgba.Pix[p+1], rgba.Pix[p+2])
+			default:
+				r, g, bl, _ := i.At(sx, sy).RGBA()
+				yy, u, v = color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+			}
+
+			luma[y*lumaW+x] = yy
+			// each chroma sample is the sum of the 2x2 pixels it covers
+			cb[(y/2)*chromaW+x/2] += uint16(u)
+			cr[(y/2)*chromaW+x/2] += uint16(v)
+		}
+	}
+
+	e.coefficients[0] = make([][jpegBlockSize]int32, e.mcusX*2*e.mcusY*2)
+	e.coefficients[1] = make([][jpegBlockSize]int32, e.mcusX*e.mcusY)
+	e.coefficients[2] = make([][jpegBlockSize]int32, e.mcusX*e.mcusY)
+
+	var samples [jpegBlockSize]float64
+	for by := 0; by < e.mcusY*2; by++ {
+		for bx := 0; bx < e.mcusX*2; bx++ {
+			for y := 0; y < 8; y++ {
+				for x := 0; x < 8; x++ {
+					samples[y*8+x] = float64(luma[(by*8+y)*lumaW+bx*8+x])
+				}
+			}
+			e.fdct(&samples, 0, &e.coefficients[0][by*e.mcusX*2+bx])
+		}
+	}
+	for c, plane := range [][]uint16{cb, cr} {
+		for by := 0; by < e.mcusY; by++ {
+			for bx := 0; bx < e.mcusX; bx++ {
+				for y := 0; y < 8; y++ {
+					for x := 0; x < 8; x++ {
+						samples[y*8+x] = float64(plane[(by*8+y)*chromaW+bx*8+x]) / 4
+					}
+				}
+				e.fdct(&samples, 1, &e.coefficients[c+1][by*e.mcusX+bx])
+			}
+		}
+	}
+}
+
+// clampEdge returns i, or the last index before length if i is past it.
+func clampEdge(i int, length int) int {
+	if i >= length {
+		return length - 1
+	}
+	return i
+}
+
+// fdct applies the forward dct from section A.3.3 of the spec to the given samples,
+// and quantizes the result into out, in zig-zag order.
+func (e *progressiveEncoder) fdct(samples *[jpegBlockSize]float64, table int, out *[jpegBlockSize]int32) {
+	// the dct is separable, so do the rows first and then the columns
+	var rows [jpegBlockSize]float64
+	for y := 0; y < 8; y++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for x := 0; x < 8; x++ {
+				sum += (samples[y*8+x] - 128) * jpegCosines[u][x]
+			}
+			rows[y*8+u] = sum
+		}
+	}
+
+	for zig, natural := range jpegZigzag {
+		u, v := natural%8, natural/8
+		sum := 0.0
+		for y := 0; y < 8; y++ {
+			sum += rows[y*8+u] * jpegCosines[v][y]
+		}
+
+		cu, cv := 1.0, 1.0
+		if u == 0 {
+			cu = math.Sqrt2 / 2
+		}
+		if v == 0 {
+			cv = math.Sqrt2 / 2
+		}
+
+		out[zig] = int32(math.Round(sum * cu * cv / 4 / float64(e.quant[table][zig])))
+	}
+}
+
+func (e *progressiveEncoder) write(p []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(p)
+}
+
+func (e *progressiveEncoder) writeByte(b byte) {
+	if e.err != nil {
+		return
+	}
+	e.err = e.w.WriteByte(b)
+}
+
+// writeMarker writes a marker, followed by the length of the data that will be written after it.
+func (e *progressiveEncoder) writeMarker(marker byte, dataLen int) {
+	length := dataLen + 2 // the length includes itself
+	e.write([]byte{0xff, marker, byte(length >> 8), byte(length)})
+}
+
+// writeDQT writes both quantization tables.
+func (e *progressiveEncoder) writeDQT() {
+	e.writeMarker(0xdb, 2*(1+jpegBlockSize))
+	for t, table := range e.quant {
+		e.writeByte(byte(t))
+		for _, q := range table {
+			e.writeByte(byte(q))
+		}
+	}
+}
+
+// writeSOF2 writes the start of frame for a progressive image, with three components and 4:2:0 subsampling.
+func (e *progressiveEncoder) writeSOF2() {
+	e.writeMarker(0xc2, 6+3*3)
+	e.write([]byte{
+		8, // bits per sample
+		byte(e.height >> 8), byte(e.height),
+		byte(e.width >> 8), byte(e.width),
+		3,          // components
+		1, 0x22, 0, // luma: id, 2x2 sampling, quantization table 0
+		2, 0x11, 1, // chroma blue: id, 1x1 sampling, quantization table 1
+		3, 0x11, 1, // chroma red: id, 1x1 sampling, quantization table 1
+	})
+}
+
+// writeDHT writes all the huffman tables.
+func (e *progressiveEncoder) writeDHT() {
+	dataLen := 0
+	for _, spec := range jpegHuffmanSpecs {
+		dataLen += 1 + 16 + len(spec.values)
+	}
+	e.writeMarker(0xc4, dataLen)
+	for i, spec := range jpegHuffmanSpecs {
+		// table class (0 dc, 1 ac) and destination (0 luma, 1 chroma)
+		e.writeByte(byte(i%2)<<4 | byte(i/2))
+		e.write(spec.counts[:])
+		e.write(spec.values)
+	}
+}
+
+// writeScan writes the start of scan header and the data for s.
+func (e *progressiveEncoder) writeScan(s jpegScan) {
+	e.writeMarker(0xda, 1+2*len(s.components)+3)
+	e.writeByte(byte(len(s.components)))
+	for _, c := range s.components {
+		table := byte(0)
+		if c != 0 {
+			table = 1
+		}
+		e.write([]byte{byte(c + 1), table<<4 | table})
+	}
+	e.write([]byte{byte(s.start), byte(s.end), 0})
+
+	if s.start == 0 {
+		e.writeDCScan(s)
+	} else {
+		e.writeACScan(s)
+	}
+
+	// pad the last byte with 1s, and start the next scan on a fresh byte
+	e.emit(0x7f, 7)
+	e.bits, e.nBits = 0, 0
+}
+
+// writeDCScan writes the dc coefficients of all three components, one mcu at a time.
+func (e *progressiveEncoder) writeDCScan(s jpegScan) {
+	var prev [3]int32
+	for my := 0; my < e.mcusY; my++ {
+		for mx := 0; mx < e.mcusX; mx++ {
+			for _, c := range s.components {
+				if c == 0 {
+					// the four luma blocks of an mcu, left to right, top to bottom
+					for j := 0; j < 4; j++ {
+						bx, by := mx*2+j%2, my*2+j/2
+						dc := e.coefficients[0][by*e.mcusX*2+bx][0]
+						e.emitValue(0, 0, dc-prev[0])
+						prev[0] = dc
+					}
+					continue
+				}
+
+				dc := e.coefficients[c][my*e.mcusX+mx][0]
+				e.emitValue(2, 0, dc-prev[c])
+				prev[c] = dc
+			}
+		}
+	}
+}
+
+// writeACScan writes the given ac coefficients of a single component, one block at a time.
+func (e *progressiveEncoder) writeACScan(s jpegScan) {
+	c := s.components[0]
+	table := 1
+	blocksX, blocksY, stride := e.mcusX, e.mcusY, e.mcusX
+	if c == 0 {
+		// only blocks that are at least partly inside the image are part of a single component scan
+		table = 0
+		blocksX, blocksY, stride = (e.width+7)/8, (e.height+7)/8, e.mcusX*2
+	}
+	h := table*2 + 1
+
+	for by := 0; by < blocksY; by++ {
+		for bx := 0; bx < blocksX; bx++ {
+			block := &e.coefficients[c][by*stride+bx]
+			run := int32(0)
+			for zig := s.start; zig <= s.end; zig++ {
+				if block[zig] == 0 {
+					run++
+					continue
+				}
+				for run > 15 {
+					e.emitHuffman(h, 0xf0) // sixteen zeroes
+					run -= 16
+				}
+				e.emitValue(h, run, block[zig])
+				run = 0
+			}
+			if run > 0 {
+				e.emitHuffman(h, 0x00) // end of block
+			}
+		}
+	}
+}
+
+// emit writes the lowest n bits of bits, stuffing a zero byte after any 0xff byte, as in section F.1.2.3 of the spec.
+func (e *progressiveEncoder) emit(bits, n uint32) {
+	n += e.nBits
+	bits <<= 32 - n
+	bits |= e.bits
+	for n >= 8 {
+		b := byte(bits >> 24)
+		e.writeByte(b)
+		if b == 0xff {
+			e.writeByte(0x00)
+		}
+		bits <<= 8
+		n -= 8
+	}
+	e.bits, e.nBits = bits, n
+}
+
+// emitHuffman writes the code for value from the given huffman table.
+func (e *progressiveEncoder) emitHuffman(table int, value byte) {
+	c := jpegHuffmanCodes[table][value]
+	e.emit(c.code, c.size)
+}
+
+// emitValue writes the code for run zeroes followed by value, and then the bits of value itself.
+func (e *progressiveEncoder) emitValue(table int, run int32, value int32) {
+	bits := value
+	abs := value
+	if value < 0 {
+		// negative values are written as their ones' complement
+		abs = -value
+		bits = value - 1
+	}
+
+	size := uint32(0)
+	for abs > 0 {
+		size++
+		abs >>= 1
+	}
+
+	e.emitHuffman(table, byte(run<<4)|byte(size))
+	if size > 0 {
+		e.emit(uint32(bits)&(1<<size-1), size)
+	}
+}
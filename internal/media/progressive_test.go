@@ -0,0 +1,231 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testPattern returns the color of a smooth pattern at (x, y) in an image of the given size, with
+// different gradients in each channel so that a mix-up between channels would show up.
+func testPattern(x, y, w, h int) color.RGBA {
+	return color.RGBA{
+		R: uint8(x * 255 / w),
+		G: uint8(y * 255 / h),
+		B: uint8((x + y) * 255 / (w + h)),
+		A: 0xff,
+	}
+}
+
+func newTestRGBA(w, h int) image.Image {
+	i := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i.SetRGBA(x, y, testPattern(x, y, w, h))
+		}
+	}
+	return i
+}
+
+func newTestGray(w, h int) image.Image {
+	i := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i.SetGray(x, y, color.Gray{Y: uint8((x + y) * 255 / (w + h))})
+		}
+	}
+	return i
+}
+
+func newTestCMYK(w, h int) image.Image {
+	i := image.NewCMYK(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i.Set(x, y, testPattern(x, y, w, h))
+		}
+	}
+	return i
+}
+
+func newTestYCbCr(w, h int, ratio image.YCbCrSubsampleRatio) image.Image {
+	i := image.NewYCbCr(image.Rect(0, 0, w, h), ratio)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := testPattern(x, y, w, h)
+			yy, cb, cr := color.RGBToYCbCr(c.R, c.G, c.B)
+			i.Y[i.YOffset(x, y)] = yy
+			i.Cb[i.COffset(x, y)] = cb
+			i.Cr[i.COffset(x, y)] = cr
+		}
+	}
+	return i
+}
+
+// pixelDifference returns the mean and the largest absolute difference between the 8 bit
+// color channels of the pixels of a and b, which must be the same size.
+func pixelDifference(a image.Image, b image.Image) (float64, int) {
+	var total, largest int
+	ab, bb := a.Bounds(), b.Bounds()
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			ar, ag, abl, _ := a.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+			br, bg, bbl, _ := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			for _, d := range []int{int(ar>>8) - int(br>>8), int(ag>>8) - int(bg>>8), int(abl>>8) - int(bbl>>8)} {
+				if d < 0 {
+					d = -d
+				}
+				total += d
+				if d > largest {
+					largest = d
+				}
+			}
+		}
+	}
+	return float64(total) / float64(ab.Dx()*ab.Dy()*3), largest
+}
+
+func TestEncodeProgressiveJpeg(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		i    image.Image
+	}{
+		{name: "rgba", i: newTestRGBA(64, 48)},
+		{name: "one pixel", i: newTestRGBA(1, 1)},
+		{name: "odd dimensions", i: newTestRGBA(37, 19)},
+		{name: "narrower than a block", i: newTestRGBA(3, 41)},
+		{name: "gray", i: newTestGray(45, 33)},
+		{name: "cmyk", i: newTestCMYK(40, 30)},
+		{name: "ycbcr 4:4:4", i: newTestYCbCr(33, 17, image.YCbCrSubsampleRatio444)},
+		{name: "ycbcr 4:2:2", i: newTestYCbCr(33, 17, image.YCbCrSubsampleRatio422)},
+		{name: "ycbcr 4:2:0", i: newTestYCbCr(33, 17, image.YCbCrSubsampleRatio420)},
+		{name: "ycbcr 4:4:0", i: newTestYCbCr(33, 17, image.YCbCrSubsampleRatio440)},
+		{name: "offset bounds", i: newTestRGBA(50, 50).(*image.RGBA).SubImage(image.Rect(7, 9, 40, 31))},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := encodeProgressiveJpeg(out, test.i, maxJpegQuality); err != nil {
+				t.Fatalf("error encoding: %s", err)
+			}
+
+			if !jpegIsProgressive(out.Bytes()) {
+				t.Error("encoded jpeg isn't progressive")
+			}
+
+			decoded, err := jpeg.Decode(bytes.NewReader(out.Bytes()))
+			if err != nil {
+				t.Fatalf("error decoding: %s", err)
+			}
+
+			if decoded.Bounds().Dx() != test.i.Bounds().Dx() || decoded.Bounds().Dy() != test.i.Bounds().Dy() {
+				t.Fatalf("decoded image is %v, expected %v", decoded.Bounds().Size(), test.i.Bounds().Size())
+			}
+
+			// jpeg is lossy, so compare with how close the standard library's baseline encoder gets, since
+			// the two should only differ in how the coefficients are ordered in the file, and in rounding
+			baseline := &bytes.Buffer{}
+			if err := jpeg.Encode(baseline, test.i, &jpeg.Options{Quality: maxJpegQuality}); err != nil {
+				t.Fatalf("error encoding baseline: %s", err)
+			}
+			decodedBaseline, err := jpeg.Decode(baseline)
+			if err != nil {
+				t.Fatalf("error decoding baseline: %s", err)
+			}
+
+			mean, largest := pixelDifference(test.i, decoded)
+			baselineMean, baselineLargest := pixelDifference(test.i, decodedBaseline)
+			if mean > baselineMean*1.1+0.5 {
+				t.Errorf("mean difference from the source is %.2f, but only %.2f for a baseline jpeg", mean, baselineMean)
+			}
+			if largest > baselineLargest+2 {
+				t.Errorf("largest difference from the source is %d, but only %d for a baseline jpeg", largest, baselineLargest)
+			}
+		})
+	}
+}
+
+func TestEncodeProgressiveJpegEmpty(t *testing.T) {
+	if err := encodeProgressiveJpeg(&bytes.Buffer{}, image.NewRGBA(image.Rect(0, 0, 0, 10)), maxJpegQuality); err == nil {
+		t.Error("expected an error encoding an empty image")
+	}
+}
+
+func TestJpegQuality(t *testing.T) {
+	for _, quality := range []int{10, 50, 75, 90, 95} {
+		out := &bytes.Buffer{}
+		if err := jpeg.Encode(out, newTestRGBA(16, 16), &jpeg.Options{Quality: quality}); err != nil {
+			t.Fatalf("error encoding: %s", err)
+		}
+
+		if estimated := jpegQuality(out.Bytes()); estimated < quality-2 || estimated > quality+2 {
+			t.Errorf("estimated quality %d for a jpeg encoded at quality %d", estimated, quality)
+		}
+
+		if jpegIsProgressive(out.Bytes()) {
+			t.Errorf("baseline jpeg encoded at quality %d was detected as progressive", quality)
+		}
+	}
+
+	if quality := jpegQuality([]byte("not a jpeg")); quality != 0 {
+		t.Errorf("estimated quality %d for something that isn't a jpeg", quality)
+	}
+}
+
+func TestPrepareJpegProgressive(t *testing.T) {
+	progressive := &bytes.Buffer{}
+	if err := encodeProgressiveJpeg(progressive, newTestRGBA(32, 32), 80); err != nil {
+		t.Fatalf("error encoding: %s", err)
+	}
+
+	// a jpeg that's already progressive and the right way up should be left as it is
+	r, size, err := prepareJpeg(bytes.NewReader(progressive.Bytes()), progressive.Len(), limits{}, true)
+	if err != nil {
+		t.Fatalf("error preparing jpeg: %s", err)
+	}
+	prepared := &bytes.Buffer{}
+	if _, err := prepared.ReadFrom(r); err != nil {
+		t.Fatalf("error reading prepared jpeg: %s", err)
+	}
+	if size != progressive.Len() || !bytes.Equal(prepared.Bytes(), progressive.Bytes()) {
+		t.Error("progressive jpeg was encoded again")
+	}
+
+	// a baseline jpeg should be made progressive, at no higher quality than it had before
+	baseline := &bytes.Buffer{}
+	if err := jpeg.Encode(baseline, newTestRGBA(32, 32), &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatalf("error encoding: %s", err)
+	}
+	r, size, err = prepareJpeg(bytes.NewReader(baseline.Bytes()), baseline.Len(), limits{}, true)
+	if err != nil {
+		t.Fatalf("error preparing jpeg: %s", err)
+	}
+	prepared.Reset()
+	if _, err := prepared.ReadFrom(r); err != nil {
+		t.Fatalf("error reading prepared jpeg: %s", err)
+	}
+	if size != prepared.Len() || !jpegIsProgressive(prepared.Bytes()) {
+		t.Error("baseline jpeg wasn't made progressive")
+	}
+	if quality := jpegQuality(prepared.Bytes()); quality > 62 {
+		t.Errorf("baseline jpeg at quality 60 was encoded again at quality %d", quality)
+	}
+}
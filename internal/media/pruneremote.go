@@ -32,8 +32,21 @@ import (
 // amount of media attachments to select at a time from the db when pruning
 const selectPruneLimit = 20
 
+// PruneReport describes the results of pruning remote media from the cache.
+type PruneReport struct {
+	// Started is when pruning began.
+	Started time.Time
+	// Finished is when pruning ended.
+	Finished time.Time
+	// Pruned is the number of attachments that were removed from the cache.
+	Pruned int
+	// PrunedBytes is the number of bytes of media, including thumbnails, that were removed from storage.
+	PrunedBytes int
+}
+
 func (m *manager) PruneRemote(ctx context.Context, olderThanDays int) (int, error) {
-	var totalPruned int
+	var totalPruned, totalPrunedBytes int
+	started := time.Now()
 
 	// convert days into a duration string
 	olderThanHoursString := fmt.Sprintf("%dh", olderThanDays*24)
@@ -56,10 +69,12 @@ func (m *manager) PruneRemote(ctx context.Context, olderThanDays int) (int, erro
 
 		// prune each attachment
 		for _, attachment := range attachments {
+			size := attachmentStorageSize(attachment)
 			if err := m.PruneOne(ctx, attachment); err != nil {
 				return totalPruned, err
 			}
 			totalPruned++
+			totalPrunedBytes += size
 		}
 	}
 
@@ -68,18 +83,38 @@ func (m *manager) PruneRemote(ctx context.Context, olderThanDays int) (int, erro
 		return totalPruned, err
 	}
 
+	m.pruneMu.Lock()
+	m.lastPruneReport = &PruneReport{
+		Started:     started,
+		Finished:    time.Now(),
+		Pruned:      totalPruned,
+		PrunedBytes: totalPrunedBytes,
+	}
+	m.pruneMu.Unlock()
+
 	logrus.Infof("PruneRemote: finished pruning remote media: pruned %d entries", totalPruned)
 	return totalPruned, nil
 }
 
+func (m *manager) LastPruneReport() *PruneReport {
+	m.pruneMu.Lock()
+	defer m.pruneMu.Unlock()
+	return m.lastPruneReport
+}
+
 func (m *manager) PruneOne(ctx context.Context, attachment *gtsmodel.MediaAttachment) error {
+	// work out how much space we're freeing up before we start changing things
+	var pruned int
+	if attachment.Cached {
+		pruned = attachmentStorageSize(attachment)
+	}
+
 	if attachment.File.Path != "" {
 		// delete the full size attachment from storage
 		logrus.Tracef("PruneOne: deleting %s", attachment.File.Path)
 		if err := m.storage.Delete(attachment.File.Path); err != nil && err != storage.ErrNotFound {
 			return err
 		}
-		attachment.Cached = false
 	}
 
 	if attachment.Thumbnail.Path != "" {
@@ -88,9 +123,22 @@ func (m *manager) PruneOne(ctx context.Context, attachment *gtsmodel.MediaAttach
 		if err := m.storage.Delete(attachment.Thumbnail.Path); err != nil && err != storage.ErrNotFound {
 			return err
 		}
-		attachment.Cached = false
+	}
+
+	for _, variant := range attachment.Variants {
+		// delete the thumbnail variant from storage
+		logrus.Tracef("PruneOne: deleting %s", variant.Path)
+		if err := m.storage.Delete(variant.Path); err != nil && err != storage.ErrNotFound {
+			return err
+		}
 	}
 
 	// update the attachment to reflect that we no longer have it cached
-	return m.db.UpdateByPrimaryKey(ctx, attachment)
+	attachment.Cached = false
+	if err := m.db.UpdateByPrimaryKey(ctx, attachment); err != nil {
+		return err
+	}
+
+	prunedBytes.Add(float64(pruned))
+	return nil
 }
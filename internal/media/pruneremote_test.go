@@ -46,6 +46,13 @@ func (suite *PruneRemoteTestSuite) TestPruneRemote() {
 
 	// the media should no longer be cached
 	suite.False(prunedAttachment.Cached)
+
+	// the results should have been kept
+	report := suite.manager.LastPruneReport()
+	suite.NotNil(report)
+	suite.Equal(1, report.Pruned)
+	suite.Equal(testAttachment.File.FileSize+testAttachment.Thumbnail.FileSize, report.PrunedBytes)
+	suite.False(report.Finished.Before(report.Started))
 }
 
 func (suite *PruneRemoteTestSuite) TestPruneRemoteTwice() {
@@ -84,8 +91,9 @@ func (suite *PruneRemoteTestSuite) TestPruneAndRecache() {
 		}
 		return bytes.NewBuffer(b), len(b), nil
 	}
-	processingRecache, err := suite.manager.RecacheMedia(ctx, data, nil, testAttachment.ID)
+	processingRecache, started, err := suite.manager.RecacheMedia(ctx, data, nil, testAttachment.ID)
 	suite.NoError(err)
+	suite.True(started)
 
 	// synchronously load the recached attachment
 	recachedAttachment, err := processingRecache.LoadAttachment(ctx)
@@ -98,6 +106,8 @@ func (suite *PruneRemoteTestSuite) TestPruneAndRecache() {
 	suite.Equal(testAttachment.File.Path, recachedAttachment.File.Path)           // file should be stored in the same place
 	suite.Equal(testAttachment.Thumbnail.Path, recachedAttachment.Thumbnail.Path) // as should the thumbnail
 	suite.EqualValues(testAttachment.FileMeta, recachedAttachment.FileMeta)       // and the filemeta should be the same
+	suite.Len(recachedAttachment.File.Checksum, 64)                               // checksums should be stored
+	suite.Len(recachedAttachment.Thumbnail.Checksum, 64)
 
 	// recached files should be back in storage
 	_, err = suite.storage.Get(recachedAttachment.File.Path)
@@ -0,0 +1,85 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// ErrQuotaExceeded is returned when storing a piece of media would take
+// the total media stored by a local account over its media storage quota.
+var ErrQuotaExceeded = errors.New("media storage quota exceeded")
+
+// AccountQuota returns the media storage quota in bytes that applies to the given user,
+// taking into account both the instance default and any override set on the user.
+//
+// A return value of 0 means that the user may store an unlimited amount of media.
+func AccountQuota(user *gtsmodel.User) int {
+	switch {
+	case user.MediaQuota < 0:
+		// explicitly unlimited for this user
+		return 0
+	case user.MediaQuota > 0:
+		// the user has an override set
+		return user.MediaQuota
+	default:
+		// fall back to the instance default
+		return viper.GetInt(config.Keys.MediaAccountQuota)
+	}
+}
+
+// checkQuota returns an error wrapping ErrQuotaExceeded if storing size more bytes of media
+// for the given accountID would exceed that account's media storage quota.
+//
+// Accounts without a corresponding user (ie., remote accounts) don't have a quota, so
+// no error will be returned for them.
+func checkQuota(ctx context.Context, database db.DB, accountID string, size int) error {
+	user := &gtsmodel.User{}
+	if err := database.GetWhere(ctx, []db.Where{{Key: "account_id", Value: accountID}}, user); err != nil {
+		if err == db.ErrNoEntries {
+			// not a local account so there's nothing to check
+			return nil
+		}
+		return fmt.Errorf("checkQuota: error getting user for account %s: %s", accountID, err)
+	}
+
+	quota := AccountQuota(user)
+	if quota == 0 {
+		// unlimited
+		return nil
+	}
+
+	used, err := database.GetAccountMediaSize(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("checkQuota: error getting media size for account %s: %s", accountID, err)
+	}
+
+	if used+size > quota {
+		return fmt.Errorf("%w: %d of %d bytes already used, cannot store %d more bytes", ErrQuotaExceeded, used, quota, size)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// amount of media attachments to select at a time from the db when recaching
+const selectRecacheLimit = 100
+
+func (m *manager) RecacheRemote(ctx context.Context, accountID string, domain string, data RecacheDataFunc) (int, error) {
+	// count everything up front, so that we can tell the caller how much will be recached
+	count, err := m.db.CountRemoteUncached(ctx, accountID, domain)
+	if err != nil {
+		return 0, fmt.Errorf("RecacheRemote: error counting uncached attachments: %s", err)
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	// the caller's context is probably a request that'll be finished long before the recaching is,
+	// so use the manager's own context to make sure the recaching stops when the manager does
+	go m.recacheRemote(m.stopCtx, accountID, domain, data, viper.GetInt(config.Keys.MediaRecacheBatchRate))
+
+	logrus.Infof("RecacheRemote: queueing %d attachments for recaching", count)
+	return count, nil
+}
+
+// recacheRemote queues the remote attachments that aren't cached, of the given account and on the given domain if
+// they're set, to be recached, at no more than perSecond attachments per second. The attachments are selected from
+// the db a page at a time as they're needed, so that a big batch doesn't have to be held in memory all at once.
+func (m *manager) recacheRemote(ctx context.Context, accountID string, domain string, data RecacheDataFunc, perSecond int) {
+	var queued int
+	var maxID string
+	for {
+		page, err := m.db.GetRemoteUncached(ctx, accountID, domain, maxID, selectRecacheLimit)
+		if err != nil && err != db.ErrNoEntries {
+			logrus.Errorf("recacheRemote: error getting uncached attachments: %s", err)
+			break
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		queued += m.recacheBatch(ctx, page, data, perSecond)
+		if ctx.Err() != nil {
+			break
+		}
+
+		maxID = page[len(page)-1].ID
+	}
+
+	logrus.Infof("recacheRemote: finished queueing %d attachments", queued)
+}
+
+// recacheBatch queues the given attachments to be recached, at no more than perSecond attachments per second,
+// and returns how many were queued. Attachments that are already being recached aren't recached a second time.
+func (m *manager) recacheBatch(ctx context.Context, attachments []*gtsmodel.MediaAttachment, data RecacheDataFunc, perSecond int) int {
+	var tick <-chan time.Time
+	if perSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(perSecond))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var queued int
+	for _, attachment := range attachments {
+		if tick != nil {
+			// wait for our turn, unless we're told to stop
+			select {
+			case <-ctx.Done():
+			case <-tick:
+			}
+		}
+
+		if ctx.Err() != nil {
+			logrus.Infof("recacheBatch: stopping early after queueing %d of %d attachments", queued, len(attachments))
+			return queued
+		}
+
+		if _, _, err := m.RecacheMedia(ctx, data(attachment.RemoteURL), nil, attachment.ID); err != nil {
+			logrus.Errorf("recacheBatch: error queueing recache of attachment %s: %s", attachment.ID, err)
+			continue
+		}
+		queued++
+	}
+
+	logrus.Debugf("recacheBatch: finished queueing %d of %d attachments", queued, len(attachments))
+	return queued
+}
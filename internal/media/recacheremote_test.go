@@ -0,0 +1,98 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+)
+
+type RecacheRemoteTestSuite struct {
+	MediaStandardTestSuite
+}
+
+func (suite *RecacheRemoteTestSuite) TestPruneAndRecacheRemote() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	totalPruned, err := suite.manager.PruneRemote(ctx, 1)
+	suite.NoError(err)
+	suite.Equal(1, totalPruned)
+
+	data := func(remoteURL string) media.DataFunc {
+		return func(_ context.Context) (io.Reader, int, error) {
+			// load bytes from a test image
+			b, err := os.ReadFile("../../testrig/media/thoughtsofdog-original.jpeg")
+			if err != nil {
+				panic(err)
+			}
+			return bytes.NewBuffer(b), len(b), nil
+		}
+	}
+
+	queued, err := suite.manager.RecacheRemote(ctx, testAttachment.AccountID, "", data)
+	suite.NoError(err)
+	suite.Equal(1, queued)
+
+	// recaching happens in the background, so wait for it to finish
+	var recachedAttachment *gtsmodel.MediaAttachment
+	for i := 0; i < 50; i++ {
+		recachedAttachment, err = suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+		suite.NoError(err)
+		if recachedAttachment.Cached {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	suite.True(recachedAttachment.Cached)
+
+	// recached files should be back in storage
+	_, err = suite.storage.Get(recachedAttachment.File.Path)
+	suite.NoError(err)
+	_, err = suite.storage.Get(recachedAttachment.Thumbnail.Path)
+	suite.NoError(err)
+
+	// nothing else should be left to recache
+	queued, err = suite.manager.RecacheRemote(ctx, "", "", data)
+	suite.NoError(err)
+	suite.Equal(0, queued)
+}
+
+func (suite *RecacheRemoteTestSuite) TestRecacheRemoteNothingToDo() {
+	data := func(remoteURL string) media.DataFunc {
+		suite.FailNow("data func should not be called")
+		return nil
+	}
+
+	queued, err := suite.manager.RecacheRemote(context.Background(), "", "", data)
+	suite.NoError(err)
+	suite.Equal(0, queued)
+}
+
+func TestRecacheRemoteTestSuite(t *testing.T) {
+	suite.Run(t, &RecacheRemoteTestSuite{})
+}
@@ -0,0 +1,153 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// persistJob puts the given job in the database, so that it can be resumed if the manager is
+// stopped before the job is done, and returns the job's id. Processing can go ahead without the
+// job being persisted, so if something goes wrong the error is only logged, and "" is returned.
+func (m *manager) persistJob(ctx context.Context, job *gtsmodel.MediaJob) string {
+	jobID, err := id.NewULID()
+	if err != nil {
+		logrus.Errorf("persistJob: error creating id for %s job: %s", job.Type, err)
+		return ""
+	}
+	job.ID = jobID
+
+	if err := m.db.Put(ctx, job); err != nil {
+		logrus.Errorf("persistJob: error putting %s job for %s in the database: %s", job.Type, job.RemoteURL, err)
+		return ""
+	}
+
+	return jobID
+}
+
+// forgetJob removes the job with the given id from the database, since it doesn't need to be resumed anymore.
+func (m *manager) forgetJob(ctx context.Context, jobID string) {
+	if jobID == "" {
+		return
+	}
+
+	if err := m.db.DeleteByID(ctx, jobID, &gtsmodel.MediaJob{}); err != nil && err != db.ErrNoEntries {
+		logrus.Errorf("forgetJob: error removing job %s from the database: %s", jobID, err)
+	}
+}
+
+func (m *manager) ResumeJobs(ctx context.Context) (int, error) {
+	jobs := []*gtsmodel.MediaJob{}
+	if err := m.db.GetAll(ctx, &jobs); err != nil {
+		if err == db.ErrNoEntries {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("ResumeJobs: error getting jobs from the database: %s", err)
+	}
+
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
+	m.integrityMu.Lock()
+	data := m.recacheData
+	m.integrityMu.Unlock()
+
+	if data == nil {
+		return 0, errors.New("ResumeJobs: no function has been set for fetching remote media")
+	}
+
+	var resumed int
+	for _, job := range jobs {
+		ok, err := m.resumeJob(ctx, job, data)
+		if err != nil {
+			// don't let one bad job stop the rest from being resumed
+			logrus.Errorf("ResumeJobs: error resuming %s job %s: %s", job.Type, job.ID, err)
+			continue
+		}
+		if ok {
+			resumed++
+		}
+	}
+
+	logrus.Infof("ResumeJobs: resumed %d of %d unfinished media jobs", resumed, len(jobs))
+	return resumed, nil
+}
+
+// resumeJob queues the given job again, unless it turns out that the job doesn't need doing anymore,
+// in which case it's just removed from the database. It returns true if the job was queued again.
+func (m *manager) resumeJob(ctx context.Context, job *gtsmodel.MediaJob, data RecacheDataFunc) (bool, error) {
+	switch job.Type {
+	case gtsmodel.MediaJobTypeAttachment, gtsmodel.MediaJobTypeRecache:
+		attachment, err := m.db.GetAttachmentByID(ctx, job.AttachmentID)
+		if err != nil && err != db.ErrNoEntries {
+			return false, fmt.Errorf("error getting attachment %s: %s", job.AttachmentID, err)
+		}
+
+		switch {
+		case attachment != nil && attachment.Cached:
+			// processing finished, we just didn't get to remove the job before being stopped
+		case attachment != nil:
+			// the attachment's in the database already, so we only need to fetch its files again
+			processingRecache := m.newProcessingMedia(attachment, data(job.RemoteURL), nil, true)
+			if m.putRecaching(processingRecache) != processingRecache {
+				// it's being recached already, by a job that's been persisted separately
+				break
+			}
+			processingRecache.jobID = job.ID
+			m.enqueueMedia(processingRecache, jobRecache)
+			return true, nil
+		case job.Attachment != nil:
+			// the attachment never made it to the database, so start again from how it was before processing
+			processingMedia := m.newProcessingMedia(job.Attachment, data(job.RemoteURL), nil, false)
+			processingMedia.jobID = job.ID
+			m.enqueueMedia(processingMedia, jobAttachment)
+			return true, nil
+		}
+	case gtsmodel.MediaJobTypeEmoji:
+		err := m.db.GetByID(ctx, job.EmojiID, &gtsmodel.Emoji{})
+		if err != nil && err != db.ErrNoEntries {
+			return false, fmt.Errorf("error getting emoji %s: %s", job.EmojiID, err)
+		}
+
+		// emoji are only put in the database once processing has finished
+		if err == db.ErrNoEntries && job.Emoji != nil {
+			instanceAccount, err := m.db.GetInstanceAccount(ctx, "")
+			if err != nil {
+				return false, fmt.Errorf("error fetching this instance account from the db: %s", err)
+			}
+
+			processingEmoji := m.newProcessingEmoji(instanceAccount.ID, job.Emoji, data(job.RemoteURL), nil)
+			processingEmoji.jobID = job.ID
+			m.enqueueEmoji(processingEmoji)
+			return true, nil
+		}
+	}
+
+	// nothing left to do for this job
+	m.forgetJob(ctx, job.ID)
+	return false, nil
+}
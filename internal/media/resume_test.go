@@ -0,0 +1,163 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ResumeTestSuite struct {
+	MediaStandardTestSuite
+}
+
+func (suite *ResumeTestSuite) pendingJobs() []*gtsmodel.MediaJob {
+	jobs := []*gtsmodel.MediaJob{}
+	if err := suite.db.GetAll(context.Background(), &jobs); err != nil {
+		return nil
+	}
+	return jobs
+}
+
+func (suite *ResumeTestSuite) TestResumeRemoteMedia() {
+	ctx := context.Background()
+
+	// remote media that won't arrive until we say so
+	release := make(chan struct{})
+	defer close(release)
+	hangingData := func(_ context.Context) (io.Reader, int, error) {
+		<-release
+		return nil, 0, fmt.Errorf("released")
+	}
+
+	accountID := "01FS1X72SK9ZPW0J1QQ68BD264"
+	remoteURL := "http://example.org/media/some_image.jpeg"
+	description := "a picture of a dog"
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, hangingData, nil, accountID, &media.AdditionalMediaInfo{
+		RemoteURL:   &remoteURL,
+		Description: &description,
+	})
+	suite.NoError(err)
+	attachmentID := processingMedia.AttachmentID()
+
+	// the job should have been persisted with everything needed to start it again
+	jobs := suite.pendingJobs()
+	suite.Len(jobs, 1)
+	job := jobs[0]
+	suite.Equal(gtsmodel.MediaJobTypeAttachment, job.Type)
+	suite.Equal(attachmentID, job.AttachmentID)
+	suite.Equal(remoteURL, job.RemoteURL)
+	suite.NotNil(job.Attachment)
+	suite.Equal(attachmentID, job.Attachment.ID)
+	suite.Equal(accountID, job.Attachment.AccountID)
+	suite.Equal(description, job.Attachment.Description)
+
+	// pretend we've restarted, and pick up where the first manager left off
+	restartedManager := testrig.NewTestMediaManager(suite.db, suite.storage)
+	defer restartedManager.Stop()
+
+	var fetched string
+	restartedManager.SetRecacheDataFunc(func(remoteURL string) media.DataFunc {
+		return func(_ context.Context) (io.Reader, int, error) {
+			fetched = remoteURL
+			b, err := os.ReadFile("./test/test-jpeg.jpg")
+			if err != nil {
+				panic(err)
+			}
+			return bytes.NewBuffer(b), len(b), nil
+		}
+	})
+
+	resumed, err := restartedManager.ResumeJobs(ctx)
+	suite.NoError(err)
+	suite.Equal(1, resumed)
+
+	// the attachment should be processed with the same id and info as before, and the job removed
+	var attachment *gtsmodel.MediaAttachment
+	suite.Eventually(func() bool {
+		attachment, err = suite.db.GetAttachmentByID(ctx, attachmentID)
+		return err == nil && len(suite.pendingJobs()) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	suite.Equal(remoteURL, fetched)
+	suite.True(attachment.Cached)
+	suite.Equal(accountID, attachment.AccountID)
+	suite.Equal(remoteURL, attachment.RemoteURL)
+	suite.Equal(description, attachment.Description)
+	suite.Equal("image/jpeg", attachment.File.ContentType)
+}
+
+func (suite *ResumeTestSuite) TestResumeFinishedJob() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	// a recache job that finished just before we were stopped
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.MediaJob{
+		ID:           "01GE0B3PQC0Y3YHEPZ5XG5B2MG",
+		Type:         gtsmodel.MediaJobTypeRecache,
+		AttachmentID: testAttachment.ID,
+		RemoteURL:    testAttachment.RemoteURL,
+	}))
+
+	suite.manager.SetRecacheDataFunc(func(remoteURL string) media.DataFunc {
+		suite.FailNow("cached attachment shouldn't be fetched again")
+		return nil
+	})
+
+	// nothing should be done, but the job should be cleaned up
+	resumed, err := suite.manager.ResumeJobs(ctx)
+	suite.NoError(err)
+	suite.Equal(0, resumed)
+	suite.Empty(suite.pendingJobs())
+}
+
+func (suite *ResumeTestSuite) TestLocalMediaNotPersisted() {
+	ctx := context.Background()
+
+	data := func(_ context.Context) (io.Reader, int, error) {
+		b, err := os.ReadFile("./test/test-jpeg.jpg")
+		if err != nil {
+			panic(err)
+		}
+		return bytes.NewBuffer(b), len(b), nil
+	}
+
+	processingMedia, err := suite.manager.ProcessMedia(ctx, data, nil, "01FS1X72SK9ZPW0J1QQ68BD264", nil)
+	suite.NoError(err)
+
+	// a local upload can't be fetched again, so there's no point keeping a job for it
+	suite.Empty(suite.pendingJobs())
+
+	_, err = processingMedia.LoadAttachment(ctx)
+	suite.NoError(err)
+}
+
+func TestResumeTestSuite(t *testing.T) {
+	suite.Run(t, &ResumeTestSuite{})
+}
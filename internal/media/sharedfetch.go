@@ -0,0 +1,125 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sharedFetch is a fetch of a remote url that's shared between all the new media being processed from that
+// url for one account. The same remote media can be attached to several statuses, and each of those gets its
+// own attachment, but the url only needs to be fetched once for all of them. The fetched bytes are kept in
+// memory until every piece of media sharing them has finished processing.
+type sharedFetch struct {
+	data DataFunc // the data function of the first media to be processed from the url
+	once sync.Once
+	b    []byte
+	size int
+	err  error
+	refs int // how much media is sharing the fetch, guarded by the manager's inFlightMu
+}
+
+// sharedFetchKey returns the key that the fetch of the given remote url for the given account is kept in sharedFetches with.
+func sharedFetchKey(remoteURL string, accountID string) string {
+	return remoteURL + " " + accountID
+}
+
+// fetch is a DataFunc that calls the data function of f the first time it's called, and then returns
+// a new reader of the same bytes every time it's called after that.
+func (f *sharedFetch) fetch(ctx context.Context) (io.Reader, int, error) {
+	f.once.Do(func() {
+		f.b, f.size, f.err = readAllData(ctx, f.data)
+	})
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	return bytes.NewReader(f.b), f.size, nil
+}
+
+// readAllData calls the given data function, and reads everything from the reader that it returns, along with
+// the size of the media that it declared. So that a huge file can't use up all our memory, no more than one
+// byte more than the largest size allowed for any type of media is read, which is enough for processing to tell
+// that the media is too large.
+func readAllData(ctx context.Context, data DataFunc) ([]byte, int, error) {
+	reader, fileSize, err := data(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if rc, ok := reader.(io.ReadCloser); ok {
+			if err := rc.Close(); err != nil {
+				logrus.Errorf("readAllData: error closing readcloser: %s", err)
+			}
+		}
+	}()
+
+	if maxSize := maxMediaSize(); maxSize > 0 {
+		reader = io.LimitReader(reader, int64(maxSize)+1)
+	}
+
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	return b, fileSize, nil
+}
+
+// maxMediaSize returns the largest size allowed for any type of new media, or 0 if there's no limit on the size of some type.
+func maxMediaSize() int {
+	maxSize := 0
+	for _, l := range []limits{imageLimits(), videoLimits(), unknownLimits()} {
+		if l.maxSize == 0 {
+			return 0
+		}
+		if l.maxSize > maxSize {
+			maxSize = l.maxSize
+		}
+	}
+	return maxSize
+}
+
+// shareFetch makes the given media, which is in inFlight, share the fetch of its remote url with any other media
+// being processed from the same url for the same account. It should only be called when m.inFlightMu is held.
+func (m *manager) shareFetch(processingMedia *ProcessingMedia) {
+	key := sharedFetchKey(processingMedia.attachment.RemoteURL, processingMedia.attachment.AccountID)
+	f, ok := m.sharedFetches[key]
+	if !ok {
+		f = &sharedFetch{data: processingMedia.data}
+		m.sharedFetches[key] = f
+	}
+	f.refs++
+	processingMedia.data = f.fetch
+}
+
+// unshareFetch stops the given media sharing the fetch of its remote url, and forgets about the fetch
+// if nothing else is sharing it. It should only be called when m.inFlightMu is held.
+func (m *manager) unshareFetch(processingMedia *ProcessingMedia) {
+	key := sharedFetchKey(processingMedia.attachment.RemoteURL, processingMedia.attachment.AccountID)
+	if f, ok := m.sharedFetches[key]; ok {
+		f.refs--
+		if f.refs <= 0 {
+			delete(m.sharedFetches, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTooLarge is returned when a piece of media turns out
+// to be bigger than the max size allowed for its type.
+var ErrTooLarge = errors.New("media too large")
+
+// sizeLimitedReader wraps a reader, and fails reading as soon as more
+// than limit bytes have been read from it. This lets media processing stop
+// as soon as it knows that media is too large, rather than having to read
+// and store all of it first, which matters when the declared size of the
+// media (eg., a Content-Length header from a remote instance) is wrong.
+type sizeLimitedReader struct {
+	r     io.Reader
+	limit int
+	read  int
+}
+
+// newSizeLimitedReader returns a sizeLimitedReader for the given reader. A limit of 0 means no limit.
+func newSizeLimitedReader(r io.Reader, limit int) *sizeLimitedReader {
+	return &sizeLimitedReader{
+		r:     r,
+		limit: limit,
+	}
+}
+
+func (s *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.read += n
+	if s.exceeded() {
+		return n, s.err()
+	}
+	return n, err
+}
+
+// exceeded returns true if more than the limit has been read from the underlying reader.
+func (s *sizeLimitedReader) exceeded() bool {
+	return s.limit > 0 && s.read > s.limit
+}
+
+// err returns an error wrapping ErrTooLarge that describes the limit.
+func (s *sizeLimitedReader) err() error {
+	return fmt.Errorf("%w: media exceeded max size of %d bytes", ErrTooLarge, s.limit)
+}
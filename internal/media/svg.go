@@ -0,0 +1,288 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// svgRasterSize is the length in pixels of the longest side
+// of the png that's rendered from an svg emoji, for clients
+// that can't display svgs themselves.
+const svgRasterSize = 128
+
+// svgForbiddenElements are removed from svgs along with everything inside them,
+// since they can be used to run scripts or to embed content from elsewhere.
+var svgForbiddenElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"object":        true,
+	"embed":         true,
+	"audio":         true,
+	"video":         true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// looksLikeSVG returns true if the given file header could be the start of an svg.
+//
+// Svgs are xml, so unlike other images they don't start with any magic bytes; whether
+// it really is an svg is only known for sure once the whole thing has been parsed.
+func looksLikeSVG(fileHeader []byte) bool {
+	h := bytes.TrimPrefix(fileHeader, []byte("\xef\xbb\xbf")) // utf-8 byte order mark
+	h = bytes.ToLower(bytes.TrimSpace(h))
+
+	for _, prefix := range []string{"<?xml", "<svg", "<!--", "<!doctype svg"} {
+		if bytes.HasPrefix(h, []byte(prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sanitizeSVG reads an svg from r, and returns a copy of it with anything that could be used to run
+// scripts or load external resources taken out. Comments, processing instructions, and doctypes
+// are also removed, since they're not needed to render the svg.
+//
+// An error will be returned if r doesn't contain a well-formed svg.
+func sanitizeSVG(r io.Reader) ([]byte, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = true
+
+	out := &bytes.Buffer{}
+	open := []xml.Name{} // elements that have been written but not closed yet
+	skipDepth := 0       // greater than 0 while we're inside a forbidden element
+	sawRoot := false
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing svg: %s", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+
+			if len(open) == 0 {
+				if sawRoot {
+					return nil, errors.New("svg has more than one root element")
+				}
+				if strings.ToLower(t.Name.Local) != "svg" {
+					return nil, fmt.Errorf("root element was %s, not svg", t.Name.Local)
+				}
+				sawRoot = true
+			}
+
+			if svgElementForbidden(t) {
+				skipDepth = 1
+				continue
+			}
+
+			writeSVGStartElement(out, t)
+			open = append(open, t.Name)
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+
+			if len(open) == 0 || open[len(open)-1] != t.Name {
+				return nil, fmt.Errorf("unexpected closing element %s", svgRawName(t.Name))
+			}
+			open = open[:len(open)-1]
+
+			out.WriteString("</" + svgRawName(t.Name) + ">")
+		case xml.CharData:
+			if skipDepth > 0 || len(open) == 0 {
+				continue
+			}
+
+			// style elements can pull in other resources just like attributes can
+			if strings.ToLower(open[len(open)-1].Local) == "style" && !svgValueSafe(string(t)) {
+				continue
+			}
+
+			if err := xml.EscapeText(out, t); err != nil {
+				return nil, fmt.Errorf("error writing svg: %s", err)
+			}
+		}
+	}
+
+	if !sawRoot {
+		return nil, errors.New("no svg element found")
+	}
+
+	if len(open) != 0 || skipDepth != 0 {
+		return nil, errors.New("svg ended before all elements were closed")
+	}
+
+	return out.Bytes(), nil
+}
+
+// svgElementForbidden returns true if the given element, and everything inside it, should be removed.
+func svgElementForbidden(e xml.StartElement) bool {
+	local := strings.ToLower(e.Name.Local)
+	if svgForbiddenElements[local] {
+		return true
+	}
+
+	// animations can be used to change links into javascript: links after they've been sanitized
+	if local == "animate" || local == "set" {
+		for _, a := range e.Attr {
+			if strings.ToLower(a.Name.Local) == "attributename" && strings.HasSuffix(strings.ToLower(a.Value), "href") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// writeSVGStartElement writes the given element to out, leaving out any unsafe attributes.
+func writeSVGStartElement(out *bytes.Buffer, e xml.StartElement) {
+	out.WriteString("<" + svgRawName(e.Name))
+
+	for _, a := range e.Attr {
+		local := strings.ToLower(a.Name.Local)
+
+		// event handlers like onload are always scripts
+		if strings.HasPrefix(local, "on") {
+			continue
+		}
+
+		// only allow links to other things within the same svg, or to embedded images
+		if local == "href" && !svgHrefSafe(a.Value) {
+			continue
+		}
+
+		if !svgValueSafe(a.Value) {
+			continue
+		}
+
+		out.WriteString(" " + svgRawName(a.Name) + `="`)
+		_ = xml.EscapeText(out, []byte(a.Value)) // writing to a bytes.Buffer never fails
+		out.WriteString(`"`)
+	}
+
+	out.WriteString(">")
+}
+
+// svgRawName returns the name of an element or attribute as it was written in the svg, including any prefix.
+func svgRawName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// svgHrefSafe returns true if the given link only refers to something that's part of the svg itself.
+func svgHrefSafe(href string) bool {
+	href = strings.ToLower(strings.TrimSpace(href))
+
+	if strings.HasPrefix(href, "#") {
+		return true
+	}
+
+	for _, prefix := range []string{"data:" + mimeImagePng + ";", "data:" + mimeImageJpeg + ";", "data:" + mimeImageGif + ";"} {
+		if strings.HasPrefix(href, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// svgValueSafe returns true if the given attribute value or stylesheet doesn't contain
+// any scripts, and doesn't refer to anything outside of the svg itself.
+func svgValueSafe(value string) bool {
+	// take out whitespace so that things like 'java script:' can't sneak through
+	v := strings.ToLower(strings.Join(strings.Fields(value), ""))
+
+	for _, forbidden := range []string{"javascript:", "vbscript:", "@import", "expression("} {
+		if strings.Contains(v, forbidden) {
+			return false
+		}
+	}
+
+	// references like fill="url(#gradient)" are fine, but anything else could be fetched from elsewhere
+	for rest := v; ; {
+		i := strings.Index(rest, "url(")
+		if i == -1 {
+			break
+		}
+		rest = strings.TrimLeft(rest[i+len("url("):], `"'`)
+		if !strings.HasPrefix(rest, "#") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rasterizeSVG renders the svg in r as an image whose longest side is svgRasterSize pixels long.
+func rasterizeSVG(r io.Reader) (img image.Image, err error) {
+	// the svg parser isn't always graceful with input it doesn't expect
+	defer func() {
+		if rec := recover(); rec != nil {
+			img = nil
+			err = fmt.Errorf("error rendering svg: %v", rec)
+		}
+	}()
+
+	icon, err := oksvg.ReadIconStream(r, oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("error reading svg: %s", err)
+	}
+
+	w, h := icon.ViewBox.W, icon.ViewBox.H
+	if w <= 0 || h <= 0 {
+		return nil, errors.New("svg has no size")
+	}
+
+	// scale the svg so that the longest side fits exactly
+	scale := svgRasterSize / math.Max(w, h)
+	width := int(math.Max(1, math.Round(w*scale)))
+	height := int(math.Max(1, math.Round(h*scale)))
+
+	icon.SetTarget(0, 0, float64(width), float64(height))
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	scanner := rasterx.NewScannerGV(width, height, rgba, rgba.Bounds())
+	icon.Draw(rasterx.NewDasher(width, height, scanner), 1)
+
+	return rgba, nil
+}
@@ -0,0 +1,99 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+// thumbnailVariantRegex matches configured thumbnail variants in the format 'name:[width]x[height]', eg., 'medium:1280x1280'.
+var thumbnailVariantRegex = regexp.MustCompile(`^([a-z]+):([0-9]+)x([0-9]+)$`)
+
+// thumbnailSize describes the maximum dimensions of one thumbnail to derive from a piece of media.
+type thumbnailSize struct {
+	size      Size // size is the key of this thumbnail, used in its path and url
+	maxWidth  int  // maxWidth is maximum width of the thumbnail in pixels
+	maxHeight int  // maxHeight is maximum height of the thumbnail in pixels
+}
+
+// thumbnailSizes returns the thumbnail sizes that should be derived for each
+// piece of media, based on the values currently set in viper.
+//
+// The first entry will always be SizeSmall, which is used as the preview
+// for the media, and which every attachment has. Any further entries are
+// additional variants configured by the instance admin.
+//
+// An error will be returned if any configured variant is not valid.
+func thumbnailSizes() ([]thumbnailSize, error) {
+	keys := config.Keys
+
+	small := thumbnailSize{
+		size:      SizeSmall,
+		maxWidth:  viper.GetInt(keys.MediaThumbnailMaxWidth),
+		maxHeight: viper.GetInt(keys.MediaThumbnailMaxHeight),
+	}
+	if small.maxWidth <= 0 || small.maxHeight <= 0 {
+		return nil, fmt.Errorf("thumbnail max dimensions must be greater than 0, got %dx%d", small.maxWidth, small.maxHeight)
+	}
+	sizes := []thumbnailSize{small}
+
+	for _, variant := range viper.GetStringSlice(keys.MediaThumbnailVariants) {
+		matches := thumbnailVariantRegex.FindStringSubmatch(variant)
+		if matches == nil {
+			return nil, fmt.Errorf("thumbnail variant %s not valid, should be in the format 'name:[width]x[height]'", variant)
+		}
+
+		// the regex guarantees these are digits, so the only possible failure here is on overflow
+		maxWidth, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("thumbnail variant %s width not valid: %s", variant, err)
+		}
+		maxHeight, err := strconv.Atoi(matches[3])
+		if err != nil {
+			return nil, fmt.Errorf("thumbnail variant %s height not valid: %s", variant, err)
+		}
+
+		ts := thumbnailSize{
+			size:      Size(matches[1]),
+			maxWidth:  maxWidth,
+			maxHeight: maxHeight,
+		}
+		if ts.maxWidth == 0 || ts.maxHeight == 0 {
+			return nil, fmt.Errorf("thumbnail variant %s max dimensions must be greater than 0", variant)
+		}
+
+		for _, existing := range sizes {
+			if existing.size == ts.size {
+				return nil, fmt.Errorf("thumbnail variant %s name is already in use", variant)
+			}
+		}
+		if ts.size == SizeOriginal || ts.size == SizeStatic || ts.size == SizeGif {
+			return nil, fmt.Errorf("thumbnail variant %s name is reserved", variant)
+		}
+
+		sizes = append(sizes, ts)
+	}
+
+	return sizes, nil
+}
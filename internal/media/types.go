@@ -43,6 +43,22 @@ const (
 
 	mimePng      = "png"
 	mimeImagePng = mimeImage + "/" + mimePng
+
+	mimeSvg      = "svg"
+	mimeImageSvg = mimeImage + "/" + mimeSvg + "+xml"
+
+	mimeVideo = "video"
+
+	mimeMp4      = "mp4"
+	mimeVideoMp4 = mimeVideo + "/" + mimeMp4
+
+	mimeQuicktime      = "quicktime"
+	mimeVideoQuicktime = mimeVideo + "/" + mimeQuicktime
+
+	mimeApplication = "application"
+
+	mimeBin                    = "bin" // extension used for media whose type we don't know at all
+	mimeApplicationOctetStream = mimeApplication + "/octet-stream"
 )
 
 type processState int32
@@ -62,6 +78,7 @@ const (
 	SizeSmall    Size = "small"    // SizeSmall is the key for small/thumbnail versions of media
 	SizeOriginal Size = "original" // SizeOriginal is the key for original/fullsize versions of media and emoji
 	SizeStatic   Size = "static"   // SizeStatic is the key for static (non-animated) versions of emoji
+	SizeGif      Size = "gif"      // SizeGif is the key for original gifs that have been kept after conversion to gifv
 )
 
 type Type string
@@ -125,3 +142,7 @@ type DataFunc func(ctx context.Context) (reader io.Reader, fileSize int, err err
 //
 // This can be set to nil, and will then not be executed.
 type PostDataCallbackFunc func(ctx context.Context) error
+
+// RecacheDataFunc returns a DataFunc that fetches the remote
+// media at the given URL again from the instance it originated on.
+type RecacheDataFunc func(remoteURL string) DataFunc
@@ -21,6 +21,7 @@ package media
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/h2non/filetype"
 	"github.com/sirupsen/logrus"
@@ -47,6 +48,18 @@ func parseContentType(fileHeader []byte) (string, error) {
 	return kind.MIME.Value, nil
 }
 
+// passthroughContentType returns the content type and file extension to store media of a type
+// that we can't process with, based on its file header. If the type can't be worked out at all,
+// or it doesn't have an extension that can be used in a file name, the media is treated as
+// arbitrary binary data.
+func passthroughContentType(fileHeader []byte) (string, string) {
+	kind, err := filetype.Match(fileHeader)
+	if err != nil || kind == filetype.Unknown || kind.Extension == "" || strings.ContainsAny(kind.Extension, "./") {
+		return mimeApplicationOctetStream, mimeBin
+	}
+	return kind.MIME.Value, kind.Extension
+}
+
 // supportedImage checks mime type of an image against a slice of accepted types,
 // and returns True if the mime type is accepted.
 func supportedImage(mimeType string) bool {
@@ -68,6 +81,7 @@ func supportedEmoji(mimeType string) bool {
 	acceptedEmojiTypes := []string{
 		mimeImageGif,
 		mimeImagePng,
+		mimeImageSvg,
 	}
 	for _, accepted := range acceptedEmojiTypes {
 		if mimeType == accepted {
@@ -92,8 +106,7 @@ func ParseMediaType(s string) (Type, error) {
 	return "", fmt.Errorf("%s not a recognized MediaType", s)
 }
 
-// ParseMediaSize converts s to a recognized MediaSize, or returns an error if unrecognized
-func ParseMediaSize(s string) (Size, error) {
+func (m *manager) ParseMediaSize(s string) (Size, error) {
 	switch s {
 	case string(SizeSmall):
 		return SizeSmall, nil
@@ -101,7 +114,17 @@ func ParseMediaSize(s string) (Size, error) {
 		return SizeOriginal, nil
 	case string(SizeStatic):
 		return SizeStatic, nil
+	case string(SizeGif):
+		return SizeGif, nil
 	}
+
+	// it might be one of the configured thumbnail variants
+	for _, ts := range m.thumbnailSizes {
+		if string(ts.size) == s {
+			return ts.size, nil
+		}
+	}
+
 	return "", fmt.Errorf("%s not a recognized MediaSize", s)
 }
 
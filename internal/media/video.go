@@ -0,0 +1,211 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxMp4MoovSize is the biggest moov box that will be read into memory to get the dimensions and duration of an mp4.
+// The moov box only holds metadata about the tracks, so even for long videos it's a lot smaller than this.
+const maxMp4MoovSize = 16 * 1024 * 1024
+
+// mp4Info is the information about an mp4 video that it's checked against limits with.
+type mp4Info struct {
+	width    int     // width of the biggest video track in the mp4
+	height   int     // height of the biggest video track in the mp4
+	duration float64 // duration of the mp4 in seconds
+}
+
+// readMp4Info reads the boxes of the mp4 (or quicktime) video in r until it finds the moov box, which holds
+// the dimensions and duration of the video. The media data boxes are skipped over without being kept in memory,
+// but the moov box can come after them, in which case all of r will be read.
+func readMp4Info(r io.Reader) (*mp4Info, error) {
+	for {
+		boxType, size, err := readMp4BoxHeader(r)
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("mp4 has no moov box")
+			}
+			return nil, err
+		}
+
+		if boxType != "moov" {
+			if size < 0 {
+				// the box runs to the end of the file, so there's nothing after it
+				return nil, errors.New("mp4 has no moov box")
+			}
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("error skipping mp4 %s box: %s", boxType, err)
+			}
+			continue
+		}
+
+		if size < 0 || size > maxMp4MoovSize {
+			return nil, fmt.Errorf("mp4 moov box of %d bytes is too big", size)
+		}
+		moov := make([]byte, size)
+		if _, err := io.ReadFull(r, moov); err != nil {
+			return nil, fmt.Errorf("error reading mp4 moov box: %s", err)
+		}
+		return parseMp4Moov(moov)
+	}
+}
+
+// readMp4BoxHeader reads the header of the next box in r, and returns its type and the size of its content.
+// A size of -1 means that the box carries on to the end of the file.
+func readMp4BoxHeader(r io.Reader) (string, int64, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", 0, err
+	}
+
+	boxType := string(header[4:8])
+	size := int64(binary.BigEndian.Uint32(header[0:4]))
+	headerSize := int64(8)
+
+	switch size {
+	case 0:
+		return boxType, -1, nil
+	case 1:
+		// the real size comes after the type, as 64 bits
+		if _, err := io.ReadFull(r, header); err != nil {
+			return "", 0, fmt.Errorf("error reading size of mp4 %s box: %s", boxType, err)
+		}
+		size = int64(binary.BigEndian.Uint64(header))
+		headerSize += 8
+	}
+
+	if size < headerSize {
+		return "", 0, fmt.Errorf("mp4 %s box has invalid size %d", boxType, size)
+	}
+	return boxType, size - headerSize, nil
+}
+
+// parseMp4Moov gets the duration of an mp4 from the mvhd box in the given moov box, and its dimensions from the tkhd boxes of its tracks.
+func parseMp4Moov(moov []byte) (*mp4Info, error) {
+	info := &mp4Info{}
+	var foundMvhd bool
+
+	err := walkMp4Boxes(moov, func(boxType string, content []byte) error {
+		switch boxType {
+		case "mvhd":
+			duration, err := parseMp4Mvhd(content)
+			if err != nil {
+				return err
+			}
+			info.duration = duration
+			foundMvhd = true
+		case "trak":
+			return walkMp4Boxes(content, func(boxType string, content []byte) error {
+				if boxType != "tkhd" {
+					return nil
+				}
+				width, height, err := parseMp4Tkhd(content)
+				if err != nil {
+					return err
+				}
+				if width*height > info.width*info.height {
+					info.width, info.height = width, height
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !foundMvhd {
+		return nil, errors.New("mp4 has no mvhd box")
+	}
+	return info, nil
+}
+
+// walkMp4Boxes calls fn with the type and content of each of the boxes in b.
+func walkMp4Boxes(b []byte, fn func(boxType string, content []byte) error) error {
+	r := bytes.NewReader(b)
+	for r.Len() > 0 {
+		boxType, size, err := readMp4BoxHeader(r)
+		if err != nil {
+			return fmt.Errorf("error reading mp4 box: %s", err)
+		}
+		if size < 0 {
+			size = int64(r.Len())
+		}
+		if size > int64(r.Len()) {
+			return fmt.Errorf("mp4 %s box runs past the end of the box it's in", boxType)
+		}
+
+		content := make([]byte, size)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return err
+		}
+		if err := fn(boxType, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseMp4Mvhd returns the duration in seconds from the content of an mvhd box.
+func parseMp4Mvhd(b []byte) (float64, error) {
+	var timescale, duration uint64
+	switch {
+	case len(b) >= 20 && b[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(b[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	case len(b) >= 32 && b[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(b[20:24]))
+		duration = binary.BigEndian.Uint64(b[24:32])
+	default:
+		return 0, errors.New("mp4 mvhd box is invalid")
+	}
+
+	if timescale == 0 {
+		return 0, errors.New("mp4 mvhd box has a timescale of 0")
+	}
+	return float64(duration) / float64(timescale), nil
+}
+
+// parseMp4Tkhd returns the width and height of a track from the content of its tkhd box.
+// Tracks without any pictures, like audio tracks, have a width and height of 0.
+func parseMp4Tkhd(b []byte) (int, int, error) {
+	// version and flags, then times, track id, and duration, which are longer for version 1
+	offset := 4 + 20
+	if len(b) > 0 && b[0] == 1 {
+		offset = 4 + 32
+	}
+	// then reserved space, layer, alternate group, volume, reserved space, and the transformation matrix
+	offset += 8 + 2 + 2 + 2 + 2 + 36
+
+	if len(b) < offset+8 {
+		return 0, 0, errors.New("mp4 tkhd box is invalid")
+	}
+
+	// width and height are 16.16 fixed point numbers
+	width := int(binary.BigEndian.Uint32(b[offset:offset+4]) >> 16)
+	height := int(binary.BigEndian.Uint32(b[offset+4:offset+8]) >> 16)
+	return width, height, nil
+}
@@ -0,0 +1,55 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+func (m *manager) WarmCache(ctx context.Context, count int) (int, error) {
+	m.integrityMu.Lock()
+	data := m.recacheData
+	m.integrityMu.Unlock()
+
+	if data == nil {
+		return 0, errors.New("WarmCache: no function has been set for fetching remote media")
+	}
+
+	attachments, err := m.db.GetPopularUncached(ctx, count)
+	if err != nil && err != db.ErrNoEntries {
+		return 0, fmt.Errorf("WarmCache: error getting popular uncached attachments: %s", err)
+	}
+
+	if len(attachments) == 0 {
+		return 0, nil
+	}
+
+	// queue at the same rate as other batches of recaches, so that warming the cache doesn't flood remote instances
+	logrus.Infof("WarmCache: queueing %d popular attachments for recaching", len(attachments))
+	m.recacheBatch(ctx, attachments, data, viper.GetInt(config.Keys.MediaRecacheBatchRate))
+
+	return len(attachments), nil
+}
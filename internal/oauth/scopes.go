@@ -0,0 +1,198 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oauth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope is an oauth scope that can be requested by an application, and granted to an access token.
+//
+// Scopes are hierarchical: a token with a broad scope like "read" is also granted all the
+// finer-grained scopes underneath it, like "read:statuses" and "read:accounts".
+type Scope string
+
+// Scopes that can be requested and granted.
+const (
+	ScopeRead              Scope = "read"
+	ScopeReadAccounts      Scope = "read:accounts"
+	ScopeReadBlocks        Scope = "read:blocks"
+	ScopeReadBookmarks     Scope = "read:bookmarks"
+	ScopeReadFavourites    Scope = "read:favourites"
+	ScopeReadFilters       Scope = "read:filters"
+	ScopeReadFollows       Scope = "read:follows"
+	ScopeReadLists         Scope = "read:lists"
+	ScopeReadMutes         Scope = "read:mutes"
+	ScopeReadNotifications Scope = "read:notifications"
+	ScopeReadSearch        Scope = "read:search"
+	ScopeReadStatuses      Scope = "read:statuses"
+
+	ScopeWrite              Scope = "write"
+	ScopeWriteAccounts      Scope = "write:accounts"
+	ScopeWriteBlocks        Scope = "write:blocks"
+	ScopeWriteBookmarks     Scope = "write:bookmarks"
+	ScopeWriteConversations Scope = "write:conversations"
+	ScopeWriteFavourites    Scope = "write:favourites"
+	ScopeWriteFilters       Scope = "write:filters"
+	ScopeWriteFollows       Scope = "write:follows"
+	ScopeWriteLists         Scope = "write:lists"
+	ScopeWriteMedia         Scope = "write:media"
+	ScopeWriteMutes         Scope = "write:mutes"
+	ScopeWriteNotifications Scope = "write:notifications"
+	ScopeWriteReports       Scope = "write:reports"
+	ScopeWriteStatuses      Scope = "write:statuses"
+
+	// ScopeFollow is a legacy scope that grants reading and writing follows, blocks, and mutes.
+	ScopeFollow Scope = "follow"
+	ScopePush   Scope = "push"
+
+	// ScopeAdmin grants all admin:read and admin:write scopes.
+	ScopeAdmin                       Scope = "admin"
+	ScopeAdminRead                   Scope = "admin:read"
+	ScopeAdminReadAccounts           Scope = "admin:read:accounts"
+	ScopeAdminReadReports            Scope = "admin:read:reports"
+	ScopeAdminReadDomainBlocks       Scope = "admin:read:domain_blocks"
+	ScopeAdminReadEmailDomainBlocks  Scope = "admin:read:email_domain_blocks"
+	ScopeAdminReadIPBlocks           Scope = "admin:read:ip_blocks"
+	ScopeAdminWrite                  Scope = "admin:write"
+	ScopeAdminWriteAccounts          Scope = "admin:write:accounts"
+	ScopeAdminWriteReports           Scope = "admin:write:reports"
+	ScopeAdminWriteDomainBlocks      Scope = "admin:write:domain_blocks"
+	ScopeAdminWriteEmailDomainBlocks Scope = "admin:write:email_domain_blocks"
+	ScopeAdminWriteIPBlocks          Scope = "admin:write:ip_blocks"
+)
+
+// DefaultScope is used when an application or token doesn't specify any scope.
+const DefaultScope = ScopeRead
+
+// scopeDescriptions contains a human-readable description of every valid scope,
+// for showing to a user who is asked to authorize an application.
+var scopeDescriptions = map[Scope]string{
+	ScopeRead:              "read all your account data",
+	ScopeReadAccounts:      "see account information",
+	ScopeReadBlocks:        "see your blocks",
+	ScopeReadBookmarks:     "see your bookmarks",
+	ScopeReadFavourites:    "see your favourites",
+	ScopeReadFilters:       "see your filters",
+	ScopeReadFollows:       "see your follows",
+	ScopeReadLists:         "see your lists",
+	ScopeReadMutes:         "see your mutes",
+	ScopeReadNotifications: "see your notifications",
+	ScopeReadSearch:        "search on your behalf",
+	ScopeReadStatuses:      "see statuses and timelines",
+
+	ScopeWrite:              "modify all your account data",
+	ScopeWriteAccounts:      "modify your profile",
+	ScopeWriteBlocks:        "block and unblock accounts and domains",
+	ScopeWriteBookmarks:     "bookmark statuses",
+	ScopeWriteConversations: "mute and delete conversations",
+	ScopeWriteFavourites:    "favourite statuses",
+	ScopeWriteFilters:       "create and modify filters",
+	ScopeWriteFollows:       "follow and unfollow accounts and hashtags",
+	ScopeWriteLists:         "create and modify lists",
+	ScopeWriteMedia:         "upload media files",
+	ScopeWriteMutes:         "mute and unmute accounts and conversations",
+	ScopeWriteNotifications: "clear your notifications",
+	ScopeWriteReports:       "report other accounts",
+	ScopeWriteStatuses:      "publish and delete statuses",
+
+	ScopeFollow: "see and modify your follows, blocks, and mutes",
+	ScopePush:   "receive push notifications",
+
+	ScopeAdmin:                       "perform all moderation and administration actions",
+	ScopeAdminRead:                   "read all data on the instance",
+	ScopeAdminReadAccounts:           "read sensitive information of all accounts",
+	ScopeAdminReadReports:            "read sensitive information of all reports",
+	ScopeAdminReadDomainBlocks:       "read domain blocks",
+	ScopeAdminReadEmailDomainBlocks:  "read email domain blocks",
+	ScopeAdminReadIPBlocks:           "read IP blocks",
+	ScopeAdminWrite:                  "perform moderation actions on the instance",
+	ScopeAdminWriteAccounts:          "perform moderation actions on accounts",
+	ScopeAdminWriteReports:           "perform moderation actions on reports",
+	ScopeAdminWriteDomainBlocks:      "create, update, and delete domain blocks",
+	ScopeAdminWriteEmailDomainBlocks: "create and delete email domain blocks",
+	ScopeAdminWriteIPBlocks:          "create, update, and delete IP blocks",
+}
+
+// followScopes are the scopes granted by the legacy follow scope.
+var followScopes = []Scope{
+	ScopeReadFollows,
+	ScopeWriteFollows,
+	ScopeReadBlocks,
+	ScopeWriteBlocks,
+	ScopeReadMutes,
+	ScopeWriteMutes,
+}
+
+// ParseScopes parses a scope string, which contains scopes separated by spaces
+// (or by pluses, if the scope came from query parameters), into a slice of Scopes.
+//
+// An error will be returned if any of the scopes isn't a valid scope.
+func ParseScopes(scope string) ([]Scope, error) {
+	fields := splitScopes(scope)
+	scopes := make([]Scope, 0, len(fields))
+	for _, f := range fields {
+		s := Scope(f)
+		if _, ok := scopeDescriptions[s]; !ok {
+			return nil, fmt.Errorf("scope %s is not a valid scope", f)
+		}
+		scopes = append(scopes, s)
+	}
+
+	return scopes, nil
+}
+
+// Description returns a human-readable description of what the scope permits.
+func (s Scope) Description() string {
+	return scopeDescriptions[s]
+}
+
+// PermittedBy returns true if the given scope string, which may contain several scopes separated
+// by spaces, grants this scope, either directly or through one of the broader scopes above it.
+//
+// An empty scope string is treated as DefaultScope.
+func (s Scope) PermittedBy(scope string) bool {
+	if scope == "" {
+		scope = string(DefaultScope)
+	}
+
+	for _, granted := range splitScopes(scope) {
+		if s == Scope(granted) || strings.HasPrefix(string(s), granted+":") {
+			return true
+		}
+
+		if Scope(granted) == ScopeFollow {
+			for _, f := range followScopes {
+				if s == f {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// splitScopes splits a scope string on spaces or pluses.
+func splitScopes(scope string) []string {
+	return strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ' ' || r == '+'
+	})
+}
@@ -0,0 +1,70 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package oauth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ScopesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ScopesTestSuite) TestParseScopes() {
+	scopes, err := oauth.ParseScopes("read:statuses+write:media push")
+	suite.NoError(err)
+	suite.Equal([]oauth.Scope{oauth.ScopeReadStatuses, oauth.ScopeWriteMedia, oauth.ScopePush}, scopes)
+
+	_, err = oauth.ParseScopes("read write:everything")
+	suite.EqualError(err, "scope write:everything is not a valid scope")
+}
+
+func (suite *ScopesTestSuite) TestPermittedBy() {
+	for _, test := range []struct {
+		scope     oauth.Scope
+		granted   string
+		permitted bool
+	}{
+		{oauth.ScopeReadStatuses, "read:statuses", true},
+		{oauth.ScopeReadStatuses, "read", true},
+		{oauth.ScopeReadStatuses, "", true},
+		{oauth.ScopeReadStatuses, "write", false},
+		{oauth.ScopeReadStatuses, "read:accounts write:statuses", false},
+		{oauth.ScopeWriteStatuses, "read", false},
+		{oauth.ScopeWriteMedia, "read write", true},
+		{oauth.ScopeWriteFollows, "follow", true},
+		{oauth.ScopeReadMutes, "follow", true},
+		{oauth.ScopeWriteStatuses, "follow", false},
+		{oauth.ScopeAdminReadReports, "admin:read", true},
+		{oauth.ScopeAdminWriteReports, "admin:read", false},
+		{oauth.ScopeAdminWriteIPBlocks, "admin", true},
+		{oauth.ScopeAdminRead, "read write", false},
+		{oauth.ScopeRead, "read:statuses", false},
+		{oauth.ScopePush, "read write follow push", true},
+	} {
+		suite.Equal(test.permitted, test.scope.PermittedBy(test.granted), "%s permitted by %q", test.scope, test.granted)
+	}
+}
+
+func TestScopesTestSuite(t *testing.T) {
+	suite.Run(t, &ScopesTestSuite{})
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/oauth2/v4"
 	"github.com/superseriousbusiness/oauth2/v4/errors"
 	"github.com/superseriousbusiness/oauth2/v4/manage"
@@ -89,7 +90,7 @@ func New(ctx context.Context, database db.Basic) Server {
 			oauth2.AuthorizationCode,
 			oauth2.ClientCredentials,
 		},
-		AllowedCodeChallengeMethods: []oauth2.CodeChallengeMethod{oauth2.CodeChallengePlain},
+		AllowedCodeChallengeMethods: []oauth2.CodeChallengeMethod{oauth2.CodeChallengeS256, oauth2.CodeChallengePlain},
 	}
 
 	srv := server.NewServer(sc, manager)
@@ -109,12 +110,86 @@ func New(ctx context.Context, database db.Basic) Server {
 		}
 		return userID, nil
 	})
-	srv.SetClientInfoHandler(server.ClientFormHandler)
+	srv.SetClientInfoHandler(pkceClientInfoHandler(ts, cs))
+	srv.SetClientScopeHandler(appScopeHandler(database))
 	return &s{
 		server: srv,
 	}
 }
 
+// pkceClientInfoHandler returns a client info handler which reads client credentials from the
+// request form, just like server.ClientFormHandler. Public clients (native apps, single page apps)
+// can't keep a client secret, so if the secret is omitted when exchanging an authorization code
+// that was issued with an S256 PKCE code challenge, the secret of the client is filled in from storage.
+// The code verifier given with the request is still checked against the code challenge by the
+// underlying oauth2 server, so possession of the verifier stands in for the secret. Plain challenges
+// are sent in the clear with the authorization request, so they can't stand in for anything.
+func pkceClientInfoHandler(ts oauth2.TokenStore, cs oauth2.ClientStore) server.ClientInfoHandler {
+	return func(r *http.Request) (string, string, error) {
+		clientID, clientSecret, err := server.ClientFormHandler(r)
+		if err != nil {
+			return "", "", err
+		}
+
+		if clientSecret != "" ||
+			r.Form.Get("grant_type") != oauth2.AuthorizationCode.String() ||
+			r.Form.Get("code_verifier") == "" {
+			return clientID, clientSecret, nil
+		}
+
+		ti, err := ts.GetByCode(r.Context(), r.Form.Get("code"))
+		if err != nil || ti == nil || ti.GetCodeChallenge() == "" || ti.GetCodeChallengeMethod() != oauth2.CodeChallengeS256 || ti.GetClientID() != clientID {
+			// let the oauth2 server reject this request as it normally would
+			return clientID, clientSecret, nil
+		}
+
+		cli, err := cs.GetByID(r.Context(), clientID)
+		if err != nil {
+			return "", "", errors.ErrInvalidClient
+		}
+
+		return clientID, cli.GetSecret(), nil
+	}
+}
+
+// appScopeHandler returns a client scope handler which only allows a client to be granted
+// scopes that its application registered with. The authorize handler already checks this
+// before asking a user to authorize an application, but tokens obtained with client
+// credentials never go past a user, so they'd otherwise be granted whatever was asked for.
+//
+// An empty requested scope is checked as DefaultScope, since that's what it's treated as.
+func appScopeHandler(database db.Basic) server.ClientScopeHandler {
+	return func(tgr *oauth2.TokenGenerateRequest) (bool, error) {
+		requested, err := ParseScopes(tgr.Scope)
+		if err != nil {
+			return false, nil
+		}
+		if len(requested) == 0 {
+			requested = []Scope{DefaultScope}
+		}
+
+		ctx := context.Background()
+		if tgr.Request != nil {
+			ctx = tgr.Request.Context()
+		}
+
+		app := &gtsmodel.Application{}
+		if err := database.GetWhere(ctx, []db.Where{{Key: "client_id", Value: tgr.ClientID}}, app); err != nil {
+			if err == db.ErrNoEntries {
+				return false, nil
+			}
+			return false, fmt.Errorf("error getting application for client %s: %s", tgr.ClientID, err)
+		}
+
+		for _, scope := range requested {
+			if !scope.PermittedBy(app.Scopes) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
 // HandleTokenRequest wraps the oauth2 library's HandleTokenRequest function
 func (s *s) HandleTokenRequest(w http.ResponseWriter, r *http.Request) error {
 	return s.server.HandleTokenRequest(w, r)
@@ -0,0 +1,41 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// accessCountFlusherSchedule is how often counted accesses to remote media are stored.
+const accessCountFlusherSchedule = "@every 1m"
+
+// startAccessCountFlusher starts a cron job that stores the accesses to remote media counted by the media processor.
+func (p *processor) startAccessCountFlusher() error {
+	if err := p.startScheduledJob(accessCountFlusherSchedule, func(ctx context.Context) {
+		if err := p.mediaProcessor.FlushAccessCounts(ctx); err != nil && ctx.Err() == nil {
+			logrus.Errorf("access count flusher: %s", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("error starting access count flusher job: %s", err)
+	}
+	return nil
+}
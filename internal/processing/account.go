@@ -20,6 +20,7 @@ package processing
 
 import (
 	"context"
+	"fmt"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
@@ -34,6 +35,18 @@ func (p *processor) AccountDeleteLocal(ctx context.Context, authed *oauth.Auth,
 	return p.accountProcessor.DeleteLocal(ctx, authed.Account, form)
 }
 
+func (p *processor) AccountMove(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountMoveRequest) gtserror.WithCode {
+	return p.accountProcessor.Move(ctx, authed.Account, form)
+}
+
+func (p *processor) AccountAliasesGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AccountAliases, gtserror.WithCode) {
+	return p.accountProcessor.AliasesGet(ctx, authed.Account)
+}
+
+func (p *processor) AccountAliasesSet(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountAliasesRequest) (*apimodel.AccountAliases, gtserror.WithCode) {
+	return p.accountProcessor.AliasesSet(ctx, authed.Account, form)
+}
+
 func (p *processor) AccountGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Account, gtserror.WithCode) {
 	return p.accountProcessor.Get(ctx, authed.Account, targetAccountID)
 }
@@ -46,7 +59,23 @@ func (p *processor) AccountUpdate(ctx context.Context, authed *oauth.Auth, form
 	return p.accountProcessor.Update(ctx, authed.Account, form)
 }
 
-func (p *processor) AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinnedOnly bool, mediaOnly bool, publicOnly bool) ([]apimodel.Status, gtserror.WithCode) {
+func (p *processor) AccountAvatarDelete(ctx context.Context, authed *oauth.Auth) (*apimodel.Account, gtserror.WithCode) {
+	account, err := p.accountProcessor.Update(ctx, authed.Account, &apimodel.UpdateCredentialsRequest{DeleteAvatar: true})
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting avatar: %s", err))
+	}
+	return account, nil
+}
+
+func (p *processor) AccountHeaderDelete(ctx context.Context, authed *oauth.Auth) (*apimodel.Account, gtserror.WithCode) {
+	account, err := p.accountProcessor.Update(ctx, authed.Account, &apimodel.UpdateCredentialsRequest{DeleteHeader: true})
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting header: %s", err))
+	}
+	return account, nil
+}
+
+func (p *processor) AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinnedOnly bool, mediaOnly bool, publicOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
 	return p.accountProcessor.StatusesGet(ctx, authed.Account, targetAccountID, limit, excludeReplies, excludeReblogs, maxID, minID, pinnedOnly, mediaOnly, publicOnly)
 }
 
@@ -77,3 +106,19 @@ func (p *processor) AccountBlockCreate(ctx context.Context, authed *oauth.Auth,
 func (p *processor) AccountBlockRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
 	return p.accountProcessor.BlockRemove(ctx, authed.Account, targetAccountID)
 }
+
+func (p *processor) AccountMuteCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode) {
+	return p.accountProcessor.MuteCreate(ctx, authed.Account, targetAccountID, form)
+}
+
+func (p *processor) AccountMuteRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	return p.accountProcessor.MuteRemove(ctx, authed.Account, targetAccountID)
+}
+
+func (p *processor) AccountEndorseCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	return p.accountProcessor.EndorseCreate(ctx, authed.Account, targetAccountID)
+}
+
+func (p *processor) AccountEndorseRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	return p.accountProcessor.EndorseRemove(ctx, authed.Account, targetAccountID)
+}
@@ -47,15 +47,27 @@ type Processor interface {
 	// DeleteLocal is like delete, but specifically for deletion of local accounts rather than federated ones.
 	// Unlike Delete, it will propagate the deletion out across the federating API to other instances.
 	DeleteLocal(ctx context.Context, account *gtsmodel.Account, form *apimodel.AccountDeleteRequest) gtserror.WithCode
+	// Move moves a local account to the account given in the form, which must list the local account as an alias.
+	// The local account is locked, and the move is propagated out across the federating API to the account's followers.
+	Move(ctx context.Context, account *gtsmodel.Account, form *apimodel.AccountMoveRequest) gtserror.WithCode
+	// AliasesGet returns the aliases of the given account.
+	AliasesGet(ctx context.Context, account *gtsmodel.Account) (*apimodel.AccountAliases, gtserror.WithCode)
+	// AliasesSet replaces the aliases of the given account with the ones in the form, after checking that they're all real accounts.
+	// The new aliases are propagated out across the federating API, so that other instances will accept moves to this account.
+	AliasesSet(ctx context.Context, account *gtsmodel.Account, form *apimodel.AccountAliasesRequest) (*apimodel.AccountAliases, gtserror.WithCode)
 	// Get processes the given request for account information.
 	Get(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Account, gtserror.WithCode)
 	// GetLocalByUsername processes the given request for account information targeting a local account by username.
 	GetLocalByUsername(ctx context.Context, requestingAccount *gtsmodel.Account, username string) (*apimodel.Account, gtserror.WithCode)
 	// Update processes the update of an account with the given form
 	Update(ctx context.Context, account *gtsmodel.Account, form *apimodel.UpdateCredentialsRequest) (*apimodel.Account, error)
+	// VerifyFields checks the web pages linked in the profile fields of the given local account for rel="me" links
+	// back to the account, and marks the fields that have one as verified. Pages are fetched, so this should be
+	// called asynchronously, after an update of the account has been processed.
+	VerifyFields(ctx context.Context, account *gtsmodel.Account) error
 	// StatusesGet fetches a number of statuses (in time descending order) from the given account, filtered by visibility for
 	// the account given in authed.
-	StatusesGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinned bool, mediaOnly bool, publicOnly bool) ([]apimodel.Status, gtserror.WithCode)
+	StatusesGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinned bool, mediaOnly bool, publicOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 	// FollowersGet fetches a list of the target account's followers.
 	FollowersGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
 	// FollowingGet fetches a list of the accounts that target account is following.
@@ -70,6 +82,14 @@ type Processor interface {
 	BlockCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 	// BlockRemove handles the removal of a block from requestingAccount to targetAccountID, either remote or local.
 	BlockRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// MuteCreate handles requestingAccount muting targetAccountID, or updating the settings of an existing mute.
+	MuteCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode)
+	// MuteRemove handles requestingAccount unmuting targetAccountID.
+	MuteRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// EndorseCreate handles requestingAccount featuring targetAccountID on its profile, which requires following it.
+	EndorseCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// EndorseRemove handles requestingAccount no longer featuring targetAccountID on its profile.
+	EndorseRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 
 	// UpdateHeader does the dirty work of checking the header part of an account update form,
 	// parsing and checking the image, and doing the necessary updates in the database for this to become
@@ -20,8 +20,11 @@ package account_test
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -33,6 +36,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/account"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
@@ -44,7 +48,7 @@ type AccountStandardTestSuite struct {
 	suite.Suite
 	db                  db.DB
 	tc                  typeutils.TypeConverter
-	storage             *kv.KVStore
+	storage             *gtsstorage.Driver
 	mediaManager        media.Manager
 	oauthServer         oauth.Server
 	fromClientAPIChan   chan messages.FromClientAPI
@@ -97,7 +101,31 @@ func (suite *AccountStandardTestSuite) SetupTest() {
 	suite.mediaManager = testrig.NewTestMediaManager(suite.db, suite.storage)
 	suite.oauthServer = testrig.NewTestOauthServer(suite.db)
 	suite.fromClientAPIChan = make(chan messages.FromClientAPI, 100)
-	suite.httpClient = testrig.NewMockHTTPClient(nil)
+	suite.httpClient = testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		// a web page of local_account_1, linking back to their profile
+		zorkPage := `<html><head><link rel="me" href="http://localhost:8080/@the_mighty_zork"></head><body><p>hi i'm zork</p></body></html>`
+
+		statusCode := http.StatusOK
+		body := ""
+		switch req.URL.String() {
+		case "https://zork.example.org/about":
+			body = zorkPage
+		case "https://unreachable.example.org/about":
+			return nil, errors.New("connection refused")
+		case "https://gone.example.org/about":
+			// a page that's gone, even though what's served in its place links back
+			statusCode = http.StatusNotFound
+			body = zorkPage
+		case "https://slow.example.org/about":
+			// a page that never finishes loading
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
 	suite.transportController = testrig.NewTestTransportController(suite.httpClient, suite.db, fedWorker)
 	suite.federator = testrig.NewTestFederator(suite.db, suite.transportController, suite.storage, suite.mediaManager, fedWorker)
 	suite.sentEmails = make(map[string]string)
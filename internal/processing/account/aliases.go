@@ -0,0 +1,117 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// maxAliases is the most aliases that an account can have set at once.
+const maxAliases = 5
+
+func (p *processor) AliasesGet(ctx context.Context, account *gtsmodel.Account) (*apimodel.AccountAliases, gtserror.WithCode) {
+	aliases := []string{}
+	aliases = append(aliases, account.AlsoKnownAsURIs...)
+
+	return &apimodel.AccountAliases{AlsoKnownAsURIs: aliases}, nil
+}
+
+func (p *processor) AliasesSet(ctx context.Context, account *gtsmodel.Account, form *apimodel.AccountAliasesRequest) (*apimodel.AccountAliases, gtserror.WithCode) {
+	// check how many aliases were given before resolving any of them, so a huge list can't make us dereference loads of accounts
+	unique := make([]string, 0, len(form.AlsoKnownAsURIs))
+	seen := make(map[string]bool, len(form.AlsoKnownAsURIs))
+	for _, alias := range form.AlsoKnownAsURIs {
+		if !seen[alias] {
+			seen[alias] = true
+			unique = append(unique, alias)
+		}
+	}
+
+	if len(unique) > maxAliases {
+		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("%d aliases given", len(unique)), fmt.Sprintf("an account can't have more than %d aliases", maxAliases))
+	}
+
+	aliases := make([]string, 0, len(unique))
+	for _, alias := range unique {
+		aliasURI, err := url.Parse(alias)
+		if err != nil || (aliasURI.Scheme != "http" && aliasURI.Scheme != "https") || aliasURI.Host == "" {
+			return nil, gtserror.NewErrorBadRequest(fmt.Errorf("couldn't parse alias %s", alias), fmt.Sprintf("alias %s was not a valid uri", alias))
+		}
+
+		if alias == account.URI {
+			return nil, gtserror.NewErrorBadRequest(errors.New("account can't be an alias of itself"), "account can't be an alias of itself")
+		}
+
+		// make sure the alias is an account that actually exists
+		aliasAccount, err := p.getAccountByURI(ctx, account.Username, aliasURI, false)
+		if err != nil {
+			return nil, gtserror.NewErrorBadRequest(fmt.Errorf("error getting alias account %s: %s", alias, err), fmt.Sprintf("alias %s could not be found", alias))
+		}
+
+		// store the URI the account gives itself, rather than whatever it was looked up with
+		aliases = append(aliases, aliasAccount.URI)
+	}
+
+	account.AlsoKnownAsURIs = aliases
+	updatedAccount, err := p.db.UpdateAccount(ctx, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating account aliases: %s", err))
+	}
+
+	// other instances need to see the new aliases before they'll accept a move to this account
+	p.clientWorker.Queue(messages.FromClientAPI{
+		APObjectType:   ap.ObjectProfile,
+		APActivityType: ap.ActivityUpdate,
+		GTSModel:       updatedAccount,
+		OriginAccount:  updatedAccount,
+	})
+
+	return p.AliasesGet(ctx, updatedAccount)
+}
+
+// getAccountByURI gets the account with the given URI from the database if it's local, or
+// dereferences it if it's remote. If refresh is true, then a remote account will always be
+// dereferenced fresh, even if it's already in the database.
+func (p *processor) getAccountByURI(ctx context.Context, requestingUsername string, uri *url.URL, refresh bool) (*gtsmodel.Account, error) {
+	if uri.Host == viper.GetString(config.Keys.Host) {
+		return p.db.GetAccountByURI(ctx, uri.String())
+	}
+	return p.federator.GetRemoteAccount(ctx, requestingUsername, uri, true, refresh)
+}
+
+// isAliasOf returns true if account lists the account with the given URI as one of its aliases.
+func isAliasOf(account *gtsmodel.Account, uri string) bool {
+	for _, alias := range account.AlsoKnownAsURIs {
+		if alias == uri {
+			return true
+		}
+	}
+	return false
+}
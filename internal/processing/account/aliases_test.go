@@ -0,0 +1,80 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type AccountAliasesTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *AccountAliasesTestSuite) TestAliasesSet() {
+	testAccount := suite.testAccounts["local_account_1"]
+	aliasAccount := suite.testAccounts["local_account_2"]
+
+	form := &apimodel.AccountAliasesRequest{
+		AlsoKnownAsURIs: []string{aliasAccount.URI, aliasAccount.URI},
+	}
+
+	aliases, errWithCode := suite.accountProcessor.AliasesSet(context.Background(), testAccount, form)
+	suite.NoError(errWithCode)
+	suite.Equal([]string{aliasAccount.URI}, aliases.AlsoKnownAsURIs)
+
+	// other instances should hear about the new aliases
+	msg := <-suite.fromClientAPIChan
+	suite.Equal(ap.ActivityUpdate, msg.APActivityType)
+	suite.Equal(ap.ObjectProfile, msg.APObjectType)
+
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Equal([]string{aliasAccount.URI}, dbAccount.AlsoKnownAsURIs)
+}
+
+func (suite *AccountAliasesTestSuite) TestAliasesSetTooMany() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	// none of these exist, but we shouldn't get as far as trying to find them
+	form := &apimodel.AccountAliasesRequest{
+		AlsoKnownAsURIs: []string{
+			"https://example.org/users/one",
+			"https://example.org/users/two",
+			"https://example.org/users/three",
+			"https://example.org/users/four",
+			"https://example.org/users/five",
+			"https://example.org/users/six",
+		},
+	}
+
+	aliases, errWithCode := suite.accountProcessor.AliasesSet(context.Background(), testAccount, form)
+	suite.Nil(aliases)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	suite.Equal("bad request: an account can't have more than 5 aliases", errWithCode.Safe())
+}
+
+func TestAccountAliasesTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountAliasesTestSuite))
+}
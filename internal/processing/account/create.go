@@ -28,6 +28,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
@@ -39,6 +40,11 @@ func (p *processor) Create(ctx context.Context, applicationToken oauth2.TokenInf
 
 	emailAvailable, err := p.db.IsEmailAvailable(ctx, form.Email)
 	if err != nil {
+		if _, blocked := err.(*db.ErrEmailDomainBlocked); blocked {
+			if err := p.db.CountEmailDomainBlockAttempt(ctx, form.Email); err != nil {
+				l.Errorf("error counting email domain block attempt: %s", err)
+			}
+		}
 		return nil, err
 	}
 	if !emailAvailable {
@@ -53,9 +59,17 @@ func (p *processor) Create(ctx context.Context, applicationToken oauth2.TokenInf
 		return nil, fmt.Errorf("username %s in use", form.Username)
 	}
 
+	ipBlockSeverity, err := p.db.GetIPBlockSeverity(ctx, form.IP)
+	if err != nil {
+		return nil, err
+	}
+	if ipBlockSeverity == gtsmodel.IPBlockSeveritySignUpBlock || ipBlockSeverity == gtsmodel.IPBlockSeverityNoAccess {
+		return nil, fmt.Errorf("sign-ups from ip address %s are blocked", form.IP)
+	}
+
 	keys := config.Keys
 	reasonRequired := viper.GetBool(keys.AccountsReasonRequired)
-	approvalRequired := viper.GetBool(keys.AccountsApprovalRequired)
+	approvalRequired := viper.GetBool(keys.AccountsApprovalRequired) || ipBlockSeverity == gtsmodel.IPBlockSeveritySignUpRequiresApproval
 
 	// don't store a reason if we don't require one
 	reason := form.Reason
@@ -0,0 +1,61 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type AccountCreateTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *AccountCreateTestSuite) TestAccountCreateBlockedEmailDomain() {
+	ctx := context.Background()
+
+	block := &gtsmodel.EmailDomainBlock{
+		ID:                 "01G3BQ6E6PN3B6DHV4EBHE7Z3Q",
+		Domain:             "mailinator.com",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+	}
+	suite.NoError(suite.db.Put(ctx, block))
+
+	_, err := suite.accountProcessor.Create(ctx, nil, suite.testApplications["application_1"], &apimodel.AccountCreateRequest{
+		Username:  "spammer",
+		Email:     "spammer@eu.mailinator.com",
+		Password:  "some_really_really_really_strong_password",
+		Agreement: true,
+		Locale:    "en",
+	})
+	suite.EqualError(err, "email domain eu.mailinator.com is blocked")
+
+	// the attempt is counted on the block
+	suite.NoError(suite.db.GetByID(ctx, block.ID, block))
+	suite.Equal(1, block.Attempts)
+	suite.False(block.LastAttemptAt.IsZero())
+}
+
+func TestAccountCreateTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountCreateTestSuite))
+}
@@ -78,6 +78,14 @@ func (p *processor) BlockCreate(ctx context.Context, requestingAccount *gtsmodel
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: error removing follow in db: %s", err))
 	}
 
+	// neither account can keep featuring the other
+	if err := p.removeEndorsement(ctx, requestingAccount.ID, targetAccountID); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: %s", err))
+	}
+	if err := p.removeEndorsement(ctx, targetAccountID, requestingAccount.ID); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("BlockCreate: %s", err))
+	}
+
 	// clear any follows or follow requests from the requesting account to the target account --
 	// this might require federation so we need to pass some messages around
 
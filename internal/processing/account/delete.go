@@ -135,10 +135,25 @@ func (p *processor) Delete(ctx context.Context, account *gtsmodel.Account, origi
 		l.Errorf("error deleting follows targeting account: %s", err)
 	}
 
+	// endorsements depend on follows, so they go too
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, &[]*gtsmodel.Endorsement{}); err != nil {
+		l.Errorf("error deleting endorsements created by account: %s", err)
+	}
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "target_account_id", Value: account.ID}}, &[]*gtsmodel.Endorsement{}); err != nil {
+		l.Errorf("error deleting endorsements targeting account: %s", err)
+	}
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, &[]*gtsmodel.FeaturedTag{}); err != nil {
+		l.Errorf("error deleting featured tags of account: %s", err)
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, &[]*gtsmodel.FollowedTag{}); err != nil {
+		l.Errorf("error deleting followed tags of account: %s", err)
+	}
+
 	// 6. Delete account's statuses
 	l.Debug("deleting account statuses")
 	// we'll select statuses 20 at a time so we don't wreck the db, and pass them through to the client api channel
-	// Deleting the statuses in this way also handles 7. Delete account's media attachments, 8. Delete account's mentions, and 9. Delete account's polls,
+	// Deleting the statuses in this way also handles the media attachments of statuses, 8. Delete account's mentions, and 9. Delete account's polls,
 	// since these are all attached to statuses.
 	var maxID string
 selectStatusesLoop:
@@ -220,6 +235,13 @@ selectStatusesLoop:
 	}
 	l.Debug("done deleting statuses")
 
+	// 7. Delete account's media attachments
+	// media attached to statuses goes along with the statuses, which leaves the avatar, header, and any unused uploads
+	l.Debug("deleting account media")
+	if _, err := p.mediaManager.DeleteAccountMedia(ctx, account.ID); err != nil {
+		l.Errorf("error deleting account media: %s", err)
+	}
+
 	// 10. Delete account's notifications
 	l.Debug("deleting account notifications")
 	// first notifications created by account
@@ -245,11 +267,35 @@ selectStatusesLoop:
 		l.Errorf("error deleting faves created by account: %s", err)
 	}
 
+	// reactions aren't undone one by one: the Delete of the account that's federated once it's gone
+	// tells remote instances to drop everything that the account made, reactions included
+	l.Debug("deleting account reactions")
+	if err := p.db.DeleteStatusReactions(ctx, []db.Where{{Key: "account_id", Value: account.ID}}); err != nil {
+		l.Errorf("error deleting reactions created by account: %s", err)
+	}
+
 	// 13. Delete account's mutes
 	l.Debug("deleting account mutes")
 	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, &[]*gtsmodel.StatusMute{}); err != nil {
 		l.Errorf("error deleting status mutes created by account: %s", err)
 	}
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, &[]*gtsmodel.AccountMute{}); err != nil {
+		l.Errorf("error deleting account mutes created by account: %s", err)
+	}
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "target_account_id", Value: account.ID}}, &[]*gtsmodel.AccountMute{}); err != nil {
+		l.Errorf("error deleting account mutes targeting account: %s", err)
+	}
+
+	l.Debug("deleting account conversations")
+	conversations, err := p.db.GetConversationsByAccountID(ctx, account.ID, "", "", "", 0)
+	if err != nil && err != db.ErrNoEntries {
+		l.Errorf("error getting conversations of account: %s", err)
+	}
+	for _, c := range conversations {
+		if err := p.db.DeleteConversationByID(ctx, c.ID); err != nil {
+			l.Errorf("error deleting conversation %s of account: %s", c.ID, err)
+		}
+	}
 
 	// 14. Delete account's streams
 	// TODO
@@ -284,7 +330,7 @@ selectStatusesLoop:
 	account.SuspendedAt = time.Now()
 	account.SuspensionOrigin = origin
 
-	account, err := p.db.UpdateAccount(ctx, account)
+	account, err = p.db.UpdateAccount(ctx, account)
 	if err != nil {
 		return gtserror.NewErrorInternalError(err)
 	}
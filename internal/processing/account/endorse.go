@@ -0,0 +1,108 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+func (p *processor) EndorseCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	// make sure the target account actually exists in our db
+	targetAccount, err := p.db.GetAccountByID(ctx, targetAccountID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("EndorseCreate: account %s not found in the db: %s", targetAccountID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseCreate: error getting account %s from the db: %s", targetAccountID, err))
+	}
+
+	// only accounts that are already followed can be featured
+	following, err := p.db.IsFollowing(ctx, requestingAccount, targetAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseCreate: error checking existence of follow: %s", err))
+	}
+	if !following {
+		err := errors.New("you must be following an account to feature it on your profile")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// if requestingAccount already endorses target account, we don't need to do anything
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: requestingAccount.ID},
+		{Key: "target_account_id", Value: targetAccountID},
+	}, &gtsmodel.Endorsement{}); err == nil {
+		return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+	} else if err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseCreate: error checking existence of endorsement: %s", err))
+	}
+
+	newEndorsementID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	endorsement := &gtsmodel.Endorsement{
+		ID:              newEndorsementID,
+		AccountID:       requestingAccount.ID,
+		Account:         requestingAccount,
+		TargetAccountID: targetAccountID,
+		TargetAccount:   targetAccount,
+	}
+
+	if err := p.db.Put(ctx, endorsement); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseCreate: error creating endorsement in db: %s", err))
+	}
+
+	return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+}
+
+func (p *processor) EndorseRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	// make sure the target account actually exists in our db
+	if _, err := p.db.GetAccountByID(ctx, targetAccountID); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("EndorseRemove: account %s not found in the db: %s", targetAccountID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseRemove: error getting account %s from the db: %s", targetAccountID, err))
+	}
+
+	if err := p.removeEndorsement(ctx, requestingAccount.ID, targetAccountID); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("EndorseRemove: %s", err))
+	}
+
+	return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+}
+
+// removeEndorsement deletes the endorsement of targetAccountID by accountID, if there is one.
+func (p *processor) removeEndorsement(ctx context.Context, accountID string, targetAccountID string) error {
+	if err := p.db.DeleteWhere(ctx, []db.Where{
+		{Key: "account_id", Value: accountID},
+		{Key: "target_account_id", Value: targetAccountID},
+	}, &gtsmodel.Endorsement{}); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error removing endorsement from db: %s", err)
+	}
+	return nil
+}
@@ -21,14 +21,18 @@ package account
 import (
 	"context"
 	"fmt"
+	"net/url"
 
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-func (p *processor) StatusesGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinnedOnly bool, mediaOnly bool, publicOnly bool) ([]apimodel.Status, gtserror.WithCode) {
+func (p *processor) StatusesGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinnedOnly bool, mediaOnly bool, publicOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
 	if requestingAccount != nil {
 		if blocked, err := p.db.IsBlocked(ctx, requestingAccount.ID, targetAccountID, true); err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
@@ -37,12 +41,18 @@ func (p *processor) StatusesGet(ctx context.Context, requestingAccount *gtsmodel
 		}
 	}
 
-	apiStatuses := []apimodel.Status{}
+	if pinnedOnly {
+		p.dereferenceFeatured(ctx, requestingAccount, targetAccountID)
+	}
+
+	resp := &apimodel.StatusTimelineResponse{
+		Statuses: []*apimodel.Status{},
+	}
 
 	statuses, err := p.db.GetAccountStatuses(ctx, targetAccountID, limit, excludeReplies, excludeReblogs, maxID, minID, pinnedOnly, mediaOnly, publicOnly)
 	if err != nil {
 		if err == db.ErrNoEntries {
-			return apiStatuses, nil
+			return resp, nil
 		}
 		return nil, gtserror.NewErrorInternalError(err)
 	}
@@ -53,13 +63,93 @@ func (p *processor) StatusesGet(ctx context.Context, requestingAccount *gtsmodel
 			continue
 		}
 
+		hide, results, err := p.filter.StatusFilterResults(ctx, s, requestingAccount, gtsmodel.FilterContextAccount)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking filters: %s", err))
+		}
+		if hide {
+			continue
+		}
+
 		apiStatus, err := p.tc.StatusToAPIStatus(ctx, s, requestingAccount)
 		if err != nil {
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status to api: %s", err))
 		}
 
-		apiStatuses = append(apiStatuses, *apiStatus)
+		if len(results) != 0 {
+			apiStatus.Filtered, err = p.tc.FilterResultsToAPIFilterResults(ctx, results)
+			if err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter results to api: %s", err))
+			}
+		}
+
+		resp.Statuses = append(resp.Statuses, apiStatus)
+	}
+
+	// pinned statuses aren't paged through; for everything else, page from the database
+	// statuses rather than the api ones, so filtered statuses at the edges aren't served again
+	if !pinnedOnly {
+		resp.LinkHeader = statusesLinkHeader(targetAccountID, limit, statuses[len(statuses)-1].ID, statuses[0].ID, excludeReplies, excludeReblogs, mediaOnly, publicOnly)
+	}
+
+	return resp, nil
+}
+
+// statusesLinkHeader returns a Link header for the next and previous pages of the statuses of the given account,
+// keeping any of the given filters that are set.
+func statusesLinkHeader(targetAccountID string, limit int, nextMaxID string, prevMinID string, excludeReplies bool, excludeReblogs bool, mediaOnly bool, publicOnly bool) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	path := fmt.Sprintf("api/v1/accounts/%s/statuses", targetAccountID)
+
+	var extraQuery string
+	if excludeReplies {
+		extraQuery += "&exclude_replies=true"
+	}
+	if excludeReblogs {
+		extraQuery += "&exclude_reblogs=true"
+	}
+	if mediaOnly {
+		extraQuery += "&only_media=true"
+	}
+	if publicOnly {
+		extraQuery += "&only_public=true"
+	}
+
+	nextLink := &url.URL{
+		Scheme:   protocol,
+		Host:     host,
+		Path:     path,
+		RawQuery: fmt.Sprintf("limit=%d&max_id=%s%s", limit, nextMaxID, extraQuery),
+	}
+	next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+	prevLink := &url.URL{
+		Scheme:   protocol,
+		Host:     host,
+		Path:     path,
+		RawQuery: fmt.Sprintf("limit=%d&min_id=%s%s", limit, prevMinID, extraQuery),
 	}
+	prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
 
-	return apiStatuses, nil
+	return fmt.Sprintf("%s, %s", next, prev)
+}
+
+// dereferenceFeatured makes sure that the pinned statuses of the target account are up to date,
+// if it's a remote account, by fetching its featured collection. Errors are only logged, so that
+// the statuses that are already known to be pinned can still be shown.
+func (p *processor) dereferenceFeatured(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) {
+	targetAccount, err := p.db.GetAccountByID(ctx, targetAccountID)
+	if err != nil || targetAccount.Domain == "" {
+		return
+	}
+
+	var requestingUsername string
+	if requestingAccount != nil {
+		requestingUsername = requestingAccount.Username
+	}
+
+	if err := p.federator.DereferenceFeatured(ctx, requestingUsername, targetAccount); err != nil {
+		logrus.Debugf("dereferenceFeatured: error dereferencing featured collection of account %s: %s", targetAccount.ID, err)
+	}
 }
@@ -0,0 +1,92 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (p *processor) Move(ctx context.Context, account *gtsmodel.Account, form *apimodel.AccountMoveRequest) gtserror.WithCode {
+	// get the user of the account, so we can check that the password they supplied is correct
+	user := &gtsmodel.User{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: account.ID}}, user); err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	// make sure a password is actually set and bail if not
+	if user.EncryptedPassword == "" {
+		return gtserror.NewErrorForbidden(errors.New("user password was not set"))
+	}
+
+	// compare the provided password with the encrypted one from the db, bail if they don't match
+	if err := bcrypt.CompareHashAndPassword([]byte(user.EncryptedPassword), []byte(form.Password)); err != nil {
+		return gtserror.NewErrorForbidden(errors.New("invalid password"))
+	}
+
+	targetURI, err := url.Parse(form.MovedToURI)
+	if err != nil || targetURI.Scheme == "" || targetURI.Host == "" {
+		return gtserror.NewErrorBadRequest(fmt.Errorf("couldn't parse moved_to_uri %s", form.MovedToURI), "moved_to_uri was not a valid uri")
+	}
+
+	// get the account being moved to; if it's remote, always get a fresh
+	// copy of it, so that we see the aliases that it has set right now
+	targetAccount, err := p.getAccountByURI(ctx, account.Username, targetURI, true)
+	if err != nil {
+		return gtserror.NewErrorBadRequest(fmt.Errorf("error getting move target account %s: %s", targetURI, err), "account to move to could not be found")
+	}
+
+	if targetAccount.ID == account.ID {
+		return gtserror.NewErrorBadRequest(errors.New("account can't move to itself"), "account can't move to itself")
+	}
+
+	// the account being moved to has to agree to the move, by listing this account as an alias
+	if !isAliasOf(targetAccount, account.URI) {
+		err := fmt.Errorf("move target account %s doesn't list account %s as an alias", targetAccount.URI, account.URI)
+		return gtserror.NewErrorBadRequest(err, "account to move to must list this account as an alias first")
+	}
+
+	// mark the account as moved, and lock it so that nobody new can follow it without approval
+	account.MovedToAccountID = targetAccount.ID
+	account.Locked = true
+	if _, err := p.db.UpdateAccount(ctx, account); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error updating moved account: %s", err))
+	}
+
+	// put the move in the processor queue to handle federating it and moving followers asynchronously
+	p.clientWorker.Queue(messages.FromClientAPI{
+		APObjectType:   ap.ObjectProfile,
+		APActivityType: ap.ActivityMove,
+		GTSModel:       targetAccount,
+		OriginAccount:  account,
+		TargetAccount:  targetAccount,
+	})
+
+	return nil
+}
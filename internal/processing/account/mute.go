@@ -0,0 +1,136 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+func (p *processor) MuteCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode) {
+	if targetAccountID == requestingAccount.ID {
+		err := errors.New("you can't mute yourself")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	if form.Duration < 0 {
+		err := errors.New("duration must not be negative")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// make sure the target account actually exists in our db
+	targetAccount, err := p.db.GetAccountByID(ctx, targetAccountID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("MuteCreate: account %s not found in the db: %s", targetAccountID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error getting account %s from the db: %s", targetAccountID, err))
+	}
+
+	notifications := true
+	if form.Notifications != nil {
+		notifications = *form.Notifications
+	}
+
+	var expiresAt time.Time
+	if form.Duration > 0 {
+		expiresAt = time.Now().Add(time.Duration(form.Duration) * time.Second)
+	}
+
+	// if requestingAccount already mutes target account, just update the existing mute with the new settings;
+	// this also brings back a mute that's expired but hasn't been lifted yet
+	mute := &gtsmodel.AccountMute{}
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: requestingAccount.ID},
+		{Key: "target_account_id", Value: targetAccountID},
+	}, mute); err == nil {
+		mute.Notifications = notifications
+		mute.ExpiresAt = expiresAt
+		mute.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, mute); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error updating mute in db: %s", err))
+		}
+		p.queueMute(mute, requestingAccount, targetAccount)
+		return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+	} else if err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error checking existence of mute: %s", err))
+	}
+
+	newMuteID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	mute = &gtsmodel.AccountMute{
+		ID:              newMuteID,
+		ExpiresAt:       expiresAt,
+		AccountID:       requestingAccount.ID,
+		Account:         requestingAccount,
+		TargetAccountID: targetAccountID,
+		TargetAccount:   targetAccount,
+		Notifications:   notifications,
+	}
+
+	if err := p.db.Put(ctx, mute); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteCreate: error creating mute in db: %s", err))
+	}
+	p.queueMute(mute, requestingAccount, targetAccount)
+
+	return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+}
+
+// queueMute queues the given mute for processing, so that statuses by the muted account are taken out of the muting account's timeline.
+func (p *processor) queueMute(mute *gtsmodel.AccountMute, requestingAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) {
+	p.clientWorker.Queue(messages.FromClientAPI{
+		APObjectType:   ap.ObjectMute,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       mute,
+		OriginAccount:  requestingAccount,
+		TargetAccount:  targetAccount,
+	})
+}
+
+func (p *processor) MuteRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode) {
+	// make sure the target account actually exists in our db
+	if _, err := p.db.GetAccountByID(ctx, targetAccountID); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("MuteRemove: account %s not found in the db: %s", targetAccountID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteRemove: error getting account %s from the db: %s", targetAccountID, err))
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{
+		{Key: "account_id", Value: requestingAccount.ID},
+		{Key: "target_account_id", Value: targetAccountID},
+	}, &gtsmodel.AccountMute{}); err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("MuteRemove: error removing mute from db: %s", err))
+	}
+
+	return p.RelationshipGet(ctx, requestingAccount, targetAccountID)
+}
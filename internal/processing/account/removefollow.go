@@ -75,6 +75,10 @@ func (p *processor) FollowRemove(ctx context.Context, requestingAccount *gtsmode
 		if err := p.db.DeleteByID(ctx, f.ID, f); err != nil {
 			return nil, gtserror.NewErrorInternalError(fmt.Errorf("AccountFollowRemove: error removing follow from db: %s", err))
 		}
+		// accounts can only be featured by their followers
+		if err := p.removeEndorsement(ctx, requestingAccount.ID, targetAccountID); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("AccountFollowRemove: %s", err))
+		}
 		fChanged = true
 	}
 
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -67,6 +68,29 @@ func (p *processor) Update(ctx context.Context, account *gtsmodel.Account, form
 		account.Note = note
 	}
 
+	if form.FieldsAttributes != nil {
+		if err := validate.ProfileFields(*form.FieldsAttributes); err != nil {
+			return nil, err
+		}
+		account.Fields = processFields(account.Fields, *form.FieldsAttributes)
+	}
+
+	// media is only deleted once the account no longer points to it, so that a
+	// failed update can't leave the account with a dangling avatar or header
+	deleteMediaIDs := []string{}
+
+	if form.DeleteAvatar && account.AvatarMediaAttachmentID != "" {
+		deleteMediaIDs = append(deleteMediaIDs, account.AvatarMediaAttachmentID)
+		account.AvatarMediaAttachmentID = ""
+		account.AvatarMediaAttachment = nil
+	}
+
+	if form.DeleteHeader && account.HeaderMediaAttachmentID != "" {
+		deleteMediaIDs = append(deleteMediaIDs, account.HeaderMediaAttachmentID)
+		account.HeaderMediaAttachmentID = ""
+		account.HeaderMediaAttachment = nil
+	}
+
 	if form.Avatar != nil && form.Avatar.Size != 0 {
 		avatarInfo, err := p.UpdateAvatar(ctx, form.Avatar, account.ID)
 		if err != nil {
@@ -110,6 +134,32 @@ func (p *processor) Update(ctx context.Context, account *gtsmodel.Account, form
 			privacy := p.tc.APIVisToVis(apimodel.Visibility(*form.Source.Privacy))
 			account.Privacy = privacy
 		}
+
+		if form.Source.ExcludedNotifications != nil {
+			excludedNotifications := []gtsmodel.NotificationType{}
+			for _, t := range *form.Source.ExcludedNotifications {
+				if err := validate.NotificationType(t); err != nil {
+					return nil, err
+				}
+				excludedNotifications = append(excludedNotifications, gtsmodel.NotificationType(t))
+			}
+			account.ExcludedNotifications = excludedNotifications
+		}
+
+		if form.Source.ExpandMedia != nil {
+			if err := validate.ExpandMedia(*form.Source.ExpandMedia); err != nil {
+				return nil, err
+			}
+			account.ExpandMedia = gtsmodel.ExpandMedia(*form.Source.ExpandMedia)
+		}
+
+		if form.Source.ExpandSpoilers != nil {
+			account.ExpandSpoilers = *form.Source.ExpandSpoilers
+		}
+	}
+
+	if err := p.processAccountEmojis(ctx, account); err != nil {
+		return nil, err
 	}
 
 	updatedAccount, err := p.db.UpdateAccount(ctx, account)
@@ -117,6 +167,12 @@ func (p *processor) Update(ctx context.Context, account *gtsmodel.Account, form
 		return nil, fmt.Errorf("could not update account %s: %s", account.ID, err)
 	}
 
+	for _, id := range deleteMediaIDs {
+		if err := p.mediaManager.DeleteMedia(ctx, id); err != nil {
+			l.Errorf("error deleting media %s of account %s: %s", id, account.ID, err)
+		}
+	}
+
 	p.clientWorker.Queue(messages.FromClientAPI{
 		APObjectType:   ap.ObjectProfile,
 		APActivityType: ap.ActivityUpdate,
@@ -209,9 +265,60 @@ func (p *processor) processNote(ctx context.Context, note string, accountID stri
 		mentions = append(mentions, mention)
 	}
 
-	// TODO: support emojis in account notes
-	// emojiStrings := util.DeriveEmojisFromText(note)
-	// emojis, err := p.db.EmojiStringsToEmojis(ctx, emojiStrings)
-
 	return p.formatter.FromPlain(ctx, note, mentions, tags), nil
 }
+
+// processFields turns the given profile fields from an update form into fields to set on an account,
+// leaving out empty ones. Fields that haven't changed keep the time they were verified at, if any,
+// so that they don't have to be verified all over again.
+func processFields(oldFields []gtsmodel.Field, updateFields []apimodel.UpdateField) []gtsmodel.Field {
+	fields := []gtsmodel.Field{}
+	for _, f := range updateFields {
+		var name, value string
+		if f.Name != nil {
+			name = text.RemoveHTML(strings.TrimSpace(*f.Name))
+		}
+		if f.Value != nil {
+			value = text.RemoveHTML(strings.TrimSpace(*f.Value))
+		}
+
+		if name == "" && value == "" {
+			continue
+		}
+
+		field := gtsmodel.Field{
+			Name:  name,
+			Value: value,
+		}
+		for _, old := range oldFields {
+			if old.Name == field.Name && old.Value == field.Value {
+				field.VerifiedAt = old.VerifiedAt
+				break
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// processAccountEmojis sets the custom emojis used in the display name, note, and fields of the given account,
+// so that they can be shown alongside the account in the client API, and included in its actor for federation.
+func (p *processor) processAccountEmojis(ctx context.Context, account *gtsmodel.Account) error {
+	texts := []string{account.DisplayName, account.Note}
+	for _, f := range account.Fields {
+		texts = append(texts, f.Name, f.Value)
+	}
+
+	emojis, err := p.db.EmojiStringsToEmojis(ctx, util.DeriveEmojisFromText(strings.Join(texts, "\n")))
+	if err != nil {
+		return fmt.Errorf("error generating emojis from account: %s", err)
+	}
+
+	emojiIDs := make([]string, 0, len(emojis))
+	for _, e := range emojis {
+		emojiIDs = append(emojiIDs, e.ID)
+	}
+	account.Emojis = emojis
+	account.EmojiIDs = emojiIDs
+	return nil
+}
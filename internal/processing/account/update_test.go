@@ -21,10 +21,12 @@ package account_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 )
 
 type AccountUpdateTestSuite struct {
@@ -113,6 +115,178 @@ go check out @1happyturtle, they have a cool account!
 	suite.Equal(noteExpected, dbAccount.Note)
 }
 
+func (suite *AccountUpdateTestSuite) TestAccountUpdateWithEmoji() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	displayName := "zork :rainbow:"
+	note := "i like :rainbow: and :not_an_emoji:"
+
+	form := &apimodel.UpdateCredentialsRequest{
+		DisplayName: &displayName,
+		Note:        &note,
+	}
+
+	apiAccount, err := suite.accountProcessor.Update(context.Background(), testAccount, form)
+	suite.NoError(err)
+	suite.NotNil(apiAccount)
+
+	// the emoji should be included with the account, just once, and unknown shortcodes should be left out
+	suite.Len(apiAccount.Emojis, 1)
+	suite.Equal("rainbow", apiAccount.Emojis[0].Shortcode)
+	suite.Equal("http://localhost:8080/fileserver/01F8MH261H1KSV3GW3016GZRY3/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png", apiAccount.Emojis[0].URL)
+
+	// and stored with it in the database
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Equal([]string{"01F8MH9H8E4VG3KDYJR9EGPXCQ"}, dbAccount.EmojiIDs)
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountUpdateFields() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	field := func(name string, value string) apimodel.UpdateField {
+		return apimodel.UpdateField{Name: &name, Value: &value}
+	}
+	form := &apimodel.UpdateCredentialsRequest{
+		FieldsAttributes: &[]apimodel.UpdateField{
+			field("pronouns", "they/them"),
+			field("", ""),
+			field("website", "https://zork.example.org/about"),
+			field("other website", "https://example.org/not-zork"),
+		},
+	}
+
+	apiAccount, err := suite.accountProcessor.Update(context.Background(), testAccount, form)
+	suite.NoError(err)
+	<-suite.fromClientAPIChan
+
+	// the empty field should be left out, and none should be verified yet
+	suite.Len(apiAccount.Fields, 3)
+	suite.Equal("website", apiAccount.Fields[1].Name)
+	suite.Equal("https://zork.example.org/about", apiAccount.Fields[1].Value)
+	for _, f := range apiAccount.Fields {
+		suite.Empty(f.VerifiedAt)
+	}
+
+	// only the page that links back to zork should be verified
+	err = suite.accountProcessor.VerifyFields(context.Background(), testAccount)
+	suite.NoError(err)
+
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.True(dbAccount.Fields[0].VerifiedAt.IsZero())
+	suite.False(dbAccount.Fields[1].VerifiedAt.IsZero())
+	suite.True(dbAccount.Fields[2].VerifiedAt.IsZero())
+	verifiedAt := dbAccount.Fields[1].VerifiedAt
+
+	// a field that hasn't changed should stay verified when the fields are updated again
+	form.FieldsAttributes = &[]apimodel.UpdateField{
+		field("website", "https://zork.example.org/about"),
+		field("pronouns", "it/its"),
+	}
+	apiAccount, err = suite.accountProcessor.Update(context.Background(), dbAccount, form)
+	suite.NoError(err)
+	<-suite.fromClientAPIChan
+
+	suite.Len(apiAccount.Fields, 2)
+	suite.Equal(verifiedAt.Format(time.RFC3339), apiAccount.Fields[0].VerifiedAt)
+	suite.Empty(apiAccount.Fields[1].VerifiedAt)
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountVerifyFieldsUnavailable() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	field := func(name string, value string) apimodel.UpdateField {
+		return apimodel.UpdateField{Name: &name, Value: &value}
+	}
+	form := &apimodel.UpdateCredentialsRequest{
+		FieldsAttributes: &[]apimodel.UpdateField{
+			field("unreachable", "https://unreachable.example.org/about"),
+			field("gone", "https://gone.example.org/about"),
+			field("slow", "https://slow.example.org/about"),
+		},
+	}
+
+	_, err := suite.accountProcessor.Update(context.Background(), testAccount, form)
+	suite.NoError(err)
+	<-suite.fromClientAPIChan
+
+	// the slow page is given up on when the context times out
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// none of the pages can be fetched properly, so none of them should be verified
+	begin := time.Now()
+	err = suite.accountProcessor.VerifyFields(ctx, testAccount)
+	suite.NoError(err)
+	suite.Less(time.Since(begin), 5*time.Second)
+
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Len(dbAccount.Fields, 3)
+	for _, f := range dbAccount.Fields {
+		suite.True(f.VerifiedAt.IsZero(), f.Name)
+	}
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountUpdateTooManyFields() {
+	testAccount := suite.testAccounts["local_account_1"]
+
+	fields := []apimodel.UpdateField{}
+	for _, name := range []string{"one", "two", "three", "four", "five"} {
+		name := name
+		fields = append(fields, apimodel.UpdateField{Name: &name, Value: &name})
+	}
+
+	_, err := suite.accountProcessor.Update(context.Background(), testAccount, &apimodel.UpdateCredentialsRequest{FieldsAttributes: &fields})
+	suite.EqualError(err, "no more than 4 profile fields can be set but 5 were given")
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountUpdateDeleteAvatar() {
+	testAccount := suite.testAccounts["local_account_1"]
+	avatarID := testAccount.AvatarMediaAttachmentID
+
+	apiAccount, err := suite.accountProcessor.Update(context.Background(), testAccount, &apimodel.UpdateCredentialsRequest{DeleteAvatar: true})
+	suite.NoError(err)
+	suite.Empty(apiAccount.Avatar)
+	suite.Empty(apiAccount.AvatarStatic)
+	suite.NotEmpty(apiAccount.Header)
+
+	// the update should be federated out, like any other
+	msg := <-suite.fromClientAPIChan
+	suite.Equal(ap.ActivityUpdate, msg.APActivityType)
+
+	// the avatar should be gone from the account and the database
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Empty(dbAccount.AvatarMediaAttachmentID)
+	suite.NotEmpty(dbAccount.HeaderMediaAttachmentID)
+
+	_, err = suite.db.GetAttachmentByID(context.Background(), avatarID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *AccountUpdateTestSuite) TestAccountUpdateDeleteAvatarInvalid() {
+	testAccount, err := suite.db.GetAccountByID(context.Background(), suite.testAccounts["local_account_1"].ID)
+	suite.NoError(err)
+	avatarID := testAccount.AvatarMediaAttachmentID
+
+	privacy := "not a real privacy setting"
+	_, err = suite.accountProcessor.Update(context.Background(), testAccount, &apimodel.UpdateCredentialsRequest{
+		DeleteAvatar: true,
+		Source:       &apimodel.UpdateSource{Privacy: &privacy},
+	})
+	suite.Error(err)
+
+	// the update failed, so the avatar should still be there
+	dbAccount, err := suite.db.GetAccountByID(context.Background(), testAccount.ID)
+	suite.NoError(err)
+	suite.Equal(avatarID, dbAccount.AvatarMediaAttachmentID)
+
+	_, err = suite.db.GetAttachmentByID(context.Background(), avatarID)
+	suite.NoError(err)
+}
+
 func TestAccountUpdateTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountUpdateTestSuite))
 }
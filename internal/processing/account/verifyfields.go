@@ -0,0 +1,127 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+)
+
+// verifyFieldTimeout is how long to wait for the web page linked in a profile field.
+const verifyFieldTimeout = 10 * time.Second
+
+func (p *processor) VerifyFields(ctx context.Context, account *gtsmodel.Account) error {
+	l := logrus.WithFields(logrus.Fields{
+		"func":      "VerifyFields",
+		"accountID": account.ID,
+	})
+
+	if account.Domain != "" {
+		return fmt.Errorf("VerifyFields: account %s is not a local account", account.ID)
+	}
+
+	// make sure we're working with the fields as they are now, rather than when the update was queued
+	account, err := p.db.GetAccountByID(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("VerifyFields: error getting account: %s", err)
+	}
+
+	var t transport.Transport
+	verified := []gtsmodel.Field{}
+	for _, field := range account.Fields {
+		if !field.VerifiedAt.IsZero() {
+			continue
+		}
+
+		fieldURL := fieldLink(field.Value)
+		if fieldURL == nil {
+			continue
+		}
+
+		if t == nil {
+			t, err = p.federator.TransportController().NewTransportForUsername(ctx, account.Username)
+			if err != nil {
+				return fmt.Errorf("VerifyFields: error creating transport: %s", err)
+			}
+		}
+
+		if !linksBack(ctx, t, fieldURL, account) {
+			l.Debugf("no rel=me link back to the account found at %s", fieldURL)
+			continue
+		}
+
+		verified = append(verified, field)
+	}
+
+	if len(verified) == 0 {
+		return nil
+	}
+
+	// the fields might have been changed while the links were being checked, in which case they're left alone
+	if err := p.db.VerifyAccountFields(ctx, account.ID, verified, time.Now()); err != nil {
+		return fmt.Errorf("VerifyFields: error updating account fields: %s", err)
+	}
+	return nil
+}
+
+// fieldLink returns the URL in the given profile field value, if the value is
+// nothing but a link to a web page, or nil otherwise.
+func fieldLink(value string) *url.URL {
+	value = html.UnescapeString(value)
+	if strings.ContainsAny(value, " \t\n") {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil
+	}
+	return u
+}
+
+// linksBack checks whether the web page at the given URL has a rel="me" link to the given account,
+// either to its web profile or to its ActivityPub URI.
+func linksBack(ctx context.Context, t transport.Transport, pageURL *url.URL, account *gtsmodel.Account) bool {
+	ctx, cancel := context.WithTimeout(ctx, verifyFieldTimeout)
+	defer cancel()
+
+	page, err := t.DereferenceWebPage(ctx, pageURL)
+	if err != nil {
+		logrus.Debugf("linksBack: error fetching %s: %s", pageURL, err)
+		return false
+	}
+
+	for _, link := range text.FindRelMeLinks(string(page)) {
+		l := strings.TrimSuffix(link.String(), "/")
+		if l == account.URL || l == account.URI {
+			return true
+		}
+	}
+	return false
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/pub"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
@@ -79,6 +80,24 @@ func (suite *AccountTestSuite) TestAccountDeleteLocal() {
 	suite.Equal(pub.PublicActivityPubIRI, delete.CC)
 	suite.Equal("Delete", delete.Type)
 
+	// other instances we know about should get the same delete, even though nobody there follows the account
+	sent, ok = suite.sentHTTPRequests[suite.testAccounts["remote_account_2"].InboxURI]
+	suite.True(ok)
+	knownDelete := &struct {
+		ID     string `json:"id"`
+		Object string `json:"object"`
+		Type   string `json:"type"`
+	}{}
+	err = json.Unmarshal(sent, knownDelete)
+	suite.NoError(err)
+	suite.Equal(delete.ID, knownDelete.ID)
+	suite.Equal(deletingAccount.URI, knownDelete.Object)
+	suite.Equal("Delete", knownDelete.Type)
+
+	// the avatar of the account should be gone
+	_, err = suite.db.GetAttachmentByID(ctx, suite.testAttachments["local_account_1_avatar"].ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
 	// the deleted account should be deleted
 	dbAccount, err := suite.db.GetAccountByID(ctx, deletingAccount.ID)
 	suite.NoError(err)
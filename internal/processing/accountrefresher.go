@@ -0,0 +1,116 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+const (
+	// accountRefresherSchedule is how often stale remote accounts are looked for and refreshed.
+	accountRefresherSchedule = "@every 1h"
+	// accountRefreshBatchSize is the most stale accounts that will be looked at in one run.
+	accountRefreshBatchSize = 500
+	// accountRefreshJitter is the longest the refresher will wait between refreshing one account and the next,
+	// so that refreshes are spread out a bit rather than all hitting remote instances at once.
+	accountRefreshJitter = 5 * time.Second
+)
+
+// startAccountRefresher starts a cron job that fetches remote accounts from their instances again once they've gone
+// stale, so that their profiles don't drift out of date if their instance never sends an update for them.
+func (p *processor) startAccountRefresher() error {
+	days := viper.GetInt(config.Keys.FederationRefreshDays)
+	if days <= 0 {
+		// refreshing is turned off
+		return nil
+	}
+
+	if err := p.startScheduledJob(accountRefresherSchedule, func(ctx context.Context) {
+		olderThan := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+		if err := p.refreshStaleAccounts(ctx, olderThan, viper.GetInt(config.Keys.FederationRefreshPerDomain)); err != nil && ctx.Err() == nil {
+			logrus.Errorf("account refresher: error refreshing stale accounts: %s", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("error starting account refresher job: %s", err)
+	}
+	return nil
+}
+
+// refreshStaleAccounts fetches remote accounts that haven't been fetched since olderThan from their instances again,
+// refreshing no more than perDomain accounts from any one domain. If refreshing an account from a domain fails, the
+// rest of that domain's accounts are left until the next run, since its instance is probably having trouble.
+//
+// Each run looks at the next batch of stale accounts after the ones the last run looked at, starting from the
+// beginning again once it runs out, so that accounts skipped in one run don't stop the rest from ever being reached.
+func (p *processor) refreshStaleAccounts(ctx context.Context, olderThan time.Time, perDomain int) error {
+	accounts, err := p.db.GetStaleRemoteAccounts(ctx, olderThan, p.accountRefreshCursor, accountRefreshBatchSize)
+	if err != nil {
+		return fmt.Errorf("refreshStaleAccounts: error getting stale accounts: %s", err)
+	}
+
+	if len(accounts) < accountRefreshBatchSize {
+		p.accountRefreshCursor = ""
+	} else {
+		p.accountRefreshCursor = accounts[len(accounts)-1].ID
+	}
+
+	refreshed := make(map[string]int)
+	failed := make(map[string]bool)
+	for _, account := range accounts {
+		if failed[account.Domain] || (perDomain > 0 && refreshed[account.Domain] >= perDomain) {
+			continue
+		}
+
+		// wait a little while before each refresh, unless we're told to stop
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(rand.Int63n(int64(accountRefreshJitter)))):
+		}
+
+		accountURI, err := url.Parse(account.URI)
+		if err != nil {
+			logrus.Debugf("refreshStaleAccounts: couldn't parse uri %s of account %s: %s", account.URI, account.ID, err)
+			continue
+		}
+
+		if _, err := p.federator.GetRemoteAccount(ctx, "", accountURI, false, true); err != nil {
+			logrus.Debugf("refreshStaleAccounts: couldn't refresh account %s, skipping domain %s for now: %s", account.URI, account.Domain, err)
+			failed[account.Domain] = true
+
+			// count the attempt as a fetch, so that an account that can't be fetched
+			// isn't tried again on every run until it would be stale again anyway
+			if err := p.db.UpdateAccountLastWebfingeredAt(ctx, account.ID, time.Now()); err != nil {
+				logrus.Errorf("refreshStaleAccounts: error updating last fetch time of account %s: %s", account.ID, err)
+			}
+			continue
+		}
+		refreshed[account.Domain]++
+	}
+
+	return nil
+}
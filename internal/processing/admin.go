@@ -30,12 +30,64 @@ func (p *processor) AdminAccountAction(ctx context.Context, authed *oauth.Auth,
 	return p.adminProcessor.AccountAction(ctx, authed.Account, form)
 }
 
+func (p *processor) AdminMediaRecache(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminMediaRecacheRequest) (*apimodel.AdminMediaRecacheResponse, gtserror.WithCode) {
+	return p.adminProcessor.MediaRecache(ctx, authed.Account, form)
+}
+
+func (p *processor) AdminMediaIntegrityGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminMediaIntegrityReport, gtserror.WithCode) {
+	return p.adminProcessor.MediaIntegrityGet(ctx)
+}
+
+func (p *processor) AdminMediaStatsGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminMediaStats, gtserror.WithCode) {
+	return p.adminProcessor.MediaStatsGet(ctx)
+}
+
+func (p *processor) AdminDeliveryStatsGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminDeliveryStats, gtserror.WithCode) {
+	return p.adminProcessor.DeliveryStatsGet(ctx)
+}
+
+func (p *processor) AdminDeliveriesGet(ctx context.Context, authed *oauth.Auth, domain string, limit int) ([]*apimodel.AdminDelivery, gtserror.WithCode) {
+	return p.adminProcessor.DeliveriesGet(ctx, domain, limit)
+}
+
+func (p *processor) AdminUnreachableDomainsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	return p.adminProcessor.UnreachableDomainsGet(ctx)
+}
+
+func (p *processor) AdminUnreachableDomainCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminUnreachableDomainCreateRequest) (*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	return p.adminProcessor.UnreachableDomainCreate(ctx, form)
+}
+
+func (p *processor) AdminUnreachableDomainDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	return p.adminProcessor.UnreachableDomainDelete(ctx, id)
+}
+
+func (p *processor) AdminRulesGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminRule, gtserror.WithCode) {
+	return p.adminProcessor.RulesGet(ctx)
+}
+
+func (p *processor) AdminRuleGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminRule, gtserror.WithCode) {
+	return p.adminProcessor.RuleGet(ctx, id)
+}
+
+func (p *processor) AdminRuleCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminRuleCreateRequest) (*apimodel.AdminRule, gtserror.WithCode) {
+	return p.adminProcessor.RuleCreate(ctx, form)
+}
+
+func (p *processor) AdminRuleUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminRuleUpdateRequest) (*apimodel.AdminRule, gtserror.WithCode) {
+	return p.adminProcessor.RuleUpdate(ctx, id, form)
+}
+
+func (p *processor) AdminRuleDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminRule, gtserror.WithCode) {
+	return p.adminProcessor.RuleDelete(ctx, id)
+}
+
 func (p *processor) AdminEmojiCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, gtserror.WithCode) {
 	return p.adminProcessor.EmojiCreate(ctx, authed.Account, authed.User, form)
 }
 
 func (p *processor) AdminDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
-	return p.adminProcessor.DomainBlockCreate(ctx, authed.Account, form.Domain, form.Obfuscate, form.PublicComment, form.PrivateComment, "")
+	return p.adminProcessor.DomainBlockCreate(ctx, authed.Account, form.Domain, form.Obfuscate, form.IncludeSubdomains, form.Severity, form.RejectMedia, form.PublicComment, form.PrivateComment, "")
 }
 
 func (p *processor) AdminDomainBlocksImport(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) ([]*apimodel.DomainBlock, gtserror.WithCode) {
@@ -50,6 +102,74 @@ func (p *processor) AdminDomainBlockGet(ctx context.Context, authed *oauth.Auth,
 	return p.adminProcessor.DomainBlockGet(ctx, authed.Account, id, export)
 }
 
+func (p *processor) AdminDomainBlockUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.DomainBlockUpdateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.DomainBlockUpdate(ctx, authed.Account, id, form)
+}
+
 func (p *processor) AdminDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode) {
 	return p.adminProcessor.DomainBlockDelete(ctx, authed.Account, id)
 }
+
+func (p *processor) AdminEmailDomainBlocksGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.EmailDomainBlocksGet(ctx)
+}
+
+func (p *processor) AdminEmailDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.EmailDomainBlockGet(ctx, id)
+}
+
+func (p *processor) AdminEmailDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminEmailDomainBlockCreateRequest) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.EmailDomainBlockCreate(ctx, authed.Account, form)
+}
+
+func (p *processor) AdminEmailDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	return p.adminProcessor.EmailDomainBlockDelete(ctx, id)
+}
+
+func (p *processor) AdminIPBlocksGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminIPBlock, gtserror.WithCode) {
+	return p.adminProcessor.IPBlocksGet(ctx)
+}
+
+func (p *processor) AdminIPBlockGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	return p.adminProcessor.IPBlockGet(ctx, id)
+}
+
+func (p *processor) AdminIPBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminIPBlockCreateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	return p.adminProcessor.IPBlockCreate(ctx, authed.Account, form)
+}
+
+func (p *processor) AdminIPBlockUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminIPBlockUpdateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	return p.adminProcessor.IPBlockUpdate(ctx, id, form)
+}
+
+func (p *processor) AdminIPBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	return p.adminProcessor.IPBlockDelete(ctx, id)
+}
+
+func (p *processor) AdminMeasuresGet(ctx context.Context, authed *oauth.Auth, weeks int) ([]*apimodel.AdminWeeklyMeasures, gtserror.WithCode) {
+	return p.adminProcessor.MeasuresGet(ctx, weeks)
+}
+
+func (p *processor) AdminDimensionsGet(ctx context.Context, authed *oauth.Auth, limit int) (*apimodel.AdminDimensions, gtserror.WithCode) {
+	return p.adminProcessor.DimensionsGet(ctx, limit)
+}
+
+func (p *processor) AdminReportsGet(ctx context.Context, authed *oauth.Auth, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportsGet(ctx, authed.Account, resolved, accountID, targetAccountID, maxID, sinceID, limit)
+}
+
+func (p *processor) AdminReportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportGet(ctx, authed.Account, id)
+}
+
+func (p *processor) AdminReportAssign(ctx context.Context, authed *oauth.Auth, id string, assign bool) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportAssign(ctx, authed.Account, id, assign)
+}
+
+func (p *processor) AdminReportResolve(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportResolve(ctx, authed.Account, id, form)
+}
+
+func (p *processor) AdminReportReopen(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	return p.adminProcessor.ReportReopen(ctx, authed.Account, id)
+}
@@ -21,6 +21,7 @@ package admin
 import (
 	"context"
 	"mime/multipart"
+	"sync"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -28,34 +29,81 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 )
 
 // Processor wraps a bunch of functions for processing admin actions.
 type Processor interface {
-	DomainBlockCreate(ctx context.Context, account *gtsmodel.Account, domain string, obfuscate bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlockCreate(ctx context.Context, account *gtsmodel.Account, domain string, obfuscate bool, includeSubdomains bool, severity string, rejectMedia bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode)
 	DomainBlocksImport(ctx context.Context, account *gtsmodel.Account, domains *multipart.FileHeader) ([]*apimodel.DomainBlock, gtserror.WithCode)
 	DomainBlocksGet(ctx context.Context, account *gtsmodel.Account, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
 	DomainBlockGet(ctx context.Context, account *gtsmodel.Account, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
+	DomainBlockUpdate(ctx context.Context, account *gtsmodel.Account, id string, form *apimodel.DomainBlockUpdateRequest) (*apimodel.DomainBlock, gtserror.WithCode)
 	DomainBlockDelete(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.DomainBlock, gtserror.WithCode)
+	EmailDomainBlocksGet(ctx context.Context) ([]*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	EmailDomainBlockGet(ctx context.Context, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	EmailDomainBlockCreate(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminEmailDomainBlockCreateRequest) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	EmailDomainBlockDelete(ctx context.Context, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	IPBlocksGet(ctx context.Context) ([]*apimodel.AdminIPBlock, gtserror.WithCode)
+	IPBlockGet(ctx context.Context, id string) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	IPBlockCreate(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminIPBlockCreateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	IPBlockUpdate(ctx context.Context, id string, form *apimodel.AdminIPBlockUpdateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	IPBlockDelete(ctx context.Context, id string) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	MeasuresGet(ctx context.Context, weeks int) ([]*apimodel.AdminWeeklyMeasures, gtserror.WithCode)
+	DimensionsGet(ctx context.Context, limit int) (*apimodel.AdminDimensions, gtserror.WithCode)
 	AccountAction(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminAccountActionRequest) gtserror.WithCode
 	EmojiCreate(ctx context.Context, account *gtsmodel.Account, user *gtsmodel.User, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, gtserror.WithCode)
+	MediaRecache(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminMediaRecacheRequest) (*apimodel.AdminMediaRecacheResponse, gtserror.WithCode)
+	MediaIntegrityGet(ctx context.Context) (*apimodel.AdminMediaIntegrityReport, gtserror.WithCode)
+	MediaStatsGet(ctx context.Context) (*apimodel.AdminMediaStats, gtserror.WithCode)
+	DeliveryStatsGet(ctx context.Context) (*apimodel.AdminDeliveryStats, gtserror.WithCode)
+	DeliveriesGet(ctx context.Context, domain string, limit int) ([]*apimodel.AdminDelivery, gtserror.WithCode)
+	UnreachableDomainsGet(ctx context.Context) ([]*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	UnreachableDomainDelete(ctx context.Context, id string) (*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	UnreachableDomainCreate(ctx context.Context, form *apimodel.AdminUnreachableDomainCreateRequest) (*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	RulesGet(ctx context.Context) ([]*apimodel.AdminRule, gtserror.WithCode)
+	RuleGet(ctx context.Context, id string) (*apimodel.AdminRule, gtserror.WithCode)
+	RuleCreate(ctx context.Context, form *apimodel.AdminRuleCreateRequest) (*apimodel.AdminRule, gtserror.WithCode)
+	RuleUpdate(ctx context.Context, id string, form *apimodel.AdminRuleUpdateRequest) (*apimodel.AdminRule, gtserror.WithCode)
+	RuleDelete(ctx context.Context, id string) (*apimodel.AdminRule, gtserror.WithCode)
+	ReportsGet(ctx context.Context, account *gtsmodel.Account, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportGet(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportAssign(ctx context.Context, account *gtsmodel.Account, id string, assign bool) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportResolve(ctx context.Context, account *gtsmodel.Account, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	ReportReopen(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+
+	// Wait blocks until the side effects of any domain blocks that have been created or changed have been processed.
+	// Account deletions are queued on the client API worker as part of that, so they might still be going on after.
+	Wait()
 }
 
 type processor struct {
-	tc           typeutils.TypeConverter
-	mediaManager media.Manager
-	clientWorker *worker.Worker[messages.FromClientAPI]
-	db           db.DB
+	tc                  typeutils.TypeConverter
+	mediaManager        media.Manager
+	transportController transport.Controller
+	clientWorker        *worker.Worker[messages.FromClientAPI]
+	db                  db.DB
+	sideEffects         sync.WaitGroup // domain block side effects still being processed
 }
 
 // New returns a new admin processor.
-func New(db db.DB, tc typeutils.TypeConverter, mediaManager media.Manager, clientWorker *worker.Worker[messages.FromClientAPI]) Processor {
-	return &processor{
-		tc:           tc,
-		mediaManager: mediaManager,
-		clientWorker: clientWorker,
-		db:           db,
+func New(db db.DB, tc typeutils.TypeConverter, mediaManager media.Manager, transportController transport.Controller, clientWorker *worker.Worker[messages.FromClientAPI]) Processor {
+	p := &processor{
+		tc:                  tc,
+		mediaManager:        mediaManager,
+		transportController: transportController,
+		clientWorker:        clientWorker,
+		db:                  db,
 	}
+
+	// let media integrity checks fetch damaged remote media again straight away
+	mediaManager.SetRecacheDataFunc(p.recacheData)
+
+	return p
+}
+
+func (p *processor) Wait() {
+	p.sideEffects.Wait()
 }
@@ -34,7 +34,11 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/text"
 )
 
-func (p *processor) DomainBlockCreate(ctx context.Context, account *gtsmodel.Account, domain string, obfuscate bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode) {
+func (p *processor) DomainBlockCreate(ctx context.Context, account *gtsmodel.Account, domain string, obfuscate bool, includeSubdomains bool, severity string, rejectMedia bool, publicComment string, privateComment string, subscriptionID string) (*apimodel.DomainBlock, gtserror.WithCode) {
+	if severity == "" {
+		severity = gtsmodel.DomainBlockSeveritySuspend
+	}
+
 	// first check if we already have a block -- if err == nil we already had a block so we can skip a whole lot of work
 	domainBlock := &gtsmodel.DomainBlock{}
 	err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, domainBlock)
@@ -58,7 +62,10 @@ func (p *processor) DomainBlockCreate(ctx context.Context, account *gtsmodel.Acc
 			PrivateComment:     text.RemoveHTML(privateComment),
 			PublicComment:      text.RemoveHTML(publicComment),
 			Obfuscate:          obfuscate,
+			IncludeSubdomains:  includeSubdomains,
 			SubscriptionID:     subscriptionID,
+			Severity:           severity,
+			RejectMedia:        rejectMedia,
 		}
 
 		// put the new block in the database
@@ -68,8 +75,41 @@ func (p *processor) DomainBlockCreate(ctx context.Context, account *gtsmodel.Acc
 				return nil, gtserror.NewErrorInternalError(fmt.Errorf("DomainBlockCreate: db error putting new domain block %s: %s", domain, err))
 			}
 		}
-		// process the side effects of the domain block asynchronously since it might take a while
-		go p.initiateDomainBlockSideEffects(context.Background(), account, domainBlock) // TODO: add this to a queuing system so it can retry/resume
+		// process the side effects of the domain block asynchronously since it might take a while;
+		// a silenced domain keeps its accounts and statuses, so there's nothing to process for those
+		if domainBlock.Severity == gtsmodel.DomainBlockSeveritySuspend {
+			p.goDomainBlockSideEffects(account, domainBlock) // TODO: add this to a queuing system so it can retry/resume
+		}
+	} else {
+		// there's already a block for this domain, but it might need to be widened to cover subdomains,
+		// escalated from a silence to a suspension, or made to reject media -- blocks are never narrowed or softened here
+		changed := false
+
+		if includeSubdomains && !domainBlock.IncludeSubdomains {
+			domainBlock.IncludeSubdomains = true
+			changed = true
+		}
+
+		if severity == gtsmodel.DomainBlockSeveritySuspend && domainBlock.Severity != gtsmodel.DomainBlockSeveritySuspend {
+			domainBlock.Severity = gtsmodel.DomainBlockSeveritySuspend
+			changed = true
+		}
+
+		if rejectMedia && !domainBlock.RejectMedia {
+			domainBlock.RejectMedia = true
+			changed = true
+		}
+
+		if changed {
+			domainBlock.UpdatedAt = time.Now()
+			if err := p.db.UpdateByPrimaryKey(ctx, domainBlock); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("DomainBlockCreate: db error updating domain block %s: %s", domain, err))
+			}
+
+			if domainBlock.Severity == gtsmodel.DomainBlockSeveritySuspend {
+				p.goDomainBlockSideEffects(account, domainBlock)
+			}
+		}
 	}
 
 	apiDomainBlock, err := p.tc.DomainBlockToAPIDomainBlock(ctx, domainBlock, false)
@@ -80,22 +120,49 @@ func (p *processor) DomainBlockCreate(ctx context.Context, account *gtsmodel.Acc
 	return apiDomainBlock, nil
 }
 
-// initiateDomainBlockSideEffects should be called asynchronously, to process the side effects of a domain block:
+// goDomainBlockSideEffects processes the side effects of a domain block in the background, keeping track of them so that Wait can wait for them.
+func (p *processor) goDomainBlockSideEffects(account *gtsmodel.Account, block *gtsmodel.DomainBlock) {
+	p.sideEffects.Add(1)
+	go func() {
+		defer p.sideEffects.Done()
+		p.initiateDomainBlockSideEffects(context.Background(), account, block)
+	}()
+}
+
+// initiateDomainBlockSideEffects should be called asynchronously, to process the side effects of a domain block.
+// If the block includes subdomains, then the side effects are processed for every known subdomain as well.
+func (p *processor) initiateDomainBlockSideEffects(ctx context.Context, account *gtsmodel.Account, block *gtsmodel.DomainBlock) {
+	domains := []string{block.Domain}
+
+	if block.IncludeSubdomains {
+		subdomains, err := p.db.GetKnownSubdomains(ctx, block.Domain)
+		if err != nil {
+			logrus.Errorf("initiateDomainBlockSideEffects: db error getting subdomains of %s: %s", block.Domain, err)
+		}
+		domains = append(domains, subdomains...)
+	}
+
+	for _, domain := range domains {
+		p.domainBlockProcessSideEffects(ctx, account, block, domain)
+	}
+}
+
+// domainBlockProcessSideEffects processes the side effects of a domain block for one domain that it covers:
 //
 // 1. Strip most info away from the instance entry for the domain.
 // 2. Delete the instance account for that instance if it exists.
 // 3. Select all accounts from this instance and pass them through the delete functionality of the processor.
-func (p *processor) initiateDomainBlockSideEffects(ctx context.Context, account *gtsmodel.Account, block *gtsmodel.DomainBlock) {
+func (p *processor) domainBlockProcessSideEffects(ctx context.Context, account *gtsmodel.Account, block *gtsmodel.DomainBlock, domain string) {
 	l := logrus.WithFields(logrus.Fields{
 		"func":   "domainBlockProcessSideEffects",
-		"domain": block.Domain,
+		"domain": domain,
 	})
 
 	l.Debug("processing domain block side effects")
 
 	// if we have an instance entry for this domain, update it with the new block ID and clear all fields
 	instance := &gtsmodel.Instance{}
-	if err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: block.Domain, CaseInsensitive: true}}, instance); err == nil {
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, instance); err == nil {
 		instance.Title = ""
 		instance.UpdatedAt = time.Now()
 		instance.SuspendedAt = time.Now()
@@ -114,7 +181,7 @@ func (p *processor) initiateDomainBlockSideEffects(ctx context.Context, account
 	}
 
 	// if we have an instance account for this instance, delete it
-	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "username", Value: block.Domain, CaseInsensitive: true}}, &gtsmodel.Account{}); err != nil {
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "username", Value: domain, CaseInsensitive: true}}, &gtsmodel.Account{}); err != nil {
 		l.Errorf("domainBlockProcessSideEffects: db error removing instance account: %s", err)
 	}
 
@@ -125,15 +192,15 @@ func (p *processor) initiateDomainBlockSideEffects(ctx context.Context, account
 
 selectAccountsLoop:
 	for {
-		accounts, err := p.db.GetInstanceAccounts(ctx, block.Domain, maxID, limit)
+		accounts, err := p.db.GetInstanceAccounts(ctx, domain, maxID, limit)
 		if err != nil {
 			if err == db.ErrNoEntries {
 				// no accounts left for this instance so we're done
-				l.Infof("domainBlockProcessSideEffects: done iterating through accounts for domain %s", block.Domain)
+				l.Infof("domainBlockProcessSideEffects: done iterating through accounts for domain %s", domain)
 				break selectAccountsLoop
 			}
 			// an actual error has occurred
-			l.Errorf("domainBlockProcessSideEffects: db error selecting accounts for domain %s: %s", block.Domain, err)
+			l.Errorf("domainBlockProcessSideEffects: db error selecting accounts for domain %s: %s", domain, err)
 			break selectAccountsLoop
 		}
 
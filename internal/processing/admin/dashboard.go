@@ -0,0 +1,121 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+)
+
+const (
+	// maxMeasuredWeeks is the most weeks that measures can be requested for at once.
+	maxMeasuredWeeks = 52
+	// maxTopRemoteDomains is the most remote domains that can be requested when getting dimensions.
+	maxTopRemoteDomains = 50
+	// week is how long a week is.
+	week = 7 * 24 * time.Hour
+)
+
+func (p *processor) MeasuresGet(ctx context.Context, weeks int) ([]*apimodel.AdminWeeklyMeasures, gtserror.WithCode) {
+	if weeks > maxMeasuredWeeks {
+		weeks = maxMeasuredWeeks
+	}
+	if weeks < 1 {
+		weeks = 1
+	}
+
+	// weeks start at midnight UTC on a Monday, and the current week is measured so far
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	thisWeek := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	measures := make([]*apimodel.AdminWeeklyMeasures, 0, weeks)
+	for i := weeks - 1; i >= 0; i-- {
+		since := thisWeek.Add(-time.Duration(i) * week)
+		until := since.Add(week)
+
+		newUsers, err := p.db.CountLocalUsersCreated(ctx, since, until)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting new users: %s", err))
+		}
+
+		statuses, err := p.db.CountLocalStatusesCreated(ctx, since, until)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting statuses: %s", err))
+		}
+
+		logins, err := p.db.CountLocalUsersSignedIn(ctx, since, until)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting logins: %s", err))
+		}
+
+		measures = append(measures, &apimodel.AdminWeeklyMeasures{
+			StartsAt: since.Format(time.RFC3339),
+			NewUsers: newUsers,
+			Statuses: statuses,
+			Logins:   logins,
+		})
+	}
+
+	return measures, nil
+}
+
+func (p *processor) DimensionsGet(ctx context.Context, limit int) (*apimodel.AdminDimensions, gtserror.WithCode) {
+	if limit > maxTopRemoteDomains {
+		limit = maxTopRemoteDomains
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	localBytes, err := p.db.GetMediaSize(ctx, false)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting size of local media: %s", err))
+	}
+
+	remoteBytes, err := p.db.GetMediaSize(ctx, true)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting size of remote media: %s", err))
+	}
+
+	traffic, err := p.db.GetTopRemoteDomains(ctx, time.Now().Add(-week), limit)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting top remote domains: %s", err))
+	}
+
+	topRemoteDomains := make([]apimodel.AdminDomainTraffic, 0, len(traffic))
+	for _, t := range traffic {
+		topRemoteDomains = append(topRemoteDomains, apimodel.AdminDomainTraffic{
+			Domain:   t.Domain,
+			Statuses: t.Statuses,
+		})
+	}
+
+	return &apimodel.AdminDimensions{
+		LocalBytesStored:  localBytes,
+		RemoteBytesStored: remoteBytes,
+		MediaJobsQueued:   p.mediaManager.JobsQueued(),
+		EmojiJobsQueued:   p.mediaManager.EmojiJobsQueued(),
+		TopRemoteDomains:  topRemoteDomains,
+	}, nil
+}
@@ -52,16 +52,18 @@ func (p *processor) DomainBlockDelete(ctx context.Context, account *gtsmodel.Acc
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	// remove the domain block reference from the instance, if we have an entry for it
-	i := &gtsmodel.Instance{}
+	// remove the domain block reference from the instances it covered, if we have entries for them;
+	// there can be more than one if the block included subdomains
+	instances := []*gtsmodel.Instance{}
 	if err := p.db.GetWhere(ctx, []db.Where{
-		{Key: "domain", Value: domainBlock.Domain, CaseInsensitive: true},
 		{Key: "domain_block_id", Value: id},
-	}, i); err == nil {
-		i.SuspendedAt = time.Time{}
-		i.DomainBlockID = ""
-		if err := p.db.UpdateByPrimaryKey(ctx, i); err != nil {
-			return nil, gtserror.NewErrorInternalError(fmt.Errorf("couldn't update database entry for instance %s: %s", domainBlock.Domain, err))
+	}, &instances); err == nil {
+		for _, i := range instances {
+			i.SuspendedAt = time.Time{}
+			i.DomainBlockID = ""
+			if err := p.db.UpdateByPrimaryKey(ctx, i); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("couldn't update database entry for instance %s: %s", i.Domain, err))
+			}
 		}
 	}
 
@@ -0,0 +1,91 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) DeliveryStatsGet(ctx context.Context) (*apimodel.AdminDeliveryStats, gtserror.WithCode) {
+	pending, failed, err := p.db.CountDeliveries(ctx)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting deliveries: %s", err))
+	}
+
+	return &apimodel.AdminDeliveryStats{
+		Pending: pending,
+		Failed:  failed,
+	}, nil
+}
+
+func (p *processor) DeliveriesGet(ctx context.Context, domain string, limit int) ([]*apimodel.AdminDelivery, gtserror.WithCode) {
+	deliveries, err := p.db.GetRecentDeliveries(ctx, strings.ToLower(domain), limit)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting deliveries: %s", err))
+	}
+
+	apiDeliveries := make([]*apimodel.AdminDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		apiDeliveries = append(apiDeliveries, apiDelivery(d))
+	}
+
+	return apiDeliveries, nil
+}
+
+func apiDelivery(d *gtsmodel.Delivery) *apimodel.AdminDelivery {
+	apiDelivery := &apimodel.AdminDelivery{
+		ID:            d.ID,
+		Inbox:         d.InboxURI,
+		Attempts:      d.Attempts,
+		LastError:     d.LastError,
+		LastAttemptAt: d.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if inbox, err := url.Parse(d.InboxURI); err == nil {
+		apiDelivery.Domain = inbox.Host
+	}
+
+	// the activity itself isn't shown, since it might not be public, but its type helps to tell what went missing
+	activity := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(d.Payload, &activity); err == nil {
+		apiDelivery.ActivityType = activity.Type
+	}
+
+	if !d.NextAttemptAt.IsZero() && d.FailedAt.IsZero() {
+		apiDelivery.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	if !d.FailedAt.IsZero() {
+		apiDelivery.FailedAt = d.FailedAt.Format(time.RFC3339)
+	}
+
+	return apiDelivery
+}
@@ -0,0 +1,149 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+)
+
+func (p *processor) EmailDomainBlocksGet(ctx context.Context) ([]*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	blocks := []*gtsmodel.EmailDomainBlock{}
+	if err := p.db.GetAll(ctx, &blocks); err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting email domain blocks: %s", err))
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Domain < blocks[j].Domain
+	})
+
+	apiBlocks := make([]*apimodel.AdminEmailDomainBlock, 0, len(blocks))
+	for _, b := range blocks {
+		apiBlocks = append(apiBlocks, apiEmailDomainBlock(b))
+	}
+
+	return apiBlocks, nil
+}
+
+func (p *processor) EmailDomainBlockGet(ctx context.Context, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	block, errWithCode := p.getEmailDomainBlock(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return apiEmailDomainBlock(block), nil
+}
+
+func (p *processor) EmailDomainBlockCreate(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminEmailDomainBlockCreateRequest) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	// be forgiving about people pasting in an email address, or the part of one after the @
+	domain := strings.ToLower(strings.TrimSpace(form.Domain))
+	if i := strings.LastIndex(domain, "@"); i != -1 {
+		domain = domain[i+1:]
+	}
+	domain = strings.TrimSuffix(domain, ".")
+
+	if domain == "" {
+		err := errors.New("empty domain provided")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	existing := &gtsmodel.EmailDomainBlock{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain, CaseInsensitive: true}}, existing); err == nil {
+		err := fmt.Errorf("email domain %s is already blocked", domain)
+		return nil, gtserror.NewErrorConflict(err, err.Error())
+	} else if err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking for existing email domain block: %s", err))
+	}
+
+	blockID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	block := &gtsmodel.EmailDomainBlock{
+		ID:                 blockID,
+		Domain:             domain,
+		CreatedByAccountID: account.ID,
+	}
+
+	if err := validate.Struct(block); err != nil {
+		err := fmt.Errorf("%s is not a valid domain", domain)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := p.db.Put(ctx, block); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting email domain block: %s", err))
+	}
+
+	return apiEmailDomainBlock(block), nil
+}
+
+func (p *processor) EmailDomainBlockDelete(ctx context.Context, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode) {
+	block, errWithCode := p.getEmailDomainBlock(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteByID(ctx, id, block); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting email domain block: %s", err))
+	}
+
+	return apiEmailDomainBlock(block), nil
+}
+
+// getEmailDomainBlock gets the email domain block with the given ID.
+func (p *processor) getEmailDomainBlock(ctx context.Context, id string) (*gtsmodel.EmailDomainBlock, gtserror.WithCode) {
+	block := &gtsmodel.EmailDomainBlock{}
+	if err := p.db.GetByID(ctx, id, block); err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	return block, nil
+}
+
+func apiEmailDomainBlock(b *gtsmodel.EmailDomainBlock) *apimodel.AdminEmailDomainBlock {
+	apiBlock := &apimodel.AdminEmailDomainBlock{
+		ID:        b.ID,
+		Domain:    b.Domain,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+		CreatedBy: b.CreatedByAccountID,
+		Attempts:  b.Attempts,
+	}
+
+	if !b.LastAttemptAt.IsZero() {
+		lastAttemptAt := b.LastAttemptAt.Format(time.RFC3339)
+		apiBlock.LastAttemptAt = &lastAttemptAt
+	}
+
+	return apiBlock
+}
@@ -19,44 +19,38 @@
 package admin
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"mime/multipart"
 
+	"github.com/sirupsen/logrus"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/blocklist"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 // DomainBlocksImport handles the import of a bunch of domain blocks at once, by calling the DomainBlockCreate function for each domain in the provided file.
+// The file can either be JSON, as exported by GoToSocial, or CSV, as exported by Mastodon.
 func (p *processor) DomainBlocksImport(ctx context.Context, account *gtsmodel.Account, domains *multipart.FileHeader) ([]*apimodel.DomainBlock, gtserror.WithCode) {
-
 	f, err := domains.Open()
 	if err != nil {
 		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("DomainBlocksImport: error opening attachment: %s", err))
 	}
-	buf := new(bytes.Buffer)
-	size, err := io.Copy(buf, f)
+	defer f.Close()
+
+	entries, skipped, err := blocklist.Parse(f)
 	if err != nil {
-		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("DomainBlocksImport: error reading attachment: %s", err))
-	}
-	if size == 0 {
-		return nil, gtserror.NewErrorBadRequest(errors.New("DomainBlocksImport: could not read provided attachment: size 0 bytes"))
+		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("DomainBlocksImport: could not read provided attachment: %s", err))
 	}
 
-	d := []apimodel.DomainBlock{}
-	if err := json.Unmarshal(buf.Bytes(), &d); err != nil {
-		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("DomainBlocksImport: could not read provided attachment: %s", err))
+	if len(skipped) != 0 {
+		logrus.Debugf("DomainBlocksImport: skipping %d domains that can't be blocked: %v", len(skipped), skipped)
 	}
 
 	blocks := []*apimodel.DomainBlock{}
-	for _, d := range d {
-		block, err := p.DomainBlockCreate(ctx, account, d.Domain, false, d.PublicComment, "", "")
-
+	for _, e := range entries {
+		block, err := p.DomainBlockCreate(ctx, account, e.Domain, e.Obfuscate, e.IncludeSubdomains, e.Severity, e.RejectMedia, e.PublicComment, e.PrivateComment, "")
 		if err != nil {
 			return nil, err
 		}
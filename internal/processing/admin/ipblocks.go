@@ -0,0 +1,243 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+func (p *processor) IPBlocksGet(ctx context.Context) ([]*apimodel.AdminIPBlock, gtserror.WithCode) {
+	blocks, err := p.db.GetIPBlocks(ctx)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting ip blocks: %s", err))
+	}
+
+	apiBlocks := make([]*apimodel.AdminIPBlock, 0, len(blocks))
+	for _, b := range blocks {
+		// expired blocks are just waiting to be cleaned up
+		if b.Expired() {
+			continue
+		}
+		apiBlocks = append(apiBlocks, apiIPBlock(b))
+	}
+
+	return apiBlocks, nil
+}
+
+func (p *processor) IPBlockGet(ctx context.Context, id string) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	block, errWithCode := p.getIPBlock(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return apiIPBlock(block), nil
+}
+
+func (p *processor) IPBlockCreate(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminIPBlockCreateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	ipRange, errWithCode := p.checkIPBlockRange(ctx, form.IP, "")
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	severity := form.Severity
+	if severity == "" {
+		severity = gtsmodel.IPBlockSeverityNoAccess
+	}
+	if errWithCode := checkIPBlockSeverity(severity); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	blockID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	block := &gtsmodel.IPBlock{
+		ID:                 blockID,
+		IP:                 ipRange,
+		Severity:           severity,
+		Comment:            text.RemoveHTML(form.Comment),
+		ExpiresAt:          ipBlockExpiresAt(form.ExpiresIn),
+		CreatedByAccountID: account.ID,
+	}
+
+	if err := p.db.PutIPBlock(ctx, block); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting ip block: %s", err))
+	}
+
+	return apiIPBlock(block), nil
+}
+
+func (p *processor) IPBlockUpdate(ctx context.Context, id string, form *apimodel.AdminIPBlockUpdateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	block, errWithCode := p.getIPBlock(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if form.IP != nil {
+		ipRange, errWithCode := p.checkIPBlockRange(ctx, *form.IP, block.ID)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		block.IP = ipRange
+	}
+
+	if form.Severity != nil {
+		if errWithCode := checkIPBlockSeverity(*form.Severity); errWithCode != nil {
+			return nil, errWithCode
+		}
+		block.Severity = *form.Severity
+	}
+
+	if form.Comment != nil {
+		block.Comment = text.RemoveHTML(*form.Comment)
+	}
+
+	if form.ExpiresIn != nil {
+		block.ExpiresAt = ipBlockExpiresAt(*form.ExpiresIn)
+	}
+
+	block.UpdatedAt = time.Now()
+	if err := p.db.UpdateIPBlock(ctx, block); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating ip block %s: %s", block.ID, err))
+	}
+
+	return apiIPBlock(block), nil
+}
+
+func (p *processor) IPBlockDelete(ctx context.Context, id string) (*apimodel.AdminIPBlock, gtserror.WithCode) {
+	block, errWithCode := p.getIPBlock(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteIPBlockByID(ctx, id); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting ip block: %s", err))
+	}
+
+	return apiIPBlock(block), nil
+}
+
+// getIPBlock gets the unexpired IP block with the given ID.
+func (p *processor) getIPBlock(ctx context.Context, id string) (*gtsmodel.IPBlock, gtserror.WithCode) {
+	block := &gtsmodel.IPBlock{}
+	if err := p.db.GetByID(ctx, id, block); err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	if block.Expired() {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("ip block %s has expired", id))
+	}
+
+	return block, nil
+}
+
+// checkIPBlockRange parses the given IP address or range into CIDR notation, and makes sure
+// that it isn't already blocked by any IP block other than the one with the given ID.
+func (p *processor) checkIPBlockRange(ctx context.Context, ip string, blockID string) (string, gtserror.WithCode) {
+	ip = strings.TrimSpace(ip)
+	if ip == "" {
+		err := errors.New("no ip address or range provided")
+		return "", gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// a single address is blocked as a range that only contains that address
+	if !strings.Contains(ip, "/") {
+		addr := net.ParseIP(ip)
+		if addr == nil {
+			err := fmt.Errorf("%s is not a valid ip address or range", ip)
+			return "", gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		bits := 8 * net.IPv6len
+		if addr.To4() != nil {
+			bits = 8 * net.IPv4len
+		}
+		ip = fmt.Sprintf("%s/%d", addr, bits)
+	}
+
+	_, ipNet, err := net.ParseCIDR(ip)
+	if err != nil {
+		err := fmt.Errorf("%s is not a valid ip address or range", ip)
+		return "", gtserror.NewErrorBadRequest(err, err.Error())
+	}
+	ipRange := ipNet.String()
+
+	existing := &gtsmodel.IPBlock{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "ip", Value: ipRange}}, existing); err == nil {
+		if existing.ID != blockID {
+			err := fmt.Errorf("ip range %s is already blocked", ipRange)
+			return "", gtserror.NewErrorConflict(err, err.Error())
+		}
+	} else if err != db.ErrNoEntries {
+		return "", gtserror.NewErrorInternalError(fmt.Errorf("error checking for existing ip block: %s", err))
+	}
+
+	return ipRange, nil
+}
+
+// checkIPBlockSeverity makes sure that the given IP block severity is one we know about.
+func checkIPBlockSeverity(severity string) gtserror.WithCode {
+	switch severity {
+	case gtsmodel.IPBlockSeveritySignUpRequiresApproval, gtsmodel.IPBlockSeveritySignUpBlock, gtsmodel.IPBlockSeverityNoAccess:
+		return nil
+	}
+	err := fmt.Errorf("ip block severity must be one of %s, %s, or %s", gtsmodel.IPBlockSeveritySignUpRequiresApproval, gtsmodel.IPBlockSeveritySignUpBlock, gtsmodel.IPBlockSeverityNoAccess)
+	return gtserror.NewErrorBadRequest(err, err.Error())
+}
+
+// ipBlockExpiresAt returns the time that an IP block set to expire in the given number of seconds from now
+// should expire at, or the zero time if it shouldn't expire.
+func ipBlockExpiresAt(expiresIn int) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
+
+func apiIPBlock(b *gtsmodel.IPBlock) *apimodel.AdminIPBlock {
+	apiBlock := &apimodel.AdminIPBlock{
+		ID:        b.ID,
+		IP:        b.IP,
+		Severity:  b.Severity,
+		Comment:   b.Comment,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+	}
+
+	if !b.ExpiresAt.IsZero() {
+		expiresAt := b.ExpiresAt.Format(time.RFC3339)
+		apiBlock.ExpiresAt = &expiresAt
+	}
+
+	return apiBlock
+}
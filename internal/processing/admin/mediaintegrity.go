@@ -0,0 +1,51 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+)
+
+func (p *processor) MediaIntegrityGet(ctx context.Context) (*apimodel.AdminMediaIntegrityReport, gtserror.WithCode) {
+	report := p.mediaManager.LastIntegrityReport()
+	if report == nil {
+		err := errors.New("media integrity hasn't been checked yet")
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return &apimodel.AdminMediaIntegrityReport{
+		StartedAt:        report.Started.Format(time.RFC3339),
+		FinishedAt:       report.Finished.Format(time.RFC3339),
+		Checked:          report.Checked,
+		Backfilled:       report.Backfilled,
+		Missing:          report.Missing,
+		Corrupted:        report.Corrupted,
+		Requeued:         report.Requeued,
+		CheckedEmojis:    report.CheckedEmojis,
+		BackfilledEmojis: report.BackfilledEmojis,
+		MissingEmojis:    report.MissingEmojis,
+		CorruptedEmojis:  report.CorruptedEmojis,
+		RequeuedEmojis:   report.RequeuedEmojis,
+	}, nil
+}
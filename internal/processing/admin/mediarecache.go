@@ -0,0 +1,82 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+)
+
+func (p *processor) MediaRecache(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminMediaRecacheRequest) (*apimodel.AdminMediaRecacheResponse, gtserror.WithCode) {
+	if form.AccountID != "" {
+		if _, err := p.db.GetAccountByID(ctx, form.AccountID); err != nil {
+			if err == db.ErrNoEntries {
+				return nil, gtserror.NewErrorNotFound(fmt.Errorf("account %s not found", form.AccountID), "account not found")
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting account %s: %s", form.AccountID, err))
+		}
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(form.Domain))
+
+	queued, err := p.mediaManager.RecacheRemote(ctx, form.AccountID, domain, p.recacheData)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error recaching media: %s", err))
+	}
+
+	return &apimodel.AdminMediaRecacheResponse{
+		Queued: queued,
+	}, nil
+}
+
+// recacheData returns a function that fetches the remote media at the given URL again. The media
+// is fetched using the instance account, since this isn't being done on behalf of any one user.
+func (p *processor) recacheData(remoteURL string) media.DataFunc {
+	return func(innerCtx context.Context) (io.Reader, int, error) {
+		remoteMediaIRI, err := url.Parse(remoteURL)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error parsing remote media iri %s: %s", remoteURL, err)
+		}
+
+		// media from silenced domains, or domains whose media is rejected, is never cached
+		rejected, err := p.db.IsURIMediaRejected(innerCtx, remoteMediaIRI)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error checking whether media from domain %s is rejected: %s", remoteMediaIRI.Host, err)
+		}
+		if rejected {
+			return nil, 0, fmt.Errorf("media from domain %s is rejected, so it isn't cached", remoteMediaIRI.Host)
+		}
+
+		transport, err := p.transportController.NewTransportForInstance(innerCtx)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return transport.DereferenceMedia(innerCtx, remoteMediaIRI)
+	}
+}
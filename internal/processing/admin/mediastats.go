@@ -0,0 +1,64 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+)
+
+func (p *processor) MediaStatsGet(ctx context.Context) (*apimodel.AdminMediaStats, gtserror.WithCode) {
+	localBytes, err := p.db.GetMediaSize(ctx, false)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting size of local media: %s", err))
+	}
+
+	remoteBytes, err := p.db.GetMediaSize(ctx, true)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting size of remote media: %s", err))
+	}
+
+	stats := &apimodel.AdminMediaStats{
+		NumWorkers:         p.mediaManager.NumWorkers(),
+		QueueSize:          p.mediaManager.QueueSize(),
+		JobsQueued:         p.mediaManager.JobsQueued(),
+		ActiveWorkers:      p.mediaManager.ActiveWorkers(),
+		EmojiNumWorkers:    p.mediaManager.EmojiNumWorkers(),
+		EmojiQueueSize:     p.mediaManager.EmojiQueueSize(),
+		EmojiJobsQueued:    p.mediaManager.EmojiJobsQueued(),
+		EmojiActiveWorkers: p.mediaManager.EmojiActiveWorkers(),
+		LocalBytesStored:   localBytes,
+		RemoteBytesStored:  remoteBytes,
+	}
+
+	if report := p.mediaManager.LastPruneReport(); report != nil {
+		stats.LastPrune = &apimodel.AdminMediaPruneReport{
+			StartedAt:   report.Started.Format(time.RFC3339),
+			FinishedAt:  report.Finished.Format(time.RFC3339),
+			Pruned:      report.Pruned,
+			PrunedBytes: report.PrunedBytes,
+		}
+	}
+
+	return stats, nil
+}
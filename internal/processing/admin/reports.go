@@ -0,0 +1,169 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+func (p *processor) ReportsGet(ctx context.Context, account *gtsmodel.Account, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode) {
+	reports, err := p.db.GetReports(ctx, resolved, accountID, targetAccountID, maxID, sinceID, limit)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting reports: %s", err))
+	}
+
+	apiReports := make([]*apimodel.AdminReportInfo, 0, len(reports))
+	for _, r := range reports {
+		apiReport, err := p.tc.ReportToAdminAPIReport(ctx, r, account)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting report to api representation: %s", err))
+		}
+		apiReports = append(apiReports, apiReport)
+	}
+
+	return apiReports, nil
+}
+
+func (p *processor) ReportGet(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiAdminReport(ctx, report, account)
+}
+
+func (p *processor) ReportAssign(ctx context.Context, account *gtsmodel.Account, id string, assign bool) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if assign {
+		report.AssignedAccountID = account.ID
+	} else {
+		report.AssignedAccountID = ""
+	}
+
+	return p.updateReport(ctx, report, account)
+}
+
+func (p *processor) ReportResolve(ctx context.Context, account *gtsmodel.Account, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if !report.ActionTakenAt.IsZero() {
+		err := errors.New("report was already resolved")
+		return nil, gtserror.NewErrorConflict(err, err.Error())
+	}
+
+	report.ActionTaken = form.ActionTakenComment
+	report.ActionTakenAt = time.Now()
+	report.ActionTakenByAccountID = account.ID
+
+	apiReport, errWithCode := p.updateReport(ctx, report, account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// reports about remote accounts can be sent on to their instance as a flag, which marks the report as forwarded
+	if form.Forward && !report.Forwarded {
+		targetAccount, err := p.db.GetAccountByID(ctx, report.TargetAccountID)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting report target account: %s", err))
+		}
+
+		if targetAccount.Domain != "" {
+			// queue a copy, since marking the report as forwarded happens in the background
+			forwarded := *report
+			forwarded.TargetAccount = targetAccount
+			p.clientWorker.Queue(messages.FromClientAPI{
+				APObjectType:   ap.ActivityFlag,
+				APActivityType: ap.ActivityCreate,
+				GTSModel:       &forwarded,
+				OriginAccount:  account,
+				TargetAccount:  targetAccount,
+			})
+		}
+	}
+
+	return apiReport, nil
+}
+
+func (p *processor) ReportReopen(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report, errWithCode := p.getReport(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if report.ActionTakenAt.IsZero() {
+		err := errors.New("report is not resolved")
+		return nil, gtserror.NewErrorConflict(err, err.Error())
+	}
+
+	report.ActionTaken = ""
+	report.ActionTakenAt = time.Time{}
+	report.ActionTakenByAccountID = ""
+
+	return p.updateReport(ctx, report, account)
+}
+
+// getReport gets the report with the given ID.
+func (p *processor) getReport(ctx context.Context, id string) (*gtsmodel.Report, gtserror.WithCode) {
+	report, err := p.db.GetReportByID(ctx, id)
+	if err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	return report, nil
+}
+
+// updateReport saves the given changed report, and returns it as seen by the given moderator.
+func (p *processor) updateReport(ctx context.Context, report *gtsmodel.Report, account *gtsmodel.Account) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	report.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, report); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating report: %s", err))
+	}
+
+	return p.apiAdminReport(ctx, report, account)
+}
+
+func (p *processor) apiAdminReport(ctx context.Context, report *gtsmodel.Report, account *gtsmodel.Account) (*apimodel.AdminReportInfo, gtserror.WithCode) {
+	apiReport, err := p.tc.ReportToAdminAPIReport(ctx, report, account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting report to api representation: %s", err))
+	}
+
+	return apiReport, nil
+}
@@ -0,0 +1,151 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+func (p *processor) RulesGet(ctx context.Context) ([]*apimodel.AdminRule, gtserror.WithCode) {
+	rules, err := p.db.GetActiveRules(ctx)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting rules: %s", err))
+	}
+
+	apiRules := make([]*apimodel.AdminRule, 0, len(rules))
+	for _, r := range rules {
+		apiRules = append(apiRules, apiAdminRule(r))
+	}
+
+	return apiRules, nil
+}
+
+func (p *processor) RuleGet(ctx context.Context, id string) (*apimodel.AdminRule, gtserror.WithCode) {
+	rule, errWithCode := p.getRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return apiAdminRule(rule), nil
+}
+
+func (p *processor) RuleCreate(ctx context.Context, form *apimodel.AdminRuleCreateRequest) (*apimodel.AdminRule, gtserror.WithCode) {
+	text := strings.TrimSpace(form.Text)
+	if text == "" {
+		err := errors.New("rule text was empty")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	ruleID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	rule := &gtsmodel.Rule{
+		ID:       ruleID,
+		Text:     text,
+		Priority: form.Priority,
+	}
+
+	if err := p.db.Put(ctx, rule); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting rule: %s", err))
+	}
+
+	return apiAdminRule(rule), nil
+}
+
+func (p *processor) RuleUpdate(ctx context.Context, id string, form *apimodel.AdminRuleUpdateRequest) (*apimodel.AdminRule, gtserror.WithCode) {
+	rule, errWithCode := p.getRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if form.Text != nil {
+		text := strings.TrimSpace(*form.Text)
+		if text == "" {
+			err := errors.New("rule text was empty")
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		rule.Text = text
+	}
+
+	if form.Priority != nil {
+		rule.Priority = *form.Priority
+	}
+
+	rule.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, rule); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating rule: %s", err))
+	}
+
+	return apiAdminRule(rule), nil
+}
+
+func (p *processor) RuleDelete(ctx context.Context, id string) (*apimodel.AdminRule, gtserror.WithCode) {
+	rule, errWithCode := p.getRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// the rule is only marked as deleted, since reports can still point to it
+	rule.DeletedAt = time.Now()
+	rule.UpdatedAt = rule.DeletedAt
+	if err := p.db.UpdateByPrimaryKey(ctx, rule); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting rule: %s", err))
+	}
+
+	return apiAdminRule(rule), nil
+}
+
+// getRule gets the rule with the given ID, as long as it hasn't been deleted.
+func (p *processor) getRule(ctx context.Context, id string) (*gtsmodel.Rule, gtserror.WithCode) {
+	rule := &gtsmodel.Rule{}
+	if err := p.db.GetByID(ctx, id, rule); err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	if !rule.DeletedAt.IsZero() {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("rule %s was deleted", id))
+	}
+
+	return rule, nil
+}
+
+func apiAdminRule(r *gtsmodel.Rule) *apimodel.AdminRule {
+	return &apimodel.AdminRule{
+		ID:        r.ID,
+		Text:      r.Text,
+		Priority:  r.Priority,
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,107 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (p *processor) UnreachableDomainsGet(ctx context.Context) ([]*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	domains := []*gtsmodel.UnreachableDomain{}
+	if err := p.db.GetAll(ctx, &domains); err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting unreachable domains: %s", err))
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Domain < domains[j].Domain
+	})
+
+	apiDomains := make([]*apimodel.AdminUnreachableDomain, 0, len(domains))
+	for _, d := range domains {
+		apiDomains = append(apiDomains, apiUnreachableDomain(d))
+	}
+
+	return apiDomains, nil
+}
+
+func (p *processor) UnreachableDomainDelete(ctx context.Context, id string) (*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	domain := &gtsmodel.UnreachableDomain{}
+	if err := p.db.GetByID(ctx, id, domain); err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	// this clears the domain out of the database too
+	if err := p.transportController.ResumeDeliveries(ctx, domain.Domain); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiUnreachableDomain(domain), nil
+}
+
+func (p *processor) UnreachableDomainCreate(ctx context.Context, form *apimodel.AdminUnreachableDomainCreateRequest) (*apimodel.AdminUnreachableDomain, gtserror.WithCode) {
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(form.Domain)), ".")
+	if domain == "" {
+		err := errors.New("empty domain provided")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if domain == viper.GetString(config.Keys.Host) || domain == viper.GetString(config.Keys.AccountDomain) {
+		err := fmt.Errorf("domain %s is this instance's own domain", domain)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	d, err := p.transportController.SuspendDeliveries(ctx, domain)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiUnreachableDomain(d), nil
+}
+
+func apiUnreachableDomain(d *gtsmodel.UnreachableDomain) *apimodel.AdminUnreachableDomain {
+	apiDomain := &apimodel.AdminUnreachableDomain{
+		ID:               d.ID,
+		Domain:           d.Domain,
+		FailingSince:     d.CreatedAt.Format(time.RFC3339),
+		Failures:         d.Failures,
+		LastError:        d.LastError,
+		SuspendedByAdmin: d.SuspendedByAdmin,
+	}
+
+	if !d.SuspendedAt.IsZero() {
+		apiDomain.SuspendedAt = d.SuspendedAt.Format(time.RFC3339)
+	}
+
+	return apiDomain
+}
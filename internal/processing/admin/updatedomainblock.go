@@ -0,0 +1,89 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+func (p *processor) DomainBlockUpdate(ctx context.Context, account *gtsmodel.Account, id string, form *apimodel.DomainBlockUpdateRequest) (*apimodel.DomainBlock, gtserror.WithCode) {
+	domainBlock := &gtsmodel.DomainBlock{}
+
+	if err := p.db.GetByID(ctx, id, domainBlock); err != nil {
+		if err != db.ErrNoEntries {
+			// something has gone really wrong
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		// there are no entries for this ID
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no entry for ID %s", id))
+	}
+
+	escalated := false
+	if form.Severity != nil && *form.Severity != domainBlock.Severity {
+		// the accounts of a suspended domain have already been deleted, so there's no going back to a silence
+		if domainBlock.Severity == gtsmodel.DomainBlockSeveritySuspend {
+			err := errors.New("a suspension can't be changed to a silence: delete the domain block and create a new one instead")
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+		domainBlock.Severity = *form.Severity
+		escalated = domainBlock.Severity == gtsmodel.DomainBlockSeveritySuspend
+	}
+
+	if form.RejectMedia != nil {
+		domainBlock.RejectMedia = *form.RejectMedia
+	}
+
+	if form.Obfuscate != nil {
+		domainBlock.Obfuscate = *form.Obfuscate
+	}
+
+	if form.PrivateComment != nil {
+		domainBlock.PrivateComment = text.RemoveHTML(*form.PrivateComment)
+	}
+
+	if form.PublicComment != nil {
+		domainBlock.PublicComment = text.RemoveHTML(*form.PublicComment)
+	}
+
+	domainBlock.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, domainBlock); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("DomainBlockUpdate: db error updating domain block %s: %s", domainBlock.Domain, err))
+	}
+
+	// a silence that's escalated to a suspension has the same side effects as a new suspension
+	if escalated {
+		p.goDomainBlockSideEffects(account, domainBlock)
+	}
+
+	apiDomainBlock, err := p.tc.DomainBlockToAPIDomainBlock(ctx, domainBlock, false)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("DomainBlockUpdate: error converting domain block to frontend/api representation %s: %s", domainBlock.Domain, err))
+	}
+
+	return apiDomainBlock, nil
+}
@@ -20,9 +20,14 @@ package processing
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
@@ -32,7 +37,7 @@ func (p *processor) AppCreate(ctx context.Context, authed *oauth.Auth, form *api
 	// set default 'read' for scopes if it's not set
 	var scopes string
 	if form.Scopes == "" {
-		scopes = "read"
+		scopes = string(oauth.DefaultScope)
 	} else {
 		scopes = form.Scopes
 	}
@@ -85,3 +90,57 @@ func (p *processor) AppCreate(ctx context.Context, authed *oauth.Auth, form *api
 
 	return apiApp, nil
 }
+
+func (p *processor) AppVerifyCredentials(ctx context.Context, authed *oauth.Auth) (*apimodel.Application, gtserror.WithCode) {
+	apiApp, err := p.tc.AppToAPIAppPublic(ctx, authed.Application)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting application to api: %s", err))
+	}
+
+	// give the app the key it'll need to set up web push subscriptions
+	keyPair, err := p.db.GetVAPIDKeyPair(ctx)
+	if err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting vapid key pair: %s", err))
+		}
+	} else {
+		apiApp.VapidKey = keyPair.PublicKey
+	}
+
+	return apiApp, nil
+}
+
+func (p *processor) AppRevokeToken(ctx context.Context, clientID string, clientSecret string, access string) gtserror.WithCode {
+	// make sure the client is who it says it is
+	client := &gtsmodel.Client{}
+	if err := p.db.GetByID(ctx, clientID, client); err != nil && err != db.ErrNoEntries {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error getting client %s: %s", clientID, err))
+	}
+	if client.ID == "" || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(clientSecret)) != 1 {
+		return gtserror.NewErrorNotAuthorized(errors.New("client authentication failed"), "client authentication failed")
+	}
+
+	token := &gtsmodel.Token{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "access", Value: access}}, token); err != nil {
+		if err != db.ErrNoEntries {
+			return gtserror.NewErrorInternalError(fmt.Errorf("error getting access token: %s", err))
+		}
+		// already gone, which is what the client wanted anyway
+		return nil
+	}
+
+	if token.ClientID != client.ID {
+		return gtserror.NewErrorForbidden(errors.New("token belongs to another client"), "you are not authorized to revoke this token")
+	}
+
+	// a web push subscription can't be used anymore without the token it was created with
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "token_id", Value: token.ID}}, &gtsmodel.WebPushSubscription{}); err != nil && err != db.ErrNoEntries {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting web push subscription of token %s: %s", token.ID, err))
+	}
+
+	if err := p.db.DeleteByID(ctx, token.ID, token); err != nil && err != db.ErrNoEntries {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting token %s: %s", token.ID, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+func (p *processor) ConversationsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int) (*apimodel.ConversationsResponse, gtserror.WithCode) {
+	conversations, err := p.db.GetConversationsByAccountID(ctx, authed.Account.ID, maxID, sinceID, minID, limit)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting conversations: %s", err))
+	}
+
+	resp := &apimodel.ConversationsResponse{
+		Conversations: []*apimodel.Conversation{},
+	}
+
+	for _, conversation := range conversations {
+		conversation.Account = authed.Account
+		apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation)
+		if err != nil {
+			logrus.Debugf("ConversationsGet: error converting conversation %s to api representation: %s", conversation.ID, err)
+			continue
+		}
+		resp.Conversations = append(resp.Conversations, apiConversation)
+	}
+
+	// conversations are paged by their last status, since that's what they're ordered by
+	if len(conversations) != 0 {
+		protocol := viper.GetString(config.Keys.Protocol)
+		host := viper.GetString(config.Keys.Host)
+
+		nextLink := &url.URL{
+			Scheme:   protocol,
+			Host:     host,
+			Path:     "/api/v1/conversations",
+			RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, conversations[len(conversations)-1].LastStatusID),
+		}
+		next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+		prevLink := &url.URL{
+			Scheme:   protocol,
+			Host:     host,
+			Path:     "/api/v1/conversations",
+			RawQuery: fmt.Sprintf("limit=%d&min_id=%s", limit, conversations[0].LastStatusID),
+		}
+		prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+		resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
+	}
+
+	return resp, nil
+}
+
+func (p *processor) ConversationRead(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Conversation, gtserror.WithCode) {
+	conversation, errWithCode := p.getOwnConversation(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if !conversation.Read {
+		conversation.Read = true
+		conversation.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, conversation); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating conversation: %s", err))
+		}
+	}
+
+	apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting conversation %s to api representation: %s", conversation.ID, err))
+	}
+
+	return apiConversation, nil
+}
+
+func (p *processor) ConversationDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	conversation, errWithCode := p.getOwnConversation(ctx, authed, id)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	// this only removes the conversation for the requesting account; the statuses
+	// stay where they are, and a new status will start the conversation again
+	if err := p.db.DeleteConversationByID(ctx, conversation.ID); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting conversation: %s", err))
+	}
+
+	return nil
+}
+
+// getOwnConversation gets the conversation with the given id, or a 404 if it doesn't belong to the requesting account.
+func (p *processor) getOwnConversation(ctx context.Context, authed *oauth.Auth, id string) (*gtsmodel.Conversation, gtserror.WithCode) {
+	conversation, err := p.db.GetConversationByID(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("conversation %s not found", id)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting conversation: %s", err))
+	}
+
+	if conversation.AccountID != authed.Account.ID {
+		err := fmt.Errorf("conversation %s not found", id)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	conversation.Account = authed.Account
+	return conversation, nil
+}
+
+// updateConversations adds the given status to the conversation of each local account that it was
+// sent to or written by, if it's a direct message, and streams the updated conversations to them.
+func (p *processor) updateConversations(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Visibility != gtsmodel.VisibilityDirect || status.BoostOfID != "" {
+		return nil
+	}
+
+	mentions := status.Mentions
+	if mentions == nil {
+		var err error
+		mentions, err = p.db.GetMentions(ctx, status.MentionIDs)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error getting mentions of status %s: %s", status.ID, err)
+		}
+	}
+
+	// everyone taking part in the conversation, author first
+	participantIDs := []string{status.AccountID}
+	seen := map[string]bool{status.AccountID: true}
+	for _, m := range mentions {
+		if seen[m.TargetAccountID] {
+			continue
+		}
+		participantIDs = append(participantIDs, m.TargetAccountID)
+		seen[m.TargetAccountID] = true
+	}
+
+	for _, participantID := range participantIDs {
+		participant, err := p.db.GetAccountByID(ctx, participantID)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error getting account %s: %s", participantID, err)
+		}
+
+		// only local accounts have conversations here
+		if participant.Domain != "" {
+			continue
+		}
+
+		visible, err := p.filter.StatusVisible(ctx, status, participant)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error checking visibility of status %s for account %s: %s", status.ID, participant.ID, err)
+		}
+		if !visible {
+			continue
+		}
+
+		otherAccountIDs := []string{}
+		for _, id := range participantIDs {
+			if id != participant.ID {
+				otherAccountIDs = append(otherAccountIDs, id)
+			}
+		}
+
+		conversation, err := p.addStatusToConversation(ctx, status, participant, otherAccountIDs)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error adding status %s to conversation of account %s: %s", status.ID, participant.ID, err)
+		}
+
+		apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation)
+		if err != nil {
+			return fmt.Errorf("updateConversations: error converting conversation %s to api representation: %s", conversation.ID, err)
+		}
+
+		if err := p.streamingProcessor.StreamConversationToAccount(apiConversation, participant); err != nil {
+			return fmt.Errorf("updateConversations: error streaming conversation %s: %s", conversation.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// addStatusToConversation adds the given status to the conversation between the given account and the
+// accounts with the given ids, starting the conversation if there isn't one already, and returns the conversation.
+func (p *processor) addStatusToConversation(ctx context.Context, status *gtsmodel.Status, account *gtsmodel.Account, otherAccountIDs []string) (*gtsmodel.Conversation, error) {
+	// the conversation is unread for everyone except whoever wrote the status
+	read := status.AccountID == account.ID
+
+	conversation, err := p.db.GetConversationByOtherAccountIDs(ctx, account.ID, otherAccountIDs)
+	switch {
+	case err == db.ErrNoEntries:
+		conversationID, err := id.NewULID()
+		if err != nil {
+			return nil, err
+		}
+
+		conversation = &gtsmodel.Conversation{
+			ID:               conversationID,
+			AccountID:        account.ID,
+			OtherAccountIDs:  otherAccountIDs,
+			OtherAccountsKey: gtsmodel.ConversationOtherAccountsKey(otherAccountIDs),
+			LastStatusID:     status.ID,
+			LastStatus:       status,
+			Read:             read,
+		}
+		if err := p.db.Put(ctx, conversation); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case status.ID > conversation.LastStatusID:
+		conversation.LastStatusID = status.ID
+		conversation.LastStatus = status
+		conversation.Read = read
+		conversation.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, conversation); err != nil {
+			return nil, err
+		}
+	}
+	conversation.Account = account
+
+	if err := p.db.Put(ctx, &gtsmodel.ConversationToStatus{
+		ConversationID: conversation.ID,
+		StatusID:       status.ID,
+	}); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if !errors.As(err, &alreadyExistsError) {
+			return nil, err
+		}
+	}
+
+	return conversation, nil
+}
+
+// deleteStatusFromConversations removes the given status from any conversations it's in. Conversations
+// that it was the last status of fall back to their previous status, or are removed if there isn't one.
+func (p *processor) deleteStatusFromConversations(ctx context.Context, status *gtsmodel.Status) error {
+	conversations, err := p.db.GetConversationsByStatusID(ctx, status.ID)
+	if err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("deleteStatusFromConversations: error getting conversations of status %s: %s", status.ID, err)
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: status.ID}}, &[]*gtsmodel.ConversationToStatus{}); err != nil {
+		return fmt.Errorf("deleteStatusFromConversations: error removing status %s from conversations: %s", status.ID, err)
+	}
+
+	for _, conversation := range conversations {
+		if conversation.LastStatusID != status.ID {
+			continue
+		}
+
+		lastStatusID, err := p.db.GetConversationLatestStatusID(ctx, conversation.ID)
+		if err != nil {
+			if err != db.ErrNoEntries {
+				return fmt.Errorf("deleteStatusFromConversations: error getting latest status of conversation %s: %s", conversation.ID, err)
+			}
+			// nothing left in the conversation
+			if err := p.db.DeleteConversationByID(ctx, conversation.ID); err != nil {
+				return fmt.Errorf("deleteStatusFromConversations: error deleting conversation %s: %s", conversation.ID, err)
+			}
+			continue
+		}
+
+		conversation.LastStatusID = lastStatusID
+		conversation.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, conversation); err != nil {
+			return fmt.Errorf("deleteStatusFromConversations: error updating conversation %s: %s", conversation.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ConversationTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *ConversationTestSuite) TestConversationLifecycle() {
+	ctx := context.Background()
+	zork := suite.testAccounts["local_account_1"]
+	turtle := suite.testAccounts["local_account_2"]
+	zorkAuthed := suite.testAutheds["local_account_1"]
+	turtleAuthed := &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     turtle,
+	}
+
+	// turtle sends zork a direct message
+	dm := suite.testStatuses["local_account_2_status_6"]
+	err := suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       dm,
+		OriginAccount:  turtle,
+	})
+	suite.NoError(err)
+
+	// zork has an unread conversation with turtle
+	resp, errWithCode := suite.processor.ConversationsGet(ctx, zorkAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Conversations, 1)
+	zorkConversation := resp.Conversations[0]
+	suite.True(zorkConversation.Unread)
+	suite.Len(zorkConversation.Accounts, 1)
+	suite.Equal(turtle.ID, zorkConversation.Accounts[0].ID)
+	suite.Equal(dm.ID, zorkConversation.LastStatus.ID)
+	suite.Contains(resp.LinkHeader, "max_id="+dm.ID)
+
+	// turtle wrote the message, so their side of the conversation is already read
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, turtleAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Conversations, 1)
+	suite.False(resp.Conversations[0].Unread)
+	suite.Equal(zork.ID, resp.Conversations[0].Accounts[0].ID)
+
+	// zork reads the conversation
+	read, errWithCode := suite.processor.ConversationRead(ctx, zorkAuthed, zorkConversation.ID)
+	suite.NoError(errWithCode)
+	suite.False(read.Unread)
+
+	// turtle can't touch zork's conversation
+	_, errWithCode = suite.processor.ConversationRead(ctx, turtleAuthed, zorkConversation.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	// zork replies, which should go in the same conversation
+	reply := suite.putDirectReply(zork, turtle, dm)
+	err = suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       reply,
+		OriginAccount:  zork,
+	})
+	suite.NoError(err)
+
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, zorkAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Conversations, 1)
+	suite.Equal(zorkConversation.ID, resp.Conversations[0].ID)
+	suite.Equal(reply.ID, resp.Conversations[0].LastStatus.ID)
+	suite.False(resp.Conversations[0].Unread)
+
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, turtleAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Equal(reply.ID, resp.Conversations[0].LastStatus.ID)
+	suite.True(resp.Conversations[0].Unread)
+
+	// paging past the last status of the conversation gives nothing
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, zorkAuthed, reply.ID, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Conversations)
+	suite.Empty(resp.LinkHeader)
+
+	// zork deletes the reply, so the conversation goes back to the first message
+	err = suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityDelete,
+		GTSModel:       reply,
+		OriginAccount:  zork,
+		TargetAccount:  zork,
+	})
+	suite.NoError(err)
+
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, zorkAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Conversations, 1)
+	suite.Equal(dm.ID, resp.Conversations[0].LastStatus.ID)
+
+	// zork removes the conversation, which leaves turtle's side of it alone
+	errWithCode = suite.processor.ConversationDelete(ctx, zorkAuthed, zorkConversation.ID)
+	suite.NoError(errWithCode)
+
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, zorkAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Conversations)
+
+	resp, errWithCode = suite.processor.ConversationsGet(ctx, turtleAuthed, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Conversations, 1)
+}
+
+func (suite *ConversationTestSuite) TestNoConversationForPublicStatus() {
+	ctx := context.Background()
+
+	err := suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       suite.testStatuses["local_account_2_status_1"],
+		OriginAccount:  suite.testAccounts["local_account_2"],
+	})
+	suite.NoError(err)
+
+	resp, errWithCode := suite.processor.ConversationsGet(ctx, suite.testAutheds["local_account_1"], "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Conversations)
+}
+
+// putDirectReply puts a direct message from one account to another in the db, in reply to the given status.
+func (suite *ConversationTestSuite) putDirectReply(from *gtsmodel.Account, to *gtsmodel.Account, inReplyTo *gtsmodel.Status) *gtsmodel.Status {
+	ctx := context.Background()
+
+	mention := &gtsmodel.Mention{
+		ID:               "01G1ZB7Q1F9N6W4KX8C0MZ3D2R",
+		StatusID:         "01G1ZB7HX2Y3T0N5J8WQ4K6E1V",
+		OriginAccountID:  from.ID,
+		OriginAccountURI: from.URI,
+		TargetAccountID:  to.ID,
+		NameString:       "@" + to.Username,
+		TargetAccountURI: to.URI,
+		TargetAccountURL: to.URL,
+	}
+	if err := suite.db.Put(ctx, mention); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	reply := &gtsmodel.Status{
+		ID:                       "01G1ZB7HX2Y3T0N5J8WQ4K6E1V",
+		URI:                      from.URI + "/statuses/01G1ZB7HX2Y3T0N5J8WQ4K6E1V",
+		URL:                      from.URL + "/statuses/01G1ZB7HX2Y3T0N5J8WQ4K6E1V",
+		Content:                  "hey turtle, got your message",
+		MentionIDs:               []string{mention.ID},
+		CreatedAt:                testrig.TimeMustParse("2022-04-27T12:00:00Z"),
+		UpdatedAt:                testrig.TimeMustParse("2022-04-27T12:00:00Z"),
+		Local:                    true,
+		AccountURI:               from.URI,
+		AccountID:                from.ID,
+		InReplyToID:              inReplyTo.ID,
+		InReplyToAccountID:       inReplyTo.AccountID,
+		InReplyToURI:             inReplyTo.URI,
+		Visibility:               gtsmodel.VisibilityDirect,
+		Language:                 "en",
+		CreatedWithApplicationID: "01F8MGXQRHYF5QPMTMXP78QC2F",
+		Federated:                false,
+		Boostable:                false,
+		Replyable:                true,
+		Likeable:                 true,
+		ActivityStreamsType:      ap.ObjectNote,
+	}
+	if err := suite.db.PutStatus(ctx, reply); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return reply
+}
+
+func TestConversationTestSuite(t *testing.T) {
+	suite.Run(t, &ConversationTestSuite{})
+}
@@ -0,0 +1,53 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// deliveryRetrierSchedule is how often failed deliveries are looked for and tried again.
+const deliveryRetrierSchedule = "@every 1m"
+
+// startDeliveryRetrier starts a cron job that tries failed deliveries again once they're due, and
+// checks whether domains that deliveries were suspended to have come back. Deliveries left over
+// from before a restart are picked up straight away.
+func (p *processor) startDeliveryRetrier() error {
+	if err := p.startScheduledJobNow(deliveryRetrierSchedule, p.retryDeliveries); err != nil {
+		return fmt.Errorf("error starting delivery retrier job: %s", err)
+	}
+	return nil
+}
+
+// retryDeliveries probes domains that deliveries were suspended to, and then tries failed deliveries again.
+func (p *processor) retryDeliveries(ctx context.Context) {
+	tc := p.federator.TransportController()
+
+	// probe first, so that deliveries to any domains that have come back can be retried straight away
+	if err := tc.ProbeUnreachableDomains(ctx); err != nil && ctx.Err() == nil {
+		logrus.Errorf("delivery retrier: error probing unreachable domains: %s", err)
+	}
+
+	if err := tc.RetryDeliveries(ctx); err != nil && ctx.Err() == nil {
+		logrus.Errorf("delivery retrier: error retrying deliveries: %s", err)
+	}
+}
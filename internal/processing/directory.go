@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+const (
+	// DirectoryOrderActive sorts the profile directory by most recently posted first.
+	DirectoryOrderActive = "active"
+	// DirectoryOrderNew sorts the profile directory by most recently created first.
+	DirectoryOrderNew = "new"
+)
+
+func (p *processor) DirectoryGet(ctx context.Context, authed *oauth.Auth, order string, local bool, limit int, offset int) ([]*apimodel.Account, gtserror.WithCode) {
+	if order != DirectoryOrderActive && order != DirectoryOrderNew {
+		err := fmt.Errorf("order must be one of %s or %s", DirectoryOrderActive, DirectoryOrderNew)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	accounts, err := p.db.GetDirectoryAccounts(ctx, order == DirectoryOrderNew, local, limit, offset)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("DirectoryGet: error getting accounts: %s", err))
+	}
+
+	apiAccounts := []*apimodel.Account{}
+	for _, a := range accounts {
+		if authed.Account != nil {
+			blocked, err := p.db.IsBlocked(ctx, authed.Account.ID, a.ID, true)
+			if err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("DirectoryGet: error checking blocks: %s", err))
+			}
+			if blocked {
+				continue
+			}
+		}
+
+		apiAccount, err := p.tc.AccountToAPIAccountPublic(ctx, a)
+		if err != nil {
+			continue
+		}
+		apiAccounts = append(apiAccounts, apiAccount)
+	}
+
+	return apiAccounts, nil
+}
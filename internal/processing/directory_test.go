@@ -0,0 +1,88 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type DirectoryTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *DirectoryTestSuite) TestDirectoryLocal() {
+	accounts, errWithCode := suite.processor.DirectoryGet(context.Background(), suite.testAutheds["local_account_1"], "active", true, 40, 0)
+	suite.NoError(errWithCode)
+	suite.ElementsMatch([]string{
+		suite.testAccounts["admin_account"].ID,
+		suite.testAccounts["local_account_1"].ID,
+	}, accountIDs(accounts))
+	for _, a := range accounts {
+		suite.True(a.Discoverable)
+	}
+}
+
+func (suite *DirectoryTestSuite) TestDirectoryRemote() {
+	accounts, errWithCode := suite.processor.DirectoryGet(context.Background(), suite.testAutheds["local_account_1"], "active", false, 40, 0)
+	suite.NoError(errWithCode)
+	suite.Contains(accountIDs(accounts), suite.testAccounts["remote_account_1"].ID)
+	suite.NotContains(accountIDs(accounts), suite.testAccounts["local_account_2"].ID)
+
+	// paging past the end gives nothing
+	accounts, errWithCode = suite.processor.DirectoryGet(context.Background(), suite.testAutheds["local_account_1"], "active", false, 40, len(accounts))
+	suite.NoError(errWithCode)
+	suite.Empty(accounts)
+}
+
+func (suite *DirectoryTestSuite) TestDirectoryNew() {
+	accounts, errWithCode := suite.processor.DirectoryGet(context.Background(), suite.testAutheds["local_account_1"], "new", false, 40, 0)
+	suite.NoError(errWithCode)
+	suite.NotEmpty(accounts)
+	for i := 1; i < len(accounts); i++ {
+		previous, err := time.Parse(time.RFC3339, accounts[i-1].CreatedAt)
+		suite.NoError(err)
+		current, err := time.Parse(time.RFC3339, accounts[i].CreatedAt)
+		suite.NoError(err)
+		suite.False(current.After(previous))
+	}
+}
+
+func (suite *DirectoryTestSuite) TestDirectoryBadOrder() {
+	accounts, errWithCode := suite.processor.DirectoryGet(context.Background(), suite.testAutheds["local_account_1"], "popular", false, 40, 0)
+	suite.Nil(accounts)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func accountIDs(accounts []*apimodel.Account) []string {
+	ids := []string{}
+	for _, a := range accounts {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestDirectoryTestSuite(t *testing.T) {
+	suite.Run(t, &DirectoryTestSuite{})
+}
@@ -0,0 +1,62 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+func (p *processor) EndorsementsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.EndorsementsResponse, gtserror.WithCode) {
+	accounts, nextMaxID, prevMinID, err := p.db.GetAccountEndorsements(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// there are just no entries
+			return &apimodel.EndorsementsResponse{
+				Accounts: []*apimodel.Account{},
+			}, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiAccounts := []*apimodel.Account{}
+	for _, a := range accounts {
+		apiAccount, err := p.tc.AccountToAPIAccountPublic(ctx, a)
+		if err != nil {
+			continue
+		}
+		apiAccounts = append(apiAccounts, apiAccount)
+	}
+
+	// endorsements page the same way as blocks, so reuse the link header building
+	resp, errWithCode := p.packageBlocksResponse(apiAccounts, "/api/v1/endorsements", nextMaxID, prevMinID, limit)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return &apimodel.EndorsementsResponse{
+		Accounts:   resp.Accounts,
+		LinkHeader: resp.LinkHeader,
+	}, nil
+}
@@ -0,0 +1,85 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EndorsementsTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *EndorsementsTestSuite) TestEndorseAndUnendorse() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	targetAccount := suite.testAccounts["local_account_2"]
+
+	relationship, errWithCode := suite.processor.AccountEndorseCreate(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.True(relationship.Endorsed)
+
+	// endorsing twice is fine
+	relationship, errWithCode = suite.processor.AccountEndorseCreate(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.True(relationship.Endorsed)
+
+	resp, errWithCode := suite.processor.EndorsementsGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Accounts, 1)
+	suite.Equal(targetAccount.ID, resp.Accounts[0].ID)
+	suite.NotEmpty(resp.LinkHeader)
+
+	relationship, errWithCode = suite.processor.AccountEndorseRemove(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.False(relationship.Endorsed)
+
+	resp, errWithCode = suite.processor.EndorsementsGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Accounts)
+	suite.Empty(resp.LinkHeader)
+}
+
+func (suite *EndorsementsTestSuite) TestEndorseNotFollowing() {
+	relationship, errWithCode := suite.processor.AccountEndorseCreate(context.Background(), suite.testAutheds["local_account_1"], suite.testAccounts["remote_account_1"].ID)
+	suite.Nil(relationship)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *EndorsementsTestSuite) TestUnfollowRemovesEndorsement() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	targetAccount := suite.testAccounts["admin_account"]
+
+	_, errWithCode := suite.processor.AccountEndorseCreate(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+
+	relationship, errWithCode := suite.processor.AccountFollowRemove(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.False(relationship.Following)
+	suite.False(relationship.Endorsed)
+}
+
+func TestEndorsementsTestSuite(t *testing.T) {
+	suite.Run(t, &EndorsementsTestSuite{})
+}
@@ -0,0 +1,384 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// exportCleanerSchedule is how often expired exports are looked for and deleted.
+const exportCleanerSchedule = "@every 1h"
+
+// exportFilenames are the names that exports are downloaded as, which are the same as Mastodon uses.
+var exportFilenames = map[gtsmodel.ExportType]string{
+	gtsmodel.ExportTypeFollows:   "following_accounts.csv",
+	gtsmodel.ExportTypeFollowers: "followers.csv",
+	gtsmodel.ExportTypeBlocks:    "blocked_accounts.csv",
+	gtsmodel.ExportTypeMutes:     "muted_accounts.csv",
+	gtsmodel.ExportTypeBookmarks: "bookmarks.csv",
+	gtsmodel.ExportTypeArchive:   "archive.zip",
+}
+
+// exportTypeLists is the Mastodon export type for lists, which this instance can't export.
+const exportTypeLists = "lists"
+
+// exportArchiveStatusesPage is how many statuses are selected from the db at a time when generating an archive.
+const exportArchiveStatusesPage = 100
+
+func (p *processor) ExportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ExportCreateRequest) (*apimodel.Export, gtserror.WithCode) {
+	if form.Type == exportTypeLists {
+		err := fmt.Errorf("export type %s not supported", form.Type)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	exportType := gtsmodel.ExportType(form.Type)
+	if _, ok := exportFilenames[exportType]; !ok {
+		err := fmt.Errorf("export type %s not recognized", form.Type)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	exports, err := p.db.GetAccountExports(ctx, authed.Account.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting exports: %s", err))
+	}
+
+	// there's no point generating the same file twice at once
+	for _, e := range exports {
+		if e.Type == exportType && e.State == gtsmodel.ExportStatePending {
+			return p.exportToAPIExport(ctx, e)
+		}
+	}
+
+	exportID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	export := &gtsmodel.Export{
+		ID:        exportID,
+		AccountID: authed.Account.ID,
+		Type:      exportType,
+		State:     gtsmodel.ExportStatePending,
+	}
+	if err := p.db.Put(ctx, export); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting export: %s", err))
+	}
+
+	// convert the export before it's handed off, since generating the file changes it
+	apiExport, errWithCode := p.exportToAPIExport(ctx, export)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// the file could take a while to generate for a big account, so don't make the caller wait for it
+	go p.runExport(p.ctx, export)
+
+	return apiExport, nil
+}
+
+func (p *processor) ExportsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Export, gtserror.WithCode) {
+	exports, err := p.db.GetAccountExports(ctx, authed.Account.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting exports: %s", err))
+	}
+
+	apiExports := []*apimodel.Export{}
+	for _, e := range exports {
+		// expired exports might not have been cleaned up yet
+		if time.Now().After(e.ExpiresAt()) {
+			continue
+		}
+
+		apiExport, errWithCode := p.exportToAPIExport(ctx, e)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiExports = append(apiExports, apiExport)
+	}
+
+	return apiExports, nil
+}
+
+func (p *processor) ExportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Export, gtserror.WithCode) {
+	export, errWithCode := p.getExport(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.exportToAPIExport(ctx, export)
+}
+
+func (p *processor) ExportFileGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Content, gtserror.WithCode) {
+	export, errWithCode := p.getExport(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	contentType := "text/csv"
+	if export.Type == gtsmodel.ExportTypeArchive {
+		contentType = "application/zip"
+	}
+
+	content := &apimodel.Content{
+		ContentType:   contentType,
+		ContentLength: int64(export.Size),
+		Download:      true,
+		Filename:      exportFilenames[export.Type],
+	}
+
+	if export.State != gtsmodel.ExportStateDone {
+		content.Pending = export.State == gtsmodel.ExportStatePending
+		if !content.Pending {
+			err := fmt.Errorf("export %s failed", export.ID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return content, nil
+	}
+
+	reader, err := p.storage.GetStream(export.Path)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error retrieving from storage: %s", err))
+	}
+
+	content.Content = reader
+	return content, nil
+}
+
+// getExport gets the export with the given ID, as long as it's owned by the requesting account and hasn't expired.
+func (p *processor) getExport(ctx context.Context, authed *oauth.Auth, id string) (*gtsmodel.Export, gtserror.WithCode) {
+	export := &gtsmodel.Export{}
+	if err := p.db.GetByID(ctx, id, export); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting export %s: %s", id, err))
+	}
+
+	if export.AccountID != authed.Account.ID || time.Now().After(export.ExpiresAt()) {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("export %s not found", id))
+	}
+
+	return export, nil
+}
+
+func (p *processor) exportToAPIExport(ctx context.Context, export *gtsmodel.Export) (*apimodel.Export, gtserror.WithCode) {
+	apiExport, err := p.tc.ExportToAPIExport(ctx, export)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting export %s to api export: %s", export.ID, err))
+	}
+	return apiExport, nil
+}
+
+// runExport generates the file of the given pending export, and puts it in storage.
+// The export is marked as done or failed afterwards, depending on how it went.
+func (p *processor) runExport(ctx context.Context, export *gtsmodel.Export) {
+	l := logrus.WithField("export", export.ID)
+
+	var err error
+	if export.Type == gtsmodel.ExportTypeArchive {
+		export.Path = fmt.Sprintf("%s/export/%s.zip", export.AccountID, export.ID)
+		export.Size, err = p.putExportArchive(ctx, export)
+	} else {
+		export.Path = fmt.Sprintf("%s/export/%s.csv", export.AccountID, export.ID)
+		export.Size, err = p.putExportCSV(ctx, export)
+	}
+
+	if err != nil {
+		l.Errorf("runExport: error generating export: %s", err)
+
+		// don't leave half an archive lying around in storage
+		if err := p.storage.Delete(export.Path); err != nil && err != storage.ErrNotFound {
+			l.Errorf("runExport: error deleting file of failed export: %s", err)
+		}
+
+		export.Path = ""
+		export.Size = 0
+		export.State = gtsmodel.ExportStateFailed
+	} else {
+		export.State = gtsmodel.ExportStateDone
+	}
+
+	export.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, export); err != nil {
+		l.Errorf("runExport: error updating export: %s", err)
+	}
+}
+
+// putExportCSV generates the csv file of the given export, puts it in storage at the export's path, and returns its size.
+func (p *processor) putExportCSV(ctx context.Context, export *gtsmodel.Export) (int, error) {
+	records, err := p.exportRecords(ctx, export)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := csv.NewWriter(buf).WriteAll(records); err != nil {
+		return 0, err
+	}
+
+	if err := p.storage.Put(export.Path, buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return buf.Len(), nil
+}
+
+// exportRecords returns the csv records of the given export, including the header record if the export type has one.
+func (p *processor) exportRecords(ctx context.Context, export *gtsmodel.Export) ([][]string, error) {
+	switch export.Type {
+	case gtsmodel.ExportTypeFollows:
+		follows, err := p.db.GetAccountFollows(ctx, export.AccountID)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting follows: %s", err)
+		}
+
+		records := [][]string{{"Account address", "Show boosts", "Notify on new posts", "Languages"}}
+		for _, f := range follows {
+			if f.TargetAccount == nil {
+				continue
+			}
+			records = append(records, []string{exportAddress(f.TargetAccount), strconv.FormatBool(f.ShowReblogs), strconv.FormatBool(f.Notify), ""})
+		}
+		return records, nil
+	case gtsmodel.ExportTypeFollowers:
+		follows, err := p.db.GetAccountFollowedBy(ctx, export.AccountID, false)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting followers: %s", err)
+		}
+
+		records := [][]string{{"Account address"}}
+		for _, f := range follows {
+			if f.Account == nil {
+				continue
+			}
+			records = append(records, []string{exportAddress(f.Account)})
+		}
+		return records, nil
+	case gtsmodel.ExportTypeBlocks:
+		accounts, _, _, err := p.db.GetAccountBlocks(ctx, export.AccountID, "", "", 0)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting blocks: %s", err)
+		}
+
+		records := [][]string{}
+		for _, a := range accounts {
+			records = append(records, []string{exportAddress(a)})
+		}
+		return records, nil
+	case gtsmodel.ExportTypeMutes:
+		mutes, err := p.db.GetAccountMutes(ctx, export.AccountID, "", "", 0)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting mutes: %s", err)
+		}
+
+		records := [][]string{{"Account address", "Hide notifications"}}
+		for _, m := range mutes {
+			if m.TargetAccount == nil {
+				continue
+			}
+			records = append(records, []string{exportAddress(m.TargetAccount), strconv.FormatBool(m.Notifications)})
+		}
+		return records, nil
+	case gtsmodel.ExportTypeBookmarks:
+		bookmarks := []*gtsmodel.StatusBookmark{}
+		if err := p.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: export.AccountID}}, &bookmarks); err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting bookmarks: %s", err)
+		}
+
+		records := [][]string{}
+		for _, b := range bookmarks {
+			status, err := p.db.GetStatusByID(ctx, b.StatusID)
+			if err != nil {
+				// the status might have been deleted since it was bookmarked
+				continue
+			}
+			records = append(records, []string{status.URI})
+		}
+		return records, nil
+	}
+
+	return nil, fmt.Errorf("export type %s not recognized", export.Type)
+}
+
+// exportAddress returns the address of the given account in the form username@domain,
+// which is how accounts are written in exports, for both local and remote accounts.
+func exportAddress(account *gtsmodel.Account) string {
+	if account.Domain != "" {
+		return account.Username + "@" + account.Domain
+	}
+
+	domain := viper.GetString(config.Keys.AccountDomain)
+	if domain == "" {
+		domain = viper.GetString(config.Keys.Host)
+	}
+	return account.Username + "@" + domain
+}
+
+// startExportCleaner picks up any exports that were still pending when the processor was last stopped,
+// and then starts a cron job that deletes exports, and their files, once they've expired.
+func (p *processor) startExportCleaner() error {
+	pending, err := p.db.GetPendingExports(p.ctx)
+	if err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error getting pending exports: %s", err)
+	}
+	for _, e := range pending {
+		go p.runExport(p.ctx, e)
+	}
+
+	if err := p.startScheduledJob(exportCleanerSchedule, p.deleteExpiredExports); err != nil {
+		return fmt.Errorf("error starting export cleaner job: %s", err)
+	}
+	return nil
+}
+
+// deleteExpiredExports deletes exports, and their files, once they've expired.
+func (p *processor) deleteExpiredExports(ctx context.Context) {
+	expired, err := p.db.GetExportsCreatedBefore(ctx, time.Now().Add(-gtsmodel.ExportLifetime))
+	if err != nil && err != db.ErrNoEntries {
+		logrus.Errorf("export cleaner: error getting expired exports: %s", err)
+		return
+	}
+
+	for _, e := range expired {
+		if e.Path != "" {
+			if err := p.storage.Delete(e.Path); err != nil && err != storage.ErrNotFound {
+				logrus.Errorf("export cleaner: error deleting file of export %s: %s", e.ID, err)
+				continue
+			}
+		}
+		if err := p.db.DeleteByID(ctx, e.ID, e); err != nil && err != db.ErrNoEntries {
+			logrus.Errorf("export cleaner: error deleting export %s: %s", e.ID, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ExportTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// exportAndDownload requests an export of the given type, waits for it to finish, and returns the contents of its file.
+func (suite *ExportTestSuite) exportAndDownload(authed *oauth.Auth, exportType string) string {
+	ctx := context.Background()
+
+	export, errWithCode := suite.processor.ExportCreate(ctx, authed, &apimodel.ExportCreateRequest{Type: exportType})
+	suite.NoError(errWithCode)
+	suite.Equal(exportType, export.Type)
+
+	for i := 0; export.State == "pending" && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		export, errWithCode = suite.processor.ExportGet(ctx, authed, export.ID)
+		suite.NoError(errWithCode)
+	}
+	suite.Equal("done", export.State)
+	suite.Equal("http://localhost:8080/api/v1/exports/"+export.ID+"/download", export.URL)
+
+	content, errWithCode := suite.processor.ExportFileGet(ctx, authed, export.ID)
+	suite.NoError(errWithCode)
+	if exportType == "archive" {
+		suite.Equal("application/zip", content.ContentType)
+	} else {
+		suite.Equal("text/csv", content.ContentType)
+	}
+	suite.EqualValues(export.Size, content.ContentLength)
+
+	reader, ok := content.Content.(io.ReadCloser)
+	suite.True(ok)
+	defer reader.Close()
+
+	b, err := io.ReadAll(reader)
+	suite.NoError(err)
+	return string(b)
+}
+
+func (suite *ExportTestSuite) TestExportFollows() {
+	csv := suite.exportAndDownload(suite.testAutheds["local_account_1"], "follows")
+	suite.Equal("Account address,Show boosts,Notify on new posts,Languages\n"+
+		"admin@localhost:8080,true,false,\n"+
+		"1happyturtle@localhost:8080,true,false,\n", csv)
+}
+
+func (suite *ExportTestSuite) TestExportMutes() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	hideNotifications := false
+
+	_, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, suite.testAccounts["remote_account_1"].ID, &apimodel.AccountMuteRequest{Notifications: &hideNotifications})
+	suite.NoError(errWithCode)
+
+	csv := suite.exportAndDownload(authed, "mutes")
+	suite.Equal("Account address,Hide notifications\nfoss_satan@fossbros-anonymous.io,false\n", csv)
+}
+
+func (suite *ExportTestSuite) TestExportBookmarks() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	status := suite.testStatuses["admin_account_status_1"]
+
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.StatusBookmark{
+		ID:              "01G2BN8V5DR8QVGXQ1Y6BXHXCB",
+		AccountID:       authed.Account.ID,
+		TargetAccountID: status.AccountID,
+		StatusID:        status.ID,
+	}))
+
+	csv := suite.exportAndDownload(authed, "bookmarks")
+	suite.Equal(status.URI+"\n", csv)
+
+	exports, errWithCode := suite.processor.ExportsGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Len(exports, 1)
+}
+
+func (suite *ExportTestSuite) TestExportArchive() {
+	authed := suite.testAutheds["local_account_1"]
+
+	archive := suite.exportAndDownload(authed, "archive")
+	zr, err := zip.NewReader(strings.NewReader(archive), int64(len(archive)))
+	suite.NoError(err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		suite.NoError(err)
+		b, err := io.ReadAll(rc)
+		suite.NoError(err)
+		suite.NoError(rc.Close())
+		files[f.Name] = b
+	}
+
+	actor := map[string]interface{}{}
+	suite.NoError(json.Unmarshal(files["actor.json"], &actor))
+	suite.Equal("Person", actor["type"])
+	suite.Equal(authed.Account.URI, actor["id"])
+
+	statusesCount := 0
+	for _, s := range suite.testStatuses {
+		if s.AccountID == authed.Account.ID {
+			statusesCount++
+		}
+	}
+
+	outbox := map[string]interface{}{}
+	suite.NoError(json.Unmarshal(files["outbox.json"], &outbox))
+	suite.Equal("OrderedCollection", outbox["type"])
+	suite.Equal(authed.Account.OutboxURI, outbox["id"])
+	suite.NotEmpty(outbox["@context"])
+	suite.EqualValues(statusesCount, outbox["totalItems"])
+	suite.Len(outbox["orderedItems"], statusesCount)
+
+	// the avatar, and the attachments of statuses, are in there
+	avatar := suite.testAttachments["local_account_1_avatar"]
+	suite.Contains(files, "media/"+avatar.File.Path)
+	attachment := suite.testAttachments["local_account_1_status_4_attachment_1"]
+	suite.Contains(files, "media/"+attachment.File.Path)
+
+	// but attachments that aren't part of anything aren't
+	unattached := suite.testAttachments["local_account_1_unattached_1"]
+	suite.NotContains(files, "media/"+unattached.File.Path)
+}
+
+func (suite *ExportTestSuite) TestExportOtherAccount() {
+	ctx := context.Background()
+
+	export, errWithCode := suite.processor.ExportCreate(ctx, suite.testAutheds["local_account_1"], &apimodel.ExportCreateRequest{Type: "followers"})
+	suite.NoError(errWithCode)
+
+	// nobody else can see the export
+	_, errWithCode = suite.processor.ExportGet(ctx, &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     suite.testAccounts["local_account_2"],
+	}, export.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *ExportTestSuite) TestExportInvalidType() {
+	_, errWithCode := suite.processor.ExportCreate(context.Background(), suite.testAutheds["local_account_1"], &apimodel.ExportCreateRequest{Type: "statuses"})
+	suite.EqualError(errWithCode, "export type statuses not recognized")
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func TestExportTestSuite(t *testing.T) {
+	suite.Run(t, &ExportTestSuite{})
+}
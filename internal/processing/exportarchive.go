@@ -0,0 +1,266 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// putExportArchive generates the zip archive of the given export, puts it in storage at the export's path, and returns its size.
+//
+// The archive contains actor.json, with the account as an activitypub actor, outbox.json, with all of the account's statuses and
+// boosts as an activitypub ordered collection, and the account's media files under media/, at the same paths as they're stored at.
+func (p *processor) putExportArchive(ctx context.Context, export *gtsmodel.Export) (int, error) {
+	account, err := p.db.GetAccountByID(ctx, export.AccountID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting account: %s", err)
+	}
+
+	// media files can make the archive big, so write it straight into storage
+	// as it's generated, rather than holding the whole thing in memory
+	pr, pw := io.Pipe()
+	counter := &countingWriter{w: pw}
+	go func() {
+		pw.CloseWithError(p.writeExportArchive(ctx, account, counter))
+	}()
+
+	if err := p.storage.PutStream(export.Path, pr); err != nil {
+		// make sure the archive stops being written if storage gave up partway through
+		pr.CloseWithError(err)
+		return 0, err
+	}
+
+	return counter.n, nil
+}
+
+// writeExportArchive writes a zip archive of the given account to w.
+func (p *processor) writeExportArchive(ctx context.Context, account *gtsmodel.Account, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	person, err := p.tc.AccountToAS(ctx, account)
+	if err != nil {
+		return fmt.Errorf("error converting account to activitypub: %s", err)
+	}
+	if err := writeArchiveJSON(zw, "actor.json", person); err != nil {
+		return err
+	}
+
+	attachmentIDs, err := p.writeArchiveOutbox(ctx, zw, account)
+	if err != nil {
+		return err
+	}
+
+	attachments := []*gtsmodel.MediaAttachment{}
+	if account.AvatarMediaAttachment != nil {
+		attachments = append(attachments, account.AvatarMediaAttachment)
+	}
+	if account.HeaderMediaAttachment != nil {
+		attachments = append(attachments, account.HeaderMediaAttachment)
+	}
+	for _, id := range attachmentIDs {
+		attachment, err := p.db.GetAttachmentByID(ctx, id)
+		if err != nil {
+			// the attachment might have been deleted since the status was made
+			continue
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	for _, attachment := range attachments {
+		if attachment.File.Path == "" {
+			continue
+		}
+		if err := p.writeArchiveFile(zw, "media/"+attachment.File.Path, attachment.File.Path); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				// a missing file shouldn't stop everything else being archived
+				continue
+			}
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeArchiveOutbox writes all of the given account's statuses and boosts to outbox.json in the given zip archive, as an
+// activitypub ordered collection, and returns the ids of their attachments. The statuses are converted and written one page
+// at a time, so that accounts with lots of statuses don't have to be held in memory all at once.
+func (p *processor) writeArchiveOutbox(ctx context.Context, zw *zip.Writer, account *gtsmodel.Account) ([]string, error) {
+	f, err := zw.Create("outbox.json")
+	if err != nil {
+		return nil, err
+	}
+
+	outboxID, err := json.Marshal(account.OutboxURI)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling outbox id: %s", err)
+	}
+	if _, err := fmt.Fprintf(f, `{"type":"OrderedCollection","id":%s,"orderedItems":[`, outboxID); err != nil {
+		return nil, err
+	}
+
+	// each page is serialized with the @context that its own items need,
+	// so these are gathered up into one @context for the whole collection
+	contexts := []interface{}{}
+	seenContexts := map[string]bool{}
+
+	attachmentIDs := []string{}
+	totalItems := 0
+	var maxID string
+	for {
+		page, err := p.db.GetAccountStatuses(ctx, account.ID, exportArchiveStatusesPage, false, false, maxID, "", false, false, false)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting statuses: %s", err)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+		maxID = page[len(page)-1].ID
+
+		collection, err := p.tc.StatusesToASOutboxCollection(ctx, account.OutboxURI, page)
+		if err != nil {
+			return nil, fmt.Errorf("error converting statuses to activitypub: %s", err)
+		}
+		m, err := streams.Serialize(collection)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing outbox: %s", err)
+		}
+
+		for _, c := range asSlice(m["@context"]) {
+			b, err := json.Marshal(c)
+			if err != nil {
+				return nil, fmt.Errorf("error marshalling outbox context: %s", err)
+			}
+			if !seenContexts[string(b)] {
+				seenContexts[string(b)] = true
+				contexts = append(contexts, c)
+			}
+		}
+
+		for _, item := range asSlice(m["orderedItems"]) {
+			b, err := json.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("error marshalling outbox item: %s", err)
+			}
+			if totalItems != 0 {
+				b = append([]byte{','}, b...)
+			}
+			if _, err := f.Write(b); err != nil {
+				return nil, err
+			}
+			totalItems++
+		}
+
+		for _, s := range page {
+			attachmentIDs = append(attachmentIDs, s.AttachmentIDs...)
+		}
+	}
+
+	var outboxContext interface{} = contexts
+	switch len(contexts) {
+	case 0:
+		outboxContext = "https://www.w3.org/ns/activitystreams"
+	case 1:
+		outboxContext = contexts[0]
+	}
+	b, err := json.Marshal(outboxContext)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling outbox context: %s", err)
+	}
+	if _, err := fmt.Fprintf(f, `],"totalItems":%d,"@context":%s}`, totalItems, b); err != nil {
+		return nil, err
+	}
+
+	return attachmentIDs, nil
+}
+
+// asSlice returns v if it's a slice, or otherwise a slice containing just v, since serialized activitypub
+// properties with only one value are written as that value on its own rather than in an array.
+func asSlice(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// writeArchiveJSON writes t as activitypub json to a new file in the given zip archive.
+func writeArchiveJSON(zw *zip.Writer, name string, t vocab.Type) error {
+	m, err := streams.Serialize(t)
+	if err != nil {
+		return fmt.Errorf("error serializing %s: %s", name, err)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %s", name, err)
+	}
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	return err
+}
+
+// writeArchiveFile copies the value at the given storage path to a new file in the given zip archive.
+func (p *processor) writeArchiveFile(zw *zip.Writer, name string, storagePath string) error {
+	stored, err := p.storage.GetStream(storagePath)
+	if err != nil {
+		return err
+	}
+	defer stored.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, stored)
+	return err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += n
+	return n, err
+}
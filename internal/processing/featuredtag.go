@@ -0,0 +1,165 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// maxFeaturedTags is the most hashtags that one account can feature on its profile.
+const maxFeaturedTags = 10
+
+func (p *processor) FeaturedTagsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.FeaturedTag, gtserror.WithCode) {
+	return p.featuredTagsGet(ctx, authed.Account.ID)
+}
+
+func (p *processor) AccountFeaturedTagsGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]*apimodel.FeaturedTag, gtserror.WithCode) {
+	if authed.Account != nil {
+		blocked, err := p.db.IsBlocked(ctx, authed.Account.ID, targetAccountID, true)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking blocks: %s", err))
+		}
+		if blocked {
+			return nil, gtserror.NewErrorNotFound(errors.New("block exists between accounts"))
+		}
+	}
+
+	if _, err := p.db.GetAccountByID(ctx, targetAccountID); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("account %s not found", targetAccountID))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting account %s: %s", targetAccountID, err))
+	}
+
+	return p.featuredTagsGet(ctx, targetAccountID)
+}
+
+func (p *processor) FeaturedTagCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FeaturedTagCreateRequest) (*apimodel.FeaturedTag, gtserror.WithCode) {
+	name := strings.TrimPrefix(form.Name, "#")
+	if tagStrings := util.DeriveHashtagsFromText("#" + name); len(tagStrings) != 1 || tagStrings[0] != name {
+		err := fmt.Errorf("%s is not a valid hashtag", form.Name)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	featuredTags, err := p.db.GetAccountFeaturedTags(ctx, authed.Account.ID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting featured tags: %s", err))
+	}
+
+	if len(featuredTags) >= maxFeaturedTags {
+		err := fmt.Errorf("you can feature at most %d hashtags", maxFeaturedTags)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	tags, err := p.db.TagStringsToTags(ctx, []string{name}, authed.Account.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting tag %s: %s", name, err))
+	}
+	if len(tags) != 1 {
+		err := fmt.Errorf("hashtag %s can't be used", name)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+	tag := tags[0]
+
+	for _, ft := range featuredTags {
+		if ft.TagID == tag.ID {
+			err := fmt.Errorf("you're already featuring hashtag %s", tag.Name)
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+	}
+
+	// the tag might not be in the database yet, if it's never been used
+	if err := p.db.Put(ctx, tag); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if !errors.As(err, &alreadyExistsError) {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting tag %s: %s", tag.Name, err))
+		}
+	}
+
+	featuredTagID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	featuredTag := &gtsmodel.FeaturedTag{
+		ID:        featuredTagID,
+		AccountID: authed.Account.ID,
+		TagID:     tag.ID,
+		Tag:       tag,
+	}
+
+	if err := p.db.Put(ctx, featuredTag); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting featured tag: %s", err))
+	}
+
+	apiFeaturedTag, err := p.tc.FeaturedTagToAPIFeaturedTag(ctx, featuredTag)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiFeaturedTag, nil
+}
+
+func (p *processor) FeaturedTagDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	featuredTag := &gtsmodel.FeaturedTag{}
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "id", Value: id},
+		{Key: "account_id", Value: authed.Account.ID},
+	}, featuredTag); err != nil {
+		if err == db.ErrNoEntries {
+			return gtserror.NewErrorNotFound(fmt.Errorf("featured tag %s not found", id))
+		}
+		return gtserror.NewErrorInternalError(fmt.Errorf("error getting featured tag %s: %s", id, err))
+	}
+
+	if err := p.db.DeleteByID(ctx, featuredTag.ID, featuredTag); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting featured tag %s: %s", id, err))
+	}
+
+	return nil
+}
+
+func (p *processor) featuredTagsGet(ctx context.Context, accountID string) ([]*apimodel.FeaturedTag, gtserror.WithCode) {
+	featuredTags, err := p.db.GetAccountFeaturedTags(ctx, accountID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting featured tags: %s", err))
+	}
+
+	apiFeaturedTags := []*apimodel.FeaturedTag{}
+	for _, ft := range featuredTags {
+		apiFeaturedTag, err := p.tc.FeaturedTagToAPIFeaturedTag(ctx, ft)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		apiFeaturedTags = append(apiFeaturedTags, apiFeaturedTag)
+	}
+
+	return apiFeaturedTags, nil
+}
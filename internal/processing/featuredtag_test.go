@@ -0,0 +1,101 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type FeaturedTagTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *FeaturedTagTestSuite) TestFeaturedTagCreateAndDelete() {
+	ctx := context.Background()
+	authed := &oauth.Auth{
+		Application: suite.testApplications["admin_account"],
+		User:        suite.testUsers["admin_account"],
+		Account:     suite.testAccounts["admin_account"],
+	}
+
+	featuredTag, errWithCode := suite.processor.FeaturedTagCreate(ctx, authed, &apimodel.FeaturedTagCreateRequest{Name: "#welcome"})
+	suite.NoError(errWithCode)
+	suite.Equal("welcome", featuredTag.Name)
+	suite.Equal(1, featuredTag.StatusesCount)
+	suite.Equal("2021-10-20T11:36:45Z", featuredTag.LastStatusAt)
+
+	// anyone can see which tags an account features
+	featuredTags, errWithCode := suite.processor.AccountFeaturedTagsGet(ctx, suite.testAutheds["local_account_1"], suite.testAccounts["admin_account"].ID)
+	suite.NoError(errWithCode)
+	suite.Len(featuredTags, 1)
+	suite.Equal(featuredTag.ID, featuredTags[0].ID)
+
+	// a tag can only be featured once
+	_, errWithCode = suite.processor.FeaturedTagCreate(ctx, authed, &apimodel.FeaturedTagCreateRequest{Name: "welcome"})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	// someone else can't delete it
+	errWithCode = suite.processor.FeaturedTagDelete(ctx, suite.testAutheds["local_account_1"], featuredTag.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	errWithCode = suite.processor.FeaturedTagDelete(ctx, authed, featuredTag.ID)
+	suite.NoError(errWithCode)
+
+	featuredTags, errWithCode = suite.processor.FeaturedTagsGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Empty(featuredTags)
+}
+
+func (suite *FeaturedTagTestSuite) TestFeaturedTagCreateUnusedTag() {
+	featuredTag, errWithCode := suite.processor.FeaturedTagCreate(context.Background(), suite.testAutheds["local_account_1"], &apimodel.FeaturedTagCreateRequest{Name: "neverbeforeseen"})
+	suite.NoError(errWithCode)
+	suite.Equal("neverbeforeseen", featuredTag.Name)
+	suite.Equal(0, featuredTag.StatusesCount)
+	suite.Empty(featuredTag.LastStatusAt)
+}
+
+func (suite *FeaturedTagTestSuite) TestFeaturedTagCreateInvalid() {
+	for _, name := range []string{"", "#", "not a tag", "#two #tags"} {
+		_, errWithCode := suite.processor.FeaturedTagCreate(context.Background(), suite.testAutheds["local_account_1"], &apimodel.FeaturedTagCreateRequest{Name: name})
+		suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code(), name)
+	}
+}
+
+func (suite *FeaturedTagTestSuite) TestFeaturedTagCreateTooMany() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	for _, name := range []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"} {
+		_, errWithCode := suite.processor.FeaturedTagCreate(ctx, authed, &apimodel.FeaturedTagCreateRequest{Name: name})
+		suite.NoError(errWithCode)
+	}
+
+	_, errWithCode := suite.processor.FeaturedTagCreate(ctx, authed, &apimodel.FeaturedTagCreateRequest{Name: "eleven"})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func TestFeaturedTagTestSuite(t *testing.T) {
+	suite.Run(t, &FeaturedTagTestSuite{})
+}
@@ -31,12 +31,12 @@ func (p *processor) GetFediUser(ctx context.Context, requestedUsername string, r
 	return p.federationProcessor.GetUser(ctx, requestedUsername, requestURL)
 }
 
-func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
-	return p.federationProcessor.GetFollowers(ctx, requestedUsername, requestURL)
+func (p *processor) GetFediFollowers(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	return p.federationProcessor.GetFollowers(ctx, requestedUsername, page, maxID, minID, requestURL)
 }
 
-func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
-	return p.federationProcessor.GetFollowing(ctx, requestedUsername, requestURL)
+func (p *processor) GetFediFollowing(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	return p.federationProcessor.GetFollowing(ctx, requestedUsername, page, maxID, minID, requestURL)
 }
 
 func (p *processor) GetFediStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
@@ -51,6 +51,18 @@ func (p *processor) GetFediOutbox(ctx context.Context, requestedUsername string,
 	return p.federationProcessor.GetOutbox(ctx, requestedUsername, page, maxID, minID, requestURL)
 }
 
+func (p *processor) GetFediFeaturedCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	return p.federationProcessor.GetFeaturedCollection(ctx, requestedUsername, requestURL)
+}
+
+func (p *processor) GetFediFeaturedAccountsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	return p.federationProcessor.GetFeaturedAccountsCollection(ctx, requestedUsername, requestURL)
+}
+
+func (p *processor) GetFediFeaturedTagsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
+	return p.federationProcessor.GetFeaturedTagsCollection(ctx, requestedUsername, requestURL)
+}
+
 func (p *processor) GetWebfingerAccount(ctx context.Context, requestedUsername string) (*apimodel.WellKnownResponse, gtserror.WithCode) {
 	return p.federationProcessor.GetWebfingerAccount(ctx, requestedUsername)
 }
@@ -59,8 +71,8 @@ func (p *processor) GetNodeInfoRel(ctx context.Context, request *http.Request) (
 	return p.federationProcessor.GetNodeInfoRel(ctx, request)
 }
 
-func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode) {
-	return p.federationProcessor.GetNodeInfo(ctx, request)
+func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request, version string) (*apimodel.Nodeinfo, gtserror.WithCode) {
+	return p.federationProcessor.GetNodeInfo(ctx, request, version)
 }
 
 func (p *processor) InboxPost(ctx context.Context, w http.ResponseWriter, r *http.Request) (bool, error) {
@@ -38,12 +38,14 @@ type Processor interface {
 	GetUser(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 
 	// GetFollowers handles the getting of a fedi/activitypub representation of a user/account's followers, performing appropriate
-	// authentication before returning a JSON serializable interface to the caller.
-	GetFollowers(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// authentication before returning a JSON serializable interface to the caller. If page is true, a single page of the
+	// collection is returned, which maxID and minID can be used to select.
+	GetFollowers(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 
 	// GetFollowing handles the getting of a fedi/activitypub representation of a user/account's following, performing appropriate
-	// authentication before returning a JSON serializable interface to the caller.
-	GetFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// authentication before returning a JSON serializable interface to the caller. If page is true, a single page of the
+	// collection is returned, which maxID and minID can be used to select.
+	GetFollowing(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 
 	// GetStatus handles the getting of a fedi/activitypub representation of a particular status, performing appropriate
 	// authentication before returning a JSON serializable interface to the caller.
@@ -53,14 +55,26 @@ type Processor interface {
 	// authentication before returning a JSON serializable interface to the caller.
 	GetStatusReplies(ctx context.Context, requestedUsername string, requestedStatusID string, page bool, onlyOtherAccounts bool, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 
+	// GetFeaturedCollection handles the getting of a fedi/activitypub representation of a user/account's pinned statuses,
+	// performing appropriate authentication before returning a JSON serializable interface to the caller.
+	GetFeaturedCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+
+	// GetFeaturedAccountsCollection handles the getting of a fedi/activitypub representation of the accounts a user/account features on its profile,
+	// performing appropriate authentication before returning a JSON serializable interface.
+	GetFeaturedAccountsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+
+	// GetFeaturedTagsCollection handles the getting of a fedi/activitypub representation of the hashtags a user/account features on its profile,
+	// performing appropriate authentication before returning a JSON serializable interface.
+	GetFeaturedTagsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+
 	// GetWebfingerAccount handles the GET for a webfinger resource. Most commonly, it will be used for returning account lookups.
 	GetWebfingerAccount(ctx context.Context, requestedUsername string) (*apimodel.WellKnownResponse, gtserror.WithCode)
 
 	// GetNodeInfoRel returns a well known response giving the path to node info.
 	GetNodeInfoRel(ctx context.Context, request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode)
 
-	// GetNodeInfo returns a node info struct in response to a node info request.
-	GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode)
+	// GetNodeInfo returns a node info struct with the given schema version in response to a node info request.
+	GetNodeInfo(ctx context.Context, request *http.Request, version string) (*apimodel.Nodeinfo, gtserror.WithCode)
 
 	// GetOutbox returns the activitypub representation of a local user's outbox.
 	// This contains links to PUBLIC posts made by this user.
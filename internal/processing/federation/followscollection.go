@@ -0,0 +1,121 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package federation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+)
+
+// followsPageSize is the number of accounts on each page of a followers or following collection.
+const followsPageSize = 40
+
+// getFollowsCollection returns the followers collection of the requested account if followers is true,
+// or its following collection if not, either as a whole collection or as a single page of it.
+//
+// If the account's collections are hidden, either by the account itself or for every account on this
+// instance, then only the number of items in the collection is shown, and pages of it can't be fetched.
+func (p *processor) getFollowsCollection(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, followers bool) (interface{}, gtserror.WithCode) {
+	// get the account the request is referring to
+	requestedAccount, err := p.db.GetLocalAccountByUsername(ctx, requestedUsername)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("database error getting account with username %s: %s", requestedUsername, err))
+	}
+
+	// authenticate the request
+	requestingAccountURI, errWithCode := p.federator.AuthenticateFederatedRequest(ctx, requestedUsername)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	requestingAccount, err := p.federator.GetRemoteAccount(ctx, requestedUsername, requestingAccountURI, false, false)
+	if err != nil {
+		return nil, gtserror.NewErrorNotAuthorized(err)
+	}
+
+	blocked, err := p.db.IsBlocked(ctx, requestedAccount.ID, requestingAccount.ID, true)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if blocked {
+		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("block exists between accounts %s and %s", requestedAccount.ID, requestingAccount.ID))
+	}
+
+	collectionID := requestedAccount.FollowingURI
+	count := p.db.CountAccountFollows
+	if followers {
+		collectionID = requestedAccount.FollowersURI
+		count = p.db.CountAccountFollowedBy
+	}
+
+	totalItems, err := count(ctx, requestedAccount.ID, false)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting follows of account %s: %s", requestedAccount.ID, err))
+	}
+
+	hidden := requestedAccount.HideCollections || viper.GetBool(config.Keys.FederationHideCollections)
+	if hidden && page {
+		return nil, gtserror.NewErrorForbidden(fmt.Errorf("collection %s is hidden", collectionID), "this collection is hidden")
+	}
+
+	var data map[string]interface{}
+
+	if !page {
+		// return the collection with no items, and a link to the first page unless it's hidden
+		collection, err := p.tc.FollowsToASCollection(ctx, collectionID, totalItems, hidden)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		data, err = streams.Serialize(collection)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		return data, nil
+	}
+
+	getPage := p.db.GetAccountFollowsPage
+	if followers {
+		getPage = p.db.GetAccountFollowedByPage
+	}
+
+	follows, err := getPage(ctx, requestedAccount.ID, maxID, minID, followsPageSize)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting follows of account %s: %s", requestedAccount.ID, err))
+	}
+
+	collectionPage, err := p.tc.FollowsToASCollectionPage(ctx, collectionID, maxID, minID, totalItems, follows, followers)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	data, err = streams.Serialize(collectionPage)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return data, nil
+}
@@ -22,20 +22,28 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/spf13/viper"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 const (
-	nodeInfoVersion      = "2.0"
-	nodeInfoSoftwareName = "gotosocial"
+	nodeInfoSoftwareName       = "gotosocial"
+	nodeInfoSoftwareRepository = "https://github.com/superseriousbusiness/gotosocial"
+	nodeInfoSoftwareHomepage   = "https://docs.gotosocial.org"
+	nodeInfoSchema             = "http://nodeinfo.diaspora.software/ns/schema/"
+	nodeInfoActiveMonth        = 30 * 24 * time.Hour
+	nodeInfoActiveHalfyear     = 180 * 24 * time.Hour
 )
 
 var (
-	nodeInfoRel       = fmt.Sprintf("http://nodeinfo.diaspora.software/ns/schema/%s", nodeInfoVersion)
+	// nodeInfoVersions are the nodeinfo schema versions that are served, newest first.
+	nodeInfoVersions  = []string{"2.1", "2.0"}
 	nodeInfoProtocols = []string{"activitypub"}
 )
 
@@ -43,26 +51,82 @@ func (p *processor) GetNodeInfoRel(ctx context.Context, request *http.Request) (
 	protocol := viper.GetString(config.Keys.Protocol)
 	host := viper.GetString(config.Keys.Host)
 
+	links := make([]apimodel.Link, 0, len(nodeInfoVersions))
+	for _, version := range nodeInfoVersions {
+		links = append(links, apimodel.Link{
+			Rel:  nodeInfoSchema + version,
+			Href: fmt.Sprintf("%s://%s/nodeinfo/%s", protocol, host, version),
+		})
+	}
+
 	return &apimodel.WellKnownResponse{
-		Links: []apimodel.Link{
-			{
-				Rel:  nodeInfoRel,
-				Href: fmt.Sprintf("%s://%s/nodeinfo/%s", protocol, host, nodeInfoVersion),
-			},
-		},
+		Links: links,
 	}, nil
 }
 
-func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode) {
+func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request, version string) (*apimodel.Nodeinfo, gtserror.WithCode) {
+	supported := false
+	for _, v := range nodeInfoVersions {
+		if v == version {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("nodeinfo version %s is not supported", version))
+	}
+
 	openRegistration := viper.GetBool(config.Keys.AccountsRegistrationOpen)
 	softwareVersion := viper.GetString(config.Keys.SoftwareVersion)
+	host := viper.GetString(config.Keys.Host)
+
+	users, err := p.db.CountInstanceUsers(ctx, host)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting users: %s", err))
+	}
+
+	now := time.Now()
+	activeMonth, err := p.db.CountActiveLocalUsers(ctx, now.Add(-nodeInfoActiveMonth))
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting active users: %s", err))
+	}
+
+	activeHalfyear, err := p.db.CountActiveLocalUsers(ctx, now.Add(-nodeInfoActiveHalfyear))
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting active users: %s", err))
+	}
+
+	localPosts, err := p.db.CountInstanceStatuses(ctx, host)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error counting statuses: %s", err))
+	}
+
+	// the metadata set in the config goes in as-is, along with a few things that crawlers commonly look for
+	metadata := make(map[string]interface{})
+	for k, v := range viper.GetStringMapString(config.Keys.FederationNodeInfoMetadata) {
+		metadata[k] = v
+	}
+
+	instance := &gtsmodel.Instance{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: host}}, instance); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("db error fetching instance %s: %s", host, err))
+	}
+	metadata["nodeName"] = instance.Title
+	metadata["nodeDescription"] = instance.ShortDescription
+
+	software := apimodel.NodeInfoSoftware{
+		Name:    nodeInfoSoftwareName,
+		Version: softwareVersion,
+	}
+	if version != "2.0" {
+		// these fields were only added in 2.1
+		software.Repository = nodeInfoSoftwareRepository
+		software.Homepage = nodeInfoSoftwareHomepage
+	}
 
 	return &apimodel.Nodeinfo{
-		Version: nodeInfoVersion,
-		Software: apimodel.NodeInfoSoftware{
-			Name:    nodeInfoSoftwareName,
-			Version: softwareVersion,
-		},
+		Version:   version,
+		Software:  software,
 		Protocols: nodeInfoProtocols,
 		Services: apimodel.NodeInfoServices{
 			Inbound:  []string{},
@@ -70,8 +134,13 @@ func (p *processor) GetNodeInfo(ctx context.Context, request *http.Request) (*ap
 		},
 		OpenRegistrations: openRegistration,
 		Usage: apimodel.NodeInfoUsage{
-			Users: apimodel.NodeInfoUsers{},
+			Users: apimodel.NodeInfoUsers{
+				Total:          users,
+				ActiveHalfyear: activeHalfyear,
+				ActiveMonth:    activeMonth,
+			},
+			LocalPosts: localPosts,
 		},
-		Metadata: make(map[string]interface{}),
+		Metadata: metadata,
 	}, nil
 }
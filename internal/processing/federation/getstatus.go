@@ -27,6 +27,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
 func (p *processor) GetStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode) {
@@ -58,6 +59,20 @@ func (p *processor) GetStatus(ctx context.Context, requestedUsername string, req
 		return nil, gtserror.NewErrorNotAuthorized(fmt.Errorf("block exists between accounts %s and %s", requestedAccount.ID, requestingAccount.ID))
 	}
 
+	// if the status, or its whole account, has been deleted, then say so to the requester,
+	// but only once we know they're allowed to ask us about this account at all
+	statusURI := fmt.Sprintf("%s/%s", uris.GenerateURIsForAccount(requestedUsername).StatusesURI, requestedStatusID)
+	if !requestedAccount.SuspendedAt.IsZero() {
+		return p.gone(ctx, statusURI)
+	}
+	tombstoned, err := p.db.TombstoneExistsWithURI(ctx, statusURI)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	if tombstoned {
+		return p.gone(ctx, statusURI)
+	}
+
 	// get the status out of the database here
 	s := &gtsmodel.Status{}
 	if err := p.db.GetWhere(ctx, []db.Where{
@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
@@ -43,6 +45,18 @@ func (p *processor) GetUser(ctx context.Context, requestedUsername string, reque
 		if err != nil {
 			return nil, gtserror.NewErrorInternalError(err)
 		}
+	} else if !requestedAccount.SuspendedAt.IsZero() {
+		// the account has been deleted or suspended; the public key is still served above so that
+		// remote instances can check the signature on the delete, but the rest of the account is gone
+		return p.gone(ctx, requestedAccount.URI)
+	} else if requestedUsername == viper.GetString(config.Keys.Host) {
+		// the instance actor signs requests made on behalf of the whole instance, so remote instances need to be able to
+		// fetch it to check those signatures; if we required it to be fetched with a signed request, then instances that
+		// do the same would never be able to check each other's signatures, so serve it to anyone who asks
+		requestedPerson, err = p.tc.AccountToAS(ctx, requestedAccount)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
 	} else {
 		// if it's any other path, we want to fully authenticate the request before we serve any data, and then we can serve a more complete profile
 		requestingAccountURI, errWithCode := p.federator.AuthenticateFederatedRequest(ctx, requestedUsername)
@@ -19,10 +19,78 @@
 package federation
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 )
 
 func (p *processor) PostInbox(ctx context.Context, w http.ResponseWriter, r *http.Request) (bool, error) {
+	if err := rewriteEmojiReact(r); err != nil {
+		return false, err
+	}
 	return p.federator.FederatingActor().PostInbox(ctx, w, r)
 }
+
+// rewriteEmojiReact turns pleroma's EmojiReact activities in the body of r, including ones
+// that are being undone, into Likes. EmojiReact isn't part of the vocabulary that we understand,
+// so it would be rejected otherwise, but it has the same shape as a Like with the emoji as its content.
+//
+// The body of r is left as it was if there's nothing to rewrite.
+func rewriteEmojiReact(r *http.Request) error {
+	if r.Body == nil {
+		return nil
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("error reading request body: %s", err)
+	}
+	if err := r.Body.Close(); err != nil {
+		return fmt.Errorf("error closing request body: %s", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+
+	// cheap check before doing any parsing, since most activities won't be reactions
+	if !bytes.Contains(b, []byte(ap.ActivityEmojiReact)) {
+		return nil
+	}
+
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		// leave it to the federating actor to reject
+		return nil
+	}
+
+	rewritten := false
+	rewrite := func(m map[string]interface{}) {
+		if t, ok := m["type"].(string); ok && t == ap.ActivityEmojiReact {
+			m["type"] = ap.ActivityLike
+			rewritten = true
+		}
+	}
+
+	rewrite(m)
+	if t, ok := m["type"].(string); ok && t == ap.ActivityUndo {
+		if object, ok := m["object"].(map[string]interface{}); ok {
+			rewrite(object)
+		}
+	}
+
+	if !rewritten {
+		return nil
+	}
+
+	b, err = json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error marshalling rewritten request body: %s", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.ContentLength = int64(len(b))
+
+	return nil
+}
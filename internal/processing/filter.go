@@ -0,0 +1,274 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// maxFilterPhraseLength is the longest phrase, in characters, that can be filtered.
+const maxFilterPhraseLength = 500
+
+// The v1 filters API is a view over the keywords of v2 filters: each keyword is served as a
+// v1 filter, with the id of the keyword and the contexts and expiry of the filter it belongs to.
+
+func (p *processor) FiltersGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Filter, gtserror.WithCode) {
+	filters, err := p.db.GetFiltersByAccountID(ctx, authed.Account.ID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filters: %s", err))
+	}
+
+	apiFilters := []*apimodel.Filter{}
+	for _, filter := range filters {
+		for _, keyword := range filter.Keywords {
+			apiFilter, err := p.tc.FilterKeywordToAPIFilter(ctx, filter, keyword)
+			if err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter keyword %s to api representation: %s", keyword.ID, err))
+			}
+			apiFilters = append(apiFilters, apiFilter)
+		}
+	}
+
+	return apiFilters, nil
+}
+
+func (p *processor) FilterGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Filter, gtserror.WithCode) {
+	keyword, filter, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiFilter(ctx, filter, keyword)
+}
+
+func (p *processor) FilterCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterCreateUpdateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	if err := validateFilterForm(form); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// the filter and its only keyword share an id, the same as filters that were created before keywords existed
+	filterID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filter := &gtsmodel.Filter{
+		ID:        filterID,
+		AccountID: authed.Account.ID,
+		Title:     form.Phrase,
+		Action:    gtsmodel.FilterActionHide,
+	}
+	setFilterOptions(filter, form)
+
+	keyword := &gtsmodel.FilterKeyword{
+		ID:        filterID,
+		AccountID: authed.Account.ID,
+		FilterID:  filterID,
+		Keyword:   form.Phrase,
+		WholeWord: form.WholeWord,
+	}
+
+	if err := p.db.PutFilter(ctx, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting filter: %s", err))
+	}
+
+	if err := p.db.PutFilterKeyword(ctx, keyword); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting filter keyword: %s", err))
+	}
+
+	return p.apiFilter(ctx, filter, keyword)
+}
+
+func (p *processor) FilterUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterCreateUpdateRequest) (*apimodel.Filter, gtserror.WithCode) {
+	keyword, filter, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := validateFilterForm(form); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// keep the title in step with the phrase, unless it's been given a title of its own through the v2 api
+	if filter.Title == keyword.Keyword {
+		filter.Title = form.Phrase
+	}
+	setFilterOptions(filter, form)
+
+	filter.UpdatedAt = time.Now()
+	if err := p.db.UpdateFilter(ctx, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating filter: %s", err))
+	}
+
+	keyword.Keyword = form.Phrase
+	keyword.WholeWord = form.WholeWord
+	keyword.UpdatedAt = time.Now()
+	if err := p.db.UpdateFilterKeyword(ctx, keyword); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating filter keyword: %s", err))
+	}
+
+	return p.apiFilter(ctx, filter, keyword)
+}
+
+func (p *processor) FilterDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	keyword, filter, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	// if this is all that's left of the filter, get rid of the whole thing
+	if len(filter.Keywords) == 1 && len(filter.Statuses) == 0 {
+		if err := p.db.DeleteFilterByID(ctx, filter.ID); err != nil {
+			return gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter: %s", err))
+		}
+		return nil
+	}
+
+	if err := p.db.DeleteFilterKeyword(ctx, keyword); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter keyword: %s", err))
+	}
+
+	return nil
+}
+
+// getOwnFilter gets the filter with the given id, provided it belongs to the requesting account.
+func (p *processor) getOwnFilter(ctx context.Context, authed *oauth.Auth, id string) (*gtsmodel.Filter, gtserror.WithCode) {
+	filter, err := p.db.GetFilterByID(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("filter %s not found", id)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filter: %s", err))
+	}
+
+	// don't let on that other accounts' filters exist
+	if filter.AccountID != authed.Account.ID {
+		err := fmt.Errorf("filter %s not found", id)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return filter, nil
+}
+
+// getOwnFilterKeyword gets the filter keyword with the given id, and the filter it belongs to, provided it belongs to the requesting account.
+func (p *processor) getOwnFilterKeyword(ctx context.Context, authed *oauth.Auth, id string) (*gtsmodel.FilterKeyword, *gtsmodel.Filter, gtserror.WithCode) {
+	keyword, err := p.db.GetFilterKeywordByID(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("filter %s not found", id)
+			return nil, nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filter keyword: %s", err))
+	}
+
+	// don't let on that other accounts' filters exist
+	if keyword.AccountID != authed.Account.ID {
+		err := fmt.Errorf("filter %s not found", id)
+		return nil, nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	filter, errWithCode := p.getOwnFilter(ctx, authed, keyword.FilterID)
+	if errWithCode != nil {
+		return nil, nil, errWithCode
+	}
+
+	return keyword, filter, nil
+}
+
+func (p *processor) apiFilter(ctx context.Context, filter *gtsmodel.Filter, keyword *gtsmodel.FilterKeyword) (*apimodel.Filter, gtserror.WithCode) {
+	apiFilter, err := p.tc.FilterKeywordToAPIFilter(ctx, filter, keyword)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiFilter, nil
+}
+
+// validateFilterForm checks that the given v1 form is fit to be used to create or update a filter.
+func validateFilterForm(form *apimodel.FilterCreateUpdateRequest) error {
+	if err := validateFilterKeyword(form.Phrase); err != nil {
+		return err
+	}
+
+	if err := validateFilterContexts(form.Context); err != nil {
+		return err
+	}
+
+	if form.ExpiresIn < 0 {
+		return errors.New("expires_in must not be negative")
+	}
+
+	return nil
+}
+
+// setFilterOptions sets the contexts and expiry of the given v1 form on the given filter, replacing whatever was there before.
+// The form should already have been validated.
+func setFilterOptions(filter *gtsmodel.Filter, form *apimodel.FilterCreateUpdateRequest) {
+	filter.SetContexts(toFilterContexts(form.Context))
+	filter.ExpiresAt = filterExpiresAt(form.ExpiresIn)
+}
+
+// validateFilterKeyword checks that the given phrase can be filtered.
+func validateFilterKeyword(phrase string) error {
+	if phrase == "" {
+		return errors.New("phrase must be set")
+	}
+	if len([]rune(phrase)) > maxFilterPhraseLength {
+		return fmt.Errorf("phrase must be no more than %d characters", maxFilterPhraseLength)
+	}
+	return nil
+}
+
+// validateFilterContexts checks that there's at least one context, and that they're all recognised.
+func validateFilterContexts(contexts []string) error {
+	if len(contexts) == 0 {
+		return errors.New("at least one context must be set")
+	}
+	if !(&gtsmodel.Filter{}).SetContexts(toFilterContexts(contexts)) {
+		return errors.New("context not recognised, valid contexts are home, notifications, public, thread, and account")
+	}
+	return nil
+}
+
+func toFilterContexts(contexts []string) []gtsmodel.FilterContext {
+	filterContexts := make([]gtsmodel.FilterContext, 0, len(contexts))
+	for _, context := range contexts {
+		filterContexts = append(filterContexts, gtsmodel.FilterContext(context))
+	}
+	return filterContexts
+}
+
+// filterExpiresAt returns the time that a filter set to expire in the given number of seconds from now
+// should expire at, or the zero time if it shouldn't expire.
+func filterExpiresAt(expiresIn int) time.Time {
+	if expiresIn == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
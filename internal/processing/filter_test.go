@@ -0,0 +1,121 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type FilterTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *FilterTestSuite) TestFilterLifecycle() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	created, errWithCode := suite.processor.FilterCreate(ctx, authed, &model.FilterCreateUpdateRequest{
+		Phrase:    "turtles",
+		Context:   []string{"home", "public"},
+		WholeWord: true,
+		ExpiresIn: 3600,
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("turtles", created.Phrase)
+	suite.Equal([]string{"home", "public"}, created.Context)
+	suite.True(created.WholeWord)
+	suite.NotEmpty(created.ExpiresAt)
+
+	filters, errWithCode := suite.processor.FiltersGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Len(filters, 1)
+	suite.Equal(created, filters[0])
+
+	updated, errWithCode := suite.processor.FilterUpdate(ctx, authed, created.ID, &model.FilterCreateUpdateRequest{
+		Phrase:  "tortoises",
+		Context: []string{"thread"},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal(created.ID, updated.ID)
+	suite.Equal("tortoises", updated.Phrase)
+	suite.Equal([]string{"thread"}, updated.Context)
+	suite.False(updated.WholeWord)
+	suite.Empty(updated.ExpiresAt)
+
+	// other accounts can't see the filter
+	_, errWithCode = suite.processor.FilterGet(ctx, &oauth.Auth{Account: suite.testAccounts["local_account_2"]}, created.ID)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *FilterTestSuite) TestFilterCreateInvalid() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	for _, form := range []*model.FilterCreateUpdateRequest{
+		{Phrase: "", Context: []string{"home"}},
+		{Phrase: "turtles"},
+		{Phrase: "turtles", Context: []string{"everywhere"}},
+		{Phrase: "turtles", Context: []string{"home"}, ExpiresIn: -1},
+	} {
+		_, errWithCode := suite.processor.FilterCreate(ctx, authed, form)
+		suite.Error(errWithCode)
+		suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	}
+}
+
+func (suite *FilterTestSuite) TestFilterPublicTimeline() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	containsTurtles := func() bool {
+		resp, errWithCode := suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false, false, false)
+		suite.NoError(errWithCode)
+		for _, s := range resp.Statuses {
+			if strings.Contains(s.Content, "turtles") {
+				return true
+			}
+		}
+		return false
+	}
+
+	suite.True(containsTurtles())
+
+	filter, errWithCode := suite.processor.FilterCreate(ctx, authed, &model.FilterCreateUpdateRequest{
+		Phrase:  "turtles",
+		Context: []string{"public"},
+	})
+	suite.NoError(errWithCode)
+	suite.False(containsTurtles())
+
+	errWithCode = suite.processor.FilterDelete(ctx, authed, filter.ID)
+	suite.NoError(errWithCode)
+	suite.True(containsTurtles())
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, &FilterTestSuite{})
+}
@@ -0,0 +1,495 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// maxFilterTitleLength is the longest title, in characters, that a filter can have.
+const maxFilterTitleLength = 200
+
+func (p *processor) FiltersV2Get(ctx context.Context, authed *oauth.Auth) ([]*apimodel.FilterV2, gtserror.WithCode) {
+	filters, err := p.db.GetFiltersByAccountID(ctx, authed.Account.ID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filters: %s", err))
+	}
+
+	apiFilters := []*apimodel.FilterV2{}
+	for _, filter := range filters {
+		apiFilter, err := p.tc.FilterToAPIFilterV2(ctx, filter)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter %s to api representation: %s", filter.ID, err))
+		}
+		apiFilters = append(apiFilters, apiFilter)
+	}
+
+	return apiFilters, nil
+}
+
+func (p *processor) FilterV2Get(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterV2, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiFilterV2(ctx, filter)
+}
+
+func (p *processor) FilterV2Create(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterV2CreateRequest) (*apimodel.FilterV2, gtserror.WithCode) {
+	if err := validateFilterTitle(form.Title); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := validateFilterContexts(form.Context); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	action := gtsmodel.FilterActionWarn
+	if form.FilterAction != "" {
+		action = gtsmodel.FilterAction(form.FilterAction)
+		if err := validateFilterAction(action); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	if form.ExpiresIn < 0 {
+		err := errors.New("expires_in must not be negative")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	for _, attributes := range form.KeywordsAttributes {
+		if err := validateFilterKeyword(attributes.Keyword); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	filterID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filter := &gtsmodel.Filter{
+		ID:        filterID,
+		AccountID: authed.Account.ID,
+		Title:     form.Title,
+		Action:    action,
+		ExpiresAt: filterExpiresAt(form.ExpiresIn),
+		Keywords:  []*gtsmodel.FilterKeyword{},
+		Statuses:  []*gtsmodel.FilterStatus{},
+	}
+	filter.SetContexts(toFilterContexts(form.Context))
+
+	if err := p.db.PutFilter(ctx, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting filter: %s", err))
+	}
+
+	for _, attributes := range form.KeywordsAttributes {
+		keyword, errWithCode := p.putFilterKeyword(ctx, filter, attributes.Keyword, attributes.WholeWord != nil && *attributes.WholeWord)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		filter.Keywords = append(filter.Keywords, keyword)
+	}
+
+	return p.apiFilterV2(ctx, filter)
+}
+
+func (p *processor) FilterV2Update(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterV2UpdateRequest) (*apimodel.FilterV2, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// check everything before changing anything, so a bad request doesn't leave the filter half updated
+	if form.Title != nil {
+		if err := validateFilterTitle(*form.Title); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	if form.Context != nil {
+		if err := validateFilterContexts(form.Context); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	if form.FilterAction != nil {
+		if err := validateFilterAction(gtsmodel.FilterAction(*form.FilterAction)); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	if form.ExpiresIn != nil && *form.ExpiresIn < 0 {
+		err := errors.New("expires_in must not be negative")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	keywords := make(map[string]*gtsmodel.FilterKeyword, len(filter.Keywords))
+	for _, keyword := range filter.Keywords {
+		keywords[keyword.ID] = keyword
+	}
+
+	for _, attributes := range form.KeywordsAttributes {
+		if attributes.ID != "" {
+			if _, ok := keywords[attributes.ID]; !ok {
+				err := fmt.Errorf("keyword %s not found in filter %s", attributes.ID, filter.ID)
+				return nil, gtserror.NewErrorBadRequest(err, err.Error())
+			}
+			if attributes.Destroy || attributes.Keyword == "" {
+				continue
+			}
+		}
+		if err := validateFilterKeyword(attributes.Keyword); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	if form.Title != nil {
+		filter.Title = *form.Title
+	}
+	if form.Context != nil {
+		filter.SetContexts(toFilterContexts(form.Context))
+	}
+	if form.FilterAction != nil {
+		filter.Action = gtsmodel.FilterAction(*form.FilterAction)
+	}
+	if form.ExpiresIn != nil {
+		filter.ExpiresAt = filterExpiresAt(*form.ExpiresIn)
+	}
+
+	filter.UpdatedAt = time.Now()
+	if err := p.db.UpdateFilter(ctx, filter); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating filter: %s", err))
+	}
+
+	for _, attributes := range form.KeywordsAttributes {
+		keyword, ok := keywords[attributes.ID]
+		switch {
+		case !ok:
+			// no id, so it's a new keyword
+			keyword, errWithCode := p.putFilterKeyword(ctx, filter, attributes.Keyword, attributes.WholeWord != nil && *attributes.WholeWord)
+			if errWithCode != nil {
+				return nil, errWithCode
+			}
+			keywords[keyword.ID] = keyword
+		case attributes.Destroy:
+			if err := p.db.DeleteFilterKeyword(ctx, keyword); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter keyword: %s", err))
+			}
+			delete(keywords, keyword.ID)
+		default:
+			if attributes.Keyword != "" {
+				keyword.Keyword = attributes.Keyword
+			}
+			if attributes.WholeWord != nil {
+				keyword.WholeWord = *attributes.WholeWord
+			}
+			keyword.UpdatedAt = time.Now()
+			if err := p.db.UpdateFilterKeyword(ctx, keyword); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating filter keyword: %s", err))
+			}
+		}
+	}
+
+	// fetch the filter again to pick up the changed keywords in order
+	return p.FilterV2Get(ctx, authed, filter.ID)
+}
+
+func (p *processor) FilterV2Delete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, id)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.db.DeleteFilterByID(ctx, filter.ID); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter: %s", err))
+	}
+
+	return nil
+}
+
+func (p *processor) FilterKeywordsGet(ctx context.Context, authed *oauth.Auth, filterID string) ([]*apimodel.FilterKeyword, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	apiKeywords := []*apimodel.FilterKeyword{}
+	for _, keyword := range filter.Keywords {
+		apiKeyword, err := p.tc.FilterKeywordToAPIFilterKeyword(ctx, keyword)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter keyword %s to api representation: %s", keyword.ID, err))
+		}
+		apiKeywords = append(apiKeywords, apiKeyword)
+	}
+
+	return apiKeywords, nil
+}
+
+func (p *processor) FilterKeywordGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	keyword, _, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiFilterKeyword(ctx, keyword)
+}
+
+func (p *processor) FilterKeywordCreate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := validateFilterKeyword(form.Keyword); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	keyword, errWithCode := p.putFilterKeyword(ctx, filter, form.Keyword, form.WholeWord)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiFilterKeyword(ctx, keyword)
+}
+
+func (p *processor) FilterKeywordUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	keyword, _, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := validateFilterKeyword(form.Keyword); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	keyword.Keyword = form.Keyword
+	keyword.WholeWord = form.WholeWord
+	keyword.UpdatedAt = time.Now()
+	if err := p.db.UpdateFilterKeyword(ctx, keyword); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating filter keyword: %s", err))
+	}
+
+	return p.apiFilterKeyword(ctx, keyword)
+}
+
+func (p *processor) FilterKeywordDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	keyword, _, errWithCode := p.getOwnFilterKeyword(ctx, authed, id)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.db.DeleteFilterKeyword(ctx, keyword); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter keyword: %s", err))
+	}
+
+	return nil
+}
+
+func (p *processor) FilterStatusesGet(ctx context.Context, authed *oauth.Auth, filterID string) ([]*apimodel.FilterStatus, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	apiStatuses := []*apimodel.FilterStatus{}
+	for _, filterStatus := range filter.Statuses {
+		apiStatus, err := p.tc.FilterStatusToAPIFilterStatus(ctx, filterStatus)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter status %s to api representation: %s", filterStatus.ID, err))
+		}
+		apiStatuses = append(apiStatuses, apiStatus)
+	}
+
+	return apiStatuses, nil
+}
+
+func (p *processor) FilterStatusGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterStatus, gtserror.WithCode) {
+	filterStatus, errWithCode := p.getOwnFilterStatus(ctx, authed, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiFilterStatus(ctx, filterStatus)
+}
+
+func (p *processor) FilterStatusCreate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterStatusCreateRequest) (*apimodel.FilterStatus, gtserror.WithCode) {
+	filter, errWithCode := p.getOwnFilter(ctx, authed, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if form.StatusID == "" {
+		err := errors.New("status_id must be set")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	status, err := p.db.GetStatusByID(ctx, form.StatusID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("status %s not found", form.StatusID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting status: %s", err))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, status, authed.Account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking visibility of status %s: %s", status.ID, err))
+	}
+	if !visible {
+		err := fmt.Errorf("status %s not found", form.StatusID)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	for _, filterStatus := range filter.Statuses {
+		if filterStatus.StatusID == status.ID {
+			err := fmt.Errorf("status %s is already in filter %s", status.ID, filter.ID)
+			return nil, gtserror.NewErrorConflict(err, err.Error())
+		}
+	}
+
+	filterStatusID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filterStatus := &gtsmodel.FilterStatus{
+		ID:        filterStatusID,
+		AccountID: authed.Account.ID,
+		FilterID:  filter.ID,
+		StatusID:  status.ID,
+	}
+
+	if err := p.db.PutFilterStatus(ctx, filterStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting filter status: %s", err))
+	}
+
+	return p.apiFilterStatus(ctx, filterStatus)
+}
+
+func (p *processor) FilterStatusDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	filterStatus, errWithCode := p.getOwnFilterStatus(ctx, authed, id)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.db.DeleteFilterStatus(ctx, filterStatus); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting filter status: %s", err))
+	}
+
+	return nil
+}
+
+// getOwnFilterStatus gets the filter status with the given id, provided it belongs to the requesting account.
+func (p *processor) getOwnFilterStatus(ctx context.Context, authed *oauth.Auth, id string) (*gtsmodel.FilterStatus, gtserror.WithCode) {
+	filterStatus, err := p.db.GetFilterStatusByID(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("filter status %s not found", id)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting filter status: %s", err))
+	}
+
+	// don't let on that other accounts' filters exist
+	if filterStatus.AccountID != authed.Account.ID {
+		err := fmt.Errorf("filter status %s not found", id)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return filterStatus, nil
+}
+
+// putFilterKeyword stores a new keyword in the given filter. The keyword should already have been validated.
+func (p *processor) putFilterKeyword(ctx context.Context, filter *gtsmodel.Filter, keyword string, wholeWord bool) (*gtsmodel.FilterKeyword, gtserror.WithCode) {
+	keywordID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	filterKeyword := &gtsmodel.FilterKeyword{
+		ID:        keywordID,
+		AccountID: filter.AccountID,
+		FilterID:  filter.ID,
+		Keyword:   keyword,
+		WholeWord: wholeWord,
+	}
+
+	if err := p.db.PutFilterKeyword(ctx, filterKeyword); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting filter keyword: %s", err))
+	}
+
+	return filterKeyword, nil
+}
+
+func (p *processor) apiFilterV2(ctx context.Context, filter *gtsmodel.Filter) (*apimodel.FilterV2, gtserror.WithCode) {
+	apiFilter, err := p.tc.FilterToAPIFilterV2(ctx, filter)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiFilter, nil
+}
+
+func (p *processor) apiFilterKeyword(ctx context.Context, keyword *gtsmodel.FilterKeyword) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	apiKeyword, err := p.tc.FilterKeywordToAPIFilterKeyword(ctx, keyword)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiKeyword, nil
+}
+
+func (p *processor) apiFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) (*apimodel.FilterStatus, gtserror.WithCode) {
+	apiStatus, err := p.tc.FilterStatusToAPIFilterStatus(ctx, filterStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiStatus, nil
+}
+
+// validateFilterTitle checks that the given title can be used for a filter.
+func validateFilterTitle(title string) error {
+	if title == "" {
+		return errors.New("title must be set")
+	}
+	if len([]rune(title)) > maxFilterTitleLength {
+		return fmt.Errorf("title must be no more than %d characters", maxFilterTitleLength)
+	}
+	return nil
+}
+
+// validateFilterAction checks that the given filter action is recognised.
+func validateFilterAction(action gtsmodel.FilterAction) error {
+	switch action {
+	case gtsmodel.FilterActionWarn, gtsmodel.FilterActionHide:
+		return nil
+	}
+	return fmt.Errorf("filter_action %s not recognised, valid filter actions are warn and hide", action)
+}
@@ -0,0 +1,170 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type FilterV2TestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *FilterV2TestSuite) TestFilterV2Lifecycle() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	wholeWord := true
+	created, errWithCode := suite.processor.FilterV2Create(ctx, authed, &model.FilterV2CreateRequest{
+		Title:   "reptiles",
+		Context: []string{"home", "account"},
+		KeywordsAttributes: []model.FilterKeywordAttributes{
+			{Keyword: "turtles", WholeWord: &wholeWord},
+			{Keyword: "lizards"},
+		},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("reptiles", created.Title)
+	suite.Equal([]string{"home", "account"}, created.Context)
+	suite.Equal("warn", created.FilterAction)
+	suite.Empty(created.ExpiresAt)
+	suite.Len(created.Keywords, 2)
+	suite.Empty(created.Statuses)
+
+	// each keyword shows up as a filter in the v1 api
+	v1Filters, errWithCode := suite.processor.FiltersGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Len(v1Filters, 2)
+	suite.False(v1Filters[0].Irreversible)
+
+	turtlesID := created.Keywords[0].ID
+	lizardsID := created.Keywords[1].ID
+	if created.Keywords[0].Keyword != "turtles" {
+		turtlesID, lizardsID = lizardsID, turtlesID
+	}
+
+	// change one keyword, remove the other, and add a third
+	title := "shelled reptiles"
+	action := "hide"
+	updated, errWithCode := suite.processor.FilterV2Update(ctx, authed, created.ID, &model.FilterV2UpdateRequest{
+		Title:        &title,
+		FilterAction: &action,
+		KeywordsAttributes: []model.FilterKeywordAttributes{
+			{ID: turtlesID, Keyword: "tortoises"},
+			{ID: lizardsID, Destroy: true},
+			{Keyword: "terrapins"},
+		},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("shelled reptiles", updated.Title)
+	suite.Equal([]string{"home", "account"}, updated.Context)
+	suite.Equal("hide", updated.FilterAction)
+	suite.Len(updated.Keywords, 2)
+	keywords := map[string]model.FilterKeyword{}
+	for _, k := range updated.Keywords {
+		keywords[k.Keyword] = k
+	}
+	suite.Equal(turtlesID, keywords["tortoises"].ID)
+	suite.True(keywords["tortoises"].WholeWord)
+	suite.NotEmpty(keywords["terrapins"].ID)
+
+	filterStatus, errWithCode := suite.processor.FilterStatusCreate(ctx, authed, created.ID, &model.FilterStatusCreateRequest{
+		StatusID: suite.testStatuses["admin_account_status_1"].ID,
+	})
+	suite.NoError(errWithCode)
+	suite.Equal(suite.testStatuses["admin_account_status_1"].ID, filterStatus.StatusID)
+
+	// the same status can't be added twice
+	_, errWithCode = suite.processor.FilterStatusCreate(ctx, authed, created.ID, &model.FilterStatusCreateRequest{
+		StatusID: suite.testStatuses["admin_account_status_1"].ID,
+	})
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusConflict, errWithCode.Code())
+
+	statuses, errWithCode := suite.processor.FilterStatusesGet(ctx, authed, created.ID)
+	suite.NoError(errWithCode)
+	suite.Len(statuses, 1)
+
+	errWithCode = suite.processor.FilterV2Delete(ctx, authed, created.ID)
+	suite.NoError(errWithCode)
+
+	_, errWithCode = suite.processor.FilterKeywordGet(ctx, authed, turtlesID)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	_, errWithCode = suite.processor.FilterStatusGet(ctx, authed, filterStatus.ID)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *FilterV2TestSuite) TestFilterV2CreateInvalid() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	for _, form := range []*model.FilterV2CreateRequest{
+		{Title: "", Context: []string{"home"}},
+		{Title: "reptiles"},
+		{Title: "reptiles", Context: []string{"home"}, FilterAction: "explode"},
+		{Title: "reptiles", Context: []string{"home"}, KeywordsAttributes: []model.FilterKeywordAttributes{{Keyword: ""}}},
+	} {
+		_, errWithCode := suite.processor.FilterV2Create(ctx, authed, form)
+		suite.Error(errWithCode)
+		suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	}
+}
+
+func (suite *FilterV2TestSuite) TestFilterV2WarnPublicTimeline() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	filter, errWithCode := suite.processor.FilterV2Create(ctx, authed, &model.FilterV2CreateRequest{
+		Title:              "reptiles",
+		Context:            []string{"public"},
+		FilterAction:       "warn",
+		KeywordsAttributes: []model.FilterKeywordAttributes{{Keyword: "turtles"}},
+	})
+	suite.NoError(errWithCode)
+
+	resp, errWithCode := suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false, false, false)
+	suite.NoError(errWithCode)
+
+	var found bool
+	for _, s := range resp.Statuses {
+		if strings.Contains(s.Content, "turtles") {
+			// the status is still served, with the results of the filter attached
+			found = true
+			suite.Len(s.Filtered, 1)
+			suite.Equal(filter.ID, s.Filtered[0].Filter.ID)
+			suite.Equal([]string{"turtles"}, s.Filtered[0].KeywordMatches)
+		} else {
+			suite.Empty(s.Filtered)
+		}
+	}
+	suite.True(found)
+}
+
+func TestFilterV2TestSuite(t *testing.T) {
+	suite.Run(t, &FilterV2TestSuite{})
+}
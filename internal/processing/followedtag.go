@@ -0,0 +1,158 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+func (p *processor) TagFollow(ctx context.Context, authed *oauth.Auth, name string) (*apimodel.Tag, gtserror.WithCode) {
+	tag, errWithCode := p.getTagToFollow(ctx, authed, name)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// the tag might not be in the database yet, if it's never been used
+	if err := p.db.Put(ctx, tag); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if !errors.As(err, &alreadyExistsError) {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting tag %s: %s", tag.Name, err))
+		}
+	}
+
+	followedTagID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// following a tag that's already followed is fine, it just stays followed
+	if err := p.db.Put(ctx, &gtsmodel.FollowedTag{
+		ID:        followedTagID,
+		AccountID: authed.Account.ID,
+		TagID:     tag.ID,
+	}); err != nil {
+		var alreadyExistsError *db.ErrAlreadyExists
+		if !errors.As(err, &alreadyExistsError) {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting followed tag: %s", err))
+		}
+	}
+
+	return p.tagToAPIFollowedTag(ctx, tag, true)
+}
+
+func (p *processor) TagUnfollow(ctx context.Context, authed *oauth.Auth, name string) (*apimodel.Tag, gtserror.WithCode) {
+	tag, errWithCode := p.getTagToFollow(ctx, authed, name)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{
+		{Key: "account_id", Value: authed.Account.ID},
+		{Key: "tag_id", Value: tag.ID},
+	}, &[]*gtsmodel.FollowedTag{}); err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting followed tag: %s", err))
+	}
+
+	return p.tagToAPIFollowedTag(ctx, tag, false)
+}
+
+func (p *processor) FollowedTagsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.FollowedTagsResponse, gtserror.WithCode) {
+	followedTags, err := p.db.GetFollowedTags(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	resp := &apimodel.FollowedTagsResponse{
+		Tags: []*apimodel.Tag{},
+	}
+
+	for _, ft := range followedTags {
+		apiTag, errWithCode := p.tagToAPIFollowedTag(ctx, ft.Tag, true)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		resp.Tags = append(resp.Tags, apiTag)
+	}
+
+	// prepare the next and previous links
+	if len(followedTags) != 0 {
+		protocol := viper.GetString(config.Keys.Protocol)
+		host := viper.GetString(config.Keys.Host)
+
+		nextLink := &url.URL{
+			Scheme:   protocol,
+			Host:     host,
+			Path:     "/api/v1/followed_tags",
+			RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, followedTags[len(followedTags)-1].ID),
+		}
+		next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+		prevLink := &url.URL{
+			Scheme:   protocol,
+			Host:     host,
+			Path:     "/api/v1/followed_tags",
+			RawQuery: fmt.Sprintf("limit=%d&min_id=%s", limit, followedTags[0].ID),
+		}
+		prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+		resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
+	}
+
+	return resp, nil
+}
+
+// getTagToFollow returns the tag with the given name, which might not be in the database yet.
+func (p *processor) getTagToFollow(ctx context.Context, authed *oauth.Auth, name string) (*gtsmodel.Tag, gtserror.WithCode) {
+	name = strings.ToLower(strings.TrimPrefix(name, "#"))
+	if tagStrings := util.DeriveHashtagsFromText("#" + name); len(tagStrings) != 1 || tagStrings[0] != name {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("%s is not a valid hashtag", name))
+	}
+
+	tags, err := p.db.TagStringsToTags(ctx, []string{name}, authed.Account.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting tag %s: %s", name, err))
+	}
+	if len(tags) != 1 {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("hashtag %s can't be used", name))
+	}
+
+	return tags[0], nil
+}
+
+func (p *processor) tagToAPIFollowedTag(ctx context.Context, tag *gtsmodel.Tag, following bool) (*apimodel.Tag, gtserror.WithCode) {
+	apiTag, err := p.tc.TagToAPITag(ctx, tag)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	apiTag.Following = &following
+	return &apiTag, nil
+}
@@ -0,0 +1,106 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type FollowedTagTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *FollowedTagTestSuite) TestFollowAndUnfollow() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	tag, errWithCode := suite.processor.TagFollow(ctx, authed, "#Welcome")
+	suite.NoError(errWithCode)
+	suite.Equal("welcome", tag.Name)
+	suite.True(*tag.Following)
+
+	// following again is fine
+	_, errWithCode = suite.processor.TagFollow(ctx, authed, "welcome")
+	suite.NoError(errWithCode)
+
+	// so is following a tag that's never been used
+	_, errWithCode = suite.processor.TagFollow(ctx, authed, "capybaras")
+	suite.NoError(errWithCode)
+
+	resp, errWithCode := suite.processor.FollowedTagsGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	// both follows may have been made in the same millisecond, so the order of their ids isn't certain
+	suite.Len(resp.Tags, 2)
+	names := []string{}
+	for _, t := range resp.Tags {
+		names = append(names, t.Name)
+		suite.True(*t.Following)
+	}
+	suite.ElementsMatch([]string{"capybaras", "welcome"}, names)
+	suite.Contains(resp.LinkHeader, "/api/v1/followed_tags?limit=20&max_id=")
+
+	tag, errWithCode = suite.processor.TagUnfollow(ctx, authed, "welcome")
+	suite.NoError(errWithCode)
+	suite.False(*tag.Following)
+
+	resp, errWithCode = suite.processor.FollowedTagsGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Tags, 1)
+	suite.Equal("capybaras", resp.Tags[0].Name)
+}
+
+func (suite *FollowedTagTestSuite) TestFollowInvalidTag() {
+	_, errWithCode := suite.processor.TagFollow(context.Background(), suite.testAutheds["local_account_1"], "not a hashtag")
+	suite.EqualError(errWithCode, "not a hashtag is not a valid hashtag")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *FollowedTagTestSuite) TestFollowedTagHomeTimeline() {
+	ctx := context.Background()
+	authed := &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     suite.testAccounts["local_account_2"],
+	}
+	taggedStatus := suite.testStatuses["admin_account_status_1"]
+
+	_, errWithCode := suite.processor.TagFollow(ctx, authed, "welcome")
+	suite.NoError(errWithCode)
+
+	// local_account_2 doesn't follow the admin account, but does follow the hashtag the status uses
+	resp, errWithCode := suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+	suite.NoError(errWithCode)
+
+	var found bool
+	for _, s := range resp.Statuses {
+		if s.ID == taggedStatus.ID {
+			found = true
+		}
+	}
+	suite.True(found)
+}
+
+func TestFollowedTagTestSuite(t *testing.T) {
+	suite.Run(t, new(FollowedTagTestSuite))
+}
@@ -20,12 +20,16 @@ package processing
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -49,12 +53,24 @@ func (p *processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 		case ap.ActivityLike:
 			// CREATE LIKE/FAVE
 			return p.processCreateFaveFromClientAPI(ctx, clientMsg)
+		case ap.ActivityEmojiReact:
+			// CREATE EMOJI REACTION
+			return p.processCreateReactionFromClientAPI(ctx, clientMsg)
 		case ap.ActivityAnnounce:
 			// CREATE BOOST/ANNOUNCE
 			return p.processCreateAnnounceFromClientAPI(ctx, clientMsg)
 		case ap.ActivityBlock:
 			// CREATE BLOCK
 			return p.processCreateBlockFromClientAPI(ctx, clientMsg)
+		case ap.ActivityFlag:
+			// CREATE FLAG/REPORT
+			return p.processCreateReportFromClientAPI(ctx, clientMsg)
+		case ap.ObjectMute:
+			// CREATE MUTE
+			return p.processCreateMuteFromClientAPI(ctx, clientMsg)
+		case ap.ObjectPollVote:
+			// CREATE POLL VOTE
+			return p.processCreatePollVoteFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityUpdate:
 		// UPDATE
@@ -62,6 +78,21 @@ func (p *processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 		case ap.ObjectProfile, ap.ActorPerson:
 			// UPDATE ACCOUNT/PROFILE
 			return p.processUpdateAccountFromClientAPI(ctx, clientMsg)
+		case ap.ObjectNote:
+			// UPDATE NOTE/STATUS
+			return p.processUpdateStatusFromClientAPI(ctx, clientMsg)
+		}
+	case ap.ActivityAdd:
+		// ADD
+		if clientMsg.APObjectType == ap.ObjectNote {
+			// ADD NOTE/STATUS TO FEATURED (PIN)
+			return p.processAddStatusFromClientAPI(ctx, clientMsg)
+		}
+	case ap.ActivityRemove:
+		// REMOVE
+		if clientMsg.APObjectType == ap.ObjectNote {
+			// REMOVE NOTE/STATUS FROM FEATURED (UNPIN)
+			return p.processRemoveStatusFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityAccept:
 		// ACCEPT
@@ -87,6 +118,9 @@ func (p *processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 		case ap.ActivityLike:
 			// UNDO LIKE/FAVE
 			return p.processUndoFaveFromClientAPI(ctx, clientMsg)
+		case ap.ActivityEmojiReact:
+			// UNDO EMOJI REACTION
+			return p.processUndoReactionFromClientAPI(ctx, clientMsg)
 		case ap.ActivityAnnounce:
 			// UNDO ANNOUNCE/BOOST
 			return p.processUndoAnnounceFromClientAPI(ctx, clientMsg)
@@ -101,6 +135,12 @@ func (p *processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 			// DELETE ACCOUNT/PROFILE
 			return p.processDeleteAccountFromClientAPI(ctx, clientMsg)
 		}
+	case ap.ActivityMove:
+		// MOVE
+		if clientMsg.APObjectType == ap.ObjectProfile {
+			// MOVE ACCOUNT/PROFILE
+			return p.processMoveAccountFromClientAPI(ctx, clientMsg)
+		}
 	}
 	return nil
 }
@@ -136,10 +176,18 @@ func (p *processor) processCreateStatusFromClientAPI(ctx context.Context, client
 		return err
 	}
 
+	if err := p.updateConversations(ctx, status); err != nil {
+		return err
+	}
+
 	if err := p.notifyStatus(ctx, status); err != nil {
 		return err
 	}
 
+	if err := p.trackStatusLinks(ctx, status); err != nil {
+		return err
+	}
+
 	return p.federateStatus(ctx, status)
 }
 
@@ -169,6 +217,24 @@ func (p *processor) processCreateFaveFromClientAPI(ctx context.Context, clientMs
 	return p.federateFave(ctx, fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
 }
 
+func (p *processor) processCreateReactionFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	reaction, ok := clientMsg.GTSModel.(*gtsmodel.StatusReaction)
+	if !ok {
+		return errors.New("reaction was not parseable as *gtsmodel.StatusReaction")
+	}
+
+	return p.federateReaction(ctx, reaction, clientMsg.OriginAccount, clientMsg.TargetAccount)
+}
+
+func (p *processor) processCreatePollVoteFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	vote, ok := clientMsg.GTSModel.(*gtsmodel.PollVote)
+	if !ok {
+		return errors.New("vote was not parseable as *gtsmodel.PollVote")
+	}
+
+	return p.federatePollVote(ctx, vote, clientMsg.OriginAccount, clientMsg.TargetAccount)
+}
+
 func (p *processor) processCreateAnnounceFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	boostWrapperStatus, ok := clientMsg.GTSModel.(*gtsmodel.Status)
 	if !ok {
@@ -206,13 +272,66 @@ func (p *processor) processCreateBlockFromClientAPI(ctx context.Context, clientM
 	return p.federateBlock(ctx, block)
 }
 
+func (p *processor) processCreateMuteFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	mute, ok := clientMsg.GTSModel.(*gtsmodel.AccountMute)
+	if !ok {
+		return errors.New("mute was not parseable as *gtsmodel.AccountMute")
+	}
+
+	// remove any of the muted account's statuses, and boosts of them, from the muting account's timeline;
+	// mutes don't go the other way, and they're not federated, so there's nothing else to do
+	return p.statusTimelines.WipeItemsFromAccountID(ctx, mute.AccountID, mute.TargetAccountID)
+}
+
+func (p *processor) processCreateReportFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	report, ok := clientMsg.GTSModel.(*gtsmodel.Report)
+	if !ok {
+		return errors.New("report was not parseable as *gtsmodel.Report")
+	}
+
+	// reports are only put on the queue when they should be forwarded
+	return p.federateReport(ctx, report)
+}
+
 func (p *processor) processUpdateAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	account, ok := clientMsg.GTSModel.(*gtsmodel.Account)
 	if !ok {
 		return errors.New("account was not parseable as *gtsmodel.Account")
 	}
 
-	return p.federateAccountUpdate(ctx, account, clientMsg.OriginAccount)
+	if err := p.federateAccountUpdate(ctx, account, clientMsg.OriginAccount); err != nil {
+		return err
+	}
+
+	// fields might have been changed to link to new web pages, so look for rel=me links back to the account on them
+	return p.accountProcessor.VerifyFields(ctx, account)
+}
+
+func (p *processor) processUpdateStatusFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return errors.New("note was not parseable as *gtsmodel.Status")
+	}
+
+	return p.federateStatusUpdate(ctx, status)
+}
+
+func (p *processor) processAddStatusFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return errors.New("pinned status was not parseable as *gtsmodel.Status")
+	}
+
+	return p.federateStatusPin(ctx, status)
+}
+
+func (p *processor) processRemoveStatusFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return errors.New("unpinned status was not parseable as *gtsmodel.Status")
+	}
+
+	return p.federateStatusUnpin(ctx, status)
 }
 
 func (p *processor) processAcceptFollowFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
@@ -261,6 +380,14 @@ func (p *processor) processUndoFaveFromClientAPI(ctx context.Context, clientMsg
 	return p.federateUnfave(ctx, fave, clientMsg.OriginAccount, clientMsg.TargetAccount)
 }
 
+func (p *processor) processUndoReactionFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	reaction, ok := clientMsg.GTSModel.(*gtsmodel.StatusReaction)
+	if !ok {
+		return errors.New("undo was not parseable as *gtsmodel.StatusReaction")
+	}
+	return p.federateUnreact(ctx, reaction, clientMsg.OriginAccount, clientMsg.TargetAccount)
+}
+
 func (p *processor) processUndoAnnounceFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	boost, ok := clientMsg.GTSModel.(*gtsmodel.Status)
 	if !ok {
@@ -303,6 +430,26 @@ func (p *processor) processDeleteStatusFromClientAPI(ctx context.Context, client
 		return err
 	}
 
+	// delete the poll for this status, if it has one
+	if err := p.deleteStatusPoll(ctx, statusToDelete); err != nil {
+		return err
+	}
+
+	// delete all emoji reactions to this status
+	if err := p.db.DeleteStatusReactions(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}); err != nil {
+		return err
+	}
+
+	// delete all previous versions of this status
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusEdit{}); err != nil {
+		return err
+	}
+
+	// delete this status from any conversations it's in
+	if err := p.deleteStatusFromConversations(ctx, statusToDelete); err != nil {
+		return err
+	}
+
 	// delete this status from any and all timelines
 	if err := p.deleteStatusFromTimelines(ctx, statusToDelete); err != nil {
 		return err
@@ -329,6 +476,27 @@ func (p *processor) processDeleteAccountFromClientAPI(ctx context.Context, clien
 	return p.accountProcessor.Delete(ctx, clientMsg.TargetAccount, origin)
 }
 
+func (p *processor) processMoveAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	// remote followers will move their follows themselves when they receive the move
+	if err := p.federateAccountMove(ctx, clientMsg.OriginAccount, clientMsg.TargetAccount); err != nil {
+		return err
+	}
+
+	// but we have to take care of followers on this instance
+	follows, err := p.db.GetAccountFollowedBy(ctx, clientMsg.OriginAccount.ID, true)
+	if err != nil {
+		return fmt.Errorf("processMoveAccountFromClientAPI: error getting local followers of moved account: %s", err)
+	}
+
+	for _, follow := range follows {
+		if err := p.moveFollow(ctx, follow, clientMsg.TargetAccount); err != nil {
+			logrus.Errorf("processMoveAccountFromClientAPI: error moving follow %s: %s", follow.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // TODO: move all the below functions into federation.Federator
 
 func (p *processor) federateAccountDelete(ctx context.Context, account *gtsmodel.Account) error {
@@ -380,7 +548,72 @@ func (p *processor) federateAccountDelete(ctx context.Context, account *gtsmodel
 	deleteCC.AppendIRI(publicIRI)
 	delete.SetActivityStreamsCc(deleteCC)
 
-	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, delete)
+	if _, err := p.federator.FederatingActor().Send(ctx, outboxIRI, delete); err != nil {
+		return err
+	}
+
+	// followers have had the delete delivered to them by the outbox, but other instances
+	// might still have copies of the account from replies, boosts etc, so tell them too
+	return p.deliverToKnownInboxes(ctx, account, delete)
+}
+
+// deliverToKnownInboxes delivers the given activity from the given local account to one inbox on each remote instance
+// this instance knows about, apart from instances that the account has followers on, which the outbox takes care of.
+func (p *processor) deliverToKnownInboxes(ctx context.Context, account *gtsmodel.Account, activity vocab.Type) error {
+	inboxes, err := p.db.GetKnownInboxes(ctx, account.ID)
+	if err != nil {
+		return fmt.Errorf("deliverToKnownInboxes: error getting known inboxes: %s", err)
+	}
+
+	recipients := make([]*url.URL, 0, len(inboxes))
+	for _, i := range inboxes {
+		inbox, err := url.Parse(i)
+		if err != nil {
+			logrus.Debugf("deliverToKnownInboxes: couldn't parse inbox %s: %s", i, err)
+			continue
+		}
+		recipients = append(recipients, inbox)
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	activityI, err := streams.Serialize(activity)
+	if err != nil {
+		return fmt.Errorf("deliverToKnownInboxes: error serializing activity: %s", err)
+	}
+
+	b, err := json.Marshal(activityI)
+	if err != nil {
+		return fmt.Errorf("deliverToKnownInboxes: error marshalling activity: %s", err)
+	}
+
+	t, err := p.federator.TransportController().NewTransportForUsername(ctx, account.Username)
+	if err != nil {
+		return fmt.Errorf("deliverToKnownInboxes: error creating transport for account %s: %s", account.Username, err)
+	}
+
+	return t.BatchDeliver(ctx, b, recipients)
+}
+
+func (p *processor) federateAccountMove(ctx context.Context, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// do nothing if this isn't our account
+	if originAccount.Domain != "" {
+		return nil
+	}
+
+	move, err := p.tc.MoveToAS(ctx, originAccount, targetAccount)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error converting move to as format: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, move)
 	return err
 }
 
@@ -422,6 +655,93 @@ func (p *processor) federateStatus(ctx context.Context, status *gtsmodel.Status)
 	return err
 }
 
+func (p *processor) federateStatusUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	// do nothing if the status shouldn't be federated
+	if !status.Federated {
+		return nil
+	}
+
+	if status.Account == nil {
+		statusAccount, err := p.db.GetAccountByID(ctx, status.AccountID)
+		if err != nil {
+			return fmt.Errorf("federateStatusUpdate: error fetching status author account: %s", err)
+		}
+		status.Account = statusAccount
+	}
+
+	// do nothing if this isn't our status
+	if status.Account.Domain != "" {
+		return nil
+	}
+
+	asStatus, err := p.tc.StatusToAS(ctx, status)
+	if err != nil {
+		return fmt.Errorf("federateStatusUpdate: error converting status to as format: %s", err)
+	}
+
+	update, err := p.tc.WrapNoteInUpdate(asStatus, status.Account)
+	if err != nil {
+		return fmt.Errorf("federateStatusUpdate: error wrapping status in update: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(status.Account.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateStatusUpdate: error parsing outboxURI %s: %s", status.Account.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, update)
+	return err
+}
+
+// federateStatusPin tells the followers of the status owner that the status has been added to their featured collection.
+func (p *processor) federateStatusPin(ctx context.Context, status *gtsmodel.Status) error {
+	if !pinFederated(status) {
+		return nil
+	}
+
+	add, err := p.tc.PinToAS(ctx, status)
+	if err != nil {
+		return fmt.Errorf("federateStatusPin: error converting pin to as format: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(status.Account.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateStatusPin: error parsing outboxURI %s: %s", status.Account.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, add)
+	return err
+}
+
+// federateStatusUnpin tells the followers of the status owner that the status has been removed from their featured collection.
+func (p *processor) federateStatusUnpin(ctx context.Context, status *gtsmodel.Status) error {
+	if !pinFederated(status) {
+		return nil
+	}
+
+	remove, err := p.tc.UnpinToAS(ctx, status)
+	if err != nil {
+		return fmt.Errorf("federateStatusUnpin: error converting unpin to as format: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(status.Account.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateStatusUnpin: error parsing outboxURI %s: %s", status.Account.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, remove)
+	return err
+}
+
+// pinFederated returns true if pinning or unpinning the given status should be federated. Only statuses that
+// show up in the featured collection, ie., federated statuses that anyone can see, are worth telling anyone about.
+func pinFederated(status *gtsmodel.Status) bool {
+	if !status.Federated {
+		return false
+	}
+	return status.Visibility == gtsmodel.VisibilityPublic || status.Visibility == gtsmodel.VisibilityUnlocked
+}
+
 func (p *processor) federateStatusDelete(ctx context.Context, status *gtsmodel.Status) error {
 	if status.Account == nil {
 		statusAccount, err := p.db.GetAccountByID(ctx, status.AccountID)
@@ -461,7 +781,9 @@ func (p *processor) federateStatusDelete(ctx context.Context, status *gtsmodel.S
 
 	// Set the status as the 'object' property.
 	deleteObject := streams.NewActivityStreamsObjectProperty()
-	deleteObject.AppendActivityStreamsNote(asStatus)
+	if err := deleteObject.AppendType(asStatus); err != nil {
+		return fmt.Errorf("federateStatusDelete: error setting object: %s", err)
+	}
 	delete.SetActivityStreamsObject(deleteObject)
 
 	// set the to and cc as the original to/cc of the original status
@@ -574,6 +896,45 @@ func (p *processor) federateUnfave(ctx context.Context, fave *gtsmodel.StatusFav
 	return err
 }
 
+func (p *processor) federateUnreact(ctx context.Context, reaction *gtsmodel.StatusReaction, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// if both accounts are local there's nothing to do here
+	if originAccount.Domain == "" && targetAccount.Domain == "" {
+		return nil
+	}
+
+	// create the AS reaction
+	asReaction, err := p.tc.ReactionToAS(ctx, reaction)
+	if err != nil {
+		return fmt.Errorf("federateUnreact: error converting reaction to as format: %s", err)
+	}
+
+	targetAccountURI, err := url.Parse(targetAccount.URI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", targetAccount.URI, err)
+	}
+
+	// create an Undo and set the appropriate actor on it
+	undo := streams.NewActivityStreamsUndo()
+	undo.SetActivityStreamsActor(asReaction.GetActivityStreamsActor())
+
+	// Set the reaction as the 'object' property.
+	undoObject := streams.NewActivityStreamsObjectProperty()
+	undoObject.AppendActivityStreamsLike(asReaction)
+	undo.SetActivityStreamsObject(undoObject)
+
+	// Set the To of the undo as the target of the reaction
+	undoTo := streams.NewActivityStreamsToProperty()
+	undoTo.AppendIRI(targetAccountURI)
+	undo.SetActivityStreamsTo(undoTo)
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateUnreact: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, undo)
+	return err
+}
+
 func (p *processor) federateUnannounce(ctx context.Context, boost *gtsmodel.Status, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
 	if originAccount.Domain != "" {
 		// nothing to do here
@@ -776,6 +1137,51 @@ func (p *processor) federateFave(ctx context.Context, fave *gtsmodel.StatusFave,
 	return err
 }
 
+func (p *processor) federateReaction(ctx context.Context, reaction *gtsmodel.StatusReaction, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// if both accounts are local there's nothing to do here
+	if originAccount.Domain == "" && targetAccount.Domain == "" {
+		return nil
+	}
+
+	// create the AS reaction
+	asReaction, err := p.tc.ReactionToAS(ctx, reaction)
+	if err != nil {
+		return fmt.Errorf("federateReaction: error converting reaction to as format: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateReaction: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, asReaction)
+	return err
+}
+
+func (p *processor) federatePollVote(ctx context.Context, vote *gtsmodel.PollVote, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// votes on local polls are counted directly, so only votes on remote polls need to be sent anywhere
+	if targetAccount.Domain == "" {
+		return nil
+	}
+
+	note, err := p.tc.PollVoteToAS(ctx, vote)
+	if err != nil {
+		return fmt.Errorf("federatePollVote: error converting vote to as format: %s", err)
+	}
+
+	create, err := p.tc.WrapNoteInCreate(note, false)
+	if err != nil {
+		return fmt.Errorf("federatePollVote: error wrapping vote in create: %s", err)
+	}
+
+	outboxIRI, err := url.Parse(originAccount.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federatePollVote: error parsing outboxURI %s: %s", originAccount.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, create)
+	return err
+}
+
 func (p *processor) federateAnnounce(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) error {
 	announce, err := p.tc.BoostToAS(ctx, boostWrapperStatus, boostingAccount, boostedAccount)
 	if err != nil {
@@ -847,6 +1253,60 @@ func (p *processor) federateBlock(ctx context.Context, block *gtsmodel.Block) er
 	return err
 }
 
+func (p *processor) federateReport(ctx context.Context, report *gtsmodel.Report) error {
+	if report.TargetAccount == nil {
+		reportTargetAccount, err := p.db.GetAccountByID(ctx, report.TargetAccountID)
+		if err != nil {
+			return fmt.Errorf("federateReport: error getting report target account from database: %s", err)
+		}
+		report.TargetAccount = reportTargetAccount
+	}
+
+	// if the reported account is local there's nobody to forward the report to
+	if report.TargetAccount.Domain == "" {
+		return nil
+	}
+
+	flag, err := p.tc.ReportToASFlag(ctx, report)
+	if err != nil {
+		return fmt.Errorf("federateReport: error converting report to AS format: %s", err)
+	}
+
+	flagI, err := streams.Serialize(flag)
+	if err != nil {
+		return fmt.Errorf("federateReport: error serializing flag: %s", err)
+	}
+
+	b, err := json.Marshal(flagI)
+	if err != nil {
+		return fmt.Errorf("federateReport: error marshalling flag: %s", err)
+	}
+
+	inboxIRI, err := url.Parse(report.TargetAccount.InboxURI)
+	if err != nil {
+		return fmt.Errorf("federateReport: error parsing inboxURI %s: %s", report.TargetAccount.InboxURI, err)
+	}
+
+	// the flag is sent by the instance account, so that whoever made the report stays anonymous;
+	// this also means we can't go through the federating actor, since the instance account has no outbox
+	t, err := p.federator.TransportController().NewTransportForInstance(ctx)
+	if err != nil {
+		return fmt.Errorf("federateReport: error creating instance transport: %s", err)
+	}
+
+	if err := t.Deliver(ctx, b, inboxIRI); err != nil {
+		return fmt.Errorf("federateReport: error delivering flag to %s: %s", inboxIRI, err)
+	}
+
+	report.Forwarded = true
+	report.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, report); err != nil {
+		return fmt.Errorf("federateReport: error marking report %s as forwarded: %s", report.ID, err)
+	}
+
+	return nil
+}
+
 func (p *processor) federateUnblock(ctx context.Context, block *gtsmodel.Block) error {
 	if block.Account == nil {
 		blockAccount, err := p.db.GetAccountByID(ctx, block.AccountID)
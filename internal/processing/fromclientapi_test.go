@@ -45,12 +45,12 @@ func (suite *FromClientAPITestSuite) TestProcessStreamNewStatus() {
 	receivingAccount := suite.testAccounts["local_account_1"]
 
 	// open a home timeline stream for zork
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(ctx, receivingAccount, stream.TimelineHome)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(ctx, receivingAccount, stream.TimelineHome, "")
 	suite.NoError(errWithCode)
 
 	// open another stream for zork, but for a different timeline;
 	// this shouldn't get stuff streamed into it, since it's for the public timeline
-	irrelevantStream, errWithCode := suite.processor.OpenStreamForAccount(ctx, receivingAccount, stream.TimelinePublic)
+	irrelevantStream, errWithCode := suite.processor.OpenStreamForAccount(ctx, receivingAccount, stream.TimelinePublic, "")
 	suite.NoError(errWithCode)
 
 	// make a new status from admin account
@@ -113,6 +113,60 @@ func (suite *FromClientAPITestSuite) TestProcessStreamNewStatus() {
 	suite.Empty(irrelevantStream.Messages)
 }
 
+func (suite *FromClientAPITestSuite) TestProcessForwardReport() {
+	ctx := context.Background()
+
+	reportingAccount := suite.testAccounts["local_account_1"]
+	reportedAccount := suite.testAccounts["remote_account_1"]
+	reportedStatus := suite.testStatuses["remote_account_1_status_1"]
+
+	report := &gtsmodel.Report{
+		ID:              "01G0Y8BVJ5GRB5R1GKJ3KYGZ3E",
+		URI:             "http://localhost:8080/reports/01G0Y8BVJ5GRB5R1GKJ3KYGZ3E",
+		AccountID:       reportingAccount.ID,
+		TargetAccountID: reportedAccount.ID,
+		StatusIDs:       []string{reportedStatus.ID},
+		Comment:         "this is spam",
+	}
+	err := suite.db.Put(ctx, report)
+	suite.NoError(err)
+
+	err = suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ActivityFlag,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       report,
+		OriginAccount:  reportingAccount,
+		TargetAccount:  reportedAccount,
+	})
+	suite.NoError(err)
+
+	// a flag should have been sent to the inbox of the reported account
+	sent, ok := suite.sentHTTPRequests[reportedAccount.InboxURI]
+	suite.True(ok)
+	flag := &struct {
+		Actor   string   `json:"actor"`
+		ID      string   `json:"id"`
+		Object  []string `json:"object"`
+		Content string   `json:"content"`
+		Type    string   `json:"type"`
+	}{}
+	err = json.Unmarshal(sent, flag)
+	suite.NoError(err)
+
+	// the flag should come from the instance account, not from the account that made the report
+	suite.Equal("http://localhost:8080/users/localhost:8080", flag.Actor)
+	suite.Equal(report.URI, flag.ID)
+	suite.Equal([]string{reportedAccount.URI, reportedStatus.URI}, flag.Object)
+	suite.Equal("this is spam", flag.Content)
+	suite.Equal("Flag", flag.Type)
+
+	// the report should now be marked as forwarded
+	dbReport := &gtsmodel.Report{}
+	err = suite.db.GetByID(ctx, report.ID, dbReport)
+	suite.NoError(err)
+	suite.True(dbReport.Forwarded)
+}
+
 func TestFromClientAPITestSuite(t *testing.T) {
 	suite.Run(t, &FromClientAPITestSuite{})
 }
@@ -24,12 +24,30 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/sirupsen/logrus"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
+// notificationExcluded returns true if the given account has chosen not to be sent notifications of the given type.
+func notificationExcluded(account *gtsmodel.Account, notificationType gtsmodel.NotificationType) bool {
+	for _, t := range account.ExcludedNotifications {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationMuted returns true if the target account has muted notifications from the origin account.
+func (p *processor) notificationMuted(ctx context.Context, targetAccountID string, originAccountID string) (bool, error) {
+	return p.db.IsMuted(ctx, targetAccountID, originAccountID, true)
+}
+
 func (p *processor) notifyStatus(ctx context.Context, status *gtsmodel.Status) error {
 	// if there are no mentions in this status then just bail
 	if len(status.MentionIDs) == 0 {
@@ -61,6 +79,18 @@ func (p *processor) notifyStatus(ctx context.Context, status *gtsmodel.Status) e
 			continue
 		}
 
+		if notificationExcluded(m.TargetAccount, gtsmodel.NotificationMention) {
+			// the account doesn't want to be notified of mentions
+			continue
+		}
+
+		if muted, err := p.notificationMuted(ctx, m.TargetAccountID, status.AccountID); err != nil {
+			return fmt.Errorf("notifyStatus: error checking mute: %s", err)
+		} else if muted {
+			// the account has muted notifications from the origin account
+			continue
+		}
+
 		// make sure a notif doesn't already exist for this mention
 		if err := p.db.GetWhere(ctx, []db.Where{
 			{Key: "notification_type", Value: gtsmodel.NotificationMention},
@@ -96,15 +126,28 @@ func (p *processor) notifyStatus(ctx context.Context, status *gtsmodel.Status) e
 			return fmt.Errorf("notifyStatus: error putting notification in database: %s", err)
 		}
 
+		hide, results, err := p.notificationFilterResults(ctx, notif, m.TargetAccount)
+		if err != nil {
+			return fmt.Errorf("notifyStatus: error checking filters of account: %s", err)
+		}
+		if hide {
+			continue
+		}
+
 		// now stream the notification to the user
 		apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
 		if err != nil {
 			return fmt.Errorf("notifyStatus: error converting notification to api representation: %s", err)
 		}
+		if apiNotif.Status != nil {
+			apiNotif.Status.Filtered = results
+		}
 
 		if err := p.streamingProcessor.StreamNotificationToAccount(apiNotif, m.TargetAccount); err != nil {
 			return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 		}
+
+		p.pushNotification(apiNotif, m.TargetAccount)
 	}
 
 	return nil
@@ -127,6 +170,18 @@ func (p *processor) notifyFollowRequest(ctx context.Context, followRequest *gtsm
 		return nil
 	}
 
+	if notificationExcluded(targetAccount, gtsmodel.NotificationFollowRequest) {
+		// the account doesn't want to be notified of follow requests
+		return nil
+	}
+
+	if muted, err := p.notificationMuted(ctx, targetAccount.ID, followRequest.AccountID); err != nil {
+		return fmt.Errorf("notifyFollowRequest: error checking mute: %s", err)
+	} else if muted {
+		// the account has muted notifications from the origin account
+		return nil
+	}
+
 	notifID, err := id.NewULID()
 	if err != nil {
 		return err
@@ -153,6 +208,8 @@ func (p *processor) notifyFollowRequest(ctx context.Context, followRequest *gtsm
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
+	p.pushNotification(apiNotif, targetAccount)
+
 	return nil
 }
 
@@ -171,6 +228,18 @@ func (p *processor) notifyFollow(ctx context.Context, follow *gtsmodel.Follow, t
 		return fmt.Errorf("notifyFollow: error removing old follow request notification from database: %s", err)
 	}
 
+	if notificationExcluded(targetAccount, gtsmodel.NotificationFollow) {
+		// the account doesn't want to be notified of follows
+		return nil
+	}
+
+	if muted, err := p.notificationMuted(ctx, targetAccount.ID, follow.AccountID); err != nil {
+		return fmt.Errorf("notifyFollow: error checking mute: %s", err)
+	} else if muted {
+		// the account has muted notifications from the origin account
+		return nil
+	}
+
 	// now create the new follow notification
 	notifID, err := id.NewULID()
 	if err != nil {
@@ -199,6 +268,8 @@ func (p *processor) notifyFollow(ctx context.Context, follow *gtsmodel.Follow, t
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
+	p.pushNotification(apiNotif, targetAccount)
+
 	return nil
 }
 
@@ -217,6 +288,18 @@ func (p *processor) notifyFave(ctx context.Context, fave *gtsmodel.StatusFave) e
 		return nil
 	}
 
+	if notificationExcluded(targetAccount, gtsmodel.NotificationFave) {
+		// the account doesn't want to be notified of faves
+		return nil
+	}
+
+	if muted, err := p.notificationMuted(ctx, targetAccount.ID, fave.AccountID); err != nil {
+		return fmt.Errorf("notifyFave: error checking mute: %s", err)
+	} else if muted {
+		// the account has muted notifications from the origin account
+		return nil
+	}
+
 	notifID, err := id.NewULID()
 	if err != nil {
 		return err
@@ -237,16 +320,29 @@ func (p *processor) notifyFave(ctx context.Context, fave *gtsmodel.StatusFave) e
 		return fmt.Errorf("notifyFave: error putting notification in database: %s", err)
 	}
 
+	hide, results, err := p.notificationFilterResults(ctx, notif, targetAccount)
+	if err != nil {
+		return fmt.Errorf("notifyFave: error checking filters of account: %s", err)
+	}
+	if hide {
+		return nil
+	}
+
 	// now stream the notification to the user
 	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to api representation: %s", err)
 	}
+	if apiNotif.Status != nil {
+		apiNotif.Status.Filtered = results
+	}
 
 	if err := p.streamingProcessor.StreamNotificationToAccount(apiNotif, targetAccount); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
+	p.pushNotification(apiNotif, targetAccount)
+
 	return nil
 }
 
@@ -283,6 +379,18 @@ func (p *processor) notifyAnnounce(ctx context.Context, status *gtsmodel.Status)
 		return nil
 	}
 
+	if notificationExcluded(status.BoostOfAccount, gtsmodel.NotificationReblog) {
+		// the account doesn't want to be notified of boosts
+		return nil
+	}
+
+	if muted, err := p.notificationMuted(ctx, status.BoostOfAccountID, status.AccountID); err != nil {
+		return fmt.Errorf("notifyAnnounce: error checking mute: %s", err)
+	} else if muted {
+		// the account has muted notifications from the origin account
+		return nil
+	}
+
 	// make sure a notif doesn't already exist for this announce
 	err := p.db.GetWhere(ctx, []db.Where{
 		{Key: "notification_type", Value: gtsmodel.NotificationReblog},
@@ -316,21 +424,57 @@ func (p *processor) notifyAnnounce(ctx context.Context, status *gtsmodel.Status)
 		return fmt.Errorf("notifyAnnounce: error putting notification in database: %s", err)
 	}
 
+	hide, results, err := p.notificationFilterResults(ctx, notif, status.BoostOfAccount)
+	if err != nil {
+		return fmt.Errorf("notifyAnnounce: error checking filters of account: %s", err)
+	}
+	if hide {
+		return nil
+	}
+
 	// now stream the notification to the user
 	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
 	if err != nil {
 		return fmt.Errorf("notifyStatus: error converting notification to api representation: %s", err)
 	}
+	if apiNotif.Status != nil {
+		apiNotif.Status.Filtered = results
+	}
 
 	if err := p.streamingProcessor.StreamNotificationToAccount(apiNotif, status.BoostOfAccount); err != nil {
 		return fmt.Errorf("notifyStatus: error streaming notification to account: %s", err)
 	}
 
+	p.pushNotification(apiNotif, status.BoostOfAccount)
+
 	return nil
 }
 
+// notificationFilterResults checks the status that the given notification is about against the filters that the
+// notified account has set for notifications, returning whether the notification should be hidden, and the api
+// representation of the results of any warn filters that the status matched. Hidden notifications are still stored,
+// so that they show up if the filter is removed or expires, but they aren't streamed or pushed.
+func (p *processor) notificationFilterResults(ctx context.Context, notif *gtsmodel.Notification, targetAccount *gtsmodel.Account) (bool, []apimodel.FilterResult, error) {
+	if notif.StatusID == "" {
+		return false, nil, nil
+	}
+
+	status := notif.Status
+	if status == nil {
+		var err error
+		status, err = p.db.GetStatusByID(ctx, notif.StatusID)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	return p.statusFilterResults(ctx, status, targetAccount, gtsmodel.FilterContextNotifications)
+}
+
 // timelineStatus processes the given new status and inserts it into
 // the HOME timelines of accounts that follow the status author.
+//
+// The status will also be streamed to any open public, hashtag, or direct streams that it belongs on.
 func (p *processor) timelineStatus(ctx context.Context, status *gtsmodel.Status) error {
 	// make sure the author account is pinned onto the status
 	if status.Account == nil {
@@ -341,6 +485,11 @@ func (p *processor) timelineStatus(ctx context.Context, status *gtsmodel.Status)
 		status.Account = a
 	}
 
+	// streaming errors shouldn't stop the status being timelined, so just log them
+	if err := p.streamingProcessor.StreamStatus(ctx, status); err != nil {
+		logrus.Errorf("timelineStatus: error streaming status %s: %s", status.ID, err)
+	}
+
 	// get local followers of the account that posted the status
 	follows, err := p.db.GetAccountFollowedBy(ctx, status.AccountID, true)
 	if err != nil {
@@ -355,12 +504,24 @@ func (p *processor) timelineStatus(ctx context.Context, status *gtsmodel.Status)
 		})
 	}
 
+	timelineAccountIDs := make([]string, 0, len(follows))
+	for _, f := range follows {
+		timelineAccountIDs = append(timelineAccountIDs, f.AccountID)
+	}
+
+	// also timeline the status for accounts following any of the hashtags it uses, if they're not already covered
+	tagFollowerIDs, err := p.db.GetTagFollowerIDs(ctx, status.TagIDs)
+	if err != nil {
+		return fmt.Errorf("timelineStatus: error getting followers of tags of status %s: %s", status.ID, err)
+	}
+	timelineAccountIDs = util.UniqueStrings(append(timelineAccountIDs, tagFollowerIDs...))
+
 	wg := sync.WaitGroup{}
-	wg.Add(len(follows))
-	errors := make(chan error, len(follows))
+	wg.Add(len(timelineAccountIDs))
+	errors := make(chan error, len(timelineAccountIDs))
 
-	for _, f := range follows {
-		go p.timelineStatusForAccount(ctx, status, f.AccountID, errors, &wg)
+	for _, accountID := range timelineAccountIDs {
+		go p.timelineStatusForAccount(ctx, status, accountID, errors, &wg)
 	}
 
 	// read any errors that come in from the async functions
@@ -418,13 +579,23 @@ func (p *processor) timelineStatusForAccount(ctx context.Context, status *gtsmod
 		return
 	}
 
-	// the status was inserted so stream it to the user
+	// the status was inserted so stream it to the user, unless it's filtered out of their home timeline
 	if inserted {
+		hide, results, err := p.statusFilterResults(ctx, status, timelineAccount, gtsmodel.FilterContextHome)
+		if err != nil {
+			errors <- fmt.Errorf("timelineStatusForAccount: error checking filters for status %s: %s", status.ID, err)
+			return
+		}
+		if hide {
+			return
+		}
+
 		apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, timelineAccount)
 		if err != nil {
 			errors <- fmt.Errorf("timelineStatusForAccount: error converting status %s to frontend representation: %s", status.ID, err)
 			return
 		}
+		apiStatus.Filtered = results
 
 		if err := p.streamingProcessor.StreamUpdateToAccount(apiStatus, timelineAccount, stream.TimelineHome); err != nil {
 			errors <- fmt.Errorf("timelineStatusForAccount: error streaming status %s: %s", status.ID, err)
@@ -441,3 +612,61 @@ func (p *processor) deleteStatusFromTimelines(ctx context.Context, status *gtsmo
 
 	return p.streamingProcessor.StreamDelete(status.ID)
 }
+
+// deleteStatusPoll deletes the poll attached to the given status, if it has one, along with all the votes cast in it.
+// The poll is left set on the status, so that the status can still be converted to its activitystreams representation.
+func (p *processor) deleteStatusPoll(ctx context.Context, status *gtsmodel.Status) error {
+	if status.PollID == "" {
+		return nil
+	}
+
+	if status.Poll == nil {
+		poll, err := p.db.GetPollByID(ctx, status.PollID)
+		if err != nil {
+			if err != db.ErrNoEntries {
+				return fmt.Errorf("deleteStatusPoll: error getting poll %s: %s", status.PollID, err)
+			}
+			// the poll is already gone, so treat the status as if it never had one
+			status.PollID = ""
+			return nil
+		}
+		status.Poll = poll
+	}
+
+	if err := p.db.DeletePoll(ctx, status.PollID); err != nil {
+		return fmt.Errorf("deleteStatusPoll: error deleting poll %s: %s", status.PollID, err)
+	}
+
+	return nil
+}
+
+// moveFollow moves the given follow of an account that has moved over to the account that it moved to.
+// The following account will follow the target account with the same settings as before, and unfollow
+// the account that moved.
+func (p *processor) moveFollow(ctx context.Context, follow *gtsmodel.Follow, targetAccount *gtsmodel.Account) error {
+	if follow.Account == nil {
+		a, err := p.db.GetAccountByID(ctx, follow.AccountID)
+		if err != nil {
+			return fmt.Errorf("moveFollow: error getting following account from database: %s", err)
+		}
+		follow.Account = a
+	}
+
+	// the target account can't follow itself, but it should still unfollow the account that moved
+	if follow.AccountID != targetAccount.ID {
+		form := &apimodel.AccountFollowRequest{
+			ID:      targetAccount.ID,
+			Reblogs: &follow.ShowReblogs,
+			Notify:  &follow.Notify,
+		}
+		if _, errWithCode := p.accountProcessor.FollowCreate(ctx, follow.Account, form); errWithCode != nil {
+			return fmt.Errorf("moveFollow: error following target account %s: %s", targetAccount.ID, errWithCode)
+		}
+	}
+
+	if _, errWithCode := p.accountProcessor.FollowRemove(ctx, follow.Account, follow.TargetAccountID); errWithCode != nil {
+		return fmt.Errorf("moveFollow: error unfollowing moved account %s: %s", follow.TargetAccountID, errWithCode)
+	}
+
+	return nil
+}
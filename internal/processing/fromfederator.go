@@ -25,7 +25,9 @@ import (
 	"net/url"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
@@ -79,6 +81,12 @@ func (p *processor) ProcessFromFederator(ctx context.Context, federatorMsg messa
 			// DELETE A PROFILE/ACCOUNT
 			return p.processDeleteAccountFromFederator(ctx, federatorMsg)
 		}
+	case ap.ActivityMove:
+		// MOVE SOMETHING
+		if federatorMsg.APObjectType == ap.ObjectProfile {
+			// MOVE AN ACCOUNT
+			return p.processMoveAccountFromFederator(ctx, federatorMsg)
+		}
 	}
 
 	// not a combination we can/need to process
@@ -143,10 +151,18 @@ func (p *processor) processCreateStatusFromFederator(ctx context.Context, federa
 		return err
 	}
 
+	if err := p.updateConversations(ctx, status); err != nil {
+		return err
+	}
+
 	if err := p.notifyStatus(ctx, status); err != nil {
 		return err
 	}
 
+	if err := p.trackStatusLinks(ctx, status); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -232,6 +248,15 @@ func (p *processor) processCreateFollowRequestFromFederator(ctx context.Context,
 		return p.notifyFollowRequest(ctx, followRequest)
 	}
 
+	// follows from silenced domains always have to be approved, even if the target account isn't locked
+	silenced, err := p.db.IsDomainSilenced(ctx, followRequest.Account.Domain)
+	if err != nil {
+		return err
+	}
+	if silenced {
+		return p.notifyFollowRequest(ctx, followRequest)
+	}
+
 	// if the target account isn't locked, we should already accept the follow and notify about the new follower instead
 	follow, err := p.db.AcceptFollowRequest(ctx, followRequest.AccountID, followRequest.TargetAccountID)
 	if err != nil {
@@ -318,6 +343,14 @@ func (p *processor) processCreateBlockFromFederator(ctx context.Context, federat
 	// TODO: same with notifications
 	// TODO: same with bookmarks
 
+	// the blocked account can't keep featuring the account that blocked it
+	if err := p.db.DeleteWhere(ctx, []db.Where{
+		{Key: "account_id", Value: block.TargetAccountID},
+		{Key: "target_account_id", Value: block.AccountID},
+	}, &gtsmodel.Endorsement{}); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error removing endorsement from db: %s", err)
+	}
+
 	return nil
 }
 
@@ -370,6 +403,26 @@ func (p *processor) processDeleteStatusFromFederator(ctx context.Context, federa
 		return err
 	}
 
+	// delete the poll for this status, if it has one
+	if err := p.deleteStatusPoll(ctx, statusToDelete); err != nil {
+		return err
+	}
+
+	// delete all emoji reactions to this status
+	if err := p.db.DeleteStatusReactions(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}); err != nil {
+		return err
+	}
+
+	// delete all previous versions of this status
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "status_id", Value: statusToDelete.ID}}, &[]*gtsmodel.StatusEdit{}); err != nil {
+		return err
+	}
+
+	// remove this status from any conversations it's in
+	if err := p.deleteStatusFromConversations(ctx, statusToDelete); err != nil {
+		return err
+	}
+
 	// remove this status from any and all timelines
 	return p.deleteStatusFromTimelines(ctx, statusToDelete)
 }
@@ -383,3 +436,63 @@ func (p *processor) processDeleteAccountFromFederator(ctx context.Context, feder
 
 	return p.accountProcessor.Delete(ctx, account, account.ID)
 }
+
+// processMoveAccountFromFederator handles Activity Move and Object Profile
+func (p *processor) processMoveAccountFromFederator(ctx context.Context, federatorMsg messages.FromFederator) error {
+	originAccount, ok := federatorMsg.GTSModel.(*gtsmodel.Account)
+	if !ok {
+		return errors.New("account move was not parseable as *gtsmodel.Account")
+	}
+
+	if federatorMsg.APIri == nil {
+		return errors.New("account move had no target IRI")
+	}
+
+	// get the account being moved to; if it's remote, always get a fresh
+	// copy of it, so that we see the aliases that it has set right now
+	var targetAccount *gtsmodel.Account
+	var err error
+	if federatorMsg.APIri.Host == viper.GetString(config.Keys.Host) {
+		targetAccount, err = p.db.GetAccountByURI(ctx, federatorMsg.APIri.String())
+	} else {
+		targetAccount, err = p.federator.GetRemoteAccount(ctx, federatorMsg.ReceivingAccount.Username, federatorMsg.APIri, true, true)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting move target account %s: %s", federatorMsg.APIri, err)
+	}
+
+	// the account being moved to has to agree to the move, by listing the origin account as an alias
+	var aliased bool
+	for _, alias := range targetAccount.AlsoKnownAsURIs {
+		if alias == originAccount.URI {
+			aliased = true
+			break
+		}
+	}
+	if !aliased {
+		return fmt.Errorf("move target account %s doesn't list account %s as an alias", targetAccount.URI, originAccount.URI)
+	}
+
+	if originAccount.MovedToAccountID != targetAccount.ID {
+		originAccount.MovedToAccountID = targetAccount.ID
+		if _, err := p.db.UpdateAccount(ctx, originAccount); err != nil {
+			return fmt.Errorf("error updating moved account: %s", err)
+		}
+	}
+
+	// the move is delivered to the inbox of each follower of the origin account, so we only need to take
+	// care of the receiving account here: if it follows the origin account, it should follow the target instead
+	follow := &gtsmodel.Follow{}
+	if err := p.db.GetWhere(ctx, []db.Where{
+		{Key: "account_id", Value: federatorMsg.ReceivingAccount.ID},
+		{Key: "target_account_id", Value: originAccount.ID},
+	}, follow); err != nil {
+		if err == db.ErrNoEntries {
+			return nil
+		}
+		return fmt.Errorf("error getting follow of moved account: %s", err)
+	}
+	follow.Account = federatorMsg.ReceivingAccount
+
+	return p.moveFollow(ctx, follow, targetAccount)
+}
@@ -116,7 +116,7 @@ func (suite *FromFederatorTestSuite) TestProcessReplyMention() {
 		Likeable:            true,
 	}
 
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), repliedAccount, stream.TimelineHome)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), repliedAccount, stream.TimelineHome, "")
 	suite.NoError(errWithCode)
 
 	// id the status based on the time it was created
@@ -175,7 +175,7 @@ func (suite *FromFederatorTestSuite) TestProcessFave() {
 	favedStatus := suite.testStatuses["local_account_1_status_1"]
 	favingAccount := suite.testAccounts["remote_account_1"]
 
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), favedAccount, stream.TimelineNotifications)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), favedAccount, stream.TimelineNotifications, "")
 	suite.NoError(errWithCode)
 
 	fave := &gtsmodel.StatusFave{
@@ -242,7 +242,7 @@ func (suite *FromFederatorTestSuite) TestProcessFaveWithDifferentReceivingAccoun
 	favedStatus := suite.testStatuses["local_account_1_status_1"]
 	favingAccount := suite.testAccounts["remote_account_1"]
 
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), receivingAccount, stream.TimelineHome)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), receivingAccount, stream.TimelineHome, "")
 	suite.NoError(errWithCode)
 
 	fave := &gtsmodel.StatusFave{
@@ -383,7 +383,7 @@ func (suite *FromFederatorTestSuite) TestProcessFollowRequestLocked() {
 	// target is a locked account
 	targetAccount := suite.testAccounts["local_account_2"]
 
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), targetAccount, stream.TimelineHome)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), targetAccount, stream.TimelineHome, "")
 	suite.NoError(errWithCode)
 
 	// put the follow request in the database as though it had passed through the federating db already
@@ -434,7 +434,7 @@ func (suite *FromFederatorTestSuite) TestProcessFollowRequestUnlocked() {
 	// target is an unlocked account
 	targetAccount := suite.testAccounts["local_account_1"]
 
-	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), targetAccount, stream.TimelineHome)
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), targetAccount, stream.TimelineHome, "")
 	suite.NoError(errWithCode)
 
 	// put the follow request in the database as though it had passed through the federating db already
@@ -503,6 +503,66 @@ func (suite *FromFederatorTestSuite) TestProcessFollowRequestUnlocked() {
 }
 
 // TestCreateStatusFromIRI checks if a forwarded status can be dereferenced by the processor.
+func (suite *FromFederatorTestSuite) TestProcessFollowRequestSilenced() {
+	ctx := context.Background()
+
+	originAccount := suite.testAccounts["remote_account_1"]
+
+	// target isn't locked, but the origin account's domain is silenced
+	targetAccount := suite.testAccounts["local_account_1"]
+
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 "01G0SYJ9ZJ7Q4RCHX4SSHHZX2P",
+		Domain:             originAccount.Domain,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		Severity:           gtsmodel.DomainBlockSeveritySilence,
+	}))
+
+	wssStream, errWithCode := suite.processor.OpenStreamForAccount(context.Background(), targetAccount, stream.TimelineHome, "")
+	suite.NoError(errWithCode)
+
+	// put the follow request in the database as though it had passed through the federating db already
+	satanFollowRequestTurtle := &gtsmodel.FollowRequest{
+		ID:              "01FGRYAVAWWPP926J175QGM0WV",
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		AccountID:       originAccount.ID,
+		Account:         originAccount,
+		TargetAccountID: targetAccount.ID,
+		TargetAccount:   targetAccount,
+		ShowReblogs:     true,
+		URI:             fmt.Sprintf("%s/follows/01FGRYAVAWWPP926J175QGM0WV", originAccount.URI),
+		Notify:          false,
+	}
+
+	err := suite.db.Put(ctx, satanFollowRequestTurtle)
+	suite.NoError(err)
+
+	err = suite.processor.ProcessFromFederator(ctx, messages.FromFederator{
+		APObjectType:     ap.ActivityFollow,
+		APActivityType:   ap.ActivityCreate,
+		GTSModel:         satanFollowRequestTurtle,
+		ReceivingAccount: targetAccount,
+	})
+	suite.NoError(err)
+
+	// a follow request notification should be streamed, with the requester marked as limited
+	msg := <-wssStream.Messages
+	suite.Equal(stream.EventTypeNotification, msg.Event)
+	notif := &model.Notification{}
+	err = json.Unmarshal([]byte(msg.Payload), notif)
+	suite.NoError(err)
+	suite.Equal("follow_request", notif.Type)
+	suite.Equal(originAccount.ID, notif.Account.ID)
+	suite.True(notif.Account.Limited)
+
+	// the follow wasn't accepted
+	follows, err := suite.db.IsFollowing(ctx, originAccount, targetAccount)
+	suite.NoError(err)
+	suite.False(follows)
+	suite.Empty(suite.sentHTTPRequests)
+}
+
 func (suite *FromFederatorTestSuite) TestCreateStatusFromIRI() {
 	ctx := context.Background()
 
@@ -524,6 +584,115 @@ func (suite *FromFederatorTestSuite) TestCreateStatusFromIRI() {
 	suite.Equal(statusCreator.URI, s.AccountURI)
 }
 
+func (suite *FromFederatorTestSuite) TestProcessAccountMove() {
+	ctx := context.Background()
+
+	movingAccount, err := suite.db.GetAccountByID(ctx, suite.testAccounts["remote_account_1"].ID)
+	suite.NoError(err)
+	receivingAccount := suite.testAccounts["admin_account"]
+
+	// foss_satan is moving to turtle, who lists foss_satan as an alias
+	targetAccount, err := suite.db.GetAccountByID(ctx, suite.testAccounts["local_account_2"].ID)
+	suite.NoError(err)
+	targetAccount.AlsoKnownAsURIs = []string{movingAccount.URI}
+	_, err = suite.db.UpdateAccount(ctx, targetAccount)
+	suite.NoError(err)
+
+	// admin follows foss_satan
+	adminFollowSatan := &gtsmodel.Follow{
+		ID:              "01FGRYAVAWWPP926J175QGM0WV",
+		CreatedAt:       time.Now().Add(-1 * time.Hour),
+		UpdatedAt:       time.Now().Add(-1 * time.Hour),
+		AccountID:       receivingAccount.ID,
+		TargetAccountID: movingAccount.ID,
+		ShowReblogs:     true,
+		URI:             fmt.Sprintf("%s/follows/01FGRYAVAWWPP926J175QGM0WV", receivingAccount.URI),
+		Notify:          false,
+	}
+	err = suite.db.Put(ctx, adminFollowSatan)
+	suite.NoError(err)
+
+	err = suite.processor.ProcessFromFederator(ctx, messages.FromFederator{
+		APObjectType:     ap.ObjectProfile,
+		APActivityType:   ap.ActivityMove,
+		APIri:            testrig.URLMustParse(targetAccount.URI),
+		GTSModel:         movingAccount,
+		ReceivingAccount: receivingAccount,
+	})
+	suite.NoError(err)
+
+	// foss_satan should be marked as moved to turtle
+	dbAccount, err := suite.db.GetAccountByID(ctx, movingAccount.ID)
+	suite.NoError(err)
+	suite.Equal(targetAccount.ID, dbAccount.MovedToAccountID)
+
+	// admin should have unfollowed foss_satan...
+	following, err := suite.db.IsFollowing(ctx, receivingAccount, movingAccount)
+	suite.NoError(err)
+	suite.False(following)
+
+	// ...and requested to follow turtle instead, since turtle is locked
+	requested, err := suite.db.IsFollowRequested(ctx, receivingAccount, targetAccount)
+	suite.NoError(err)
+	suite.True(requested)
+}
+
+func (suite *FromFederatorTestSuite) TestProcessAccountMoveNotAliased() {
+	ctx := context.Background()
+
+	movingAccount, err := suite.db.GetAccountByID(ctx, suite.testAccounts["remote_account_1"].ID)
+	suite.NoError(err)
+	targetAccount := suite.testAccounts["local_account_2"]
+
+	// turtle doesn't list foss_satan as an alias, so the move should be refused
+	err = suite.processor.ProcessFromFederator(ctx, messages.FromFederator{
+		APObjectType:     ap.ObjectProfile,
+		APActivityType:   ap.ActivityMove,
+		APIri:            testrig.URLMustParse(targetAccount.URI),
+		GTSModel:         movingAccount,
+		ReceivingAccount: suite.testAccounts["local_account_1"],
+	})
+	suite.Error(err)
+
+	dbAccount, err := suite.db.GetAccountByID(ctx, movingAccount.ID)
+	suite.NoError(err)
+	suite.Empty(dbAccount.MovedToAccountID)
+}
+
+func (suite *FromFederatorTestSuite) TestProcessBlockRemovesEndorsement() {
+	ctx := context.Background()
+	blockingAccount := suite.testAccounts["remote_account_1"]
+	blockedAccount := suite.testAccounts["local_account_1"]
+
+	// the local account features the remote account on its profile
+	endorsement := &gtsmodel.Endorsement{
+		ID:              "01G1TR6BADACCZ1X4ZZ7KSVNBZ",
+		AccountID:       blockedAccount.ID,
+		TargetAccountID: blockingAccount.ID,
+	}
+	suite.NoError(suite.db.Put(ctx, endorsement))
+
+	block := &gtsmodel.Block{
+		ID:              "01G1TR6BADACCZ1X4ZZ7KSVNCA",
+		URI:             blockingAccount.URI + "/blocks/01G1TR6BADACCZ1X4ZZ7KSVNCA",
+		AccountID:       blockingAccount.ID,
+		TargetAccountID: blockedAccount.ID,
+	}
+	suite.NoError(suite.db.Put(ctx, block))
+
+	err := suite.processor.ProcessFromFederator(ctx, messages.FromFederator{
+		APObjectType:     ap.ActivityBlock,
+		APActivityType:   ap.ActivityCreate,
+		GTSModel:         block,
+		ReceivingAccount: blockedAccount,
+	})
+	suite.NoError(err)
+
+	// the remote account shouldn't be featured by the account it blocked anymore
+	err = suite.db.GetByID(ctx, endorsement.ID, &gtsmodel.Endorsement{})
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
 func TestFromFederatorTestSuite(t *testing.T) {
 	suite.Run(t, &FromFederatorTestSuite{})
 }
@@ -0,0 +1,381 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+const (
+	// ImportMaxSize is the largest import file that can be uploaded, in bytes.
+	ImportMaxSize = 5 * 1024 * 1024
+	// importMaxRows is the most accounts that can be in one import file, which is the same limit Mastodon uses.
+	importMaxRows = 20000
+	// importInterval is how long to wait between accounts when working through an import, so that
+	// importing a big file doesn't flood remote instances with follows or blocks all at once.
+	importInterval = 1 * time.Second
+)
+
+// importRow is one account in an import file, along with the options for following or muting it.
+type importRow struct {
+	address           string
+	showReblogs       bool
+	notify            bool
+	hideNotifications bool
+}
+
+func (p *processor) ImportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ImportCreateRequest) (*apimodel.Import, gtserror.WithCode) {
+	importType := gtsmodel.ImportType(form.Type)
+	switch importType {
+	case gtsmodel.ImportTypeFollows, gtsmodel.ImportTypeBlocks, gtsmodel.ImportTypeMutes:
+	default:
+		err := fmt.Errorf("import type %s not recognized", form.Type)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.Data.Size > ImportMaxSize {
+		err := fmt.Errorf("import file is larger than %d bytes", ImportMaxSize)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	f, err := form.Data.Open()
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("error opening import file: %s", err))
+	}
+	defer f.Close()
+
+	// the declared size is only a hint, so don't read any more than the limit in case the file is bigger
+	data, err := io.ReadAll(io.LimitReader(f, ImportMaxSize+1))
+	if err != nil {
+		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("error reading import file: %s", err))
+	}
+	if len(data) > ImportMaxSize {
+		err := fmt.Errorf("import file is larger than %d bytes", ImportMaxSize)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// check the whole file up front, so that the caller finds out straight away if it's no good
+	rows, err := parseImportRows(data)
+	if err != nil {
+		err := fmt.Errorf("import file couldn't be read: %s", err)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+	if len(rows) == 0 {
+		err := errors.New("import file doesn't contain any accounts")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+	if len(rows) > importMaxRows {
+		err := fmt.Errorf("import file contains more than %d accounts", importMaxRows)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	importID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// keep the file in storage until it's been worked through, so that the import can carry on after a restart
+	imp := &gtsmodel.Import{
+		ID:        importID,
+		AccountID: authed.Account.ID,
+		Type:      importType,
+		State:     gtsmodel.ImportStatePending,
+		Path:      fmt.Sprintf("%s/import/%s.csv", authed.Account.ID, importID),
+		Total:     len(rows),
+	}
+	if err := p.storage.Put(imp.Path, data); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error storing import file: %s", err))
+	}
+
+	// only work through one import per account at a time, so that imports are rate limited per account
+	if err := p.db.PutImport(ctx, imp); err != nil {
+		if err := p.storage.Delete(imp.Path); err != nil && err != storage.ErrNotFound {
+			logrus.Errorf("ImportCreate: error deleting file of import %s: %s", imp.ID, err)
+		}
+
+		var alreadyExistsError *db.ErrAlreadyExists
+		if errors.As(err, &alreadyExistsError) {
+			err := errors.New("an import is already in progress")
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting import: %s", err))
+	}
+
+	// convert the import before it's handed off, since working through it changes it
+	apiImport, errWithCode := p.importToAPIImport(ctx, imp)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	go p.runImport(p.ctx, imp)
+
+	return apiImport, nil
+}
+
+func (p *processor) ImportsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Import, gtserror.WithCode) {
+	imports, err := p.db.GetAccountImports(ctx, authed.Account.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting imports: %s", err))
+	}
+
+	apiImports := []*apimodel.Import{}
+	for _, i := range imports {
+		apiImport, errWithCode := p.importToAPIImport(ctx, i)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		apiImports = append(apiImports, apiImport)
+	}
+
+	return apiImports, nil
+}
+
+func (p *processor) ImportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Import, gtserror.WithCode) {
+	imp, err := p.db.GetImportByID(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting import %s: %s", id, err))
+	}
+
+	if imp.AccountID != authed.Account.ID {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("import %s not found", id))
+	}
+
+	return p.importToAPIImport(ctx, imp)
+}
+
+func (p *processor) importToAPIImport(ctx context.Context, imp *gtsmodel.Import) (*apimodel.Import, gtserror.WithCode) {
+	apiImport, err := p.tc.ImportToAPIImport(ctx, imp)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting import %s to api import: %s", imp.ID, err))
+	}
+	return apiImport, nil
+}
+
+// parseImportRows parses a csv file of accounts in the format that Mastodon exports follows, blocks and mutes in.
+//
+// The first column is always the address of the account. Follows have show boosts and notify columns after that,
+// and mutes have a hide notifications column. Missing columns are fine, and header rows are skipped.
+func parseImportRows(data []byte) ([]importRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []importRow{}
+	for _, record := range records {
+		address := strings.TrimPrefix(strings.TrimSpace(record[0]), "@")
+		if address == "" || strings.EqualFold(address, "Account address") {
+			continue
+		}
+
+		row := importRow{
+			address:           address,
+			showReblogs:       true,
+			hideNotifications: true,
+		}
+		if len(record) > 1 {
+			// the second column means show boosts for follows, but hide notifications for mutes
+			if b, err := strconv.ParseBool(strings.TrimSpace(record[1])); err == nil {
+				row.showReblogs = b
+				row.hideNotifications = b
+			}
+		}
+		if len(record) > 2 {
+			if b, err := strconv.ParseBool(strings.TrimSpace(record[2])); err == nil {
+				row.notify = b
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// runImport works through the accounts in the given pending import, starting after any that have already
+// been processed, and waiting importInterval between each one. Progress is saved after every account.
+func (p *processor) runImport(ctx context.Context, imp *gtsmodel.Import) {
+	l := logrus.WithField("import", imp.ID)
+
+	data, err := p.storage.Get(imp.Path)
+	if err != nil {
+		l.Errorf("runImport: error getting import file: %s", err)
+		p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+		return
+	}
+
+	rows, err := parseImportRows(data)
+	if err != nil {
+		l.Errorf("runImport: error parsing import file: %s", err)
+		p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+		return
+	}
+
+	ticker := time.NewTicker(importInterval)
+	defer ticker.Stop()
+
+	start := imp.Processed
+	for i := start; i < len(rows); i++ {
+		if i != start {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+
+		// the account might have been suspended or deleted since the import started
+		account, err := p.db.GetAccountByID(ctx, imp.AccountID)
+		if err != nil {
+			l.Errorf("runImport: error getting importing account: %s", err)
+			p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+			return
+		}
+		if !account.SuspendedAt.IsZero() {
+			p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+			return
+		}
+
+		if err := p.importRow(ctx, account, imp.Type, rows[i]); err != nil {
+			l.Debugf("runImport: couldn't import %s: %s", rows[i].address, err)
+			if err := p.putImportFailure(ctx, imp, rows[i].address); err != nil {
+				l.Errorf("runImport: error putting import failure: %s", err)
+				p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+				return
+			}
+		}
+
+		imp.Processed = i + 1
+		imp.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, imp); err != nil {
+			l.Errorf("runImport: error updating import: %s", err)
+			p.finishImport(ctx, imp, gtsmodel.ImportStateFailed)
+			return
+		}
+	}
+
+	p.finishImport(ctx, imp, gtsmodel.ImportStateDone)
+}
+
+// putImportFailure stores that the account at the given address in the given import couldn't be imported.
+func (p *processor) putImportFailure(ctx context.Context, imp *gtsmodel.Import, address string) error {
+	failureID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+
+	return p.db.PutImportFailure(ctx, &gtsmodel.ImportFailure{
+		ID:       failureID,
+		ImportID: imp.ID,
+		Address:  address,
+	})
+}
+
+// importRow follows, blocks or mutes the account at the address of the given row on behalf of the given account.
+// Accounts that aren't known yet are looked up, so this can involve fetching them from remote instances.
+func (p *processor) importRow(ctx context.Context, account *gtsmodel.Account, importType gtsmodel.ImportType, row importRow) error {
+	target, err := p.searchAccountByMention(ctx, &oauth.Auth{Account: account}, "@"+row.address, true)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.New("account not found")
+	}
+	if target.ID == account.ID {
+		return errors.New("account is the importing account")
+	}
+
+	var errWithCode gtserror.WithCode
+	switch importType {
+	case gtsmodel.ImportTypeFollows:
+		_, errWithCode = p.accountProcessor.FollowCreate(ctx, account, &apimodel.AccountFollowRequest{
+			ID:      target.ID,
+			Reblogs: &row.showReblogs,
+			Notify:  &row.notify,
+		})
+	case gtsmodel.ImportTypeBlocks:
+		_, errWithCode = p.accountProcessor.BlockCreate(ctx, account, target.ID)
+	case gtsmodel.ImportTypeMutes:
+		_, errWithCode = p.accountProcessor.MuteCreate(ctx, account, target.ID, &apimodel.AccountMuteRequest{
+			Notifications: &row.hideNotifications,
+		})
+	default:
+		return fmt.Errorf("import type %s not recognized", importType)
+	}
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	return nil
+}
+
+// finishImport marks the given import with the given state, and removes its file from storage since it's no longer needed.
+func (p *processor) finishImport(ctx context.Context, imp *gtsmodel.Import, state gtsmodel.ImportState) {
+	if ctx.Err() != nil {
+		// the processor is stopping, which is likely why the import is being finished;
+		// it still needs to be marked, or it'll be left looking like it's in progress forever
+		ctx = context.Background()
+	}
+
+	if err := p.storage.Delete(imp.Path); err != nil && err != storage.ErrNotFound {
+		logrus.Errorf("finishImport: error deleting file of import %s: %s", imp.ID, err)
+	}
+
+	imp.State = state
+	imp.Path = ""
+	imp.UpdatedAt = time.Now()
+	if err := p.db.UpdateByPrimaryKey(ctx, imp); err != nil {
+		logrus.Errorf("finishImport: error updating import %s: %s", imp.ID, err)
+	}
+}
+
+// startImporter carries on working through any imports that were still pending when the processor was last stopped.
+func (p *processor) startImporter() error {
+	pending, err := p.db.GetPendingImports(p.ctx)
+	if err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error getting pending imports: %s", err)
+	}
+	for _, i := range pending {
+		go p.runImport(p.ctx, i)
+	}
+	return nil
+}
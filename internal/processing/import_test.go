@@ -0,0 +1,145 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ImportTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// importForm returns an import request with the given data as its uploaded file.
+func (suite *ImportTestSuite) importForm(importType string, data string) *apimodel.ImportCreateRequest {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fw, err := w.CreateFormFile("data", "import.csv")
+	suite.NoError(err)
+	_, err = fw.Write([]byte(data))
+	suite.NoError(err)
+	suite.NoError(w.Close())
+
+	form, err := multipart.NewReader(body, w.Boundary()).ReadForm(1 << 20)
+	suite.NoError(err)
+
+	return &apimodel.ImportCreateRequest{
+		Type: importType,
+		Data: form.File["data"][0],
+	}
+}
+
+// importAndWait starts an import of the given data, and waits for it to be worked through.
+func (suite *ImportTestSuite) importAndWait(authed *oauth.Auth, importType string, data string) *apimodel.Import {
+	ctx := context.Background()
+
+	imp, errWithCode := suite.processor.ImportCreate(ctx, authed, suite.importForm(importType, data))
+	suite.NoError(errWithCode)
+	suite.Equal(importType, imp.Type)
+
+	for i := 0; imp.State == "pending" && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		imp, errWithCode = suite.processor.ImportGet(ctx, authed, imp.ID)
+		suite.NoError(errWithCode)
+	}
+	suite.Equal("done", imp.State)
+	return imp
+}
+
+func (suite *ImportTestSuite) TestImportBlocks() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	imp := suite.importAndWait(authed, "blocks", "@1happyturtle@localhost:8080\nnobody@localhost:8080\n")
+	suite.Equal(2, imp.Total)
+	suite.Equal(2, imp.Processed)
+	suite.Equal([]string{"nobody@localhost:8080"}, imp.FailedAddresses)
+
+	blocked, err := suite.db.IsBlocked(ctx, authed.Account.ID, suite.testAccounts["local_account_2"].ID, false)
+	suite.NoError(err)
+	suite.True(blocked)
+
+	imports, errWithCode := suite.processor.ImportsGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Len(imports, 1)
+}
+
+func (suite *ImportTestSuite) TestImportMutes() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	imp := suite.importAndWait(authed, "mutes", "Account address,Hide notifications\nfoss_satan@fossbros-anonymous.io,false\n")
+	suite.Equal(1, imp.Total)
+	suite.Empty(imp.FailedAddresses)
+
+	mutes, err := suite.db.GetAccountMutes(ctx, authed.Account.ID, "", "", 0)
+	suite.NoError(err)
+	suite.Len(mutes, 1)
+	suite.Equal(suite.testAccounts["remote_account_1"].ID, mutes[0].TargetAccountID)
+	suite.False(mutes[0].Notifications)
+}
+
+func (suite *ImportTestSuite) TestImportAlreadyInProgress() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	imp, errWithCode := suite.processor.ImportCreate(ctx, authed, suite.importForm("blocks", "1happyturtle@localhost:8080\nadmin@localhost:8080\n"))
+	suite.NoError(errWithCode)
+
+	_, errWithCode = suite.processor.ImportCreate(ctx, authed, suite.importForm("mutes", "1happyturtle@localhost:8080\n"))
+	suite.EqualError(errWithCode, "an import is already in progress")
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	// let the first import finish before the next test starts
+	for i := 0; imp.State == "pending" && i < 50; i++ {
+		time.Sleep(100 * time.Millisecond)
+		imp, errWithCode = suite.processor.ImportGet(ctx, authed, imp.ID)
+		suite.NoError(errWithCode)
+	}
+	suite.Equal("done", imp.State)
+}
+
+func (suite *ImportTestSuite) TestImportBadFile() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	_, errWithCode := suite.processor.ImportCreate(ctx, authed, suite.importForm("follows", "Account address,Show boosts\n"))
+	suite.EqualError(errWithCode, "import file doesn't contain any accounts")
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	_, errWithCode = suite.processor.ImportCreate(ctx, authed, suite.importForm("follows", "\"1happyturtle@localhost:8080\n"))
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	_, errWithCode = suite.processor.ImportCreate(ctx, authed, suite.importForm("statuses", "1happyturtle@localhost:8080\n"))
+	suite.EqualError(errWithCode, "import type statuses not recognized")
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func TestImportTestSuite(t *testing.T) {
+	suite.Run(t, &ImportTestSuite{})
+}
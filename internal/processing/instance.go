@@ -46,6 +46,38 @@ func (p *processor) InstanceGet(ctx context.Context, domain string) (*apimodel.I
 	return ai, nil
 }
 
+func (p *processor) InstanceGetV2(ctx context.Context, domain string) (*apimodel.InstanceV2, gtserror.WithCode) {
+	i := &gtsmodel.Instance{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "domain", Value: domain}}, i); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("db error fetching instance %s: %s", domain, err))
+	}
+
+	ai, err := p.tc.InstanceToAPIV2Instance(ctx, i)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting instance to api representation: %s", err))
+	}
+
+	return ai, nil
+}
+
+func (p *processor) InstanceRulesGet(ctx context.Context) ([]apimodel.InstanceRule, gtserror.WithCode) {
+	rules, err := p.db.GetActiveRules(ctx)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("db error fetching rules: %s", err))
+	}
+
+	apiRules := make([]apimodel.InstanceRule, 0, len(rules))
+	for _, r := range rules {
+		apiRule, err := p.tc.RuleToAPIInstanceRule(ctx, r)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting rule to api representation: %s", err))
+		}
+		apiRules = append(apiRules, apiRule)
+	}
+
+	return apiRules, nil
+}
+
 func (p *processor) InstancePatch(ctx context.Context, form *apimodel.InstanceSettingsUpdateRequest) (*apimodel.Instance, gtserror.WithCode) {
 	// fetch the instance entry from the db for processing
 	i := &gtsmodel.Instance{}
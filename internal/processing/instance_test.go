@@ -0,0 +1,87 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+)
+
+type InstanceTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *InstanceTestSuite) TestInstanceGetLimits() {
+	viper.Set(config.Keys.StatusesMaxChars, 500)
+	viper.Set(config.Keys.StatusesCWMaxChars, 50)
+	viper.Set(config.Keys.StatusesMediaMaxFiles, 4)
+	viper.Set(config.Keys.StatusesPollMaxOptions, 4)
+	viper.Set(config.Keys.StatusesPollOptionMaxChars, 25)
+
+	instance, err := suite.processor.InstanceGet(context.Background(), "localhost:8080")
+	suite.NoError(err)
+	suite.EqualValues(500, instance.MaxTootChars)
+	suite.Equal(&model.InstanceConfigurationStatuses{
+		MaxCharacters:               500,
+		MaxContentWarningCharacters: 50,
+		MaxMediaAttachments:         4,
+		CharactersReservedPerURL:    23,
+	}, instance.Configuration.Statuses)
+	suite.Equal(&model.InstanceConfigurationPolls{
+		MaxOptions:             4,
+		MaxCharactersPerOption: 25,
+		MinExpiration:          300,
+		MaxExpiration:          2678400,
+	}, instance.Configuration.Polls)
+
+	// v2 should advertise the same limits
+	instanceV2, err := suite.processor.InstanceGetV2(context.Background(), "localhost:8080")
+	suite.NoError(err)
+	suite.Equal(*instance.Configuration.Statuses, instanceV2.Configuration.Statuses)
+	suite.Equal(*instance.Configuration.Polls, instanceV2.Configuration.Polls)
+}
+
+func (suite *InstanceTestSuite) TestInstanceGetV2() {
+	instance, err := suite.processor.InstanceGetV2(context.Background(), "localhost:8080")
+	suite.NoError(err)
+	suite.Equal("localhost:8080", instance.Domain)
+	suite.Equal("wss://localhost:8080", instance.Configuration.URLs.Streaming)
+	suite.Equal(viper.GetInt(config.Keys.StatusesMaxChars), instance.Configuration.Statuses.MaxCharacters)
+	suite.Equal(viper.GetInt(config.Keys.MediaImageMaxSize), instance.Configuration.MediaAttachments.ImageSizeLimit)
+	suite.False(instance.Configuration.Translation.Enabled)
+	suite.NotNil(instance.Rules)
+}
+
+func (suite *InstanceTestSuite) TestInstanceGetV2TranslationEnabled() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendDeepL)
+
+	instance, err := suite.processor.InstanceGetV2(context.Background(), "localhost:8080")
+	suite.NoError(err)
+	suite.True(instance.Configuration.Translation.Enabled)
+}
+
+func TestInstanceTestSuite(t *testing.T) {
+	suite.Run(t, &InstanceTestSuite{})
+}
@@ -0,0 +1,45 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ipBlockCleanerSchedule is how often expired IP blocks are looked for and deleted.
+const ipBlockCleanerSchedule = "@every 1m"
+
+// startIPBlockCleaner starts a cron job that deletes IP blocks once they've expired.
+//
+// Expired IP blocks are already ignored when requests and sign-ups are checked,
+// so this just stops them from showing up to admins after they've run out.
+func (p *processor) startIPBlockCleaner() error {
+	if err := p.startScheduledJob(ipBlockCleanerSchedule, func(ctx context.Context) {
+		if err := p.db.DeleteExpiredIPBlocks(ctx, time.Now()); err != nil {
+			logrus.Errorf("ip block cleaner: error deleting expired ip blocks: %s", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("error starting ip block cleaner job: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+)
+
+func (p *processor) MarkersGet(ctx context.Context, authed *oauth.Auth, timelines []string) (*apimodel.Marker, gtserror.WithCode) {
+	names := []gtsmodel.MarkerName{}
+	for _, timeline := range timelines {
+		name := gtsmodel.MarkerName(timeline)
+		if name != gtsmodel.MarkerNameHome && name != gtsmodel.MarkerNameNotifications {
+			// unknown timelines are just skipped, as mastodon does
+			continue
+		}
+		names = append(names, name)
+	}
+
+	markers := []*gtsmodel.Marker{}
+	for _, name := range names {
+		marker, err := p.db.GetMarker(ctx, authed.Account.ID, name)
+		if err != nil {
+			if err == db.ErrNoEntries {
+				// no marker set for this timeline yet
+				continue
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting %s marker: %s", name, err))
+		}
+		markers = append(markers, marker)
+	}
+
+	apiMarker, err := p.tc.MarkersToAPIMarker(ctx, markers)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting markers to api representation: %s", err))
+	}
+
+	return apiMarker, nil
+}
+
+func (p *processor) MarkersSet(ctx context.Context, authed *oauth.Auth, form *apimodel.MarkerPostRequest) (*apimodel.Marker, gtserror.WithCode) {
+	lastReadIDs := map[gtsmodel.MarkerName]string{}
+	if homeID := form.HomeID(); homeID != "" {
+		lastReadIDs[gtsmodel.MarkerNameHome] = homeID
+	}
+	if notificationsID := form.NotificationsID(); notificationsID != "" {
+		lastReadIDs[gtsmodel.MarkerNameNotifications] = notificationsID
+	}
+
+	if len(lastReadIDs) == 0 {
+		err := errors.New("no last read id given for home or notifications")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	for name, lastReadID := range lastReadIDs {
+		if !validate.ULID(lastReadID) {
+			err := fmt.Errorf("last read id %s for %s is not valid", lastReadID, name)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	markers := []*gtsmodel.Marker{}
+	for _, name := range gtsmodel.MarkerNames {
+		lastReadID, ok := lastReadIDs[name]
+		if !ok {
+			continue
+		}
+
+		marker, errWithCode := p.setMarker(ctx, authed.Account.ID, name, lastReadID)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		markers = append(markers, marker)
+	}
+
+	apiMarker, err := p.tc.MarkersToAPIMarker(ctx, markers)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting markers to api representation: %s", err))
+	}
+
+	return apiMarker, nil
+}
+
+// setMarker creates or updates the marker of the given account for the given timeline, bumping its version.
+func (p *processor) setMarker(ctx context.Context, accountID string, name gtsmodel.MarkerName, lastReadID string) (*gtsmodel.Marker, gtserror.WithCode) {
+	marker, err := p.db.GetMarker(ctx, accountID, name)
+	if err != nil {
+		if err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting %s marker: %s", name, err))
+		}
+
+		// no marker for this timeline yet, so make one
+		marker = &gtsmodel.Marker{
+			AccountID:  accountID,
+			Name:       name,
+			UpdatedAt:  time.Now(),
+			LastReadID: lastReadID,
+		}
+		if err := p.db.Put(ctx, marker); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting %s marker: %s", name, err))
+		}
+		return marker, nil
+	}
+
+	marker.LastReadID = lastReadID
+	marker.UpdatedAt = time.Now()
+	marker.Version++
+	if err := p.db.UpdateByPrimaryKey(ctx, marker); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating %s marker: %s", name, err))
+	}
+	return marker, nil
+}
@@ -0,0 +1,106 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type MarkerTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *MarkerTestSuite) TestMarkersSetAndGet() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	// nothing set yet
+	markers, errWithCode := suite.processor.MarkersGet(ctx, authed, []string{"home", "notifications"})
+	suite.NoError(errWithCode)
+	suite.Nil(markers.Home)
+	suite.Nil(markers.Notifications)
+
+	// set home only, using a form request
+	markers, errWithCode = suite.processor.MarkersSet(ctx, authed, &apimodel.MarkerPostRequest{
+		HomeLastReadID: "01F8MH75CBF9JFX4ZAD54N0W0R",
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("01F8MH75CBF9JFX4ZAD54N0W0R", markers.Home.LastReadID)
+	suite.Equal(0, markers.Home.Version)
+	suite.NotEmpty(markers.Home.UpdatedAt)
+	suite.Nil(markers.Notifications)
+
+	// set both, using a JSON request
+	markers, errWithCode = suite.processor.MarkersSet(ctx, authed, &apimodel.MarkerPostRequest{
+		Home:          &apimodel.MarkerTimelinePostRequest{LastReadID: "01G20ZM733MGN8J344T4ZDDFY1"},
+		Notifications: &apimodel.MarkerTimelinePostRequest{LastReadID: "01F8Q0ANPTWW10DAKTX7BRPBJP"},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("01G20ZM733MGN8J344T4ZDDFY1", markers.Home.LastReadID)
+	suite.Equal(1, markers.Home.Version)
+	suite.Equal("01F8Q0ANPTWW10DAKTX7BRPBJP", markers.Notifications.LastReadID)
+	suite.Equal(0, markers.Notifications.Version)
+
+	// another client sees the same positions
+	markers, errWithCode = suite.processor.MarkersGet(ctx, authed, []string{"home", "notifications"})
+	suite.NoError(errWithCode)
+	suite.Equal("01G20ZM733MGN8J344T4ZDDFY1", markers.Home.LastReadID)
+	suite.Equal(1, markers.Home.Version)
+	suite.Equal("01F8Q0ANPTWW10DAKTX7BRPBJP", markers.Notifications.LastReadID)
+
+	// only the requested timelines are returned
+	markers, errWithCode = suite.processor.MarkersGet(ctx, authed, []string{"notifications", "public"})
+	suite.NoError(errWithCode)
+	suite.Nil(markers.Home)
+	suite.NotNil(markers.Notifications)
+
+	// markers belong to the account that set them
+	turtleAuthed := &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     suite.testAccounts["local_account_2"],
+	}
+	markers, errWithCode = suite.processor.MarkersGet(ctx, turtleAuthed, []string{"home", "notifications"})
+	suite.NoError(errWithCode)
+	suite.Nil(markers.Home)
+	suite.Nil(markers.Notifications)
+}
+
+func (suite *MarkerTestSuite) TestMarkersSetInvalid() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	_, errWithCode := suite.processor.MarkersSet(ctx, authed, &apimodel.MarkerPostRequest{})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	_, errWithCode = suite.processor.MarkersSet(ctx, authed, &apimodel.MarkerPostRequest{
+		NotificationsLastReadID: "not an id",
+	})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func TestMarkerTestSuite(t *testing.T) {
+	suite.Run(t, &MarkerTestSuite{})
+}
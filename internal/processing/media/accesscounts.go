@@ -0,0 +1,53 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media
+
+import (
+	"context"
+	"fmt"
+)
+
+// countAccess counts one access to the attachment with the given id, to be stored by the next FlushAccessCounts.
+func (p *processor) countAccess(attachmentID string) {
+	p.accessCountsMu.Lock()
+	defer p.accessCountsMu.Unlock()
+	p.accessCounts[attachmentID]++
+}
+
+func (p *processor) FlushAccessCounts(ctx context.Context) error {
+	p.accessCountsMu.Lock()
+	counts := p.accessCounts
+	p.accessCounts = make(map[string]int)
+	p.accessCountsMu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	if err := p.db.IncrementAccessCounts(ctx, counts); err != nil {
+		// put the counts back, so that they can be stored by the next flush instead
+		p.accessCountsMu.Lock()
+		for id, count := range counts {
+			p.accessCounts[id] += count
+		}
+		p.accessCountsMu.Unlock()
+		return fmt.Errorf("FlushAccessCounts: error storing access counts for %d attachments: %s", len(counts), err)
+	}
+	return nil
+}
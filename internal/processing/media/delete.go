@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"codeberg.org/gruf/go-store/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 func (p *processor) Delete(ctx context.Context, mediaAttachmentID string) gtserror.WithCode {
@@ -23,12 +25,20 @@ func (p *processor) Delete(ctx context.Context, mediaAttachmentID string) gtserr
 	errs := []string{}
 
 	// delete the thumbnail from storage
-	if attachment.Thumbnail.Path != "" {
+	if attachment.Thumbnail.Path != "" && attachment.Type != gtsmodel.FileTypeUnknown {
 		if err := p.storage.Delete(attachment.Thumbnail.Path); err != nil {
 			errs = append(errs, fmt.Sprintf("remove thumbnail at path %s: %s", attachment.Thumbnail.Path, err))
 		}
 	}
 
+	// delete any thumbnail variants from storage
+	for _, variant := range attachment.Variants {
+		// the variants of remote media might already have been pruned from storage
+		if err := p.storage.Delete(variant.Path); err != nil && err != storage.ErrNotFound {
+			errs = append(errs, fmt.Sprintf("remove %s thumbnail variant at path %s: %s", variant.Name, variant.Path, err))
+		}
+	}
+
 	// delete the file from storage
 	if attachment.File.Path != "" {
 		if err := p.storage.Delete(attachment.File.Path); err != nil {
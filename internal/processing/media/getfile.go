@@ -25,8 +25,11 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/spf13/viper"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
@@ -34,7 +37,7 @@ import (
 
 func (p *processor) GetFile(ctx context.Context, account *gtsmodel.Account, form *apimodel.GetContentRequestForm) (*apimodel.Content, gtserror.WithCode) {
 	// parse the form fields
-	mediaSize, err := media.ParseMediaSize(form.MediaSize)
+	mediaSize, err := p.mediaManager.ParseMediaSize(form.MediaSize)
 	if err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("media size %s not valid", form.MediaSize))
 	}
@@ -77,15 +80,14 @@ func (p *processor) GetFile(ctx context.Context, account *gtsmodel.Account, form
 	case media.TypeEmoji:
 		return p.getEmojiContent(ctx, wantedMediaID, mediaSize)
 	case media.TypeAttachment, media.TypeHeader, media.TypeAvatar:
-		return p.getAttachmentContent(ctx, account, wantedMediaID, expectedAccountID, mediaSize)
+		return p.getAttachmentContent(ctx, wantedMediaID, expectedAccountID, mediaSize)
 	default:
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("media type %s not recognized", mediaType))
 	}
 }
 
-func (p *processor) getAttachmentContent(ctx context.Context, requestingAccount *gtsmodel.Account, wantedMediaID string, expectedAccountID string, mediaSize media.Size) (*apimodel.Content, gtserror.WithCode) {
+func (p *processor) getAttachmentContent(ctx context.Context, wantedMediaID string, expectedAccountID string, mediaSize media.Size) (*apimodel.Content, gtserror.WithCode) {
 	attachmentContent := &apimodel.Content{}
-	var storagePath string
 
 	// retrieve attachment from the database and do basic checks on it
 	a, err := p.db.GetAttachmentByID(ctx, wantedMediaID)
@@ -98,48 +100,107 @@ func (p *processor) getAttachmentContent(ctx context.Context, requestingAccount
 	}
 
 	// get file information from the attachment depending on the requested media size
-	switch mediaSize {
-	case media.SizeOriginal:
-		attachmentContent.ContentType = a.File.ContentType
-		attachmentContent.ContentLength = int64(a.File.FileSize)
-		storagePath = a.File.Path
-	case media.SizeSmall:
-		attachmentContent.ContentType = a.Thumbnail.ContentType
-		attachmentContent.ContentLength = int64(a.Thumbnail.FileSize)
-		storagePath = a.Thumbnail.Path
-	default:
-		return nil, gtserror.NewErrorNotFound(fmt.Errorf("media size %s not recognized for attachment", mediaSize))
+	storagePath, errWithCode := attachmentFile(a, mediaSize, attachmentContent)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// keep track of how popular remote media is, so that it can be recached ahead of time once it's been pruned
+	if a.RemoteURL != "" {
+		p.countAccess(a.ID)
 	}
 
 	// if we have the media cached on our server already, we can now simply return it from storage
 	if a.Cached {
-		return p.streamFromStorage(storagePath, attachmentContent)
+		return p.streamFromStorage(ctx, storagePath, attachmentContent)
+	}
+
+	// media from silenced domains, or domains whose media is rejected, is never cached, so it can't be fetched through this instance either
+	owner, err := p.db.GetAccountByID(ctx, expectedAccountID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("account with id %s could not be selected from the db: %s", expectedAccountID, err))
+	}
+	rejected, err := p.db.IsDomainMediaRejected(ctx, owner.Domain)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking whether media from domain %s is rejected: %s", owner.Domain, err))
+	}
+	if rejected {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("attachment %s is from domain %s, whose media is rejected", wantedMediaID, owner.Domain))
 	}
 
 	// if we don't have it cached, then we can assume two things:
 	// 1. this is remote media, since local media should never be uncached
 	// 2. we need to fetch it again using a transport and the media manager
-	remoteMediaIRI, err := url.Parse(a.RemoteURL)
-	if err != nil {
-		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error parsing remote media iri %s: %s", a.RemoteURL, err))
+	//
+	// someone else might have requested this media already, in which case it's
+	// being fetched already and we should just wait for that to finish
+	processingMedia, started, errWithCode := p.recacheAttachment(ctx, a, mediaSize, attachmentContent)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// if we kicked off the recache of the full-sized version, it's already being streamed to the caller
+	if started && mediaSize == media.SizeOriginal {
+		return attachmentContent, nil
+	}
+
+	// otherwise the caller will have to wait for the recache to finish
+	return p.waitForRecache(ctx, processingMedia, mediaSize, attachmentContent)
+}
+
+// attachmentFile returns the storage path of the file of the given attachment that corresponds
+// to the given media size, and sets the content type and length of that file on content.
+func attachmentFile(a *gtsmodel.MediaAttachment, mediaSize media.Size, content *apimodel.Content) (string, gtserror.WithCode) {
+	switch mediaSize {
+	case media.SizeOriginal:
+		content.ContentType = a.File.ContentType
+		content.ContentLength = int64(a.File.FileSize)
+		content.Download = a.Type == gtsmodel.FileTypeUnknown
+		return a.File.Path, nil
+	case media.SizeSmall:
+		if a.Type == gtsmodel.FileTypeUnknown {
+			// media of unknown types doesn't have a thumbnail
+			return "", gtserror.NewErrorNotFound(fmt.Errorf("attachment %s has no thumbnail", a.ID))
+		}
+		content.ContentType = a.Thumbnail.ContentType
+		content.ContentLength = int64(a.Thumbnail.FileSize)
+		return a.Thumbnail.Path, nil
+	}
+
+	// it might be one of the thumbnail variants
+	for _, variant := range a.Variants {
+		if variant.Name == string(mediaSize) {
+			content.ContentType = variant.ContentType
+			content.ContentLength = int64(variant.FileSize)
+			return variant.Path, nil
+		}
 	}
 
-	// use an empty string as requestingUsername to use the instance account, unless the request for this
-	// media has been http signed, then use the requesting account to make the request to remote server
-	var requestingUsername string
-	if requestingAccount != nil {
-		requestingUsername = requestingAccount.Username
+	return "", gtserror.NewErrorNotFound(fmt.Errorf("media size %s not recognized for attachment", mediaSize))
+}
+
+// recacheAttachment puts the given uncached remote attachment in the media manager queue to be fetched again,
+// unless it's already being fetched again, in which case the recache that's in progress is returned instead.
+// The returned bool is true if a new recache was started.
+//
+// If the full-sized version of the attachment is being requested, it will be streamed to the caller through
+// the given content as it's fetched from the remote server. Media is cached for the whole instance rather than
+// for whoever requested it, so it's fetched by the instance actor.
+func (p *processor) recacheAttachment(ctx context.Context, a *gtsmodel.MediaAttachment, mediaSize media.Size, attachmentContent *apimodel.Content) (*media.ProcessingMedia, bool, gtserror.WithCode) {
+	remoteMediaIRI, err := url.Parse(a.RemoteURL)
+	if err != nil {
+		return nil, false, gtserror.NewErrorNotFound(fmt.Errorf("error parsing remote media iri %s: %s", a.RemoteURL, err))
 	}
 
 	var data media.DataFunc
 	var postDataCallback media.PostDataCallbackFunc
 
-	if mediaSize == media.SizeSmall {
-		// if it's the thumbnail that's requested then the user will have to wait a bit while we process the
+	if mediaSize != media.SizeOriginal {
+		// if it's a thumbnail that's requested then the user will have to wait a bit while we process the
 		// large version and derive a thumbnail from it, so use the normal recaching procedure: fetch the media,
 		// process it, then return the thumbnail data
 		data = func(innerCtx context.Context) (io.Reader, int, error) {
-			transport, err := p.transportController.NewTransportForUsername(innerCtx, requestingUsername)
+			transport, err := p.transportController.NewTransportForInstance(innerCtx)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -170,7 +231,7 @@ func (p *processor) getAttachmentContent(ctx context.Context, requestingAccount
 		attachmentContent.Content = bufferedReader
 
 		data = func(innerCtx context.Context) (io.Reader, int, error) {
-			transport, err := p.transportController.NewTransportForUsername(innerCtx, requestingUsername)
+			transport, err := p.transportController.NewTransportForInstance(innerCtx)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -209,22 +270,47 @@ func (p *processor) getAttachmentContent(ctx context.Context, requestingAccount
 	}
 
 	// put the media recached in the queue
-	processingMedia, err := p.mediaManager.RecacheMedia(ctx, data, postDataCallback, wantedMediaID)
+	processingMedia, started, err := p.mediaManager.RecacheMedia(ctx, data, postDataCallback, a.ID)
 	if err != nil {
-		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error recaching media: %s", err))
+		return nil, false, gtserror.NewErrorNotFound(fmt.Errorf("error recaching media: %s", err))
+	}
+
+	return processingMedia, started, nil
+}
+
+// waitForRecache waits for the given recache to finish, and then streams the file corresponding to the given
+// media size from storage to the caller.
+//
+// If the recache doesn't finish within the configured timeout, content will be returned with Pending set to
+// true, so that the caller can be told to try again later rather than hanging around.
+func (p *processor) waitForRecache(ctx context.Context, processingMedia *media.ProcessingMedia, mediaSize media.Size, attachmentContent *apimodel.Content) (*apimodel.Content, gtserror.WithCode) {
+	// if the timeout is 0, wait as long as it takes
+	var timeout <-chan time.Time
+	if seconds := viper.GetInt(config.Keys.MediaRemoteRecacheTimeout); seconds > 0 {
+		timeout = time.After(time.Duration(seconds) * time.Second)
 	}
 
-	// if it's the thumbnail, stream the processed thumbnail from storage, after waiting for processing to finish
-	if mediaSize == media.SizeSmall {
-		// below function call blocks until all processing on the attachment has finished...
-		if _, err := processingMedia.LoadAttachment(ctx); err != nil {
+	// the recache is done by the media manager's worker pool, so just wait for it to be finished
+	select {
+	case <-processingMedia.Done():
+		// processing is finished, so this won't block
+		attachment, err := processingMedia.LoadAttachment(ctx)
+		if err != nil {
 			return nil, gtserror.NewErrorNotFound(fmt.Errorf("error loading recached attachment: %s", err))
 		}
-		// ... so now we can safely return it
-		return p.streamFromStorage(storagePath, attachmentContent)
-	}
 
-	return attachmentContent, nil
+		// the recache might have changed the files of the attachment, so look them up again
+		storagePath, errWithCode := attachmentFile(attachment, mediaSize, attachmentContent)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+		return p.streamFromStorage(ctx, storagePath, attachmentContent)
+	case <-timeout:
+		attachmentContent.Pending = true
+		return attachmentContent, nil
+	case <-ctx.Done():
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("gave up waiting for recached attachment: %s", ctx.Err()))
+	}
 }
 
 func (p *processor) getEmojiContent(ctx context.Context, wantedEmojiID string, emojiSize media.Size) (*apimodel.Content, gtserror.WithCode) {
@@ -253,11 +339,32 @@ func (p *processor) getEmojiContent(ctx context.Context, wantedEmojiID string, e
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("media size %s not recognized for emoji", emojiSize))
 	}
 
-	return p.streamFromStorage(storagePath, emojiContent)
+	return p.streamFromStorage(ctx, storagePath, emojiContent)
 }
 
-func (p *processor) streamFromStorage(storagePath string, content *apimodel.Content) (*apimodel.Content, gtserror.WithCode) {
-	reader, err := p.storage.GetStream(storagePath)
+func (p *processor) streamFromStorage(ctx context.Context, storagePath string, content *apimodel.Content) (*apimodel.Content, gtserror.WithCode) {
+	// if the storage driver can give out a url for the caller to fetch the content
+	// from directly (eg., a presigned s3 url), we don't need to stream it ourselves.
+	//
+	// Content that has to be served with extra security headers (downloads and svgs) is always
+	// streamed by us instead, since the response from the storage backend won't have those headers,
+	// and any headers set on the redirect to it aren't carried over to what it redirects to.
+	if !needsSecurityHeaders(content) {
+		if u := p.storage.URL(ctx, storagePath); u != nil {
+			content.URL = u
+			return content, nil
+		}
+	}
+
+	// video and audio is usually played from wherever the listener or viewer skips to, rather than
+	// downloaded all in one go, so make sure it can be served in parts for http range requests
+	var reader io.ReadCloser
+	var err error
+	if strings.HasPrefix(content.ContentType, "video/") || strings.HasPrefix(content.ContentType, "audio/") {
+		reader, err = p.storage.GetSeekable(storagePath)
+	} else {
+		reader, err = p.storage.GetStream(storagePath)
+	}
 	if err != nil {
 		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error retrieving from storage: %s", err))
 	}
@@ -265,3 +372,9 @@ func (p *processor) streamFromStorage(storagePath string, content *apimodel.Cont
 	content.Content = reader
 	return content, nil
 }
+
+// needsSecurityHeaders returns true if the given content has to be served along with headers that
+// stop browsers from rendering it, or from running anything in it, when it's opened directly.
+func needsSecurityHeaders(content *apimodel.Content) bool {
+	return content.Download || content.ContentType == "image/svg+xml"
+}
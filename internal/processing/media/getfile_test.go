@@ -21,13 +21,19 @@ package media_test
 import (
 	"context"
 	"io"
+	"net/url"
 	"path"
 	"testing"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	mediaprocessing "github.com/superseriousbusiness/gotosocial/internal/processing/media"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 )
 
 type GetFileTestSuite struct {
@@ -60,6 +66,12 @@ func (suite *GetFileTestSuite) TestGetRemoteFileCached() {
 	suite.Equal(suite.testRemoteAttachments[testAttachment.RemoteURL].Data, b)
 	suite.Equal(suite.testRemoteAttachments[testAttachment.RemoteURL].ContentType, content.ContentType)
 	suite.EqualValues(len(suite.testRemoteAttachments[testAttachment.RemoteURL].Data), content.ContentLength)
+
+	// the access should have been counted, and stored once counts are flushed
+	suite.NoError(suite.mediaProcessor.FlushAccessCounts(ctx))
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+	suite.NoError(err)
+	suite.Equal(1, dbAttachment.AccessCount)
 }
 
 func (suite *GetFileTestSuite) TestGetRemoteFileUncached() {
@@ -203,6 +215,156 @@ func (suite *GetFileTestSuite) TestGetRemoteFileThumbnailUncached() {
 	suite.EqualValues(testAttachment.Thumbnail.FileSize, content.ContentLength)
 }
 
+func (suite *GetFileTestSuite) TestGetRemoteFileThumbnailUncachedPending() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	// uncache the file from local
+	testAttachment.Cached = false
+	err := suite.db.UpdateByPrimaryKey(ctx, testAttachment)
+	suite.NoError(err)
+	err = suite.storage.Delete(testAttachment.File.Path)
+	suite.NoError(err)
+	err = suite.storage.Delete(testAttachment.Thumbnail.Path)
+	suite.NoError(err)
+
+	// make the remote server slower to respond than we're willing to wait
+	suite.remoteDelay = 2 * time.Second
+	viper.Set(config.Keys.MediaRemoteRecacheTimeout, 1)
+	defer viper.Set(config.Keys.MediaRemoteRecacheTimeout, 10)
+
+	fileName := path.Base(testAttachment.File.Path)
+	requestingAccount := suite.testAccounts["local_account_1"]
+	form := &apimodel.GetContentRequestForm{
+		AccountID: testAttachment.AccountID,
+		MediaType: string(media.TypeAttachment),
+		MediaSize: string(media.SizeSmall),
+		FileName:  fileName,
+	}
+
+	// the first request should give up waiting and tell us to come back later
+	content, errWithCode := suite.mediaProcessor.GetFile(ctx, requestingAccount, form)
+	suite.NoError(errWithCode)
+	suite.True(content.Pending)
+	suite.Nil(content.Content)
+
+	// a second request should join the recache that's already in progress, and get the thumbnail once it's done
+	viper.Set(config.Keys.MediaRemoteRecacheTimeout, 0)
+	content, errWithCode = suite.mediaProcessor.GetFile(ctx, requestingAccount, form)
+	suite.NoError(errWithCode)
+	suite.False(content.Pending)
+	suite.NotNil(content.Content)
+
+	b, err := io.ReadAll(content.Content)
+	suite.NoError(err)
+	if closer, ok := content.Content.(io.Closer); ok {
+		suite.NoError(closer.Close())
+	}
+	suite.NotEmpty(b)
+	suite.Equal("image/jpeg", content.ContentType)
+
+	// the attachment should be cached again
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+	suite.NoError(err)
+	suite.True(dbAttachment.Cached)
+}
+
+func (suite *GetFileTestSuite) TestGetRemoteFileUncachedConcurrent() {
+	ctx := context.Background()
+	testAttachment := suite.testAttachments["remote_account_1_status_1_attachment_1"]
+
+	// uncache the file from local
+	testAttachment.Cached = false
+	err := suite.db.UpdateByPrimaryKey(ctx, testAttachment)
+	suite.NoError(err)
+	err = suite.storage.Delete(testAttachment.File.Path)
+	suite.NoError(err)
+	err = suite.storage.Delete(testAttachment.Thumbnail.Path)
+	suite.NoError(err)
+
+	suite.remoteDelay = 500 * time.Millisecond
+
+	fileName := path.Base(testAttachment.File.Path)
+	requestingAccount := suite.testAccounts["local_account_1"]
+	form := &apimodel.GetContentRequestForm{
+		AccountID: testAttachment.AccountID,
+		MediaType: string(media.TypeAttachment),
+		MediaSize: string(media.SizeOriginal),
+		FileName:  fileName,
+	}
+
+	// the first request kicks off the recache and gets the file streamed to it
+	first, errWithCode := suite.mediaProcessor.GetFile(ctx, requestingAccount, form)
+	suite.NoError(errWithCode)
+
+	// read the first one while it's streamed, like the http handler would
+	firstBytes := make(chan []byte, 1)
+	go func() {
+		b, err := io.ReadAll(first.Content)
+		suite.NoError(err)
+		firstBytes <- b
+	}()
+
+	// the second request comes in while the first is still being fetched,
+	// so it should wait for that to finish and then get the file from storage
+	second, errWithCode := suite.mediaProcessor.GetFile(ctx, requestingAccount, form)
+	suite.NoError(errWithCode)
+	suite.False(second.Pending)
+
+	secondBytes, err := io.ReadAll(second.Content)
+	suite.NoError(err)
+	if closer, ok := second.Content.(io.Closer); ok {
+		suite.NoError(closer.Close())
+	}
+
+	suite.Equal(suite.testRemoteAttachments[testAttachment.RemoteURL].Data, <-firstBytes)
+	suite.Equal(suite.testRemoteAttachments[testAttachment.RemoteURL].Data, secondBytes)
+}
+
+// urlStorage is a storage backend that gives out urls for its values, like s3 does.
+type urlStorage struct {
+	gtsstorage.Storage
+}
+
+func (s *urlStorage) URL(ctx context.Context, key string) (*url.URL, error) {
+	return url.Parse("https://s3.example.org/gts/" + key)
+}
+
+func (suite *GetFileTestSuite) TestGetFileURL() {
+	ctx := context.Background()
+
+	processor := mediaprocessing.New(suite.db, suite.tc, suite.mediaManager, suite.transportController, gtsstorage.NewDriver(&urlStorage{suite.storage.Storage}), suite.clientWorker)
+
+	testAttachment := suite.testAttachments["admin_account_status_1_attachment_1"]
+	form := &apimodel.GetContentRequestForm{
+		AccountID: testAttachment.AccountID,
+		MediaType: string(media.TypeAttachment),
+		MediaSize: string(media.SizeOriginal),
+		FileName:  path.Base(testAttachment.File.Path),
+	}
+
+	// the caller can be sent straight to the storage backend
+	content, errWithCode := processor.GetFile(ctx, suite.testAccounts["local_account_1"], form)
+	suite.NoError(errWithCode)
+	suite.Nil(content.Content)
+	suite.Equal("https://s3.example.org/gts/"+testAttachment.File.Path, content.URL.String())
+
+	// but not for content that needs to be served as a download, since the backend won't set the headers for that
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+	suite.NoError(err)
+	dbAttachment.Type = gtsmodel.FileTypeUnknown
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, dbAttachment))
+
+	content, errWithCode = processor.GetFile(ctx, suite.testAccounts["local_account_1"], form)
+	suite.NoError(errWithCode)
+	suite.True(content.Download)
+	suite.Nil(content.URL)
+	suite.NotNil(content.Content)
+	if closer, ok := content.Content.(io.Closer); ok {
+		suite.NoError(closer.Close())
+	}
+}
+
 func TestGetFileTestSuite(t *testing.T) {
 	suite.Run(t, &GetFileTestSuite{})
 }
@@ -20,15 +20,18 @@ package media
 
 import (
 	"context"
+	"sync"
 
-	"codeberg.org/gruf/go-store/kv"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
 )
 
 // Processor wraps a bunch of functions for processing media actions.
@@ -40,24 +43,36 @@ type Processor interface {
 	// GetFile retrieves a file from storage and streams it back to the caller via an io.reader embedded in *apimodel.Content.
 	GetFile(ctx context.Context, account *gtsmodel.Account, form *apimodel.GetContentRequestForm) (*apimodel.Content, gtserror.WithCode)
 	GetMedia(ctx context.Context, account *gtsmodel.Account, mediaAttachmentID string) (*apimodel.Attachment, gtserror.WithCode)
+	// Update updates the description and/or focus of the media attachment with the given ID, using the request form.
+	// If the attachment has already been posted in a status, the change will be federated as an update of that status.
 	Update(ctx context.Context, account *gtsmodel.Account, mediaAttachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode)
+	// FlushAccessCounts stores the accesses to remote media that have been counted since the last flush.
+	// Accesses are only counted in memory by GetFile, so that serving media doesn't have to wait on the database.
+	FlushAccessCounts(ctx context.Context) error
 }
 
 type processor struct {
 	tc                  typeutils.TypeConverter
 	mediaManager        media.Manager
 	transportController transport.Controller
-	storage             *kv.KVStore
+	storage             *gtsstorage.Driver
+	clientWorker        *worker.Worker[messages.FromClientAPI]
 	db                  db.DB
+
+	// accessCounts maps the ids of remote attachments to how many times they've been accessed since the last flush
+	accessCounts   map[string]int
+	accessCountsMu sync.Mutex
 }
 
 // New returns a new media processor.
-func New(db db.DB, tc typeutils.TypeConverter, mediaManager media.Manager, transportController transport.Controller, storage *kv.KVStore) Processor {
+func New(db db.DB, tc typeutils.TypeConverter, mediaManager media.Manager, transportController transport.Controller, storage *gtsstorage.Driver, clientWorker *worker.Worker[messages.FromClientAPI]) Processor {
 	return &processor{
 		tc:                  tc,
 		mediaManager:        mediaManager,
 		transportController: transportController,
 		storage:             storage,
+		clientWorker:        clientWorker,
 		db:                  db,
+		accessCounts:        make(map[string]int),
 	}
 }
@@ -20,10 +20,11 @@ package media_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"time"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -31,6 +32,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	mediaprocessing "github.com/superseriousbusiness/gotosocial/internal/processing/media"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
@@ -42,7 +44,7 @@ type MediaStandardTestSuite struct {
 	suite.Suite
 	db                  db.DB
 	tc                  typeutils.TypeConverter
-	storage             *kv.KVStore
+	storage             *gtsstorage.Driver
 	mediaManager        media.Manager
 	transportController transport.Controller
 
@@ -56,6 +58,13 @@ type MediaStandardTestSuite struct {
 	testStatuses          map[string]*gtsmodel.Status
 	testRemoteAttachments map[string]testrig.RemoteAttachmentFile
 
+	// how long the mock transport should take to respond to requests
+	remoteDelay time.Duration
+
+	// messages queued by the processor for the client api worker
+	clientWorker      *worker.Worker[messages.FromClientAPI]
+	fromClientAPIChan chan messages.FromClientAPI
+
 	// module being tested
 	mediaProcessor mediaprocessing.Processor
 }
@@ -80,12 +89,21 @@ func (suite *MediaStandardTestSuite) SetupTest() {
 	suite.storage = testrig.NewTestStorage()
 	suite.mediaManager = testrig.NewTestMediaManager(suite.db, suite.storage)
 	suite.transportController = suite.mockTransportController()
-	suite.mediaProcessor = mediaprocessing.New(suite.db, suite.tc, suite.mediaManager, suite.transportController, suite.storage)
+	suite.fromClientAPIChan = make(chan messages.FromClientAPI, 100)
+	suite.clientWorker = worker.New[messages.FromClientAPI](-1, -1)
+	suite.clientWorker.SetProcessor(func(_ context.Context, msg messages.FromClientAPI) error {
+		suite.fromClientAPIChan <- msg
+		return nil
+	})
+	_ = suite.clientWorker.Start()
+	suite.mediaProcessor = mediaprocessing.New(suite.db, suite.tc, suite.mediaManager, suite.transportController, suite.storage, suite.clientWorker)
 	testrig.StandardDBSetup(suite.db, nil)
 	testrig.StandardStorageSetup(suite.storage, "../../../testrig/media")
 }
 
 func (suite *MediaStandardTestSuite) TearDownTest() {
+	_ = suite.clientWorker.Stop()
+	suite.remoteDelay = 0
 	testrig.StandardDBTeardown(suite.db)
 	testrig.StandardStorageTeardown(suite.storage)
 }
@@ -93,6 +111,7 @@ func (suite *MediaStandardTestSuite) TearDownTest() {
 func (suite *MediaStandardTestSuite) mockTransportController() transport.Controller {
 	do := func(req *http.Request) (*http.Response, error) {
 		logrus.Debugf("received request for %s", req.URL)
+		time.Sleep(suite.remoteDelay)
 
 		responseBytes := []byte{}
 		responseType := ""
@@ -22,11 +22,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
 )
 
@@ -44,11 +47,11 @@ func (p *processor) Update(ctx context.Context, account *gtsmodel.Account, media
 		return nil, gtserror.NewErrorNotFound(errors.New("attachment not owned by requesting account"))
 	}
 
+	var changed bool
+
 	if form.Description != nil {
 		attachment.Description = text.SanitizeCaption(*form.Description)
-		if err := p.db.UpdateByPrimaryKey(ctx, attachment); err != nil {
-			return nil, gtserror.NewErrorInternalError(fmt.Errorf("database error updating description: %s", err))
-		}
+		changed = true
 	}
 
 	if form.Focus != nil {
@@ -58,8 +61,40 @@ func (p *processor) Update(ctx context.Context, account *gtsmodel.Account, media
 		}
 		attachment.FileMeta.Focus.X = focusx
 		attachment.FileMeta.Focus.Y = focusy
+		changed = true
+	}
+
+	if changed {
 		if err := p.db.UpdateByPrimaryKey(ctx, attachment); err != nil {
-			return nil, gtserror.NewErrorInternalError(fmt.Errorf("database error updating focus: %s", err))
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("database error updating attachment: %s", err))
+		}
+
+		// if the attachment has already been posted, other instances will need to hear about the change
+		if attachment.StatusID != "" {
+			status, err := p.db.GetStatusByID(ctx, attachment.StatusID)
+			if err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("database error getting status of attachment: %s", err))
+			}
+
+			// swap the changed attachment into the status, and mark the status as edited so that it's stored
+			// and converted afresh rather than served from a cache, and so that other instances accept the update
+			for i, a := range status.Attachments {
+				if a.ID == attachment.ID {
+					status.Attachments[i] = attachment
+				}
+			}
+			status.EditedAt = time.Now()
+			status.UpdatedAt = status.EditedAt
+			if err := p.db.UpdateStatus(ctx, status); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("database error updating status of attachment: %s", err))
+			}
+
+			p.clientWorker.Queue(messages.FromClientAPI{
+				APObjectType:   ap.ObjectNote,
+				APActivityType: ap.ActivityUpdate,
+				GTSModel:       status,
+				OriginAccount:  account,
+			})
 		}
 	}
 
@@ -0,0 +1,112 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package media_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type UpdateTestSuite struct {
+	MediaStandardTestSuite
+}
+
+func (suite *UpdateTestSuite) TestUpdatePostedAttachment() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["admin_account"]
+	testAttachment := suite.testAttachments["admin_account_status_1_attachment_1"]
+
+	// the status has been federated before, so its AS representation is cached
+	_, err := suite.tc.StatusToAS(ctx, suite.testStatuses["admin_account_status_1"])
+	suite.NoError(err)
+
+	description := "a new description"
+	focus := "-0.5,0.5"
+	a, errWithCode := suite.mediaProcessor.Update(ctx, testAccount, testAttachment.ID, &apimodel.AttachmentUpdateRequest{
+		Description: &description,
+		Focus:       &focus,
+	})
+	suite.NoError(errWithCode)
+	suite.Equal(description, a.Description)
+	suite.EqualValues(-0.5, a.Meta.Focus.X)
+	suite.EqualValues(0.5, a.Meta.Focus.Y)
+
+	// the change should be stored
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, testAttachment.ID)
+	suite.NoError(err)
+	suite.Equal(description, dbAttachment.Description)
+	suite.EqualValues(-0.5, dbAttachment.FileMeta.Focus.X)
+	suite.EqualValues(0.5, dbAttachment.FileMeta.Focus.Y)
+
+	// the attachment has already been posted, so the status should be federated again
+	select {
+	case msg := <-suite.fromClientAPIChan:
+		suite.Equal(ap.ObjectNote, msg.APObjectType)
+		suite.Equal(ap.ActivityUpdate, msg.APActivityType)
+		suite.Equal(testAccount.ID, msg.OriginAccount.ID)
+		status, ok := msg.GTSModel.(*gtsmodel.Status)
+		suite.True(ok)
+		suite.Equal(testAttachment.StatusID, status.ID)
+		suite.False(status.EditedAt.IsZero())
+
+		// what gets federated should have the new description, not a cached copy of the old one
+		asStatus, err := suite.tc.StatusToAS(ctx, status)
+		suite.NoError(err)
+		asAttachments := asStatus.GetActivityStreamsAttachment()
+		suite.Equal(1, asAttachments.Len())
+		asAttachment, ok := asAttachments.At(0).GetType().(ap.Attachmentable)
+		suite.True(ok)
+		name, err := ap.ExtractName(asAttachment)
+		suite.NoError(err)
+		suite.Equal(description, name)
+		suite.NotNil(asStatus.GetActivityStreamsUpdated())
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for status update to be queued")
+	}
+}
+
+func (suite *UpdateTestSuite) TestUpdateUnpostedAttachment() {
+	ctx := context.Background()
+	testAccount := suite.testAccounts["local_account_1"]
+	testAttachment := suite.testAttachments["local_account_1_unattached_1"]
+
+	description := "a new description"
+	a, errWithCode := suite.mediaProcessor.Update(ctx, testAccount, testAttachment.ID, &apimodel.AttachmentUpdateRequest{
+		Description: &description,
+	})
+	suite.NoError(errWithCode)
+	suite.Equal(description, a.Description)
+
+	// there's nothing to federate until the attachment is posted
+	select {
+	case msg := <-suite.fromClientAPIChan:
+		suite.FailNowf("unexpected message queued", "%+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestUpdateTestSuite(t *testing.T) {
+	suite.Run(t, &UpdateTestSuite{})
+}
@@ -0,0 +1,86 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// muteLifterSchedule is how often expired mutes are looked for and lifted.
+const muteLifterSchedule = "@every 1m"
+
+func (p *processor) MutesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode) {
+	mutes, err := p.db.GetAccountMutes(ctx, authed.Account.ID, maxID, sinceID, limit)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiAccounts := []*apimodel.Account{}
+	for _, m := range mutes {
+		apiAccount, err := p.tc.AccountToAPIAccountPublic(ctx, m.TargetAccount)
+		if err != nil {
+			continue
+		}
+		if !m.ExpiresAt.IsZero() {
+			apiAccount.MuteExpiresAt = m.ExpiresAt.Format(time.RFC3339)
+		}
+		apiAccounts = append(apiAccounts, apiAccount)
+	}
+
+	if len(mutes) == 0 {
+		return &apimodel.MutesResponse{
+			Accounts: apiAccounts,
+		}, nil
+	}
+
+	// mutes page the same way as blocks, so reuse the link header building
+	nextMaxID := mutes[len(mutes)-1].ID
+	prevMinID := mutes[0].ID
+	resp, errWithCode := p.packageBlocksResponse(apiAccounts, "/api/v1/mutes", nextMaxID, prevMinID, limit)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return &apimodel.MutesResponse{
+		Accounts:   resp.Accounts,
+		LinkHeader: resp.LinkHeader,
+	}, nil
+}
+
+// startMuteLifter starts a cron job that lifts mutes once they've expired.
+//
+// Expired mutes are already ignored wherever mutes are checked, so this is mostly
+// housekeeping, but it means that expired mutes don't hang around in the db forever.
+func (p *processor) startMuteLifter() error {
+	if err := p.startScheduledJob(muteLifterSchedule, func(ctx context.Context) {
+		if err := p.db.DeleteExpiredMutes(ctx, time.Now()); err != nil {
+			logrus.Errorf("mute lifter: error lifting expired mutes: %s", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("error starting mute lifter job: %s", err)
+	}
+	return nil
+}
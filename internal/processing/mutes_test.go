@@ -0,0 +1,163 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type MutesTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *MutesTestSuite) TestMuteAndUnmute() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	targetAccount := suite.testAccounts["local_account_2"]
+
+	relationship, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, targetAccount.ID, &apimodel.AccountMuteRequest{})
+	suite.NoError(errWithCode)
+	suite.True(relationship.Muting)
+	suite.True(relationship.MutingNotifications)
+	suite.Empty(relationship.MuteExpiresAt)
+
+	// muting again updates the existing mute
+	notifications := false
+	relationship, errWithCode = suite.processor.AccountMuteCreate(ctx, authed, targetAccount.ID, &apimodel.AccountMuteRequest{Notifications: &notifications, Duration: 3600})
+	suite.NoError(errWithCode)
+	suite.True(relationship.Muting)
+	suite.False(relationship.MutingNotifications)
+	suite.NotEmpty(relationship.MuteExpiresAt)
+
+	resp, errWithCode := suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Accounts, 1)
+	suite.Equal(targetAccount.ID, resp.Accounts[0].ID)
+	suite.Equal(relationship.MuteExpiresAt, resp.Accounts[0].MuteExpiresAt)
+	suite.NotEmpty(resp.LinkHeader)
+
+	relationship, errWithCode = suite.processor.AccountMuteRemove(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.False(relationship.Muting)
+	suite.False(relationship.MutingNotifications)
+	suite.Empty(relationship.MuteExpiresAt)
+
+	resp, errWithCode = suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Accounts)
+	suite.Empty(resp.LinkHeader)
+}
+
+func (suite *MutesTestSuite) TestMuteSelf() {
+	authed := suite.testAutheds["local_account_1"]
+
+	relationship, errWithCode := suite.processor.AccountMuteCreate(context.Background(), authed, authed.Account.ID, &apimodel.AccountMuteRequest{})
+	suite.Nil(relationship)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *MutesTestSuite) TestMuteExpires() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	targetAccount := suite.testAccounts["admin_account"]
+
+	_, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, targetAccount.ID, &apimodel.AccountMuteRequest{Duration: 60})
+	suite.NoError(errWithCode)
+
+	// pretend the mute was made long enough ago that it's expired now
+	mute := &gtsmodel.AccountMute{}
+	suite.NoError(suite.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: authed.Account.ID}}, mute))
+	mute.ExpiresAt = time.Now().Add(-1 * time.Second)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, mute))
+
+	// an expired mute doesn't count, even before it's been lifted
+	relationship, errWithCode := suite.processor.AccountRelationshipGet(ctx, authed, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.False(relationship.Muting)
+
+	resp, errWithCode := suite.processor.MutesGet(ctx, authed, "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Empty(resp.Accounts)
+
+	suite.NoError(suite.db.DeleteExpiredMutes(ctx, time.Now()))
+	err := suite.db.GetByID(ctx, mute.ID, &gtsmodel.AccountMute{})
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *MutesTestSuite) TestMuteHidesStatuses() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	targetAccount := suite.testAccounts["admin_account"]
+
+	byTarget := func(statuses []*apimodel.Status) int {
+		n := 0
+		for _, s := range statuses {
+			if s.Account.ID == targetAccount.ID || (s.Reblog != nil && s.Reblog.Account.ID == targetAccount.ID) {
+				n++
+			}
+		}
+		return n
+	}
+	homeTimeline := func() []*apimodel.Status {
+		resp, errWithCode := suite.processor.HomeTimelineGet(ctx, authed, "", "", "", 20, false)
+		suite.NoError(errWithCode)
+		return resp.Statuses
+	}
+	publicTimeline := func() []*apimodel.Status {
+		resp, errWithCode := suite.processor.PublicTimelineGet(ctx, authed, "", "", "", 20, false, false, false)
+		suite.NoError(errWithCode)
+		return resp.Statuses
+	}
+	thread := func() []*apimodel.Status {
+		context, errWithCode := suite.processor.StatusGetContext(ctx, authed, suite.testStatuses["local_account_1_status_1"].ID)
+		suite.NoError(errWithCode)
+		statuses := []*apimodel.Status{}
+		for i := range context.Descendants {
+			statuses = append(statuses, &context.Descendants[i])
+		}
+		return statuses
+	}
+
+	// the admin account is followed, and its statuses are everywhere
+	suite.NotZero(byTarget(homeTimeline()))
+	suite.NotZero(byTarget(publicTimeline()))
+	suite.NotZero(byTarget(thread()))
+
+	_, errWithCode := suite.processor.AccountMuteCreate(ctx, authed, targetAccount.ID, &apimodel.AccountMuteRequest{})
+	suite.NoError(errWithCode)
+
+	// the statuses that were already in the home timeline are taken out of it
+	suite.Eventually(func() bool {
+		return byTarget(homeTimeline()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	suite.Zero(byTarget(publicTimeline()))
+	suite.Zero(byTarget(thread()))
+}
+
+func TestMutesTestSuite(t *testing.T) {
+	suite.Run(t, &MutesTestSuite{})
+}
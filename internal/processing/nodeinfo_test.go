@@ -0,0 +1,74 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NodeInfoTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *NodeInfoTestSuite) TestGetNodeInfoRel() {
+	rel, err := suite.processor.GetNodeInfoRel(context.Background(), &http.Request{})
+	suite.NoError(err)
+	suite.Len(rel.Links, 2)
+	suite.Equal("http://nodeinfo.diaspora.software/ns/schema/2.1", rel.Links[0].Rel)
+	suite.Equal("http://localhost:8080/nodeinfo/2.1", rel.Links[0].Href)
+	suite.Equal("http://nodeinfo.diaspora.software/ns/schema/2.0", rel.Links[1].Rel)
+	suite.Equal("http://localhost:8080/nodeinfo/2.0", rel.Links[1].Href)
+}
+
+func (suite *NodeInfoTestSuite) TestGetNodeInfo21() {
+	ni, err := suite.processor.GetNodeInfo(context.Background(), &http.Request{}, "2.1")
+	suite.NoError(err)
+	suite.Equal("2.1", ni.Version)
+	suite.Equal("gotosocial", ni.Software.Name)
+	suite.NotEmpty(ni.Software.Repository)
+	suite.NotEmpty(ni.Software.Homepage)
+	suite.NotZero(ni.Usage.Users.Total)
+	suite.NotZero(ni.Usage.LocalPosts)
+	suite.LessOrEqual(ni.Usage.Users.ActiveMonth, ni.Usage.Users.ActiveHalfyear)
+	suite.LessOrEqual(ni.Usage.Users.ActiveHalfyear, ni.Usage.Users.Total)
+	suite.Equal("Zork", ni.Metadata["nodeAdmin"])
+	suite.Equal("localhost:8080", ni.Metadata["nodeName"])
+}
+
+func (suite *NodeInfoTestSuite) TestGetNodeInfo20() {
+	ni, err := suite.processor.GetNodeInfo(context.Background(), &http.Request{}, "2.0")
+	suite.NoError(err)
+	suite.Equal("2.0", ni.Version)
+	suite.Empty(ni.Software.Repository)
+	suite.Empty(ni.Software.Homepage)
+}
+
+func (suite *NodeInfoTestSuite) TestGetNodeInfoUnsupportedVersion() {
+	ni, err := suite.processor.GetNodeInfo(context.Background(), &http.Request{}, "1.0")
+	suite.Nil(ni)
+	suite.Equal(http.StatusNotFound, err.Code())
+}
+
+func TestNodeInfoTestSuite(t *testing.T) {
+	suite.Run(t, &NodeInfoTestSuite{})
+}
@@ -20,31 +20,126 @@ package processing
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode) {
-	l := logrus.WithField("func", "NotificationsGet")
-
-	notifs, err := p.db.GetNotifications(ctx, authed.Account.ID, limit, maxID, sinceID)
+func (p *processor) NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string) (*apimodel.NotificationsResponse, gtserror.WithCode) {
+	notifs, err := p.db.GetNotifications(ctx, authed.Account.ID, limit, maxID, sinceID, minID, types, excludeTypes)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
+	resp := &apimodel.NotificationsResponse{
+		Notifications: p.apiNotifications(ctx, authed.Account, notifs),
+	}
+
+	// page from the database notifications rather than the api ones, so filtered notifications at the edges aren't served again
+	if len(notifs) != 0 {
+		resp.LinkHeader = notificationsLinkHeader("/api/v1/notifications", limit, notifs[len(notifs)-1].ID, notifs[0].ID, url.Values{
+			"types[]":         types,
+			"exclude_types[]": excludeTypes,
+		})
+	}
+
+	return resp, nil
+}
+
+// notificationsLinkHeader returns a Link header for the next and previous pages of the notifications at path,
+// keeping any of the given extra query values that are set.
+func notificationsLinkHeader(path string, limit int, nextMaxID string, prevMinID string, extra url.Values) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+
+	extraQuery := extra.Encode()
+	if extraQuery != "" {
+		extraQuery = "&" + extraQuery
+	}
+
+	nextLink := &url.URL{
+		Scheme:   protocol,
+		Host:     host,
+		Path:     path,
+		RawQuery: fmt.Sprintf("limit=%d&max_id=%s%s", limit, nextMaxID, extraQuery),
+	}
+	next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
+
+	prevLink := &url.URL{
+		Scheme:   protocol,
+		Host:     host,
+		Path:     path,
+		RawQuery: fmt.Sprintf("limit=%d&min_id=%s%s", limit, prevMinID, extraQuery),
+	}
+	prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
+
+	return fmt.Sprintf("%s, %s", next, prev)
+}
+
+// apiNotifications converts the given notifications of the given account into their api representations,
+// leaving out any notifications that are hidden by the account's filters or that can't be converted.
+func (p *processor) apiNotifications(ctx context.Context, account *gtsmodel.Account, notifs []*gtsmodel.Notification) []*apimodel.Notification {
+	l := logrus.WithField("func", "apiNotifications")
+
 	apiNotifs := []*apimodel.Notification{}
 	for _, n := range notifs {
+		hide, results, err := p.notificationFilterResults(ctx, n, account)
+		if err != nil {
+			l.Debugf("got an error checking filters for a notification, will skip it: %s", err)
+			continue
+		}
+		if hide {
+			continue
+		}
+
 		apiNotif, err := p.tc.NotificationToAPINotification(ctx, n)
 		if err != nil {
 			l.Debugf("got an error converting a notification to api, will skip it: %s", err)
 			continue
 		}
+		if apiNotif.Status != nil {
+			apiNotif.Status.Filtered = results
+		}
 		apiNotifs = append(apiNotifs, apiNotif)
 	}
 
-	return apiNotifs, nil
+	return apiNotifs
+}
+
+func (p *processor) NotificationDismiss(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode {
+	notif, err := p.db.GetNotification(ctx, id)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return gtserror.NewErrorNotFound(fmt.Errorf("notification %s not found", id))
+		}
+		return gtserror.NewErrorInternalError(fmt.Errorf("error getting notification %s: %s", id, err))
+	}
+
+	if notif.TargetAccountID != authed.Account.ID {
+		// don't let on that someone else's notification exists
+		return gtserror.NewErrorNotFound(fmt.Errorf("notification %s does not belong to account %s", id, authed.Account.ID))
+	}
+
+	if err := p.db.DeleteNotification(ctx, id); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting notification %s: %s", id, err))
+	}
+
+	return nil
+}
+
+func (p *processor) NotificationsClear(ctx context.Context, authed *oauth.Auth) gtserror.WithCode {
+	if err := p.db.ClearNotifications(ctx, authed.Account.ID); err != nil {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error clearing notifications: %s", err))
+	}
+
+	return nil
 }
@@ -20,9 +20,17 @@ package processing_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
 type NotificationTestSuite struct {
@@ -32,8 +40,9 @@ type NotificationTestSuite struct {
 // get a notification where someone has liked our status
 func (suite *NotificationTestSuite) TestGetNotifications() {
 	receivingAccount := suite.testAccounts["local_account_1"]
-	notifs, err := suite.processor.NotificationsGet(context.Background(), suite.testAutheds["local_account_1"], 10, "", "")
+	resp, err := suite.processor.NotificationsGet(context.Background(), suite.testAutheds["local_account_1"], 10, "", "", "", nil, nil)
 	suite.NoError(err)
+	notifs := resp.Notifications
 	suite.Len(notifs, 1)
 	notif := notifs[0]
 	suite.NotNil(notif.Status)
@@ -42,6 +51,196 @@ func (suite *NotificationTestSuite) TestGetNotifications() {
 	suite.Equal(receivingAccount.ID, notif.Status.Account.ID)
 }
 
+func (suite *NotificationTestSuite) TestGetNotificationsByType() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	resp, err := suite.processor.NotificationsGet(ctx, authed, 10, "", "", "", []string{"favourite"}, nil)
+	suite.NoError(err)
+	notifs := resp.Notifications
+	suite.Len(notifs, 1)
+	suite.Equal("favourite", notifs[0].Type)
+
+	resp, err = suite.processor.NotificationsGet(ctx, authed, 10, "", "", "", []string{"mention", "reblog"}, nil)
+	suite.NoError(err)
+	notifs = resp.Notifications
+	suite.Empty(notifs)
+
+	resp, err = suite.processor.NotificationsGet(ctx, authed, 10, "", "", "", nil, []string{"favourite"})
+	suite.NoError(err)
+	notifs = resp.Notifications
+	suite.Empty(notifs)
+}
+
+func (suite *NotificationTestSuite) TestGetNotificationsLinkHeader() {
+	ctx := context.Background()
+	like := testrig.NewTestNotifications()["local_account_1_like"]
+
+	resp, err := suite.processor.NotificationsGet(ctx, suite.testAutheds["local_account_1"], 10, "", "", "", []string{"favourite"}, nil)
+	suite.NoError(err)
+	suite.Len(resp.Notifications, 1)
+	suite.Equal(`<http://localhost:8080/api/v1/notifications?limit=10&max_id=`+like.ID+`&types%5B%5D=favourite>; rel="next", <http://localhost:8080/api/v1/notifications?limit=10&min_id=`+like.ID+`&types%5B%5D=favourite>; rel="prev"`, resp.LinkHeader)
+
+	// there's nothing newer than the like, so paging up from it gives nothing to link from
+	resp, err = suite.processor.NotificationsGet(ctx, suite.testAutheds["local_account_1"], 10, "", "", like.ID, nil, nil)
+	suite.NoError(err)
+	suite.Empty(resp.Notifications)
+	suite.Empty(resp.LinkHeader)
+}
+
+func (suite *NotificationTestSuite) TestDismissNotification() {
+	ctx := context.Background()
+	notif := testrig.NewTestNotifications()["local_account_1_like"]
+
+	// someone else can't dismiss zork's notification
+	turtleAuthed := &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     suite.testAccounts["local_account_2"],
+	}
+	errWithCode := suite.processor.NotificationDismiss(ctx, turtleAuthed, notif.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+
+	errWithCode = suite.processor.NotificationDismiss(ctx, suite.testAutheds["local_account_1"], notif.ID)
+	suite.NoError(errWithCode)
+
+	resp, err := suite.processor.NotificationsGet(ctx, suite.testAutheds["local_account_1"], 10, "", "", "", nil, nil)
+	suite.NoError(err)
+	notifs := resp.Notifications
+	suite.Empty(notifs)
+
+	// it's gone for good
+	errWithCode = suite.processor.NotificationDismiss(ctx, suite.testAutheds["local_account_1"], notif.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *NotificationTestSuite) TestClearNotifications() {
+	ctx := context.Background()
+
+	errWithCode := suite.processor.NotificationsClear(ctx, suite.testAutheds["local_account_1"])
+	suite.NoError(errWithCode)
+
+	resp, err := suite.processor.NotificationsGet(ctx, suite.testAutheds["local_account_1"], 10, "", "", "", nil, nil)
+	suite.NoError(err)
+	notifs := resp.Notifications
+	suite.Empty(notifs)
+
+	_, dbErr := suite.db.GetNotification(ctx, testrig.NewTestNotifications()["local_account_1_like"].ID)
+	suite.Error(dbErr)
+}
+
+func (suite *NotificationTestSuite) TestExcludedNotificationType() {
+	ctx := context.Background()
+	zork := suite.testAccounts["local_account_1"]
+	turtle := suite.testAccounts["local_account_2"]
+
+	// zork doesn't want to know about faves
+	zork.ExcludedNotifications = []gtsmodel.NotificationType{gtsmodel.NotificationFave}
+	_, err := suite.db.UpdateAccount(ctx, zork)
+	suite.NoError(err)
+
+	status := suite.testStatuses["local_account_1_status_1"]
+	fave := &gtsmodel.StatusFave{
+		ID:              "01G24VBCQTTG8MNV2C7Y0QT4NC",
+		AccountID:       turtle.ID,
+		Account:         turtle,
+		TargetAccountID: zork.ID,
+		StatusID:        status.ID,
+		Status:          status,
+		URI:             "http://localhost:8080/users/1happyturtle/liked/01G24VBCQTTG8MNV2C7Y0QT4NC",
+	}
+	suite.NoError(suite.db.Put(ctx, fave))
+
+	err = suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ActivityLike,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       fave,
+		OriginAccount:  turtle,
+		TargetAccount:  zork,
+	})
+	suite.NoError(err)
+
+	// only the fave notification zork already had is there
+	resp, errWithCode := suite.processor.NotificationsGet(ctx, suite.testAutheds["local_account_1"], 10, "", "", "", nil, nil)
+	suite.NoError(errWithCode)
+	notifs := resp.Notifications
+	suite.Len(notifs, 1)
+	suite.Equal(testrig.NewTestNotifications()["local_account_1_like"].ID, notifs[0].ID)
+}
+
+func (suite *NotificationTestSuite) TestGetNotificationsGrouped() {
+	ctx := context.Background()
+	zork := suite.testAccounts["local_account_1"]
+	turtle := suite.testAccounts["local_account_2"]
+	admin := suite.testAccounts["admin_account"]
+	like := testrig.NewTestNotifications()["local_account_1_like"]
+
+	// turtle faves the status that admin already faved, then later mentions zork
+	faveID, err := id.NewULIDFromTime(time.Now().Add(-time.Minute))
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Notification{
+		ID:               faveID,
+		NotificationType: gtsmodel.NotificationFave,
+		TargetAccountID:  zork.ID,
+		OriginAccountID:  turtle.ID,
+		StatusID:         like.StatusID,
+	}))
+
+	mentionID, err := id.NewULID()
+	suite.NoError(err)
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.Notification{
+		ID:               mentionID,
+		NotificationType: gtsmodel.NotificationMention,
+		TargetAccountID:  zork.ID,
+		OriginAccountID:  turtle.ID,
+		StatusID:         suite.testStatuses["local_account_2_status_1"].ID,
+	}))
+
+	resp, errWithCode := suite.processor.NotificationsGetGrouped(ctx, suite.testAutheds["local_account_1"], 40, "", "", "", nil, nil, nil)
+	suite.NoError(errWithCode)
+	suite.NotEmpty(resp.LinkHeader)
+
+	results := resp.GroupedNotifications
+	suite.Len(results.Accounts, 2)
+	suite.Len(results.Statuses, 2)
+	suite.Len(results.NotificationGroups, 2)
+
+	mentionGroup := results.NotificationGroups[0]
+	suite.Equal("ungrouped-"+mentionID, mentionGroup.GroupKey)
+	suite.Equal("mention", mentionGroup.Type)
+	suite.Equal(1, mentionGroup.NotificationsCount)
+	suite.Equal([]string{turtle.ID}, mentionGroup.SampleAccountIDs)
+
+	faveGroup := results.NotificationGroups[1]
+	suite.Equal("favourite-"+like.StatusID, faveGroup.GroupKey)
+	suite.Equal("favourite", faveGroup.Type)
+	suite.Equal(2, faveGroup.NotificationsCount)
+	suite.Equal(faveID, faveGroup.MostRecentNotificationID)
+	suite.Equal(faveID, faveGroup.PageMaxID)
+	suite.Equal(like.ID, faveGroup.PageMinID)
+	suite.Equal(like.StatusID, faveGroup.StatusID)
+	suite.Equal([]string{turtle.ID, admin.ID}, faveGroup.SampleAccountIDs)
+
+	// the limit applies to groups rather than notifications, so a group isn't cut short by it
+	resp, errWithCode = suite.processor.NotificationsGetGrouped(ctx, suite.testAutheds["local_account_1"], 2, "", "", "", nil, nil, nil)
+	suite.NoError(errWithCode)
+	suite.Len(resp.GroupedNotifications.NotificationGroups, 2)
+	suite.Equal(2, resp.GroupedNotifications.NotificationGroups[1].NotificationsCount)
+	suite.Contains(resp.LinkHeader, "max_id="+faveID)
+
+	// paging up from the oldest notification gets the groups right after it
+	resp, errWithCode = suite.processor.NotificationsGetGrouped(ctx, suite.testAutheds["local_account_1"], 1, "", "", like.ID, nil, nil, nil)
+	suite.NoError(errWithCode)
+	suite.Len(resp.GroupedNotifications.NotificationGroups, 1)
+	suite.Equal("favourite-"+like.StatusID, resp.GroupedNotifications.NotificationGroups[0].GroupKey)
+	suite.Equal(1, resp.GroupedNotifications.NotificationGroups[0].NotificationsCount)
+
+	// if faves aren't grouped, every notification has a group of its own
+	resp, errWithCode = suite.processor.NotificationsGetGrouped(ctx, suite.testAutheds["local_account_1"], 40, "", "", "", nil, nil, []string{"reblog"})
+	suite.NoError(errWithCode)
+	suite.Len(resp.GroupedNotifications.NotificationGroups, 3)
+}
+
 func TestNotificationTestSuite(t *testing.T) {
 	suite.Run(t, &NotificationTestSuite{})
 }
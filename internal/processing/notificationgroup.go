@@ -0,0 +1,218 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// maxGroupSampleAccounts is the most accounts that will be sampled for one notification group.
+const maxGroupSampleAccounts = 8
+
+// notificationGroupScanBatch is how many notifications are fetched at a time while grouping them, and
+// maxNotificationsGrouped is the most notifications that will be looked at for one page of groups, so
+// that grouping an account with a huge number of notifications can't take forever.
+const (
+	notificationGroupScanBatch = 100
+	maxNotificationsGrouped    = 1000
+)
+
+// groupableNotificationTypes are the types of notification that can be grouped, and which are grouped if the client doesn't say otherwise.
+var groupableNotificationTypes = []string{
+	string(gtsmodel.NotificationFave),
+	string(gtsmodel.NotificationReblog),
+	string(gtsmodel.NotificationFollow),
+}
+
+// notificationGroup is a group of notifications, newest first, before it's converted to its api representation.
+type notificationGroup struct {
+	key    string
+	notifs []*gtsmodel.Notification
+}
+
+func (p *processor) NotificationsGetGrouped(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string, groupedTypes []string) (*apimodel.GroupedNotificationsResponse, gtserror.WithCode) {
+	groupTypes := groupedTypes
+	if len(groupTypes) == 0 {
+		groupTypes = groupableNotificationTypes
+	}
+	grouped := map[string]bool{}
+	for _, t := range groupTypes {
+		grouped[t] = true
+	}
+
+	// notifications are grouped after looking at all of them in the requested range, rather than
+	// just a page of them, so that the limit applies to groups and each group is as full as it can be
+	lowerID := sinceID
+	if minID > lowerID {
+		lowerID = minID
+	}
+	groups := []*notificationGroup{}
+	groupsByKey := map[string]*notificationGroup{}
+	for scanned, upperID := 0, maxID; scanned < maxNotificationsGrouped; {
+		notifs, err := p.db.GetNotifications(ctx, authed.Account.ID, notificationGroupScanBatch, upperID, lowerID, "", types, excludeTypes)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		// notifications come newest first, so the first notification of each group is its most recent one
+		for _, n := range notifs {
+			key := notificationGroupKey(n, grouped)
+			group, ok := groupsByKey[key]
+			if !ok {
+				group = &notificationGroup{key: key}
+				groupsByKey[key] = group
+				groups = append(groups, group)
+			}
+			group.notifs = append(group.notifs, n)
+		}
+
+		scanned += len(notifs)
+		if len(notifs) < notificationGroupScanBatch {
+			break
+		}
+		upperID = notifs[len(notifs)-1].ID
+	}
+
+	resp := &apimodel.GroupedNotificationsResponse{
+		GroupedNotifications: &apimodel.GroupedNotifications{
+			Accounts:           []*apimodel.Account{},
+			Statuses:           []*apimodel.Status{},
+			NotificationGroups: []*apimodel.NotificationGroup{},
+		},
+	}
+	results := resp.GroupedNotifications
+
+	// paging up from minID gets the groups right after it rather than the newest ones
+	pageUp := minID != "" && maxID == ""
+	accountIDs := map[string]bool{}
+	statusIDs := map[string]bool{}
+	for i := range groups {
+		if len(results.NotificationGroups) == limit {
+			break
+		}
+		group := groups[i]
+		if pageUp {
+			group = groups[len(groups)-1-i]
+		}
+
+		apiGroup := p.apiNotificationGroup(ctx, authed.Account, group, results, accountIDs, statusIDs)
+		if apiGroup == nil {
+			// every notification of the group was hidden
+			continue
+		}
+
+		if pageUp {
+			results.NotificationGroups = append([]*apimodel.NotificationGroup{apiGroup}, results.NotificationGroups...)
+		} else {
+			results.NotificationGroups = append(results.NotificationGroups, apiGroup)
+		}
+	}
+
+	if len(results.NotificationGroups) != 0 {
+		first := results.NotificationGroups[0]
+		last := results.NotificationGroups[len(results.NotificationGroups)-1]
+		resp.LinkHeader = notificationsLinkHeader("/api/v2/notifications", limit, last.MostRecentNotificationID, first.MostRecentNotificationID, url.Values{
+			"types[]":         types,
+			"exclude_types[]": excludeTypes,
+			"grouped_types[]": groupedTypes,
+		})
+	}
+
+	return resp, nil
+}
+
+// apiNotificationGroup converts the given group of notifications into its api representation, adding the accounts and
+// statuses it refers to into results if they aren't in accountIDs and statusIDs already. Notifications of the group are
+// only converted until enough accounts have been sampled; the rest are counted without being converted. Nil is
+// returned if every notification of the group is hidden from the account.
+func (p *processor) apiNotificationGroup(ctx context.Context, account *gtsmodel.Account, group *notificationGroup, results *apimodel.GroupedNotifications, accountIDs map[string]bool, statusIDs map[string]bool) *apimodel.NotificationGroup {
+	var apiGroup *apimodel.NotificationGroup
+	sampled := map[string]bool{}
+	hidden := 0
+	for i, n := range group.notifs {
+		if apiGroup != nil && len(apiGroup.SampleAccountIDs) == maxGroupSampleAccounts {
+			break
+		}
+
+		apiNotifs := p.apiNotifications(ctx, account, group.notifs[i:i+1])
+		if len(apiNotifs) == 0 {
+			hidden++
+			continue
+		}
+		apiNotif := apiNotifs[0]
+
+		if apiNotif.Account != nil && !accountIDs[apiNotif.Account.ID] {
+			accountIDs[apiNotif.Account.ID] = true
+			results.Accounts = append(results.Accounts, apiNotif.Account)
+		}
+
+		if apiGroup == nil {
+			// the first notification that isn't hidden is the most recent one of the group
+			apiGroup = &apimodel.NotificationGroup{
+				GroupKey:                 group.key,
+				Type:                     apiNotif.Type,
+				MostRecentNotificationID: n.ID,
+				PageMaxID:                n.ID,
+				LatestPageNotificationAt: apiNotif.CreatedAt,
+				SampleAccountIDs:         []string{},
+			}
+			if apiNotif.Status != nil {
+				apiGroup.StatusID = apiNotif.Status.ID
+				if !statusIDs[apiNotif.Status.ID] {
+					statusIDs[apiNotif.Status.ID] = true
+					results.Statuses = append(results.Statuses, apiNotif.Status)
+				}
+			}
+		}
+
+		if apiNotif.Account != nil && !sampled[apiNotif.Account.ID] {
+			sampled[apiNotif.Account.ID] = true
+			apiGroup.SampleAccountIDs = append(apiGroup.SampleAccountIDs, apiNotif.Account.ID)
+		}
+	}
+
+	if apiGroup == nil {
+		return nil
+	}
+	apiGroup.NotificationsCount = len(group.notifs) - hidden
+	apiGroup.PageMinID = group.notifs[len(group.notifs)-1].ID
+	return apiGroup
+}
+
+// notificationGroupKey returns the key of the group that the given notification belongs to: favourites and boosts are grouped
+// by the status they're of, and follows by the day they happened on, as long as their type is one of the grouped types.
+// Any other notification gets a key of its own.
+func notificationGroupKey(n *gtsmodel.Notification, grouped map[string]bool) string {
+	if grouped[string(n.NotificationType)] {
+		switch n.NotificationType {
+		case gtsmodel.NotificationFave, gtsmodel.NotificationReblog:
+			return fmt.Sprintf("%s-%s", n.NotificationType, n.StatusID)
+		case gtsmodel.NotificationFollow:
+			return fmt.Sprintf("%s-%s", n.NotificationType, n.CreatedAt.UTC().Format("2006-01-02"))
+		}
+	}
+	return fmt.Sprintf("ungrouped-%s", n.ID)
+}
@@ -0,0 +1,198 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// pollCloserSchedule is how often expired polls are looked for and closed.
+const pollCloserSchedule = "@every 1m"
+
+// startPollCloser starts a cron job that closes polls once they've expired.
+func (p *processor) startPollCloser() error {
+	if err := p.startScheduledJob(pollCloserSchedule, func(ctx context.Context) {
+		if err := p.closeExpiredPolls(ctx); err != nil {
+			logrus.Errorf("poll closer: error closing expired polls: %s", err)
+		}
+	}); err != nil {
+		return fmt.Errorf("error starting poll closer job: %s", err)
+	}
+	return nil
+}
+
+// closeExpiredPolls closes all polls that have expired but haven't been closed yet.
+func (p *processor) closeExpiredPolls(ctx context.Context) error {
+	polls, err := p.db.GetExpiredPolls(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("closeExpiredPolls: error getting expired polls: %s", err)
+	}
+
+	for _, poll := range polls {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := p.closePoll(ctx, poll); err != nil {
+			logrus.Errorf("closeExpiredPolls: error closing poll %s: %s", poll.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// closePoll closes the given expired poll, and notifies everyone who voted in it that it's closed.
+//
+// Local polls are closed straight away, and the final results are federated out. Remote polls are
+// refreshed from their own instance first, so that the final results are as accurate as possible.
+func (p *processor) closePoll(ctx context.Context, poll *gtsmodel.Poll) error {
+	status, err := p.db.GetStatusByID(ctx, poll.StatusID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// the status is gone, so the poll shouldn't be hanging around either
+			return p.db.DeletePoll(ctx, poll.ID)
+		}
+		return fmt.Errorf("closePoll: error getting status %s: %s", poll.StatusID, err)
+	}
+
+	if !status.Local {
+		statusURI, err := url.Parse(status.URI)
+		if err != nil {
+			return fmt.Errorf("closePoll: error parsing status uri %s: %s", status.URI, err)
+		}
+
+		if _, _, _, err := p.federator.GetRemoteStatus(ctx, "", statusURI, true, false); err != nil {
+			logrus.Debugf("closePoll: couldn't refresh remote status %s, closing its poll with the results we have: %s", status.URI, err)
+		}
+
+		// the refresh might have changed the poll, or closed it already
+		poll, err = p.db.GetPollByID(ctx, poll.ID)
+		if err != nil {
+			return fmt.Errorf("closePoll: error getting poll %s: %s", poll.ID, err)
+		}
+	}
+
+	if poll.ClosedAt.IsZero() {
+		poll.ClosedAt = poll.ExpiresAt
+		poll.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, poll); err != nil {
+			return fmt.Errorf("closePoll: error updating poll: %s", err)
+		}
+	}
+	status.Poll = poll
+
+	if status.Local {
+		if err := p.federateStatusUpdate(ctx, status); err != nil {
+			logrus.Errorf("closePoll: error federating closed poll %s: %s", poll.ID, err)
+		}
+	}
+
+	return p.notifyPollClosed(ctx, status)
+}
+
+// notifyPollClosed notifies the author of the given status, and everyone who voted in its poll, that the poll has closed.
+// Only local accounts are notified.
+func (p *processor) notifyPollClosed(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Account == nil {
+		a, err := p.db.GetAccountByID(ctx, status.AccountID)
+		if err != nil {
+			return fmt.Errorf("notifyPollClosed: error getting status author: %s", err)
+		}
+		status.Account = a
+	}
+
+	votes, err := p.db.GetPollVotes(ctx, status.PollID)
+	if err != nil {
+		return fmt.Errorf("notifyPollClosed: error getting poll votes: %s", err)
+	}
+
+	targetAccounts := []*gtsmodel.Account{status.Account}
+	notified := map[string]bool{status.AccountID: true}
+	for _, v := range votes {
+		if v.Account == nil || notified[v.AccountID] {
+			continue
+		}
+		targetAccounts = append(targetAccounts, v.Account)
+		notified[v.AccountID] = true
+	}
+
+	for _, targetAccount := range targetAccounts {
+		// only local accounts can be notified
+		if targetAccount.Domain != "" {
+			continue
+		}
+
+		if notificationExcluded(targetAccount, gtsmodel.NotificationPoll) {
+			// the account doesn't want to be notified of ended polls
+			continue
+		}
+
+		notifID, err := id.NewULID()
+		if err != nil {
+			return err
+		}
+
+		notif := &gtsmodel.Notification{
+			ID:               notifID,
+			NotificationType: gtsmodel.NotificationPoll,
+			TargetAccountID:  targetAccount.ID,
+			TargetAccount:    targetAccount,
+			OriginAccountID:  status.AccountID,
+			OriginAccount:    status.Account,
+			StatusID:         status.ID,
+			Status:           status,
+		}
+
+		if err := p.db.Put(ctx, notif); err != nil {
+			return fmt.Errorf("notifyPollClosed: error putting notification in database: %s", err)
+		}
+
+		hide, results, err := p.notificationFilterResults(ctx, notif, targetAccount)
+		if err != nil {
+			return fmt.Errorf("notifyPollClosed: error checking filters of account: %s", err)
+		}
+		if hide {
+			continue
+		}
+
+		// now stream the notification to the user
+		apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
+		if err != nil {
+			return fmt.Errorf("notifyPollClosed: error converting notification to api representation: %s", err)
+		}
+		if apiNotif.Status != nil {
+			apiNotif.Status.Filtered = results
+		}
+
+		if err := p.streamingProcessor.StreamNotificationToAccount(apiNotif, targetAccount); err != nil {
+			return fmt.Errorf("notifyPollClosed: error streaming notification to account: %s", err)
+		}
+
+		p.pushNotification(apiNotif, targetAccount)
+	}
+
+	return nil
+}
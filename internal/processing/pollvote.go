@@ -0,0 +1,207 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+)
+
+func (p *processor) PollGet(ctx context.Context, authed *oauth.Auth, pollID string) (*apimodel.Poll, gtserror.WithCode) {
+	poll, _, errWithCode := p.getVisiblePoll(ctx, authed, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiPoll(ctx, authed, poll)
+}
+
+func (p *processor) PollVote(ctx context.Context, authed *oauth.Auth, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode) {
+	poll, status, errWithCode := p.getVisiblePoll(ctx, authed, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if poll.Closed() {
+		err := fmt.Errorf("poll %s has already ended", poll.ID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, "the poll has already ended")
+	}
+
+	if status.AccountID == authed.Account.ID {
+		err := fmt.Errorf("account %s tried to vote in its own poll %s", authed.Account.ID, poll.ID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, "you can't vote in your own poll")
+	}
+
+	if errWithCode := validatePollChoices(poll, choices); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	existingVotes, err := p.db.GetAccountPollVotes(ctx, poll.ID, authed.Account.ID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting existing votes: %s", err))
+	}
+	if len(existingVotes) != 0 {
+		err := fmt.Errorf("account %s has already voted in poll %s", authed.Account.ID, poll.ID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, "you have already voted in this poll")
+	}
+
+	for _, choice := range choices {
+		voteID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		vote := &gtsmodel.PollVote{
+			ID:        voteID,
+			CreatedAt: time.Now(),
+			AccountID: authed.Account.ID,
+			Account:   authed.Account,
+			PollID:    poll.ID,
+			Choice:    choice,
+		}
+
+		if status.Local {
+			// votes in local polls are counted straight away, nobody else needs to know about them
+			if err := p.db.PutPollVote(ctx, vote); err != nil {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting vote: %s", err))
+			}
+			continue
+		}
+
+		// votes in remote polls are sent to the poll author, so they need a uri of their own
+		vote.URI = uris.GenerateURIForPollVote(authed.Account.Username, voteID)
+		if err := p.db.Put(ctx, vote); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting vote: %s", err))
+		}
+
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ObjectPollVote,
+			APActivityType: ap.ActivityCreate,
+			GTSModel:       vote,
+			OriginAccount:  authed.Account,
+			TargetAccount:  status.Account,
+		})
+	}
+
+	if !status.Local {
+		// we only know about our own votes in a remote poll, so count them in until the poll
+		// is refreshed from its own instance, which will happen at the latest when it closes
+		if len(poll.Votes) != len(poll.Options) {
+			poll.Votes = make([]int, len(poll.Options))
+		}
+		for _, choice := range choices {
+			poll.Votes[choice]++
+		}
+		poll.VotersCount++
+		poll.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, poll); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating poll: %s", err))
+		}
+	}
+
+	// get the poll again to pick up the new counts
+	poll, err = p.db.GetPollByID(ctx, poll.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting poll: %s", err))
+	}
+
+	return p.apiPoll(ctx, authed, poll)
+}
+
+// getVisiblePoll gets the poll with the given id along with its status, or a 404 if
+// there's no such poll or its status isn't visible to the requesting account.
+func (p *processor) getVisiblePoll(ctx context.Context, authed *oauth.Auth, pollID string) (*gtsmodel.Poll, *gtsmodel.Status, gtserror.WithCode) {
+	poll, err := p.db.GetPollByID(ctx, pollID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("poll %s not found", pollID)
+			return nil, nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting poll: %s", err))
+	}
+
+	status, err := p.db.GetStatusByID(ctx, poll.StatusID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("poll %s not found", pollID)
+			return nil, nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting status of poll: %s", err))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, status, authed.Account)
+	if err != nil {
+		return nil, nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking visibility of status %s: %s", status.ID, err))
+	}
+	if !visible {
+		err := fmt.Errorf("poll %s not found", pollID)
+		return nil, nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return poll, status, nil
+}
+
+// validatePollChoices checks that the given choices make a valid vote in the given poll.
+func validatePollChoices(poll *gtsmodel.Poll, choices []int) gtserror.WithCode {
+	if len(choices) == 0 {
+		err := errors.New("no choices given")
+		return gtserror.NewErrorUnprocessableEntity(err, "at least one choice must be given")
+	}
+
+	if len(choices) > 1 && !poll.Multiple {
+		err := fmt.Errorf("%d choices given for single choice poll %s", len(choices), poll.ID)
+		return gtserror.NewErrorUnprocessableEntity(err, "only one choice can be given in this poll")
+	}
+
+	chosen := make(map[int]bool, len(choices))
+	for _, choice := range choices {
+		if choice < 0 || choice >= len(poll.Options) {
+			err := fmt.Errorf("choice %d is out of range for poll %s", choice, poll.ID)
+			return gtserror.NewErrorUnprocessableEntity(err, fmt.Sprintf("choice %d is not an option in this poll", choice))
+		}
+		if chosen[choice] {
+			err := fmt.Errorf("choice %d given more than once for poll %s", choice, poll.ID)
+			return gtserror.NewErrorUnprocessableEntity(err, fmt.Sprintf("choice %d was given more than once", choice))
+		}
+		chosen[choice] = true
+	}
+
+	return nil
+}
+
+func (p *processor) apiPoll(ctx context.Context, authed *oauth.Auth, poll *gtsmodel.Poll) (*apimodel.Poll, gtserror.WithCode) {
+	apiPoll, err := p.tc.PollToAPIPoll(ctx, poll, authed.Account)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting poll %s to api representation: %s", poll.ID, err))
+	}
+
+	return apiPoll, nil
+}
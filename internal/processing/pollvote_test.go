@@ -0,0 +1,182 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type PollVoteTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// putPollStatus puts a copy of the given status in the db, with a poll attached to it.
+func (suite *PollVoteTestSuite) putPollStatus(base *gtsmodel.Status, statusID string, pollID string, multiple bool) *gtsmodel.Status {
+	pollStatus := &gtsmodel.Status{}
+	*pollStatus = *base
+	pollStatus.ID = statusID
+	pollStatus.URI = base.URI + "/poll"
+	pollStatus.URL = base.URL + "/poll"
+	pollStatus.ActivityStreamsType = ap.ActivityQuestion
+	pollStatus.Poll = &gtsmodel.Poll{
+		ID:        pollID,
+		Options:   []string{"yes", "no", "maybe"},
+		Votes:     []int{0, 0, 0},
+		Multiple:  multiple,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := suite.db.PutStatus(context.Background(), pollStatus); err != nil {
+		suite.FailNow(err.Error())
+	}
+	return pollStatus
+}
+
+func (suite *PollVoteTestSuite) TestVoteLocalPoll() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	pollStatus := suite.putPollStatus(suite.testStatuses["local_account_2_status_1"], "01G1Z3W6QF9V5RQ0HJ7B8E5Y7N", "01G1Z3WC4V3X1M2XK9T6GZ1NQE", true)
+
+	poll, errWithCode := suite.processor.PollGet(ctx, authed, pollStatus.PollID)
+	suite.NoError(errWithCode)
+	suite.False(poll.Voted)
+	suite.Empty(poll.OwnVotes)
+
+	poll, errWithCode = suite.processor.PollVote(ctx, authed, pollStatus.PollID, []int{0, 2})
+	suite.NoError(errWithCode)
+	suite.True(poll.Voted)
+	suite.Equal([]int{0, 2}, poll.OwnVotes)
+	suite.Equal(1, poll.Options[0].VotesCount)
+	suite.Equal(0, poll.Options[1].VotesCount)
+	suite.Equal(1, poll.Options[2].VotesCount)
+	suite.Equal(2, poll.VotesCount)
+	suite.Equal(1, poll.VotersCount)
+
+	// votes in local polls aren't sent anywhere
+	time.Sleep(1 * time.Second)
+	suite.Empty(suite.sentHTTPRequests)
+
+	// voting again isn't allowed
+	_, errWithCode = suite.processor.PollVote(ctx, authed, pollStatus.PollID, []int{1})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	// the poll author can't vote in their own poll
+	authorAuthed := &oauth.Auth{
+		Application: suite.testApplications["local_account_2"],
+		User:        suite.testUsers["local_account_2"],
+		Account:     suite.testAccounts["local_account_2"],
+	}
+	_, errWithCode = suite.processor.PollVote(ctx, authorAuthed, pollStatus.PollID, []int{1})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *PollVoteTestSuite) TestVoteInvalidChoices() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	pollStatus := suite.putPollStatus(suite.testStatuses["local_account_2_status_1"], "01G1Z3W6QF9V5RQ0HJ7B8E5Y7N", "01G1Z3WC4V3X1M2XK9T6GZ1NQE", false)
+
+	for _, choices := range [][]int{nil, {3}, {-1}, {0, 1}} {
+		_, errWithCode := suite.processor.PollVote(ctx, authed, pollStatus.PollID, choices)
+		suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+	}
+
+	// none of the invalid votes should have been counted
+	poll, errWithCode := suite.processor.PollGet(ctx, authed, pollStatus.PollID)
+	suite.NoError(errWithCode)
+	suite.False(poll.Voted)
+	suite.Equal(0, poll.VotesCount)
+}
+
+func (suite *PollVoteTestSuite) TestVoteClosedPoll() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	pollStatus := suite.putPollStatus(suite.testStatuses["local_account_2_status_1"], "01G1Z3W6QF9V5RQ0HJ7B8E5Y7N", "01G1Z3WC4V3X1M2XK9T6GZ1NQE", false)
+
+	pollStatus.Poll.ExpiresAt = time.Now().Add(-1 * time.Minute)
+	if err := suite.db.UpdateByPrimaryKey(ctx, pollStatus.Poll); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, errWithCode := suite.processor.PollVote(ctx, authed, pollStatus.PollID, []int{0})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *PollVoteTestSuite) TestGetPollNotFound() {
+	_, errWithCode := suite.processor.PollGet(context.Background(), suite.testAutheds["local_account_1"], "01G1Z3WC4V3X1M2XK9T6GZ1NQE")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *PollVoteTestSuite) TestVoteRemotePoll() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	pollAuthor := suite.testAccounts["remote_account_1"]
+	pollStatus := suite.putPollStatus(suite.testStatuses["remote_account_1_status_1"], "01G1Z3W6QF9V5RQ0HJ7B8E5Y7N", "01G1Z3WC4V3X1M2XK9T6GZ1NQE", false)
+
+	poll, errWithCode := suite.processor.PollVote(ctx, authed, pollStatus.PollID, []int{1})
+	suite.NoError(errWithCode)
+	suite.True(poll.Voted)
+	suite.Equal([]int{1}, poll.OwnVotes)
+	suite.Equal(1, poll.Options[1].VotesCount)
+	suite.Equal(1, poll.VotesCount)
+
+	// the vote should have been sent to the poll author
+	time.Sleep(1 * time.Second)
+	suite.sentHTTPRequestsLock.Lock()
+	sent, ok := suite.sentHTTPRequests[pollAuthor.InboxURI]
+	suite.sentHTTPRequestsLock.Unlock()
+	suite.True(ok)
+
+	create := &struct {
+		Actor  string `json:"actor"`
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Object struct {
+			ID           string `json:"id"`
+			Type         string `json:"type"`
+			Name         string `json:"name"`
+			AttributedTo string `json:"attributedTo"`
+			InReplyTo    string `json:"inReplyTo"`
+			To           string `json:"to"`
+		} `json:"object"`
+	}{}
+	err := json.Unmarshal(sent, create)
+	suite.NoError(err)
+
+	suite.Equal("Create", create.Type)
+	suite.Equal(authed.Account.URI, create.Actor)
+	suite.Equal(create.Object.ID+"/activity", create.ID)
+	suite.Equal("Note", create.Object.Type)
+	suite.Equal("no", create.Object.Name)
+	suite.Equal(authed.Account.URI, create.Object.AttributedTo)
+	suite.Equal(pollStatus.URI, create.Object.InReplyTo)
+	suite.Equal(pollAuthor.URI, create.Object.To)
+	suite.Contains(create.Object.ID, authed.Account.URI+"#votes/")
+}
+
+func TestPollVoteTestSuite(t *testing.T) {
+	suite.Run(t, &PollVoteTestSuite{})
+}
@@ -0,0 +1,86 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type PreferencesTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *PreferencesTestSuite) TestPreferencesGetAndUpdate() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	// zork hasn't chosen any reading preferences yet
+	preferences, errWithCode := suite.processor.PreferencesGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Equal("public", preferences.PostingDefaultVisibility)
+	suite.False(preferences.PostingDefaultSensitive)
+	suite.Equal("en", preferences.PostingDefaultLanguage)
+	suite.Equal("default", preferences.ReadingExpandMedia)
+	suite.False(preferences.ReadingExpandSpoilers)
+
+	privacy := "private"
+	sensitive := true
+	expandMedia := "show_all"
+	expandSpoilers := true
+	_, err := suite.processor.AccountUpdate(ctx, authed, &apimodel.UpdateCredentialsRequest{
+		Source: &apimodel.UpdateSource{
+			Privacy:        &privacy,
+			Sensitive:      &sensitive,
+			ExpandMedia:    &expandMedia,
+			ExpandSpoilers: &expandSpoilers,
+		},
+	})
+	suite.NoError(err)
+
+	// the new preferences were stored, and the ones that weren't given were left alone
+	account, err := suite.db.GetAccountByID(ctx, authed.Account.ID)
+	suite.NoError(err)
+	authed.Account = account
+
+	preferences, errWithCode = suite.processor.PreferencesGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Equal("private", preferences.PostingDefaultVisibility)
+	suite.True(preferences.PostingDefaultSensitive)
+	suite.Equal("en", preferences.PostingDefaultLanguage)
+	suite.Equal("show_all", preferences.ReadingExpandMedia)
+	suite.True(preferences.ReadingExpandSpoilers)
+}
+
+func (suite *PreferencesTestSuite) TestPreferencesUpdateUnknownExpandMedia() {
+	expandMedia := "show_some"
+	_, err := suite.processor.AccountUpdate(context.Background(), suite.testAutheds["local_account_1"], &apimodel.UpdateCredentialsRequest{
+		Source: &apimodel.UpdateSource{
+			ExpandMedia: &expandMedia,
+		},
+	})
+	suite.EqualError(err, "expand media show_some was not recognized")
+}
+
+func TestPreferencesTestSuite(t *testing.T) {
+	suite.Run(t, &PreferencesTestSuite{})
+}
@@ -23,7 +23,8 @@ import (
 	"net/http"
 	"net/url"
 
-	"codeberg.org/gruf/go-store/kv"
+	"github.com/ReneKroon/ttlcache"
+	"github.com/robfig/cron/v3"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/email"
@@ -40,10 +41,13 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/processing/status"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/streaming"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/user"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
 	"github.com/superseriousbusiness/gotosocial/internal/timeline"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/visibility"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
 )
 
@@ -58,6 +62,10 @@ type Processor interface {
 	Start() error
 	// Stop stops the processor cleanly, finishing handling any remaining messages before closing down.
 	Stop() error
+	// Wait blocks until the processor has finished the work it's been given in the background, like the side
+	// effects of domain blocks and any queued messages. It's for command line actions, which use the processor
+	// and then exit, so that they don't exit before their work is done. The processor must have been started.
+	Wait()
 	// ProcessFromClientAPI processes one message coming from the clientAPI channel, and triggers appropriate side effects.
 	ProcessFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error
 	// ProcessFromFederator processes one message coming from the federator channel, and triggers appropriate side effects.
@@ -75,15 +83,25 @@ type Processor interface {
 	AccountCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountCreateRequest) (*apimodel.Token, error)
 	// AccountDeleteLocal processes the delete of a LOCAL account using the given form.
 	AccountDeleteLocal(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountDeleteRequest) gtserror.WithCode
+	// AccountMove processes the move of a LOCAL account to another account using the given form.
+	AccountMove(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountMoveRequest) gtserror.WithCode
+	// AccountAliasesGet returns the aliases of the authed account.
+	AccountAliasesGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AccountAliases, gtserror.WithCode)
+	// AccountAliasesSet replaces the aliases of the authed account using the given form.
+	AccountAliasesSet(ctx context.Context, authed *oauth.Auth, form *apimodel.AccountAliasesRequest) (*apimodel.AccountAliases, gtserror.WithCode)
 	// AccountGet processes the given request for account information.
 	AccountGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Account, gtserror.WithCode)
 	// AccountGet processes the given request for account information.
 	AccountGetLocalByUsername(ctx context.Context, authed *oauth.Auth, username string) (*apimodel.Account, gtserror.WithCode)
 	// AccountUpdate processes the update of an account with the given form
 	AccountUpdate(ctx context.Context, authed *oauth.Auth, form *apimodel.UpdateCredentialsRequest) (*apimodel.Account, error)
+	// AccountAvatarDelete removes the avatar of the authed account, so that the default avatar is shown instead.
+	AccountAvatarDelete(ctx context.Context, authed *oauth.Auth) (*apimodel.Account, gtserror.WithCode)
+	// AccountHeaderDelete removes the header of the authed account, so that the default header is shown instead.
+	AccountHeaderDelete(ctx context.Context, authed *oauth.Auth) (*apimodel.Account, gtserror.WithCode)
 	// AccountStatusesGet fetches a number of statuses (in time descending order) from the given account, filtered by visibility for
 	// the account given in authed.
-	AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinned bool, mediaOnly bool, publicOnly bool) ([]apimodel.Status, gtserror.WithCode)
+	AccountStatusesGet(ctx context.Context, authed *oauth.Auth, targetAccountID string, limit int, excludeReplies bool, excludeReblogs bool, maxID string, minID string, pinned bool, mediaOnly bool, publicOnly bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 	// AccountFollowersGet fetches a list of the target account's followers.
 	AccountFollowersGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]apimodel.Account, gtserror.WithCode)
 	// AccountFollowingGet fetches a list of the accounts that target account is following.
@@ -98,11 +116,55 @@ type Processor interface {
 	AccountBlockCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 	// AccountBlockRemove handles the removal of a block from authed account to target account, either remote or local.
 	AccountBlockRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountMuteCreate handles the authed account muting the target account, optionally for a limited time.
+	AccountMuteCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string, form *apimodel.AccountMuteRequest) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountMuteRemove handles the authed account unmuting the target account.
+	AccountMuteRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountEndorseCreate handles the authed account featuring the target account on its profile.
+	AccountEndorseCreate(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
+	// AccountEndorseRemove handles the authed account no longer featuring the target account on its profile.
+	AccountEndorseRemove(ctx context.Context, authed *oauth.Auth, targetAccountID string) (*apimodel.Relationship, gtserror.WithCode)
 
 	// AdminAccountAction handles the creation/execution of an action on an account.
 	AdminAccountAction(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminAccountActionRequest) gtserror.WithCode
 	// AdminEmojiCreate handles the creation of a new instance emoji by an admin, using the given form.
 	AdminEmojiCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.EmojiCreateRequest) (*apimodel.Emoji, gtserror.WithCode)
+	// AdminMediaRecache queues remote media that has been removed from the cache to be fetched again.
+	AdminMediaRecache(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminMediaRecacheRequest) (*apimodel.AdminMediaRecacheResponse, gtserror.WithCode)
+	// AdminMediaIntegrityGet returns the results of the last check of stored media for missing or corrupted files.
+	AdminMediaIntegrityGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminMediaIntegrityReport, gtserror.WithCode)
+	// AdminMediaStatsGet returns the current state of media processing and storage.
+	AdminMediaStatsGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminMediaStats, gtserror.WithCode)
+	// AdminDeliveryStatsGet returns how many failed deliveries to other instances are being retried, and how many were given up on.
+	AdminDeliveryStatsGet(ctx context.Context, authed *oauth.Auth) (*apimodel.AdminDeliveryStats, gtserror.WithCode)
+	// AdminDeliveriesGet returns up to limit failed deliveries to other instances, optionally only those to the given domain, most recently tried first.
+	AdminDeliveriesGet(ctx context.Context, authed *oauth.Auth, domain string, limit int) ([]*apimodel.AdminDelivery, gtserror.WithCode)
+	// AdminUnreachableDomainsGet returns the remote domains that deliveries have been failing to, including those that deliveries are suspended to.
+	AdminUnreachableDomainsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	// AdminUnreachableDomainCreate suspends deliveries to a remote domain until they're resumed by an admin, whether or not they've been failing.
+	AdminUnreachableDomainCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminUnreachableDomainCreateRequest) (*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	// AdminUnreachableDomainDelete forgets about one unreachable domain, specified by ID, resuming deliveries to it if they were suspended.
+	AdminUnreachableDomainDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminUnreachableDomain, gtserror.WithCode)
+	// AdminRulesGet returns the rules of this instance that haven't been deleted, in the order they're shown.
+	AdminRulesGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminRule, gtserror.WithCode)
+	// AdminRuleGet returns one rule of this instance, specified by ID.
+	AdminRuleGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminRule, gtserror.WithCode)
+	// AdminRuleCreate adds a new rule to this instance, using the given form.
+	AdminRuleCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminRuleCreateRequest) (*apimodel.AdminRule, gtserror.WithCode)
+	// AdminRuleUpdate changes the text and/or priority of one rule of this instance, specified by ID.
+	AdminRuleUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminRuleUpdateRequest) (*apimodel.AdminRule, gtserror.WithCode)
+	// AdminRuleDelete deletes one rule of this instance, specified by ID. Reports that point to the rule keep doing so.
+	AdminRuleDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminRule, gtserror.WithCode)
+	// AdminReportsGet returns up to limit reports, newest first, optionally only those that are or aren't resolved, or that were made by or about the given accounts.
+	AdminReportsGet(ctx context.Context, authed *oauth.Auth, resolved *bool, accountID string, targetAccountID string, maxID string, sinceID string, limit int) ([]*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportGet returns one report, specified by ID.
+	AdminReportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportAssign assigns one report, specified by ID, to the requesting moderator, or unassigns it from whoever it was assigned to.
+	AdminReportAssign(ctx context.Context, authed *oauth.Auth, id string, assign bool) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportResolve marks one report, specified by ID, as resolved by the requesting moderator, with a comment on what they did about it.
+	AdminReportResolve(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminReportResolveRequest) (*apimodel.AdminReportInfo, gtserror.WithCode)
+	// AdminReportReopen marks one resolved report, specified by ID, as not resolved again.
+	AdminReportReopen(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminReportInfo, gtserror.WithCode)
 	// AdminDomainBlockCreate handles the creation of a new domain block by an admin, using the given form.
 	AdminDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.DomainBlockCreateRequest) (*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlocksImport handles the import of multiple domain blocks by an admin, using the given form.
@@ -111,18 +173,133 @@ type Processor interface {
 	AdminDomainBlocksGet(ctx context.Context, authed *oauth.Auth, export bool) ([]*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlockGet returns one domain block, specified by ID.
 	AdminDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string, export bool) (*apimodel.DomainBlock, gtserror.WithCode)
+	// AdminDomainBlockUpdate changes the severity, media rejection, obfuscation and/or comments of one domain block, specified by ID.
+	AdminDomainBlockUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.DomainBlockUpdateRequest) (*apimodel.DomainBlock, gtserror.WithCode)
 	// AdminDomainBlockDelete deletes one domain block, specified by ID, returning the deleted domain block.
 	AdminDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.DomainBlock, gtserror.WithCode)
+	// AdminEmailDomainBlocksGet returns the email domains that signups are blocked from, along with how often they've been tried.
+	AdminEmailDomainBlocksGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	// AdminEmailDomainBlockGet returns one email domain block, specified by ID.
+	AdminEmailDomainBlockGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	// AdminEmailDomainBlockCreate blocks signups with email addresses on the domain in the given form, and its subdomains.
+	AdminEmailDomainBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminEmailDomainBlockCreateRequest) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	// AdminEmailDomainBlockDelete deletes one email domain block, specified by ID, returning the deleted block.
+	AdminEmailDomainBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminEmailDomainBlock, gtserror.WithCode)
+	// AdminIPBlocksGet returns the IP addresses and ranges that are blocked from signing up and/or accessing this instance.
+	AdminIPBlocksGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.AdminIPBlock, gtserror.WithCode)
+	// AdminIPBlockGet returns one IP block, specified by ID.
+	AdminIPBlockGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	// AdminIPBlockCreate blocks the IP address or range in the given form.
+	AdminIPBlockCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdminIPBlockCreateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	// AdminIPBlockUpdate changes one IP block, specified by ID, using the given form.
+	AdminIPBlockUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.AdminIPBlockUpdateRequest) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	// AdminIPBlockDelete deletes one IP block, specified by ID, returning the deleted block.
+	AdminIPBlockDelete(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.AdminIPBlock, gtserror.WithCode)
+	// AdminMeasuresGet returns how many accounts signed up, statuses were posted, and accounts signed in on this instance
+	// during each of the given number of weeks, up to and including the current one.
+	AdminMeasuresGet(ctx context.Context, authed *oauth.Auth, weeks int) ([]*apimodel.AdminWeeklyMeasures, gtserror.WithCode)
+	// AdminDimensionsGet returns the storage used and media queued on this instance, and up to limit remote domains that have sent it the most statuses lately.
+	AdminDimensionsGet(ctx context.Context, authed *oauth.Auth, limit int) (*apimodel.AdminDimensions, gtserror.WithCode)
+	// AdminTrendsGet returns up to limit hashtags, statuses or links that are trending, most trending first, including ones that aren't shown in public trends.
+	AdminTrendsGet(ctx context.Context, authed *oauth.Auth, trendType gtsmodel.TrendType, limit int) ([]*apimodel.AdminTrend, gtserror.WithCode)
+	// AdminTrendReview approves or rejects the hashtag, status or link with the given ID for being shown in public trends.
+	AdminTrendReview(ctx context.Context, authed *oauth.Auth, trendType gtsmodel.TrendType, targetID string, approve bool) (*apimodel.AdminTrend, gtserror.WithCode)
 
 	// AppCreate processes the creation of a new API application
 	AppCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ApplicationCreateRequest) (*apimodel.Application, error)
+	// AppVerifyCredentials returns the public details of the application that the requesting token belongs to.
+	AppVerifyCredentials(ctx context.Context, authed *oauth.Auth) (*apimodel.Application, gtserror.WithCode)
+	// AppRevokeToken revokes the given access token on behalf of the client it was issued to, after authenticating the client.
+	// Revoking a token that doesn't exist (anymore) isn't an error.
+	AppRevokeToken(ctx context.Context, clientID string, clientSecret string, access string) gtserror.WithCode
 
 	// BlocksGet returns a list of accounts blocked by the requesting account.
 	BlocksGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.BlocksResponse, gtserror.WithCode)
 
+	// MutesGet returns a list of accounts muted by the requesting account, with the expiry of each mute.
+	MutesGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.MutesResponse, gtserror.WithCode)
+
+	// EndorsementsGet returns a list of accounts that the requesting account features on its profile.
+	EndorsementsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.EndorsementsResponse, gtserror.WithCode)
+
+	// DirectoryGet returns up to limit accounts that are listed in the profile directory, skipping the first offset of them.
+	// Order must be either "active" (most recently posted first) or "new" (most recently created first).
+	DirectoryGet(ctx context.Context, authed *oauth.Auth, order string, local bool, limit int, offset int) ([]*apimodel.Account, gtserror.WithCode)
+
+	// FeaturedTagsGet returns the hashtags that the requesting account features on its profile.
+	FeaturedTagsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.FeaturedTag, gtserror.WithCode)
+	// AccountFeaturedTagsGet returns the hashtags that the target account features on its profile.
+	AccountFeaturedTagsGet(ctx context.Context, authed *oauth.Auth, targetAccountID string) ([]*apimodel.FeaturedTag, gtserror.WithCode)
+	// FeaturedTagCreate features a hashtag on the requesting account's profile.
+	FeaturedTagCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FeaturedTagCreateRequest) (*apimodel.FeaturedTag, gtserror.WithCode)
+	// FeaturedTagDelete stops featuring one of the requesting account's featured hashtags.
+	FeaturedTagDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+
+	// TagFollow makes the requesting account follow the hashtag with the given name, so that statuses using it show up in their home timeline.
+	TagFollow(ctx context.Context, authed *oauth.Auth, name string) (*apimodel.Tag, gtserror.WithCode)
+	// TagUnfollow makes the requesting account stop following the hashtag with the given name.
+	TagUnfollow(ctx context.Context, authed *oauth.Auth, name string) (*apimodel.Tag, gtserror.WithCode)
+	// FollowedTagsGet returns a list of hashtags followed by the requesting account.
+	FollowedTagsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, limit int) (*apimodel.FollowedTagsResponse, gtserror.WithCode)
+
+	// ExportCreate starts generating a csv file or archive of the requesting account's data in the background, and returns the pending export.
+	ExportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ExportCreateRequest) (*apimodel.Export, gtserror.WithCode)
+	// ExportsGet returns all the exports of the requesting account that haven't expired yet, newest first.
+	ExportsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Export, gtserror.WithCode)
+	// ExportGet returns the export of the requesting account with the given ID.
+	ExportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Export, gtserror.WithCode)
+	// ExportFileGet returns the file of the export of the requesting account with the given ID, once it's been generated.
+	ExportFileGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Content, gtserror.WithCode)
+
+	// ImportCreate checks an uploaded csv file of accounts, and then starts following, blocking or muting them in the background.
+	ImportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ImportCreateRequest) (*apimodel.Import, gtserror.WithCode)
+	// ImportsGet returns all the imports of the requesting account, newest first, with how far along they are.
+	ImportsGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Import, gtserror.WithCode)
+	// ImportGet returns the import of the requesting account with the given ID, with how far along it is.
+	ImportGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Import, gtserror.WithCode)
+
 	// FileGet handles the fetching of a media attachment file via the fileserver.
 	FileGet(ctx context.Context, authed *oauth.Auth, form *apimodel.GetContentRequestForm) (*apimodel.Content, gtserror.WithCode)
 
+	// FiltersGet returns the keyword filters of the requesting account.
+	FiltersGet(ctx context.Context, authed *oauth.Auth) ([]*apimodel.Filter, gtserror.WithCode)
+	// FilterGet returns one keyword filter of the requesting account.
+	FilterGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Filter, gtserror.WithCode)
+	// FilterCreate creates a hide filter with one keyword for the requesting account.
+	FilterCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterCreateUpdateRequest) (*apimodel.Filter, gtserror.WithCode)
+	// FilterUpdate replaces a keyword of one of the requesting account's filters, and the contexts and expiry of that filter.
+	FilterUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterCreateUpdateRequest) (*apimodel.Filter, gtserror.WithCode)
+	// FilterDelete deletes a keyword of one of the requesting account's filters, and the filter too if that leaves it empty.
+	FilterDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+	// FiltersV2Get returns the filters of the requesting account.
+	FiltersV2Get(ctx context.Context, authed *oauth.Auth) ([]*apimodel.FilterV2, gtserror.WithCode)
+	// FilterV2Get returns one filter of the requesting account.
+	FilterV2Get(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterV2, gtserror.WithCode)
+	// FilterV2Create creates a filter, with any given keywords, for the requesting account.
+	FilterV2Create(ctx context.Context, authed *oauth.Auth, form *apimodel.FilterV2CreateRequest) (*apimodel.FilterV2, gtserror.WithCode)
+	// FilterV2Update updates the options of a filter of the requesting account, and adds, changes, or removes its keywords.
+	FilterV2Update(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterV2UpdateRequest) (*apimodel.FilterV2, gtserror.WithCode)
+	// FilterV2Delete deletes a filter of the requesting account, along with its keywords and statuses.
+	FilterV2Delete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+	// FilterKeywordsGet returns the keywords of a filter of the requesting account.
+	FilterKeywordsGet(ctx context.Context, authed *oauth.Auth, filterID string) ([]*apimodel.FilterKeyword, gtserror.WithCode)
+	// FilterKeywordGet returns one keyword of a filter of the requesting account.
+	FilterKeywordGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterKeyword, gtserror.WithCode)
+	// FilterKeywordCreate adds a keyword to a filter of the requesting account.
+	FilterKeywordCreate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode)
+	// FilterKeywordUpdate replaces a keyword of a filter of the requesting account.
+	FilterKeywordUpdate(ctx context.Context, authed *oauth.Auth, id string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode)
+	// FilterKeywordDelete removes a keyword from a filter of the requesting account.
+	FilterKeywordDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+	// FilterStatusesGet returns the statuses of a filter of the requesting account.
+	FilterStatusesGet(ctx context.Context, authed *oauth.Auth, filterID string) ([]*apimodel.FilterStatus, gtserror.WithCode)
+	// FilterStatusGet returns one status of a filter of the requesting account.
+	FilterStatusGet(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.FilterStatus, gtserror.WithCode)
+	// FilterStatusCreate adds a status to a filter of the requesting account.
+	FilterStatusCreate(ctx context.Context, authed *oauth.Auth, filterID string, form *apimodel.FilterStatusCreateRequest) (*apimodel.FilterStatus, gtserror.WithCode)
+	// FilterStatusDelete removes a status from a filter of the requesting account.
+	FilterStatusDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+
 	// FollowRequestsGet handles the getting of the authed account's incoming follow requests
 	FollowRequestsGet(ctx context.Context, auth *oauth.Auth) ([]apimodel.Account, gtserror.WithCode)
 	// FollowRequestAccept handles the acceptance of a follow request from the given account ID.
@@ -132,6 +309,10 @@ type Processor interface {
 
 	// InstanceGet retrieves instance information for serving at api/v1/instance
 	InstanceGet(ctx context.Context, domain string) (*apimodel.Instance, gtserror.WithCode)
+	// InstanceGetV2 retrieves instance information for serving at api/v2/instance
+	InstanceGetV2(ctx context.Context, domain string) (*apimodel.InstanceV2, gtserror.WithCode)
+	// InstanceRulesGet returns the rules of this instance, in the order they should be shown.
+	InstanceRulesGet(ctx context.Context) ([]apimodel.InstanceRule, gtserror.WithCode)
 	// InstancePatch updates this instance according to the given form.
 	//
 	// It should already be ascertained that the requesting account is authenticated and an admin.
@@ -144,16 +325,63 @@ type Processor interface {
 	// MediaUpdate handles the PUT of a media attachment with the given ID and form
 	MediaUpdate(ctx context.Context, authed *oauth.Auth, attachmentID string, form *apimodel.AttachmentUpdateRequest) (*apimodel.Attachment, gtserror.WithCode)
 
-	// NotificationsGet
-	NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string) ([]*apimodel.Notification, gtserror.WithCode)
+	// NotificationsGet returns the notifications of the requesting account, optionally only of the given types or not of the given exclude types.
+	NotificationsGet(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string) (*apimodel.NotificationsResponse, gtserror.WithCode)
+	// NotificationsGetGrouped returns the notifications of the requesting account like NotificationsGet, but with notifications of the
+	// given grouped types about the same thing coalesced into groups, and the accounts and statuses they refer to returned separately.
+	NotificationsGetGrouped(ctx context.Context, authed *oauth.Auth, limit int, maxID string, sinceID string, minID string, types []string, excludeTypes []string, groupedTypes []string) (*apimodel.GroupedNotificationsResponse, gtserror.WithCode)
+	// NotificationDismiss removes the notification with the given ID from the notifications of the requesting account.
+	NotificationDismiss(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+	// NotificationsClear removes all the notifications of the requesting account.
+	NotificationsClear(ctx context.Context, authed *oauth.Auth) gtserror.WithCode
+
+	// PushSubscriptionGet returns the web push subscription made with the access token of the request.
+	PushSubscriptionGet(ctx context.Context, authed *oauth.Auth) (*apimodel.WebPushSubscription, gtserror.WithCode)
+	// PushSubscriptionCreate subscribes the access token of the request to web push notifications, replacing any subscription it already had.
+	PushSubscriptionCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.WebPushSubscriptionCreateRequest) (*apimodel.WebPushSubscription, gtserror.WithCode)
+	// PushSubscriptionUpdate changes which notifications are sent to the web push subscription made with the access token of the request.
+	PushSubscriptionUpdate(ctx context.Context, authed *oauth.Auth, form *apimodel.WebPushSubscriptionUpdateRequest) (*apimodel.WebPushSubscription, gtserror.WithCode)
+	// PushSubscriptionDelete removes the web push subscription made with the access token of the request, if there is one.
+	PushSubscriptionDelete(ctx context.Context, authed *oauth.Auth) gtserror.WithCode
+
+	// ReportCreate reports an account, and optionally some of its statuses, to the moderators of this instance, using the given form.
+	// Reports about remote accounts are also forwarded to their instance, if the form asks for that.
+	ReportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ReportCreateRequest) (*apimodel.Report, gtserror.WithCode)
 
 	// SearchGet performs a search with the given params, resolving/dereferencing remotely as desired
 	SearchGet(ctx context.Context, authed *oauth.Auth, searchQuery *apimodel.SearchQuery) (*apimodel.SearchResult, gtserror.WithCode)
 
+	// ConversationsGet returns the conversations of direct messages of the requesting account, most recently active first.
+	ConversationsGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int) (*apimodel.ConversationsResponse, gtserror.WithCode)
+	// ConversationRead marks the given conversation of the requesting account as read, returning the conversation.
+	ConversationRead(ctx context.Context, authed *oauth.Auth, id string) (*apimodel.Conversation, gtserror.WithCode)
+	// ConversationDelete removes the given conversation from the conversations of the requesting account.
+	// The statuses in it are left alone.
+	ConversationDelete(ctx context.Context, authed *oauth.Auth, id string) gtserror.WithCode
+
+	// MarkersGet returns the markers of the requesting account for the given timelines (home and/or notifications).
+	// Unknown timelines, and timelines without a marker set, are left out.
+	MarkersGet(ctx context.Context, authed *oauth.Auth, timelines []string) (*apimodel.Marker, gtserror.WithCode)
+	// MarkersSet sets the markers of the requesting account for the timelines given in the form, returning the updated markers.
+	MarkersSet(ctx context.Context, authed *oauth.Auth, form *apimodel.MarkerPostRequest) (*apimodel.Marker, gtserror.WithCode)
+
+	// PreferencesGet returns the posting and reading preferences of the requesting account.
+	PreferencesGet(ctx context.Context, authed *oauth.Auth) (*apimodel.Preferences, gtserror.WithCode)
+
+	// PollGet returns the poll with the given ID, taking account of the privacy settings of the status it's attached to.
+	PollGet(ctx context.Context, authed *oauth.Auth, pollID string) (*apimodel.Poll, gtserror.WithCode)
+	// PollVote casts the requesting account's vote for the given choices in the given poll, returning the updated poll.
+	// Votes in remote polls are federated to the poll's author.
+	PollVote(ctx context.Context, authed *oauth.Auth, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode)
+
 	// StatusCreate processes the given form to create a new status, returning the api model representation of that status if it's OK.
-	StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, error)
+	StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode)
 	// StatusDelete processes the delete of a given status, returning the deleted status if the delete goes through.
 	StatusDelete(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	// StatusEdit processes the given form to edit a status, returning the edited status if the edit goes through.
+	StatusEdit(ctx context.Context, authed *oauth.Auth, targetStatusID string, form *apimodel.StatusEditRequest) (*apimodel.Status, gtserror.WithCode)
+	// StatusHistory returns all versions of the given status, oldest first, taking account of privacy settings and blocks etc.
+	StatusHistory(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.StatusEdit, gtserror.WithCode)
 	// StatusFave processes the faving of a given status, returning the updated status if the fave goes through.
 	StatusFave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
 	// StatusBoost processes the boost/reblog of a given status, returning the newly-created boost if all is well.
@@ -168,20 +396,41 @@ type Processor interface {
 	StatusGet(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
 	// StatusUnfave processes the unfaving of a given status, returning the updated status if the fave goes through.
 	StatusUnfave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error)
+	// StatusPin pins the given status to the profile of the authed account, which must own it, returning the updated status if the pin goes through.
+	StatusPin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusUnpin unpins the given status from the profile of the authed account, returning the updated status if the unpin goes through.
+	StatusUnpin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// StatusReact processes an emoji reaction to a given status, returning the updated status if the reaction goes through.
+	StatusReact(ctx context.Context, authed *oauth.Auth, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode)
+	// StatusUnreact processes the removal of an emoji reaction from a given status, returning the updated status if the removal goes through.
+	StatusUnreact(ctx context.Context, authed *oauth.Auth, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode)
+	// StatusReactions returns the emoji reactions to the given status grouped by emoji, filtered according to privacy settings.
+	StatusReactions(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]apimodel.EmojiReaction, gtserror.WithCode)
+	// StatusTranslate translates the given status into the requested language, or into the locale of the requesting user.
+	StatusTranslate(ctx context.Context, authed *oauth.Auth, targetStatusID string, form *apimodel.StatusTranslateRequest) (*apimodel.Translation, gtserror.WithCode)
 	// StatusGetContext returns the context (previous and following posts) from the given status ID
 	StatusGetContext(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Context, gtserror.WithCode)
 
 	// HomeTimelineGet returns statuses from the home timeline, with the given filters/parameters.
 	HomeTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
-	// PublicTimelineGet returns statuses from the public/local timeline, with the given filters/parameters.
-	PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	// PublicTimelineGet returns statuses from the public/local/remote timeline, with the given filters/parameters.
+	PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool, remote bool, onlyMedia bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
+	// TagTimelineGet returns public statuses that use the given hashtag or any of anyTags, all of allTags, and none of noneTags.
+	TagTimelineGet(ctx context.Context, authed *oauth.Auth, tagName string, anyTags []string, allTags []string, noneTags []string, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 	// FavedTimelineGet returns faved statuses, with the given filters/parameters.
 	FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode)
 
+	// TrendsTagsGet returns up to limit hashtags that are trending on this instance, most trending first, skipping the first offset of them.
+	TrendsTagsGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]apimodel.Tag, gtserror.WithCode)
+	// TrendsStatusesGet returns up to limit statuses that are trending on this instance, most trending first, skipping the first offset of them.
+	TrendsStatusesGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]*apimodel.Status, gtserror.WithCode)
+	// TrendsLinksGet returns up to limit links that are trending on this instance, most trending first, skipping the first offset of them.
+	TrendsLinksGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]*apimodel.TrendsLink, gtserror.WithCode)
+
 	// AuthorizeStreamingRequest returns a gotosocial account in exchange for an access token, or an error if the given token is not valid.
 	AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error)
-	// OpenStreamForAccount opens a new stream for the given account, with the given stream type.
-	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string) (*stream.Stream, gtserror.WithCode)
+	// OpenStreamForAccount opens a new stream for the given account, with the given stream type and (for hashtag streams) tag.
+	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamType string, tag string) (*stream.Stream, gtserror.WithCode)
 
 	// UserChangePassword changes the password for the given user, with the given form.
 	UserChangePassword(ctx context.Context, authed *oauth.Auth, form *apimodel.PasswordChangeRequest) gtserror.WithCode
@@ -201,11 +450,11 @@ type Processor interface {
 	// before returning a JSON serializable interface to the caller.
 	GetFediUser(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 	// GetFediFollowers handles the getting of a fedi/activitypub representation of a user/account's followers, performing appropriate
-	// authentication before returning a JSON serializable interface to the caller.
-	GetFediFollowers(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// authentication before returning a JSON serializable interface to the caller. If page is true, a single page of the collection is returned.
+	GetFediFollowers(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 	// GetFediFollowing handles the getting of a fedi/activitypub representation of a user/account's following, performing appropriate
-	// authentication before returning a JSON serializable interface to the caller.
-	GetFediFollowing(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// authentication before returning a JSON serializable interface to the caller. If page is true, a single page of the collection is returned.
+	GetFediFollowing(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 	// GetFediStatus handles the getting of a fedi/activitypub representation of a particular status, performing appropriate
 	// authentication before returning a JSON serializable interface to the caller.
 	GetFediStatus(ctx context.Context, requestedUsername string, requestedStatusID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
@@ -214,12 +463,18 @@ type Processor interface {
 	GetFediStatusReplies(ctx context.Context, requestedUsername string, requestedStatusID string, page bool, onlyOtherAccounts bool, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 	// GetFediOutbox returns the public outbox of the requested user, with the given parameters.
 	GetFediOutbox(ctx context.Context, requestedUsername string, page bool, maxID string, minID string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// GetFediFeaturedCollection returns the featured collection of the requested user, containing their pinned statuses.
+	GetFediFeaturedCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// GetFediFeaturedAccountsCollection returns the featured accounts collection of the requested user, containing the accounts they endorse.
+	GetFediFeaturedAccountsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
+	// GetFediFeaturedTagsCollection returns the featured tags collection of the requested user, containing the hashtags they feature.
+	GetFediFeaturedTagsCollection(ctx context.Context, requestedUsername string, requestURL *url.URL) (interface{}, gtserror.WithCode)
 	// GetWebfingerAccount handles the GET for a webfinger resource. Most commonly, it will be used for returning account lookups.
 	GetWebfingerAccount(ctx context.Context, requestedUsername string) (*apimodel.WellKnownResponse, gtserror.WithCode)
 	// GetNodeInfoRel returns a well known response giving the path to node info.
 	GetNodeInfoRel(ctx context.Context, request *http.Request) (*apimodel.WellKnownResponse, gtserror.WithCode)
-	// GetNodeInfo returns a node info struct in response to a node info request.
-	GetNodeInfo(ctx context.Context, request *http.Request) (*apimodel.Nodeinfo, gtserror.WithCode)
+	// GetNodeInfo returns a node info struct with the given schema version in response to a node info request.
+	GetNodeInfo(ctx context.Context, request *http.Request, version string) (*apimodel.Nodeinfo, gtserror.WithCode)
 	// InboxPost handles POST requests to a user's inbox for new activitypub messages.
 	//
 	// InboxPost returns true if the request was handled as an ActivityPub POST to an actor's inbox.
@@ -237,15 +492,29 @@ type Processor interface {
 type processor struct {
 	clientWorker *worker.Worker[messages.FromClientAPI]
 	fedWorker    *worker.Worker[messages.FromFederator]
+	pushWorker   *worker.Worker[pushJob]
 
 	federator       federation.Federator
 	tc              typeutils.TypeConverter
 	oauthServer     oauth.Server
 	mediaManager    media.Manager
-	storage         *kv.KVStore
+	storage         *gtsstorage.Driver
 	statusTimelines timeline.Manager
 	db              db.DB
 	filter          visibility.Filter
+	webPushSender   webpush.Sender
+
+	// ctx is cancelled when the processor is told to stop, so that background work like scheduled jobs,
+	// exports, and imports can give up, and cancel does the cancelling
+	ctx    context.Context
+	cancel context.CancelFunc
+	// scheduledJobs are the cron jobs started by startScheduledJob, which are stopped along with the processor
+	scheduledJobs []*cron.Cron
+	// accountRefreshCursor is the ID of the last stale account the account refresher looked at, so that each
+	// run carries on from where the last one stopped; it's only touched by the refresher job, which never overlaps itself
+	accountRefreshCursor string
+	// trendsCache holds trends for a while after they've been ranked
+	trendsCache *ttlcache.Cache
 
 	/*
 		SUB-PROCESSORS
@@ -266,26 +535,30 @@ func NewProcessor(
 	federator federation.Federator,
 	oauthServer oauth.Server,
 	mediaManager media.Manager,
-	storage *kv.KVStore,
+	storage *gtsstorage.Driver,
 	db db.DB,
 	emailSender email.Sender,
+	webPushSender webpush.Sender,
+	translator translate.Translator,
 	clientWorker *worker.Worker[messages.FromClientAPI],
 	fedWorker *worker.Worker[messages.FromFederator],
 ) Processor {
 	parseMentionFunc := GetParseMentionFunc(db, federator)
 
-	statusProcessor := status.New(db, tc, clientWorker, parseMentionFunc)
-	streamingProcessor := streaming.New(db, oauthServer)
+	statusProcessor := status.New(db, tc, clientWorker, federator, parseMentionFunc, translator)
+	streamingProcessor := streaming.New(db, tc, oauthServer)
 	accountProcessor := account.New(db, tc, mediaManager, oauthServer, clientWorker, federator, parseMentionFunc)
-	adminProcessor := admin.New(db, tc, mediaManager, clientWorker)
-	mediaProcessor := mediaProcessor.New(db, tc, mediaManager, federator.TransportController(), storage)
+	adminProcessor := admin.New(db, tc, mediaManager, federator.TransportController(), clientWorker)
+	mediaProcessor := mediaProcessor.New(db, tc, mediaManager, federator.TransportController(), storage, clientWorker)
 	userProcessor := user.New(db, emailSender)
 	federationProcessor := federationProcessor.New(db, tc, federator)
 	filter := visibility.NewFilter(db)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &processor{
 		clientWorker: clientWorker,
 		fedWorker:    fedWorker,
+		pushWorker:   worker.New[pushJob](-1, -1),
 
 		federator:       federator,
 		tc:              tc,
@@ -295,6 +568,10 @@ func NewProcessor(
 		statusTimelines: timeline.NewManager(StatusGrabFunction(db), StatusFilterFunction(db, filter), StatusPrepareFunction(db, tc), StatusSkipInsertFunction()),
 		db:              db,
 		filter:          visibility.NewFilter(db),
+		webPushSender:   webPushSender,
+		trendsCache:     newRankedTrendsCache(),
+		ctx:             ctx,
+		cancel:          cancel,
 
 		accountProcessor:    accountProcessor,
 		adminProcessor:      adminProcessor,
@@ -320,9 +597,68 @@ func (p *processor) Start() error {
 		return err
 	}
 
+	// Setup and start the web push worker pool, so that push services don't hold up notifications
+	p.pushWorker.SetProcessor(p.sendPushNotification)
+	if err := p.pushWorker.Start(); err != nil {
+		return err
+	}
+
+	// Pick up any remote media that was still being processed when we were last stopped
+	if _, err := p.mediaManager.ResumeJobs(context.Background()); err != nil {
+		return err
+	}
+
+	// Close polls once they expire
+	if err := p.startPollCloser(); err != nil {
+		return err
+	}
+
+	// Retry deliveries that failed, including any from before we were restarted
+	if err := p.startDeliveryRetrier(); err != nil {
+		return err
+	}
+
+	// Keep remote accounts from going stale
+	if err := p.startAccountRefresher(); err != nil {
+		return err
+	}
+
+	// Lift mutes once they expire
+	if err := p.startMuteLifter(); err != nil {
+		return err
+	}
+
+	// Delete IP blocks once they expire
+	if err := p.startIPBlockCleaner(); err != nil {
+		return err
+	}
+
+	// Finish any exports that were still being generated when we were last stopped, and delete old ones
+	if err := p.startExportCleaner(); err != nil {
+		return err
+	}
+
+	// Carry on with any imports that were still being worked through when we were last stopped
+	if err := p.startImporter(); err != nil {
+		return err
+	}
+
+	// Store accesses to remote media every so often, rather than on every access
+	if err := p.startAccessCountFlusher(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func (p *processor) Wait() {
+	p.adminProcessor.Wait()
+	// messages can queue other messages, on either worker
+	p.clientWorker.Wait()
+	p.fedWorker.Wait()
+	p.clientWorker.Wait()
+}
+
 // Stop stops the processor cleanly, finishing handling any remaining messages before closing down.
 func (p *processor) Stop() error {
 	if err := p.clientWorker.Stop(); err != nil {
@@ -331,5 +667,16 @@ func (p *processor) Stop() error {
 	if err := p.fedWorker.Stop(); err != nil {
 		return err
 	}
-	return nil
+	if err := p.pushWorker.Stop(); err != nil {
+		return err
+	}
+
+	// stop background work, and wait for any scheduled jobs that are running to finish giving up
+	p.cancel()
+	for _, c := range p.scheduledJobs {
+		<-c.Stop().Done()
+	}
+
+	// store any accesses to remote media counted since the flusher last ran, so they aren't lost
+	return p.mediaProcessor.FlushAccessCounts(context.Background())
 }
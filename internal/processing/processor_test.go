@@ -25,8 +25,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sync"
 
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -37,6 +37,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 	"github.com/superseriousbusiness/gotosocial/internal/timeline"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
@@ -48,7 +49,7 @@ type ProcessingStandardTestSuite struct {
 	// standard suite interfaces
 	suite.Suite
 	db                  db.DB
-	storage             *kv.KVStore
+	storage             *gtsstorage.Driver
 	mediaManager        media.Manager
 	typeconverter       typeutils.TypeConverter
 	transportController transport.Controller
@@ -56,6 +57,7 @@ type ProcessingStandardTestSuite struct {
 	oauthServer         oauth.Server
 	timelineManager     timeline.Manager
 	emailSender         email.Sender
+	sentPushes          chan testrig.SentPush
 
 	// standard suite models
 	testTokens       map[string]*gtsmodel.Token
@@ -71,7 +73,8 @@ type ProcessingStandardTestSuite struct {
 	testBlocks       map[string]*gtsmodel.Block
 	testActivities   map[string]testrig.ActivityWithSignature
 
-	sentHTTPRequests map[string][]byte
+	sentHTTPRequests     map[string][]byte
+	sentHTTPRequestsLock sync.Mutex // deliveries can happen concurrently
 
 	processor processing.Processor
 }
@@ -92,6 +95,11 @@ func (suite *ProcessingStandardTestSuite) SetupSuite() {
 			User:        suite.testUsers["local_account_1"],
 			Account:     suite.testAccounts["local_account_1"],
 		},
+		"admin_account": {
+			Application: suite.testApplications["admin_account"],
+			User:        suite.testUsers["admin_account"],
+			Account:     suite.testAccounts["admin_account"],
+		},
 	}
 	suite.testBlocks = testrig.NewTestBlocks()
 }
@@ -117,7 +125,9 @@ func (suite *ProcessingStandardTestSuite) SetupTest() {
 			if err := req.Body.Close(); err != nil {
 				panic(err)
 			}
+			suite.sentHTTPRequestsLock.Lock()
 			suite.sentHTTPRequests[req.URL.String()] = requestBytes
+			suite.sentHTTPRequestsLock.Unlock()
 		}
 
 		if req.URL.String() == suite.testAccounts["remote_account_1"].URI {
@@ -226,7 +236,8 @@ func (suite *ProcessingStandardTestSuite) SetupTest() {
 	suite.oauthServer = testrig.NewTestOauthServer(suite.db)
 	suite.emailSender = testrig.NewEmailSender("../../web/template/", nil)
 
-	suite.processor = processing.NewProcessor(suite.typeconverter, suite.federator, suite.oauthServer, suite.mediaManager, suite.storage, suite.db, suite.emailSender, clientWorker, fedWorker)
+	suite.sentPushes = make(chan testrig.SentPush, 10)
+	suite.processor = processing.NewProcessor(suite.typeconverter, suite.federator, suite.oauthServer, suite.mediaManager, suite.storage, suite.db, suite.emailSender, testrig.NewWebPushSender(suite.sentPushes), testrig.NewTestTranslator(), clientWorker, fedWorker)
 
 	testrig.StandardDBSetup(suite.db, suite.testAccounts)
 	testrig.StandardStorageSetup(suite.storage, "../../testrig/media")
@@ -0,0 +1,173 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+)
+
+// reportCommentMaxChars is the most characters that the comment on a report may have.
+const reportCommentMaxChars = 1000
+
+func (p *processor) ReportCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.ReportCreateRequest) (*apimodel.Report, gtserror.WithCode) {
+	if form.AccountID == "" {
+		err := errors.New("account_id must be set")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.AccountID == authed.Account.ID {
+		err := errors.New("you can't report your own account")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	targetAccount, err := p.db.GetAccountByID(ctx, form.AccountID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := fmt.Errorf("account %s not found", form.AccountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting account to report: %s", err))
+	}
+
+	if len([]rune(form.Comment)) > reportCommentMaxChars {
+		err := fmt.Errorf("comment must be at most %d characters", reportCommentMaxChars)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// like mastodon, a report that points to rules is about breaking them unless it says otherwise
+	category := gtsmodel.ReportCategory(form.Category)
+	if category == "" {
+		category = gtsmodel.ReportCategoryOther
+		if len(form.RuleIDs) != 0 {
+			category = gtsmodel.ReportCategoryViolation
+		}
+	}
+
+	ruleIDs := []string{}
+	switch category {
+	case gtsmodel.ReportCategoryViolation:
+		var errWithCode gtserror.WithCode
+		ruleIDs, errWithCode = p.reportRuleIDs(ctx, form.RuleIDs)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+	case gtsmodel.ReportCategorySpam, gtsmodel.ReportCategoryOther:
+		// rules only matter for violations, so any that were given are left out
+	default:
+		err := fmt.Errorf("category %s was not recognized", category)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	statusIDs := []string{}
+	for _, statusID := range form.StatusIDs {
+		status, err := p.db.GetStatusByID(ctx, statusID)
+		if err != nil {
+			if err == db.ErrNoEntries {
+				err := fmt.Errorf("status %s not found", statusID)
+				return nil, gtserror.NewErrorNotFound(err, err.Error())
+			}
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting status to report: %s", err))
+		}
+		if status.AccountID != targetAccount.ID {
+			err := fmt.Errorf("status %s was not posted by account %s", statusID, targetAccount.ID)
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+		statusIDs = append(statusIDs, status.ID)
+	}
+
+	reportID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	report := &gtsmodel.Report{
+		ID:              reportID,
+		URI:             uris.GenerateURIForReport(reportID),
+		AccountID:       authed.Account.ID,
+		Account:         authed.Account,
+		TargetAccountID: targetAccount.ID,
+		TargetAccount:   targetAccount,
+		StatusIDs:       statusIDs,
+		Category:        category,
+		RuleIDs:         ruleIDs,
+		Comment:         form.Comment,
+	}
+
+	if err := p.db.Put(ctx, report); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting report: %s", err))
+	}
+
+	// reports about remote accounts can be sent on to their instance as a flag, which marks the report as forwarded
+	if form.Forward && targetAccount.Domain != "" {
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ActivityFlag,
+			APActivityType: ap.ActivityCreate,
+			GTSModel:       report,
+			OriginAccount:  authed.Account,
+			TargetAccount:  targetAccount,
+		})
+	}
+
+	apiReport, err := p.tc.ReportToAPIReport(ctx, report)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting report to api representation: %s", err))
+	}
+
+	return apiReport, nil
+}
+
+// reportRuleIDs checks that the given IDs are of rules of this instance which are still in force,
+// and that there's at least one of them, since a violation has to be of some rule.
+func (p *processor) reportRuleIDs(ctx context.Context, ruleIDs []string) ([]string, gtserror.WithCode) {
+	if len(ruleIDs) == 0 {
+		err := errors.New("rule_ids must be set when the category is violation")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	rules, err := p.db.GetActiveRules(ctx)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting rules: %s", err))
+	}
+
+	active := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		active[r.ID] = true
+	}
+
+	for _, ruleID := range ruleIDs {
+		if !active[ruleID] {
+			err := fmt.Errorf("rule %s not found", ruleID)
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+	}
+
+	return ruleIDs, nil
+}
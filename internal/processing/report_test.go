@@ -0,0 +1,189 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type ReportTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *ReportTestSuite) TestReportRemoteAndForward() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+	reportedAccount := suite.testAccounts["remote_account_1"]
+	reportedStatus := suite.testStatuses["remote_account_1_status_1"]
+
+	report, errWithCode := suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: reportedAccount.ID,
+		StatusIDs: []string{reportedStatus.ID},
+		Comment:   "nothing but ads",
+		Forward:   true,
+		Category:  "spam",
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("spam", report.Category)
+	suite.Equal("nothing but ads", report.Comment)
+	suite.Equal([]string{reportedStatus.ID}, report.StatusIDs)
+	suite.Empty(report.RuleIDs)
+	suite.False(report.ActionTaken)
+	suite.Nil(report.ActionTakenAt)
+	suite.Equal(reportedAccount.ID, report.TargetAccount.ID)
+
+	dbReport := &gtsmodel.Report{}
+	suite.NoError(suite.db.GetByID(ctx, report.ID, dbReport))
+	suite.Equal(authed.Account.ID, dbReport.AccountID)
+	suite.Equal("http://localhost:8080/reports/"+report.ID, dbReport.URI)
+	suite.Equal(gtsmodel.ReportCategorySpam, dbReport.Category)
+
+	// the report should have been forwarded to the instance of the reported account
+	time.Sleep(1 * time.Second)
+	suite.sentHTTPRequestsLock.Lock()
+	_, ok := suite.sentHTTPRequests[reportedAccount.InboxURI]
+	suite.sentHTTPRequestsLock.Unlock()
+	suite.True(ok)
+
+	suite.NoError(suite.db.GetByID(ctx, report.ID, dbReport))
+	suite.True(dbReport.Forwarded)
+}
+
+func (suite *ReportTestSuite) TestResolveReportAndForward() {
+	ctx := context.Background()
+	reportedAccount := suite.testAccounts["remote_account_1"]
+
+	// a report that the reporter didn't want forwarded
+	report, errWithCode := suite.processor.ReportCreate(ctx, suite.testAutheds["local_account_1"], &apimodel.ReportCreateRequest{
+		AccountID: reportedAccount.ID,
+		Comment:   "nothing but ads",
+		Category:  "spam",
+	})
+	suite.NoError(errWithCode)
+
+	time.Sleep(1 * time.Second)
+	suite.sentHTTPRequestsLock.Lock()
+	_, ok := suite.sentHTTPRequests[reportedAccount.InboxURI]
+	suite.sentHTTPRequestsLock.Unlock()
+	suite.False(ok)
+
+	// the moderator resolving it can forward it anyway
+	resolved, errWithCode := suite.processor.AdminReportResolve(ctx, suite.testAutheds["admin_account"], report.ID, &apimodel.AdminReportResolveRequest{
+		ActionTakenComment: "suspended the account",
+		Forward:            true,
+	})
+	suite.NoError(errWithCode)
+	suite.True(resolved.ActionTaken)
+
+	time.Sleep(1 * time.Second)
+	suite.sentHTTPRequestsLock.Lock()
+	_, ok = suite.sentHTTPRequests[reportedAccount.InboxURI]
+	suite.sentHTTPRequestsLock.Unlock()
+	suite.True(ok)
+
+	dbReport := &gtsmodel.Report{}
+	suite.NoError(suite.db.GetByID(ctx, report.ID, dbReport))
+	suite.True(dbReport.Forwarded)
+	suite.False(dbReport.ActionTakenAt.IsZero())
+}
+
+func (suite *ReportTestSuite) TestReportViolation() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	rule := &gtsmodel.Rule{
+		ID:   "01GB3Z7Y8M7C6WDCWD3YJ6J6AX",
+		Text: "Don't be a jerk.",
+	}
+	suite.NoError(suite.db.Put(ctx, rule))
+
+	// giving rules makes the report a violation, and nothing is forwarded unless asked for
+	report, errWithCode := suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["remote_account_1"].ID,
+		RuleIDs:   []string{rule.ID},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("violation", report.Category)
+	suite.Equal([]string{rule.ID}, report.RuleIDs)
+	suite.Empty(report.StatusIDs)
+
+	time.Sleep(1 * time.Second)
+	suite.sentHTTPRequestsLock.Lock()
+	suite.Empty(suite.sentHTTPRequests)
+	suite.sentHTTPRequestsLock.Unlock()
+
+	// a violation has to be of some rule that's in force
+	_, errWithCode = suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["local_account_2"].ID,
+		Category:  "violation",
+	})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	_, errWithCode = suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["local_account_2"].ID,
+		RuleIDs:   []string{"01GB3ZCVD2BV0NRMAXZ1R5SS64"},
+	})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+	suite.Equal("unprocessable entity: rule 01GB3ZCVD2BV0NRMAXZ1R5SS64 not found", errWithCode.Safe())
+
+	// rules are left off reports of other kinds
+	report, errWithCode = suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["local_account_2"].ID,
+		Category:  "other",
+		RuleIDs:   []string{rule.ID},
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("other", report.Category)
+	suite.Empty(report.RuleIDs)
+}
+
+func (suite *ReportTestSuite) TestReportInvalid() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	// accounts can't report themselves
+	_, errWithCode := suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: authed.Account.ID,
+	})
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	// statuses have to be by the reported account
+	_, errWithCode = suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["remote_account_1"].ID,
+		StatusIDs: []string{suite.testStatuses["local_account_2_status_1"].ID},
+	})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	_, errWithCode = suite.processor.ReportCreate(ctx, authed, &apimodel.ReportCreateRequest{
+		AccountID: suite.testAccounts["remote_account_1"].ID,
+		Category:  "rudeness",
+	})
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func TestReportTestSuite(t *testing.T) {
+	suite.Run(t, &ReportTestSuite{})
+}
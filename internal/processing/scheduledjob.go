@@ -0,0 +1,59 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+
+	"github.com/robfig/cron/v3"
+)
+
+// startScheduledJob starts running fn on the given cron schedule until the processor is stopped.
+//
+// fn is given the processor's context, which is cancelled when the processor is told to stop,
+// so that a run in progress can give up. A run is skipped if the one before it hasn't finished yet.
+func (p *processor) startScheduledJob(schedule string, fn func(ctx context.Context)) error {
+	_, err := p.scheduleJob(schedule, fn)
+	return err
+}
+
+// startScheduledJobNow is like startScheduledJob, but also runs fn once straight away, without waiting
+// for the schedule. Scheduled runs are skipped while that first run is still going, as usual.
+func (p *processor) startScheduledJobNow(schedule string, fn func(ctx context.Context)) error {
+	job, err := p.scheduleJob(schedule, fn)
+	if err != nil {
+		return err
+	}
+
+	go job.Run()
+	return nil
+}
+
+// scheduleJob starts running fn on the given cron schedule, and returns the job as it's run by the schedule.
+func (p *processor) scheduleJob(schedule string, fn func(ctx context.Context)) (cron.Job, error) {
+	c := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+	entryID, err := c.AddFunc(schedule, func() { fn(p.ctx) })
+	if err != nil {
+		return nil, err
+	}
+
+	p.scheduledJobs = append(p.scheduledJobs, c)
+	c.Start()
+	return c.Entry(entryID).WrappedJob, nil
+}
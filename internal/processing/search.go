@@ -82,6 +82,17 @@ func (p *processor) SearchGet(ctx context.Context, authed *oauth.Auth, searchQue
 		}
 	}
 
+	// if the query didn't turn up a status by URI, search the text of statuses the user has posted or interacted with
+	if !foundOne && len(foundStatuses) == 0 && viper.GetBool(config.Keys.StatusesSearchEnabled) &&
+		(searchQuery.Type == "" || searchQuery.Type == "statuses") {
+		l.Debug("searching status text...")
+		statuses, err := p.db.SearchStatuses(ctx, authed.Account.ID, query, searchQuery.AccountID, searchQuery.MaxID, searchQuery.MinID, searchQuery.Limit, searchQuery.Offset)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("SearchGet: error searching statuses: %s", err))
+		}
+		foundStatuses = append(foundStatuses, statuses...)
+	}
+
 	/*
 		FROM HERE ON we have our search results, it's just a matter of filtering them according to what this user is allowed to see,
 		and then converting them into our frontend format.
@@ -167,7 +178,8 @@ func (p *processor) searchAccountByMention(ctx context.Context, authed *oauth.Au
 	// if it's a local account we can skip a whole bunch of stuff
 	maybeAcct := &gtsmodel.Account{}
 	host := viper.GetString(config.Keys.Host)
-	if domain == host {
+	accountDomain := viper.GetString(config.Keys.AccountDomain)
+	if domain == host || (accountDomain != "" && domain == accountDomain) {
 		maybeAcct, err = p.db.GetLocalAccountByUsername(ctx, username)
 		if err != nil {
 			return nil, fmt.Errorf("searchAccountByMention: error getting local account by username: %s", err)
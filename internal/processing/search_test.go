@@ -0,0 +1,67 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+type SearchTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *SearchTestSuite) TestSearchStatusText() {
+	viper.Set(config.Keys.StatusesSearchEnabled, true)
+	authed := suite.testAutheds["local_account_1"]
+
+	result, errWithCode := suite.processor.SearchGet(context.Background(), authed, &apimodel.SearchQuery{Query: "Hello", Type: "statuses", Limit: 20})
+	suite.NoError(errWithCode)
+	suite.Len(result.Statuses, 2)
+	suite.Equal(suite.testStatuses["local_account_1_status_1"].ID, result.Statuses[0].ID)
+	suite.Equal(suite.testStatuses["admin_account_status_1"].ID, result.Statuses[1].ID)
+	suite.Empty(result.Accounts)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusTextAccounts() {
+	viper.Set(config.Keys.StatusesSearchEnabled, true)
+	authed := suite.testAutheds["local_account_1"]
+
+	// statuses aren't searched when only looking for accounts
+	result, errWithCode := suite.processor.SearchGet(context.Background(), authed, &apimodel.SearchQuery{Query: "hello", Type: "accounts", Limit: 20})
+	suite.NoError(errWithCode)
+	suite.Empty(result.Statuses)
+}
+
+func (suite *SearchTestSuite) TestSearchStatusTextDisabled() {
+	authed := suite.testAutheds["local_account_1"]
+
+	result, errWithCode := suite.processor.SearchGet(context.Background(), authed, &apimodel.SearchQuery{Query: "hello", Limit: 20})
+	suite.NoError(errWithCode)
+	suite.Empty(result.Statuses)
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
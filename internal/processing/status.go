@@ -26,7 +26,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 )
 
-func (p *processor) StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, error) {
+func (p *processor) StatusCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode) {
 	return p.statusProcessor.Create(ctx, authed.Account, authed.Application, form)
 }
 
@@ -34,6 +34,14 @@ func (p *processor) StatusDelete(ctx context.Context, authed *oauth.Auth, target
 	return p.statusProcessor.Delete(ctx, authed.Account, targetStatusID)
 }
 
+func (p *processor) StatusEdit(ctx context.Context, authed *oauth.Auth, targetStatusID string, form *apimodel.StatusEditRequest) (*apimodel.Status, gtserror.WithCode) {
+	return p.statusProcessor.Edit(ctx, authed.Account, targetStatusID, form)
+}
+
+func (p *processor) StatusHistory(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]*apimodel.StatusEdit, gtserror.WithCode) {
+	return p.statusProcessor.History(ctx, authed.Account, targetStatusID)
+}
+
 func (p *processor) StatusFave(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, error) {
 	return p.statusProcessor.Fave(ctx, authed.Account, targetStatusID)
 }
@@ -62,6 +70,38 @@ func (p *processor) StatusUnfave(ctx context.Context, authed *oauth.Auth, target
 	return p.statusProcessor.Unfave(ctx, authed.Account, targetStatusID)
 }
 
+func (p *processor) StatusPin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	return p.statusProcessor.Pin(ctx, authed.Account, targetStatusID)
+}
+
+func (p *processor) StatusUnpin(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	return p.statusProcessor.Unpin(ctx, authed.Account, targetStatusID)
+}
+
+func (p *processor) StatusReact(ctx context.Context, authed *oauth.Auth, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode) {
+	return p.statusProcessor.React(ctx, authed.Account, targetStatusID, emoji)
+}
+
+func (p *processor) StatusUnreact(ctx context.Context, authed *oauth.Auth, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode) {
+	return p.statusProcessor.Unreact(ctx, authed.Account, targetStatusID, emoji)
+}
+
+func (p *processor) StatusReactions(ctx context.Context, authed *oauth.Auth, targetStatusID string) ([]apimodel.EmojiReaction, gtserror.WithCode) {
+	return p.statusProcessor.Reactions(ctx, authed.Account, targetStatusID)
+}
+
 func (p *processor) StatusGetContext(ctx context.Context, authed *oauth.Auth, targetStatusID string) (*apimodel.Context, gtserror.WithCode) {
 	return p.statusProcessor.Context(ctx, authed.Account, targetStatusID)
 }
+
+func (p *processor) StatusTranslate(ctx context.Context, authed *oauth.Auth, targetStatusID string, form *apimodel.StatusTranslateRequest) (*apimodel.Translation, gtserror.WithCode) {
+	targetLanguage := form.Lang
+	if targetLanguage == "" && authed.User != nil {
+		targetLanguage = authed.User.Locale
+	}
+	if targetLanguage == "" {
+		targetLanguage = "en"
+	}
+
+	return p.statusProcessor.Translate(ctx, authed.Account, targetStatusID, targetLanguage)
+}
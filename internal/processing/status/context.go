@@ -22,8 +22,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
 
+	"github.com/sirupsen/logrus"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
@@ -46,6 +48,11 @@ func (p *processor) Context(ctx context.Context, requestingAccount *gtsmodel.Acc
 		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
 	}
 
+	// pull in any replies to a remote status that weren't delivered to us, so they show up next time the thread is opened
+	if !targetStatus.Local {
+		go p.backfillThread(targetStatus, requestingAccount)
+	}
+
 	context := &apimodel.Context{
 		Ancestors:   []apimodel.Status{},
 		Descendants: []apimodel.Status{},
@@ -57,9 +64,14 @@ func (p *processor) Context(ctx context.Context, requestingAccount *gtsmodel.Acc
 	}
 
 	for _, status := range parents {
-		if v, err := p.filter.StatusVisible(ctx, status, requestingAccount); err == nil && v {
+		if p.threadStatusVisible(ctx, status, requestingAccount) {
+			hide, results := p.threadStatusFilterResults(ctx, status, requestingAccount)
+			if hide {
+				continue
+			}
 			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount)
 			if err == nil {
+				apiStatus.Filtered = results
 				context.Ancestors = append(context.Ancestors, *apiStatus)
 			}
 		}
@@ -75,9 +87,14 @@ func (p *processor) Context(ctx context.Context, requestingAccount *gtsmodel.Acc
 	}
 
 	for _, status := range children {
-		if v, err := p.filter.StatusVisible(ctx, status, requestingAccount); err == nil && v {
+		if p.threadStatusVisible(ctx, status, requestingAccount) {
+			hide, results := p.threadStatusFilterResults(ctx, status, requestingAccount)
+			if hide {
+				continue
+			}
 			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount)
 			if err == nil {
+				apiStatus.Filtered = results
 				context.Descendants = append(context.Descendants, *apiStatus)
 			}
 		}
@@ -85,3 +102,56 @@ func (p *processor) Context(ctx context.Context, requestingAccount *gtsmodel.Acc
 
 	return context, nil
 }
+
+func (p *processor) backfillThread(targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) {
+	statusURI, err := url.Parse(targetStatus.URI)
+	if err != nil {
+		logrus.Errorf("backfillThread: error parsing uri %s: %s", targetStatus.URI, err)
+		return
+	}
+
+	var username string
+	if requestingAccount != nil {
+		username = requestingAccount.Username
+	}
+
+	if err := p.federator.DereferenceRemoteThread(context.Background(), username, statusURI); err != nil {
+		logrus.Debugf("backfillThread: error dereferencing thread of status %s: %s", targetStatus.URI, err)
+	}
+}
+
+// threadStatusFilterResults checks the given status against the thread filters of the requesting account, returning whether it
+// should be hidden, and the api representation of the results of any warn filters that it matched. The status being looked at
+// is always shown, so this is only used for its parents and replies.
+// threadStatusVisible returns true if the given status in a thread is visible to requestingAccount,
+// and isn't by an account that they've muted.
+func (p *processor) threadStatusVisible(ctx context.Context, status *gtsmodel.Status, requestingAccount *gtsmodel.Account) bool {
+	if v, err := p.filter.StatusVisible(ctx, status, requestingAccount); err != nil || !v {
+		return false
+	}
+
+	muted, err := p.filter.StatusMuted(ctx, status, requestingAccount)
+	if err != nil {
+		logrus.Debugf("threadStatusVisible: error checking mutes for status %s: %s", status.ID, err)
+		return false
+	}
+	return !muted
+}
+
+func (p *processor) threadStatusFilterResults(ctx context.Context, status *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, []apimodel.FilterResult) {
+	hide, results, err := p.filter.StatusFilterResults(ctx, status, requestingAccount, gtsmodel.FilterContextThread)
+	if err != nil {
+		logrus.Debugf("threadStatusFilterResults: error checking filters for status %s: %s", status.ID, err)
+		return false, nil
+	}
+	if hide || len(results) == 0 {
+		return hide, nil
+	}
+
+	apiResults, err := p.tc.FilterResultsToAPIFilterResults(ctx, results)
+	if err != nil {
+		logrus.Debugf("threadStatusFilterResults: error converting filter results for status %s: %s", status.ID, err)
+		return false, nil
+	}
+	return false, apiResults
+}
@@ -34,6 +34,10 @@ import (
 )
 
 func (p *processor) Create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode) {
+	if errWithCode := checkLimits(form); errWithCode != nil {
+		return nil, errWithCode
+	}
+
 	accountURIs := uris.GenerateURIsForAccount(account.Username)
 	thisStatusID, err := id.NewULID()
 	if err != nil {
@@ -60,11 +64,19 @@ func (p *processor) Create(ctx context.Context, account *gtsmodel.Account, appli
 	}
 
 	if err := p.ProcessReplyToID(ctx, form, account.ID, newStatus); err != nil {
-		return nil, gtserror.NewErrorInternalError(err)
+		return nil, gtserror.NewErrorBadRequest(err)
+	}
+
+	if err := p.ProcessQuoteID(ctx, form, account, newStatus); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err)
 	}
 
 	if err := p.ProcessMediaIDs(ctx, form, account.ID, newStatus); err != nil {
-		return nil, gtserror.NewErrorInternalError(err)
+		return nil, gtserror.NewErrorBadRequest(err)
+	}
+
+	if err := p.ProcessPoll(ctx, form, newStatus); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err)
 	}
 
 	if err := p.ProcessVisibility(ctx, form, account.Privacy, newStatus); err != nil {
@@ -20,10 +20,16 @@ package status_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 type StatusCreateTestSuite struct {
@@ -98,6 +104,196 @@ func (suite *StatusCreateTestSuite) TestProcessContentWarningWithHTMLEscapedQuot
 	suite.Equal("\"test\"", apiStatus.SpoilerText)
 }
 
+func (suite *StatusCreateTestSuite) TestProcessPoll() {
+	ctx := context.Background()
+
+	creatingAccount := suite.testAccounts["local_account_1"]
+	creatingApplication := suite.testApplications["application_1"]
+
+	statusCreateForm := &model.AdvancedStatusCreateForm{
+		StatusCreateRequest: model.StatusCreateRequest{
+			Status: "which is better?",
+			Poll: &model.PollRequest{
+				Options:    []string{"cats", "dogs"},
+				ExpiresIn:  3600,
+				Multiple:   true,
+				HideTotals: true,
+			},
+			Visibility: model.VisibilityPublic,
+			Language:   "en",
+			Format:     model.StatusFormatPlain,
+		},
+	}
+
+	apiStatus, errWithCode := suite.status.Create(ctx, creatingAccount, creatingApplication, statusCreateForm)
+	suite.NoError(errWithCode)
+	suite.NotNil(apiStatus)
+
+	apiPoll := apiStatus.Poll
+	suite.NotNil(apiPoll)
+	suite.True(apiPoll.Multiple)
+	suite.False(apiPoll.Expired)
+	suite.NotEmpty(apiPoll.ExpiresAt)
+	suite.Len(apiPoll.Options, 2)
+	suite.Equal("cats", apiPoll.Options[0].Title)
+	suite.Equal("dogs", apiPoll.Options[1].Title)
+
+	// the poll should have been stored along with the status
+	dbStatus, err := suite.db.GetStatusByID(ctx, apiStatus.ID)
+	suite.NoError(err)
+	suite.Equal(ap.ActivityQuestion, dbStatus.ActivityStreamsType)
+	suite.Equal(apiPoll.ID, dbStatus.PollID)
+
+	dbPoll, err := suite.db.GetPollByID(ctx, dbStatus.PollID)
+	suite.NoError(err)
+	suite.Equal(dbStatus.ID, dbPoll.StatusID)
+	suite.Equal([]string{"cats", "dogs"}, dbPoll.Options)
+	suite.Equal([]int{0, 0}, dbPoll.Votes)
+	suite.True(dbPoll.HideCounts)
+	suite.WithinDuration(dbStatus.CreatedAt.Add(time.Hour), dbPoll.ExpiresAt, time.Second)
+}
+
+func (suite *StatusCreateTestSuite) TestProcessQuote() {
+	ctx := context.Background()
+
+	creatingAccount := suite.testAccounts["local_account_1"]
+	creatingApplication := suite.testApplications["application_1"]
+	quotedStatus := suite.testStatuses["local_account_2_status_1"]
+
+	statusCreateForm := &model.AdvancedStatusCreateForm{
+		StatusCreateRequest: model.StatusCreateRequest{
+			Status:     "look at this",
+			QuoteID:    quotedStatus.ID,
+			Visibility: model.VisibilityPublic,
+			Language:   "en",
+			Format:     model.StatusFormatPlain,
+		},
+	}
+
+	apiStatus, errWithCode := suite.status.Create(ctx, creatingAccount, creatingApplication, statusCreateForm)
+	suite.NoError(errWithCode)
+	suite.NotNil(apiStatus)
+
+	suite.NotNil(apiStatus.Quote)
+	suite.Equal(quotedStatus.ID, apiStatus.Quote.ID)
+	suite.Nil(apiStatus.Quote.Quote)
+
+	// the quote should have been stored along with the status
+	dbStatus, err := suite.db.GetStatusByID(ctx, apiStatus.ID)
+	suite.NoError(err)
+	suite.Equal(quotedStatus.ID, dbStatus.QuoteOfID)
+	suite.Equal(quotedStatus.URI, dbStatus.QuoteOfURI)
+}
+
+func (suite *StatusCreateTestSuite) TestProcessQuoteHiddenFromBlocked() {
+	ctx := context.Background()
+
+	creatingAccount := suite.testAccounts["local_account_1"]
+	creatingApplication := suite.testApplications["application_1"]
+	quotedStatus := suite.testStatuses["local_account_2_status_1"]
+	viewingAccount := suite.testAccounts["admin_account"]
+
+	statusCreateForm := &model.AdvancedStatusCreateForm{
+		StatusCreateRequest: model.StatusCreateRequest{
+			Status:     "look at this",
+			QuoteID:    quotedStatus.ID,
+			Visibility: model.VisibilityPublic,
+			Language:   "en",
+			Format:     model.StatusFormatPlain,
+		},
+	}
+
+	apiStatus, errWithCode := suite.status.Create(ctx, creatingAccount, creatingApplication, statusCreateForm)
+	suite.NoError(errWithCode)
+
+	// the author of the quoted status blocks the viewer
+	err := suite.db.Put(ctx, &gtsmodel.Block{
+		ID:              "01G2B6FJ3FQHWHMQMJ3D4Q7RGG",
+		URI:             "http://localhost:8080/users/1happyturtle/blocks/01G2B6FJ3FQHWHMQMJ3D4Q7RGG",
+		AccountID:       quotedStatus.AccountID,
+		TargetAccountID: viewingAccount.ID,
+	})
+	suite.NoError(err)
+
+	dbStatus, err := suite.db.GetStatusByID(ctx, apiStatus.ID)
+	suite.NoError(err)
+
+	// the quoting status is still shown to the viewer, but without the quote
+	viewed, err := suite.typeConverter.StatusToAPIStatus(ctx, dbStatus, viewingAccount)
+	suite.NoError(err)
+	suite.Nil(viewed.Quote)
+
+	// everyone else still gets the quote
+	viewed, err = suite.typeConverter.StatusToAPIStatus(ctx, dbStatus, creatingAccount)
+	suite.NoError(err)
+	suite.NotNil(viewed.Quote)
+}
+
+func (suite *StatusCreateTestSuite) TestProcessQuoteOfDirectStatus() {
+	ctx := context.Background()
+
+	creatingAccount := suite.testAccounts["local_account_1"]
+	creatingApplication := suite.testApplications["application_1"]
+	quotedStatus := suite.testStatuses["local_account_2_status_6"]
+
+	statusCreateForm := &model.AdvancedStatusCreateForm{
+		StatusCreateRequest: model.StatusCreateRequest{
+			Status:     "look at this",
+			QuoteID:    quotedStatus.ID,
+			Visibility: model.VisibilityPublic,
+			Language:   "en",
+			Format:     model.StatusFormatPlain,
+		},
+	}
+
+	apiStatus, errWithCode := suite.status.Create(ctx, creatingAccount, creatingApplication, statusCreateForm)
+	suite.Error(errWithCode)
+	suite.Nil(apiStatus)
+}
+
+func (suite *StatusCreateTestSuite) TestCreateOverLimits() {
+	ctx := context.Background()
+
+	creatingAccount := suite.testAccounts["local_account_1"]
+	creatingApplication := suite.testApplications["application_1"]
+
+	viper.Set(config.Keys.StatusesMaxChars, 30)
+	viper.Set(config.Keys.StatusesCWMaxChars, 5)
+	viper.Set(config.Keys.StatusesPollMaxOptions, 2)
+
+	newForm := func(status string, spoilerText string, poll *model.PollRequest) *model.AdvancedStatusCreateForm {
+		return &model.AdvancedStatusCreateForm{
+			StatusCreateRequest: model.StatusCreateRequest{
+				Status:      status,
+				Poll:        poll,
+				SpoilerText: spoilerText,
+				Visibility:  model.VisibilityPublic,
+				Format:      model.StatusFormatPlain,
+			},
+		}
+	}
+
+	// characters are counted rather than bytes, and the long link only counts as 23 of them
+	apiStatus, errWithCode := suite.status.Create(ctx, creatingAccount, creatingApplication, newForm("ünï https://example.org/a/really/long/link/to/something", "", nil))
+	suite.Nil(errWithCode)
+	suite.NotNil(apiStatus)
+
+	_, errWithCode = suite.status.Create(ctx, creatingAccount, creatingApplication, newForm("this status is going to be a bit too long", "", nil))
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	suite.Equal("bad request: status too long, 41 characters provided but limit is 30", errWithCode.Safe())
+
+	_, errWithCode = suite.status.Create(ctx, creatingAccount, creatingApplication, newForm("hi", "spoilers!", nil))
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	suite.Equal("bad request: content-warning/spoilertext too long, 9 characters provided but limit is 5", errWithCode.Safe())
+
+	_, errWithCode = suite.status.Create(ctx, creatingAccount, creatingApplication, newForm("vote", "", &model.PollRequest{
+		Options:   []string{"one", "two", "three"},
+		ExpiresIn: 3600,
+	}))
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+	suite.Equal("bad request: too many poll options provided, 3 provided but limit is 2", errWithCode.Safe())
+}
+
 func TestStatusCreateTestSuite(t *testing.T) {
 	suite.Run(t, new(StatusCreateTestSuite))
 }
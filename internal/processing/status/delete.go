@@ -27,6 +27,7 @@ import (
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 )
 
@@ -52,6 +53,18 @@ func (p *processor) Delete(ctx context.Context, requestingAccount *gtsmodel.Acco
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting status from the database: %s", err))
 	}
 
+	// leave a tombstone behind, so that remote instances asking for the status are told that it's gone
+	tombstoneID, err := id.NewULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	if err := p.db.Put(ctx, &gtsmodel.Tombstone{
+		ID:  tombstoneID,
+		URI: targetStatus.URI,
+	}); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting tombstone in the database: %s", err))
+	}
+
 	// send it back to the processor for async processing
 	p.clientWorker.Queue(messages.FromClientAPI{
 		APObjectType:   ap.ObjectNote,
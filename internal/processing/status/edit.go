@@ -0,0 +1,181 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+func (p *processor) Edit(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string, form *apimodel.StatusEditRequest) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, err := p.db.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+	if targetStatus.Account == nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no status owner for status %s", targetStatusID))
+	}
+
+	if targetStatus.AccountID != requestingAccount.ID {
+		return nil, gtserror.NewErrorForbidden(errors.New("status doesn't belong to requesting account"))
+	}
+
+	if targetStatus.BoostOfID != "" {
+		return nil, gtserror.NewErrorBadRequest(errors.New("boosts can't be edited"))
+	}
+
+	// keep the version of the status from before this edit
+	edit, err := p.tc.StatusToEdit(ctx, targetStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error creating status edit: %s", err))
+	}
+
+	// the new version of the status goes through the same processing as a new status,
+	// apart from the things that can't be changed once a status has been posted
+	createForm := &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      form.Status,
+			MediaIDs:    form.MediaIDs,
+			Sensitive:   form.Sensitive,
+			SpoilerText: form.SpoilerText,
+			Language:    form.Language,
+			Format:      form.Format,
+		},
+	}
+
+	if errWithCode := checkLimits(createForm); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	previousMentionIDs := targetStatus.MentionIDs
+
+	targetStatus.ContentWarning = text.SanitizeCaption(form.SpoilerText)
+	targetStatus.Sensitive = form.Sensitive
+	targetStatus.Text = form.Status
+	targetStatus.Attachments = nil
+	targetStatus.AttachmentIDs = nil
+
+	if err := p.ProcessMediaIDs(ctx, createForm, requestingAccount.ID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := p.ProcessLanguage(ctx, createForm, requestingAccount.Language, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.ProcessMentions(ctx, createForm, requestingAccount.ID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.ProcessTags(ctx, createForm, requestingAccount.ID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.ProcessEmojis(ctx, createForm, requestingAccount.ID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.ProcessContent(ctx, createForm, requestingAccount.ID, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	targetStatus.EditedAt = time.Now()
+	targetStatus.UpdatedAt = targetStatus.EditedAt
+
+	if err := p.db.Put(ctx, edit); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting status edit in the database: %s", err))
+	}
+
+	if err := p.db.UpdateStatus(ctx, targetStatus); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating status in the database: %s", err))
+	}
+
+	// the mentions were all parsed again from the new text, so the old ones aren't needed anymore
+	for _, m := range previousMentionIDs {
+		if err := p.db.DeleteByID(ctx, m, &gtsmodel.Mention{}); err != nil {
+			logrus.Errorf("Edit: error deleting previous mention %s: %s", m, err)
+		}
+	}
+
+	// send it back to the processor for async processing
+	p.clientWorker.Queue(messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityUpdate,
+		GTSModel:       targetStatus,
+		OriginAccount:  requestingAccount,
+	})
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return apiStatus, nil
+}
+
+func (p *processor) History(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) ([]*apimodel.StatusEdit, gtserror.WithCode) {
+	targetStatus, err := p.db.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+	if targetStatus.Account == nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no status owner for status %s", targetStatusID))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
+	}
+	if !visible {
+		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
+	}
+
+	edits, err := p.db.GetStatusEdits(ctx, targetStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching edits of status %s: %s", targetStatus.ID, err))
+	}
+
+	// the current version of the status always comes last
+	currentVersion, err := p.tc.StatusToEdit(ctx, targetStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error creating status edit: %s", err))
+	}
+	edits = append(edits, currentVersion)
+
+	apiEdits := make([]*apimodel.StatusEdit, 0, len(edits))
+	for _, e := range edits {
+		apiEdit, err := p.tc.StatusEditToAPIStatusEdit(ctx, e, targetStatus)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status edit to frontend representation: %s", err))
+		}
+		apiEdits = append(apiEdits, apiEdit)
+	}
+
+	return apiEdits, nil
+}
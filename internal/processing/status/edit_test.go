@@ -0,0 +1,90 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+)
+
+type StatusEditTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusEditTestSuite) TestEditAndHistory() {
+	ctx := context.Background()
+
+	editingAccount := suite.testAccounts["local_account_1"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+
+	form := &apimodel.StatusEditRequest{
+		Status:      "hello everyone! (edited)",
+		SpoilerText: "introduction post, edited",
+		Sensitive:   false,
+		Format:      apimodel.StatusFormatPlain,
+	}
+
+	apiStatus, errWithCode := suite.status.Edit(ctx, editingAccount, targetStatus.ID, form)
+	suite.NoError(errWithCode)
+	suite.Equal("<p>hello everyone! (edited)</p>", apiStatus.Content)
+	suite.Equal("introduction post, edited", apiStatus.SpoilerText)
+	suite.False(apiStatus.Sensitive)
+	suite.NotEmpty(apiStatus.EditedAt)
+
+	history, errWithCode := suite.status.History(ctx, editingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Len(history, 2)
+	suite.Equal("hello everyone!", history[0].Content)
+	suite.Equal("introduction post", history[0].SpoilerText)
+	suite.True(history[0].Sensitive)
+	suite.Equal("<p>hello everyone! (edited)</p>", history[1].Content)
+	suite.Equal("introduction post, edited", history[1].SpoilerText)
+	suite.False(history[1].Sensitive)
+	suite.Equal(apiStatus.EditedAt, history[1].CreatedAt)
+}
+
+func (suite *StatusEditTestSuite) TestEditSomeoneElsesStatus() {
+	ctx := context.Background()
+
+	editingAccount := suite.testAccounts["local_account_2"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+
+	form := &apimodel.StatusEditRequest{
+		Status: "this isn't my status",
+		Format: apimodel.StatusFormatPlain,
+	}
+
+	_, errWithCode := suite.status.Edit(ctx, editingAccount, targetStatus.ID, form)
+	if suite.Error(errWithCode) {
+		suite.Equal(http.StatusForbidden, errWithCode.Code())
+	}
+
+	history, errWithCode := suite.status.History(ctx, editingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Len(history, 1)
+	suite.Equal("hello everyone!", history[0].Content)
+}
+
+func TestStatusEditTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusEditTestSuite))
+}
@@ -0,0 +1,69 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+// checkLimits checks the given form against the configured limits on the length of statuses and content
+// warnings, and on the number of media attachments and poll options. These limits are advertised in the
+// configuration of the instance, so that clients can show the same counts that are checked here.
+func checkLimits(form *apimodel.AdvancedStatusCreateForm) gtserror.WithCode {
+	keys := config.Keys
+
+	if length, maxChars := text.CountCharacters(form.Status), viper.GetInt(keys.StatusesMaxChars); length > maxChars {
+		err := fmt.Errorf("status too long, %d characters provided but limit is %d", length, maxChars)
+		return gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if length, maxCWChars := utf8.RuneCountInString(form.SpoilerText), viper.GetInt(keys.StatusesCWMaxChars); length > maxCWChars {
+		err := fmt.Errorf("content-warning/spoilertext too long, %d characters provided but limit is %d", length, maxCWChars)
+		return gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if maxMediaFiles := viper.GetInt(keys.StatusesMediaMaxFiles); len(form.MediaIDs) > maxMediaFiles {
+		err := fmt.Errorf("too many media files attached to status, %d attached but limit is %d", len(form.MediaIDs), maxMediaFiles)
+		return gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if form.Poll != nil {
+		if maxPollOptions := viper.GetInt(keys.StatusesPollMaxOptions); len(form.Poll.Options) > maxPollOptions {
+			err := fmt.Errorf("too many poll options provided, %d provided but limit is %d", len(form.Poll.Options), maxPollOptions)
+			return gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		maxPollChars := viper.GetInt(keys.StatusesPollOptionMaxChars)
+		for _, option := range form.Poll.Options {
+			if length := utf8.RuneCountInString(option); length > maxPollChars {
+				err := fmt.Errorf("poll option too long, %d characters provided but limit is %d", length, maxPollChars)
+				return gtserror.NewErrorBadRequest(err, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
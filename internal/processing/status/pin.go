@@ -0,0 +1,130 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// maxPinnedStatuses is the most statuses that an account can have pinned to its profile at once.
+const maxPinnedStatuses = 5
+
+func (p *processor) Pin(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getStatusToPin(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if !targetStatus.Pinned {
+		if targetStatus.BoostOfID != "" {
+			err := errors.New("boosts can't be pinned")
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+
+		if targetStatus.Visibility == gtsmodel.VisibilityDirect {
+			err := errors.New("direct statuses can't be pinned")
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+
+		pinned, err := p.db.GetAccountStatuses(ctx, requestingAccount.ID, 0, false, false, "", "", true, false, false)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting pinned statuses: %s", err))
+		}
+		if len(pinned) >= maxPinnedStatuses {
+			err := fmt.Errorf("you can't pin more than %d statuses", maxPinnedStatuses)
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+
+		targetStatus.Pinned = true
+		if err := p.db.UpdateStatus(ctx, targetStatus); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error pinning status %s: %s", targetStatus.ID, err))
+		}
+
+		// send it back to the processor for async federation
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ObjectNote,
+			APActivityType: ap.ActivityAdd,
+			GTSModel:       targetStatus,
+			OriginAccount:  requestingAccount,
+		})
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return apiStatus, nil
+}
+
+func (p *processor) Unpin(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getStatusToPin(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if targetStatus.Pinned {
+		targetStatus.Pinned = false
+		if err := p.db.UpdateStatus(ctx, targetStatus); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error unpinning status %s: %s", targetStatus.ID, err))
+		}
+
+		// send it back to the processor for async federation
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ObjectNote,
+			APActivityType: ap.ActivityRemove,
+			GTSModel:       targetStatus,
+			OriginAccount:  requestingAccount,
+		})
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return apiStatus, nil
+}
+
+// getStatusToPin gets the status with the given ID, making sure that it belongs to the requesting account, who is pinning or unpinning it.
+func (p *processor) getStatusToPin(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*gtsmodel.Status, gtserror.WithCode) {
+	targetStatus, err := p.db.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil, gtserror.NewErrorNotFound(fmt.Errorf("status %s not found in the db: %s", targetStatusID, err))
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+
+	if targetStatus.AccountID != requestingAccount.ID {
+		err := errors.New("you can only pin or unpin your own statuses")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	return targetStatus, nil
+}
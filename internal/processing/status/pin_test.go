@@ -0,0 +1,105 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusPinTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusPinTestSuite) TestPinAndUnpin() {
+	ctx := context.Background()
+	requestingAccount := suite.testAccounts["local_account_1"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+
+	apiStatus, errWithCode := suite.status.Pin(ctx, requestingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.True(apiStatus.Pinned)
+
+	pinned, err := suite.db.GetAccountStatuses(ctx, requestingAccount.ID, 0, false, false, "", "", true, false, false)
+	suite.NoError(err)
+	suite.Len(pinned, 1)
+	suite.Equal(targetStatus.ID, pinned[0].ID)
+
+	// pinning again is fine
+	apiStatus, errWithCode = suite.status.Pin(ctx, requestingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.True(apiStatus.Pinned)
+
+	apiStatus, errWithCode = suite.status.Unpin(ctx, requestingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.False(apiStatus.Pinned)
+
+	dbStatus, err := suite.db.GetStatusByID(ctx, targetStatus.ID)
+	suite.NoError(err)
+	suite.False(dbStatus.Pinned)
+}
+
+func (suite *StatusPinTestSuite) TestPinSomeoneElsesStatus() {
+	apiStatus, errWithCode := suite.status.Pin(context.Background(), suite.testAccounts["local_account_1"], suite.testStatuses["admin_account_status_1"].ID)
+	suite.Nil(apiStatus)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *StatusPinTestSuite) TestPinDirectStatus() {
+	apiStatus, errWithCode := suite.status.Pin(context.Background(), suite.testAccounts["local_account_2"], suite.testStatuses["local_account_2_status_6"].ID)
+	suite.Nil(apiStatus)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func (suite *StatusPinTestSuite) TestPinTooMany() {
+	ctx := context.Background()
+	requestingAccount := suite.testAccounts["local_account_1"]
+
+	for _, k := range []string{"local_account_1_status_1", "local_account_1_status_2", "local_account_1_status_3", "local_account_1_status_4", "local_account_1_status_5"} {
+		_, errWithCode := suite.status.Pin(ctx, requestingAccount, suite.testStatuses[k].ID)
+		suite.NoError(errWithCode)
+	}
+
+	extraStatus := &gtsmodel.Status{
+		ID:                  "01G2B3KXR6SA3ZDT8ZFDJ4KBT8",
+		URI:                 "http://localhost:8080/users/the_mighty_zork/statuses/01G2B3KXR6SA3ZDT8ZFDJ4KBT8",
+		URL:                 "http://localhost:8080/@the_mighty_zork/statuses/01G2B3KXR6SA3ZDT8ZFDJ4KBT8",
+		Content:             "one pin too many",
+		Local:               true,
+		AccountID:           requestingAccount.ID,
+		AccountURI:          requestingAccount.URI,
+		Visibility:          gtsmodel.VisibilityPublic,
+		Federated:           true,
+		ActivityStreamsType: ap.ObjectNote,
+	}
+	suite.NoError(suite.db.PutStatus(ctx, extraStatus))
+
+	apiStatus, errWithCode := suite.status.Pin(ctx, requestingAccount, extraStatus.ID)
+	suite.Nil(apiStatus)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+}
+
+func TestStatusPinTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusPinTestSuite))
+}
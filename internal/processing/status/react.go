@@ -0,0 +1,221 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+)
+
+func (p *processor) React(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode) {
+	if !viper.GetBool(config.Keys.StatusesReactionsEnabled) {
+		err := errors.New("reactions are not enabled on this instance")
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	targetStatus, errWithCode := p.getReactableStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+	if !targetStatus.Likeable {
+		return nil, gtserror.NewErrorForbidden(errors.New("status is not reactable"))
+	}
+
+	if err := validate.EmojiReaction(emoji); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// custom emojis have to be ones that this instance knows about
+	var customEmoji *gtsmodel.Emoji
+	if strings.HasPrefix(emoji, ":") {
+		customEmoji = &gtsmodel.Emoji{}
+		if err := p.db.GetWhere(ctx, []db.Where{{Key: "shortcode", Value: strings.Trim(emoji, ":")}, {Key: "domain", Value: ""}, {Key: "disabled", Value: false}}, customEmoji); err != nil {
+			if err != db.ErrNoEntries {
+				return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching custom emoji from database: %s", err))
+			}
+			err := fmt.Errorf("custom emoji %s not found", emoji)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	// first check if the status has already been reacted to with this emoji, if so we don't need to do anything
+	existing := &gtsmodel.StatusReaction{}
+	err := p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: targetStatus.ID}, {Key: "account_id", Value: requestingAccount.ID}, {Key: "emoji", Value: emoji}}, existing)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching existing reaction from database: %s", err))
+	}
+
+	if err == db.ErrNoEntries {
+		thisReactionID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		// reactions are federated as likes, so they get a like uri
+		reaction := &gtsmodel.StatusReaction{
+			ID:              thisReactionID,
+			AccountID:       requestingAccount.ID,
+			Account:         requestingAccount,
+			TargetAccountID: targetStatus.AccountID,
+			TargetAccount:   targetStatus.Account,
+			StatusID:        targetStatus.ID,
+			Status:          targetStatus,
+			Emoji:           emoji,
+			URI:             uris.GenerateURIForLike(requestingAccount.Username, thisReactionID),
+		}
+		if customEmoji != nil {
+			reaction.CustomEmojiID = customEmoji.ID
+			reaction.CustomEmoji = customEmoji
+		}
+
+		if err := p.db.PutStatusReaction(ctx, reaction); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting reaction in database: %s", err))
+		}
+
+		// send it back to the processor for async processing
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ActivityEmojiReact,
+			APActivityType: ap.ActivityCreate,
+			GTSModel:       reaction,
+			OriginAccount:  requestingAccount,
+			TargetAccount:  targetStatus.Account,
+		})
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return apiStatus, nil
+}
+
+func (p *processor) Unreact(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getReactableStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// check if we actually have a reaction with this emoji for this status
+	reaction := &gtsmodel.StatusReaction{}
+	err := p.db.GetWhere(ctx, []db.Where{{Key: "status_id", Value: targetStatus.ID}, {Key: "account_id", Value: requestingAccount.ID}, {Key: "emoji", Value: emoji}}, reaction)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching existing reaction from database: %s", err))
+	}
+
+	if err == nil {
+		// we had a reaction, so take some action to get rid of it
+		if err := p.db.DeleteStatusReactions(ctx, []db.Where{{Key: "id", Value: reaction.ID}}); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error removing reaction: %s", err))
+		}
+
+		// send it back to the processor for async processing
+		p.clientWorker.Queue(messages.FromClientAPI{
+			APObjectType:   ap.ActivityEmojiReact,
+			APActivityType: ap.ActivityUndo,
+			GTSModel:       reaction,
+			OriginAccount:  requestingAccount,
+			TargetAccount:  targetStatus.Account,
+		})
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting status %s to frontend representation: %s", targetStatus.ID, err))
+	}
+
+	return apiStatus, nil
+}
+
+func (p *processor) Reactions(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) ([]apimodel.EmojiReaction, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getReactableStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	reactions, err := p.db.GetStatusReactions(ctx, targetStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching reactions from database: %s", err))
+	}
+
+	// filter out reactions from accounts that are blocking or blocked by the requester
+	reactingAccountIDs := make([]string, 0, len(reactions))
+	for _, r := range reactions {
+		reactingAccountIDs = append(reactingAccountIDs, r.AccountID)
+	}
+
+	blockedAccountIDs, err := p.db.GetBlockedAccountIDs(ctx, requestingAccount.ID, reactingAccountIDs)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error checking blocks: %s", err))
+	}
+
+	blocked := make(map[string]bool, len(blockedAccountIDs))
+	for _, blockedAccountID := range blockedAccountIDs {
+		blocked[blockedAccountID] = true
+	}
+
+	filtered := make([]*gtsmodel.StatusReaction, 0, len(reactions))
+	for _, r := range reactions {
+		if !blocked[r.AccountID] {
+			filtered = append(filtered, r)
+		}
+	}
+
+	apiReactions, err := p.tc.StatusReactionsToAPIEmojiReactions(ctx, filtered, requestingAccount, true)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting reactions to frontend representation: %s", err))
+	}
+
+	return apiReactions, nil
+}
+
+// getReactableStatus fetches the status with the given id, making sure that it's visible to the requesting account.
+func (p *processor) getReactableStatus(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*gtsmodel.Status, gtserror.WithCode) {
+	targetStatus, err := p.db.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+	if targetStatus.Account == nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no status owner for status %s", targetStatusID))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
+	}
+	if !visible {
+		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
+	}
+
+	return targetStatus, nil
+}
@@ -0,0 +1,133 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusReactTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusReactTestSuite) TestReactAndUnreact() {
+	ctx := context.Background()
+
+	reactingAccount := suite.testAccounts["local_account_1"]
+	otherAccount := suite.testAccounts["local_account_2"]
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	apiStatus, errWithCode := suite.status.React(ctx, reactingAccount, targetStatus.ID, "👍")
+	suite.NoError(errWithCode)
+	suite.NotNil(apiStatus.Pleroma)
+	suite.Len(apiStatus.Pleroma.EmojiReactions, 1)
+	suite.Equal("👍", apiStatus.Pleroma.EmojiReactions[0].Name)
+	suite.Equal(1, apiStatus.Pleroma.EmojiReactions[0].Count)
+	suite.True(apiStatus.Pleroma.EmojiReactions[0].Me)
+	suite.Empty(apiStatus.Pleroma.EmojiReactions[0].Accounts)
+
+	// reacting with the same emoji again shouldn't change anything
+	_, errWithCode = suite.status.React(ctx, reactingAccount, targetStatus.ID, "👍")
+	suite.NoError(errWithCode)
+
+	// reacting with a custom emoji creates a separate reaction
+	_, errWithCode = suite.status.React(ctx, otherAccount, targetStatus.ID, ":rainbow:")
+	suite.NoError(errWithCode)
+
+	apiReactions, errWithCode := suite.status.Reactions(ctx, otherAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Len(apiReactions, 2)
+	suite.Equal("👍", apiReactions[0].Name)
+	suite.Equal(1, apiReactions[0].Count)
+	suite.False(apiReactions[0].Me)
+	suite.Len(apiReactions[0].Accounts, 1)
+	suite.Equal(reactingAccount.ID, apiReactions[0].Accounts[0].ID)
+	suite.Equal(":rainbow:", apiReactions[1].Name)
+	suite.True(apiReactions[1].Me)
+	suite.Equal("http://localhost:8080/fileserver/01F8MH261H1KSV3GW3016GZRY3/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png", apiReactions[1].URL)
+
+	apiStatus, errWithCode = suite.status.Unreact(ctx, reactingAccount, targetStatus.ID, "👍")
+	suite.NoError(errWithCode)
+	suite.NotNil(apiStatus.Pleroma)
+	suite.Len(apiStatus.Pleroma.EmojiReactions, 1)
+	suite.Equal(":rainbow:", apiStatus.Pleroma.EmojiReactions[0].Name)
+	suite.False(apiStatus.Pleroma.EmojiReactions[0].Me)
+}
+
+func (suite *StatusReactTestSuite) TestReactWithInvalidEmoji() {
+	ctx := context.Background()
+
+	reactingAccount := suite.testAccounts["local_account_1"]
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	for _, emoji := range []string{"lol", ":not_an_emoji_here:"} {
+		_, errWithCode := suite.status.React(ctx, reactingAccount, targetStatus.ID, emoji)
+		if suite.Error(errWithCode) {
+			suite.Equal(http.StatusBadRequest, errWithCode.Code())
+		}
+	}
+}
+
+func (suite *StatusReactTestSuite) TestReactDisabled() {
+	ctx := context.Background()
+	viper.Set(config.Keys.StatusesReactionsEnabled, false)
+
+	_, errWithCode := suite.status.React(ctx, suite.testAccounts["local_account_1"], suite.testStatuses["admin_account_status_1"].ID, "👍")
+	if suite.Error(errWithCode) {
+		suite.Equal(http.StatusForbidden, errWithCode.Code())
+	}
+}
+
+func (suite *StatusReactTestSuite) TestReactionsHideBlockedAccounts() {
+	ctx := context.Background()
+
+	// local_account_2 blocks remote_account_1
+	remoteAccount := suite.testAccounts["remote_account_1"]
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	suite.NoError(suite.db.PutStatusReaction(ctx, &gtsmodel.StatusReaction{
+		ID:              "01G3KM1V6Y4FZ2X9QPJH5T8R0B",
+		URI:             "http://fossbros-anonymous.io/users/foss_satan/likes/01G3KM1V6Y4FZ2X9QPJH5T8R0B",
+		AccountID:       remoteAccount.ID,
+		TargetAccountID: targetStatus.AccountID,
+		StatusID:        targetStatus.ID,
+		Emoji:           "🎉",
+	}))
+
+	apiReactions, errWithCode := suite.status.Reactions(ctx, suite.testAccounts["local_account_1"], targetStatus.ID)
+	suite.NoError(errWithCode)
+	if suite.Len(apiReactions, 1) {
+		suite.Equal("🎉", apiReactions[0].Name)
+	}
+
+	apiReactions, errWithCode = suite.status.Reactions(ctx, suite.testAccounts["local_account_2"], targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Empty(apiReactions)
+}
+
+func TestStatusReactTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusReactTestSuite))
+}
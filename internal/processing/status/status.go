@@ -21,12 +21,15 @@ package status
 import (
 	"context"
 
+	"github.com/ReneKroon/ttlcache"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/visibility"
 	"github.com/superseriousbusiness/gotosocial/internal/worker"
@@ -38,6 +41,10 @@ type Processor interface {
 	Create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode)
 	// Delete processes the delete of a given status, returning the deleted status if the delete goes through.
 	Delete(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// Edit processes the editing of a given status by its owner, keeping the previous version, and returning the edited status.
+	Edit(ctx context.Context, account *gtsmodel.Account, targetStatusID string, form *apimodel.StatusEditRequest) (*apimodel.Status, gtserror.WithCode)
+	// History returns all versions of the given status, oldest first, ending with the current version.
+	History(ctx context.Context, account *gtsmodel.Account, targetStatusID string) ([]*apimodel.StatusEdit, gtserror.WithCode)
 	// Fave processes the faving of a given status, returning the updated status if the fave goes through.
 	Fave(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// Boost processes the boost/reblog of a given status, returning the newly-created boost if all is well.
@@ -48,12 +55,24 @@ type Processor interface {
 	BoostedBy(ctx context.Context, account *gtsmodel.Account, targetStatusID string) ([]*apimodel.Account, gtserror.WithCode)
 	// FavedBy returns a slice of accounts that have liked the given status, filtered according to privacy settings.
 	FavedBy(ctx context.Context, account *gtsmodel.Account, targetStatusID string) ([]*apimodel.Account, gtserror.WithCode)
+	// Pin pins the given status to the profile of its owner, returning the updated status if the pin goes through.
+	Pin(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// Unpin unpins the given status from the profile of its owner, returning the updated status if the unpin goes through.
+	Unpin(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// Get gets the given status, taking account of privacy settings and blocks etc.
 	Get(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
 	// Unfave processes the unfaving of a given status, returning the updated status if the fave goes through.
 	Unfave(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode)
+	// React processes an emoji reaction to a given status, returning the updated status if the reaction goes through.
+	React(ctx context.Context, account *gtsmodel.Account, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode)
+	// Unreact processes the removal of an emoji reaction from a given status, returning the updated status if the removal goes through.
+	Unreact(ctx context.Context, account *gtsmodel.Account, targetStatusID string, emoji string) (*apimodel.Status, gtserror.WithCode)
+	// Reactions returns the emoji reactions to the given status grouped by emoji, filtered according to privacy settings.
+	Reactions(ctx context.Context, account *gtsmodel.Account, targetStatusID string) ([]apimodel.EmojiReaction, gtserror.WithCode)
 	// Context returns the context (previous and following posts) from the given status ID
 	Context(ctx context.Context, account *gtsmodel.Account, targetStatusID string) (*apimodel.Context, gtserror.WithCode)
+	// Translate translates the given status into the target language with the configured translation backend.
+	Translate(ctx context.Context, account *gtsmodel.Account, targetStatusID string, targetLanguage string) (*apimodel.Translation, gtserror.WithCode)
 
 	/*
 		PROCESSING UTILS
@@ -61,7 +80,9 @@ type Processor interface {
 
 	ProcessVisibility(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountDefaultVis gtsmodel.Visibility, status *gtsmodel.Status) error
 	ProcessReplyToID(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error
+	ProcessQuoteID(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, account *gtsmodel.Account, status *gtsmodel.Status) error
 	ProcessMediaIDs(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error
+	ProcessPoll(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, status *gtsmodel.Status) error
 	ProcessLanguage(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountDefaultLanguage string, status *gtsmodel.Status) error
 	ProcessMentions(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error
 	ProcessTags(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error
@@ -75,17 +96,23 @@ type processor struct {
 	filter       visibility.Filter
 	formatter    text.Formatter
 	clientWorker *worker.Worker[messages.FromClientAPI]
+	federator    federation.Federator
 	parseMention gtsmodel.ParseMentionFunc
+	translator   translate.Translator // nil if translation is disabled
+	translations *ttlcache.Cache
 }
 
 // New returns a new status processor.
-func New(db db.DB, tc typeutils.TypeConverter, clientWorker *worker.Worker[messages.FromClientAPI], parseMention gtsmodel.ParseMentionFunc) Processor {
+func New(db db.DB, tc typeutils.TypeConverter, clientWorker *worker.Worker[messages.FromClientAPI], federator federation.Federator, parseMention gtsmodel.ParseMentionFunc, translator translate.Translator) Processor {
 	return &processor{
 		tc:           tc,
 		db:           db,
 		filter:       visibility.NewFilter(db),
 		formatter:    text.NewFormatter(db),
 		clientWorker: clientWorker,
+		federator:    federator,
 		parseMention: parseMention,
+		translator:   translator,
+		translations: newTranslationCache(),
 	}
 }
@@ -0,0 +1,157 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+)
+
+// translationCacheTTL is how long translations of statuses are kept around for, so that
+// the translation backend isn't asked to translate the same status over and over again.
+const translationCacheTTL = 24 * time.Hour
+
+// newTranslationCache returns a cache of translations, keyed by status, edit, and target language.
+func newTranslationCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(translationCacheTTL)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
+func (p *processor) Translate(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string, targetLanguage string) (*apimodel.Translation, gtserror.WithCode) {
+	if p.translator == nil {
+		return nil, gtserror.NewErrorServiceUnavailable(errors.New("translation is not enabled on this instance"), "translation is not enabled on this instance")
+	}
+
+	targetStatus, err := p.db.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error fetching status %s: %s", targetStatusID, err))
+	}
+	if targetStatus.Account == nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no status owner for status %s", targetStatusID))
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, targetStatus, requestingAccount)
+	if err != nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("error seeing if status %s is visible: %s", targetStatus.ID, err))
+	}
+	if !visible {
+		return nil, gtserror.NewErrorNotFound(errors.New("status is not visible"))
+	}
+
+	// don't send private statuses off to a translation service
+	if targetStatus.Visibility != gtsmodel.VisibilityPublic && targetStatus.Visibility != gtsmodel.VisibilityUnlocked {
+		return nil, gtserror.NewErrorForbidden(fmt.Errorf("status %s has visibility %s", targetStatus.ID, targetStatus.Visibility), "only public and unlisted statuses can be translated")
+	}
+
+	if targetStatus.Language != "" && targetStatus.Language == targetLanguage {
+		return nil, gtserror.NewErrorBadRequest(fmt.Errorf("status %s is already in %s", targetStatus.ID, targetLanguage), "status is already in the requested language")
+	}
+
+	cacheKey := targetStatus.ID + "/" + strconv.FormatInt(targetStatus.EditedAt.UnixNano(), 10) + "/" + targetLanguage
+	if cached, ok := p.translations.Get(cacheKey); ok {
+		if translation, ok := cached.(*apimodel.Translation); ok {
+			return translation, nil
+		}
+	}
+
+	// the content of the status is html, and everything else is plain text, so they're translated separately;
+	// gather up all the plain text that needs translating, so that it can be done in one request
+	texts := []string{targetStatus.ContentWarning}
+
+	var poll *gtsmodel.Poll
+	if targetStatus.PollID != "" {
+		poll, err = p.db.GetPollByID(ctx, targetStatus.PollID)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error fetching poll of status %s: %s", targetStatus.ID, err))
+		}
+		texts = append(texts, poll.Options...)
+	}
+
+	for _, attachment := range targetStatus.Attachments {
+		texts = append(texts, attachment.Description)
+	}
+
+	content, errWithCode := p.translate(ctx, targetStatus, []string{targetStatus.Content}, translate.FormatHTML, targetStatus.Language, targetLanguage)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// translate the plain text from whatever language the content was detected as being in, so that it's consistent
+	result, errWithCode := p.translate(ctx, targetStatus, texts, translate.FormatText, content.DetectedSourceLanguage, targetLanguage)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// translation backends aren't trusted to give back safe html, so everything
+	// is sanitized just like it is when a status is created
+	translated := result.Texts
+	translation := &apimodel.Translation{
+		Content:                text.SanitizeHTML(content.Texts[0]),
+		SpoilerText:            text.SanitizeCaption(translated[0]),
+		MediaAttachments:       []apimodel.TranslationAttachment{},
+		DetectedSourceLanguage: content.DetectedSourceLanguage,
+		Provider:               p.translator.Provider(),
+	}
+	translated = translated[1:]
+
+	if poll != nil {
+		translation.Poll = &apimodel.TranslationPoll{
+			ID:      poll.ID,
+			Options: make([]apimodel.TranslationPollOption, 0, len(poll.Options)),
+		}
+		for _, option := range translated[:len(poll.Options)] {
+			translation.Poll.Options = append(translation.Poll.Options, apimodel.TranslationPollOption{Title: text.SanitizeCaption(option)})
+		}
+		translated = translated[len(poll.Options):]
+	}
+
+	for i, attachment := range targetStatus.Attachments {
+		translation.MediaAttachments = append(translation.MediaAttachments, apimodel.TranslationAttachment{
+			ID:          attachment.ID,
+			Description: text.SanitizeCaption(translated[i]),
+		})
+	}
+
+	p.translations.Set(cacheKey, translation)
+	return translation, nil
+}
+
+// translate translates the given texts of the given status with the translation backend.
+func (p *processor) translate(ctx context.Context, targetStatus *gtsmodel.Status, texts []string, format translate.Format, sourceLanguage string, targetLanguage string) (*translate.Translation, gtserror.WithCode) {
+	result, err := p.translator.Translate(ctx, texts, format, sourceLanguage, targetLanguage)
+	if err != nil {
+		if errors.Is(err, translate.ErrUnsupportedLanguage) {
+			return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+		}
+		return nil, gtserror.NewErrorServiceUnavailable(fmt.Errorf("error translating status %s: %s", targetStatus.ID, err), "the translation service could not translate this status")
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/status"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+)
+
+type StatusTranslateTestSuite struct {
+	StatusStandardTestSuite
+}
+
+// maliciousTranslator is a translation backend that slips markup into everything it translates.
+type maliciousTranslator struct {
+	formats []translate.Format
+}
+
+func (t *maliciousTranslator) Provider() string {
+	return "Malicious Translator"
+}
+
+func (t *maliciousTranslator) Translate(ctx context.Context, texts []string, format translate.Format, sourceLanguage string, targetLanguage string) (*translate.Translation, error) {
+	t.formats = append(t.formats, format)
+
+	translated := make([]string, 0, len(texts))
+	for range texts {
+		translated = append(translated, `<p>hallo</p><script>alert("pwned")</script><img src="https://evil.example.org/tracker.png" onerror="alert(1)">`)
+	}
+	return &translate.Translation{Texts: translated, DetectedSourceLanguage: "en"}, nil
+}
+
+func (suite *StatusTranslateTestSuite) TestTranslateSanitized() {
+	translator := &maliciousTranslator{}
+	processor := status.New(suite.db, suite.typeConverter, suite.clientWorker, suite.federator, processing.GetParseMentionFunc(suite.db, suite.federator), translator)
+
+	translation, errWithCode := processor.Translate(context.Background(), suite.testAccounts["local_account_1"], suite.testStatuses["admin_account_status_1"].ID, "de")
+	suite.NoError(errWithCode)
+
+	// the content is translated as html, and everything else as plain text
+	suite.Equal([]translate.Format{translate.FormatHTML, translate.FormatText}, translator.formats)
+
+	suite.Contains(translation.Content, "<p>hallo</p>")
+	suite.NotContains(translation.Content, "script")
+	suite.NotContains(translation.Content, "onerror")
+	suite.NotContains(translation.SpoilerText, "<")
+	if suite.Len(translation.MediaAttachments, 1) {
+		suite.NotContains(translation.MediaAttachments[0].Description, "<")
+	}
+}
+
+func TestStatusTranslateTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusTranslateTestSuite))
+}
@@ -22,11 +22,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
@@ -150,6 +154,40 @@ func (p *processor) ProcessReplyToID(ctx context.Context, form *apimodel.Advance
 	return nil
 }
 
+func (p *processor) ProcessQuoteID(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, account *gtsmodel.Account, status *gtsmodel.Status) error {
+	if form.QuoteID == "" {
+		return nil
+	}
+
+	quotedStatus, err := p.db.GetStatusByID(ctx, form.QuoteID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return fmt.Errorf("status with id %s not quotable because it doesn't exist", form.QuoteID)
+		}
+		return fmt.Errorf("status with id %s not quotable: %s", form.QuoteID, err)
+	}
+
+	// the quoting account has to be able to see the status in the first place
+	visible, err := p.filter.StatusVisible(ctx, quotedStatus, account)
+	if err != nil {
+		return fmt.Errorf("status with id %s not quotable: %s", form.QuoteID, err)
+	}
+	if !visible {
+		return fmt.Errorf("status with id %s not quotable", form.QuoteID)
+	}
+
+	// quoting a private status would show it to people it wasn't meant for
+	if quotedStatus.Visibility != gtsmodel.VisibilityPublic && quotedStatus.Visibility != gtsmodel.VisibilityUnlocked {
+		return fmt.Errorf("status with id %s not quotable because it isn't public or unlisted", form.QuoteID)
+	}
+
+	status.QuoteOfID = quotedStatus.ID
+	status.QuoteOfURI = quotedStatus.URI
+	status.QuoteOf = quotedStatus
+
+	return nil
+}
+
 func (p *processor) ProcessMediaIDs(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) error {
 	if form.MediaIDs == nil {
 		return nil
@@ -167,8 +205,8 @@ func (p *processor) ProcessMediaIDs(ctx context.Context, form *apimodel.Advanced
 		if a.AccountID != thisAccountID {
 			return fmt.Errorf("media with id %s does not belong to account %s", mediaID, thisAccountID)
 		}
-		// check they're not already used in a status
-		if a.StatusID != "" || a.ScheduledStatusID != "" {
+		// check they're not already used in a status, unless it's this one (which happens when a status is edited)
+		if (a.StatusID != "" && a.StatusID != status.ID) || a.ScheduledStatusID != "" {
 			return fmt.Errorf("media with id %s is already attached to a status", mediaID)
 		}
 		gtsMediaAttachments = append(gtsMediaAttachments, a)
@@ -179,6 +217,39 @@ func (p *processor) ProcessMediaIDs(ctx context.Context, form *apimodel.Advanced
 	return nil
 }
 
+func (p *processor) ProcessPoll(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, status *gtsmodel.Status) error {
+	if form.Poll == nil || len(form.Poll.Options) == 0 {
+		return nil
+	}
+
+	pollID, err := id.NewULID()
+	if err != nil {
+		return err
+	}
+
+	options := make([]string, 0, len(form.Poll.Options))
+	for _, option := range form.Poll.Options {
+		options = append(options, text.SanitizeCaption(option))
+	}
+
+	status.Poll = &gtsmodel.Poll{
+		ID:         pollID,
+		CreatedAt:  status.CreatedAt,
+		UpdatedAt:  status.UpdatedAt,
+		StatusID:   status.ID,
+		Options:    options,
+		Votes:      make([]int, len(options)),
+		Multiple:   form.Poll.Multiple,
+		HideCounts: form.Poll.HideTotals,
+		ExpiresAt:  status.CreatedAt.Add(time.Duration(form.Poll.ExpiresIn) * time.Second),
+	}
+	status.PollID = pollID
+
+	// statuses with polls are federated as questions rather than notes
+	status.ActivityStreamsType = ap.ActivityQuestion
+	return nil
+}
+
 func (p *processor) ProcessLanguage(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountDefaultLanguage string, status *gtsmodel.Status) error {
 	if form.Language != "" {
 		status.Language = form.Language
@@ -196,6 +267,8 @@ func (p *processor) ProcessMentions(ctx context.Context, form *apimodel.Advanced
 	mentions := []*gtsmodel.Mention{}
 	mentionIDs := []string{}
 
+	p.fingerMentionedAccounts(ctx, accountID, mentionedAccountNames)
+
 	for _, mentionedAccountName := range mentionedAccountNames {
 		gtsMention, err := p.parseMention(ctx, mentionedAccountName, accountID, status.ID)
 		if err != nil {
@@ -219,6 +292,38 @@ func (p *processor) ProcessMentions(ctx context.Context, form *apimodel.Advanced
 	return nil
 }
 
+// fingerMentionedAccounts does the webfinger lookups for all the remote accounts mentioned in a status that we don't know about yet
+// at the same time, so that parsing the mentions one after another doesn't have to wait for each lookup in turn. The results are
+// cached by the federator, which is where parseMention picks them up from.
+func (p *processor) fingerMentionedAccounts(ctx context.Context, accountID string, mentionedAccountNames []string) {
+	account, err := p.db.GetAccountByID(ctx, accountID)
+	if err != nil || account.Domain != "" {
+		// remote accounts can't be used to do lookups
+		return
+	}
+
+	unknown := []string{}
+	for _, mentionedAccountName := range mentionedAccountNames {
+		username, domain, remote := strings.Cut(strings.TrimPrefix(mentionedAccountName, "@"), "@")
+		if !remote {
+			continue
+		}
+
+		where := []db.Where{
+			{Key: "username", Value: username, CaseInsensitive: true},
+			{Key: "domain", Value: domain, CaseInsensitive: true},
+		}
+		if err := p.db.GetWhere(ctx, where, &gtsmodel.Account{}); err == db.ErrNoEntries {
+			unknown = append(unknown, username+"@"+domain)
+		}
+	}
+
+	// a single lookup may as well be done by parseMention
+	if len(unknown) > 1 {
+		p.federator.FingerRemoteAccounts(ctx, account.Username, unknown)
+	}
+}
+
 func (p *processor) ProcessTags(ctx context.Context, form *apimodel.AdvancedStatusCreateForm, accountID string, status *gtsmodel.Status) error {
 	tags := []string{}
 	gtsTags, err := p.db.TagStringsToTags(ctx, util.DeriveHashtagsFromText(form.Status), accountID)
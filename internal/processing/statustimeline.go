@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -140,22 +141,29 @@ func StatusSkipInsertFunction() timeline.SkipInsertFunction {
 	}
 }
 
-func (p *processor) packageStatusResponse(statuses []*apimodel.Status, path string, nextMaxID string, prevMinID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+// packageStatusResponse wraps the given statuses with a Link header for the next and previous pages of the timeline at path.
+// The links are built from nextMaxID and prevMinID rather than the statuses, so that they're still there when every status
+// of the page has been filtered out, and extraQuery, if set, is added to the query of both of them.
+func (p *processor) packageStatusResponse(statuses []*apimodel.Status, path string, nextMaxID string, prevMinID string, limit int, extraQuery string) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
 	resp := &apimodel.StatusTimelineResponse{
 		Statuses: []*apimodel.Status{},
 	}
 	resp.Statuses = statuses
 
 	// prepare the next and previous links
-	if len(statuses) != 0 {
+	if nextMaxID != "" && prevMinID != "" {
 		protocol := viper.GetString(config.Keys.Protocol)
 		host := viper.GetString(config.Keys.Host)
 
+		if extraQuery != "" {
+			extraQuery = "&" + extraQuery
+		}
+
 		nextLink := &url.URL{
 			Scheme:   protocol,
 			Host:     host,
 			Path:     path,
-			RawQuery: fmt.Sprintf("limit=%d&max_id=%s", limit, nextMaxID),
+			RawQuery: fmt.Sprintf("limit=%d&max_id=%s%s", limit, nextMaxID, extraQuery),
 		}
 		next := fmt.Sprintf("<%s>; rel=\"next\"", nextLink.String())
 
@@ -163,7 +171,7 @@ func (p *processor) packageStatusResponse(statuses []*apimodel.Status, path stri
 			Scheme:   protocol,
 			Host:     host,
 			Path:     path,
-			RawQuery: fmt.Sprintf("limit=%d&min_id=%s", limit, prevMinID),
+			RawQuery: fmt.Sprintf("limit=%d&min_id=%s%s", limit, prevMinID, extraQuery),
 		}
 		prev := fmt.Sprintf("<%s>; rel=\"prev\"", prevLink.String())
 		resp.LinkHeader = fmt.Sprintf("%s, %s", next, prev)
@@ -190,14 +198,69 @@ func (p *processor) HomeTimelineGet(ctx context.Context, authed *oauth.Auth, max
 		if !ok {
 			return nil, gtserror.NewErrorInternalError(errors.New("error converting prepared timeline entry to api status"))
 		}
+
+		hide, results, err := p.homeTimelineStatusFilterResults(ctx, status, authed.Account)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if hide {
+			continue
+		}
+
+		if len(results) != 0 {
+			// prepared statuses are shared with later requests, so annotate a copy
+			annotated := *status
+			annotated.Filtered = results
+			status = &annotated
+		}
+
 		statuses = append(statuses, status)
 	}
 
-	return p.packageStatusResponse(statuses, "api/v1/timelines/home", statuses[len(preparedItems)-1].ID, statuses[0].ID, limit)
+	// page from the prepared items rather than the statuses, so filtered statuses at the edges aren't served again
+	return p.packageStatusResponse(statuses, "api/v1/timelines/home", preparedItems[len(preparedItems)-1].GetID(), preparedItems[0].GetID(), limit, localQuery(local))
+}
+
+// localQuery returns the query to add to timeline Link headers so that they keep the local setting of the request.
+func localQuery(local bool) string {
+	if local {
+		return "local=true"
+	}
+	return ""
 }
 
-func (p *processor) PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
-	statuses, err := p.db.GetPublicTimeline(ctx, authed.Account.ID, maxID, sinceID, minID, limit, local)
+// homeTimelineStatusFilterResults checks the given prepared home timeline status against the filters of the timeline owner,
+// returning whether it should be hidden, and the results of any warn filters that it matched.
+func (p *processor) homeTimelineStatusFilterResults(ctx context.Context, apiStatus *apimodel.Status, timelineAccount *gtsmodel.Account) (bool, []apimodel.FilterResult, error) {
+	status, err := p.db.GetStatusByID(ctx, apiStatus.ID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// the status has been deleted since it was prepared, so drop it
+			return true, nil, nil
+		}
+		return false, nil, fmt.Errorf("homeTimelineStatusFilterResults: error getting status %s: %s", apiStatus.ID, err)
+	}
+
+	return p.statusFilterResults(ctx, status, timelineAccount, gtsmodel.FilterContextHome)
+}
+
+// statusFilterResults checks the given status against the filters that requestingAccount has set for the given context,
+// returning whether it should be hidden, and the api representation of the results of any warn filters that it matched.
+func (p *processor) statusFilterResults(ctx context.Context, status *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, []apimodel.FilterResult, error) {
+	hide, results, err := p.filter.StatusFilterResults(ctx, status, requestingAccount, filterContext)
+	if err != nil || hide || len(results) == 0 {
+		return hide, nil, err
+	}
+
+	apiResults, err := p.tc.FilterResultsToAPIFilterResults(ctx, results)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, apiResults, nil
+}
+
+func (p *processor) PublicTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, sinceID string, minID string, limit int, local bool, remote bool, onlyMedia bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, err := p.db.GetPublicTimeline(ctx, authed.Account.ID, maxID, sinceID, minID, limit, local, remote, onlyMedia)
 	if err != nil {
 		if err == db.ErrNoEntries {
 			// there are just no entries left
@@ -214,17 +277,36 @@ func (p *processor) PublicTimelineGet(ctx context.Context, authed *oauth.Auth, m
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	if len(s) == 0 {
+	if len(statuses) == 0 {
 		return &apimodel.StatusTimelineResponse{
 			Statuses: []*apimodel.Status{},
 		}, nil
 	}
 
-	return p.packageStatusResponse(s, "api/v1/timelines/public", s[len(s)-1].ID, s[0].ID, limit)
+	// page from the database statuses rather than the filtered ones, so filtered statuses at the edges aren't served again
+	// carry the filters over into the paging links, so the next page is the same timeline
+	extraQuery := url.Values{}
+	if local {
+		extraQuery.Set("local", "true")
+	}
+	if remote {
+		extraQuery.Set("remote", "true")
+	}
+	if onlyMedia {
+		extraQuery.Set("only_media", "true")
+	}
+
+	return p.packageStatusResponse(s, "api/v1/timelines/public", statuses[len(statuses)-1].ID, statuses[0].ID, limit, extraQuery.Encode())
 }
 
-func (p *processor) FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
-	statuses, nextMaxID, prevMinID, err := p.db.GetFavedTimeline(ctx, authed.Account.ID, maxID, minID, limit)
+func (p *processor) TagTimelineGet(ctx context.Context, authed *oauth.Auth, tagName string, anyTags []string, allTags []string, noneTags []string, maxID string, sinceID string, minID string, limit int, local bool) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	tagName = strings.ToLower(strings.TrimPrefix(tagName, "#"))
+	if tagName == "" {
+		err := errors.New("no hashtag given")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	statuses, err := p.db.GetTagTimeline(ctx, append([]string{tagName}, anyTags...), allTags, noneTags, maxID, sinceID, minID, limit, local)
 	if err != nil {
 		if err == db.ErrNoEntries {
 			// there are just no entries left
@@ -236,18 +318,50 @@ func (p *processor) FavedTimelineGet(ctx context.Context, authed *oauth.Auth, ma
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	s, err := p.filterFavedStatuses(ctx, authed, statuses)
+	s, err := p.filterPublicStatuses(ctx, authed, statuses)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	if len(s) == 0 {
+	if len(statuses) == 0 {
 		return &apimodel.StatusTimelineResponse{
 			Statuses: []*apimodel.Status{},
 		}, nil
 	}
 
-	return p.packageStatusResponse(s, "api/v1/favourites", nextMaxID, prevMinID, limit)
+	// carry the tag sets over into the paging links, so the next page is the same compound timeline
+	extraQuery := url.Values{}
+	if local {
+		extraQuery.Set("local", "true")
+	}
+	for key, tags := range map[string][]string{"any[]": anyTags, "all[]": allTags, "none[]": noneTags} {
+		for _, t := range tags {
+			extraQuery.Add(key, t)
+		}
+	}
+
+	return p.packageStatusResponse(s, "api/v1/timelines/tag/"+tagName, statuses[len(statuses)-1].ID, statuses[0].ID, limit, extraQuery.Encode())
+}
+
+func (p *processor) FavedTimelineGet(ctx context.Context, authed *oauth.Auth, maxID string, minID string, limit int) (*apimodel.StatusTimelineResponse, gtserror.WithCode) {
+	statuses, nextMaxID, prevMinID, err := p.db.GetFavedTimeline(ctx, authed.Account.ID, maxID, minID, limit)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			// there are just no entries left
+			return &apimodel.StatusTimelineResponse{
+				Statuses: []*apimodel.Status{},
+			}, nil
+		}
+		// there's an actual error
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	s, err := p.filterFavedStatuses(ctx, authed, statuses)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.packageStatusResponse(s, "api/v1/favourites", nextMaxID, prevMinID, limit, "")
 }
 
 func (p *processor) filterPublicStatuses(ctx context.Context, authed *oauth.Auth, statuses []*gtsmodel.Status) ([]*apimodel.Status, error) {
@@ -273,11 +387,21 @@ func (p *processor) filterPublicStatuses(ctx context.Context, authed *oauth.Auth
 			continue
 		}
 
+		hide, results, err := p.statusFilterResults(ctx, s, authed.Account, gtsmodel.FilterContextPublic)
+		if err != nil {
+			l.Debugf("filterPublicStatuses: skipping status %s because of an error checking filters: %s", s.ID, err)
+			continue
+		}
+		if hide {
+			continue
+		}
+
 		apiStatus, err := p.tc.StatusToAPIStatus(ctx, s, authed.Account)
 		if err != nil {
 			l.Debugf("filterPublicStatuses: skipping status %s because it couldn't be converted to its api representation: %s", s.ID, err)
 			continue
 		}
+		apiStatus.Filtered = results
 
 		apiStatuses = append(apiStatuses, apiStatus)
 	}
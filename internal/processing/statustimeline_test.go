@@ -0,0 +1,72 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type StatusTimelineTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *StatusTimelineTestSuite) TestTagTimelineSilenced() {
+	ctx := context.Background()
+	authed := suite.testAutheds["local_account_1"]
+
+	// a public status with a hashtag, from an account that local_account_1 doesn't follow
+	status := testrig.NewTestStatuses()["remote_account_1_status_1"]
+	status.ID = "01G3Z4KV0M8PZ8A4F1VBK3D5XJ"
+	status.URI = status.URI + "/tagged"
+	status.URL = status.URL + "/tagged"
+	status.Visibility = gtsmodel.VisibilityPublic
+	status.TagIDs = []string{suite.testTags["welcome"].ID}
+	suite.NoError(suite.db.PutStatus(ctx, status))
+
+	statusIDs := func() []string {
+		resp, errWithCode := suite.processor.TagTimelineGet(ctx, authed, "welcome", nil, nil, nil, "", "", "", 20, false)
+		suite.NoError(errWithCode)
+		ids := []string{}
+		for _, s := range resp.Statuses {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+	suite.Contains(statusIDs(), status.ID)
+
+	// once the author's domain is silenced, the status is kept out of the hashtag timeline like any other public timeline
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 "01G0SYJ9ZJ7Q4RCHX4SSHHZX2P",
+		Domain:             suite.testAccounts["remote_account_1"].Domain,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		Severity:           gtsmodel.DomainBlockSeveritySilence,
+	}))
+	ids := statusIDs()
+	suite.NotContains(ids, status.ID)
+	suite.Contains(ids, suite.testStatuses["admin_account_status_1"].ID)
+}
+
+func TestStatusTimelineTestSuite(t *testing.T) {
+	suite.Run(t, &StatusTimelineTestSuite{})
+}
@@ -33,5 +33,14 @@ func (p *processor) StreamNotificationToAccount(n *apimodel.Notification, accoun
 		return fmt.Errorf("error marshalling notification to json: %s", err)
 	}
 
-	return p.streamToAccount(string(bytes), stream.EventTypeNotification, []string{stream.TimelineNotifications, stream.TimelineHome}, account.ID)
+	return p.streamToAccount(string(bytes), stream.EventTypeNotification, [][]string{{stream.TimelineNotifications}, {stream.TimelineHome}}, account.ID)
+}
+
+func (p *processor) StreamConversationToAccount(c *apimodel.Conversation, account *gtsmodel.Account) error {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error marshalling conversation to json: %s", err)
+	}
+
+	return p.streamToAccount(string(bytes), stream.EventTypeConversation, [][]string{{stream.TimelineDirect}}, account.ID)
 }
@@ -30,7 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
 )
 
-func (p *processor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamTimeline string) (*stream.Stream, gtserror.WithCode) {
+func (p *processor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, streamTimeline string, tag string) (*stream.Stream, gtserror.WithCode) {
 	l := logrus.WithFields(logrus.Fields{
 		"func":       "OpenStreamForAccount",
 		"account":    account.ID,
@@ -46,11 +46,21 @@ func (p *processor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.
 
 	thisStream := &stream.Stream{
 		ID:        streamID,
-		Timeline:  streamTimeline,
+		Timelines: make(map[string][]string),
 		Messages:  make(chan *stream.Message, 100),
 		Hangup:    make(chan interface{}, 1),
 		Connected: true,
 	}
+
+	// a stream may be opened without a timeline, in which case the client will subscribe to timelines later
+	if streamTimeline != "" {
+		names, err := stream.Names(streamTimeline, tag)
+		if err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		thisStream.Subscribe(names)
+	}
+
 	go p.waitToCloseStream(account, thisStream)
 
 	v, ok := p.streamMap.Load(account.ID)
@@ -85,12 +95,15 @@ func (p *processor) OpenStreamForAccount(ctx context.Context, account *gtsmodel.
 func (p *processor) waitToCloseStream(account *gtsmodel.Account, thisStream *stream.Stream) {
 	<-thisStream.Hangup // wait for a hangup message
 
-	// lock the stream to prevent more messages being put in it while we work
+	// indicate the stream is no longer connected, so that no more messages are put in it;
+	// the stream is locked on its own, since streaming locks the streams for the account
+	// first and then each stream, and taking the locks the other way around could deadlock
 	thisStream.Lock()
-	defer thisStream.Unlock()
-
-	// indicate the stream is no longer connected
 	thisStream.Connected = false
+	thisStream.Unlock()
+
+	// finally close the messages channel so no more messages can be read from it
+	defer close(thisStream.Messages)
 
 	// load and parse the entry for this account from the stream map
 	v, ok := p.streamMap.Load(account.ID)
@@ -114,7 +127,4 @@ func (p *processor) waitToCloseStream(account *gtsmodel.Account, thisStream *str
 		}
 	}
 	streamsForAccount.Streams = modifiedStreams
-
-	// finally close the messages channel so no more messages can be read from it
-	close(thisStream.Messages)
 }
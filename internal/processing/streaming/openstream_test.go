@@ -32,7 +32,7 @@ type OpenStreamTestSuite struct {
 func (suite *OpenStreamTestSuite) TestOpenStream() {
 	account := suite.testAccounts["local_account_1"]
 
-	_, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, "user")
+	_, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, "user", "")
 	suite.NoError(errWithCode)
 }
 
@@ -0,0 +1,231 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
+)
+
+func (p *processor) StreamStatus(ctx context.Context, status *gtsmodel.Status) error {
+	timelines, err := p.statusTimelines(ctx, status)
+	if err != nil {
+		return fmt.Errorf("StreamStatus: error getting timelines for status %s: %s", status.ID, err)
+	}
+
+	if len(timelines) == 0 {
+		return nil
+	}
+
+	errs := []string{}
+	for _, accountID := range p.accountsSubscribedTo(timelines) {
+		if err := p.streamStatusToAccount(ctx, status, timelines, accountID); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) != 0 {
+		return fmt.Errorf("StreamStatus: one or more errors streaming status %s: %s", status.ID, strings.Join(errs, ";"))
+	}
+
+	return nil
+}
+
+// statusTimelines returns the public, hashtag, and direct timelines that the given status belongs on.
+// Home timelines are taken care of separately, since they're timelined as well as streamed.
+func (p *processor) statusTimelines(ctx context.Context, status *gtsmodel.Status) ([][]string, error) {
+	// boosts only ever go to home timelines
+	if status.BoostOfID != "" {
+		return nil, nil
+	}
+
+	timelines := [][]string{}
+	switch status.Visibility {
+	case gtsmodel.VisibilityPublic:
+		if status.InReplyToURI == "" && status.InReplyToID == "" {
+			timelines = append(timelines, []string{stream.TimelinePublic})
+			if status.Local {
+				timelines = append(timelines, []string{stream.TimelineLocal})
+			}
+		}
+
+		tags := status.Tags
+		if tags == nil {
+			for _, tagID := range status.TagIDs {
+				tag := &gtsmodel.Tag{}
+				if err := p.db.GetByID(ctx, tagID, tag); err != nil {
+					return nil, err
+				}
+				tags = append(tags, tag)
+			}
+		}
+
+		for _, tag := range tags {
+			name := strings.ToLower(tag.Name)
+			timelines = append(timelines, []string{stream.TimelineHashtag, name})
+			if status.Local {
+				timelines = append(timelines, []string{stream.TimelineHashtagLocal, name})
+			}
+		}
+	case gtsmodel.VisibilityDirect:
+		timelines = append(timelines, []string{stream.TimelineDirect})
+	}
+
+	return timelines, nil
+}
+
+// streamStatusToAccount streams the given status to the streams of the given account that are
+// subscribed to any of the given timelines, provided the account is permitted to see it there.
+func (p *processor) streamStatusToAccount(ctx context.Context, status *gtsmodel.Status, timelines [][]string, accountID string) error {
+	account, err := p.db.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("error getting account %s: %s", accountID, err)
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, status, account)
+	if err != nil {
+		return fmt.Errorf("error checking visibility of status for account %s: %s", accountID, err)
+	}
+
+	if !visible {
+		return nil
+	}
+
+	muted, err := p.filter.StatusMuted(ctx, status, account)
+	if err != nil {
+		return fmt.Errorf("error checking mutes of account %s: %s", accountID, err)
+	}
+
+	if muted {
+		return nil
+	}
+
+	filtered, results, err := p.filter.StatusFilterResults(ctx, status, account, gtsmodel.FilterContextPublic)
+	if err != nil {
+		return fmt.Errorf("error checking filters of account %s: %s", accountID, err)
+	}
+
+	// public filters apply to the public and hashtag timelines, so they're streamed
+	// separately, with the results of any warn filters that the status matched
+	permitted := [][]string{}
+	publicPermitted := [][]string{}
+	var publicTimelineable *bool
+	for _, t := range timelines {
+		switch t[0] {
+		case stream.TimelinePublic, stream.TimelineLocal, stream.TimelineHashtag, stream.TimelineHashtagLocal:
+			// hashtag timelines are public timelines too, so statuses from silenced domains are kept out of them as well
+			if publicTimelineable == nil {
+				timelineable, err := p.filter.StatusPublictimelineable(ctx, status, account)
+				if err != nil {
+					return fmt.Errorf("error checking public timelineability of status for account %s: %s", accountID, err)
+				}
+				publicTimelineable = &timelineable
+			}
+			if *publicTimelineable && !filtered {
+				publicPermitted = append(publicPermitted, t)
+			}
+			continue
+		case stream.TimelineDirect:
+			involved, err := p.directlyInvolved(ctx, status, accountID)
+			if err != nil {
+				return fmt.Errorf("error checking mentions of status for account %s: %s", accountID, err)
+			}
+			if !involved {
+				continue
+			}
+		}
+		permitted = append(permitted, t)
+	}
+
+	if len(permitted) == 0 && len(publicPermitted) == 0 {
+		return nil
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, account)
+	if err != nil {
+		return fmt.Errorf("error converting status to api representation for account %s: %s", accountID, err)
+	}
+
+	if len(results) == 0 {
+		// nothing to tell the public timelines that the others don't need to know
+		permitted = append(permitted, publicPermitted...)
+		publicPermitted = nil
+	}
+
+	if len(permitted) != 0 {
+		if err := p.streamStatusToTimelines(apiStatus, permitted, accountID); err != nil {
+			return err
+		}
+	}
+
+	if len(publicPermitted) != 0 {
+		apiResults, err := p.tc.FilterResultsToAPIFilterResults(ctx, results)
+		if err != nil {
+			return fmt.Errorf("error converting filter results to api representation for account %s: %s", accountID, err)
+		}
+
+		annotated := *apiStatus
+		annotated.Filtered = apiResults
+		if err := p.streamStatusToTimelines(&annotated, publicPermitted, accountID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamStatusToTimelines streams the given api status as an update to the given timelines of the given account.
+func (p *processor) streamStatusToTimelines(apiStatus *apimodel.Status, timelines [][]string, accountID string) error {
+	bytes, err := json.Marshal(apiStatus)
+	if err != nil {
+		return fmt.Errorf("error marshalling status to json: %s", err)
+	}
+
+	return p.streamToAccount(string(bytes), stream.EventTypeUpdate, timelines, accountID)
+}
+
+// directlyInvolved returns true if the account with the given ID authored or is mentioned in the given status.
+func (p *processor) directlyInvolved(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	if status.AccountID == accountID {
+		return true, nil
+	}
+
+	mentions := status.Mentions
+	if mentions == nil {
+		var err error
+		mentions, err = p.db.GetMentions(ctx, status.MentionIDs)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for _, m := range mentions {
+		if m.TargetAccountID == accountID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,156 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package streaming_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type StatusTestSuite struct {
+	StreamingTestSuite
+}
+
+func (suite *StatusTestSuite) TestStreamStatusPublic() {
+	account := suite.testAccounts["local_account_1"]
+	status := testrig.NewTestStatuses()["admin_account_status_1"]
+
+	publicStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, stream.TimelinePublic, "")
+	suite.NoError(errWithCode)
+
+	// subscribe the same stream to the local timeline and the hashtag of the status, like a multiplexing client would
+	publicStream.Lock()
+	publicStream.Subscribe([]string{stream.TimelineLocal})
+	publicStream.Subscribe([]string{stream.TimelineHashtag, "welcome"})
+	publicStream.Unlock()
+
+	err := suite.streamingProcessor.StreamStatus(context.Background(), status)
+	suite.NoError(err)
+
+	streams := [][]string{}
+	for i := 0; i < 3; i++ {
+		msg := <-publicStream.Messages
+		suite.Equal(stream.EventTypeUpdate, msg.Event)
+		suite.Contains(msg.Payload, status.ID)
+		streams = append(streams, msg.Stream)
+	}
+	suite.ElementsMatch([][]string{{"public"}, {"public:local"}, {"hashtag", "welcome"}}, streams)
+	suite.Empty(publicStream.Messages)
+}
+
+func (suite *StatusTestSuite) TestStreamStatusOtherHashtag() {
+	account := suite.testAccounts["local_account_1"]
+	status := testrig.NewTestStatuses()["admin_account_status_1"]
+
+	hashtagStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, stream.TimelineHashtag, "#Gotosocial")
+	suite.NoError(errWithCode)
+	suite.True(hashtagStream.Subscribed([]string{stream.TimelineHashtag, "gotosocial"}))
+
+	err := suite.streamingProcessor.StreamStatus(context.Background(), status)
+	suite.NoError(err)
+	suite.Empty(hashtagStream.Messages)
+}
+
+func (suite *StatusTestSuite) TestStreamStatusSilencedHashtag() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+
+	// a public status with a hashtag, from an account that local_account_1 doesn't follow
+	status := testrig.NewTestStatuses()["remote_account_1_status_1"]
+	status.Visibility = gtsmodel.VisibilityPublic
+	status.Tags = []*gtsmodel.Tag{{Name: "Welcome"}}
+
+	hashtagStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(ctx, account, stream.TimelineHashtag, "welcome")
+	suite.NoError(errWithCode)
+
+	suite.NoError(suite.streamingProcessor.StreamStatus(ctx, status))
+	msg := <-hashtagStream.Messages
+	suite.Equal([]string{stream.TimelineHashtag, "welcome"}, msg.Stream)
+
+	// once the author's domain is silenced, the status is kept out of the hashtag timeline like any other public timeline
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.DomainBlock{
+		ID:                 "01G0SYJ9ZJ7Q4RCHX4SSHHZX2P",
+		Domain:             suite.testAccounts["remote_account_1"].Domain,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		Severity:           gtsmodel.DomainBlockSeveritySilence,
+	}))
+
+	suite.NoError(suite.streamingProcessor.StreamStatus(ctx, status))
+	suite.Empty(hashtagStream.Messages)
+}
+
+func (suite *StatusTestSuite) TestStreamStatusDirect() {
+	status := testrig.NewTestStatuses()["local_account_2_status_6"]
+
+	// local account 1 is mentioned in the status, the admin isn't
+	mentionedStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), suite.testAccounts["local_account_1"], stream.TimelineDirect, "")
+	suite.NoError(errWithCode)
+	otherStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), suite.testAccounts["admin_account"], stream.TimelineDirect, "")
+	suite.NoError(errWithCode)
+
+	err := suite.streamingProcessor.StreamStatus(context.Background(), status)
+	suite.NoError(err)
+
+	msg := <-mentionedStream.Messages
+	suite.Equal([]string{"direct"}, msg.Stream)
+	suite.Contains(msg.Payload, status.ID)
+	suite.Empty(otherStream.Messages)
+}
+
+func (suite *StatusTestSuite) TestStreamStatusFullStream() {
+	account := suite.testAccounts["local_account_1"]
+	status := testrig.NewTestStatuses()["admin_account_status_1"]
+
+	fullStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, stream.TimelinePublic, "")
+	suite.NoError(errWithCode)
+	otherStream, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), account, stream.TimelinePublic, "")
+	suite.NoError(errWithCode)
+
+	// fill up one stream, as if its client had stopped reading from it
+	for i := 0; i < cap(fullStream.Messages); i++ {
+		fullStream.Messages <- &stream.Message{}
+	}
+
+	// the status should be dropped from the full stream rather than waiting for space in it
+	err := suite.streamingProcessor.StreamStatus(context.Background(), status)
+	suite.NoError(err)
+
+	msg := <-otherStream.Messages
+	suite.Contains(msg.Payload, status.ID)
+	suite.Len(fullStream.Messages, cap(fullStream.Messages))
+
+	// the full stream can still hang up
+	close(fullStream.Hangup)
+	for range fullStream.Messages {
+	}
+}
+
+func (suite *StatusTestSuite) TestOpenListStream() {
+	_, errWithCode := suite.streamingProcessor.OpenStreamForAccount(context.Background(), suite.testAccounts["local_account_1"], stream.TimelineList, "")
+	suite.EqualError(errWithCode, "list streams are not supported")
+}
+
+func TestStatusTestSuite(t *testing.T) {
+	suite.Run(t, &StatusTestSuite{})
+}
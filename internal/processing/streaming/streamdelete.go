@@ -19,37 +19,50 @@
 package streaming
 
 import (
-	"fmt"
-	"strings"
+	"errors"
 
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
 )
 
 func (p *processor) StreamDelete(statusID string) error {
-	errs := []string{}
+	var err error
 
-	// get all account IDs with open streams
-	accountIDs := []string{}
-	p.streamMap.Range(func(k interface{}, _ interface{}) bool {
-		key, ok := k.(string)
+	// stream the delete to every open stream, on every status timeline the stream is subscribed to
+	p.streamMap.Range(func(_ interface{}, v interface{}) bool {
+		streamsForAccount, ok := v.(*stream.StreamsForAccount)
 		if !ok {
-			panic("streamMap key was not a string (account id)")
+			err = errors.New("stream map error")
+			return true
 		}
 
-		accountIDs = append(accountIDs, key)
+		streamsForAccount.Lock()
+		defer streamsForAccount.Unlock()
+		for _, s := range streamsForAccount.Streams {
+			s.Lock()
+			if s.Connected {
+				for _, t := range s.Timelines {
+					if isStatusTimeline(t[0]) {
+						send(s, &stream.Message{
+							Stream:  t,
+							Event:   stream.EventTypeDelete,
+							Payload: statusID,
+						})
+					}
+				}
+			}
+			s.Unlock()
+		}
 		return true
 	})
 
-	// stream the delete to every account
-	for _, accountID := range accountIDs {
-		if err := p.streamToAccount(statusID, stream.EventTypeDelete, stream.AllStatusTimelines, accountID); err != nil {
-			errs = append(errs, err.Error())
-		}
-	}
+	return err
+}
 
-	if len(errs) != 0 {
-		return fmt.Errorf("one or more errors streaming status delete: %s", strings.Join(errs, ";"))
+func isStatusTimeline(timeline string) bool {
+	for _, t := range stream.AllStatusTimelines {
+		if t == timeline {
+			return true
+		}
 	}
-
-	return nil
+	return false
 }
@@ -28,6 +28,8 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/visibility"
 )
 
 // Processor wraps a bunch of functions for processing streaming.
@@ -35,25 +37,36 @@ type Processor interface {
 	// AuthorizeStreamingRequest returns an oauth2 token info in response to an access token query from the streaming API
 	AuthorizeStreamingRequest(ctx context.Context, accessToken string) (*gtsmodel.Account, error)
 	// OpenStreamForAccount returns a new Stream for the given account, which will contain a channel for passing messages back to the caller.
-	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, timeline string) (*stream.Stream, gtserror.WithCode)
+	//
+	// If timeline is set, the stream will start out subscribed to it; tag is only used for hashtag timelines.
+	// If timeline is empty, the stream will start out with no subscriptions.
+	OpenStreamForAccount(ctx context.Context, account *gtsmodel.Account, timeline string, tag string) (*stream.Stream, gtserror.WithCode)
 	// StreamUpdateToAccount streams the given update to any open, appropriate streams belonging to the given account.
 	StreamUpdateToAccount(s *apimodel.Status, account *gtsmodel.Account, timeline string) error
 	// StreamNotificationToAccount streams the given notification to any open, appropriate streams belonging to the given account.
 	StreamNotificationToAccount(n *apimodel.Notification, account *gtsmodel.Account) error
+	// StreamConversationToAccount streams the given conversation to any open direct streams belonging to the given account.
+	StreamConversationToAccount(c *apimodel.Conversation, account *gtsmodel.Account) error
+	// StreamStatus streams the given new status to any open public, hashtag, or direct streams belonging to accounts that can see it.
+	StreamStatus(ctx context.Context, status *gtsmodel.Status) error
 	// StreamDelete streams the delete of the given statusID to *ALL* open streams.
 	StreamDelete(statusID string) error
 }
 
 type processor struct {
+	tc          typeutils.TypeConverter
 	db          db.DB
+	filter      visibility.Filter
 	oauthServer oauth.Server
 	streamMap   *sync.Map
 }
 
 // New returns a new status processor.
-func New(db db.DB, oauthServer oauth.Server) Processor {
+func New(db db.DB, tc typeutils.TypeConverter, oauthServer oauth.Server) Processor {
 	return &processor{
+		tc:          tc,
 		db:          db,
+		filter:      visibility.NewFilter(db),
 		oauthServer: oauthServer,
 		streamMap:   &sync.Map{},
 	}
@@ -45,7 +45,7 @@ func (suite *StreamingTestSuite) SetupTest() {
 	suite.testTokens = testrig.NewTestTokens()
 	suite.db = testrig.NewTestDB()
 	suite.oauthServer = testrig.NewTestOauthServer(suite.db)
-	suite.streamingProcessor = streaming.New(suite.db, suite.oauthServer)
+	suite.streamingProcessor = streaming.New(suite.db, testrig.NewTestTypeConverter(suite.db), suite.oauthServer)
 
 	testrig.StandardDBSetup(suite.db, suite.testAccounts)
 }
@@ -21,11 +21,13 @@ package streaming
 import (
 	"errors"
 
+	"github.com/sirupsen/logrus"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
 )
 
-// streamToAccount streams the given payload with the given event type to any streams currently open for the given account ID.
-func (p *processor) streamToAccount(payload string, event string, timelines []string, accountID string) error {
+// streamToAccount streams the given payload with the given event type to any streams currently open for the given account ID,
+// which are subscribed to one of the given timelines. Timelines should be given as returned by stream.Names.
+func (p *processor) streamToAccount(payload string, event string, timelines [][]string, accountID string) error {
 	v, ok := p.streamMap.Load(accountID)
 	if !ok {
 		// no open connections so nothing to stream
@@ -41,21 +43,72 @@ func (p *processor) streamToAccount(payload string, event string, timelines []st
 	defer streamsForAccount.Unlock()
 	for _, s := range streamsForAccount.Streams {
 		s.Lock()
-		defer s.Unlock()
-		if !s.Connected {
-			continue
-		}
-
-		for _, t := range timelines {
-			if s.Timeline == string(t) {
-				s.Messages <- &stream.Message{
-					Stream:  []string{string(t)},
-					Event:   string(event),
-					Payload: payload,
+		if s.Connected {
+			for _, t := range timelines {
+				if s.Subscribed(t) {
+					send(s, &stream.Message{
+						Stream:  t,
+						Event:   event,
+						Payload: payload,
+					})
 				}
 			}
 		}
+		s.Unlock()
 	}
 
 	return nil
 }
+
+// send puts the given message in the given stream, or drops it if the stream is full.
+// The caller should hold the lock on the stream, and check that it's connected.
+func send(s *stream.Stream, msg *stream.Message) {
+	if !s.Send(msg) {
+		logrus.Debugf("stream %s is full, dropping %s message", s.ID, msg.Event)
+	}
+}
+
+// accountsSubscribedTo returns the IDs of all accounts that have at least one open stream
+// subscribed to one of the given timelines. Timelines should be given as returned by stream.Names.
+func (p *processor) accountsSubscribedTo(timelines [][]string) []string {
+	accountIDs := []string{}
+	p.streamMap.Range(func(k interface{}, v interface{}) bool {
+		accountID, ok := k.(string)
+		if !ok {
+			panic("streamMap key was not a string (account id)")
+		}
+
+		streamsForAccount, ok := v.(*stream.StreamsForAccount)
+		if !ok {
+			return true
+		}
+
+		if streamsSubscribedTo(streamsForAccount, timelines) {
+			accountIDs = append(accountIDs, accountID)
+		}
+		return true
+	})
+	return accountIDs
+}
+
+// streamsSubscribedTo returns true if any of the connected streams in streamsForAccount are subscribed to one of the given timelines.
+func streamsSubscribedTo(streamsForAccount *stream.StreamsForAccount, timelines [][]string) bool {
+	streamsForAccount.Lock()
+	defer streamsForAccount.Unlock()
+	for _, s := range streamsForAccount.Streams {
+		s.Lock()
+		subscribed := false
+		for _, t := range timelines {
+			if s.Connected && s.Subscribed(t) {
+				subscribed = true
+				break
+			}
+		}
+		s.Unlock()
+
+		if subscribed {
+			return true
+		}
+	}
+	return false
+}
@@ -33,5 +33,5 @@ func (p *processor) StreamUpdateToAccount(s *apimodel.Status, account *gtsmodel.
 		return fmt.Errorf("error marshalling status to json: %s", err)
 	}
 
-	return p.streamToAccount(string(bytes), stream.EventTypeUpdate, []string{timeline}, account.ID)
+	return p.streamToAccount(string(bytes), stream.EventTypeUpdate, [][]string{{timeline}}, account.ID)
 }
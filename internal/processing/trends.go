@@ -0,0 +1,542 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/spf13/viper"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+// minTrendAccounts is how many different accounts need to use a hashtag or link,
+// or fave or boost a status, within the trends window before it can trend.
+const minTrendAccounts = 2
+
+// rankedTrendsCacheTTL is how long trends are kept around for once they've been ranked, so that
+// uses of everything in the trends window aren't counted up again every time trends are looked at.
+const rankedTrendsCacheTTL = 5 * time.Minute
+
+// newRankedTrendsCache returns a cache of ranked trends, keyed by trend type and the number of days in the trends window.
+func newRankedTrendsCache() *ttlcache.Cache {
+	c := ttlcache.NewCache()
+	c.SetTTL(rankedTrendsCacheTTL)
+	c.SkipTtlExtensionOnHit(true)
+	return c
+}
+
+// trend is a hashtag, status or link that has been used within the trends window, with its usage counted up.
+type trend struct {
+	targetID string
+	score    float64
+	history  []apimodel.History
+}
+
+// rankTrends counts up the given uses by target, over a window of the given number of days
+// ending now, and returns the targets used by enough different accounts, most trending first.
+//
+// Each account that used a target adds to its score, by 1 if the account last used it today,
+// down to 1/days if it was at the start of the window, so trends fade as the window slides on.
+func rankTrends(uses []*gtsmodel.TrendUse, now time.Time, days int) []*trend {
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	type counts struct {
+		uses     []int
+		accounts []map[string]bool
+		lastUsed map[string]int
+	}
+
+	byTarget := map[string]*counts{}
+	for _, use := range uses {
+		usedOn, err := time.Parse("2006-01-02", use.Day)
+		if err != nil {
+			continue
+		}
+		day := int(today.Sub(usedOn) / (24 * time.Hour))
+		if day < 0 || day >= days {
+			continue
+		}
+
+		c, ok := byTarget[use.TargetID]
+		if !ok {
+			c = &counts{
+				uses:     make([]int, days),
+				accounts: make([]map[string]bool, days),
+				lastUsed: map[string]int{},
+			}
+			byTarget[use.TargetID] = c
+		}
+
+		c.uses[day] += use.Uses
+		if c.accounts[day] == nil {
+			c.accounts[day] = map[string]bool{}
+		}
+		c.accounts[day][use.AccountID] = true
+		if lastUsed, ok := c.lastUsed[use.AccountID]; !ok || day < lastUsed {
+			c.lastUsed[use.AccountID] = day
+		}
+	}
+
+	trends := []*trend{}
+	for targetID, c := range byTarget {
+		if len(c.lastUsed) < minTrendAccounts {
+			continue
+		}
+
+		t := &trend{
+			targetID: targetID,
+			history:  make([]apimodel.History, 0, days),
+		}
+		for _, day := range c.lastUsed {
+			t.score += float64(days-day) / float64(days)
+		}
+		for day := 0; day < days; day++ {
+			t.history = append(t.history, apimodel.History{
+				Day:      strconv.FormatInt(today.AddDate(0, 0, -day).Unix(), 10),
+				Uses:     strconv.Itoa(c.uses[day]),
+				Accounts: strconv.Itoa(len(c.accounts[day])),
+			})
+		}
+		trends = append(trends, t)
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].score != trends[j].score {
+			return trends[i].score > trends[j].score
+		}
+		// newer targets first if they're level
+		return trends[i].targetID > trends[j].targetID
+	})
+
+	return trends
+}
+
+// rankedTrends returns the trends of the given type within the trends window, most trending first,
+// along with the admin reviews of them by target ID.
+func (p *processor) rankedTrends(ctx context.Context, trendType gtsmodel.TrendType) ([]*trend, map[string]*gtsmodel.TrendReview, error) {
+	days := viper.GetInt(config.Keys.StatusesTrendsDays)
+	if days <= 0 {
+		return []*trend{}, map[string]*gtsmodel.TrendReview{}, nil
+	}
+
+	// reviews aren't cached along with the trends, so that admins see their reviews take effect straight away
+	cacheKey := string(trendType) + "/" + strconv.Itoa(days)
+	var trends []*trend
+	cached, ok := p.trendsCache.Get(cacheKey)
+	if ok {
+		trends, ok = cached.([]*trend)
+	}
+
+	if !ok {
+		now := time.Now()
+		since := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+
+		var uses []*gtsmodel.TrendUse
+		var err error
+		switch trendType {
+		case gtsmodel.TrendTypeTag:
+			uses, err = p.db.GetTagUses(ctx, since)
+		case gtsmodel.TrendTypeStatus:
+			uses, err = p.db.GetStatusUses(ctx, since)
+		case gtsmodel.TrendTypeLink:
+			uses, err = p.db.GetLinkUses(ctx, since)
+		default:
+			return nil, nil, fmt.Errorf("unknown trend type %s", trendType)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting %s uses: %s", trendType, err)
+		}
+
+		trends = rankTrends(uses, now, days)
+		p.trendsCache.Set(cacheKey, trends)
+	}
+
+	reviews, err := p.db.GetTrendReviews(ctx, trendType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting %s reviews: %s", trendType, err)
+	}
+
+	reviewsByTarget := make(map[string]*gtsmodel.TrendReview, len(reviews))
+	for _, review := range reviews {
+		reviewsByTarget[review.TargetID] = review
+	}
+
+	return trends, reviewsByTarget, nil
+}
+
+// trendable returns whether something with the given admin review, which is nil if
+// it hasn't been reviewed yet, may be shown in public trends.
+func trendable(review *gtsmodel.TrendReview) bool {
+	if review == nil {
+		return !viper.GetBool(config.Keys.StatusesTrendsApproval)
+	}
+	return review.Approved
+}
+
+func (p *processor) TrendsTagsGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]apimodel.Tag, gtserror.WithCode) {
+	trends, reviews, err := p.rankedTrends(ctx, gtsmodel.TrendTypeTag)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiTags := []apimodel.Tag{}
+	for _, t := range trends {
+		if len(apiTags) >= limit {
+			break
+		}
+
+		if !trendable(reviews[t.targetID]) {
+			continue
+		}
+
+		apiTag, err := p.trendingTag(ctx, t)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if apiTag == nil {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+		apiTags = append(apiTags, *apiTag)
+	}
+
+	return apiTags, nil
+}
+
+func (p *processor) TrendsStatusesGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]*apimodel.Status, gtserror.WithCode) {
+	trends, reviews, err := p.rankedTrends(ctx, gtsmodel.TrendTypeStatus)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiStatuses := []*apimodel.Status{}
+	for _, t := range trends {
+		if len(apiStatuses) >= limit {
+			break
+		}
+
+		if !trendable(reviews[t.targetID]) {
+			continue
+		}
+
+		apiStatus, err := p.trendingStatus(ctx, t, authed.Account)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if apiStatus == nil {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+		apiStatuses = append(apiStatuses, apiStatus)
+	}
+
+	return apiStatuses, nil
+}
+
+func (p *processor) TrendsLinksGet(ctx context.Context, authed *oauth.Auth, limit int, offset int) ([]*apimodel.TrendsLink, gtserror.WithCode) {
+	trends, reviews, err := p.rankedTrends(ctx, gtsmodel.TrendTypeLink)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiLinks := []*apimodel.TrendsLink{}
+	for _, t := range trends {
+		if len(apiLinks) >= limit {
+			break
+		}
+
+		if !trendable(reviews[t.targetID]) {
+			continue
+		}
+
+		apiLink, err := p.trendingLink(ctx, t)
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if apiLink == nil {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+		apiLinks = append(apiLinks, apiLink)
+	}
+
+	return apiLinks, nil
+}
+
+func (p *processor) AdminTrendsGet(ctx context.Context, authed *oauth.Auth, trendType gtsmodel.TrendType, limit int) ([]*apimodel.AdminTrend, gtserror.WithCode) {
+	trends, reviews, err := p.rankedTrends(ctx, trendType)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiTrends := []*apimodel.AdminTrend{}
+	for _, t := range trends {
+		if len(apiTrends) >= limit {
+			break
+		}
+
+		apiTrend, err := p.adminTrend(ctx, authed.Account, trendType, t, reviews[t.targetID])
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		if apiTrend == nil {
+			continue
+		}
+		apiTrends = append(apiTrends, apiTrend)
+	}
+
+	return apiTrends, nil
+}
+
+func (p *processor) AdminTrendReview(ctx context.Context, authed *oauth.Auth, trendType gtsmodel.TrendType, targetID string, approve bool) (*apimodel.AdminTrend, gtserror.WithCode) {
+	trends, _, err := p.rankedTrends(ctx, trendType)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// the target doesn't have to be trending right now to be reviewed, but it does have to exist
+	t := &trend{targetID: targetID}
+	for _, rankedTrend := range trends {
+		if rankedTrend.targetID == targetID {
+			t = rankedTrend
+			break
+		}
+	}
+
+	review, err := p.db.GetTrendReview(ctx, trendType, targetID)
+	if err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting %s review: %s", trendType, err))
+	}
+
+	// make sure the review comes back with the target, so we're not storing reviews of things that don't exist
+	apiTrend, err := p.adminTrend(ctx, authed.Account, trendType, t, &gtsmodel.TrendReview{Approved: approve})
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	if apiTrend == nil {
+		return nil, gtserror.NewErrorNotFound(fmt.Errorf("no %s found with id %s", trendType, targetID))
+	}
+
+	if review == nil {
+		reviewID, err := id.NewULID()
+		if err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		review = &gtsmodel.TrendReview{
+			ID:         reviewID,
+			TargetType: trendType,
+			TargetID:   targetID,
+			Approved:   approve,
+			AccountID:  authed.Account.ID,
+		}
+		if err := p.db.Put(ctx, review); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting %s review: %s", trendType, err))
+		}
+	} else {
+		review.Approved = approve
+		review.AccountID = authed.Account.ID
+		review.UpdatedAt = time.Now()
+		if err := p.db.UpdateByPrimaryKey(ctx, review); err != nil {
+			return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating %s review: %s", trendType, err))
+		}
+	}
+
+	return apiTrend, nil
+}
+
+// adminTrend converts the given trend of the given type to its admin api representation, given its review,
+// which is nil if it hasn't been reviewed yet. If the target of the trend can't be shown, nil is returned.
+func (p *processor) adminTrend(ctx context.Context, requestingAccount *gtsmodel.Account, trendType gtsmodel.TrendType, t *trend, review *gtsmodel.TrendReview) (*apimodel.AdminTrend, error) {
+	apiTrend := &apimodel.AdminTrend{
+		ID:             t.targetID,
+		Type:           string(trendType),
+		Trendable:      trendable(review),
+		RequiresReview: review == nil,
+	}
+
+	switch trendType {
+	case gtsmodel.TrendTypeTag:
+		apiTag, err := p.trendingTag(ctx, t)
+		if err != nil || apiTag == nil {
+			return nil, err
+		}
+		apiTrend.Tag = apiTag
+	case gtsmodel.TrendTypeStatus:
+		apiStatus, err := p.trendingStatus(ctx, t, requestingAccount)
+		if err != nil || apiStatus == nil {
+			return nil, err
+		}
+		apiTrend.Status = apiStatus
+	case gtsmodel.TrendTypeLink:
+		apiLink, err := p.trendingLink(ctx, t)
+		if err != nil || apiLink == nil {
+			return nil, err
+		}
+		apiTrend.Link = apiLink
+	}
+
+	return apiTrend, nil
+}
+
+// trendingTag returns the api representation of the tag that the given trend is of,
+// or nil if the tag doesn't exist anymore or can't be listed.
+func (p *processor) trendingTag(ctx context.Context, t *trend) (*apimodel.Tag, error) {
+	tag := &gtsmodel.Tag{}
+	if err := p.db.GetByID(ctx, t.targetID, tag); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting tag %s: %s", t.targetID, err)
+	}
+
+	if !tag.Listable {
+		return nil, nil
+	}
+
+	apiTag, err := p.tc.TagToAPITag(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error converting tag %s to api representation: %s", tag.ID, err)
+	}
+	apiTag.History = t.history
+
+	return &apiTag, nil
+}
+
+// trendingStatus returns the api representation of the status that the given trend is of,
+// or nil if the status doesn't exist anymore or isn't visible to the requesting account.
+func (p *processor) trendingStatus(ctx context.Context, t *trend, requestingAccount *gtsmodel.Account) (*apimodel.Status, error) {
+	status, err := p.db.GetStatusByID(ctx, t.targetID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting status %s: %s", t.targetID, err)
+	}
+
+	visible, err := p.filter.StatusVisible(ctx, status, requestingAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error checking visibility of status %s: %s", status.ID, err)
+	}
+	if !visible {
+		return nil, nil
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error converting status %s to api representation: %s", status.ID, err)
+	}
+
+	return apiStatus, nil
+}
+
+// trendingLink returns the api representation of the link that the given trend is of,
+// or nil if the link doesn't exist anymore.
+func (p *processor) trendingLink(ctx context.Context, t *trend) (*apimodel.TrendsLink, error) {
+	link := &gtsmodel.Link{}
+	if err := p.db.GetByID(ctx, t.targetID, link); err != nil {
+		if err == db.ErrNoEntries {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting link %s: %s", t.targetID, err)
+	}
+
+	apiLink := &apimodel.TrendsLink{
+		Card: apimodel.Card{
+			URL:  link.URL,
+			Type: "link",
+		},
+		History: t.history,
+	}
+
+	if u, err := url.Parse(link.URL); err == nil {
+		apiLink.ProviderName = u.Host
+		apiLink.ProviderURL = u.Scheme + "://" + u.Host
+	}
+
+	return apiLink, nil
+}
+
+// trackStatusLinks records the web pages that the given status links to, if it's a public, original
+// status, so that they can be counted towards trends.
+func (p *processor) trackStatusLinks(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Visibility != gtsmodel.VisibilityPublic || status.BoostOfID != "" {
+		return nil
+	}
+
+	linkIDs := []string{}
+	for _, u := range text.FindHTMLLinks(status.Content) {
+		link, err := p.db.GetLinkByURL(ctx, u.String())
+		if err != nil {
+			if err != db.ErrNoEntries {
+				return fmt.Errorf("error getting link %s: %s", u, err)
+			}
+
+			// we haven't seen this link before
+			linkID, err := id.NewULID()
+			if err != nil {
+				return err
+			}
+
+			link = &gtsmodel.Link{
+				ID:  linkID,
+				URL: u.String(),
+			}
+			if err := p.db.Put(ctx, link); err != nil {
+				return fmt.Errorf("error putting link %s: %s", u, err)
+			}
+		}
+		linkIDs = append(linkIDs, link.ID)
+	}
+
+	if len(linkIDs) == 0 {
+		return nil
+	}
+
+	if err := p.db.PutStatusLinks(ctx, status.ID, linkIDs); err != nil {
+		return fmt.Errorf("error putting links of status %s: %s", status.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type TrendsTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *TrendsTestSuite) TestTrendingTag() {
+	ctx := context.Background()
+	welcome := suite.testTags["welcome"]
+
+	// one account using a tag isn't enough for it to trend, but two is
+	suite.putPublicStatus(suite.testAccounts["local_account_1"], "welcome everyone #Hashtag", welcome.ID, suite.testTags["Hashtag"].ID)
+	suite.putPublicStatus(suite.testAccounts["local_account_2"], "welcome welcome", welcome.ID)
+	suite.putPublicStatus(suite.testAccounts["local_account_2"], "welcome again", welcome.ID)
+	tags, errWithCode := suite.processor.TrendsTagsGet(ctx, suite.testAutheds["local_account_1"], 10, 0)
+	suite.NoError(errWithCode)
+	suite.Len(tags, 1)
+	suite.Equal("welcome", tags[0].Name)
+	suite.Len(tags[0].History, 7)
+	suite.Equal("3", tags[0].History[0].Uses)
+	suite.Equal("2", tags[0].History[0].Accounts)
+	suite.Equal("0", tags[0].History[1].Uses)
+
+	// paging past it gives nothing
+	tags, errWithCode = suite.processor.TrendsTagsGet(ctx, suite.testAutheds["local_account_1"], 10, 1)
+	suite.NoError(errWithCode)
+	suite.Empty(tags)
+
+	// an admin rejects the tag, so it's not shown anymore
+	adminAuthed := suite.adminAuthed()
+	rejected, errWithCode := suite.processor.AdminTrendReview(ctx, adminAuthed, gtsmodel.TrendTypeTag, welcome.ID, false)
+	suite.NoError(errWithCode)
+	suite.False(rejected.Trendable)
+	suite.False(rejected.RequiresReview)
+
+	tags, errWithCode = suite.processor.TrendsTagsGet(ctx, suite.testAutheds["local_account_1"], 10, 0)
+	suite.NoError(errWithCode)
+	suite.Empty(tags)
+
+	// but admins still see it
+	adminTrends, errWithCode := suite.processor.AdminTrendsGet(ctx, adminAuthed, gtsmodel.TrendTypeTag, 20)
+	suite.NoError(errWithCode)
+	suite.Len(adminTrends, 1)
+	suite.Equal(welcome.ID, adminTrends[0].ID)
+	suite.Equal("welcome", adminTrends[0].Tag.Name)
+	suite.False(adminTrends[0].Trendable)
+}
+
+func (suite *TrendsTestSuite) TestTrendingStatus() {
+	ctx := context.Background()
+	zork := suite.testAccounts["local_account_1"]
+	status := suite.putPublicStatus(zork, "what a nice day")
+
+	for _, faver := range []*gtsmodel.Account{suite.testAccounts["local_account_2"], suite.testAccounts["admin_account"]} {
+		faveID, err := id.NewULID()
+		suite.NoError(err)
+		suite.NoError(suite.db.Put(ctx, &gtsmodel.StatusFave{
+			ID:              faveID,
+			AccountID:       faver.ID,
+			TargetAccountID: zork.ID,
+			StatusID:        status.ID,
+			URI:             faver.URI + "/liked/" + faveID,
+		}))
+	}
+
+	statuses, errWithCode := suite.processor.TrendsStatusesGet(ctx, &oauth.Auth{}, 20, 0)
+	suite.NoError(errWithCode)
+	suite.Len(statuses, 1)
+	suite.Equal(status.ID, statuses[0].ID)
+	suite.Equal(2, statuses[0].FavouritesCount)
+}
+
+func (suite *TrendsTestSuite) TestTrendingLink() {
+	ctx := context.Background()
+
+	for _, account := range []*gtsmodel.Account{suite.testAccounts["local_account_1"], suite.testAccounts["local_account_2"]} {
+		status := suite.putPublicStatus(account, `<p>look at this <a href="https://example.org/some/article" rel="noopener">example.org/some/article</a></p>`)
+		err := suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+			APObjectType:   ap.ObjectNote,
+			APActivityType: ap.ActivityCreate,
+			GTSModel:       status,
+			OriginAccount:  account,
+		})
+		suite.NoError(err)
+	}
+
+	links, errWithCode := suite.processor.TrendsLinksGet(ctx, suite.testAutheds["local_account_1"], 10, 0)
+	suite.NoError(errWithCode)
+	suite.Len(links, 1)
+	suite.Equal("https://example.org/some/article", links[0].URL)
+	suite.Equal("example.org", links[0].ProviderName)
+	suite.Equal("2", links[0].History[0].Accounts)
+}
+
+func (suite *TrendsTestSuite) TestTrendsApproval() {
+	ctx := context.Background()
+	viper.Set(config.Keys.StatusesTrendsApproval, true)
+	welcome := suite.testTags["welcome"]
+
+	suite.putPublicStatus(suite.testAccounts["local_account_1"], "welcome everyone", welcome.ID)
+	suite.putPublicStatus(suite.testAccounts["local_account_2"], "welcome welcome", welcome.ID)
+
+	// the tag is trending, but it's not shown until an admin approves it
+	tags, errWithCode := suite.processor.TrendsTagsGet(ctx, suite.testAutheds["local_account_1"], 10, 0)
+	suite.NoError(errWithCode)
+	suite.Empty(tags)
+
+	adminAuthed := suite.adminAuthed()
+	adminTrends, errWithCode := suite.processor.AdminTrendsGet(ctx, adminAuthed, gtsmodel.TrendTypeTag, 20)
+	suite.NoError(errWithCode)
+	suite.Len(adminTrends, 1)
+	suite.False(adminTrends[0].Trendable)
+	suite.True(adminTrends[0].RequiresReview)
+
+	approved, errWithCode := suite.processor.AdminTrendReview(ctx, adminAuthed, gtsmodel.TrendTypeTag, welcome.ID, true)
+	suite.NoError(errWithCode)
+	suite.True(approved.Trendable)
+	suite.Len(approved.Tag.History, 7)
+
+	tags, errWithCode = suite.processor.TrendsTagsGet(ctx, suite.testAutheds["local_account_1"], 10, 0)
+	suite.NoError(errWithCode)
+	suite.Len(tags, 1)
+
+	// trends are kept for a while once they're ranked, so new uses don't show up straight away,
+	// but admin reviews of them do
+	suite.putPublicStatus(suite.testAccounts["local_account_1"], "#Hashtag", suite.testTags["Hashtag"].ID)
+	suite.putPublicStatus(suite.testAccounts["local_account_2"], "#Hashtag", suite.testTags["Hashtag"].ID)
+	adminTrends, errWithCode = suite.processor.AdminTrendsGet(ctx, adminAuthed, gtsmodel.TrendTypeTag, 20)
+	suite.NoError(errWithCode)
+	suite.Len(adminTrends, 1)
+	suite.True(adminTrends[0].Trendable)
+
+	// things that don't exist can't be reviewed
+	_, errWithCode = suite.processor.AdminTrendReview(ctx, adminAuthed, gtsmodel.TrendTypeLink, "01G2C9X2ZB0Y8SE4WJ6DM3RWKQ", true)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *TrendsTestSuite) adminAuthed() *oauth.Auth {
+	return &oauth.Auth{
+		Application: suite.testApplications["admin_account"],
+		User:        suite.testUsers["admin_account"],
+		Account:     suite.testAccounts["admin_account"],
+	}
+}
+
+// putPublicStatus puts a public status with the given content and tags, written by the given account just now, in the db.
+func (suite *TrendsTestSuite) putPublicStatus(account *gtsmodel.Account, content string, tagIDs ...string) *gtsmodel.Status {
+	statusID, err := id.NewULID()
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	status := &gtsmodel.Status{
+		ID:                       statusID,
+		URI:                      account.URI + "/statuses/" + statusID,
+		URL:                      account.URL + "/statuses/" + statusID,
+		Content:                  content,
+		TagIDs:                   tagIDs,
+		Local:                    true,
+		AccountURI:               account.URI,
+		AccountID:                account.ID,
+		Account:                  account,
+		Visibility:               gtsmodel.VisibilityPublic,
+		Language:                 "en",
+		CreatedWithApplicationID: suite.testApplications["application_1"].ID,
+		Federated:                true,
+		Boostable:                true,
+		Replyable:                true,
+		Likeable:                 true,
+		ActivityStreamsType:      ap.ObjectNote,
+	}
+	if err := suite.db.PutStatus(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return status
+}
+
+func TestTrendsTestSuite(t *testing.T) {
+	suite.Run(t, &TrendsTestSuite{})
+}
@@ -0,0 +1,297 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+)
+
+// pushBodyLength is how many characters of a status are included in the body of a web push notification.
+const pushBodyLength = 140
+
+func (p *processor) PushSubscriptionGet(ctx context.Context, authed *oauth.Auth) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	subscription, errWithCode := p.getPushSubscription(ctx, authed)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiPushSubscription(ctx, subscription)
+}
+
+func (p *processor) PushSubscriptionCreate(ctx context.Context, authed *oauth.Auth, form *apimodel.WebPushSubscriptionCreateRequest) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	if err := validate.PushEndpoint(form.Subscription.Endpoint); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if err := webpush.ValidateKeys(form.Subscription.Keys.P256dh, form.Subscription.Keys.Auth); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	token, errWithCode := p.getAccessToken(ctx, authed)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// each token only has one subscription, so a new one replaces whatever was there before
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "token_id", Value: token.ID}}, &gtsmodel.WebPushSubscription{}); err != nil && err != db.ErrNoEntries {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error deleting existing subscription: %s", err))
+	}
+
+	subscriptionID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	subscription := &gtsmodel.WebPushSubscription{
+		ID:        subscriptionID,
+		AccountID: authed.Account.ID,
+		TokenID:   token.ID,
+		Endpoint:  form.Subscription.Endpoint,
+		P256dh:    form.Subscription.Keys.P256dh,
+		Auth:      form.Subscription.Keys.Auth,
+	}
+	setPushAlerts(subscription, form.Data.Alerts)
+
+	if err := p.db.Put(ctx, subscription); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error putting subscription: %s", err))
+	}
+
+	return p.apiPushSubscription(ctx, subscription)
+}
+
+func (p *processor) PushSubscriptionUpdate(ctx context.Context, authed *oauth.Auth, form *apimodel.WebPushSubscriptionUpdateRequest) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	subscription, errWithCode := p.getPushSubscription(ctx, authed)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	setPushAlerts(subscription, form.Data.Alerts)
+	if err := p.db.UpdateByPrimaryKey(ctx, subscription); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error updating subscription: %s", err))
+	}
+
+	return p.apiPushSubscription(ctx, subscription)
+}
+
+func (p *processor) PushSubscriptionDelete(ctx context.Context, authed *oauth.Auth) gtserror.WithCode {
+	token, errWithCode := p.getAccessToken(ctx, authed)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.db.DeleteWhere(ctx, []db.Where{{Key: "token_id", Value: token.ID}}, &gtsmodel.WebPushSubscription{}); err != nil && err != db.ErrNoEntries {
+		return gtserror.NewErrorInternalError(fmt.Errorf("error deleting subscription: %s", err))
+	}
+
+	return nil
+}
+
+// getAccessToken gets the database entry of the access token that the given request was authed with.
+func (p *processor) getAccessToken(ctx context.Context, authed *oauth.Auth) (*gtsmodel.Token, gtserror.WithCode) {
+	if authed.Token == nil || authed.Token.GetAccess() == "" {
+		err := errors.New("web push subscriptions can only be managed with an access token")
+		return nil, gtserror.NewErrorNotAuthorized(err, err.Error())
+	}
+
+	token := &gtsmodel.Token{}
+	if err := p.db.GetWhere(ctx, []db.Where{{Key: "access", Value: authed.Token.GetAccess()}}, token); err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting access token: %s", err))
+	}
+
+	return token, nil
+}
+
+// getPushSubscription gets the web push subscription made with the access token that the given request was authed with.
+func (p *processor) getPushSubscription(ctx context.Context, authed *oauth.Auth) (*gtsmodel.WebPushSubscription, gtserror.WithCode) {
+	token, errWithCode := p.getAccessToken(ctx, authed)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	subscription, err := p.db.GetWebPushSubscriptionByTokenID(ctx, token.ID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			err := errors.New("no web push subscription exists for this access token")
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting subscription: %s", err))
+	}
+
+	return subscription, nil
+}
+
+func (p *processor) apiPushSubscription(ctx context.Context, subscription *gtsmodel.WebPushSubscription) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	apiSubscription, err := p.tc.WebPushSubscriptionToAPIWebPushSubscription(ctx, subscription)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiSubscription, nil
+}
+
+// setPushAlerts sets which notifications are sent to the given subscription.
+func setPushAlerts(subscription *gtsmodel.WebPushSubscription, alerts apimodel.WebPushSubscriptionAlerts) {
+	subscription.AlertFollow = alerts.Follow
+	subscription.AlertFollowRequest = alerts.FollowRequest
+	subscription.AlertFavourite = alerts.Favourite
+	subscription.AlertReblog = alerts.Reblog
+	subscription.AlertMention = alerts.Mention
+	subscription.AlertPoll = alerts.Poll
+	subscription.AlertStatus = alerts.Status
+}
+
+// pushAlertEnabled returns true if the given subscription wants notifications of the given type.
+func pushAlertEnabled(subscription *gtsmodel.WebPushSubscription, notificationType string) bool {
+	switch gtsmodel.NotificationType(notificationType) {
+	case gtsmodel.NotificationFollow:
+		return subscription.AlertFollow
+	case gtsmodel.NotificationFollowRequest:
+		return subscription.AlertFollowRequest
+	case gtsmodel.NotificationFave:
+		return subscription.AlertFavourite
+	case gtsmodel.NotificationReblog:
+		return subscription.AlertReblog
+	case gtsmodel.NotificationMention:
+		return subscription.AlertMention
+	case gtsmodel.NotificationPoll:
+		return subscription.AlertPoll
+	case gtsmodel.NotificationStatus:
+		return subscription.AlertStatus
+	}
+	return false
+}
+
+// pushJob is a notification waiting to be sent to the web push subscriptions of an account.
+type pushJob struct {
+	notification *apimodel.Notification
+	account      *gtsmodel.Account
+}
+
+// pushNotification queues the given notification to be sent to the web push subscriptions of the given account.
+func (p *processor) pushNotification(n *apimodel.Notification, account *gtsmodel.Account) {
+	p.pushWorker.Queue(pushJob{notification: n, account: account})
+}
+
+// sendPushNotification sends the notification of the given job to the web push subscriptions
+// of its account that want notifications of its type. It's called by the push worker.
+//
+// Push services are outside of our control, so failures to send to them are logged rather than returned,
+// and a failure with one subscription doesn't stop the notification from going to the others.
+// Subscriptions that a push service says don't exist anymore are deleted.
+func (p *processor) sendPushNotification(ctx context.Context, job pushJob) error {
+	n := job.notification
+	subscriptions, err := p.db.GetWebPushSubscriptionsByAccountID(ctx, job.account.ID)
+	if err != nil {
+		return fmt.Errorf("sendPushNotification: error getting web push subscriptions: %s", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if !pushAlertEnabled(subscription, n.Type) {
+			continue
+		}
+
+		token := &gtsmodel.Token{}
+		if err := p.db.GetByID(ctx, subscription.TokenID, token); err != nil {
+			logrus.Errorf("sendPushNotification: error getting access token of web push subscription %s: %s", subscription.ID, err)
+			continue
+		}
+
+		payload, err := json.Marshal(&apimodel.WebPushNotification{
+			AccessToken:      token.Access,
+			NotificationID:   n.ID,
+			NotificationType: n.Type,
+			Icon:             n.Account.Avatar,
+			Title:            pushTitle(n),
+			Body:             pushBody(n),
+		})
+		if err != nil {
+			logrus.Errorf("sendPushNotification: error marshalling notification %s for web push subscription %s: %s", n.ID, subscription.ID, err)
+			continue
+		}
+
+		err = p.webPushSender.Send(ctx, subscription, payload)
+		switch {
+		case errors.Is(err, webpush.ErrGone):
+			logrus.Debugf("sendPushNotification: deleting web push subscription %s, which its push service says is gone", subscription.ID)
+			if err := p.db.DeleteByID(ctx, subscription.ID, subscription); err != nil {
+				logrus.Errorf("sendPushNotification: error deleting web push subscription %s: %s", subscription.ID, err)
+			}
+		case err != nil:
+			logrus.Infof("sendPushNotification: error sending notification %s to web push subscription %s: %s", n.ID, subscription.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// pushTitle returns a short summary of the given notification.
+func pushTitle(n *apimodel.Notification) string {
+	name := n.Account.DisplayName
+	if name == "" {
+		name = n.Account.Username
+	}
+
+	switch gtsmodel.NotificationType(n.Type) {
+	case gtsmodel.NotificationFollow:
+		return fmt.Sprintf("%s followed you", name)
+	case gtsmodel.NotificationFollowRequest:
+		return fmt.Sprintf("%s requested to follow you", name)
+	case gtsmodel.NotificationFave:
+		return fmt.Sprintf("%s favourited your post", name)
+	case gtsmodel.NotificationReblog:
+		return fmt.Sprintf("%s boosted your post", name)
+	case gtsmodel.NotificationMention:
+		return fmt.Sprintf("%s mentioned you", name)
+	case gtsmodel.NotificationPoll:
+		return "A poll has ended"
+	case gtsmodel.NotificationStatus:
+		return fmt.Sprintf("%s just posted", name)
+	}
+	return name
+}
+
+// pushBody returns the start of the text of the status of the given notification, or its content warning if it has one.
+func pushBody(n *apimodel.Notification) string {
+	if n.Status == nil {
+		return ""
+	}
+
+	body := n.Status.SpoilerText
+	if body == "" {
+		body = text.RemoveHTML(n.Status.Content)
+	}
+
+	if runes := []rune(body); len(runes) > pushBodyLength {
+		body = string(runes[:pushBodyLength-1]) + "…"
+	}
+	return body
+}
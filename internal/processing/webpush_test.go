@@ -0,0 +1,210 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package processing_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+const (
+	testPushEndpoint = "https://push.example.org/send/abcdef"
+	testPushP256dh   = "BEs9j6Pgf_T9JUhfcsmTs_J9sszsYrizfHOBgqyXy7k-Zt0UlmYtd63tHpFal8PYeyE8EyR5pxp1XR19Ofx0aTQ"
+	testPushAuth     = "m0159YH5UrJzFQ6v4O39jA"
+)
+
+type WebPushTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+// authedWithToken returns the auth of local_account_1, with the access token it was made with
+func (suite *WebPushTestSuite) authedWithToken() *oauth.Auth {
+	authed := *suite.testAutheds["local_account_1"]
+	authed.Token = oauth.DBTokenToToken(suite.testTokens["local_account_1"])
+	return &authed
+}
+
+func (suite *WebPushTestSuite) createSubscription(authed *oauth.Auth, alerts model.WebPushSubscriptionAlerts) *model.WebPushSubscription {
+	form := &model.WebPushSubscriptionCreateRequest{}
+	form.Subscription.Endpoint = testPushEndpoint
+	form.Subscription.Keys.P256dh = testPushP256dh
+	form.Subscription.Keys.Auth = testPushAuth
+	form.Data.Alerts = alerts
+
+	subscription, errWithCode := suite.processor.PushSubscriptionCreate(context.Background(), authed, form)
+	suite.NoError(errWithCode)
+	return subscription
+}
+
+// faveStatus has admin fave a status of local_account_1, and processes the fave like the client API would.
+func (suite *WebPushTestSuite) faveStatus() {
+	ctx := context.Background()
+	favingAccount := suite.testAccounts["admin_account"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+	fave := &gtsmodel.StatusFave{
+		ID:              "01G1MKFTRR7ZTCMJD6HQ2ZNTJQ",
+		URI:             "http://localhost:8080/users/admin/liked/01G1MKFTRR7ZTCMJD6HQ2ZNTJQ",
+		AccountID:       favingAccount.ID,
+		Account:         favingAccount,
+		TargetAccountID: targetStatus.AccountID,
+		TargetAccount:   suite.testAccounts["local_account_1"],
+		StatusID:        targetStatus.ID,
+		Status:          targetStatus,
+	}
+	suite.NoError(suite.db.Put(ctx, fave))
+
+	err := suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ActivityLike,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       fave,
+		OriginAccount:  favingAccount,
+		TargetAccount:  fave.TargetAccount,
+	})
+	suite.NoError(err)
+}
+
+func (suite *WebPushTestSuite) TestPushSubscriptionLifecycle() {
+	ctx := context.Background()
+	authed := suite.authedWithToken()
+
+	created := suite.createSubscription(authed, model.WebPushSubscriptionAlerts{Follow: true, Favourite: true})
+	suite.Equal(testPushEndpoint, created.Endpoint)
+	suite.True(created.Alerts.Follow)
+	suite.True(created.Alerts.Favourite)
+	suite.False(created.Alerts.Mention)
+	suite.NotEmpty(created.ServerKey)
+
+	got, errWithCode := suite.processor.PushSubscriptionGet(ctx, authed)
+	suite.NoError(errWithCode)
+	suite.Equal(created, got)
+
+	update := &model.WebPushSubscriptionUpdateRequest{}
+	update.Data.Alerts.Mention = true
+	updated, errWithCode := suite.processor.PushSubscriptionUpdate(ctx, authed, update)
+	suite.NoError(errWithCode)
+	suite.Equal(created.ID, updated.ID)
+	suite.False(updated.Alerts.Follow)
+	suite.False(updated.Alerts.Favourite)
+	suite.True(updated.Alerts.Mention)
+
+	// subscribing again replaces the existing subscription
+	replaced := suite.createSubscription(authed, model.WebPushSubscriptionAlerts{})
+	suite.NotEqual(created.ID, replaced.ID)
+
+	errWithCode = suite.processor.PushSubscriptionDelete(ctx, authed)
+	suite.NoError(errWithCode)
+
+	_, errWithCode = suite.processor.PushSubscriptionGet(ctx, authed)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *WebPushTestSuite) TestPushSubscriptionCreateInvalid() {
+	form := &model.WebPushSubscriptionCreateRequest{}
+	form.Subscription.Endpoint = "http://push.example.org/send/abcdef"
+	form.Subscription.Keys.P256dh = testPushP256dh
+	form.Subscription.Keys.Auth = testPushAuth
+
+	_, errWithCode := suite.processor.PushSubscriptionCreate(context.Background(), suite.authedWithToken(), form)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+
+	form.Subscription.Endpoint = testPushEndpoint
+	form.Subscription.Keys.P256dh = "not a key"
+
+	_, errWithCode = suite.processor.PushSubscriptionCreate(context.Background(), suite.authedWithToken(), form)
+	suite.Error(errWithCode)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *WebPushTestSuite) TestPushNotification() {
+	suite.createSubscription(suite.authedWithToken(), model.WebPushSubscriptionAlerts{Favourite: true})
+
+	// admin faves a status of local_account_1, which should be pushed to its subscription
+	suite.faveStatus()
+
+	var sent testrig.SentPush
+	select {
+	case sent = <-suite.sentPushes:
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for push notification")
+	}
+	suite.Equal(testPushEndpoint, sent.Endpoint)
+
+	notification := &model.WebPushNotification{}
+	suite.NoError(json.Unmarshal([]byte(sent.Payload), notification))
+	suite.Equal(suite.testTokens["local_account_1"].Access, notification.AccessToken)
+	suite.Equal("favourite", notification.NotificationType)
+	suite.NotEmpty(notification.NotificationID)
+	suite.Equal("admin favourited your post", notification.Title)
+	// the status has a content warning, which is shown instead of the content
+	suite.Equal("introduction post", notification.Body)
+}
+
+func (suite *WebPushTestSuite) TestPushNotificationAlertDisabled() {
+	suite.createSubscription(suite.authedWithToken(), model.WebPushSubscriptionAlerts{Mention: true})
+
+	suite.faveStatus()
+
+	select {
+	case sent := <-suite.sentPushes:
+		suite.FailNow("unexpected push notification", sent.Payload)
+	case <-time.After(time.Second):
+	}
+}
+
+func (suite *WebPushTestSuite) TestPushNotificationBrokenSubscription() {
+	ctx := context.Background()
+	suite.createSubscription(suite.authedWithToken(), model.WebPushSubscriptionAlerts{Favourite: true})
+
+	// a subscription whose token can't be found shouldn't stop the notification going to the others
+	suite.NoError(suite.db.Put(ctx, &gtsmodel.WebPushSubscription{
+		ID:             "01G1MKFTRR7ZTCMJD6HQ2ZNTJR",
+		AccountID:      suite.testAccounts["local_account_1"].ID,
+		TokenID:        "01G1MKFTRR7ZTCMJD6HQ2ZNTJS",
+		Endpoint:       "https://push.example.org/send/broken",
+		P256dh:         testPushP256dh,
+		Auth:           testPushAuth,
+		AlertFavourite: true,
+	}))
+
+	suite.faveStatus()
+
+	select {
+	case sent := <-suite.sentPushes:
+		suite.Equal(testPushEndpoint, sent.Endpoint)
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out waiting for push notification")
+	}
+}
+
+func TestWebPushTestSuite(t *testing.T) {
+	suite.Run(t, &WebPushTestSuite{})
+}
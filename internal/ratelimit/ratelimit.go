@@ -0,0 +1,128 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package ratelimit limits how many requests can be made by any one domain, IP address, access token, etc.
+package ratelimit
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// MaxKeys is the most keys that a Limiter will keep track of, so that making requests with lots of different keys
+// can't use up memory without limit. Once it's reached, the bucket of the key that was least recently used to make
+// a request is forgotten to make room for a new one. That lets the forgotten key start over with a full bucket, but
+// refusing new keys instead would let anyone with enough keys to hand lock everyone else out.
+const MaxKeys = 4096
+
+// Limiter limits how many requests can be made with any one key. Each key gets a token bucket which
+// holds up to limit tokens, and fills up again at a rate of limit tokens per period, so bursts of requests
+// can go through, but a key that keeps making more requests than that has them refused.
+type Limiter struct {
+	limit   float64       // most tokens a bucket can hold, or 0 for no limit
+	period  time.Duration // how long it takes an empty bucket to fill up
+	mu      sync.Mutex
+	buckets map[string]*list.Element // elements of lru, by key
+	lru     *list.List               // buckets, most recently used first
+}
+
+// bucket is the token bucket for a single key.
+type bucket struct {
+	key    string
+	tokens float64   // tokens left in the bucket, never more than limit
+	last   time.Time // when tokens was last updated
+}
+
+// Result is the outcome of trying to make a request with a Limiter.
+type Result struct {
+	// Allowed is true if the request can go ahead.
+	Allowed bool
+	// Limit is how many requests can be made in a burst, or 0 if requests aren't limited.
+	Limit int
+	// Remaining is how many more requests can be made right away.
+	Remaining int
+	// Reset is when the bucket will be full again, if no more requests are made.
+	Reset time.Time
+	// RetryAfter is how long it'll be until a request is allowed again, if this one wasn't.
+	RetryAfter time.Duration
+}
+
+// New returns a Limiter that lets each key make limit requests every period.
+// If limit is 0 or less, then requests won't be limited at all.
+func New(limit int, period time.Duration) *Limiter {
+	if limit < 0 {
+		limit = 0
+	}
+	return &Limiter{
+		limit:   float64(limit),
+		period:  period,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Allow takes a token from the bucket for the given key, and returns whether there was one to take.
+func (l *Limiter) Allow(key string, now time.Time) Result {
+	if l.limit == 0 {
+		return Result{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(e)
+		b = e.Value.(*bucket)
+	} else {
+		if l.lru.Len() >= MaxKeys {
+			oldest := l.lru.Back()
+			l.lru.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: l.limit, last: now}
+		l.buckets[key] = l.lru.PushFront(b)
+	}
+
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	result := Result{Limit: int(l.limit)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.fillTime(1 - b.tokens)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = now.Add(l.fillTime(l.limit - b.tokens))
+
+	return result
+}
+
+// refill returns the number of tokens that the given bucket would have at the given time.
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.limit, b.tokens+float64(now.Sub(b.last))/float64(l.period)*l.limit)
+}
+
+// fillTime returns how long it takes for the given number of tokens to be added to a bucket.
+func (l *Limiter) fillTime(tokens float64) time.Duration {
+	return time.Duration(tokens / l.limit * float64(l.period))
+}
@@ -0,0 +1,106 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ratelimit_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ratelimit"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RateLimitTestSuite) TestAllow() {
+	limiter := ratelimit.New(3, time.Minute)
+	now := time.Now()
+
+	// the bucket starts full, so a burst can go through
+	for remaining := 2; remaining >= 0; remaining-- {
+		result := limiter.Allow("example.org", now)
+		suite.True(result.Allowed)
+		suite.Equal(3, result.Limit)
+		suite.Equal(remaining, result.Remaining)
+	}
+
+	// but then it's empty, and takes a minute to fill up again
+	result := limiter.Allow("example.org", now)
+	suite.False(result.Allowed)
+	suite.Equal(0, result.Remaining)
+	suite.Equal(20*time.Second, result.RetryAfter)
+	suite.Equal(now.Add(time.Minute), result.Reset)
+
+	// other keys have their own buckets
+	suite.True(limiter.Allow("example.com", now).Allowed)
+
+	// a token is added every 20 seconds
+	result = limiter.Allow("example.org", now.Add(20*time.Second))
+	suite.True(result.Allowed)
+	suite.Equal(0, result.Remaining)
+
+	// and the bucket never holds more than the limit
+	result = limiter.Allow("example.org", now.Add(time.Hour))
+	suite.True(result.Allowed)
+	suite.Equal(2, result.Remaining)
+}
+
+func (suite *RateLimitTestSuite) TestNoLimit() {
+	limiter := ratelimit.New(0, time.Minute)
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		result := limiter.Allow("example.org", now)
+		suite.True(result.Allowed)
+		suite.Zero(result.Limit)
+	}
+}
+
+func (suite *RateLimitTestSuite) TestMaxKeys() {
+	limiter := ratelimit.New(1, time.Hour)
+	now := time.Now()
+
+	flood := func(prefix string, n int, at time.Time) {
+		for i := 0; i < n; i++ {
+			suite.True(limiter.Allow(fmt.Sprintf("%s%d", prefix, i), at).Allowed)
+		}
+	}
+
+	suite.True(limiter.Allow("example.org", now).Allowed)
+	suite.False(limiter.Allow("example.org", now).Allowed)
+
+	// new keys can always make requests, however many other keys there are
+	flood("a", ratelimit.MaxKeys-1, now)
+	suite.True(limiter.Allow("b", now).Allowed)
+
+	// because the bucket of the least recently used key is forgotten to make room
+	suite.True(limiter.Allow("example.org", now).Allowed)
+
+	// but a key that's still being used is kept track of
+	suite.False(limiter.Allow("example.org", now).Allowed)
+	flood("c", ratelimit.MaxKeys-1, now)
+	suite.False(limiter.Allow("example.org", now).Allowed)
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
@@ -0,0 +1,51 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package router
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type connContextKey struct{}
+
+// ConnContext stores the connection that a request came in on in the request context,
+// so that handlers can get at it with ClearWriteDeadline. It's meant to be used as the
+// ConnContext function of an http.Server.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// ClearWriteDeadline removes the write timeout that the server puts on every response from the
+// connection of the given request, for responses that are meant to stay open for a long time,
+// like server-sent events.
+//
+// This only works for HTTP/1.x connections: with HTTP/2 the timeout is kept per stream by the
+// server, and can't be cleared from here.
+func ClearWriteDeadline(c *gin.Context) error {
+	conn, ok := c.Request.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return errors.New("ClearWriteDeadline: no connection in request context")
+	}
+	return conn.SetWriteDeadline(time.Time{})
+}
@@ -24,7 +24,9 @@ import (
 )
 
 func useGzip(engine *gin.Engine) error {
-	gzipMiddleware := ginGzip.Gzip(ginGzip.DefaultCompression)
+	// streamed responses are left alone, since gzip would hold on
+	// to each event until enough of them have come in to compress
+	gzipMiddleware := ginGzip.Gzip(ginGzip.DefaultCompression, ginGzip.WithExcludedPaths([]string{"/api/v1/streaming"}))
 	engine.Use(gzipMiddleware)
 	return nil
 }
@@ -198,6 +198,7 @@ func New(ctx context.Context, db db.DB) (Router, error) {
 		WriteTimeout:      writeTimeout,
 		IdleTimeout:       idleTimeout,
 		ReadHeaderTimeout: readHeaderTimeout,
+		ConnContext:       ConnContext,
 	}
 
 	// We need to spawn the underlying server slightly differently depending on whether lets encrypt is enabled or not.
@@ -0,0 +1,155 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// migrateProgressInterval is the number of objects after which Migrate logs its progress.
+const migrateProgressInterval = 100
+
+// Migrate copies every object in src into dst, using the same keys.
+//
+// Objects that already exist in dst with the same contents are skipped, so if a migration is
+// interrupted, calling Migrate again with the same src and dst will resume where it left off.
+// Objects in dst whose contents differ, eg., because they were only partly written when the
+// migration was interrupted, are replaced.
+//
+// Media URLs stored in the database point to this instance's fileserver rather than
+// to the storage backend itself, so they don't need to be updated after migrating.
+//
+// The number of objects copied, and the number of objects skipped because they
+// were already present in dst, will be returned.
+func Migrate(ctx context.Context, src *Driver, dst *Driver) (int, int, error) {
+	// gather the keys first, so we can report progress against a total
+	keys := []string{}
+	if err := src.WalkKeys(func(key string) error {
+		if !IsInternalKey(key) {
+			keys = append(keys, key)
+		}
+		return nil
+	}); err != nil {
+		return 0, 0, fmt.Errorf("Migrate: error iterating source storage: %s", err)
+	}
+
+	total := len(keys)
+	logrus.Infof("Migrate: found %d objects to migrate", total)
+
+	var copied, skipped int
+	for i, key := range keys {
+		select {
+		case <-ctx.Done():
+			return copied, skipped, ctx.Err()
+		default:
+		}
+
+		exists, err := dst.Has(key)
+		if err != nil {
+			return copied, skipped, fmt.Errorf("Migrate: error checking destination for %s: %s", key, err)
+		}
+
+		if exists {
+			same, err := sameValue(src, dst, key)
+			if err != nil {
+				return copied, skipped, err
+			}
+
+			if same {
+				skipped++
+			} else {
+				// remove what's there first, since not every backend overwrites existing values
+				if err := dst.Delete(key); err != nil && err != storage.ErrNotFound {
+					return copied, skipped, fmt.Errorf("Migrate: error removing mismatched %s from destination: %s", key, err)
+				}
+				exists = false
+			}
+		}
+
+		if !exists {
+			if err := migrateKey(src, dst, key); err != nil {
+				return copied, skipped, err
+			}
+			copied++
+		}
+
+		if done := i + 1; done%migrateProgressInterval == 0 || done == total {
+			logrus.Infof("Migrate: processed %d/%d objects (%d copied, %d already present)", done, total, copied, skipped)
+		}
+	}
+
+	return copied, skipped, nil
+}
+
+// sameValue returns true if the value of key in dst has the same checksum as its value in src.
+func sameValue(src *Driver, dst *Driver, key string) (bool, error) {
+	srcSum, err := checksumKey(src, key)
+	if err != nil {
+		return false, fmt.Errorf("sameValue: error reading %s from source: %s", key, err)
+	}
+
+	dstSum, err := checksumKey(dst, key)
+	if err != nil {
+		return false, fmt.Errorf("sameValue: error reading %s from destination: %s", key, err)
+	}
+
+	return bytes.Equal(srcSum, dstSum), nil
+}
+
+// checksumKey streams the value of key from d through sha256, so that large values don't have to be held in memory.
+func checksumKey(d *Driver, key string) ([]byte, error) {
+	rc, err := d.GetStream(key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// migrateKey streams the value of key from src into dst. If writing fails partway through,
+// it makes a best effort at removing the incomplete value from dst, so that it's copied
+// again when the migration is resumed.
+func migrateKey(src *Driver, dst *Driver, key string) error {
+	rc, err := src.GetStream(key)
+	if err != nil {
+		return fmt.Errorf("migrateKey: error reading %s from source: %s", key, err)
+	}
+	defer rc.Close()
+
+	if err := dst.PutStream(key, rc); err != nil {
+		if deleteErr := dst.Delete(key); deleteErr != nil && deleteErr != storage.ErrNotFound {
+			logrus.Warnf("migrateKey: error removing incomplete %s from destination: %s", key, deleteErr)
+		}
+		return fmt.Errorf("migrateKey: error writing %s to destination: %s", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type MigrateTestSuite struct {
+	suite.Suite
+
+	src *storage.Driver
+	dst *storage.Driver
+}
+
+func (suite *MigrateTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+
+	suite.src = testrig.NewTestStorage()
+	suite.dst = testrig.NewTestStorage()
+}
+
+func (suite *MigrateTestSuite) TestMigrate() {
+	ctx := context.Background()
+
+	values := map[string][]byte{
+		"01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpeg": []byte("original"),
+		"01F8MH17FWEB39HZJ76B6VXSKF/attachment/small/01F8MH6NEM8D7527KZAECTCR76.jpeg":    []byte("small"),
+		"01F8MH17FWEB39HZJ76B6VXSKF/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png":       []byte("emoji"),
+	}
+	for k, v := range values {
+		suite.NoError(suite.src.Put(k, v))
+	}
+
+	// pretend a previous migration already got as far as the emoji
+	suite.NoError(suite.dst.Put("01F8MH17FWEB39HZJ76B6VXSKF/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png", []byte("emoji")))
+
+	copied, skipped, err := storage.Migrate(ctx, suite.src, suite.dst)
+	suite.NoError(err)
+	suite.Equal(2, copied)
+	suite.Equal(1, skipped)
+
+	for k, v := range values {
+		b, err := suite.dst.Get(k)
+		suite.NoError(err)
+		suite.Equal(v, b)
+	}
+
+	// running again should copy nothing new
+	copied, skipped, err = storage.Migrate(ctx, suite.src, suite.dst)
+	suite.NoError(err)
+	suite.Equal(0, copied)
+	suite.Equal(3, skipped)
+}
+
+func (suite *MigrateTestSuite) TestMigrateReplacesPartialValues() {
+	ctx := context.Background()
+
+	key := "01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpeg"
+	suite.NoError(suite.src.Put(key, []byte("original")))
+
+	// a previous migration was interrupted while it was writing the value
+	suite.NoError(suite.dst.Put(key, []byte("orig")))
+
+	copied, skipped, err := storage.Migrate(ctx, suite.src, suite.dst)
+	suite.NoError(err)
+	suite.Equal(1, copied)
+	suite.Equal(0, skipped)
+
+	b, err := suite.dst.Get(key)
+	suite.NoError(err)
+	suite.Equal([]byte("original"), b)
+}
+
+func (suite *MigrateTestSuite) TestMigrateCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.NoError(suite.src.Put("some/key.jpeg", []byte("value")))
+
+	copied, _, err := storage.Migrate(ctx, suite.src, suite.dst)
+	suite.ErrorIs(err, context.Canceled)
+	suite.Zero(copied)
+}
+
+func TestMigrateTestSuite(t *testing.T) {
+	suite.Run(t, &MigrateTestSuite{})
+}
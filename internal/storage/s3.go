@@ -0,0 +1,195 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3PartSize is the size of each part when streaming an object of unknown
+// size into s3. This is the minimum size permitted by the s3 api; leaving it
+// unset makes the minio client allocate enormous buffers for each upload.
+const s3PartSize = 5 * 1024 * 1024
+
+// S3 implements storage.Storage by storing values as objects in an s3-compatible bucket.
+type S3 struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 returns a new S3 storage for the given bucket, using the given endpoint and credentials.
+// The bucket must already exist.
+func NewS3(endpoint string, accessKey string, secretKey string, bucket string, useSSL bool) (*S3, error) {
+	if endpoint == "" {
+		return nil, errors.New("NewS3: no s3 endpoint set")
+	}
+
+	if bucket == "" {
+		return nil, errors.New("NewS3: no s3 bucket set")
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("NewS3: error creating s3 client: %s", err)
+	}
+
+	exists, err := client.BucketExists(context.Background(), bucket)
+	if err != nil {
+		return nil, fmt.Errorf("NewS3: error checking s3 bucket %s: %s", bucket, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("NewS3: s3 bucket %s does not exist", bucket)
+	}
+
+	return &S3{
+		client: client,
+		bucket: bucket,
+	}, nil
+}
+
+// PresignedURL returns a URL at which the object with the given key can be fetched
+// directly from s3 without any further authentication, until the given expiry has passed.
+func (s *S3) PresignedURL(ctx context.Context, key string, expiry time.Duration) (*url.URL, error) {
+	return s.client.PresignedGetObject(ctx, s.bucket, key, expiry, url.Values{})
+}
+
+func (s *S3) ReadBytes(key string) ([]byte, error) {
+	rc, err := s.ReadStream(key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (s *S3) ReadStream(key string) (io.ReadCloser, error) {
+	return s.ReadSeekStream(key)
+}
+
+// ReadSeekStream returns a stream of the object with the given key that can be seeked through.
+// Reading after a seek fetches only the requested part of the object from s3, starting at the
+// new offset, so the parts of the object before it don't need to be fetched.
+func (s *S3) ReadSeekStream(key string) (io.ReadSeekCloser, error) {
+	obj, err := s.client.GetObject(context.Background(), s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, processS3Error(err)
+	}
+
+	// GetObject is lazy, so stat the object to make sure it's actually there
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, processS3Error(err)
+	}
+
+	return obj, nil
+}
+
+func (s *S3) WriteBytes(key string, value []byte) error {
+	if _, err := s.client.PutObject(context.Background(), s.bucket, key, bytes.NewReader(value), int64(len(value)), minio.PutObjectOptions{}); err != nil {
+		return processS3Error(err)
+	}
+	return nil
+}
+
+func (s *S3) WriteStream(key string, r io.Reader) error {
+	if _, err := s.client.PutObject(context.Background(), s.bucket, key, r, -1, minio.PutObjectOptions{PartSize: s3PartSize}); err != nil {
+		return processS3Error(err)
+	}
+	return nil
+}
+
+func (s *S3) Stat(key string) (bool, error) {
+	if _, err := s.client.StatObject(context.Background(), s.bucket, key, minio.StatObjectOptions{}); err != nil {
+		if err := processS3Error(err); err != storage.ErrNotFound {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *S3) Remove(key string) error {
+	// s3 silently succeeds when removing objects that don't exist,
+	// but callers expect ErrNotFound, so check first
+	exists, err := s.Stat(key)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrNotFound
+	}
+
+	if err := s.client.RemoveObject(context.Background(), s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return processS3Error(err)
+	}
+	return nil
+}
+
+func (s *S3) Close() error {
+	// nothing to close or unlock
+	return nil
+}
+
+func (s *S3) Clean() error {
+	// s3 has no directories to unclutter
+	return nil
+}
+
+func (s *S3) WalkKeys(opts storage.WalkKeysOptions) error {
+	for obj := range s.client.ListObjects(context.Background(), s.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if obj.Err != nil {
+			return processS3Error(obj.Err)
+		}
+		opts.WalkFn(s3Entry(obj.Key))
+	}
+	return nil
+}
+
+// s3Entry implements storage.StorageEntry for an object key.
+type s3Entry string
+
+func (e s3Entry) Key() string {
+	return string(e)
+}
+
+// processS3Error converts s3 'not found' errors into storage.ErrNotFound,
+// so that callers can treat all storage backends in the same way.
+func processS3Error(err error) error {
+	resp := minio.ToErrorResponse(err)
+	if resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey" {
+		return storage.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,330 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package storage provides the storage backends that GoToSocial can use
+// to store media files, wrapped in a Driver for convenient access.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"codeberg.org/gruf/go-store/kv"
+	"codeberg.org/gruf/go-store/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+// Storage backend names, as set by config.Keys.StorageBackend.
+const (
+	BackendLocal = "local" // BackendLocal stores files on local disk, under config.Keys.StorageLocalBasePath
+	BackendS3    = "s3"    // BackendS3 stores files in an s3-compatible bucket
+)
+
+// lockFileName is the name of the lockfile that's placed in the root of local storage.
+const lockFileName = "store.lock"
+
+// presignedURLExpiry is how long pre-signed URLs returned by Driver.URL remain valid for.
+const presignedURLExpiry = 1 * time.Hour
+
+// Storage is implemented by storage backends. Keys are slash-separated paths that are set by GoToSocial,
+// never by users. When a key isn't in storage, backends should return storage.ErrNotFound from the
+// codeberg.org/gruf/go-store/storage package, so that callers can treat all backends in the same way.
+type Storage interface {
+	// Get returns the value for the given key.
+	Get(key string) ([]byte, error)
+	// GetStream returns a stream of the value for the given key.
+	GetStream(key string) (io.ReadCloser, error)
+	// Put stores the given value under the given key, replacing any value that's already there.
+	Put(key string, value []byte) error
+	// PutStream stores everything read from r under the given key, replacing any value that's already there.
+	PutStream(key string, r io.Reader) error
+	// Has returns true if there's a value stored under the given key.
+	Has(key string) (bool, error)
+	// Delete removes the value for the given key.
+	Delete(key string) error
+	// WalkKeys calls fn with every key in storage, stopping at the first error that fn returns.
+	// fn may add or remove values while the keys are being walked.
+	WalkKeys(fn func(key string) error) error
+	// URL returns a short-lived URL at which the value for the given key can be fetched directly from
+	// the backend, so that it doesn't have to be proxied through GoToSocial. Backends that don't support
+	// this, or that have it disabled by config, should return nil and no error.
+	URL(ctx context.Context, key string) (*url.URL, error)
+	// Close releases any resources held by the backend, such as lockfiles.
+	Close() error
+}
+
+// SeekableStorage is implemented by storage backends that can return streams that can be seeked
+// through, without having to read the whole value first.
+type SeekableStorage interface {
+	Storage
+	// GetSeekable returns a stream of the value for the given key that can be seeked through.
+	GetSeekable(key string) (io.ReadSeekCloser, error)
+}
+
+// OpenFunc opens a storage backend, using the values currently set in viper to configure it.
+type OpenFunc func() (Storage, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]OpenFunc{}
+)
+
+func init() {
+	Register(BackendLocal, openLocal)
+	Register(BackendS3, openS3)
+}
+
+// Register makes a storage backend available under the given name, so that it can be opened with Open,
+// and selected with config.Keys.StorageBackend. It's meant to be called from the init function of the
+// package that provides the backend. Register panics if it's called twice with the same name, or if
+// open is nil.
+func Register(name string, open OpenFunc) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if open == nil {
+		panic("storage: Register open func is nil for backend " + name)
+	}
+
+	if _, registered := backends[name]; registered {
+		panic("storage: Register called twice for backend " + name)
+	}
+
+	backends[name] = open
+}
+
+// Backends returns the names of all registered storage backends, in alphabetical order.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Driver wraps a storage backend, so that stored values can be accessed in the same way
+// regardless of backend, while still making use of functionality that only some backends support.
+type Driver struct {
+	Storage
+}
+
+// NewDriver returns a Driver for the given storage backend.
+func NewDriver(s Storage) *Driver {
+	return &Driver{Storage: s}
+}
+
+// URL returns a short-lived URL at which the value for the given key can be fetched directly
+// from the storage backend, so that it doesn't have to be proxied through GoToSocial.
+//
+// If the backend doesn't support this, or it has been disabled by config, then nil is returned,
+// and the caller should stream the value from storage instead.
+func (d *Driver) URL(ctx context.Context, key string) *url.URL {
+	u, err := d.Storage.URL(ctx, key)
+	if err != nil {
+		// not fatal, the caller can still fall back to proxying
+		logrus.Errorf("URL: error getting url for %s: %s", key, err)
+		return nil
+	}
+
+	return u
+}
+
+// GetSeekable returns a stream of the value for the given key that can be seeked through, so that
+// part of the value can be read without reading everything before it, eg., to serve a range request.
+//
+// Values in backends that implement SeekableStorage are read directly from the backend. For any other
+// backend, the whole value is read into memory first, so this should only be used when seeking is needed.
+func (d *Driver) GetSeekable(key string) (io.ReadSeekCloser, error) {
+	if seekable, ok := d.Storage.(SeekableStorage); ok {
+		return seekable.GetSeekable(key)
+	}
+
+	b, err := d.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return nopSeekCloser{bytes.NewReader(b)}, nil
+}
+
+// nopSeekCloser adds a no-op Close method to a ReadSeeker that doesn't need closing.
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error {
+	return nil
+}
+
+// KVStorage implements Storage using a *kv.KVStore, so that any
+// codeberg.org/gruf/go-store storage can be used as a storage backend.
+type KVStorage struct {
+	*kv.KVStore
+}
+
+// WalkKeys gathers up all the keys before calling fn with any of them, so that the
+// store isn't locked while fn runs.
+func (s *KVStorage) WalkKeys(fn func(key string) error) error {
+	iter, err := s.Iterator(nil)
+	if err != nil {
+		return err
+	}
+	keys := []string{}
+	for iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Release()
+
+	for _, key := range keys {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// URL always returns nil, since a kv store has no way of serving values itself.
+func (s *KVStorage) URL(ctx context.Context, key string) (*url.URL, error) {
+	return nil, nil
+}
+
+// Open opens the storage backend registered under the given name, using the values
+// currently set in viper to configure it, and returns a Driver for it.
+func Open(backend string) (*Driver, error) {
+	backendsMu.RLock()
+	open, ok := backends[backend]
+	backendsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("storage backend %s not recognised", backend)
+	}
+
+	s, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDriver(s), nil
+}
+
+// IsInternalKey returns true if the given key is used by a storage
+// backend for its own purposes (eg., a lockfile), and so doesn't refer
+// to media that GoToSocial has stored.
+func IsInternalKey(key string) bool {
+	return key == lockFileName
+}
+
+// localStorage stores values as files on local disk.
+type localStorage struct {
+	*KVStorage
+
+	// path is the directory that files are stored in.
+	path string
+}
+
+// GetSeekable opens the file for the given key directly rather than through the kv store,
+// since streams from the kv store can't be seeked through.
+func (s *localStorage) GetSeekable(key string) (io.ReadSeekCloser, error) {
+	// keys are set by GtS and not the user, but make sure we never leave the storage dir all the same
+	filePath := filepath.Join(s.path, filepath.FromSlash(key))
+	if !strings.HasPrefix(filePath, filepath.Clean(s.path)+string(filepath.Separator)) {
+		return nil, storage.ErrInvalidKey
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, storage.ErrNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func openLocal() (Storage, error) {
+	basePath := viper.GetString(config.Keys.StorageLocalBasePath)
+	store, err := kv.OpenFile(basePath, &storage.DiskConfig{
+		// Put the store lockfile in the storage dir itself.
+		// Normally this would not be safe, since we could end up
+		// overwriting the lockfile if we store a file called 'store.lock'.
+		// However, in this case it's OK because the keys are set by
+		// GtS and not the user, so we know we're never going to overwrite it.
+		LockFile: path.Join(basePath, lockFileName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &localStorage{KVStorage: &KVStorage{KVStore: store}, path: basePath}, nil
+}
+
+// s3Storage stores values as objects in an s3-compatible bucket.
+type s3Storage struct {
+	*KVStorage
+	s3 *S3
+}
+
+// URL returns a pre-signed URL for the object with the given key, unless
+// config.Keys.StorageS3Proxy is set, in which case it returns nil.
+func (s *s3Storage) URL(ctx context.Context, key string) (*url.URL, error) {
+	if viper.GetBool(config.Keys.StorageS3Proxy) {
+		return nil, nil
+	}
+	return s.s3.PresignedURL(ctx, key, presignedURLExpiry)
+}
+
+func (s *s3Storage) GetSeekable(key string) (io.ReadSeekCloser, error) {
+	return s.s3.ReadSeekStream(key)
+}
+
+func openS3() (Storage, error) {
+	s3, err := NewS3(
+		viper.GetString(config.Keys.StorageS3Endpoint),
+		viper.GetString(config.Keys.StorageS3AccessKey),
+		viper.GetString(config.Keys.StorageS3SecretKey),
+		viper.GetString(config.Keys.StorageS3BucketName),
+		viper.GetBool(config.Keys.StorageS3UseSSL),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := kv.OpenStorage(s3)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Storage{KVStorage: &KVStorage{KVStore: store}, s3: s3}, nil
+}
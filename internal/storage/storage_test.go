@@ -0,0 +1,142 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package storage_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"codeberg.org/gruf/go-store/kv"
+	gostorage "codeberg.org/gruf/go-store/storage"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/storage"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type StorageTestSuite struct {
+	suite.Suite
+}
+
+func (suite *StorageTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+}
+
+func (suite *StorageTestSuite) TestURLNonS3() {
+	// storage that isn't backed by s3 can't give out urls, so content should always be streamed
+	storage := testrig.NewTestStorage()
+	suite.Nil(storage.URL(context.Background(), "01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.jpeg"))
+}
+
+func (suite *StorageTestSuite) TestGetSeekableNonLocal() {
+	s := testrig.NewTestStorage()
+	suite.NoError(s.Put("some/video.mp4", []byte("0123456789")))
+
+	rsc, err := s.GetSeekable("some/video.mp4")
+	suite.NoError(err)
+	defer rsc.Close()
+
+	_, err = rsc.Seek(4, io.SeekStart)
+	suite.NoError(err)
+
+	b, err := io.ReadAll(rsc)
+	suite.NoError(err)
+	suite.Equal("456789", string(b))
+
+	_, err = s.GetSeekable("some/other/video.mp4")
+	suite.ErrorIs(err, gostorage.ErrNotFound)
+}
+
+func (suite *StorageTestSuite) TestGetSeekableLocal() {
+	viper.Set(config.Keys.StorageLocalBasePath, suite.T().TempDir())
+
+	s, err := storage.Open(storage.BackendLocal)
+	suite.NoError(err)
+	defer s.Close()
+
+	suite.NoError(s.Put("some/video.mp4", []byte("0123456789")))
+
+	rsc, err := s.GetSeekable("some/video.mp4")
+	suite.NoError(err)
+	defer rsc.Close()
+
+	_, err = rsc.Seek(-3, io.SeekEnd)
+	suite.NoError(err)
+
+	b, err := io.ReadAll(rsc)
+	suite.NoError(err)
+	suite.Equal("789", string(b))
+
+	_, err = s.GetSeekable("some/other/video.mp4")
+	suite.ErrorIs(err, gostorage.ErrNotFound)
+
+	_, err = s.GetSeekable("../../etc/passwd")
+	suite.Error(err)
+}
+
+func (suite *StorageTestSuite) TestRegisterBackend() {
+	kvstore, err := kv.OpenStorage(gostorage.OpenMemory(10, false))
+	suite.NoError(err)
+	storage.Register("test-memory", func() (storage.Storage, error) {
+		return &storage.KVStorage{KVStore: kvstore}, nil
+	})
+	suite.Contains(storage.Backends(), "test-memory")
+
+	s, err := storage.Open("test-memory")
+	suite.NoError(err)
+	suite.NoError(s.Put("some/file.txt", []byte("hello")))
+
+	b, err := kvstore.Get("some/file.txt")
+	suite.NoError(err)
+	suite.Equal("hello", string(b))
+
+	// registering the same name twice is a programming error
+	suite.Panics(func() {
+		storage.Register("test-memory", func() (storage.Storage, error) { return nil, nil })
+	})
+	suite.Panics(func() {
+		storage.Register(storage.BackendLocal, func() (storage.Storage, error) { return nil, nil })
+	})
+
+	_, err = storage.Open("not-registered")
+	suite.EqualError(err, "storage backend not-registered not recognised")
+}
+
+func (suite *StorageTestSuite) TestWalkKeys() {
+	s := testrig.NewTestStorage()
+	suite.NoError(s.Put("a", []byte("a")))
+	suite.NoError(s.Put("b", []byte("b")))
+
+	// values can be removed while walking
+	suite.NoError(s.WalkKeys(s.Delete))
+
+	keys := []string{}
+	suite.NoError(s.WalkKeys(func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}))
+	suite.Empty(keys)
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, &StorageTestSuite{})
+}
@@ -1,6 +1,10 @@
 package stream
 
-import "sync"
+import (
+	"errors"
+	"strings"
+	"sync"
+)
 
 const (
 	// EventTypeNotification -- a user should be shown a notification
@@ -9,6 +13,8 @@ const (
 	EventTypeUpdate string = "update"
 	// EventTypeDelete -- something should be deleted from a user
 	EventTypeDelete string = "delete"
+	// EventTypeConversation -- a conversation of a user has a new status in it
+	EventTypeConversation string = "conversation"
 )
 
 const (
@@ -22,6 +28,12 @@ const (
 	TimelineNotifications string = "user:notification"
 	// TimelineDirect -- statuses sent to a user directly.
 	TimelineDirect string = "direct"
+	// TimelineHashtag -- public statuses with a given hashtag, including federated ones.
+	TimelineHashtag string = "hashtag"
+	// TimelineHashtagLocal -- public statuses with a given hashtag from the LOCAL timeline.
+	TimelineHashtagLocal string = "hashtag:local"
+	// TimelineList -- statuses for a list of accounts.
+	TimelineList string = "list"
 )
 
 // AllStatusTimelines contains all Timelines that a status could conceivably be delivered to -- useful for doing deletes.
@@ -30,6 +42,32 @@ var AllStatusTimelines = []string{
 	TimelinePublic,
 	TimelineHome,
 	TimelineDirect,
+	TimelineHashtag,
+	TimelineHashtagLocal,
+}
+
+// Names returns the stream names that identify the given timeline in streamed messages,
+// eg., ["user"] or ["hashtag", "gotosocial"].
+//
+// A tag must be given for the hashtag timelines, and is ignored otherwise.
+// An error will be returned if the timeline is not one we know how to stream.
+func Names(timeline string, tag string) ([]string, error) {
+	switch timeline {
+	case TimelineLocal, TimelinePublic, TimelineHome, TimelineNotifications, TimelineDirect:
+		return []string{timeline}, nil
+	case TimelineHashtag, TimelineHashtagLocal:
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" {
+			return nil, errors.New("no tag provided for hashtag stream")
+		}
+		return []string{timeline, tag}, nil
+	case TimelineList:
+		// this instance has no lists for a stream to follow
+		return nil, errors.New("list streams are not supported")
+	case "":
+		return nil, errors.New("no stream type provided")
+	}
+	return nil, errors.New("unknown stream type")
 }
 
 // StreamsForAccount is a wrapper for the multiple streams that one account can have running at the same time.
@@ -45,8 +83,8 @@ type StreamsForAccount struct {
 type Stream struct {
 	// ID of this stream, generated during creation.
 	ID string
-	// Timeline of this stream: user/public/etc
-	Timeline string
+	// Timelines this stream is subscribed to, as returned by Names, keyed by the names joined with a space.
+	Timelines map[string][]string
 	// Channel of messages for the client to read from
 	Messages chan *Message
 	// Channel to close when the client drops away
@@ -57,6 +95,41 @@ type Stream struct {
 	sync.Mutex
 }
 
+// Subscribe adds the timeline with the given names to the stream.
+// The caller should hold the lock on the stream.
+func (s *Stream) Subscribe(names []string) {
+	if s.Timelines == nil {
+		s.Timelines = make(map[string][]string)
+	}
+	s.Timelines[strings.Join(names, " ")] = names
+}
+
+// Unsubscribe removes the timeline with the given names from the stream.
+// The caller should hold the lock on the stream.
+func (s *Stream) Unsubscribe(names []string) {
+	delete(s.Timelines, strings.Join(names, " "))
+}
+
+// Subscribed returns true if the stream is subscribed to the timeline with the given names.
+// The caller should hold the lock on the stream.
+func (s *Stream) Subscribed(names []string) bool {
+	_, ok := s.Timelines[strings.Join(names, " ")]
+	return ok
+}
+
+// Send puts the given message in the stream without waiting, and returns false if it couldn't, because the
+// client isn't reading messages fast enough to keep space in the stream for more. Messages that can't be sent
+// are dropped, so that a slow client can't hold up streaming to everybody else.
+// The caller should hold the lock on the stream, and check that it's connected.
+func (s *Stream) Send(msg *Message) bool {
+	select {
+	case s.Messages <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
 // Message represents one streamed message.
 type Message struct {
 	// All the stream types this message should be delivered to.
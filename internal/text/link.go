@@ -22,7 +22,10 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
+	"unicode/utf8"
 
+	"golang.org/x/net/html"
 	"mvdan.cc/xurls/v2"
 )
 
@@ -30,6 +33,10 @@ import (
 // Basically, we accept https or http.
 var schemes = `(((http|https))://)`
 
+// CharactersReservedPerURL is how many characters each link in a status counts as towards the length limit
+// of statuses, however long the link really is. It's the same as on Mastodon, so that clients count the same way.
+const CharactersReservedPerURL = 23
+
 // FindLinks parses the given string looking for recognizable URLs (including scheme).
 // It returns a list of those URLs, without changing the string, or an error if something goes wrong.
 // If no URLs are found within the given string, an empty slice and nil will be returned.
@@ -69,6 +76,125 @@ func FindLinks(in string) ([]*url.URL, error) {
 	return urlsDeduped, nil
 }
 
+// CountCharacters returns how many characters the given text counts as towards the length limit of statuses.
+// Characters are counted rather than bytes, and each link counts as CharactersReservedPerURL characters.
+func CountCharacters(in string) int {
+	rxStrict, err := xurls.StrictMatchingScheme(schemes)
+	if err != nil {
+		panic(err)
+	}
+
+	count := utf8.RuneCountInString(in)
+	for _, link := range rxStrict.FindAllString(in, -1) {
+		count += CharactersReservedPerURL - utf8.RuneCountInString(link)
+	}
+	return count
+}
+
+// FindHTMLLinks parses the given html looking for links to web pages, ie., the http or https hrefs of anchors.
+// Links that are mentions of accounts or hashtags are left out, since they point to profiles and tag pages
+// rather than to anything that was linked on purpose. The returned URLs are deduplicated.
+func FindHTMLLinks(in string) []*url.URL {
+	urls := []*url.URL{}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(in))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// either we're at the end of the html or it's broken, either way we're done
+			return urls
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			if token.Data != "a" {
+				continue
+			}
+
+			var href string
+			var isMention bool
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "href":
+					href = attr.Val
+				case "class":
+					for _, class := range strings.Fields(attr.Val) {
+						if class == "mention" || class == "hashtag" {
+							isMention = true
+						}
+					}
+				case "rel":
+					for _, rel := range strings.Fields(attr.Val) {
+						if rel == "tag" {
+							isMention = true
+						}
+					}
+				}
+			}
+
+			if href == "" || isMention {
+				continue
+			}
+
+			u, err := url.Parse(href)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				continue
+			}
+
+			if !contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+}
+
+// FindRelMeLinks parses the given html looking for links that have a rel of "me", ie., links from a
+// web page to other profiles of the person it belongs to. Both anchors and link elements in the head are
+// checked, since pages often only have the latter. The returned URLs are deduplicated.
+func FindRelMeLinks(in string) []*url.URL {
+	urls := []*url.URL{}
+
+	tokenizer := html.NewTokenizer(strings.NewReader(in))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// either we're at the end of the html or it's broken, either way we're done
+			return urls
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data != "a" && token.Data != "link" {
+				continue
+			}
+
+			var href string
+			var isMe bool
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "href":
+					href = attr.Val
+				case "rel":
+					for _, rel := range strings.Fields(attr.Val) {
+						if strings.EqualFold(rel, "me") {
+							isMe = true
+						}
+					}
+				}
+			}
+
+			if href == "" || !isMe {
+				continue
+			}
+
+			u, err := url.Parse(href)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				continue
+			}
+
+			if !contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+}
+
 // contains checks if the given url is already within a slice of URLs
 func contains(urls []*url.URL, url *url.URL) bool {
 	for _, u := range urls {
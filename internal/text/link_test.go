@@ -65,6 +65,9 @@ what happens when we already have a link within an href?
 <a href="https://example.org">https://example.org</a>
 `
 
+const html1 = `<p>hey <span class="h-card"><a href="https://example.org/@someone" class="u-url mention">@<span>someone</span></a></span> check this out <a href="https://example.org/some/article" rel="noopener">example.org/some/article</a> <a href="https://example.org/tags/news" class="mention hashtag" rel="tag">#<span>news</span></a></p><p>again: <a href="https://example.org/some/article">https://example.org/some/article</a> and <a href="mailto:whatever@test.org">email me</a></p>`
+const html2 = `<html><head><link rel="me" href="https://example.org/@someone"><link rel="stylesheet" href="https://example.org/style.css"></head><body><a href="https://example.org/some/article">read this</a> <a rel="me nofollow" href="https://another.example.org/users/someone">fedi</a> <a rel="me" href="https://example.org/@someone">@someone</a> <a rel="me" href="mailto:someone@example.org">email</a></body></html>`
+
 type LinkTestSuite struct {
 	TextStandardTestSuite
 }
@@ -101,6 +104,35 @@ func (suite *LinkTestSuite) TestParseURLsFromText3() {
 	assert.Len(suite.T(), urls, 0)
 }
 
+func (suite *LinkTestSuite) TestCountCharacters() {
+	// characters are counted rather than bytes
+	assert.Equal(suite.T(), 6, text.CountCharacters("héllo!"))
+	assert.Equal(suite.T(), 2, text.CountCharacters("日本"))
+
+	// links count as 23 characters, however long they are
+	assert.Equal(suite.T(), 4+23, text.CountCharacters("see https://example.org/some/really/long/path/to/an/article?with=a&query=string"))
+	assert.Equal(suite.T(), 23+5+23, text.CountCharacters("http://a.org and https://example.org/b"))
+}
+
+func (suite *LinkTestSuite) TestFindHTMLLinks() {
+	urls := text.FindHTMLLinks(html1)
+
+	// mentions, hashtags and mailto links are left out, and the article is deduplicated
+	if assert.Len(suite.T(), urls, 1) {
+		assert.Equal(suite.T(), "https://example.org/some/article", urls[0].String())
+	}
+}
+
+func (suite *LinkTestSuite) TestFindRelMeLinks() {
+	urls := text.FindRelMeLinks(html2)
+
+	// links without rel=me and mailto links are left out, and the profile is deduplicated
+	if assert.Len(suite.T(), urls, 2) {
+		assert.Equal(suite.T(), "https://example.org/@someone", urls[0].String())
+		assert.Equal(suite.T(), "https://another.example.org/users/someone", urls[1].String())
+	}
+}
+
 func (suite *LinkTestSuite) TestReplaceLinksFromText1() {
 	replaced := suite.formatter.ReplaceLinks(context.Background(), text1)
 	assert.Equal(suite.T(), `
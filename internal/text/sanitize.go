@@ -19,7 +19,9 @@
 package text
 
 import (
+	"html"
 	"regexp"
+	"strings"
 
 	"github.com/microcosm-cc/bluemonday"
 )
@@ -46,6 +48,10 @@ var regular *bluemonday.Policy = bluemonday.UGCPolicy().
 // Source: https://github.com/microcosm-cc/bluemonday#usage
 var strict *bluemonday.Policy = bluemonday.StrictPolicy()
 
+// searchable is like strict, but puts a space where each element was, so that
+// words either side of a tag like <br/> aren't run together.
+var searchable *bluemonday.Policy = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+
 // SanitizeHTML cleans up HTML in the given string, allowing through only safe HTML elements.
 func SanitizeHTML(in string) string {
 	return regular.Sanitize(in)
@@ -55,3 +61,9 @@ func SanitizeHTML(in string) string {
 func RemoveHTML(in string) string {
 	return strict.Sanitize(in)
 }
+
+// SearchableText removes all HTML from the given string and unescapes any entities,
+// leaving plain words separated by single spaces, suitable for full-text indexing.
+func SearchableText(in string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(searchable.Sanitize(in))), " ")
+}
@@ -29,6 +29,9 @@ const (
 	removeHTML  = `<p>Another test <span class="h-card"><a href="http://fossbros-anonymous.io/@foss_satan" class="u-url mention" rel="nofollow noreferrer noopener" target="_blank">@<span>foss_satan</span></a></span><br/><br/><a href="http://localhost:8080/tags/Hashtag" class="mention hashtag" rel="tag nofollow noreferrer noopener" target="_blank">#<span>Hashtag</span></a><br/><br/>Text</p>`
 	removedHTML = `Another test @foss_satan#HashtagText`
 
+	searchableHTML = `<p>Another test <span class="h-card"><a href="http://fossbros-anonymous.io/@foss_satan" class="u-url mention" rel="nofollow noreferrer noopener" target="_blank">@<span>foss_satan</span></a></span><br/><br/><a href="http://localhost:8080/tags/Hashtag" class="mention hashtag" rel="tag nofollow noreferrer noopener" target="_blank">#<span>Hashtag</span></a><br/><br/>it&#39;s &lt;text&gt;</p>`
+	searchableText = `Another test @ foss_satan # Hashtag it's <text>`
+
 	sanitizeHTML  = `here's some naughty html: <script>alert(ahhhh)</script> !!!`
 	sanitizedHTML = `here&#39;s some naughty html:  !!!`
 
@@ -50,6 +53,11 @@ func (suite *SanitizeTestSuite) TestRemoveHTML() {
 	suite.Equal(removedHTML, s)
 }
 
+func (suite *SanitizeTestSuite) TestSearchableText() {
+	s := text.SearchableText(searchableHTML)
+	suite.Equal(searchableText, s)
+}
+
 func (suite *SanitizeTestSuite) TestSanitizeOutgoing() {
 	s := text.SanitizeHTML(sanitizeOutgoing)
 	suite.Equal(sanitizedOutgoing, s)
@@ -85,19 +85,26 @@ func (t *timeline) Get(ctx context.Context, amount int, maxID string, sinceID st
 	// maxID is defined and sinceID || minID are as well, so take a slice between them
 	// this is equivalent to a user asking for items older than x but newer than y
 	if maxID != "" && sinceID != "" {
-		items, err = t.GetXBetweenID(ctx, amount, maxID, minID)
+		items, err = t.GetXBetweenID(ctx, amount, maxID, sinceID)
 	}
 	if maxID != "" && minID != "" {
 		items, err = t.GetXBetweenID(ctx, amount, maxID, minID)
 	}
 
-	// maxID isn't defined, but sinceID || minID are, so take x before
-	// this is equivalent to a user asking for items newer than x (eg., refreshing the top of their timeline)
+	// maxID isn't defined, but sinceID is, so take x before starting from the top
+	// this is equivalent to a user asking for the newest items newer than x (eg., refreshing the top of their timeline)
 	if maxID == "" && sinceID != "" {
 		items, err = t.GetXBeforeID(ctx, amount, sinceID, true)
 	}
+
+	// maxID isn't defined, but minID is, so take the x items right before it
+	// this is equivalent to a user paging up through their timeline without leaving gaps
 	if maxID == "" && minID != "" {
-		items, err = t.GetXBeforeID(ctx, amount, minID, true)
+		items, err = t.GetXBeforeID(ctx, amount, minID, false)
+		// these are served from the bottom up, so flip them back to newest first
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
 	}
 
 	return items, err
@@ -265,7 +272,7 @@ findMarkLoop:
 				return nil, errors.New("GetXBeforeID: could not parse e as a preparedPostsEntry")
 			}
 
-			if entry.itemID == beforeID {
+			if entry.itemID <= beforeID {
 				break serveloopFromTop
 			}
 
@@ -277,9 +284,14 @@ findMarkLoop:
 			}
 		}
 	} else if !startFromTop {
-		// start serving from the entry right before the mark
+		// start serving from the entry right before the mark, or from the mark itself if
+		// it's not the item with beforeID, since it's then the oldest item newer than beforeID
+		start := beforeIDMark
+		if entry, ok := start.Value.(*preparedItemsEntry); ok && entry.itemID == beforeID {
+			start = start.Prev()
+		}
 	serveloopFromBottom:
-		for e := beforeIDMark.Prev(); e != nil; e = e.Prev() {
+		for e := start; e != nil; e = e.Prev() {
 			entry, ok := e.Value.(*preparedItemsEntry)
 			if !ok {
 				return nil, errors.New("GetXBeforeID: could not parse e as a preparedPostsEntry")
@@ -343,7 +355,7 @@ serveloop:
 			return nil, errors.New("GetXBetweenID: could not parse e as a preparedPostsEntry")
 		}
 
-		if entry.itemID == beforeID {
+		if entry.itemID <= beforeID {
 			break serveloop
 		}
 
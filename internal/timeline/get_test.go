@@ -192,6 +192,27 @@ func (suite *GetTestSuite) TestGetMinID() {
 	}
 }
 
+func (suite *GetTestSuite) TestGetMinIDPagesUp() {
+	// get everything newer than the 'middle' id, newest first
+	newer, err := suite.timeline.Get(context.Background(), 20, "", "01F8MHBQCBTDKN6X5VHGMMN4MA", "", false)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(newer, 8)
+
+	// ask for 3 with the 'middle' id as min ID
+	statuses, err := suite.timeline.Get(context.Background(), 3, "", "", "01F8MHBQCBTDKN6X5VHGMMN4MA", false)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// we should get the 3 statuses right after the min ID rather than the 3 newest, still sorted highest to lowest ID
+	suite.Len(statuses, 3)
+	for i, s := range statuses {
+		suite.Equal(newer[len(newer)-3+i].GetID(), s.GetID())
+	}
+}
+
 func (suite *GetTestSuite) TestGetSinceID() {
 	// ask for 10 with a since ID somewhere in the middle of the stack
 	statuses, err := suite.timeline.Get(context.Background(), 10, "", "", "01F8MHBQCBTDKN6X5VHGMMN4MA", false)
@@ -65,7 +65,11 @@ grabloop:
 			if shouldIndex {
 				toIndex = append(toIndex, item)
 			}
-			offsetID = item.GetID()
+		}
+
+		// items come newest first, so carry on paging up from the newest one
+		if len(items) != 0 {
+			offsetID = items[0].GetID()
 		}
 	}
 	l.Trace("left grabloop")
@@ -77,7 +77,7 @@ func (suite *IndexTestSuite) TestIndexBeforeLowID() {
 	// the oldest indexed post should be the lowest one we have in our testrig
 	postID, err := suite.timeline.OldestIndexedItemID(context.Background())
 	suite.NoError(err)
-	suite.Equal("01F8MHAYFKS4KMXF8K5Y1C0KRN", postID)
+	suite.Equal("01F8MH75CBF9JFX4ZAD54N0W0R", postID)
 
 	indexLength := suite.timeline.ItemIndexLength(context.Background())
 	suite.Equal(10, indexLength)
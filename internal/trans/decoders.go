@@ -43,16 +43,18 @@ func (i *importer) accountDecode(e transmodel.Entry) (*transmodel.Account, error
 		return nil, err
 	}
 
-	// extract public key
-	publicKeyBlock, _ := pem.Decode([]byte(a.PublicKeyString))
-	if publicKeyBlock == nil {
-		return nil, errors.New("accountDecode: error decoding account public key")
-	}
-	publicKey, err := x509.ParsePKCS1PublicKey(publicKeyBlock.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("accountDecode: error parsing account public key: %s", err)
+	// extract public key; remote accounts with ed25519 keys won't have one
+	if a.PublicKeyString != "" {
+		publicKeyBlock, _ := pem.Decode([]byte(a.PublicKeyString))
+		if publicKeyBlock == nil {
+			return nil, errors.New("accountDecode: error decoding account public key")
+		}
+		publicKey, err := x509.ParsePKCS1PublicKey(publicKeyBlock.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("accountDecode: error parsing account public key: %s", err)
+		}
+		a.PublicKey = publicKey
 	}
-	a.PublicKey = publicKey
 
 	if a.Domain == "" {
 		// extract private key (local account)
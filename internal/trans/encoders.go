@@ -34,16 +34,18 @@ import (
 func (e *exporter) accountEncode(ctx context.Context, f *os.File, a *transmodel.Account) error {
 	a.Type = transmodel.TransAccount
 
-	// marshal public key
-	encodedPublicKey := x509.MarshalPKCS1PublicKey(a.PublicKey)
-	if encodedPublicKey == nil {
-		return errors.New("could not MarshalPKCS1PublicKey")
+	// marshal public key; remote accounts with ed25519 keys won't have one
+	if a.PublicKey != nil {
+		encodedPublicKey := x509.MarshalPKCS1PublicKey(a.PublicKey)
+		if encodedPublicKey == nil {
+			return errors.New("could not MarshalPKCS1PublicKey")
+		}
+		publicKeyBytes := pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PUBLIC KEY",
+			Bytes: encodedPublicKey,
+		})
+		a.PublicKeyString = string(publicKeyBytes)
 	}
-	publicKeyBytes := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PUBLIC KEY",
-		Bytes: encodedPublicKey,
-	})
-	a.PublicKeyString = string(publicKeyBytes)
 
 	if a.Domain == "" {
 		// marshal private key for local account
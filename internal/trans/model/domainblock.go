@@ -30,5 +30,6 @@ type DomainBlock struct {
 	PrivateComment     string     `json:"privateComment,omitempty" bun:",nullzero"`
 	PublicComment      string     `json:"publicComment,omitempty" bun:",nullzero"`
 	Obfuscate          bool       `json:"obfuscate" bun:",nullzero"`
+	IncludeSubdomains  bool       `json:"includeSubdomains,omitempty" bun:",nullzero"`
 	SubscriptionID     string     `json:"subscriptionID,omitempty" bun:",nullzero"`
 }
@@ -0,0 +1,143 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// deepL translates texts with the DeepL API: https://www.deepl.com/docs-api
+type deepL struct {
+	client   HTTPClient
+	endpoint string
+	apiKey   string
+}
+
+type deepLRequest struct {
+	Text        []string `json:"text"`
+	SourceLang  string   `json:"source_lang,omitempty"`
+	TargetLang  string   `json:"target_lang"`
+	TagHandling string   `json:"tag_handling,omitempty"`
+}
+
+type deepLResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+	Message string `json:"message"`
+}
+
+// deepLEndpoint returns the API endpoint to use for the given key. Keys for
+// the free API end with :fx, and have to be used with a different endpoint.
+func deepLEndpoint(apiKey string) string {
+	if strings.HasSuffix(apiKey, ":fx") {
+		return "https://api-free.deepl.com"
+	}
+	return "https://api.deepl.com"
+}
+
+// deepLTargetLanguage returns the DeepL code for translating into the given language.
+// DeepL wants to know which variant of English or Portuguese to translate into.
+func deepLTargetLanguage(language string) string {
+	switch language = baseLanguage(language); language {
+	case "en":
+		return "EN-US"
+	case "pt":
+		return "PT-PT"
+	default:
+		return strings.ToUpper(language)
+	}
+}
+
+func (d *deepL) Provider() string {
+	return "DeepL.com"
+}
+
+func (d *deepL) Translate(ctx context.Context, texts []string, format Format, sourceLanguage string, targetLanguage string) (*Translation, error) {
+	dr := &deepLRequest{
+		Text:       texts,
+		SourceLang: strings.ToUpper(baseLanguage(sourceLanguage)),
+		TargetLang: deepLTargetLanguage(targetLanguage),
+	}
+	if format == FormatHTML {
+		// plain text is translated as it is, without any tag handling
+		dr.TagHandling = "html"
+	}
+
+	body, err := json.Marshal(dr)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint+"/v2/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %s", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error doing request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &deepLResponse{}
+	if resp.StatusCode != http.StatusOK {
+		// error responses usually have a message, but don't fail if they don't
+		_ = json.Unmarshal(b, r)
+		if resp.StatusCode == http.StatusBadRequest {
+			// DeepL answers with a 400 for languages it doesn't know
+			return nil, ErrUnsupportedLanguage
+		}
+		return nil, fmt.Errorf("translation request failed with status %d: %s", resp.StatusCode, r.Message)
+	}
+
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %s", err)
+	}
+
+	if len(r.Translations) != len(texts) {
+		return nil, fmt.Errorf("asked for %d translations but got %d", len(texts), len(r.Translations))
+	}
+
+	translation := &Translation{
+		Texts: make([]string, 0, len(texts)),
+	}
+	for _, t := range r.Translations {
+		translation.Texts = append(translation.Texts, t.Text)
+		if translation.DetectedSourceLanguage == "" {
+			translation.DetectedSourceLanguage = strings.ToLower(t.DetectedSourceLanguage)
+		}
+	}
+
+	return translation, nil
+}
@@ -0,0 +1,119 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// libreTranslate translates texts with a LibreTranslate instance: https://libretranslate.com
+type libreTranslate struct {
+	client   HTTPClient
+	endpoint string
+	apiKey   string
+}
+
+type libreTranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText   []string `json:"translatedText"`
+	DetectedLanguage []struct {
+		Language string `json:"language"`
+	} `json:"detectedLanguage"`
+	Error string `json:"error"`
+}
+
+func (l *libreTranslate) Provider() string {
+	return "LibreTranslate"
+}
+
+func (l *libreTranslate) Translate(ctx context.Context, texts []string, format Format, sourceLanguage string, targetLanguage string) (*Translation, error) {
+	source := "auto"
+	if sourceLanguage != "" {
+		source = baseLanguage(sourceLanguage)
+	}
+
+	body, err := json.Marshal(&libreTranslateRequest{
+		Q:      texts,
+		Source: source,
+		Target: baseLanguage(targetLanguage),
+		Format: string(format),
+		APIKey: l.apiKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error doing request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &libreTranslateResponse{}
+	if resp.StatusCode != http.StatusOK {
+		// error responses usually have an error message, but don't fail if they don't
+		_ = json.Unmarshal(b, r)
+		if resp.StatusCode == http.StatusBadRequest {
+			// LibreTranslate answers with a 400 for languages it doesn't know
+			return nil, ErrUnsupportedLanguage
+		}
+		return nil, fmt.Errorf("translation request failed with status %d: %s", resp.StatusCode, r.Error)
+	}
+
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %s", err)
+	}
+
+	if len(r.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("asked for %d translations but got %d", len(texts), len(r.TranslatedText))
+	}
+
+	translation := &Translation{
+		Texts:                  r.TranslatedText,
+		DetectedSourceLanguage: source,
+	}
+	if len(r.DetectedLanguage) != 0 && r.DetectedLanguage[0].Language != "" {
+		translation.DetectedSourceLanguage = r.DetectedLanguage[0].Language
+	}
+
+	return translation, nil
+}
@@ -0,0 +1,136 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package translate provides the backends that GoToSocial can use to translate statuses
+// into the language of the user reading them.
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+// Translation backend names, as set by config.Keys.TranslationBackend.
+const (
+	BackendLibreTranslate = "libretranslate" // BackendLibreTranslate uses a LibreTranslate instance at config.Keys.TranslationEndpoint
+	BackendDeepL          = "deepl"          // BackendDeepL uses the DeepL API, with the key at config.Keys.TranslationAPIKey
+)
+
+// ErrUnsupportedLanguage is returned by Translate when the backend can't translate from the source language, or into the target language.
+var ErrUnsupportedLanguage = errors.New("translation between these languages is not supported")
+
+// maxResponseSize is the most bytes that will be read from a response from a translation backend,
+// which is plenty for the texts of a status, and stops a misbehaving backend from using up memory.
+const maxResponseSize = 1 << 20 // 1MiB
+
+// Format is the format of texts that are given to Translate.
+type Format string
+
+// Formats that texts can be translated in.
+const (
+	FormatHTML Format = "html" // FormatHTML texts can contain html markup, which should be left as it is
+	FormatText Format = "text" // FormatText texts are plain text
+)
+
+// HTTPClient is the subset of *http.Client that's used to make requests to translation backends.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Translation is the result of translating some texts.
+type Translation struct {
+	// Texts contains the translated texts, in the same order as they were given to Translate.
+	Texts []string
+	// DetectedSourceLanguage is the ISO 639 code of the language that the texts were translated from.
+	DetectedSourceLanguage string
+}
+
+// Translator is implemented by translation backends.
+type Translator interface {
+	// Provider returns the name of the service that does the translating, for showing to users.
+	Provider() string
+	// Translate translates the given texts, which are all in the given format, from the source language into the target language.
+	// Languages are ISO 639 codes, and if the source language is empty then the backend should detect it.
+	// If the backend doesn't support one of the languages, ErrUnsupportedLanguage should be returned.
+	//
+	// Backends aren't trusted to return safe html, so callers must sanitize the translated texts themselves.
+	Translate(ctx context.Context, texts []string, format Format, sourceLanguage string, targetLanguage string) (*Translation, error)
+}
+
+// New returns the Translator that's selected with config.Keys.TranslationBackend, using the given client
+// to make requests to it. If translation is disabled, because no backend has been set, then nil is returned.
+func New(client HTTPClient) (Translator, error) {
+	backend := viper.GetString(config.Keys.TranslationBackend)
+	endpoint := strings.TrimSuffix(viper.GetString(config.Keys.TranslationEndpoint), "/")
+	apiKey := viper.GetString(config.Keys.TranslationAPIKey)
+
+	switch backend {
+	case "":
+		return nil, nil
+	case BackendLibreTranslate:
+		if endpoint == "" {
+			return nil, fmt.Errorf("%s must be set to use translation backend %s", config.Keys.TranslationEndpoint, backend)
+		}
+		return &libreTranslate{
+			client:   client,
+			endpoint: endpoint,
+			apiKey:   apiKey,
+		}, nil
+	case BackendDeepL:
+		if apiKey == "" {
+			return nil, fmt.Errorf("%s must be set to use translation backend %s", config.Keys.TranslationAPIKey, backend)
+		}
+		if endpoint == "" {
+			endpoint = deepLEndpoint(apiKey)
+		}
+		return &deepL{
+			client:   client,
+			endpoint: endpoint,
+			apiKey:   apiKey,
+		}, nil
+	default:
+		return nil, fmt.Errorf("translation backend %s not recognised, use one of: %s, %s", backend, BackendLibreTranslate, BackendDeepL)
+	}
+}
+
+// readResponse reads the body of the given response, up to maxResponseSize bytes.
+func readResponse(resp *http.Response) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %s", err)
+	}
+	if len(b) > maxResponseSize {
+		return nil, fmt.Errorf("response was larger than %d bytes", maxResponseSize)
+	}
+	return b, nil
+}
+
+// baseLanguage returns the primary language subtag of the given language tag, eg., en for en-GB.
+func baseLanguage(language string) string {
+	if i := strings.IndexAny(language, "-_"); i != -1 {
+		language = language[:i]
+	}
+	return strings.ToLower(language)
+}
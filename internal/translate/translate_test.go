@@ -0,0 +1,202 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package translate_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type TranslateTestSuite struct {
+	suite.Suite
+}
+
+// roundTripper is an http client that passes requests to a function rather than making them.
+type roundTripper func(req *http.Request) (*http.Response, error)
+
+func (f roundTripper) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func (suite *TranslateTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+}
+
+func (suite *TranslateTestSuite) TestDisabled() {
+	translator, err := translate.New(nil)
+	suite.NoError(err)
+	suite.Nil(translator)
+}
+
+func (suite *TranslateTestSuite) TestUnknownBackend() {
+	viper.Set(config.Keys.TranslationBackend, "babelfish")
+	_, err := translate.New(nil)
+	suite.EqualError(err, "translation backend babelfish not recognised, use one of: libretranslate, deepl")
+}
+
+func (suite *TranslateTestSuite) TestLibreTranslate() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendLibreTranslate)
+	viper.Set(config.Keys.TranslationEndpoint, "https://translate.example.org/")
+
+	var request map[string]interface{}
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		suite.Equal("https://translate.example.org/translate", req.URL.String())
+		suite.NoError(json.NewDecoder(req.Body).Decode(&request))
+		return jsonResponse(http.StatusOK, `{"translatedText":["<p>hello world</p>","hi"],"detectedLanguage":[{"confidence":90,"language":"nl"},{"confidence":80,"language":"nl"}]}`), nil
+	}))
+	suite.NoError(err)
+	suite.Equal("LibreTranslate", translator.Provider())
+
+	translation, err := translator.Translate(context.Background(), []string{"<p>hallo wereld</p>", "hoi"}, translate.FormatHTML, "", "en-GB")
+	suite.NoError(err)
+	suite.Equal([]string{"<p>hello world</p>", "hi"}, translation.Texts)
+	suite.Equal("nl", translation.DetectedSourceLanguage)
+
+	suite.Equal("auto", request["source"])
+	suite.Equal("en", request["target"])
+	suite.Equal("html", request["format"])
+}
+
+func (suite *TranslateTestSuite) TestLibreTranslateUnsupportedLanguage() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendLibreTranslate)
+	viper.Set(config.Keys.TranslationEndpoint, "https://translate.example.org")
+
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusBadRequest, `{"error":"xx is not supported"}`), nil
+	}))
+	suite.NoError(err)
+
+	_, err = translator.Translate(context.Background(), []string{"hoi"}, translate.FormatText, "nl", "xx")
+	suite.ErrorIs(err, translate.ErrUnsupportedLanguage)
+}
+
+func (suite *TranslateTestSuite) TestDeepL() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendDeepL)
+	viper.Set(config.Keys.TranslationAPIKey, "some-key:fx")
+
+	var request map[string]interface{}
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		suite.Equal("https://api-free.deepl.com/v2/translate", req.URL.String())
+		suite.Equal("DeepL-Auth-Key some-key:fx", req.Header.Get("Authorization"))
+		suite.NoError(json.NewDecoder(req.Body).Decode(&request))
+		return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"NL","text":"<p>hello world</p>"}]}`), nil
+	}))
+	suite.NoError(err)
+	suite.Equal("DeepL.com", translator.Provider())
+
+	translation, err := translator.Translate(context.Background(), []string{"<p>hallo wereld</p>"}, translate.FormatHTML, "nl", "en")
+	suite.NoError(err)
+	suite.Equal([]string{"<p>hello world</p>"}, translation.Texts)
+	suite.Equal("nl", translation.DetectedSourceLanguage)
+
+	suite.Equal("NL", request["source_lang"])
+	suite.Equal("EN-US", request["target_lang"])
+	suite.Equal("html", request["tag_handling"])
+}
+
+func (suite *TranslateTestSuite) TestLibreTranslateText() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendLibreTranslate)
+	viper.Set(config.Keys.TranslationEndpoint, "https://translate.example.org")
+
+	var request map[string]interface{}
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		suite.NoError(json.NewDecoder(req.Body).Decode(&request))
+		return jsonResponse(http.StatusOK, `{"translatedText":["hi"]}`), nil
+	}))
+	suite.NoError(err)
+
+	translation, err := translator.Translate(context.Background(), []string{"hoi"}, translate.FormatText, "nl", "en")
+	suite.NoError(err)
+	suite.Equal([]string{"hi"}, translation.Texts)
+	suite.Equal("text", request["format"])
+}
+
+func (suite *TranslateTestSuite) TestLibreTranslateResponseTooLarge() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendLibreTranslate)
+	viper.Set(config.Keys.TranslationEndpoint, "https://translate.example.org")
+
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		huge := `{"translatedText":["` + strings.Repeat("a", 2<<20) + `"]}`
+		return jsonResponse(http.StatusOK, huge), nil
+	}))
+	suite.NoError(err)
+
+	_, err = translator.Translate(context.Background(), []string{"hoi"}, translate.FormatText, "nl", "en")
+	suite.EqualError(err, "response was larger than 1048576 bytes")
+}
+
+func (suite *TranslateTestSuite) TestDeepLText() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendDeepL)
+	viper.Set(config.Keys.TranslationAPIKey, "some-key")
+
+	var request map[string]interface{}
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		suite.Equal("https://api.deepl.com/v2/translate", req.URL.String())
+		suite.NoError(json.NewDecoder(req.Body).Decode(&request))
+		return jsonResponse(http.StatusOK, `{"translations":[{"detected_source_language":"NL","text":"hi"}]}`), nil
+	}))
+	suite.NoError(err)
+
+	translation, err := translator.Translate(context.Background(), []string{"hoi"}, translate.FormatText, "", "en")
+	suite.NoError(err)
+	suite.Equal([]string{"hi"}, translation.Texts)
+	suite.NotContains(request, "tag_handling")
+	suite.NotContains(request, "source_lang")
+}
+
+func (suite *TranslateTestSuite) TestDeepLResponseTooLarge() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendDeepL)
+	viper.Set(config.Keys.TranslationAPIKey, "some-key")
+
+	translator, err := translate.New(roundTripper(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusBadGateway, strings.Repeat("a", 2<<20)), nil
+	}))
+	suite.NoError(err)
+
+	_, err = translator.Translate(context.Background(), []string{"hoi"}, translate.FormatText, "nl", "en")
+	suite.EqualError(err, "response was larger than 1048576 bytes")
+}
+
+func (suite *TranslateTestSuite) TestDeepLNeedsAPIKey() {
+	viper.Set(config.Keys.TranslationBackend, translate.BackendDeepL)
+	_, err := translate.New(nil)
+	suite.EqualError(err, "translation-api-key must be set to use translation backend deepl")
+}
+
+func TestTranslateTestSuite(t *testing.T) {
+	suite.Run(t, &TranslateTestSuite{})
+}
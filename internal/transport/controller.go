@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/go-fed/httpsig"
 	"github.com/spf13/viper"
@@ -33,12 +34,41 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/federation/federatingdb"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
 // Controller generates transports for use in making federation requests to other servers.
 type Controller interface {
+	// NewTransport returns a transport that signs requests with the given key.
 	NewTransport(pubKeyID string, privkey crypto.PrivateKey) (Transport, error)
+	// NewTransportForUsername returns a transport that signs requests with the key of the local account
+	// with the given username. If username is empty, the instance actor's key is used.
 	NewTransportForUsername(ctx context.Context, username string) (Transport, error)
+	// NewTransportForInstance returns a transport that signs requests with the key of the instance actor:
+	// the account that's named after this instance's host, and that doesn't belong to any user. It should be used for any requests that
+	// are made on behalf of the whole instance rather than a particular user, such as fetching remote keys
+	// and media, so that no user's key is borrowed, and so that remote instances that only serve signed
+	// requests will still serve them.
+	NewTransportForInstance(ctx context.Context) (Transport, error)
+	// NoteSignatureAlgorithm records the algorithm named in a valid http signature that the given host sent to us,
+	// so that requests to that host can be signed in the way it expects. Hosts that name a specific algorithm,
+	// such as rsa-sha256, are sent signatures that name it too; everyone else is sent hs2019 signatures.
+	NoteSignatureAlgorithm(host string, algorithm string)
+	// RetryDeliveries tries again any deliveries that failed earlier and are now due to be retried. Deliveries that keep
+	// failing are tried less and less often, and are given up on after a few days. Since failed deliveries are stored
+	// in the database, they'll still be retried after a restart.
+	RetryDeliveries(ctx context.Context) error
+	// ProbeUnreachableDomains checks whether any domains that deliveries have been suspended to are responding again,
+	// and resumes deliveries to those that are. Deliveries to a domain are suspended once every delivery to it has been
+	// failing for the configured number of days, and each domain is checked at most once an hour.
+	ProbeUnreachableDomains(ctx context.Context) error
+	// ResumeDeliveries forgets about any failed deliveries to the given domain, so that if deliveries
+	// to the domain were suspended, they're resumed straight away.
+	ResumeDeliveries(ctx context.Context, domain string) error
+	// SuspendDeliveries suspends deliveries to the given domain straight away, whether or not deliveries to it
+	// have been failing. Deliveries stay suspended until they're resumed with ResumeDeliveries; the domain
+	// isn't checked to see whether it's responding, since it might well be.
+	SuspendDeliveries(ctx context.Context, domain string) (*gtsmodel.UnreachableDomain, error)
 }
 
 type controller struct {
@@ -47,6 +77,10 @@ type controller struct {
 	client   pub.HttpClient
 	appAgent string
 
+	// webPageClient is derived from client, but refuses to connect to addresses that aren't public,
+	// since the urls of web pages come from users rather than from other fediverse servers
+	webPageClient pub.HttpClient
+
 	// dereferenceFollowersShortcut is a shortcut to dereference followers of an
 	// account on this instance, without making any external api/http calls.
 	//
@@ -58,6 +92,26 @@ type controller struct {
 	//
 	// It is passed to new transports, and should only be invoked when the iri.Host == this host.
 	dereferenceUserShortcut func(ctx context.Context, iri *url.URL) ([]byte, error)
+
+	// mediaLimiter is shared by all transports, so that the limit on fetching media
+	// from any one host applies no matter which account the media is fetched with.
+	mediaLimiter *hostLimiter
+
+	// deliveryLimiter is shared by all transports, so that the limit on concurrent deliveries
+	// to any one host applies no matter which account the deliveries are made by.
+	deliveryLimiter *hostSemaphore
+
+	// unreachable is shared by all transports, and keeps track
+	// of which domains deliveries have been failing to.
+	unreachable *unreachableDomains
+
+	// peerAlgorithms is shared by all transports, and keeps track
+	// of which algorithm names remote hosts expect in signatures.
+	peerAlgorithms *peerAlgorithms
+
+	// created is when the controller was created; deliveries stored since
+	// then that haven't been tried yet are still waiting to be made by it
+	created time.Time
 }
 
 func dereferenceFollowersShortcut(federatingDB federatingdb.DB) func(context.Context, *url.URL) ([]byte, error) {
@@ -103,32 +157,46 @@ func NewController(db db.DB, federatingDB federatingdb.DB, clock pub.Clock, clie
 		clock:                        clock,
 		client:                       client,
 		appAgent:                     appAgent,
+		webPageClient:                PublicOnlyClient(client),
 		dereferenceFollowersShortcut: dereferenceFollowersShortcut(federatingDB),
 		dereferenceUserShortcut:      dereferenceUserShortcut(federatingDB),
+		mediaLimiter:                 newHostLimiter(viper.GetInt(config.Keys.MediaRemoteFetchRate)),
+		deliveryLimiter:              newHostSemaphore(deliveryHostConcurrency),
+		unreachable:                  newUnreachableDomains(db),
+		peerAlgorithms:               newPeerAlgorithms(),
+		created:                      time.Now(),
 	}
 }
 
 // NewTransport returns a new http signature transport with the given public key id (a URL), and the given private key.
 func (c *controller) NewTransport(pubKeyID string, privkey crypto.PrivateKey) (Transport, error) {
-	prefs := []httpsig.Algorithm{httpsig.RSA_SHA256}
+	return c.newTransport(pubKeyID, privkey)
+}
+
+func (c *controller) newTransport(pubKeyID string, privkey crypto.PrivateKey) (*transport, error) {
+	algorithm := signingAlgorithm(privkey)
+	prefs := []httpsig.Algorithm{algorithm}
 	digestAlgo := httpsig.DigestSha256
 	getHeaders := []string{httpsig.RequestTarget, "host", "date"}
 	postHeaders := []string{httpsig.RequestTarget, "host", "date", "digest"}
 
-	getSigner, _, err := httpsig.NewSigner(prefs, digestAlgo, getHeaders, httpsig.Signature, 120)
+	httpGetSigner, _, err := httpsig.NewSigner(prefs, digestAlgo, getHeaders, httpsig.Signature, 120)
 	if err != nil {
 		return nil, fmt.Errorf("error creating get signer: %s", err)
 	}
+	getSigner := &negotiatingSigner{Signer: httpGetSigner, algorithm: algorithm, peers: c.peerAlgorithms}
 
-	postSigner, _, err := httpsig.NewSigner(prefs, digestAlgo, postHeaders, httpsig.Signature, 120)
+	httpPostSigner, _, err := httpsig.NewSigner(prefs, digestAlgo, postHeaders, httpsig.Signature, 120)
 	if err != nil {
 		return nil, fmt.Errorf("error creating post signer: %s", err)
 	}
+	postSigner := &negotiatingSigner{Signer: httpPostSigner, algorithm: algorithm, peers: c.peerAlgorithms}
 
 	sigTransport := pub.NewHttpSigTransport(c.client, c.appAgent, c.clock, getSigner, postSigner, pubKeyID, privkey)
 
 	return &transport{
 		client:                       c.client,
+		webPageClient:                c.webPageClient,
 		appAgent:                     c.appAgent,
 		gofedAgent:                   "(go-fed/activity v1.0.0)",
 		clock:                        c.clock,
@@ -137,30 +205,67 @@ func (c *controller) NewTransport(pubKeyID string, privkey crypto.PrivateKey) (T
 		sigTransport:                 sigTransport,
 		getSigner:                    getSigner,
 		getSignerMu:                  &sync.Mutex{},
+		postSigner:                   postSigner,
+		postSignerMu:                 &sync.Mutex{},
 		dereferenceFollowersShortcut: c.dereferenceFollowersShortcut,
 		dereferenceUserShortcut:      c.dereferenceUserShortcut,
+		mediaLimiter:                 c.mediaLimiter,
+		deliveryLimiter:              c.deliveryLimiter,
+		unreachable:                  c.unreachable,
+		deliveries:                   c,
 	}, nil
 }
 
+// newTransportForAccount returns a transport that signs requests with the key of the given local
+// account, and that adds integrity proofs to the account's activities when they're delivered.
+func (c *controller) newTransportForAccount(account *gtsmodel.Account) (*transport, error) {
+	transport, err := c.newTransport(account.PublicKeyURI, account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.actorURI = account.URI
+	if len(account.AssertionPrivateKey) != 0 && account.AssertionKeyURI != "" {
+		transport.assertionKeyURI = account.AssertionKeyURI
+		transport.assertionKey = account.AssertionPrivateKey
+	}
+
+	return transport, nil
+}
+
 func (c *controller) NewTransportForUsername(ctx context.Context, username string) (Transport, error) {
 	// We need an account to use to create a transport for dereferecing something.
 	// If a username has been given, we can fetch the account with that username and use it.
 	// Otherwise, we can take the instance account and use those credentials to make the request.
-	var u string
 	if username == "" {
-		u = viper.GetString(config.Keys.Host)
-	} else {
-		u = username
+		return c.NewTransportForInstance(ctx)
 	}
 
-	ourAccount, err := c.db.GetLocalAccountByUsername(ctx, u)
+	ourAccount, err := c.db.GetLocalAccountByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("error getting account %s from db: %s", username, err)
 	}
 
-	transport, err := c.NewTransport(ourAccount.PublicKeyURI, ourAccount.PrivateKey)
+	transport, err := c.newTransportForAccount(ourAccount)
 	if err != nil {
 		return nil, fmt.Errorf("error creating transport for user %s: %s", username, err)
 	}
 	return transport, nil
 }
+
+func (c *controller) NewTransportForInstance(ctx context.Context) (Transport, error) {
+	instanceAccount, err := c.db.GetInstanceAccount(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting instance account from db: %s", err)
+	}
+
+	transport, err := c.newTransportForAccount(instanceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transport for instance account: %s", err)
+	}
+	return transport, nil
+}
+
+func (c *controller) NoteSignatureAlgorithm(host string, algorithm string) {
+	c.peerAlgorithms.note(host, algorithm)
+}
@@ -19,36 +19,94 @@
 package transport
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
+// deliveryHostConcurrency is how many deliveries can be made to any one host at the same time.
+// Deliveries to different hosts are made in parallel, so a host that's slow to respond only holds
+// up deliveries to itself, and can't tie up more than this many requests while it does.
+const deliveryHostConcurrency = 4
+
 func (t *transport) BatchDeliver(ctx context.Context, b []byte, recipients []*url.URL) error {
-	// concurrently deliver to recipients; for each delivery, buffer the error if it fails
-	wg := sync.WaitGroup{}
-	errCh := make(chan error, len(recipients))
+	// the proof only has to be made once, no matter how many recipients there are
+	b = t.addProof(b)
+
+	// leave out the recipients that won't be delivered to, and store deliveries to the rest before making any of them
+	errs := []string{}
+	inboxes := make([]*url.URL, 0, len(recipients))
 	for _, recipient := range recipients {
-		wg.Add(1)
-		go func(r *url.URL) {
-			defer wg.Done()
-			if err := t.Deliver(ctx, b, r); err != nil {
-				errCh <- err
-			}
-		}(recipient)
+		deliver, err := t.shouldDeliver(ctx, recipient)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if deliver {
+			inboxes = append(inboxes, recipient)
+		}
+	}
+	deliveries := t.deliveries.queueDeliveries(t.pubKeyID, b, inboxes)
+
+	// split the inboxes up by host, so that each host can be delivered to at its own pace
+	hosts := []string{}
+	byHost := make(map[string][]int)
+	for i, inbox := range inboxes {
+		host := strings.ToLower(inbox.Host)
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], i)
+	}
+
+	// concurrently deliver to each host, using up to deliveryHostConcurrency
+	// workers per host; for each delivery, buffer the error if it fails
+	wg := sync.WaitGroup{}
+	errCh := make(chan error, len(inboxes))
+	for _, host := range hosts {
+		queue := make(chan int, len(byHost[host]))
+		for _, i := range byHost[host] {
+			queue <- i
+		}
+		close(queue)
+
+		workers := len(byHost[host])
+		if workers > deliveryHostConcurrency {
+			workers = deliveryHostConcurrency
+		}
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range queue {
+					var delivery *gtsmodel.Delivery
+					if deliveries != nil {
+						delivery = deliveries[i]
+					}
+					if err := t.deliverQueued(ctx, b, inboxes[i], delivery); err != nil {
+						errCh <- err
+					}
+				}
+			}()
+		}
 	}
 
 	// wait until all deliveries have succeeded or failed
 	wg.Wait()
 
 	// receive any buffered errors
-	errs := make([]string, 0, len(recipients))
 outer:
 	for {
 		select {
@@ -67,11 +125,132 @@ outer:
 }
 
 func (t *transport) Deliver(ctx context.Context, b []byte, to *url.URL) error {
-	// if the 'to' host is our own, just skip this delivery since we by definition already have the message!
+	b = t.addProof(b)
+
+	if deliver, err := t.shouldDeliver(ctx, to); !deliver {
+		return err
+	}
+
+	var delivery *gtsmodel.Delivery
+	if deliveries := t.deliveries.queueDeliveries(t.pubKeyID, b, []*url.URL{to}); deliveries != nil {
+		delivery = deliveries[0]
+	}
+	return t.deliverQueued(ctx, b, to, delivery)
+}
+
+// shouldDeliver returns true if b should be delivered to the given inbox at all. Deliveries to our own host are
+// skipped, since we by definition already have the message, and an error is returned for deliveries to instances
+// that have stopped responding altogether; those deliveries aren't stored either, since by the time deliveries
+// to the instance are resumed it'd most likely be too late to retry them.
+func (t *transport) shouldDeliver(ctx context.Context, to *url.URL) (bool, error) {
 	if to.Host == viper.GetString(config.Keys.Host) || to.Host == viper.GetString(config.Keys.AccountDomain) {
-		return nil
+		return false, nil
 	}
 
+	if t.unreachable.suspended(ctx, to.Host) {
+		return false, fmt.Errorf("not delivering to %s: %w", to.String(), ErrDeliveriesSuspended)
+	}
+
+	return true, nil
+}
+
+// deliverQueued delivers b to the given inbox, and then forgets the given stored delivery of it if that went well,
+// or schedules it to be tried again later if it didn't. The delivery is nil if it couldn't be stored beforehand.
+func (t *transport) deliverQueued(ctx context.Context, b []byte, to *url.URL, delivery *gtsmodel.Delivery) error {
 	logrus.Debugf("Deliver: posting as %s to %s", t.pubKeyID, to.String())
-	return t.sigTransport.Deliver(ctx, b, to)
+	if err := t.deliver(ctx, b, to); err != nil {
+		t.deliveries.firstDeliveryFailed(t.pubKeyID, b, to, delivery, err)
+		return err
+	}
+
+	t.deliveries.forgetDelivery(delivery)
+	return nil
+}
+
+// deliver waits until a delivery can be made to the host of the given inbox, and then makes it,
+// keeping track of whether deliveries to the host are failing.
+func (t *transport) deliver(ctx context.Context, b []byte, to *url.URL) error {
+	if t.unreachable.suspended(ctx, to.Host) {
+		return fmt.Errorf("error delivering to %s: %w", to.Host, ErrDeliveriesSuspended)
+	}
+
+	release, err := t.deliveryLimiter.acquire(ctx, to.Host)
+	if err != nil {
+		return fmt.Errorf("error waiting to deliver to %s: %s", to.Host, err)
+	}
+	defer release()
+
+	domain := strings.ToLower(to.Host)
+	start := time.Now()
+	err = t.post(ctx, b, to)
+	deliveryDuration.WithLabelValues(domain).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		deliveries.WithLabelValues(domain, deliveryFailed).Inc()
+	} else {
+		deliveries.WithLabelValues(domain, deliverySucceeded).Inc()
+	}
+
+	// a delivery that the remote instance turned down still means that the instance is there, and
+	// if we gave up on the delivery ourselves, that's not the remote instance's fault either
+	var statusErr *StatusError
+	switch {
+	case err == nil, errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError:
+		if err := t.unreachable.succeeded(ctx, to.Host); err != nil {
+			logrus.Errorf("deliver: %s", err)
+		}
+	case ctx.Err() == nil:
+		t.unreachable.failed(ctx, to.Host, err)
+	}
+
+	return err
+}
+
+// post signs and POSTs b to the given inbox, returning a *StatusError if the inbox responds with anything but success.
+func (t *transport) post(ctx context.Context, b []byte, to *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Content-Type", `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`)
+	req.Header.Add("Accept-Charset", "utf-8")
+	req.Header.Add("Date", t.clock.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05")+" GMT")
+	req.Header.Add("User-Agent", fmt.Sprintf("%s %s", t.appAgent, t.gofedAgent))
+	req.Header.Set("Host", to.Host)
+	t.postSignerMu.Lock()
+	err = t.postSigner.SignRequest(t.privkey, t.pubKeyID, req, b)
+	t.postSignerMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		return &StatusError{Method: http.MethodPost, IRI: to.String(), StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return nil
+}
+
+// addProof adds an integrity proof to the activity in b, if it's an activity of the account
+// that this transport acts for, so that it can still be verified if it's forwarded by one
+// of its recipients. If a proof can't be added, the activity is delivered without one.
+func (t *transport) addProof(b []byte) []byte {
+	if t.assertionKey == nil {
+		return b
+	}
+
+	proved, _, err := ap.AddProof(b, t.actorURI, t.assertionKeyURI, t.assertionKey, t.clock.Now())
+	if err != nil {
+		logrus.Errorf("addProof: error adding integrity proof as %s: %s", t.actorURI, err)
+		return b
+	}
+
+	return proved
 }
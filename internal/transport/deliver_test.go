@@ -0,0 +1,425 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DeliverTestSuite struct {
+	suite.Suite
+	db db.DB
+}
+
+func (suite *DeliverTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db, nil)
+}
+
+func (suite *DeliverTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *DeliverTestSuite) newTransport(do func(req *http.Request) (*http.Response, error)) (transport.Controller, transport.Transport) {
+	privkey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	tc := transport.NewController(suite.db, nil, &federation.Clock{}, testrig.NewMockHTTPClient(do))
+	t, err := tc.NewTransport("http://localhost:8080/users/the_mighty_zork/main-key", privkey)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return tc, t
+}
+
+// putUnreachableDomain stores an unreachable domain that deliveries started failing to the given time ago.
+func (suite *DeliverTestSuite) putUnreachableDomain(domain string, failingFor time.Duration, suspended bool) *gtsmodel.UnreachableDomain {
+	d := &gtsmodel.UnreachableDomain{
+		ID:        "01G0SRHQ5X5KTSAT2C1MVPJ2W8",
+		CreatedAt: time.Now().Add(-failingFor),
+		UpdatedAt: time.Now().Add(-failingFor),
+		Domain:    domain,
+		Failures:  20,
+		LastError: "POST request failed (502): 502 Bad Gateway",
+	}
+	if suspended {
+		d.SuspendedAt = time.Now().Add(-2 * time.Hour)
+		d.LastProbedAt = time.Now().Add(-2 * time.Hour)
+	}
+	suite.NoError(suite.db.Put(context.Background(), d))
+	return d
+}
+
+// getUnreachableDomain returns the unreachable domain with the given domain name from the database, or nil if there isn't one.
+func (suite *DeliverTestSuite) getUnreachableDomain(domain string) *gtsmodel.UnreachableDomain {
+	d := &gtsmodel.UnreachableDomain{}
+	if err := suite.db.GetWhere(context.Background(), []db.Where{{Key: "domain", Value: domain}}, d); err != nil {
+		suite.ErrorIs(err, db.ErrNoEntries)
+		return nil
+	}
+	return d
+}
+
+func (suite *DeliverTestSuite) TestBatchDeliverSlowHost() {
+	slowHost := "slow.example.org"
+	unblock := make(chan struct{})
+
+	mu := sync.Mutex{}
+	inFlight := make(map[string]int)
+	maxInFlight := make(map[string]int)
+	delivered := make(map[string]int)
+
+	_, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		host := req.URL.Host
+
+		mu.Lock()
+		inFlight[host]++
+		if inFlight[host] > maxInFlight[host] {
+			maxInFlight[host] = inFlight[host]
+		}
+		mu.Unlock()
+
+		if host == slowHost {
+			<-unblock
+		}
+
+		mu.Lock()
+		inFlight[host]--
+		delivered[host]++
+		mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	recipients := []*url.URL{}
+	for i := 0; i < 10; i++ {
+		recipients = append(recipients, testrig.URLMustParse(fmt.Sprintf("https://%s/users/user_%d/inbox", slowHost, i)))
+	}
+	for i := 0; i < 10; i++ {
+		recipients = append(recipients, testrig.URLMustParse(fmt.Sprintf("https://fast.example.org/users/user_%d/inbox", i)))
+		recipients = append(recipients, testrig.URLMustParse(fmt.Sprintf("https://fossbros-anonymous.io/users/user_%d/inbox", i)))
+	}
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- t.BatchDeliver(context.Background(), []byte(`{"type":"Create"}`), recipients)
+	}()
+
+	// everyone else should get the delivery while the slow host is still stuck
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return delivered["fast.example.org"] == 10 && delivered["fossbros-anonymous.io"] == 10
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// and the slow host shouldn't have more than a few deliveries waiting on it
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return inFlight[slowHost] == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	suite.Equal(0, delivered[slowHost])
+	suite.Equal(4, inFlight[slowHost])
+	mu.Unlock()
+
+	close(unblock)
+	suite.NoError(<-errCh)
+
+	suite.Equal(10, delivered[slowHost])
+	suite.Equal(4, maxInFlight[slowHost])
+	suite.LessOrEqual(maxInFlight["fast.example.org"], 4)
+	suite.LessOrEqual(maxInFlight["fossbros-anonymous.io"], 4)
+}
+
+func (suite *DeliverTestSuite) TestDeliverUnreachable() {
+	ctx := context.Background()
+	inbox := testrig.URLMustParse("https://dead.example.org/users/someone/inbox")
+
+	// deliveries to the domain have been failing for a while, but not long enough to suspend them
+	suite.putUnreachableDomain("dead.example.org", 6*24*time.Hour, false)
+
+	sent := 0
+	_, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Status:     "502 Bad Gateway",
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	suite.Error(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	suite.Equal(1, sent)
+
+	d := suite.getUnreachableDomain("dead.example.org")
+	suite.Equal(21, d.Failures)
+	suite.Contains(d.LastError, "502")
+	suite.True(d.SuspendedAt.IsZero())
+
+	// once they've been failing for long enough, deliveries get suspended
+	d.CreatedAt = time.Now().Add(-8 * 24 * time.Hour)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, d))
+	_, t = suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Status:     "502 Bad Gateway",
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	suite.Error(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	suite.Equal(2, sent)
+	d = suite.getUnreachableDomain("dead.example.org")
+	suite.Equal(22, d.Failures)
+	suite.False(d.SuspendedAt.IsZero())
+
+	// and then nothing else is sent to the domain
+	suite.ErrorIs(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox), transport.ErrDeliveriesSuspended)
+	suite.Equal(2, sent)
+}
+
+func (suite *DeliverTestSuite) TestDeliverRejected() {
+	ctx := context.Background()
+	suite.putUnreachableDomain("picky.example.org", 8*24*time.Hour, false)
+
+	_, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Status:     "401 Unauthorized",
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	// the instance turned the delivery down, but it's clearly there to do so
+	err := t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse("https://picky.example.org/inbox"))
+	var statusErr *transport.StatusError
+	suite.ErrorAs(err, &statusErr)
+	suite.Equal(http.StatusUnauthorized, statusErr.StatusCode)
+	suite.Nil(suite.getUnreachableDomain("picky.example.org"))
+
+	suite.Error(t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse("https://new.example.org/inbox")))
+	suite.Nil(suite.getUnreachableDomain("new.example.org"))
+}
+
+func (suite *DeliverTestSuite) TestDeliverSucceedsAfterFailures() {
+	ctx := context.Background()
+	suite.putUnreachableDomain("flaky.example.org", 2*time.Hour, false)
+
+	_, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	// one successful delivery is all it takes for the domain to be forgotten about
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse("https://flaky.example.org/inbox")))
+	suite.Nil(suite.getUnreachableDomain("flaky.example.org"))
+}
+
+func (suite *DeliverTestSuite) TestDeliverWithIntegrityProof() {
+	ctx := context.Background()
+	zork := testrig.NewTestAccounts()["local_account_1"]
+
+	delivered := make(chan []byte, 4)
+	tc, _ := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		suite.NoError(err)
+		delivered <- b
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	t, err := tc.NewTransportForUsername(ctx, zork.Username)
+	suite.NoError(err)
+
+	// zork's own activities get a proof made with zork's assertion key
+	activity := []byte(`{"@context":"https://www.w3.org/ns/activitystreams","actor":"http://localhost:8080/users/the_mighty_zork","id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/activity","type":"Create"}`)
+	suite.NoError(t.BatchDeliver(ctx, activity, []*url.URL{
+		testrig.URLMustParse("https://one.example.org/inbox"),
+		testrig.URLMustParse("https://two.example.org/inbox"),
+	}))
+	for i := 0; i < 2; i++ {
+		proof, err := ap.ExtractProof(<-delivered)
+		suite.NoError(err)
+		suite.NotNil(proof)
+		suite.Equal(zork.AssertionKeyURI, proof.VerificationMethod.String())
+		suite.NoError(proof.Verify(zork.AssertionPublicKey))
+	}
+
+	// but activities that zork is only forwarding are delivered as they are
+	forwarded := []byte(`{"actor":"http://fossbros-anonymous.io/users/foss_satan","type":"Create"}`)
+	suite.NoError(t.Deliver(ctx, forwarded, testrig.URLMustParse("https://one.example.org/inbox")))
+	suite.Equal(forwarded, <-delivered)
+}
+
+func (suite *DeliverTestSuite) TestProbeUnreachableDomains() {
+	ctx := context.Background()
+	suite.putUnreachableDomain("dead.example.org", 10*24*time.Hour, true)
+
+	statusCode := http.StatusBadGateway
+	probed := []string{}
+	tc, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			probed = append(probed, req.URL.String())
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	// the domain is still down
+	suite.NoError(tc.ProbeUnreachableDomains(ctx))
+	suite.Equal([]string{"https://dead.example.org/.well-known/nodeinfo"}, probed)
+	d := suite.getUnreachableDomain("dead.example.org")
+	suite.False(d.SuspendedAt.IsZero())
+	suite.WithinDuration(time.Now(), d.LastProbedAt, time.Minute)
+
+	// it was only just checked, so it shouldn't be checked again yet
+	statusCode = http.StatusOK
+	suite.NoError(tc.ProbeUnreachableDomains(ctx))
+	suite.Len(probed, 1)
+
+	// when it's due to be checked again, it's back up, so deliveries should be resumed
+	d.LastProbedAt = time.Now().Add(-2 * time.Hour)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, d))
+	tc, t = suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			probed = append(probed, req.URL.String())
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+	suite.NoError(tc.ProbeUnreachableDomains(ctx))
+	suite.Len(probed, 2)
+	suite.Nil(suite.getUnreachableDomain("dead.example.org"))
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse("https://dead.example.org/inbox")))
+}
+
+func (suite *DeliverTestSuite) TestResumeDeliveries() {
+	ctx := context.Background()
+	suite.putUnreachableDomain("dead.example.org", 10*24*time.Hour, true)
+
+	sent := 0
+	tc, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		sent++
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	inbox := testrig.URLMustParse("https://dead.example.org/inbox")
+	suite.ErrorIs(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox), transport.ErrDeliveriesSuspended)
+	suite.Zero(sent)
+
+	suite.NoError(tc.ResumeDeliveries(ctx, "dead.example.org"))
+	suite.Nil(suite.getUnreachableDomain("dead.example.org"))
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	suite.Equal(1, sent)
+}
+
+func (suite *DeliverTestSuite) TestSuspendDeliveries() {
+	ctx := context.Background()
+
+	sent := 0
+	probed := 0
+	tc, t := suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			probed++
+		} else {
+			sent++
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	inbox := testrig.URLMustParse("https://fine.example.org/inbox")
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+	suite.Equal(1, sent)
+
+	d, err := tc.SuspendDeliveries(ctx, "fine.example.org")
+	suite.NoError(err)
+	suite.True(d.SuspendedByAdmin)
+	suite.False(d.SuspendedAt.IsZero())
+	suite.True(suite.getUnreachableDomain("fine.example.org").SuspendedByAdmin)
+
+	suite.ErrorIs(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox), transport.ErrDeliveriesSuspended)
+	suite.Equal(1, sent)
+
+	// the domain is responding fine, but since an admin suspended deliveries to it, they stay suspended
+	d = suite.getUnreachableDomain("fine.example.org")
+	d.LastProbedAt = time.Now().Add(-2 * time.Hour)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, d))
+	tc, t = suite.newTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			probed++
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+	suite.NoError(tc.ProbeUnreachableDomains(ctx))
+	suite.Zero(probed)
+	suite.ErrorIs(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox), transport.ErrDeliveriesSuspended)
+
+	suite.NoError(tc.ResumeDeliveries(ctx, "fine.example.org"))
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), inbox))
+}
+
+func TestDeliverTestSuite(t *testing.T) {
+	suite.Run(t, &DeliverTestSuite{})
+}
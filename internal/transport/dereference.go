@@ -20,6 +20,9 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 
 	"github.com/sirupsen/logrus"
@@ -28,6 +31,25 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
+// StatusError is returned by Dereference when the remote server responds to a GET with something
+// other than 200 OK, and by Deliver when it responds to a POST with something other than a success
+// code, so that the caller can tell what went wrong.
+type StatusError struct {
+	Method     string
+	IRI        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s request to %s failed (%d): %s", e.Method, e.IRI, e.StatusCode, e.Status)
+}
+
+// Gone returns true if the remote server said that the thing doesn't exist, or doesn't exist anymore.
+func (e *StatusError) Gone() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+}
+
 func (t *transport) Dereference(ctx context.Context, iri *url.URL) ([]byte, error) {
 	l := logrus.WithField("func", "Dereference")
 
@@ -47,5 +69,29 @@ func (t *transport) Dereference(ctx context.Context, iri *url.URL) ([]byte, erro
 
 	// the request is either for a remote host or for us but we don't have a shortcut, so continue as normal
 	l.Debugf("performing GET to %s", iri.String())
-	return t.sigTransport.Dereference(ctx, iri)
+	req, err := http.NewRequestWithContext(ctx, "GET", iri.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`)
+	req.Header.Add("Accept-Charset", "utf-8")
+	req.Header.Add("Date", t.clock.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05")+" GMT")
+	req.Header.Add("User-Agent", fmt.Sprintf("%s %s", t.appAgent, t.gofedAgent))
+	req.Header.Set("Host", iri.Host)
+	t.getSignerMu.Lock()
+	err = t.getSigner.SignRequest(t.privkey, t.pubKeyID, req, nil)
+	t.getSignerMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Method: http.MethodGet, IRI: iri.String(), StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return io.ReadAll(resp.Body)
 }
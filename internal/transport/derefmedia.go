@@ -30,6 +30,12 @@ import (
 
 func (t *transport) DereferenceMedia(ctx context.Context, iri *url.URL) (io.ReadCloser, int, error) {
 	l := logrus.WithField("func", "DereferenceMedia")
+
+	// don't hammer any one host with requests, for example when lots of its media is being recached at once
+	if err := t.mediaLimiter.wait(ctx, iri.Host); err != nil {
+		return nil, 0, fmt.Errorf("error waiting to fetch media from %s: %s", iri.Host, err)
+	}
+
 	l.Debugf("performing GET to %s", iri.String())
 	req, err := http.NewRequestWithContext(ctx, "GET", iri.String(), nil)
 	if err != nil {
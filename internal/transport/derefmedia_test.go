@@ -0,0 +1,127 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DerefMediaTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DerefMediaTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+}
+
+func (suite *DerefMediaTestSuite) newTransport() transport.Transport {
+	client := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Body:          io.NopCloser(bytes.NewReader([]byte("some media"))),
+			ContentLength: 10,
+		}, nil
+	})
+
+	privkey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	tc := transport.NewController(nil, nil, &federation.Clock{}, client)
+	t, err := tc.NewTransport("http://localhost:8080/users/the_mighty_zork/main-key", privkey)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return t
+}
+
+func (suite *DerefMediaTestSuite) fetch(t transport.Transport, ctx context.Context, rawURL string) error {
+	iri, err := url.Parse(rawURL)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	rc, _, err := t.DereferenceMedia(ctx, iri)
+	if err != nil {
+		return err
+	}
+	return rc.Close()
+}
+
+func (suite *DerefMediaTestSuite) TestDereferenceMediaRateLimited() {
+	viper.Set(config.Keys.MediaRemoteFetchRate, 2)
+	t := suite.newTransport()
+	ctx := context.Background()
+
+	// a short burst is allowed straight away
+	begin := time.Now()
+	suite.NoError(suite.fetch(t, ctx, "http://example.org/media/1.jpeg"))
+	suite.NoError(suite.fetch(t, ctx, "http://example.org/media/2.jpeg"))
+	suite.Less(time.Since(begin), 250*time.Millisecond)
+
+	// other hosts have their own limit
+	begin = time.Now()
+	suite.NoError(suite.fetch(t, ctx, "http://fossbros-anonymous.io/media/1.jpeg"))
+	suite.Less(time.Since(begin), 250*time.Millisecond)
+
+	// giving up while waiting should fail the fetch
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err := suite.fetch(t, timeoutCtx, "http://example.org/media/3.jpeg")
+	suite.Error(err)
+	suite.Contains(err.Error(), "context deadline exceeded")
+
+	// but anything more to the same host has to wait its turn
+	begin = time.Now()
+	suite.NoError(suite.fetch(t, ctx, "http://example.org/media/3.jpeg"))
+	suite.Greater(time.Since(begin), 300*time.Millisecond)
+}
+
+func (suite *DerefMediaTestSuite) TestDereferenceMediaNotLimited() {
+	viper.Set(config.Keys.MediaRemoteFetchRate, 0)
+	t := suite.newTransport()
+	ctx := context.Background()
+
+	begin := time.Now()
+	for i := 0; i < 20; i++ {
+		suite.NoError(suite.fetch(t, ctx, "http://example.org/media/1.jpeg"))
+	}
+	suite.Less(time.Since(begin), 250*time.Millisecond)
+}
+
+func TestDerefMediaTestSuite(t *testing.T) {
+	suite.Run(t, &DerefMediaTestSuite{})
+}
@@ -0,0 +1,148 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/activity/pub"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DerefWebPageTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DerefWebPageTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+}
+
+func (suite *DerefWebPageTestSuite) newTransport(client pub.HttpClient) transport.Transport {
+	privkey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	tc := transport.NewController(nil, nil, &federation.Clock{}, client)
+	t, err := tc.NewTransport("http://localhost:8080/users/the_mighty_zork/main-key", privkey)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return t
+}
+
+func (suite *DerefWebPageTestSuite) fetch(t transport.Transport, ctx context.Context, rawURL string) ([]byte, error) {
+	iri, err := url.Parse(rawURL)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return t.DereferenceWebPage(ctx, iri)
+}
+
+func (suite *DerefWebPageTestSuite) TestDereferenceWebPage() {
+	t := suite.newTransport(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("<html>hello</html>")),
+		}, nil
+	}))
+
+	page, err := suite.fetch(t, context.Background(), "https://example.org/about")
+	suite.NoError(err)
+	suite.Equal("<html>hello</html>", string(page))
+}
+
+func (suite *DerefWebPageTestSuite) TestDereferenceWebPageFailed() {
+	t := suite.newTransport(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+
+	page, err := suite.fetch(t, context.Background(), "https://example.org/about")
+	suite.Error(err)
+	suite.Contains(err.Error(), "connection refused")
+	suite.Nil(page)
+}
+
+func (suite *DerefWebPageTestSuite) TestDereferenceWebPageNotOK() {
+	t := suite.newTransport(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Status:     "404 Not Found",
+			Body:       io.NopCloser(strings.NewReader("<html>not here</html>")),
+		}, nil
+	}))
+
+	page, err := suite.fetch(t, context.Background(), "https://example.org/about")
+	suite.EqualError(err, "GET request to https://example.org/about failed (404): 404 Not Found")
+	suite.Nil(page)
+}
+
+func (suite *DerefWebPageTestSuite) TestDereferenceWebPageTimeout() {
+	t := suite.newTransport(testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		// a server that never answers
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	page, err := suite.fetch(t, ctx, "https://example.org/about")
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Nil(page)
+	suite.Less(time.Since(begin), time.Second)
+}
+
+func (suite *DerefWebPageTestSuite) TestDereferenceWebPageNonPublic() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Fail("the request shouldn't have been made")
+	}))
+	defer server.Close()
+
+	// a real client is used here, since it's the connection that's refused
+	t := suite.newTransport(&http.Client{})
+
+	page, err := suite.fetch(t, context.Background(), server.URL+"/about")
+	suite.ErrorIs(err, transport.ErrNonPublicAddress)
+	suite.Nil(page)
+
+	// the same goes for a hostname that resolves to a non-public address
+	page, err = suite.fetch(t, context.Background(), strings.Replace(server.URL, "127.0.0.1", "localhost", 1)+"/about")
+	suite.ErrorIs(err, transport.ErrNonPublicAddress)
+	suite.Nil(page)
+}
+
+func TestDerefWebPageTestSuite(t *testing.T) {
+	suite.Run(t, &DerefWebPageTestSuite{})
+}
@@ -0,0 +1,51 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsNamespace = "gotosocial"
+	metricsSubsystem = "federation"
+
+	deliverySucceeded = "succeeded" // deliverySucceeded is the metrics label for deliveries that the remote inbox accepted
+	deliveryFailed    = "failed"    // deliveryFailed is the metrics label for deliveries that the remote inbox refused, or that couldn't be made at all
+)
+
+var (
+	// deliveries counts attempts at delivering activities to remote inboxes, by domain and by whether they succeeded.
+	deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "deliveries_total",
+		Help:      "Number of attempts at delivering activities to remote inboxes, including retries, by domain and by whether they succeeded.",
+	}, []string{"domain", "result"})
+
+	// deliveryDuration measures how long remote inboxes take to respond to deliveries, by domain.
+	deliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "delivery_duration_seconds",
+		Help:      "Time taken by remote inboxes to respond to deliveries of activities, by domain.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"domain"})
+)
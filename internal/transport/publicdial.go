@@ -0,0 +1,124 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/superseriousbusiness/activity/pub"
+)
+
+// ErrNonPublicAddress is returned when a request would connect to an address that isn't on the public internet.
+var ErrNonPublicAddress = errors.New("address is not public")
+
+// nonPublicNetworks are ranges of addresses that aren't on the public internet, besides
+// the loopback, private, link-local and multicast ones that net.IP can check for itself.
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // "this" network
+	"100.64.0.0/10",  // carrier-grade nat
+	"192.0.0.0/24",   // ietf protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved
+	"64:ff9b::/96",   // nat64, which could map to any ipv4 address
+	"64:ff9b:1::/48", // local-use nat64
+	"2001:db8::/32",  // documentation
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isPublicIP returns true if the given ip is an address on the public internet.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicOnlyControl is a net.Dialer Control function that refuses connections to non-public addresses.
+// It's called with the address that's actually being connected to, after any dns lookup, so a
+// hostname that resolves to a non-public address can't be used to get around it.
+func publicOnlyControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("refusing to connect to %s: %w", address, ErrNonPublicAddress)
+	}
+	return nil
+}
+
+// PublicOnlyClient returns a copy of the given client that refuses to connect to non-public addresses,
+// including when following redirects. It should be used for any request to a url that a user gave us.
+// Clients other than *http.Client, such as mock clients used in testing, don't make connections of
+// their own, so they're returned as they are.
+func PublicOnlyClient(client pub.HttpClient) pub.HttpClient {
+	c, ok := client.(*http.Client)
+	if !ok {
+		return client
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnlyControl,
+	}
+
+	publicOnly := *c
+	publicOnly.Transport = &http.Transport{
+		// no proxy is used, since the proxy would be connected to instead of the requested host
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &publicOnly
+}
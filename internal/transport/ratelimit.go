@@ -0,0 +1,197 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIdleHosts is the number of hosts that a hostLimiter will keep track of before
+// it starts forgetting about hosts that haven't had any requests made to them lately.
+const maxIdleHosts = 1024
+
+// hostLimiter limits how often requests can be made to any one host. Each host gets a token bucket
+// which fills up at the given rate, and holds up to a second's worth of tokens, so short bursts of
+// requests can go ahead straight away, but anything more than that has to wait its turn.
+type hostLimiter struct {
+	rate    float64 // tokens added to each bucket per second, or 0 for no limit
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket is the token bucket for a single host.
+type bucket struct {
+	tokens float64   // can go below 0 when requests are waiting for tokens
+	last   time.Time // when tokens was last updated
+}
+
+// newHostLimiter returns a hostLimiter that allows perSecond requests to each host every second.
+// If perSecond is 0 or less, then requests won't be limited at all.
+func newHostLimiter(perSecond int) *hostLimiter {
+	return &hostLimiter{
+		rate:    float64(perSecond),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// wait blocks until a request can be made to the given host, or until ctx is done, in which case ctx's error is returned.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	host = strings.ToLower(host)
+
+	delay := l.reserve(host, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// we're not going to make the request after all, so let someone else have the token
+		l.release(host)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket for the given host, and returns how long
+// the caller needs to wait before the token is really theirs to use.
+func (l *hostLimiter) reserve(host string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		if len(l.buckets) >= maxIdleHosts {
+			l.forgetIdle(now)
+		}
+		b = &bucket{tokens: l.rate, last: now}
+		l.buckets[host] = b
+	}
+
+	b.tokens = l.refill(b, now)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / l.rate * float64(time.Second))
+}
+
+// release gives back a token that was reserved for the given host but not used.
+func (l *hostLimiter) release(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[host]; ok {
+		b.tokens++
+	}
+}
+
+// refill returns the number of tokens that the given bucket would have at the given time.
+func (l *hostLimiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.rate, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+}
+
+// forgetIdle removes buckets that have filled up again, since a full bucket is
+// no different to a new one. It should only be called when l.mu is held.
+func (l *hostLimiter) forgetIdle(now time.Time) {
+	for host, b := range l.buckets {
+		if l.refill(b, now) >= l.rate {
+			delete(l.buckets, host)
+		}
+	}
+}
+
+// hostSemaphore limits how many requests can be in flight to any one host at the same time.
+// Unlike hostLimiter, it doesn't matter how quickly requests are made, only how many are
+// waiting on a host at once, so a host that's slow to respond can't tie up more than its share.
+type hostSemaphore struct {
+	limit int // requests allowed to each host at the same time, or 0 for no limit
+	mu    sync.Mutex
+	hosts map[string]*hostSlots
+}
+
+// hostSlots are the slots for requests to a single host.
+type hostSlots struct {
+	slots chan struct{} // holds a value for each request in flight
+	users int           // requests that are in flight or waiting for a slot
+}
+
+// newHostSemaphore returns a hostSemaphore that allows limit requests to be in flight to each host at once.
+// If limit is 0 or less, then requests won't be limited at all.
+func newHostSemaphore(limit int) *hostSemaphore {
+	return &hostSemaphore{
+		limit: limit,
+		hosts: make(map[string]*hostSlots),
+	}
+}
+
+// acquire blocks until a request can be made to the given host, or until ctx is done, in which case ctx's error
+// is returned. If acquire doesn't return an error, the returned function must be called once the request is done.
+func (s *hostSemaphore) acquire(ctx context.Context, host string) (func(), error) {
+	if s.limit <= 0 {
+		return func() {}, nil
+	}
+
+	host = strings.ToLower(host)
+
+	s.mu.Lock()
+	h, ok := s.hosts[host]
+	if !ok {
+		h = &hostSlots{slots: make(chan struct{}, s.limit)}
+		s.hosts[host] = h
+	}
+	h.users++
+	s.mu.Unlock()
+
+	select {
+	case h.slots <- struct{}{}:
+		return func() {
+			<-h.slots
+			s.done(host, h)
+		}, nil
+	case <-ctx.Done():
+		s.done(host, h)
+		return nil, ctx.Err()
+	}
+}
+
+// done forgets about the slots for the given host once nobody is using or waiting for them,
+// so that the semaphore doesn't keep growing with every host that's ever been sent a request.
+func (s *hostSemaphore) done(host string, h *hostSlots) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h.users--
+	if h.users == 0 {
+		delete(s.hosts, host)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+const (
+	// deliveryRetryBackoff is how long to wait before trying a failed delivery again for the
+	// first time. The wait doubles every time delivery fails, up to deliveryRetryMaxBackoff.
+	deliveryRetryBackoff    = time.Minute
+	deliveryRetryMaxBackoff = 6 * time.Hour
+
+	// deliveryMaxAge is how long a delivery keeps being tried for, from when it first failed, before it's given up on.
+	deliveryMaxAge = 3 * 24 * time.Hour
+
+	// failedDeliveryRetention is how long deliveries that were given up on are kept around
+	// after that, so that admins can see how many deliveries have been failing lately.
+	failedDeliveryRetention = 7 * 24 * time.Hour
+
+	// deliveryRetryBatch is the most deliveries that RetryDeliveries will try in one go,
+	// and deliveryRetryWorkers is how many of them it will try at the same time.
+	deliveryRetryBatch   = 200
+	deliveryRetryWorkers = 10
+)
+
+// deliveryBackoff returns how long to wait before trying a delivery again, after it's failed the given number of times.
+func deliveryBackoff(attempts int) time.Duration {
+	backoff := deliveryRetryBackoff
+	for i := 1; i < attempts && backoff < deliveryRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > deliveryRetryMaxBackoff {
+		backoff = deliveryRetryMaxBackoff
+	}
+	return backoff
+}
+
+// retryable returns true if a delivery that failed with the given error might succeed if it's tried again later:
+// that is, if the inbox couldn't be reached at all, or if it responded with a server error or asked us to slow down.
+// Any other response means the delivery was turned down, and it'd only be turned down again.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+}
+
+// queueDeliveries stores deliveries of b to each of the given inboxes, as the local account with the given public key id, before
+// any of them are made, so that deliveries that are still waiting to be made aren't lost if we're stopped. The deliveries are
+// returned in the same order as the inboxes, or nil is returned if they couldn't be stored.
+//
+// Deliveries that haven't been tried yet are stored as due straight away, but RetryDeliveries leaves the ones that were stored
+// since this controller was created alone, since they're still waiting to be made; only those left over from before a restart
+// are picked up by it.
+func (c *controller) queueDeliveries(pubKeyID string, b []byte, inboxes []*url.URL) []*gtsmodel.Delivery {
+	if len(inboxes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	deliveries := make([]*gtsmodel.Delivery, 0, len(inboxes))
+	for _, inbox := range inboxes {
+		deliveryID, err := id.NewRandomULID()
+		if err != nil {
+			logrus.Errorf("queueDeliveries: error generating id for delivery to %s: %s", inbox, err)
+			return nil
+		}
+
+		deliveries = append(deliveries, &gtsmodel.Delivery{
+			ID:            deliveryID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			PubKeyID:      pubKeyID,
+			InboxURI:      inbox.String(),
+			Payload:       b,
+			NextAttemptAt: now,
+		})
+	}
+
+	// the caller's context might be cancelled just as we're stopped, which is exactly when we need to hang on to the deliveries
+	if err := c.db.Put(context.Background(), &deliveries); err != nil {
+		logrus.Errorf("queueDeliveries: error storing %d deliveries: %s", len(deliveries), err)
+		return nil
+	}
+	return deliveries
+}
+
+// firstDeliveryFailed schedules the first retry of the given stored delivery after it's failed for the first time,
+// or forgets about it if it was turned down. If the delivery is nil because it couldn't be stored before it was made,
+// it's stored now instead.
+func (c *controller) firstDeliveryFailed(pubKeyID string, b []byte, to *url.URL, delivery *gtsmodel.Delivery, deliverErr error) {
+	if !retryable(deliverErr) {
+		logrus.Debugf("firstDeliveryFailed: not retrying delivery to %s: %s", to, deliverErr)
+		c.forgetDelivery(delivery)
+		return
+	}
+
+	if delivery == nil {
+		deliveries := c.queueDeliveries(pubKeyID, b, []*url.URL{to})
+		if deliveries == nil {
+			return
+		}
+		delivery = deliveries[0]
+	}
+
+	now := time.Now()
+	delivery.Attempts = 1
+	delivery.LastError = deliverErr.Error()
+	delivery.UpdatedAt = now
+	delivery.NextAttemptAt = now.Add(deliveryBackoff(1))
+
+	// the delivery might have failed because its context was cancelled, which is exactly
+	// when we need to hang on to it, so don't update it with the same context
+	if err := c.db.UpdateByPrimaryKey(context.Background(), delivery); err != nil {
+		logrus.Errorf("firstDeliveryFailed: error updating delivery to %s: %s", to, err)
+	}
+}
+
+// forgetDelivery deletes the given stored delivery, if it isn't nil, since it doesn't need to be tried again.
+func (c *controller) forgetDelivery(delivery *gtsmodel.Delivery) {
+	if delivery == nil {
+		return
+	}
+
+	if err := c.db.DeleteByID(context.Background(), delivery.ID, &gtsmodel.Delivery{}); err != nil {
+		logrus.Errorf("forgetDelivery: error deleting delivery %s: %s", delivery.ID, err)
+	}
+}
+
+func (c *controller) RetryDeliveries(ctx context.Context) error {
+	now := time.Now()
+
+	if _, err := c.db.DeleteFailedDeliveries(ctx, now.Add(-failedDeliveryRetention)); err != nil {
+		return fmt.Errorf("RetryDeliveries: error deleting old failed deliveries: %s", err)
+	}
+
+	deliveries, err := c.db.GetDueDeliveries(ctx, now, c.created, deliveryRetryBatch)
+	if err != nil {
+		return fmt.Errorf("RetryDeliveries: error getting due deliveries: %s", err)
+	}
+
+	// deliveries are often made by the same few accounts, so only create one transport for each
+	transports := make(map[string]*transport)
+
+	wg := sync.WaitGroup{}
+	workers := make(chan struct{}, deliveryRetryWorkers)
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		t, ok := transports[delivery.PubKeyID]
+		if !ok {
+			t, err = c.transportForKey(ctx, delivery.PubKeyID)
+			if err != nil {
+				c.deliveryFailed(ctx, delivery, err)
+				continue
+			}
+			transports[delivery.PubKeyID] = t
+		}
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(t *transport, delivery *gtsmodel.Delivery) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			c.retryDelivery(ctx, t, delivery)
+		}(t, delivery)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// transportForKey returns a transport that signs requests with the private key of the local account with the given public key id.
+func (c *controller) transportForKey(ctx context.Context, pubKeyID string) (*transport, error) {
+	account := &gtsmodel.Account{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "public_key_uri", Value: pubKeyID}}, account); err != nil {
+		return nil, fmt.Errorf("error getting account with public key %s: %s", pubKeyID, err)
+	}
+
+	if account.PrivateKey == nil {
+		return nil, fmt.Errorf("account with public key %s has no private key", pubKeyID)
+	}
+
+	return c.newTransportForAccount(account)
+}
+
+// retryDelivery tries the given delivery again, and deletes it if it succeeds this time.
+func (c *controller) retryDelivery(ctx context.Context, t *transport, delivery *gtsmodel.Delivery) {
+	to, err := url.Parse(delivery.InboxURI)
+	if err == nil {
+		logrus.Debugf("retryDelivery: posting as %s to %s, attempt %d", delivery.PubKeyID, delivery.InboxURI, delivery.Attempts+1)
+		// don't use Deliver, so that another failure doesn't queue the delivery all over again
+		err = t.deliver(ctx, delivery.Payload, to)
+	}
+
+	if err != nil {
+		c.deliveryFailed(ctx, delivery, err)
+		return
+	}
+
+	c.forgetDelivery(delivery)
+}
+
+// deliveryFailed schedules the next attempt of the given delivery, or gives up on it if it's been failing for too long,
+// or if it was turned down.
+func (c *controller) deliveryFailed(ctx context.Context, delivery *gtsmodel.Delivery, deliverErr error) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastError = deliverErr.Error()
+	delivery.UpdatedAt = now
+	delivery.NextAttemptAt = now.Add(deliveryBackoff(delivery.Attempts))
+
+	if !retryable(deliverErr) || delivery.NextAttemptAt.After(delivery.CreatedAt.Add(deliveryMaxAge)) {
+		logrus.Debugf("deliveryFailed: giving up on delivery to %s after %d attempts: %s", delivery.InboxURI, delivery.Attempts, deliverErr)
+		delivery.NextAttemptAt = time.Time{}
+		delivery.FailedAt = now
+	}
+
+	if err := c.db.UpdateByPrimaryKey(ctx, delivery); err != nil {
+		logrus.Errorf("deliveryFailed: error updating delivery %s: %s", delivery.ID, err)
+	}
+}
@@ -0,0 +1,243 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/internal/worker"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type RetryTestSuite struct {
+	suite.Suite
+	db           db.DB
+	testAccounts map[string]*gtsmodel.Account
+
+	// status code that the mock client responds to deliveries with, and how many deliveries it's been sent
+	statusCode int
+	delivered  int
+	mu         sync.Mutex
+}
+
+func (suite *RetryTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+	suite.db = testrig.NewTestDB()
+	suite.testAccounts = testrig.NewTestAccounts()
+	testrig.StandardDBSetup(suite.db, suite.testAccounts)
+}
+
+func (suite *RetryTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *RetryTestSuite) newController() transport.Controller {
+	suite.statusCode = http.StatusServiceUnavailable
+	suite.delivered = 0
+
+	client := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		suite.mu.Lock()
+		defer suite.mu.Unlock()
+		suite.delivered++
+		return &http.Response{
+			StatusCode: suite.statusCode,
+			Status:     http.StatusText(suite.statusCode),
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	})
+
+	return testrig.NewTestTransportController(client, suite.db, worker.New[messages.FromFederator](-1, -1))
+}
+
+// getDeliveries returns all the deliveries currently stored in the database.
+func (suite *RetryTestSuite) getDeliveries() []*gtsmodel.Delivery {
+	deliveries := []*gtsmodel.Delivery{}
+	suite.NoError(suite.db.GetAll(context.Background(), &deliveries))
+	return deliveries
+}
+
+// deliver delivers an activity as zork to the inbox of remote_account_1, which is expected to fail.
+func (suite *RetryTestSuite) deliver(tc transport.Controller) {
+	ctx := context.Background()
+	t, err := tc.NewTransportForUsername(ctx, "the_mighty_zork")
+	suite.NoError(err)
+	suite.Error(t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse(suite.testAccounts["remote_account_1"].InboxURI)))
+}
+
+func (suite *RetryTestSuite) TestRetryDeliveries() {
+	ctx := context.Background()
+	tc := suite.newController()
+
+	// a failed delivery should be stored so it can be tried again
+	suite.deliver(tc)
+	deliveries := suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	suite.Equal(suite.testAccounts["local_account_1"].PublicKeyURI, deliveries[0].PubKeyID)
+	suite.Equal(suite.testAccounts["remote_account_1"].InboxURI, deliveries[0].InboxURI)
+	suite.Equal(`{"type":"Create"}`, string(deliveries[0].Payload))
+	suite.Equal(1, deliveries[0].Attempts)
+	suite.Contains(deliveries[0].LastError, "503")
+
+	// it's not due yet, so retrying shouldn't do anything
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(1, suite.delivered)
+
+	// make it due, and have it fail again
+	deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, deliveries[0]))
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(2, suite.delivered)
+
+	deliveries = suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	suite.Equal(2, deliveries[0].Attempts)
+	suite.True(deliveries[0].NextAttemptAt.After(time.Now().Add(time.Minute)))
+	suite.True(deliveries[0].FailedAt.IsZero())
+
+	// this time the remote instance is back up, so the delivery should be done with
+	suite.statusCode = http.StatusAccepted
+	deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, deliveries[0]))
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(3, suite.delivered)
+	suite.Empty(suite.getDeliveries())
+}
+
+func (suite *RetryTestSuite) TestDeliveryRejected() {
+	ctx := context.Background()
+	tc := suite.newController()
+
+	// deliveries that are turned down aren't stored, since they'd only be turned down again
+	suite.statusCode = http.StatusForbidden
+	suite.deliver(tc)
+	suite.Empty(suite.getDeliveries())
+
+	// but those that the remote instance asks us to slow down with are
+	suite.statusCode = http.StatusTooManyRequests
+	suite.deliver(tc)
+	deliveries := suite.getDeliveries()
+	suite.Len(deliveries, 1)
+
+	// if the delivery is turned down when it's retried, it's given up on straight away
+	suite.statusCode = http.StatusNotFound
+	deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, deliveries[0]))
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(3, suite.delivered)
+
+	deliveries = suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	suite.Equal(2, deliveries[0].Attempts)
+	suite.False(deliveries[0].FailedAt.IsZero())
+	suite.True(deliveries[0].NextAttemptAt.IsZero())
+}
+
+func (suite *RetryTestSuite) TestRetryDeliveriesGivesUp() {
+	ctx := context.Background()
+	tc := suite.newController()
+
+	// the delivery has been failing for days
+	suite.deliver(tc)
+	deliveries := suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	deliveries[0].CreatedAt = time.Now().Add(-4 * 24 * time.Hour)
+	deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, deliveries[0]))
+
+	// so after failing once more, it should be given up on
+	suite.NoError(tc.RetryDeliveries(ctx))
+	deliveries = suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	suite.False(deliveries[0].FailedAt.IsZero())
+	suite.True(deliveries[0].NextAttemptAt.IsZero())
+
+	pending, failed, err := suite.db.CountDeliveries(ctx)
+	suite.NoError(err)
+	suite.Equal(0, pending)
+	suite.Equal(1, failed)
+
+	// and not tried again
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(2, suite.delivered)
+}
+
+func (suite *RetryTestSuite) TestDeliverySucceeded() {
+	ctx := context.Background()
+	tc := suite.newController()
+	suite.statusCode = http.StatusAccepted
+
+	// the delivery is stored before it's made, but forgotten once it's gone through
+	t, err := tc.NewTransportForUsername(ctx, "the_mighty_zork")
+	suite.NoError(err)
+	suite.NoError(t.Deliver(ctx, []byte(`{"type":"Create"}`), testrig.URLMustParse(suite.testAccounts["remote_account_1"].InboxURI)))
+	suite.Equal(1, suite.delivered)
+	suite.Empty(suite.getDeliveries())
+}
+
+func (suite *RetryTestSuite) TestRetryDeliveriesLeftOver() {
+	ctx := context.Background()
+
+	// a delivery stored by a previous run that stopped before it could make it
+	leftOver := &gtsmodel.Delivery{
+		ID:            "01G3FJ5QK4XBYKDWWGKAE8PJ9E",
+		CreatedAt:     time.Now().Add(-time.Minute),
+		PubKeyID:      suite.testAccounts["local_account_1"].PublicKeyURI,
+		InboxURI:      suite.testAccounts["remote_account_1"].InboxURI,
+		Payload:       []byte(`{"type":"Create"}`),
+		NextAttemptAt: time.Now().Add(-time.Minute),
+	}
+	suite.NoError(suite.db.Put(ctx, leftOver))
+
+	tc := suite.newController()
+	suite.statusCode = http.StatusAccepted
+
+	// a delivery that this controller has stored but not made yet
+	waiting := &gtsmodel.Delivery{
+		ID:            "01G3FJ6HKPV7MFC4Q4S4PBXN3W",
+		CreatedAt:     time.Now(),
+		PubKeyID:      suite.testAccounts["local_account_1"].PublicKeyURI,
+		InboxURI:      suite.testAccounts["remote_account_1"].InboxURI,
+		Payload:       []byte(`{"type":"Create"}`),
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+	suite.NoError(suite.db.Put(ctx, waiting))
+
+	// only the left over delivery should be made
+	suite.NoError(tc.RetryDeliveries(ctx))
+	suite.Equal(1, suite.delivered)
+	deliveries := suite.getDeliveries()
+	suite.Len(deliveries, 1)
+	suite.Equal(waiting.ID, deliveries[0].ID)
+}
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, &RetryTestSuite{})
+}
@@ -0,0 +1,104 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-fed/httpsig"
+)
+
+// hs2019 is the algorithm name that httpsig puts in every signature it creates. It
+// tells the receiver to work out the real algorithm from the key that signed the request.
+const hs2019 = "hs2019"
+
+// signingAlgorithm returns the algorithm that requests should be signed with when using the given private key.
+func signingAlgorithm(privkey crypto.PrivateKey) httpsig.Algorithm {
+	switch privkey.(type) {
+	case ed25519.PrivateKey, *ed25519.PrivateKey:
+		return httpsig.ED25519
+	default:
+		return httpsig.RSA_SHA256
+	}
+}
+
+// peerAlgorithms keeps track of which hosts sign their own requests with a named algorithm,
+// like rsa-sha256, rather than hs2019. Older implementations don't understand hs2019, so
+// requests to those hosts are signed with the name of the real algorithm instead.
+type peerAlgorithms struct {
+	mu     sync.RWMutex
+	legacy map[string]bool
+}
+
+// newPeerAlgorithms returns a peerAlgorithms that doesn't know about any hosts yet.
+func newPeerAlgorithms() *peerAlgorithms {
+	return &peerAlgorithms{
+		legacy: make(map[string]bool),
+	}
+}
+
+// note records the algorithm name that the given host used when it signed a request to us.
+func (p *peerAlgorithms) note(host string, algorithm string) {
+	host = strings.ToLower(host)
+	legacy := algorithm != "" && !strings.EqualFold(algorithm, hs2019)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if legacy {
+		p.legacy[host] = true
+	} else {
+		delete(p.legacy, host)
+	}
+}
+
+// usesLegacy returns true if the given host has been seen signing requests with a named algorithm.
+func (p *peerAlgorithms) usesLegacy(host string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.legacy[strings.ToLower(host)]
+}
+
+// negotiatingSigner wraps an httpsig.Signer, and changes the algorithm named in the signatures
+// it creates from hs2019 to the real algorithm, for hosts that are known to expect that.
+//
+// Only the algorithm parameter changes; it isn't part of the signed string, so the signature stays valid.
+type negotiatingSigner struct {
+	httpsig.Signer
+	algorithm httpsig.Algorithm
+	peers     *peerAlgorithms
+}
+
+func (s *negotiatingSigner) SignRequest(pKey crypto.PrivateKey, pubKeyID string, r *http.Request, body []byte) error {
+	if err := s.Signer.SignRequest(pKey, pubKeyID, r, body); err != nil {
+		return err
+	}
+
+	if r.URL == nil || !s.peers.usesLegacy(r.URL.Host) {
+		return nil
+	}
+
+	signature := r.Header.Get(string(httpsig.Signature))
+	r.Header.Set(string(httpsig.Signature), strings.Replace(signature, `algorithm="`+hs2019+`"`, `algorithm="`+string(s.algorithm)+`"`, 1))
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/go-fed/httpsig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/federation"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type SigningTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SigningTestSuite) SetupTest() {
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+	viper.Set(config.Keys.MediaRemoteFetchRate, 0)
+}
+
+func (suite *SigningTestSuite) TestSignEd25519() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// keep hold of the last request that was sent out
+	var sent *http.Request
+	client := testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte("some media"))),
+		}, nil
+	})
+
+	tc := transport.NewController(nil, nil, &federation.Clock{}, client)
+	t, err := tc.NewTransport("http://localhost:8080/users/the_mighty_zork/main-key", privateKey)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	verify := func(rawURL string) string {
+		rc, _, err := t.DereferenceMedia(context.Background(), testrig.URLMustParse(rawURL))
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+		suite.NoError(rc.Close())
+
+		sent.Header.Set("Host", sent.URL.Host)
+		verifier, err := httpsig.NewVerifier(sent)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+		suite.NoError(verifier.Verify(publicKey, httpsig.ED25519))
+		return sent.Header.Get("Signature")
+	}
+
+	// by default, the algorithm is hidden
+	suite.Contains(verify("http://example.org/media/1.jpeg"), `algorithm="hs2019"`)
+
+	// hosts that name their algorithms get told ours
+	tc.NoteSignatureAlgorithm("example.org", "rsa-sha256")
+	suite.Contains(verify("http://example.org/media/1.jpeg"), `algorithm="ed25519"`)
+	suite.Contains(verify("http://fossbros-anonymous.io/media/1.jpeg"), `algorithm="hs2019"`)
+
+	// until they stop doing so
+	tc.NoteSignatureAlgorithm("example.org", "hs2019")
+	suite.Contains(verify("http://example.org/media/1.jpeg"), `algorithm="hs2019"`)
+}
+
+func TestSigningTestSuite(t *testing.T) {
+	suite.Run(t, &SigningTestSuite{})
+}
@@ -21,6 +21,7 @@ package transport
 import (
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	"io"
 	"net/url"
 	"sync"
@@ -38,9 +39,14 @@ import (
 type Transport interface {
 	pub.Transport
 	// DereferenceMedia fetches the given media attachment IRI, returning the reader and filesize.
+	// If media has been fetched from the same host too often lately, it waits until the host's
+	// rate limit allows another fetch, or until ctx is done.
 	DereferenceMedia(ctx context.Context, iri *url.URL) (io.ReadCloser, int, error)
 	// DereferenceInstance dereferences remote instance information, first by checking /api/v1/instance, and then by checking /.well-known/nodeinfo.
 	DereferenceInstance(ctx context.Context, iri *url.URL) (*gtsmodel.Instance, error)
+	// DereferenceWebPage fetches the html of the web page at the given IRI, for example to check it for links
+	// back to an account. The request isn't signed, since the page probably isn't served by a fediverse server.
+	DereferenceWebPage(ctx context.Context, iri *url.URL) ([]byte, error)
 	// Finger performs a webfinger request with the given username and domain, and returns the bytes from the response body.
 	Finger(ctx context.Context, targetUsername string, targetDomains string) ([]byte, error)
 	// SigTransport returns the underlying http signature transport wrapped by the GoToSocial transport.
@@ -49,20 +55,49 @@ type Transport interface {
 
 // transport implements the Transport interface
 type transport struct {
-	client       pub.HttpClient
-	appAgent     string
-	gofedAgent   string
-	clock        pub.Clock
-	pubKeyID     string
-	privkey      crypto.PrivateKey
-	sigTransport *pub.HttpSigTransport
-	getSigner    httpsig.Signer
-	getSignerMu  *sync.Mutex
+	client pub.HttpClient
+	// webPageClient is used for fetching arbitrary web pages, and refuses to connect to non-public addresses
+	webPageClient pub.HttpClient
+	appAgent      string
+	gofedAgent    string
+	clock         pub.Clock
+	pubKeyID      string
+	privkey       crypto.PrivateKey
+	sigTransport  *pub.HttpSigTransport
+	getSigner     httpsig.Signer
+	getSignerMu   *sync.Mutex
+	postSigner    httpsig.Signer
+	postSignerMu  *sync.Mutex
+
+	// the account that this transport acts for, and the key that it makes integrity proofs of
+	// the account's activities with; the key is nil if the account doesn't have one
+	actorURI        string
+	assertionKeyURI string
+	assertionKey    ed25519.PrivateKey
 
 	// shortcuts for dereferencing things that exist on our instance without making an http call to ourself
 
 	dereferenceFollowersShortcut func(ctx context.Context, iri *url.URL) ([]byte, error)
 	dereferenceUserShortcut      func(ctx context.Context, iri *url.URL) ([]byte, error)
+
+	// limits how often media can be fetched from any one host
+	mediaLimiter *hostLimiter
+
+	// limits how many deliveries can be made to any one host at the same time
+	deliveryLimiter *hostSemaphore
+
+	// keeps track of which domains deliveries have been failing to
+	unreachable *unreachableDomains
+
+	// stores deliveries before they're made, so that they can be retried later if they fail or if we're stopped first
+	deliveries deliveryQueue
+}
+
+// deliveryQueue stores deliveries, so that they aren't lost if they fail, or if we're stopped before they're made.
+type deliveryQueue interface {
+	queueDeliveries(pubKeyID string, b []byte, inboxes []*url.URL) []*gtsmodel.Delivery
+	firstDeliveryFailed(pubKeyID string, b []byte, to *url.URL, delivery *gtsmodel.Delivery, deliverErr error)
+	forgetDelivery(delivery *gtsmodel.Delivery)
 }
 
 func (t *transport) SigTransport() pub.Transport {
@@ -0,0 +1,379 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// unreachableProbeInterval is how often a domain that deliveries are suspended to is checked to see whether it's come back.
+const unreachableProbeInterval = time.Hour
+
+// ErrDeliveriesSuspended is returned when trying to deliver to a domain that deliveries are suspended to.
+var ErrDeliveriesSuspended = errors.New("deliveries are suspended")
+
+// unreachableDomains keeps track of which domains deliveries have been failing to, and suspends deliveries
+// to a domain once they've been failing for long enough. Domains are kept in the database so that they're
+// remembered after a restart, and in memory so that the database doesn't need to be checked before every delivery.
+//
+// mu only guards the in-memory domains, and is never held while waiting on the database, since every
+// delivery has to take it; writes to the database are ordered by each domain's own lock instead.
+type unreachableDomains struct {
+	db      db.DB
+	loadMu  sync.Mutex
+	mu      sync.Mutex
+	loaded  bool
+	domains map[string]*unreachableDomain
+}
+
+// unreachableDomain is an unreachable domain as it's kept in memory.
+type unreachableDomain struct {
+	// d and stored are guarded by unreachableDomains.mu
+	d      gtsmodel.UnreachableDomain
+	stored bool
+
+	// writeMu is held while the domain is being written to the database
+	writeMu sync.Mutex
+}
+
+func newUnreachableDomains(db db.DB) *unreachableDomains {
+	return &unreachableDomains{
+		db:      db,
+		domains: make(map[string]*unreachableDomain),
+	}
+}
+
+// load reads the unreachable domains from the database the first time it's called.
+func (u *unreachableDomains) load(ctx context.Context) error {
+	u.mu.Lock()
+	loaded := u.loaded
+	u.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	u.loadMu.Lock()
+	defer u.loadMu.Unlock()
+
+	u.mu.Lock()
+	loaded = u.loaded
+	u.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	domains := []*gtsmodel.UnreachableDomain{}
+	if err := u.db.GetAll(ctx, &domains); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error getting unreachable domains: %s", err)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, d := range domains {
+		u.domains[d.Domain] = &unreachableDomain{d: *d, stored: true}
+	}
+	u.loaded = true
+
+	return nil
+}
+
+// store writes the current state of the given domain to the database, unless it's been forgotten about in the meantime.
+// Since the state is read again once the domain's write lock is held, the last write always stores the latest state.
+func (u *unreachableDomains) store(ctx context.Context, e *unreachableDomain) error {
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+
+	u.mu.Lock()
+	d := e.d
+	stored := e.stored
+	forgotten := u.domains[d.Domain] != e
+	u.mu.Unlock()
+
+	if forgotten {
+		return nil
+	}
+
+	if !stored {
+		if err := u.db.Put(ctx, &d); err != nil {
+			return fmt.Errorf("error storing domain %s: %s", d.Domain, err)
+		}
+		u.mu.Lock()
+		e.stored = true
+		u.mu.Unlock()
+		return nil
+	}
+
+	if err := u.db.UpdateByPrimaryKey(ctx, &d); err != nil {
+		return fmt.Errorf("error updating domain %s: %s", d.Domain, err)
+	}
+	return nil
+}
+
+// newUnreachableDomain returns a new domain for keeping track of failed deliveries to the given domain.
+func newUnreachableDomain(domain string, now time.Time) (*unreachableDomain, error) {
+	domainID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, fmt.Errorf("error generating id for domain %s: %s", domain, err)
+	}
+
+	return &unreachableDomain{
+		d: gtsmodel.UnreachableDomain{
+			ID:        domainID,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Domain:    domain,
+		},
+	}, nil
+}
+
+// suspended returns true if deliveries to the given domain are suspended.
+func (u *unreachableDomains) suspended(ctx context.Context, domain string) bool {
+	if err := u.load(ctx); err != nil {
+		logrus.Errorf("unreachableDomains: %s", err)
+		return false
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	e, ok := u.domains[strings.ToLower(domain)]
+	return ok && !e.d.SuspendedAt.IsZero()
+}
+
+// failed records that a delivery to the given domain failed, and suspends deliveries to the domain
+// if every delivery to it has been failing for longer than the configured number of days.
+func (u *unreachableDomains) failed(ctx context.Context, domain string, deliverErr error) {
+	if err := u.load(ctx); err != nil {
+		logrus.Errorf("unreachableDomains: %s", err)
+		return
+	}
+
+	domain = strings.ToLower(domain)
+	now := time.Now()
+
+	u.mu.Lock()
+	e, ok := u.domains[domain]
+	if !ok {
+		var err error
+		if e, err = newUnreachableDomain(domain, now); err != nil {
+			u.mu.Unlock()
+			logrus.Errorf("unreachableDomains: %s", err)
+			return
+		}
+		u.domains[domain] = e
+	}
+
+	e.d.Failures++
+	e.d.LastError = deliverErr.Error()
+	e.d.UpdatedAt = now
+
+	days := viper.GetInt(config.Keys.FederationUnreachableDays)
+	if e.d.SuspendedAt.IsZero() && days > 0 && now.Sub(e.d.CreatedAt) >= time.Duration(days)*24*time.Hour {
+		logrus.Infof("unreachableDomains: suspending deliveries to %s, after %d failed deliveries since %s", domain, e.d.Failures, e.d.CreatedAt.Format(time.RFC3339))
+		e.d.SuspendedAt = now
+		e.d.LastProbedAt = now
+	}
+	u.mu.Unlock()
+
+	if err := u.store(ctx, e); err != nil {
+		logrus.Errorf("unreachableDomains: %s", err)
+	}
+}
+
+// suspend suspends deliveries to the given domain on an admin's say-so, and returns the domain as it's now stored.
+func (u *unreachableDomains) suspend(ctx context.Context, domain string) (*gtsmodel.UnreachableDomain, error) {
+	if err := u.load(ctx); err != nil {
+		return nil, err
+	}
+
+	domain = strings.ToLower(domain)
+	now := time.Now()
+
+	u.mu.Lock()
+	e, ok := u.domains[domain]
+	if !ok {
+		var err error
+		if e, err = newUnreachableDomain(domain, now); err != nil {
+			u.mu.Unlock()
+			return nil, err
+		}
+		u.domains[domain] = e
+	}
+
+	if e.d.SuspendedAt.IsZero() {
+		logrus.Infof("unreachableDomains: suspending deliveries to %s on an admin's say-so", domain)
+		e.d.SuspendedAt = now
+	}
+	e.d.SuspendedByAdmin = true
+	e.d.UpdatedAt = now
+	u.mu.Unlock()
+
+	if err := u.store(ctx, e); err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	d := e.d
+	u.mu.Unlock()
+
+	return &d, nil
+}
+
+// succeeded records that a delivery to the given domain succeeded, or that
+// it's otherwise known to be reachable, and resumes deliveries to it if need be.
+func (u *unreachableDomains) succeeded(ctx context.Context, domain string) error {
+	if err := u.load(ctx); err != nil {
+		return err
+	}
+
+	domain = strings.ToLower(domain)
+
+	u.mu.Lock()
+	e, ok := u.domains[domain]
+	if !ok {
+		u.mu.Unlock()
+		return nil
+	}
+	delete(u.domains, domain)
+	d := e.d
+	u.mu.Unlock()
+
+	// wait for any write that's under way to finish, so that it can't store the domain again after it's been deleted
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+
+	if err := u.db.DeleteByID(ctx, d.ID, &gtsmodel.UnreachableDomain{}); err != nil && err != db.ErrNoEntries {
+		return fmt.Errorf("error deleting domain %s: %s", domain, err)
+	}
+
+	if !d.SuspendedAt.IsZero() {
+		logrus.Infof("unreachableDomains: resuming deliveries to %s", domain)
+	}
+
+	return nil
+}
+
+// dueForProbe returns the domains that deliveries are suspended to, and that haven't been
+// checked lately, and marks them as checked now so that they're not checked again too soon.
+// Domains that an admin suspended deliveries to are never checked.
+func (u *unreachableDomains) dueForProbe(ctx context.Context) ([]string, error) {
+	if err := u.load(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := []string{}
+	dueEntries := []*unreachableDomain{}
+
+	u.mu.Lock()
+	for domain, e := range u.domains {
+		if e.d.SuspendedAt.IsZero() || e.d.SuspendedByAdmin || now.Sub(e.d.LastProbedAt) < unreachableProbeInterval {
+			continue
+		}
+
+		e.d.LastProbedAt = now
+		e.d.UpdatedAt = now
+		due = append(due, domain)
+		dueEntries = append(dueEntries, e)
+	}
+	u.mu.Unlock()
+
+	for _, e := range dueEntries {
+		if err := u.store(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return due, nil
+}
+
+func (c *controller) ResumeDeliveries(ctx context.Context, domain string) error {
+	if err := c.unreachable.succeeded(ctx, domain); err != nil {
+		return fmt.Errorf("ResumeDeliveries: %s", err)
+	}
+	return nil
+}
+
+func (c *controller) SuspendDeliveries(ctx context.Context, domain string) (*gtsmodel.UnreachableDomain, error) {
+	d, err := c.unreachable.suspend(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("SuspendDeliveries: %s", err)
+	}
+	return d, nil
+}
+
+func (c *controller) ProbeUnreachableDomains(ctx context.Context) error {
+	domains, err := c.unreachable.dueForProbe(ctx)
+	if err != nil {
+		return fmt.Errorf("ProbeUnreachableDomains: %s", err)
+	}
+
+	for _, domain := range domains {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := c.probe(ctx, domain); err != nil {
+			logrus.Debugf("ProbeUnreachableDomains: %s is still unreachable: %s", domain, err)
+			continue
+		}
+
+		if err := c.unreachable.succeeded(ctx, domain); err != nil {
+			return fmt.Errorf("ProbeUnreachableDomains: %s", err)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// probe checks whether the given domain is responding to requests again, by fetching its nodeinfo
+// discovery document. Any response that isn't a server error means there's something there again.
+func (c *controller) probe(ctx context.Context, domain string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+domain+"/.well-known/nodeinfo", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", c.appAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("GET request to %s failed (%d): %s", req.URL, resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
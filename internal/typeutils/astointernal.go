@@ -20,14 +20,21 @@ package typeutils
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"errors"
 	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
 )
 
 func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable ap.Accountable, update bool) (*gtsmodel.Account, error) {
@@ -92,6 +99,12 @@ func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable a
 		acct.Note = note
 	}
 
+	// emojis
+	// these are only extracted here, the dereferencer takes care of storing them
+	if emojis, err := ap.ExtractEmojis(accountable); err == nil {
+		acct.Emojis = emojis
+	}
+
 	// check for bot and actor type
 	switch accountable.GetTypeName() {
 	case ap.ActorPerson, ap.ActorGroup, ap.ActorOrganization:
@@ -136,6 +149,11 @@ func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable a
 		acct.InboxURI = accountable.GetActivityStreamsInbox().GetIRI().String()
 	}
 
+	// SharedInboxURI
+	if sharedInbox := ap.ExtractSharedInbox(accountable); sharedInbox != nil {
+		acct.SharedInboxURI = sharedInbox.String()
+	}
+
 	// OutboxURI
 	if accountable.GetActivityStreamsOutbox() != nil && accountable.GetActivityStreamsOutbox().GetIRI() != nil {
 		acct.OutboxURI = accountable.GetActivityStreamsOutbox().GetIRI().String()
@@ -158,16 +176,39 @@ func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable a
 
 	// TODO: FeaturedTagsURI
 
-	// TODO: alsoKnownAs
+	// alsoKnownAs
+	for _, alias := range ap.ExtractAlsoKnownAs(accountable) {
+		acct.AlsoKnownAsURIs = append(acct.AlsoKnownAsURIs, alias.String())
+	}
+
+	// movedTo
+	// we can only point to the account it moved to if we already know about it
+	if movedTo := ap.ExtractMovedTo(accountable); movedTo != nil {
+		if movedToAccount, err := c.db.GetAccountByURI(ctx, movedTo.String()); err == nil {
+			acct.MovedToAccountID = movedToAccount.ID
+		}
+	}
 
 	// publicKey
 	pkey, pkeyURL, err := ap.ExtractPublicKeyForOwner(accountable, uri)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get public key for person %s: %s", uri.String(), err)
 	}
-	acct.PublicKey = pkey
+	switch pkey := pkey.(type) {
+	case *rsa.PublicKey:
+		acct.PublicKey = pkey
+	case ed25519.PublicKey:
+		acct.PublicKeyEd25519 = pkey
+	}
 	acct.PublicKeyURI = pkeyURL.String()
 
+	// assertionMethod
+	// not every implementation makes integrity proofs yet, so it's fine if there's no key for them
+	if assertionKey, assertionKeyURL, err := ap.ExtractAssertionMethodForOwner(accountable, uri); err == nil {
+		acct.AssertionPublicKey = assertionKey
+		acct.AssertionKeyURI = assertionKeyURL.String()
+	}
+
 	return acct, nil
 }
 
@@ -197,6 +238,14 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 		status.Content = content
 	}
 
+	// articles and pages, like the posts of lemmy communities, have a title that's part of the
+	// post rather than a content warning, so show it as a heading above the rest of the content
+	if typeName := statusable.GetTypeName(); typeName == ap.ObjectArticle || typeName == ap.ObjectPage {
+		if title, err := ap.ExtractName(statusable); err == nil {
+			status.Content = "<p><strong>" + html.EscapeString(title) + "</strong></p>" + status.Content
+		}
+	}
+
 	// attachments to dereference and fetch later on (we don't do that here)
 	if attachments, err := ap.ExtractAttachments(statusable); err != nil {
 		l.Infof("ASStatusToStatus: error extracting status attachments: %s", err)
@@ -241,6 +290,9 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 		status.UpdatedAt = published
 	}
 
+	// has this status been edited since it was created?
+	status.EditedAt = ap.ExtractUpdated(statusable)
+
 	// which account posted this status?
 	// if we don't know the account yet we can dereference it later
 	attributedTo, err := ap.ExtractAttributedTo(statusable)
@@ -279,6 +331,17 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 		}
 	}
 
+	// check if there's a post that this status quotes
+	if quoteURI := ap.ExtractQuoteURI(statusable); quoteURI != nil {
+		status.QuoteOfURI = quoteURI.String()
+
+		// we may already have the quoted status in our db
+		if quoteOf, err := c.db.GetStatusByURI(ctx, quoteURI.String()); err == nil {
+			status.QuoteOfID = quoteOf.ID
+			status.QuoteOf = quoteOf
+		}
+	}
+
 	// visibility entry for this status
 	visibility, err := ap.ExtractVisibility(statusable, status.Account.FollowersURI)
 	if err != nil {
@@ -300,6 +363,22 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 	// language
 	// we might be able to extract this from the contentMap field
 
+	// poll, if this status is a question
+	if pollable, ok := statusable.(ap.Pollable); ok {
+		if poll, err := ap.ExtractPoll(pollable); err != nil {
+			l.Infof("ASStatusToStatus: error extracting status poll: %s", err)
+		} else {
+			pollID, err := id.NewULID()
+			if err != nil {
+				return nil, fmt.Errorf("ASStatusToStatus: error generating id for poll: %s", err)
+			}
+			poll.ID = pollID
+			poll.CreatedAt = status.CreatedAt
+			status.PollID = poll.ID
+			status.Poll = poll
+		}
+	}
+
 	// ActivityStreamsType
 	status.ActivityStreamsType = statusable.GetTypeName()
 
@@ -422,6 +501,55 @@ func (c *converter) ASLikeToFave(ctx context.Context, likeable ap.Likeable) (*gt
 	}, nil
 }
 
+func (c *converter) ASLikeToReaction(ctx context.Context, reactable ap.Reactable) (*gtsmodel.StatusReaction, error) {
+	emoji := ap.ExtractReaction(reactable)
+	if emoji == "" {
+		return nil, errors.New("like has no reaction emoji")
+	}
+
+	// reactions can come from all sorts of software, so only take the ones that our own users could have made
+	if err := validate.EmojiReaction(emoji); err != nil {
+		return nil, fmt.Errorf("like has an invalid reaction emoji: %s", err)
+	}
+
+	fave, err := c.ASLikeToFave(ctx, reactable)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction := &gtsmodel.StatusReaction{
+		AccountID:       fave.AccountID,
+		Account:         fave.Account,
+		TargetAccountID: fave.TargetAccountID,
+		TargetAccount:   fave.TargetAccount,
+		StatusID:        fave.StatusID,
+		Status:          fave.Status,
+		Emoji:           emoji,
+		URI:             fave.URI,
+	}
+
+	// if the reaction is a custom emoji that we already know about, link it
+	if strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") {
+		emojis, err := ap.ExtractEmojis(reactable)
+		if err != nil {
+			return nil, fmt.Errorf("error extracting emojis from like: %s", err)
+		}
+		for _, e := range emojis {
+			if ":"+e.Shortcode+":" != emoji {
+				continue
+			}
+			customEmoji := &gtsmodel.Emoji{}
+			if err := c.db.GetWhere(ctx, []db.Where{{Key: "uri", Value: e.URI}}, customEmoji); err == nil {
+				reaction.CustomEmojiID = customEmoji.ID
+				reaction.CustomEmoji = customEmoji
+			}
+			break
+		}
+	}
+
+	return reaction, nil
+}
+
 func (c *converter) ASBlockToBlock(ctx context.Context, blockable ap.Blockable) (*gtsmodel.Block, error) {
 	idProp := blockable.GetJSONLDId()
 	if idProp == nil || !idProp.IsIRI() {
@@ -457,6 +585,85 @@ func (c *converter) ASBlockToBlock(ctx context.Context, blockable ap.Blockable)
 	}, nil
 }
 
+func (c *converter) ASFlagToReport(ctx context.Context, flaggable ap.Flaggable) (*gtsmodel.Report, error) {
+	idProp := flaggable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return nil, errors.New("ASFlagToReport: no id property set on flag, or was not an iri")
+	}
+	uri := idProp.GetIRI().String()
+
+	origin, err := ap.ExtractActor(flaggable)
+	if err != nil {
+		return nil, errors.New("ASFlagToReport: error extracting actor property from flag")
+	}
+	originAccount, err := c.db.GetAccountByURI(ctx, origin.String())
+	if err != nil {
+		return nil, fmt.Errorf("ASFlagToReport: error extracting account with uri %s from the database: %s", origin.String(), err)
+	}
+
+	objects, err := ap.ExtractObjects(flaggable)
+	if err != nil {
+		return nil, errors.New("ASFlagToReport: error extracting object property from flag")
+	}
+
+	// the objects of a flag can be the reported account, and any of its statuses, in any order
+	var targetAccount *gtsmodel.Account
+	statuses := []*gtsmodel.Status{}
+	for _, object := range objects {
+		if a, err := c.db.GetAccountByURI(ctx, object.String()); err == nil {
+			if targetAccount == nil {
+				targetAccount = a
+			}
+			continue
+		}
+		if s, err := c.db.GetStatusByURI(ctx, object.String()); err == nil {
+			statuses = append(statuses, s)
+		}
+	}
+
+	// if only statuses were flagged, then it's their author that's being reported
+	if targetAccount == nil && len(statuses) != 0 {
+		targetAccount, err = c.db.GetAccountByID(ctx, statuses[0].AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("ASFlagToReport: error getting author of status %s: %s", statuses[0].URI, err)
+		}
+	}
+
+	if targetAccount == nil {
+		return nil, errors.New("ASFlagToReport: no known account or status found in flag objects")
+	}
+
+	if targetAccount.Domain != "" {
+		return nil, fmt.Errorf("ASFlagToReport: flagged account %s is not a local account", targetAccount.URI)
+	}
+
+	statusIDs := []string{}
+	for _, s := range statuses {
+		if s.AccountID == targetAccount.ID {
+			statusIDs = append(statusIDs, s.ID)
+		}
+	}
+
+	// a flag doesn't need to say why it was made
+	comment, _ := ap.ExtractContent(flaggable)
+
+	reportID, err := id.NewULID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gtsmodel.Report{
+		ID:              reportID,
+		URI:             uri,
+		AccountID:       originAccount.ID,
+		Account:         originAccount,
+		TargetAccountID: targetAccount.ID,
+		TargetAccount:   targetAccount,
+		StatusIDs:       statusIDs,
+		Comment:         comment,
+	}, nil
+}
+
 func (c *converter) ASAnnounceToStatus(ctx context.Context, announceable ap.Announceable) (*gtsmodel.Status, bool, error) {
 	status := &gtsmodel.Status{}
 	isNew := true
@@ -476,7 +683,7 @@ func (c *converter) ASAnnounceToStatus(ctx context.Context, announceable ap.Anno
 	status.URI = uri
 
 	// get the URI of the announced/boosted status
-	boostedStatusURI, err := ap.ExtractObject(announceable)
+	boostedStatusURI, err := ap.ExtractAnnounced(announceable)
 	if err != nil {
 		return nil, isNew, fmt.Errorf("ASAnnounceToStatus: error getting object from announce: %s", err)
 	}
@@ -486,10 +693,11 @@ func (c *converter) ASAnnounceToStatus(ctx context.Context, announceable ap.Anno
 		URI: boostedStatusURI.String(),
 	}
 
-	// get the published time for the announce
+	// get the published time for the announce; groups don't always set one when
+	// they pass on the posts of their members, so just use the time we got it then
 	published, err := ap.ExtractPublished(announceable)
 	if err != nil {
-		return nil, isNew, fmt.Errorf("ASAnnounceToStatus: error extracting published time: %s", err)
+		published = time.Now()
 	}
 	status.CreatedAt = published
 	status.UpdatedAt = published
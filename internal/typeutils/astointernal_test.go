@@ -22,7 +22,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/streams"
@@ -74,6 +76,141 @@ func (suite *ASToInternalTestSuite) TestParsePublicStatus() {
 	suite.Equal(`<p>&gt; So we have to examine critical thinking as a signifier, dynamic and ambiguous.  It has a normative definition, a tacit definition, and an ideal definition.  One of the hallmarks of graduate training is learning to comprehend those definitions and applying the correct one as needed for professional success.</p>`, status.Content)
 }
 
+func (suite *ASToInternalTestSuite) TestParsePoll() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(publicPollActivityJson), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Statusable)
+	suite.True(ok)
+
+	status, err := suite.typeconverter.ASStatusToStatus(context.Background(), rep)
+	suite.NoError(err)
+
+	suite.Equal(ap.ActivityQuestion, status.ActivityStreamsType)
+	suite.Equal("<p>which is the best linux distro?</p>", status.Content)
+
+	poll := status.Poll
+	suite.NotNil(poll)
+	suite.NotEmpty(poll.ID)
+	suite.Equal(poll.ID, status.PollID)
+	suite.Equal([]string{"arch btw", "gentoo"}, poll.Options)
+	suite.Equal([]int{5, 2}, poll.Votes)
+	suite.Equal(7, poll.VotersCount)
+	suite.False(poll.Multiple)
+	suite.Equal("2022-04-27T02:16:38Z", poll.ExpiresAt.Format(time.RFC3339))
+	suite.Equal("2022-04-27T02:16:38Z", poll.ClosedAt.Format(time.RFC3339))
+	suite.True(poll.Closed())
+}
+
+func (suite *ASToInternalTestSuite) TestParseMisskeyReaction() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(misskeyReactionActivityJson), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Reactable)
+	suite.True(ok)
+
+	reaction, err := suite.typeconverter.ASLikeToReaction(context.Background(), rep)
+	suite.NoError(err)
+
+	suite.Equal("🍆", reaction.Emoji)
+	suite.Empty(reaction.CustomEmojiID)
+	suite.Equal("http://fossbros-anonymous.io/likes/8xsrhkoq5c", reaction.URI)
+	suite.Equal(suite.testAccounts["remote_account_1"].ID, reaction.AccountID)
+	suite.Equal(suite.testStatuses["local_account_1_status_1"].ID, reaction.StatusID)
+	suite.Equal(suite.testAccounts["local_account_1"].ID, reaction.TargetAccountID)
+}
+
+func (suite *ASToInternalTestSuite) TestParseInvalidReaction() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(misskeyReactionActivityJson), &m)
+	suite.NoError(err)
+
+	// not an emoji, and much longer than any emoji would be
+	m["content"] = strings.Repeat("this isn't an emoji ", 100)
+	m["_misskey_reaction"] = m["content"]
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Reactable)
+	suite.True(ok)
+
+	reaction, err := suite.typeconverter.ASLikeToReaction(context.Background(), rep)
+	suite.Error(err)
+	suite.Nil(reaction)
+}
+
+func (suite *ASToInternalTestSuite) TestParseMisskeyQuote() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(misskeyQuoteActivityJson), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Statusable)
+	suite.True(ok)
+
+	status, err := suite.typeconverter.ASStatusToStatus(context.Background(), rep)
+	suite.NoError(err)
+
+	quotedStatus := suite.testStatuses["local_account_1_status_1"]
+	suite.Equal(quotedStatus.URI, status.QuoteOfURI)
+	suite.Equal(quotedStatus.ID, status.QuoteOfID)
+	suite.NotNil(status.QuoteOf)
+	suite.Empty(status.InReplyToURI)
+}
+
+func (suite *ASToInternalTestSuite) TestParseLemmyPage() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(lemmyPageActivityJson), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Statusable)
+	suite.True(ok)
+
+	status, err := suite.typeconverter.ASStatusToStatus(context.Background(), rep)
+	suite.NoError(err)
+
+	// the title of the page should be shown above its content
+	suite.Equal("<p><strong>Which distro &lt;should&gt; I use?</strong></p><p>asking for a friend</p>", status.Content)
+	suite.Empty(status.ContentWarning)
+}
+
+func (suite *ASToInternalTestSuite) TestParseLemmyAnnounce() {
+	m := make(map[string]interface{})
+	err := json.Unmarshal([]byte(lemmyAnnounceActivityJson), &m)
+	suite.NoError(err)
+
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+
+	rep, ok := t.(ap.Announceable)
+	suite.True(ok)
+
+	boost, isNew, err := suite.typeconverter.ASAnnounceToStatus(context.Background(), rep)
+	suite.NoError(err)
+	suite.True(isNew)
+
+	// the boost should be of the page created in the announced create, not of the create itself
+	suite.Equal("http://fossbros-anonymous.io/post/1234", boost.BoostOf.URI)
+	suite.Equal(suite.testAccounts["remote_account_1"].ID, boost.AccountID)
+	suite.Equal(gtsmodel.VisibilityPublic, boost.Visibility)
+	// there's no published time on the announce, so it should be given the time it was received
+	suite.WithinDuration(time.Now(), boost.CreatedAt, 30*time.Second)
+}
+
 func (suite *ASToInternalTestSuite) TestParseGargron() {
 	m := make(map[string]interface{})
 	err := json.Unmarshal([]byte(gargronAsActivityJson), &m)
@@ -87,6 +224,8 @@ func (suite *ASToInternalTestSuite) TestParseGargron() {
 
 	acct, err := suite.typeconverter.ASRepresentationToAccount(context.Background(), rep, false)
 	suite.NoError(err)
+	suite.Equal([]string{"https://tooting.ai/users/Gargron"}, acct.AlsoKnownAsURIs)
+	suite.Equal("https://mastodon.social/inbox", acct.SharedInboxURI)
 
 	fmt.Printf("%+v", acct)
 	// TODO: write assertions here, rn we're just eyeballing the output
@@ -28,6 +28,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/cache"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/visibility"
 )
 
 // TypeConverter is an interface for the common action of converting between apimodule (frontend, serializable) models,
@@ -44,6 +45,8 @@ type TypeConverter interface {
 	// if something goes wrong. The returned account should be ready to serialize on an API level, and may have sensitive fields,
 	// so serve it only to an authorized user who should have permission to see it.
 	AccountToAPIAccountSensitive(ctx context.Context, account *gtsmodel.Account) (*model.Account, error)
+	// AccountToAPIPreferences returns the posting and reading preferences of the given local account.
+	AccountToAPIPreferences(ctx context.Context, account *gtsmodel.Account) (*model.Preferences, error)
 	// AccountToAPIAccountPublic takes a db model account as a param, and returns a populated apitype account, or an error
 	// if something goes wrong. The returned account should be ready to serialize on an API level, and may NOT have sensitive fields.
 	// In other words, this is the public record that the server has of an account.
@@ -68,20 +71,63 @@ type TypeConverter interface {
 	EmojiToAPIEmoji(ctx context.Context, e *gtsmodel.Emoji) (model.Emoji, error)
 	// TagToAPITag converts a gts model tag into its api (frontend) representation for serialization on the API.
 	TagToAPITag(ctx context.Context, t *gtsmodel.Tag) (model.Tag, error)
+	// FeaturedTagToAPIFeaturedTag converts a gts model featured tag into its api (frontend) representation for serialization on the API,
+	// counting the statuses of the featuring account that use the tag. The featured tag's Tag must be populated.
+	FeaturedTagToAPIFeaturedTag(ctx context.Context, ft *gtsmodel.FeaturedTag) (*model.FeaturedTag, error)
+	// ExportToAPIExport converts a gts model export into its api (frontend) representation for serialization on the API.
+	ExportToAPIExport(ctx context.Context, e *gtsmodel.Export) (*model.Export, error)
+	// ImportToAPIImport converts a gts model import into its api (frontend) representation for serialization on the API.
+	ImportToAPIImport(ctx context.Context, i *gtsmodel.Import) (*model.Import, error)
+	// PollToAPIPoll converts a gts model poll into its api (frontend) representation for serialization on the API.
+	//
+	// Vote counts will be left out if they're hidden until the poll closes, and it hasn't closed yet.
+	// If requestingAccount is set, the poll will say whether, and how, they've voted in it.
+	PollToAPIPoll(ctx context.Context, p *gtsmodel.Poll, requestingAccount *gtsmodel.Account) (*model.Poll, error)
 	// StatusToAPIStatus converts a gts model status into its api (frontend) representation for serialization on the API.
 	//
 	// Requesting account can be nil.
 	StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error)
+	// StatusEditToAPIStatusEdit converts a version of the given status into its api (frontend) representation for serialization on the API.
+	StatusEditToAPIStatusEdit(ctx context.Context, e *gtsmodel.StatusEdit, s *gtsmodel.Status) (*model.StatusEdit, error)
+	// StatusReactionsToAPIEmojiReactions groups the given reactions to a status by emoji, and converts them into their
+	// api (frontend) representation for serialization on the API. Groups are returned in the order of their first reaction.
+	//
+	// Requesting account can be nil. If withAccounts is true, the accounts that reacted will be included with each group.
+	StatusReactionsToAPIEmojiReactions(ctx context.Context, reactions []*gtsmodel.StatusReaction, requestingAccount *gtsmodel.Account, withAccounts bool) ([]model.EmojiReaction, error)
 	// VisToAPIVis converts a gts visibility into its api equivalent
 	VisToAPIVis(ctx context.Context, m gtsmodel.Visibility) model.Visibility
 	// InstanceToAPIInstance converts a gts instance into its api equivalent for serving at /api/v1/instance
 	InstanceToAPIInstance(ctx context.Context, i *gtsmodel.Instance) (*model.Instance, error)
+	// InstanceToAPIV2Instance converts a gts instance into its api equivalent for serving at /api/v2/instance
+	InstanceToAPIV2Instance(ctx context.Context, i *gtsmodel.Instance) (*model.InstanceV2, error)
 	// RelationshipToAPIRelationship converts a gts relationship into its api equivalent for serving in various places
 	RelationshipToAPIRelationship(ctx context.Context, r *gtsmodel.Relationship) (*model.Relationship, error)
 	// NotificationToAPINotification converts a gts notification into a api notification
 	NotificationToAPINotification(ctx context.Context, n *gtsmodel.Notification) (*model.Notification, error)
 	// DomainBlockToAPIDomainBlock converts a gts model domin block into a api domain block, for serving at /api/v1/admin/domain_blocks
 	DomainBlockToAPIDomainBlock(ctx context.Context, b *gtsmodel.DomainBlock, export bool) (*model.DomainBlock, error)
+	// WebPushSubscriptionToAPIWebPushSubscription converts a gts web push subscription into its api equivalent, for serving at /api/v1/push/subscription
+	WebPushSubscriptionToAPIWebPushSubscription(ctx context.Context, s *gtsmodel.WebPushSubscription) (*model.WebPushSubscription, error)
+	// FilterKeywordToAPIFilter converts a gts filter keyword, and the filter it belongs to, into a v1 api filter, for serving at /api/v1/filters
+	FilterKeywordToAPIFilter(ctx context.Context, f *gtsmodel.Filter, k *gtsmodel.FilterKeyword) (*model.Filter, error)
+	// FilterToAPIFilterV2 converts a gts filter into its api equivalent, for serving at /api/v2/filters
+	FilterToAPIFilterV2(ctx context.Context, f *gtsmodel.Filter) (*model.FilterV2, error)
+	// FilterKeywordToAPIFilterKeyword converts a gts filter keyword into its api equivalent
+	FilterKeywordToAPIFilterKeyword(ctx context.Context, k *gtsmodel.FilterKeyword) (*model.FilterKeyword, error)
+	// FilterStatusToAPIFilterStatus converts a gts filter status into its api equivalent
+	FilterStatusToAPIFilterStatus(ctx context.Context, s *gtsmodel.FilterStatus) (*model.FilterStatus, error)
+	// FilterResultsToAPIFilterResults converts gts filter results into their api equivalent, for attaching to api statuses
+	FilterResultsToAPIFilterResults(ctx context.Context, r []*gtsmodel.FilterResult) ([]model.FilterResult, error)
+	// ConversationToAPIConversation converts a gts conversation into its api equivalent, with its last status as seen by the account the conversation belongs to
+	ConversationToAPIConversation(ctx context.Context, conv *gtsmodel.Conversation) (*model.Conversation, error)
+	// ReportToAPIReport converts a gts report into its api equivalent, for showing to the account that made it
+	ReportToAPIReport(ctx context.Context, r *gtsmodel.Report) (*model.Report, error)
+	// ReportToAdminAPIReport converts a gts report into its admin api equivalent, with the statuses it points to as seen by the requesting moderator
+	ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Report, requestingAccount *gtsmodel.Account) (*model.AdminReportInfo, error)
+	// RuleToAPIInstanceRule converts a gts rule into its api equivalent, for showing to accounts of this instance
+	RuleToAPIInstanceRule(ctx context.Context, r *gtsmodel.Rule) (model.InstanceRule, error)
+	// MarkersToAPIMarker converts the given gts markers of one account into the api representation of its markers
+	MarkersToAPIMarker(ctx context.Context, markers []*gtsmodel.Marker) (*model.Marker, error)
 
 	/*
 		FRONTEND (api) MODEL TO INTERNAL (gts) MODEL
@@ -108,8 +154,13 @@ type TypeConverter interface {
 	ASFollowToFollow(ctx context.Context, followable ap.Followable) (*gtsmodel.Follow, error)
 	// ASLikeToFave converts a remote activitystreams 'like' representation into a gts model status fave.
 	ASLikeToFave(ctx context.Context, likeable ap.Likeable) (*gtsmodel.StatusFave, error)
+	// ASLikeToReaction converts a remote activitystreams 'like' with an emoji as its content into a gts model status reaction.
+	ASLikeToReaction(ctx context.Context, reactable ap.Reactable) (*gtsmodel.StatusReaction, error)
 	// ASBlockToBlock converts a remote activity streams 'block' representation into a gts model block.
 	ASBlockToBlock(ctx context.Context, blockable ap.Blockable) (*gtsmodel.Block, error)
+	// ASFlagToReport converts a remote activitystreams 'flag' about a local account into a gts model report.
+	// Any objects of the flag that aren't the reported account, or statuses of that account, are ignored.
+	ASFlagToReport(ctx context.Context, flaggable ap.Flaggable) (*gtsmodel.Report, error)
 	// ASAnnounceToStatus converts an activitystreams 'announce' into a status.
 	//
 	// The returned bool indicates whether this status is new (true) or not new (false).
@@ -136,8 +187,9 @@ type TypeConverter interface {
 	// suitable for serving to requesters to whom we want to give as little information as possible because
 	// we don't trust them (yet).
 	AccountToASMinimal(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error)
-	// StatusToAS converts a gts model status into an activity streams note, suitable for federation
-	StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error)
+	// StatusToAS converts a gts model status into an activity streams note, suitable for federation.
+	// If the status has a poll attached to it, it will be converted into a question instead.
+	StatusToAS(ctx context.Context, s *gtsmodel.Status) (ap.Statusable, error)
 	// FollowToASFollow converts a gts model Follow into an activity streams Follow, suitable for federation
 	FollowToAS(ctx context.Context, f *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsFollow, error)
 	// MentionToAS converts a gts model mention into an activity streams Mention, suitable for federation
@@ -146,10 +198,26 @@ type TypeConverter interface {
 	AttachmentToAS(ctx context.Context, a *gtsmodel.MediaAttachment) (vocab.ActivityStreamsDocument, error)
 	// FaveToAS converts a gts model status fave into an activityStreams LIKE, suitable for federation.
 	FaveToAS(ctx context.Context, f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error)
+	// ReactionToAS converts a gts model status reaction into an activityStreams LIKE with the emoji as its content, suitable for federation.
+	ReactionToAS(ctx context.Context, r *gtsmodel.StatusReaction) (vocab.ActivityStreamsLike, error)
 	// BoostToAS converts a gts model boost into an activityStreams ANNOUNCE, suitable for federation
 	BoostToAS(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error)
 	// BlockToAS converts a gts model block into an activityStreams BLOCK, suitable for federation.
 	BlockToAS(ctx context.Context, block *gtsmodel.Block) (vocab.ActivityStreamsBlock, error)
+	// PollVoteToAS converts a gts model poll vote into an activityStreams NOTE, named after the chosen option and in reply
+	// to the status with the poll, suitable for wrapping in a CREATE and sending to the author of the poll.
+	PollVoteToAS(ctx context.Context, v *gtsmodel.PollVote) (vocab.ActivityStreamsNote, error)
+	// ReportToASFlag converts a gts model report into an activityStreams FLAG, suitable for forwarding to the instance of the
+	// reported account. The flag is made by this instance's instance account, so that the account that made the report stays private.
+	ReportToASFlag(ctx context.Context, r *gtsmodel.Report) (vocab.ActivityStreamsFlag, error)
+	// TombstoneToAS converts a gts model tombstone into an activityStreams TOMBSTONE, which is served in place of something that's been deleted.
+	TombstoneToAS(ctx context.Context, t *gtsmodel.Tombstone) (vocab.ActivityStreamsTombstone, error)
+	// MoveToAS converts a move of originAccount to targetAccount into an activityStreams MOVE, suitable for federation to originAccount's followers.
+	MoveToAS(ctx context.Context, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsMove, error)
+	// PinToAS converts a pinned gts model status into an activityStreams ADD of the status to its owner's featured collection, suitable for federation.
+	PinToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsAdd, error)
+	// UnpinToAS converts an unpinned gts model status into an activityStreams REMOVE of the status from its owner's featured collection, suitable for federation.
+	UnpinToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsRemove, error)
 	// StatusToASRepliesCollection converts a gts model status into an activityStreams REPLIES collection.
 	StatusToASRepliesCollection(ctx context.Context, status *gtsmodel.Status, onlyOtherAccounts bool) (vocab.ActivityStreamsCollection, error)
 	// StatusURIsToASRepliesPage returns a collection page with appropriate next/part of pagination.
@@ -166,6 +234,31 @@ type TypeConverter interface {
 	//
 	// Appropriate 'next' and 'prev' fields will be created based on the highest and lowest IDs present in the statuses slice.
 	StatusesToASOutboxPage(ctx context.Context, outboxID string, maxID string, minID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollectionPage, error)
+	// StatusesToASOutboxCollection returns an ordered collection with creates of the given statuses, and announces of any boosts, as contents.
+	//
+	// Unlike the outbox that's served to other instances, the whole outbox is in the one collection, and the statuses aren't just IRIs,
+	// so that it's suitable for archiving an account.
+	StatusesToASOutboxCollection(ctx context.Context, outboxID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error)
+	// StatusesToASFeaturedCollection returns an ordered collection with the given pinned statuses as contents,
+	// for serving as the featured collection of an account.
+	StatusesToASFeaturedCollection(ctx context.Context, featuredCollectionID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error)
+	// AccountsToASFeaturedAccountsCollection returns an ordered collection with the URIs of the given endorsed accounts as contents,
+	// for serving as the featured accounts collection of an account.
+	AccountsToASFeaturedAccountsCollection(ctx context.Context, featuredAccountsID string, accounts []*gtsmodel.Account) (vocab.ActivityStreamsOrderedCollection, error)
+	// FeaturedTagsToASCollection returns an ordered collection with the given featured tags as hashtags,
+	// for serving as the featured tags collection of an account. The featured tags' Tags must be populated.
+	FeaturedTagsToASCollection(ctx context.Context, featuredTagsID string, featuredTags []*gtsmodel.FeaturedTag) (vocab.ActivityStreamsOrderedCollection, error)
+	// FollowsToASCollection returns an ordered collection for the followers or following of an account, with the given total number of items.
+	// The returned collection won't have any actual entries. Unless hidden is true, its 'first' field links to where entries can be obtained.
+	FollowsToASCollection(ctx context.Context, collectionID string, totalItems int, hidden bool) (vocab.ActivityStreamsOrderedCollection, error)
+	// FollowsToASCollectionPage returns an ordered collection page for the followers or following of an account, with the URIs of the
+	// accounts in the given follows as contents. If followers is true, the owners of the follows are used; otherwise their targets are.
+	//
+	// The maxID and minID should be the parameters that were passed to the database to obtain the given follows.
+	// These will be used to create the 'id' field of the collection.
+	//
+	// Appropriate 'next' and 'prev' fields will be created based on the highest and lowest IDs present in the follows slice.
+	FollowsToASCollectionPage(ctx context.Context, collectionID string, maxID string, minID string, totalItems int, follows []*gtsmodel.Follow, followers bool) (vocab.ActivityStreamsOrderedCollectionPage, error)
 
 	/*
 		INTERNAL (gts) MODEL TO INTERNAL MODEL
@@ -175,6 +268,8 @@ type TypeConverter interface {
 	FollowRequestToFollow(ctx context.Context, f *gtsmodel.FollowRequest) *gtsmodel.Follow
 	// StatusToBoost wraps the given status into a boosting status.
 	StatusToBoost(ctx context.Context, s *gtsmodel.Status, boostingAccount *gtsmodel.Account) (*gtsmodel.Status, error)
+	// StatusToEdit copies the current version of the given status into a status edit, so that it can be kept when the status is edited.
+	StatusToEdit(ctx context.Context, s *gtsmodel.Status) (*gtsmodel.StatusEdit, error)
 
 	/*
 		WRAPPER CONVENIENCE FUNCTIONS
@@ -182,16 +277,20 @@ type TypeConverter interface {
 
 	// WrapPersonInUpdate
 	WrapPersonInUpdate(person vocab.ActivityStreamsPerson, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error)
-	// WrapNoteInCreate wraps a Note with a Create activity.
+	// WrapNoteInCreate wraps a Note (or a Question, for statuses with a poll) with a Create activity.
 	//
 	// If objectIRIOnly is set to true, then the function won't put the *entire* note in the Object field of the Create,
 	// but just the AP URI of the note. This is useful in cases where you want to give a remote server something to dereference,
 	// and still have control over whether or not they're allowed to actually see the contents.
-	WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error)
+	WrapNoteInCreate(note ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error)
+	// WrapNoteInUpdate wraps a Note (or a Question, for statuses with a poll) with an Update activity, addressed to the same recipients as the note itself.
+	// This is used to let other instances know when something about a status has changed since it was created.
+	WrapNoteInUpdate(note ap.Statusable, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error)
 }
 
 type converter struct {
 	db      db.DB
+	filter  visibility.Filter
 	asCache cache.Cache
 }
 
@@ -199,6 +298,7 @@ type converter struct {
 func NewConverter(db db.DB) TypeConverter {
 	return &converter{
 		db:      db,
+		filter:  visibility.NewFilter(db),
 		asCache: cache.New(),
 	}
 }
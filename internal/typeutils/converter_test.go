@@ -367,6 +367,153 @@ const (
 		}
 	  }	  
 	`
+	publicPollActivityJson = `
+	{
+		"@context": [
+		  "https://www.w3.org/ns/activitystreams",
+		  {
+			"ostatus": "http://ostatus.org#",
+			"atomUri": "ostatus:atomUri",
+			"inReplyToAtomUri": "ostatus:inReplyToAtomUri",
+			"conversation": "ostatus:conversation",
+			"sensitive": "as:sensitive",
+			"toot": "http://joinmastodon.org/ns#",
+			"votersCount": "toot:votersCount"
+		  }
+		],
+		"id": "http://fossbros-anonymous.io/users/foss_satan/statuses/108195913206705299",
+		"type": "Question",
+		"summary": null,
+		"inReplyTo": null,
+		"published": "2022-04-26T02:16:38Z",
+		"url": "http://fossbros-anonymous.io/@foss_satan/108195913206705299",
+		"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+		"to": [
+		  "https://www.w3.org/ns/activitystreams#Public"
+		],
+		"cc": [
+		  "http://fossbros-anonymous.io/users/foss_satan/followers"
+		],
+		"sensitive": false,
+		"atomUri": "http://fossbros-anonymous.io/users/foss_satan/statuses/108195913206705299",
+		"content": "<p>which is the best linux distro?</p>",
+		"attachment": [],
+		"tag": [],
+		"endTime": "2022-04-27T02:16:38Z",
+		"closed": "2022-04-27T02:16:38Z",
+		"votersCount": 7,
+		"oneOf": [
+		  {
+			"type": "Note",
+			"name": "arch btw",
+			"replies": {
+			  "type": "Collection",
+			  "totalItems": 5
+			}
+		  },
+		  {
+			"type": "Note",
+			"name": "gentoo",
+			"replies": {
+			  "type": "Collection",
+			  "totalItems": 2
+			}
+		  }
+		]
+	  }
+	`
+	misskeyReactionActivityJson = `
+	{
+		"@context": [
+		  "https://www.w3.org/ns/activitystreams",
+		  "https://w3id.org/security/v1",
+		  {
+			"_misskey_reaction": "misskey:_misskey_reaction",
+			"misskey": "https://misskey-hub.net/ns#"
+		  }
+		],
+		"id": "http://fossbros-anonymous.io/likes/8xsrhkoq5c",
+		"type": "Like",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"object": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+		"content": "🍆",
+		"_misskey_reaction": "🍆"
+	  }
+	`
+	misskeyQuoteActivityJson = `
+	{
+		"@context": [
+		  "https://www.w3.org/ns/activitystreams",
+		  "https://w3id.org/security/v1",
+		  {
+			"sensitive": "as:sensitive",
+			"quoteUrl": "as:quoteUrl",
+			"_misskey_quote": "misskey:_misskey_quote",
+			"misskey": "https://misskey-hub.net/ns#"
+		  }
+		],
+		"id": "http://fossbros-anonymous.io/notes/8xtn2xpkgu",
+		"type": "Note",
+		"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+		"summary": null,
+		"content": "<p>look at this<br><br>RE: <a href=\"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY\">http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY</a></p>",
+		"_misskey_content": "look at this",
+		"_misskey_quote": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+		"quoteUrl": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+		"published": "2022-04-28T10:15:42.612Z",
+		"to": [
+		  "https://www.w3.org/ns/activitystreams#Public"
+		],
+		"cc": [
+		  "http://fossbros-anonymous.io/users/foss_satan/followers"
+		],
+		"inReplyTo": null,
+		"attachment": [],
+		"sensitive": false,
+		"tag": []
+	  }
+	`
+	lemmyPageActivityJson = `
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/post/1234",
+		"type": "Page",
+		"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+		"to": [
+		  "http://fossbros-anonymous.io/c/linux",
+		  "https://www.w3.org/ns/activitystreams#Public"
+		],
+		"name": "Which distro <should> I use?",
+		"content": "<p>asking for a friend</p>",
+		"mediaType": "text/html",
+		"published": "2022-04-28T10:15:42.612Z"
+	  }
+	`
+	lemmyAnnounceActivityJson = `
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "http://fossbros-anonymous.io/activities/announce/5678",
+		"type": "Announce",
+		"actor": "http://fossbros-anonymous.io/users/foss_satan",
+		"to": [
+		  "https://www.w3.org/ns/activitystreams#Public"
+		],
+		"cc": [
+		  "http://fossbros-anonymous.io/users/foss_satan/followers"
+		],
+		"object": {
+		  "id": "http://fossbros-anonymous.io/activities/create/9012",
+		  "type": "Create",
+		  "actor": "http://fossbros-anonymous.io/users/foss_satan",
+		  "object": {
+			"id": "http://fossbros-anonymous.io/post/1234",
+			"type": "Page",
+			"attributedTo": "http://fossbros-anonymous.io/users/foss_satan",
+			"name": "Which distro should I use?"
+		  }
+		}
+	  }
+	`
 )
 
 type TypeUtilsTestSuite struct {
@@ -374,6 +521,7 @@ type TypeUtilsTestSuite struct {
 	db           db.DB
 	testAccounts map[string]*gtsmodel.Account
 	testStatuses map[string]*gtsmodel.Status
+	testEmojis   map[string]*gtsmodel.Emoji
 	testPeople   map[string]vocab.ActivityStreamsPerson
 
 	typeconverter typeutils.TypeConverter
@@ -386,6 +534,7 @@ func (suite *TypeUtilsTestSuite) SetupSuite() {
 	suite.db = testrig.NewTestDB()
 	suite.testAccounts = testrig.NewTestAccounts()
 	suite.testStatuses = testrig.NewTestStatuses()
+	suite.testEmojis = testrig.NewTestEmojis()
 	suite.testPeople = testrig.NewTestFediPeople()
 	suite.typeconverter = typeutils.NewConverter(suite.db)
 }
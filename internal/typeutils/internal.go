@@ -83,3 +83,29 @@ func (c *converter) StatusToBoost(ctx context.Context, s *gtsmodel.Status, boost
 
 	return boostWrapperStatus, nil
 }
+
+func (c *converter) StatusToEdit(ctx context.Context, s *gtsmodel.Status) (*gtsmodel.StatusEdit, error) {
+	editID, err := id.NewULID()
+	if err != nil {
+		return nil, err
+	}
+
+	// this version of the status was written either when it was last edited, or when it was created
+	versionCreatedAt := s.EditedAt
+	if versionCreatedAt.IsZero() {
+		versionCreatedAt = s.CreatedAt
+	}
+
+	return &gtsmodel.StatusEdit{
+		ID:             editID,
+		CreatedAt:      versionCreatedAt,
+		UpdatedAt:      versionCreatedAt,
+		StatusID:       s.ID,
+		Content:        s.Content,
+		ContentWarning: s.ContentWarning,
+		Text:           s.Text,
+		Sensitive:      s.Sensitive,
+		AttachmentIDs:  s.AttachmentIDs,
+		EmojiIDs:       s.EmojiIDs,
+	}, nil
+}
@@ -30,9 +30,12 @@ import (
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
 // const (
@@ -107,7 +110,18 @@ func (c *converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	person.SetTootFeatured(featuredProp)
 
 	// featuredTags
-	// NOT IMPLEMENTED
+	// Hashtags shown on the profile.
+	// This isn't in the go-fed vocabulary, so it's set as an unknown property.
+	if a.Domain == "" {
+		person.GetUnknownProperties()[ap.PropertyFeaturedTags] = uris.GenerateURIForFeaturedTags(a.Username)
+	}
+
+	// endorsements
+	// Accounts shown on the profile.
+	// This isn't in the go-fed vocabulary either.
+	if a.Domain == "" {
+		person.GetUnknownProperties()[ap.PropertyEndorsements] = uris.GenerateURIForFeaturedAccounts(a.Username)
+	}
 
 	// preferredUsername
 	// Used for Webfinger lookup. Must be unique on the domain, and must correspond to a Webfinger acct: URI.
@@ -160,7 +174,21 @@ func (c *converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 
 	// alsoKnownAs
 	// Required for Move activity.
-	// TODO: NOT IMPLEMENTED **YET** -- this needs to be added as an activitypub extension to https://github.com/go-fed/activity, see https://github.com/go-fed/activity/tree/master/astool
+	// This isn't in the go-fed vocabulary, so it's set as an unknown property.
+	if len(a.AlsoKnownAsURIs) != 0 {
+		person.GetUnknownProperties()[ap.PropertyAlsoKnownAs] = a.AlsoKnownAsURIs
+	}
+
+	// movedTo
+	// Set once this account has moved to another account.
+	if a.MovedToAccountID != "" {
+		movedTo, err := c.db.GetAccountByID(ctx, a.MovedToAccountID)
+		if err == nil {
+			person.GetUnknownProperties()[ap.PropertyMovedTo] = movedTo.URI
+		} else {
+			logrus.Errorf("AccountToAS: error getting moved to account with id %s: %s", a.MovedToAccountID, err)
+		}
+	}
 
 	// publicKey
 	// Required for signatures.
@@ -202,8 +230,39 @@ func (c *converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 	// set the public key property on the Person
 	person.SetW3IDSecurityV1PublicKey(publicKeyProp)
 
+	// assertionMethod
+	// The key that integrity proofs of this account's activities can be verified with.
+	// This isn't in the go-fed vocabulary, so it's set as an unknown property.
+	if len(a.AssertionPublicKey) != 0 && a.AssertionKeyURI != "" {
+		person.GetUnknownProperties()[ap.PropertyAssertionMethod] = []interface{}{
+			map[string]interface{}{
+				"id":                 a.AssertionKeyURI,
+				"type":               "Multikey",
+				"controller":         a.URI,
+				"publicKeyMultibase": ap.EncodeMultikey(a.AssertionPublicKey),
+			},
+		}
+	}
+
 	// tag
-	// TODO: Any tags used in the summary of this profile
+	// Used for custom emojis in the display name, summary and fields of this profile.
+	// TODO: Any hashtags or mentions used in the summary of this profile
+	if len(a.EmojiIDs) != 0 {
+		emojis, err := c.db.GetEmojisByIDs(ctx, a.EmojiIDs)
+		if err != nil {
+			return nil, fmt.Errorf("AccountToAS: error getting emojis: %s", err)
+		}
+
+		tagProp := streams.NewActivityStreamsTagProperty()
+		for _, emoji := range emojis {
+			asEmoji, err := emojiToAS(emoji)
+			if err != nil {
+				return nil, fmt.Errorf("AccountToAS: error converting emoji with id %s to activitystreams: %s", emoji.ID, err)
+			}
+			tagProp.AppendTootEmoji(asEmoji)
+		}
+		person.SetActivityStreamsTag(tagProp)
+	}
 
 	// attachment
 	// Used for profile fields.
@@ -348,12 +407,35 @@ func (c *converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account)
 	return person, nil
 }
 
-func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error) {
-	// first check if we have this note in our asCache already
-	if noteI, err := c.asCache.Fetch(s.ID); err == nil {
-		if note, ok := noteI.(vocab.ActivityStreamsNote); ok {
-			// we have it, so just return it as-is
-			return note, nil
+// statusableBuilder is fulfilled by the activitystreams types that a status can be converted into,
+// so that StatusToAS can set the properties they have in common without caring which type it's building.
+type statusableBuilder interface {
+	ap.Statusable
+	SetJSONLDId(vocab.JSONLDIdProperty)
+	SetActivityStreamsSummary(vocab.ActivityStreamsSummaryProperty)
+	SetActivityStreamsInReplyTo(vocab.ActivityStreamsInReplyToProperty)
+	SetActivityStreamsPublished(vocab.ActivityStreamsPublishedProperty)
+	SetActivityStreamsUpdated(vocab.ActivityStreamsUpdatedProperty)
+	SetActivityStreamsUrl(vocab.ActivityStreamsUrlProperty)
+	SetActivityStreamsAttributedTo(vocab.ActivityStreamsAttributedToProperty)
+	SetActivityStreamsTag(vocab.ActivityStreamsTagProperty)
+	SetActivityStreamsTo(vocab.ActivityStreamsToProperty)
+	SetActivityStreamsCc(vocab.ActivityStreamsCcProperty)
+	SetActivityStreamsContent(vocab.ActivityStreamsContentProperty)
+	SetActivityStreamsAttachment(vocab.ActivityStreamsAttachmentProperty)
+	SetActivityStreamsReplies(vocab.ActivityStreamsRepliesProperty)
+	SetActivityStreamsSensitive(vocab.ActivityStreamsSensitiveProperty)
+}
+
+func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (ap.Statusable, error) {
+	// first check if we have this status in our asCache already (statuses with a poll or edits never are, see below)
+	cacheable := s.PollID == "" && s.EditedAt.IsZero()
+	if cacheable {
+		if statusableI, err := c.asCache.Fetch(s.ID); err == nil {
+			if statusable, ok := statusableI.(ap.Statusable); ok {
+				// we have it, so just return it as-is
+				return statusable, nil
+			}
 		}
 	}
 
@@ -369,8 +451,23 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 		s.Account = a
 	}
 
-	// create the Note!
-	status := streams.NewActivityStreamsNote()
+	// statuses with a poll are represented as a Question, everything else is a Note
+	var status statusableBuilder
+	if s.PollID != "" {
+		if s.Poll == nil {
+			p, err := c.db.GetPollByID(ctx, s.PollID)
+			if err != nil {
+				return nil, fmt.Errorf("StatusToAS: error retrieving poll from db: %s", err)
+			}
+			s.Poll = p
+		}
+
+		question := streams.NewActivityStreamsQuestion()
+		c.setPollProperties(s.Poll, question)
+		status = question
+	} else {
+		status = streams.NewActivityStreamsNote()
+	}
 
 	// id
 	statusURI, err := url.Parse(s.URI)
@@ -414,6 +511,13 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 	publishedProp.Set(s.CreatedAt)
 	status.SetActivityStreamsPublished(publishedProp)
 
+	// updated -- only set if the status has been edited
+	if !s.EditedAt.IsZero() {
+		updatedProp := streams.NewActivityStreamsUpdatedProperty()
+		updatedProp.Set(s.EditedAt)
+		status.SetActivityStreamsUpdated(updatedProp)
+	}
+
 	// url
 	if s.URL != "" {
 		sURL, err := url.Parse(s.URL)
@@ -453,6 +557,35 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 	// tag -- hashtags
 	// TODO
 
+	// tag -- quoted status, as a link with the activitystreams media type (FEP-e232)
+	if s.QuoteOfURI != "" {
+		quoteURI, err := url.Parse(s.QuoteOfURI)
+		if err != nil {
+			return nil, fmt.Errorf("StatusToAS: error parsing url %s: %s", s.QuoteOfURI, err)
+		}
+
+		quoteLink := streams.NewActivityStreamsLink()
+
+		mediaTypeProp := streams.NewActivityStreamsMediaTypeProperty()
+		mediaTypeProp.Set(ap.MediaTypeActivityStreams)
+		quoteLink.SetActivityStreamsMediaType(mediaTypeProp)
+
+		hrefProp := streams.NewActivityStreamsHrefProperty()
+		hrefProp.Set(quoteURI)
+		quoteLink.SetActivityStreamsHref(hrefProp)
+
+		nameProp := streams.NewActivityStreamsNameProperty()
+		nameProp.AppendXMLSchemaString("RE: " + s.QuoteOfURI)
+		quoteLink.SetActivityStreamsName(nameProp)
+
+		tagProp.AppendActivityStreamsLink(quoteLink)
+
+		// also set the properties that misskey and fedibird use for quotes
+		status.GetUnknownProperties()[ap.PropertyQuoteURL] = s.QuoteOfURI
+		status.GetUnknownProperties()[ap.PropertyQuoteURI] = s.QuoteOfURI
+		status.GetUnknownProperties()[ap.PropertyMisskeyQuote] = s.QuoteOfURI
+	}
+
 	status.SetActivityStreamsTag(tagProp)
 
 	// parse out some URIs we need here
@@ -551,14 +684,79 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 	sensitiveProp.AppendXMLSchemaBoolean(s.Sensitive)
 	status.SetActivityStreamsSensitive(sensitiveProp)
 
-	// put the note in our cache in case we need it again soon
-	if err := c.asCache.Store(s.ID, status); err != nil {
-		return nil, err
+	// put the status in our cache in case we need it again soon, unless it has a poll,
+	// since the vote counts can change at any time, or it's been edited, since any version
+	// of it that's already in the cache would be out of date
+	if cacheable {
+		if err := c.asCache.Store(s.ID, status); err != nil {
+			return nil, err
+		}
 	}
 
 	return status, nil
 }
 
+// setPollProperties sets the options, vote counts, and end time of the given poll on a question.
+func (c *converter) setPollProperties(p *gtsmodel.Poll, question vocab.ActivityStreamsQuestion) {
+	// vote counts are kept to ourselves until the poll is closed if the author asked for them to be hidden
+	showCounts := !p.HideCounts || p.Closed()
+
+	oneOfProp := streams.NewActivityStreamsOneOfProperty()
+	anyOfProp := streams.NewActivityStreamsAnyOfProperty()
+	for i, title := range p.Options {
+		option := streams.NewActivityStreamsNote()
+
+		nameProp := streams.NewActivityStreamsNameProperty()
+		nameProp.AppendXMLSchemaString(title)
+		option.SetActivityStreamsName(nameProp)
+
+		// the number of votes for each option is given as the total items of its replies
+		votes := 0
+		if showCounts && i < len(p.Votes) {
+			votes = p.Votes[i]
+		}
+		totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+		totalItemsProp.Set(votes)
+		replies := streams.NewActivityStreamsCollection()
+		replies.SetActivityStreamsTotalItems(totalItemsProp)
+		repliesProp := streams.NewActivityStreamsRepliesProperty()
+		repliesProp.SetActivityStreamsCollection(replies)
+		option.SetActivityStreamsReplies(repliesProp)
+
+		if p.Multiple {
+			anyOfProp.AppendActivityStreamsNote(option)
+		} else {
+			oneOfProp.AppendActivityStreamsNote(option)
+		}
+	}
+
+	if p.Multiple {
+		question.SetActivityStreamsAnyOf(anyOfProp)
+	} else {
+		question.SetActivityStreamsOneOf(oneOfProp)
+	}
+
+	if !p.ExpiresAt.IsZero() {
+		endTimeProp := streams.NewActivityStreamsEndTimeProperty()
+		endTimeProp.Set(p.ExpiresAt)
+		question.SetActivityStreamsEndTime(endTimeProp)
+	}
+
+	if !p.ClosedAt.IsZero() {
+		closedProp := streams.NewActivityStreamsClosedProperty()
+		closedProp.AppendXMLSchemaDateTime(p.ClosedAt)
+		question.SetActivityStreamsClosed(closedProp)
+	}
+
+	votersCount := 0
+	if showCounts {
+		votersCount = p.VotersCount
+	}
+	votersCountProp := streams.NewTootVotersCountProperty()
+	votersCountProp.Set(votersCount)
+	question.SetTootVotersCount(votersCountProp)
+}
+
 func (c *converter) FollowToAS(ctx context.Context, f *gtsmodel.Follow, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsFollow, error) {
 	// parse out the various URIs we need for this
 	// origin account (who's doing the follow)
@@ -761,6 +959,50 @@ func (c *converter) FaveToAS(ctx context.Context, f *gtsmodel.StatusFave) (vocab
 	return like, nil
 }
 
+func (c *converter) ReactionToAS(ctx context.Context, r *gtsmodel.StatusReaction) (vocab.ActivityStreamsLike, error) {
+	// a reaction is federated as a like with some extra bits on it, so start from that
+	like, err := c.FaveToAS(ctx, &gtsmodel.StatusFave{
+		AccountID:       r.AccountID,
+		Account:         r.Account,
+		TargetAccountID: r.TargetAccountID,
+		TargetAccount:   r.TargetAccount,
+		StatusID:        r.StatusID,
+		Status:          r.Status,
+		URI:             r.URI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ReactionToAS: error converting reaction to like: %s", err)
+	}
+
+	// set the emoji as the content, which is what pleroma looks at
+	contentProp := streams.NewActivityStreamsContentProperty()
+	contentProp.AppendXMLSchemaString(r.Emoji)
+	like.SetActivityStreamsContent(contentProp)
+
+	// and as the misskey reaction too, so misskey doesn't treat it as a plain like
+	like.GetUnknownProperties()[ap.PropertyMisskeyReaction] = r.Emoji
+
+	// custom emojis are tagged, so that remote instances know where to get the image from
+	if r.CustomEmojiID != "" {
+		if r.CustomEmoji == nil {
+			customEmoji := &gtsmodel.Emoji{}
+			if err := c.db.GetByID(ctx, r.CustomEmojiID, customEmoji); err != nil {
+				return nil, fmt.Errorf("ReactionToAS: error getting emoji with id %s: %s", r.CustomEmojiID, err)
+			}
+			r.CustomEmoji = customEmoji
+		}
+		asEmoji, err := emojiToAS(r.CustomEmoji)
+		if err != nil {
+			return nil, fmt.Errorf("ReactionToAS: error converting emoji with id %s to activitystreams: %s", r.CustomEmojiID, err)
+		}
+		tagProp := streams.NewActivityStreamsTagProperty()
+		tagProp.AppendTootEmoji(asEmoji)
+		like.SetActivityStreamsTag(tagProp)
+	}
+
+	return like, nil
+}
+
 func (c *converter) BoostToAS(ctx context.Context, boostWrapperStatus *gtsmodel.Status, boostingAccount *gtsmodel.Account, boostedAccount *gtsmodel.Account) (vocab.ActivityStreamsAnnounce, error) {
 	// the boosted status is probably pinned to the boostWrapperStatus but double check to make sure
 	if boostWrapperStatus.BoostOf == nil {
@@ -904,6 +1146,311 @@ func (c *converter) BlockToAS(ctx context.Context, b *gtsmodel.Block) (vocab.Act
 	return block, nil
 }
 
+func (c *converter) PollVoteToAS(ctx context.Context, v *gtsmodel.PollVote) (vocab.ActivityStreamsNote, error) {
+	if v.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, v.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("PollVoteToAS: error getting voting account from database: %s", err)
+		}
+		v.Account = a
+	}
+
+	poll, err := c.db.GetPollByID(ctx, v.PollID)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting poll from database: %s", err)
+	}
+
+	if v.Choice >= len(poll.Options) {
+		return nil, fmt.Errorf("PollVoteToAS: vote is for option %d, but poll %s only has %d options", v.Choice, poll.ID, len(poll.Options))
+	}
+
+	status, err := c.db.GetStatusByID(ctx, poll.StatusID)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error getting poll status from database: %s", err)
+	}
+
+	if status.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, status.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("PollVoteToAS: error getting poll author account from database: %s", err)
+		}
+		status.Account = a
+	}
+
+	note := streams.NewActivityStreamsNote()
+
+	// set the ID property to the vote's URI
+	idProp := streams.NewJSONLDIdProperty()
+	idIRI, err := url.Parse(v.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", v.URI, err)
+	}
+	idProp.Set(idIRI)
+	note.SetJSONLDId(idProp)
+
+	// set the name property to the title of the chosen option
+	nameProp := streams.NewActivityStreamsNameProperty()
+	nameProp.AppendXMLSchemaString(poll.Options[v.Choice])
+	note.SetActivityStreamsName(nameProp)
+
+	// set the attributedTo property to the voting account's URI
+	attributedToProp := streams.NewActivityStreamsAttributedToProperty()
+	attributedToIRI, err := url.Parse(v.Account.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", v.Account.URI, err)
+	}
+	attributedToProp.AppendIRI(attributedToIRI)
+	note.SetActivityStreamsAttributedTo(attributedToProp)
+
+	// set the inReplyTo property to the URI of the status with the poll
+	inReplyToProp := streams.NewActivityStreamsInReplyToProperty()
+	inReplyToIRI, err := url.Parse(status.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", status.URI, err)
+	}
+	inReplyToProp.AppendIRI(inReplyToIRI)
+	note.SetActivityStreamsInReplyTo(inReplyToProp)
+
+	// set the TO property to the poll author's URI, since votes aren't for anyone else
+	toProp := streams.NewActivityStreamsToProperty()
+	toIRI, err := url.Parse(status.Account.URI)
+	if err != nil {
+		return nil, fmt.Errorf("PollVoteToAS: error parsing uri %s: %s", status.Account.URI, err)
+	}
+	toProp.AppendIRI(toIRI)
+	note.SetActivityStreamsTo(toProp)
+
+	// set the published property to when the vote was cast
+	publishedProp := streams.NewActivityStreamsPublishedProperty()
+	publishedProp.Set(v.CreatedAt)
+	note.SetActivityStreamsPublished(publishedProp)
+
+	return note, nil
+}
+
+func (c *converter) ReportToASFlag(ctx context.Context, r *gtsmodel.Report) (vocab.ActivityStreamsFlag, error) {
+	if r.TargetAccount == nil {
+		a, err := c.db.GetAccountByID(ctx, r.TargetAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("ReportToASFlag: error getting report target account from database: %s", err)
+		}
+		r.TargetAccount = a
+	}
+
+	instanceAccount, err := c.db.GetInstanceAccount(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error getting instance account from database: %s", err)
+	}
+
+	flag := streams.NewActivityStreamsFlag()
+
+	// set the ID property to the report's URI
+	idProp := streams.NewJSONLDIdProperty()
+	idIRI, err := url.Parse(r.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", r.URI, err)
+	}
+	idProp.Set(idIRI)
+	flag.SetJSONLDId(idProp)
+
+	// set the actor property to the instance account's URI, rather than the URI of whoever made the report
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorIRI, err := url.Parse(instanceAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", instanceAccount.URI, err)
+	}
+	actorProp.AppendIRI(actorIRI)
+	flag.SetActivityStreamsActor(actorProp)
+
+	// set the object property to the reported account's URI, followed by the URIs of any reported statuses
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	targetIRI, err := url.Parse(r.TargetAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", r.TargetAccount.URI, err)
+	}
+	objectProp.AppendIRI(targetIRI)
+
+	for _, statusID := range r.StatusIDs {
+		s, err := c.db.GetStatusByID(ctx, statusID)
+		if err != nil {
+			return nil, fmt.Errorf("ReportToASFlag: error getting reported status %s from database: %s", statusID, err)
+		}
+		statusIRI, err := url.Parse(s.URI)
+		if err != nil {
+			return nil, fmt.Errorf("ReportToASFlag: error parsing uri %s: %s", s.URI, err)
+		}
+		objectProp.AppendIRI(statusIRI)
+	}
+	flag.SetActivityStreamsObject(objectProp)
+
+	// set the content property to the report's comment
+	if r.Comment != "" {
+		contentProp := streams.NewActivityStreamsContentProperty()
+		contentProp.AppendXMLSchemaString(r.Comment)
+		flag.SetActivityStreamsContent(contentProp)
+	}
+
+	return flag, nil
+}
+
+func (c *converter) TombstoneToAS(ctx context.Context, t *gtsmodel.Tombstone) (vocab.ActivityStreamsTombstone, error) {
+	tombstone := streams.NewActivityStreamsTombstone()
+
+	tombstoneIRI, err := url.Parse(t.URI)
+	if err != nil {
+		return nil, fmt.Errorf("TombstoneToAS: error parsing uri %s: %s", t.URI, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(tombstoneIRI)
+	tombstone.SetJSONLDId(idProp)
+
+	return tombstone, nil
+}
+
+func (c *converter) MoveToAS(ctx context.Context, originAccount *gtsmodel.Account, targetAccount *gtsmodel.Account) (vocab.ActivityStreamsMove, error) {
+	move := streams.NewActivityStreamsMove()
+
+	// set the ID property to a new move URI
+	newID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, err
+	}
+	idString := uris.GenerateURIForMove(originAccount.Username, newID)
+	idIRI, err := url.Parse(idString)
+	if err != nil {
+		return nil, fmt.Errorf("MoveToAS: error parsing uri %s: %s", idString, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idIRI)
+	move.SetJSONLDId(idProp)
+
+	// the actor and the object are both the account that's moving
+	originIRI, err := url.Parse(originAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("MoveToAS: error parsing uri %s: %s", originAccount.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(originIRI)
+	move.SetActivityStreamsActor(actorProp)
+
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	objectProp.AppendIRI(originIRI)
+	move.SetActivityStreamsObject(objectProp)
+
+	// set the target property to the account that's being moved to
+	targetIRI, err := url.Parse(targetAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("MoveToAS: error parsing uri %s: %s", targetAccount.URI, err)
+	}
+	targetProp := streams.NewActivityStreamsTargetProperty()
+	targetProp.AppendIRI(targetIRI)
+	move.SetActivityStreamsTarget(targetProp)
+
+	// send to followers...
+	followersIRI, err := url.Parse(originAccount.FollowersURI)
+	if err != nil {
+		return nil, fmt.Errorf("MoveToAS: error parsing uri %s: %s", originAccount.FollowersURI, err)
+	}
+	toProp := streams.NewActivityStreamsToProperty()
+	toProp.AppendIRI(followersIRI)
+	move.SetActivityStreamsTo(toProp)
+
+	// ... and CC to public
+	publicIRI, err := url.Parse(pub.PublicActivityPubIRI)
+	if err != nil {
+		return nil, fmt.Errorf("MoveToAS: error parsing uri %s: %s", pub.PublicActivityPubIRI, err)
+	}
+	ccProp := streams.NewActivityStreamsCcProperty()
+	ccProp.AppendIRI(publicIRI)
+	move.SetActivityStreamsCc(ccProp)
+
+	return move, nil
+}
+
+func (c *converter) PinToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsAdd, error) {
+	add := streams.NewActivityStreamsAdd()
+	if err := c.setPinProperties(ctx, s, add); err != nil {
+		return nil, fmt.Errorf("PinToAS: %s", err)
+	}
+	return add, nil
+}
+
+func (c *converter) UnpinToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsRemove, error) {
+	remove := streams.NewActivityStreamsRemove()
+	if err := c.setPinProperties(ctx, s, remove); err != nil {
+		return nil, fmt.Errorf("UnpinToAS: %s", err)
+	}
+	return remove, nil
+}
+
+// pinActivity is implemented by both ADD and REMOVE, which are used to pin and unpin statuses respectively.
+type pinActivity interface {
+	SetActivityStreamsActor(vocab.ActivityStreamsActorProperty)
+	SetActivityStreamsObject(vocab.ActivityStreamsObjectProperty)
+	SetActivityStreamsTarget(vocab.ActivityStreamsTargetProperty)
+	SetActivityStreamsTo(vocab.ActivityStreamsToProperty)
+	SetActivityStreamsCc(vocab.ActivityStreamsCcProperty)
+}
+
+// setPinProperties sets the properties of the given pin activity so that it adds s to, or removes s from,
+// the featured collection of the account that owns it. It's addressed to that account's followers.
+func (c *converter) setPinProperties(ctx context.Context, s *gtsmodel.Status, activity pinActivity) error {
+	if s.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, s.AccountID)
+		if err != nil {
+			return fmt.Errorf("error getting account %s: %s", s.AccountID, err)
+		}
+		s.Account = a
+	}
+
+	// the actor is the account that owns the status
+	actorIRI, err := url.Parse(s.Account.URI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", s.Account.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(actorIRI)
+	activity.SetActivityStreamsActor(actorProp)
+
+	// the object is the status being pinned or unpinned
+	statusIRI, err := url.Parse(s.URI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", s.URI, err)
+	}
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	objectProp.AppendIRI(statusIRI)
+	activity.SetActivityStreamsObject(objectProp)
+
+	// the target is the featured collection of the account
+	featuredIRI, err := url.Parse(s.Account.FeaturedCollectionURI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", s.Account.FeaturedCollectionURI, err)
+	}
+	targetProp := streams.NewActivityStreamsTargetProperty()
+	targetProp.AppendIRI(featuredIRI)
+	activity.SetActivityStreamsTarget(targetProp)
+
+	// send to followers...
+	followersIRI, err := url.Parse(s.Account.FollowersURI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", s.Account.FollowersURI, err)
+	}
+	toProp := streams.NewActivityStreamsToProperty()
+	toProp.AppendIRI(followersIRI)
+	activity.SetActivityStreamsTo(toProp)
+
+	// ... and CC to public
+	publicIRI, err := url.Parse(pub.PublicActivityPubIRI)
+	if err != nil {
+		return fmt.Errorf("error parsing uri %s: %s", pub.PublicActivityPubIRI, err)
+	}
+	ccProp := streams.NewActivityStreamsCcProperty()
+	ccProp.AppendIRI(publicIRI)
+	activity.SetActivityStreamsCc(ccProp)
+
+	return nil
+}
+
 /*
 	the goal is to end up with something like this:
 
@@ -1069,10 +1616,10 @@ func (c *converter) StatusesToASOutboxPage(ctx context.Context, outboxID string,
 	pageIDProp := streams.NewJSONLDIdProperty()
 	pageID := fmt.Sprintf("%s?page=true", outboxID)
 	if minID != "" {
-		pageID = fmt.Sprintf("%s&minID=%s", pageID, minID)
+		pageID = fmt.Sprintf("%s&min_id=%s", pageID, minID)
 	}
 	if maxID != "" {
-		pageID = fmt.Sprintf("%s&maxID=%s", pageID, maxID)
+		pageID = fmt.Sprintf("%s&max_id=%s", pageID, maxID)
 	}
 	pageIDURI, err := url.Parse(pageID)
 	if err != nil {
@@ -1175,3 +1722,421 @@ func (c *converter) OutboxToASCollection(ctx context.Context, outboxID string) (
 
 	return collection, nil
 }
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/outbox",
+		"type": "OrderedCollection",
+		"totalItems": 1,
+		"orderedItems": [
+			{
+				"id": "https://example.org/users/whatever/statuses/01FCNEXAGAKPEX1J7VJRPJP490/activity",
+				"type": "Create",
+				"actor": "https://example.org/users/whatever",
+				"object": {
+					"id": "https://example.org/users/whatever/statuses/01FCNEXAGAKPEX1J7VJRPJP490",
+					"type": "Note",
+					...
+				},
+				...
+			}
+		]
+	}
+*/
+func (c *converter) StatusesToASOutboxCollection(ctx context.Context, outboxID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	collectionIDProp := streams.NewJSONLDIdProperty()
+	collectionIDURI, err := url.Parse(outboxID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", outboxID)
+	}
+	collectionIDProp.SetIRI(collectionIDURI)
+	collection.SetJSONLDId(collectionIDProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(len(statuses))
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	itemsProp := streams.NewActivityStreamsOrderedItemsProperty()
+	for _, s := range statuses {
+		if s.BoostOfID != "" {
+			// boosts are announces rather than creates
+			if s.Account == nil {
+				a, err := c.db.GetAccountByID(ctx, s.AccountID)
+				if err != nil {
+					return nil, fmt.Errorf("error getting account of boost %s: %s", s.ID, err)
+				}
+				s.Account = a
+			}
+			if s.BoostOfAccount == nil {
+				a, err := c.db.GetAccountByID(ctx, s.BoostOfAccountID)
+				if err != nil {
+					return nil, fmt.Errorf("error getting boosted account of boost %s: %s", s.ID, err)
+				}
+				s.BoostOfAccount = a
+			}
+
+			announce, err := c.BoostToAS(ctx, s, s.Account, s.BoostOfAccount)
+			if err != nil {
+				return nil, err
+			}
+			itemsProp.AppendActivityStreamsAnnounce(announce)
+			continue
+		}
+
+		note, err := c.StatusToAS(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		create, err := c.WrapNoteInCreate(note, false)
+		if err != nil {
+			return nil, err
+		}
+		itemsProp.AppendActivityStreamsCreate(create)
+	}
+	collection.SetActivityStreamsOrderedItems(itemsProp)
+
+	return collection, nil
+}
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/followers",
+		"type": "OrderedCollection",
+		"totalItems": 2,
+		"first": "https://example.org/users/whatever/followers?page=true"
+	}
+*/
+func (c *converter) FollowsToASCollection(ctx context.Context, collectionID string, totalItems int, hidden bool) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	collectionIDProp := streams.NewJSONLDIdProperty()
+	collectionIDURI, err := url.Parse(collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", collectionID)
+	}
+	collectionIDProp.SetIRI(collectionIDURI)
+	collection.SetJSONLDId(collectionIDProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(totalItems)
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	// if the collection is hidden, only the number of items in it is shown
+	if hidden {
+		return collection, nil
+	}
+
+	collectionFirstProp := streams.NewActivityStreamsFirstProperty()
+	collectionFirstPropID := fmt.Sprintf("%s?page=true", collectionID)
+	collectionFirstPropIDURI, err := url.Parse(collectionFirstPropID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", collectionFirstPropID)
+	}
+	collectionFirstProp.SetIRI(collectionFirstPropIDURI)
+	collection.SetActivityStreamsFirst(collectionFirstProp)
+
+	return collection, nil
+}
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/following?page=true",
+		"type": "OrderedCollectionPage",
+		"totalItems": 2,
+		"partOf": "https://example.org/users/whatever/following",
+		"next": "https://example.org/users/whatever/following?page=true&max_id=01F8PY8RHWRQZV038T4E8T9YK8",
+		"prev": "https://example.org/users/whatever/following?page=true&min_id=01F8PYDCE8XE23GRE5DPZJDZDP",
+		"orderedItems": [
+			"https://example.org/users/someone",
+			"https://another.example.com/users/someone_else"
+		]
+	}
+*/
+func (c *converter) FollowsToASCollectionPage(ctx context.Context, collectionID string, maxID string, minID string, totalItems int, follows []*gtsmodel.Follow, followers bool) (vocab.ActivityStreamsOrderedCollectionPage, error) {
+	page := streams.NewActivityStreamsOrderedCollectionPage()
+
+	// .id
+	pageIDProp := streams.NewJSONLDIdProperty()
+	pageID := fmt.Sprintf("%s?page=true", collectionID)
+	if minID != "" {
+		pageID = fmt.Sprintf("%s&min_id=%s", pageID, minID)
+	}
+	if maxID != "" {
+		pageID = fmt.Sprintf("%s&max_id=%s", pageID, maxID)
+	}
+	pageIDURI, err := url.Parse(pageID)
+	if err != nil {
+		return nil, err
+	}
+	pageIDProp.SetIRI(pageIDURI)
+	page.SetJSONLDId(pageIDProp)
+
+	// .totalItems
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(totalItems)
+	page.SetActivityStreamsTotalItems(totalItemsProp)
+
+	// .partOf
+	collectionIDURI, err := url.Parse(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	partOfProp := streams.NewActivityStreamsPartOfProperty()
+	partOfProp.SetIRI(collectionIDURI)
+	page.SetActivityStreamsPartOf(partOfProp)
+
+	// .orderedItems
+	itemsProp := streams.NewActivityStreamsOrderedItemsProperty()
+	var highest string
+	var lowest string
+	for _, f := range follows {
+		account := f.TargetAccount
+		if followers {
+			account = f.Account
+		}
+		if account == nil {
+			// the account has gone away since the follow was made
+			continue
+		}
+
+		accountURI, err := url.Parse(account.URI)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing url %s: %s", account.URI, err)
+		}
+		itemsProp.AppendIRI(accountURI)
+
+		if highest == "" || f.ID > highest {
+			highest = f.ID
+		}
+		if lowest == "" || f.ID < lowest {
+			lowest = f.ID
+		}
+	}
+	page.SetActivityStreamsOrderedItems(itemsProp)
+
+	// .next
+	if lowest != "" {
+		nextProp := streams.NewActivityStreamsNextProperty()
+		nextPropIDString := fmt.Sprintf("%s?page=true&max_id=%s", collectionID, lowest)
+		nextPropIDURI, err := url.Parse(nextPropIDString)
+		if err != nil {
+			return nil, err
+		}
+		nextProp.SetIRI(nextPropIDURI)
+		page.SetActivityStreamsNext(nextProp)
+	}
+
+	// .prev
+	if highest != "" {
+		prevProp := streams.NewActivityStreamsPrevProperty()
+		prevPropIDString := fmt.Sprintf("%s?page=true&min_id=%s", collectionID, highest)
+		prevPropIDURI, err := url.Parse(prevPropIDString)
+		if err != nil {
+			return nil, err
+		}
+		prevProp.SetIRI(prevPropIDURI)
+		page.SetActivityStreamsPrev(prevProp)
+	}
+
+	return page, nil
+}
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/collections/featured",
+		"type": "OrderedCollection",
+		"totalItems": 1,
+		"orderedItems": [
+			{
+				"id": "https://example.org/users/whatever/statuses/01FJC1MKPVX2VMWP2ST93Q90K7",
+				"type": "Note",
+				...
+			}
+		]
+	}
+*/
+func (c *converter) StatusesToASFeaturedCollection(ctx context.Context, featuredCollectionID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	collectionIDProp := streams.NewJSONLDIdProperty()
+	collectionIDURI, err := url.Parse(featuredCollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", featuredCollectionID)
+	}
+	collectionIDProp.SetIRI(collectionIDURI)
+	collection.SetJSONLDId(collectionIDProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(len(statuses))
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	itemsProp := streams.NewActivityStreamsOrderedItemsProperty()
+	for _, s := range statuses {
+		statusable, err := c.StatusToAS(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		if err := itemsProp.AppendType(statusable); err != nil {
+			return nil, fmt.Errorf("error appending status %s to featured collection: %s", s.ID, err)
+		}
+	}
+	collection.SetActivityStreamsOrderedItems(itemsProp)
+
+	return collection, nil
+}
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/collections/featured_accounts",
+		"type": "OrderedCollection",
+		"totalItems": 1,
+		"orderedItems": [
+			"https://another.example.org/users/someone_else"
+		]
+	}
+*/
+func (c *converter) AccountsToASFeaturedAccountsCollection(ctx context.Context, featuredAccountsID string, accounts []*gtsmodel.Account) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	collectionIDProp := streams.NewJSONLDIdProperty()
+	collectionIDURI, err := url.Parse(featuredAccountsID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", featuredAccountsID)
+	}
+	collectionIDProp.SetIRI(collectionIDURI)
+	collection.SetJSONLDId(collectionIDProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(len(accounts))
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	itemsProp := streams.NewActivityStreamsOrderedItemsProperty()
+	for _, a := range accounts {
+		accountURI, err := url.Parse(a.URI)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing url %s", a.URI)
+		}
+		itemsProp.AppendIRI(accountURI)
+	}
+	collection.SetActivityStreamsOrderedItems(itemsProp)
+
+	return collection, nil
+}
+
+/*
+	we want something that looks like this:
+
+	{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://example.org/users/whatever/collections/tags",
+		"type": "OrderedCollection",
+		"totalItems": 1,
+		"orderedItems": [
+			{
+				"type": "Hashtag",
+				"href": "https://example.org/tags/welcome",
+				"name": "#welcome"
+			}
+		]
+	}
+*/
+func (c *converter) FeaturedTagsToASCollection(ctx context.Context, featuredTagsID string, featuredTags []*gtsmodel.FeaturedTag) (vocab.ActivityStreamsOrderedCollection, error) {
+	collection := streams.NewActivityStreamsOrderedCollection()
+
+	collectionIDProp := streams.NewJSONLDIdProperty()
+	collectionIDURI, err := url.Parse(featuredTagsID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url %s", featuredTagsID)
+	}
+	collectionIDProp.SetIRI(collectionIDURI)
+	collection.SetJSONLDId(collectionIDProp)
+
+	totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+	totalItemsProp.Set(len(featuredTags))
+	collection.SetActivityStreamsTotalItems(totalItemsProp)
+
+	// go-fed has no Hashtag type, so the items are set as an unknown property instead
+	items := []interface{}{}
+	for _, ft := range featuredTags {
+		if ft.Tag == nil {
+			return nil, fmt.Errorf("tag of featured tag %s was not populated", ft.ID)
+		}
+		items = append(items, map[string]interface{}{
+			"type": ap.ObjectHashtag,
+			"href": ft.Tag.URL,
+			"name": "#" + ft.Tag.Name,
+		})
+	}
+	collection.GetUnknownProperties()["orderedItems"] = items
+
+	return collection, nil
+}
+
+// emojiToAS converts a local custom emoji into a toot:Emoji, for using as a tag, like:
+//
+//	{
+//	  "id": "http://example.org/emoji/01F8MH9H8E4VG3KDYJR9EGPXCQ",
+//	  "type": "Emoji",
+//	  "name": ":rainbow:",
+//	  "updated": "2021-09-20T10:40:37Z",
+//	  "icon": {
+//	    "type": "Image",
+//	    "mediaType": "image/png",
+//	    "url": "http://example.org/fileserver/01AY6P665V14JJR0AFVRT7311Y/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png"
+//	  }
+//	}
+func emojiToAS(e *gtsmodel.Emoji) (vocab.TootEmoji, error) {
+	emoji := streams.NewTootEmoji()
+
+	emojiURI, err := url.Parse(e.URI)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing emoji uri %s: %s", e.URI, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(emojiURI)
+	emoji.SetJSONLDId(idProp)
+
+	nameProp := streams.NewActivityStreamsNameProperty()
+	nameProp.AppendXMLSchemaString(":" + e.Shortcode + ":")
+	emoji.SetActivityStreamsName(nameProp)
+
+	updatedProp := streams.NewActivityStreamsUpdatedProperty()
+	updatedProp.Set(e.ImageUpdatedAt)
+	emoji.SetActivityStreamsUpdated(updatedProp)
+
+	imageURL, err := url.Parse(e.ImageURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing emoji image url %s: %s", e.ImageURL, err)
+	}
+	image := streams.NewActivityStreamsImage()
+	mediaTypeProp := streams.NewActivityStreamsMediaTypeProperty()
+	mediaTypeProp.Set(e.ImageContentType)
+	image.SetActivityStreamsMediaType(mediaTypeProp)
+	urlProp := streams.NewActivityStreamsUrlProperty()
+	urlProp.AppendIRI(imageURL)
+	image.SetActivityStreamsUrl(urlProp)
+
+	iconProp := streams.NewActivityStreamsIconProperty()
+	iconProp.AppendActivityStreamsImage(image)
+	emoji.SetActivityStreamsIcon(iconProp)
+
+	return emoji, nil
+}
@@ -23,10 +23,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
 type InternalToASTestSuite struct {
@@ -49,6 +53,91 @@ func (suite *InternalToASTestSuite) TestAccountToAS() {
 	// TODO: write assertions here, rn we're just eyeballing the output
 }
 
+func (suite *InternalToASTestSuite) TestAccountToASMoved() {
+	testAccount := &gtsmodel.Account{}
+	*testAccount = *suite.testAccounts["local_account_1"] // take zork for this test
+	testAccount.AlsoKnownAsURIs = []string{"http://fossbros-anonymous.io/users/foss_satan"}
+	testAccount.MovedToAccountID = suite.testAccounts["local_account_2"].ID
+
+	asPerson, err := suite.typeconverter.AccountToAS(context.Background(), testAccount)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asPerson)
+	suite.NoError(err)
+
+	suite.Equal([]string{"http://fossbros-anonymous.io/users/foss_satan"}, ser["alsoKnownAs"])
+	suite.Equal("http://localhost:8080/users/1happyturtle", ser["movedTo"])
+}
+
+func (suite *InternalToASTestSuite) TestAccountToASFeaturedTags() {
+	asPerson, err := suite.typeconverter.AccountToAS(context.Background(), suite.testAccounts["local_account_1"])
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asPerson)
+	suite.NoError(err)
+
+	suite.Equal("http://localhost:8080/users/the_mighty_zork/collections/tags", ser["featuredTags"])
+	suite.Equal("http://localhost:8080/users/the_mighty_zork/collections/featured_accounts", ser["endorsements"])
+}
+
+func (suite *InternalToASTestSuite) TestAccountToASAssertionMethod() {
+	testAccount := suite.testAccounts["local_account_1"] // take zork for this test
+
+	asPerson, err := suite.typeconverter.AccountToAS(context.Background(), testAccount)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asPerson)
+	suite.NoError(err)
+
+	b, err := json.Marshal(ser)
+	suite.NoError(err)
+	suite.Contains(string(b), `"assertionMethod":[{"controller":"http://localhost:8080/users/the_mighty_zork","id":"http://localhost:8080/users/the_mighty_zork#ed25519-key","publicKeyMultibase":"`+ap.EncodeMultikey(testAccount.AssertionPublicKey)+`","type":"Multikey"}]`)
+
+	// the key can be read back out of the actor by remote instances
+	m := make(map[string]interface{})
+	suite.NoError(json.Unmarshal(b, &m))
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+	accountable, ok := t.(ap.Accountable)
+	suite.True(ok)
+
+	publicKey, keyID, err := ap.ExtractAssertionMethodForOwner(accountable, testrig.URLMustParse(testAccount.URI))
+	suite.NoError(err)
+	suite.Equal(testAccount.AssertionPublicKey, publicKey)
+	suite.Equal(testAccount.AssertionKeyURI, keyID.String())
+}
+
+func (suite *InternalToASTestSuite) TestAccountToASWithEmoji() {
+	testAccount := &gtsmodel.Account{}
+	*testAccount = *suite.testAccounts["local_account_1"] // take zork for this test
+	testAccount.DisplayName = "original zork :rainbow:"
+	testAccount.EmojiIDs = []string{"01F8MH9H8E4VG3KDYJR9EGPXCQ"}
+
+	asPerson, err := suite.typeconverter.AccountToAS(context.Background(), testAccount)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asPerson)
+	suite.NoError(err)
+
+	b, err := json.Marshal(ser)
+	suite.NoError(err)
+
+	// the emoji can be read back out of the actor by remote instances
+	m := make(map[string]interface{})
+	suite.NoError(json.Unmarshal(b, &m))
+	t, err := streams.ToType(context.Background(), m)
+	suite.NoError(err)
+	accountable, ok := t.(ap.Accountable)
+	suite.True(ok)
+
+	emojis, err := ap.ExtractEmojis(accountable)
+	suite.NoError(err)
+	suite.Len(emojis, 1)
+	suite.Equal("rainbow", emojis[0].Shortcode)
+	suite.Equal("http://localhost:8080/emoji/01F8MH9H8E4VG3KDYJR9EGPXCQ", emojis[0].URI)
+	suite.Equal("http://localhost:8080/fileserver/01F8MH261H1KSV3GW3016GZRY3/emoji/original/01F8MH9H8E4VG3KDYJR9EGPXCQ.png", emojis[0].ImageRemoteURL)
+}
+
 func (suite *InternalToASTestSuite) TestOutboxToASCollection() {
 	testAccount := suite.testAccounts["admin_account"]
 	ctx := context.Background()
@@ -91,6 +180,135 @@ func (suite *InternalToASTestSuite) TestStatusToAS() {
 	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","attachment":[],"attributedTo":"http://localhost:8080/users/the_mighty_zork","cc":"http://localhost:8080/users/the_mighty_zork/followers","content":"hello everyone!","id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","published":"2021-10-20T12:40:37+02:00","replies":{"first":{"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?page=true","next":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?only_other_accounts=false\u0026page=true","partOf":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"CollectionPage"},"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"Collection"},"sensitive":true,"summary":"introduction post","tag":[],"to":"https://www.w3.org/ns/activitystreams#Public","type":"Note","url":"http://localhost:8080/@the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY"}`, string(bytes))
 }
 
+func (suite *InternalToASTestSuite) TestStatusToASWithPoll() {
+	testStatus := &gtsmodel.Status{}
+	*testStatus = *suite.testStatuses["local_account_1_status_1"]
+	testStatus.PollID = "01G1WQ3XJ9AJ3K0T1AE0WHJE2T"
+	testStatus.Poll = &gtsmodel.Poll{
+		ID:          testStatus.PollID,
+		StatusID:    testStatus.ID,
+		Options:     []string{"yes", "no"},
+		Votes:       []int{3, 1},
+		VotersCount: 4,
+		ExpiresAt:   time.Date(2021, 10, 21, 12, 40, 37, 0, time.UTC),
+		ClosedAt:    time.Date(2021, 10, 21, 12, 40, 37, 0, time.UTC),
+	}
+	ctx := context.Background()
+
+	asStatus, err := suite.typeconverter.StatusToAS(ctx, testStatus)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asStatus)
+	assert.NoError(suite.T(), err)
+
+	// the order of the namespaces in the context isn't stable, so check them separately
+	suite.ElementsMatch([]interface{}{"https://www.w3.org/ns/activitystreams", "http://joinmastodon.org/ns"}, ser["@context"])
+	delete(ser, "@context")
+
+	bytes, err := json.Marshal(ser)
+	suite.NoError(err)
+
+	suite.Equal(`{"attachment":[],"attributedTo":"http://localhost:8080/users/the_mighty_zork","cc":"http://localhost:8080/users/the_mighty_zork/followers","closed":"2021-10-21T12:40:37Z","content":"hello everyone!","endTime":"2021-10-21T12:40:37Z","id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","oneOf":[{"name":"yes","replies":{"totalItems":3,"type":"Collection"},"type":"Note"},{"name":"no","replies":{"totalItems":1,"type":"Collection"},"type":"Note"}],"published":"2021-10-20T12:40:37+02:00","replies":{"first":{"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?page=true","next":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?only_other_accounts=false\u0026page=true","partOf":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"CollectionPage"},"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"Collection"},"sensitive":true,"summary":"introduction post","tag":[],"to":"https://www.w3.org/ns/activitystreams#Public","type":"Question","url":"http://localhost:8080/@the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","votersCount":4}`, string(bytes))
+}
+
+func (suite *InternalToASTestSuite) TestStatusToASWithQuote() {
+	testStatus := &gtsmodel.Status{}
+	*testStatus = *suite.testStatuses["admin_account_status_1"]
+	testStatus.ID = "01G1XDQ7V0W4ZMAFN1PCNE2GJC" // so that the status isn't fetched from the cache
+	testStatus.QuoteOfID = suite.testStatuses["local_account_1_status_1"].ID
+	testStatus.QuoteOfURI = suite.testStatuses["local_account_1_status_1"].URI
+	ctx := context.Background()
+
+	asStatus, err := suite.typeconverter.StatusToAS(ctx, testStatus)
+	suite.NoError(err)
+
+	suite.Equal(testStatus.QuoteOfURI, ap.ExtractQuoteURI(asStatus).String())
+
+	ser, err := streams.Serialize(asStatus)
+	suite.NoError(err)
+
+	bytes, err := json.Marshal(ser)
+	suite.NoError(err)
+
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","_misskey_quote":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","attachment":[],"attributedTo":"http://localhost:8080/users/admin","cc":"http://localhost:8080/users/admin/followers","content":"hello world! #welcome ! first post on the instance :rainbow: !","id":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R","published":"2021-10-20T11:36:45Z","quoteUri":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","quoteUrl":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","replies":{"first":{"id":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R/replies?page=true","next":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R/replies?only_other_accounts=false\u0026page=true","partOf":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R/replies","type":"CollectionPage"},"id":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R/replies","type":"Collection"},"sensitive":false,"summary":"","tag":{"href":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","mediaType":"application/ld+json; profile=\"https://www.w3.org/ns/activitystreams\"","name":"RE: http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","type":"Link"},"to":"https://www.w3.org/ns/activitystreams#Public","type":"Note","url":"http://localhost:8080/@admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R"}`, string(bytes))
+}
+
+func (suite *InternalToASTestSuite) TestReactionToAS() {
+	testStatus := suite.testStatuses["admin_account_status_1"]
+	testReaction := &gtsmodel.StatusReaction{
+		ID:              "01G1XBS1G0KWZ7S2ATBZ5PMF9X",
+		AccountID:       suite.testAccounts["local_account_1"].ID,
+		TargetAccountID: testStatus.AccountID,
+		StatusID:        testStatus.ID,
+		Emoji:           "👍",
+		URI:             "http://localhost:8080/users/the_mighty_zork/liked/01G1XBS1G0KWZ7S2ATBZ5PMF9X",
+	}
+
+	asReaction, err := suite.typeconverter.ReactionToAS(context.Background(), testReaction)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asReaction)
+	suite.NoError(err)
+
+	bytes, err := json.Marshal(ser)
+	suite.NoError(err)
+
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","_misskey_reaction":"👍","actor":"http://localhost:8080/users/the_mighty_zork","content":"👍","id":"http://localhost:8080/users/the_mighty_zork/liked/01G1XBS1G0KWZ7S2ATBZ5PMF9X","object":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R","to":"http://localhost:8080/users/admin","type":"Like"}`, string(bytes))
+}
+
+func (suite *InternalToASTestSuite) TestCustomEmojiReactionToAS() {
+	testStatus := suite.testStatuses["admin_account_status_1"]
+	testEmoji := suite.testEmojis["rainbow"]
+	testReaction := &gtsmodel.StatusReaction{
+		ID:              "01G1XBS1G0KWZ7S2ATBZ5PMF9X",
+		AccountID:       suite.testAccounts["local_account_1"].ID,
+		TargetAccountID: testStatus.AccountID,
+		StatusID:        testStatus.ID,
+		Emoji:           ":" + testEmoji.Shortcode + ":",
+		CustomEmojiID:   testEmoji.ID,
+		URI:             "http://localhost:8080/users/the_mighty_zork/liked/01G1XBS1G0KWZ7S2ATBZ5PMF9X",
+	}
+
+	asReaction, err := suite.typeconverter.ReactionToAS(context.Background(), testReaction)
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asReaction)
+	suite.NoError(err)
+
+	// the emoji is tagged, so that remote instances can get the image of it
+	tag, ok := ser["tag"].(map[string]interface{})
+	suite.True(ok)
+	suite.Equal("Emoji", tag["type"])
+	suite.Equal(":rainbow:", tag["name"])
+	suite.Equal(testEmoji.URI, tag["id"])
+	suite.Equal(":rainbow:", ser["content"])
+}
+
+func (suite *InternalToASTestSuite) TestPinToAS() {
+	asAdd, err := suite.typeconverter.PinToAS(context.Background(), suite.testStatuses["admin_account_status_1"])
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asAdd)
+	suite.NoError(err)
+
+	bytes, err := json.Marshal(ser)
+	suite.NoError(err)
+
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","actor":"http://localhost:8080/users/admin","cc":"https://www.w3.org/ns/activitystreams#Public","object":"http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R","target":"http://localhost:8080/users/admin/collections/featured","to":"http://localhost:8080/users/admin/followers","type":"Add"}`, string(bytes))
+}
+
+func (suite *InternalToASTestSuite) TestUnpinToAS() {
+	asRemove, err := suite.typeconverter.UnpinToAS(context.Background(), suite.testStatuses["admin_account_status_1"])
+	suite.NoError(err)
+
+	ser, err := streams.Serialize(asRemove)
+	suite.NoError(err)
+
+	suite.Equal("Remove", ser["type"])
+	suite.Equal("http://localhost:8080/users/admin/statuses/01F8MH75CBF9JFX4ZAD54N0W0R", ser["object"])
+	suite.Equal("http://localhost:8080/users/admin/collections/featured", ser["target"])
+}
+
 func (suite *InternalToASTestSuite) TestStatusToASWithMentions() {
 	testStatusID := suite.testStatuses["admin_account_status_3"].ID
 	ctx := context.Background()
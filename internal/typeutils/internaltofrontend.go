@@ -27,10 +27,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
 )
 
 func (c *converter) AccountToAPIAccountSensitive(ctx context.Context, a *gtsmodel.Account) (*model.Account, error) {
@@ -54,18 +57,59 @@ func (c *converter) AccountToAPIAccountSensitive(ctx context.Context, a *gtsmode
 		frc = len(frs)
 	}
 
+	// work out how much media this account is storing, and how much it's allowed to store
+	mediaUsed, err := c.db.GetAccountMediaSize(ctx, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting media size: %s", err)
+	}
+	var mediaQuota int
+	user := &gtsmodel.User{}
+	if err := c.db.GetWhere(ctx, []db.Where{{Key: "account_id", Value: a.ID}}, user); err == nil {
+		mediaQuota = media.AccountQuota(user)
+	} else if err != db.ErrNoEntries {
+		return nil, fmt.Errorf("error getting user: %s", err)
+	}
+
+	excludedNotifications := make([]string, 0, len(a.ExcludedNotifications))
+	for _, t := range a.ExcludedNotifications {
+		excludedNotifications = append(excludedNotifications, string(t))
+	}
+
 	apiAccount.Source = &model.Source{
-		Privacy:             c.VisToAPIVis(ctx, a.Privacy),
-		Sensitive:           a.Sensitive,
-		Language:            a.Language,
-		Note:                a.Note,
-		Fields:              apiAccount.Fields,
-		FollowRequestsCount: frc,
+		Privacy:               c.VisToAPIVis(ctx, a.Privacy),
+		Sensitive:             a.Sensitive,
+		Language:              a.Language,
+		Note:                  a.Note,
+		Fields:                apiAccount.Fields,
+		FollowRequestsCount:   frc,
+		MediaStorageUsed:      mediaUsed,
+		MediaStorageQuota:     mediaQuota,
+		ExcludedNotifications: excludedNotifications,
 	}
 
 	return apiAccount, nil
 }
 
+func (c *converter) AccountToAPIPreferences(ctx context.Context, a *gtsmodel.Account) (*model.Preferences, error) {
+	if a == nil {
+		return nil, fmt.Errorf("given account was nil")
+	}
+
+	// accounts that haven't chosen yet get the default
+	expandMedia := a.ExpandMedia
+	if expandMedia == "" {
+		expandMedia = gtsmodel.ExpandMediaDefault
+	}
+
+	return &model.Preferences{
+		PostingDefaultVisibility: string(c.VisToAPIVis(ctx, a.Privacy)),
+		PostingDefaultSensitive:  a.Sensitive,
+		PostingDefaultLanguage:   a.Language,
+		ReadingExpandMedia:       string(expandMedia),
+		ReadingExpandSpoilers:    a.ExpandSpoilers,
+	}, nil
+}
+
 func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.Account) (*model.Account, error) {
 	if a == nil {
 		return nil, fmt.Errorf("given account was nil")
@@ -145,8 +189,24 @@ func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.A
 		fields = append(fields, mField)
 	}
 
+	// get the emojis used in the display name, note and fields of this account
+	// remote emojis might still be processing, in which case they're just left out
+	gtsEmojis, err := c.db.GetEmojisByIDs(ctx, a.EmojiIDs)
+	if err != nil {
+		return nil, fmt.Errorf("AccountToAPIAccountPublic: error getting emojis: %s", err)
+	}
 	emojis := []model.Emoji{}
-	// TODO: account emojis
+	for _, gtsEmoji := range gtsEmojis {
+		if gtsEmoji.Disabled {
+			continue
+		}
+		apiEmoji, err := c.EmojiToAPIEmoji(ctx, gtsEmoji)
+		if err != nil {
+			logrus.Errorf("AccountToAPIAccountPublic: error converting emoji with id %s: %s", gtsEmoji.ID, err)
+			continue
+		}
+		emojis = append(emojis, apiEmoji)
+	}
 
 	var acct string
 	if a.Domain != "" {
@@ -162,6 +222,30 @@ func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.A
 		suspended = true
 	}
 
+	// accounts on silenced domains are marked as limited
+	var limited bool
+	if a.Domain != "" {
+		limited, err = c.db.IsDomainSilenced(ctx, a.Domain)
+		if err != nil {
+			return nil, fmt.Errorf("error checking whether domain %s is silenced: %s", a.Domain, err)
+		}
+	}
+
+	// set the account this account moved to, if it's moved
+	var moved *model.Account
+	if a.MovedToAccountID != "" {
+		movedTo, err := c.db.GetAccountByID(ctx, a.MovedToAccountID)
+		if err == nil {
+			// don't follow the moved account's own move, or we could go round in circles
+			movedToCopy := *movedTo
+			movedToCopy.MovedToAccountID = ""
+			moved, err = c.AccountToAPIAccountPublic(ctx, &movedToCopy)
+		}
+		if err != nil {
+			logrus.Errorf("AccountToAPIAccountPublic: error getting moved to account with id %s: %s", a.MovedToAccountID, err)
+		}
+	}
+
 	accountFrontend := &model.Account{
 		ID:             a.ID,
 		Username:       a.Username,
@@ -169,6 +253,8 @@ func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.A
 		DisplayName:    a.DisplayName,
 		Locked:         a.Locked,
 		Bot:            a.Bot,
+		Group:          a.ActorType == ap.ActorGroup,
+		Discoverable:   a.Discoverable,
 		CreatedAt:      a.CreatedAt.Format(time.RFC3339),
 		Note:           a.Note,
 		URL:            a.URL,
@@ -180,9 +266,11 @@ func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.A
 		FollowingCount: followingCount,
 		StatusesCount:  statusesCount,
 		LastStatusAt:   lastStatusAt,
-		Emojis:         emojis, // TODO: implement this
+		Emojis:         emojis,
 		Fields:         fields,
 		Suspended:      suspended,
+		Limited:        limited,
+		Moved:          moved,
 	}
 
 	return accountFrontend, nil
@@ -209,6 +297,7 @@ func (c *converter) AccountToAPIAccountBlocked(ctx context.Context, a *gtsmodel.
 		Acct:        acct,
 		DisplayName: a.DisplayName,
 		Bot:         a.Bot,
+		Group:       a.ActorType == ap.ActorGroup,
 		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
 		URL:         a.URL,
 		Suspended:   suspended,
@@ -234,11 +323,39 @@ func (c *converter) AppToAPIAppPublic(ctx context.Context, a *gtsmodel.Applicati
 }
 
 func (c *converter) AttachmentToAPIAttachment(ctx context.Context, a *gtsmodel.MediaAttachment) (model.Attachment, error) {
+	var variants map[string]model.MediaVariant
+	if len(a.Variants) != 0 {
+		variants = make(map[string]model.MediaVariant, len(a.Variants))
+		for _, v := range a.Variants {
+			variants[v.Name] = model.MediaVariant{
+				URL: v.URL,
+				Meta: model.MediaDimensions{
+					Width:  v.Width,
+					Height: v.Height,
+					Size:   fmt.Sprintf("%dx%d", v.Width, v.Height),
+					Aspect: float32(v.Aspect),
+				},
+			}
+		}
+	}
+
+	// media of unknown types is stored without a thumbnail, so there's nothing to preview
+	previewURL := a.Thumbnail.URL
+	if a.Type == gtsmodel.FileTypeUnknown {
+		previewURL = ""
+	}
+
+	// media that's never cached, like media from silenced domains, can only be retrieved from the remote server
+	attachmentURL := a.URL
+	if attachmentURL == "" {
+		attachmentURL = a.RemoteURL
+	}
+
 	return model.Attachment{
 		ID:               a.ID,
 		Type:             strings.ToLower(string(a.Type)),
-		URL:              a.URL,
-		PreviewURL:       a.Thumbnail.URL,
+		URL:              attachmentURL,
+		PreviewURL:       previewURL,
 		RemoteURL:        a.RemoteURL,
 		PreviewRemoteURL: a.Thumbnail.RemoteURL,
 		Meta: model.MediaMeta{
@@ -259,6 +376,7 @@ func (c *converter) AttachmentToAPIAttachment(ctx context.Context, a *gtsmodel.M
 				Y: a.FileMeta.Focus.Y,
 			},
 		},
+		Variants:    variants,
 		Description: a.Description,
 		Blurhash:    a.Blurhash,
 	}, nil
@@ -310,7 +428,125 @@ func (c *converter) TagToAPITag(ctx context.Context, t *gtsmodel.Tag) (model.Tag
 	}, nil
 }
 
+func (c *converter) FeaturedTagToAPIFeaturedTag(ctx context.Context, ft *gtsmodel.FeaturedTag) (*model.FeaturedTag, error) {
+	if ft.Tag == nil {
+		return nil, fmt.Errorf("FeaturedTagToAPIFeaturedTag: tag of featured tag %s was not populated", ft.ID)
+	}
+
+	statusesCount, lastStatusAt, err := c.db.GetAccountTagUsage(ctx, ft.AccountID, ft.TagID)
+	if err != nil {
+		return nil, fmt.Errorf("FeaturedTagToAPIFeaturedTag: error counting statuses with tag %s: %s", ft.TagID, err)
+	}
+
+	var lastStatusAtString string
+	if !lastStatusAt.IsZero() {
+		lastStatusAtString = lastStatusAt.Format(time.RFC3339)
+	}
+
+	return &model.FeaturedTag{
+		ID:            ft.ID,
+		Name:          ft.Tag.Name,
+		URL:           ft.Tag.URL,
+		StatusesCount: statusesCount,
+		LastStatusAt:  lastStatusAtString,
+	}, nil
+}
+
+func (c *converter) ExportToAPIExport(ctx context.Context, e *gtsmodel.Export) (*model.Export, error) {
+	apiExport := &model.Export{
+		ID:        e.ID,
+		Type:      string(e.Type),
+		State:     string(e.State),
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: e.ExpiresAt().Format(time.RFC3339),
+		Size:      e.Size,
+	}
+
+	if e.State == gtsmodel.ExportStateDone {
+		protocol := viper.GetString(config.Keys.Protocol)
+		host := viper.GetString(config.Keys.Host)
+		apiExport.URL = fmt.Sprintf("%s://%s/api/v1/exports/%s/download", protocol, host, e.ID)
+	}
+
+	return apiExport, nil
+}
+
+func (c *converter) ImportToAPIImport(ctx context.Context, i *gtsmodel.Import) (*model.Import, error) {
+	failedAddresses := make([]string, 0, len(i.Failures))
+	for _, f := range i.Failures {
+		failedAddresses = append(failedAddresses, f.Address)
+	}
+
+	return &model.Import{
+		ID:              i.ID,
+		Type:            string(i.Type),
+		State:           string(i.State),
+		CreatedAt:       i.CreatedAt.Format(time.RFC3339),
+		Total:           i.Total,
+		Processed:       i.Processed,
+		FailedAddresses: failedAddresses,
+	}, nil
+}
+
+func (c *converter) PollToAPIPoll(ctx context.Context, p *gtsmodel.Poll, requestingAccount *gtsmodel.Account) (*model.Poll, error) {
+	closed := p.Closed()
+
+	// vote counts are only shown before the poll closes if the author allows it
+	showCounts := closed || !p.HideCounts
+
+	apiOptions := make([]model.PollOptions, 0, len(p.Options))
+	votesCount := 0
+	for i, title := range p.Options {
+		option := model.PollOptions{
+			Title: title,
+		}
+		if showCounts && i < len(p.Votes) {
+			option.VotesCount = p.Votes[i]
+			votesCount += p.Votes[i]
+		}
+		apiOptions = append(apiOptions, option)
+	}
+
+	apiPoll := &model.Poll{
+		ID:       p.ID,
+		Expired:  closed,
+		Multiple: p.Multiple,
+		Options:  apiOptions,
+		Emojis:   []model.Emoji{},
+	}
+
+	if !p.ExpiresAt.IsZero() {
+		apiPoll.ExpiresAt = p.ExpiresAt.Format(time.RFC3339)
+	}
+
+	if showCounts {
+		apiPoll.VotesCount = votesCount
+		if p.Multiple {
+			apiPoll.VotersCount = p.VotersCount
+		}
+	}
+
+	if requestingAccount != nil {
+		votes, err := c.db.GetAccountPollVotes(ctx, p.ID, requestingAccount.ID)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting votes of account %s in poll %s: %s", requestingAccount.ID, p.ID, err)
+		}
+		for _, v := range votes {
+			apiPoll.OwnVotes = append(apiPoll.OwnVotes, v.Choice)
+		}
+		apiPoll.Voted = len(apiPoll.OwnVotes) != 0
+	}
+
+	return apiPoll, nil
+}
+
 func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*model.Status, error) {
+	return c.statusToAPIStatus(ctx, s, requestingAccount, true)
+}
+
+// statusToAPIStatus does the work of StatusToAPIStatus. The quoted status is only included if withQuote
+// is true, so that a quote of a quote doesn't drag in a whole chain of statuses.
+func (c *converter) statusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account, withQuote bool) (*model.Status, error) {
 	repliesCount, err := c.db.CountStatusReplies(ctx, s)
 	if err != nil {
 		return nil, fmt.Errorf("error counting replies: %s", err)
@@ -501,7 +737,39 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 	}
 
 	var apiCard *model.Card
+
 	var apiPoll *model.Poll
+	if s.PollID != "" {
+		gtsPoll := s.Poll
+		if gtsPoll == nil {
+			gtsPoll, err = c.db.GetPollByID(ctx, s.PollID)
+			if err != nil {
+				return nil, fmt.Errorf("error getting poll with id %s: %s", s.PollID, err)
+			}
+		}
+		apiPoll, err = c.PollToAPIPoll(ctx, gtsPoll, requestingAccount)
+		if err != nil {
+			return nil, fmt.Errorf("error converting poll with id %s: %s", s.PollID, err)
+		}
+	}
+
+	var apiPleroma *model.StatusPleroma
+	reactions, err := c.db.GetStatusReactions(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("error getting reactions: %s", err)
+	}
+	if len(reactions) != 0 {
+		apiReactions, err := c.StatusReactionsToAPIEmojiReactions(ctx, reactions, requestingAccount, false)
+		if err != nil {
+			return nil, fmt.Errorf("error converting reactions: %s", err)
+		}
+		apiPleroma = &model.StatusPleroma{EmojiReactions: apiReactions}
+	}
+
+	var editedAt string
+	if !s.EditedAt.IsZero() {
+		editedAt = s.EditedAt.Format(time.RFC3339)
+	}
 
 	statusInteractions := &statusInteractions{}
 	si, err := c.interactionsWithStatusForAccount(ctx, s, requestingAccount)
@@ -512,6 +780,7 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 	apiStatus := &model.Status{
 		ID:                 s.ID,
 		CreatedAt:          s.CreatedAt.Format(time.RFC3339),
+		EditedAt:           editedAt,
 		InReplyToID:        s.InReplyToID,
 		InReplyToAccountID: s.InReplyToAccountID,
 		Sensitive:          s.Sensitive,
@@ -536,17 +805,141 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 		Tags:               apiTags,
 		Emojis:             apiEmojis,
 		Card:               apiCard, // TODO: implement cards
-		Poll:               apiPoll, // TODO: implement polls
+		Poll:               apiPoll,
 		Text:               s.Text,
+		Pleroma:            apiPleroma,
 	}
 
 	if apiRebloggedStatus != nil {
 		apiStatus.Reblog = &model.StatusReblogged{Status: apiRebloggedStatus}
 	}
 
+	if withQuote && s.QuoteOfID != "" {
+		quoteOf := s.QuoteOf
+		if quoteOf == nil {
+			// the quoted status may have been deleted since, in which case we just leave it out
+			if qs, err := c.db.GetStatusByID(ctx, s.QuoteOfID); err == nil {
+				quoteOf = qs
+			}
+		}
+
+		// only publicly visible statuses are shown in quotes, anything else could leak to people who shouldn't see it;
+		// even then, the quote is left out for anyone who couldn't see the quoted status by itself, eg., because of a block
+		if quoteOf != nil && (quoteOf.Visibility == gtsmodel.VisibilityPublic || quoteOf.Visibility == gtsmodel.VisibilityUnlocked) {
+			visible, err := c.filter.StatusVisible(ctx, quoteOf, requestingAccount)
+			if err != nil {
+				return nil, fmt.Errorf("error checking visibility of quoted status with id %s: %s", s.QuoteOfID, err)
+			}
+			if !visible {
+				return apiStatus, nil
+			}
+
+			apiQuote, err := c.statusToAPIStatus(ctx, quoteOf, requestingAccount, false)
+			if err != nil {
+				return nil, fmt.Errorf("error converting quoted status with id %s: %s", s.QuoteOfID, err)
+			}
+			apiStatus.Quote = apiQuote
+		}
+	}
+
 	return apiStatus, nil
 }
 
+func (c *converter) StatusEditToAPIStatusEdit(ctx context.Context, e *gtsmodel.StatusEdit, s *gtsmodel.Status) (*model.StatusEdit, error) {
+	if s.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, s.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting status author: %s", err)
+		}
+		s.Account = a
+	}
+
+	apiAuthorAccount, err := c.AccountToAPIAccountPublic(ctx, s.Account)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing account of status author: %s", err)
+	}
+
+	apiAttachments := []model.Attachment{}
+	for _, aID := range e.AttachmentIDs {
+		gtsAttachment, err := c.db.GetAttachmentByID(ctx, aID)
+		if err != nil {
+			logrus.Errorf("error getting attachment with id %s: %s", aID, err)
+			continue
+		}
+		apiAttachment, err := c.AttachmentToAPIAttachment(ctx, gtsAttachment)
+		if err != nil {
+			logrus.Errorf("error converting attachment with id %s: %s", aID, err)
+			continue
+		}
+		apiAttachments = append(apiAttachments, apiAttachment)
+	}
+
+	apiEmojis := []model.Emoji{}
+	for _, eID := range e.EmojiIDs {
+		gtsEmoji := &gtsmodel.Emoji{}
+		if err := c.db.GetByID(ctx, eID, gtsEmoji); err != nil {
+			logrus.Errorf("error getting emoji with id %s: %s", eID, err)
+			continue
+		}
+		apiEmoji, err := c.EmojiToAPIEmoji(ctx, gtsEmoji)
+		if err != nil {
+			logrus.Errorf("error converting emoji with id %s: %s", eID, err)
+			continue
+		}
+		apiEmojis = append(apiEmojis, apiEmoji)
+	}
+
+	return &model.StatusEdit{
+		Content:          e.Content,
+		SpoilerText:      e.ContentWarning,
+		Sensitive:        e.Sensitive,
+		CreatedAt:        e.CreatedAt.Format(time.RFC3339),
+		Account:          apiAuthorAccount,
+		MediaAttachments: apiAttachments,
+		Emojis:           apiEmojis,
+	}, nil
+}
+
+func (c *converter) StatusReactionsToAPIEmojiReactions(ctx context.Context, reactions []*gtsmodel.StatusReaction, requestingAccount *gtsmodel.Account, withAccounts bool) ([]model.EmojiReaction, error) {
+	apiReactions := []model.EmojiReaction{}
+	indexes := make(map[string]int, len(reactions)) // index of each emoji in apiReactions
+
+	for _, r := range reactions {
+		i, ok := indexes[r.Emoji]
+		if !ok {
+			apiReaction := model.EmojiReaction{Name: r.Emoji}
+			if r.CustomEmoji != nil {
+				apiReaction.URL = r.CustomEmoji.ImageURL
+			}
+			i = len(apiReactions)
+			indexes[r.Emoji] = i
+			apiReactions = append(apiReactions, apiReaction)
+		}
+
+		apiReactions[i].Count++
+		if requestingAccount != nil && r.AccountID == requestingAccount.ID {
+			apiReactions[i].Me = true
+		}
+
+		if withAccounts {
+			if r.Account == nil {
+				a, err := c.db.GetAccountByID(ctx, r.AccountID)
+				if err != nil {
+					return nil, fmt.Errorf("error getting account %s of reaction: %s", r.AccountID, err)
+				}
+				r.Account = a
+			}
+			apiAccount, err := c.AccountToAPIAccountPublic(ctx, r.Account)
+			if err != nil {
+				return nil, fmt.Errorf("error converting account %s of reaction: %s", r.AccountID, err)
+			}
+			apiReactions[i].Accounts = append(apiReactions[i].Accounts, *apiAccount)
+		}
+	}
+
+	return apiReactions, nil
+}
+
 // VisToapi converts a gts visibility into its api equivalent
 func (c *converter) VisToAPIVis(ctx context.Context, m gtsmodel.Visibility) model.Visibility {
 	switch m {
@@ -572,6 +965,7 @@ func (c *converter) InstanceToAPIInstance(ctx context.Context, i *gtsmodel.Insta
 		Version:          i.Version,
 		Stats:            make(map[string]int),
 		ContactAccount:   &model.Account{},
+		Rules:            []model.InstanceRule{},
 	}
 
 	// if the requested instance is *this* instance, we can add some extra information
@@ -601,6 +995,43 @@ func (c *converter) InstanceToAPIInstance(ctx context.Context, i *gtsmodel.Insta
 			StreamingAPI: fmt.Sprintf("wss://%s", host),
 		}
 		mi.Version = viper.GetString(keys.SoftwareVersion)
+		mi.Configuration = &model.InstanceConfiguration{
+			Statuses: &model.InstanceConfigurationStatuses{
+				MaxCharacters:               viper.GetInt(keys.StatusesMaxChars),
+				MaxContentWarningCharacters: viper.GetInt(keys.StatusesCWMaxChars),
+				MaxMediaAttachments:         viper.GetInt(keys.StatusesMediaMaxFiles),
+				CharactersReservedPerURL:    text.CharactersReservedPerURL,
+			},
+			Polls: &model.InstanceConfigurationPolls{
+				MaxOptions:             viper.GetInt(keys.StatusesPollMaxOptions),
+				MaxCharactersPerOption: viper.GetInt(keys.StatusesPollOptionMaxChars),
+				MinExpiration:          int(gtsmodel.PollMinExpiresIn.Seconds()),
+				MaxExpiration:          int(gtsmodel.PollMaxExpiresIn.Seconds()),
+			},
+			MediaAttachments: &model.InstanceConfigurationMediaAttachments{
+				ImageSizeLimit:     viper.GetInt(keys.MediaImageMaxSize),
+				ImageMatrixLimit:   viper.GetInt(keys.MediaImageMaxPixels),
+				VideoSizeLimit:     viper.GetInt(keys.MediaVideoMaxSize),
+				VideoMatrixLimit:   viper.GetInt(keys.MediaVideoMaxPixels),
+				VideoDurationLimit: viper.GetInt(keys.MediaVideoMaxDuration),
+			},
+			Emojis: &model.InstanceConfigurationEmojis{
+				EmojiSizeLimit:   viper.GetInt(keys.MediaEmojiMaxSize),
+				EmojiMatrixLimit: viper.GetInt(keys.MediaEmojiMaxPixels),
+			},
+		}
+
+		rules, err := c.db.GetActiveRules(ctx)
+		if err != nil && err != db.ErrNoEntries {
+			return nil, fmt.Errorf("error getting rules: %s", err)
+		}
+		for _, r := range rules {
+			apiRule, err := c.RuleToAPIInstanceRule(ctx, r)
+			if err != nil {
+				return nil, fmt.Errorf("error converting rule %s: %s", r.ID, err)
+			}
+			mi.Rules = append(mi.Rules, apiRule)
+		}
 	}
 
 	// get the instance account if it exists and just skip if it doesn't
@@ -628,7 +1059,226 @@ func (c *converter) InstanceToAPIInstance(ctx context.Context, i *gtsmodel.Insta
 	return mi, nil
 }
 
+func (c *converter) InstanceToAPIV2Instance(ctx context.Context, i *gtsmodel.Instance) (*model.InstanceV2, error) {
+	// most of the information is the same as in v1, it's just laid out differently
+	v1, err := c.InstanceToAPIInstance(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := config.Keys
+	mi := &model.InstanceV2{
+		Domain:      i.Domain,
+		Title:       i.Title,
+		Version:     v1.Version,
+		SourceURL:   "https://github.com/superseriousbusiness/gotosocial",
+		Description: i.ShortDescription,
+		Thumbnail: model.InstanceV2Thumbnail{
+			URL: v1.Thumbnail,
+		},
+		Languages: []string{},
+		Configuration: model.InstanceV2Configuration{
+			URLs: model.InstanceV2URLs{
+				Streaming: fmt.Sprintf("wss://%s", viper.GetString(keys.Host)),
+			},
+			Translation: model.InstanceV2ConfigurationTranslation{
+				Enabled: viper.GetString(keys.TranslationBackend) != "",
+			},
+		},
+		Registrations: model.InstanceV2Registrations{
+			Enabled:          v1.Registrations,
+			ApprovalRequired: v1.ApprovalRequired,
+		},
+		Contact: model.InstanceV2Contact{
+			Email: i.ContactEmail,
+		},
+		Rules: v1.Rules,
+	}
+
+	if v1.Languages != nil {
+		mi.Languages = v1.Languages
+	}
+
+	if v1.Configuration != nil {
+		mi.Configuration.Statuses = *v1.Configuration.Statuses
+		mi.Configuration.Polls = *v1.Configuration.Polls
+		mi.Configuration.MediaAttachments = *v1.Configuration.MediaAttachments
+		mi.Configuration.Emojis = *v1.Configuration.Emojis
+	}
+
+	if v1.ContactAccount != nil && v1.ContactAccount.ID != "" {
+		mi.Contact.Account = v1.ContactAccount
+	}
+
+	return mi, nil
+}
+
+func (c *converter) ReportToAPIReport(ctx context.Context, r *gtsmodel.Report) (*model.Report, error) {
+	if r.TargetAccount == nil {
+		targetAccount, err := c.db.GetAccountByID(ctx, r.TargetAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report target account %s: %s", r.TargetAccountID, err)
+		}
+		r.TargetAccount = targetAccount
+	}
+
+	apiTargetAccount, err := c.AccountToAPIAccountPublic(ctx, r.TargetAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error converting report target account: %s", err)
+	}
+
+	// reports from before categories existed count as other
+	category := r.Category
+	if category == "" {
+		category = gtsmodel.ReportCategoryOther
+	}
+
+	apiReport := &model.Report{
+		ID:            r.ID,
+		ActionTaken:   !r.ActionTakenAt.IsZero(),
+		Category:      string(category),
+		Comment:       r.Comment,
+		Forwarded:     r.Forwarded,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+		StatusIDs:     []string{},
+		RuleIDs:       []string{},
+		TargetAccount: apiTargetAccount,
+	}
+
+	if apiReport.ActionTaken {
+		actionTakenAt := r.ActionTakenAt.Format(time.RFC3339)
+		apiReport.ActionTakenAt = &actionTakenAt
+	}
+
+	apiReport.StatusIDs = append(apiReport.StatusIDs, r.StatusIDs...)
+	apiReport.RuleIDs = append(apiReport.RuleIDs, r.RuleIDs...)
+
+	return apiReport, nil
+}
+
+func (c *converter) ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Report, requestingAccount *gtsmodel.Account) (*model.AdminReportInfo, error) {
+	if r.Account == nil {
+		account, err := c.db.GetAccountByID(ctx, r.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report account %s: %s", r.AccountID, err)
+		}
+		r.Account = account
+	}
+
+	if r.TargetAccount == nil {
+		targetAccount, err := c.db.GetAccountByID(ctx, r.TargetAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report target account %s: %s", r.TargetAccountID, err)
+		}
+		r.TargetAccount = targetAccount
+	}
+
+	apiAccount, err := c.AccountToAPIAccountPublic(ctx, r.Account)
+	if err != nil {
+		return nil, fmt.Errorf("error converting report account: %s", err)
+	}
+
+	apiTargetAccount, err := c.AccountToAPIAccountPublic(ctx, r.TargetAccount)
+	if err != nil {
+		return nil, fmt.Errorf("error converting report target account: %s", err)
+	}
+
+	// reports from before categories existed count as other
+	category := r.Category
+	if category == "" {
+		category = gtsmodel.ReportCategoryOther
+	}
+
+	apiReport := &model.AdminReportInfo{
+		ID:                 r.ID,
+		ActionTaken:        !r.ActionTakenAt.IsZero(),
+		ActionTakenComment: r.ActionTaken,
+		Category:           string(category),
+		Comment:            r.Comment,
+		Forwarded:          r.Forwarded,
+		CreatedAt:          r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          r.UpdatedAt.Format(time.RFC3339),
+		Account:            apiAccount,
+		TargetAccount:      apiTargetAccount,
+		Statuses:           []model.Status{},
+		Rules:              []model.InstanceRule{},
+	}
+
+	if apiReport.ActionTaken {
+		actionTakenAt := r.ActionTakenAt.Format(time.RFC3339)
+		apiReport.ActionTakenAt = &actionTakenAt
+	}
+
+	if r.AssignedAccountID != "" {
+		assignedAccount, err := c.db.GetAccountByID(ctx, r.AssignedAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report assigned account %s: %s", r.AssignedAccountID, err)
+		}
+		apiReport.AssignedAccount, err = c.AccountToAPIAccountPublic(ctx, assignedAccount)
+		if err != nil {
+			return nil, fmt.Errorf("error converting report assigned account: %s", err)
+		}
+	}
+
+	if r.ActionTakenByAccountID != "" {
+		actionTakenByAccount, err := c.db.GetAccountByID(ctx, r.ActionTakenByAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report action taken by account %s: %s", r.ActionTakenByAccountID, err)
+		}
+		apiReport.ActionTakenByAccount, err = c.AccountToAPIAccountPublic(ctx, actionTakenByAccount)
+		if err != nil {
+			return nil, fmt.Errorf("error converting report action taken by account: %s", err)
+		}
+	}
+
+	// statuses may have been deleted since the report was made, in which case they're left out
+	for _, statusID := range r.StatusIDs {
+		status, err := c.db.GetStatusByID(ctx, statusID)
+		if err != nil {
+			if err == db.ErrNoEntries {
+				continue
+			}
+			return nil, fmt.Errorf("error getting report status %s: %s", statusID, err)
+		}
+		apiStatus, err := c.StatusToAPIStatus(ctx, status, requestingAccount)
+		if err != nil {
+			return nil, fmt.Errorf("error converting report status %s: %s", statusID, err)
+		}
+		apiReport.Statuses = append(apiReport.Statuses, *apiStatus)
+	}
+
+	// rules that were deleted since are still shown, since the report was made under them
+	for _, ruleID := range r.RuleIDs {
+		rule := &gtsmodel.Rule{}
+		if err := c.db.GetByID(ctx, ruleID, rule); err != nil {
+			if err == db.ErrNoEntries {
+				continue
+			}
+			return nil, fmt.Errorf("error getting report rule %s: %s", ruleID, err)
+		}
+		apiRule, err := c.RuleToAPIInstanceRule(ctx, rule)
+		if err != nil {
+			return nil, fmt.Errorf("error converting report rule %s: %s", ruleID, err)
+		}
+		apiReport.Rules = append(apiReport.Rules, apiRule)
+	}
+
+	return apiReport, nil
+}
+
+func (c *converter) RuleToAPIInstanceRule(ctx context.Context, r *gtsmodel.Rule) (model.InstanceRule, error) {
+	return model.InstanceRule{
+		ID:   r.ID,
+		Text: r.Text,
+	}, nil
+}
+
 func (c *converter) RelationshipToAPIRelationship(ctx context.Context, r *gtsmodel.Relationship) (*model.Relationship, error) {
+	var muteExpiresAt string
+	if !r.MuteExpiresAt.IsZero() {
+		muteExpiresAt = r.MuteExpiresAt.Format(time.RFC3339)
+	}
+
 	return &model.Relationship{
 		ID:                  r.ID,
 		Following:           r.Following,
@@ -639,6 +1289,7 @@ func (c *converter) RelationshipToAPIRelationship(ctx context.Context, r *gtsmod
 		BlockedBy:           r.BlockedBy,
 		Muting:              r.Muting,
 		MutingNotifications: r.MutingNotifications,
+		MuteExpiresAt:       muteExpiresAt,
 		Requested:           r.Requested,
 		DomainBlocking:      r.DomainBlocking,
 		Endorsed:            r.Endorsed,
@@ -705,14 +1356,17 @@ func (c *converter) NotificationToAPINotification(ctx context.Context, n *gtsmod
 func (c *converter) DomainBlockToAPIDomainBlock(ctx context.Context, b *gtsmodel.DomainBlock, export bool) (*model.DomainBlock, error) {
 
 	domainBlock := &model.DomainBlock{
-		Domain:        b.Domain,
-		PublicComment: b.PublicComment,
+		Domain:            b.Domain,
+		Obfuscate:         b.Obfuscate,
+		IncludeSubdomains: b.IncludeSubdomains,
+		Severity:          b.Severity,
+		RejectMedia:       b.RejectMedia,
+		PublicComment:     b.PublicComment,
 	}
 
 	// if we're exporting a domain block, return it with minimal information attached
 	if !export {
 		domainBlock.ID = b.ID
-		domainBlock.Obfuscate = b.Obfuscate
 		domainBlock.PrivateComment = b.PrivateComment
 		domainBlock.SubscriptionID = b.SubscriptionID
 		domainBlock.CreatedBy = b.CreatedByAccountID
@@ -721,3 +1375,193 @@ func (c *converter) DomainBlockToAPIDomainBlock(ctx context.Context, b *gtsmodel
 
 	return domainBlock, nil
 }
+
+func (c *converter) WebPushSubscriptionToAPIWebPushSubscription(ctx context.Context, s *gtsmodel.WebPushSubscription) (*model.WebPushSubscription, error) {
+	keyPair, err := c.db.GetVAPIDKeyPair(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("WebPushSubscriptionToAPIWebPushSubscription: error getting vapid key pair: %s", err)
+	}
+
+	return &model.WebPushSubscription{
+		ID:       s.ID,
+		Endpoint: s.Endpoint,
+		Alerts: model.WebPushSubscriptionAlerts{
+			Follow:        s.AlertFollow,
+			FollowRequest: s.AlertFollowRequest,
+			Favourite:     s.AlertFavourite,
+			Reblog:        s.AlertReblog,
+			Mention:       s.AlertMention,
+			Poll:          s.AlertPoll,
+			Status:        s.AlertStatus,
+		},
+		ServerKey: keyPair.PublicKey,
+	}, nil
+}
+
+func (c *converter) FilterKeywordToAPIFilter(ctx context.Context, f *gtsmodel.Filter, k *gtsmodel.FilterKeyword) (*model.Filter, error) {
+	apiFilter := &model.Filter{
+		ID:           k.ID,
+		Phrase:       k.Keyword,
+		Context:      filterContextsToAPIContexts(f),
+		WholeWord:    k.WholeWord,
+		Irreversible: f.Action == gtsmodel.FilterActionHide,
+	}
+
+	if !f.ExpiresAt.IsZero() {
+		apiFilter.ExpiresAt = f.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return apiFilter, nil
+}
+
+func (c *converter) FilterToAPIFilterV2(ctx context.Context, f *gtsmodel.Filter) (*model.FilterV2, error) {
+	apiFilter := &model.FilterV2{
+		ID:           f.ID,
+		Title:        f.Title,
+		Context:      filterContextsToAPIContexts(f),
+		FilterAction: string(f.Action),
+		Keywords:     []model.FilterKeyword{},
+		Statuses:     []model.FilterStatus{},
+	}
+
+	if !f.ExpiresAt.IsZero() {
+		apiFilter.ExpiresAt = f.ExpiresAt.Format(time.RFC3339)
+	}
+
+	for _, k := range f.Keywords {
+		apiKeyword, err := c.FilterKeywordToAPIFilterKeyword(ctx, k)
+		if err != nil {
+			return nil, fmt.Errorf("FilterToAPIFilterV2: error converting keyword with id %s: %s", k.ID, err)
+		}
+		apiFilter.Keywords = append(apiFilter.Keywords, *apiKeyword)
+	}
+
+	for _, s := range f.Statuses {
+		apiStatus, err := c.FilterStatusToAPIFilterStatus(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("FilterToAPIFilterV2: error converting filter status with id %s: %s", s.ID, err)
+		}
+		apiFilter.Statuses = append(apiFilter.Statuses, *apiStatus)
+	}
+
+	return apiFilter, nil
+}
+
+func (c *converter) FilterKeywordToAPIFilterKeyword(ctx context.Context, k *gtsmodel.FilterKeyword) (*model.FilterKeyword, error) {
+	return &model.FilterKeyword{
+		ID:        k.ID,
+		Keyword:   k.Keyword,
+		WholeWord: k.WholeWord,
+	}, nil
+}
+
+func (c *converter) FilterStatusToAPIFilterStatus(ctx context.Context, s *gtsmodel.FilterStatus) (*model.FilterStatus, error) {
+	return &model.FilterStatus{
+		ID:       s.ID,
+		StatusID: s.StatusID,
+	}, nil
+}
+
+func (c *converter) FilterResultsToAPIFilterResults(ctx context.Context, r []*gtsmodel.FilterResult) ([]model.FilterResult, error) {
+	apiResults := []model.FilterResult{}
+
+	for _, result := range r {
+		apiFilter, err := c.FilterToAPIFilterV2(ctx, result.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("FilterResultsToAPIFilterResults: error converting filter with id %s: %s", result.Filter.ID, err)
+		}
+		apiResults = append(apiResults, model.FilterResult{
+			Filter:         *apiFilter,
+			KeywordMatches: result.KeywordMatches,
+			StatusMatches:  result.StatusMatches,
+		})
+	}
+
+	return apiResults, nil
+}
+
+// filterContextsToAPIContexts returns the api names of the contexts that the given filter applies in.
+func filterContextsToAPIContexts(f *gtsmodel.Filter) []string {
+	contexts := []string{}
+	for _, context := range gtsmodel.FilterContexts {
+		if f.AppliesTo(context) {
+			contexts = append(contexts, string(context))
+		}
+	}
+	return contexts
+}
+
+func (c *converter) ConversationToAPIConversation(ctx context.Context, conv *gtsmodel.Conversation) (*model.Conversation, error) {
+	if conv.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, conv.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("ConversationToAPIConversation: error getting account %s: %s", conv.AccountID, err)
+		}
+		conv.Account = a
+	}
+
+	if conv.OtherAccounts == nil {
+		for _, id := range conv.OtherAccountIDs {
+			a, err := c.db.GetAccountByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("ConversationToAPIConversation: error getting account %s: %s", id, err)
+			}
+			conv.OtherAccounts = append(conv.OtherAccounts, a)
+		}
+	}
+
+	if conv.LastStatus == nil {
+		s, err := c.db.GetStatusByID(ctx, conv.LastStatusID)
+		if err != nil {
+			return nil, fmt.Errorf("ConversationToAPIConversation: error getting status %s: %s", conv.LastStatusID, err)
+		}
+		conv.LastStatus = s
+	}
+
+	// a conversation with nobody else in it is shown as being with the account itself
+	accounts := conv.OtherAccounts
+	if len(accounts) == 0 {
+		accounts = []*gtsmodel.Account{conv.Account}
+	}
+
+	apiAccounts := make([]model.Account, 0, len(accounts))
+	for _, a := range accounts {
+		apiAccount, err := c.AccountToAPIAccountPublic(ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("ConversationToAPIConversation: error converting account %s to api: %s", a.ID, err)
+		}
+		apiAccounts = append(apiAccounts, *apiAccount)
+	}
+
+	apiStatus, err := c.StatusToAPIStatus(ctx, conv.LastStatus, conv.Account)
+	if err != nil {
+		return nil, fmt.Errorf("ConversationToAPIConversation: error converting status %s to api: %s", conv.LastStatus.ID, err)
+	}
+
+	return &model.Conversation{
+		ID:         conv.ID,
+		Accounts:   apiAccounts,
+		Unread:     !conv.Read,
+		LastStatus: apiStatus,
+	}, nil
+}
+
+func (c *converter) MarkersToAPIMarker(ctx context.Context, markers []*gtsmodel.Marker) (*model.Marker, error) {
+	apiMarker := &model.Marker{}
+	for _, marker := range markers {
+		apiTimelineMarker := &model.TimelineMarker{
+			LastReadID: marker.LastReadID,
+			UpdatedAt:  marker.UpdatedAt.Format(time.RFC3339),
+			Version:    marker.Version,
+		}
+		switch marker.Name {
+		case gtsmodel.MarkerNameHome:
+			apiMarker.Home = apiTimelineMarker
+		case gtsmodel.MarkerNameNotifications:
+			apiMarker.Notifications = apiTimelineMarker
+		default:
+			return nil, fmt.Errorf("MarkersToAPIMarker: unknown marker name %s", marker.Name)
+		}
+	}
+	return apiMarker, nil
+}
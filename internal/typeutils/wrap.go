@@ -68,15 +68,15 @@ func (c *converter) WrapPersonInUpdate(person vocab.ActivityStreamsPerson, origi
 	return update, nil
 }
 
-func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error) {
+func (c *converter) WrapNoteInCreate(note ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error) {
 	create := streams.NewActivityStreamsCreate()
 
 	// Object property
 	objectProp := streams.NewActivityStreamsObjectProperty()
 	if objectIRIOnly {
 		objectProp.AppendIRI(note.GetJSONLDId().GetIRI())
-	} else {
-		objectProp.AppendActivityStreamsNote(note)
+	} else if err := objectProp.AppendType(note); err != nil {
+		return nil, fmt.Errorf("WrapNoteInCreate: error setting object: %s", err)
 	}
 	create.SetActivityStreamsObject(objectProp)
 
@@ -130,3 +130,60 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	return create, nil
 }
+
+func (c *converter) WrapNoteInUpdate(note ap.Statusable, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error) {
+	update := streams.NewActivityStreamsUpdate()
+
+	// Object property
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	if err := objectProp.AppendType(note); err != nil {
+		return nil, fmt.Errorf("WrapNoteInUpdate: error setting object: %s", err)
+	}
+	update.SetActivityStreamsObject(objectProp)
+
+	// ID property
+	newID, err := id.NewRandomULID()
+	if err != nil {
+		return nil, err
+	}
+
+	idString := uris.GenerateURIForUpdate(originAccount.Username, newID)
+	idURI, err := url.Parse(idString)
+	if err != nil {
+		return nil, fmt.Errorf("WrapNoteInUpdate: error parsing url %s: %s", idString, err)
+	}
+	idProp := streams.NewJSONLDIdProperty()
+	idProp.SetIRI(idURI)
+	update.SetJSONLDId(idProp)
+
+	// Actor Property
+	actorURI, err := url.Parse(originAccount.URI)
+	if err != nil {
+		return nil, fmt.Errorf("WrapNoteInUpdate: error parsing url %s: %s", originAccount.URI, err)
+	}
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorProp.AppendIRI(actorURI)
+	update.SetActivityStreamsActor(actorProp)
+
+	// To Property
+	toProp := streams.NewActivityStreamsToProperty()
+	tos, err := ap.ExtractTos(note)
+	if err == nil {
+		for _, to := range tos {
+			toProp.AppendIRI(to)
+		}
+		update.SetActivityStreamsTo(toProp)
+	}
+
+	// Cc Property
+	ccProp := streams.NewActivityStreamsCcProperty()
+	ccs, err := ap.ExtractCCs(note)
+	if err == nil {
+		for _, cc := range ccs {
+			ccProp.AppendIRI(cc)
+		}
+		update.SetActivityStreamsCc(ccProp)
+	}
+
+	return update, nil
+}
@@ -69,6 +69,37 @@ func (suite *WrapTestSuite) TestWrapNoteInCreate() {
 	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","actor":"http://localhost:8080/users/the_mighty_zork","cc":"http://localhost:8080/users/the_mighty_zork/followers","id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/activity","object":{"attachment":[],"attributedTo":"http://localhost:8080/users/the_mighty_zork","cc":"http://localhost:8080/users/the_mighty_zork/followers","content":"hello everyone!","id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY","published":"2021-10-20T12:40:37+02:00","replies":{"first":{"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?page=true","next":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?only_other_accounts=false\u0026page=true","partOf":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"CollectionPage"},"id":"http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies","type":"Collection"},"sensitive":true,"summary":"introduction post","tag":[],"to":"https://www.w3.org/ns/activitystreams#Public","type":"Note","url":"http://localhost:8080/@the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY"},"published":"2021-10-20T12:40:37+02:00","to":"https://www.w3.org/ns/activitystreams#Public","type":"Create"}`, string(bytes))
 }
 
+func (suite *WrapTestSuite) TestWrapNoteInUpdate() {
+	testStatus := suite.testStatuses["local_account_1_status_1"]
+	testAccount := suite.testAccounts["local_account_1"]
+
+	note, err := suite.typeconverter.StatusToAS(context.Background(), testStatus)
+	suite.NoError(err)
+
+	update, err := suite.typeconverter.WrapNoteInUpdate(note, testAccount)
+	suite.NoError(err)
+	suite.NotNil(update)
+
+	updateI, err := streams.Serialize(update)
+	suite.NoError(err)
+
+	// the id of the update is random, so check it separately
+	suite.Regexp(`^http://localhost:8080/users/the_mighty_zork#updates/[0-9A-Z]{26}$`, updateI["id"])
+	delete(updateI, "id")
+	delete(updateI, "object")
+
+	bytes, err := json.Marshal(updateI)
+	suite.NoError(err)
+
+	suite.Equal(`{"@context":"https://www.w3.org/ns/activitystreams","actor":"http://localhost:8080/users/the_mighty_zork","cc":"http://localhost:8080/users/the_mighty_zork/followers","to":"https://www.w3.org/ns/activitystreams#Public","type":"Update"}`, string(bytes))
+
+	// the whole note should be included, rather than just its id
+	object := update.GetActivityStreamsObject()
+	suite.Equal(1, object.Len())
+	suite.True(object.At(0).IsActivityStreamsNote())
+	suite.Equal(testStatus.URI, object.At(0).GetActivityStreamsNote().GetJSONLDId().Get().String())
+}
+
 func TestWrapTestSuite(t *testing.T) {
 	suite.Run(t, new(WrapTestSuite))
 }
@@ -28,23 +28,29 @@ import (
 )
 
 const (
-	UsersPath        = "users"         // UsersPath is for serving users info
-	ActorsPath       = "actors"        // ActorsPath is for serving actors info
-	StatusesPath     = "statuses"      // StatusesPath is for serving statuses
-	InboxPath        = "inbox"         // InboxPath represents the activitypub inbox location
-	OutboxPath       = "outbox"        // OutboxPath represents the activitypub outbox location
-	FollowersPath    = "followers"     // FollowersPath represents the activitypub followers location
-	FollowingPath    = "following"     // FollowingPath represents the activitypub following location
-	LikedPath        = "liked"         // LikedPath represents the activitypub liked location
-	CollectionsPath  = "collections"   // CollectionsPath represents the activitypub collections location
-	FeaturedPath     = "featured"      // FeaturedPath represents the activitypub featured location
-	PublicKeyPath    = "main-key"      // PublicKeyPath is for serving an account's public key
-	FollowPath       = "follow"        // FollowPath used to generate the URI for an individual follow or follow request
-	UpdatePath       = "updates"       // UpdatePath is used to generate the URI for an account update
-	BlocksPath       = "blocks"        // BlocksPath is used to generate the URI for a block
-	ConfirmEmailPath = "confirm_email" // ConfirmEmailPath is used to generate the URI for an email confirmation link
-	FileserverPath   = "fileserver"    // FileserverPath is a path component for serving attachments + media
-	EmojiPath        = "emoji"         // EmojiPath represents the activitypub emoji location
+	UsersPath            = "users"             // UsersPath is for serving users info
+	ActorsPath           = "actors"            // ActorsPath is for serving actors info
+	StatusesPath         = "statuses"          // StatusesPath is for serving statuses
+	InboxPath            = "inbox"             // InboxPath represents the activitypub inbox location
+	OutboxPath           = "outbox"            // OutboxPath represents the activitypub outbox location
+	FollowersPath        = "followers"         // FollowersPath represents the activitypub followers location
+	FollowingPath        = "following"         // FollowingPath represents the activitypub following location
+	LikedPath            = "liked"             // LikedPath represents the activitypub liked location
+	CollectionsPath      = "collections"       // CollectionsPath represents the activitypub collections location
+	FeaturedPath         = "featured"          // FeaturedPath represents the activitypub featured location
+	FeaturedAccountsPath = "featured_accounts" // FeaturedAccountsPath represents the activitypub featured accounts location
+	FeaturedTagsPath     = "tags"              // FeaturedTagsPath represents the activitypub featured tags location
+	PublicKeyPath        = "main-key"          // PublicKeyPath is for serving an account's public key
+	AssertionKeyPath     = "ed25519-key"       // AssertionKeyPath is the fragment of an account's URI that identifies its key for integrity proofs
+	FollowPath           = "follow"            // FollowPath used to generate the URI for an individual follow or follow request
+	UpdatePath           = "updates"           // UpdatePath is used to generate the URI for an account update
+	MovesPath            = "moves"             // MovesPath is used to generate the URI for an account move
+	BlocksPath           = "blocks"            // BlocksPath is used to generate the URI for a block
+	ReportsPath          = "reports"           // ReportsPath is used to generate the URI for a report
+	VotesPath            = "votes"             // VotesPath is used to generate the URI for a vote in a poll
+	ConfirmEmailPath     = "confirm_email"     // ConfirmEmailPath is used to generate the URI for an email confirmation link
+	FileserverPath       = "fileserver"        // FileserverPath is a path component for serving attachments + media
+	EmojiPath            = "emoji"             // EmojiPath represents the activitypub emoji location
 )
 
 // UserURIs contains a bunch of UserURIs and URLs for a user, host, account, etc.
@@ -74,6 +80,8 @@ type UserURIs struct {
 	CollectionURI string
 	// The URI for this user's public key, eg., https://example.org/users/example_user/publickey
 	PublicKeyURI string
+	// The URI for this user's key for integrity proofs, eg., https://example.org/users/example_user#ed25519-key
+	AssertionKeyURI string
 }
 
 // GenerateURIForFollow returns the AP URI for a new follow -- something like:
@@ -100,6 +108,14 @@ func GenerateURIForUpdate(username string, thisUpdateID string) string {
 	return fmt.Sprintf("%s://%s/%s/%s#%s/%s", protocol, host, UsersPath, username, UpdatePath, thisUpdateID)
 }
 
+// GenerateURIForMove returns the AP URI for a new move activity -- something like:
+// https://example.org/users/whatever_user#moves/01F7XTH1QGBAPMGF49WJZ91XGC
+func GenerateURIForMove(username string, thisMoveID string) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	return fmt.Sprintf("%s://%s/%s/%s#%s/%s", protocol, host, UsersPath, username, MovesPath, thisMoveID)
+}
+
 // GenerateURIForBlock returns the AP URI for a new block activity -- something like:
 // https://example.org/users/whatever_user/blocks/01F7XTH1QGBAPMGF49WJZ91XGC
 func GenerateURIForBlock(username string, thisBlockID string) string {
@@ -108,6 +124,38 @@ func GenerateURIForBlock(username string, thisBlockID string) string {
 	return fmt.Sprintf("%s://%s/%s/%s/%s/%s", protocol, host, UsersPath, username, BlocksPath, thisBlockID)
 }
 
+// GenerateURIForFeaturedAccounts returns the AP URI for the collection of accounts featured by a user -- something like:
+// https://example.org/users/whatever_user/collections/featured_accounts
+func GenerateURIForFeaturedAccounts(username string) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	return fmt.Sprintf("%s://%s/%s/%s/%s/%s", protocol, host, UsersPath, username, CollectionsPath, FeaturedAccountsPath)
+}
+
+// GenerateURIForFeaturedTags returns the AP URI for the collection of hashtags featured by a user -- something like:
+// https://example.org/users/whatever_user/collections/tags
+func GenerateURIForFeaturedTags(username string) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	return fmt.Sprintf("%s://%s/%s/%s/%s/%s", protocol, host, UsersPath, username, CollectionsPath, FeaturedTagsPath)
+}
+
+// GenerateURIForReport returns the AP URI for a new report, as it's sent in a flag activity -- something like:
+// https://example.org/reports/01F7XTH1QGBAPMGF49WJZ91XGC
+func GenerateURIForReport(thisReportID string) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	return fmt.Sprintf("%s://%s/%s/%s", protocol, host, ReportsPath, thisReportID)
+}
+
+// GenerateURIForPollVote returns the AP URI for a new vote in a poll -- something like:
+// https://example.org/users/whatever_user#votes/01F7XTH1QGBAPMGF49WJZ91XGC
+func GenerateURIForPollVote(username string, thisVoteID string) string {
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+	return fmt.Sprintf("%s://%s/%s/%s#%s/%s", protocol, host, UsersPath, username, VotesPath, thisVoteID)
+}
+
 // GenerateURIForEmailConfirm returns a link for email confirmation -- something like:
 // https://example.org/confirm_email?token=490e337c-0162-454f-ac48-4b22bb92a205
 func GenerateURIForEmailConfirm(token string) string {
@@ -136,21 +184,23 @@ func GenerateURIsForAccount(username string) *UserURIs {
 	likedURI := fmt.Sprintf("%s/%s", userURI, LikedPath)
 	collectionURI := fmt.Sprintf("%s/%s/%s", userURI, CollectionsPath, FeaturedPath)
 	publicKeyURI := fmt.Sprintf("%s/%s", userURI, PublicKeyPath)
+	assertionKeyURI := fmt.Sprintf("%s#%s", userURI, AssertionKeyPath)
 
 	return &UserURIs{
 		HostURL:     hostURL,
 		UserURL:     userURL,
 		StatusesURL: statusesURL,
 
-		UserURI:       userURI,
-		StatusesURI:   statusesURI,
-		InboxURI:      inboxURI,
-		OutboxURI:     outboxURI,
-		FollowersURI:  followersURI,
-		FollowingURI:  followingURI,
-		LikedURI:      likedURI,
-		CollectionURI: collectionURI,
-		PublicKeyURI:  publicKeyURI,
+		UserURI:         userURI,
+		StatusesURI:     statusesURI,
+		InboxURI:        inboxURI,
+		OutboxURI:       outboxURI,
+		FollowersURI:    followersURI,
+		FollowingURI:    followingURI,
+		LikedURI:        likedURI,
+		CollectionURI:   collectionURI,
+		PublicKeyURI:    publicKeyURI,
+		AssertionKeyURI: assertionKeyURI,
 	}
 }
 
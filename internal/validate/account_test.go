@@ -19,6 +19,7 @@
 package validate_test
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"testing"
@@ -335,7 +336,12 @@ func (suite *AccountValidateTestSuite) TestValidatePublicKey() {
 
 	a.PublicKey = nil
 	err := validate.Struct(*a)
-	suite.EqualError(err, "Key: 'Account.PublicKey' Error:Field validation for 'PublicKey' failed on the 'required' tag")
+	suite.EqualError(err, "Key: 'Account.PublicKey' Error:Field validation for 'PublicKey' failed on the 'required_without' tag")
+
+	// an ed25519 key will do instead
+	a.PublicKeyEd25519 = make(ed25519.PublicKey, ed25519.PublicKeySize)
+	err = validate.Struct(*a)
+	suite.NoError(err)
 }
 
 func TestAccountValidateTestSuite(t *testing.T) {
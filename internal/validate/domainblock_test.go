@@ -38,6 +38,7 @@ func happyDomainBlock() *gtsmodel.DomainBlock {
 		PublicComment:      "poo poo dudes",
 		Obfuscate:          false,
 		SubscriptionID:     "",
+		Severity:           gtsmodel.DomainBlockSeveritySuspend,
 	}
 }
 
@@ -117,6 +118,18 @@ func (suite *DomainBlockValidateTestSuite) TestValidateDomainSubscriptionID() {
 	suite.NoError(err)
 }
 
+func (suite *DomainBlockValidateTestSuite) TestValidateDomainBlockSeverity() {
+	d := happyDomainBlock()
+
+	d.Severity = gtsmodel.DomainBlockSeveritySilence
+	err := validate.Struct(d)
+	suite.NoError(err)
+
+	d.Severity = "noop"
+	err = validate.Struct(d)
+	suite.EqualError(err, "Key: 'DomainBlock.Severity' Error:Field validation for 'Severity' failed on the 'oneof' tag")
+}
+
 func TestDomainBlockValidateTestSuite(t *testing.T) {
 	suite.Run(t, new(DomainBlockValidateTestSuite))
 }
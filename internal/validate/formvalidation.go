@@ -21,9 +21,15 @@ package validate
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/mail"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/regexes"
 	pwv "github.com/wagslane/go-password-validator"
 	"golang.org/x/text/language"
@@ -39,6 +45,9 @@ const (
 	maximumDescriptionLength      = 5000
 	maximumSiteTermsLength        = 5000
 	maximumUsernameLength         = 64
+	maximumEmojiReactionLength    = 16 // in runes; some emoji are made up of several runes joined together
+	maximumProfileFields          = 4
+	maximumProfileFieldLength     = 255 // in runes, for both the name and the value of a field
 	// maximumEmojiShortcodeLength   = 30
 	// maximumHashtagLength          = 30
 )
@@ -137,6 +146,25 @@ func Privacy(privacy string) error {
 	return fmt.Errorf("privacy %s was not recognized", privacy)
 }
 
+// ExpandMedia checks that the given string is one of the ways that media attachments can be shown by default.
+func ExpandMedia(expandMedia string) error {
+	switch gtsmodel.ExpandMedia(expandMedia) {
+	case gtsmodel.ExpandMediaDefault, gtsmodel.ExpandMediaShowAll, gtsmodel.ExpandMediaHideAll:
+		return nil
+	}
+	return fmt.Errorf("expand media %s was not recognized", expandMedia)
+}
+
+// NotificationType checks that the given string is one of the types of notification that can be sent to an account.
+func NotificationType(notificationType string) error {
+	switch gtsmodel.NotificationType(notificationType) {
+	case gtsmodel.NotificationFollow, gtsmodel.NotificationFollowRequest, gtsmodel.NotificationMention, gtsmodel.NotificationReblog,
+		gtsmodel.NotificationFave, gtsmodel.NotificationPoll, gtsmodel.NotificationStatus:
+		return nil
+	}
+	return fmt.Errorf("notification type %s was not recognized", notificationType)
+}
+
 // EmojiShortcode just runs the given shortcode through the regular expression
 // for emoji shortcodes, to figure out whether it's a valid shortcode, ie., 2-30 characters,
 // lowercase a-z, numbers, and underscores.
@@ -147,6 +175,64 @@ func EmojiShortcode(shortcode string) error {
 	return nil
 }
 
+// EmojiReaction checks that the given emoji reaction is either the :shortcode: of a custom emoji,
+// or a short string made up of just emoji, ie., no letters, numbers, spaces, or punctuation.
+func EmojiReaction(emoji string) error {
+	if len(emoji) > 2 && strings.HasPrefix(emoji, ":") && strings.HasSuffix(emoji, ":") {
+		return EmojiShortcode(strings.Trim(emoji, ":"))
+	}
+
+	if emoji == "" {
+		return errors.New("no emoji reaction provided")
+	}
+
+	if length := utf8.RuneCountInString(emoji); length > maximumEmojiReactionLength {
+		return fmt.Errorf("emoji reaction should be no more than %d chars but was %d", maximumEmojiReactionLength, length)
+	}
+
+	for _, r := range emoji {
+		if r == utf8.RuneError || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsControl(r) {
+			return fmt.Errorf("emoji reaction %s did not pass validation, must be a single emoji or the :shortcode: of a custom emoji", emoji)
+		}
+	}
+
+	return nil
+}
+
+// ProfileFields checks that there aren't too many of the given profile fields, and that their names and values
+// aren't too long. Fields with an empty name and value are ignored, since clients send those for unused fields.
+func ProfileFields(fields []apimodel.UpdateField) error {
+	count := 0
+	for _, f := range fields {
+		var name, value string
+		if f.Name != nil {
+			name = *f.Name
+		}
+		if f.Value != nil {
+			value = *f.Value
+		}
+
+		if strings.TrimSpace(name) == "" && strings.TrimSpace(value) == "" {
+			continue
+		}
+		count++
+
+		if length := utf8.RuneCountInString(name); length > maximumProfileFieldLength {
+			return fmt.Errorf("profile field name should be no more than %d chars but was %d", maximumProfileFieldLength, length)
+		}
+
+		if length := utf8.RuneCountInString(value); length > maximumProfileFieldLength {
+			return fmt.Errorf("profile field value should be no more than %d chars but was %d", maximumProfileFieldLength, length)
+		}
+	}
+
+	if count > maximumProfileFields {
+		return fmt.Errorf("no more than %d profile fields can be set but %d were given", maximumProfileFields, count)
+	}
+
+	return nil
+}
+
 // SiteTitle ensures that the given site title is within spec.
 func SiteTitle(siteTitle string) error {
 	if len(siteTitle) > maximumSiteTitleLength {
@@ -183,6 +269,40 @@ func SiteTerms(t string) error {
 	return nil
 }
 
+// PushEndpoint ensures that the given web push endpoint is an absolute https URL, as push services must use,
+// and that its host isn't obviously on a private network, since push services are on the public internet.
+//
+// This only catches literal addresses and localhost; hostnames that resolve to private addresses are
+// refused when notifications are sent instead.
+func PushEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("push endpoint %s could not be parsed: %s", endpoint, err)
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("push endpoint %s should be an absolute https url", endpoint)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("push endpoint %s should not be on localhost", endpoint)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() ||
+			ip.IsPrivate() ||
+			ip.IsUnspecified() ||
+			ip.IsLinkLocalUnicast() ||
+			ip.IsLinkLocalMulticast() ||
+			ip.IsMulticast() {
+			return fmt.Errorf("push endpoint %s should not be on a private network", endpoint)
+		}
+	}
+
+	return nil
+}
+
 // ULID returns true if the passed string is a valid ULID.
 func ULID(i string) bool {
 	return regexes.ULID.MatchString(i)
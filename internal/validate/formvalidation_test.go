@@ -21,10 +21,12 @@ package validate_test
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/validate"
 )
 
@@ -278,6 +280,56 @@ func (suite *ValidationTestSuite) TestValidateReason() {
 	}
 }
 
+func (suite *ValidationTestSuite) TestValidateEmojiReaction() {
+	for _, good := range []string{"👍", "❤️", "👩‍👩‍👧‍👦", "👍🏽", ":blobcat_uwu:"} {
+		suite.NoError(validate.EmojiReaction(good), good)
+	}
+
+	for _, bad := range []string{"", "yes", "👍 ", "!", "::", ":Blob Cat:", "👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍👍"} {
+		suite.Error(validate.EmojiReaction(bad), bad)
+	}
+}
+
+func (suite *ValidationTestSuite) TestValidateProfileFields() {
+	field := func(name string, value string) apimodel.UpdateField {
+		return apimodel.UpdateField{Name: &name, Value: &value}
+	}
+
+	// empty fields don't count towards the limit
+	suite.NoError(validate.ProfileFields([]apimodel.UpdateField{
+		field("pronouns", "they/them"),
+		field("website", "https://example.org"),
+		field("", ""),
+		field("matrix", "@someone:example.org"),
+		field("", " "),
+		field("👍", "👍"),
+	}))
+
+	suite.EqualError(validate.ProfileFields([]apimodel.UpdateField{
+		field("1", "a"), field("2", "b"), field("3", "c"), field("4", "d"), field("5", "e"),
+	}), "no more than 4 profile fields can be set but 5 were given")
+
+	suite.EqualError(validate.ProfileFields([]apimodel.UpdateField{
+		field("website", "https://example.org/"+strings.Repeat("a", 255)),
+	}), "profile field value should be no more than 255 chars but was 275")
+}
+
+func (suite *ValidationTestSuite) TestValidatePushEndpoint() {
+	suite.NoError(validate.PushEndpoint("https://fcm.googleapis.com/fcm/send/some-token"))
+
+	suite.NoError(validate.PushEndpoint("https://203.0.113.5:8443/send"))
+
+	for _, bad := range []string{
+		"", "http://push.example.org/send", "/send", "https://", "not a url",
+		"https://localhost/send", "https://LocalHost./send", "https://push.localhost/send",
+		"https://127.0.0.1/send", "https://10.0.0.1/send", "https://192.168.1.20:8443/send",
+		"https://169.254.169.254/latest/meta-data", "https://[::1]/send", "https://[fe80::1]/send",
+		"https://[fd00::1]/send", "https://0.0.0.0/send",
+	} {
+		suite.Error(validate.PushEndpoint(bad), bad)
+	}
+}
+
 func TestValidationTestSuite(t *testing.T) {
 	suite.Run(t, new(ValidationTestSuite))
 }
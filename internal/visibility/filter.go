@@ -45,6 +45,23 @@ type Filter interface {
 	//
 	// This function will call StatusVisible internally, so it's not necessary to call it beforehand.
 	StatusPublictimelineable(ctx context.Context, targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error)
+
+	// StatusMuted returns true if requestingAccount has muted the author of targetStatus or, if it's a boost, the author of the
+	// boosted status, meaning it shouldn't be shown to them in timelines, streams or threads. Expired mutes are ignored.
+	//
+	// This function doesn't check visibility either, so it should be called as well as one of the functions above, not instead of them.
+	StatusMuted(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error)
+
+	// StatusFiltered returns true if targetStatus matches any of the unexpired hide filters that requestingAccount
+	// has set for the given context, meaning it shouldn't be shown to them there. Boosts are checked by the boosted status.
+	//
+	// This function doesn't check visibility, so it should be called as well as one of the functions above, not instead of them.
+	StatusFiltered(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, error)
+
+	// StatusFilterResults is like StatusFiltered, but as well as whether targetStatus should be hidden, it returns the results
+	// of any unexpired warn filters that it matched, so they can be served along with it. If the status should be hidden,
+	// the results are nil.
+	StatusFilterResults(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, []*gtsmodel.FilterResult, error)
 }
 
 type filter struct {
@@ -0,0 +1,135 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package visibility
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+func (f *filter) StatusFiltered(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, error) {
+	hide, _, err := f.StatusFilterResults(ctx, targetStatus, requestingAccount, filterContext)
+	return hide, err
+}
+
+func (f *filter) StatusFilterResults(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext gtsmodel.FilterContext) (bool, []*gtsmodel.FilterResult, error) {
+	if requestingAccount == nil {
+		return false, nil, nil
+	}
+
+	status := targetStatus
+	if status.BoostOfID != "" {
+		if status.BoostOf == nil {
+			boostOf, err := f.db.GetStatusByID(ctx, status.BoostOfID)
+			if err != nil {
+				return false, nil, fmt.Errorf("StatusFilterResults: error getting boosted status with id %s: %s", status.BoostOfID, err)
+			}
+			status.BoostOf = boostOf
+		}
+		status = status.BoostOf
+	}
+
+	// filters are for other people's statuses, not your own
+	if status.AccountID == requestingAccount.ID {
+		return false, nil, nil
+	}
+
+	filters, err := f.db.GetFiltersByAccountID(ctx, requestingAccount.ID)
+	if err != nil {
+		if err == db.ErrNoEntries {
+			return false, nil, nil
+		}
+		return false, nil, fmt.Errorf("StatusFilterResults: error getting filters of account %s: %s", requestingAccount.ID, err)
+	}
+
+	// only work out the text of the status if there's a keyword to match against it
+	var (
+		filterable    string
+		gotFilterable bool
+	)
+
+	results := []*gtsmodel.FilterResult{}
+	for _, filter := range filters {
+		if filter.Expired() || !filter.AppliesTo(filterContext) {
+			continue
+		}
+
+		result := &gtsmodel.FilterResult{Filter: filter}
+
+		for _, keyword := range filter.Keywords {
+			if !gotFilterable {
+				filterable, err = f.statusFilterableText(ctx, status)
+				if err != nil {
+					return false, nil, fmt.Errorf("StatusFilterResults: error getting text of status with id %s: %s", status.ID, err)
+				}
+				gotFilterable = true
+			}
+
+			if keyword.Regexp().MatchString(filterable) {
+				result.KeywordMatches = append(result.KeywordMatches, keyword.Keyword)
+			}
+		}
+
+		for _, filterStatus := range filter.Statuses {
+			if filterStatus.StatusID == targetStatus.ID || filterStatus.StatusID == status.ID {
+				result.StatusMatches = append(result.StatusMatches, filterStatus.StatusID)
+			}
+		}
+
+		if len(result.KeywordMatches) == 0 && len(result.StatusMatches) == 0 {
+			continue
+		}
+
+		if filter.Action == gtsmodel.FilterActionHide {
+			return true, nil, nil
+		}
+		results = append(results, result)
+	}
+
+	return false, results, nil
+}
+
+// statusFilterableText returns all the text of the given status that filters are matched against:
+// the content warning, the content without html, the descriptions of any media, and the options of any poll.
+func (f *filter) statusFilterableText(ctx context.Context, status *gtsmodel.Status) (string, error) {
+	parts := []string{status.ContentWarning, text.RemoveHTML(status.Content)}
+
+	for _, a := range status.Attachments {
+		parts = append(parts, a.Description)
+	}
+
+	if status.PollID != "" {
+		poll := status.Poll
+		if poll == nil {
+			var err error
+			poll, err = f.db.GetPollByID(ctx, status.PollID)
+			if err != nil {
+				return "", err
+			}
+		}
+		parts = append(parts, poll.Options...)
+	}
+
+	return strings.Join(parts, "\n"), nil
+}
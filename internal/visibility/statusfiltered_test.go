@@ -0,0 +1,179 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package visibility_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusFilteredTestSuite struct {
+	FilterStandardTestSuite
+}
+
+// putFilter stores a hide filter for local_account_1 on the home timeline, with the given phrase as its only keyword.
+func (suite *StatusFilteredTestSuite) putFilter(id string, phrase string, wholeWord bool, expiresAt time.Time) {
+	suite.putFilterWithAction(id, phrase, wholeWord, expiresAt, gtsmodel.FilterActionHide)
+}
+
+func (suite *StatusFilteredTestSuite) putFilterWithAction(id string, phrase string, wholeWord bool, expiresAt time.Time, action gtsmodel.FilterAction) {
+	accountID := suite.testAccounts["local_account_1"].ID
+
+	err := suite.db.PutFilter(context.Background(), &gtsmodel.Filter{
+		ID:          id,
+		AccountID:   accountID,
+		Title:       phrase,
+		Action:      action,
+		ContextHome: true,
+		ExpiresAt:   expiresAt,
+	})
+	suite.NoError(err)
+
+	err = suite.db.PutFilterKeyword(context.Background(), &gtsmodel.FilterKeyword{
+		ID:        id,
+		AccountID: accountID,
+		FilterID:  id,
+		Keyword:   phrase,
+		WholeWord: wholeWord,
+	})
+	suite.NoError(err)
+}
+
+func (suite *StatusFilteredTestSuite) filtered(statusKey string, filterContext gtsmodel.FilterContext) bool {
+	filtered, err := suite.filter.StatusFiltered(context.Background(), suite.testStatuses[statusKey], suite.testAccounts["local_account_1"], filterContext)
+	suite.NoError(err)
+	return filtered
+}
+
+func (suite *StatusFilteredTestSuite) TestNoFilters() {
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestPhrase() {
+	suite.putFilter("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "TURTLE", false, time.Time{})
+
+	// matches case insensitively, and inside longer words
+	suite.True(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+
+	// only applies in the contexts it was set for
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextPublic))
+
+	suite.False(suite.filtered("admin_account_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestWholeWord() {
+	suite.putFilter("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "turtle", true, time.Time{})
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+
+	suite.putFilter("01G1PB6F6JYCE6MTQ0YH1B7R5Q", "turtles", true, time.Time{})
+	suite.True(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestChanged() {
+	ctx := context.Background()
+	suite.putFilter("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "turtles", true, time.Time{})
+	suite.True(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+
+	// the filters of the account are cached, but changing them should be picked up straight away
+	keyword, err := suite.db.GetFilterKeywordByID(ctx, "01G1PB3V2PZ3X8C8WAN4VVBQ4X")
+	suite.NoError(err)
+	keyword.Keyword = "tortoises"
+	suite.NoError(suite.db.UpdateFilterKeyword(ctx, keyword))
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+
+	keyword.Keyword = "turtles"
+	suite.NoError(suite.db.UpdateFilterKeyword(ctx, keyword))
+	suite.True(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+
+	suite.NoError(suite.db.DeleteFilterByID(ctx, "01G1PB3V2PZ3X8C8WAN4VVBQ4X"))
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestContentWarning() {
+	// local_account_1_status_1 has a content warning of "introduction post", but its own statuses are never filtered
+	suite.putFilter("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "introduction", false, time.Time{})
+	suite.False(suite.filtered("local_account_1_status_1", gtsmodel.FilterContextHome))
+
+	status := suite.testStatuses["local_account_2_status_1"]
+	status.ContentWarning = "introduction to turtles"
+	filtered, err := suite.filter.StatusFiltered(context.Background(), status, suite.testAccounts["local_account_1"], gtsmodel.FilterContextHome)
+	suite.NoError(err)
+	suite.True(filtered)
+}
+
+func (suite *StatusFilteredTestSuite) TestExpired() {
+	suite.putFilter("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "turtles", false, time.Now().Add(-1*time.Minute))
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestWarn() {
+	suite.putFilterWithAction("01G1PB3V2PZ3X8C8WAN4VVBQ4X", "turtles", false, time.Time{}, gtsmodel.FilterActionWarn)
+
+	// warn filters don't hide the status, but say which keywords it matched
+	hide, results, err := suite.filter.StatusFilterResults(context.Background(), suite.testStatuses["local_account_2_status_1"], suite.testAccounts["local_account_1"], gtsmodel.FilterContextHome)
+	suite.NoError(err)
+	suite.False(hide)
+	suite.Len(results, 1)
+	suite.Equal("01G1PB3V2PZ3X8C8WAN4VVBQ4X", results[0].Filter.ID)
+	suite.Equal([]string{"turtles"}, results[0].KeywordMatches)
+	suite.Empty(results[0].StatusMatches)
+
+	suite.False(suite.filtered("local_account_2_status_1", gtsmodel.FilterContextHome))
+}
+
+func (suite *StatusFilteredTestSuite) TestFilterStatus() {
+	accountID := suite.testAccounts["local_account_1"].ID
+	status := suite.testStatuses["admin_account_status_1"]
+
+	err := suite.db.PutFilter(context.Background(), &gtsmodel.Filter{
+		ID:          "01G1PB3V2PZ3X8C8WAN4VVBQ4X",
+		AccountID:   accountID,
+		Title:       "not this one",
+		Action:      gtsmodel.FilterActionWarn,
+		ContextHome: true,
+	})
+	suite.NoError(err)
+
+	err = suite.db.PutFilterStatus(context.Background(), &gtsmodel.FilterStatus{
+		ID:        "01G1PB6F6JYCE6MTQ0YH1B7R5Q",
+		AccountID: accountID,
+		FilterID:  "01G1PB3V2PZ3X8C8WAN4VVBQ4X",
+		StatusID:  status.ID,
+	})
+	suite.NoError(err)
+
+	hide, results, err := suite.filter.StatusFilterResults(context.Background(), status, suite.testAccounts["local_account_1"], gtsmodel.FilterContextHome)
+	suite.NoError(err)
+	suite.False(hide)
+	suite.Len(results, 1)
+	suite.Equal([]string{status.ID}, results[0].StatusMatches)
+
+	hide, results, err = suite.filter.StatusFilterResults(context.Background(), suite.testStatuses["local_account_2_status_1"], suite.testAccounts["local_account_1"], gtsmodel.FilterContextHome)
+	suite.NoError(err)
+	suite.False(hide)
+	suite.Empty(results)
+}
+
+func TestStatusFilteredTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusFilteredTestSuite))
+}
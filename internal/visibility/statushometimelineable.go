@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"github.com/sirupsen/logrus"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
@@ -47,6 +48,16 @@ func (f *filter) StatusHometimelineable(ctx context.Context, targetStatus *gtsmo
 		return false, nil
 	}
 
+	// statuses from muted accounts, and boosts of them, are kept out of the home timeline even if they mention the timeline owner
+	muted, err := f.StatusMuted(ctx, targetStatus, timelineOwnerAccount)
+	if err != nil {
+		return false, fmt.Errorf("StatusHometimelineable: %s", err)
+	}
+	if muted {
+		l.Debug("status is not hometimelineable because its account is muted by the timeline owner")
+		return false, nil
+	}
+
 	for _, m := range targetStatus.Mentions {
 		if m.TargetAccountID == timelineOwnerAccount.ID {
 			// if we're mentioned we should be able to see the post
@@ -54,6 +65,18 @@ func (f *filter) StatusHometimelineable(ctx context.Context, targetStatus *gtsmo
 		}
 	}
 
+	// Groups pass on every reply made in them as well as the posts that start threads, which would flood the timeline,
+	// so only timeline the posts that start threads, or replies to the timeline owner.
+	if targetStatus.BoostOfID != "" {
+		groupReply, err := f.groupBoostOfReply(ctx, targetStatus, timelineOwnerAccount)
+		if err != nil {
+			return false, fmt.Errorf("StatusHometimelineable: error checking boost %s: %s", targetStatus.ID, err)
+		}
+		if groupReply {
+			return false, nil
+		}
+	}
+
 	// Don't timeline a status whose parent hasn't been dereferenced yet or can't be dereferenced.
 	// If we have the reply to URI but don't have an ID for the replied-to account or the replied-to status in our database, we haven't dereferenced it yet.
 	if targetStatus.InReplyToURI != "" && (targetStatus.InReplyToID == "" || targetStatus.InReplyToAccountID == "") {
@@ -99,3 +122,28 @@ func (f *filter) StatusHometimelineable(ctx context.Context, targetStatus *gtsmo
 
 	return true, nil
 }
+
+// groupBoostOfReply returns true if the given boost is a group passing on a reply to someone other than the timeline owner.
+func (f *filter) groupBoostOfReply(ctx context.Context, boost *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
+	if boost.Account == nil {
+		a, err := f.db.GetAccountByID(ctx, boost.AccountID)
+		if err != nil {
+			return false, err
+		}
+		boost.Account = a
+	}
+
+	if boost.Account.ActorType != ap.ActorGroup {
+		return false, nil
+	}
+
+	if boost.BoostOf == nil {
+		s, err := f.db.GetStatusByID(ctx, boost.BoostOfID)
+		if err != nil {
+			return false, err
+		}
+		boost.BoostOf = s
+	}
+
+	return boost.BoostOf.InReplyToURI != "" && boost.BoostOf.InReplyToAccountID != timelineOwnerAccount.ID, nil
+}
@@ -0,0 +1,115 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package visibility_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusHometimelineableTestSuite struct {
+	FilterStandardTestSuite
+}
+
+// groupBoost makes remote_account_1 into a group, and has it boost the given status.
+func (suite *StatusHometimelineableTestSuite) groupBoost(boostID string, boostOf *gtsmodel.Status) *gtsmodel.Status {
+	ctx := context.Background()
+
+	group := suite.testAccounts["remote_account_1"]
+	group.ActorType = ap.ActorGroup
+	_, err := suite.db.UpdateAccount(ctx, group)
+	suite.NoError(err)
+
+	boost := &gtsmodel.Status{
+		ID:                  boostID,
+		URI:                 "http://fossbros-anonymous.io/users/foss_satan/statuses/" + boostID,
+		AccountID:           group.ID,
+		AccountURI:          group.URI,
+		BoostOfID:           boostOf.ID,
+		BoostOfAccountID:    boostOf.AccountID,
+		Visibility:          gtsmodel.VisibilityPublic,
+		ActivityStreamsType: ap.ActivityAnnounce,
+	}
+	err = suite.db.PutStatus(ctx, boost)
+	suite.NoError(err)
+
+	return boost
+}
+
+func (suite *StatusHometimelineableTestSuite) TestGroupBoostOfPost() {
+	boost := suite.groupBoost("01G1ZE4M2QK0ANBYWGW7B6KTTY", suite.testStatuses["local_account_2_status_1"])
+
+	timelineable, err := suite.filter.StatusHometimelineable(context.Background(), boost, suite.testAccounts["admin_account"])
+	suite.NoError(err)
+	suite.True(timelineable)
+}
+
+func (suite *StatusHometimelineableTestSuite) TestGroupBoostOfReply() {
+	// this is a reply from local_account_2 to local_account_1
+	boost := suite.groupBoost("01G1ZE5Y2XK3TQ2G3RSSRXD4FM", suite.testStatuses["local_account_2_status_5"])
+
+	// someone else following the group shouldn't see every reply that's made in it...
+	timelineable, err := suite.filter.StatusHometimelineable(context.Background(), boost, suite.testAccounts["admin_account"])
+	suite.NoError(err)
+	suite.False(timelineable)
+
+	// ...but the account being replied to should
+	timelineable, err = suite.filter.StatusHometimelineable(context.Background(), boost, suite.testAccounts["local_account_1"])
+	suite.NoError(err)
+	suite.True(timelineable)
+}
+
+func (suite *StatusHometimelineableTestSuite) TestMutedAccount() {
+	ctx := context.Background()
+	status := suite.testStatuses["local_account_2_status_1"]
+	timelineOwner := suite.testAccounts["local_account_1"]
+
+	timelineable, err := suite.filter.StatusHometimelineable(ctx, status, timelineOwner)
+	suite.NoError(err)
+	suite.True(timelineable)
+
+	mute := &gtsmodel.AccountMute{
+		ID:              "01G2A0YVKG6T2Q7YHMWFW6B3EN",
+		ExpiresAt:       time.Now().Add(1 * time.Hour),
+		AccountID:       timelineOwner.ID,
+		TargetAccountID: status.AccountID,
+	}
+	suite.NoError(suite.db.Put(ctx, mute))
+
+	timelineable, err = suite.filter.StatusHometimelineable(ctx, status, timelineOwner)
+	suite.NoError(err)
+	suite.False(timelineable)
+
+	// once the mute has expired the status should be timelined again
+	mute.ExpiresAt = time.Now().Add(-1 * time.Minute)
+	suite.NoError(suite.db.UpdateByPrimaryKey(ctx, mute))
+
+	timelineable, err = suite.filter.StatusHometimelineable(ctx, status, timelineOwner)
+	suite.NoError(err)
+	suite.True(timelineable)
+}
+
+func TestStatusHometimelineableTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusHometimelineableTestSuite))
+}
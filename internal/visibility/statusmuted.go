@@ -0,0 +1,47 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package visibility
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+func (f *filter) StatusMuted(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (bool, error) {
+	if requestingAccount == nil {
+		return false, nil
+	}
+
+	for _, accountID := range []string{targetStatus.AccountID, targetStatus.BoostOfAccountID} {
+		if accountID == "" || accountID == requestingAccount.ID {
+			continue
+		}
+		muted, err := f.db.IsMuted(ctx, requestingAccount.ID, accountID, false)
+		if err != nil {
+			return false, fmt.Errorf("StatusMuted: error checking mute of account %s: %s", accountID, err)
+		}
+		if muted {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -57,5 +57,55 @@ func (f *filter) StatusPublictimelineable(ctx context.Context, targetStatus *gts
 		return false, nil
 	}
 
+	muted, err := f.StatusMuted(ctx, targetStatus, timelineOwnerAccount)
+	if err != nil {
+		return false, fmt.Errorf("StatusPublictimelineable: %s", err)
+	}
+	if muted {
+		l.Debug("status is not publicTimelineable because its account is muted by the requester")
+		return false, nil
+	}
+
+	// statuses from silenced domains are kept out of the public timeline, unless the owner of the timeline follows the author
+	if !targetStatus.Local {
+		silenced, err := f.statusAuthorSilenced(ctx, targetStatus, timelineOwnerAccount)
+		if err != nil {
+			return false, fmt.Errorf("StatusPublictimelineable: error checking whether author of status with id %s is silenced: %s", targetStatus.ID, err)
+		}
+		if silenced {
+			l.Debug("status is not publicTimelineable because its author's domain is silenced")
+			return false, nil
+		}
+	}
+
 	return true, nil
 }
+
+// statusAuthorSilenced returns true if the author of targetStatus is on a silenced domain,
+// and timelineOwnerAccount (if set) doesn't follow them.
+func (f *filter) statusAuthorSilenced(ctx context.Context, targetStatus *gtsmodel.Status, timelineOwnerAccount *gtsmodel.Account) (bool, error) {
+	author := targetStatus.Account
+	if author == nil {
+		a, err := f.db.GetAccountByID(ctx, targetStatus.AccountID)
+		if err != nil {
+			return false, err
+		}
+		author = a
+	}
+
+	silenced, err := f.db.IsDomainSilenced(ctx, author.Domain)
+	if err != nil || !silenced {
+		return false, err
+	}
+
+	if timelineOwnerAccount == nil {
+		return true, nil
+	}
+
+	follows, err := f.db.IsFollowing(ctx, timelineOwnerAccount, author)
+	if err != nil {
+		return false, err
+	}
+
+	return !follows, nil
+}
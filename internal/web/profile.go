@@ -80,12 +80,20 @@ func (m *Module) profileTemplateHandler(c *gin.Context) {
 	// get latest 10 top-level public statuses;
 	// ie., exclude replies and boosts, public only,
 	// with or without media
-	statuses, errWithCode := m.processor.AccountStatusesGet(ctx, authed, account.ID, 10, true, true, "", "", false, false, true)
+	statusResp, errWithCode := m.processor.AccountStatusesGet(ctx, authed, account.ID, 10, true, true, "", "", false, false, true)
 	if errWithCode != nil {
 		l.Debugf("error getting statuses from processor: %s", errWithCode.Error())
 		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
 		return
 	}
+	statuses := statusResp.Statuses
+
+	featuredTags, errWithCode := m.processor.AccountFeaturedTagsGet(ctx, authed, account.ID)
+	if errWithCode != nil {
+		l.Debugf("error getting featured tags from processor: %s", errWithCode.Error())
+		c.JSON(errWithCode.Code(), gin.H{"error": errWithCode.Safe()})
+		return
+	}
 
 	// pick a random dummy avatar if this account avatar isn't set yet
 	if account.Avatar == "" && len(m.defaultAvatars) > 0 {
@@ -99,9 +107,10 @@ func (m *Module) profileTemplateHandler(c *gin.Context) {
 	}
 
 	c.HTML(http.StatusOK, "profile.tmpl", gin.H{
-		"instance": instance,
-		"account":  account,
-		"statuses": statuses,
+		"instance":     instance,
+		"account":      account,
+		"statuses":     statuses,
+		"featuredTags": featuredTags,
 		"stylesheets": []string{
 			"/assets/Fork-Awesome/css/fork-awesome.min.css",
 			"/assets/status.css",
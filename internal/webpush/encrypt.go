@@ -0,0 +1,145 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// recordSize is the record size given in the header of encrypted payloads.
+// Payloads are always sent as a single record, which push services limit to 4096 bytes anyway.
+const recordSize = 4096
+
+// ValidateKeys checks that the given client public key and auth secret of
+// a web push subscription can be used to encrypt payloads for the client.
+func ValidateKeys(p256dh string, auth string) error {
+	uaPublic, err := decode(p256dh)
+	if err != nil {
+		return fmt.Errorf("error decoding p256dh key: %s", err)
+	}
+
+	if x, _ := elliptic.Unmarshal(elliptic.P256(), uaPublic); x == nil {
+		return errors.New("p256dh key is not an uncompressed P-256 public key")
+	}
+
+	authSecret, err := decode(auth)
+	if err != nil {
+		return fmt.Errorf("error decoding auth secret: %s", err)
+	}
+
+	if len(authSecret) != 16 {
+		return errors.New("auth secret should be 16 bytes long")
+	}
+
+	return nil
+}
+
+// encrypt encrypts the given payload for the client with the given public key and auth secret, in the
+// aes128gcm content encoding (RFC 8188), with keys derived as described for web push in RFC 8291.
+func encrypt(payload []byte, p256dh string, auth string) ([]byte, error) {
+	if err := ValidateKeys(p256dh, auth); err != nil {
+		return nil, err
+	}
+	uaPublic, _ := decode(p256dh)
+	authSecret, _ := decode(auth)
+
+	// generate a new key pair for every message, and agree on a secret with the client's key
+	curve := elliptic.P256()
+	asPrivate, asX, asY, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, asX, asY)
+
+	uaX, uaY := elliptic.Unmarshal(curve, uaPublic)
+	sharedX, _ := curve.ScalarMult(uaX, uaY, asPrivate)
+	ecdhSecret := sharedX.FillBytes(make([]byte, 32))
+
+	// combine the agreed secret with the auth secret, then derive the content encryption key and nonce
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// the payload is the last (and only) record, which is marked with a 0x02 delimiter
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+	if len(plaintext)+gcm.Overhead() > recordSize {
+		return nil, fmt.Errorf("payload of %d bytes is too big", len(payload))
+	}
+
+	// the header is salt, record size, and the length of our public key followed by the key itself
+	header := make([]byte, 16+4+1, 16+4+1+len(asPublic))
+	copy(header, salt)
+	binary.BigEndian.PutUint32(header[16:], recordSize)
+	header[20] = byte(len(asPublic))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// hkdf derives length bytes (up to 32) from the given salt, secret, and info, as described in RFC 5869.
+func hkdf(salt []byte, secret []byte, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(secret)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(sha256.New, prk)
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+	return expand.Sum(nil)[:length]
+}
+
+// encode encodes the given bytes as unpadded base64url.
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decode decodes the given base64url string, tolerating padding and the standard base64 alphabet, which some clients use.
+func decode(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,46 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package webpush
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// NewNoopSender returns a no-op web push sender that will just execute the given sendCallback
+// every time it would otherwise send the given (unencrypted) payload to the given subscription.
+//
+// Passing a nil function is also acceptable, in which case Send will just return nil.
+func NewNoopSender(sendCallback func(subscription *gtsmodel.WebPushSubscription, payload []byte)) Sender {
+	return &noopSender{
+		sendCallback: sendCallback,
+	}
+}
+
+type noopSender struct {
+	sendCallback func(subscription *gtsmodel.WebPushSubscription, payload []byte)
+}
+
+func (s *noopSender) Send(ctx context.Context, subscription *gtsmodel.WebPushSubscription, payload []byte) error {
+	if s.sendCallback != nil {
+		s.sendCallback(subscription, payload)
+	}
+	return nil
+}
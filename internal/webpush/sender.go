@@ -0,0 +1,121 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// ErrGone is returned by Send when the push service says that a subscription doesn't exist anymore, so it should be deleted.
+var ErrGone = errors.New("web push subscription no longer exists")
+
+// ttl is how long push services should hold on to a notification for a client that isn't online, in seconds.
+const ttl = "172800"
+
+// HTTPClient is the subset of *http.Client that's used to send notifications to push services.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Sender contains functions for sending notifications to the push services of web push subscriptions.
+type Sender interface {
+	// Send encrypts the given payload for the given subscription, and posts it to the subscription's push service.
+	// If the push service says the subscription doesn't exist anymore, ErrGone is returned.
+	Send(ctx context.Context, subscription *gtsmodel.WebPushSubscription, payload []byte) error
+}
+
+// NewSender returns a new web push Sender, which uses the given client to make requests,
+// and identifies itself to push services with the instance's VAPID key pair from the given db.
+func NewSender(db db.DB, client HTTPClient) Sender {
+	applicationName := viper.GetString(config.Keys.ApplicationName)
+	protocol := viper.GetString(config.Keys.Protocol)
+	host := viper.GetString(config.Keys.Host)
+
+	return &sender{
+		db:       db,
+		client:   client,
+		appAgent: fmt.Sprintf("%s %s", applicationName, host),
+		subject:  fmt.Sprintf("%s://%s", protocol, host),
+	}
+}
+
+type sender struct {
+	db       db.DB
+	client   HTTPClient
+	appAgent string
+	subject  string
+}
+
+func (s *sender) Send(ctx context.Context, subscription *gtsmodel.WebPushSubscription, payload []byte) error {
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil {
+		return fmt.Errorf("error parsing endpoint %s: %s", subscription.Endpoint, err)
+	}
+
+	keyPair, err := s.db.GetVAPIDKeyPair(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting vapid key pair: %s", err)
+	}
+
+	authorization, err := vapidAuthorization(keyPair, endpoint, s.subject, time.Now())
+	if err != nil {
+		return fmt.Errorf("error creating vapid authorization: %s", err)
+	}
+
+	body, err := encrypt(payload, subscription.P256dh, subscription.Auth)
+	if err != nil {
+		return fmt.Errorf("error encrypting payload: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", ttl)
+	req.Header.Set("User-Agent", s.appAgent)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to %s: %s", endpoint.Host, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("push service %s responded with status %s", endpoint.Host, resp.Status)
+	}
+
+	return nil
+}
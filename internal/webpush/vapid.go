@@ -0,0 +1,83 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package webpush
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// vapidExpiry is how long the signed claims we send to push services are valid for. The maximum allowed is 24 hours.
+const vapidExpiry = 12 * time.Hour
+
+// GenerateVAPIDKeyPair generates a new ECDSA P-256 key pair for identifying this instance to push services (RFC 8292),
+// and returns the private key and the uncompressed public key, encoded as unpadded base64url as clients expect them.
+func GenerateVAPIDKeyPair() (privateKey string, publicKey string, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	privateKey = encode(key.D.FillBytes(make([]byte, 32)))
+	publicKey = encode(elliptic.Marshal(elliptic.P256(), key.X, key.Y))
+	return privateKey, publicKey, nil
+}
+
+// vapidAuthorization returns the Authorization header value that identifies us to the push service of the given endpoint,
+// made up of a JWT signed with the private key of the given key pair, and the public key to check it with.
+func vapidAuthorization(keyPair *gtsmodel.VAPIDKeyPair, endpoint *url.URL, subject string, now time.Time) (string, error) {
+	d, err := decode(keyPair.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("error decoding private key: %s", err)
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d)
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": now.Add(vapidExpiry).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := encode([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." + encode(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	// ES256 signatures are r and s as fixed length big-endian integers, one after the other
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", unsigned, encode(signature), keyPair.PublicKey), nil
+}
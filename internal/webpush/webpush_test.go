@@ -0,0 +1,203 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package webpush_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type WebPushTestSuite struct {
+	suite.Suite
+	db db.DB
+
+	// key pair and auth secret of the client that receives notifications
+	uaPrivate []byte
+	uaPublic  []byte
+	auth      []byte
+}
+
+// roundTripper is an http client that passes requests to a function rather than making them.
+type roundTripper func(req *http.Request) (*http.Response, error)
+
+func (f roundTripper) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (suite *WebPushTestSuite) SetupTest() {
+	testrig.InitTestLog()
+	testrig.InitTestConfig()
+	suite.db = testrig.NewTestDB()
+	testrig.StandardDBSetup(suite.db, nil)
+
+	var x, y *big.Int
+	var err error
+	suite.uaPrivate, x, y, err = elliptic.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.NoError(err)
+	suite.uaPublic = elliptic.Marshal(elliptic.P256(), x, y)
+	suite.auth = make([]byte, 16)
+	_, err = rand.Read(suite.auth)
+	suite.NoError(err)
+}
+
+func (suite *WebPushTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+func (suite *WebPushTestSuite) subscription() *gtsmodel.WebPushSubscription {
+	return &gtsmodel.WebPushSubscription{
+		Endpoint: "https://push.example.org/send/some-client",
+		P256dh:   base64.RawURLEncoding.EncodeToString(suite.uaPublic),
+		Auth:     base64.RawURLEncoding.EncodeToString(suite.auth),
+	}
+}
+
+func (suite *WebPushTestSuite) TestSend() {
+	payload := []byte(`{"notification_id":"01FH57SJCMDWQGEAJ0X08CE3WV","title":"hello"}`)
+
+	var sent *http.Request
+	var body []byte
+	sender := webpush.NewSender(suite.db, roundTripper(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		body, _ = io.ReadAll(req.Body)
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	}))
+
+	err := sender.Send(context.Background(), suite.subscription(), payload)
+	suite.NoError(err)
+
+	suite.Equal("https://push.example.org/send/some-client", sent.URL.String())
+	suite.Equal("aes128gcm", sent.Header.Get("Content-Encoding"))
+	suite.NotEmpty(sent.Header.Get("TTL"))
+
+	// the vapid token should be signed by the instance's key, for the origin of the push service
+	keyPair, err := suite.db.GetVAPIDKeyPair(context.Background())
+	suite.NoError(err)
+	suite.verifyVAPID(sent.Header.Get("Authorization"), keyPair.PublicKey, "https://push.example.org")
+
+	// and the client should be able to decrypt the payload
+	suite.Equal(payload, suite.decrypt(body))
+}
+
+func (suite *WebPushTestSuite) TestSendGone() {
+	sender := webpush.NewSender(suite.db, roundTripper(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusGone, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	}))
+
+	err := sender.Send(context.Background(), suite.subscription(), []byte("{}"))
+	suite.ErrorIs(err, webpush.ErrGone)
+}
+
+func (suite *WebPushTestSuite) TestValidateKeys() {
+	s := suite.subscription()
+	suite.NoError(webpush.ValidateKeys(s.P256dh, s.Auth))
+	suite.Error(webpush.ValidateKeys(s.P256dh, "c2hvcnQ"))
+	suite.Error(webpush.ValidateKeys("bm90IGEga2V5", s.Auth))
+}
+
+// verifyVAPID checks the signature and claims of a vapid Authorization header.
+func (suite *WebPushTestSuite) verifyVAPID(authorization string, publicKey string, audience string) {
+	suite.True(strings.HasPrefix(authorization, "vapid t="))
+	parts := strings.Split(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	suite.Len(parts, 2)
+	suite.Equal(publicKey, parts[1])
+
+	token := strings.Split(parts[0], ".")
+	suite.Len(token, 3)
+
+	claims := map[string]interface{}{}
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(token[1])
+	suite.NoError(err)
+	suite.NoError(json.Unmarshal(claimsBytes, &claims))
+	suite.Equal(audience, claims["aud"])
+	suite.Equal("http://localhost:8080", claims["sub"])
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(publicKey)
+	suite.NoError(err)
+	x, y := elliptic.Unmarshal(elliptic.P256(), keyBytes)
+	signature, err := base64.RawURLEncoding.DecodeString(token[2])
+	suite.NoError(err)
+	hash := sha256.Sum256([]byte(token[0] + "." + token[1]))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	suite.True(ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, hash[:], r, s))
+}
+
+// decrypt decrypts an aes128gcm web push payload the way a client would.
+func (suite *WebPushTestSuite) decrypt(body []byte) []byte {
+	salt := body[:16]
+	suite.Equal(uint32(4096), binary.BigEndian.Uint32(body[16:20]))
+	keyLen := int(body[20])
+	asPublic := body[21 : 21+keyLen]
+	ciphertext := body[21+keyLen:]
+
+	asX, asY := elliptic.Unmarshal(elliptic.P256(), asPublic)
+	sharedX, _ := elliptic.P256().ScalarMult(asX, asY, suite.uaPrivate)
+
+	keyInfo := append([]byte("WebPush: info\x00"), suite.uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(suite.auth, sharedX.FillBytes(make([]byte, 32)), keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	suite.NoError(err)
+	gcm, err := cipher.NewGCM(block)
+	suite.NoError(err)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	suite.NoError(err)
+
+	// strip the last record delimiter
+	suite.Equal(byte(0x02), plaintext[len(plaintext)-1])
+	return plaintext[:len(plaintext)-1]
+}
+
+func hkdf(salt []byte, secret []byte, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(secret)
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+	return expand.Sum(nil)[:length]
+}
+
+func TestWebPushTestSuite(t *testing.T) {
+	suite.Run(t, &WebPushTestSuite{})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"runtime"
+	"sync"
 
 	"codeberg.org/gruf/go-runners"
 	"github.com/sirupsen/logrus"
@@ -13,6 +14,7 @@ import (
 type Worker[MsgType any] struct {
 	workers runners.WorkerPool
 	process func(context.Context, MsgType) error
+	queued  sync.WaitGroup // messages that have been queued but not processed yet
 }
 
 // New returns a new Worker[MsgType] with given number of workers and queue size
@@ -61,9 +63,17 @@ func (w *Worker[MsgType]) SetProcessor(fn func(context.Context, MsgType) error)
 // Queue will queue provided message to be processed with there's a free worker.
 func (w *Worker[MsgType]) Queue(msg MsgType) {
 	logrus.Tracef("queueing %[1]T message; %+[1]v", msg)
+	w.queued.Add(1)
 	w.workers.Enqueue(func(ctx context.Context) {
+		defer w.queued.Done()
 		if err := w.process(ctx, msg); err != nil {
 			logrus.Error(err)
 		}
 	})
 }
+
+// Wait blocks until every message that's been queued has been processed, including any
+// that are queued while waiting. The worker pool must be running, or this might never return.
+func (w *Worker[MsgType]) Wait() {
+	w.queued.Wait()
+}
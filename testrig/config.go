@@ -83,20 +83,60 @@ var TestDefaults = config.Values{
 	AccountsApprovalRequired: true,
 	AccountsReasonRequired:   true,
 
-	MediaImageMaxSize:        1048576, // 1mb
-	MediaVideoMaxSize:        5242880, // 5mb
-	MediaDescriptionMinChars: 0,
-	MediaDescriptionMaxChars: 500,
-	MediaRemoteCacheDays:     30,
+	MediaImageMaxSize:         1048576,  // 1mb
+	MediaImageMaxPixels:       16777216, // 4096x4096
+	MediaVideoMaxSize:         5242880,  // 5mb
+	MediaVideoMaxPixels:       2304000,  // 1920x1200
+	MediaVideoMaxDuration:     300,      // 5 minutes
+	MediaEmojiMaxSize:         51200,    // 50kb
+	MediaEmojiMaxPixels:       1048576,  // 1024x1024
+	MediaUnknownMaxSize:       5242880,  // 5mb
+	MediaDescriptionMinChars:  0,
+	MediaDescriptionMaxChars:  500,
+	MediaRemoteCacheDays:      30,
+	MediaRemoteRecacheTimeout: 10,
+	MediaAccountQuota:         0,
+	MediaThumbnailMaxWidth:    512,
+	MediaThumbnailMaxHeight:   512,
+	MediaThumbnailVariants:    []string{},
+	MediaJpegProgressive:      false,
+	MediaGifvEnabled:          false,
+	MediaGifvMinSize:          1048576, // 1mb
+	MediaGifvKeepOriginal:     false,
+	MediaGifvFfmpegPath:       "ffmpeg",
+	MediaJobTimeout:           300,
+	MediaRecacheBatchRate:     5,
+	MediaIntegrityCheckSample: 0,
+	MediaRemoteFetchRate:      0,
+	MediaCacheWarmCount:       0,
 
 	StorageBackend:       "local",
 	StorageLocalBasePath: "/gotosocial/storage",
+	StorageS3Endpoint:    "",
+	StorageS3AccessKey:   "",
+	StorageS3SecretKey:   "",
+	StorageS3UseSSL:      true,
+	StorageS3BucketName:  "",
+	StorageS3Proxy:       false,
 
 	StatusesMaxChars:           5000,
 	StatusesCWMaxChars:         100,
 	StatusesPollMaxOptions:     6,
 	StatusesPollOptionMaxChars: 50,
 	StatusesMediaMaxFiles:      6,
+	StatusesQuotesEnabled:      false,
+	StatusesReactionsEnabled:   true,
+	StatusesTrendsDays:         7,
+	StatusesTrendsApproval:     false,
+	StatusesSearchEnabled:      false,
+
+	FederationUnreachableDays:  7,
+	FederationNodeInfoMetadata: map[string]string{"nodeAdmin": "Zork"},
+	FederationInboxRateLimit:   0,
+	FederationHideCollections:  false,
+	FederationRefreshDays:      7,
+	FederationRefreshPerDomain: 20,
+	FederationRefuseActivities: []string{},
 
 	LetsEncryptEnabled:      false,
 	LetsEncryptPort:         0,
@@ -120,4 +160,13 @@ var TestDefaults = config.Values{
 	SyslogEnabled:  false,
 	SyslogProtocol: "udp",
 	SyslogAddress:  "localhost:514",
+
+	MetricsEnabled: false,
+
+	RateLimitRequests:          0,
+	RateLimitExpensiveRequests: 0,
+
+	TranslationBackend:  "",
+	TranslationEndpoint: "",
+	TranslationAPIKey:   "",
 }
@@ -33,6 +33,30 @@ import (
 
 var testModels = []interface{}{
 	&gtsmodel.Account{},
+	&gtsmodel.Delivery{},
+	&gtsmodel.UnreachableDomain{},
+	&gtsmodel.Report{},
+	&gtsmodel.Tombstone{},
+	&gtsmodel.WebPushSubscription{},
+	&gtsmodel.VAPIDKeyPair{},
+	&gtsmodel.Conversation{},
+	&gtsmodel.ConversationToStatus{},
+	&gtsmodel.Filter{},
+	&gtsmodel.FilterKeyword{},
+	&gtsmodel.FilterStatus{},
+	&gtsmodel.Marker{},
+	&gtsmodel.Link{},
+	&gtsmodel.StatusToLink{},
+	&gtsmodel.TrendReview{},
+	&gtsmodel.Endorsement{},
+	&gtsmodel.FeaturedTag{},
+	&gtsmodel.FollowedTag{},
+	&gtsmodel.Export{},
+	&gtsmodel.Import{},
+	&gtsmodel.ImportFailure{},
+	&gtsmodel.Rule{},
+	&gtsmodel.IPBlock{},
+	&gtsmodel.AccountMute{},
 	&gtsmodel.Application{},
 	&gtsmodel.Block{},
 	&gtsmodel.DomainBlock{},
@@ -40,11 +64,16 @@ var testModels = []interface{}{
 	&gtsmodel.Follow{},
 	&gtsmodel.FollowRequest{},
 	&gtsmodel.MediaAttachment{},
+	&gtsmodel.MediaJob{},
 	&gtsmodel.Mention{},
+	&gtsmodel.Poll{},
+	&gtsmodel.PollVote{},
 	&gtsmodel.Status{},
 	&gtsmodel.StatusToEmoji{},
 	&gtsmodel.StatusToTag{},
 	&gtsmodel.StatusFave{},
+	&gtsmodel.StatusReaction{},
+	&gtsmodel.StatusEdit{},
 	&gtsmodel.StatusBookmark{},
 	&gtsmodel.StatusMute{},
 	&gtsmodel.Tag{},
@@ -99,6 +128,9 @@ func CreateTestTables(db db.DB) {
 			logrus.Panicf("error creating table for %+v: %s", m, err)
 		}
 	}
+	if err := db.CreateStatusSearchIndex(ctx); err != nil {
+		logrus.Panicf("error creating status search index: %s", err)
+	}
 }
 
 // StandardDBSetup populates a given db with all the necessary tables/models for perfoming tests.
@@ -224,6 +256,10 @@ func StandardDBSetup(db db.DB, accounts map[string]*gtsmodel.Account) {
 		logrus.Panic(err)
 	}
 
+	if err := db.CreateInstanceVAPIDKeyPair(ctx); err != nil {
+		logrus.Panic(err)
+	}
+
 	logrus.Debug("testing db setup complete")
 }
 
@@ -233,6 +269,9 @@ func StandardDBTeardown(db db.DB) {
 	if db == nil {
 		logrus.Panic("db teardown: db was nil")
 	}
+	if err := db.DropStatusSearchIndex(ctx); err != nil {
+		logrus.Panic(err)
+	}
 	for _, m := range testModels {
 		if err := db.DropTable(ctx, m); err != nil {
 			logrus.Panic(err)
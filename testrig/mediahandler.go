@@ -19,13 +19,13 @@
 package testrig
 
 import (
-	"codeberg.org/gruf/go-store/kv"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 )
 
 // NewTestMediaManager returns a media handler with the default test config, and the given db and storage.
-func NewTestMediaManager(db db.DB, storage *kv.KVStore) media.Manager {
+func NewTestMediaManager(db db.DB, storage *gtsstorage.Driver) media.Manager {
 	m, err := media.NewManager(db, storage)
 	if err != nil {
 		panic(err)
@@ -24,19 +24,21 @@ import (
 
 	"codeberg.org/gruf/go-store/kv"
 	"codeberg.org/gruf/go-store/storage"
+
+	gtsstorage "github.com/superseriousbusiness/gotosocial/internal/storage"
 )
 
 // NewTestStorage returns a new in memory storage with the default test config
-func NewTestStorage() *kv.KVStore {
-	storage, err := kv.OpenStorage(storage.OpenMemory(200, false))
+func NewTestStorage() *gtsstorage.Driver {
+	kvstore, err := kv.OpenStorage(storage.OpenMemory(200, false))
 	if err != nil {
 		panic(err)
 	}
-	return storage
+	return gtsstorage.NewDriver(&gtsstorage.KVStorage{KVStore: kvstore})
 }
 
 // StandardStorageSetup populates the storage with standard test entries from the given directory.
-func StandardStorageSetup(s *kv.KVStore, relativePath string) {
+func StandardStorageSetup(s *gtsstorage.Driver, relativePath string) {
 	storedA := newTestStoredAttachments()
 	a := NewTestAttachments()
 	for k, paths := range storedA {
@@ -93,19 +95,8 @@ func StandardStorageSetup(s *kv.KVStore, relativePath string) {
 }
 
 // StandardStorageTeardown deletes everything in storage so that it's clean for the next test
-func StandardStorageTeardown(s *kv.KVStore) {
-	iter, err := s.Iterator(nil)
-	if err != nil {
+func StandardStorageTeardown(s *gtsstorage.Driver) {
+	if err := s.WalkKeys(s.Delete); err != nil {
 		panic(err)
 	}
-	keys := []string{}
-	for iter.Next() {
-		keys = append(keys, iter.Key())
-	}
-	iter.Release()
-	for _, k := range keys {
-		if err := s.Delete(k); err != nil {
-			panic(err)
-		}
-	}
 }
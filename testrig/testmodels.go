@@ -22,8 +22,10 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -533,6 +535,12 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 		}
 		v.PrivateKey = priv
 		v.PublicKey = &priv.PublicKey
+
+		// ed25519 keys can just be derived from something that's different for each account
+		seed := sha256.Sum256([]byte(v.URI))
+		v.AssertionPrivateKey = ed25519.NewKeyFromSeed(seed[:])
+		v.AssertionPublicKey = v.AssertionPrivateKey.Public().(ed25519.PublicKey)
+		v.AssertionKeyURI = v.URI + "#ed25519-key"
 		i++
 	}
 	return accounts
@@ -872,6 +880,7 @@ func NewTestDomainBlocks() map[string]*gtsmodel.DomainBlock {
 			PrivateComment:     "i blocked this domain because they keep replying with pushy + unwarranted linux advice",
 			PublicComment:      "reply-guying to tech posts",
 			Obfuscate:          false,
+			Severity:           gtsmodel.DomainBlockSeveritySuspend,
 		},
 	}
 }
@@ -1794,6 +1803,38 @@ func NewTestDereferenceRequests(accounts map[string]*gtsmodel.Account) map[strin
 		DateHeader:      date,
 	}
 
+	target = URLMustParse(accounts["local_account_1"].FollowingURI)
+	sig, digest, date = GetSignatureForDereference(accounts["remote_account_1"].PublicKeyURI, accounts["remote_account_1"].PrivateKey, target)
+	fossSatanDereferenceZorkFollowing := ActivityWithSignature{
+		SignatureHeader: sig,
+		DigestHeader:    digest,
+		DateHeader:      date,
+	}
+
+	target = URLMustParse(accounts["local_account_1"].FollowingURI + "?page=true")
+	sig, digest, date = GetSignatureForDereference(accounts["remote_account_1"].PublicKeyURI, accounts["remote_account_1"].PrivateKey, target)
+	fossSatanDereferenceZorkFollowingFirst := ActivityWithSignature{
+		SignatureHeader: sig,
+		DigestHeader:    digest,
+		DateHeader:      date,
+	}
+
+	target = URLMustParse(accounts["local_account_1"].FeaturedCollectionURI)
+	sig, digest, date = GetSignatureForDereference(accounts["remote_account_1"].PublicKeyURI, accounts["remote_account_1"].PrivateKey, target)
+	fossSatanDereferenceZorkFeatured := ActivityWithSignature{
+		SignatureHeader: sig,
+		DigestHeader:    digest,
+		DateHeader:      date,
+	}
+
+	target = URLMustParse(accounts["local_account_1"].URI + "/collections/featured_accounts")
+	sig, digest, date = GetSignatureForDereference(accounts["remote_account_1"].PublicKeyURI, accounts["remote_account_1"].PrivateKey, target)
+	fossSatanDereferenceZorkFeaturedAccounts := ActivityWithSignature{
+		SignatureHeader: sig,
+		DigestHeader:    digest,
+		DateHeader:      date,
+	}
+
 	return map[string]ActivityWithSignature{
 		"foss_satan_dereference_zork":                                  fossSatanDereferenceZork,
 		"foss_satan_dereference_zork_public_key":                       fossSatanDereferenceZorkPublicKey,
@@ -1805,6 +1846,10 @@ func NewTestDereferenceRequests(accounts map[string]*gtsmodel.Account) map[strin
 		"foss_satan_dereference_zork_outbox":                           fossSatanDereferenceZorkOutbox,
 		"foss_satan_dereference_zork_outbox_first":                     fossSatanDereferenceZorkOutboxFirst,
 		"foss_satan_dereference_zork_outbox_next":                      fossSatanDereferenceZorkOutboxNext,
+		"foss_satan_dereference_zork_following":                        fossSatanDereferenceZorkFollowing,
+		"foss_satan_dereference_zork_following_first":                  fossSatanDereferenceZorkFollowingFirst,
+		"foss_satan_dereference_zork_featured":                         fossSatanDereferenceZorkFeatured,
+		"foss_satan_dereference_zork_featured_accounts":                fossSatanDereferenceZorkFeaturedAccounts,
 	}
 }
 
@@ -0,0 +1,54 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package testrig
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/translate"
+)
+
+// NewTestTranslator returns a translator that doesn't make any requests, but just
+// "translates" texts by prefixing them with the code of the target language in brackets,
+// eg., "[de] hello world". Texts are always detected as being in English.
+func NewTestTranslator() translate.Translator {
+	return &testTranslator{}
+}
+
+type testTranslator struct{}
+
+func (t *testTranslator) Provider() string {
+	return "Test Translator"
+}
+
+func (t *testTranslator) Translate(ctx context.Context, texts []string, format translate.Format, sourceLanguage string, targetLanguage string) (*translate.Translation, error) {
+	translated := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text == "" {
+			translated = append(translated, "")
+			continue
+		}
+		translated = append(translated, "["+targetLanguage+"] "+text)
+	}
+
+	return &translate.Translation{
+		Texts:                  translated,
+		DetectedSourceLanguage: "en",
+	}, nil
+}
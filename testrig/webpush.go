@@ -0,0 +1,47 @@
+/*
+   GoToSocial
+   Copyright (C) 2021-2022 GoToSocial Authors admin@gotosocial.org
+
+   This program is free software: you can redistribute it and/or modify
+   it under the terms of the GNU Affero General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   This program is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU Affero General Public License for more details.
+
+   You should have received a copy of the GNU Affero General Public License
+   along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package testrig
+
+import (
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+)
+
+// SentPush is a web push payload as it would have been sent to the push service at Endpoint, before encryption.
+type SentPush struct {
+	Endpoint string
+	Payload  string
+}
+
+// NewWebPushSender returns a noop web push sender that won't make any remote calls.
+//
+// If sentPushes is not nil, the noop callback function will send each payload that
+// would have been sent to the channel. Pushes are sent from a worker, so the channel
+// should be buffered.
+func NewWebPushSender(sentPushes chan<- SentPush) webpush.Sender {
+	var sendCallback func(subscription *gtsmodel.WebPushSubscription, payload []byte)
+
+	if sentPushes != nil {
+		sendCallback = func(subscription *gtsmodel.WebPushSubscription, payload []byte) {
+			sentPushes <- SentPush{Endpoint: subscription.Endpoint, Payload: string(payload)}
+		}
+	}
+
+	return webpush.NewNoopSender(sendCallback)
+}